@@ -6,25 +6,60 @@ import (
 	"sync"
 	"sync/atomic"
 
+	"myT-x/internal/activitydigest"
+	"myT-x/internal/bringup"
+	"myT-x/internal/cmdapproval"
+	"myT-x/internal/cmdqueue"
 	"myT-x/internal/config"
 	"myT-x/internal/devpanel"
+	"myT-x/internal/ephemeral"
+	"myT-x/internal/errreport"
+	"myT-x/internal/eventsub"
+	gitpkg "myT-x/internal/git"
 	"myT-x/internal/hotkeys"
 	"myT-x/internal/inputhistory"
 	"myT-x/internal/ipc"
+	"myT-x/internal/jumplist"
+	"myT-x/internal/layoutpreset"
+	"myT-x/internal/logging"
+	"myT-x/internal/maintenance"
 	"myT-x/internal/mcp"
 	"myT-x/internal/mcpapi"
+	"myT-x/internal/metrics"
+	"myT-x/internal/netcapture"
 	"myT-x/internal/orchestrator"
+	"myT-x/internal/outputspill"
+	"myT-x/internal/outputwatch"
+	"myT-x/internal/paneactivity"
 	"myT-x/internal/panestate"
+	"myT-x/internal/powerstate"
+	"myT-x/internal/preopsnapshot"
 	"myT-x/internal/promptpresets"
+	"myT-x/internal/recentdirs"
+	"myT-x/internal/rendering"
+	"myT-x/internal/repoconfig"
+	"myT-x/internal/repostats"
+	"myT-x/internal/resourcelimit"
 	"myT-x/internal/scheduler"
+	"myT-x/internal/screensync"
 	"myT-x/internal/session"
+	"myT-x/internal/sessionbadge"
+	"myT-x/internal/sessioncopy"
+	"myT-x/internal/sessiongroup"
 	"myT-x/internal/sessionlog"
 	"myT-x/internal/sessionmemo"
+	"myT-x/internal/sessionports"
+	"myT-x/internal/setupwatch"
+	"myT-x/internal/shellpool"
 	"myT-x/internal/singletaskrunner"
 	"myT-x/internal/snapshot"
+	"myT-x/internal/startupmetrics"
+	"myT-x/internal/statestore"
+	"myT-x/internal/taskbar"
 	"myT-x/internal/taskscheduler"
 	"myT-x/internal/tmux"
 	"myT-x/internal/usagedashboard"
+	"myT-x/internal/webhook"
 	"myT-x/internal/worktree"
 	"myT-x/internal/wsserver"
 )
@@ -52,7 +87,7 @@ type App struct {
 	// (paneEnvUpdateMu and claudeEnvUpdateMu also have nested ordering with
 	// tmux.CommandRouter locks — see nested lock ordering above.)
 	//   windowMu, startupWarnMu, ctxMu,
-	//   paneEnvUpdateMu, claudeEnvUpdateMu,
+	//   paneEnvUpdateMu, claudeEnvUpdateMu, hotkeyUpdateMu,
 	//   snapshot.Service (internal locks: see snapshot.Service doc),
 	//   scheduler.Service.mu (internal), scheduler.Service.templateMu (internal)
 	//   orchestrator.Service.mu (internal)
@@ -61,23 +96,35 @@ type App struct {
 	paneEnvAppliedVersion   uint64
 	claudeEnvUpdateMu       sync.Mutex
 	claudeEnvAppliedVersion uint64
+	hotkeyUpdateMu          sync.Mutex
+	hotkeyAppliedVersion    uint64
 	workspace               string
 	// launchDir is the working directory captured at startup. Read-only after
 	// startup() returns; safe to access without mutex from any goroutine.
 	launchDir          string
 	startupWarnMu      sync.Mutex
 	configLoadWarnings []string
+	// pendingDeepLink is the mytx:// link the app was launched with, held
+	// until the frontend is ready. Guarded by startupWarnMu.
+	pendingDeepLink string
 	// Session lifecycle management (create, rename, kill, active session tracking).
 	// Thread-safety is managed internally by the Service. No App-level mutex is needed.
 	// Initialized in NewApp().
 	sessionService *session.Service
 
 	// Backend services.
-	sessions   *tmux.SessionManager
-	router     *tmux.CommandRouter
-	pipeServer *ipc.PipeServer
-	hotkeys    *hotkeys.Manager
-	paneStates *panestate.Manager
+	sessions *tmux.SessionManager
+	router   *tmux.CommandRouter
+	// pipeServerMu protects pipeServer, which the pipe takeover worker sets
+	// after startup when another server owned the pipe or a restart left the
+	// server stopped, and pipeTakeoverCancel.
+	pipeServerMu sync.Mutex
+	pipeServer   *ipc.PipeServer
+	hotkeys      *hotkeys.Manager
+	paneStates   *panestate.Manager
+	// lastPipeServerRestart is the report of the latest RestartPipeServer
+	// call; nil until the first restart.
+	lastPipeServerRestart atomic.Pointer[ipc.RestartReport]
 
 	// MCP process management.
 	// Independent locks: mcp.Registry.mu and mcp.Manager.mu are independent of
@@ -93,6 +140,15 @@ type App struct {
 	windowToggling atomic.Bool // CAS guard to prevent concurrent toggleQuakeWindow
 	shuttingDown   atomic.Bool // set true at the start of shutdown(); checked by worker recovery loops
 
+	// Sleep/resume and lock/unlock tracking. systemSuspended is set between
+	// suspend and resume; background pollers skip work while it is set.
+	// Initialized in NewApp().
+	powerMonitor    *powerstate.Monitor
+	systemSuspended atomic.Bool
+
+	// relaunchRequested makes main start a new instance after shutdown (RelaunchApp).
+	relaunchRequested atomic.Bool
+
 	// wsHub provides a WebSocket binary stream for high-throughput pane data.
 	// Set once during startup (single-goroutine); nil if WebSocket server fails to start.
 	// Read by snapshotService flush callback (concurrent) and GetWebSocketURL (Wails-bound).
@@ -121,6 +177,132 @@ type App struct {
 	// Initialized in NewApp().
 	schedulerService *scheduler.Service
 
+	// Per-pane shell command queues (sequential execution gated on the prompt).
+	// Thread-safety is managed internally by the Service. No App-level mutex is needed.
+	// Initialized in NewApp().
+	commandQueueService *cmdqueue.Service
+
+	// User-defined watch expressions evaluated against pane output.
+	// Thread-safety is managed internally by the Service. No App-level mutex is needed.
+	// Initialized in NewApp().
+	outputWatchService *outputwatch.Service
+
+	// Orchestrated bring-up and tear-down of config session_templates.
+	// Thread-safety is managed internally by the Service. No App-level mutex is needed.
+	// Initialized in NewApp().
+	bringUpService *bringup.Service
+
+	// Session groups (multi-repo workspaces) created and killed as one unit.
+	// Thread-safety is managed internally by the Service. No App-level mutex is needed.
+	// Initialized in NewApp().
+	sessionGroupService *sessiongroup.Service
+
+	// TCP listeners opened by pane process trees, attributed to sessions.
+	// Thread-safety is managed internally by the Service. No App-level mutex is needed.
+	// Initialized in NewApp().
+	sessionPortsService *sessionports.Service
+
+	// Busy/idle/waiting classification of every pane.
+	// Thread-safety is managed internally by the Service. No App-level mutex is needed.
+	// Initialized in NewApp().
+	paneActivityService *paneactivity.Service
+
+	// Pane output kept on disk while no client consumes it.
+	// Thread-safety is managed internally by the Spool. No App-level mutex is needed.
+	// Initialized in NewApp().
+	outputSpill *outputspill.Spool
+	// wsConnectedOnce is set once the frontend stream has connected; until
+	// then pane output reaches the frontend over Wails IPC.
+	wsConnectedOnce atomic.Bool
+
+	// Level, format, and component files of the app log.
+	// Thread-safety is managed internally by the Manager. No App-level mutex is needed.
+	// Initialized in startup() before any subsystem logs; nil before that.
+	logs *logging.Manager
+
+	// Prometheus metrics of the host and the endpoint serving them.
+	// Thread-safety is managed internally by the Registry and Server. No App-level mutex is needed.
+	// Initialized in NewApp(); the endpoint starts when the metrics config enables it.
+	metrics       *metrics.Registry
+	metricsServer *metrics.Server
+
+	// CPU/memory limits of session pane processes and their live usage.
+	// Thread-safety is managed internally by the Manager and Sampler. No App-level mutex is needed.
+	// Initialized in NewApp().
+	resourceLimits  *resourcelimit.Manager
+	resourceSampler *resourcelimit.Sampler
+
+	// Events and pane output the frontend declared it renders.
+	// Thread-safety is managed internally by the Filter. No App-level mutex is needed.
+	// Initialized in NewApp().
+	eventFilter *eventsub.Filter
+
+	// Worktree git status of every session, polled in the background.
+	// Thread-safety is managed internally by the StatusWatcher. No App-level mutex is needed.
+	// Initialized in NewApp().
+	gitStatusWatcher *gitpkg.StatusWatcher
+
+	// Re-runs worktree setup scripts when watched dependency files change.
+	// Thread-safety is managed internally by the Service. No App-level mutex is needed.
+	// Initialized in NewApp().
+	setupWatchService *setupwatch.Service
+
+	// Removes ephemeral sessions after their TTL and on app exit.
+	// Thread-safety is managed internally by the Service. No App-level mutex is needed.
+	// Initialized in NewApp().
+	ephemeralService *ephemeral.Service
+
+	// Copies files between session directories (CopyBetweenSessions and the
+	// copy-between-sessions shim command).
+	// Thread-safety: copies are independent. No App-level mutex is needed.
+	// Initialized in NewApp().
+	sessionCopyService *sessioncopy.Service
+
+	// Opt-in recording proxies for the outbound HTTP(S) traffic of sessions.
+	// Thread-safety: managed internally by netcapture.Service.
+	// Initialized in NewApp().
+	networkCapture *netcapture.Service
+
+	// Windows taskbar jump list of recent sessions and quick actions.
+	// Thread-safety is managed internally by the Service. No App-level mutex is needed.
+	// Initialized in NewApp().
+	jumpListService *jumplist.Service
+
+	// Taskbar flashing and badge count for background sessions needing input.
+	// Thread-safety is managed internally by the Service. No App-level mutex is needed.
+	// Initialized in NewApp().
+	taskbarService *taskbar.Service
+
+	// Event webhooks POSTed to user-configured endpoints.
+	// Thread-safety is managed internally by the Service. No App-level mutex is needed.
+	// Initialized in NewApp().
+	webhookService *webhook.Service
+
+	// Deduplicated, rate-limited error reports emitted as app:errors toasts.
+	// Thread-safety is managed internally by the Reporter. No App-level mutex is needed.
+	// Initialized in NewApp().
+	errorReporter *errreport.Reporter
+
+	// Per-repository .mytx.yaml gated by the persisted directory trust store.
+	// Thread-safety is managed internally by the Service. No App-level mutex is needed.
+	// Initialized in NewApp() before sessionService, which resolves through it.
+	repoConfigService *repoconfig.Service
+
+	// Session snapshots captured before force cleanup and branch promotion.
+	// Thread-safety is managed internally by the Service. No App-level mutex is needed.
+	// Initialized in NewApp() before sessionService and worktreeService, which capture through it.
+	preOpSnapshotService *preopsnapshot.Service
+
+	// Startup phase timings and background subsystem readiness (GetMetrics).
+	// Thread-safety is managed internally by the Recorder. No App-level mutex is needed.
+	// Initialized in NewApp() so its clock starts before startup().
+	startupMetrics *startupmetrics.Recorder
+
+	// Cached per-repository language breakdown for the new-session dialogs.
+	// Thread-safety is managed internally by the Service. No App-level mutex is needed.
+	// Initialized in NewApp(); watchers are closed in shutdown().
+	repoStatsService *repostats.Service
+
 	// Task scheduler manager (per-session sequential task queue with completion detection).
 	// Thread-safety is managed internally by the ServiceManager. No App-level mutex is needed.
 	// Initialized in NewApp().
@@ -146,6 +328,51 @@ type App struct {
 	// Initialized in NewApp().
 	sessionMemoService *sessionmemo.Service
 
+	// User-defined session badge persistence.
+	// Thread-safety is managed internally by the Service. No App-level mutex is needed.
+	// Initialized in NewApp().
+	sessionBadgeService *sessionbadge.Service
+
+	// Recent session directories for the new-session dialog.
+	// Thread-safety is managed internally by the Service. No App-level mutex is needed.
+	// Initialized in NewApp().
+	recentDirsService *recentdirs.Service
+
+	// Saved window layout presets (session-scoped and global).
+	// Thread-safety is managed internally by the Service. No App-level mutex is needed.
+	// Initialized in NewApp().
+	layoutPresetService *layoutpreset.Service
+
+	// Approval gate for tmux commands arriving via the shim from gated sessions.
+	// Thread-safety is managed internally by the Gate. No App-level mutex is needed.
+	// Initialized in NewApp().
+	commandApproval *cmdapproval.Gate
+
+	// Persisted state backend (session badges, audit logs). Opened on first
+	// use by requireStateStore and closed during shutdown.
+	stateStoreMu sync.Mutex
+	stateStore   statestore.Store
+
+	// Idle-time maintenance job registry and scheduler.
+	// Thread-safety is managed internally by the Service. No App-level mutex is needed.
+	// Initialized in NewApp().
+	maintenanceService *maintenance.Service
+
+	// Daily activity digest of every session (config activity_digest).
+	// Thread-safety is managed internally by the Service. No App-level mutex is needed.
+	// Initialized in NewApp().
+	activityDigestService *activitydigest.Service
+
+	// WebView2 crash-loop and GPU driver reset detection with the persisted
+	// software rendering flag. Thread-safety is managed internally by the Monitor.
+	// Initialized in NewApp() because main reads it before the window exists.
+	renderingMonitor *rendering.Monitor
+
+	// Warm shell pool used by new panes. Configured at startup and on config
+	// changes; thread-safety is managed internally by the Pool.
+	// Initialized in NewApp().
+	sessionPool *shellpool.Pool
+
 	// Developer panel file browsing and git operations.
 	// Stateless service; no mutex needed. Initialized in NewApp().
 	devpanelService *devpanel.Service
@@ -171,13 +398,38 @@ type App struct {
 	// Replaced in tests to avoid launching explorer.exe.
 	openExplorerFn func(string) error
 
+	// sessionBadgeFactsFn collects git facts for session_badge_rules.
+	// Replaced in tests to avoid running git.
+	sessionBadgeFactsFn func(workDir string, needDirty bool) config.SessionBadgeFacts
+
+	// runCommandHookFn runs a command_triggers hook.
+	// Replaced in tests to avoid running cmd.exe.
+	runCommandHookFn func(ctx context.Context, hook, workDir string, env []string) error
+
 	// Background worker cancellation/waits.
-	idleCancel        context.CancelFunc
-	bgWG              sync.WaitGroup
-	setupWG           sync.WaitGroup
-	setupCancelMu     sync.Mutex
-	setupCancels      map[uint64]context.CancelFunc
-	nextSetupCancelID atomic.Uint64
+	idleCancel           context.CancelFunc
+	portsCancel          context.CancelFunc
+	paneActivityCancel   context.CancelFunc
+	gitStatusCancel      context.CancelFunc
+	baseRefreshCancel    context.CancelFunc
+	setupWatchCancel     context.CancelFunc
+	ephemeralCancel      context.CancelFunc
+	jumpListCancel       context.CancelFunc
+	taskbarCancel        context.CancelFunc
+	webhooksCancel       context.CancelFunc
+	errorsCancel         context.CancelFunc
+	maintenanceCancel    context.CancelFunc
+	activityDigestCancel context.CancelFunc
+	renderingCancel      context.CancelFunc
+	sessionPoolCancel    context.CancelFunc
+	heartbeatCancel      context.CancelFunc
+	sessionLockCancel    context.CancelFunc
+	pipeTakeoverCancel   context.CancelFunc
+	bgWG                 sync.WaitGroup
+	setupWG              sync.WaitGroup
+	setupCancelMu        sync.Mutex
+	setupCancels         map[uint64]context.CancelFunc
+	nextSetupCancelID    atomic.Uint64
 }
 
 // NewApp creates the app service.
@@ -186,17 +438,31 @@ func NewApp() *App {
 	app := &App{
 		hotkeys:        hotkeys.NewManager(),
 		paneStates:     panestate.NewManager(512 * 1024),
+		eventFilter:    eventsub.NewFilter(),
 		configState:    config.NewStateService(),
 		setupCancels:   make(map[uint64]context.CancelFunc),
 		sendKeys:       defaultSendKeysIO(),
 		openExplorerFn: openExplorer,
+
+		sessionBadgeFactsFn: resolveSessionBadgeFacts,
+		runCommandHookFn:    runCommandHook,
+		startupMetrics:      startupmetrics.NewRecorder(nil),
 	}
 	app.configDirProvider = appConfigDirProvider(app)
+	app.subscribeConfigConsumers()
 
 	emitter := newAppRuntimeEventEmitterAdapter(app)
 	isShuttingDown := func() bool { return app.shuttingDown.Load() }
 
 	app.sessionLogService = sessionlog.NewService(emitter, isShuttingDown)
+	app.repoConfigService = repoconfig.NewService(repoconfig.Deps{
+		ConfigDir: app.configDirProvider,
+		Emitter:   emitter,
+		SigningPolicy: func() *config.RepoConfigSettings {
+			return app.configState.Snapshot().RepoConfig
+		},
+	})
+	app.preOpSnapshotService = preopsnapshot.NewService(buildPreOpSnapshotServiceDeps(app))
 	app.sessionService = session.NewService(buildSessionServiceDeps(app))
 	app.inputHistoryService = inputhistory.NewService(
 		emitter,
@@ -211,14 +477,48 @@ func NewApp() *App {
 	app.orchestratorService = orchestrator.NewService(buildOrchestratorServiceDeps(app))
 	app.promptPresetsService = promptpresets.NewService(buildPromptPresetsServiceDeps(app))
 	app.sessionMemoService = sessionmemo.NewService(buildSessionMemoServiceDeps(app))
+	app.sessionBadgeService = sessionbadge.NewService(buildSessionBadgeServiceDeps(app))
+	app.recentDirsService = recentdirs.NewService(recentdirs.Deps{Store: app.requireStateStore})
+	app.layoutPresetService = layoutpreset.NewService(buildLayoutPresetServiceDeps(app))
 	app.devpanelService = devpanel.NewService(buildDevPanelServiceDeps(app))
 	app.worktreeService = worktree.NewService(buildWorktreeServiceDeps(app))
 	app.mcpAPIService = mcpapi.NewService(buildMCPAPIServiceDeps(app))
 	app.usageDashboard = usagedashboard.NewService(buildUsageDashboardServiceDeps(app))
 	app.snapshotService = snapshot.NewService(buildSnapshotServiceDeps(app))
 	app.schedulerService = scheduler.NewService(buildSchedulerServiceDeps(app))
+	app.commandQueueService = cmdqueue.NewService(buildCommandQueueServiceDeps(app))
+	app.outputWatchService = outputwatch.NewService(buildOutputWatchServiceDeps(app))
+	app.bringUpService = bringup.NewService(buildBringUpServiceDeps(app))
+	app.sessionGroupService = sessiongroup.NewService(buildSessionGroupServiceDeps(app))
+	app.sessionPortsService = sessionports.NewService(buildSessionPortsServiceDeps(app))
+	app.paneActivityService = paneactivity.NewService(buildPaneActivityServiceDeps(app))
+	app.outputSpill = outputspill.NewSpool(buildOutputSpillDeps(app))
+	app.gitStatusWatcher = gitpkg.NewStatusWatcher(buildGitStatusWatcherDeps(app))
+	app.setupWatchService = setupwatch.NewService(buildSetupWatchServiceDeps(app))
+	app.ephemeralService = ephemeral.NewService(buildEphemeralServiceDeps(app))
+	app.sessionCopyService = sessioncopy.NewService(sessioncopy.Deps{
+		SessionRoot: app.sessionService.ResolveSessionWorkDir,
+		Emitter:     newAppRuntimeEventEmitterAdapter(app),
+	})
+	app.networkCapture = netcapture.NewService(netcapture.Deps{LogPath: app.networkCaptureLogPath})
+	app.jumpListService = jumplist.NewService(buildJumpListServiceDeps(app))
+	app.taskbarService = taskbar.NewService(taskbar.Deps{Alerts: app.taskbarAlerts})
+	app.webhookService = webhook.NewService(webhook.Deps{})
+	app.errorReporter = errreport.New(errreport.Deps{Emit: app.emitRuntimeEvent}, errreport.Options{})
+	app.repoStatsService = repostats.NewService(repostats.Deps{})
 	app.taskSchedulerManager = taskscheduler.NewServiceManager(buildTaskSchedulerDepsFactory(app))
 	app.singleTaskRunnerManager = singletaskrunner.NewServiceManager(buildSingleTaskRunnerDepsFactory(app))
+	app.metrics = metrics.NewRegistry(buildMetricsDeps(app))
+	app.metricsServer = metrics.NewServer(app.metrics)
+	app.resourceLimits = resourcelimit.NewManager()
+	app.resourceSampler = resourcelimit.NewSampler()
+	app.commandApproval = cmdapproval.NewGate(buildCommandApprovalDeps(app))
+	app.maintenanceService = maintenance.NewService(buildMaintenanceServiceDeps(app))
+	app.registerMaintenanceJobs()
+	app.activityDigestService = activitydigest.NewService(buildActivityDigestDeps(app))
+	app.renderingMonitor = rendering.NewMonitor(buildRenderingMonitorDeps(app))
+	app.sessionPool = shellpool.NewPool(buildSessionPoolDeps())
+	app.powerMonitor = powerstate.NewMonitor(buildPowerMonitorDeps(app))
 	return app
 }
 
@@ -233,3 +533,49 @@ func (a *App) GetWebSocketURL() string {
 	}
 	return a.wsHub.URL()
 }
+
+// GetScreenSyncURL returns the view-only screen sync WebSocket URL. Viewers
+// connected there receive a keyframe per watched pane followed by cell-level
+// diffs of the emulated screen instead of raw terminal output.
+// Returns empty string if the WebSocket server is not available.
+// Wails-bound: called from the frontend.
+func (a *App) GetScreenSyncURL() string {
+	if a.wsHub == nil {
+		return ""
+	}
+	return a.wsHub.ScreenURL()
+}
+
+// GetPaneStreamURL returns the pane output stream WebSocket URL. Clients
+// connected there subscribe to session, window or pane topics and receive
+// raw pane output as it is flushed.
+// Returns empty string if the WebSocket server is not available.
+// Wails-bound: called from the frontend.
+func (a *App) GetPaneStreamURL() string {
+	if a.wsHub == nil {
+		return ""
+	}
+	return a.wsHub.StreamURL()
+}
+
+// paneLocation adapts the session manager to the pane stream topics.
+func (a *App) paneLocation(paneID string) (wsserver.PaneLocation, bool) {
+	sessions, err := a.requireSessions()
+	if err != nil {
+		return wsserver.PaneLocation{}, false
+	}
+	sessionName, windowID, ok := sessions.PaneLocation(paneID)
+	if !ok {
+		return wsserver.PaneLocation{}, false
+	}
+	return wsserver.PaneLocation{Session: sessionName, WindowID: windowID}, true
+}
+
+// paneScreen adapts the pane state emulator to the screen sync endpoint.
+func (a *App) paneScreen(paneID string) (screensync.Screen, bool) {
+	screen, ok := a.paneStates.Screen(paneID)
+	if !ok {
+		return screensync.Screen{}, false
+	}
+	return screensync.Screen{Cols: screen.Cols, Rows: screen.Rows, Lines: screen.Lines}, true
+}
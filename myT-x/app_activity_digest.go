@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"time"
+
+	"myT-x/internal/activitydigest"
+	"myT-x/internal/config"
+	"myT-x/internal/tmux"
+	"myT-x/internal/workerutil"
+)
+
+// activityDigestDirName is the default digest directory under the config dir.
+const activityDigestDirName = "digests"
+
+// buildActivityDigestDeps constructs the dependency set for the daily
+// activity digest. Settings are read from the config snapshot on every use,
+// so activity_digest changes apply without a restart.
+func buildActivityDigestDeps(app *App) activitydigest.Deps {
+	configDir := appConfigDirProvider(app)
+	return activitydigest.Deps{
+		Settings: func() *config.ActivityDigestConfig {
+			return app.configState.Snapshot().ActivityDigest
+		},
+		DefaultDir: func() (string, error) {
+			dir, err := configDir()
+			if err != nil {
+				return "", err
+			}
+			return filepath.Join(dir, activityDigestDirName), nil
+		},
+		Sessions: app.activityDigestSessions,
+		Commands: func(workDir, sessionName string, day time.Time) ([]string, error) {
+			entries, err := app.ensureInputHistoryService().EntriesForDay(workDir, sessionName, day)
+			if err != nil {
+				return nil, err
+			}
+			commands := make([]string, 0, len(entries))
+			for _, entry := range entries {
+				commands = append(commands, entry.Input)
+			}
+			return commands, nil
+		},
+		StatePath: func() (string, error) {
+			dir, err := configDir()
+			if err != nil {
+				return "", err
+			}
+			return filepath.Join(dir, activitydigest.StateFileName), nil
+		},
+		Emitter: newAppRuntimeEventEmitterAdapter(app),
+	}
+}
+
+// activityDigestSessions returns the live sessions with their work directory
+// and latest output time.
+// Wired as activitydigest.Deps.Sessions.
+func (a *App) activityDigestSessions() []activitydigest.SessionInfo {
+	sessions, err := a.requireSessions()
+	if err != nil {
+		return nil
+	}
+	activities := sessions.SessionActivities()
+	var infos []activitydigest.SessionInfo
+	for _, snapshot := range sessions.Snapshot() {
+		workDir, err := a.sessionService.ResolveSessionWorkDir(snapshot.Name)
+		if err != nil {
+			workDir = ""
+		}
+		infos = append(infos, activitydigest.SessionInfo{
+			Name:         snapshot.Name,
+			WorkDir:      workDir,
+			LastActivity: activities[snapshot.Name].LastActivity,
+		})
+	}
+	return infos
+}
+
+// startActivityDigest samples session activity and writes the daily digest
+// while activity_digest is configured.
+func (a *App) startActivityDigest(parent context.Context) {
+	ctx, cancel := context.WithCancel(parent)
+	a.activityDigestCancel = cancel
+	workerutil.RunWithPanicRecovery(ctx, "activity-digest", &a.bgWG, a.activityDigestService.Run, a.defaultRecoveryOptions())
+}
+
+// recordActivityDigestCommand counts a finished command in the digest,
+// using the pane's last input to recognize test runs.
+func (a *App) recordActivityDigestCommand(paneID string, paneCtx tmux.PaneContextSnapshot, exitCode int) {
+	if a.activityDigestService == nil {
+		return
+	}
+	command := a.ensureInputHistoryService().LastInputForPane(paneCtx.SessionName, paneID)
+	a.activityDigestService.RecordCommandFinished(paneCtx.SessionName, paneCtx.SessionWorkDir, command, exitCode)
+}
+
+// GenerateActivityDigest writes the activity digest of the current day so
+// far and returns the path of the Markdown file.
+// Wails-bound: called from the frontend.
+func (a *App) GenerateActivityDigest() (string, error) {
+	if a.activityDigestService == nil {
+		return "", errors.New("activity digest service is unavailable")
+	}
+	return a.activityDigestService.GenerateNow()
+}
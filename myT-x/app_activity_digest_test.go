@@ -0,0 +1,33 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"myT-x/internal/activitydigest"
+)
+
+func TestBuildActivityDigestDepsPaths(t *testing.T) {
+	app := NewApp()
+	dir := t.TempDir()
+	app.configDirProvider = func() (string, error) { return dir, nil }
+	deps := buildActivityDigestDeps(app)
+
+	if got, err := deps.DefaultDir(); err != nil || got != filepath.Join(dir, activityDigestDirName) {
+		t.Fatalf("DefaultDir() = %q, %v", got, err)
+	}
+	if got, err := deps.StatePath(); err != nil || got != filepath.Join(dir, activitydigest.StateFileName) {
+		t.Fatalf("StatePath() = %q, %v", got, err)
+	}
+	if got := deps.Settings(); got != nil {
+		t.Fatalf("Settings() = %+v, want nil without activity_digest", got)
+	}
+}
+
+func TestGenerateActivityDigestDisabled(t *testing.T) {
+	app := NewApp()
+	if _, err := app.GenerateActivityDigest(); !errors.Is(err, activitydigest.ErrDisabled) {
+		t.Fatalf("GenerateActivityDigest() error = %v, want ErrDisabled", err)
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"myT-x/internal/bugreport"
+	"myT-x/internal/statestore"
+)
+
+const (
+	// bugReportDirName is the directory next to the config file that
+	// receives bug report zips.
+	bugReportDirName = "bug-reports"
+	// bugReportAuditEntries is the number of most recent audit entries
+	// included in a bug report.
+	bugReportAuditEntries = 50
+	// bugReportIssueURL is the new-issue page prefilled by CreateBugReport.
+	bugReportIssueURL = "https://github.com/my-take-dev/myT-x/issues/new"
+)
+
+// shimLogFileNames are the tmux-shim logs under %LOCALAPPDATA%\myT-x.
+var shimLogFileNames = []string{"shim-debug.log", "tmux-compat.log"}
+
+// BugReportResult is the outcome of CreateBugReport.
+type BugReportResult struct {
+	// Path is the written zip.
+	Path string `json:"path"`
+	// IssueURL opens a new GitHub issue prefilled with the environment of
+	// the report. The zip itself must be attached by hand.
+	IssueURL string             `json:"issue_url"`
+	Manifest bugreport.Manifest `json:"manifest"`
+}
+
+// CreateBugReport writes a zip of the recent host and shim logs, the
+// redacted config, a summary of the sessions, recent errors, and the last
+// audit entries under <config dir>/bug-reports, and returns its path with
+// a prefilled new-issue URL.
+// Wails-bound: called from the frontend.
+func (a *App) CreateBugReport() (BugReportResult, error) {
+	dir, err := a.configDirProvider()
+	if err != nil {
+		return BugReportResult{}, fmt.Errorf("resolve config directory: %w", err)
+	}
+	now := time.Now()
+	path := filepath.Join(dir, bugReportDirName, "mytx-bug-report-"+now.Format("20060102-150405")+".zip")
+	homeDir, _ := os.UserHomeDir()
+
+	manifest, err := bugreport.WriteFile(path, bugreport.Input{
+		Version:   appVersion,
+		CreatedAt: now,
+		LogFiles:  a.bugReportLogFiles(dir),
+		Config:    a.configState.Snapshot(),
+		Sessions:  a.currentHeartbeatSessions(),
+		Errors:    a.ensureSessionLogService().Snapshot(),
+		Audit:     a.bugReportAuditEntries(),
+		HomeDir:   homeDir,
+	})
+	if err != nil {
+		return BugReportResult{}, err
+	}
+	slog.Info("[BUG-REPORT] created", "path", path, "files", len(manifest.Files), "skipped", len(manifest.Skipped))
+	return BugReportResult{
+		Path:     path,
+		IssueURL: bugReportIssue(manifest, filepath.Base(path)),
+		Manifest: manifest,
+	}, nil
+}
+
+// bugReportLogFiles returns the component logs and the shim logs. The shim
+// logs are looked up next to the config file too, for custom config paths.
+func (a *App) bugReportLogFiles(configDir string) []string {
+	var files []string
+	if a.logs != nil && a.logs.Dir() != "" {
+		files = bugreport.ActiveLogs(a.logs.Dir())
+	}
+	shimDirs := []string{configDir}
+	if localAppData := os.Getenv("LOCALAPPDATA"); localAppData != "" {
+		shimDirs = append(shimDirs, filepath.Join(localAppData, "myT-x"))
+	}
+	seen := map[string]bool{}
+	for _, dir := range shimDirs {
+		for _, name := range shimLogFileNames {
+			path := filepath.Clean(filepath.Join(dir, name))
+			if seen[strings.ToLower(path)] {
+				continue
+			}
+			seen[strings.ToLower(path)] = true
+			if _, err := os.Stat(path); err == nil {
+				files = append(files, path)
+			}
+		}
+	}
+	return files
+}
+
+// bugReportAuditEntry is an audit log entry with its decoded value.
+type bugReportAuditEntry struct {
+	ID        int64           `json:"id"`
+	Stream    string          `json:"stream"`
+	Value     json.RawMessage `json:"value"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// bugReportAuditEntries returns the last bugReportAuditEntries command
+// approval decisions, or nil when the state store is unavailable.
+func (a *App) bugReportAuditEntries() []bugReportAuditEntry {
+	store, err := a.requireStateStore()
+	if err != nil {
+		slog.Debug("[BUG-REPORT] audit log unavailable", "error", err)
+		return nil
+	}
+	entries, err := store.ReadLog(context.Background(), statestore.StreamCommandApprovals, 0, 0)
+	if err != nil {
+		slog.Warn("[BUG-REPORT] failed to read audit log", "error", err)
+		return nil
+	}
+	entries = entries[max(0, len(entries)-bugReportAuditEntries):]
+	result := make([]bugReportAuditEntry, 0, len(entries))
+	for _, entry := range entries {
+		value := json.RawMessage(entry.Value)
+		if !json.Valid(value) {
+			value, _ = json.Marshal(string(entry.Value))
+		}
+		result = append(result, bugReportAuditEntry{ID: entry.ID, Stream: entry.Stream, Value: value, CreatedAt: entry.CreatedAt})
+	}
+	return result
+}
+
+// bugReportIssue builds the new-issue URL for a report named zipName.
+func bugReportIssue(manifest bugreport.Manifest, zipName string) string {
+	var body strings.Builder
+	body.WriteString("## What happened\n\n\n## Steps to reproduce\n\n1. \n\n## Environment\n\n")
+	fmt.Fprintf(&body, "- myT-x: %s\n- OS: %s/%s\n- Go: %s\n\n", manifest.Version, manifest.OS, manifest.Arch, manifest.GoVersion)
+	fmt.Fprintf(&body, "Please attach `%s` (secrets are redacted; review it before attaching).\n", zipName)
+	query := url.Values{}
+	query.Set("title", "[bug] ")
+	query.Set("body", body.String())
+	return bugReportIssueURL + "?" + query.Encode()
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"myT-x/internal/bugreport"
+	"myT-x/internal/config"
+	"myT-x/internal/logging"
+	"myT-x/internal/statestore"
+)
+
+func TestCreateBugReport(t *testing.T) {
+	origEmit := runtimeEventsEmitFn
+	t.Cleanup(func() {
+		runtimeEventsEmitFn = origEmit
+	})
+	runtimeEventsEmitFn = func(context.Context, string, ...any) {}
+	t.Setenv("LOCALAPPDATA", "")
+
+	app := NewApp()
+	configPath := newConfigPathForAPITest(t, "config.yaml")
+	app.setRuntimeContext(context.Background())
+	cfg := config.DefaultConfig()
+	cfg.PaneEnv = map[string]string{"GITHUB_TOKEN": "ghp_secret"}
+	app.configState.Initialize(configPath, cfg)
+	app.logs = logging.NewManager(loggingOptions(configPath, nil))
+	store := statestore.NewMemoryStore()
+	app.stateStore = store
+	for i := range bugReportAuditEntries + 5 {
+		if _, err := store.Append(context.Background(), statestore.StreamCommandApprovals, fmt.Appendf(nil, `{"n":%d}`, i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	configDir := filepath.Dir(configPath)
+	if err := os.MkdirAll(app.logs.Dir(), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(app.logs.Dir(), "app.log"), []byte("started\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(configDir, "shim-debug.log"), []byte("shim\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := app.CreateBugReport()
+	if err != nil {
+		t.Fatalf("CreateBugReport() error = %v", err)
+	}
+	if filepath.Dir(result.Path) != filepath.Join(configDir, bugReportDirName) {
+		t.Fatalf("Path = %q", result.Path)
+	}
+	if !strings.HasPrefix(result.IssueURL, bugReportIssueURL+"?") {
+		t.Fatalf("IssueURL = %q", result.IssueURL)
+	}
+
+	reader, err := zip.OpenReader(result.Path)
+	if err != nil {
+		t.Fatalf("OpenReader() error = %v", err)
+	}
+	defer reader.Close()
+	names := map[string]bool{}
+	for _, file := range reader.File {
+		names[file.Name] = true
+	}
+	for _, want := range []string{bugreport.ManifestName, "config.json", "audit.json", "logs/app.log", "logs/shim-debug.log"} {
+		if !names[want] {
+			t.Fatalf("zip entries = %v, missing %s", names, want)
+		}
+	}
+	configJSON := readZipEntry(t, reader, "config.json")
+	if strings.Contains(configJSON, "ghp_secret") {
+		t.Fatalf("config.json leaked pane_env: %s", configJSON)
+	}
+	audit := readZipEntry(t, reader, "audit.json")
+	if strings.Contains(audit, "\"n\": 4\n") || !strings.Contains(audit, fmt.Sprintf("\"n\": %d\n", bugReportAuditEntries+4)) {
+		t.Fatalf("audit.json should hold the last %d entries: %s", bugReportAuditEntries, audit)
+	}
+}
+
+func TestBugReportIssue(t *testing.T) {
+	issue := bugReportIssue(bugreport.Manifest{Version: "1.2.3", OS: "windows", Arch: "amd64", GoVersion: "go1.25"}, "report.zip")
+	parsed, err := url.Parse(issue)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	body := parsed.Query().Get("body")
+	for _, want := range []string{"myT-x: 1.2.3", "windows/amd64", "`report.zip`"} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("body = %q, want %q", body, want)
+		}
+	}
+}
+
+func readZipEntry(t *testing.T, reader *zip.ReadCloser, name string) string {
+	t.Helper()
+	file, err := reader.Open(name)
+	if err != nil {
+		t.Fatalf("Open(%s) error = %v", name, err)
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		t.Fatalf("ReadAll(%s) error = %v", name, err)
+	}
+	return string(data)
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"myT-x/internal/changelog"
+	"myT-x/internal/statestore"
+)
+
+// changelogSeenVersionKey holds the app version whose changelog the user
+// last saw, in statestore.BucketChangelog.
+const changelogSeenVersionKey = "seen-version"
+
+// ChangelogPayload carries the release notes to show after an update.
+type ChangelogPayload struct {
+	CurrentVersion string `json:"current_version"`
+	// PreviousVersion is the version whose changelog was last acknowledged,
+	// or "" on the first run.
+	PreviousVersion string `json:"previous_version"`
+	// Updated is true when the app was updated since then.
+	Updated bool `json:"updated"`
+	// Releases are the releases since PreviousVersion, newest first.
+	Releases []changelog.Release `json:"releases"`
+}
+
+// GetChangelog returns the release notes of the versions installed since the
+// changelog was last acknowledged. The first run records the current version
+// without showing anything, so new installs start with a clean slate.
+// Wails-bound: called from the frontend at startup.
+func (a *App) GetChangelog() (ChangelogPayload, error) {
+	payload := ChangelogPayload{CurrentVersion: appVersion, Releases: []changelog.Release{}}
+	store, err := a.requireStateStore()
+	if err != nil {
+		return payload, err
+	}
+	ctx := context.Background()
+	seen, err := store.Get(ctx, statestore.BucketChangelog, changelogSeenVersionKey)
+	if errors.Is(err, statestore.ErrNotFound) {
+		if err := a.AcknowledgeChangelog(); err != nil {
+			slog.Warn("[WARN-CHANGELOG] failed to record the first-run version", "error", err)
+		}
+		return payload, nil
+	}
+	if err != nil {
+		return payload, fmt.Errorf("read changelog state: %w", err)
+	}
+
+	payload.PreviousVersion = string(seen)
+	if changelog.CompareVersions(appVersion, payload.PreviousVersion) <= 0 {
+		return payload, nil
+	}
+	releases, err := changelog.Releases()
+	if err != nil {
+		return payload, err
+	}
+	payload.Updated = true
+	if picked := changelog.Between(releases, payload.PreviousVersion, appVersion); picked != nil {
+		payload.Releases = picked
+	}
+	return payload, nil
+}
+
+// AcknowledgeChangelog records that the changelog of the running version was
+// shown, so GetChangelog reports no update until the next one.
+// Wails-bound: called from the frontend.
+func (a *App) AcknowledgeChangelog() error {
+	store, err := a.requireStateStore()
+	if err != nil {
+		return err
+	}
+	if err := store.Put(context.Background(), statestore.BucketChangelog, changelogSeenVersionKey, []byte(appVersion)); err != nil {
+		return fmt.Errorf("write changelog state: %w", err)
+	}
+	return nil
+}
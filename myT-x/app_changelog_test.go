@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"myT-x/internal/statestore"
+)
+
+func TestGetChangelogRecordsFirstRunWithoutShowingNotes(t *testing.T) {
+	app := NewApp()
+	app.stateStore = statestore.NewMemoryStore()
+
+	payload, err := app.GetChangelog()
+	if err != nil {
+		t.Fatalf("GetChangelog() error = %v", err)
+	}
+	if payload.Updated || len(payload.Releases) != 0 {
+		t.Fatalf("first run payload = %+v, want no update", payload)
+	}
+	// The first run recorded the version, so the next start is quiet too.
+	if payload, err = app.GetChangelog(); err != nil || payload.Updated || payload.PreviousVersion != appVersion {
+		t.Fatalf("second GetChangelog() = %+v, %v", payload, err)
+	}
+}
+
+func TestGetChangelogShowsReleasesSinceSeenVersion(t *testing.T) {
+	app := NewApp()
+	store := statestore.NewMemoryStore()
+	app.stateStore = store
+	if err := store.Put(context.Background(), statestore.BucketChangelog, changelogSeenVersionKey, []byte("1.1.2")); err != nil {
+		t.Fatal(err)
+	}
+
+	payload, err := app.GetChangelog()
+	if err != nil {
+		t.Fatalf("GetChangelog() error = %v", err)
+	}
+	if !payload.Updated || len(payload.Releases) == 0 || payload.Releases[0].Version != appVersion {
+		t.Fatalf("payload = %+v, want releases up to %s", payload, appVersion)
+	}
+
+	if err := app.AcknowledgeChangelog(); err != nil {
+		t.Fatalf("AcknowledgeChangelog() error = %v", err)
+	}
+	if payload, err = app.GetChangelog(); err != nil || payload.Updated {
+		t.Fatalf("GetChangelog() after acknowledge = %+v, %v", payload, err)
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"myT-x/internal/cmdapproval"
+	"myT-x/internal/statestore"
+)
+
+var errCommandApprovalUnavailable = errors.New("command approval is unavailable")
+
+// SetSessionApprovalMode turns command approval on or off for a session.
+// While on, commands sent via the shim from the session's panes are held
+// until the user resolves them with ResolveCommandApproval.
+// Wails-bound: called from the frontend.
+func (a *App) SetSessionApprovalMode(sessionName string, enabled bool) error {
+	if a.commandApproval == nil {
+		return errCommandApprovalUnavailable
+	}
+	sessionName = strings.TrimSpace(sessionName)
+	if enabled {
+		sessions, err := a.requireSessions()
+		if err != nil {
+			return err
+		}
+		if !sessions.HasSession(sessionName) {
+			return fmt.Errorf("session not found: %s", sessionName)
+		}
+	}
+	return a.commandApproval.SetEnabled(sessionName, enabled)
+}
+
+// GetSessionApprovalMode returns whether command approval is on for a session
+// and which commands were answered with allow-always.
+// Wails-bound: called from the frontend.
+func (a *App) GetSessionApprovalMode(sessionName string) cmdapproval.SessionApproval {
+	if a.commandApproval == nil {
+		return cmdapproval.SessionApproval{SessionName: sessionName, AllowedCommands: []string{}}
+	}
+	return a.commandApproval.Session(sessionName)
+}
+
+// GetPendingCommandApprovals returns the commands waiting for a decision.
+// Wails-bound: called from the frontend.
+func (a *App) GetPendingCommandApprovals() []cmdapproval.PendingCommand {
+	if a.commandApproval == nil {
+		return []cmdapproval.PendingCommand{}
+	}
+	return a.commandApproval.Pending()
+}
+
+// ResolveCommandApproval answers a held command with "allow", "deny", or
+// "allow-always".
+// Wails-bound: called from the frontend.
+func (a *App) ResolveCommandApproval(id string, decision string) error {
+	if a.commandApproval == nil {
+		return errCommandApprovalUnavailable
+	}
+	return a.commandApproval.Resolve(id, cmdapproval.Decision(strings.TrimSpace(decision)))
+}
+
+// commandApprovalAuditEntry is one decision in the command approval audit log.
+type commandApprovalAuditEntry struct {
+	cmdapproval.PendingCommand
+	Decision cmdapproval.Decision `json:"decision"`
+	Reason   string               `json:"reason"`
+}
+
+// recordCommandApproval appends a decision to the command approval audit log.
+// Failures are logged only: auditing must never block the decision itself.
+func (a *App) recordCommandApproval(command cmdapproval.PendingCommand, resolution cmdapproval.Resolution) {
+	store, err := a.requireStateStore()
+	if err != nil {
+		slog.Warn("[APPROVAL] audit log unavailable", "id", command.ID, "error", err)
+		return
+	}
+	data, err := json.Marshal(commandApprovalAuditEntry{
+		PendingCommand: command,
+		Decision:       resolution.Decision,
+		Reason:         resolution.Reason,
+	})
+	if err != nil {
+		slog.Warn("[APPROVAL] failed to encode audit entry", "id", command.ID, "error", err)
+		return
+	}
+	if _, err := store.Append(context.Background(), statestore.StreamCommandApprovals, data); err != nil {
+		slog.Warn("[APPROVAL] failed to append audit entry", "id", command.ID, "error", err)
+	}
+}
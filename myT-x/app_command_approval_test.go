@@ -0,0 +1,44 @@
+package main
+
+import (
+	"testing"
+
+	"myT-x/internal/tmux"
+)
+
+func TestSetSessionApprovalModeRequiresExistingSession(t *testing.T) {
+	app := NewApp()
+	app.sessions = tmux.NewSessionManager()
+	if _, _, err := app.sessions.CreateSession("agent", "0", 120, 40); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+
+	if err := app.SetSessionApprovalMode("missing", true); err == nil {
+		t.Fatal("SetSessionApprovalMode(missing) error = nil")
+	}
+	if err := app.SetSessionApprovalMode("agent", true); err != nil {
+		t.Fatalf("SetSessionApprovalMode() error = %v", err)
+	}
+	if got := app.GetSessionApprovalMode("agent"); !got.Enabled {
+		t.Fatalf("GetSessionApprovalMode() = %+v, want enabled", got)
+	}
+	if err := app.SetSessionApprovalMode("agent", false); err != nil {
+		t.Fatalf("SetSessionApprovalMode(false) error = %v", err)
+	}
+	if got := app.GetSessionApprovalMode("agent"); got.Enabled {
+		t.Fatalf("GetSessionApprovalMode() = %+v, want disabled", got)
+	}
+}
+
+func TestCommandApprovalAPIWithoutGate(t *testing.T) {
+	app := &App{}
+	if err := app.SetSessionApprovalMode("agent", true); err == nil {
+		t.Fatal("SetSessionApprovalMode() error = nil without gate")
+	}
+	if err := app.ResolveCommandApproval("approval-1", "allow"); err == nil {
+		t.Fatal("ResolveCommandApproval() error = nil without gate")
+	}
+	if got := app.GetPendingCommandApprovals(); got == nil || len(got) != 0 {
+		t.Fatalf("GetPendingCommandApprovals() = %v, want empty", got)
+	}
+}
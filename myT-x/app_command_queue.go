@@ -0,0 +1,44 @@
+package main
+
+import (
+	"log/slog"
+
+	"myT-x/internal/cmdqueue"
+	"myT-x/internal/shellintegration"
+)
+
+// EnqueueCommands queues shell commands for a pane. Commands are sent one at
+// a time; each waits for the shell prompt (detected via shell integration
+// markers) or the options timeout before the next is sent. With
+// options.KillPolicy, a command still running at the timeout is interrupted
+// and reported via cmdqueue.TimeoutEvent.
+// Wails-bound: called from the frontend.
+func (a *App) EnqueueCommands(paneID string, commands []string, options cmdqueue.Options) (cmdqueue.QueueStatus, error) {
+	return a.commandQueueService.Enqueue(paneID, commands, options)
+}
+
+// GetCommandQueue returns the queued, running, and recently finished
+// commands of a pane.
+// Wails-bound: called from the frontend.
+func (a *App) GetCommandQueue(paneID string) cmdqueue.QueueStatus {
+	return a.commandQueueService.Status(paneID)
+}
+
+// CancelCommandQueue cancels the pending commands of a pane and stops
+// waiting for the running one.
+// Wails-bound: called from the frontend.
+func (a *App) CancelCommandQueue(paneID string) error {
+	return a.commandQueueService.Cancel(paneID)
+}
+
+// handleShellMarkers dispatches shell integration markers found in pane output.
+// Wired as snapshot.Deps.OnShellMarkers.
+func (a *App) handleShellMarkers(paneID string, markers []shellintegration.Marker) {
+	slog.Debug("[DEBUG-SHELL-INTEGRATION] markers", "paneID", paneID, "count", len(markers))
+	a.commandQueueService.HandleShellMarkers(paneID, markers)
+	for _, marker := range markers {
+		if marker.Kind == shellintegration.MarkerCommandFinished && marker.HasExitCode {
+			a.handleCommandFinished(paneID, marker.ExitCode)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"myT-x/internal/cmdqueue"
+	"myT-x/internal/shellintegration"
+	"myT-x/internal/tmux"
+)
+
+func TestEnqueueCommandsRequiresLivePane(t *testing.T) {
+	app := NewApp()
+	app.sessions = tmux.NewSessionManager()
+	t.Cleanup(app.sessions.Close)
+
+	if _, err := app.EnqueueCommands("%99", []string{"ls"}, cmdqueue.Options{}); err == nil || !strings.Contains(err.Error(), "does not exist") {
+		t.Fatalf("EnqueueCommands() error = %v, want missing pane error", err)
+	}
+	if status := app.GetCommandQueue("%99"); status.Running || len(status.Items) != 0 {
+		t.Fatalf("GetCommandQueue() = %+v, want empty idle queue", status)
+	}
+	if err := app.CancelCommandQueue("%99"); err == nil {
+		t.Fatal("CancelCommandQueue() error = nil, want error for pane without queue")
+	}
+}
+
+func TestEnqueueCommandsRoutesShellMarkers(t *testing.T) {
+	app := NewApp()
+	app.sessions = tmux.NewSessionManager()
+	t.Cleanup(app.sessions.Close)
+	_, pane, err := app.sessions.CreateSession("session-a", "main", 80, 24)
+	if err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	paneID := pane.IDString()
+
+	// Without a router the send fails; the queue reports it and stops.
+	status, err := app.EnqueueCommands(paneID, []string{"echo one", "echo two"}, cmdqueue.Options{})
+	if err != nil {
+		t.Fatalf("EnqueueCommands() error = %v", err)
+	}
+	if len(status.Items) != 2 {
+		t.Fatalf("EnqueueCommands() status = %+v, want two items", status)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for app.GetCommandQueue(paneID).Running {
+		if time.Now().After(deadline) {
+			t.Fatal("queue did not stop after send failure")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	status = app.GetCommandQueue(paneID)
+	if status.Items[0].State != cmdqueue.ItemFailed || status.Items[1].State != cmdqueue.ItemCancelled {
+		t.Fatalf("status = %+v, want failed then cancelled", status)
+	}
+
+	app.handleShellMarkers(paneID, []shellintegration.Marker{{Kind: shellintegration.MarkerPromptStart}})
+	if !app.GetCommandQueue(paneID).ShellIntegration {
+		t.Fatal("ShellIntegration = false after markers were routed to the pane queue")
+	}
+}
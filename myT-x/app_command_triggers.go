@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"myT-x/internal/config"
+	"myT-x/internal/procutil"
+	"myT-x/internal/workerutil"
+)
+
+const (
+	// commandFinishedEvent carries the exit status of a shell command
+	// reported by shell integration.
+	commandFinishedEvent = "pane:command-finished"
+
+	// commandHookTimeout bounds a command_triggers hook run.
+	commandHookTimeout = 5 * time.Minute
+)
+
+// handleCommandFinished propagates the exit status of a finished command and
+// fires the command_triggers configured for the pane's session.
+func (a *App) handleCommandFinished(paneID string, exitCode int) {
+	sessions, err := a.requireSessions()
+	if err != nil {
+		return
+	}
+	paneCtx, err := paneContextSnapshot(sessions, paneID)
+	if err != nil {
+		slog.Debug("[DEBUG-TRIGGER] pane context unavailable", "paneID", paneID, "error", err)
+		return
+	}
+
+	a.recordActivityDigestCommand(paneID, paneCtx, exitCode)
+
+	triggers := a.commandTriggersForSession(paneCtx.SessionName)
+	action := triggers.OnSuccess
+	if exitCode != 0 {
+		action = triggers.OnFailure
+	}
+
+	a.emitRuntimeEvent(commandFinishedEvent, map[string]any{
+		"paneId":      paneID,
+		"sessionName": paneCtx.SessionName,
+		"exitCode":    exitCode,
+		"notify":      action.Notify,
+	})
+	if action.Hook == "" {
+		return
+	}
+
+	env := append(os.Environ(),
+		"MYTX_PANE_ID="+paneID,
+		"MYTX_SESSION="+paneCtx.SessionName,
+		"MYTX_EXIT_CODE="+strconv.Itoa(exitCode),
+	)
+	a.launchCommandHook(action.Hook, paneCtx.SessionWorkDir, env)
+}
+
+// launchCommandHook runs hook in the background so pane output processing
+// never waits for it.
+func (a *App) launchCommandHook(hook, workDir string, env []string) {
+	parentCtx := a.runtimeContext()
+	if parentCtx == nil {
+		slog.Warn("[WARN-TRIGGER] runtime context nil, skipping command hook", "hook", hook)
+		return
+	}
+	opts := a.defaultRecoveryOptions()
+	opts.MaxRetries = 1 // Never re-run a hook after a panic.
+	workerutil.RunWithPanicRecovery(parentCtx, "command-trigger-hook", &a.bgWG, func(ctx context.Context) {
+		ctx, cancel := context.WithTimeout(ctx, commandHookTimeout)
+		defer cancel()
+		if err := a.runCommandHookFn(ctx, hook, workDir, env); err != nil {
+			slog.Warn("[WARN-TRIGGER] command hook failed", "hook", hook, "error", err)
+			return
+		}
+		slog.Debug("[DEBUG-TRIGGER] command hook finished", "hook", hook)
+	}, opts)
+}
+
+// runCommandHook runs hook through cmd.exe, matching run-shell.
+func runCommandHook(ctx context.Context, hook, workDir string, env []string) error {
+	cmd := exec.CommandContext(ctx, "cmd.exe", "/C", hook)
+	cmd.Dir = workDir
+	cmd.Env = env
+	procutil.HideWindow(cmd)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, truncateHookOutput(output))
+	}
+	return nil
+}
+
+// truncateHookOutput keeps hook failure logs short.
+func truncateHookOutput(output []byte) string {
+	const maxLen = 512
+	if len(output) > maxLen {
+		output = output[:maxLen]
+	}
+	return string(output)
+}
+
+// commandTriggersForSession returns the effective command_triggers of a session.
+func (a *App) commandTriggersForSession(sessionName string) config.CommandTriggers {
+	return a.configState.Snapshot().CommandTriggers.ForSession(sessionName)
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"myT-x/internal/config"
+	"myT-x/internal/shellintegration"
+	"myT-x/internal/tmux"
+)
+
+type hookCall struct {
+	hook string
+	env  []string
+}
+
+func newCommandTriggerTestApp(t *testing.T, triggers *config.CommandTriggersConfig) (*App, string, chan hookCall, *[]map[string]any) {
+	t.Helper()
+	origEmit := runtimeEventsEmitFn
+	t.Cleanup(func() { runtimeEventsEmitFn = origEmit })
+
+	app := NewApp()
+	app.setRuntimeContext(context.Background())
+	app.sessions = tmux.NewSessionManager()
+	t.Cleanup(app.sessions.Close)
+	cfg := config.DefaultConfig()
+	cfg.CommandTriggers = triggers
+	app.configState.SetSnapshot(cfg)
+
+	_, pane, err := app.sessions.CreateSession("session-a", "main", 80, 24)
+	if err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+
+	calls := make(chan hookCall, 4)
+	app.runCommandHookFn = func(_ context.Context, hook, _ string, env []string) error {
+		calls <- hookCall{hook: hook, env: env}
+		return nil
+	}
+	var events []map[string]any
+	runtimeEventsEmitFn = func(_ context.Context, name string, data ...any) {
+		if name != commandFinishedEvent || len(data) == 0 {
+			return
+		}
+		if payload, ok := data[0].(map[string]any); ok {
+			events = append(events, payload)
+		}
+	}
+	return app, pane.IDString(), calls, &events
+}
+
+func commandFinishedMarkers(exitCode int) []shellintegration.Marker {
+	return []shellintegration.Marker{{Kind: shellintegration.MarkerCommandFinished, ExitCode: exitCode, HasExitCode: true}}
+}
+
+func TestCommandFailureFiresNotifyAndHook(t *testing.T) {
+	app, paneID, calls, events := newCommandTriggerTestApp(t, &config.CommandTriggersConfig{
+		OnFailure: config.CommandTriggerAction{Notify: true, Hook: "notify-failure.cmd"},
+	})
+
+	app.handleShellMarkers(paneID, commandFinishedMarkers(3))
+
+	select {
+	case call := <-calls:
+		if call.hook != "notify-failure.cmd" {
+			t.Fatalf("hook = %q, want notify-failure.cmd", call.hook)
+		}
+		for _, want := range []string{"MYTX_PANE_ID=" + paneID, "MYTX_SESSION=session-a", "MYTX_EXIT_CODE=3"} {
+			if !slices.Contains(call.env, want) {
+				t.Fatalf("hook env missing %q", want)
+			}
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("hook was not run")
+	}
+	app.bgWG.Wait()
+
+	if len(*events) != 1 {
+		t.Fatalf("events = %v, want one %s event", *events, commandFinishedEvent)
+	}
+	got := (*events)[0]
+	if got["paneId"] != paneID || got["sessionName"] != "session-a" || got["exitCode"] != 3 || got["notify"] != true {
+		t.Fatalf("event payload = %v", got)
+	}
+}
+
+func TestCommandSuccessUsesSessionTriggers(t *testing.T) {
+	app, paneID, calls, events := newCommandTriggerTestApp(t, &config.CommandTriggersConfig{
+		OnFailure: config.CommandTriggerAction{Notify: true},
+		OnSuccess: config.CommandTriggerAction{Hook: "global.cmd"},
+		Sessions: map[string]config.CommandTriggers{
+			"session-a": {OnFailure: config.CommandTriggerAction{Hook: "session-failure.cmd"}},
+		},
+	})
+
+	// The session entry replaces the global triggers: no success hook.
+	app.handleShellMarkers(paneID, commandFinishedMarkers(0))
+	// Markers without an exit code carry no status to propagate.
+	app.handleShellMarkers(paneID, []shellintegration.Marker{{Kind: shellintegration.MarkerCommandFinished}})
+	app.bgWG.Wait()
+
+	select {
+	case call := <-calls:
+		t.Fatalf("unexpected hook run %q", call.hook)
+	default:
+	}
+	if len(*events) != 1 || (*events)[0]["exitCode"] != 0 || (*events)[0]["notify"] != false {
+		t.Fatalf("events = %v, want one success event without notify", *events)
+	}
+}
@@ -1,8 +1,11 @@
 package main
 
 import (
+	"encoding/json"
+	"fmt"
 	"log/slog"
 	"maps"
+	"slices"
 	"strings"
 
 	"myT-x/internal/config"
@@ -34,9 +37,11 @@ func (a *App) GetConfig() config.Config {
 	return a.configState.Snapshot()
 }
 
-// GetConfigAndFlushWarnings returns loaded config and emits any pending startup warnings.
+// GetConfigAndFlushWarnings returns loaded config, emits any pending startup
+// warnings, and opens the mytx:// link the app was launched with.
 func (a *App) GetConfigAndFlushWarnings() config.Config {
 	a.flushPendingConfigLoadWarnings()
+	a.flushPendingDeepLink()
 	return a.configState.Snapshot()
 }
 
@@ -63,6 +68,36 @@ func (a *App) SaveConfig(cfg config.Config) error {
 	return nil
 }
 
+// ConfigPatchResult is returned by ApplyConfigPatch.
+type ConfigPatchResult struct {
+	// Config is the normalized config that was saved.
+	Config config.Config `json:"config"`
+	// Changes lists every key that differs from the previous config.
+	Changes []config.KeyChange `json:"changes"`
+}
+
+// ApplyConfigPatch merges a JSON merge patch (RFC 7386) into the latest
+// config, validates and saves the result, and reports the changed keys.
+// Only the keys present in patch are touched, so concurrent edits to other
+// settings are not overwritten.
+// Wails-bound: called from the frontend.
+func (a *App) ApplyConfigPatch(patch map[string]any) (ConfigPatchResult, error) {
+	raw, err := json.Marshal(patch)
+	if err != nil {
+		return ConfigPatchResult{}, fmt.Errorf("config patch: %w", err)
+	}
+	event, err := a.configState.ApplyPatch(raw)
+	if err != nil {
+		return ConfigPatchResult{}, err
+	}
+	a.emitConfigUpdatedEvent(event)
+	changes := event.Diff.Changes
+	if changes == nil {
+		changes = []config.KeyChange{}
+	}
+	return ConfigPatchResult{Config: event.Config, Changes: changes}, nil
+}
+
 // ToggleViewerSidebarMode flips the persisted viewer sidebar mode using the
 // latest in-memory config snapshot under the save lock to avoid stale overwrite.
 func (a *App) ToggleViewerSidebarMode() error {
@@ -80,13 +115,92 @@ func (a *App) ToggleViewerSidebarMode() error {
 	return nil
 }
 
+// ConfigAppliedEvent reports which changed keys took effect after a save.
+// Keys are top-level config JSON keys (e.g. "global_hotkey").
+type ConfigAppliedEvent struct {
+	Version         uint64   `json:"version"`
+	Immediate       []string `json:"immediate"`
+	NextUse         []string `json:"next_use"`
+	RequiresRestart []string `json:"requires_restart"`
+}
+
+// subscribeConfigConsumers re-applies the runtime subsystems affected by a
+// config save. The callbacks run inside the save, before config:updated is
+// emitted, whichever API saved the config.
+func (a *App) subscribeConfigConsumers() {
+	a.configState.Subscribe(func(event config.UpdatedEvent) {
+		a.applyRuntimePaneEnvUpdate(event)
+		a.applyRuntimeClaudeEnvUpdate(event)
+	}, config.SubsystemKeys(config.SubsystemRouterEnv)...)
+	a.configState.Subscribe(a.applyRuntimeHotkeyUpdate, config.SubsystemKeys(config.SubsystemHotkey)...)
+	a.configState.Subscribe(func(config.UpdatedEvent) {
+		a.applyRuntimeLoggingUpdate()
+	}, config.SubsystemKeys(config.SubsystemLogging)...)
+	a.configState.Subscribe(func(config.UpdatedEvent) {
+		if err := a.applyRuntimeMetricsUpdate(); err != nil {
+			slog.Warn("[WARN-METRICS] failed to apply metrics config", "error", err)
+		}
+	}, config.SubsystemKeys(config.SubsystemMetrics)...)
+	a.configState.Subscribe(func(config.UpdatedEvent) {
+		a.applyRuntimeResourceLimitsUpdate()
+	}, config.SubsystemKeys(config.SubsystemResourceLimits)...)
+	a.configState.Subscribe(func(config.UpdatedEvent) {
+		a.applyRuntimeSessionPoolUpdate()
+	}, slices.Concat(
+		config.SubsystemKeys(config.SubsystemSessionPool),
+		config.SubsystemKeys(config.SubsystemPaneSpawn),
+	)...)
+	a.configState.Subscribe(func(config.UpdatedEvent) {
+		a.applyRuntimeWebhookUpdate()
+	}, config.SubsystemKeys(config.SubsystemWebhooks)...)
+	a.configState.Subscribe(func(config.UpdatedEvent) {
+		// Rule evaluation runs git commands per session.
+		go a.RefreshSessionBadges()
+	}, config.SubsystemKeys(config.SubsystemSessionBadges)...)
+}
+
+// emitConfigUpdatedEvent emits config:updated followed by config:applied.
+// The runtime subsystems were already re-applied by subscribeConfigConsumers.
 func (a *App) emitConfigUpdatedEvent(event config.UpdatedEvent) {
-	a.applyRuntimePaneEnvUpdate(event)
-	a.applyRuntimeClaudeEnvUpdate(event)
 	// Event emission intentionally happens outside the save lock.
 	// Concurrent saves are ordered by Version, and frontend consumers must
 	// treat the highest version as authoritative.
 	a.emitRuntimeEvent("config:updated", event)
+	if event.Diff.Empty() {
+		return
+	}
+	a.emitRuntimeEvent("config:applied", ConfigAppliedEvent{
+		Version:         event.Version,
+		Immediate:       event.Diff.KeysWithMode(config.ApplyImmediate),
+		NextUse:         event.Diff.KeysWithMode(config.ApplyNextUse),
+		RequiresRestart: event.Diff.KeysWithMode(config.ApplyRestart),
+	})
+}
+
+// applyRuntimeHotkeyUpdate re-registers the global hotkey after quake_mode or
+// global_hotkey changed, preventing out-of-order re-registration from
+// concurrent SaveConfig calls.
+func (a *App) applyRuntimeHotkeyUpdate(event config.UpdatedEvent) {
+	if a.hotkeys == nil {
+		slog.Debug("[DEBUG-CONFIG] skipped hotkey update: hotkey backend unavailable")
+		return
+	}
+
+	a.hotkeyUpdateMu.Lock()
+	defer a.hotkeyUpdateMu.Unlock()
+
+	if event.Version <= a.hotkeyAppliedVersion {
+		slog.Debug("[DEBUG-CONFIG] skipped stale hotkey update", "received", event.Version, "applied", a.hotkeyAppliedVersion)
+		return
+	}
+
+	if err := a.hotkeys.Stop(); err != nil {
+		slog.Warn("[WARN-CONFIG] failed to stop global hotkey before re-registration", "error", err)
+	}
+	// configureGlobalHotkey reads the latest snapshot, which is at least as
+	// new as event.Config because Save updates the snapshot before returning.
+	a.configureGlobalHotkey()
+	a.hotkeyAppliedVersion = event.Version
 }
 
 // applyRuntimePaneEnvUpdate updates router pane_env defaults while preventing
@@ -138,8 +252,10 @@ func (a *App) applyRuntimeClaudeEnvUpdate(event config.UpdatedEvent) {
 }
 
 // GetAllowedShells returns the list of allowed shell executables for UI dropdown.
+// Verified trusted_shells paths from the current config are appended after
+// the built-in names.
 func (a *App) GetAllowedShells() []string {
-	return config.AllowedShellList()
+	return config.AllowedShellListFor(a.configState.Snapshot())
 }
 
 // GetValidationRules returns frontend validation parameters shared with backend checks.
@@ -78,6 +78,40 @@ func TestSaveConfigEmitsUpdatedConfigEvent(t *testing.T) {
 	}
 }
 
+func TestApplyConfigPatch(t *testing.T) {
+	origEmit := runtimeEventsEmitFn
+	t.Cleanup(func() {
+		runtimeEventsEmitFn = origEmit
+	})
+
+	app := NewApp()
+	app.setRuntimeContext(context.Background())
+	app.configState.Initialize(newConfigPathForAPITest(t, "config.yaml"), config.DefaultConfig())
+
+	var eventNames []string
+	runtimeEventsEmitFn = func(_ context.Context, name string, _ ...any) {
+		eventNames = append(eventNames, name)
+	}
+
+	result, err := app.ApplyConfigPatch(map[string]any{"shell": "cmd.exe"})
+	if err != nil {
+		t.Fatalf("ApplyConfigPatch() error = %v", err)
+	}
+	if result.Config.Shell != "cmd.exe" || app.GetConfig().Shell != "cmd.exe" {
+		t.Fatalf("shell = %q (state %q), want cmd.exe", result.Config.Shell, app.GetConfig().Shell)
+	}
+	if len(result.Changes) != 1 || result.Changes[0].Key != "shell" {
+		t.Fatalf("changes = %+v, want one shell change", result.Changes)
+	}
+	if want := []string{"config:updated", "config:applied"}; !reflect.DeepEqual(eventNames, want) {
+		t.Fatalf("events = %v, want %v", eventNames, want)
+	}
+
+	if _, err := app.ApplyConfigPatch(nil); err == nil {
+		t.Fatal("ApplyConfigPatch(nil) error = nil")
+	}
+}
+
 func TestSaveConfigEmitsMonotonicEventVersion(t *testing.T) {
 	origEmit := runtimeEventsEmitFn
 	t.Cleanup(func() {
@@ -547,8 +581,76 @@ func TestGetClaudeEnvVarDescriptionsMutationSafety(t *testing.T) {
 }
 
 func TestConfigEventFieldCounts(t *testing.T) {
-	if got := reflect.TypeFor[config.UpdatedEvent]().NumField(); got != 3 {
-		t.Fatalf("config.UpdatedEvent field count = %d, want 3; update emit payload and tests for new fields", got)
+	if got := reflect.TypeFor[config.UpdatedEvent]().NumField(); got != 4 {
+		t.Fatalf("config.UpdatedEvent field count = %d, want 4; update emit payload and tests for new fields", got)
+	}
+	if got := reflect.TypeFor[ConfigAppliedEvent]().NumField(); got != 4 {
+		t.Fatalf("ConfigAppliedEvent field count = %d, want 4; update emit payload and tests for new fields", got)
+	}
+}
+
+func TestSaveConfigEmitsAppliedEventForChangedKeys(t *testing.T) {
+	origEmit := runtimeEventsEmitFn
+	t.Cleanup(func() {
+		runtimeEventsEmitFn = origEmit
+	})
+
+	app := NewApp()
+	app.setRuntimeContext(context.Background())
+	app.configState.Initialize(newConfigPathForAPITest(t, "config.yaml"), config.DefaultConfig())
+	runtimeEventsEmitFn = func(context.Context, string, ...any) {}
+	// Normalize the initial snapshot so the next diff only reflects the edit.
+	if err := app.SaveConfig(config.DefaultConfig()); err != nil {
+		t.Fatalf("SaveConfig(default) error = %v", err)
+	}
+
+	var applied []ConfigAppliedEvent
+	runtimeEventsEmitFn = func(_ context.Context, name string, data ...any) {
+		if name != "config:applied" || len(data) == 0 {
+			return
+		}
+		payload, ok := data[0].(ConfigAppliedEvent)
+		if !ok {
+			t.Fatalf("unexpected payload type: %T", data[0])
+		}
+		applied = append(applied, payload)
+	}
+
+	cfg := config.DefaultConfig()
+	// quake_mode off keeps the test from registering a real OS hotkey while
+	// still exercising the hotkey subsystem re-apply path.
+	cfg.QuakeMode = false
+	cfg.GlobalHotkey = "Ctrl+Alt+J"
+	cfg.Shell = "cmd.exe"
+	cfg.WebSocketPort = 40555
+	if err := app.SaveConfig(cfg); err != nil {
+		t.Fatalf("SaveConfig() error = %v", err)
+	}
+	if len(applied) != 1 {
+		t.Fatalf("config:applied count = %d, want 1", len(applied))
+	}
+	want := ConfigAppliedEvent{
+		Version:         2,
+		Immediate:       []string{"global_hotkey", "quake_mode"},
+		NextUse:         []string{"shell"},
+		RequiresRestart: []string{"websocket_port"},
+	}
+	if !reflect.DeepEqual(applied[0], want) {
+		t.Fatalf("config:applied = %+v, want %+v", applied[0], want)
+	}
+	if app.hotkeyAppliedVersion != 2 {
+		t.Fatalf("hotkeyAppliedVersion = %d, want 2", app.hotkeyAppliedVersion)
+	}
+	if got := app.hotkeys.ActiveBinding(); got != "" {
+		t.Fatalf("ActiveBinding() = %q after disabling quake_mode, want empty", got)
+	}
+
+	// Saving the same config again changes nothing and emits no applied event.
+	if err := app.SaveConfig(cfg); err != nil {
+		t.Fatalf("second SaveConfig() error = %v", err)
+	}
+	if len(applied) != 1 {
+		t.Fatalf("config:applied count after no-op save = %d, want 1", len(applied))
 	}
 }
 
@@ -714,7 +816,10 @@ func TestSaveConfigSerializesConcurrentUpdates(t *testing.T) {
 	secondEventEntered := make(chan struct{})
 	var eventCount atomic.Int32
 
-	runtimeEventsEmitFn = func(_ context.Context, _ string, _ ...any) {
+	runtimeEventsEmitFn = func(_ context.Context, name string, _ ...any) {
+		if name != "config:updated" {
+			return
+		}
 		current := eventCount.Add(1)
 		if current == 1 {
 			close(enterFirstEvent)
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"myT-x/internal/config"
+)
+
+// configPolicyPath resolves the machine policy file. Tests override it.
+var configPolicyPath = config.DefaultPolicyPath
+
+// enforceConfigPolicy loads the machine policy, applies its locked values to
+// cfg, and installs it for later saves. When the policy changed cfg, the
+// enforced config is written back so that the shim, which reads the file
+// directly, sees the same values. A policy that exists but cannot be loaded
+// or applied fails closed: cfg is kept as it is, every key is locked, and the
+// user is warned.
+func (a *App) enforceConfigPolicy(configPath string, cfg config.Config) config.Config {
+	path := configPolicyPath()
+	policy, err := config.LoadPolicy(path)
+	if err != nil {
+		slog.Warn("[WARN-CONFIG] failed to load config policy, locking all settings", "error", err)
+		a.addPendingConfigLoadWarning(fmt.Sprintf("Failed to load the config policy. All settings are locked until the policy is fixed. Error: %v", err))
+		a.configState.SetPolicy(config.FailClosedPolicy(path))
+		return cfg
+	}
+	if policy.Empty() {
+		return cfg
+	}
+	enforced, changed, err := policy.Enforce(cfg)
+	if err != nil {
+		slog.Warn("[WARN-CONFIG] failed to apply config policy, locking all settings", "error", err)
+		a.addPendingConfigLoadWarning(fmt.Sprintf("Failed to apply the config policy. All settings are locked until the policy is fixed. Error: %v", err))
+		a.configState.SetPolicy(config.FailClosedPolicy(path))
+		return cfg
+	}
+	a.configState.SetPolicy(policy)
+	if len(changed) == 0 {
+		return enforced
+	}
+	slog.Info("[CONFIG] config policy overrode locked keys", "keys", strings.Join(changed, ","))
+	if _, err := config.Save(configPath, enforced); err != nil {
+		slog.Warn("[WARN-CONFIG] failed to persist policy-enforced config", "path", configPath, "error", err)
+	}
+	return enforced
+}
+
+// GetConfigPolicy returns the machine policy path and the config keys it locks.
+// Wails-bound: called from the frontend.
+func (a *App) GetConfigPolicy() config.PolicyInfo {
+	return a.configState.Policy().Info()
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"myT-x/internal/config"
+)
+
+// NOTE: This file overrides the package-level function variable
+// configPolicyPath. Do not use t.Parallel() here.
+
+func setConfigPolicyForTest(t *testing.T, content string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	orig := configPolicyPath
+	t.Cleanup(func() { configPolicyPath = orig })
+	configPolicyPath = func() string { return path }
+}
+
+func TestEnforceConfigPolicy(t *testing.T) {
+	setConfigPolicyForTest(t, "locked:\n  shell: cmd.exe\n")
+	configPath := newConfigPathForTest(t, "config.yaml")
+
+	app := NewApp()
+	cfg := config.DefaultConfig()
+	cfg.Shell = "pwsh.exe"
+	got := app.enforceConfigPolicy(configPath, cfg)
+	if got.Shell != "cmd.exe" {
+		t.Fatalf("enforced shell = %q, want cmd.exe", got.Shell)
+	}
+	persisted, err := config.Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if persisted.Shell != "cmd.exe" {
+		t.Fatalf("persisted shell = %q, want cmd.exe", persisted.Shell)
+	}
+	if info := app.GetConfigPolicy(); !reflect.DeepEqual(info.LockedKeys, []string{"shell"}) {
+		t.Fatalf("GetConfigPolicy().LockedKeys = %v, want [shell]", info.LockedKeys)
+	}
+}
+
+func TestEnforceConfigPolicyFailsClosedOnInvalidPolicy(t *testing.T) {
+	setConfigPolicyForTest(t, "locked:\n  no_such_key: 1\n")
+	configPath := newConfigPathForTest(t, "config.yaml")
+
+	app := NewApp()
+	cfg := config.DefaultConfig()
+	cfg.Shell = "pwsh.exe"
+	got := app.enforceConfigPolicy(configPath, cfg)
+	if got.Shell != "pwsh.exe" {
+		t.Fatalf("shell = %q, want the unchanged user value", got.Shell)
+	}
+	if warning := app.consumePendingConfigLoadWarning(); warning == "" {
+		t.Fatal("expected a pending config policy warning")
+	}
+	if info := app.GetConfigPolicy(); info.Path == "" || !reflect.DeepEqual(info.LockedKeys, []string{"*"}) {
+		t.Fatalf("GetConfigPolicy() = %+v, want every key locked", info)
+	}
+	app.configState.Initialize(configPath, got)
+	if _, err := app.configState.ApplyPatch([]byte(`{"shell": "cmd.exe"}`)); !errors.Is(err, config.ErrLockedByPolicy) {
+		t.Fatalf("ApplyPatch() error = %v, want ErrLockedByPolicy", err)
+	}
+}
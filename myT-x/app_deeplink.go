@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"myT-x/internal/deeplink"
+	"myT-x/internal/workerutil"
+)
+
+const (
+	// deepLinkFailedEvent reports a mytx:// link that could not be opened.
+	deepLinkFailedEvent = "app:deep-link-failed"
+	// deepLinkActionEvent asks the frontend to run a UI action, such as
+	// opening the new session dialog from the taskbar jump list.
+	deepLinkActionEvent = "app:deep-link-action"
+)
+
+var (
+	registerDeepLinkProtocolFn = deeplink.EnsureRegistered
+	executablePathFn           = os.Executable
+)
+
+// ensureDeepLinkProtocol points the per-user mytx:// registration at the
+// running executable. Like the tmux shim, it is refreshed on every startup
+// so a moved or updated build keeps handling links. Failures are non-fatal.
+func (a *App) ensureDeepLinkProtocol() {
+	exePath, err := executablePathFn()
+	if err != nil {
+		slog.Warn("[DEEPLINK] cannot resolve executable path; protocol not registered", "error", err)
+		return
+	}
+	changed, err := registerDeepLinkProtocolFn(exePath)
+	if err != nil {
+		slog.Warn("[DEEPLINK] protocol registration failed", "error", err)
+		return
+	}
+	if changed {
+		slog.Info("[DEEPLINK] registered mytx:// protocol", "exe", exePath)
+	}
+}
+
+// setPendingDeepLink stores the link the app was launched with. It is
+// opened by flushPendingDeepLink once the frontend can receive events.
+func (a *App) setPendingDeepLink(link string) {
+	a.startupWarnMu.Lock()
+	a.pendingDeepLink = link
+	a.startupWarnMu.Unlock()
+}
+
+func (a *App) flushPendingDeepLink() {
+	a.startupWarnMu.Lock()
+	link := a.pendingDeepLink
+	a.pendingDeepLink = ""
+	a.startupWarnMu.Unlock()
+	if link != "" {
+		a.launchDeepLink(link)
+	}
+}
+
+// deepLinkFromActivatePayload extracts the link a second instance forwarded
+// with activate-window.
+func deepLinkFromActivatePayload(payload any) string {
+	if fields, ok := payload.(map[string]string); ok {
+		return fields["link"]
+	}
+	return ""
+}
+
+// launchDeepLink opens link on a background worker. Session creation runs
+// tmux commands through the router, which must not happen on the IPC
+// goroutine that delivered activate-window.
+func (a *App) launchDeepLink(link string) {
+	ctx := a.runtimeContext()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	opts := a.defaultRecoveryOptions()
+	opts.MaxRetries = 1
+	workerutil.RunWithPanicRecovery(ctx, "deep-link", &a.bgWG, func(context.Context) {
+		if err := a.openDeepLink(link); err != nil {
+			slog.Warn("[DEEPLINK] cannot open link", "link", link, "error", err)
+			a.emitRuntimeEvent(deepLinkFailedEvent, map[string]string{
+				"link":    link,
+				"message": err.Error(),
+			})
+		}
+	}, opts)
+}
+
+// openDeepLink focuses or creates the session a mytx:// link refers to, or
+// forwards a UI action to the frontend.
+func (a *App) openDeepLink(raw string) error {
+	link, err := deeplink.Parse(raw)
+	if err != nil {
+		return err
+	}
+	switch link.Kind {
+	case deeplink.KindOpen:
+		snapshot, err := a.sessionService.OpenDirectorySession(link.Path)
+		if err != nil {
+			return err
+		}
+		slog.Info("[DEEPLINK] opened directory session", "path", link.Path, "session", snapshot.Name)
+		return nil
+	case deeplink.KindSession:
+		if _, err := a.sessionService.FindSessionSnapshotByName(link.Session); err != nil {
+			return fmt.Errorf("session %q does not exist", link.Session)
+		}
+		a.sessionService.SetActive(link.Session)
+		return nil
+	case deeplink.KindAction:
+		a.emitRuntimeEvent(deepLinkActionEvent, map[string]string{"action": string(link.Action)})
+		return nil
+	default:
+		return fmt.Errorf("unsupported link action %q", link.Kind)
+	}
+}
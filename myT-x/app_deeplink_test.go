@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"myT-x/internal/config"
+)
+
+func TestOpenDeepLinkFocusesSession(t *testing.T) {
+	app, _ := newSessionAttentionAppForTest(t, config.DefaultConfig(), "current", "api")
+	app.SetActiveSession("current")
+
+	if err := app.openDeepLink("mytx://session/api"); err != nil {
+		t.Fatalf("openDeepLink() error = %v", err)
+	}
+	if active := app.GetActiveSession(); active != "api" {
+		t.Fatalf("active session = %q, want api", active)
+	}
+
+	err := app.openDeepLink("mytx://session/missing")
+	if err == nil || !strings.Contains(err.Error(), `"missing" does not exist`) {
+		t.Fatalf("openDeepLink(missing) error = %v", err)
+	}
+	if active := app.GetActiveSession(); active != "api" {
+		t.Fatalf("active session = %q, want api unchanged", active)
+	}
+}
+
+func TestFlushPendingDeepLinkReportsInvalidLink(t *testing.T) {
+	app := NewApp()
+	app.setRuntimeContext(context.Background())
+
+	var mu sync.Mutex
+	var failures []map[string]string
+	origEmit := runtimeEventsEmitFn
+	t.Cleanup(func() { runtimeEventsEmitFn = origEmit })
+	runtimeEventsEmitFn = func(_ context.Context, name string, data ...any) {
+		if name != deepLinkFailedEvent || len(data) == 0 {
+			return
+		}
+		mu.Lock()
+		failures = append(failures, data[0].(map[string]string))
+		mu.Unlock()
+	}
+
+	app.setPendingDeepLink("mytx://delete?path=C:\\repo")
+	app.flushPendingDeepLink()
+	app.bgWG.Wait()
+	// The link is consumed: a second flush opens nothing.
+	app.flushPendingDeepLink()
+	app.bgWG.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(failures) != 1 || failures[0]["link"] != "mytx://delete?path=C:\\repo" {
+		t.Fatalf("failures = %v, want one for the pending link", failures)
+	}
+}
+
+func TestOpenDeepLinkForwardsActionToFrontend(t *testing.T) {
+	app := NewApp()
+	app.setRuntimeContext(context.Background())
+
+	var actions []string
+	origEmit := runtimeEventsEmitFn
+	t.Cleanup(func() { runtimeEventsEmitFn = origEmit })
+	runtimeEventsEmitFn = func(_ context.Context, name string, data ...any) {
+		if name == deepLinkActionEvent && len(data) > 0 {
+			actions = append(actions, data[0].(map[string]string)["action"])
+		}
+	}
+
+	if err := app.openDeepLink("mytx://action/new-worktree-session"); err != nil {
+		t.Fatalf("openDeepLink() error = %v", err)
+	}
+	if len(actions) != 1 || actions[0] != "new-worktree-session" {
+		t.Fatalf("actions = %v, want [new-worktree-session]", actions)
+	}
+}
+
+func TestDeepLinkFromActivatePayload(t *testing.T) {
+	if got := deepLinkFromActivatePayload(map[string]string{"link": "mytx://session/a"}); got != "mytx://session/a" {
+		t.Fatalf("deepLinkFromActivatePayload() = %q", got)
+	}
+	if got := deepLinkFromActivatePayload(nil); got != "" {
+		t.Fatalf("deepLinkFromActivatePayload(nil) = %q, want empty", got)
+	}
+}
+
+func TestEnsureDeepLinkProtocolRegistersExecutable(t *testing.T) {
+	origRegister, origExe := registerDeepLinkProtocolFn, executablePathFn
+	t.Cleanup(func() {
+		registerDeepLinkProtocolFn, executablePathFn = origRegister, origExe
+	})
+
+	executablePathFn = func() (string, error) { return `C:\Apps\myT-x.exe`, nil }
+	var registered string
+	registerDeepLinkProtocolFn = func(exePath string) (bool, error) {
+		registered = exePath
+		return true, nil
+	}
+	NewApp().ensureDeepLinkProtocol()
+	if registered != `C:\Apps\myT-x.exe` {
+		t.Fatalf("registered = %q, want executable path", registered)
+	}
+
+	registered = ""
+	executablePathFn = func() (string, error) { return "", errors.New("not found") }
+	NewApp().ensureDeepLinkProtocol()
+	if registered != "" {
+		t.Fatal("protocol registered without an executable path")
+	}
+}
@@ -152,3 +152,14 @@ func (a *App) DevPanelListBranches(sessionName string) ([]string, error) {
 func (a *App) DevPanelSearchFiles(sessionName string, query string) ([]SearchFileResult, error) {
 	return a.devpanelService.SearchFiles(sessionName, query)
 }
+
+// RunFindReplace previews, or with options.Apply performs, a find and replace
+// across a session's working directory. Applied edits are backed up first.
+// Wails-bound: called from the frontend developer panel.
+func (a *App) RunFindReplace(sessionKey string, query string, replacement string, options FindReplaceOptions) (FindReplaceResult, error) {
+	sessionName, err := a.requireExistingSessionKey(sessionKey)
+	if err != nil {
+		return FindReplaceResult{}, err
+	}
+	return a.devpanelService.FindReplace(sessionName, query, replacement, options)
+}
@@ -57,3 +57,11 @@ func (a *App) DevPanelGitPull(sessionName string) (DevPanelPullResult, error) {
 func (a *App) DevPanelGitFetch(sessionName string) error {
 	return a.devpanelService.GitFetch(sessionName)
 }
+
+// OpenInMergeTool opens a conflicted file in the configured merge tool
+// (worktree.merge_tool, or merge.tool from git config). The frontend refreshes
+// git status on devpanel:merge-tool-finished when the tool exits.
+// Wails-bound: called from the frontend developer panel.
+func (a *App) OpenInMergeTool(sessionName string, path string) error {
+	return a.devpanelService.OpenInMergeTool(sessionName, path, a.configState.Snapshot().Worktree.MergeTool)
+}
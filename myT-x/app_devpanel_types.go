@@ -26,3 +26,7 @@ type WorkingDiffFile = devpanel.WorkingDiffFile
 type WorkingDiffResult = devpanel.WorkingDiffResult
 type SearchFileResult = devpanel.SearchFileResult
 type SearchContentLine = devpanel.SearchContentLine
+type FindReplaceOptions = devpanel.FindReplaceOptions
+type FindReplaceResult = devpanel.FindReplaceResult
+type FindReplaceFile = devpanel.FindReplaceFile
+type FindReplaceMatch = devpanel.FindReplaceMatch
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"myT-x/internal/config"
+	"myT-x/internal/ephemeral"
+	"myT-x/internal/sessioninfo"
+	"myT-x/internal/tmux"
+)
+
+// ephemeralTempDirPattern names the temp directory of an ephemeral session
+// created without a directory.
+const ephemeralTempDirPattern = "mytx-preview-*"
+
+// createEphemeralTempDir creates the working directory of an ephemeral
+// session that was created without one.
+func createEphemeralTempDir() (string, error) {
+	dir, err := os.MkdirTemp("", ephemeralTempDirPattern)
+	if err != nil {
+		return "", fmt.Errorf("create preview directory: %w", err)
+	}
+	return dir, nil
+}
+
+// registerEphemeralSession marks a created session as ephemeral. workDir is
+// the directory the session owns (empty when it runs in a directory it does
+// not own); tempDir marks workDir as created for the session. The session's
+// worktree, if any, is taken from the snapshot. A failed registration kills
+// the session again, so that it never outlives the app unnoticed.
+func (a *App) registerEphemeralSession(snapshot tmux.SessionSnapshot, workDir string, tempDir bool) error {
+	ttl := config.Read(a.configState, func(cfg *config.Config) time.Duration { return config.EphemeralSessionTTL(*cfg) })
+	now := time.Now()
+	entry := ephemeral.Entry{
+		SessionName: snapshot.Name,
+		WorkDir:     workDir,
+		TempDir:     tempDir,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(ttl),
+	}
+	if wt := snapshot.Worktree; wt != nil && wt.Path != "" {
+		entry.WorkDir = wt.Path
+		entry.Worktree = &ephemeral.Worktree{Path: wt.Path, RepoPath: wt.RepoPath, BranchName: wt.BranchName}
+	}
+	if err := a.ephemeralService.Register(entry); err != nil {
+		slog.Warn("[WARN-EPHEMERAL] failed to register ephemeral session, closing it", "session", snapshot.Name, "error", err)
+		if killErr := a.sessionService.KillSession(snapshot.Name, entry.Worktree != nil); killErr != nil {
+			slog.Warn("[WARN-EPHEMERAL] failed to close unregistered ephemeral session", "session", snapshot.Name, "error", killErr)
+		}
+		if tempDir {
+			_ = os.RemoveAll(workDir)
+		}
+		return fmt.Errorf("register ephemeral session: %w", err)
+	}
+	slog.Info("[EPHEMERAL] created ephemeral session", "session", snapshot.Name, "expiresAt", entry.ExpiresAt)
+	return nil
+}
+
+// removeEphemeralSessionData deletes the session-info directory (input and
+// shell history) stored for workDir.
+func (a *App) removeEphemeralSessionData(workDir string) error {
+	configDir, err := appConfigDirProvider(a)()
+	if err != nil {
+		return err
+	}
+	baseDir, err := sessioninfo.DirectoryPath(configDir, workDir)
+	if err != nil {
+		return err
+	}
+	if err := a.ensureInputHistoryService().ForgetWorkDir(workDir); err != nil {
+		slog.Debug("[DEBUG-EPHEMERAL] failed to close input history", "workDir", workDir, "error", err)
+	}
+	return os.RemoveAll(baseDir)
+}
+
+// ListEphemeralSessions returns the live ephemeral sessions, soonest expiry
+// first.
+// Wails-bound: called from the frontend.
+func (a *App) ListEphemeralSessions() ([]ephemeral.Entry, error) {
+	return a.ephemeralService.List()
+}
+
+// KeepEphemeralSession turns an ephemeral session into a regular one; it no
+// longer expires and nothing is removed when it is closed.
+// Wails-bound: called from the frontend.
+func (a *App) KeepEphemeralSession(sessionName string) error {
+	if _, ok := a.ephemeralService.Lookup(sessionName); !ok {
+		return fmt.Errorf("session %q is not ephemeral", sessionName)
+	}
+	return a.ephemeralService.Keep(sessionName)
+}
+
+// ExtendEphemeralSession moves the expiry of an ephemeral session to minutes
+// from now.
+// Wails-bound: called from the frontend.
+func (a *App) ExtendEphemeralSession(sessionName string, minutes int) (ephemeral.Entry, error) {
+	if minutes <= 0 || minutes > config.MaxEphemeralSessionTTLMinutes {
+		return ephemeral.Entry{}, fmt.Errorf("minutes must be between 1 and %d", config.MaxEphemeralSessionTTLMinutes)
+	}
+	return a.ephemeralService.Extend(sessionName, time.Duration(minutes)*time.Minute)
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"myT-x/internal/ephemeral"
+	"myT-x/internal/sessioninfo"
+	"myT-x/internal/statestore"
+)
+
+func TestRemoveEphemeralSessionDataDeletesSessionInfo(t *testing.T) {
+	app := NewApp()
+	configDir := t.TempDir()
+	workDir := filepath.Join(t.TempDir(), "preview")
+	app.configDirProvider = func() (string, error) { return configDir, nil }
+
+	shellHistory := app.resolvePaneShellHistoryDir("preview", workDir)
+	if shellHistory == "" {
+		t.Fatal("resolvePaneShellHistoryDir() returned empty")
+	}
+	if err := app.removeEphemeralSessionData(workDir); err != nil {
+		t.Fatalf("removeEphemeralSessionData() error = %v", err)
+	}
+	baseDir, err := sessioninfo.DirectoryPath(configDir, workDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(baseDir); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("session-info directory should be removed, stat error = %v", err)
+	}
+}
+
+func TestExtendEphemeralSessionValidatesMinutes(t *testing.T) {
+	app := NewApp()
+	app.stateStore = statestore.NewMemoryStore()
+
+	for _, minutes := range []int{0, -5, 7*24*60 + 1} {
+		if _, err := app.ExtendEphemeralSession("demo", minutes); err == nil {
+			t.Fatalf("ExtendEphemeralSession(%d) should fail", minutes)
+		}
+	}
+	if _, err := app.ExtendEphemeralSession("demo", 30); err == nil {
+		t.Fatal("ExtendEphemeralSession() of a regular session should fail")
+	}
+	if err := app.KeepEphemeralSession("demo"); err == nil {
+		t.Fatal("KeepEphemeralSession() of a regular session should fail")
+	}
+
+	if err := app.ephemeralService.Register(ephemeral.Entry{SessionName: "demo", ExpiresAt: time.Now()}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	entry, err := app.ExtendEphemeralSession("demo", 30)
+	if err != nil {
+		t.Fatalf("ExtendEphemeralSession() error = %v", err)
+	}
+	if until := time.Until(entry.ExpiresAt); until < 29*time.Minute || until > 31*time.Minute {
+		t.Fatalf("ExpiresAt in %v, want about 30m", until)
+	}
+	if err := app.KeepEphemeralSession("demo"); err != nil {
+		t.Fatalf("KeepEphemeralSession() error = %v", err)
+	}
+	if _, ok := app.ephemeralService.Lookup("demo"); ok {
+		t.Fatal("kept session should no longer be ephemeral")
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"myT-x/internal/errreport"
+	"myT-x/internal/workerutil"
+)
+
+// startErrorReporter starts the worker that emits deduplicated error batches
+// to the frontend as errreport.Event.
+func (a *App) startErrorReporter(parent context.Context) {
+	ctx, cancel := context.WithCancel(parent)
+	a.errorsCancel = cancel
+	workerutil.RunWithPanicRecovery(ctx, "error-report", &a.bgWG, a.errorReporter.Run, a.defaultRecoveryOptions())
+}
+
+// reportError routes a backend error to the frontend error toasts.
+func (a *App) reportError(report errreport.Report) {
+	if a.errorReporter == nil {
+		return
+	}
+	a.errorReporter.Report(report)
+}
+
+func workerPanicReport(worker string, attempt int) errreport.Report {
+	return errreport.Report{
+		Code:     errreport.CodeWorkerPanic,
+		Severity: errreport.SeverityWarn,
+		Source:   worker,
+		Message:  fmt.Sprintf("Background worker %q crashed and was restarted", worker),
+		Detail:   fmt.Sprintf("worker=%s attempt=%d", worker, attempt),
+	}
+}
+
+func workerStoppedReport(worker string, maxRetries int) errreport.Report {
+	return errreport.Report{
+		Code:    errreport.CodeWorkerStopped,
+		Source:  worker,
+		Message: fmt.Sprintf("Background worker %q stopped after repeated crashes", worker),
+		Detail:  fmt.Sprintf("worker=%s maxRetries=%d", worker, maxRetries),
+	}
+}
+
+func sessionCleanupReport(component, sessionName string, err error) errreport.Report {
+	return errreport.Report{
+		Code:     errreport.CodeSessionCleanup,
+		Severity: errreport.SeverityWarn,
+		Source:   component,
+		Message:  fmt.Sprintf("Cleanup of session %q was incomplete", sessionName),
+		Detail:   err.Error(),
+	}
+}
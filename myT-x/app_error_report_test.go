@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"myT-x/internal/errreport"
+)
+
+func captureErrorBatches(t *testing.T, app *App) *[]errreport.Batch {
+	t.Helper()
+	origEmit := runtimeEventsEmitFn
+	t.Cleanup(func() { runtimeEventsEmitFn = origEmit })
+
+	app.setRuntimeContext(context.Background())
+	var batches []errreport.Batch
+	runtimeEventsEmitFn = func(_ context.Context, name string, data ...any) {
+		if name != errreport.Event || len(data) == 0 {
+			return
+		}
+		if batch, ok := data[0].(errreport.Batch); ok {
+			batches = append(batches, batch)
+		}
+	}
+	return &batches
+}
+
+func TestRecoveryOptionsReportWorkerErrors(t *testing.T) {
+	app := NewApp()
+	batches := captureErrorBatches(t, app)
+
+	opts := app.defaultRecoveryOptions()
+	opts.OnPanic("session-ports", 1)
+	opts.OnPanic("session-ports", 2)
+	opts.OnFatal("session-ports", 3)
+	app.errorReporter.Flush()
+
+	if len(*batches) != 1 {
+		t.Fatalf("batches = %d, want 1", len(*batches))
+	}
+	groups := (*batches)[0].Groups
+	if len(groups) != 2 {
+		t.Fatalf("groups = %+v, want a panic and a stopped group", groups)
+	}
+	if groups[0].Code != errreport.CodeWorkerPanic || groups[0].Count != 2 || groups[0].Source != "session-ports" {
+		t.Fatalf("panic group = %+v", groups[0])
+	}
+	if groups[1].Code != errreport.CodeWorkerStopped || groups[1].Severity != errreport.SeverityError {
+		t.Fatalf("stopped group = %+v", groups[1])
+	}
+}
+
+func TestSessionCleanupDegradedIsReported(t *testing.T) {
+	app := NewApp()
+	batches := captureErrorBatches(t, app)
+
+	app.emitSessionCleanupDegraded("scheduler", "session-a", errors.New("stop failed"))
+	app.emitSessionCleanupDegraded("scheduler", "session-a", nil)
+	app.errorReporter.Flush()
+
+	if len(*batches) != 1 || len((*batches)[0].Groups) != 1 {
+		t.Fatalf("batches = %+v, want one cleanup group", *batches)
+	}
+	group := (*batches)[0].Groups[0]
+	if group.Code != errreport.CodeSessionCleanup || group.Detail != "stop failed" || group.Hint == "" {
+		t.Fatalf("cleanup group = %+v", group)
+	}
+}
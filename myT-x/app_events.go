@@ -5,6 +5,7 @@ import (
 	"log/slog"
 
 	"myT-x/internal/apptypes"
+	"myT-x/internal/eventsub"
 	"myT-x/internal/snapshot"
 )
 
@@ -35,12 +36,19 @@ func (a *App) emitRuntimeEvent(name string, payload any) {
 
 // emitRuntimeEventWithContext emits a runtime event only when ctx is non-nil.
 // Prefer this helper for best-effort contexts that may not be initialized yet.
+// Events outside the frontend's declared subscriptions are not emitted.
 func (a *App) emitRuntimeEventWithContext(ctx context.Context, name string, payload any) {
 	if ctx == nil {
 		slog.Warn("[EVENT] runtime event dropped because app context is nil", "event", name)
 		return
 	}
-	runtimeEventsEmitFn(ctx, name, payload)
+	// Webhooks receive every event; only the frontend declares what it renders.
+	if a.eventFilter == nil || a.eventFilter.Allows(name, payload) {
+		runtimeEventsEmitFn(ctx, name, payload)
+	}
+	if a.webhookService != nil {
+		a.webhookService.Notify(name, payload)
+	}
 }
 
 // emitBackendEvent handles backend-originated runtime events.
@@ -54,6 +62,9 @@ func (a *App) emitBackendEvent(name string, payload any) {
 	}
 	if name == "app:activate-window" {
 		a.bringWindowToFront()
+		if link := deepLinkFromActivatePayload(payload); link != "" {
+			a.launchDeepLink(link)
+		}
 		return
 	}
 	if name == "tmux:pane-output" {
@@ -66,3 +77,16 @@ func (a *App) emitBackendEvent(name string, payload any) {
 		a.snapshotService.RequestSnapshot(bypassDebounce)
 	}
 }
+
+// SetEventSubscriptions declares which event classes, sessions, and panes
+// the frontend currently renders. Events and pane output outside of them are
+// not sent to the frontend; webhooks and stream clients are unaffected.
+// Wails-bound: called from the frontend whenever its visible set changes.
+func (a *App) SetEventSubscriptions(spec eventsub.Spec) {
+	if a.eventFilter == nil {
+		return
+	}
+	a.eventFilter.Set(spec)
+	slog.Debug("[EVENT] event subscriptions updated",
+		"classes", spec.Classes, "sessions", spec.Sessions, "panes", len(spec.Panes))
+}
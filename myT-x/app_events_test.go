@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"myT-x/internal/eventsub"
 	"myT-x/internal/tmux"
 	"myT-x/internal/wsserver"
 )
@@ -71,6 +72,31 @@ func TestEmitRuntimeEventWithContextEmitsWhenContextIsReady(t *testing.T) {
 	}
 }
 
+func TestEmitRuntimeEventWithContextHonorsEventSubscriptions(t *testing.T) {
+	origEmit := runtimeEventsEmitFn
+	t.Cleanup(func() {
+		runtimeEventsEmitFn = origEmit
+	})
+
+	var emitted []string
+	runtimeEventsEmitFn = func(_ context.Context, name string, _ ...any) {
+		emitted = append(emitted, name)
+	}
+
+	app := NewApp()
+	app.SetEventSubscriptions(eventsub.Spec{Classes: []string{"git"}, Sessions: []string{"alpha"}})
+	ctx := context.Background()
+	app.emitRuntimeEventWithContext(ctx, "git:status-changed", map[string]any{"session_name": "alpha"})
+	app.emitRuntimeEventWithContext(ctx, "git:status-changed", map[string]any{"session_name": "beta"})
+	app.emitRuntimeEventWithContext(ctx, "worktree:pull-failed", map[string]any{"session_name": "alpha"})
+	app.emitRuntimeEventWithContext(ctx, "tmux:session-created", map[string]any{"session_name": "beta"})
+
+	want := []string{"git:status-changed", "tmux:session-created"}
+	if strings.Join(emitted, ",") != strings.Join(want, ",") {
+		t.Fatalf("emitted = %v, want %v", emitted, want)
+	}
+}
+
 func TestAppRuntimeEventEmitterAdapterEmitUsesRuntimeContext(t *testing.T) {
 	origEmit := runtimeEventsEmitFn
 	t.Cleanup(func() {
@@ -311,3 +337,67 @@ func TestGetWebSocketURL(t *testing.T) {
 		}
 	})
 }
+
+func TestGetScreenSyncURL(t *testing.T) {
+	app := NewApp()
+	if got := app.GetScreenSyncURL(); got != "" {
+		t.Fatalf("GetScreenSyncURL() = %q, want empty string when wsHub is nil", got)
+	}
+
+	hub := wsserver.NewHub(wsserver.HubOptions{Addr: "127.0.0.1:0", ScreenSource: app.paneScreen})
+	if err := hub.Start(t.Context()); err != nil {
+		t.Fatalf("hub.Start() error: %v", err)
+	}
+	defer func() {
+		if err := hub.Stop(); err != nil {
+			t.Logf("hub.Stop(): %v", err)
+		}
+	}()
+	app.wsHub = hub
+
+	if got := app.GetScreenSyncURL(); !strings.HasPrefix(got, "ws://127.0.0.1:") || !strings.HasSuffix(got, "/screen") {
+		t.Fatalf("GetScreenSyncURL() = %q, want ws://127.0.0.1:PORT/screen", got)
+	}
+
+	app.paneStates.EnsurePane("%1", 20, 2)
+	app.paneStates.Feed("%1", []byte("hello"))
+	screen, ok := app.paneScreen("%1")
+	if !ok || screen.Cols != 20 || len(screen.Lines) != 2 || screen.Lines[0] != "hello" {
+		t.Fatalf("paneScreen() = %+v, %v, want emulated pane screen", screen, ok)
+	}
+}
+
+func TestGetPaneStreamURL(t *testing.T) {
+	app := NewApp()
+	if got := app.GetPaneStreamURL(); got != "" {
+		t.Fatalf("GetPaneStreamURL() = %q, want empty string when wsHub is nil", got)
+	}
+	if _, ok := app.paneLocation("%0"); ok {
+		t.Fatal("paneLocation() ok = true without a session manager")
+	}
+
+	hub := wsserver.NewHub(wsserver.HubOptions{Addr: "127.0.0.1:0", LocatePane: app.paneLocation})
+	if err := hub.Start(t.Context()); err != nil {
+		t.Fatalf("hub.Start() error: %v", err)
+	}
+	defer func() {
+		if err := hub.Stop(); err != nil {
+			t.Logf("hub.Stop(): %v", err)
+		}
+	}()
+	app.wsHub = hub
+
+	if got := app.GetPaneStreamURL(); !strings.HasPrefix(got, "ws://127.0.0.1:") || !strings.Contains(got, "/stream?token=") {
+		t.Fatalf("GetPaneStreamURL() = %q, want ws://127.0.0.1:PORT/stream?token=...", got)
+	}
+
+	app.sessions = tmux.NewSessionManager()
+	t.Cleanup(app.sessions.Close)
+	if _, _, err := app.sessions.CreateSession("demo", "0", 80, 24); err != nil {
+		t.Fatalf("CreateSession() error: %v", err)
+	}
+	location, ok := app.paneLocation("%0")
+	if !ok || location.Session != "demo" || !strings.HasPrefix(location.WindowID, "@") {
+		t.Fatalf("paneLocation() = %+v, %v, want the demo session", location, ok)
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+
+	"myT-x/internal/config"
+)
+
+// FeatureFlagState is one feature flag with its effective value.
+type FeatureFlagState struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Default     bool             `json:"default"`
+	Apply       config.ApplyMode `json:"apply"`
+	Enabled     bool             `json:"enabled"`
+	// Overridden is true when feature_flags sets the flag.
+	Overridden bool `json:"overridden"`
+}
+
+// ListFeatureFlags returns every known feature flag, ordered by name.
+// Wails-bound: called from the frontend.
+func (a *App) ListFeatureFlags() []FeatureFlagState {
+	cfg := a.configState.Snapshot()
+	defs := config.FeatureFlagDefinitions()
+	states := make([]FeatureFlagState, 0, len(defs))
+	for _, def := range defs {
+		_, overridden := cfg.FeatureFlags[def.Name]
+		states = append(states, FeatureFlagState{
+			Name:        def.Name,
+			Description: def.Description,
+			Default:     def.Default,
+			Apply:       def.Apply,
+			Enabled:     config.FeatureEnabled(cfg, def.Name),
+			Overridden:  overridden,
+		})
+	}
+	return states
+}
+
+// SetFeatureFlag enables or disables the feature flag name and saves the
+// config. Setting a flag to its default removes the override, so a later
+// change of the default applies.
+// Wails-bound: called from the frontend.
+func (a *App) SetFeatureFlag(name string, enabled bool) error {
+	name = strings.TrimSpace(name)
+	def, ok := config.LookupFeatureFlag(name)
+	if !ok {
+		return fmt.Errorf("unknown feature flag: %s", name)
+	}
+	event, err := a.configState.Update(func(cfg *config.Config) {
+		flags := maps.Clone(cfg.FeatureFlags)
+		if flags == nil {
+			flags = map[string]bool{}
+		}
+		if enabled == def.Default {
+			delete(flags, name)
+		} else {
+			flags[name] = enabled
+		}
+		cfg.FeatureFlags = flags
+	})
+	if err != nil {
+		return err
+	}
+	a.emitConfigUpdatedEvent(event)
+	return nil
+}
+
+// featureEnabled reports whether the feature flag name is enabled in the
+// current config.
+func (a *App) featureEnabled(name string) bool {
+	return config.Read(a.configState, func(cfg *config.Config) bool {
+		return config.FeatureEnabled(*cfg, name)
+	})
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"myT-x/internal/config"
+)
+
+func TestSetFeatureFlagStoresOnlyOverrides(t *testing.T) {
+	origEmit := runtimeEventsEmitFn
+	t.Cleanup(func() {
+		runtimeEventsEmitFn = origEmit
+	})
+	runtimeEventsEmitFn = func(context.Context, string, ...any) {}
+
+	app := NewApp()
+	app.setRuntimeContext(context.Background())
+	app.configState.Initialize(newConfigPathForAPITest(t, "config.yaml"), config.DefaultConfig())
+
+	if app.featureEnabled(config.FeatureControlMode) {
+		t.Fatal("control_mode enabled by default")
+	}
+	if err := app.SetFeatureFlag(config.FeatureControlMode, true); err != nil {
+		t.Fatalf("SetFeatureFlag() error = %v", err)
+	}
+	if !app.featureEnabled(config.FeatureControlMode) {
+		t.Fatal("control_mode still disabled after SetFeatureFlag(true)")
+	}
+	for _, state := range app.ListFeatureFlags() {
+		if state.Name == config.FeatureControlMode && (!state.Enabled || !state.Overridden) {
+			t.Fatalf("ListFeatureFlags() control_mode = %+v, want enabled override", state)
+		}
+	}
+
+	// Going back to the default removes the override.
+	if err := app.SetFeatureFlag(config.FeatureControlMode, false); err != nil {
+		t.Fatalf("SetFeatureFlag() error = %v", err)
+	}
+	if flags := app.GetConfig().FeatureFlags; flags != nil {
+		t.Fatalf("FeatureFlags = %v, want nil", flags)
+	}
+
+	if err := app.SetFeatureFlag("no_such_flag", true); err == nil {
+		t.Fatal("SetFeatureFlag() with an unknown flag succeeded")
+	}
+}
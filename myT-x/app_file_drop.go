@@ -0,0 +1,114 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"myT-x/internal/config"
+	"myT-x/internal/filedrop"
+)
+
+// FileDropResult reports the outcome of HandleFileDrop.
+type FileDropResult struct {
+	// Mode is the applied mode: "path", "copy", or "link".
+	Mode string `json:"mode"`
+	// Paths are the paths the pane can use: the dropped paths in path mode,
+	// the placed copies or links otherwise.
+	Paths []string `json:"paths"`
+	// NeedsConfirmation is set when files with the dropped names already
+	// exist in the pane's directory. Nothing was placed; call HandleFileDrop
+	// again with overwrite=true to replace them.
+	NeedsConfirmation bool     `json:"needs_confirmation"`
+	Conflicts         []string `json:"conflicts"`
+	// Typed is set when the paths were typed into the pane (config
+	// file_drop.type_path).
+	Typed bool `json:"typed"`
+}
+
+// HandleFileDrop handles files dropped on a pane. mode "copy" or "link"
+// places them in the pane's working directory first, "path" uses them where
+// they are; an empty mode uses config file_drop.mode. Copies are limited to
+// file_drop.max_size_mb in total.
+func (a *App) HandleFileDrop(paneID string, paths []string, mode string, overwrite bool) (FileDropResult, error) {
+	sessions, err := a.requireSessionsWithPaneID(&paneID)
+	if err != nil {
+		return FileDropResult{}, err
+	}
+	sources := make([]string, 0, len(paths))
+	for _, path := range paths {
+		if path = strings.TrimSpace(path); path != "" {
+			sources = append(sources, path)
+		}
+	}
+	if len(sources) == 0 {
+		return FileDropResult{}, errors.New("no files dropped")
+	}
+
+	settings := config.Read(a.configState, func(cfg *config.Config) config.FileDropConfig {
+		if cfg.FileDrop == nil {
+			return config.FileDropConfig{}
+		}
+		return *cfg.FileDrop
+	})
+	mode = strings.ToLower(strings.TrimSpace(mode))
+	if mode == "" {
+		mode = settings.EffectiveMode()
+	}
+
+	result := FileDropResult{Mode: mode}
+	switch mode {
+	case config.FileDropModePath:
+		result.Paths = sources
+	case config.FileDropModeCopy, config.FileDropModeLink:
+		destDir, err := sessions.PaneCurrentPath(paneID)
+		if err != nil {
+			return FileDropResult{}, err
+		}
+		if strings.TrimSpace(destDir) == "" {
+			return FileDropResult{}, fmt.Errorf("pane %s has no working directory", paneID)
+		}
+		placed, err := filedrop.Place(sources, filedrop.Options{
+			DestDir:   destDir,
+			Link:      mode == config.FileDropModeLink,
+			Overwrite: overwrite,
+			MaxBytes:  settings.MaxSizeBytes(),
+		})
+		if err != nil {
+			return FileDropResult{}, err
+		}
+		if len(placed.Conflicts) > 0 && !overwrite {
+			result.NeedsConfirmation = true
+			result.Conflicts = placed.Conflicts
+			return result, nil
+		}
+		result.Paths = placed.Paths
+		slog.Info("[FILE-DROP] placed dropped files", "paneID", paneID, "mode", mode,
+			"dir", destDir, "count", len(placed.Paths), "overwritten", len(placed.Conflicts))
+	default:
+		return FileDropResult{}, fmt.Errorf("unknown file drop mode %q", mode)
+	}
+
+	if mode == config.FileDropModePath || settings.TypesPath() {
+		input := quoteDroppedPaths(result.Paths)
+		if err := sessions.WriteToPane(paneID, input); err != nil {
+			slog.Debug("[PANE] HandleFileDrop failed to type paths", "paneID", paneID, "err", err)
+			return FileDropResult{}, err
+		}
+		a.recordInput(paneID, input, "file-drop", a.resolveSessionNameForPane(sessions, paneID))
+		result.Typed = true
+	}
+	return result, nil
+}
+
+// quoteDroppedPaths joins paths as double-quoted shell arguments, escaping
+// the characters PowerShell expands inside double quotes.
+func quoteDroppedPaths(paths []string) string {
+	quoted := make([]string, len(paths))
+	for i, path := range paths {
+		escaped := strings.NewReplacer("`", "``", "$", "`$").Replace(path)
+		quoted[i] = `"` + escaped + `"`
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"myT-x/internal/config"
+	"myT-x/internal/tmux"
+)
+
+func TestHandleFileDrop(t *testing.T) {
+	newApp := func(t *testing.T, fileDrop *config.FileDropConfig) (*App, string, string) {
+		t.Helper()
+		app := NewApp()
+		app.sessions = tmux.NewSessionManager()
+		cfg := config.DefaultConfig()
+		cfg.FileDrop = fileDrop
+		app.configState.SetSnapshot(cfg)
+		_, pane, err := app.sessions.CreateSession("s1", "0", 120, 40)
+		if err != nil {
+			t.Fatalf("CreateSession() error = %v", err)
+		}
+		workDir := t.TempDir()
+		if err := app.sessions.SetRootPath("s1", workDir); err != nil {
+			t.Fatalf("SetRootPath() error = %v", err)
+		}
+		return app, fmt.Sprintf("%%%d", pane.ID), workDir
+	}
+	dropped := func(t *testing.T, content string) string {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), "report.txt")
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	t.Run("copies into the pane directory", func(t *testing.T) {
+		app, paneID, workDir := newApp(t, &config.FileDropConfig{TypePath: "off"})
+		result, err := app.HandleFileDrop(paneID, []string{dropped(t, "v1")}, config.FileDropModeCopy, false)
+		if err != nil {
+			t.Fatalf("HandleFileDrop() error = %v", err)
+		}
+		want := []string{filepath.Join(workDir, "report.txt")}
+		if !slices.Equal(result.Paths, want) || result.Typed || result.NeedsConfirmation {
+			t.Fatalf("HandleFileDrop() = %+v, want untyped copy %v", result, want)
+		}
+	})
+
+	t.Run("existing files need confirmation", func(t *testing.T) {
+		app, paneID, workDir := newApp(t, &config.FileDropConfig{Mode: config.FileDropModeCopy, TypePath: "off"})
+		target := filepath.Join(workDir, "report.txt")
+		if err := os.WriteFile(target, []byte("old"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		source := dropped(t, "new")
+
+		result, err := app.HandleFileDrop(paneID, []string{source}, "", false)
+		if err != nil {
+			t.Fatalf("HandleFileDrop() error = %v", err)
+		}
+		if result.Mode != config.FileDropModeCopy || !result.NeedsConfirmation || !slices.Equal(result.Conflicts, []string{"report.txt"}) {
+			t.Fatalf("HandleFileDrop() = %+v, want confirmation for report.txt", result)
+		}
+		if _, err := app.HandleFileDrop(paneID, []string{source}, "", true); err != nil {
+			t.Fatalf("HandleFileDrop(overwrite) error = %v", err)
+		}
+		if data, _ := os.ReadFile(target); string(data) != "new" {
+			t.Fatalf("overwritten file = %q, want new", data)
+		}
+	})
+
+	t.Run("path mode types the paths", func(t *testing.T) {
+		app, paneID, _ := newApp(t, nil)
+		// The pane has no terminal, so typing the paths fails.
+		if _, err := app.HandleFileDrop(paneID, []string{dropped(t, "v1")}, "", false); err == nil {
+			t.Fatal("HandleFileDrop() expected write error for nil terminal")
+		}
+	})
+
+	t.Run("rejects invalid drops", func(t *testing.T) {
+		app, paneID, _ := newApp(t, nil)
+		if _, err := app.HandleFileDrop(paneID, []string{" "}, "", false); err == nil {
+			t.Error("HandleFileDrop(no paths) error = nil, want error")
+		}
+		if _, err := app.HandleFileDrop(paneID, []string{dropped(t, "v1")}, "move", false); err == nil {
+			t.Error("HandleFileDrop(unknown mode) error = nil, want error")
+		}
+	})
+}
+
+func TestQuoteDroppedPaths(t *testing.T) {
+	got := quoteDroppedPaths([]string{`C:\work\a b.txt`, `C:\$env\x` + "`" + `y`})
+	want := `"C:\work\a b.txt" "C:\` + "`$env\\x``y\""
+	if got != want {
+		t.Fatalf("quoteDroppedPaths() = %s, want %s", got, want)
+	}
+}
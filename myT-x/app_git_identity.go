@@ -0,0 +1,30 @@
+package main
+
+import (
+	"log/slog"
+
+	"myT-x/internal/config"
+)
+
+// resolvePaneGitIdentityEnv returns the environment of the git_identities
+// entry matching a new pane, or nil. Worktree sessions match repo rules
+// against their main repository rather than the worktree directory.
+func (a *App) resolvePaneGitIdentityEnv(sessionName, workDir string) map[string]string {
+	cfg := a.configState.Snapshot()
+	if len(cfg.GitIdentities) == 0 {
+		return nil
+	}
+	facts := config.GitIdentityFacts{SessionName: sessionName, WorkDir: workDir}
+	if sessions, err := a.requireSessions(); err == nil {
+		if info, err := sessions.GetWorktreeInfo(sessionName); err == nil && info != nil {
+			facts.RepoPath = info.RepoPath
+		}
+	}
+	identity, ok := config.ResolveGitIdentity(cfg, facts)
+	if !ok {
+		return nil
+	}
+	slog.Debug("[DEBUG-GIT] git identity applied to pane",
+		"session", sessionName, "email", identity.Email)
+	return identity.Env()
+}
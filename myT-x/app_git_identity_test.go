@@ -0,0 +1,50 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"myT-x/internal/config"
+	"myT-x/internal/tmux"
+)
+
+func TestResolvePaneGitIdentityEnv(t *testing.T) {
+	mainRepo := filepath.Join(t.TempDir(), "backend")
+	app := NewApp()
+	cfg := config.DefaultConfig()
+	cfg.GitIdentities = []config.GitIdentity{
+		{Repo: "backend", Name: "Work", Email: "work@example.com"},
+		{Session: "oss-*", Name: "OSS", Email: "oss@example.com"},
+	}
+	app.configState.Initialize(filepath.Join(t.TempDir(), "config.yaml"), cfg)
+	app.sessions = tmux.NewSessionManager()
+	for _, name := range []string{"feature", "oss-api", "plain"} {
+		if _, _, err := app.sessions.CreateSession(name, "0", 120, 40); err != nil {
+			t.Fatalf("CreateSession(%q) error = %v", name, err)
+		}
+	}
+	if err := app.sessions.SetWorktreeInfo("feature", &tmux.SessionWorktreeInfo{
+		Path:     filepath.Join(t.TempDir(), "backend-wt-feature"),
+		RepoPath: mainRepo,
+	}); err != nil {
+		t.Fatalf("SetWorktreeInfo() error = %v", err)
+	}
+
+	tests := []struct {
+		session   string
+		wantEmail string
+	}{
+		{session: "feature", wantEmail: "work@example.com"},
+		{session: "oss-api", wantEmail: "oss@example.com"},
+		{session: "plain"},
+	}
+	for _, tt := range tests {
+		env := app.resolvePaneGitIdentityEnv(tt.session, t.TempDir())
+		if got := env["GIT_AUTHOR_EMAIL"]; got != tt.wantEmail {
+			t.Errorf("resolvePaneGitIdentityEnv(%q) GIT_AUTHOR_EMAIL = %q, want %q", tt.session, got, tt.wantEmail)
+		}
+		if got := env["GIT_COMMITTER_EMAIL"]; got != tt.wantEmail {
+			t.Errorf("resolvePaneGitIdentityEnv(%q) GIT_COMMITTER_EMAIL = %q, want %q", tt.session, got, tt.wantEmail)
+		}
+	}
+}
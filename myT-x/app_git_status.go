@@ -0,0 +1,13 @@
+package main
+
+import (
+	gitpkg "myT-x/internal/git"
+)
+
+// GetGitStatuses returns the latest worktree git status of every session
+// inside a git repository, keyed by session name. Changes are also pushed as
+// git:status-changed events.
+// Wails-bound: called from the frontend.
+func (a *App) GetGitStatuses() map[string]gitpkg.WorkingTreeStatus {
+	return a.gitStatusWatcher.Statuses()
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	gitpkg "myT-x/internal/git"
+)
+
+func TestGetGitStatusesReturnsPolledStatuses(t *testing.T) {
+	app := NewApp()
+	app.gitStatusWatcher = gitpkg.NewStatusWatcher(gitpkg.StatusWatcherDeps{
+		Targets: func() []gitpkg.StatusTarget {
+			return []gitpkg.StatusTarget{
+				{SessionName: "api", Dir: `C:\repo`},
+				{SessionName: "notes", Dir: `C:\notes`},
+			}
+		},
+		ReadStatus: func(dir string) (gitpkg.WorkingTreeStatus, error) {
+			if dir != `C:\repo` {
+				return gitpkg.WorkingTreeStatus{}, errors.New("not a git repository")
+			}
+			return gitpkg.WorkingTreeStatus{Branch: "main", Ahead: 1}, nil
+		},
+	})
+	if got := app.GetGitStatuses(); len(got) != 0 {
+		t.Fatalf("GetGitStatuses() before poll = %+v, want empty", got)
+	}
+
+	app.gitStatusWatcher.Poll()
+	got := app.GetGitStatuses()
+	if len(got) != 1 || got["api"].Branch != "main" || got["api"].Ahead != 1 {
+		t.Fatalf("GetGitStatuses() = %+v, want only api", got)
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+
+	"myT-x/internal/config"
+	"myT-x/internal/heartbeat"
+	"myT-x/internal/tmux"
+	"myT-x/internal/workerutil"
+)
+
+// heartbeatPath returns the heartbeat file path in the config directory.
+func (a *App) heartbeatPath() (string, error) {
+	dir, err := a.configDirProvider()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, heartbeat.FileName), nil
+}
+
+// heartbeatSessions summarizes snapshots for the heartbeat file. gitFacts
+// returns the branch and dirty state of a session; nil skips git lookups.
+func heartbeatSessions(
+	snapshots []tmux.SessionSnapshot,
+	activeName string,
+	activities map[string]tmux.SessionActivity,
+	gitFacts func(sessionName string) config.SessionBadgeFacts,
+) []heartbeat.Session {
+	sessions := make([]heartbeat.Session, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		panes := 0
+		for _, window := range snapshot.Windows {
+			panes += len(window.Panes)
+		}
+		activity := activities[snapshot.Name]
+		session := heartbeat.Session{
+			Name:         snapshot.Name,
+			Windows:      len(snapshot.Windows),
+			Panes:        panes,
+			Idle:         snapshot.IsIdle,
+			Active:       snapshot.Name == activeName,
+			LastActivity: activity.LastActivity.UTC(),
+			NeedsInput:   activity.NeedsInput,
+		}
+		if !snapshot.Badge.IsEmpty() {
+			session.Badge = &heartbeat.Badge{Color: snapshot.Badge.Color, Emoji: snapshot.Badge.Emoji}
+		}
+		if gitFacts != nil {
+			facts := gitFacts(snapshot.Name)
+			session.Branch = facts.Branch
+			session.Dirty = facts.Dirty
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions
+}
+
+// currentHeartbeatSessions collects the live sessions for the heartbeat.
+// Wired as heartbeat.Deps.Sessions; the git lookups run once per interval.
+func (a *App) currentHeartbeatSessions() []heartbeat.Session {
+	sessions, err := a.requireSessions()
+	if err != nil {
+		return nil
+	}
+	gitFacts := func(sessionName string) config.SessionBadgeFacts {
+		workDir, err := a.sessionService.ResolveSessionWorkDir(sessionName)
+		if err != nil || workDir == "" {
+			return config.SessionBadgeFacts{}
+		}
+		return a.sessionBadgeFactsFn(workDir, true)
+	}
+	return heartbeatSessions(sessions.Snapshot(), a.sessionService.GetActiveSessionName(),
+		sessions.SessionActivities(), gitFacts)
+}
+
+// startHeartbeat writes the heartbeat file until shutdown, so external tools
+// can check host health without connecting to the pipe.
+func (a *App) startHeartbeat(parent context.Context) {
+	writer := heartbeat.NewWriter(heartbeat.Deps{
+		Path:      a.heartbeatPath,
+		Sessions:  a.currentHeartbeatSessions,
+		Version:   appVersion,
+		PipeName:  a.router.PipeName(),
+		StreamURL: a.GetPaneStreamURL(),
+	})
+	ctx, cancel := context.WithCancel(parent)
+	a.heartbeatCancel = cancel
+	workerutil.RunWithPanicRecovery(ctx, "heartbeat", &a.bgWG, writer.Run, a.defaultRecoveryOptions())
+}
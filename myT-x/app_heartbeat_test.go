@@ -0,0 +1,65 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"myT-x/internal/config"
+	"myT-x/internal/heartbeat"
+	"myT-x/internal/tmux"
+)
+
+func TestHeartbeatSessions(t *testing.T) {
+	snapshots := []tmux.SessionSnapshot{
+		{
+			Name: "main",
+			Windows: []tmux.WindowSnapshot{
+				{Panes: []tmux.PaneSnapshot{{}, {}}},
+				{Panes: []tmux.PaneSnapshot{{}}},
+			},
+		},
+		{
+			Name: "build", IsIdle: true,
+			Badge:   &tmux.SessionBadge{Color: "#00ff00", Emoji: "🚧", Auto: true},
+			Windows: []tmux.WindowSnapshot{{Panes: []tmux.PaneSnapshot{{}}}},
+		},
+	}
+	activity := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	activities := map[string]tmux.SessionActivity{
+		"main": {LastActivity: activity, NeedsInput: true},
+	}
+	gitFacts := func(sessionName string) config.SessionBadgeFacts {
+		if sessionName == "main" {
+			return config.SessionBadgeFacts{RepoPath: "/repo", Branch: "feature/x", Dirty: true}
+		}
+		return config.SessionBadgeFacts{}
+	}
+	want := []heartbeat.Session{
+		{Name: "main", Windows: 2, Panes: 3, Active: true, Branch: "feature/x", Dirty: true, LastActivity: activity, NeedsInput: true},
+		{Name: "build", Windows: 1, Panes: 1, Idle: true, Badge: &heartbeat.Badge{Color: "#00ff00", Emoji: "🚧"}},
+	}
+	if got := heartbeatSessions(snapshots, "main", activities, gitFacts); !reflect.DeepEqual(got, want) {
+		t.Fatalf("heartbeatSessions() = %+v, want %+v", got, want)
+	}
+	if got := heartbeatSessions(snapshots, "", nil, nil); got[0].Branch != "" || got[0].NeedsInput {
+		t.Fatalf("heartbeatSessions() without activity or git = %+v", got[0])
+	}
+	if got := heartbeatSessions(nil, "", nil, nil); got == nil || len(got) != 0 {
+		t.Fatalf("heartbeatSessions(nil) = %#v, want empty non-nil slice", got)
+	}
+}
+
+func TestHeartbeatPath(t *testing.T) {
+	app := NewApp()
+	dir := t.TempDir()
+	app.configDirProvider = func() (string, error) { return dir, nil }
+	got, err := app.heartbeatPath()
+	if err != nil {
+		t.Fatalf("heartbeatPath() error = %v", err)
+	}
+	if want := filepath.Join(dir, heartbeat.FileName); got != want {
+		t.Fatalf("heartbeatPath() = %q, want %q", got, want)
+	}
+}
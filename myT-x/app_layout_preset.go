@@ -0,0 +1,118 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"unicode/utf8"
+
+	"myT-x/internal/layoutpreset"
+	"myT-x/internal/tmux"
+)
+
+// maxLayoutPresetNameLength bounds preset names shown in the layout bar.
+const maxLayoutPresetNameLength = 64
+
+// SaveLayoutPreset stores the active window layout of a session as a named
+// preset scoped to the session's working directory.
+// Wails-bound: called from the frontend.
+func (a *App) SaveLayoutPreset(sessionName string, name string) error {
+	return a.saveLayoutPreset(sessionName, name, false)
+}
+
+// SaveGlobalLayoutPreset stores the active window layout of a session as a
+// named preset available to every session.
+// Wails-bound: called from the frontend.
+func (a *App) SaveGlobalLayoutPreset(sessionName string, name string) error {
+	return a.saveLayoutPreset(sessionName, name, true)
+}
+
+// ListLayoutPresets returns the saved presets visible to a session: its own
+// presets first, then global presets.
+// Wails-bound: called from the frontend.
+func (a *App) ListLayoutPresets(sessionName string) ([]layoutpreset.Preset, error) {
+	sessionName = strings.TrimSpace(sessionName)
+	if sessionName == "" {
+		return nil, errors.New("session name is required")
+	}
+	return a.layoutPresetService.List(sessionName)
+}
+
+// DeleteLayoutPreset removes a saved preset from the session or global scope.
+// Wails-bound: called from the frontend.
+func (a *App) DeleteLayoutPreset(sessionName string, name string, global bool) error {
+	sessionName = strings.TrimSpace(sessionName)
+	if !global && sessionName == "" {
+		return errors.New("session name is required")
+	}
+	return a.layoutPresetService.Delete(sessionName, name, global)
+}
+
+func (a *App) saveLayoutPreset(sessionName string, name string, global bool) error {
+	sessionName = strings.TrimSpace(sessionName)
+	if sessionName == "" {
+		return errors.New("session name is required")
+	}
+	name, err := validateLayoutPresetName(name)
+	if err != nil {
+		return err
+	}
+	sessions, err := a.requireSessions()
+	if err != nil {
+		return err
+	}
+
+	layout, paneCount, err := sessions.ActiveWindowLayoutString(sessionName)
+	if err != nil {
+		return err
+	}
+	return a.layoutPresetService.Save(sessionName, layoutpreset.Preset{
+		Name:      name,
+		Layout:    layout,
+		PaneCount: paneCount,
+	}, global)
+}
+
+func validateLayoutPresetName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	switch {
+	case name == "":
+		return "", errors.New("preset name is required")
+	case utf8.RuneCountInString(name) > maxLayoutPresetNameLength:
+		return "", fmt.Errorf("preset name must be at most %d characters", maxLayoutPresetNameLength)
+	case tmux.IsBuiltinLayoutPreset(name):
+		return "", fmt.Errorf("preset name %q is reserved for a built-in layout", name)
+	case tmux.IsLayoutString(name):
+		return "", fmt.Errorf("preset name %q looks like a tmux layout string", name)
+	}
+	return name, nil
+}
+
+// applyLayoutToActiveWindow resolves preset as a built-in preset, a tmux
+// layout string, or a saved preset name, in that order. Unknown names fall
+// back to the default built-in arrangement as before saved presets existed.
+func (a *App) applyLayoutToActiveWindow(sessions *tmux.SessionManager, sessionName string, preset string) error {
+	if tmux.IsBuiltinLayoutPreset(preset) {
+		return sessions.ApplyLayoutPresetToActiveWindow(sessionName, tmux.LayoutPreset(preset))
+	}
+	if tmux.IsLayoutString(preset) {
+		return sessions.ApplyLayoutStringToActiveWindow(sessionName, preset)
+	}
+
+	saved, ok, err := a.layoutPresetService.Find(sessionName, preset)
+	if err != nil {
+		slog.Debug("[DEBUG-LAYOUT] saved layout preset lookup failed, using built-in fallback",
+			"session", sessionName,
+			"preset", preset,
+			"error", err,
+		)
+	}
+	if ok {
+		if err := sessions.ApplyLayoutStringToActiveWindow(sessionName, saved.Layout); err != nil {
+			return fmt.Errorf("apply layout preset %q: %w", preset, err)
+		}
+		return nil
+	}
+	return sessions.ApplyLayoutPresetToActiveWindow(sessionName, tmux.LayoutPreset(preset))
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"myT-x/internal/config"
+	"myT-x/internal/tmux"
+)
+
+func activeLayoutForTest(t *testing.T, app *App, sessionName string) string {
+	t.Helper()
+	layout, _, err := app.sessions.ActiveWindowLayoutString(sessionName)
+	if err != nil {
+		t.Fatalf("ActiveWindowLayoutString(%s): %v", sessionName, err)
+	}
+	return layout
+}
+
+func TestSaveAndApplyLayoutPreset(t *testing.T) {
+	app, _ := newSessionBadgeAppForTest(t, config.DefaultConfig())
+	session, ok := app.sessions.GetSession("session-a")
+	if !ok {
+		t.Fatal("session-a not found")
+	}
+	if _, err := app.sessions.SplitPane(session.Windows[0].Panes[0].ID, tmux.SplitHorizontal); err != nil {
+		t.Fatalf("SplitPane() error = %v", err)
+	}
+
+	if err := app.ApplyLayoutPreset("session-a", "even-vertical"); err != nil {
+		t.Fatalf("ApplyLayoutPreset(even-vertical) error = %v", err)
+	}
+	stacked := activeLayoutForTest(t, app, "session-a")
+	if err := app.SaveLayoutPreset("session-a", " stacked "); err != nil {
+		t.Fatalf("SaveLayoutPreset() error = %v", err)
+	}
+
+	if err := app.ApplyLayoutPreset("session-a", "even-horizontal"); err != nil {
+		t.Fatalf("ApplyLayoutPreset(even-horizontal) error = %v", err)
+	}
+	if activeLayoutForTest(t, app, "session-a") == stacked {
+		t.Fatal("even-horizontal layout should differ from the saved stacked layout")
+	}
+
+	if err := app.ApplyLayoutPreset("session-a", "stacked"); err != nil {
+		t.Fatalf("ApplyLayoutPreset(stacked) error = %v", err)
+	}
+	if got := activeLayoutForTest(t, app, "session-a"); got != stacked {
+		t.Fatalf("layout after applying saved preset = %q, want %q", got, stacked)
+	}
+
+	// A raw tmux layout string is accepted directly.
+	if err := app.ApplyLayoutPreset("session-a", "even-horizontal"); err != nil {
+		t.Fatalf("ApplyLayoutPreset(even-horizontal) error = %v", err)
+	}
+	if err := app.ApplyLayoutPreset("session-a", stacked); err != nil {
+		t.Fatalf("ApplyLayoutPreset(layout string) error = %v", err)
+	}
+	if got := activeLayoutForTest(t, app, "session-a"); got != stacked {
+		t.Fatalf("layout after applying layout string = %q, want %q", got, stacked)
+	}
+
+	presets, err := app.ListLayoutPresets("session-a")
+	if err != nil {
+		t.Fatalf("ListLayoutPresets() error = %v", err)
+	}
+	if len(presets) != 1 || presets[0].Name != "stacked" || presets[0].PaneCount != 2 || presets[0].Global {
+		t.Fatalf("ListLayoutPresets() = %+v, want one session preset", presets)
+	}
+
+	if err := app.DeleteLayoutPreset("session-a", "stacked", false); err != nil {
+		t.Fatalf("DeleteLayoutPreset() error = %v", err)
+	}
+	if presets, _ := app.ListLayoutPresets("session-a"); len(presets) != 0 {
+		t.Fatalf("ListLayoutPresets() after delete = %+v, want empty", presets)
+	}
+}
+
+func TestGlobalLayoutPresetRejectsPaneCountMismatch(t *testing.T) {
+	app, _ := newSessionBadgeAppForTest(t, config.DefaultConfig())
+	if err := app.SaveGlobalLayoutPreset("session-a", "single"); err != nil {
+		t.Fatalf("SaveGlobalLayoutPreset() error = %v", err)
+	}
+
+	session, _ := app.sessions.GetSession("session-a")
+	if _, err := app.sessions.SplitPane(session.Windows[0].Panes[0].ID, tmux.SplitVertical); err != nil {
+		t.Fatalf("SplitPane() error = %v", err)
+	}
+	err := app.ApplyLayoutPreset("session-a", "single")
+	if err == nil || !strings.Contains(err.Error(), "layout has 1 panes but window has 2") {
+		t.Fatalf("ApplyLayoutPreset(single) error = %v, want pane count mismatch", err)
+	}
+
+	presets, err := app.ListLayoutPresets("session-a")
+	if err != nil || len(presets) != 1 || !presets[0].Global {
+		t.Fatalf("ListLayoutPresets() = %+v, %v, want one global preset", presets, err)
+	}
+}
+
+func TestSaveLayoutPresetValidatesName(t *testing.T) {
+	app, _ := newSessionBadgeAppForTest(t, config.DefaultConfig())
+	for _, name := range []string{"", "   ", "tiled", "b25d,80x24,0,0,0", strings.Repeat("x", maxLayoutPresetNameLength+1)} {
+		if err := app.SaveLayoutPreset("session-a", name); err == nil {
+			t.Fatalf("SaveLayoutPreset(%q) error = nil, want validation error", name)
+		}
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"myT-x/internal/config"
 	gitpkg "myT-x/internal/git"
 	"myT-x/internal/ipc"
+	"myT-x/internal/logging"
 	"myT-x/internal/mcp"
 	"myT-x/internal/mcp/lspmcp/lsppkg"
 	"myT-x/internal/mcpapi"
@@ -49,13 +50,14 @@ type sessionScopedLifecycleParticipant struct {
 	rename  func(oldName, newName string) error
 }
 
-const expectedSessionScopedLifecycleParticipantCount = 5
+const expectedSessionScopedLifecycleParticipantCount = 9
 
 func (a *App) emitSessionCleanupDegraded(component, sessionName string, err error) {
 	if err == nil {
 		return
 	}
 
+	a.reportError(sessionCleanupReport(component, sessionName, err))
 	a.emitBackendEvent("session:cleanup-degraded", map[string]string{
 		"component":    component,
 		"session_name": sessionName,
@@ -158,6 +160,34 @@ func (a *App) sessionScopedLifecycleParticipants() []sessionScopedLifecycleParti
 			rename:  a.sessionMemoService.RenameSession,
 		})
 	}
+	if a.commandApproval != nil {
+		participants = append(participants, sessionScopedLifecycleParticipant{
+			name:    "command approval",
+			cleanup: a.commandApproval.CleanupSession,
+			rename:  a.commandApproval.RenameSession,
+		})
+	}
+	if a.resourceLimits != nil {
+		participants = append(participants, sessionScopedLifecycleParticipant{
+			name:    "resource limits",
+			cleanup: a.resourceLimits.Release,
+			rename:  a.resourceLimits.Rename,
+		})
+	}
+	if a.ephemeralService != nil {
+		participants = append(participants, sessionScopedLifecycleParticipant{
+			name:    "ephemeral sessions",
+			cleanup: a.ephemeralService.CleanupSession,
+			rename:  a.ephemeralService.Rename,
+		})
+	}
+	if a.networkCapture != nil {
+		participants = append(participants, sessionScopedLifecycleParticipant{
+			name:    "network capture",
+			cleanup: a.networkCapture.CleanupSession,
+			rename:  a.networkCapture.Rename,
+		})
+	}
 	return participants
 }
 
@@ -238,9 +268,10 @@ func (a *App) finalizeSessionDestroyed(sessionName string) {
 		)
 		return
 	}
-	a.snapshotService.CleanupDetachedPaneStates(
-		a.snapshotService.DetachStaleOutputBuffers(sessions.ActivePaneIDs()),
-	)
+	removed := a.snapshotService.DetachStaleOutputBuffers(sessions.ActivePaneIDs())
+	a.snapshotService.CleanupDetachedPaneStates(removed)
+	a.commandQueueService.Forget(removed)
+	a.outputWatchService.Forget(removed)
 }
 
 // Router-driven session lifecycle changes bypass session.Service, so the router
@@ -314,6 +345,33 @@ func (a *App) newRouterOptions(cfg config.Config) tmux.RouterOptions {
 		},
 		ResolveMCPStdio:     a.ResolveMCPStdio,
 		ResolveSessionByCwd: a.sessionService.ResolveSessionByCwd,
+		CopyBetweenSessions: a.copyBetweenSessionsFromShim,
+		ResolveShell: func(workDir string) string {
+			return config.Read(a.configState, func(cfg *config.Config) string {
+				return config.ResolveShell(*cfg, workDir)
+			})
+		},
+		ResolveWindowStartupCommand: func() (string, bool) {
+			startup := config.Read(a.configState, func(cfg *config.Config) config.StartupCommandsConfig {
+				if cfg.StartupCommands == nil {
+					return config.StartupCommandsConfig{}
+				}
+				return *cfg.StartupCommands
+			})
+			return startup.Window, startup.RemainOnExit
+		},
+		AcquireWarmTerminal: a.acquireWarmTerminal,
+		FoldOutput: func() bool {
+			return config.Read(a.configState, func(cfg *config.Config) bool { return cfg.OutputFolding })
+		},
+		ResolveGitIdentityEnv:    a.resolvePaneGitIdentityEnv,
+		ResolveToolPaths:         a.resolvePaneToolPaths,
+		ResolveShellHistoryDir:   a.resolvePaneShellHistoryDir,
+		ResolveNetworkCaptureEnv: a.networkCapture.ProxyEnv,
+		ControlModeEnabled: func() bool {
+			return a.featureEnabled(config.FeatureControlMode)
+		},
+		OnPaneStarted: a.applyPaneResourceLimits,
 	}
 }
 
@@ -350,8 +408,10 @@ func (a *App) startup(ctx context.Context) {
 	// Wrapping defaultHandler would create a cycle:
 	//   TeeHandler → defaultHandler → log.Logger → handlerWriter → TeeHandler
 	// which deadlocks on log.Logger's internal mutex.
-	a.initSessionLog(configPath)
-	baseHandler := slog.NewTextHandler(safeStderrWriter(), nil)
+	metrics := a.startupMetrics
+	metrics.Measure("session-log", func() { a.initSessionLog(configPath) })
+	a.logs = logging.NewManager(loggingOptions(configPath, nil))
+	baseHandler := a.logs.Handler(safeStderrWriter())
 	teeHandler := sessionlog.NewTeeHandler(baseHandler, slog.LevelWarn, func(ts time.Time, level slog.Level, msg string, group string) {
 		entry := SessionLogEntry{
 			Timestamp: ts.Format("20060102150405"),
@@ -362,9 +422,10 @@ func (a *App) startup(ctx context.Context) {
 		a.writeSessionLogEntry(entry)
 	})
 	slog.SetDefault(slog.New(teeHandler))
-	a.initInputHistory(configPath)
+	metrics.Measure("input-history", func() { a.initInputHistory(configPath) })
 
-	cfg, err := config.EnsureFile(configPath)
+	var cfg config.Config
+	metrics.Measure("config-load", func() { cfg, err = config.EnsureFile(configPath) })
 	if err != nil {
 		// Config load/parse failures are non-fatal by product spec.
 		// Continue startup with defaults and surface a warning to the user.
@@ -374,17 +435,83 @@ func (a *App) startup(ctx context.Context) {
 		)
 		runtimeLogger.Warningf(ctx, "failed to load config from %s: %v", configPath, err)
 	}
+	metrics.Measure("config-policy", func() { cfg = a.enforceConfigPolicy(configPath, cfg) })
 	a.configState.Initialize(configPath, cfg)
+	a.logs.Apply(loggingOptions(configPath, cfg.Logging))
+
+	metrics.Measure("router", func() {
+		a.sessions = tmux.NewSessionManager()
+		routerOpts := a.newRouterOptions(cfg)
+		slog.Debug("[CONFIG] agent model mapping is handled by tmux-shim")
+		a.router = tmux.NewCommandRouter(
+			a.sessions,
+			apptypes.EventEmitterFunc(a.emitBackendEvent),
+			routerOpts,
+		)
+	})
+	metrics.Measure("mcp-registry", func() { a.initMCP(ctx, cfg) })
+	// Ephemeral sessions left behind by a crash are removed before the pipe
+	// server accepts sessions, so that none can take over a leftover's name.
+	metrics.Measure("ephemeral-leftovers", a.ephemeralService.RemoveLeftovers)
+
+	metrics.Measure("pipe-server", func() { a.startPipeServer(ctx) })
+
+	// The shim must be on PATH before the first pane is spawned, so it stays
+	// on the blocking path.
+	metrics.Measure("shim-check", func() { a.ensureShimReady(workspace) })
+
+	metrics.Measure("websocket", func() { a.startWebSocketHub(ctx, cfg.WebSocketPort) })
 
-	a.sessions = tmux.NewSessionManager()
-	routerOpts := a.newRouterOptions(cfg)
-	slog.Debug("[CONFIG] agent model mapping is handled by tmux-shim")
-	a.router = tmux.NewCommandRouter(
-		a.sessions,
-		apptypes.EventEmitterFunc(a.emitBackendEvent),
-		routerOpts,
-	)
-	// MCP registry and manager initialization.
+	// Non-critical subsystems finish after the window is shown and report
+	// readiness via subsystemReadyEvent.
+	a.startBackgroundSubsystem(ctx, "deep-link", func() error {
+		a.ensureDeepLinkProtocol()
+		return nil
+	})
+	// Prune stale worktree entries left by abnormal exits.
+	a.startBackgroundSubsystem(ctx, "worktree-prune", func() error {
+		a.pruneStaleWorktreesOnStartup(cfg)
+		return nil
+	})
+	a.startBackgroundSubsystem(ctx, "global-hotkey", func() error {
+		a.configureGlobalHotkey()
+		return nil
+	})
+	a.startBackgroundSubsystem(ctx, "metrics", a.applyRuntimeMetricsUpdate)
+
+	metrics.Measure("watcher-setup", func() {
+		a.snapshotService.StartPaneFeedWorker(ctx)
+		a.startIdleMonitor(ctx)
+		a.startSessionPortWatcher(ctx)
+		a.startPaneActivityMonitor(ctx)
+		a.startGitStatusWatcher(ctx)
+		a.startBaseRefreshScheduler(ctx)
+		a.startSetupWatcher(ctx)
+		a.startEphemeralExpiry(ctx)
+		a.startJumpListWatcher(ctx)
+		a.startTaskbarAlerts(ctx)
+		a.startWebhookDelivery(ctx)
+		a.startErrorReporter(ctx)
+		a.startMaintenanceScheduler(ctx)
+		a.startActivityDigest(ctx)
+		a.startRenderingMonitor(ctx)
+		a.startSessionPool(ctx)
+		a.startHeartbeat(ctx)
+		a.startSessionLockListener(ctx)
+	})
+	a.snapshotService.RequestSnapshot(true)
+	metrics.MarkStartupDone()
+	slog.Info("[STARTUP] blocking startup finished", "elapsedMs", metrics.Report().StartupMs)
+	// NOTE: flushPendingConfigLoadWarnings is intentionally NOT called here.
+	// At this point the frontend has not yet registered its EventsOn() handlers,
+	// so any emitted warning events would be lost. Instead, warnings are flushed
+	// via GetConfigAndFlushWarnings(), which the frontend calls after Wails
+	// initialization is complete.
+}
+
+// initMCP builds the MCP registry from config and the built-in definitions
+// and creates the MCP manager. Servers are launched on demand, not here.
+func (a *App) initMCP(ctx context.Context, cfg config.Config) {
 	a.mcpRegistry = mcp.NewRegistry()
 	for _, loadErr := range a.mcpRegistry.LoadFromConfig(mcpapi.MCPServerConfigsToDefinitions(cfg.MCPServers)) {
 		warnMsg := fmt.Sprintf("Skipped MCP server config entry: %v", loadErr)
@@ -416,25 +543,55 @@ func (a *App) startup(ctx context.Context) {
 		ConfigDir:               appConfigDirProvider(a),
 		SingleTaskRunnerManager: a.singleTaskRunnerManager,
 	})
+}
 
-	a.pipeServer = newPipeServerFn(a.router.PipeName(), a.router)
-	if err := a.pipeServer.Start(); err != nil {
-		runtimeLogger.Errorf(ctx, "pipe server failed: %v", err)
+func (a *App) startPipeServer(ctx context.Context) {
+	pipeName := a.router.PipeName()
+	if a.discoverLiveServer(ctx, pipeName) {
+		a.startPipeTakeover(ctx, pipeName)
+		return
+	}
+	if err := a.listenPipeServer(ctx, pipeName); err != nil {
 		a.addPendingConfigLoadWarning(
 			fmt.Sprintf("Failed to start tmux IPC pipe server at startup. tmux commands may be unavailable. Error: %v", err),
 		)
-	} else {
-		runtimeLogger.Infof(ctx, "pipe server listening: %s", a.pipeServer.PipeName())
 	}
+}
 
-	a.ensureShimReady(workspace)
+// listenPipeServer starts the pipe server on pipeName. Shim commands pass
+// through the approval gate before reaching the router.
+func (a *App) listenPipeServer(ctx context.Context, pipeName string) error {
+	server := newPipeServerFn(pipeName, a.commandApproval)
+	if err := server.Start(); err != nil {
+		runtimeLogger.Errorf(ctx, "pipe server failed: %v", err)
+		return err
+	}
+	a.pipeServerMu.Lock()
+	a.pipeServer = server
+	a.pipeServerMu.Unlock()
+	runtimeLogger.Infof(ctx, "pipe server listening: %s", server.PipeName())
+	return nil
+}
 
-	// WebSocket server for high-throughput pane data streaming.
-	// Binds to localhost with OS-assigned port to avoid conflicts.
-	// Failure is non-fatal: output falls back to Wails IPC (slower but functional).
-	wsPort := cfg.WebSocketPort
+// currentPipeServer returns the running pipe server, or nil while another
+// server owns the pipe or startup failed.
+func (a *App) currentPipeServer() *ipc.PipeServer {
+	a.pipeServerMu.Lock()
+	defer a.pipeServerMu.Unlock()
+	return a.pipeServer
+}
+
+// startWebSocketHub starts the WebSocket server for high-throughput pane data
+// streaming. It binds to localhost with OS-assigned port to avoid conflicts.
+// Failure is non-fatal: output falls back to Wails IPC (slower but functional).
+func (a *App) startWebSocketHub(ctx context.Context, wsPort int) {
 	hub := wsserver.NewHub(wsserver.HubOptions{
-		Addr: fmt.Sprintf("127.0.0.1:%d", wsPort),
+		Addr:         fmt.Sprintf("127.0.0.1:%d", wsPort),
+		ScreenSource: a.paneScreen,
+		LocatePane:   a.paneLocation,
+		StreamEnabled: func() bool {
+			return a.featureEnabled(config.FeatureExternalStream)
+		},
 	})
 	if err := hub.Start(ctx); err != nil {
 		runtimeLogger.Errorf(ctx, "websocket server failed on port %d: %v", wsPort, err)
@@ -453,20 +610,6 @@ func (a *App) startup(ctx context.Context) {
 		// through the WebSocket path until after startup completes.
 		a.wsHub = hub
 	}
-
-	// Prune stale worktree entries left by abnormal exits.
-	// Runs before snapshot to keep git state clean from the start.
-	a.pruneStaleWorktreesOnStartup(cfg)
-
-	a.configureGlobalHotkey()
-	a.snapshotService.StartPaneFeedWorker(ctx)
-	a.startIdleMonitor(ctx)
-	a.snapshotService.RequestSnapshot(true)
-	// NOTE: flushPendingConfigLoadWarnings is intentionally NOT called here.
-	// At this point the frontend has not yet registered its EventsOn() handlers,
-	// so any emitted warning events would be lost. Instead, warnings are flushed
-	// via GetConfigAndFlushWarnings(), which the frontend calls after Wails
-	// initialization is complete.
 }
 
 // pruneStaleWorktreesOnStartup removes orphaned git worktree entries
@@ -514,6 +657,79 @@ func (a *App) shutdown(_ context.Context) {
 		a.idleCancel()
 		a.idleCancel = nil
 	}
+	if a.portsCancel != nil {
+		a.portsCancel()
+		a.portsCancel = nil
+	}
+	if a.paneActivityCancel != nil {
+		a.paneActivityCancel()
+		a.paneActivityCancel = nil
+	}
+	if a.gitStatusCancel != nil {
+		a.gitStatusCancel()
+		a.gitStatusCancel = nil
+	}
+	if a.baseRefreshCancel != nil {
+		a.baseRefreshCancel()
+		a.baseRefreshCancel = nil
+	}
+	if a.setupWatchCancel != nil {
+		a.setupWatchCancel()
+		a.setupWatchCancel = nil
+	}
+	if a.ephemeralCancel != nil {
+		a.ephemeralCancel()
+		a.ephemeralCancel = nil
+	}
+	if a.jumpListCancel != nil {
+		a.jumpListCancel()
+		a.jumpListCancel = nil
+	}
+	if a.taskbarCancel != nil {
+		a.taskbarCancel()
+		a.taskbarCancel = nil
+	}
+	if a.webhooksCancel != nil {
+		a.webhooksCancel()
+		a.webhooksCancel = nil
+	}
+	if a.errorsCancel != nil {
+		a.errorsCancel()
+		a.errorsCancel = nil
+	}
+	if a.maintenanceCancel != nil {
+		a.maintenanceCancel()
+		a.maintenanceCancel = nil
+	}
+	if a.activityDigestCancel != nil {
+		a.activityDigestCancel()
+		a.activityDigestCancel = nil
+	}
+	if a.renderingCancel != nil {
+		a.renderingCancel()
+		a.renderingCancel = nil
+	}
+	if a.sessionPoolCancel != nil {
+		a.sessionPoolCancel()
+		a.sessionPoolCancel = nil
+	}
+	if a.sessionPool != nil {
+		a.sessionPool.Close()
+	}
+	if a.heartbeatCancel != nil {
+		a.heartbeatCancel()
+		a.heartbeatCancel = nil
+	}
+	if a.sessionLockCancel != nil {
+		a.sessionLockCancel()
+		a.sessionLockCancel = nil
+	}
+	a.pipeServerMu.Lock()
+	if a.pipeTakeoverCancel != nil {
+		a.pipeTakeoverCancel()
+		a.pipeTakeoverCancel = nil
+	}
+	a.pipeServerMu.Unlock()
 	canceledSetupWorkers := a.cancelTrackedSetupWorkers()
 	if canceledSetupWorkers > 0 {
 		slog.Debug("[DEBUG-GIT] canceled active setup workers during shutdown", "count", canceledSetupWorkers)
@@ -530,6 +746,10 @@ func (a *App) shutdown(_ context.Context) {
 	// persistence, preventing entry loss for partially-typed lines.
 	a.flushAllLineBuffers()
 
+	// Ephemeral sessions end with the app; their destroy hooks remove the
+	// temp directories and history, the kills remove the worktrees.
+	a.ephemeralService.Shutdown()
+
 	// Shutdown the snapshot pipeline: detach output buffers, cleanup pane states,
 	// and reset caches/metrics. paneStates.Reset() is called separately because
 	// paneStates is shared with non-snapshot code (e.g. app_pane_api.go).
@@ -538,14 +758,17 @@ func (a *App) shutdown(_ context.Context) {
 	if a.paneStates != nil {
 		a.paneStates.Reset()
 	}
+	if a.outputSpill != nil {
+		a.outputSpill.Close()
+	}
 	if a.hotkeys != nil {
 		if err := a.hotkeys.Stop(); err != nil {
 			runtimeLogger.Warningf(logCtx, "hotkeys stop failed: %v", err)
 		}
 	}
 
-	if a.pipeServer != nil {
-		if err := a.pipeServer.Stop(); err != nil {
+	if pipeServer := a.currentPipeServer(); pipeServer != nil {
+		if err := pipeServer.Stop(); err != nil {
 			runtimeLogger.Warningf(logCtx, "pipe server stop failed: %v", err)
 		}
 	}
@@ -554,11 +777,23 @@ func (a *App) shutdown(_ context.Context) {
 			runtimeLogger.Warningf(logCtx, "websocket server stop failed: %v", err)
 		}
 	}
+	if a.metricsServer != nil {
+		a.metricsServer.Stop()
+	}
+	if a.resourceLimits != nil {
+		a.resourceLimits.Close()
+	}
+	if a.networkCapture != nil {
+		a.networkCapture.Shutdown()
+	}
 	if a.devpanelService != nil {
 		if err := a.devpanelService.StopAllWatchers(); err != nil {
 			runtimeLogger.Warningf(logCtx, "devpanel watcher stop failed: %v", err)
 		}
 	}
+	if a.repoStatsService != nil {
+		a.repoStatsService.Close()
+	}
 	if a.mcpManager != nil {
 		// Shutdown path: avoid runtime-dependent frontend lifecycle emissions.
 		a.mcpManager.CloseWithoutEvent()
@@ -568,4 +803,5 @@ func (a *App) shutdown(_ context.Context) {
 	}
 	a.closeInputHistory()
 	a.closeSessionLog()
+	a.closeStateStore()
 }
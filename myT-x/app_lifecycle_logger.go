@@ -59,6 +59,7 @@ var (
 	runtimeEventsEmitFn                  = runtime.EventsEmit
 	runtimeLogger       appRuntimeLogger = wailsRuntimeLogger{}
 	newPipeServerFn                      = ipc.NewPipeServer
+	probeServerFn                        = ipc.ProbeServer
 )
 
 // safeStderrWriter returns os.Stderr if it is writable, otherwise io.Discard.
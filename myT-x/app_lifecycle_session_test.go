@@ -43,7 +43,7 @@ func TestSessionScopedLifecycleParticipantsIncludeAllSessionManagers(t *testing.
 		}
 	}
 
-	wantNames := []string{"task scheduler", "single task runner", "devpanel", "mcp", "session memo"}
+	wantNames := []string{"task scheduler", "single task runner", "devpanel", "mcp", "session memo", "command approval", "resource limits", "ephemeral sessions", "network capture"}
 	for i, wantName := range wantNames {
 		if gotNames[i] != wantName {
 			t.Fatalf("participant[%d] = %q, want %q", i, gotNames[i], wantName)
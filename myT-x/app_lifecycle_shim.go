@@ -13,6 +13,7 @@ var (
 	ensureShimInstalledFn       = install.EnsureShimInstalled
 	resolveShimInstallDirFn     = install.ResolveInstallDir
 	ensureProcessPathContainsFn = install.EnsureProcessPathContains
+	runPathDoctorFn             = install.RunPathDoctor
 )
 
 // ensureShimReady synchronizes the tmux shim on every startup and updates
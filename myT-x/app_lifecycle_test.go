@@ -5,13 +5,16 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"os"
 	"strings"
 	"testing"
 	"time"
 
 	"myT-x/internal/config"
+	"myT-x/internal/deeplink"
 	"myT-x/internal/install"
 	"myT-x/internal/ipc"
+	"myT-x/internal/jumplist"
 	"myT-x/internal/mcp"
 	"myT-x/internal/panestate"
 	"myT-x/internal/singletaskrunner"
@@ -57,8 +60,11 @@ func restoreAllLifecycleHooks() {
 	ensureShimInstalledFn = install.EnsureShimInstalled
 	resolveShimInstallDirFn = install.ResolveInstallDir
 	ensureProcessPathContainsFn = install.EnsureProcessPathContains
+	registerDeepLinkProtocolFn = deeplink.EnsureRegistered
+	executablePathFn = os.Executable
 	runtimeLogger = wailsRuntimeLogger{}
 	newPipeServerFn = ipc.NewPipeServer
+	probeServerFn = ipc.ProbeServer
 	runtimeWindowIsMinimisedFn = runtime.WindowIsMinimised
 	runtimeWindowHideFn = runtime.WindowHide
 	runtimeWindowShowFn = runtime.WindowShow
@@ -606,6 +612,7 @@ func TestStartupAddsWarningWhenPipeServerStartFails(t *testing.T) {
 	ensureProcessPathContainsFn = func(string) bool {
 		return false
 	}
+	registerDeepLinkProtocolFn = func(string) (bool, error) { return false, nil }
 	var emittedWarning string
 	runtimeEventsEmitFn = func(_ context.Context, name string, data ...any) {
 		if name != "config:load-failed" || len(data) == 0 {
@@ -621,11 +628,18 @@ func TestStartupAddsWarningWhenPipeServerStartFails(t *testing.T) {
 	newPipeServerFn = func(pipeName string, _ ipc.CommandExecutor) *ipc.PipeServer {
 		return ipc.NewPipeServer(pipeName, nil)
 	}
+	probeServerFn = func(string) (ipc.ServerInfo, error) { return ipc.ServerInfo{}, ipc.ErrNoServer }
 
 	originalSlogHandler := slog.Default()
 
 	app := NewApp()
 	app.hotkeys = nil
+	// Keep the test binary's taskbar jump list untouched.
+	app.jumpListService = jumplist.NewService(jumplist.Deps{
+		SessionNames:  func() []string { return nil },
+		ActiveSession: func() string { return "" },
+		Apply:         func(jumplist.List) error { return nil },
+	})
 	app.startup(context.Background())
 	t.Cleanup(func() {
 		app.shutdown(context.Background())
@@ -68,6 +68,34 @@ func (a *App) setWindowVisible(visible bool) {
 	a.windowMu.Lock()
 	a.windowVisible = visible
 	a.windowMu.Unlock()
+	if visible && a.outputSpill != nil {
+		a.outputSpill.Refresh()
+	}
+}
+
+// paneOutputAttended reports whether the frontend consumes pane output: the
+// window is shown and its output stream is connected. Output is spilled to
+// disk otherwise.
+func (a *App) paneOutputAttended() bool {
+	// Before startup there is no frontend to come back; nothing is spilled.
+	if a.runtimeContext() == nil {
+		return true
+	}
+	a.windowMu.Lock()
+	visible := a.windowVisible
+	a.windowMu.Unlock()
+	if !visible {
+		return false
+	}
+	if a.wsHub == nil {
+		return true
+	}
+	if a.wsHub.HasActiveConnection() {
+		a.wsConnectedOnce.Store(true)
+		return true
+	}
+	// Until the stream first connects, output reaches the frontend over Wails IPC.
+	return !a.wsConnectedOnce.Load()
 }
 
 func (a *App) toggleQuakeWindow() {
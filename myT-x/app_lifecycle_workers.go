@@ -10,6 +10,10 @@ import (
 
 const shutdownWaitTimeout = 10 * time.Second
 
+// baseRefreshTickInterval is the resolution of worktree.base_refresh
+// interval_minutes.
+const baseRefreshTickInterval = time.Minute
+
 func (a *App) startIdleMonitor(parent context.Context) {
 	sessions, err := a.requireSessions()
 	if err != nil {
@@ -35,6 +39,7 @@ func (a *App) startIdleMonitor(parent context.Context) {
 			case <-timer.C:
 				if sessions.CheckIdleState() {
 					a.snapshotService.RequestSnapshot(false)
+					a.followActivity()
 				}
 				nextInterval = sessions.RecommendedIdleCheckInterval()
 				if nextInterval <= 0 {
@@ -46,14 +51,82 @@ func (a *App) startIdleMonitor(parent context.Context) {
 	}, a.defaultRecoveryOptions())
 }
 
+// startSessionPortWatcher polls listening TCP ports of pane process trees
+// and emits session-ports events when a session's dev server starts or stops.
+func (a *App) startSessionPortWatcher(parent context.Context) {
+	ctx, cancel := context.WithCancel(parent)
+	a.portsCancel = cancel
+	workerutil.RunWithPanicRecovery(ctx, "session-ports", &a.bgWG, a.sessionPortsService.Run, a.defaultRecoveryOptions())
+}
+
+// startPaneActivityMonitor moves quiet panes to idle and tracks the process
+// state that decides whether a pane waits for input.
+func (a *App) startPaneActivityMonitor(parent context.Context) {
+	ctx, cancel := context.WithCancel(parent)
+	a.paneActivityCancel = cancel
+	workerutil.RunWithPanicRecovery(ctx, "pane-activity", &a.bgWG, a.paneActivityService.Run, a.defaultRecoveryOptions())
+}
+
+// startGitStatusWatcher polls the worktree git status of every session and
+// emits git:status-changed events, so the sidebar stays current without
+// per-session queries.
+func (a *App) startGitStatusWatcher(parent context.Context) {
+	ctx, cancel := context.WithCancel(parent)
+	a.gitStatusCancel = cancel
+	workerutil.RunWithPanicRecovery(ctx, "git-status", &a.bgWG, a.gitStatusWatcher.Run, a.defaultRecoveryOptions())
+}
+
+// startBaseRefreshScheduler applies the worktree.base_refresh policy to the
+// worktree sessions whose scheduled check is due.
+func (a *App) startBaseRefreshScheduler(parent context.Context) {
+	ctx, cancel := context.WithCancel(parent)
+	a.baseRefreshCancel = cancel
+	workerutil.RunWithPanicRecovery(ctx, "base-refresh", &a.bgWG, func(ctx context.Context) {
+		ticker := time.NewTicker(baseRefreshTickInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				a.worktreeService.RefreshDueBaseBranches(now)
+			}
+		}
+	}, a.defaultRecoveryOptions())
+}
+
+// startSetupWatcher re-runs worktree.setup_watchers scripts when the
+// dependency files of a worktree session change.
+func (a *App) startSetupWatcher(parent context.Context) {
+	ctx, cancel := context.WithCancel(parent)
+	a.setupWatchCancel = cancel
+	workerutil.RunWithPanicRecovery(ctx, "setup-watch", &a.bgWG, a.setupWatchService.Run, a.defaultRecoveryOptions())
+}
+
+// startEphemeralExpiry removes ephemeral sessions whose TTL ran out.
+func (a *App) startEphemeralExpiry(parent context.Context) {
+	ctx, cancel := context.WithCancel(parent)
+	a.ephemeralCancel = cancel
+	workerutil.RunWithPanicRecovery(ctx, "ephemeral-expiry", &a.bgWG, a.ephemeralService.Run, a.defaultRecoveryOptions())
+}
+
+// startJumpListWatcher keeps the taskbar jump list in sync with the session
+// list and the order in which sessions were activated.
+func (a *App) startJumpListWatcher(parent context.Context) {
+	ctx, cancel := context.WithCancel(parent)
+	a.jumpListCancel = cancel
+	workerutil.RunWithPanicRecovery(ctx, "jump-list", &a.bgWG, a.jumpListService.Run, a.defaultRecoveryOptions())
+}
+
 // defaultRecoveryOptions returns the standard RecoveryOptions for App background
-// workers: notifies the frontend on panic/fatal and exits on shutdown detection.
+// workers: reports panic/fatal to the frontend error toasts, emits the
+// legacy worker events for webhooks, and exits on shutdown detection.
 // Worker-specific overrides (e.g. different MaxRetries) can be set on the
-// returned struct after calling this function, though no caller currently
-// applies overrides.
+// returned struct after calling this function.
 func (a *App) defaultRecoveryOptions() workerutil.RecoveryOptions {
 	return workerutil.RecoveryOptions{
 		OnPanic: func(worker string, attempt int) {
+			a.reportError(workerPanicReport(worker, attempt))
 			payload := map[string]any{"worker": worker, "attempt": attempt}
 			if rtCtx := a.runtimeContext(); rtCtx != nil {
 				a.emitRuntimeEventWithContext(rtCtx, "tmux:worker-panic", payload)
@@ -63,6 +136,7 @@ func (a *App) defaultRecoveryOptions() workerutil.RecoveryOptions {
 			}
 		},
 		OnFatal: func(worker string, maxRetries int) {
+			a.reportError(workerStoppedReport(worker, maxRetries))
 			payload := map[string]any{"worker": worker, "maxRetries": maxRetries}
 			if fatalCtx := a.runtimeContext(); fatalCtx != nil {
 				a.emitRuntimeEventWithContext(fatalCtx, "tmux:worker-fatal", payload)
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"errors"
+	"log/slog"
+	"path/filepath"
+
+	"myT-x/internal/config"
+	"myT-x/internal/logging"
+)
+
+// logDirName is the directory next to the config file that receives the
+// per-component log files.
+const logDirName = "logs"
+
+var errLoggingNotReady = errors.New("logging is not initialized")
+
+// loggingOptions converts the logging config into manager options. A nil
+// config uses info level, text format, and the default file limits.
+func loggingOptions(configPath string, cfg *config.LoggingConfig) logging.Options {
+	opts := logging.Options{
+		Dir:   filepath.Join(filepath.Dir(configPath), logDirName),
+		Level: slog.LevelInfo,
+	}
+	if cfg == nil {
+		return opts
+	}
+	if level, err := logging.ParseLevel(cfg.Level); err == nil {
+		opts.Level = level
+	}
+	opts.Format = cfg.Format
+	opts.MaxBytes = int64(cfg.MaxFileMB) * 1024 * 1024
+	opts.Keep = cfg.KeepFiles
+	opts.DisableFiles = cfg.DisableFiles
+	return opts
+}
+
+// applyRuntimeLoggingUpdate re-applies the saved logging config. It also
+// replaces a level set by SetLogLevel.
+func (a *App) applyRuntimeLoggingUpdate() {
+	if a.logs == nil {
+		return
+	}
+	a.logs.Apply(loggingOptions(a.configState.ConfigPath(), a.configState.Snapshot().Logging))
+}
+
+// GetLogLevel returns the current level of the app log: debug, info, warn,
+// or error.
+// Wails-bound: called from the frontend.
+func (a *App) GetLogLevel() string {
+	if a.logs == nil {
+		return logging.LevelName(slog.LevelInfo)
+	}
+	return logging.LevelName(a.logs.Level())
+}
+
+// SetLogLevel changes the level of the app log until the next restart or
+// the next save of the logging config, without restarting. Use
+// logging.level in the config to keep a level across restarts.
+// Wails-bound: called from the frontend.
+func (a *App) SetLogLevel(level string) error {
+	parsed, err := logging.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	if a.logs == nil {
+		return errLoggingNotReady
+	}
+	a.logs.SetLevel(parsed)
+	slog.Info("[LOGGING] log level changed", "level", logging.LevelName(parsed))
+	return nil
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+	"testing"
+
+	"myT-x/internal/config"
+	"myT-x/internal/logging"
+)
+
+func TestLoggingOptions(t *testing.T) {
+	configPath := filepath.Join("base", "config.yaml")
+	opts := loggingOptions(configPath, nil)
+	if opts.Dir != filepath.Join("base", logDirName) || opts.Level != slog.LevelInfo {
+		t.Fatalf("loggingOptions(nil) = %+v", opts)
+	}
+
+	opts = loggingOptions(configPath, &config.LoggingConfig{
+		Level: "debug", Format: "json", MaxFileMB: 2, KeepFiles: 3, DisableFiles: true,
+	})
+	if opts.Level != slog.LevelDebug || opts.Format != "json" || opts.MaxBytes != 2*1024*1024 || opts.Keep != 3 || !opts.DisableFiles {
+		t.Fatalf("loggingOptions() = %+v", opts)
+	}
+}
+
+func TestSetLogLevel(t *testing.T) {
+	origEmit := runtimeEventsEmitFn
+	t.Cleanup(func() {
+		runtimeEventsEmitFn = origEmit
+	})
+	runtimeEventsEmitFn = func(context.Context, string, ...any) {}
+
+	app := NewApp()
+	if err := app.SetLogLevel("debug"); err == nil {
+		t.Fatal("SetLogLevel() before startup succeeded")
+	}
+
+	configPath := newConfigPathForAPITest(t, "config.yaml")
+	app.setRuntimeContext(context.Background())
+	app.configState.Initialize(configPath, config.DefaultConfig())
+	app.logs = logging.NewManager(loggingOptions(configPath, nil))
+
+	if got := app.GetLogLevel(); got != "info" {
+		t.Fatalf("GetLogLevel() = %q, want info", got)
+	}
+	if err := app.SetLogLevel("verbose"); err == nil {
+		t.Fatal("SetLogLevel() with an unknown level succeeded")
+	}
+	if err := app.SetLogLevel("DEBUG"); err != nil {
+		t.Fatalf("SetLogLevel() error = %v", err)
+	}
+	if got := app.GetLogLevel(); got != "debug" {
+		t.Fatalf("GetLogLevel() = %q, want debug", got)
+	}
+
+	// Saving the logging config replaces the runtime level.
+	if _, err := app.configState.Update(func(cfg *config.Config) {
+		cfg.Logging = &config.LoggingConfig{Level: "warn"}
+	}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if got := app.GetLogLevel(); got != "warn" {
+		t.Fatalf("GetLogLevel() after config save = %q, want warn", got)
+	}
+}
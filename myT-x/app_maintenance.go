@@ -0,0 +1,227 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	gitpkg "myT-x/internal/git"
+	"myT-x/internal/maintenance"
+	"myT-x/internal/workerutil"
+)
+
+const (
+	// configBackupDirName is the directory under the config directory that
+	// holds config.yaml backups made by the config-backup maintenance job.
+	configBackupDirName = "config-backups"
+	// maxConfigBackups is the number of config.yaml backups kept.
+	maxConfigBackups = 10
+	// repoStatsCacheMaxIdle is how long an unused repository stays in the
+	// repository stats cache before cache compaction drops it.
+	repoStatsCacheMaxIdle = 30 * time.Minute
+)
+
+// buildMaintenanceServiceDeps constructs the dependency set for the
+// maintenance scheduler. Jobs only run while no app window has focus and
+// system CPU usage is low, and never around system sleep.
+func buildMaintenanceServiceDeps(app *App) maintenance.Deps {
+	probe := maintenance.NewIdleProbe()
+	return maintenance.Deps{
+		IsIdle: func() bool {
+			return !app.shuttingDown.Load() && !app.systemSuspended.Load() && probe.Idle()
+		},
+		StatePath: func() (string, error) {
+			dir, err := app.configDirProvider()
+			if err != nil {
+				return "", err
+			}
+			return filepath.Join(dir, maintenance.StateFileName), nil
+		},
+	}
+}
+
+// maintenanceJobs returns the built-in maintenance jobs.
+func (a *App) maintenanceJobs() []maintenance.Job {
+	return []maintenance.Job{
+		{
+			Name:        "log-prune",
+			Description: "Remove the oldest session log files",
+			Interval:    6 * time.Hour,
+			Run: func(context.Context) error {
+				a.sessionLogService.CleanupOldFiles()
+				return nil
+			},
+		},
+		{
+			Name:        "git-gc",
+			Description: "Run git gc --auto on repositories with worktree sessions",
+			Interval:    24 * time.Hour,
+			Run:         a.runWorktreeRepoGC,
+		},
+		{
+			Name:        "cache-compaction",
+			Description: "Drop unused entries from the repository stats cache",
+			Interval:    time.Hour,
+			Run: func(context.Context) error {
+				if dropped := a.repoStatsService.Compact(repoStatsCacheMaxIdle); dropped > 0 {
+					slog.Debug("[MAINTENANCE] repository stats cache compacted", "dropped", dropped)
+				}
+				return nil
+			},
+		},
+		{
+			Name:        "config-backup",
+			Description: "Back up config.yaml when it has changed",
+			Interval:    24 * time.Hour,
+			Run:         a.backupConfigFile,
+		},
+		{
+			Name:        "state-store-check",
+			Description: "Check the state store integrity and trim audit logs",
+			Interval:    24 * time.Hour,
+			Run:         a.checkStateStore,
+		},
+	}
+}
+
+// registerMaintenanceJobs adds the built-in jobs to the maintenance service.
+func (a *App) registerMaintenanceJobs() {
+	for _, job := range a.maintenanceJobs() {
+		if err := a.maintenanceService.Register(job); err != nil {
+			slog.Warn("[MAINTENANCE] failed to register job", "job", job.Name, "error", err)
+		}
+	}
+}
+
+// startMaintenanceScheduler runs due maintenance jobs while the app is idle.
+func (a *App) startMaintenanceScheduler(parent context.Context) {
+	ctx, cancel := context.WithCancel(parent)
+	a.maintenanceCancel = cancel
+	workerutil.RunWithPanicRecovery(ctx, "maintenance", &a.bgWG, a.maintenanceService.Run, a.defaultRecoveryOptions())
+}
+
+// GetMaintenanceJobs returns the registered maintenance jobs and their last run.
+// Wails-bound: called from the frontend.
+func (a *App) GetMaintenanceJobs() []maintenance.JobStatus {
+	if a.maintenanceService == nil {
+		return []maintenance.JobStatus{}
+	}
+	return a.maintenanceService.Status()
+}
+
+// RunMaintenanceJob runs the named maintenance job now, regardless of idleness.
+// Wails-bound: called from the frontend.
+func (a *App) RunMaintenanceJob(name string) error {
+	if a.maintenanceService == nil {
+		return errors.New("maintenance service is unavailable")
+	}
+	ctx := a.runtimeContext()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return a.maintenanceService.Trigger(ctx, strings.TrimSpace(name))
+}
+
+// runWorktreeRepoGC runs git gc --auto on every distinct parent repository
+// of the current worktree sessions.
+func (a *App) runWorktreeRepoGC(ctx context.Context) error {
+	sessions, err := a.requireSessions()
+	if err != nil {
+		return err
+	}
+	var repoPaths []string
+	for _, snapshot := range sessions.Snapshot() {
+		if snapshot.Worktree == nil || strings.TrimSpace(snapshot.Worktree.RepoPath) == "" {
+			continue
+		}
+		repoPaths = append(repoPaths, filepath.Clean(snapshot.Worktree.RepoPath))
+	}
+	slices.Sort(repoPaths)
+	repoPaths = slices.Compact(repoPaths)
+
+	var errs []error
+	for _, repoPath := range repoPaths {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if _, err := gitpkg.RunGitCLIPublic(repoPath, []string{"gc", "--auto", "--quiet"}); err != nil {
+			errs = append(errs, fmt.Errorf("git gc in %s: %w", repoPath, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// backupConfigFile copies config.yaml into configBackupDirName when it differs
+// from the newest backup, keeping the newest maxConfigBackups copies.
+func (a *App) backupConfigFile(context.Context) error {
+	configPath := a.configState.ConfigPath()
+	if strings.TrimSpace(configPath) == "" {
+		return errors.New("config path is not initialized")
+	}
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("read config: %w", err)
+	}
+
+	backupDir := filepath.Join(filepath.Dir(configPath), configBackupDirName)
+	backups, err := listConfigBackups(backupDir)
+	if err != nil {
+		return err
+	}
+	if len(backups) > 0 {
+		if newest, readErr := os.ReadFile(backups[len(backups)-1]); readErr == nil && bytes.Equal(newest, data) {
+			return nil
+		}
+	}
+
+	if err := os.MkdirAll(backupDir, 0o755); err != nil {
+		return fmt.Errorf("create backup directory: %w", err)
+	}
+	name := "config-" + time.Now().UTC().Format("20060102-150405") + ".yaml"
+	target := filepath.Join(backupDir, name)
+	if err := os.WriteFile(target, data, 0o600); err != nil {
+		return fmt.Errorf("write config backup: %w", err)
+	}
+	backups = append(backups, target)
+	backups = slices.Compact(backups)
+
+	for len(backups) > maxConfigBackups {
+		if err := os.Remove(backups[0]); err != nil && !errors.Is(err, os.ErrNotExist) {
+			slog.Warn("[MAINTENANCE] failed to remove old config backup", "path", backups[0], "error", err)
+		}
+		backups = backups[1:]
+	}
+	return nil
+}
+
+// listConfigBackups returns the config backups in dir, oldest first.
+// The timestamped names sort chronologically.
+func listConfigBackups(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read backup directory: %w", err)
+	}
+	var backups []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, "config-") || !strings.HasSuffix(name, ".yaml") {
+			continue
+		}
+		backups = append(backups, filepath.Join(dir, name))
+	}
+	slices.Sort(backups)
+	return backups, nil
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"myT-x/internal/config"
+	"myT-x/internal/maintenance"
+)
+
+func TestGetMaintenanceJobsListsBuiltInJobs(t *testing.T) {
+	app := NewApp()
+	app.configState.Initialize(filepath.Join(t.TempDir(), "config.yaml"), config.DefaultConfig())
+
+	var names []string
+	for _, job := range app.GetMaintenanceJobs() {
+		names = append(names, job.Name)
+	}
+	want := []string{"cache-compaction", "config-backup", "git-gc", "log-prune", "state-store-check"}
+	if len(names) != len(want) {
+		t.Fatalf("GetMaintenanceJobs() names = %v, want %v", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("GetMaintenanceJobs() names = %v, want %v", names, want)
+		}
+	}
+}
+
+func TestRunMaintenanceJobUnknown(t *testing.T) {
+	app := NewApp()
+	if err := app.RunMaintenanceJob("nope"); !errors.Is(err, maintenance.ErrJobNotFound) {
+		t.Fatalf("RunMaintenanceJob() error = %v, want ErrJobNotFound", err)
+	}
+	if err := (&App{}).RunMaintenanceJob("log-prune"); err == nil {
+		t.Fatal("RunMaintenanceJob() without service error = nil")
+	}
+}
+
+func TestBackupConfigFileSkipsUnchangedAndTrims(t *testing.T) {
+	app := NewApp()
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	app.configState.Initialize(configPath, config.DefaultConfig())
+	backupDir := filepath.Join(filepath.Dir(configPath), configBackupDirName)
+
+	// Missing config is not an error.
+	if err := app.RunMaintenanceJob("config-backup"); err != nil {
+		t.Fatalf("config-backup without config error = %v", err)
+	}
+
+	if err := os.WriteFile(configPath, []byte("shell: pwsh.exe\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for range 2 {
+		if err := app.RunMaintenanceJob("config-backup"); err != nil {
+			t.Fatalf("config-backup error = %v", err)
+		}
+	}
+	backups, err := listConfigBackups(backupDir)
+	if err != nil || len(backups) != 1 {
+		t.Fatalf("backups = %v (err %v), want one backup for unchanged config", backups, err)
+	}
+
+	// Old backups beyond the limit are removed, oldest first.
+	for i := range maxConfigBackups + 2 {
+		name := filepath.Join(backupDir, "config-20000101-0000"+string(rune('a'+i))+".yaml")
+		if err := os.WriteFile(name, []byte("old"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(configPath, []byte("shell: cmd.exe\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.RunMaintenanceJob("config-backup"); err != nil {
+		t.Fatalf("config-backup error = %v", err)
+	}
+	backups, err = listConfigBackups(backupDir)
+	if err != nil || len(backups) != maxConfigBackups {
+		t.Fatalf("backups = %d (err %v), want %d", len(backups), err, maxConfigBackups)
+	}
+	newest, err := os.ReadFile(backups[len(backups)-1])
+	if err != nil || string(newest) != "shell: cmd.exe\n" {
+		t.Fatalf("newest backup = %q (err %v), want current config", newest, err)
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"time"
+
+	"myT-x/internal/config"
+)
+
+// Worktree operations counted by the metrics endpoint.
+const (
+	worktreeOpCreate      = "create"
+	worktreeOpAttach      = "attach"
+	worktreeOpAdopt       = "adopt"
+	worktreeOpCleanup     = "cleanup"
+	worktreeOpStash       = "stash"
+	worktreeOpStashPop    = "stash-pop"
+	worktreeOpRefreshBase = "refresh-base"
+	worktreeOpCommitPush  = "commit-push"
+	worktreeOpPromote     = "promote"
+	worktreeOpPrune       = "prune"
+)
+
+// applyRuntimeMetricsUpdate starts, restarts, or stops the metrics endpoint
+// to match the metrics config.
+func (a *App) applyRuntimeMetricsUpdate() error {
+	if a.metricsServer == nil {
+		return nil
+	}
+	cfg := a.configState.Snapshot().Metrics
+	if cfg == nil || !cfg.Enabled {
+		a.metricsServer.Stop()
+		return nil
+	}
+	port := cfg.Port
+	if port == 0 {
+		port = config.DefaultMetricsPort
+	}
+	return a.metricsServer.Start(port)
+}
+
+// recordIPCRequest counts an executed IPC request that started at started.
+func (a *App) recordIPCRequest(command string, exitCode int, started time.Time) {
+	if a.metrics == nil {
+		return
+	}
+	a.metrics.ObserveIPC(command, exitCode, time.Since(started))
+}
+
+// recordWorktreeOp counts a worktree operation for the metrics endpoint.
+func (a *App) recordWorktreeOp(operation string, err error) {
+	if a.metrics == nil {
+		return
+	}
+	a.metrics.ObserveWorktree(operation, err)
+}
+
+// GetMetricsURL returns the Prometheus scrape URL, or "" when the metrics
+// endpoint is disabled.
+// Wails-bound: called from the frontend.
+func (a *App) GetMetricsURL() string {
+	if a.metricsServer == nil {
+		return ""
+	}
+	return a.metricsServer.URL()
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"myT-x/internal/config"
+)
+
+func TestApplyRuntimeMetricsUpdate(t *testing.T) {
+	origEmit := runtimeEventsEmitFn
+	t.Cleanup(func() {
+		runtimeEventsEmitFn = origEmit
+	})
+	runtimeEventsEmitFn = func(context.Context, string, ...any) {}
+
+	app := NewApp()
+	t.Cleanup(app.metricsServer.Stop)
+	configPath := newConfigPathForAPITest(t, "config.yaml")
+	app.setRuntimeContext(context.Background())
+	app.configState.Initialize(configPath, config.DefaultConfig())
+
+	if err := app.applyRuntimeMetricsUpdate(); err != nil {
+		t.Fatalf("applyRuntimeMetricsUpdate() error = %v", err)
+	}
+	if got := app.GetMetricsURL(); got != "" {
+		t.Fatalf("GetMetricsURL() = %q, want disabled by default", got)
+	}
+
+	// Saving the metrics config starts the endpoint; port 0 would pick the
+	// default, so a test uses the port of a stopped probe server.
+	if err := app.metricsServer.Start(0); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	probeURL := app.GetMetricsURL()
+	app.metricsServer.Stop()
+	port := portFromMetricsURL(t, probeURL)
+	if _, err := app.configState.Update(func(cfg *config.Config) {
+		cfg.Metrics = &config.MetricsConfig{Enabled: true, Port: port}
+	}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if got := app.GetMetricsURL(); got != probeURL {
+		t.Fatalf("GetMetricsURL() after enabling = %q, want %q", got, probeURL)
+	}
+
+	if _, err := app.configState.Update(func(cfg *config.Config) {
+		cfg.Metrics.Enabled = false
+	}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if got := app.GetMetricsURL(); got != "" {
+		t.Fatalf("GetMetricsURL() after disabling = %q", got)
+	}
+}
+
+func TestRecordMetricsOnBareApp(t *testing.T) {
+	app := &App{}
+	app.recordIPCRequest("list-panes", 0, time.Now())
+	app.recordWorktreeOp(worktreeOpCreate, errors.New("boom"))
+	if got := app.GetMetricsURL(); got != "" {
+		t.Fatalf("GetMetricsURL() = %q", got)
+	}
+}
+
+func TestRecordWorktreeOpCountsResult(t *testing.T) {
+	app := NewApp()
+	app.recordWorktreeOp(worktreeOpCleanup, nil)
+	app.recordWorktreeOp(worktreeOpCleanup, errors.New("locked"))
+	var out strings.Builder
+	if err := app.metrics.WriteText(&out); err != nil {
+		t.Fatalf("WriteText() error = %v", err)
+	}
+	for _, want := range []string{
+		`mytx_worktree_operations_total{operation="cleanup",result="ok"} 1`,
+		`mytx_worktree_operations_total{operation="cleanup",result="error"} 1`,
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("metrics missing %q in:\n%s", want, out.String())
+		}
+	}
+}
+
+func portFromMetricsURL(t *testing.T, url string) int {
+	t.Helper()
+	var port int
+	if _, err := fmt.Sscanf(url, "http://127.0.0.1:%d/metrics", &port); err != nil {
+		t.Fatalf("parse %q: %v", url, err)
+	}
+	return port
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"log/slog"
+	"strings"
+
+	"myT-x/internal/netcapture"
+	"myT-x/internal/sessioninfo"
+)
+
+// networkCaptureLogPath returns the capture log of a session in its
+// session-info directory, next to its shell and input history.
+func (a *App) networkCaptureLogPath(sessionName string) (string, error) {
+	configDir, err := appConfigDirProvider(a)()
+	if err != nil {
+		return "", err
+	}
+	workDir, err := a.sessionService.ResolveSessionWorkDir(sessionName)
+	if err != nil {
+		return "", err
+	}
+	return sessioninfo.FilePath(configDir, workDir, netcapture.LogFileName)
+}
+
+// SetNetworkCapture turns network capture on or off for a session. While it
+// is on, panes started afterwards send their HTTP(S) traffic through a local
+// recording proxy; captureBodies also keeps the start of plain HTTP bodies.
+// Turning it off keeps the recorded requests.
+// Wails-bound: called from the frontend.
+func (a *App) SetNetworkCapture(sessionName string, enabled, captureBodies bool) (netcapture.Status, error) {
+	sessionName = strings.TrimSpace(sessionName)
+	if !enabled {
+		if err := a.networkCapture.Disable(sessionName); err != nil {
+			return netcapture.Status{}, err
+		}
+		return a.networkCapture.Status(sessionName), nil
+	}
+	status, err := a.networkCapture.Enable(sessionName, captureBodies)
+	if err != nil {
+		slog.Warn("[WARN-NET-CAPTURE] failed to start capture", "session", sessionName, "error", err)
+		return netcapture.Status{}, err
+	}
+	return status, nil
+}
+
+// GetNetworkCaptureStatus returns whether a session's network capture is on.
+// Wails-bound: called from the frontend.
+func (a *App) GetNetworkCaptureStatus(sessionName string) netcapture.Status {
+	return a.networkCapture.Status(strings.TrimSpace(sessionName))
+}
+
+// ListNetworkCaptures returns up to limit requests recorded for a session,
+// newest first. limit <= 0 returns the most recent 1000.
+// Wails-bound: called from the frontend.
+func (a *App) ListNetworkCaptures(sessionName string, limit int) ([]netcapture.Record, error) {
+	return a.networkCapture.Records(strings.TrimSpace(sessionName), limit)
+}
+
+// ClearNetworkCaptures deletes the requests recorded for a session.
+// Wails-bound: called from the frontend.
+func (a *App) ClearNetworkCaptures(sessionName string) error {
+	return a.networkCapture.Clear(strings.TrimSpace(sessionName))
+}
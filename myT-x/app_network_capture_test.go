@@ -0,0 +1,52 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"myT-x/internal/netcapture"
+	"myT-x/internal/sessioninfo"
+	"myT-x/internal/tmux"
+)
+
+func TestSetNetworkCaptureStoresLogInSessionInfo(t *testing.T) {
+	app := NewApp()
+	configDir := t.TempDir()
+	workDir := t.TempDir()
+	app.configDirProvider = func() (string, error) { return configDir, nil }
+	mgr := tmux.NewSessionManager()
+	if _, _, err := mgr.CreateSession("agent", "0", 80, 24); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	if err := mgr.SetRootPath("agent", workDir); err != nil {
+		t.Fatalf("SetRootPath() error = %v", err)
+	}
+	app.sessions = mgr
+	t.Cleanup(app.networkCapture.Shutdown)
+
+	path, err := app.networkCaptureLogPath("agent")
+	if err != nil {
+		t.Fatalf("networkCaptureLogPath() error = %v", err)
+	}
+	baseDir, err := sessioninfo.DirectoryPath(configDir, workDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(baseDir, netcapture.LogFileName); path != want {
+		t.Fatalf("networkCaptureLogPath() = %q, want %q", path, want)
+	}
+
+	status, err := app.SetNetworkCapture("agent", true, false)
+	if err != nil || !status.Enabled || status.ProxyURL == "" {
+		t.Fatalf("SetNetworkCapture(on) = %+v, %v", status, err)
+	}
+	if !app.GetNetworkCaptureStatus("agent").Enabled {
+		t.Fatal("GetNetworkCaptureStatus() should report capture on")
+	}
+	if status, err := app.SetNetworkCapture("agent", false, false); err != nil || status.Enabled {
+		t.Fatalf("SetNetworkCapture(off) = %+v, %v", status, err)
+	}
+	if _, err := app.SetNetworkCapture("missing", true, false); err == nil {
+		t.Fatal("SetNetworkCapture() of an unknown session should fail")
+	}
+}
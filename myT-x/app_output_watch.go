@@ -0,0 +1,59 @@
+package main
+
+import (
+	"os"
+
+	"myT-x/internal/outputwatch"
+)
+
+// AddOutputWatch registers a watch expression evaluated against pane output.
+// target is a pane ID ("%3"), a session name, or "*" (or "") for all panes.
+// pattern is a regular expression matched against each output line with
+// escape sequences removed. action is "notify", "mark", or "hook:<command>".
+// Watches are kept until removed or until the app exits.
+// Wails-bound: called from the frontend.
+func (a *App) AddOutputWatch(target, pattern, action string) (outputwatch.Watch, error) {
+	return a.outputWatchService.Add(target, pattern, action)
+}
+
+// RemoveOutputWatch deletes an output watch by ID.
+// Wails-bound: called from the frontend.
+func (a *App) RemoveOutputWatch(id string) error {
+	return a.outputWatchService.Remove(id)
+}
+
+// ListOutputWatches returns the registered output watches.
+// Wails-bound: called from the frontend.
+func (a *App) ListOutputWatches() []outputwatch.Watch {
+	return a.outputWatchService.List()
+}
+
+// handlePaneOutputWatch evaluates output watches against flushed pane output.
+// Called from handlePaneOutput.
+func (a *App) handlePaneOutputWatch(paneID string, data []byte) {
+	a.outputWatchService.Scan(paneID, data)
+}
+
+// handleOutputWatchMatch reports a matched watch to the frontend and runs its
+// action. Wired as outputwatch.Deps.OnMatch.
+func (a *App) handleOutputWatchMatch(m outputwatch.Match) {
+	a.emitRuntimeEvent(outputwatch.MatchedEvent, outputwatch.NewMatchEvent(m))
+	switch m.Watch.Action {
+	case outputwatch.ActionMark:
+		a.handlePaneBell(m.PaneID)
+	case outputwatch.ActionHook:
+		workDir := ""
+		if sessions, err := a.requireSessions(); err == nil {
+			if paneCtx, err := paneContextSnapshot(sessions, m.PaneID); err == nil {
+				workDir = paneCtx.SessionWorkDir
+			}
+		}
+		env := append(os.Environ(),
+			"MYTX_PANE_ID="+m.PaneID,
+			"MYTX_SESSION="+m.SessionName,
+			"MYTX_WATCH_ID="+m.Watch.ID,
+			"MYTX_WATCH_LINE="+m.Line,
+		)
+		a.launchCommandHook(m.Watch.Hook, workDir, env)
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"myT-x/internal/outputwatch"
+	"myT-x/internal/tmux"
+)
+
+func newOutputWatchTestApp(t *testing.T) (*App, string, chan hookCall, *[]outputwatch.MatchEvent) {
+	t.Helper()
+	origEmit := runtimeEventsEmitFn
+	t.Cleanup(func() { runtimeEventsEmitFn = origEmit })
+
+	app := NewApp()
+	app.setRuntimeContext(context.Background())
+	app.sessions = tmux.NewSessionManager()
+	t.Cleanup(app.sessions.Close)
+
+	_, pane, err := app.sessions.CreateSession("agents", "main", 80, 24)
+	if err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+
+	calls := make(chan hookCall, 4)
+	app.runCommandHookFn = func(_ context.Context, hook, _ string, env []string) error {
+		calls <- hookCall{hook: hook, env: env}
+		return nil
+	}
+	var events []outputwatch.MatchEvent
+	runtimeEventsEmitFn = func(_ context.Context, name string, data ...any) {
+		if name != outputwatch.MatchedEvent || len(data) == 0 {
+			return
+		}
+		if payload, ok := data[0].(outputwatch.MatchEvent); ok {
+			events = append(events, payload)
+		}
+	}
+	return app, pane.IDString(), calls, &events
+}
+
+func TestOutputWatchNotifyEmitsMatch(t *testing.T) {
+	app, paneID, _, events := newOutputWatchTestApp(t)
+	watch, err := app.AddOutputWatch("agents", "FATAL", "notify")
+	if err != nil {
+		t.Fatalf("AddOutputWatch() error = %v", err)
+	}
+
+	app.handlePaneOutputWatch(paneID, []byte("ok\n\x1b[31mFATAL\x1b[0m: out of memory\r\n"))
+
+	if len(*events) != 1 {
+		t.Fatalf("events = %v, want one %s event", *events, outputwatch.MatchedEvent)
+	}
+	got := (*events)[0]
+	want := outputwatch.MatchEvent{
+		WatchID:     watch.ID,
+		PaneID:      paneID,
+		SessionName: "agents",
+		Pattern:     "FATAL",
+		Action:      outputwatch.ActionNotify,
+		Line:        "FATAL: out of memory",
+	}
+	if got != want {
+		t.Fatalf("event = %+v, want %+v", got, want)
+	}
+}
+
+func TestOutputWatchMarkRecordsBell(t *testing.T) {
+	app, paneID, _, _ := newOutputWatchTestApp(t)
+	if _, err := app.AddOutputWatch(paneID, "All tests passed", "mark"); err != nil {
+		t.Fatalf("AddOutputWatch() error = %v", err)
+	}
+
+	app.handlePaneOutputWatch(paneID, []byte("All tests passed\n"))
+
+	if !app.sessions.SessionNeedsInput("agents") {
+		t.Fatal("marked session should need input")
+	}
+}
+
+func TestOutputWatchHookRunsWithMatchEnv(t *testing.T) {
+	app, paneID, calls, _ := newOutputWatchTestApp(t)
+	watch, err := app.AddOutputWatch("", "deploy (ok|failed)", "hook:notify.cmd")
+	if err != nil {
+		t.Fatalf("AddOutputWatch() error = %v", err)
+	}
+
+	app.handlePaneOutputWatch(paneID, []byte("deploy failed\n"))
+
+	select {
+	case call := <-calls:
+		if call.hook != "notify.cmd" {
+			t.Fatalf("hook = %q, want notify.cmd", call.hook)
+		}
+		for _, want := range []string{
+			"MYTX_PANE_ID=" + paneID,
+			"MYTX_SESSION=agents",
+			"MYTX_WATCH_ID=" + watch.ID,
+			"MYTX_WATCH_LINE=deploy failed",
+		} {
+			if !slices.Contains(call.env, want) {
+				t.Fatalf("hook env missing %q", want)
+			}
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("hook was not run")
+	}
+	app.bgWG.Wait()
+}
+
+func TestOutputWatchListAndRemove(t *testing.T) {
+	app, paneID, _, events := newOutputWatchTestApp(t)
+	watch, err := app.AddOutputWatch("*", "boom", "notify")
+	if err != nil {
+		t.Fatalf("AddOutputWatch() error = %v", err)
+	}
+	if got := app.ListOutputWatches(); len(got) != 1 || got[0].ID != watch.ID {
+		t.Fatalf("ListOutputWatches() = %+v", got)
+	}
+	if err := app.RemoveOutputWatch(watch.ID); err != nil {
+		t.Fatalf("RemoveOutputWatch() error = %v", err)
+	}
+
+	app.handlePaneOutputWatch(paneID, []byte("boom\n"))
+	if len(*events) != 0 || len(app.ListOutputWatches()) != 0 {
+		t.Fatalf("events = %v after removing the watch", *events)
+	}
+}
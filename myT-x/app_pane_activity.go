@@ -0,0 +1,60 @@
+package main
+
+import (
+	"myT-x/internal/paneactivity"
+	"myT-x/internal/tmux"
+)
+
+// GetPaneActivity returns whether each pane is busy, idle, or waiting for
+// input, ordered by session name, then pane ID. Changes are also pushed as
+// pane:activity-changed events.
+// Wails-bound: called from the frontend.
+func (a *App) GetPaneActivity() []paneactivity.Activity {
+	return a.paneActivityService.Snapshot()
+}
+
+// handlePaneOutput feeds flushed pane output to the output consumers.
+// Wired as snapshot.Deps.OnPaneOutput.
+func (a *App) handlePaneOutput(paneID string, data []byte) {
+	a.paneActivityService.Observe(paneID, data)
+	a.handlePaneOutputWatch(paneID, data)
+}
+
+// forEachLivePane calls fn with the shell process of every live pane.
+func (a *App) forEachLivePane(fn func(sessionName string, pane tmux.PanePIDInfo)) {
+	sessions, err := a.requireSessions()
+	if err != nil {
+		return
+	}
+	for _, snapshot := range sessions.Snapshot() {
+		panes, err := sessions.GetSessionPanePIDs(snapshot.Name)
+		if err != nil {
+			// Session closed between Snapshot and the PID lookup.
+			continue
+		}
+		for _, pane := range panes {
+			fn(snapshot.Name, pane)
+		}
+	}
+}
+
+// paneChildProcesses reports which pane shells run a child process, keyed
+// by pane ID. It returns nil when the process tree is unavailable, which is
+// always the case outside Windows. Wired as paneactivity.Deps.ChildProcesses.
+func paneChildProcesses(panes []paneactivity.Pane) map[string]bool {
+	infos := make([]tmux.PanePIDInfo, 0, len(panes))
+	for _, pane := range panes {
+		infos = append(infos, tmux.PanePIDInfo{PaneID: pane.PaneID, PID: pane.PID})
+	}
+	childPIDs, err := buildChildPIDSet(infos)
+	if err != nil || childPIDs == nil {
+		return nil
+	}
+	children := make(map[string]bool, len(panes))
+	for _, pane := range panes {
+		if pane.PID > 0 {
+			children[pane.PaneID] = childPIDs[uint32(pane.PID)]
+		}
+	}
+	return children
+}
@@ -0,0 +1,18 @@
+package main
+
+import (
+	"testing"
+
+	"myT-x/internal/paneactivity"
+)
+
+func TestHandlePaneOutputFeedsPaneActivity(t *testing.T) {
+	app := NewApp()
+
+	app.handlePaneOutput("%1", []byte("Overwrite config.yaml? (y/n) "))
+
+	activities := app.GetPaneActivity()
+	if len(activities) != 1 || activities[0].PaneID != "%1" || activities[0].State != paneactivity.StateWaiting {
+		t.Fatalf("GetPaneActivity() = %+v, want %%1 waiting", activities)
+	}
+}
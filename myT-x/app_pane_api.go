@@ -5,6 +5,7 @@ import (
 	"log/slog"
 	"strings"
 
+	"myT-x/internal/config"
 	"myT-x/internal/tmux"
 )
 
@@ -81,6 +82,59 @@ func (a *App) SendInput(paneID string, input string) error {
 	return nil
 }
 
+// PasteResult reports the outcome of PasteToPane.
+type PasteResult struct {
+	// Pasted is false when nothing was written: the paste was empty or
+	// awaits confirmation.
+	Pasted bool `json:"pasted"`
+	// NeedsConfirmation is set when the paste would submit its lines to a
+	// shell and config paste_confirm is on. Call PasteToPane again with
+	// confirmed=true to paste anyway.
+	NeedsConfirmation bool `json:"needs_confirmation"`
+	Lines             int  `json:"lines"`
+	Bracketed         bool `json:"bracketed"`
+	StrippedControls  bool `json:"stripped_controls"`
+}
+
+// PasteToPane pastes clipboard text into a pane. Control characters are
+// stripped, and the text is wrapped in bracketed paste markers when the pane
+// application enabled bracketed paste. A paste whose line breaks would run
+// commands in a shell is held back until confirmed (config paste_confirm).
+func (a *App) PasteToPane(paneID string, text string, confirmed bool) (PasteResult, error) {
+	sessions, err := a.requireSessionsWithPaneID(&paneID)
+	if err != nil {
+		return PasteResult{}, err
+	}
+	modes, err := sessions.PaneTerminalModes(paneID)
+	if err != nil {
+		return PasteResult{}, err
+	}
+	paste := tmux.PreparePaste(text, modes)
+	result := PasteResult{
+		Lines:            paste.Lines,
+		Bracketed:        paste.Bracketed,
+		StrippedControls: paste.StrippedControls,
+	}
+	if paste.Lines == 0 {
+		return result, nil
+	}
+	if !confirmed && paste.SubmitsLines(modes) && config.EffectivePasteConfirm(a.configState.Snapshot()) {
+		result.NeedsConfirmation = true
+		return result, nil
+	}
+	if err := sessions.WriteToPane(paneID, paste.Data); err != nil {
+		slog.Debug("[PANE] PasteToPane failed", "paneID", paneID, "err", err)
+		return PasteResult{}, err
+	}
+	if paste.StrippedControls {
+		slog.Debug("[PANE] PasteToPane stripped control characters", "paneID", paneID)
+	}
+	sessionName := a.resolveSessionNameForPane(sessions, paneID)
+	a.recordInput(paneID, paste.Data, "paste", sessionName)
+	result.Pasted = true
+	return result, nil
+}
+
 // SendSyncInput writes input to all panes in the same window as the given pane.
 func (a *App) SendSyncInput(paneID string, input string) error {
 	sessions, err := a.requireSessionsWithPaneID(&paneID)
@@ -100,6 +154,11 @@ func (a *App) SendSyncInput(paneID string, input string) error {
 // Active panes use Snapshot semantics so restore does not start from an
 // arbitrary replay-ring byte boundary. Inactive panes may fall back to bounded
 // recent replay data.
+// Output spilled to disk while no client was attended takes precedence and is
+// consumed by the call, so it replays in full exactly once.
+// The replay is prefixed with the terminal modes the pane application has
+// enabled (alternate screen, application keys, bracketed paste); viewport
+// text alone would drop them and break full-screen programs after a remount.
 func (a *App) GetPaneReplay(paneID string) string {
 	if a.paneStates == nil {
 		return ""
@@ -108,7 +167,27 @@ func (a *App) GetPaneReplay(paneID string) string {
 	if paneID == "" {
 		return ""
 	}
-	return a.paneStates.Snapshot(paneID)
+	var replay string
+	if spilled, ok := a.takeSpilledOutput(paneID); ok {
+		replay = string(spilled)
+	} else {
+		replay = a.paneStates.Snapshot(paneID)
+	}
+	if a.sessions == nil {
+		return replay
+	}
+	modes, err := a.sessions.PaneTerminalModes(paneID)
+	if err != nil {
+		return replay
+	}
+	return modes.RestoreSequence() + replay
+}
+
+func (a *App) takeSpilledOutput(paneID string) ([]byte, bool) {
+	if a.outputSpill == nil {
+		return nil, false
+	}
+	return a.outputSpill.Take(paneID)
 }
 
 // ResizePane updates pane PTY size.
@@ -191,6 +270,72 @@ func (a *App) SwapPanes(sourcePaneID string, targetPaneID string) error {
 	return nil
 }
 
+// CollapsePane collapses a pane to its header. The pane keeps running and is
+// expanded again by output, by focusing it, or by ExpandPane.
+func (a *App) CollapsePane(paneID string) error {
+	return a.setPaneCollapsed(paneID, true)
+}
+
+// ExpandPane gives a collapsed pane its place in the layout back.
+func (a *App) ExpandPane(paneID string) error {
+	return a.setPaneCollapsed(paneID, false)
+}
+
+func (a *App) setPaneCollapsed(paneID string, collapsed bool) error {
+	sessions, err := a.requireSessionsWithPaneID(&paneID)
+	if err != nil {
+		return err
+	}
+	sessionName, err := sessions.SetPaneCollapsed(paneID, collapsed)
+	if err != nil {
+		return err
+	}
+	a.emitBackendEvent("tmux:layout-changed", map[string]any{
+		"sessionName": sessionName,
+	})
+	return nil
+}
+
+// SetPaneMouse sets whether mouse events reach the pane's applications:
+// "on", "off", or "" to follow the mouse config default.
+func (a *App) SetPaneMouse(paneID string, mode string) error {
+	sessions, err := a.requireSessionsWithPaneID(&paneID)
+	if err != nil {
+		return err
+	}
+	sessionName, err := sessions.SetPaneMouse(paneID, mode)
+	if err != nil {
+		return err
+	}
+	a.emitBackendEvent("tmux:layout-changed", map[string]any{
+		"sessionName": sessionName,
+	})
+	return nil
+}
+
+// CollapseIdlePanes collapses the quiet panes of the session's active window
+// and returns their IDs.
+func (a *App) CollapseIdlePanes(sessionName string) ([]string, error) {
+	sessionName = strings.TrimSpace(sessionName)
+	if sessionName == "" {
+		return nil, errors.New("session name is required")
+	}
+	sessions, err := a.requireSessions()
+	if err != nil {
+		return nil, err
+	}
+	collapsed, err := sessions.CollapseIdlePanes(sessionName)
+	if err != nil {
+		return nil, err
+	}
+	if len(collapsed) > 0 {
+		a.emitBackendEvent("tmux:layout-changed", map[string]any{
+			"sessionName": sessionName,
+		})
+	}
+	return collapsed, nil
+}
+
 // KillPane closes one pane and updates session state.
 func (a *App) KillPane(paneID string) error {
 	sessions, err := a.requireSessionsWithPaneID(&paneID)
@@ -212,7 +357,9 @@ func (a *App) KillPane(paneID string) error {
 	return nil
 }
 
-// ApplyLayoutPreset applies a layout preset to the active window of a session.
+// ApplyLayoutPreset applies a layout to the active window of a session. preset
+// may be a built-in preset, a tmux layout string, or a saved preset name
+// (see SaveLayoutPreset).
 // Active-window resolution and preset application are performed atomically inside
 // SessionManager to eliminate the TOCTOU gap between reading ActiveWindowID and
 // applying the layout. If ActiveWindowID points to a deleted window, the session
@@ -231,7 +378,7 @@ func (a *App) ApplyLayoutPreset(sessionName string, preset string) error {
 		return err
 	}
 
-	if err := sessions.ApplyLayoutPresetToActiveWindow(sessionName, tmux.LayoutPreset(preset)); err != nil {
+	if err := a.applyLayoutToActiveWindow(sessions, sessionName, preset); err != nil {
 		return err
 	}
 	a.emitBackendEvent("tmux:layout-changed", map[string]any{
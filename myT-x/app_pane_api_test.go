@@ -10,6 +10,8 @@ import (
 	"testing"
 	"time"
 
+	"myT-x/internal/config"
+	"myT-x/internal/outputspill"
 	"myT-x/internal/tmux"
 )
 
@@ -49,6 +51,67 @@ func TestSendInput(t *testing.T) {
 	})
 }
 
+func TestPasteToPane(t *testing.T) {
+	newApp := func(t *testing.T, cfg config.Config) (*App, string) {
+		t.Helper()
+		app := NewApp()
+		app.sessions = tmux.NewSessionManager()
+		app.configState.SetSnapshot(cfg)
+		_, pane, err := app.sessions.CreateSession("s1", "0", 120, 40)
+		if err != nil {
+			t.Fatalf("CreateSession() error = %v", err)
+		}
+		return app, fmt.Sprintf("%%%d", pane.ID)
+	}
+
+	t.Run("holds multi-line shell pastes for confirmation", func(t *testing.T) {
+		app, paneID := newApp(t, config.DefaultConfig())
+		result, err := app.PasteToPane(paneID, "cd /tmp\nrm -rf build\n", false)
+		if err != nil {
+			t.Fatalf("PasteToPane() error = %v", err)
+		}
+		if !result.NeedsConfirmation || result.Pasted || result.Lines != 2 {
+			t.Fatalf("PasteToPane() = %+v, want confirmation for 2 lines", result)
+		}
+	})
+
+	t.Run("confirmed pastes are written", func(t *testing.T) {
+		app, paneID := newApp(t, config.DefaultConfig())
+		// The pane has no terminal, so the write itself fails.
+		if _, err := app.PasteToPane(paneID, "a\nb", true); err == nil {
+			t.Fatal("PasteToPane() expected write error for nil terminal")
+		}
+	})
+
+	t.Run("paste_confirm off skips confirmation", func(t *testing.T) {
+		cfg := config.DefaultConfig()
+		cfg.PasteConfirm = config.PasteConfirmOff
+		app, paneID := newApp(t, cfg)
+		if _, err := app.PasteToPane(paneID, "a\nb", false); err == nil {
+			t.Fatal("PasteToPane() expected write error for nil terminal")
+		}
+	})
+
+	t.Run("empty paste writes nothing", func(t *testing.T) {
+		app, paneID := newApp(t, config.DefaultConfig())
+		result, err := app.PasteToPane(paneID, "\x1b\x07", false)
+		if err != nil {
+			t.Fatalf("PasteToPane() error = %v", err)
+		}
+		if result.Pasted || !result.StrippedControls {
+			t.Fatalf("PasteToPane() = %+v, want nothing pasted with stripped controls", result)
+		}
+	})
+
+	t.Run("requires pane id", func(t *testing.T) {
+		app := NewApp()
+		app.sessions = tmux.NewSessionManager()
+		if _, err := app.PasteToPane("  ", "echo", false); err == nil {
+			t.Fatal("PasteToPane() expected pane id validation error")
+		}
+	})
+}
+
 func TestSendSyncInput(t *testing.T) {
 	t.Run("returns error when session manager is unavailable", func(t *testing.T) {
 		app := NewApp()
@@ -155,6 +218,12 @@ func TestPaneMutationAPIsRequireSessionManager(t *testing.T) {
 	if err := app.KillPane("%1"); err == nil {
 		t.Fatal("KillPane() expected error when sessions is nil")
 	}
+	if err := app.CollapsePane("%1"); err == nil {
+		t.Fatal("CollapsePane() expected error when sessions is nil")
+	}
+	if _, err := app.CollapseIdlePanes("session-a"); err == nil {
+		t.Fatal("CollapseIdlePanes() expected error when sessions is nil")
+	}
 	if _, err := app.CreatePaneInSession("session-a"); err == nil {
 		t.Fatal("CreatePaneInSession() expected error when router is nil")
 	}
@@ -280,6 +349,88 @@ func TestPaneMutationAPIsSuccessPaths(t *testing.T) {
 		)
 	})
 
+	t.Run("CollapsePane removes pane from layout until ExpandPane", func(t *testing.T) {
+		app, firstPane, _ := newAppWithPanes(t)
+		var eventsMu sync.Mutex
+		events := make([]string, 0, 4)
+		runtimeEventsEmitFn = func(_ context.Context, name string, _ ...any) {
+			eventsMu.Lock()
+			events = append(events, name)
+			eventsMu.Unlock()
+		}
+		takeEvents := func() []string {
+			eventsMu.Lock()
+			defer eventsMu.Unlock()
+			taken := events
+			events = make([]string, 0, 4)
+			return taken
+		}
+		waitForSnapshot := func(step string) {
+			t.Helper()
+			// snapshotCoalesceWindow (50ms) + 300ms headroom for CI jitter.
+			var seen []string
+			waitForCondition(
+				t,
+				350*time.Millisecond,
+				func() bool {
+					seen = append(seen, takeEvents()...)
+					return containsAnyEvent(seen, "tmux:snapshot", "tmux:snapshot-delta")
+				},
+				"snapshot update event after "+step,
+			)
+			if !containsEvent(seen, "tmux:layout-changed") {
+				t.Fatalf("events = %v, want tmux:layout-changed after %s", seen, step)
+			}
+		}
+
+		if err := app.CollapsePane(firstPane); err != nil {
+			t.Fatalf("CollapsePane() error = %v", err)
+		}
+		window := app.sessions.Snapshot()[0].Windows[0]
+		if !window.Panes[0].Collapsed || window.Layout.Type != tmux.LayoutLeaf {
+			t.Fatalf("collapsed window = %+v, want single-leaf layout", window)
+		}
+		waitForSnapshot("collapse")
+
+		if err := app.ExpandPane(firstPane); err != nil {
+			t.Fatalf("ExpandPane() error = %v", err)
+		}
+		window = app.sessions.Snapshot()[0].Windows[0]
+		if window.Panes[0].Collapsed || window.Layout.Type != tmux.LayoutSplit {
+			t.Fatalf("expanded window = %+v, want split layout", window)
+		}
+		waitForSnapshot("expand")
+	})
+
+	t.Run("SetPaneMouse stores the pane override and rejects unknown modes", func(t *testing.T) {
+		app, firstPane, _ := newAppWithPanes(t)
+		var eventsMu sync.Mutex
+		var events []string
+		runtimeEventsEmitFn = func(_ context.Context, name string, _ ...any) {
+			eventsMu.Lock()
+			events = append(events, name)
+			eventsMu.Unlock()
+		}
+
+		if err := app.SetPaneMouse(firstPane, "off"); err != nil {
+			t.Fatalf("SetPaneMouse() error = %v", err)
+		}
+		if got := app.sessions.Snapshot()[0].Windows[0].Panes[0].Mouse; got != tmux.PaneMouseOff {
+			t.Fatalf("pane mouse = %q, want off", got)
+		}
+		eventsMu.Lock()
+		defer eventsMu.Unlock()
+		if !containsEvent(events, "tmux:layout-changed") {
+			t.Fatalf("events = %v, want tmux:layout-changed", events)
+		}
+		if err := app.SetPaneMouse(firstPane, "scroll"); err == nil {
+			t.Fatal("SetPaneMouse(scroll) succeeded, want error")
+		}
+		if err := app.SetPaneMouse(" ", "on"); err == nil {
+			t.Fatal("SetPaneMouse with empty pane id succeeded, want error")
+		}
+	})
+
 	t.Run("KillPane emits session-emptied when last pane is removed", func(t *testing.T) {
 		app := NewApp()
 		app.setRuntimeContext(context.Background())
@@ -408,6 +559,30 @@ func TestGetPaneReplay(t *testing.T) {
 			t.Fatalf("GetPaneReplay() = %q, want exact inactive replay", got)
 		}
 	})
+
+	t.Run("replays spilled output once", func(t *testing.T) {
+		app := NewApp()
+		app.outputSpill = outputspill.NewSpool(outputspill.Deps{
+			Attended: func() bool { return false },
+			Seed: func(paneID string) []byte {
+				return []byte(app.paneStates.Replay(paneID))
+			},
+			Dir: t.TempDir(),
+		})
+		t.Cleanup(app.outputSpill.Close)
+		app.paneStates.EnsurePane("%11", 40, 2)
+		app.paneStates.SetActivePanes(map[string]struct{}{"%11": {}})
+		app.paneStates.Feed("%11", []byte("line1\n"))
+		app.outputSpill.Write("%11", []byte("line2\nline3"))
+		app.paneStates.Feed("%11", []byte("line2\nline3"))
+
+		if got := app.GetPaneReplay("%11"); got != "line1\nline2\nline3" {
+			t.Fatalf("GetPaneReplay() = %q, want the full spilled history", got)
+		}
+		if got := app.GetPaneReplay("%11"); got != "line2\nline3" {
+			t.Fatalf("GetPaneReplay() after the spill was taken = %q, want the viewport", got)
+		}
+	})
 }
 
 func TestGetPaneEnvValidation(t *testing.T) {
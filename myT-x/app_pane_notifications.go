@@ -0,0 +1,35 @@
+package main
+
+import (
+	"log/slog"
+
+	"myT-x/internal/oscnotify"
+)
+
+// paneNotificationEvent carries a desktop notification (OSC 9, OSC 777
+// notify) sent by a program running in a pane.
+const paneNotificationEvent = "pane:notification"
+
+// handlePaneNotifications forwards notifications from pane output to the
+// frontend and marks the pane's session as needing attention.
+// Wired as snapshot.Deps.OnPaneNotifications.
+func (a *App) handlePaneNotifications(paneID string, notifications []oscnotify.Notification) {
+	sessions, err := a.requireSessions()
+	if err != nil {
+		return
+	}
+	sessionName, _, ok := sessions.PaneLocation(paneID)
+	if !ok {
+		slog.Debug("[DEBUG-NOTIFY] pane notification from unknown pane", "paneID", paneID)
+		return
+	}
+	for _, notification := range notifications {
+		a.emitRuntimeEvent(paneNotificationEvent, map[string]any{
+			"paneId":      paneID,
+			"sessionName": sessionName,
+			"title":       notification.Title,
+			"body":        notification.Body,
+		})
+	}
+	a.handlePaneBell(paneID)
+}
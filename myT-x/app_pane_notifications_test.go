@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"myT-x/internal/config"
+	"myT-x/internal/oscnotify"
+)
+
+func TestHandlePaneNotificationsEmitsEventAndMarksSession(t *testing.T) {
+	origEmit := runtimeEventsEmitFn
+	t.Cleanup(func() { runtimeEventsEmitFn = origEmit })
+	var events []map[string]any
+	runtimeEventsEmitFn = func(_ context.Context, name string, data ...any) {
+		if name == paneNotificationEvent && len(data) > 0 {
+			events = append(events, data[0].(map[string]any))
+		}
+	}
+
+	app, panes := newSessionAttentionAppForTest(t, config.DefaultConfig(), "current", "build")
+	app.setRuntimeContext(context.Background())
+	app.SetActiveSession("current")
+
+	app.handlePaneNotifications(panes["build"], []oscnotify.Notification{
+		{Body: "Build finished"},
+		{Title: "Tests", Body: "all passed"},
+	})
+
+	if len(events) != 2 {
+		t.Fatalf("events = %v, want two %s events", events, paneNotificationEvent)
+	}
+	if events[0]["sessionName"] != "build" || events[0]["paneId"] != panes["build"] || events[0]["body"] != "Build finished" {
+		t.Fatalf("first event = %v", events[0])
+	}
+	if events[1]["title"] != "Tests" || events[1]["body"] != "all passed" {
+		t.Fatalf("second event = %v", events[1])
+	}
+	if got, _ := app.FocusNextActiveSession(); got != "build" {
+		t.Fatalf("FocusNextActiveSession() = %q, want the notifying session", got)
+	}
+
+	events = nil
+	app.handlePaneNotifications("%999", []oscnotify.Notification{{Body: "stray"}})
+	if len(events) != 0 {
+		t.Fatalf("events for unknown pane = %v, want none", events)
+	}
+}
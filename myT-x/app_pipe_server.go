@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"myT-x/internal/ipc"
+)
+
+// pipeServerRestartDrainTimeout bounds how long a restart waits for
+// in-flight shim requests. It stays below the shim's response timeout so
+// requests parked during the drain are still waiting for their retry frame.
+const pipeServerRestartDrainTimeout = 10 * time.Second
+
+// RestartPipeServer re-creates the shim pipe listener. In-flight requests
+// are drained first, and requests that arrive meanwhile are handed to the
+// new listener instead of failing. The report is kept for GetMetrics.
+// Wails-bound: called from the frontend.
+func (a *App) RestartPipeServer() (ipc.RestartReport, error) {
+	pipeServer := a.currentPipeServer()
+	if pipeServer == nil {
+		return ipc.RestartReport{}, errors.New("pipe server is not running")
+	}
+	ctx := a.runtimeContext()
+	report, err := pipeServer.Restart(pipeServerRestartDrainTimeout)
+	a.lastPipeServerRestart.Store(&report)
+	if err != nil {
+		runtimeLogger.Errorf(ctx, "pipe server restart failed: %v", err)
+		if errors.Is(err, ipc.ErrRestartListenFailed) {
+			a.replaceStoppedPipeServer(ctx, pipeServer)
+		}
+		return report, err
+	}
+	runtimeLogger.Infof(ctx, "pipe server restarted: drained=%d dropped=%d handed_off=%d in %dms",
+		report.Drained, report.Dropped, report.HandedOff, report.DurationMs)
+	return report, nil
+}
+
+// replaceStoppedPipeServer forgets server, which stopped because it could not
+// re-create its listener, and keeps trying to start a new one in the
+// background like after a foreign server exits.
+func (a *App) replaceStoppedPipeServer(ctx context.Context, server *ipc.PipeServer) {
+	a.pipeServerMu.Lock()
+	if a.pipeServer == server {
+		a.pipeServer = nil
+	}
+	a.pipeServerMu.Unlock()
+	if a.shuttingDown.Load() {
+		return
+	}
+	a.startPipeTakeover(ctx, server.PipeName())
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"myT-x/internal/ipc"
+)
+
+func TestRestartPipeServerWithoutServer(t *testing.T) {
+	app := NewApp()
+	if _, err := app.RestartPipeServer(); err == nil {
+		t.Fatal("RestartPipeServer() without a server error = nil")
+	}
+	if got := app.GetMetrics().PipeServerRestart; got != nil {
+		t.Fatalf("PipeServerRestart = %+v, want nil before any restart", got)
+	}
+}
+
+func TestRestartPipeServerRecordsFailedRestart(t *testing.T) {
+	app := NewApp()
+	// Never started, so Restart fails without touching a real pipe.
+	app.pipeServer = ipc.NewPipeServer(`\\.\pipe\myT-x-restart-test`, nil)
+	if _, err := app.RestartPipeServer(); err == nil {
+		t.Fatal("RestartPipeServer() on a stopped server error = nil")
+	}
+	if got := app.GetMetrics().PipeServerRestart; got == nil || got.Restarts != 0 {
+		t.Fatalf("PipeServerRestart = %+v, want the failed restart recorded", got)
+	}
+}
+
+func TestReplaceStoppedPipeServerForgetsServer(t *testing.T) {
+	app := NewApp()
+	server := ipc.NewPipeServer(`\\.\pipe\myT-x-restart-test`, nil)
+	app.pipeServer = server
+	// While shutting down no replacement is started.
+	app.shuttingDown.Store(true)
+
+	app.replaceStoppedPipeServer(context.Background(), server)
+
+	if app.currentPipeServer() != nil {
+		t.Fatal("stopped pipe server still current")
+	}
+	if app.pipeTakeoverCancel != nil {
+		t.Fatal("replacement started during shutdown")
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"myT-x/internal/powerstate"
+	"myT-x/internal/procutil"
+	"myT-x/internal/tmux"
+	"myT-x/internal/workerutil"
+)
+
+// SystemResumedEvent is the system:resumed payload: what the health check
+// after a wake or unlock found.
+type SystemResumedEvent struct {
+	// Reason is powerstate.ReasonResume or powerstate.ReasonUnlock.
+	Reason string `json:"reason"`
+	// AwaySec is how long the system slept or stayed locked; 0 when unknown.
+	AwaySec      int64           `json:"away_sec"`
+	PanesChecked int             `json:"panes_checked"`
+	DeadPanes    []tmux.DeadPane `json:"dead_panes"`
+}
+
+// buildPowerMonitorDeps constructs the dependency set for the power state
+// monitor. Suspend only raises a flag that background pollers check; the
+// actual work of resume runs in a worker so the OS event thread never blocks.
+func buildPowerMonitorDeps(app *App) powerstate.Deps {
+	return powerstate.Deps{
+		Pause: func() {
+			app.systemSuspended.Store(true)
+			slog.Info("[POWER] system is suspending; pausing background work")
+		},
+		Resume: app.handleSystemResume,
+	}
+}
+
+// onSystemSuspend is the Wails OnSuspend callback.
+func (a *App) onSystemSuspend() {
+	a.powerMonitor.Suspend()
+}
+
+// onSystemResume is the Wails OnResume callback.
+func (a *App) onSystemResume() {
+	a.powerMonitor.Resume()
+}
+
+// handleSystemResume resumes background work and checks sessions in a worker.
+func (a *App) handleSystemResume(reason string, away time.Duration) {
+	a.systemSuspended.Store(false)
+	ctx := a.runtimeContext()
+	if ctx == nil || a.shuttingDown.Load() {
+		return
+	}
+	opts := a.defaultRecoveryOptions()
+	opts.MaxRetries = 1
+	workerutil.RunWithPanicRecovery(ctx, "power-resume", &a.bgWG, func(context.Context) {
+		a.emitRuntimeEvent("system:resumed", a.checkSessionsAfterResume(reason, away))
+	}, opts)
+}
+
+// checkSessionsAfterResume closes panes whose shell died while the system was
+// away, drops dead warm shells, and refreshes the frontend snapshot.
+func (a *App) checkSessionsAfterResume(reason string, away time.Duration) SystemResumedEvent {
+	event := SystemResumedEvent{
+		Reason:    reason,
+		AwaySec:   int64(away / time.Second),
+		DeadPanes: []tmux.DeadPane{},
+	}
+	if router, err := a.requireRouter(); err == nil {
+		checked, dead := router.ReconcileDeadPanes(procutil.ProcessAlive)
+		event.PanesChecked = checked
+		event.DeadPanes = append(event.DeadPanes, dead...)
+	}
+	a.sessionPool.CheckHealth()
+	a.snapshotService.RequestSnapshot(true)
+	slog.Info("[POWER] checked sessions after resume",
+		"reason", reason,
+		"awaySec", event.AwaySec,
+		"panesChecked", event.PanesChecked,
+		"deadPanes", len(event.DeadPanes),
+	)
+	return event
+}
+
+// startSessionLockListener forwards session lock/unlock notifications to the
+// power monitor until shutdown.
+func (a *App) startSessionLockListener(parent context.Context) {
+	ctx, cancel := context.WithCancel(parent)
+	a.sessionLockCancel = cancel
+	opts := a.defaultRecoveryOptions()
+	opts.MaxRetries = 1
+	workerutil.RunWithPanicRecovery(ctx, "session-lock", &a.bgWG, func(ctx context.Context) {
+		err := powerstate.ListenSessionLock(ctx, a.powerMonitor.Lock, a.powerMonitor.Unlock)
+		if err != nil && !errors.Is(err, errors.ErrUnsupported) {
+			slog.Warn("[WARN-POWER] session lock notifications unavailable", "error", err)
+		}
+	}, opts)
+}
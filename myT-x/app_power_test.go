@@ -0,0 +1,36 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"myT-x/internal/powerstate"
+)
+
+func TestSystemSuspendResumeTogglesPause(t *testing.T) {
+	app := NewApp()
+
+	app.onSystemSuspend()
+	if !app.systemSuspended.Load() {
+		t.Fatal("systemSuspended = false after suspend")
+	}
+	// Without a runtime context the resume worker is skipped, but background
+	// work must still be unpaused.
+	app.onSystemResume()
+	if app.systemSuspended.Load() {
+		t.Fatal("systemSuspended = true after resume")
+	}
+}
+
+func TestCheckSessionsAfterResumeWithoutRouter(t *testing.T) {
+	app := NewApp()
+
+	event := app.checkSessionsAfterResume(powerstate.ReasonUnlock, 90*time.Second)
+
+	if event.Reason != powerstate.ReasonUnlock || event.AwaySec != 90 || event.PanesChecked != 0 {
+		t.Fatalf("checkSessionsAfterResume() = %+v", event)
+	}
+	if event.DeadPanes == nil {
+		t.Fatal("DeadPanes must be an empty slice, not nil, for the frontend")
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	gitpkg "myT-x/internal/git"
+	"myT-x/internal/preopsnapshot"
+)
+
+// GetPreOpSnapshots returns the snapshots captured before risky operations
+// (force cleanup, branch promotion) on sessionName, newest first. Snapshots
+// outlive the session so they can be inspected after a failed operation.
+// Wails-bound: called from the frontend for post-mortem.
+func (a *App) GetPreOpSnapshots(sessionName string) ([]preopsnapshot.Snapshot, error) {
+	if a.preOpSnapshotService == nil {
+		return nil, errors.New("pre-operation snapshot service is unavailable")
+	}
+	sessionName = strings.TrimSpace(sessionName)
+	if sessionName == "" {
+		return nil, errors.New("session name is required")
+	}
+	return a.preOpSnapshotService.List(sessionName), nil
+}
+
+// captureBeforeRiskyOperation records a pre-operation snapshot; wired into
+// the session and worktree services.
+func (a *App) captureBeforeRiskyOperation(operation preopsnapshot.Operation, sessionName, detail string) {
+	if a.preOpSnapshotService == nil {
+		return
+	}
+	a.preOpSnapshotService.Capture(operation, sessionName, detail)
+}
+
+// capturePreOpSessionState collects the layout and pane contents of a session
+// for preopsnapshot.
+func (a *App) capturePreOpSessionState(sessionName string) (preopsnapshot.SessionState, error) {
+	sessions, err := a.requireSessions()
+	if err != nil {
+		return preopsnapshot.SessionState{}, err
+	}
+	var state preopsnapshot.SessionState
+	found := false
+	for _, snapshot := range sessions.Snapshot() {
+		if snapshot.Name != sessionName {
+			continue
+		}
+		found = true
+		state.WorkDir = snapshot.RootPath
+		if snapshot.Worktree != nil && snapshot.Worktree.Path != "" {
+			state.WorkDir = snapshot.Worktree.Path
+		}
+		for _, window := range snapshot.Windows {
+			for _, pane := range window.Panes {
+				state.Panes = append(state.Panes, preopsnapshot.PaneContent{
+					PaneID:  pane.ID,
+					Window:  window.Name,
+					Title:   pane.Title,
+					Content: a.GetPaneReplay(pane.ID),
+				})
+			}
+		}
+		break
+	}
+	if !found {
+		return preopsnapshot.SessionState{}, fmt.Errorf("session not found: %s", sessionName)
+	}
+	if layout, _, err := sessions.ActiveWindowLayoutString(sessionName); err == nil {
+		state.Layout = layout
+	}
+	return state, nil
+}
+
+// preOpGitStatus returns the branch header and porcelain status of dir.
+func preOpGitStatus(dir string) (string, error) {
+	output, err := gitpkg.RunGitCLIPublic(dir, []string{"status", "--branch", "--porcelain=v1"})
+	return strings.TrimRight(string(output), "\n"), err
+}
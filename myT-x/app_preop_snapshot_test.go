@@ -0,0 +1,68 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"myT-x/internal/preopsnapshot"
+	"myT-x/internal/testutil"
+	"myT-x/internal/tmux"
+)
+
+func TestPromoteWorktreeToBranchCapturesPreOpSnapshot(t *testing.T) {
+	repoPath := testutil.CreateTempGitRepo(t)
+	runGitInDir(t, repoPath, "checkout", "--detach")
+
+	app := NewApp()
+	app.sessions = tmux.NewSessionManager()
+	if _, _, err := app.sessions.CreateSession("session-a", "0", 120, 40); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	if err := app.sessions.SetWorktreeInfo("session-a", &tmux.SessionWorktreeInfo{
+		Path:       repoPath,
+		RepoPath:   repoPath,
+		BaseBranch: "HEAD",
+		IsDetached: true,
+	}); err != nil {
+		t.Fatalf("SetWorktreeInfo() error = %v", err)
+	}
+
+	if err := app.PromoteWorktreeToBranch("session-a", "feature/promoted", PromoteWorktreeOptions{}); err != nil {
+		t.Fatalf("PromoteWorktreeToBranch() error = %v", err)
+	}
+
+	snapshots, err := app.GetPreOpSnapshots("session-a")
+	if err != nil {
+		t.Fatalf("GetPreOpSnapshots() error = %v", err)
+	}
+	if len(snapshots) != 1 {
+		t.Fatalf("GetPreOpSnapshots() = %d snapshots, want 1", len(snapshots))
+	}
+	got := snapshots[0]
+	if got.Operation != preopsnapshot.OperationPromoteBranch || got.Detail != "feature/promoted" {
+		t.Fatalf("snapshot = %+v, want promote-branch of feature/promoted", got)
+	}
+	// Taken before the checkout, so git still reports a detached HEAD.
+	if !strings.HasPrefix(got.GitStatus, "## HEAD (no branch)") {
+		t.Fatalf("snapshot git status = %q, want detached HEAD", got.GitStatus)
+	}
+	if got.Layout == "" || len(got.Panes) != 1 {
+		t.Fatalf("snapshot layout = %q, panes = %+v", got.Layout, got.Panes)
+	}
+}
+
+func TestGetPreOpSnapshotsValidation(t *testing.T) {
+	app := NewApp()
+	if _, err := app.GetPreOpSnapshots("  "); err == nil {
+		t.Fatal("GetPreOpSnapshots() expected session name validation error")
+	}
+	snapshots, err := app.GetPreOpSnapshots("unknown")
+	if err != nil || len(snapshots) != 0 {
+		t.Fatalf("GetPreOpSnapshots(unknown) = %+v, %v; want empty", snapshots, err)
+	}
+
+	app.preOpSnapshotService = nil
+	if _, err := app.GetPreOpSnapshots("session-a"); err == nil {
+		t.Fatal("GetPreOpSnapshots() expected unavailable service error")
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"strings"
+
+	"myT-x/internal/config"
+	"myT-x/internal/recentdirs"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// BrowseForDirectory opens the native folder picker at start and returns the
+// picked directory, or "" when the dialog was cancelled. An empty or missing
+// start falls back to default_session_dir, then the launch directory.
+// Wails-bound: called from the frontend.
+func (a *App) BrowseForDirectory(start string) (string, error) {
+	return a.openDirectoryDialog("Select Session Root Directory", a.browseStartDirectory(start))
+}
+
+// ListRecentDirectories returns the recent session directories, pinned first,
+// with their current existence and git repository state.
+// Wails-bound: called from the frontend.
+func (a *App) ListRecentDirectories() ([]recentdirs.Directory, error) {
+	return a.recentDirsService.List()
+}
+
+// SetRecentDirectoryPinned pins or unpins a recent directory.
+// Wails-bound: called from the frontend.
+func (a *App) SetRecentDirectoryPinned(path string, pinned bool) error {
+	return a.recentDirsService.SetPinned(path, pinned)
+}
+
+// RemoveRecentDirectory removes a directory from the recent list.
+// Wails-bound: called from the frontend.
+func (a *App) RemoveRecentDirectory(path string) error {
+	return a.recentDirsService.Remove(path)
+}
+
+// openDirectoryDialog shows the native folder picker starting in defaultDir.
+func (a *App) openDirectoryDialog(title, defaultDir string) (string, error) {
+	ctx := a.runtimeContext()
+	if ctx == nil {
+		return "", errors.New("app context is not ready")
+	}
+	dir, err := runtime.OpenDirectoryDialog(ctx, runtime.OpenDialogOptions{
+		Title:            title,
+		DefaultDirectory: defaultDir,
+	})
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(dir), nil
+}
+
+// browseStartDirectory returns the first existing directory among start,
+// default_session_dir, and the launch directory, or "" to let the OS choose.
+func (a *App) browseStartDirectory(start string) string {
+	for _, candidate := range []string{start, config.Read(a.configState, func(cfg *config.Config) string { return cfg.DefaultSessionDir }), a.launchDir} {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "" {
+			continue
+		}
+		if info, err := os.Stat(candidate); err == nil && info.IsDir() {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// recordRecentDirectory adds dir to the recent list after a session was
+// created there. Failures only cost the history entry.
+func (a *App) recordRecentDirectory(dir string) {
+	if err := a.recentDirsService.Record(dir); err != nil {
+		slog.Warn("[WARN-RECENTDIRS] failed to record recent directory", "dir", dir, "error", err)
+	}
+}
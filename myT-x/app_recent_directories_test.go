@@ -0,0 +1,66 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"myT-x/internal/config"
+	"myT-x/internal/statestore"
+)
+
+func TestBrowseForDirectoryRequiresRuntimeContext(t *testing.T) {
+	app := NewApp()
+	app.configState.Initialize(filepath.Join(t.TempDir(), "config.yaml"), config.DefaultConfig())
+	if _, err := app.BrowseForDirectory(""); err == nil {
+		t.Fatal("BrowseForDirectory() expected context-not-ready error")
+	}
+}
+
+func TestBrowseStartDirectoryFallbacks(t *testing.T) {
+	app := NewApp()
+	defaultDir := t.TempDir()
+	launchDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.DefaultSessionDir = defaultDir
+	app.configState.Initialize(filepath.Join(t.TempDir(), "config.yaml"), cfg)
+	app.launchDir = launchDir
+
+	start := t.TempDir()
+	if got := app.browseStartDirectory(start); got != start {
+		t.Fatalf("browseStartDirectory(existing) = %q, want %q", got, start)
+	}
+	if got := app.browseStartDirectory(filepath.Join(start, "missing")); got != defaultDir {
+		t.Fatalf("browseStartDirectory(missing) = %q, want default_session_dir %q", got, defaultDir)
+	}
+
+	cfg.DefaultSessionDir = ""
+	app.configState.Initialize(filepath.Join(t.TempDir(), "config.yaml"), cfg)
+	if got := app.browseStartDirectory(""); got != launchDir {
+		t.Fatalf("browseStartDirectory(\"\") = %q, want launch dir %q", got, launchDir)
+	}
+}
+
+func TestRecentDirectoriesRoundTrip(t *testing.T) {
+	app := NewApp()
+	app.stateStore = statestore.NewMemoryStore()
+	dir := t.TempDir()
+
+	app.recordRecentDirectory(dir)
+	if err := app.SetRecentDirectoryPinned(dir, true); err != nil {
+		t.Fatalf("SetRecentDirectoryPinned() error = %v", err)
+	}
+	dirs, err := app.ListRecentDirectories()
+	if err != nil {
+		t.Fatalf("ListRecentDirectories() error = %v", err)
+	}
+	if len(dirs) != 1 || dirs[0].Path != dir || !dirs[0].Pinned || !dirs[0].Exists {
+		t.Fatalf("ListRecentDirectories() = %+v, want pinned %s", dirs, dir)
+	}
+
+	if err := app.RemoveRecentDirectory(dir); err != nil {
+		t.Fatalf("RemoveRecentDirectory() error = %v", err)
+	}
+	if dirs, _ := app.ListRecentDirectories(); len(dirs) != 0 {
+		t.Fatalf("ListRecentDirectories() after remove = %+v, want empty", dirs)
+	}
+}
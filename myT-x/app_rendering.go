@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"myT-x/internal/config"
+	"myT-x/internal/rendering"
+	"myT-x/internal/workerutil"
+
+	"github.com/wailsapp/wails/v2/pkg/options/windows"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+var runtimeQuitFn = runtime.Quit
+
+// buildRenderingMonitorDeps constructs the dependency set for the rendering
+// monitor. The state file lives in the default config directory because it
+// is read before the window, and therefore the config, exists.
+func buildRenderingMonitorDeps(app *App) rendering.Deps {
+	return rendering.Deps{
+		StatePath: func() (string, error) {
+			return filepath.Join(filepath.Dir(config.DefaultPath()), rendering.StateFileName), nil
+		},
+		Emitter: newAppRuntimeEventEmitterAdapter(app),
+	}
+}
+
+// applyRenderingFallback records the launch and, when the fallback flag is
+// set, switches WebView2 to software rendering. RendererCodeIntegrity is
+// disabled too: security software injecting into the renderer is another
+// common cause of blank windows.
+func (a *App) applyRenderingFallback(opts *windows.Options) {
+	if !a.renderingMonitor.BeginLaunch() {
+		return
+	}
+	slog.Warn("[WARN-RENDER] launching WebView2 with software rendering")
+	opts.WebviewGpuIsDisabled = true
+	opts.WebviewDisableRendererCodeIntegrity = true
+}
+
+// startRenderingMonitor watches for GPU driver resets while the window
+// renders on the GPU.
+func (a *App) startRenderingMonitor(parent context.Context) {
+	ctx, cancel := context.WithCancel(parent)
+	a.renderingCancel = cancel
+	workerutil.RunWithPanicRecovery(ctx, "rendering-monitor", &a.bgWG, a.renderingMonitor.Run, a.defaultRecoveryOptions())
+}
+
+// GetRenderingDiagnostics reports the rendering mode, the persisted
+// software rendering flag, and recent GPU driver resets.
+// Wails-bound: called from the frontend.
+func (a *App) GetRenderingDiagnostics() rendering.Diagnostics {
+	ctx := a.runtimeContext()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return a.renderingMonitor.Diagnostics(ctx)
+}
+
+// SetSoftwareRendering sets whether the next launch uses software rendering.
+// Wails-bound: called from the frontend.
+func (a *App) SetSoftwareRendering(enabled bool) error {
+	return a.renderingMonitor.SetSoftwareRendering(enabled)
+}
+
+// RelaunchApp quits and starts the app again, e.g. to apply the software
+// rendering flag. The new process is started by main once this one has
+// released the single-instance lock.
+// Wails-bound: called from the frontend.
+func (a *App) RelaunchApp() error {
+	ctx := a.runtimeContext()
+	if ctx == nil {
+		return errRuntimeContextNil
+	}
+	a.relaunchRequested.Store(true)
+	runtimeQuitFn(ctx)
+	return nil
+}
+
+// relaunchProcess starts a new instance of the executable without the
+// original arguments, so a deep link is not opened twice.
+func relaunchProcess() error {
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("resolve executable: %w", err)
+	}
+	cmd := exec.Command(exe)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("start %s: %w", filepath.Base(exe), err)
+	}
+	if err := cmd.Process.Release(); err != nil {
+		slog.Debug("[DEBUG-RENDER] failed to release relaunched process handle", "error", err)
+	}
+	return nil
+}
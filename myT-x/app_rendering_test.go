@@ -0,0 +1,48 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"myT-x/internal/rendering"
+
+	"github.com/wailsapp/wails/v2/pkg/options/windows"
+)
+
+func TestApplyRenderingFallback(t *testing.T) {
+	path := filepath.Join(t.TempDir(), rendering.StateFileName)
+	newMonitor := func() *rendering.Monitor {
+		return rendering.NewMonitor(rendering.Deps{StatePath: func() (string, error) { return path, nil }})
+	}
+	app := NewApp()
+
+	app.renderingMonitor = newMonitor()
+	opts := &windows.Options{}
+	app.applyRenderingFallback(opts)
+	if opts.WebviewGpuIsDisabled || opts.WebviewDisableRendererCodeIntegrity {
+		t.Fatalf("options = %+v, want GPU rendering without the fallback flag", opts)
+	}
+	if err := app.SetSoftwareRendering(true); err != nil {
+		t.Fatalf("SetSoftwareRendering() error = %v", err)
+	}
+
+	app.renderingMonitor = newMonitor()
+	opts = &windows.Options{}
+	app.applyRenderingFallback(opts)
+	if !opts.WebviewGpuIsDisabled || !opts.WebviewDisableRendererCodeIntegrity {
+		t.Fatalf("options = %+v, want software rendering", opts)
+	}
+	if diag := app.GetRenderingDiagnostics(); !diag.SoftwareRendering || diag.Reason != rendering.ReasonUser {
+		t.Fatalf("GetRenderingDiagnostics() = %+v", diag)
+	}
+}
+
+func TestRelaunchAppRequiresRuntimeContext(t *testing.T) {
+	app := NewApp()
+	if err := app.RelaunchApp(); err == nil {
+		t.Fatal("RelaunchApp() without a runtime context should fail")
+	}
+	if app.relaunchRequested.Load() {
+		t.Fatal("relaunch requested without a runtime context")
+	}
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"errors"
+
+	"myT-x/internal/repoconfig"
+)
+
+// GetDirectoryTrust returns the trust level of dir and the .mytx.yaml it
+// contains, so the frontend can show what a trust decision would allow.
+// Wails-bound: called from the new-session dialog and the trust prompt.
+func (a *App) GetDirectoryTrust(dir string) (repoconfig.Status, error) {
+	if a.repoConfigService == nil {
+		return repoconfig.Status{}, errors.New("repo config service is unavailable")
+	}
+	return a.repoConfigService.Status(dir)
+}
+
+// SetDirectoryTrust stores the trust level of dir: "trusted" lets its
+// .mytx.yaml setup_scripts and startup_command run, "untrusted" ignores them
+// without prompting again, and "" forgets the decision. Trust applies to dir
+// and every directory below it.
+// Wails-bound: called from the trust prompt.
+func (a *App) SetDirectoryTrust(dir string, level string) error {
+	if a.repoConfigService == nil {
+		return errors.New("repo config service is unavailable")
+	}
+	return a.repoConfigService.SetTrust(dir, repoconfig.Level(level))
+}
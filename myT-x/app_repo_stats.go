@@ -0,0 +1,18 @@
+package main
+
+import (
+	"errors"
+
+	"myT-x/internal/repostats"
+)
+
+// GetRepoStats returns the language breakdown (files, lines, bytes) of the
+// repository or directory at repoPath. Results are cached and refreshed
+// incrementally from a file watcher, so repeated calls are cheap.
+// Wails-bound: called from the new-session and worktree dialogs.
+func (a *App) GetRepoStats(repoPath string) (repostats.RepoStats, error) {
+	if a.repoStatsService == nil {
+		return repostats.RepoStats{}, errors.New("repository stats service is unavailable")
+	}
+	return a.repoStatsService.Stats(repoPath)
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGetRepoStatsCountsLanguages(t *testing.T) {
+	app := NewApp()
+	t.Cleanup(app.repoStatsService.Close)
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := app.GetRepoStats(dir)
+	if err != nil {
+		t.Fatalf("GetRepoStats() error = %v", err)
+	}
+	if stats.TotalFiles != 1 || len(stats.Languages) != 1 || stats.Languages[0].Language != "Go" {
+		t.Fatalf("GetRepoStats() = %+v, want one Go file", stats)
+	}
+}
+
+func TestGetRepoStatsRequiresPath(t *testing.T) {
+	app := NewApp()
+	if _, err := app.GetRepoStats(" "); err == nil {
+		t.Fatal("GetRepoStats() error = nil, want error for empty path")
+	}
+}
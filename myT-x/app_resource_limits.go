@@ -0,0 +1,98 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"myT-x/internal/config"
+	"myT-x/internal/resourcelimit"
+	"myT-x/internal/tmux"
+)
+
+// sessionResourceLimits returns the configured limits of sessionName.
+func (a *App) sessionResourceLimits(sessionName string) resourcelimit.Limits {
+	limits := config.Read(a.configState, func(cfg *config.Config) config.ResourceLimits {
+		return cfg.ResourceLimits.ForSession(sessionName)
+	})
+	return resourcelimit.Limits{MemoryMB: limits.MemoryMB, CPUPercent: limits.CPUPercent}
+}
+
+// applyPaneResourceLimits places a newly started pane shell under the limits
+// of its session. Wired as tmux.RouterOptions.OnPaneStarted.
+func (a *App) applyPaneResourceLimits(sessionName string, paneID string, pid int) {
+	if a.resourceLimits == nil {
+		return
+	}
+	if err := a.resourceLimits.Assign(sessionName, pid, a.sessionResourceLimits(sessionName)); err != nil {
+		slog.Warn("[WARN-RESOURCE-LIMIT] failed to limit pane process",
+			"session", sessionName, "paneId", paneID, "pid", pid, "error", err)
+	}
+}
+
+// applyRuntimeResourceLimitsUpdate applies changed resource_limits to every
+// live pane, creating jobs for sessions that became limited and lifting the
+// limits of sessions that no longer are.
+func (a *App) applyRuntimeResourceLimitsUpdate() {
+	if a.resourceLimits == nil {
+		return
+	}
+	limitsBySession := map[string]resourcelimit.Limits{}
+	a.forEachLivePane(func(sessionName string, pane tmux.PanePIDInfo) {
+		if pane.PID <= 0 {
+			return
+		}
+		limits, ok := limitsBySession[sessionName]
+		if !ok {
+			limits = a.sessionResourceLimits(sessionName)
+			limitsBySession[sessionName] = limits
+		}
+		if err := a.resourceLimits.Assign(sessionName, pane.PID, limits); err != nil {
+			slog.Warn("[WARN-RESOURCE-LIMIT] failed to apply resource limits",
+				"session", sessionName, "paneId", pane.PaneID, "pid", pane.PID, "error", err)
+		}
+	})
+}
+
+// GetSessionResourceUsage returns the live CPU and memory usage of each pane
+// process tree of the session, with the limits applied to it. CPU usage is a
+// share of the whole machine since the previous call; the first call for a
+// pane measures over a short window.
+// Wails-bound: called from the frontend.
+func (a *App) GetSessionResourceUsage(sessionName string) (resourcelimit.SessionUsage, error) {
+	sessionName = strings.TrimSpace(sessionName)
+	if sessionName == "" {
+		return resourcelimit.SessionUsage{}, errors.New("session name is required")
+	}
+	sessions, err := a.requireSessions()
+	if err != nil {
+		return resourcelimit.SessionUsage{}, err
+	}
+	if _, err := sessions.GetSessionPanePIDs(sessionName); err != nil {
+		return resourcelimit.SessionUsage{}, err
+	}
+
+	// Every live pane is sampled so the sampler keeps the previous CPU
+	// readings of other sessions' panes.
+	var roots []resourcelimit.PaneRoot
+	a.forEachLivePane(func(name string, pane tmux.PanePIDInfo) {
+		roots = append(roots, resourcelimit.PaneRoot{SessionName: name, PaneID: pane.PaneID, PID: pane.PID})
+	})
+	usage, err := a.resourceSampler.Sample(roots)
+	if err != nil {
+		return resourcelimit.SessionUsage{}, fmt.Errorf("sample resource usage: %w", err)
+	}
+
+	result := resourcelimit.SessionUsage{
+		SessionName: sessionName,
+		Limits:      a.resourceLimits.Limits(sessionName),
+		Panes:       []resourcelimit.PaneUsage{},
+	}
+	for i, root := range roots {
+		if root.SessionName == sessionName {
+			result.Panes = append(result.Panes, usage[i])
+		}
+	}
+	return result, nil
+}
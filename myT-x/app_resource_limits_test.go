@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"myT-x/internal/config"
+	"myT-x/internal/resourcelimit"
+	"myT-x/internal/tmux"
+)
+
+func TestGetSessionResourceUsageRequiresExistingSession(t *testing.T) {
+	app := NewApp()
+	app.sessions = tmux.NewSessionManager()
+	if _, err := app.GetSessionResourceUsage("  "); err == nil {
+		t.Fatal("GetSessionResourceUsage() error = nil, want error for empty name")
+	}
+	if _, err := app.GetSessionResourceUsage("missing"); err == nil {
+		t.Fatal("GetSessionResourceUsage(missing) error = nil")
+	}
+}
+
+func TestApplyPaneResourceLimitsFollowsConfig(t *testing.T) {
+	origEmit := runtimeEventsEmitFn
+	t.Cleanup(func() { runtimeEventsEmitFn = origEmit })
+	runtimeEventsEmitFn = func(context.Context, string, ...any) {}
+
+	app := NewApp()
+	t.Cleanup(app.resourceLimits.Close)
+	app.setRuntimeContext(context.Background())
+	app.sessions = tmux.NewSessionManager()
+	if _, _, err := app.sessions.CreateSession("agent", "0", 120, 40); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	cfg := config.DefaultConfig()
+	cfg.ResourceLimits = &config.ResourceLimitsConfig{
+		MemoryMB: 4096,
+		Sessions: map[string]config.ResourceLimits{"agent": {MemoryMB: 1024, CPUPercent: 50}},
+	}
+	app.configState.Initialize(newConfigPathForAPITest(t, "config.yaml"), cfg)
+
+	app.applyPaneResourceLimits("agent", "%0", 4242)
+	app.applyPaneResourceLimits("other", "%9", 4243)
+	want := resourcelimit.Limits{MemoryMB: 1024, CPUPercent: 50}
+	if got := app.resourceLimits.Limits("agent"); got != want {
+		t.Fatalf("Limits(agent) = %+v, want %+v", got, want)
+	}
+	if got := app.resourceLimits.Limits("other"); got != (resourcelimit.Limits{MemoryMB: 4096}) {
+		t.Fatalf("Limits(other) = %+v, want the defaults", got)
+	}
+
+	usage, err := app.GetSessionResourceUsage("agent")
+	if err != nil {
+		t.Fatalf("GetSessionResourceUsage() error = %v", err)
+	}
+	if usage.SessionName != "agent" || usage.Limits != want || len(usage.Panes) != 1 {
+		t.Fatalf("GetSessionResourceUsage() = %+v", usage)
+	}
+
+	if _, err := app.configState.Update(func(cfg *config.Config) {
+		cfg.ResourceLimits = nil
+	}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	app.applyPaneResourceLimits("agent", "%0", 4242)
+	if got := app.resourceLimits.Limits("agent"); !got.IsZero() {
+		t.Fatalf("Limits(agent) after removing the config = %+v, want lifted", got)
+	}
+}
@@ -219,6 +219,21 @@ func (sk sendKeysIO) schedulerSendMessage(router *tmux.CommandRouter, paneID, me
 	return nil
 }
 
+// sendKey sends one named key (e.g. "C-c") to a pane without selecting it.
+func (sk sendKeysIO) sendKey(router *tmux.CommandRouter, paneID, key string) error {
+	resp := sk.executeRequest(router, ipc.TmuxRequest{
+		Command: "send-keys",
+		Flags: map[string]any{
+			"-t": paneID,
+		},
+		Args: []string{key},
+	})
+	if resp.ExitCode != 0 {
+		return fmt.Errorf("send-keys %s failed: %s", key, strings.TrimSpace(resp.Stderr))
+	}
+	return nil
+}
+
 // sendPasteEnd sends the bracketed paste end sequence (best-effort).
 // Failure is logged but not propagated to avoid masking the original error.
 func (sk sendKeysIO) sendPasteEnd(router *tmux.CommandRouter, paneID string) {
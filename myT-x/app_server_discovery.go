@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"myT-x/internal/ipc"
+	"myT-x/internal/workerutil"
+)
+
+// pipeTakeoverPollInterval is how often the pipe of a foreign server is
+// probed until that server exits. A variable so tests can shorten it.
+var pipeTakeoverPollInterval = 5 * time.Second
+
+// discoverLiveServer probes pipeName before this instance starts its own
+// pipe server. A server that still answers there after a host crash is
+// reported and true is returned, so startup does not try to take over a
+// pipe another process owns.
+//
+// The sessions of such a server cannot be adopted: their pane processes are
+// attached to pseudo consoles owned by that process, and pane output only
+// reaches the window of the process that owns them. startPipeTakeover
+// starts this instance's own server once that process has exited.
+func (a *App) discoverLiveServer(ctx context.Context, pipeName string) bool {
+	info, err := probeServerFn(pipeName)
+	if errors.Is(err, ipc.ErrNoServer) {
+		return false
+	}
+	if err != nil {
+		// An unresponsive or older server; starting our own server reports
+		// the conflict if the pipe is really taken.
+		runtimeLogger.Warningf(ctx, "live server probe failed on %s: %v", pipeName, err)
+		return false
+	}
+	if info.PID == os.Getpid() {
+		return false
+	}
+	runtimeLogger.Warningf(ctx, "live server pid %d owns %s with %d session(s)", info.PID, pipeName, len(info.Sessions))
+	a.addPendingConfigLoadWarning(fmt.Sprintf(
+		"Another myT-x server (pid %d) with %d session(s) and %d pane(s) still owns the tmux IPC pipe. "+
+			"Its panes belong to that process and cannot be reopened in this window; tmux commands go to it until it exits, "+
+			"then this window takes over the pipe.",
+		info.PID, len(info.Sessions), info.PaneCount(),
+	))
+	return true
+}
+
+// startPipeTakeover polls pipeName in the background until the foreign
+// server that owns it is gone, then starts this instance's pipe server.
+// A takeover already running is replaced.
+func (a *App) startPipeTakeover(parent context.Context, pipeName string) {
+	ctx, cancel := context.WithCancel(parent)
+	a.pipeServerMu.Lock()
+	if a.pipeTakeoverCancel != nil {
+		a.pipeTakeoverCancel()
+	}
+	a.pipeTakeoverCancel = cancel
+	a.pipeServerMu.Unlock()
+	workerutil.RunWithPanicRecovery(ctx, "pipe-takeover", &a.bgWG, func(ctx context.Context) {
+		a.waitForPipeTakeover(ctx, pipeName)
+	}, a.defaultRecoveryOptions())
+}
+
+// waitForPipeTakeover returns once this instance serves pipeName or ctx is
+// done. A start that loses a race for the freed pipe keeps polling.
+func (a *App) waitForPipeTakeover(ctx context.Context, pipeName string) {
+	ticker := time.NewTicker(pipeTakeoverPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if a.currentPipeServer() != nil {
+			return
+		}
+		if _, err := probeServerFn(pipeName); !errors.Is(err, ipc.ErrNoServer) {
+			continue
+		}
+		if err := a.listenPipeServer(ctx, pipeName); err != nil {
+			continue
+		}
+		runtimeLogger.Infof(ctx, "foreign server left %s; this instance now serves tmux commands", pipeName)
+		return
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"myT-x/internal/ipc"
+)
+
+func TestDiscoverLiveServer(t *testing.T) {
+	t.Cleanup(func() {
+		probeServerFn = ipc.ProbeServer
+		runtimeLogger = wailsRuntimeLogger{}
+	})
+	runtimeLogger = lifecycleTestLogger{}
+
+	tests := []struct {
+		name        string
+		info        ipc.ServerInfo
+		err         error
+		wantLive    bool
+		wantWarning string
+	}{
+		{name: "no server", err: ipc.ErrNoServer},
+		{name: "probe failure", err: errors.New("timeout")},
+		{name: "own server", info: ipc.ServerInfo{PID: os.Getpid()}},
+		{
+			name: "foreign server",
+			info: ipc.ServerInfo{PID: os.Getpid() + 1, Sessions: []ipc.ServerSession{
+				{Name: "api", Panes: []string{"%1", "%2"}},
+			}},
+			wantLive:    true,
+			wantWarning: "1 session(s) and 2 pane(s)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			probeServerFn = func(string) (ipc.ServerInfo, error) { return tt.info, tt.err }
+			app := NewApp()
+			if got := app.discoverLiveServer(context.Background(), `\\.\pipe\myT-x-probe-test`); got != tt.wantLive {
+				t.Fatalf("discoverLiveServer() = %v, want %v", got, tt.wantLive)
+			}
+			warning := app.consumePendingConfigLoadWarning()
+			if tt.wantWarning == "" && warning != "" {
+				t.Fatalf("warning = %q, want none", warning)
+			}
+			if !strings.Contains(warning, tt.wantWarning) {
+				t.Fatalf("warning = %q, want it to contain %q", warning, tt.wantWarning)
+			}
+		})
+	}
+}
+
+func TestWaitForPipeTakeoverStartsServerOnceForeignServerExits(t *testing.T) {
+	originalInterval := pipeTakeoverPollInterval
+	t.Cleanup(func() {
+		probeServerFn = ipc.ProbeServer
+		newPipeServerFn = ipc.NewPipeServer
+		pipeTakeoverPollInterval = originalInterval
+		runtimeLogger = wailsRuntimeLogger{}
+	})
+	runtimeLogger = lifecycleTestLogger{}
+	pipeTakeoverPollInterval = time.Millisecond
+
+	var probes atomic.Int32
+	probeServerFn = func(string) (ipc.ServerInfo, error) {
+		// The foreign server answers twice, then exits.
+		if probes.Add(1) <= 2 {
+			return ipc.ServerInfo{PID: os.Getpid() + 1}, nil
+		}
+		return ipc.ServerInfo{}, ipc.ErrNoServer
+	}
+	newPipeServerFn = func(pipeName string, _ ipc.CommandExecutor) *ipc.PipeServer {
+		return ipc.NewPipeServer(pipeName, nil)
+	}
+
+	app := NewApp()
+	pipeName := `\\.\pipe\myT-x-takeover-test`
+	if !app.discoverLiveServer(context.Background(), pipeName) {
+		t.Fatal("discoverLiveServer() = false, want the foreign server reported")
+	}
+	if app.currentPipeServer() != nil {
+		t.Fatal("pipe server started while the foreign server owns the pipe")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	app.waitForPipeTakeover(ctx, pipeName)
+	server := app.currentPipeServer()
+	if server == nil {
+		t.Fatalf("pipe server not started after the foreign server exited (probes = %d)", probes.Load())
+	}
+	t.Cleanup(func() { _ = server.Stop() })
+	if server.PipeName() != pipeName {
+		t.Fatalf("PipeName() = %q, want %q", server.PipeName(), pipeName)
+	}
+	if probes.Load() < 3 {
+		t.Fatalf("probes = %d, want polling until the pipe is free", probes.Load())
+	}
+}
@@ -2,24 +2,30 @@ package main
 
 import (
 	"errors"
+	"fmt"
 	"log/slog"
+	"os"
 	"strings"
 
+	"myT-x/internal/config"
 	"myT-x/internal/install"
 	"myT-x/internal/session"
 	"myT-x/internal/tmux"
-
-	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
-// CreateSessionOptions holds the boolean options for session creation APIs.
+// CreateSessionOptions holds the options for session creation APIs.
 // This struct replaces consecutive bool parameters (enableAgentTeam, useClaudeEnv,
 // usePaneEnv) to eliminate argument-ordering mistakes at call sites.
 type CreateSessionOptions struct {
-	EnableAgentTeam     bool `json:"enable_agent_team"`      // set Agent Teams env vars on initial pane
-	UseClaudeEnv        bool `json:"use_claude_env"`         // apply claude_env config to panes
-	UsePaneEnv          bool `json:"use_pane_env"`           // apply pane_env config to additional panes
-	UseSessionPaneScope bool `json:"use_session_pane_scope"` // set MYTX_SESSION on panes + scope list-panes
+	EnableAgentTeam     bool   `json:"enable_agent_team"`               // set Agent Teams env vars on initial pane
+	UseClaudeEnv        bool   `json:"use_claude_env"`                  // apply claude_env config to panes
+	UsePaneEnv          bool   `json:"use_pane_env"`                    // apply pane_env config to additional panes
+	UseSessionPaneScope bool   `json:"use_session_pane_scope"`          // set MYTX_SESSION on panes + scope list-panes
+	StartupCommand      string `json:"startup_command,omitempty"`       // run in the initial pane; empty = trusted .mytx.yaml, then config startup_commands.session
+	RemainOnExit        bool   `json:"remain_on_exit,omitempty"`        // keep the initial pane open after the startup command exits
+	IsolateShellHistory bool   `json:"isolate_shell_history,omitempty"` // keep shell history in the session's state directory
+	Ephemeral           bool   `json:"ephemeral,omitempty"`             // remove the session after the configured TTL or on app exit; CreateSession only
+	Detached            bool   `json:"detached,omitempty"`              // run headless without activating until attached; CreateSession only
 }
 
 // toSessionOpts maps the Wails-bound CreateSessionOptions to the session
@@ -30,6 +36,10 @@ func (o CreateSessionOptions) toSessionOpts() session.CreateSessionOptions {
 		UseClaudeEnv:        o.UseClaudeEnv,
 		UsePaneEnv:          o.UsePaneEnv,
 		UseSessionPaneScope: o.UseSessionPaneScope,
+		StartupCommand:      o.StartupCommand,
+		RemainOnExit:        o.RemainOnExit,
+		IsolateShellHistory: o.IsolateShellHistory,
+		Detached:            o.Detached,
 	}
 }
 
@@ -51,9 +61,82 @@ func (a *App) QuickStartSession() (tmux.SessionSnapshot, error) {
 // is appended automatically (same deduplication as CreateSessionWithWorktree).
 // When opts.EnableAgentTeam is true, Agent Teams environment variables are set on the
 // session's initial pane so that Claude Code creates team member panes automatically.
+// When opts.Ephemeral is true, the session is removed after the configured TTL
+// or on app exit; without a path it runs in a new temp directory.
 // Wails-bound: called from the frontend.
 func (a *App) CreateSession(rootPath string, sessionName string, opts CreateSessionOptions) (tmux.SessionSnapshot, error) {
-	return a.sessionService.CreateSession(rootPath, sessionName, opts.toSessionOpts())
+	if opts.Ephemeral {
+		return a.createEphemeralSession(rootPath, sessionName, opts)
+	}
+	snapshot, err := a.sessionService.CreateSession(rootPath, sessionName, opts.toSessionOpts())
+	if err == nil {
+		a.recordRecentDirectory(rootPath)
+	}
+	return snapshot, err
+}
+
+// createEphemeralSession creates a session that is removed after the
+// configured TTL or on app exit. Without rootPath it runs in a new temp
+// directory that is removed with it. The directory is not recorded as recent.
+func (a *App) createEphemeralSession(rootPath string, sessionName string, opts CreateSessionOptions) (tmux.SessionSnapshot, error) {
+	workDir, tempDir := "", false
+	if strings.TrimSpace(rootPath) == "" {
+		dir, err := createEphemeralTempDir()
+		if err != nil {
+			return tmux.SessionSnapshot{}, err
+		}
+		rootPath, workDir, tempDir = dir, dir, true
+	}
+	snapshot, err := a.sessionService.CreateSession(rootPath, sessionName, opts.toSessionOpts())
+	if err != nil {
+		if tempDir {
+			_ = os.RemoveAll(workDir)
+		}
+		return snapshot, err
+	}
+	if err := a.registerEphemeralSession(snapshot, workDir, tempDir); err != nil {
+		return tmux.SessionSnapshot{}, err
+	}
+	return snapshot, nil
+}
+
+// CreateSessionFromTemplate creates a session from the session template
+// (config session_templates) with the given name: its initial pane runs the
+// template command, its extra panes are split off with their own commands and
+// env, and its layout is applied. dir overrides the template dir; when both
+// are empty, default_session_dir and then the launch directory are used.
+// Wails-bound: called from the frontend.
+func (a *App) CreateSessionFromTemplate(name string, dir string) (tmux.SessionSnapshot, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return tmux.SessionSnapshot{}, errors.New("template name is required")
+	}
+	for _, template := range a.configState.Snapshot().SessionTemplates {
+		if template.Name == name {
+			return a.createSessionFromTemplate(template, dir)
+		}
+	}
+	return tmux.SessionSnapshot{}, fmt.Errorf("session template not found: %s", name)
+}
+
+// createSessionFromTemplate is shared by CreateSessionFromTemplate and the
+// session bring-up service.
+func (a *App) createSessionFromTemplate(template config.SessionTemplate, dir string) (tmux.SessionSnapshot, error) {
+	dir = strings.TrimSpace(dir)
+	if dir == "" {
+		dir = template.Dir
+	}
+	if dir == "" {
+		dir = config.Read(a.configState, func(cfg *config.Config) string { return cfg.DefaultSessionDir })
+	}
+	if dir == "" {
+		dir = a.launchDir
+	}
+	snapshot, err := a.sessionService.CreateSession(dir, template.Name, session.TemplateCreateOptions(template))
+	if err == nil {
+		a.recordRecentDirectory(dir)
+	}
+	return snapshot, err
 }
 
 // RenameSession renames an existing session.
@@ -83,10 +166,25 @@ func (a *App) ListSessions() []tmux.SessionSnapshot {
 	return a.sessionService.ListSessions()
 }
 
+// QuerySessions returns one page of the sessions matching filter, sorted by
+// sort. API clients use it instead of ListSessions for large session counts.
+// Wails-bound: called from the frontend.
+func (a *App) QuerySessions(filter SessionQueryFilter, sort SessionQuerySort, page SessionQueryPage) (SessionQueryResult, error) {
+	return a.sessionService.QuerySessions(filter, sort, page)
+}
+
+// NegotiateSnapshotEncoding enables the snapshot wire encodings the frontend
+// accepts ("compact-delta", "gzip") and returns the ones now in effect.
+// Wails-bound: called from the frontend.
+func (a *App) NegotiateSnapshotEncoding(accepted []string) []string {
+	return a.snapshotService.NegotiateEncoding(accepted)
+}
+
 // SetActiveSession sets current active session for status line and UI.
 // Wails-bound: called from the frontend.
 func (a *App) SetActiveSession(sessionName string) {
 	a.sessionService.SetActive(sessionName)
+	a.launchBaseRefreshOnFocus(sessionName)
 }
 
 // GetActiveSession returns active session name.
@@ -141,17 +239,7 @@ func (a *App) OpenDirectoryInExplorer(sessionName string) error {
 // PickSessionDirectory opens a directory picker for new session root.
 // Wails-bound: called from the frontend.
 func (a *App) PickSessionDirectory() (string, error) {
-	ctx := a.runtimeContext()
-	if ctx == nil {
-		return "", errors.New("app context is not ready")
-	}
-	dir, err := runtime.OpenDirectoryDialog(ctx, runtime.OpenDialogOptions{
-		Title: "Select Session Root Directory",
-	})
-	if err != nil {
-		return "", err
-	}
-	return strings.TrimSpace(dir), nil
+	return a.openDirectoryDialog("Select Session Root Directory", "")
 }
 
 // DetachSession currently keeps process alive and only emits UI event.
@@ -184,3 +272,18 @@ func (a *App) InstallTmuxShim() (install.ShimInstallResult, error) {
 	a.emitRuntimeEvent("tmux:shim-installed", result)
 	return result, nil
 }
+
+// RunPathDoctor diagnoses why tmux commands in panes might not reach the
+// shim: other tmux executables ahead of it on PATH (Git Bash, scoop, app
+// execution aliases), a missing PATH registration, tmux aliases that
+// redirect to WSL, and a WSL pane shell.
+// Wails-bound: called from the frontend.
+func (a *App) RunPathDoctor() (install.PathDoctorReport, error) {
+	report, err := runPathDoctorFn(config.Read(a.configState, func(cfg *config.Config) string { return cfg.Shell }))
+	if err != nil {
+		return install.PathDoctorReport{}, err
+	}
+	slog.Info("[shim] PATH doctor finished",
+		"candidates", len(report.Candidates), "findings", len(report.Findings))
+	return report, nil
+}
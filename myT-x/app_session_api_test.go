@@ -80,7 +80,7 @@ func TestCreateSessionOptionsFieldCountGuard(t *testing.T) {
 	//   - SessionEnvOptions in internal/worktree/types.go
 	//   - the mapping in CreateSessionWithExistingWorktree / applySessionEnvFlags
 	//   - frontend models.ts CreateSessionOptions class
-	const expectedFieldCount = 4
+	const expectedFieldCount = 9
 	if got := reflect.TypeFor[CreateSessionOptions]().NumField(); got != expectedFieldCount {
 		t.Fatalf("CreateSessionOptions field count = %d, want %d; "+
 			"update WorktreeSessionOptions mapping, SessionEnvOptions, applySessionEnvFlags callers, and frontend models.ts",
@@ -92,7 +92,9 @@ func TestSessionEnvOptionsAlignedWithCreateSessionOptions(t *testing.T) {
 	// Guard against field divergence between CreateSessionOptions (main) and
 	// SessionEnvOptions (internal/worktree). The manual mapping in
 	// CreateSessionWithExistingWorktree must cover all SessionEnvOptions fields.
-	want := reflect.TypeFor[CreateSessionOptions]().NumField() // 4
+	// Ephemeral and Detached apply to CreateSession only and have no
+	// SessionEnvOptions counterpart.
+	want := reflect.TypeFor[CreateSessionOptions]().NumField() - 2 // 7
 	got := reflect.TypeFor[worktree.SessionEnvOptions]().NumField()
 	if got != want {
 		t.Fatalf("SessionEnvOptions field count (%d) != CreateSessionOptions (%d); "+
@@ -1029,7 +1031,7 @@ func newCopyTestService(t *testing.T) *worktree.Service {
 		GetConfigSnapshot:          func() config.Config { return config.DefaultConfig() },
 		RuntimeContext:             func() context.Context { return context.Background() },
 		FindAvailableSessionName:   func(name string) string { return name },
-		CreateSession:              func(_, _ string, _, _, _ bool) (string, error) { return "", nil },
+		CreateSession:              func(_, _ string, _, _, _, _ bool) (string, error) { return "", nil },
 		ApplySessionEnvFlags:       func(_ *tmux.SessionManager, _ string, _, _, _ bool) {},
 		ActivateCreatedSession:     func(_ string) (tmux.SessionSnapshot, error) { return tmux.SessionSnapshot{}, nil },
 		RollbackCreatedSession:     func(_ string) error { return nil },
@@ -1076,7 +1078,7 @@ func TestCopyConfigPathTraversalDeep(t *testing.T) {
 				}
 			}
 
-			failures := svc.CopyConfigFilesToWorktree(repoDir, wtDir, []string{tt.file})
+			failures := svc.CopyConfigFilesToWorktree(repoDir, wtDir, []string{tt.file}, "")
 
 			if tt.wantSkip {
 				// Should be skipped, not in failures (traversal entries are skipped silently).
@@ -1274,6 +1276,75 @@ func TestQuickStartSession(t *testing.T) {
 	})
 }
 
+func TestCreateSessionFromTemplate(t *testing.T) {
+	t.Run("creates panes and env from the template", func(t *testing.T) {
+		app := NewApp()
+		app.sessions = tmux.NewSessionManager()
+		app.router = tmux.NewCommandRouter(app.sessions, nil, tmux.RouterOptions{})
+
+		var newSessionEnv, splitEnv map[string]string
+		stubExecuteRouterRequest(t, app, func(_ *tmux.CommandRouter, req ipc.TmuxRequest) ipc.TmuxResponse {
+			switch req.Command {
+			case "new-session":
+				newSessionEnv = req.Env
+				sessionName, _ := req.Flags["-s"].(string)
+				if _, _, err := app.sessions.CreateSession(sessionName, "0", 120, 40); err != nil {
+					return ipc.TmuxResponse{ExitCode: 1, Stderr: err.Error()}
+				}
+				return ipc.TmuxResponse{Stdout: sessionName}
+			case "split-window":
+				splitEnv = req.Env
+				pane, err := app.sessions.SplitPane(0, tmux.SplitHorizontal)
+				if err != nil {
+					return ipc.TmuxResponse{ExitCode: 1, Stderr: err.Error()}
+				}
+				return ipc.TmuxResponse{Stdout: pane.IDString()}
+			default:
+				return ipc.TmuxResponse{ExitCode: 1, Stderr: "unexpected command"}
+			}
+		})
+
+		rootDir := filepath.Clean(t.TempDir())
+		cfg := config.DefaultConfig()
+		cfg.SessionTemplates = []config.SessionTemplate{{
+			Name:   "dev",
+			Env:    map[string]string{"APP_ENV": "dev"},
+			Panes:  []config.SessionTemplatePane{{Env: map[string]string{"PORT": "3000"}}},
+			Layout: "even-horizontal",
+		}}
+		app.configState.SetSnapshot(cfg)
+
+		snapshot, err := app.CreateSessionFromTemplate(" dev ", rootDir)
+		if err != nil {
+			t.Fatalf("CreateSessionFromTemplate() error = %v", err)
+		}
+		if snapshot.Name != "dev" {
+			t.Fatalf("session name = %q, want dev", snapshot.Name)
+		}
+		if newSessionEnv["APP_ENV"] != "dev" {
+			t.Fatalf("new-session env = %v, want APP_ENV=dev", newSessionEnv)
+		}
+		if splitEnv["APP_ENV"] != "dev" || splitEnv["PORT"] != "3000" {
+			t.Fatalf("split-window env = %v, want APP_ENV and PORT", splitEnv)
+		}
+		if got := len(snapshot.Windows[0].Panes); got != 2 {
+			t.Fatalf("pane count = %d, want 2", got)
+		}
+	})
+
+	t.Run("rejects unknown and empty names", func(t *testing.T) {
+		app := NewApp()
+		app.configState.SetSnapshot(config.DefaultConfig())
+		if _, err := app.CreateSessionFromTemplate("  ", ""); err == nil {
+			t.Fatal("CreateSessionFromTemplate() with empty name should fail")
+		}
+		_, err := app.CreateSessionFromTemplate("missing", "")
+		if err == nil || !strings.Contains(err.Error(), "session template not found") {
+			t.Fatalf("CreateSessionFromTemplate() error = %v, want not found", err)
+		}
+	})
+}
+
 func TestFindSessionByRootPathSkipsWorktreeSessions(t *testing.T) {
 	app := NewApp()
 	app.sessions = tmux.NewSessionManager()
@@ -2736,6 +2807,36 @@ func TestInstallTmuxShimDoesNotEmitOnError(t *testing.T) {
 	}
 }
 
+func TestRunPathDoctorPassesConfiguredShell(t *testing.T) {
+	origDoctor := runPathDoctorFn
+	t.Cleanup(func() { runPathDoctorFn = origDoctor })
+
+	app := NewApp()
+	cfg := config.DefaultConfig()
+	cfg.Shell = "wsl.exe"
+	app.configState.SetSnapshot(cfg)
+
+	var gotShell string
+	runPathDoctorFn = func(shell string) (install.PathDoctorReport, error) {
+		gotShell = shell
+		return install.PathDoctorReport{ShimInstalled: true}, nil
+	}
+	report, err := app.RunPathDoctor()
+	if err != nil {
+		t.Fatalf("RunPathDoctor() error = %v", err)
+	}
+	if gotShell != "wsl.exe" || !report.ShimInstalled {
+		t.Fatalf("shell = %q, report = %+v", gotShell, report)
+	}
+
+	runPathDoctorFn = func(string) (install.PathDoctorReport, error) {
+		return install.PathDoctorReport{}, errors.New("LOCALAPPDATA is not set")
+	}
+	if _, err := app.RunPathDoctor(); err == nil {
+		t.Fatal("RunPathDoctor() error = nil, want error")
+	}
+}
+
 func TestListSessionsViews(t *testing.T) {
 	t.Run("returns nil when session manager is unavailable", func(t *testing.T) {
 		app := NewApp()
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"log/slog"
+
+	"myT-x/internal/config"
+)
+
+// FocusNextActiveSession activates the session that most needs attention:
+// sessions with an unseen bell or that went idle after unseen output come
+// first, then sessions still producing unseen output, most recent first.
+// Returns the activated session name, or "" when no other session has
+// unseen activity.
+// Wails-bound: called from the frontend.
+func (a *App) FocusNextActiveSession() (string, error) {
+	sessions, err := a.requireSessions()
+	if err != nil {
+		return "", err
+	}
+	next, ok := sessions.NextAttentionSession(a.sessionService.GetActiveSessionName(), false)
+	if !ok {
+		return "", nil
+	}
+	a.sessionService.SetActive(next)
+	return next, nil
+}
+
+// handlePaneBell records a terminal bell for the pane's session.
+// Wired as snapshot.Deps.OnPaneBell.
+func (a *App) handlePaneBell(paneID string) {
+	sessions, err := a.requireSessions()
+	if err != nil {
+		return
+	}
+	if sessions.RecordBellByPaneID(paneID) {
+		a.followActivity()
+	}
+}
+
+// followActivity implements focus_follows_activity: when the active session
+// is idle, it switches to another session that needs input. Sessions that
+// are merely busy never steal focus.
+func (a *App) followActivity() {
+	if !config.Read(a.configState, func(cfg *config.Config) bool { return cfg.FocusFollowsActivity }) {
+		return
+	}
+	sessions, err := a.requireSessions()
+	if err != nil {
+		return
+	}
+
+	current := a.sessionService.GetActiveSessionName()
+	if current != "" {
+		snapshot, err := a.sessionService.FindSessionSnapshotByName(current)
+		if err == nil && !snapshot.IsIdle {
+			return
+		}
+	}
+	next, ok := sessions.NextAttentionSession(current, true)
+	if !ok {
+		return
+	}
+	slog.Debug("[DEBUG-SESSION] focus follows activity", "from", current, "to", next)
+	a.sessionService.SetActive(next)
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"myT-x/internal/config"
+	"myT-x/internal/tmux"
+)
+
+func newSessionAttentionAppForTest(t *testing.T, cfg config.Config, names ...string) (*App, map[string]string) {
+	t.Helper()
+
+	app := NewApp()
+	app.configState.Initialize(filepath.Join(t.TempDir(), "config.yaml"), cfg)
+	app.sessions = tmux.NewSessionManager()
+	t.Cleanup(app.sessions.Close)
+
+	panes := make(map[string]string, len(names))
+	for _, name := range names {
+		_, pane, err := app.sessions.CreateSession(name, "main", 80, 24)
+		if err != nil {
+			t.Fatalf("CreateSession(%s): %v", name, err)
+		}
+		panes[name] = pane.IDString()
+	}
+	// Events recorded after creation must carry a later timestamp.
+	time.Sleep(2 * time.Millisecond)
+	return app, panes
+}
+
+func TestFocusNextActiveSessionJumpsToBelledSession(t *testing.T) {
+	app, panes := newSessionAttentionAppForTest(t, config.DefaultConfig(), "current", "other")
+	app.SetActiveSession("current")
+
+	got, err := app.FocusNextActiveSession()
+	if err != nil {
+		t.Fatalf("FocusNextActiveSession() error = %v", err)
+	}
+	if got != "" {
+		t.Fatalf("FocusNextActiveSession() = %q, want none without activity", got)
+	}
+
+	// focus_follows_activity is off: a bell alone must not switch sessions.
+	app.handlePaneBell(panes["other"])
+	if active := app.GetActiveSession(); active != "current" {
+		t.Fatalf("active session = %q, want current", active)
+	}
+
+	got, err = app.FocusNextActiveSession()
+	if err != nil {
+		t.Fatalf("FocusNextActiveSession() error = %v", err)
+	}
+	if got != "other" || app.GetActiveSession() != "other" {
+		t.Fatalf("FocusNextActiveSession() = %q (active %q), want other", got, app.GetActiveSession())
+	}
+
+	// Both sessions are now seen; nothing is pending.
+	if got, _ := app.FocusNextActiveSession(); got != "" {
+		t.Fatalf("second FocusNextActiveSession() = %q, want none", got)
+	}
+}
+
+func TestFocusFollowsActivity(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.FocusFollowsActivity = true
+	app, panes := newSessionAttentionAppForTest(t, cfg, "busy", "waiting")
+
+	// The active session is still producing output, so it keeps focus.
+	app.SetActiveSession("busy")
+	app.handlePaneBell(panes["waiting"])
+	if active := app.GetActiveSession(); active != "busy" {
+		t.Fatalf("active session = %q, want busy to keep focus while not idle", active)
+	}
+
+	// Once there is no busy active session, the idle monitor's re-evaluation
+	// moves focus to the session with the pending bell.
+	app.sessionService.SetActiveSessionName("")
+	app.followActivity()
+	if active := app.GetActiveSession(); active != "waiting" {
+		t.Fatalf("active session = %q, want waiting", active)
+	}
+}
+
+func TestFocusNextActiveSessionRequiresSessions(t *testing.T) {
+	app := NewApp()
+	if _, err := app.FocusNextActiveSession(); err == nil {
+		t.Fatal("FocusNextActiveSession() error = nil, want error without session manager")
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+
+	"myT-x/internal/config"
+	"myT-x/internal/git"
+	"myT-x/internal/sessionbadge"
+	"myT-x/internal/tmux"
+)
+
+// SetSessionBadge sets the user-defined color/emoji badge of a session.
+// Empty color and emoji clear it. The badge is persisted per session working
+// directory and overrides any badge from session_badge_rules.
+// Wails-bound: called from the frontend.
+func (a *App) SetSessionBadge(sessionName, color, emoji string) error {
+	sessionName = strings.TrimSpace(sessionName)
+	if sessionName == "" {
+		return errors.New("session name is required")
+	}
+	color, emoji, err := config.NormalizeSessionBadge(color, emoji)
+	if err != nil {
+		return err
+	}
+	sessions, err := a.requireSessions()
+	if err != nil {
+		return err
+	}
+
+	var badge *tmux.SessionBadge
+	if color != "" || emoji != "" {
+		badge = &tmux.SessionBadge{Color: color, Emoji: emoji}
+	}
+	if err := sessions.SetSessionBadge(sessionName, badge); err != nil {
+		return err
+	}
+	if err := a.sessionBadgeService.Save(sessionName, sessionbadge.Badge{Color: color, Emoji: emoji}); err != nil {
+		// Sessions without a resolvable working directory keep the badge in
+		// memory only; it is still shown until the session is destroyed.
+		slog.Warn("[WARN-SESSION-BADGE] badge applied but not persisted",
+			"session", sessionName, "error", err)
+	}
+	a.snapshotService.RequestSnapshot(false)
+	return nil
+}
+
+// RefreshSessionBadges re-evaluates session_badge_rules for every session.
+// Branch and dirty state change outside myT-x, so the frontend calls this
+// periodically and when the window regains focus.
+// Wails-bound: called from the frontend.
+func (a *App) RefreshSessionBadges() {
+	sessions, err := a.requireSessions()
+	if err != nil {
+		return
+	}
+	cfg := a.configState.Snapshot()
+	for _, session := range sessions.ListSessions() {
+		a.refreshAutoBadge(sessions, cfg, session.Name)
+	}
+	a.snapshotService.RequestSnapshot(false)
+}
+
+// applySessionBadges restores the persisted user badge of a newly created
+// session and evaluates session_badge_rules for it.
+// Wired as session.Deps.OnSessionCreated.
+func (a *App) applySessionBadges(sessionName string) {
+	sessions, err := a.requireSessions()
+	if err != nil {
+		return
+	}
+
+	// Restore synchronously so the badge is part of the creation snapshot.
+	badge, err := a.sessionBadgeService.Load(sessionName)
+	if err != nil {
+		slog.Debug("[DEBUG-SESSION-BADGE] failed to load persisted badge",
+			"session", sessionName, "error", err)
+	} else if !badge.IsEmpty() {
+		if err := sessions.SetSessionBadge(sessionName, &tmux.SessionBadge{Color: badge.Color, Emoji: badge.Emoji}); err != nil {
+			slog.Debug("[DEBUG-SESSION-BADGE] failed to restore badge",
+				"session", sessionName, "error", err)
+		}
+	}
+
+	cfg := a.configState.Snapshot()
+	if len(cfg.SessionBadgeRules) == 0 {
+		return
+	}
+	// Rule evaluation runs git commands; keep it off the creation path.
+	go func() {
+		a.refreshAutoBadge(sessions, cfg, sessionName)
+		a.snapshotService.RequestSnapshot(false)
+	}()
+}
+
+// refreshAutoBadge evaluates session_badge_rules for one session and stores
+// the result as its automatic badge.
+func (a *App) refreshAutoBadge(sessions *tmux.SessionManager, cfg config.Config, sessionName string) {
+	var badge *tmux.SessionBadge
+	if len(cfg.SessionBadgeRules) > 0 {
+		if workDir, err := a.sessionService.ResolveSessionWorkDir(sessionName); err == nil {
+			facts := a.sessionBadgeFactsFn(workDir, config.SessionBadgeRulesNeedDirty(cfg))
+			if rule, ok := config.ResolveSessionBadge(cfg, facts); ok {
+				badge = &tmux.SessionBadge{Color: rule.Color, Emoji: rule.Emoji}
+			}
+		}
+	}
+	if err := sessions.SetAutoBadge(sessionName, badge); err != nil {
+		slog.Debug("[DEBUG-SESSION-BADGE] failed to set automatic badge",
+			"session", sessionName, "error", err)
+	}
+}
+
+// resolveSessionBadgeFacts collects the git state session_badge_rules match on.
+// Non-repository directories yield empty facts. git status is only run when
+// needDirty is true because it is the most expensive lookup.
+func resolveSessionBadgeFacts(workDir string, needDirty bool) config.SessionBadgeFacts {
+	repoRoot, err := git.FindRepoRoot(workDir)
+	if err != nil {
+		return config.SessionBadgeFacts{}
+	}
+	facts := config.SessionBadgeFacts{RepoPath: repoRoot}
+	repo, err := git.Open(workDir)
+	if err != nil {
+		return facts
+	}
+	if branch, err := repo.CurrentBranch(); err == nil {
+		facts.Branch = branch
+	}
+	if needDirty {
+		if dirty, err := repo.HasUncommittedChanges(); err == nil {
+			facts.Dirty = dirty
+		}
+	}
+	return facts
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"myT-x/internal/config"
+	"myT-x/internal/tmux"
+)
+
+func newSessionBadgeAppForTest(t *testing.T, cfg config.Config) (*App, string) {
+	t.Helper()
+
+	app := NewApp()
+	app.configState.Initialize(filepath.Join(t.TempDir(), "config.yaml"), cfg)
+	// Close the state store before TempDir cleanup removes its directory.
+	t.Cleanup(app.closeStateStore)
+	app.sessions = tmux.NewSessionManager()
+	t.Cleanup(app.sessions.Close)
+
+	rootPath := t.TempDir()
+	if _, _, err := app.sessions.CreateSession("session-a", "bash", 80, 24); err != nil {
+		t.Fatalf("CreateSession(session-a): %v", err)
+	}
+	if err := app.sessions.SetRootPath("session-a", rootPath); err != nil {
+		t.Fatalf("SetRootPath(session-a): %v", err)
+	}
+	return app, rootPath
+}
+
+func sessionBadgeForTest(t *testing.T, app *App, sessionName string) *tmux.SessionBadge {
+	t.Helper()
+	for _, snapshot := range app.sessions.Snapshot() {
+		if snapshot.Name == sessionName {
+			return snapshot.Badge
+		}
+	}
+	t.Fatalf("session %q not found", sessionName)
+	return nil
+}
+
+func TestSetSessionBadgePersistsAndRestores(t *testing.T) {
+	app, _ := newSessionBadgeAppForTest(t, config.DefaultConfig())
+
+	if err := app.SetSessionBadge("session-a", " #FF8800 ", "🔥"); err != nil {
+		t.Fatalf("SetSessionBadge() error = %v", err)
+	}
+	got := sessionBadgeForTest(t, app, "session-a")
+	if got == nil || got.Color != "#ff8800" || got.Emoji != "🔥" || got.Auto {
+		t.Fatalf("badge = %+v, want normalized user badge", got)
+	}
+
+	// Simulate the session being recreated: clear the in-memory badge and
+	// let the creation hook restore it from disk.
+	if err := app.sessions.SetSessionBadge("session-a", nil); err != nil {
+		t.Fatalf("SetSessionBadge(nil) error = %v", err)
+	}
+	app.applySessionBadges("session-a")
+	got = sessionBadgeForTest(t, app, "session-a")
+	if got == nil || got.Color != "#ff8800" {
+		t.Fatalf("restored badge = %+v, want #ff8800", got)
+	}
+
+	if err := app.SetSessionBadge("session-a", "", ""); err != nil {
+		t.Fatalf("SetSessionBadge(clear) error = %v", err)
+	}
+	if got := sessionBadgeForTest(t, app, "session-a"); got != nil {
+		t.Fatalf("badge after clear = %+v, want nil", got)
+	}
+	persisted, err := app.sessionBadgeService.Load("session-a")
+	if err != nil || !persisted.IsEmpty() {
+		t.Fatalf("persisted badge after clear = %+v, %v; want empty", persisted, err)
+	}
+}
+
+func TestSetSessionBadgeRejectsInvalidInput(t *testing.T) {
+	app, _ := newSessionBadgeAppForTest(t, config.DefaultConfig())
+
+	tests := []struct {
+		name        string
+		sessionName string
+		color       string
+	}{
+		{"empty session", " ", "#fff"},
+		{"invalid color", "session-a", "red"},
+		{"unknown session", "missing", "#fff"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := app.SetSessionBadge(tt.sessionName, tt.color, ""); err == nil {
+				t.Fatal("SetSessionBadge() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestRefreshSessionBadgesAppliesRules(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.SessionBadgeRules = []config.SessionBadgeRule{
+		{BranchPrefix: "feature/", Dirty: true, Color: "#f00"},
+		{BranchPrefix: "feature/", Emoji: "🚧"},
+	}
+	app, rootPath := newSessionBadgeAppForTest(t, cfg)
+
+	dirty := true
+	app.sessionBadgeFactsFn = func(workDir string, needDirty bool) config.SessionBadgeFacts {
+		if workDir != rootPath {
+			t.Errorf("workDir = %q, want %q", workDir, rootPath)
+		}
+		if !needDirty {
+			t.Error("needDirty = false, want true for dirty rule")
+		}
+		return config.SessionBadgeFacts{RepoPath: rootPath, Branch: "feature/x", Dirty: dirty}
+	}
+
+	app.RefreshSessionBadges()
+	got := sessionBadgeForTest(t, app, "session-a")
+	if got == nil || got.Color != "#f00" || !got.Auto {
+		t.Fatalf("badge = %+v, want dirty rule auto badge", got)
+	}
+
+	dirty = false
+	app.RefreshSessionBadges()
+	got = sessionBadgeForTest(t, app, "session-a")
+	if got == nil || got.Emoji != "🚧" || got.Color != "" {
+		t.Fatalf("badge = %+v, want clean rule auto badge", got)
+	}
+
+	// A user badge overrides the rule result.
+	if err := app.SetSessionBadge("session-a", "#00f", ""); err != nil {
+		t.Fatalf("SetSessionBadge() error = %v", err)
+	}
+	got = sessionBadgeForTest(t, app, "session-a")
+	if got == nil || got.Color != "#00f" || got.Auto {
+		t.Fatalf("badge = %+v, want user badge", got)
+	}
+}
@@ -0,0 +1,33 @@
+package main
+
+import "myT-x/internal/bringup"
+
+// BringUpSessions starts the named session templates (config
+// session_templates) and their dependencies in dependency order, waiting for
+// each health probe before starting dependents. Empty names brings up every
+// template. Progress is streamed as session-bringup:status events.
+// Wails-bound: called from the frontend.
+func (a *App) BringUpSessions(names []string) (bringup.Status, error) {
+	return a.bringUpService.BringUp(names)
+}
+
+// TearDownSessions closes the named template sessions and every template
+// session that depends on them, dependents first. Empty names tears down
+// every template.
+// Wails-bound: called from the frontend.
+func (a *App) TearDownSessions(names []string) (bringup.Status, error) {
+	return a.bringUpService.TearDown(names)
+}
+
+// GetBringUpStatus returns the status of the latest bring-up or tear-down.
+// Wails-bound: called from the frontend.
+func (a *App) GetBringUpStatus() bringup.Status {
+	return a.bringUpService.Status()
+}
+
+// CancelBringUp stops the running bring-up or tear-down. Sessions already
+// created keep running.
+// Wails-bound: called from the frontend.
+func (a *App) CancelBringUp() error {
+	return a.bringUpService.Cancel()
+}
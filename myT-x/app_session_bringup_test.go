@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"myT-x/internal/bringup"
+	"myT-x/internal/config"
+	"myT-x/internal/tmux"
+)
+
+func TestBringUpSessionsValidatesTemplates(t *testing.T) {
+	app := NewApp()
+	if _, err := app.BringUpSessions(nil); err == nil || !strings.Contains(err.Error(), "no session templates") {
+		t.Fatalf("BringUpSessions() error = %v, want no templates error", err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.SessionTemplates = []config.SessionTemplate{{Name: "api"}}
+	app.configState.SetSnapshot(cfg)
+	if _, err := app.TearDownSessions([]string{"web"}); err == nil || !strings.Contains(err.Error(), `unknown session template "web"`) {
+		t.Fatalf("TearDownSessions() error = %v, want unknown template error", err)
+	}
+	if err := app.CancelBringUp(); err == nil {
+		t.Fatal("CancelBringUp() error = nil, want error when idle")
+	}
+}
+
+func TestBringUpSessionsReusesExistingSession(t *testing.T) {
+	stubRuntimeEventsEmit(t)
+	app := NewApp()
+	app.setRuntimeContext(context.Background())
+	app.sessions = tmux.NewSessionManager()
+	t.Cleanup(app.sessions.Close)
+	if _, _, err := app.sessions.CreateSession("api", "main", 80, 24); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	cfg := config.DefaultConfig()
+	cfg.SessionTemplates = []config.SessionTemplate{{Name: "api"}}
+	app.configState.SetSnapshot(cfg)
+
+	if _, err := app.BringUpSessions([]string{"api"}); err != nil {
+		t.Fatalf("BringUpSessions() error = %v", err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for app.GetBringUpStatus().Running {
+		if time.Now().After(deadline) {
+			t.Fatal("bring-up did not finish")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	app.bgWG.Wait()
+	status := app.GetBringUpStatus()
+	if len(status.Templates) != 1 || status.Templates[0].State != bringup.StateReady || !status.Templates[0].Reused {
+		t.Fatalf("status = %+v, want api reused and ready", status)
+	}
+}
@@ -0,0 +1,27 @@
+package main
+
+import "myT-x/internal/sessioncopy"
+
+// CopyBetweenSessions copies a file or directory from srcSession's directory
+// (its worktree or root) to dstSession's. Relative paths are resolved against
+// each session's directory and both must stay inside it. An empty dstPath
+// keeps the source's relative path. Progress is reported through
+// sessioncopy.ProgressEvent.
+// Wails-bound: called from the frontend.
+func (a *App) CopyBetweenSessions(srcSession, srcPath, dstSession, dstPath string) (sessioncopy.Result, error) {
+	return a.sessionCopyService.Copy(sessioncopy.Request{
+		SrcSession: srcSession,
+		SrcPath:    srcPath,
+		DstSession: dstSession,
+		DstPath:    dstPath,
+	})
+}
+
+// copyBetweenSessionsFromShim serves the copy-between-sessions shim command.
+func (a *App) copyBetweenSessionsFromShim(srcSession, srcPath, dstSession, dstPath string) (string, error) {
+	result, err := a.CopyBetweenSessions(srcSession, srcPath, dstSession, dstPath)
+	if err != nil {
+		return "", err
+	}
+	return result.Destination, nil
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"myT-x/internal/tmux"
+)
+
+func TestCopyBetweenSessionsFromShimCopiesIntoTargetSession(t *testing.T) {
+	app := NewApp()
+	mgr := tmux.NewSessionManager()
+	roots := map[string]string{"agent-a": t.TempDir(), "agent-b": t.TempDir()}
+	for name, root := range roots {
+		if _, _, err := mgr.CreateSession(name, "0", 80, 24); err != nil {
+			t.Fatalf("CreateSession(%q) error = %v", name, err)
+		}
+		if err := mgr.SetRootPath(name, root); err != nil {
+			t.Fatalf("SetRootPath(%q) error = %v", name, err)
+		}
+	}
+	app.sessions = mgr
+	if err := os.WriteFile(filepath.Join(roots["agent-a"], "fix.patch"), []byte("diff"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	destination, err := app.copyBetweenSessionsFromShim("agent-a", "fix.patch", "agent-b", "")
+	if err != nil {
+		t.Fatalf("copyBetweenSessionsFromShim() error = %v", err)
+	}
+	data, err := os.ReadFile(destination)
+	if err != nil || string(data) != "diff" {
+		t.Fatalf("destination %q content = %q, %v; want the copied patch", destination, data, err)
+	}
+
+	if _, err := app.copyBetweenSessionsFromShim("agent-a", "fix.patch", "missing", ""); err == nil {
+		t.Fatal("copyBetweenSessionsFromShim() to an unknown session should fail")
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"myT-x/internal/sessiongroup"
+	"myT-x/internal/tmux"
+)
+
+// SessionGroupMember describes one session of CreateSessionGroup.
+type SessionGroupMember = sessiongroup.Member
+
+// CreateSessionGroup creates one session per member, in order, and puts them
+// in group, e.g. the frontend, backend and infra repositories of one
+// workspace. Members with worktree options get a new worktree each. When a
+// member fails, the sessions and worktrees already created are rolled back
+// and the error lists the failure and every rollback error.
+// Wails-bound: called from the frontend.
+func (a *App) CreateSessionGroup(group string, members []SessionGroupMember) ([]tmux.SessionSnapshot, error) {
+	return a.sessionGroupService.Create(group, members)
+}
+
+// AddSessionToGroup moves an existing session into group.
+// Wails-bound: called from the frontend.
+func (a *App) AddSessionToGroup(sessionName string, group string) error {
+	return a.sessionGroupService.Add(sessionName, group)
+}
+
+// KillSessionGroup kills every session of group, removing their worktrees
+// when deleteWorktrees is true. Failures do not stop the remaining kills;
+// the error lists all of them.
+// Wails-bound: called from the frontend.
+func (a *App) KillSessionGroup(group string, deleteWorktrees bool) error {
+	return a.sessionGroupService.Kill(group, deleteWorktrees)
+}
+
+// createSessionGroupMember creates the session of one group member through
+// the regular session and worktree APIs.
+func (a *App) createSessionGroupMember(member sessiongroup.Member) (tmux.SessionSnapshot, error) {
+	if member.Worktree != nil {
+		return a.CreateSessionWithWorktree(member.RepoPath, member.SessionName, *member.Worktree)
+	}
+	return a.CreateSession(member.RepoPath, member.SessionName, CreateSessionOptions{
+		EnableAgentTeam:     member.Options.EnableAgentTeam,
+		UseClaudeEnv:        member.Options.UseClaudeEnv,
+		UsePaneEnv:          member.Options.UsePaneEnv,
+		UseSessionPaneScope: member.Options.UseSessionPaneScope,
+		StartupCommand:      member.Options.StartupCommand,
+		RemainOnExit:        member.Options.RemainOnExit,
+		IsolateShellHistory: member.Options.IsolateShellHistory,
+	})
+}
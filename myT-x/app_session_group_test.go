@@ -0,0 +1,33 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"myT-x/internal/tmux"
+)
+
+func TestSessionGroupAPIs(t *testing.T) {
+	stubRuntimeEventsEmit(t)
+	app := NewApp()
+	if _, err := app.CreateSessionGroup(" ", []SessionGroupMember{{RepoPath: `C:\src\web`}}); err == nil ||
+		!strings.Contains(err.Error(), "group name is required") {
+		t.Fatalf("CreateSessionGroup() error = %v, want group name error", err)
+	}
+
+	app.sessions = tmux.NewSessionManager()
+	t.Cleanup(app.sessions.Close)
+	if _, _, err := app.sessions.CreateSession("web", "0", 120, 40); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	if err := app.AddSessionToGroup("web", "shop"); err != nil {
+		t.Fatalf("AddSessionToGroup() error = %v", err)
+	}
+	snapshots := app.sessions.Snapshot()
+	if len(snapshots) != 1 || snapshots[0].Group != "shop" {
+		t.Fatalf("snapshots = %+v, want web in group shop", snapshots)
+	}
+	if err := app.KillSessionGroup("missing", false); err == nil || !strings.Contains(err.Error(), "session group not found") {
+		t.Fatalf("KillSessionGroup() error = %v, want group not found", err)
+	}
+}
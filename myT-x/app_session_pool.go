@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"myT-x/internal/config"
+	"myT-x/internal/shellpool"
+	"myT-x/internal/terminal"
+	"myT-x/internal/workerutil"
+)
+
+// buildSessionPoolDeps constructs the dependency set for the warm shell pool.
+// Warm shells inherit the app environment; pane-specific variables are
+// applied when a shell is acquired.
+func buildSessionPoolDeps() shellpool.Deps {
+	return shellpool.Deps{
+		Start: func(shell string, args []string, dir string) (shellpool.Terminal, error) {
+			term, err := terminal.Start(terminal.Config{Shell: shell, Args: args, Dir: dir, Env: os.Environ()})
+			if err != nil {
+				// Return an untyped nil, not a nil *terminal.Terminal.
+				return nil, err
+			}
+			return term, nil
+		},
+	}
+}
+
+// sessionPoolSpec derives the pool spec from cfg. Warm shells run the default
+// shell and start in the launch directory; panes resolving to another shell
+// through shell_rules bypass the pool.
+func sessionPoolSpec(cfg config.Config, dir string) shellpool.Spec {
+	spec := shellpool.Spec{Shell: cfg.Shell, Dir: dir}
+	if pool := cfg.SessionPool; pool != nil {
+		spec.Size = pool.Size
+		spec.MaxIdle = time.Duration(pool.MaxIdleMinutes) * time.Minute
+	}
+	return spec
+}
+
+// startSessionPool starts the pool health checks and fills the pool for the
+// current config. It runs after the shim check so warm shells see the final PATH.
+func (a *App) startSessionPool(parent context.Context) {
+	ctx, cancel := context.WithCancel(parent)
+	a.sessionPoolCancel = cancel
+	workerutil.RunWithPanicRecovery(ctx, "session-pool", &a.bgWG, a.sessionPool.Run, a.defaultRecoveryOptions())
+	a.sessionPool.Configure(sessionPoolSpec(a.configState.Snapshot(), a.launchDir))
+}
+
+// applyRuntimeSessionPoolUpdate resizes the pool, draining warm shells when
+// the default shell changed. It reads the latest snapshot so concurrent saves
+// converge on the newest config.
+func (a *App) applyRuntimeSessionPoolUpdate() {
+	a.sessionPool.Configure(sessionPoolSpec(a.configState.Snapshot(), a.launchDir))
+}
+
+// acquireWarmTerminal hands a warm shell to the router, or nil when the pool
+// has none for shell.
+func (a *App) acquireWarmTerminal(shell, workDir string, env map[string]string, cols, rows int) *terminal.Terminal {
+	warm, ok := a.sessionPool.Acquire(shellpool.Request{
+		Shell:   shell,
+		Dir:     workDir,
+		Env:     env,
+		Columns: cols,
+		Rows:    rows,
+	})
+	if !ok {
+		return nil
+	}
+	term, _ := warm.(*terminal.Terminal)
+	return term
+}
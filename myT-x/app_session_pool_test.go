@@ -0,0 +1,30 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"myT-x/internal/config"
+	"myT-x/internal/shellpool"
+)
+
+func TestSessionPoolSpec(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Shell = "pwsh.exe"
+	if got, want := sessionPoolSpec(cfg, `C:\launch`), (shellpool.Spec{Shell: "pwsh.exe", Dir: `C:\launch`}); got != want {
+		t.Fatalf("sessionPoolSpec(no pool) = %+v, want %+v", got, want)
+	}
+
+	cfg.SessionPool = &config.SessionPoolConfig{Size: 2, MaxIdleMinutes: 15}
+	want := shellpool.Spec{Shell: "pwsh.exe", Dir: `C:\launch`, Size: 2, MaxIdle: 15 * time.Minute}
+	if got := sessionPoolSpec(cfg, `C:\launch`); got != want {
+		t.Fatalf("sessionPoolSpec() = %+v, want %+v", got, want)
+	}
+}
+
+func TestAcquireWarmTerminalWithoutPoolConfig(t *testing.T) {
+	app := NewApp()
+	if term := app.acquireWarmTerminal("powershell.exe", t.TempDir(), nil, 80, 24); term != nil {
+		t.Fatalf("acquireWarmTerminal() = %v, want nil before the pool is configured", term)
+	}
+}
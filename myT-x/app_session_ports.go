@@ -0,0 +1,20 @@
+package main
+
+import (
+	"errors"
+	"strings"
+
+	"myT-x/internal/sessionports"
+)
+
+// GetSessionPorts returns the TCP ports that processes started from the
+// session's panes are listening on, sorted by port number. Changes are also
+// pushed as session-ports:opened and session-ports:closed events.
+// Wails-bound: called from the frontend.
+func (a *App) GetSessionPorts(sessionName string) ([]sessionports.Port, error) {
+	sessionName = strings.TrimSpace(sessionName)
+	if sessionName == "" {
+		return nil, errors.New("session name is required")
+	}
+	return a.sessionPortsService.SessionPorts(sessionName), nil
+}
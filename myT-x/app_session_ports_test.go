@@ -0,0 +1,46 @@
+package main
+
+import (
+	"testing"
+
+	"myT-x/internal/sessionports"
+)
+
+func TestGetSessionPortsRequiresSessionName(t *testing.T) {
+	app := NewApp()
+	if _, err := app.GetSessionPorts("  "); err == nil {
+		t.Fatal("GetSessionPorts() error = nil, want error for empty name")
+	}
+}
+
+func TestGetSessionPortsReturnsSessionListeners(t *testing.T) {
+	app := NewApp()
+	app.sessionPortsService = sessionports.NewService(sessionports.Deps{
+		PaneRoots: func() []sessionports.PaneRoot {
+			return []sessionports.PaneRoot{
+				{SessionName: "web", PaneID: "%1", PID: 10},
+				{SessionName: "api", PaneID: "%2", PID: 20},
+			}
+		},
+		ScanListeners: func() ([]sessionports.Listener, error) {
+			return []sessionports.Listener{
+				{Address: "0.0.0.0", Port: 5173, PID: 11},
+				{Address: "127.0.0.1", Port: 8080, PID: 20},
+			}, nil
+		},
+		ScanProcesses: func() (map[uint32]sessionports.Process, error) {
+			return map[uint32]sessionports.Process{11: {PID: 11, ParentPID: 10, Name: "node.exe"}}, nil
+		},
+	})
+	if err := app.sessionPortsService.Poll(); err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+
+	ports, err := app.GetSessionPorts(" web ")
+	if err != nil {
+		t.Fatalf("GetSessionPorts() error = %v", err)
+	}
+	if len(ports) != 1 || ports[0].Port != 5173 || ports[0].URL != "http://localhost:5173" {
+		t.Fatalf("GetSessionPorts(web) = %+v, want only 5173", ports)
+	}
+}
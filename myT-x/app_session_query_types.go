@@ -0,0 +1,13 @@
+package main
+
+import (
+	"myT-x/internal/session"
+	"myT-x/internal/sessionquery"
+)
+
+// Type aliases for Wails binding compatibility.
+// These re-export the session query types used by App.QuerySessions.
+type SessionQueryFilter = sessionquery.Filter
+type SessionQuerySort = sessionquery.Sort
+type SessionQueryPage = sessionquery.Page
+type SessionQueryResult = session.QueryResult
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"myT-x/internal/sessioninfo"
+)
+
+// shellHistoryDirName is the shell history directory under a session's
+// session-info directory.
+const shellHistoryDirName = "shell-history"
+
+// resolvePaneShellHistoryDir returns the directory that holds the shell
+// history of an isolated session, creating it when needed. An empty result
+// leaves the pane on the user's global history.
+func (a *App) resolvePaneShellHistoryDir(sessionName, workDir string) string {
+	configDir, err := appConfigDirProvider(a)()
+	if err != nil {
+		slog.Warn("[WARN-ENV] shell history isolation skipped: config dir unavailable",
+			"session", sessionName, "error", err)
+		return ""
+	}
+	baseDir, err := sessioninfo.DirectoryPath(configDir, workDir)
+	if err != nil {
+		slog.Warn("[WARN-ENV] shell history isolation skipped: invalid session directory",
+			"session", sessionName, "workDir", workDir, "error", err)
+		return ""
+	}
+	dir := filepath.Join(baseDir, shellHistoryDirName)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		slog.Warn("[WARN-ENV] shell history isolation skipped: failed to create directory",
+			"session", sessionName, "dir", dir, "error", err)
+		return ""
+	}
+	return dir
+}
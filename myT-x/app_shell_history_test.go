@@ -0,0 +1,34 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"myT-x/internal/sessioninfo"
+)
+
+func TestResolvePaneShellHistoryDir(t *testing.T) {
+	app := NewApp()
+	configDir := t.TempDir()
+	workDir := t.TempDir()
+	app.configDirProvider = func() (string, error) { return configDir, nil }
+
+	got := app.resolvePaneShellHistoryDir("demo", workDir)
+	baseDir, err := sessioninfo.DirectoryPath(configDir, workDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(baseDir, shellHistoryDirName); got != want {
+		t.Fatalf("resolvePaneShellHistoryDir() = %q, want %q", got, want)
+	}
+	if info, err := os.Stat(got); err != nil || !info.IsDir() {
+		t.Fatalf("history dir not created: %v", err)
+	}
+
+	app.configDirProvider = func() (string, error) { return "", errors.New("no config") }
+	if got := app.resolvePaneShellHistoryDir("demo", workDir); got != "" {
+		t.Fatalf("resolvePaneShellHistoryDir() without config dir = %q, want empty", got)
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"myT-x/internal/ipc"
+	"myT-x/internal/startupmetrics"
+	"myT-x/internal/workerutil"
+)
+
+// subsystemReadyEvent reports that a background startup subsystem finished.
+// The payload is the startupmetrics.Phase of that subsystem.
+const subsystemReadyEvent = "app:subsystem-ready"
+
+// AppMetrics is the runtime metrics snapshot returned by GetMetrics.
+type AppMetrics struct {
+	Startup startupmetrics.Report `json:"startup"`
+	// PipeServerRestart is the latest pipe server restart, if any.
+	PipeServerRestart *ipc.RestartReport `json:"pipe_server_restart,omitempty"`
+}
+
+// GetMetrics returns startup phase timings, the background subsystems
+// that are still initializing, and the latest pipe server restart.
+// Wails-bound: called from the frontend.
+func (a *App) GetMetrics() AppMetrics {
+	metrics := AppMetrics{PipeServerRestart: a.lastPipeServerRestart.Load()}
+	if a.startupMetrics != nil {
+		metrics.Startup = a.startupMetrics.Report()
+	}
+	return metrics
+}
+
+// domReady is the Wails OnDomReady callback: the window content has loaded.
+func (a *App) domReady(_ context.Context) {
+	a.startupMetrics.MarkDOMReady()
+	a.renderingMonitor.MarkReady()
+	report := a.startupMetrics.Report()
+	slog.Info("[STARTUP] frontend ready", "domReadyMs", report.DOMReadyMs, "pending", report.Pending)
+}
+
+// startBackgroundSubsystem initializes a non-critical subsystem off the
+// startup path. Its timing is recorded as a background phase and
+// subsystemReadyEvent is emitted when it finishes, including on failure.
+func (a *App) startBackgroundSubsystem(ctx context.Context, name string, fn func() error) {
+	done := a.startupMetrics.BeginBackground(name)
+	finish := func(err error) {
+		phase := done(err)
+		if err != nil {
+			slog.Warn("[STARTUP] background subsystem failed", "subsystem", name, "error", err)
+		} else {
+			slog.Debug("[STARTUP] background subsystem ready", "subsystem", name, "durationMs", phase.DurationMs)
+		}
+		a.emitRuntimeEventWithContext(a.runtimeContext(), subsystemReadyEvent, phase)
+	}
+
+	opts := a.defaultRecoveryOptions()
+	// One-shot initialization: a panic is reported as a failed subsystem, not retried.
+	opts.MaxRetries = 1
+	onFatal := opts.OnFatal
+	opts.OnFatal = func(worker string, maxRetries int) {
+		finish(fmt.Errorf("%s panicked during initialization", name))
+		if onFatal != nil {
+			onFatal(worker, maxRetries)
+		}
+	}
+	workerutil.RunWithPanicRecovery(ctx, "startup-"+name, &a.bgWG, func(context.Context) {
+		finish(fn())
+	}, opts)
+}
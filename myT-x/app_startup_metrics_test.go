@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"myT-x/internal/startupmetrics"
+)
+
+func TestStartBackgroundSubsystemReportsReadiness(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		events []startupmetrics.Phase
+	)
+	orig := runtimeEventsEmitFn
+	runtimeEventsEmitFn = func(_ context.Context, name string, data ...any) {
+		if name != subsystemReadyEvent || len(data) == 0 {
+			return
+		}
+		if phase, ok := data[0].(startupmetrics.Phase); ok {
+			mu.Lock()
+			events = append(events, phase)
+			mu.Unlock()
+		}
+	}
+	t.Cleanup(func() { runtimeEventsEmitFn = orig })
+
+	app := NewApp()
+	app.setRuntimeContext(context.Background())
+
+	release := make(chan struct{})
+	app.startBackgroundSubsystem(context.Background(), "deep-link", func() error {
+		<-release
+		return nil
+	})
+	app.startBackgroundSubsystem(context.Background(), "worktree-prune", func() error {
+		return errors.New("not a git repository")
+	})
+	app.startBackgroundSubsystem(context.Background(), "global-hotkey", func() error {
+		panic("boom")
+	})
+
+	if pending := app.GetMetrics().Startup.Pending; len(pending) == 0 || pending[0] != "deep-link" {
+		t.Fatalf("Pending = %v, want deep-link still pending", pending)
+	}
+	close(release)
+	app.bgWG.Wait()
+
+	metrics := app.GetMetrics().Startup
+	if len(metrics.Pending) != 0 || len(metrics.Phases) != 3 {
+		t.Fatalf("metrics after completion = %+v", metrics)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	got := map[string]string{}
+	for _, phase := range events {
+		if !phase.Background {
+			t.Errorf("phase %q is not marked background", phase.Name)
+		}
+		got[phase.Name] = phase.Error
+	}
+	want := map[string]string{
+		"deep-link":      "",
+		"worktree-prune": "not a git repository",
+		"global-hotkey":  "global-hotkey panicked during initialization",
+	}
+	for name, wantErr := range want {
+		if gotErr, ok := got[name]; !ok || gotErr != wantErr {
+			t.Errorf("ready event %q error = %q (emitted %v), want %q", name, gotErr, ok, wantErr)
+		}
+	}
+}
+
+func TestGetMetricsWithoutRecorder(t *testing.T) {
+	app := &App{}
+	if got := app.GetMetrics(); got.Startup.Phases != nil || got.Startup.StartupMs != 0 {
+		t.Fatalf("GetMetrics() = %+v, want zero value", got)
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+
+	"myT-x/internal/statestore"
+)
+
+// maxCommandApprovalAuditEntries is the number of command approval decisions
+// kept in the audit log.
+const maxCommandApprovalAuditEntries = 5000
+
+// requireStateStore returns the persisted state store, opening the SQLite
+// database under the config directory on first use. A failed open is retried
+// on the next call.
+func (a *App) requireStateStore() (statestore.Store, error) {
+	a.stateStoreMu.Lock()
+	defer a.stateStoreMu.Unlock()
+	if a.stateStore != nil {
+		return a.stateStore, nil
+	}
+	if a.shuttingDown.Load() {
+		return nil, statestore.ErrClosed
+	}
+	dir, err := a.configDirProvider()
+	if err != nil {
+		return nil, err
+	}
+	store, err := statestore.OpenSQLite(filepath.Join(dir, statestore.FileName))
+	if err != nil {
+		return nil, fmt.Errorf("open state store: %w", err)
+	}
+	a.stateStore = store
+	return store, nil
+}
+
+// closeStateStore closes the state store if it was opened.
+func (a *App) closeStateStore() {
+	a.stateStoreMu.Lock()
+	store := a.stateStore
+	a.stateStore = nil
+	a.stateStoreMu.Unlock()
+	if store == nil {
+		return
+	}
+	if err := store.Close(); err != nil {
+		slog.Warn("[STATESTORE] close failed", "error", err)
+	}
+}
+
+// checkStateStore runs the state store integrity check and trims audit logs.
+// It is registered as a maintenance job.
+func (a *App) checkStateStore(ctx context.Context) error {
+	store, err := a.requireStateStore()
+	if err != nil {
+		return err
+	}
+	if err := store.Check(ctx); err != nil {
+		return err
+	}
+	trimmed, err := store.TrimLog(ctx, statestore.StreamCommandApprovals, maxCommandApprovalAuditEntries)
+	if err != nil {
+		return err
+	}
+	if trimmed > 0 {
+		slog.Debug("[STATESTORE] trimmed command approval audit log", "deleted", trimmed)
+	}
+	return nil
+}
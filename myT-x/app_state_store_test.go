@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"myT-x/internal/cmdapproval"
+	"myT-x/internal/statestore"
+)
+
+func TestRequireStateStoreNeedsConfigDir(t *testing.T) {
+	app := NewApp()
+	if _, err := app.requireStateStore(); err == nil {
+		t.Fatal("requireStateStore() error = nil before config is initialized")
+	}
+	// Closing a store that was never opened is a no-op.
+	app.closeStateStore()
+}
+
+func TestRecordCommandApprovalAppendsAuditEntry(t *testing.T) {
+	app := NewApp()
+	store := statestore.NewMemoryStore()
+	app.stateStore = store
+
+	app.recordCommandApproval(
+		cmdapproval.PendingCommand{ID: "approval-1", SessionName: "agent", Command: "send-keys"},
+		cmdapproval.Resolution{ID: "approval-1", SessionName: "agent", Decision: cmdapproval.DecisionDeny, Reason: cmdapproval.ReasonUser},
+	)
+
+	entries, err := store.ReadLog(context.Background(), statestore.StreamCommandApprovals, 0, 0)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("ReadLog() = %+v, %v, want one entry", entries, err)
+	}
+	var got commandApprovalAuditEntry
+	if err := json.Unmarshal(entries[0].Value, &got); err != nil {
+		t.Fatalf("audit entry is not JSON: %v", err)
+	}
+	if got.ID != "approval-1" || got.Command != "send-keys" || got.Decision != cmdapproval.DecisionDeny {
+		t.Fatalf("audit entry = %+v", got)
+	}
+}
+
+func TestCheckStateStoreTrimsAuditLog(t *testing.T) {
+	app := NewApp()
+	store := statestore.NewMemoryStore()
+	app.stateStore = store
+	ctx := context.Background()
+	for range maxCommandApprovalAuditEntries + 3 {
+		if _, err := store.Append(ctx, statestore.StreamCommandApprovals, []byte("{}")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := app.checkStateStore(ctx); err != nil {
+		t.Fatalf("checkStateStore() error = %v", err)
+	}
+	entries, _ := store.ReadLog(ctx, statestore.StreamCommandApprovals, 0, 0)
+	if len(entries) != maxCommandApprovalAuditEntries {
+		t.Fatalf("audit entries = %d, want %d", len(entries), maxCommandApprovalAuditEntries)
+	}
+}
@@ -408,8 +408,10 @@ func TestSaveTaskSchedulerSettings(t *testing.T) {
 			app.configState.Initialize(newConfigPathForTaskSchedulerTest(t, configFileName), config.DefaultConfig())
 
 			eventCount := 0
-			runtimeEventsEmitFn = func(_ context.Context, _ string, _ ...any) {
-				eventCount++
+			runtimeEventsEmitFn = func(_ context.Context, name string, _ ...any) {
+				if name == "config:updated" {
+					eventCount++
+				}
 			}
 
 			err := app.SaveTaskSchedulerSettings(tt.input)
@@ -450,6 +452,9 @@ func TestSaveTaskSchedulerSettingsEmitsUpdateEvent(t *testing.T) {
 	var eventName string
 	var eventPayload config.UpdatedEvent
 	runtimeEventsEmitFn = func(_ context.Context, name string, data ...any) {
+		if name == "config:applied" {
+			return
+		}
 		eventCount++
 		eventName = name
 		if len(data) == 0 {
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"myT-x/internal/taskbar"
+	"myT-x/internal/workerutil"
+)
+
+// SetSessionTaskbarAlertsMuted sets whether a session is excluded from
+// taskbar flashing and the taskbar badge count.
+// Wails-bound: called from the frontend.
+func (a *App) SetSessionTaskbarAlertsMuted(sessionName string, muted bool) error {
+	sessionName = strings.TrimSpace(sessionName)
+	if sessionName == "" {
+		return errors.New("session name is required")
+	}
+	sessions, err := a.requireSessions()
+	if err != nil {
+		return err
+	}
+	if err := sessions.SetSessionTaskbarAlertsMuted(sessionName, muted); err != nil {
+		return err
+	}
+	a.snapshotService.RequestSnapshot(false)
+	return nil
+}
+
+// taskbarAlerts returns the background sessions that need input, in sidebar
+// order. The active session, detached sessions, and muted sessions are
+// excluded.
+// Wired as taskbar.Deps.Alerts.
+func (a *App) taskbarAlerts() []taskbar.Alert {
+	sessions, err := a.requireSessions()
+	if err != nil {
+		return nil
+	}
+	active := a.sessionService.GetActiveSessionName()
+	activities := sessions.SessionActivities()
+
+	var alerts []taskbar.Alert
+	for _, snapshot := range sessions.Snapshot() {
+		if snapshot.Name == active || snapshot.Detached || snapshot.TaskbarAlertsMuted ||
+			!activities[snapshot.Name].NeedsInput {
+			continue
+		}
+		alert := taskbar.Alert{Session: snapshot.Name}
+		if snapshot.Badge != nil {
+			alert.Color = snapshot.Badge.Color
+		}
+		alerts = append(alerts, alert)
+	}
+	return alerts
+}
+
+// startTaskbarAlerts flashes and badges the taskbar button while background
+// sessions need input and the window is unfocused.
+func (a *App) startTaskbarAlerts(parent context.Context) {
+	ctx, cancel := context.WithCancel(parent)
+	a.taskbarCancel = cancel
+	workerutil.RunWithPanicRecovery(ctx, "taskbar-alerts", &a.bgWG, a.taskbarService.Run, a.defaultRecoveryOptions())
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"myT-x/internal/config"
+	"myT-x/internal/taskbar"
+	"myT-x/internal/tmux"
+)
+
+func TestTaskbarAlerts(t *testing.T) {
+	app, panes := newSessionAttentionAppForTest(t, config.DefaultConfig(), "active", "blocked", "muted", "quiet")
+	app.SetActiveSession("active")
+	if err := app.sessions.SetSessionBadge("blocked", &tmux.SessionBadge{Color: "#0af"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"active", "blocked", "muted"} {
+		app.handlePaneBell(panes[name])
+	}
+	if err := app.SetSessionTaskbarAlertsMuted("muted", true); err != nil {
+		t.Fatalf("SetSessionTaskbarAlertsMuted() error = %v", err)
+	}
+
+	want := []taskbar.Alert{{Session: "blocked", Color: "#0af"}}
+	if got := app.taskbarAlerts(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("taskbarAlerts() = %+v, want %+v", got, want)
+	}
+
+	if err := app.SetSessionTaskbarAlertsMuted("muted", false); err != nil {
+		t.Fatal(err)
+	}
+	if got := app.taskbarAlerts(); len(got) != 2 {
+		t.Fatalf("taskbarAlerts() after unmute = %+v, want blocked and muted", got)
+	}
+	if err := app.SetSessionTaskbarAlertsMuted("missing", true); err == nil {
+		t.Fatal("SetSessionTaskbarAlertsMuted(missing) error = nil, want session not found")
+	}
+}
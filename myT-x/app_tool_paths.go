@@ -0,0 +1,85 @@
+package main
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+
+	"myT-x/internal/config"
+	"myT-x/internal/repoconfig"
+	"myT-x/internal/toolpaths"
+)
+
+// SessionToolPaths reports the tool directories new panes of a session get
+// in front of PATH (config tool_paths).
+type SessionToolPaths struct {
+	// Enabled is false when tool_paths is not configured.
+	Enabled bool `json:"enabled"`
+	// Root is the directory whose manifests were checked.
+	Root    string            `json:"root"`
+	Entries []toolpaths.Entry `json:"entries"`
+}
+
+// resolvePaneToolPaths returns the tool directories for a new pane, or nil.
+func (a *App) resolvePaneToolPaths(sessionName, workDir string) []string {
+	report := a.detectSessionToolPaths(sessionName, workDir)
+	if len(report.Entries) == 0 {
+		return nil
+	}
+	dirs := toolpaths.Dirs(report.Entries)
+	slog.Debug("[DEBUG-ENV] tool paths prepended to pane PATH",
+		"session", sessionName, "root", report.Root, "dirs", dirs)
+	return dirs
+}
+
+// detectSessionToolPaths checks the manifests in the session's working
+// directory, falling back to fallbackDir for unknown sessions. Repo-local
+// tool directories (node_modules/.bin) are only reported for directories
+// the user trusted, as a cloned repository could otherwise shadow common
+// commands on PATH.
+func (a *App) detectSessionToolPaths(sessionName, fallbackDir string) SessionToolPaths {
+	kinds := config.EnabledToolPathKinds(a.configState.Snapshot())
+	if kinds == nil {
+		return SessionToolPaths{Entries: []toolpaths.Entry{}}
+	}
+	root := fallbackDir
+	if sessionDir, err := a.sessionService.ResolveSessionWorkDir(sessionName); err == nil && sessionDir != "" {
+		root = sessionDir
+	}
+	entries := toolpaths.Detect(root, kinds)
+	if !a.directoryTrusted(root) {
+		entries = toolpaths.WithoutRepoLocal(entries)
+	}
+	if entries == nil {
+		entries = []toolpaths.Entry{}
+	}
+	return SessionToolPaths{Enabled: true, Root: root, Entries: entries}
+}
+
+// directoryTrusted reports whether the user trusted dir in the repo config
+// trust store. Lookup failures count as untrusted.
+func (a *App) directoryTrusted(dir string) bool {
+	if a.repoConfigService == nil || strings.TrimSpace(dir) == "" {
+		return false
+	}
+	status, err := a.repoConfigService.Status(dir)
+	if err != nil {
+		slog.Debug("[DEBUG-ENV] trust lookup failed", "dir", dir, "error", err)
+		return false
+	}
+	return status.Level == repoconfig.LevelTrusted
+}
+
+// GetSessionToolPaths reports the tool directories prepended to the PATH of
+// new panes in sessionName and the manifests that caused them.
+// Wails-bound: called from the frontend.
+func (a *App) GetSessionToolPaths(sessionName string) (SessionToolPaths, error) {
+	sessionName = strings.TrimSpace(sessionName)
+	if sessionName == "" {
+		return SessionToolPaths{}, errors.New("session name is required")
+	}
+	if _, err := a.sessionService.ResolveSessionWorkDir(sessionName); err != nil {
+		return SessionToolPaths{}, err
+	}
+	return a.detectSessionToolPaths(sessionName, ""), nil
+}
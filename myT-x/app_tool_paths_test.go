@@ -0,0 +1,83 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"myT-x/internal/config"
+	"myT-x/internal/repoconfig"
+	"myT-x/internal/tmux"
+)
+
+func TestResolvePaneToolPaths(t *testing.T) {
+	root := t.TempDir()
+	nodeBin := filepath.Join(root, "node_modules", ".bin")
+	if err := os.MkdirAll(nodeBin, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "package.json"), []byte("{}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	app := NewApp()
+	cfg := config.DefaultConfig()
+	app.configState.Initialize(newConfigPathForAPITest(t, "config.yaml"), cfg)
+	app.sessions = tmux.NewSessionManager()
+	if _, _, err := app.sessions.CreateSession("web", "0", 120, 40); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.sessions.SetRootPath("web", root); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := app.resolvePaneToolPaths("web", root); got != nil {
+		t.Fatalf("resolvePaneToolPaths() without tool_paths = %v, want nil", got)
+	}
+	report, err := app.GetSessionToolPaths("web")
+	if err != nil || report.Enabled || len(report.Entries) != 0 {
+		t.Fatalf("GetSessionToolPaths() without tool_paths = %+v, %v", report, err)
+	}
+
+	cfg.ToolPaths = &config.ToolPathsConfig{Kinds: []string{config.ToolPathKindNode}}
+	if _, err := app.configState.Save(cfg); err != nil {
+		t.Fatal(err)
+	}
+	// node_modules/.bin of an untrusted repository stays off PATH.
+	if got := app.resolvePaneToolPaths("web", root); got != nil {
+		t.Fatalf("resolvePaneToolPaths() of an untrusted root = %v, want nil", got)
+	}
+	report, err = app.GetSessionToolPaths("web")
+	if err != nil || !report.Enabled || len(report.Entries) != 0 {
+		t.Fatalf("GetSessionToolPaths() of an untrusted root = %+v, %v, want no entries", report, err)
+	}
+	if err := app.SetDirectoryTrust(root, string(repoconfig.LevelUntrusted)); err != nil {
+		t.Fatal(err)
+	}
+	if got := app.resolvePaneToolPaths("web", root); got != nil {
+		t.Fatalf("resolvePaneToolPaths() of a distrusted root = %v, want nil", got)
+	}
+
+	if err := app.SetDirectoryTrust(root, string(repoconfig.LevelTrusted)); err != nil {
+		t.Fatal(err)
+	}
+	// The session root wins over the pane's own directory.
+	if got := app.resolvePaneToolPaths("web", t.TempDir()); !reflect.DeepEqual(got, []string{nodeBin}) {
+		t.Fatalf("resolvePaneToolPaths() = %v, want [%s]", got, nodeBin)
+	}
+	report, err = app.GetSessionToolPaths(" web ")
+	if err != nil {
+		t.Fatalf("GetSessionToolPaths() error = %v", err)
+	}
+	if !report.Enabled || report.Root != root || len(report.Entries) != 1 || report.Entries[0].Dir != nodeBin {
+		t.Fatalf("GetSessionToolPaths() = %+v, want the node entry", report)
+	}
+
+	if _, err := app.GetSessionToolPaths("missing"); err == nil {
+		t.Fatal("GetSessionToolPaths(missing) error = nil")
+	}
+	if _, err := app.GetSessionToolPaths(" "); err == nil {
+		t.Fatal("GetSessionToolPaths(blank) error = nil")
+	}
+}
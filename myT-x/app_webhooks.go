@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+
+	"myT-x/internal/config"
+	"myT-x/internal/webhook"
+	"myT-x/internal/workerutil"
+)
+
+// webhookEndpoints converts the webhooks config into delivery endpoints.
+func webhookEndpoints(cfg config.Config) []webhook.Endpoint {
+	if len(cfg.Webhooks) == 0 {
+		return nil
+	}
+	endpoints := make([]webhook.Endpoint, 0, len(cfg.Webhooks))
+	for _, hook := range cfg.Webhooks {
+		endpoints = append(endpoints, webhook.Endpoint{
+			URL:    hook.URL,
+			Events: hook.SubscribedEvents(),
+			Secret: hook.Secret,
+		})
+	}
+	return endpoints
+}
+
+// startWebhookDelivery starts the background sender for queued webhook
+// deliveries and loads the endpoints of the current config.
+func (a *App) startWebhookDelivery(parent context.Context) {
+	ctx, cancel := context.WithCancel(parent)
+	a.webhooksCancel = cancel
+	workerutil.RunWithPanicRecovery(ctx, "webhooks", &a.bgWG, a.webhookService.Run, a.defaultRecoveryOptions())
+	a.applyRuntimeWebhookUpdate()
+}
+
+// applyRuntimeWebhookUpdate replaces the webhook endpoints. It reads the
+// latest snapshot so concurrent saves converge on the newest config.
+func (a *App) applyRuntimeWebhookUpdate() {
+	a.webhookService.SetEndpoints(webhookEndpoints(a.configState.Snapshot()))
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"myT-x/internal/config"
+)
+
+func TestWebhookEndpoints(t *testing.T) {
+	if got := webhookEndpoints(config.Config{}); got != nil {
+		t.Fatalf("webhookEndpoints(empty) = %v, want nil", got)
+	}
+
+	cfg := config.Config{Webhooks: []config.Webhook{
+		{URL: "https://hooks.example.com/a", Events: []string{"worktree:setup-complete"}, Secret: "s"},
+		{URL: "http://localhost:5678/hook", Events: []string{config.WebhookAllEvents}},
+	}}
+	got := webhookEndpoints(cfg)
+	if len(got) != 2 {
+		t.Fatalf("len(webhookEndpoints) = %d, want 2", len(got))
+	}
+	if got[0].URL != "https://hooks.example.com/a" || got[0].Secret != "s" ||
+		!slices.Equal(got[0].Events, []string{"worktree:setup-complete"}) {
+		t.Fatalf("endpoint[0] = %+v", got[0])
+	}
+	if !slices.Equal(got[1].Events, config.WebhookEvents) {
+		t.Fatalf("endpoint[1].Events = %v, want every webhook event", got[1].Events)
+	}
+}
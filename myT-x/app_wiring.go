@@ -8,23 +8,44 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
+	"myT-x/internal/bringup"
+	"myT-x/internal/cmdapproval"
+	"myT-x/internal/cmdqueue"
 	"myT-x/internal/config"
 	"myT-x/internal/devpanel"
+	"myT-x/internal/ephemeral"
 	gitpkg "myT-x/internal/git"
+	"myT-x/internal/ipc"
+	"myT-x/internal/jumplist"
+	"myT-x/internal/layoutpreset"
 	"myT-x/internal/mcp"
 	"myT-x/internal/mcpapi"
+	"myT-x/internal/metrics"
 	"myT-x/internal/orchestrator"
+	"myT-x/internal/outputspill"
+	"myT-x/internal/outputwatch"
+	"myT-x/internal/paneactivity"
+	"myT-x/internal/preopsnapshot"
 	"myT-x/internal/promptpresets"
+	"myT-x/internal/repoconfig"
 	"myT-x/internal/scheduler"
 	"myT-x/internal/session"
+	"myT-x/internal/sessionbadge"
+	"myT-x/internal/sessiongroup"
 	"myT-x/internal/sessionmemo"
+	"myT-x/internal/sessionports"
+	"myT-x/internal/setupwatch"
 	"myT-x/internal/singletaskrunner"
 	"myT-x/internal/snapshot"
 	"myT-x/internal/taskscheduler"
+	"myT-x/internal/terminal"
 	"myT-x/internal/tmux"
 	"myT-x/internal/usagedashboard"
 	"myT-x/internal/workerutil"
@@ -99,9 +120,17 @@ func buildSessionServiceDeps(app *App) session.Deps {
 		// *App and is always available. Other fields use closure wrappers for nil
 		// guards or parameter adaptation.
 		EmitBackendEvent:              app.emitBackendEvent,
+		OnSessionCreated:              app.applySessionBadges,
 		OnSessionDestroyed:            app.finalizeSessionDestroyed,
 		OnSessionRenamed:              app.handleSessionRenamed,
 		OnSessionRenameRollbackFailed: app.reconcileSessionRenameRollbackFailure,
+		ResolveRepoStartupCommand: func(rootPath string) string {
+			if repoCfg := app.repoConfigService.Resolve(rootPath); repoCfg != nil {
+				return repoCfg.StartupCommand
+			}
+			return ""
+		},
+		CaptureBeforeRiskyOperation: app.captureBeforeRiskyOperation,
 	}
 }
 
@@ -171,6 +200,26 @@ func buildSessionMemoServiceDeps(app *App) sessionmemo.Deps {
 	return sessionmemo.Deps{
 		ResolveSessionWorkDir: app.sessionService.ResolveSessionWorkDir,
 		ConfigDir:             appConfigDirProvider(app),
+		Store:                 app.requireStateStore,
+	}
+}
+
+// buildSessionBadgeServiceDeps constructs the dependency set for the
+// session badge service.
+func buildSessionBadgeServiceDeps(app *App) sessionbadge.Deps {
+	return sessionbadge.Deps{
+		ResolveSessionWorkDir: app.sessionService.ResolveSessionWorkDir,
+		ConfigDir:             appConfigDirProvider(app),
+		Store:                 app.requireStateStore,
+	}
+}
+
+// buildLayoutPresetServiceDeps constructs the dependency set for the
+// layout preset service.
+func buildLayoutPresetServiceDeps(app *App) layoutpreset.Deps {
+	return layoutpreset.Deps{
+		ResolveSessionWorkDir: app.sessionService.ResolveSessionWorkDir,
+		ConfigDir:             appConfigDirProvider(app),
 	}
 }
 
@@ -185,6 +234,7 @@ func buildDevPanelServiceDeps(app *App) devpanel.Deps {
 		ResolveSessionDir: app.sessionService.ResolveSessionDir,
 		IsPathWithinBase:  worktree.IsPathWithinBase,
 		Emitter:           newAppRuntimeEventEmitterAdapter(app),
+		ConfigDir:         appConfigDirProvider(app),
 	}
 }
 
@@ -227,14 +277,16 @@ func buildWorktreeServiceDeps(app *App) worktree.Deps {
 		},
 		FindAvailableSessionName:    app.sessionService.FindAvailableSessionName,
 		ReserveAvailableSessionName: app.sessionService.ReserveAvailableSessionName,
-		CreateSession: func(sessionDir, sessionName string, enableAgentTeam, useClaudeEnv, usePaneEnv bool) (string, error) {
+		CreateSession: func(sessionDir, sessionName string, enableAgentTeam, useClaudeEnv, usePaneEnv, isolateShellHistory bool) (string, error) {
 			return app.sessionService.CreateSessionForDirectory(sessionDir, sessionName, session.CreateSessionOptions{
-				EnableAgentTeam: enableAgentTeam,
-				UseClaudeEnv:    useClaudeEnv,
-				UsePaneEnv:      usePaneEnv,
+				EnableAgentTeam:     enableAgentTeam,
+				UseClaudeEnv:        useClaudeEnv,
+				UsePaneEnv:          usePaneEnv,
+				IsolateShellHistory: isolateShellHistory,
 			})
 		},
 		ApplySessionEnvFlags:   session.ApplySessionEnvFlags,
+		RunStartupCommand:      app.sessionService.RunStartupCommand,
 		ActivateCreatedSession: app.sessionService.ActivateCreatedSession,
 		RollbackCreatedSession: app.sessionService.RollbackCreatedSession,
 		StoreRootPath: func(sessionName, rootPath string) error {
@@ -259,6 +311,10 @@ func buildWorktreeServiceDeps(app *App) worktree.Deps {
 			return app.trackSetupCancel(cancel)
 		},
 		RecoverBackgroundPanic: recoverBackgroundPanic,
+		ResolveRepoConfig: func(repoPath string) *repoconfig.Config {
+			return app.repoConfigService.Resolve(repoPath)
+		},
+		CaptureBeforeRiskyOperation: app.captureBeforeRiskyOperation,
 	}
 }
 
@@ -306,7 +362,18 @@ func buildSnapshotServiceDeps(app *App) snapshot.Deps {
 			}
 			return app.sessions.UpdateActivityByPaneID(paneID)
 		},
+		OnPaneBell:          app.handlePaneBell,
+		OnShellMarkers:      app.handleShellMarkers,
+		OnPaneNotifications: app.handlePaneNotifications,
+		OnPaneOutput:        app.handlePaneOutput,
 		DeliverPaneOutput: func(ctx context.Context, paneID string, data []byte) {
+			if app.outputSpill != nil {
+				app.outputSpill.Write(paneID, data)
+			}
+			// Stream clients tail panes independently of the frontend channel.
+			if app.wsHub != nil {
+				app.wsHub.PublishPaneOutput(paneID, data)
+			}
 			// Prefer WebSocket binary stream for pane data (avoids Wails IPC JSON overhead).
 			// Falls back to Wails IPC when no WebSocket client is connected (e.g. during
 			// startup before frontend establishes the WebSocket channel).
@@ -318,6 +385,11 @@ func buildSnapshotServiceDeps(app *App) snapshot.Deps {
 			// This is an accepted design trade-off: the frontend reconnects via
 			// paneDataStream's exponential backoff, and any missed terminal output
 			// is at most one flush interval worth of data - invisible to users.
+			// Panes the frontend does not render (collapsed, other sessions)
+			// catch up from GetPaneReplay once they are shown again.
+			if app.eventFilter != nil && !app.eventFilter.AllowsPaneOutput(paneID) {
+				return
+			}
 			if app.wsHub != nil && app.wsHub.HasActiveConnection() {
 				app.wsHub.BroadcastPaneData(paneID, data)
 			} else {
@@ -339,9 +411,15 @@ func buildSnapshotServiceDeps(app *App) snapshot.Deps {
 		},
 		PaneStateRetainPanes: func(alive map[string]struct{}) {
 			app.paneStates.RetainPanes(alive)
+			if app.outputSpill != nil {
+				app.outputSpill.Retain(alive)
+			}
 		},
 		PaneStateRemovePane: func(paneID string) {
 			app.paneStates.RemovePane(paneID)
+			if app.outputSpill != nil {
+				app.outputSpill.Remove(paneID)
+			}
 		},
 		HasPaneStates: func() bool { return app.paneStates != nil },
 		LaunchWorker: func(name string, ctx context.Context, fn func(ctx context.Context), opts workerutil.RecoveryOptions) {
@@ -355,6 +433,367 @@ func buildSnapshotServiceDeps(app *App) snapshot.Deps {
 // Scheduler
 // ---------------------------------------------------------------------------
 
+// buildCommandQueueServiceDeps constructs the dependency set for the
+// command queue service, wiring app-layer dependencies.
+func buildCommandQueueServiceDeps(app *App) cmdqueue.Deps {
+	return cmdqueue.Deps{
+		Emitter: newAppRuntimeEventEmitterAdapter(app),
+		CheckPaneAlive: func(paneID string) error {
+			sessions, err := app.requireSessions()
+			if err != nil {
+				return err
+			}
+			if !isPaneAlive(sessions, paneID) {
+				return fmt.Errorf("pane %s does not exist", paneID)
+			}
+			return nil
+		},
+		SendCommand: func(paneID, command string) error {
+			router, err := app.requireRouter()
+			if err != nil {
+				return err
+			}
+			return app.sendKeys.schedulerSendMessage(router, paneID, command)
+		},
+		SendKey: func(paneID, key string) error {
+			router, err := app.requireRouter()
+			if err != nil {
+				return err
+			}
+			return app.sendKeys.sendKey(router, paneID, key)
+		},
+		NewContext: func() (context.Context, context.CancelFunc) {
+			parentCtx := app.runtimeContext()
+			if parentCtx == nil {
+				slog.Warn("[CMDQUEUE] NewContext: runtime context nil, falling back to background context")
+				parentCtx = context.Background()
+			}
+			return context.WithCancel(parentCtx)
+		},
+		LaunchWorker: func(name string, ctx context.Context, fn func(ctx context.Context), opts workerutil.RecoveryOptions) {
+			workerutil.RunWithPanicRecovery(ctx, name, &app.bgWG, fn, opts)
+		},
+		BaseRecoveryOptions: app.defaultRecoveryOptions,
+	}
+}
+
+// buildOutputWatchServiceDeps constructs the dependency set for the output
+// watch service, wiring app-layer dependencies.
+func buildOutputWatchServiceDeps(app *App) outputwatch.Deps {
+	return outputwatch.Deps{
+		SessionName: func(paneID string) (string, error) {
+			sessions, err := app.requireSessions()
+			if err != nil {
+				return "", err
+			}
+			paneCtx, err := paneContextSnapshot(sessions, paneID)
+			if err != nil {
+				return "", err
+			}
+			return paneCtx.SessionName, nil
+		},
+		OnMatch: app.handleOutputWatchMatch,
+	}
+}
+
+// buildBringUpServiceDeps constructs the dependency set for the session
+// bring-up service, wiring app-layer dependencies.
+func buildBringUpServiceDeps(app *App) bringup.Deps {
+	return bringup.Deps{
+		Emitter: newAppRuntimeEventEmitterAdapter(app),
+		Templates: func() []config.SessionTemplate {
+			return app.configState.Snapshot().SessionTemplates
+		},
+		SessionExists: func(name string) bool {
+			_, err := app.sessionService.FindSessionSnapshotByName(name)
+			return err == nil
+		},
+		CreateSession: func(template config.SessionTemplate) error {
+			_, err := app.createSessionFromTemplate(template, "")
+			return err
+		},
+		KillSession: func(name string) error {
+			return app.sessionService.KillSession(name, false)
+		},
+		NewContext: func() (context.Context, context.CancelFunc) {
+			parentCtx := app.runtimeContext()
+			if parentCtx == nil {
+				slog.Warn("[BRINGUP] NewContext: runtime context nil, falling back to background context")
+				parentCtx = context.Background()
+			}
+			return context.WithCancel(parentCtx)
+		},
+		LaunchWorker: func(name string, ctx context.Context, fn func(ctx context.Context), opts workerutil.RecoveryOptions) {
+			workerutil.RunWithPanicRecovery(ctx, name, &app.bgWG, fn, opts)
+		},
+		BaseRecoveryOptions: app.defaultRecoveryOptions,
+	}
+}
+
+// buildSessionGroupServiceDeps constructs the dependency set for the session
+// group service, wiring app-layer dependencies.
+func buildSessionGroupServiceDeps(app *App) sessiongroup.Deps {
+	return sessiongroup.Deps{
+		RequireSessions: app.requireSessions,
+		CreateMember:    app.createSessionGroupMember,
+		KillSession:     app.sessionService.KillSession,
+		RequestSnapshot: func() {
+			app.snapshotService.RequestSnapshot(false)
+		},
+	}
+}
+
+// buildSessionPortsServiceDeps constructs the dependency set for the
+// session port service, wiring app-layer dependencies.
+func buildSessionPortsServiceDeps(app *App) sessionports.Deps {
+	return sessionports.Deps{
+		Emitter: newAppRuntimeEventEmitterAdapter(app),
+		PaneRoots: func() []sessionports.PaneRoot {
+			var roots []sessionports.PaneRoot
+			app.forEachLivePane(func(sessionName string, pane tmux.PanePIDInfo) {
+				roots = append(roots, sessionports.PaneRoot{
+					SessionName: sessionName,
+					PaneID:      pane.PaneID,
+					PID:         pane.PID,
+				})
+			})
+			return roots
+		},
+		Paused: app.systemSuspended.Load,
+	}
+}
+
+// buildPaneActivityServiceDeps constructs the dependency set for the pane
+// activity service, wiring app-layer dependencies.
+func buildOutputSpillDeps(app *App) outputspill.Deps {
+	return outputspill.Deps{
+		Attended: app.paneOutputAttended,
+		Seed: func(paneID string) []byte {
+			return []byte(app.paneStates.Replay(paneID))
+		},
+		OnResume: func(paneIDs []string) {
+			app.emitRuntimeEvent("pane:output-spilled", paneIDs)
+		},
+	}
+}
+
+func buildPaneActivityServiceDeps(app *App) paneactivity.Deps {
+	return paneactivity.Deps{
+		Emitter: newAppRuntimeEventEmitterAdapter(app),
+		Panes: func() []paneactivity.Pane {
+			var panes []paneactivity.Pane
+			app.forEachLivePane(func(sessionName string, pane tmux.PanePIDInfo) {
+				panes = append(panes, paneactivity.Pane{
+					SessionName: sessionName,
+					PaneID:      pane.PaneID,
+					PID:         pane.PID,
+				})
+			})
+			return panes
+		},
+		ChildProcesses: paneChildProcesses,
+		Paused:         app.systemSuspended.Load,
+	}
+}
+
+// buildGitStatusWatcherDeps constructs the dependency set for the git
+// status watcher, wiring app-layer dependencies.
+func buildGitStatusWatcherDeps(app *App) gitpkg.StatusWatcherDeps {
+	return gitpkg.StatusWatcherDeps{
+		Emitter: newAppRuntimeEventEmitterAdapter(app),
+		Targets: func() []gitpkg.StatusTarget {
+			sessions, err := app.requireSessions()
+			if err != nil {
+				return nil
+			}
+			var targets []gitpkg.StatusTarget
+			for _, snapshot := range sessions.Snapshot() {
+				workDir, err := app.sessionService.ResolveSessionWorkDir(snapshot.Name)
+				if err != nil || workDir == "" {
+					continue
+				}
+				targets = append(targets, gitpkg.StatusTarget{SessionName: snapshot.Name, Dir: workDir})
+			}
+			return targets
+		},
+		Paused: app.systemSuspended.Load,
+		OnPoll: func(statuses map[string]gitpkg.WorkingTreeStatus) {
+			app.worktreeService.WarnSharedBranchConflicts(statuses)
+			app.worktreeService.DetectExternalWorktrees()
+		},
+	}
+}
+
+// buildSetupWatchServiceDeps constructs the dependency set for the setup
+// watch service. Only worktree sessions are watched, and only while worktrees
+// are enabled.
+func buildSetupWatchServiceDeps(app *App) setupwatch.Deps {
+	return setupwatch.Deps{
+		Targets: func() []setupwatch.Target {
+			if !config.Read(app.configState, func(cfg *config.Config) bool { return cfg.Worktree.Enabled }) {
+				return nil
+			}
+			sessions, err := app.requireSessions()
+			if err != nil {
+				return nil
+			}
+			var targets []setupwatch.Target
+			for _, snapshot := range sessions.Snapshot() {
+				if snapshot.Worktree == nil || snapshot.Worktree.Path == "" {
+					continue
+				}
+				targets = append(targets, setupwatch.Target{SessionName: snapshot.Name, Dir: snapshot.Worktree.Path})
+			}
+			return targets
+		},
+		Rules: func() []setupwatch.Rule {
+			return config.Read(app.configState, func(cfg *config.Config) []setupwatch.Rule {
+				rules := make([]setupwatch.Rule, 0, len(cfg.Worktree.SetupWatchers))
+				for _, watcher := range cfg.Worktree.SetupWatchers {
+					rules = append(rules, setupwatch.Rule{Script: watcher.Script, Files: slices.Clone(watcher.Files)})
+				}
+				return rules
+			})
+		},
+		RunScript:    app.worktreeService.RunSetupScript,
+		SetupRunning: app.worktreeService.SetupRunning,
+		Emitter:      newAppRuntimeEventEmitterAdapter(app),
+	}
+}
+
+// buildEphemeralServiceDeps constructs the dependency set for the ephemeral
+// session service. Kills delete the worktree; the session's destroy hooks
+// remove its temp directory and history through the lifecycle participant.
+func buildEphemeralServiceDeps(app *App) ephemeral.Deps {
+	return ephemeral.Deps{
+		Store: app.requireStateStore,
+		SessionExists: func(sessionName string) bool {
+			sessions, err := app.requireSessions()
+			return err == nil && sessions.HasSession(sessionName)
+		},
+		KillSession: func(sessionName string) error {
+			return app.sessionService.KillSession(sessionName, true)
+		},
+		RemoveWorktree: func(sessionName string, wt ephemeral.Worktree) {
+			app.sessionService.CleanupSessionWorktree(session.WorktreeCleanupParams{
+				SessionName: sessionName,
+				WtPath:      wt.Path,
+				RepoPath:    wt.RepoPath,
+				BranchName:  wt.BranchName,
+			})
+		},
+		RemoveSessionData: app.removeEphemeralSessionData,
+		Emitter:           newAppRuntimeEventEmitterAdapter(app),
+	}
+}
+
+// buildMetricsDeps constructs the sampled values of the metrics registry.
+func buildMetricsDeps(app *App) metrics.Deps {
+	return metrics.Deps{
+		Counts: func() (int, int) {
+			sessions, err := app.requireSessions()
+			if err != nil {
+				return 0, 0
+			}
+			return sessions.Counts()
+		},
+		TerminalIO: terminal.IOTotals,
+	}
+}
+
+// buildCommandApprovalDeps constructs the dependency set for the shim
+// command approval gate. Allowed commands go to the tmux command router.
+func buildCommandApprovalDeps(app *App) cmdapproval.Deps {
+	return cmdapproval.Deps{
+		Next: func(req ipc.TmuxRequest) ipc.TmuxResponse {
+			router, err := app.requireRouter()
+			if err != nil {
+				return ipc.TmuxResponse{ExitCode: 1, Stderr: err.Error() + "\n"}
+			}
+			started := time.Now()
+			resp := router.Execute(req)
+			app.recordIPCRequest(req.Command, resp.ExitCode, started)
+			return resp
+		},
+		NextStream: func(ctx context.Context, req ipc.TmuxRequest, stdout, stderr io.Writer) ipc.TmuxResponse {
+			router, err := app.requireRouter()
+			if err != nil {
+				return ipc.TmuxResponse{ExitCode: 1, Stderr: err.Error() + "\n"}
+			}
+			started := time.Now()
+			resp := router.ExecuteStream(ctx, req, stdout, stderr)
+			app.recordIPCRequest(req.Command, resp.ExitCode, started)
+			return resp
+		},
+		SessionForPane: func(callerPane string) (string, bool) {
+			sessions, err := app.requireSessions()
+			if err != nil {
+				return "", false
+			}
+			paneID := tmux.ParseCallerPane(callerPane)
+			if paneID < 0 {
+				return "", false
+			}
+			paneCtx, err := sessions.GetPaneContextSnapshot(paneID)
+			if err != nil {
+				return "", false
+			}
+			return paneCtx.SessionName, true
+		},
+		PaneForPID: commandApprovalPaneForPID(app),
+		Emitter:    newAppRuntimeEventEmitterAdapter(app),
+		OnResolved: app.recordCommandApproval,
+	}
+}
+
+// commandApprovalPaneForPID maps a shim client process to its pane through
+// the pane process trees. It is nil where process snapshots are unavailable,
+// leaving the gate with the client-supplied caller pane.
+func commandApprovalPaneForPID(app *App) func(pid uint32) (string, bool) {
+	if !sessionports.ProcessTreeSupported {
+		return nil
+	}
+	return func(pid uint32) (string, bool) {
+		if app.sessionPortsService == nil {
+			return "", false
+		}
+		root, ok := app.sessionPortsService.PaneForProcess(pid)
+		return root.PaneID, ok
+	}
+}
+
+// buildPreOpSnapshotServiceDeps constructs the dependency set for the
+// pre-operation snapshot service. The audit log lives in the config directory.
+func buildPreOpSnapshotServiceDeps(app *App) preopsnapshot.Deps {
+	return preopsnapshot.Deps{
+		CaptureSession: app.capturePreOpSessionState,
+		GitStatus:      preOpGitStatus,
+		AuditLogDir:    app.configDirProvider,
+	}
+}
+
+// buildJumpListServiceDeps constructs the dependency set for the jump list
+// service, reading session names and the active session from the session service.
+func buildJumpListServiceDeps(app *App) jumplist.Deps {
+	return jumplist.Deps{
+		SessionNames: func() []string {
+			sessions, err := app.requireSessions()
+			if err != nil {
+				return nil
+			}
+			snapshots := sessions.Snapshot()
+			names := make([]string, 0, len(snapshots))
+			for _, snapshot := range snapshots {
+				names = append(names, snapshot.Name)
+			}
+			return names
+		},
+		ActiveSession: func() string {
+			return app.sessionService.GetActiveSessionName()
+		},
+	}
+}
+
 // buildSchedulerServiceDeps constructs the dependency set for the
 // scheduler service, wiring app-layer dependencies.
 func buildSchedulerServiceDeps(app *App) scheduler.Deps {
@@ -362,7 +801,8 @@ func buildSchedulerServiceDeps(app *App) scheduler.Deps {
 		Emitter:        newAppRuntimeEventEmitterAdapter(app),
 		IsShuttingDown: func() bool { return app.shuttingDown.Load() },
 		IsPaneQuiet: func(paneID string) bool {
-			return app.snapshotService.IsPaneQuiet(paneID)
+			// Defer delivery around system sleep as if the pane were busy.
+			return !app.systemSuspended.Load() && app.snapshotService.IsPaneQuiet(paneID)
 		},
 		CheckPaneAlive: func(paneID string) error {
 			sessions, err := app.requireSessions()
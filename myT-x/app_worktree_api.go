@@ -1,6 +1,7 @@
 package main
 
 import (
+	"myT-x/internal/forge"
 	gitpkg "myT-x/internal/git"
 	"myT-x/internal/tmux"
 	"myT-x/internal/worktree"
@@ -17,7 +18,18 @@ func (a *App) CreateSessionWithWorktree(
 	sessionName string,
 	opts WorktreeSessionOptions,
 ) (tmux.SessionSnapshot, error) {
-	return a.worktreeService.CreateSessionWithWorktree(repoPath, sessionName, opts)
+	snapshot, err := a.worktreeService.CreateSessionWithWorktree(repoPath, sessionName, opts)
+	a.recordWorktreeOp(worktreeOpCreate, err)
+	if err != nil {
+		return snapshot, err
+	}
+	a.recordRecentDirectory(repoPath)
+	if opts.Ephemeral {
+		if err := a.registerEphemeralSession(snapshot, "", false); err != nil {
+			return tmux.SessionSnapshot{}, err
+		}
+	}
+	return snapshot, nil
 }
 
 // CreateSessionWithExistingWorktree creates a session using an existing worktree.
@@ -28,18 +40,93 @@ func (a *App) CreateSessionWithExistingWorktree(
 	worktreePath string,
 	opts CreateSessionOptions,
 ) (tmux.SessionSnapshot, error) {
-	return a.worktreeService.CreateSessionWithExistingWorktree(repoPath, sessionName, worktreePath, worktree.SessionEnvOptions{
+	snapshot, err := a.worktreeService.CreateSessionWithExistingWorktree(repoPath, sessionName, worktreePath, toWorktreeEnvOptions(opts))
+	a.recordWorktreeOp(worktreeOpAttach, err)
+	if err == nil {
+		a.recordRecentDirectory(repoPath)
+	}
+	return snapshot, err
+}
+
+// ListExternalWorktrees returns the worktrees of repoPath that were created
+// outside myT-x (e.g. by `git worktree add`) and that no session uses.
+// Wails-bound: called from the frontend.
+func (a *App) ListExternalWorktrees(repoPath string) ([]worktree.ExternalWorktree, error) {
+	return a.worktreeService.ListExternalWorktrees(repoPath)
+}
+
+// AdoptExternalWorktree opens an external worktree as a worktree session.
+// An empty sessionName defaults to the worktree's branch.
+// Wails-bound: called from the frontend.
+func (a *App) AdoptExternalWorktree(
+	repoPath string,
+	worktreePath string,
+	sessionName string,
+	opts CreateSessionOptions,
+) (tmux.SessionSnapshot, error) {
+	snapshot, err := a.worktreeService.AdoptExternalWorktree(repoPath, worktreePath, sessionName, toWorktreeEnvOptions(opts))
+	a.recordWorktreeOp(worktreeOpAdopt, err)
+	if err == nil {
+		a.recordRecentDirectory(repoPath)
+	}
+	return snapshot, err
+}
+
+// toWorktreeEnvOptions maps the session options of an existing-worktree
+// session to the worktree service.
+func toWorktreeEnvOptions(opts CreateSessionOptions) worktree.SessionEnvOptions {
+	return worktree.SessionEnvOptions{
 		EnableAgentTeam:     opts.EnableAgentTeam,
 		UseClaudeEnv:        opts.UseClaudeEnv,
 		UsePaneEnv:          opts.UsePaneEnv,
 		UseSessionPaneScope: opts.UseSessionPaneScope,
-	})
+		StartupCommand:      opts.StartupCommand,
+		RemainOnExit:        opts.RemainOnExit,
+		IsolateShellHistory: opts.IsolateShellHistory,
+	}
 }
 
 // CleanupWorktree manually removes the worktree associated with a session.
 // Wails-bound: called from the frontend.
 func (a *App) CleanupWorktree(sessionName string) error {
-	return a.worktreeService.CleanupWorktree(sessionName)
+	err := a.worktreeService.CleanupWorktree(sessionName)
+	a.recordWorktreeOp(worktreeOpCleanup, err)
+	return err
+}
+
+// StashWorktree stashes the changes of the session's worktree, e.g. before
+// CleanupWorktree or switching branches.
+// Wails-bound: called from the frontend.
+func (a *App) StashWorktree(sessionName, message string) (gitpkg.StashEntry, error) {
+	entry, err := a.worktreeService.StashWorktree(sessionName, message)
+	a.recordWorktreeOp(worktreeOpStash, err)
+	return entry, err
+}
+
+// PopWorktreeStash restores the newest stash StashWorktree created for the
+// session. Conflicts roll the worktree back and are reported in the result.
+// Wails-bound: called from the frontend.
+func (a *App) PopWorktreeStash(sessionName string) (WorktreeStashPopResult, error) {
+	result, err := a.worktreeService.PopWorktreeStash(sessionName)
+	a.recordWorktreeOp(worktreeOpStashPop, err)
+	return result, err
+}
+
+// CheckWorktreeBase fetches the base branch of the session's worktree and
+// reports how far the worktree branch is behind it.
+// Wails-bound: called from the frontend.
+func (a *App) CheckWorktreeBase(sessionName string) (BaseBranchStatus, error) {
+	return a.worktreeService.CheckBaseBranch(sessionName)
+}
+
+// RefreshWorktreeBase rebases the session's branch onto its base branch or
+// merges the base branch into it (strategy "rebase" or "merge"). Conflicts
+// abort the operation and are reported in the result.
+// Wails-bound: called from the frontend.
+func (a *App) RefreshWorktreeBase(sessionName, strategy string) (BaseRefreshResult, error) {
+	result, err := a.worktreeService.RefreshBaseBranch(sessionName, strategy)
+	a.recordWorktreeOp(worktreeOpRefreshBase, err)
+	return result, err
 }
 
 // CheckWorktreeStatus returns the worktree status for a session.
@@ -48,16 +135,36 @@ func (a *App) CheckWorktreeStatus(sessionName string) (WorktreeStatus, error) {
 	return a.worktreeService.CheckWorktreeStatus(sessionName)
 }
 
+// SetWorktreeLock locks or unlocks the worktree of a session with git worktree lock.
+// Wails-bound: called from the frontend.
+func (a *App) SetWorktreeLock(sessionName string, locked bool, reason string) error {
+	return a.worktreeService.SetWorktreeLock(sessionName, locked, reason)
+}
+
 // CommitAndPushWorktree commits and/or pushes changes in the session's worktree.
+// With forge.create_on_push, a push also opens a pull request, reported in
+// the result.
+// Wails-bound: called from the frontend.
+func (a *App) CommitAndPushWorktree(sessionName, commitMessage string, push bool) (CommitAndPushResult, error) {
+	result, err := a.worktreeService.CommitAndPushWorktree(sessionName, commitMessage, push)
+	a.recordWorktreeOp(worktreeOpCommitPush, err)
+	return result, err
+}
+
+// CreatePullRequestForSession opens a pull request (GitHub) or merge request
+// (GitLab) for the pushed branch of the session's worktree.
 // Wails-bound: called from the frontend.
-func (a *App) CommitAndPushWorktree(sessionName, commitMessage string, push bool) error {
-	return a.worktreeService.CommitAndPushWorktree(sessionName, commitMessage, push)
+func (a *App) CreatePullRequestForSession(sessionName string, opts CreatePullRequestOptions) (forge.PullRequest, error) {
+	return a.worktreeService.CreatePullRequestForSession(sessionName, opts)
 }
 
-// PromoteWorktreeToBranch promotes a detached HEAD worktree to a named branch.
+// PromoteWorktreeToBranch promotes a detached HEAD worktree to a named branch,
+// optionally rebasing it onto the base branch first.
 // Wails-bound: called from the frontend.
-func (a *App) PromoteWorktreeToBranch(sessionName string, branchName string) error {
-	return a.worktreeService.PromoteWorktreeToBranch(sessionName, branchName)
+func (a *App) PromoteWorktreeToBranch(sessionName string, branchName string, opts PromoteWorktreeOptions) error {
+	err := a.worktreeService.PromoteWorktreeToBranch(sessionName, branchName, opts)
+	a.recordWorktreeOp(worktreeOpPromote, err)
+	return err
 }
 
 // ListWorktreesByRepo returns all worktree information for a given repository.
@@ -97,3 +204,27 @@ func (a *App) CheckWorktreePathConflict(worktreePath string) string {
 func (a *App) ListOrphanedWorktrees(repoPath string) ([]worktree.OrphanedWorktree, error) {
 	return a.worktreeService.ListOrphanedWorktrees(repoPath)
 }
+
+// PruneOrphanedWorktrees removes the orphans reported by ListOrphanedWorktrees.
+// With dryRun the result lists what would be removed and nothing is touched.
+// Wails-bound: called from the frontend.
+func (a *App) PruneOrphanedWorktrees(repoPath string, dryRun bool) (OrphanPruneResult, error) {
+	result, err := a.worktreeService.PruneOrphanedWorktrees(repoPath, dryRun)
+	if !dryRun {
+		a.recordWorktreeOp(worktreeOpPrune, err)
+	}
+	return result, err
+}
+
+// GetRepoHygiene reports stale branches, prunable worktrees, large untracked
+// files, and old stashes of the repository at repoPath.
+// Wails-bound: called from the frontend.
+func (a *App) GetRepoHygiene(repoPath string) (RepoHygiene, error) {
+	return a.worktreeService.GetRepoHygiene(repoPath)
+}
+
+// CleanupRepoHygiene removes the selected GetRepoHygiene items in one batch.
+// Wails-bound: called from the frontend.
+func (a *App) CleanupRepoHygiene(repoPath string, req RepoHygieneCleanup) (RepoHygieneCleanupResult, error) {
+	return a.worktreeService.CleanupRepoHygiene(repoPath, req)
+}
@@ -318,7 +318,7 @@ func TestPromoteWorktreeToBranchSuccess(t *testing.T) {
 		t.Fatalf("SetWorktreeInfo() error = %v", err)
 	}
 
-	if err := app.PromoteWorktreeToBranch("session-a", "feature/promoted"); err != nil {
+	if err := app.PromoteWorktreeToBranch("session-a", "feature/promoted", PromoteWorktreeOptions{}); err != nil {
 		t.Fatalf("PromoteWorktreeToBranch() error = %v", err)
 	}
 
@@ -372,7 +372,7 @@ func TestCommitAndPushWorktreeSuccess(t *testing.T) {
 		t.Fatalf("SetWorktreeInfo() error = %v", err)
 	}
 
-	if err := app.CommitAndPushWorktree("session-a", "add feature file", true); err != nil {
+	if _, err := app.CommitAndPushWorktree("session-a", "add feature file", true); err != nil {
 		t.Fatalf("CommitAndPushWorktree() error = %v", err)
 	}
 
@@ -387,6 +387,40 @@ func TestCommitAndPushWorktreeSuccess(t *testing.T) {
 	}
 }
 
+func TestCommitAndPushWorktreeUsesGitIdentity(t *testing.T) {
+	testutil.SkipIfNoGit(t)
+
+	repoPath := testutil.CreateTempGitRepo(t)
+	if err := os.WriteFile(filepath.Join(repoPath, "identity.txt"), []byte("identity"), 0o644); err != nil {
+		t.Fatalf("write identity.txt: %v", err)
+	}
+
+	app := NewApp()
+	cfg := config.DefaultConfig()
+	cfg.GitIdentities = []config.GitIdentity{
+		{Session: "oss-*", Name: "OSS Identity", Email: "oss@example.com"},
+	}
+	app.configState.Initialize(filepath.Join(t.TempDir(), "config.yaml"), cfg)
+	app.sessions = tmux.NewSessionManager()
+	if _, _, err := app.sessions.CreateSession("oss-api", "0", 120, 40); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	if err := app.sessions.SetWorktreeInfo("oss-api", &tmux.SessionWorktreeInfo{
+		Path:     repoPath,
+		RepoPath: repoPath,
+	}); err != nil {
+		t.Fatalf("SetWorktreeInfo() error = %v", err)
+	}
+
+	if _, err := app.CommitAndPushWorktree("oss-api", "commit as oss identity", false); err != nil {
+		t.Fatalf("CommitAndPushWorktree() error = %v", err)
+	}
+
+	if got := runGitInDir(t, repoPath, "log", "-1", "--format=%an <%ae>|%cn <%ce>"); got != "OSS Identity <oss@example.com>|OSS Identity <oss@example.com>" {
+		t.Fatalf("commit identity = %q", got)
+	}
+}
+
 func TestCommitAndPushWorktreePushOnlyWhenCommitMessageEmpty(t *testing.T) {
 	testutil.SkipIfNoLocalGitTransport(t)
 
@@ -427,7 +461,7 @@ func TestCommitAndPushWorktreePushOnlyWhenCommitMessageEmpty(t *testing.T) {
 		t.Fatalf("SetWorktreeInfo() error = %v", err)
 	}
 
-	if err := app.CommitAndPushWorktree("session-a", "   ", true); err != nil {
+	if _, err := app.CommitAndPushWorktree("session-a", "   ", true); err != nil {
 		t.Fatalf("CommitAndPushWorktree() push-only error = %v", err)
 	}
 
@@ -1298,7 +1332,7 @@ func TestWorktreePublicAPIsValidation(t *testing.T) {
 			t.Fatalf("CreateSession() error = %v", err)
 		}
 
-		if err := app.PromoteWorktreeToBranch("session-a", "feature/new"); err == nil {
+		if err := app.PromoteWorktreeToBranch("session-a", "feature/new", PromoteWorktreeOptions{}); err == nil {
 			t.Fatal("PromoteWorktreeToBranch() expected no-worktree error")
 		}
 	})
@@ -1310,11 +1344,26 @@ func TestWorktreePublicAPIsValidation(t *testing.T) {
 			t.Fatalf("CreateSession() error = %v", err)
 		}
 
-		if err := app.CommitAndPushWorktree("session-a", "message", true); err == nil {
+		if _, err := app.CommitAndPushWorktree("session-a", "message", true); err == nil {
 			t.Fatal("CommitAndPushWorktree() expected no-worktree error")
 		}
 	})
 
+	t.Run("StashWorktree and PopWorktreeStash return error when session has no worktree", func(t *testing.T) {
+		app := NewApp()
+		app.sessions = tmux.NewSessionManager()
+		if _, _, err := app.sessions.CreateSession("session-a", "0", 120, 40); err != nil {
+			t.Fatalf("CreateSession() error = %v", err)
+		}
+
+		if _, err := app.StashWorktree("session-a", "wip"); err == nil {
+			t.Fatal("StashWorktree() expected no-worktree error")
+		}
+		if _, err := app.PopWorktreeStash("session-a"); err == nil {
+			t.Fatal("PopWorktreeStash() expected no-worktree error")
+		}
+	})
+
 	t.Run("ListWorktreesByRepo returns error for non-git directory", func(t *testing.T) {
 		app := NewApp()
 		if _, err := app.ListWorktreesByRepo(t.TempDir()); err == nil {
@@ -1344,10 +1393,10 @@ func TestWorktreePublicAPIsValidation(t *testing.T) {
 func TestWorktreePublicAPIsRejectEmptySessionName(t *testing.T) {
 	app := NewApp()
 
-	if err := app.PromoteWorktreeToBranch("   ", "feature/new"); err == nil {
+	if err := app.PromoteWorktreeToBranch("   ", "feature/new", PromoteWorktreeOptions{}); err == nil {
 		t.Fatal("PromoteWorktreeToBranch() expected session-name validation error")
 	}
-	if err := app.CommitAndPushWorktree("   ", "message", true); err == nil {
+	if _, err := app.CommitAndPushWorktree("   ", "message", true); err == nil {
 		t.Fatal("CommitAndPushWorktree() expected session-name validation error")
 	}
 	if _, err := app.CheckWorktreeStatus("   "); err == nil {
@@ -1377,7 +1426,7 @@ func TestPromoteWorktreeToBranchRejectsInvalidBranchName(t *testing.T) {
 		t.Fatalf("SetWorktreeInfo() error = %v", err)
 	}
 
-	if err := app.PromoteWorktreeToBranch("session-a", "invalid branch name"); err == nil {
+	if err := app.PromoteWorktreeToBranch("session-a", "invalid branch name", PromoteWorktreeOptions{}); err == nil {
 		t.Fatal("PromoteWorktreeToBranch() expected invalid-branch error")
 	}
 }
@@ -1572,7 +1621,7 @@ func TestWorktreeAPIsRequireSessionManager(t *testing.T) {
 	app := NewApp()
 	app.sessions = nil
 
-	if err := app.PromoteWorktreeToBranch("session-a", "feature/new"); err == nil {
+	if err := app.PromoteWorktreeToBranch("session-a", "feature/new", PromoteWorktreeOptions{}); err == nil {
 		t.Fatal("PromoteWorktreeToBranch() expected session manager availability error")
 	}
 	if err := app.CleanupWorktree("session-a"); err == nil {
@@ -1581,7 +1630,7 @@ func TestWorktreeAPIsRequireSessionManager(t *testing.T) {
 	if _, err := app.CheckWorktreeStatus("session-a"); err == nil {
 		t.Fatal("CheckWorktreeStatus() expected session manager availability error")
 	}
-	if err := app.CommitAndPushWorktree("session-a", "message", true); err == nil {
+	if _, err := app.CommitAndPushWorktree("session-a", "message", true); err == nil {
 		t.Fatal("CommitAndPushWorktree() expected session manager availability error")
 	}
 	if got := app.sessionService.FindSessionByWorktreePath(`C:\Projects\myapp.wt\feature`); got != "" {
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+
+	"myT-x/internal/config"
+	"myT-x/internal/workerutil"
+)
+
+// launchBaseRefreshOnFocus applies the worktree.base_refresh policy to a
+// session that was just activated. Fetching the base branch can take a
+// while, so the check runs in the background.
+func (a *App) launchBaseRefreshOnFocus(sessionName string) {
+	if a.worktreeService == nil || a.configState == nil {
+		return
+	}
+	onFocus := config.Read(a.configState, func(cfg *config.Config) bool {
+		return cfg.Worktree.Enabled && cfg.Worktree.BaseRefresh != nil && cfg.Worktree.BaseRefresh.OnFocus
+	})
+	if !onFocus {
+		return
+	}
+	parentCtx := a.runtimeContext()
+	if parentCtx == nil {
+		slog.Debug("[DEBUG-GIT] runtime context nil, skipping base branch check", "session", sessionName)
+		return
+	}
+	opts := a.defaultRecoveryOptions()
+	opts.MaxRetries = 1 // A later activation checks again.
+	workerutil.RunWithPanicRecovery(parentCtx, "base-refresh-focus", &a.bgWG, func(context.Context) {
+		a.worktreeService.RefreshBaseOnFocus(sessionName)
+	}, opts)
+}
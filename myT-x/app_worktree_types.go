@@ -11,5 +11,15 @@ import (
 // discover them without exposing the internal package directly.
 type WorktreeSessionOptions = worktree.WorktreeSessionOptions
 type WorktreeStatus = worktree.WorktreeStatus
+type WorktreeStashPopResult = worktree.WorktreeStashPopResult
+type PromoteWorktreeOptions = worktree.PromoteWorktreeOptions
+type CommitAndPushResult = worktree.CommitAndPushResult
+type CreatePullRequestOptions = worktree.CreatePullRequestOptions
+type BaseBranchStatus = worktree.BaseBranchStatus
+type BaseRefreshResult = worktree.BaseRefreshResult
 type OrphanedWorktree = worktree.OrphanedWorktree
+type OrphanPruneResult = worktree.OrphanPruneResult
 type WorktreeHealth = gitpkg.WorktreeHealth
+type RepoHygiene = worktree.RepoHygiene
+type RepoHygieneCleanup = worktree.RepoHygieneCleanup
+type RepoHygieneCleanupResult = worktree.RepoHygieneCleanupResult
@@ -4,6 +4,8 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"runtime"
+	"strings"
 	"syscall"
 	"time"
 
@@ -21,7 +23,7 @@ func main() {
 	})
 
 	router := tmux.NewCommandRouter(sessions, emitter, tmux.RouterOptions{
-		DefaultShell: "powershell.exe",
+		DefaultShell: defaultShell(),
 		PipeName:     ipc.DefaultPipeName(),
 		HostPID:      os.Getpid(),
 	})
@@ -42,3 +44,15 @@ func main() {
 	}
 	sessions.Close()
 }
+
+// defaultShell is the shell of new panes: PowerShell on Windows, $SHELL on
+// Linux and macOS.
+func defaultShell() string {
+	if runtime.GOOS == "windows" {
+		return "powershell.exe"
+	}
+	if shell := strings.TrimSpace(os.Getenv("SHELL")); shell != "" {
+		return shell
+	}
+	return "/bin/sh"
+}
@@ -0,0 +1,73 @@
+// Command mytx-ipc-schema writes the IPC protocol of myT-x as an OpenRPC
+// document with JSON Schemas for every frame.
+//
+// Usage:
+//
+//	mytx-ipc-schema [-o file]   write the document (default: stdout)
+//
+// The document is built from the Go definitions the server uses, so it always
+// matches the protocol of the build it ships with. Tools generate clients or
+// validate requests against it instead of reverse-engineering the shim.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime/debug"
+
+	"myT-x/internal/ipcschema"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "mytx-ipc-schema:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("mytx-ipc-schema", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	var outPath string
+	fs.StringVar(&outPath, "o", "", "Output file (default: stdout)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return errors.New("unexpected arguments")
+	}
+
+	doc := ipcschema.Build(buildVersion())
+	if outPath == "" {
+		return writeDocument(stdout, doc)
+	}
+	file, err := os.Create(outPath)
+	if err != nil {
+		return fmt.Errorf("create schema file: %w", err)
+	}
+	if err := writeDocument(file, doc); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func writeDocument(w io.Writer, doc ipcschema.Document) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(doc)
+}
+
+// buildVersion returns the module version of the binary, or "devel" for
+// builds from a working tree.
+func buildVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok || info.Main.Version == "" || info.Main.Version == "(devel)" {
+		return "devel"
+	}
+	return info.Main.Version
+}
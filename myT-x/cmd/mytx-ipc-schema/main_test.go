@@ -0,0 +1,41 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunWritesDocument(t *testing.T) {
+	var stdout bytes.Buffer
+	if err := run(nil, &stdout); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &doc); err != nil {
+		t.Fatalf("stdout is not JSON: %v", err)
+	}
+	if doc["openrpc"] == nil || doc["methods"] == nil {
+		t.Fatalf("document = %v, want openrpc and methods", doc)
+	}
+
+	path := filepath.Join(t.TempDir(), "ipc.json")
+	stdout.Reset()
+	if err := run([]string{"-o", path}, &stdout); err != nil {
+		t.Fatalf("run(-o) error = %v", err)
+	}
+	if stdout.Len() != 0 {
+		t.Fatalf("run(-o) wrote %d bytes to stdout", stdout.Len())
+	}
+	if data, err := os.ReadFile(path); err != nil || !json.Valid(data) {
+		t.Fatalf("output file: valid=%v err=%v", json.Valid(data), err)
+	}
+}
+
+func TestRunRejectsArguments(t *testing.T) {
+	if err := run([]string{"extra"}, &bytes.Buffer{}); err == nil {
+		t.Fatal("run() should reject positional arguments")
+	}
+}
@@ -0,0 +1,28 @@
+//go:build windows
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+func main() {
+	cfg, err := parseCLI(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "mytx-soak: %v\n", err)
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, cfg, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "mytx-soak: FAIL: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Fprintln(os.Stdout, "mytx-soak: PASS")
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	defaultDuration           = 4 * time.Hour
+	defaultWarmup             = 5 * time.Minute
+	defaultSampleInterval     = time.Minute
+	defaultCycleInterval      = 5 * time.Second
+	defaultLogRotateInterval  = 10 * time.Minute
+	defaultConfigToggleEvery  = 2 * time.Minute
+	defaultSessionsPerCycle   = 4
+	defaultOutputLines        = 200
+	defaultMaxHeapGrowthMB    = 64
+	defaultMaxGoroutineGrowth = 50
+	defaultMaxHandleGrowth    = 200
+	defaultViolationsToFail   = 3
+)
+
+// cliConfig holds the soak run parameters.
+type cliConfig struct {
+	duration          time.Duration
+	warmup            time.Duration
+	sampleInterval    time.Duration
+	cycleInterval     time.Duration
+	logRotateInterval time.Duration
+	configToggleEvery time.Duration
+	sessionsPerCycle  int
+	outputLines       int
+	shell             string
+	workDir           string
+	limits            growthLimits
+}
+
+// growthLimits bounds how far a sample may drift from the post-warmup baseline.
+type growthLimits struct {
+	maxHeapGrowthBytes uint64
+	maxGoroutineGrowth int
+	maxHandleGrowth    int
+	// violationsToFail is the number of consecutive over-limit samples that
+	// fail the run; single spikes (GC timing, in-flight cycles) are tolerated.
+	violationsToFail int
+}
+
+// resourceSample is one point-in-time measurement of the process.
+type resourceSample struct {
+	at         time.Time
+	heapBytes  uint64
+	goroutines int
+	// handles is -1 when the platform cannot report a handle count.
+	handles int
+}
+
+func (s resourceSample) String() string {
+	return fmt.Sprintf("heap=%.1fMiB goroutines=%d handles=%d",
+		float64(s.heapBytes)/(1<<20), s.goroutines, s.handles)
+}
+
+func parseCLI(args []string) (cliConfig, error) {
+	fs := flag.NewFlagSet("mytx-soak", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	cfg := cliConfig{}
+	fs.DurationVar(&cfg.duration, "duration", defaultDuration, "Total run time")
+	fs.DurationVar(&cfg.warmup, "warmup", defaultWarmup, "Time before the baseline sample is taken")
+	fs.DurationVar(&cfg.sampleInterval, "sample-interval", defaultSampleInterval, "Resource sampling interval")
+	fs.DurationVar(&cfg.cycleInterval, "cycle-interval", defaultCycleInterval, "Pause between session create/destroy cycles")
+	fs.DurationVar(&cfg.logRotateInterval, "log-rotate-interval", defaultLogRotateInterval, "Session log rotation interval")
+	fs.DurationVar(&cfg.configToggleEvery, "config-toggle-interval", defaultConfigToggleEvery, "Config save/reload interval")
+	fs.IntVar(&cfg.sessionsPerCycle, "sessions", defaultSessionsPerCycle, "Sessions created per cycle")
+	fs.IntVar(&cfg.outputLines, "output-lines", defaultOutputLines, "Lines of pane output generated per session")
+	fs.StringVar(&cfg.shell, "shell", "cmd.exe", "Shell used for soak panes")
+	fs.StringVar(&cfg.workDir, "dir", "", "Working directory for soak artifacts (default: temp dir)")
+	heapMB := fs.Int("max-heap-growth-mb", defaultMaxHeapGrowthMB, "Allowed heap growth over baseline in MiB")
+	fs.IntVar(&cfg.limits.maxGoroutineGrowth, "max-goroutine-growth", defaultMaxGoroutineGrowth, "Allowed goroutine growth over baseline")
+	fs.IntVar(&cfg.limits.maxHandleGrowth, "max-handle-growth", defaultMaxHandleGrowth, "Allowed OS handle growth over baseline")
+	fs.IntVar(&cfg.limits.violationsToFail, "violations", defaultViolationsToFail, "Consecutive over-limit samples that fail the run")
+	if err := fs.Parse(args); err != nil {
+		return cliConfig{}, err
+	}
+	if fs.NArg() > 0 {
+		return cliConfig{}, fmt.Errorf("unexpected arguments: %v", fs.Args())
+	}
+
+	switch {
+	case cfg.duration <= 0:
+		return cliConfig{}, errors.New("-duration must be positive")
+	case cfg.warmup < 0 || cfg.warmup >= cfg.duration:
+		return cliConfig{}, errors.New("-warmup must be non-negative and shorter than -duration")
+	case cfg.sampleInterval <= 0 || cfg.cycleInterval <= 0:
+		return cliConfig{}, errors.New("-sample-interval and -cycle-interval must be positive")
+	case cfg.logRotateInterval <= 0 || cfg.configToggleEvery <= 0:
+		return cliConfig{}, errors.New("-log-rotate-interval and -config-toggle-interval must be positive")
+	case cfg.sessionsPerCycle < 1 || cfg.outputLines < 0:
+		return cliConfig{}, errors.New("-sessions must be at least 1 and -output-lines non-negative")
+	case *heapMB < 1 || cfg.limits.maxGoroutineGrowth < 1 || cfg.limits.maxHandleGrowth < 1:
+		return cliConfig{}, errors.New("growth limits must be positive")
+	case cfg.limits.violationsToFail < 1:
+		return cliConfig{}, errors.New("-violations must be at least 1")
+	}
+	cfg.limits.maxHeapGrowthBytes = uint64(*heapMB) << 20
+	return cfg, nil
+}
+
+// growthViolation describes which limits sample exceeds relative to baseline.
+// It returns nil when the sample is within limits.
+func growthViolation(baseline, sample resourceSample, limits growthLimits) error {
+	var errs []error
+	if sample.heapBytes > baseline.heapBytes && sample.heapBytes-baseline.heapBytes > limits.maxHeapGrowthBytes {
+		errs = append(errs, fmt.Errorf("heap grew %d bytes (limit %d)",
+			sample.heapBytes-baseline.heapBytes, limits.maxHeapGrowthBytes))
+	}
+	if growth := sample.goroutines - baseline.goroutines; growth > limits.maxGoroutineGrowth {
+		errs = append(errs, fmt.Errorf("goroutines grew by %d (limit %d)", growth, limits.maxGoroutineGrowth))
+	}
+	if baseline.handles >= 0 && sample.handles >= 0 {
+		if growth := sample.handles - baseline.handles; growth > limits.maxHandleGrowth {
+			errs = append(errs, fmt.Errorf("handles grew by %d (limit %d)", growth, limits.maxHandleGrowth))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// leakDetector tracks consecutive over-limit samples against a baseline.
+type leakDetector struct {
+	limits     growthLimits
+	baseline   resourceSample
+	hasBase    bool
+	violations int
+}
+
+// observe records sample. The first call sets the baseline. It returns an
+// error once limits have been exceeded for violationsToFail samples in a row.
+func (d *leakDetector) observe(sample resourceSample) error {
+	if !d.hasBase {
+		d.baseline = sample
+		d.hasBase = true
+		return nil
+	}
+	err := growthViolation(d.baseline, sample, d.limits)
+	if err == nil {
+		d.violations = 0
+		return nil
+	}
+	d.violations++
+	if d.violations >= d.limits.violationsToFail {
+		return fmt.Errorf("resource growth over %d consecutive samples (baseline %s, current %s): %w",
+			d.violations, d.baseline, sample, err)
+	}
+	return nil
+}
+
+// outputCommand returns a one-line loop printing n numbered lines in shell.
+func outputCommand(shell string, n int) string {
+	switch strings.ToLower(filepath.Base(shell)) {
+	case "cmd.exe", "cmd":
+		return fmt.Sprintf("for /L %%i in (1,1,%d) do @echo soak line %%i", n)
+	case "powershell.exe", "powershell", "pwsh.exe", "pwsh":
+		return fmt.Sprintf("1..%d | ForEach-Object { \"soak line $_\" }", n)
+	default:
+		return fmt.Sprintf("for i in $(seq 1 %d); do echo \"soak line $i\"; done", n)
+	}
+}
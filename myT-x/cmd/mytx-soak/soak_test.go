@@ -0,0 +1,110 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseCLIDefaults(t *testing.T) {
+	cfg, err := parseCLI(nil)
+	if err != nil {
+		t.Fatalf("parseCLI(nil) error = %v", err)
+	}
+	if cfg.duration != defaultDuration || cfg.warmup != defaultWarmup {
+		t.Fatalf("duration/warmup = %s/%s, want defaults", cfg.duration, cfg.warmup)
+	}
+	if cfg.limits.maxHeapGrowthBytes != defaultMaxHeapGrowthMB<<20 {
+		t.Fatalf("maxHeapGrowthBytes = %d, want %d", cfg.limits.maxHeapGrowthBytes, defaultMaxHeapGrowthMB<<20)
+	}
+}
+
+func TestParseCLIRejectsInvalidValues(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"warmup not shorter than duration", []string{"-duration", "1m", "-warmup", "1m"}},
+		{"zero sessions", []string{"-sessions", "0"}},
+		{"zero violations", []string{"-violations", "0"}},
+		{"non-positive heap limit", []string{"-max-heap-growth-mb", "0"}},
+		{"positional argument", []string{"extra"}},
+		{"unknown flag", []string{"-nope"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseCLI(tt.args); err == nil {
+				t.Fatalf("parseCLI(%v) error = nil, want error", tt.args)
+			}
+		})
+	}
+}
+
+func TestGrowthViolation(t *testing.T) {
+	limits := growthLimits{maxHeapGrowthBytes: 100, maxGoroutineGrowth: 5, maxHandleGrowth: 10, violationsToFail: 1}
+	base := resourceSample{heapBytes: 1000, goroutines: 20, handles: 100}
+
+	if err := growthViolation(base, resourceSample{heapBytes: 1100, goroutines: 25, handles: 110}, limits); err != nil {
+		t.Fatalf("growth at limits error = %v, want nil", err)
+	}
+	if err := growthViolation(base, resourceSample{heapBytes: 500, goroutines: 10, handles: 50}, limits); err != nil {
+		t.Fatalf("shrinking sample error = %v, want nil", err)
+	}
+
+	err := growthViolation(base, resourceSample{heapBytes: 1101, goroutines: 26, handles: 111}, limits)
+	if err == nil {
+		t.Fatal("growth over limits error = nil, want violation")
+	}
+	for _, want := range []string{"heap", "goroutines", "handles"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("error = %q, want mention of %s", err, want)
+		}
+	}
+
+	unknownHandles := resourceSample{heapBytes: 1000, goroutines: 20, handles: -1}
+	if err := growthViolation(unknownHandles, resourceSample{heapBytes: 1000, goroutines: 20, handles: 5000}, limits); err != nil {
+		t.Fatalf("unknown baseline handle count must be ignored, got %v", err)
+	}
+}
+
+func TestLeakDetectorRequiresConsecutiveViolations(t *testing.T) {
+	d := &leakDetector{limits: growthLimits{
+		maxHeapGrowthBytes: 100, maxGoroutineGrowth: 5, maxHandleGrowth: 10, violationsToFail: 2,
+	}}
+	ok := resourceSample{at: time.Now(), heapBytes: 1000, goroutines: 10, handles: 50}
+	spike := ok
+	spike.goroutines = 100
+
+	steps := []struct {
+		sample  resourceSample
+		wantErr bool
+	}{
+		{ok, false},    // baseline
+		{spike, false}, // single spike tolerated
+		{ok, false},    // resets the streak
+		{spike, false},
+		{spike, true},
+	}
+	for i, step := range steps {
+		err := d.observe(step.sample)
+		if (err != nil) != step.wantErr {
+			t.Fatalf("step %d: observe() error = %v, wantErr %v", i, err, step.wantErr)
+		}
+	}
+}
+
+func TestOutputCommand(t *testing.T) {
+	tests := []struct {
+		shell string
+		want  string
+	}{
+		{"cmd.exe", "for /L %i in (1,1,3) do @echo soak line %i"},
+		{"pwsh.exe", `1..3 | ForEach-Object { "soak line $_" }`},
+		{"bash.exe", `for i in $(seq 1 3); do echo "soak line $i"; done`},
+	}
+	for _, tt := range tests {
+		if got := outputCommand(tt.shell, 3); got != tt.want {
+			t.Fatalf("outputCommand(%q) = %q, want %q", tt.shell, got, tt.want)
+		}
+	}
+}
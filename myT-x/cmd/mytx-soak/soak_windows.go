@@ -0,0 +1,312 @@
+//go:build windows
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+
+	"myT-x/internal/apptypes"
+	"myT-x/internal/config"
+	"myT-x/internal/ipc"
+	"myT-x/internal/sessionlog"
+	"myT-x/internal/tmux"
+)
+
+const (
+	outputWaitTimeout = 30 * time.Second
+	outputPollDelay   = 200 * time.Millisecond
+	// settleDelay lets ConPTY teardown and read-loop goroutines finish before
+	// the final sample is compared against the baseline.
+	settleDelay = 10 * time.Second
+)
+
+var (
+	kernel32                  = windows.NewLazySystemDLL("kernel32.dll")
+	procGetProcessHandleCount = kernel32.NewProc("GetProcessHandleCount")
+)
+
+// soakRun holds the in-process app components exercised by the soak loop.
+type soakRun struct {
+	cfg        cliConfig
+	logger     *log.Logger
+	workDir    string
+	configPath string
+	sessions   *tmux.SessionManager
+	router     *tmux.CommandRouter
+	logs       atomic.Pointer[sessionlog.Service]
+	events     atomic.Uint64
+	cycle      int
+}
+
+func run(ctx context.Context, cfg cliConfig, w io.Writer) (retErr error) {
+	s := &soakRun{
+		cfg:    cfg,
+		logger: log.New(w, "[mytx-soak] ", log.LstdFlags|log.Lmsgprefix),
+	}
+
+	s.workDir = cfg.workDir
+	if s.workDir == "" {
+		dir, err := os.MkdirTemp("", "mytx-soak-*")
+		if err != nil {
+			return fmt.Errorf("create work dir: %w", err)
+		}
+		s.workDir = dir
+		defer os.RemoveAll(dir)
+	}
+	s.configPath = filepath.Join(s.workDir, "config.yaml")
+	if _, err := config.Save(s.configPath, config.DefaultConfig()); err != nil {
+		return fmt.Errorf("seed config: %w", err)
+	}
+
+	s.rotateLogs()
+	defer func() {
+		if logs := s.logs.Load(); logs != nil {
+			logs.Close()
+		}
+	}()
+	slog.SetDefault(slog.New(sessionlog.NewTeeHandler(
+		slog.NewTextHandler(io.Discard, nil), slog.LevelWarn,
+		func(ts time.Time, level slog.Level, msg string, group string) {
+			s.writeLog(ts, strings.ToLower(level.String()), msg, group)
+		})))
+
+	s.sessions = tmux.NewSessionManager()
+	defer s.sessions.Close()
+	s.router = tmux.NewCommandRouter(s.sessions, apptypes.EventEmitterFunc(func(string, any) {
+		s.events.Add(1)
+	}), tmux.RouterOptions{
+		DefaultShell: cfg.shell,
+		// A private pipe name keeps soak panes from reaching a running myT-x.
+		PipeName: fmt.Sprintf(`\\.\pipe\myT-x-soak-%d`, os.Getpid()),
+		HostPID:  os.Getpid(),
+	})
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.duration)
+	defer cancel()
+	start := time.Now()
+	warmupDone := time.After(cfg.warmup)
+	s.logger.Printf("started: duration=%s warmup=%s dir=%s initial %s",
+		cfg.duration, cfg.warmup, s.workDir, takeSample())
+
+	detector := &leakDetector{limits: cfg.limits}
+	var sampleTick <-chan time.Time
+	sampleTicker := time.NewTicker(cfg.sampleInterval)
+	defer sampleTicker.Stop()
+	rotateTicker := time.NewTicker(cfg.logRotateInterval)
+	defer rotateTicker.Stop()
+	toggleTicker := time.NewTicker(cfg.configToggleEvery)
+	defer toggleTicker.Stop()
+	cycleTimer := time.NewTimer(0)
+	defer cycleTimer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return s.finish(detector, time.Since(start))
+			}
+			return fmt.Errorf("interrupted after %s", time.Since(start).Round(time.Second))
+		case <-warmupDone:
+			warmupDone = nil
+			s.killAllSessions()
+			baseline := takeSample()
+			detector.observe(baseline)
+			sampleTick = sampleTicker.C
+			s.logger.Printf("baseline %s", baseline)
+		case <-sampleTick:
+			sample := takeSample()
+			s.logger.Printf("sample cycles=%d events=%d %s", s.cycle, s.events.Load(), sample)
+			if err := detector.observe(sample); err != nil {
+				return err
+			}
+		case <-rotateTicker.C:
+			s.rotateLogs()
+		case <-toggleTicker.C:
+			if err := s.toggleConfig(); err != nil {
+				return err
+			}
+		case <-cycleTimer.C:
+			if err := s.runCycle(ctx); err != nil {
+				if ctx.Err() != nil {
+					continue
+				}
+				return fmt.Errorf("cycle %d: %w", s.cycle, err)
+			}
+			cycleTimer.Reset(s.cfg.cycleInterval)
+		}
+	}
+}
+
+// runCycle creates sessionsPerCycle sessions, makes each pane print
+// outputLines lines, waits for the output, and destroys the sessions.
+func (s *soakRun) runCycle(ctx context.Context) error {
+	s.cycle++
+	names := make([]string, 0, s.cfg.sessionsPerCycle)
+	defer func() {
+		for _, name := range names {
+			if s.sessions.HasSession(name) {
+				s.exec("kill-session", map[string]any{"-t": name})
+			}
+		}
+	}()
+
+	for i := range s.cfg.sessionsPerCycle {
+		name := fmt.Sprintf("soak-%d-%d", s.cycle, i)
+		if _, err := s.exec("new-session", map[string]any{"-d": true, "-s": name, "-c": s.workDir}); err != nil {
+			return err
+		}
+		names = append(names, name)
+		if s.cfg.outputLines == 0 {
+			continue
+		}
+		if _, err := s.exec("send-keys", map[string]any{"-t": name},
+			outputCommand(s.cfg.shell, s.cfg.outputLines), "Enter"); err != nil {
+			return err
+		}
+	}
+
+	if s.cfg.outputLines > 0 {
+		want := fmt.Sprintf("soak line %d", s.cfg.outputLines)
+		for _, name := range names {
+			if err := s.waitForOutput(ctx, name, want); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, name := range names {
+		if _, err := s.exec("kill-session", map[string]any{"-t": name}); err != nil {
+			return err
+		}
+	}
+	s.writeLog(time.Now(), "warn", fmt.Sprintf("soak cycle %d completed", s.cycle), "soak")
+	return nil
+}
+
+func (s *soakRun) waitForOutput(ctx context.Context, name, want string) error {
+	deadline := time.Now().Add(outputWaitTimeout)
+	for {
+		out, err := s.exec("capture-pane", map[string]any{"-p": true, "-t": name, "-S": "-"})
+		if err == nil && strings.Contains(out, want) {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("pane %s did not print %q within %s", name, want, outputWaitTimeout)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(outputPollDelay):
+		}
+	}
+}
+
+func (s *soakRun) exec(command string, flags map[string]any, args ...string) (string, error) {
+	resp := s.router.Execute(ipc.TmuxRequest{Command: command, Flags: flags, Args: args})
+	if resp.ExitCode != 0 {
+		return "", fmt.Errorf("%s: exit %d: %s", command, resp.ExitCode, strings.TrimSpace(resp.Stderr))
+	}
+	return resp.Stdout, nil
+}
+
+func (s *soakRun) killAllSessions() {
+	for _, session := range s.sessions.ListSessions() {
+		s.exec("kill-session", map[string]any{"-t": session.Name})
+	}
+}
+
+// rotateLogs closes the current session log and opens a new file, which also
+// prunes old files the same way a restarted app does.
+func (s *soakRun) rotateLogs() {
+	next := sessionlog.NewService(nil, nil)
+	next.Init(s.configPath)
+	if prev := s.logs.Swap(next); prev != nil {
+		prev.Close()
+	}
+}
+
+func (s *soakRun) writeLog(ts time.Time, level, msg, source string) {
+	if logs := s.logs.Load(); logs != nil {
+		logs.WriteEntry(sessionlog.Entry{
+			Timestamp: ts.Format("20060102150405"),
+			Level:     level,
+			Message:   msg,
+			Source:    source,
+		})
+	}
+}
+
+// toggleConfig flips boolean settings through a full save/load round trip and
+// pushes the reloaded pane_env into the router, as the settings UI does.
+func (s *soakRun) toggleConfig() error {
+	cfg, err := config.Load(s.configPath)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	cfg.QuakeMode = !cfg.QuakeMode
+	cfg.PaneEnvDefaultEnabled = !cfg.PaneEnvDefaultEnabled
+	if cfg.PaneEnv == nil {
+		cfg.PaneEnv = map[string]string{}
+	}
+	cfg.PaneEnv["MYTX_SOAK_CYCLE"] = fmt.Sprint(s.cycle)
+	saved, err := config.Save(s.configPath, cfg)
+	if err != nil {
+		return fmt.Errorf("save config: %w", err)
+	}
+	s.router.UpdatePaneEnv(saved.PaneEnv)
+	return nil
+}
+
+// finish tears down all sessions, lets the runtime settle, and compares the
+// final sample with the baseline without the consecutive-sample allowance.
+func (s *soakRun) finish(detector *leakDetector, elapsed time.Duration) error {
+	s.killAllSessions()
+	time.Sleep(settleDelay)
+	final := takeSample()
+	s.logger.Printf("finished after %s: cycles=%d events=%d final %s",
+		elapsed.Round(time.Second), s.cycle, s.events.Load(), final)
+	if !detector.hasBase {
+		return errors.New("no baseline sample was taken; increase -duration or reduce -warmup")
+	}
+	if err := growthViolation(detector.baseline, final, detector.limits); err != nil {
+		return fmt.Errorf("resources not released after teardown (baseline %s, final %s): %w",
+			detector.baseline, final, err)
+	}
+	return nil
+}
+
+// takeSample forces a GC so heap numbers reflect live memory only.
+func takeSample() resourceSample {
+	runtime.GC()
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	return resourceSample{
+		at:         time.Now(),
+		heapBytes:  mem.HeapAlloc,
+		goroutines: runtime.NumGoroutine(),
+		handles:    processHandleCount(),
+	}
+}
+
+func processHandleCount() int {
+	var count uint32
+	ret, _, _ := procGetProcessHandleCount.Call(uintptr(windows.CurrentProcess()), uintptr(unsafe.Pointer(&count)))
+	if ret == 0 {
+		return -1
+	}
+	return int(count)
+}
@@ -0,0 +1,211 @@
+// Command mytx-state inspects the myT-x state store and host heartbeat.
+//
+// Usage:
+//
+//	mytx-state [-db path] export [-o file]   write the store as JSON (default: stdout)
+//	mytx-state [-db path] check              run the integrity check
+//	mytx-state [-heartbeat path] health      print the heartbeat; exit 1 when the host is not healthy
+//	mytx-state [-heartbeat path] status [-json]
+//	                                         print session status for status bars and statuslines
+//
+// status prints one line per session: an active marker, the name, the branch
+// (suffixed with "*" when dirty), and the activity state. -json prints the
+// stable heartbeat.Summary instead. Both exit 0 when the host is down, with no
+// sessions and "healthy": false, so polling widgets do not flash errors.
+//
+// The database is opened while myT-x may be running; SQLite's busy timeout
+// makes the tool wait for in-flight writes instead of failing.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"myT-x/internal/config"
+	"myT-x/internal/heartbeat"
+	"myT-x/internal/statestore"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "mytx-state:", err)
+		os.Exit(1)
+	}
+}
+
+// cliCommand is a parsed mytx-state invocation.
+type cliCommand struct {
+	dbPath        string
+	heartbeatPath string
+	action        string
+	outPath       string
+	jsonOutput    bool
+}
+
+func parseCLI(args []string) (cliCommand, error) {
+	fs := flag.NewFlagSet("mytx-state", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	configDir := filepath.Dir(config.DefaultPath())
+	cmd := cliCommand{}
+	fs.StringVar(&cmd.dbPath, "db", filepath.Join(configDir, statestore.FileName), "State store database path")
+	fs.StringVar(&cmd.heartbeatPath, "heartbeat", filepath.Join(configDir, heartbeat.FileName), "Heartbeat file path")
+	if err := fs.Parse(args); err != nil {
+		return cliCommand{}, err
+	}
+	if fs.NArg() == 0 {
+		return cliCommand{}, errors.New("missing command: export, check, health or status")
+	}
+	cmd.action = fs.Arg(0)
+	rest := fs.Args()[1:]
+
+	switch cmd.action {
+	case "export":
+		exportFlags := flag.NewFlagSet("export", flag.ContinueOnError)
+		exportFlags.SetOutput(io.Discard)
+		exportFlags.StringVar(&cmd.outPath, "o", "", "Output file (default: stdout)")
+		if err := exportFlags.Parse(rest); err != nil {
+			return cliCommand{}, err
+		}
+		rest = exportFlags.Args()
+	case "status":
+		statusFlags := flag.NewFlagSet("status", flag.ContinueOnError)
+		statusFlags.SetOutput(io.Discard)
+		statusFlags.BoolVar(&cmd.jsonOutput, "json", false, "Print JSON")
+		if err := statusFlags.Parse(rest); err != nil {
+			return cliCommand{}, err
+		}
+		rest = statusFlags.Args()
+	case "check", "health":
+	default:
+		return cliCommand{}, fmt.Errorf("unknown command %q: want export, check, health or status", cmd.action)
+	}
+	if len(rest) > 0 {
+		return cliCommand{}, fmt.Errorf("unexpected arguments: %v", rest)
+	}
+	return cmd, nil
+}
+
+func run(args []string, stdout io.Writer) (err error) {
+	cmd, err := parseCLI(args)
+	if err != nil {
+		return err
+	}
+	if cmd.action == "health" {
+		return reportHealth(cmd.heartbeatPath, time.Now(), stdout)
+	}
+	if cmd.action == "status" {
+		return reportStatus(cmd.heartbeatPath, cmd.jsonOutput, time.Now(), stdout)
+	}
+	// Never create a database as a side effect of inspecting one.
+	if _, statErr := os.Stat(cmd.dbPath); statErr != nil {
+		return fmt.Errorf("state store %s: %w", cmd.dbPath, statErr)
+	}
+	store, err := statestore.OpenSQLite(cmd.dbPath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := store.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+
+	ctx := context.Background()
+	switch cmd.action {
+	case "check":
+		if err := store.Check(ctx); err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "%s: ok (schema version %d)\n", cmd.dbPath, statestore.SchemaVersion)
+		return nil
+	default:
+		return exportStore(ctx, store, cmd.outPath, stdout)
+	}
+}
+
+func exportStore(ctx context.Context, store statestore.Store, outPath string, stdout io.Writer) error {
+	if outPath == "" {
+		return statestore.ExportJSON(ctx, store, stdout)
+	}
+	file, err := os.Create(outPath)
+	if err != nil {
+		return fmt.Errorf("create export file: %w", err)
+	}
+	if err := statestore.ExportJSON(ctx, store, file); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// reportHealth prints the heartbeat at path and fails unless the host wrote it
+// recently and is still running.
+func reportHealth(path string, now time.Time, stdout io.Writer) error {
+	status, err := heartbeat.Read(path)
+	if err != nil {
+		return fmt.Errorf("heartbeat: %w", err)
+	}
+	encoder := json.NewEncoder(stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(status); err != nil {
+		return err
+	}
+	if !status.Healthy(now) {
+		return fmt.Errorf("host is not healthy: state %s, last update %s", status.State, status.UpdatedAt.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// reportStatus prints the status bar summary of the heartbeat at path. A
+// missing heartbeat means no host has run yet and reports as unhealthy.
+func reportStatus(path string, jsonOutput bool, now time.Time, stdout io.Writer) error {
+	status, err := heartbeat.Read(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("heartbeat: %w", err)
+	}
+	summary := status.Summary(now)
+	if jsonOutput {
+		return json.NewEncoder(stdout).Encode(summary)
+	}
+	if !summary.Healthy {
+		_, err := fmt.Fprintln(stdout, "myT-x is not running")
+		return err
+	}
+	for _, session := range summary.Sessions {
+		if _, err := fmt.Fprintln(stdout, formatStatusLine(session)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// formatStatusLine renders one session as tab-separated columns:
+// active marker, name, branch, and activity state.
+func formatStatusLine(session heartbeat.Session) string {
+	marker := " "
+	if session.Active {
+		marker = "*"
+	}
+	branch := session.Branch
+	if branch == "" {
+		branch = "-"
+	} else if session.Dirty {
+		branch += "*"
+	}
+	state := "busy"
+	switch {
+	case session.NeedsInput:
+		state = "needs-input"
+	case session.Idle:
+		state = "idle"
+	}
+	return strings.Join([]string{marker, session.Name, branch, state}, "\t")
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"myT-x/internal/heartbeat"
+)
+
+func TestParseCLI(t *testing.T) {
+	cmd, err := parseCLI([]string{"-db", "state.db", "export", "-o", "out.json"})
+	if err != nil {
+		t.Fatalf("parseCLI() error = %v", err)
+	}
+	if cmd.dbPath != "state.db" || cmd.action != "export" || cmd.outPath != "out.json" {
+		t.Fatalf("parseCLI() = %+v", cmd)
+	}
+
+	cmd, err = parseCLI([]string{"check"})
+	if err != nil || cmd.action != "check" || !strings.HasSuffix(cmd.dbPath, "state.db") {
+		t.Fatalf("parseCLI(check) = %+v, %v, want default db path", cmd, err)
+	}
+
+	cmd, err = parseCLI([]string{"-heartbeat", "hb.json", "health"})
+	if err != nil || cmd.action != "health" || cmd.heartbeatPath != "hb.json" {
+		t.Fatalf("parseCLI(health) = %+v, %v", cmd, err)
+	}
+
+	cmd, err = parseCLI([]string{"status", "-json"})
+	if err != nil || cmd.action != "status" || !cmd.jsonOutput {
+		t.Fatalf("parseCLI(status -json) = %+v, %v", cmd, err)
+	}
+}
+
+func TestParseCLIRejectsInvalidArguments(t *testing.T) {
+	for _, args := range [][]string{
+		{},
+		{"vacuum"},
+		{"check", "extra"},
+		{"export", "-x"},
+		{"status", "-x"},
+		{"status", "extra"},
+	} {
+		if _, err := parseCLI(args); err == nil {
+			t.Fatalf("parseCLI(%v) error = nil", args)
+		}
+	}
+}
+
+func TestRunRequiresExistingDatabase(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.db")
+	if err := run([]string{"-db", missing, "check"}, &strings.Builder{}); err == nil {
+		t.Fatal("run() error = nil for missing database")
+	}
+}
+
+func TestRunHealth(t *testing.T) {
+	path := filepath.Join(t.TempDir(), heartbeat.FileName)
+	if err := run([]string{"-heartbeat", path, "health"}, &strings.Builder{}); err == nil {
+		t.Fatal("run(health) error = nil for missing heartbeat")
+	}
+
+	writer := heartbeat.NewWriter(heartbeat.Deps{
+		Path:     func() (string, error) { return path, nil },
+		Sessions: func() []heartbeat.Session { return []heartbeat.Session{{Name: "main"}} },
+		Version:  "1.0.0",
+	})
+	if err := writer.Write(heartbeat.StateRunning); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	var out strings.Builder
+	if err := run([]string{"-heartbeat", path, "health"}, &out); err != nil {
+		t.Fatalf("run(health) error = %v", err)
+	}
+	if !strings.Contains(out.String(), `"name": "main"`) {
+		t.Fatalf("run(health) output = %q, want session list", out.String())
+	}
+
+	if err := reportHealth(path, time.Now().Add(time.Hour), &strings.Builder{}); err == nil {
+		t.Fatal("reportHealth() error = nil for stale heartbeat")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	writer.Run(ctx)
+	if err := run([]string{"-heartbeat", path, "health"}, &strings.Builder{}); err == nil {
+		t.Fatal("run(health) error = nil after the host stopped")
+	}
+}
+
+func TestRunStatus(t *testing.T) {
+	path := filepath.Join(t.TempDir(), heartbeat.FileName)
+	var out strings.Builder
+	if err := run([]string{"-heartbeat", path, "status", "-json"}, &out); err != nil {
+		t.Fatalf("run(status) error = %v for missing heartbeat", err)
+	}
+	var summary heartbeat.Summary
+	if err := json.Unmarshal([]byte(out.String()), &summary); err != nil {
+		t.Fatalf("status -json output %q: %v", out.String(), err)
+	}
+	if summary.Healthy || summary.Sessions == nil || len(summary.Sessions) != 0 {
+		t.Fatalf("status of missing heartbeat = %+v, want unhealthy with no sessions", summary)
+	}
+
+	writer := heartbeat.NewWriter(heartbeat.Deps{
+		Path: func() (string, error) { return path, nil },
+		Sessions: func() []heartbeat.Session {
+			return []heartbeat.Session{
+				{Name: "main", Active: true, Branch: "feature/x", Dirty: true, NeedsInput: true},
+				{Name: "docs", Idle: true},
+			}
+		},
+		Version: "1.0.0",
+	})
+	if err := writer.Write(heartbeat.StateRunning); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	out.Reset()
+	if err := run([]string{"-heartbeat", path, "status"}, &out); err != nil {
+		t.Fatalf("run(status) error = %v", err)
+	}
+	if want := "*\tmain\tfeature/x*\tneeds-input\n \tdocs\t-\tidle\n"; out.String() != want {
+		t.Fatalf("status output = %q, want %q", out.String(), want)
+	}
+
+	out.Reset()
+	if err := run([]string{"-heartbeat", path, "status", "-json"}, &out); err != nil {
+		t.Fatalf("run(status -json) error = %v", err)
+	}
+	if err := json.Unmarshal([]byte(out.String()), &summary); err != nil {
+		t.Fatal(err)
+	}
+	if !summary.Healthy || summary.Version != "1.0.0" || len(summary.Sessions) != 2 || summary.Sessions[0].Branch != "feature/x" {
+		t.Fatalf("status -json = %+v", summary)
+	}
+
+	out.Reset()
+	if err := reportStatus(path, false, time.Now().Add(time.Hour), &out); err != nil || out.String() != "myT-x is not running\n" {
+		t.Fatalf("reportStatus(stale) = %q, %v", out.String(), err)
+	}
+}
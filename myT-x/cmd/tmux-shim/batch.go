@@ -0,0 +1,247 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"myT-x/internal/ipc"
+)
+
+const (
+	// stdinBatchFlag reads commands from stdin, one line at a time, and
+	// sends them all over one connection.
+	stdinBatchFlag = "--stdin"
+	// commandSeparator ends a command in a sequence, as in
+	// `tmux new-session -d \; split-window`.
+	commandSeparator = ";"
+	// batchMaxLineBytes bounds one command line read in stdin batch mode.
+	batchMaxLineBytes = 1024 * 1024
+)
+
+// splitCommandSequence splits args into commands like tmux does: an
+// argument ending in ";" ends the command, and a trailing "\;" is a literal
+// semicolon. Empty commands are dropped.
+func splitCommandSequence(args []string) [][]string {
+	var commands [][]string
+	var current []string
+	for _, arg := range args {
+		body, ok := strings.CutSuffix(arg, commandSeparator)
+		if !ok {
+			current = append(current, arg)
+			continue
+		}
+		if escaped, ok := strings.CutSuffix(body, `\`); ok {
+			current = append(current, escaped+commandSeparator)
+			continue
+		}
+		if body != "" {
+			current = append(current, body)
+		}
+		if len(current) > 0 {
+			commands = append(commands, current)
+		}
+		current = nil
+	}
+	if len(current) > 0 {
+		commands = append(commands, current)
+	}
+	return commands
+}
+
+// splitCommandLine splits one stdin batch line into commands. Words are
+// separated by spaces and tabs; single quotes keep their content literally,
+// a backslash escapes the next character outside single quotes, and an
+// unquoted ";" ends a command.
+func splitCommandLine(line string) ([][]string, error) {
+	var commands [][]string
+	var words []string
+	var word strings.Builder
+	inWord, escaped := false, false
+	var quote rune
+
+	endWord := func() {
+		if inWord {
+			words = append(words, word.String())
+			word.Reset()
+			inWord = false
+		}
+	}
+	endCommand := func() {
+		endWord()
+		if len(words) > 0 {
+			commands = append(commands, words)
+			words = nil
+		}
+	}
+	for _, r := range line {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case quote == '"' && r == '"':
+			quote = 0
+		case r == '\\':
+			escaped, inWord = true, true
+		case quote == '"':
+			word.WriteRune(r)
+		case r == '\'' || r == '"':
+			quote, inWord = r, true
+		case r == ' ' || r == '\t':
+			endWord()
+		case r == ';':
+			endCommand()
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if escaped {
+		return nil, errors.New("trailing backslash")
+	}
+	endCommand()
+	return commands, nil
+}
+
+// batchRunner runs the commands of a batch over one connection. The
+// exit code is that of the first failing command.
+type batchRunner struct {
+	// prepare and send default to prepareRequest and a BatchClient; tests
+	// replace them.
+	prepare  func(args []string) (ipc.TmuxRequest, bool, error)
+	send     func(req ipc.TmuxRequest) (ipc.TmuxResponse, error)
+	pipeName string
+	stdout   io.Writer
+	stderr   io.Writer
+	exitCode int
+}
+
+// runBatchMode runs a command sequence, or the commands on stdin when args
+// starts with stdinBatchFlag, and returns the exit code.
+func runBatchMode(args []string, commands [][]string) int {
+	pipeName := ipc.DefaultPipeName()
+	client := ipc.NewBatchClient(pipeName)
+	defer client.Close()
+	runner := &batchRunner{
+		prepare:  prepareRequest,
+		send:     client.Send,
+		pipeName: pipeName,
+		stdout:   os.Stdout,
+		stderr:   os.Stderr,
+	}
+	if args[0] == stdinBatchFlag {
+		if len(args) > 1 {
+			writeLineToStderr(stdinBatchFlag + " takes no arguments")
+			return 1
+		}
+		return runner.runLines(os.Stdin)
+	}
+	runner.runSequence(commands)
+	return runner.exitCode
+}
+
+// runSequence runs commands in order and stops at the first failure, like a
+// tmux command sequence. It reports whether the connection is still usable.
+func (b *batchRunner) runSequence(commands [][]string) bool {
+	for _, args := range commands {
+		ok, err := b.run(args)
+		if err != nil {
+			return false
+		}
+		if !ok {
+			break
+		}
+	}
+	return true
+}
+
+// runLines runs every line of stdin as a command sequence. A failing line
+// does not stop the following ones; a lost connection does.
+func (b *batchRunner) runLines(stdin io.Reader) int {
+	scanner := bufio.NewScanner(stdin)
+	scanner.Buffer(make([]byte, 0, 4096), batchMaxLineBytes)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		commands, err := splitCommandLine(line)
+		if err != nil {
+			_, _ = fmt.Fprintf(b.stderr, "%s: %v\n", line, err)
+			b.fail(1)
+			continue
+		}
+		if !b.runSequence(commands) {
+			return b.exitCode
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		_, _ = fmt.Fprintf(b.stderr, "read stdin: %v\n", err)
+		b.fail(1)
+	}
+	return b.exitCode
+}
+
+// run sends one command and writes its output. ok is false when the command
+// failed; err is set when the server could not be reached.
+func (b *batchRunner) run(args []string) (ok bool, err error) {
+	req, skip, err := b.prepare(args)
+	if err != nil {
+		_, _ = fmt.Fprintln(b.stderr, err.Error())
+		b.fail(1)
+		return false, nil
+	}
+	if skip {
+		return true, nil
+	}
+	if err := checkNestedClient(req, false); err != nil {
+		_, _ = fmt.Fprintln(b.stderr, err.Error())
+		b.fail(1)
+		return false, nil
+	}
+
+	resp, err := b.send(req)
+	if err != nil {
+		warnLog("ipc error: %v", err)
+		if ipc.IsConnectionError(err) {
+			_, _ = fmt.Fprintf(b.stderr, "no server running on %s\n", b.pipeName)
+		} else {
+			_, _ = fmt.Fprintln(b.stderr, err.Error())
+		}
+		b.fail(1)
+		return false, err
+	}
+	debugLog("response: exit=%d stdout=%q stderr=%q",
+		resp.ExitCode, truncate(resp.Stdout, 200), truncate(resp.Stderr, 200))
+
+	if resp.Stdout != "" {
+		_, _ = io.WriteString(b.stdout, resp.Stdout)
+	}
+	if resp.Stderr != "" {
+		_, _ = io.WriteString(b.stderr, resp.Stderr)
+	}
+	if resp.ExitCode != 0 {
+		b.fail(resp.ExitCode)
+		return false, nil
+	}
+	return true, nil
+}
+
+// fail records code unless an earlier command already failed.
+func (b *batchRunner) fail(code int) {
+	if b.exitCode == 0 {
+		b.exitCode = code
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"myT-x/internal/ipc"
+)
+
+func TestSplitCommandSequence(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want [][]string
+	}{
+		{name: "single", args: []string{"list-sessions"}, want: [][]string{{"list-sessions"}}},
+		{name: "separator argument", args: []string{"new-session", "-d", ";", "split-window"},
+			want: [][]string{{"new-session", "-d"}, {"split-window"}}},
+		{name: "attached separator", args: []string{"rename-window", "w1;", "list-windows"},
+			want: [][]string{{"rename-window", "w1"}, {"list-windows"}}},
+		{name: "escaped semicolon", args: []string{"send-keys", `\;`, `a\;`},
+			want: [][]string{{"send-keys", ";", "a;"}}},
+		{name: "empty commands dropped", args: []string{";", "list-sessions", ";", ";"}, want: [][]string{{"list-sessions"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitCommandSequence(tt.args); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("splitCommandSequence(%q) = %q, want %q", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitCommandLine(t *testing.T) {
+	got, err := splitCommandLine(`send-keys -t %1 "echo a; b" Enter ; display-message -p 'it''s' a\ b\;c`)
+	if err != nil {
+		t.Fatalf("splitCommandLine() error = %v", err)
+	}
+	want := [][]string{
+		{"send-keys", "-t", "%1", "echo a; b", "Enter"},
+		{"display-message", "-p", "its", "a b;c"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("splitCommandLine() = %q, want %q", got, want)
+	}
+
+	for _, bad := range []string{`send-keys "open`, `send-keys 'open`, `send-keys \`} {
+		if _, err := splitCommandLine(bad); err == nil {
+			t.Fatalf("splitCommandLine(%q) error = nil", bad)
+		}
+	}
+}
+
+func newTestBatchRunner(responses map[string]ipc.TmuxResponse) (*batchRunner, *[]string, *strings.Builder, *strings.Builder) {
+	var sent []string
+	var stdout, stderr strings.Builder
+	return &batchRunner{
+		prepare: func(args []string) (ipc.TmuxRequest, bool, error) {
+			if args[0] == "bogus" {
+				return ipc.TmuxRequest{}, false, errors.New("unknown command: bogus")
+			}
+			return ipc.TmuxRequest{Command: args[0], Args: args[1:]}, false, nil
+		},
+		send: func(req ipc.TmuxRequest) (ipc.TmuxResponse, error) {
+			sent = append(sent, req.Command)
+			if req.Command == "unreachable" {
+				return ipc.TmuxResponse{}, errors.New("broken pipe")
+			}
+			return responses[req.Command], nil
+		},
+		pipeName: "test-pipe",
+		stdout:   &stdout,
+		stderr:   &stderr,
+	}, &sent, &stdout, &stderr
+}
+
+func TestBatchRunnerSequenceStopsAtFirstFailure(t *testing.T) {
+	runner, sent, stdout, stderr := newTestBatchRunner(map[string]ipc.TmuxResponse{
+		"list-sessions": {Stdout: "dev\n"},
+		"kill-pane":     {ExitCode: 1, Stderr: "can't find pane\n"},
+	})
+	runner.runSequence([][]string{{"list-sessions"}, {"kill-pane"}, {"list-sessions"}})
+
+	if want := []string{"list-sessions", "kill-pane"}; !reflect.DeepEqual(*sent, want) {
+		t.Fatalf("sent = %q, want %q", *sent, want)
+	}
+	if runner.exitCode != 1 || stdout.String() != "dev\n" || stderr.String() != "can't find pane\n" {
+		t.Fatalf("exit = %d, stdout = %q, stderr = %q", runner.exitCode, stdout, stderr)
+	}
+}
+
+func TestBatchRunnerLinesContinueAfterFailure(t *testing.T) {
+	runner, sent, stdout, stderr := newTestBatchRunner(map[string]ipc.TmuxResponse{
+		"has-session": {ExitCode: 2},
+		"list-panes":  {Stdout: "%1\n"},
+	})
+	stdin := strings.NewReader("# comment\n\nbogus\nhas-session -t x ; list-panes\nsend-keys \"open\nlist-panes\nunreachable\nlist-panes\n")
+
+	if code := runner.runLines(stdin); code != 1 {
+		t.Fatalf("runLines() = %d, want the first failure's exit code 1", code)
+	}
+	// has-session fails, so list-panes on its line is skipped; the lost
+	// connection ends the batch.
+	if want := []string{"has-session", "list-panes", "unreachable"}; !reflect.DeepEqual(*sent, want) {
+		t.Fatalf("sent = %q, want %q", *sent, want)
+	}
+	if stdout.String() != "%1\n" {
+		t.Fatalf("stdout = %q", stdout)
+	}
+	for _, want := range []string{"unknown command: bogus", "unterminated \" quote", "broken pipe"} {
+		if !strings.Contains(stderr.String(), want) {
+			t.Fatalf("stderr = %q, want it to contain %q", stderr, want)
+		}
+	}
+}
+
+func TestBatchRunnerRefusesNestedNewSession(t *testing.T) {
+	t.Setenv("TMUX", `\\.\pipe\myT-x,1,0`)
+	runner, sent, _, stderr := newTestBatchRunner(map[string]ipc.TmuxResponse{})
+	runner.prepare = func(args []string) (ipc.TmuxRequest, bool, error) {
+		flags := map[string]any{}
+		if len(args) > 1 && args[1] == "-d" {
+			flags["-d"] = true
+		}
+		return ipc.TmuxRequest{Command: args[0], Flags: flags}, false, nil
+	}
+	runner.runSequence([][]string{{"new-session", "-d"}, {"new-session"}, {"list-sessions"}})
+
+	if want := []string{"new-session"}; !reflect.DeepEqual(*sent, want) {
+		t.Fatalf("sent = %q, want only the detached new-session", *sent)
+	}
+	if runner.exitCode != 1 || !strings.Contains(stderr.String(), "sessions should be nested with care") {
+		t.Fatalf("exit = %d, stderr = %q", runner.exitCode, stderr)
+	}
+}
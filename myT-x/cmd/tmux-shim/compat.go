@@ -0,0 +1,186 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"myT-x/internal/config"
+	"myT-x/internal/ipc"
+)
+
+const (
+	compatReportFileName = "tmux-compat.log"
+	compatReportMaxBytes = 1024 * 1024
+)
+
+// tmuxCommands lists the commands of real tmux (3.4). In lenient mode only
+// these are ignored; any other name is an error in tmux itself.
+var tmuxCommands = []string{
+	"attach-session", "bind-key", "break-pane", "capture-pane", "choose-buffer",
+	"choose-client", "choose-tree", "clear-history", "clear-prompt-history",
+	"clock-mode", "command-prompt", "confirm-before", "copy-mode",
+	"customize-mode", "delete-buffer", "detach-client", "display-menu",
+	"display-message", "display-panes", "display-popup", "find-window",
+	"has-session", "if-shell", "join-pane", "kill-pane", "kill-server",
+	"kill-session", "kill-window", "last-pane", "last-window", "link-window",
+	"list-buffers", "list-clients", "list-commands", "list-keys", "list-panes",
+	"list-sessions", "list-windows", "load-buffer", "lock-client", "lock-server",
+	"lock-session", "move-pane", "move-window", "new-session", "new-window",
+	"next-layout", "next-window", "paste-buffer", "pipe-pane", "previous-layout",
+	"previous-window", "refresh-client", "rename-session", "rename-window",
+	"resize-pane", "resize-window", "respawn-pane", "respawn-window",
+	"rotate-window", "run-shell", "save-buffer", "select-layout", "select-pane",
+	"select-window", "send-keys", "send-prefix", "server-access", "set-buffer",
+	"set-environment", "set-hook", "set-option", "set-window-option",
+	"show-buffer", "show-environment", "show-hooks", "show-messages",
+	"show-options", "show-prompt-history", "show-window-options", "source-file",
+	"split-window", "start-server", "suspend-client", "swap-pane", "swap-window",
+	"switch-client", "unbind-key", "unlink-window", "wait-for",
+}
+
+// compatIssue is a tmux command or flag the shim does not support. Its
+// Error text matches what real tmux prints for an unknown command or flag.
+type compatIssue struct {
+	Command string
+	// Flag is empty when the command itself is unsupported.
+	Flag string
+}
+
+func (i *compatIssue) Error() string {
+	if i.Flag == "" {
+		return "unknown command: " + i.Command
+	}
+	return fmt.Sprintf("command %s: unknown flag %s", i.Command, i.Flag)
+}
+
+// ignorable reports whether lenient mode may ignore the issue. Names that
+// are not tmux commands at all fail in both modes, like in tmux.
+func (i *compatIssue) ignorable() bool {
+	return i.Flag != "" || slices.Contains(tmuxCommands, i.Command)
+}
+
+func (i *compatIssue) warning() string {
+	if i.Flag == "" {
+		return fmt.Sprintf("myT-x: %s is not supported and was ignored", i.Command)
+	}
+	return fmt.Sprintf("myT-x: flag %s of %s is not supported and was ignored", i.Flag, i.Command)
+}
+
+// parseWithCompat parses args under the tmux_compat mode. Supported
+// commands take the strict parse and never load the config. skip is true
+// when lenient mode ignored the whole command.
+func parseWithCompat(args []string, loadMode func() string) (req ipc.TmuxRequest, skip bool, err error) {
+	req, err = parseCommand(args)
+	var issue *compatIssue
+	if !errors.As(err, &issue) {
+		return req, false, err
+	}
+
+	mode := loadMode()
+	if mode != config.TmuxCompatLenient || !issue.ignorable() {
+		reportCompatIssue(mode, issue, "rejected", args)
+		return ipc.TmuxRequest{}, false, err
+	}
+	if issue.Flag == "" {
+		reportCompatIssue(mode, issue, "ignored", args)
+		writeLineToStderr(issue.warning())
+		return ipc.TmuxRequest{}, true, nil
+	}
+
+	req, issues, err := parseCommandMode(args, true)
+	for _, ignored := range issues {
+		reportCompatIssue(mode, ignored, "ignored", args)
+		writeLineToStderr(ignored.warning())
+	}
+	return req, false, err
+}
+
+var (
+	compatModeLoadMu sync.Mutex
+	compatModeCached string
+	compatModeLoaded bool
+)
+
+// loadTmuxCompatMode returns the tmux_compat mode of the config. Config load
+// failures fall back to strict (shim spec: never block on config failure).
+func loadTmuxCompatMode() string {
+	compatModeLoadMu.Lock()
+	defer compatModeLoadMu.Unlock()
+
+	if compatModeLoaded {
+		return compatModeCached
+	}
+	compatModeCached = config.TmuxCompatStrict
+	compatModeLoaded = true
+	cfg, err := config.Load(config.DefaultPath())
+	if err != nil {
+		debugLog("loadTmuxCompatMode: config load failed: %v", err)
+		return compatModeCached
+	}
+	compatModeCached = config.EffectiveTmuxCompat(cfg)
+	return compatModeCached
+}
+
+// compatReportEntry is one line of the compatibility report.
+type compatReportEntry struct {
+	Time    time.Time `json:"time"`
+	Mode    string    `json:"mode"`
+	Command string    `json:"command"`
+	Flag    string    `json:"flag,omitempty"`
+	// Action is "rejected" or "ignored".
+	Action string `json:"action"`
+	Args   string `json:"args"`
+}
+
+// reportCompatIssue appends the issue to the compatibility report so users
+// can see which parts of their tmux automation myT-x does not cover.
+// Report file: %LOCALAPPDATA%\myT-x\tmux-compat.log (one JSON object per line).
+func reportCompatIssue(mode string, issue *compatIssue, action string, args []string) {
+	debugLog("compat: %s (mode=%s action=%s)", issue.Error(), mode, action)
+
+	localAppData := os.Getenv("LOCALAPPDATA")
+	if localAppData == "" {
+		return
+	}
+	entry := compatReportEntry{
+		Time:    time.Now().UTC(),
+		Mode:    mode,
+		Command: issue.Command,
+		Flag:    issue.Flag,
+		Action:  action,
+		Args:    strings.Join(args, " "),
+	}
+	if err := appendCompatReport(filepath.Join(localAppData, "myT-x", compatReportFileName), entry); err != nil {
+		warnLog("compat report write failed: %v", err)
+	}
+}
+
+func appendCompatReport(path string, entry compatReportEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	// Keep one previous generation; the report only needs recent history.
+	if info, statErr := os.Stat(path); statErr == nil && info.Size() >= compatReportMaxBytes {
+		if err := os.Rename(path, path+".old"); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"myT-x/internal/config"
+)
+
+func readCompatReport(t *testing.T, localAppData string) []compatReportEntry {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(localAppData, "myT-x", compatReportFileName))
+	if err != nil {
+		t.Fatalf("read compat report: %v", err)
+	}
+	var entries []compatReportEntry
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var entry compatReportEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("decode compat report line %q: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestParseWithCompatStrictUsesTmuxErrors(t *testing.T) {
+	localAppData := t.TempDir()
+	t.Setenv("LOCALAPPDATA", localAppData)
+	strict := func() string { return config.TmuxCompatStrict }
+
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{args: []string{"choose-tree"}, want: "unknown command: choose-tree"},
+		{args: []string{"new-session", "-A", "-s", "x"}, want: "command new-session: unknown flag -A"},
+		{args: []string{"split-window", "-dZ"}, want: "command split-window: unknown flag -Z"},
+		{args: []string{"show", "-x"}, want: "command show-options: unknown flag -x"},
+	}
+	for _, tt := range tests {
+		_, skip, err := parseWithCompat(tt.args, strict)
+		if err == nil || err.Error() != tt.want || skip {
+			t.Errorf("parseWithCompat(%v) = skip %v, err %v; want %q", tt.args, skip, err, tt.want)
+		}
+	}
+
+	entries := readCompatReport(t, localAppData)
+	if len(entries) != len(tests) {
+		t.Fatalf("report entries = %d, want %d", len(entries), len(tests))
+	}
+	if got := entries[1]; got.Mode != config.TmuxCompatStrict || got.Action != "rejected" ||
+		got.Command != "new-session" || got.Flag != "-A" || got.Args != "new-session -A -s x" {
+		t.Fatalf("report entry = %+v", got)
+	}
+}
+
+func TestParseWithCompatLenientIgnoresUnsupported(t *testing.T) {
+	localAppData := t.TempDir()
+	t.Setenv("LOCALAPPDATA", localAppData)
+	lenient := func() string { return config.TmuxCompatLenient }
+
+	req, skip, err := parseWithCompat([]string{"split-window", "-dZ", "-t", "%1", "-y", "vim"}, lenient)
+	if err != nil || skip {
+		t.Fatalf("parseWithCompat(split-window) = skip %v, err %v", skip, err)
+	}
+	if !asBool(req.Flags["-d"]) || asString(req.Flags["-t"]) != "%1" || len(req.Args) != 1 || req.Args[0] != "vim" {
+		t.Fatalf("request = %+v, want -d -t %%1 vim with -Z and -y dropped", req)
+	}
+
+	if _, skip, err := parseWithCompat([]string{"bind-key", "C-a", "send-prefix"}, lenient); err != nil || !skip {
+		t.Fatalf("parseWithCompat(bind-key) = skip %v, err %v; want skipped", skip, err)
+	}
+
+	// Names tmux itself does not know still fail.
+	if _, skip, err := parseWithCompat([]string{"frobnicate"}, lenient); err == nil || skip {
+		t.Fatalf("parseWithCompat(frobnicate) = skip %v, err %v; want error", skip, err)
+	}
+
+	entries := readCompatReport(t, localAppData)
+	var actions []string
+	for _, entry := range entries {
+		actions = append(actions, entry.Command+" "+entry.Flag+" "+entry.Action)
+	}
+	want := []string{"split-window -Z ignored", "split-window -y ignored", "bind-key  ignored", "frobnicate  rejected"}
+	if strings.Join(actions, "|") != strings.Join(want, "|") {
+		t.Fatalf("report = %q, want %q", actions, want)
+	}
+}
+
+func TestParseWithCompatSkipsConfigForSupportedCommands(t *testing.T) {
+	loaded := false
+	req, skip, err := parseWithCompat([]string{"list-sessions"}, func() string {
+		loaded = true
+		return config.TmuxCompatStrict
+	})
+	if err != nil || skip || req.Command != "list-sessions" || loaded {
+		t.Fatalf("parseWithCompat(list-sessions) = %+v, skip %v, err %v, loaded %v", req, skip, err, loaded)
+	}
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"strings"
+	"sync"
+
+	"myT-x/internal/ipc"
+)
+
+// controlMode is the tmux control mode requested with -C or -CC.
+type controlMode int
+
+const (
+	controlModeOff controlMode = iota
+	// controlModeEcho is `tmux -C`: plain control mode for scripts.
+	controlModeEcho
+	// controlModeNoEcho is `tmux -CC`, which iTerm2-style integrations use.
+	// tmux wraps the session in a DCS sequence so that the terminal can
+	// detect it.
+	controlModeNoEcho
+)
+
+const (
+	controlModeDCSStart = "\x1bP1000p"
+	controlModeDCSEnd   = "\x1b\\"
+	// controlModeMaxLineBytes bounds one command line read from stdin.
+	controlModeMaxLineBytes = 1024 * 1024
+)
+
+// splitControlModeArgs strips the leading -C/-CC global flags from args.
+// As in tmux, -CC and -C -C both select controlModeNoEcho. Control mode
+// without a command creates a new session.
+func splitControlModeArgs(args []string) (controlMode, []string) {
+	count := 0
+	for len(args) > 0 && (args[0] == "-C" || args[0] == "-CC") {
+		count += len(args[0]) - 1
+		args = args[1:]
+	}
+	switch {
+	case count == 0:
+		return controlModeOff, args
+	case len(args) == 0:
+		args = []string{"new-session"}
+	}
+	if count == 1 {
+		return controlModeEcho, args
+	}
+	return controlModeNoEcho, args
+}
+
+// isControlModeCommand reports whether command can start a control mode
+// client.
+func isControlModeCommand(command string) bool {
+	return command == "attach-session" || command == "new-session"
+}
+
+// controlModeTransport sends requests to the server. Tests replace it.
+type controlModeTransport struct {
+	send       func(req ipc.TmuxRequest) (ipc.TmuxResponse, error)
+	sendStream func(ctx context.Context, req ipc.TmuxRequest, stdout, stderr io.Writer) (ipc.TmuxResponse, error)
+}
+
+func pipeControlModeTransport(pipeName string) controlModeTransport {
+	// Commands are sent one at a time, so they share a kept-open connection.
+	commands := ipc.NewBatchClient(pipeName)
+	return controlModeTransport{
+		send: commands.Send,
+		sendStream: func(ctx context.Context, req ipc.TmuxRequest, stdout, stderr io.Writer) (ipc.TmuxResponse, error) {
+			return ipc.SendStream(ctx, pipeName, req, stdout, stderr)
+		},
+	}
+}
+
+// lockedWriter serializes writes from the notification stream and the
+// command blocks so that their lines do not interleave.
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (w *lockedWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.w.Write(p)
+}
+
+// runControlMode runs a tmux control mode client and returns its exit code.
+//
+// req (attach-session or new-session) is sent as a streaming request; the
+// server answers its %begin/%end block and then streams notifications such
+// as %output until the client detaches. Every line read from stdin is a
+// tmux command, run as `run-shell -C` and framed in its own %begin/%end
+// block here. An empty line or the end of stdin detaches.
+func runControlMode(ctx context.Context, mode controlMode, req ipc.TmuxRequest, stdin io.Reader, stdout io.Writer, transport controlModeTransport) int {
+	out := &lockedWriter{w: stdout}
+	if mode == controlModeNoEcho {
+		_, _ = io.WriteString(out, controlModeDCSStart)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	req.Stream = true
+	if req.Flags == nil {
+		req.Flags = map[string]any{}
+	}
+	req.Flags[ipc.ControlModeFlag] = true
+
+	type streamResult struct {
+		resp ipc.TmuxResponse
+		err  error
+	}
+	streamDone := make(chan streamResult, 1)
+	go func() {
+		resp, err := transport.sendStream(ctx, req, out, out)
+		streamDone <- streamResult{resp: resp, err: err}
+	}()
+
+	go func() {
+		// Detach when stdin ends; the stream then returns.
+		defer cancel()
+		scanner := bufio.NewScanner(stdin)
+		scanner.Buffer(make([]byte, 0, 4096), controlModeMaxLineBytes)
+		for number := 1; scanner.Scan(); number++ {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" {
+				return
+			}
+			resp, err := transport.send(ipc.TmuxRequest{
+				Command:    "run-shell",
+				Flags:      map[string]any{"-C": true},
+				Args:       []string{line},
+				CallerPane: req.CallerPane,
+			})
+			if err != nil {
+				debugLog("control mode command failed: %v", err)
+				resp = ipc.TmuxResponse{ExitCode: 1, Stderr: err.Error()}
+			}
+			if err := ipc.WriteControlModeBlock(out, number, resp); err != nil {
+				debugLog("control mode write failed: %v", err)
+				return
+			}
+		}
+	}()
+
+	result := <-streamDone
+	exitCode := 0
+	exitLine := "%exit\n"
+	switch {
+	case result.err != nil:
+		debugLog("control mode stream failed: %v", result.err)
+		exitCode = 1
+		exitLine = "%exit " + strings.TrimSpace(result.err.Error()) + "\n"
+	case result.resp.ExitCode != 0:
+		exitCode = result.resp.ExitCode
+		if reason := strings.TrimSpace(result.resp.Stderr); reason != "" {
+			exitLine = "%exit " + reason + "\n"
+		}
+	}
+	_, _ = io.WriteString(out, exitLine)
+	if mode == controlModeNoEcho {
+		_, _ = io.WriteString(out, controlModeDCSEnd)
+	}
+	return exitCode
+}
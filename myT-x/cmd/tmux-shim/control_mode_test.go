@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"myT-x/internal/ipc"
+)
+
+func TestSplitControlModeArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantMode controlMode
+		wantArgs []string
+	}{
+		{name: "no control mode", args: []string{"list-sessions"}, wantMode: controlModeOff, wantArgs: []string{"list-sessions"}},
+		{name: "-C", args: []string{"-C", "attach", "-t", "dev"}, wantMode: controlModeEcho, wantArgs: []string{"attach", "-t", "dev"}},
+		{name: "-CC", args: []string{"-CC", "attach-session"}, wantMode: controlModeNoEcho, wantArgs: []string{"attach-session"}},
+		{name: "-C -C", args: []string{"-C", "-C", "new-session"}, wantMode: controlModeNoEcho, wantArgs: []string{"new-session"}},
+		{name: "defaults to new-session", args: []string{"-CC"}, wantMode: controlModeNoEcho, wantArgs: []string{"new-session"}},
+		{name: "empty", args: nil, wantMode: controlModeOff, wantArgs: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mode, args := splitControlModeArgs(tt.args)
+			if mode != tt.wantMode || !slices.Equal(args, tt.wantArgs) {
+				t.Fatalf("splitControlModeArgs(%q) = %v, %q; want %v, %q", tt.args, mode, args, tt.wantMode, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestRunControlMode(t *testing.T) {
+	newTransport := func(streamErr error) (controlModeTransport, *[]ipc.TmuxRequest, *ipc.TmuxRequest) {
+		var mu sync.Mutex
+		var commands []ipc.TmuxRequest
+		var stream ipc.TmuxRequest
+		return controlModeTransport{
+			send: func(req ipc.TmuxRequest) (ipc.TmuxResponse, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				commands = append(commands, req)
+				if req.Args[0] == "bad-command" {
+					return ipc.TmuxResponse{ExitCode: 1, Stderr: "unknown command: bad-command\n"}, nil
+				}
+				return ipc.TmuxResponse{Stdout: "@0 main\n"}, nil
+			},
+			sendStream: func(ctx context.Context, req ipc.TmuxRequest, stdout, _ io.Writer) (ipc.TmuxResponse, error) {
+				stream = req
+				if streamErr != nil {
+					return ipc.TmuxResponse{}, streamErr
+				}
+				_, _ = io.WriteString(stdout, "%session-changed $0 dev\n")
+				<-ctx.Done()
+				return ipc.TmuxResponse{}, nil
+			},
+		}, &commands, &stream
+	}
+
+	t.Run("runs commands until an empty line detaches", func(t *testing.T) {
+		transport, commands, stream := newTransport(nil)
+		var out strings.Builder
+		stdin := strings.NewReader("list-windows\nbad-command\n\nignored\n")
+		req := ipc.TmuxRequest{Command: "attach-session", Flags: map[string]any{"-t": "dev"}}
+		code := runControlMode(context.Background(), controlModeNoEcho, req, stdin, &out, transport)
+		if code != 0 {
+			t.Fatalf("runControlMode() = %d, want 0", code)
+		}
+		if !stream.Stream || stream.Flags[ipc.ControlModeFlag] != true {
+			t.Fatalf("stream request = %#v, want a control mode stream", *stream)
+		}
+		if len(*commands) != 2 {
+			t.Fatalf("commands = %#v, want 2", *commands)
+		}
+		if first := (*commands)[0]; first.Command != "run-shell" || first.Flags["-C"] != true || first.Args[0] != "list-windows" {
+			t.Fatalf("first command = %#v", first)
+		}
+
+		got := out.String()
+		if !strings.HasPrefix(got, controlModeDCSStart) || !strings.HasSuffix(got, "%exit\n"+controlModeDCSEnd) {
+			t.Fatalf("output = %q, want DCS-wrapped session ending in %%exit", got)
+		}
+		for _, want := range []string{"%session-changed $0 dev\n", " 1 1\n@0 main\n%end ", " 2 1\nunknown command: bad-command\n%error "} {
+			if !strings.Contains(got, want) {
+				t.Fatalf("output = %q, want it to contain %q", got, want)
+			}
+		}
+	})
+
+	t.Run("stream failure exits with reason", func(t *testing.T) {
+		transport, _, _ := newTransport(errors.New("no server"))
+		var out strings.Builder
+		req := ipc.TmuxRequest{Command: "new-session"}
+		code := runControlMode(context.Background(), controlModeEcho, req, strings.NewReader(""), &out, transport)
+		if code != 1 {
+			t.Fatalf("runControlMode() = %d, want 1", code)
+		}
+		if out.String() != "%exit no server\n" {
+			t.Fatalf("output = %q", out.String())
+		}
+	})
+}
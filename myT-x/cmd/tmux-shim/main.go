@@ -1,21 +1,24 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
+	"log/slog"
 	"maps"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"runtime/debug"
-	"sort"
-	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"myT-x/internal/ipc"
+	"myT-x/internal/logging"
+	"myT-x/internal/tmuxtarget"
 )
 
 const (
@@ -25,36 +28,32 @@ const (
 	debugLogFallbackMaxMessages = 3
 )
 
-// shimFileOps holds injectable file operations for log rotation and pruning.
-// Tests create custom instances instead of mutating package-level state.
-type shimFileOps struct {
-	renameFile func(oldPath, newPath string) error
-	removeFile func(string) error
-}
-
-// defaultShimFileOps returns production file operations.
-func defaultShimFileOps() shimFileOps {
-	return shimFileOps{
-		renameFile: os.Rename,
-		removeFile: os.Remove,
-	}
-}
-
 var (
 	// Keep only the first fallback reason to avoid flooding stderr.
 	// Message count throttling is handled separately by debugLogFallbackMessageCount.
 	debugLogFallbackMu           sync.Mutex
 	debugLogFallbackLogged       bool
 	debugLogFallbackMessageCount int
-	pruneCountByDirMu            sync.Mutex
-	// Cache per-process rotated log counts to avoid repeated directory scans.
-	pruneCountByDir = map[string]int{}
+	// shimLogFiles caches one rotating log file per path so the rotated
+	// generations are counted once per process.
+	shimLogFiles sync.Map // path -> *logging.File
 )
 
 // debugLog writes shim debug info to a log file for troubleshooting.
 // Active log file: %LOCALAPPDATA%\myT-x\shim-debug.log
 // Rotated log file: %LOCALAPPDATA%\myT-x\shim-debug-<unixtime>.log
+// The level (default debug) and format follow MYTX_LOG_LEVEL and
+// MYTX_LOG_FORMAT.
 func debugLog(format string, args ...any) {
+	shimLog(slog.LevelDebug, "[DEBUG-SHIM] ", format, args...)
+}
+
+// warnLog is debugLog for failures worth keeping at a higher level.
+func warnLog(format string, args ...any) {
+	shimLog(slog.LevelWarn, "[WARN-SHIM] ", format, args...)
+}
+
+func shimLog(level slog.Level, tag string, format string, args ...any) {
 	message := fmt.Sprintf(format, args...)
 
 	localAppData := os.Getenv("LOCALAPPDATA")
@@ -63,27 +62,19 @@ func debugLog(format string, args ...any) {
 		debugLogFallbackMessage(message)
 		return
 	}
-	logDir := filepath.Join(localAppData, "myT-x")
-	if err := os.MkdirAll(logDir, 0o755); err != nil {
-		debugLogFallback(fmt.Errorf("create log directory %q: %w", logDir, err))
-		debugLogFallbackMessage(message)
-		return
-	}
-	logPath := filepath.Join(logDir, shimDebugLogFileName)
-	if err := rotateShimDebugLogIfNeeded(logPath, shimDebugLogMaxBytes, time.Now().Unix()); err != nil {
-		debugLogFallback(fmt.Errorf("rotate log file %q: %w", logPath, err))
-		debugLogFallbackMessage(message)
+	logPath := filepath.Join(localAppData, "myT-x", shimDebugLogFileName)
+	cached, _ := shimLogFiles.LoadOrStore(logPath, logging.NewFile(logPath, shimDebugLogMaxBytes, shimDebugLogKeepGenerations))
+	handler := logging.NewHandler(cached.(*logging.File), logging.FormatFromEnv(), logging.LevelFromEnv(slog.LevelDebug))
+	ctx := context.Background()
+	if !handler.Enabled(ctx, level) {
 		return
 	}
-	f, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
-	if err != nil {
-		debugLogFallback(fmt.Errorf("open log file %q: %w", logPath, err))
+	// Handle is called directly instead of through slog.Logger so a failing
+	// log file falls back to stderr instead of being dropped.
+	if err := handler.Handle(ctx, slog.NewRecord(time.Now(), level, tag+message, 0)); err != nil {
+		debugLogFallback(err)
 		debugLogFallbackMessage(message)
-		return
 	}
-	defer f.Close()
-	logger := log.New(f, "[DEBUG-SHIM] ", log.LstdFlags)
-	logger.Print(message)
 }
 
 func debugLogFallback(err error) {
@@ -135,19 +126,104 @@ func exitWithCode(code int) {
 func main() {
 	args := os.Args[1:]
 	debugLog("invoked: tmux %s", strings.Join(args, " "))
+	mode, args := splitControlModeArgs(args)
 
-	if len(args) == 0 {
+	if len(args) == 0 || args[0] == helpFlag {
 		printUsage()
 		flushDebugLogFallbackSummary()
 		return
 	}
 
-	req, err := parseCommand(args)
+	commands := splitCommandSequence(args)
+	if args[0] == stdinBatchFlag || len(commands) > 1 {
+		if mode != controlModeOff {
+			writeLineToStderr("control mode does not support command sequences")
+			exitWithCode(1)
+		}
+		exitWithCode(runBatchMode(args, commands))
+	}
+	if len(commands) == 0 {
+		printUsage()
+		flushDebugLogFallbackSummary()
+		return
+	}
+	args = commands[0]
+
+	req, skip, err := prepareRequest(args)
 	if err != nil {
-		debugLog("parse error: %v (args=%v)", err, args)
 		writeLineToStderr(err.Error())
 		exitWithCode(1)
 	}
+	if skip {
+		flushDebugLogFallbackSummary()
+		return
+	}
+	if mode != controlModeOff && !isControlModeCommand(req.Command) {
+		writeLineToStderr("control mode requires attach-session or new-session")
+		exitWithCode(1)
+	}
+	if err := checkNestedClient(req, mode != controlModeOff); err != nil {
+		writeLineToStderr(err.Error())
+		exitWithCode(1)
+	}
+
+	pipeName := ipc.DefaultPipeName()
+
+	// Ctrl+C asks the server to abort the request instead of leaving it
+	// running after the shim is gone; the shim still waits for the final
+	// response. A second Ctrl+C terminates the shim immediately.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	context.AfterFunc(ctx, stop)
+
+	if mode != controlModeOff {
+		code := runControlMode(ctx, mode, req, os.Stdin, os.Stdout, pipeControlModeTransport(pipeName))
+		stop()
+		exitWithCode(code)
+	}
+
+	// Long-running commands (run-shell) stream their output as it is
+	// produced; the final response carries only what was not streamed.
+	resp, err := ipc.SendStream(ctx, pipeName, req, os.Stdout, os.Stderr)
+	stop()
+	if err != nil {
+		warnLog("ipc error: %v", err)
+		if ipc.IsConnectionError(err) {
+			if req.Command == listCommandsCommand {
+				// Help stays available without a server, minus the myT-x notes.
+				renderLocalHelp(os.Stdout, req.Args)
+				flushDebugLogFallbackSummary()
+				return
+			}
+			writeToStderr("no server running on %s\n", pipeName)
+			exitWithCode(1)
+		}
+		writeLineToStderr(err.Error())
+		exitWithCode(1)
+	}
+
+	debugLog("response: exit=%d stdout=%q stderr=%q",
+		resp.ExitCode, truncate(resp.Stdout, 200), truncate(resp.Stderr, 200))
+
+	if resp.Stdout != "" {
+		writeToStdout(resp.Stdout)
+	}
+	if resp.Stderr != "" {
+		writeToStderr("%s", resp.Stderr)
+	}
+	exitWithCode(resp.ExitCode)
+}
+
+// prepareRequest parses args and applies the request transforms. skip is
+// true when lenient compat mode ignored the whole command.
+func prepareRequest(args []string) (req ipc.TmuxRequest, skip bool, err error) {
+	req, skip, err = parseWithCompat(args, loadTmuxCompatMode)
+	if err != nil {
+		debugLog("parse error: %v (args=%v)", err, args)
+		return ipc.TmuxRequest{}, false, err
+	}
+	if skip {
+		return ipc.TmuxRequest{}, true, nil
+	}
 
 	debugLog("parsed: command=%s flags=%s env=%v args=%v",
 		req.Command, flagsJSON(req.Flags), req.Env, req.Args)
@@ -175,30 +251,15 @@ func main() {
 		debugLog("model transform applied: command=%s args=%v", req.Command, req.Args)
 	}
 	debugLog("sending request after transform: %s", requestJSON(req))
+	return req, false, nil
+}
 
-	pipeName := ipc.DefaultPipeName()
-
-	resp, err := ipc.Send(pipeName, req)
-	if err != nil {
-		debugLog("ipc error: %v", err)
-		if ipc.IsConnectionError(err) {
-			writeToStderr("no server running on %s\n", pipeName)
-			exitWithCode(1)
-		}
-		writeLineToStderr(err.Error())
-		exitWithCode(1)
-	}
-
-	debugLog("response: exit=%d stdout=%q stderr=%q",
-		resp.ExitCode, truncate(resp.Stdout, 200), truncate(resp.Stderr, 200))
-
-	if resp.Stdout != "" {
-		writeToStdout(resp.Stdout)
-	}
-	if resp.Stderr != "" {
-		writeToStderr("%s", resp.Stderr)
-	}
-	exitWithCode(resp.ExitCode)
+// checkNestedClient refuses a command that would attach this client while it
+// already runs inside a session ($TMUX is set), as tmux does. Pane-relative
+// targets need no check here: the server resolves them against CallerPane.
+func checkNestedClient(req ipc.TmuxRequest, controlMode bool) error {
+	detached, _ := req.Flags["-d"].(bool)
+	return tmuxtarget.CheckNesting(req.Command, detached, controlMode, os.Getenv("TMUX") != "")
 }
 
 func flagsJSON(flags map[string]any) string {
@@ -227,7 +288,7 @@ func requestJSON(req ipc.TmuxRequest) string {
 
 func writeToStdout(message string) {
 	if _, err := fmt.Fprint(os.Stdout, message); err != nil {
-		debugLog("stdout write failed: %v", err)
+		warnLog("stdout write failed: %v", err)
 	}
 }
 
@@ -241,213 +302,6 @@ func writeLineToStderr(message string) {
 	writeToStderr("%s\n", message)
 }
 
-func rotateShimDebugLogIfNeeded(basePath string, maxBytes int64, unixTime int64) error {
-	return rotateShimDebugLogIfNeededWith(basePath, maxBytes, unixTime, defaultShimFileOps())
-}
-
-// rotateShimDebugLogIfNeededWith is the testable core of rotateShimDebugLogIfNeeded,
-// allowing tests to inject test doubles for file system operations.
-func rotateShimDebugLogIfNeededWith(basePath string, maxBytes int64, unixTime int64, fileOps shimFileOps) error {
-	info, err := os.Stat(basePath)
-	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			return nil
-		}
-		return err
-	}
-	if info.Size() < maxBytes {
-		return nil
-	}
-
-	logDir := filepath.Dir(basePath)
-	for retry := range 4 {
-		nextPath, err := nextRotatedShimDebugLogPath(logDir, unixTime+int64(retry))
-		if err != nil {
-			return err
-		}
-		err = fileOps.renameFile(basePath, nextPath)
-		if err == nil {
-			if shouldPruneRotatedShimDebugLogs(logDir, shimDebugLogKeepGenerations) {
-				if cleanupErr := pruneRotatedShimDebugLogsWith(logDir, shimDebugLogKeepGenerations, fileOps); cleanupErr != nil {
-					debugLogFallback(fmt.Errorf("prune rotated log files in %q: %w", logDir, cleanupErr))
-				} else {
-					markPrunedRotatedShimDebugLogs(logDir, shimDebugLogKeepGenerations)
-				}
-			}
-			return nil
-		}
-		if errors.Is(err, os.ErrExist) {
-			continue
-		}
-		if errors.Is(err, os.ErrNotExist) {
-			// Another process already rotated/deleted it.
-			return nil
-		}
-		return err
-	}
-	return fmt.Errorf("failed to rotate log file after retries: %s", basePath)
-}
-
-func nextRotatedShimDebugLogPath(logDir string, unixTime int64) (string, error) {
-	// keep=32 is the normal steady state, and 64 keeps 2x headroom for
-	// short timestamp collisions during concurrent rotations.
-	const maxAttempts = 64
-	for offset := range int64(maxAttempts) {
-		candidateUnix := unixTime + offset
-		candidate := filepath.Join(logDir, fmt.Sprintf("shim-debug-%d.log", candidateUnix))
-		_, err := os.Stat(candidate)
-		if errors.Is(err, os.ErrNotExist) {
-			return candidate, nil
-		}
-		if err != nil {
-			return "", err
-		}
-	}
-	return "", fmt.Errorf("unable to allocate rotated log path from unix=%d", unixTime)
-}
-
-// shouldPruneRotatedShimDebugLogs updates the per-directory generation count
-// and reports whether pruneRotatedShimDebugLogs should run.
-func shouldPruneRotatedShimDebugLogs(logDir string, keep int) bool {
-	if keep <= 0 {
-		return false
-	}
-
-	pruneCountByDirMu.Lock()
-	defer pruneCountByDirMu.Unlock()
-	if count, ok := pruneCountByDir[logDir]; ok {
-		count++
-		pruneCountByDir[logDir] = count
-		return count > keep
-	}
-
-	candidateCount, err := countRotatedShimDebugLogs(logDir)
-	if err != nil {
-		return true
-	}
-
-	pruneCountByDir[logDir] = candidateCount
-	return candidateCount > keep
-}
-
-func markPrunedRotatedShimDebugLogs(logDir string, keep int) {
-	if keep < 0 {
-		keep = 0
-	}
-	pruneCountByDirMu.Lock()
-	pruneCountByDir[logDir] = keep
-	pruneCountByDirMu.Unlock()
-}
-
-// countRotatedShimDebugLogs returns the number of valid shim-debug-<unix>.log
-// files in logDir.
-func countRotatedShimDebugLogs(logDir string) (int, error) {
-	entries, err := os.ReadDir(logDir)
-	if err != nil {
-		return 0, err
-	}
-
-	count := 0
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
-		}
-		if _, ok := parseRotatedShimDebugLogUnix(entry.Name()); ok {
-			count++
-		}
-	}
-	return count, nil
-}
-
-// parseRotatedShimDebugLogUnix parses shim-debug-<unix>.log and returns its
-// unix timestamp.
-func parseRotatedShimDebugLogUnix(path string) (int64, bool) {
-	name := filepath.Base(path)
-	if !strings.HasPrefix(name, "shim-debug-") || !strings.HasSuffix(name, ".log") {
-		return 0, false
-	}
-	timestampText := strings.TrimSuffix(strings.TrimPrefix(name, "shim-debug-"), ".log")
-	timestamp, err := strconv.ParseInt(timestampText, 10, 64)
-	if err != nil {
-		return 0, false
-	}
-	return timestamp, true
-}
-
-// pruneRotatedShimDebugLogs keeps the newest keep generations and removes
-// older rotated shim logs using default file operations.
-func pruneRotatedShimDebugLogs(logDir string, keep int) error {
-	return pruneRotatedShimDebugLogsWith(logDir, keep, defaultShimFileOps())
-}
-
-// pruneRotatedShimDebugLogsWith is the testable core of pruneRotatedShimDebugLogs,
-// allowing tests to inject test doubles for file system operations.
-//
-// IMPORTANT: This function MUST NOT call debugLog() because it is invoked from
-// the rotation path: debugLog -> rotateShimDebugLogIfNeeded -> pruneRotatedShimDebugLogs.
-// Calling debugLog here would create infinite recursion -> stack overflow (C-03).
-// Use pruneLogWarning() for any diagnostic output instead.
-func pruneRotatedShimDebugLogsWith(logDir string, keep int, fileOps shimFileOps) error {
-	if keep <= 0 {
-		return nil
-	}
-
-	entries, err := os.ReadDir(logDir)
-	if err != nil {
-		return err
-	}
-
-	type rotatedLog struct {
-		path      string
-		timestamp int64
-	}
-	logs := make([]rotatedLog, 0, len(entries))
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
-		}
-
-		name := entry.Name()
-		timestamp, ok := parseRotatedShimDebugLogUnix(name)
-		if !ok {
-			if strings.HasPrefix(name, "shim-debug-") && strings.HasSuffix(name, ".log") {
-				// Cannot use debugLog here — see function doc comment (C-03 recursion guard).
-				pruneLogWarning("skip rotated shim debug log with invalid unix timestamp: %s", name)
-			}
-			continue
-		}
-
-		logs = append(logs, rotatedLog{
-			path:      filepath.Join(logDir, name),
-			timestamp: timestamp,
-		})
-	}
-
-	sort.Slice(logs, func(i, j int) bool {
-		return logs[i].timestamp > logs[j].timestamp
-	})
-
-	var removeErrs []error
-	for i := keep; i < len(logs); i++ {
-		if err := fileOps.removeFile(logs[i].path); err != nil && !errors.Is(err, os.ErrNotExist) {
-			removeErrs = append(removeErrs, fmt.Errorf("remove %s: %w", logs[i].path, err))
-		}
-	}
-	return errors.Join(removeErrs...)
-}
-
-// pruneLogWarning writes a warning directly to stderr, bypassing debugLog
-// to avoid infinite recursion in the log rotation path (C-03).
-// This is intentionally best-effort: if stderr is unavailable the warning is silently dropped.
-// Uses fmt.Fprintf instead of log.New to avoid allocating a new Logger per call.
-func pruneLogWarning(format string, args ...any) {
-	msg := fmt.Sprintf(format, args...)
-	now := time.Now().Format("2006/01/02 15:04:05")
-	// NOTE: stderr write is best-effort for debug visibility only.
-	// Failure to write is silently ignored (shim must not block on diagnostics).
-	fmt.Fprintf(os.Stderr, "[DEBUG-SHIM] %s %s\n", now, msg)
-}
-
 // runTransformSafe executes one transform stage with panic recovery and request rollback.
 // When an error or panic occurs, the original request snapshot is restored.
 func runTransformSafe(name string, req *ipc.TmuxRequest, run func() (bool, error)) (changed bool, err error) {
@@ -458,7 +312,7 @@ func runTransformSafe(name string, req *ipc.TmuxRequest, run func() (bool, error
 
 	defer func() {
 		if recovered := recover(); recovered != nil {
-			debugLog("panic recovered in %s transform: %v\n%s", name, recovered, debug.Stack())
+			warnLog("panic recovered in %s transform: %v\n%s", name, recovered, debug.Stack())
 			err = fmt.Errorf("panic during %s transform: %v", name, recovered)
 		}
 		if err != nil {
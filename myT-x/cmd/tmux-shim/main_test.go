@@ -13,6 +13,7 @@ import (
 
 	"myT-x/internal/config"
 	"myT-x/internal/ipc"
+	"myT-x/internal/logging"
 	"myT-x/internal/tmux"
 )
 
@@ -48,9 +49,7 @@ func resetDebugLogFallbackState() {
 	debugLogFallbackLogged = false
 	debugLogFallbackMessageCount = 0
 	debugLogFallbackMu.Unlock()
-	pruneCountByDirMu.Lock()
-	pruneCountByDir = map[string]int{}
-	pruneCountByDirMu.Unlock()
+	shimLogFiles.Clear()
 }
 
 func prepareDebugLogFallbackState(t *testing.T) {
@@ -636,372 +635,42 @@ func TestParseCommandTrimsEnvKeyForEFlag(t *testing.T) {
 	}
 }
 
-func TestNextRotatedShimDebugLogPathIncrementsOnCollision(t *testing.T) {
-	logDir := t.TempDir()
-	startUnix := int64(1700000000)
+func TestDebugLogWritesLeveledFile(t *testing.T) {
+	localAppData := t.TempDir()
+	t.Setenv("LOCALAPPDATA", localAppData)
+	t.Setenv(logging.EnvLevel, "")
+	t.Setenv(logging.EnvFormat, "")
+	prepareDebugLogFallbackState(t)
 
-	collided0 := filepath.Join(logDir, "shim-debug-1700000000.log")
-	collided1 := filepath.Join(logDir, "shim-debug-1700000001.log")
-	if err := os.WriteFile(collided0, []byte("x"), 0o644); err != nil {
-		t.Fatalf("failed to create collision file 0: %v", err)
-	}
-	if err := os.WriteFile(collided1, []byte("x"), 0o644); err != nil {
-		t.Fatalf("failed to create collision file 1: %v", err)
-	}
+	debugLog("invoked: tmux %s", "list-panes")
+	warnLog("ipc error: %v", "broken pipe")
 
-	nextPath, err := nextRotatedShimDebugLogPath(logDir, startUnix)
+	data, err := os.ReadFile(filepath.Join(localAppData, "myT-x", shimDebugLogFileName))
 	if err != nil {
-		t.Fatalf("nextRotatedShimDebugLogPath() error = %v", err)
-	}
-	want := filepath.Join(logDir, "shim-debug-1700000002.log")
-	if nextPath != want {
-		t.Fatalf("next path = %q, want %q", nextPath, want)
-	}
-}
-
-func TestRotateShimDebugLogIfNeededScenarios(t *testing.T) {
-	t.Parallel()
-	tests := []struct {
-		name          string
-		unixTime      int64
-		basePayload   []byte
-		wantBase      bool
-		wantRotatedAt int64
-	}{
-		{
-			name:          "rotates at size limit",
-			unixTime:      1700000100,
-			basePayload:   bytes.Repeat([]byte("a"), shimDebugLogMaxBytes),
-			wantBase:      false,
-			wantRotatedAt: 1700000100,
-		},
-		{
-			name:          "no-op below size limit",
-			unixTime:      1700000200,
-			basePayload:   bytes.Repeat([]byte("a"), shimDebugLogMaxBytes-1),
-			wantBase:      true,
-			wantRotatedAt: 0,
-		},
-		{
-			name:          "rotates above size limit",
-			unixTime:      1700000250,
-			basePayload:   bytes.Repeat([]byte("a"), shimDebugLogMaxBytes+1),
-			wantBase:      false,
-			wantRotatedAt: 1700000250,
-		},
-		{
-			name:          "no-op when base file missing",
-			unixTime:      1700000300,
-			basePayload:   nil,
-			wantBase:      false,
-			wantRotatedAt: 0,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			t.Parallel()
-			logDir := t.TempDir()
-			basePath := filepath.Join(logDir, shimDebugLogFileName)
-			if tt.basePayload != nil {
-				if err := os.WriteFile(basePath, tt.basePayload, 0o644); err != nil {
-					t.Fatalf("failed to create base log: %v", err)
-				}
-			}
-
-			if err := rotateShimDebugLogIfNeeded(basePath, shimDebugLogMaxBytes, tt.unixTime); err != nil {
-				t.Fatalf("rotateShimDebugLogIfNeeded() error = %v", err)
-			}
-
-			_, baseErr := os.Stat(basePath)
-			if tt.wantBase {
-				if baseErr != nil {
-					t.Fatalf("base log should remain, stat err = %v", baseErr)
-				}
-			} else if !errors.Is(baseErr, os.ErrNotExist) {
-				t.Fatalf("base log should be absent, stat err = %v", baseErr)
-			}
-
-			rotatedPath := filepath.Join(logDir, fmt.Sprintf("shim-debug-%d.log", tt.unixTime))
-			_, rotatedErr := os.Stat(rotatedPath)
-			if tt.wantRotatedAt > 0 {
-				if rotatedErr != nil {
-					t.Fatalf("rotated log missing: %v", rotatedErr)
-				}
-			} else if !errors.Is(rotatedErr, os.ErrNotExist) {
-				t.Fatalf("rotated log should not exist, stat err = %v", rotatedErr)
-			}
-		})
-	}
-}
-
-func TestRotateShimDebugLogIfNeededRetriesOnRenameCollision(t *testing.T) {
-	t.Parallel()
-
-	logDir := t.TempDir()
-	basePath := filepath.Join(logDir, shimDebugLogFileName)
-	if err := os.WriteFile(basePath, bytes.Repeat([]byte("a"), shimDebugLogMaxBytes), 0o644); err != nil {
-		t.Fatalf("failed to create base log: %v", err)
-	}
-
-	renameCalls := 0
-	ops := shimFileOps{
-		renameFile: func(oldPath, newPath string) error {
-			renameCalls++
-			if renameCalls < 3 {
-				return os.ErrExist
-			}
-			return os.Rename(oldPath, newPath)
-		},
-		removeFile: os.Remove,
-	}
-
-	const unixTime = int64(1700002100)
-	if err := rotateShimDebugLogIfNeededWith(basePath, shimDebugLogMaxBytes, unixTime, ops); err != nil {
-		t.Fatalf("rotateShimDebugLogIfNeededWith() error = %v", err)
+		t.Fatalf("ReadFile() error = %v", err)
 	}
-	if renameCalls != 3 {
-		t.Fatalf("rename call count = %d, want 3", renameCalls)
+	got := string(data)
+	if !strings.Contains(got, "level=DEBUG") || !strings.Contains(got, "[DEBUG-SHIM] invoked: tmux list-panes") {
+		t.Fatalf("log = %q, want debug line", got)
 	}
-
-	wantRotated := filepath.Join(logDir, "shim-debug-1700002102.log")
-	if _, err := os.Stat(wantRotated); err != nil {
-		t.Fatalf("expected rotated log %q, stat err = %v", wantRotated, err)
-	}
-}
-
-func TestRotateShimDebugLogIfNeededFailsAfterMaxRenameRetries(t *testing.T) {
-	t.Parallel()
-
-	logDir := t.TempDir()
-	basePath := filepath.Join(logDir, shimDebugLogFileName)
-	if err := os.WriteFile(basePath, bytes.Repeat([]byte("a"), shimDebugLogMaxBytes), 0o644); err != nil {
-		t.Fatalf("failed to create base log: %v", err)
+	if !strings.Contains(got, "level=WARN") || !strings.Contains(got, "[WARN-SHIM] ipc error: broken pipe") {
+		t.Fatalf("log = %q, want warn line", got)
 	}
 
-	renameCalls := 0
-	ops := shimFileOps{
-		renameFile: func(_, _ string) error {
-			renameCalls++
-			return os.ErrExist
-		},
-		removeFile: os.Remove,
-	}
-
-	err := rotateShimDebugLogIfNeededWith(basePath, shimDebugLogMaxBytes, 1700002150, ops)
-	if err == nil {
-		t.Fatal("rotateShimDebugLogIfNeededWith() expected retry exhaustion error")
-	}
-	if renameCalls != 4 {
-		t.Fatalf("rename call count = %d, want 4", renameCalls)
-	}
-}
-
-func TestRotateShimDebugLogIfNeededPrunesOldGenerations(t *testing.T) {
-	logDir := t.TempDir()
-	basePath := filepath.Join(logDir, shimDebugLogFileName)
-	payload := bytes.Repeat([]byte("a"), shimDebugLogMaxBytes)
-	if err := os.WriteFile(basePath, payload, 0o644); err != nil {
-		t.Fatalf("failed to create base log: %v", err)
-	}
-
-	for ts := int64(1700001000); ts < 1700001048; ts++ {
-		path := filepath.Join(logDir, fmt.Sprintf("shim-debug-%d.log", ts))
-		if err := os.WriteFile(path, []byte("old"), 0o644); err != nil {
-			t.Fatalf("failed to create rotated log %s: %v", path, err)
-		}
-	}
-
-	if err := rotateShimDebugLogIfNeeded(basePath, shimDebugLogMaxBytes, 1700002000); err != nil {
-		t.Fatalf("rotateShimDebugLogIfNeeded() error = %v", err)
-	}
-
-	rotated, err := filepath.Glob(filepath.Join(logDir, "shim-debug-*.log"))
+	t.Setenv(logging.EnvLevel, "warn")
+	t.Setenv(logging.EnvFormat, "json")
+	debugLog("suppressed")
+	warnLog("kept")
+	data, err = os.ReadFile(filepath.Join(localAppData, "myT-x", shimDebugLogFileName))
 	if err != nil {
-		t.Fatalf("Glob() error = %v", err)
-	}
-	if len(rotated) != shimDebugLogKeepGenerations {
-		t.Fatalf("rotated log count = %d, want %d", len(rotated), shimDebugLogKeepGenerations)
+		t.Fatalf("ReadFile() error = %v", err)
 	}
-
-	newest := filepath.Join(logDir, "shim-debug-1700002000.log")
-	if _, statErr := os.Stat(newest); statErr != nil {
-		t.Fatalf("newest rotated log missing: %v", statErr)
+	got = string(data)
+	if strings.Contains(got, "suppressed") {
+		t.Fatalf("log = %q, debug line should be filtered at warn level", got)
 	}
-}
-
-func TestPruneRotatedShimDebugLogsContinuesAfterRemoveError(t *testing.T) {
-	t.Parallel()
-
-	logDir := t.TempDir()
-	log1 := filepath.Join(logDir, "shim-debug-1.log")
-	log2 := filepath.Join(logDir, "shim-debug-2.log")
-	log3 := filepath.Join(logDir, "shim-debug-3.log")
-	for _, path := range []string{log1, log2, log3} {
-		if err := os.WriteFile(path, []byte("old"), 0o644); err != nil {
-			t.Fatalf("failed to create rotated log %s: %v", path, err)
-		}
-	}
-
-	var removed []string
-	ops := shimFileOps{
-		renameFile: os.Rename,
-		removeFile: func(path string) error {
-			removed = append(removed, filepath.Base(path))
-			if strings.HasSuffix(path, "shim-debug-2.log") {
-				return errors.New("simulated remove failure")
-			}
-			return os.Remove(path)
-		},
-	}
-
-	err := pruneRotatedShimDebugLogsWith(logDir, 1, ops)
-	if err == nil {
-		t.Fatal("pruneRotatedShimDebugLogsWith() expected aggregated remove error")
-	}
-	if len(removed) != 2 {
-		t.Fatalf("remove calls = %v, want 2 files", removed)
-	}
-
-	if _, statErr := os.Stat(log2); errors.Is(statErr, os.ErrNotExist) {
-		t.Fatalf("failed file should remain: %s", log2)
-	}
-	if _, statErr := os.Stat(log1); !errors.Is(statErr, os.ErrNotExist) {
-		t.Fatalf("other old file should still be pruned, stat err = %v", statErr)
-	}
-}
-
-func TestPruneRotatedShimDebugLogsNoopWhenKeepIsNonPositive(t *testing.T) {
-	logDir := t.TempDir()
-	logPath := filepath.Join(logDir, "shim-debug-1.log")
-	if err := os.WriteFile(logPath, []byte("old"), 0o644); err != nil {
-		t.Fatalf("failed to create rotated log: %v", err)
-	}
-
-	if err := pruneRotatedShimDebugLogs(logDir, 0); err != nil {
-		t.Fatalf("pruneRotatedShimDebugLogs() error = %v", err)
-	}
-	if _, err := os.Stat(logPath); err != nil {
-		t.Fatalf("rotated log should remain for keep<=0: %v", err)
-	}
-}
-
-func TestShouldPruneRotatedShimDebugLogsSkipsBelowLimit(t *testing.T) {
-	prepareDebugLogFallbackState(t)
-
-	logDir := t.TempDir()
-	rotatedPath := filepath.Join(logDir, "shim-debug-1700001001.log")
-	if err := os.WriteFile(rotatedPath, []byte("new"), 0o644); err != nil {
-		t.Fatalf("failed to create rotated log: %v", err)
-	}
-
-	shouldPrune := shouldPruneRotatedShimDebugLogs(logDir, 32)
-	if shouldPrune {
-		t.Fatal("shouldPruneRotatedShimDebugLogs() = true, want false below keep limit")
-	}
-}
-
-func TestShouldPruneRotatedShimDebugLogsUsesCachedCountPerDirectory(t *testing.T) {
-	prepareDebugLogFallbackState(t)
-
-	logDir := t.TempDir()
-	path1 := filepath.Join(logDir, "shim-debug-1700001001.log")
-	path2 := filepath.Join(logDir, "shim-debug-1700001002.log")
-	path3 := filepath.Join(logDir, "shim-debug-1700001003.log")
-
-	if err := os.WriteFile(path1, []byte("new"), 0o644); err != nil {
-		t.Fatalf("failed to create rotated log %s: %v", path1, err)
-	}
-
-	if shouldPruneRotatedShimDebugLogs(logDir, 2) {
-		t.Fatal("first check should not prune at keep limit")
-	}
-	if err := os.WriteFile(path2, []byte("new"), 0o644); err != nil {
-		t.Fatalf("failed to create rotated log %s: %v", path2, err)
-	}
-	if shouldPruneRotatedShimDebugLogs(logDir, 2) {
-		t.Fatal("second check should not prune at keep limit")
-	}
-	if err := os.WriteFile(path3, []byte("new"), 0o644); err != nil {
-		t.Fatalf("failed to create rotated log %s: %v", path3, err)
-	}
-	if !shouldPruneRotatedShimDebugLogs(logDir, 2) {
-		t.Fatal("third check should prune when cached count exceeds keep")
-	}
-}
-
-func TestNextRotatedShimDebugLogPathFailsWhenAttemptsExhausted(t *testing.T) {
-	logDir := t.TempDir()
-	startUnix := int64(1700003000)
-	for ts := startUnix; ts < startUnix+64; ts++ {
-		path := filepath.Join(logDir, fmt.Sprintf("shim-debug-%d.log", ts))
-		if err := os.WriteFile(path, []byte("occupied"), 0o644); err != nil {
-			t.Fatalf("failed to create occupied path %s: %v", path, err)
-		}
-	}
-
-	if _, err := nextRotatedShimDebugLogPath(logDir, startUnix); err == nil {
-		t.Fatal("nextRotatedShimDebugLogPath() expected exhaustion error")
-	}
-}
-
-func TestNextRotatedShimDebugLogPathReturnsErrorForInvalidLogDir(t *testing.T) {
-	if _, err := nextRotatedShimDebugLogPath(string([]byte{0}), 1700004000); err == nil {
-		t.Fatal("nextRotatedShimDebugLogPath() expected stat error")
-	}
-}
-
-func TestParseRotatedShimDebugLogUnix(t *testing.T) {
-	tests := []struct {
-		name      string
-		path      string
-		wantOK    bool
-		wantValue int64
-	}{
-		{
-			name:      "valid filename",
-			path:      "shim-debug-1700000123.log",
-			wantOK:    true,
-			wantValue: 1700000123,
-		},
-		{
-			name:      "valid path with directory",
-			path:      filepath.Join("C:\\logs", "shim-debug-1700000456.log"),
-			wantOK:    true,
-			wantValue: 1700000456,
-		},
-		{
-			name:   "invalid prefix",
-			path:   "debug-1700000123.log",
-			wantOK: false,
-		},
-		{
-			name:   "invalid suffix",
-			path:   "shim-debug-1700000123.txt",
-			wantOK: false,
-		},
-		{
-			name:   "missing timestamp",
-			path:   "shim-debug-.log",
-			wantOK: false,
-		},
-		{
-			name:   "non numeric timestamp",
-			path:   "shim-debug-abc.log",
-			wantOK: false,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			gotValue, gotOK := parseRotatedShimDebugLogUnix(tt.path)
-			if gotOK != tt.wantOK {
-				t.Fatalf("parseRotatedShimDebugLogUnix(%q) ok = %v, want %v", tt.path, gotOK, tt.wantOK)
-			}
-			if gotValue != tt.wantValue {
-				t.Fatalf("parseRotatedShimDebugLogUnix(%q) value = %d, want %d", tt.path, gotValue, tt.wantValue)
-			}
-		})
+	if !strings.Contains(got, `"msg":"[WARN-SHIM] kept"`) {
+		t.Fatalf("log = %q, want JSON warn line", got)
 	}
 }
 
@@ -1678,8 +1347,8 @@ func TestParseCommandAttachSession(t *testing.T) {
 }
 
 func TestTmuxRequestStructFieldCountForCloneTransformRequest(t *testing.T) {
-	if got := reflect.TypeFor[ipc.TmuxRequest]().NumField(); got != 5 {
-		t.Fatalf("ipc.TmuxRequest field count = %d, want 5 (command, flags, args, env, caller_pane)", got)
+	if got := reflect.TypeFor[ipc.TmuxRequest]().NumField(); got != 10 {
+		t.Fatalf("ipc.TmuxRequest field count = %d, want 10 (command, flags, args, env, caller_pane, stream, id, keep_open, token, caller_pid)", got)
 	}
 }
 
@@ -2239,56 +1908,62 @@ func TestShowOptionsParserConsistencyWithInternalParser(t *testing.T) {
 // NOTE: resize-pane direction flag scenarios are consolidated into
 // TestParseCommandNewCommands to avoid test duplication (I-20).
 
-// C-03: Verify pruneRotatedShimDebugLogs does not recurse through debugLog.
-// Before the fix, an invalid filename (shim-debug-abc.log) would trigger
-// debugLog -> rotateShimDebugLogIfNeeded -> pruneRotatedShimDebugLogs -> debugLog
-// creating infinite recursion. After the fix, pruneLogWarning writes to stderr
-// directly without going through debugLog.
-func TestPruneRotatedShimDebugLogsDoesNotRecurseThroughDebugLog(t *testing.T) {
-	prepareDebugLogFallbackState(t)
-
-	logDir := t.TempDir()
-
-	// Create a valid rotated log to keep.
-	validLog := filepath.Join(logDir, "shim-debug-1700005000.log")
-	if err := os.WriteFile(validLog, []byte("valid"), 0o644); err != nil {
-		t.Fatalf("failed to create valid rotated log: %v", err)
+func TestParseCommandHelpFlag(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{name: "right after command", args: []string{"new-window", "--help"}, want: "new-window"},
+		{name: "after other flags", args: []string{"split-window", "-t", "%1", "--help"}, want: "split-window"},
+		{name: "alias is canonicalized", args: []string{"show", "--help"}, want: "show-options"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := parseCommand(tt.args)
+			if err != nil {
+				t.Fatalf("parseCommand(%v) error = %v", tt.args, err)
+			}
+			if req.Command != listCommandsCommand || len(req.Args) != 1 || req.Args[0] != tt.want {
+				t.Fatalf("parseCommand(%v) = %+v, want list-commands %s", tt.args, req, tt.want)
+			}
+		})
 	}
 
-	// Create a file with invalid timestamp that triggers the warning path.
-	invalidLog := filepath.Join(logDir, "shim-debug-notanumber.log")
-	if err := os.WriteFile(invalidLog, []byte("invalid"), 0o644); err != nil {
-		t.Fatalf("failed to create invalid rotated log: %v", err)
+	// A positional "--help" is an ordinary argument.
+	req, err := parseCommand([]string{"send-keys", "-t", "%1", "echo", "--help"})
+	if err != nil || req.Command != "send-keys" || !reflect.DeepEqual(req.Args, []string{"echo", "--help"}) {
+		t.Fatalf("parseCommand(send-keys ... echo --help) = %+v, %v", req, err)
 	}
+}
 
-	// If pruneRotatedShimDebugLogs still called debugLog, this would recurse
-	// infinitely when debugLog triggers rotation. The fact that this returns
-	// without stack overflow proves the recursion is broken.
-	output := captureStderr(t, func() {
-		err := pruneRotatedShimDebugLogs(logDir, 10)
-		if err != nil {
-			t.Fatalf("pruneRotatedShimDebugLogs() unexpected error = %v", err)
+func TestServerHasHelpForEveryShimCommand(t *testing.T) {
+	router := tmux.NewCommandRouter(tmux.NewSessionManager(), nil, tmux.RouterOptions{})
+	for _, name := range commandOrder {
+		req := helpRequest(canonicalShimCommandName(name))
+		if resp := router.Execute(req); resp.ExitCode != 0 {
+			t.Errorf("list-commands %s: exit %d, stderr %q", name, resp.ExitCode, resp.Stderr)
 		}
-	})
-
-	// Verify the warning was written to stderr (via pruneLogWarning, not debugLog).
-	if !strings.Contains(output, "skip rotated shim debug log with invalid unix timestamp") {
-		t.Fatalf("stderr output = %q, want warning about invalid timestamp", output)
-	}
-	if !strings.Contains(output, "shim-debug-notanumber.log") {
-		t.Fatalf("stderr output = %q, want filename in warning", output)
 	}
 }
 
-// C-03: Verify pruneLogWarning writes to stderr without going through debugLog.
-func TestPruneLogWarningWritesToStderr(t *testing.T) {
-	output := captureStderr(t, func() {
-		pruneLogWarning("test warning: %s", "hello")
-	})
-	if !strings.Contains(output, "[DEBUG-SHIM]") {
-		t.Fatalf("stderr output = %q, want [DEBUG-SHIM] prefix", output)
+func TestRenderLocalHelp(t *testing.T) {
+	var output bytes.Buffer
+	renderLocalHelp(&output, []string{"show"})
+	if !strings.Contains(output.String(), "show: "+commandSpecs["show-options"].description) ||
+		!strings.Contains(output.String(), "not running") {
+		t.Fatalf("renderLocalHelp(show) = %q", output.String())
 	}
-	if !strings.Contains(output, "test warning: hello") {
-		t.Fatalf("stderr output = %q, want formatted message", output)
+
+	output.Reset()
+	renderLocalHelp(&output, []string{"choose-tree"})
+	if got := output.String(); got != "unknown command: choose-tree\n" {
+		t.Fatalf("renderLocalHelp(choose-tree) = %q", got)
+	}
+
+	output.Reset()
+	renderLocalHelp(&output, nil)
+	if !strings.Contains(output.String(), "Supported commands:") {
+		t.Fatalf("renderLocalHelp(nil) = %q, want the usage", output.String())
 	}
 }
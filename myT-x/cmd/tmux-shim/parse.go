@@ -9,14 +9,23 @@ import (
 	"myT-x/internal/ipc"
 )
 
+// parseCommand parses args strictly: an unsupported command or flag fails
+// with a *compatIssue.
 func parseCommand(args []string) (ipc.TmuxRequest, error) {
+	req, _, err := parseCommandMode(args, false)
+	return req, err
+}
+
+// parseCommandMode parses args. When lenient, unsupported flags are dropped
+// and returned as issues instead of failing the parse.
+func parseCommandMode(args []string, lenient bool) (ipc.TmuxRequest, []*compatIssue, error) {
 	if len(args) == 0 {
-		return ipc.TmuxRequest{}, fmt.Errorf("command is required")
+		return ipc.TmuxRequest{}, nil, fmt.Errorf("command is required")
 	}
 
 	name := strings.TrimSpace(args[0])
 	if name == "" {
-		return ipc.TmuxRequest{}, fmt.Errorf("command is required")
+		return ipc.TmuxRequest{}, nil, fmt.Errorf("command is required")
 	}
 
 	canonicalName := canonicalShimCommandName(name)
@@ -25,7 +34,7 @@ func parseCommand(args []string) (ipc.TmuxRequest, error) {
 		spec, ok = commandSpecs[canonicalName]
 	}
 	if !ok {
-		return ipc.TmuxRequest{}, fmt.Errorf("unknown command: %s", name)
+		return ipc.TmuxRequest{}, nil, &compatIssue{Command: name}
 	}
 
 	req := ipc.TmuxRequest{
@@ -34,16 +43,21 @@ func parseCommand(args []string) (ipc.TmuxRequest, error) {
 		Env:     map[string]string{},
 	}
 
+	var issues []*compatIssue
 	i := 1
 	for i < len(args) {
 		arg := args[i]
 		if arg == "--" {
 			req.Args = append(req.Args, args[i+1:]...)
-			return req, validateRequired(req.Command, req)
+			return req, issues, validateRequired(req.Command, req)
 		}
 		if !strings.HasPrefix(arg, "-") || arg == "-" {
 			req.Args = append(req.Args, args[i:]...)
-			return req, validateRequired(req.Command, req)
+			return req, issues, validateRequired(req.Command, req)
+		}
+
+		if arg == helpFlag {
+			return helpRequest(canonicalName), nil, nil
 		}
 
 		kind, known := spec.flags[arg]
@@ -56,7 +70,17 @@ func parseCommand(args []string) (ipc.TmuxRequest, error) {
 				i++
 				continue
 			}
-			return ipc.TmuxRequest{}, fmt.Errorf("unsupported flag %s for %s", arg, name)
+			issue := &compatIssue{Command: canonicalName, Flag: unknownFlagName(spec, arg)}
+			if !lenient {
+				return ipc.TmuxRequest{}, nil, issue
+			}
+			// Keep the known flags of a combined argument like -dZ.
+			issues = append(issues, issue)
+			for _, flag := range knownCombinedBoolFlags(spec, arg) {
+				req.Flags[flag] = true
+			}
+			i++
+			continue
 		}
 
 		switch kind {
@@ -65,37 +89,48 @@ func parseCommand(args []string) (ipc.TmuxRequest, error) {
 			i++
 		case flagString:
 			if i+1 >= len(args) {
-				return ipc.TmuxRequest{}, fmt.Errorf("flag %s requires a value", arg)
+				return ipc.TmuxRequest{}, nil, fmt.Errorf("flag %s requires a value", arg)
 			}
 			req.Flags[arg] = args[i+1]
 			i += 2
 		case flagInt:
 			if i+1 >= len(args) {
-				return ipc.TmuxRequest{}, fmt.Errorf("flag %s requires a value", arg)
+				return ipc.TmuxRequest{}, nil, fmt.Errorf("flag %s requires a value", arg)
 			}
 			value, err := strconv.Atoi(args[i+1])
 			if err != nil {
-				return ipc.TmuxRequest{}, fmt.Errorf("flag %s expects integer, got %q", arg, args[i+1])
+				return ipc.TmuxRequest{}, nil, fmt.Errorf("flag %s expects integer, got %q", arg, args[i+1])
 			}
 			req.Flags[arg] = value
 			i += 2
 		case flagEnv:
 			if i+1 >= len(args) {
-				return ipc.TmuxRequest{}, fmt.Errorf("flag %s requires KEY=VALUE", arg)
+				return ipc.TmuxRequest{}, nil, fmt.Errorf("flag %s requires KEY=VALUE", arg)
 			}
 			key, value, ok := strings.Cut(args[i+1], "=")
 			key = strings.TrimSpace(key)
 			if !ok || key == "" {
-				return ipc.TmuxRequest{}, fmt.Errorf("invalid env: %s", args[i+1])
+				return ipc.TmuxRequest{}, nil, fmt.Errorf("invalid env: %s", args[i+1])
 			}
 			req.Env[key] = value
 			i += 2
 		default:
-			return ipc.TmuxRequest{}, errors.New("unsupported flag parser")
+			return ipc.TmuxRequest{}, nil, errors.New("unsupported flag parser")
 		}
 	}
 
-	return req, validateRequired(req.Command, req)
+	return req, issues, validateRequired(req.Command, req)
+}
+
+// helpRequest asks the server for the help of command: its usage and the
+// myT-x notes on unsupported flags and differences from tmux.
+func helpRequest(command string) ipc.TmuxRequest {
+	return ipc.TmuxRequest{
+		Command: listCommandsCommand,
+		Flags:   map[string]any{},
+		Env:     map[string]string{},
+		Args:    []string{command},
+	}
 }
 
 func canonicalShimCommandName(name string) string {
@@ -250,3 +285,33 @@ func expandCombinedFlags(spec commandSpec, arg string) ([]string, bool) {
 	}
 	return flags, true
 }
+
+// unknownFlagName returns the flag real tmux reports for arg: the first
+// character of a combined argument that is not a flag of the command.
+func unknownFlagName(spec commandSpec, arg string) string {
+	if len(arg) < 3 || strings.HasPrefix(arg, "--") {
+		return arg
+	}
+	for _, ch := range arg[1:] {
+		if _, known := spec.flags["-"+string(ch)]; !known {
+			return "-" + string(ch)
+		}
+	}
+	return arg
+}
+
+// knownCombinedBoolFlags returns the known bool flags of a combined
+// argument like -dZ, skipping the rest.
+func knownCombinedBoolFlags(spec commandSpec, arg string) []string {
+	if len(arg) < 3 || strings.HasPrefix(arg, "--") {
+		return nil
+	}
+	var flags []string
+	for _, ch := range arg[1:] {
+		flag := "-" + string(ch)
+		if kind, known := spec.flags[flag]; known && kind == flagBool {
+			flags = append(flags, flag)
+		}
+	}
+	return flags
+}
@@ -304,7 +304,7 @@ var commandSpecs = map[string]commandSpec{
 		},
 	},
 	"capture-pane": {
-		description: "Capture pane output. Use -p to print and -S/-E to choose line range.",
+		description: "Capture pane output. Use -p to print, -S/-E to choose line range, and -J to join wrapped lines.",
 		flags: map[string]flagKind{
 			"-a": flagBool,
 			"-b": flagString,
@@ -339,8 +339,26 @@ var commandSpecs = map[string]commandSpec{
 			"-t": flagString, // target pane (for format context)
 		},
 	},
+	"copy-between-sessions": {
+		description: "Copy a file or directory from one session's directory to another's (myT-x).",
+		flags: map[string]flagKind{
+			"-s": flagString, // source session (default: the calling pane's)
+			"-t": flagString, // target session
+		},
+	},
+	listCommandsCommand: {
+		description: "List supported commands, or show usage and myT-x notes of one command.",
+		flags:       map[string]flagKind{},
+	},
 }
 
+// helpFlag in place of a flag prints the help of the command instead of
+// running it.
+const helpFlag = "--help"
+
+// listCommandsCommand is answered by the server from its command registry.
+const listCommandsCommand = "list-commands"
+
 var commandOrder = []string{
 	"new-session",
 	"has-session",
@@ -376,6 +394,8 @@ var commandOrder = []string{
 	"capture-pane",
 	"run-shell",
 	"if-shell",
+	"copy-between-sessions",
+	listCommandsCommand,
 }
 
 func validateCommandSpecConsistency() error {
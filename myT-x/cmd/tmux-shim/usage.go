@@ -15,10 +15,29 @@ func renderUsage(w io.Writer) {
 	const commandPadding = 18
 
 	_, _ = fmt.Fprintln(w, "tmux shim for myT-x")
-	_, _ = fmt.Fprintln(w, "Usage: tmux <command> [flags] [args]")
+	_, _ = fmt.Fprintln(w, "Usage: tmux [-C|-CC] <command> [flags] [args]")
+	_, _ = fmt.Fprintln(w, "       tmux <command> [flags] [args] \\; <command> ...")
+	_, _ = fmt.Fprintf(w, "       tmux %s  (read commands from stdin, one per line)\n", stdinBatchFlag)
 	_, _ = fmt.Fprintln(w, "Supported commands:")
 	for _, name := range commandOrder {
 		description := commandSpecs[name].description
 		_, _ = fmt.Fprintf(w, "  %-*s  %s\n", commandPadding, name, description)
 	}
+	_, _ = fmt.Fprintf(w, "Run 'tmux <command> %s' for the usage and myT-x notes of a command.\n", helpFlag)
+}
+
+// renderLocalHelp is the list-commands output when no server is running:
+// the shim only knows the one-line descriptions of its commands.
+func renderLocalHelp(w io.Writer, args []string) {
+	if len(args) == 0 {
+		renderUsage(w)
+		return
+	}
+	spec, ok := commandSpecs[canonicalShimCommandName(args[0])]
+	if !ok {
+		_, _ = fmt.Fprintf(w, "unknown command: %s\n", args[0])
+		return
+	}
+	_, _ = fmt.Fprintf(w, "%s: %s\n", args[0], spec.description)
+	_, _ = fmt.Fprintln(w, "(myT-x is not running; start it to see the full usage and notes)")
 }
@@ -1,6 +1,7 @@
 import {type CSSProperties, useCallback, useEffect, useMemo, useRef, useState} from "react";
 import "@xterm/xterm/css/xterm.css";
 import {api} from "./api";
+import {CommandApprovalPanel} from "./components/CommandApprovalPanel";
 import {ConfirmDialog} from "./components/ConfirmDialog";
 import {MenuBar} from "./components/MenuBar";
 import {QuickSearch} from "./components/QuickSearch";
@@ -25,8 +26,10 @@ import {useAppImeRecovery} from "./hooks/useAppImeRecovery";
 import {useBackendSync} from "./hooks/useBackendSync";
 import {useFileDrop} from "./hooks/useFileDrop";
 import {usePrefixKeyMode} from "./hooks/usePrefixKeyMode";
-import {useI18n} from "./i18n";
+import {getLanguage, useI18n} from "./i18n";
+import {useNotificationStore} from "./stores/notificationStore";
 import {useTmuxStore} from "./stores/tmuxStore";
+import {EventsOn} from "../wailsjs/runtime/runtime";
 import type {ValidationRules} from "./types/tmux";
 import {isImeTransitionalEvent} from "./utils/ime";
 import {notifyAndLog} from "./utils/notifyUtils";
@@ -43,6 +46,7 @@ function App() {
     const [quickSearchOpen, setQuickSearchOpen] = useState(false);
     const [quickSearchTriggerMode, setQuickSearchTriggerMode] = useState<QuickSearchTriggerMode>("palette");
     const [newSessionSignal, setNewSessionSignal] = useState(0);
+    const [newSessionWorktree, setNewSessionWorktree] = useState(false);
     const [settingsOpen, setSettingsOpen] = useState(false);
     const [validationRules, setValidationRules] = useState<ValidationRules | null>(null);
     const [windowWidth, setWindowWidth] = useState(readWindowWidth);
@@ -103,9 +107,25 @@ function App() {
         setQuickSearchOpen(true);
     }, []);
     const handleOpenNewSession = useCallback(() => {
+        setNewSessionWorktree(false);
         setNewSessionSignal((currentSignal) => currentSignal + 1);
     }, []);
 
+    // Taskbar jump list tasks arrive as mytx://action/... links.
+    useEffect(() => {
+        return EventsOn("app:deep-link-action", (payload: unknown) => {
+            const action = (payload as {action?: unknown} | null)?.action;
+            if (action !== "new-session" && action !== "new-worktree-session") {
+                if (import.meta.env.DEV) {
+                    console.warn("[app] deep-link-action: unknown action", payload);
+                }
+                return;
+            }
+            setNewSessionWorktree(action === "new-worktree-session");
+            setNewSessionSignal((currentSignal) => currentSignal + 1);
+        });
+    }, []);
+
     useEffect(() => {
         let cancelled = false;
         void api.GetValidationRules()
@@ -124,6 +144,34 @@ function App() {
         };
     }, []);
 
+    // After an update, show what changed once.
+    useEffect(() => {
+        void api.GetChangelog()
+            .then((payload) => {
+                if (!payload.updated) {
+                    return;
+                }
+                const language = getLanguage();
+                const changes = (payload.releases ?? [])
+                    .flatMap((release) => release.changes ?? [])
+                    .map((change) => (language === "en" ? change.en : change.ja))
+                    .join(" / ");
+                if (changes !== "") {
+                    useNotificationStore.getState().addNotification(
+                        t("app.changelog.updated", "v{version} に更新しました: {changes}", {
+                            version: payload.current_version,
+                            changes,
+                        }),
+                        "info",
+                    );
+                }
+                return api.AcknowledgeChangelog();
+            })
+            .catch((err: unknown) => {
+                console.warn("[app] changelog check failed (non-fatal)", err);
+            });
+    }, [t]);
+
     useEffect(() => {
         let resizeFrameID: number | null = null;
         const handleResize = () => {
@@ -207,6 +255,7 @@ function App() {
                         sessions={sessions}
                         activeSession={current?.name ?? null}
                         newSessionSignal={newSessionSignal}
+                        newSessionWorktree={newSessionWorktree}
                     />
                     <main className="main-content">
                         <ChatLayout
@@ -225,6 +274,7 @@ function App() {
             </div>
             <SettingsModal open={settingsOpen} onClose={handleCloseSettings}/>
             <ToastContainer/>
+            <CommandApprovalPanel/>
             <QuickSearch
                 open={quickSearchOpen}
                 onClose={handleCloseQuickSearch}
@@ -15,18 +15,38 @@
  * - このファイル内では Promise を握り潰さない。Promise はそのまま返却する。
  */
 import {
+    AcknowledgeChangelog,
+    AddOutputWatch,
+    AddSessionToGroup,
     AddSingleTaskRunnerItem,
+    AdoptExternalWorktree,
+    ApplyConfigPatch,
     ApplyLayoutPreset,
+    BringUpSessions,
+    BrowseForDirectory,
     BuildStatusLine,
+    CancelBringUp,
+    CancelCommandQueue,
     CheckDirectoryConflict,
+    CheckWorktreeBase,
     CheckWorktreePathConflict,
     CheckWorktreeStatus,
+    CleanupRepoHygiene,
     CleanupWorktree,
+    ClearNetworkCaptures,
+    CollapseIdlePanes,
+    CollapsePane,
     CommitAndPushWorktree,
+    CopyBetweenSessions,
+    CreateBugReport,
     CreatePaneInSession,
+    CreatePullRequestForSession,
     CreateSession,
+    CreateSessionFromTemplate,
+    CreateSessionGroup,
     CreateSessionWithExistingWorktree,
     CreateSessionWithWorktree,
+    DeleteLayoutPreset,
     DetachSession,
     DevPanelCommitDiff,
     DevPanelCreateDirectory,
@@ -57,11 +77,26 @@ import {
     DevPanelStopWatcher,
     DevPanelWriteFile,
     DevPanelWorkingDiff,
+    EnqueueCommands,
+    ExpandPane,
+    ExtendEphemeralSession,
+    FocusNextActiveSession,
     FocusPane,
+    GenerateActivityDigest,
     GetActiveSession,
     GetAllowedShells,
+    GetBringUpStatus,
+    GetChangelog,
     GetClaudeEnvVarDescriptions,
+    GetCommandQueue,
+    GetConfigPolicy,
     GetCurrentBranch,
+    GetDirectoryTrust,
+    GetGitStatuses,
+    GetLogLevel,
+    GetMetricsURL,
+    GetNetworkCaptureStatus,
+    GetPaneActivity,
     GetPaneEnv,
     GetPaneReplay,
     GetConfig,
@@ -69,13 +104,36 @@ import {
     GetInputHistory,
     GetInputHistoryForSession,
     GetMCPDetail as GetMCPDetailRaw,
+    GetMaintenanceJobs,
+    GetMetrics,
     GetInputHistoryFilePath,
+    GetPaneStreamURL,
+    GetRenderingDiagnostics,
+    GetRepoHygiene,
     GetSessionErrorLog,
     GetSessionLogFilePath,
+    GetSessionPorts,
+    GetPendingCommandApprovals,
+    GetPreOpSnapshots,
+    GetRepoStats,
+    GetSessionResourceUsage,
+    GetSessionToolPaths,
+    HandleFileDrop,
+    KeepEphemeralSession,
+    KillSessionGroup,
+    ListEphemeralSessions,
+    ListExternalWorktrees,
+    ListFeatureFlags,
+    ListLayoutPresets,
+    ListNetworkCaptures,
+    ListOutputWatches,
+    ListRecentDirectories,
     LoadSessionMemo,
     GetValidationRules as GetValidationRulesWails,
     LogFrontendEvent,
+    GetSessionApprovalMode,
     GetSessionEnv,
+    GetScreenSyncURL,
     GetWebSocketURL,
     GetSingleTaskRunnerClearDelay,
     GetSingleTaskRunnerStatus,
@@ -88,21 +146,53 @@ import {
     ListMCPServers as ListMCPServersRaw,
     ListSessions,
     ListWorktreesByRepo,
+    OpenInMergeTool,
+    PasteToPane,
     PickSessionDirectory,
+    PopWorktreeStash,
     PromoteWorktreeToBranch,
+    PruneOrphanedWorktrees,
+    QuerySessions,
     QuickStartSession,
     RecoverIMEWindowFocus,
+    RefreshSessionBadges,
+    RefreshWorktreeBase,
+    RelaunchApp,
+    RemoveOutputWatch,
+    RemoveRecentDirectory,
     RenamePane,
     RenameSession,
     ResizePane,
+    ResolveCommandApproval,
+    RestartPipeServer,
+    RunFindReplace,
+    RunMaintenanceJob,
+    RunPathDoctor,
     SaveConfig,
+    SaveGlobalLayoutPreset,
+    SaveLayoutPreset,
     SaveSessionMemo,
     SendInput,
     SendSyncInput,
     SetActiveSession,
+    SetDirectoryTrust,
+    SetEventSubscriptions,
+    SetFeatureFlag,
+    SetLogLevel,
+    SetNetworkCapture,
+    SetPaneMouse,
+    SetRecentDirectoryPinned,
+    SetSessionApprovalMode,
+    SetSessionBadge,
+    SetSessionTaskbarAlertsMuted,
+    SetSoftwareRendering,
+    SetWorktreeLock,
     SplitPane,
+    StashWorktree,
+    TearDownSessions,
     ToggleViewerSidebarMode,
     SwapPanes,
+    NegotiateSnapshotEncoding,
     OpenDirectoryInExplorer,
     LoadOrchestratorTeams,
     LoadPromptPresets as LoadPromptPresetsRaw,
@@ -151,23 +241,83 @@ async function LoadPromptPresets(sessionName: string): Promise<PromptPresetLoadR
 }
 
 export const api = {
+    AcknowledgeChangelog,
+    AddOutputWatch,
+    AddSessionToGroup,
     AddSingleTaskRunnerItem,
+    AdoptExternalWorktree,
+    ApplyConfigPatch,
     ApplyLayoutPreset,
+    BringUpSessions,
+    BrowseForDirectory,
+    CancelBringUp,
+    CancelCommandQueue,
+    CheckWorktreeBase,
+    CleanupRepoHygiene,
+    ClearNetworkCaptures,
+    CollapseIdlePanes,
+    CollapsePane,
+    CopyBetweenSessions,
+    CreateBugReport,
+    CreatePullRequestForSession,
+    CreateSessionFromTemplate,
+    CreateSessionGroup,
+    DeleteLayoutPreset,
+    EnqueueCommands,
+    ExpandPane,
+    ExtendEphemeralSession,
+    GenerateActivityDigest,
     GetAllowedShells,
     GetActiveSession,
+    GetBringUpStatus,
+    GetChangelog,
     GetClaudeEnvVarDescriptions,
+    GetCommandQueue,
     GetConfig,
     GetConfigAndFlushWarnings,
+    GetConfigPolicy,
+    GetGitStatuses,
+    GetLogLevel,
     GetMCPDetail,
+    GetMaintenanceJobs,
+    GetMetrics,
     // GetValidationRules のみ型キャストが必要（Wails 自動生成型が ValidationRules と異なるため）。
     GetValidationRules: () => GetValidationRulesWails() as Promise<ValidationRules>,
+    GetMetricsURL,
+    GetNetworkCaptureStatus,
+    GetPaneActivity,
+    GetPaneStreamURL,
+    GetRenderingDiagnostics,
+    GetRepoHygiene,
+    GetSessionApprovalMode,
     GetSessionEnv,
+    GetSessionPorts,
+    GetPendingCommandApprovals,
+    GetPreOpSnapshots,
+    GetRepoStats,
+    GetSessionResourceUsage,
+    GetSessionToolPaths,
     GetSingleTaskRunnerClearDelay,
     GetSingleTaskRunnerStatus,
+    HandleFileDrop,
     IsAgentTeamsAvailable,
+    KeepEphemeralSession,
+    KillSessionGroup,
+    ListEphemeralSessions,
+    ListExternalWorktrees,
+    ListFeatureFlags,
+    ListLayoutPresets,
     ListMCPServers,
+    ListNetworkCaptures,
+    ListOutputWatches,
+    ListRecentDirectories,
     ListSessions,
+    OpenInMergeTool,
+    PasteToPane,
     PickSessionDirectory,
+    PopWorktreeStash,
+    PruneOrphanedWorktrees,
+    QuerySessions,
     QuickStartSession,
     CreatePaneInSession,
     CreateSession,
@@ -178,12 +328,35 @@ export const api = {
     CheckWorktreeStatus,
     CommitAndPushWorktree,
     GetCurrentBranch,
+    GetDirectoryTrust,
     RecoverIMEWindowFocus,
+    RefreshWorktreeBase,
+    RelaunchApp,
+    RemoveOutputWatch,
+    RemoveRecentDirectory,
+    ResolveCommandApproval,
+    RestartPipeServer,
+    RunFindReplace,
+    RunMaintenanceJob,
+    RunPathDoctor,
+    SaveGlobalLayoutPreset,
+    SaveLayoutPreset,
     SetActiveSession,
+    SetDirectoryTrust,
+    SetEventSubscriptions,
+    SetFeatureFlag,
+    SetLogLevel,
+    SetNetworkCapture,
+    SetPaneMouse,
+    SetRecentDirectoryPinned,
+    SetSessionTaskbarAlertsMuted,
+    SetSoftwareRendering,
+    SetWorktreeLock,
     SplitPane,
     SendInput,
     SendSyncInput,
     ResizePane,
+    FocusNextActiveSession,
     FocusPane,
     GetPaneEnv,
     GetPaneReplay,
@@ -194,7 +367,12 @@ export const api = {
     RenameSession,
     SaveConfig,
     SaveSessionMemo,
+    RefreshSessionBadges,
+    SetSessionApprovalMode,
+    SetSessionBadge,
+    StashWorktree,
     SwapPanes,
+    TearDownSessions,
     ToggleViewerSidebarMode,
     BuildStatusLine,
     IsGitRepository,
@@ -203,6 +381,7 @@ export const api = {
     ListWorktreesByRepo,
     PromoteWorktreeToBranch,
     CleanupWorktree,
+    GetScreenSyncURL,
     GetWebSocketURL,
     DevPanelListDir,
     DevPanelReadBinary,
@@ -240,6 +419,7 @@ export const api = {
     GetSessionLogFilePath,
     LoadSessionMemo,
     LogFrontendEvent,
+    NegotiateSnapshotEncoding,
     OpenDirectoryInExplorer,
     LoadOrchestratorTeams,
     LoadPromptPresets,
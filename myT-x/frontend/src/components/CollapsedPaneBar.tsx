@@ -0,0 +1,42 @@
+import type {PaneSnapshot} from "../types/tmux";
+import {useI18n} from "../i18n";
+
+interface CollapsedPaneBarProps {
+    panes: PaneSnapshot[];
+    onExpandPane: (paneId: string) => void;
+}
+
+/**
+ * Headers of collapsed panes. Collapsed panes keep running but get no space
+ * in the layout; clicking a header (or new output in the pane) expands it.
+ */
+export function CollapsedPaneBar({panes, onExpandPane}: CollapsedPaneBarProps) {
+    const {language, t} = useI18n();
+    if (panes.length === 0) {
+        return null;
+    }
+    return (
+        <div
+            className="collapsed-pane-bar"
+            role="toolbar"
+            aria-label={language === "en" ? "Collapsed panes" : t("sessionView.collapsedPanes.aria", "折りたたまれたペイン")}
+        >
+            {panes.map((pane) => (
+                <button
+                    key={pane.id}
+                    type="button"
+                    className="collapsed-pane-header"
+                    title={
+                        language === "en"
+                            ? `Expand pane ${pane.id}`
+                            : t("sessionView.collapsedPanes.expand", "ペイン {paneId} を展開", {paneId: pane.id})
+                    }
+                    onClick={() => onExpandPane(pane.id)}
+                >
+                    <span className="collapsed-pane-header-id">{pane.id}</span>
+                    {pane.title && <span className="collapsed-pane-header-title">{pane.title}</span>}
+                </button>
+            ))}
+        </div>
+    );
+}
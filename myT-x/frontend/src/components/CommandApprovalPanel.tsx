@@ -0,0 +1,86 @@
+import {useState} from "react";
+import {api} from "../api";
+import {useI18n} from "../i18n";
+import {useCommandApprovalStore, type PendingApproval} from "../stores/commandApprovalStore";
+import {useNotificationStore} from "../stores/notificationStore";
+
+type ApprovalDecision = "allow" | "deny" | "allow-always";
+
+function ApprovalItem({approval}: { readonly approval: PendingApproval }) {
+    const {language, t} = useI18n();
+    const isEn = language === "en";
+    const [busy, setBusy] = useState(false);
+
+    const resolve = (decision: ApprovalDecision) => {
+        setBusy(true);
+        void api.ResolveCommandApproval(approval.id, decision).then(() => {
+            useCommandApprovalStore.getState().removePending(approval.id);
+        }).catch((error: unknown) => {
+            setBusy(false);
+            // The command may already have timed out or its session closed.
+            useCommandApprovalStore.getState().removePending(approval.id);
+            const message = error instanceof Error ? error.message : String(error);
+            useNotificationStore.getState().addNotification(message, "warn");
+        });
+    };
+
+    return (
+        <div className="command-approval-item">
+            <div className="command-approval-meta">
+                <span className="command-approval-session">{approval.sessionName}</span>
+                {approval.paneId && <span className="command-approval-pane">{approval.paneId}</span>}
+            </div>
+            <code className="command-approval-command">{approval.commandLine}</code>
+            <div className="command-approval-actions">
+                <button
+                    type="button"
+                    className="modal-btn primary"
+                    disabled={busy}
+                    onClick={() => resolve("allow")}
+                >
+                    {isEn ? "Allow" : t("approval.allow", "許可")}
+                </button>
+                <button
+                    type="button"
+                    className="modal-btn"
+                    disabled={busy}
+                    onClick={() => resolve("allow-always")}
+                    title={isEn
+                        ? `Always allow ${approval.command} in this session`
+                        : t("approval.allowAlways.title", "このセッションで {command} を常に許可", {command: approval.command})}
+                >
+                    {isEn ? "Always allow" : t("approval.allowAlways", "常に許可")}
+                </button>
+                <button
+                    type="button"
+                    className="modal-btn danger"
+                    disabled={busy}
+                    onClick={() => resolve("deny")}
+                >
+                    {isEn ? "Deny" : t("approval.deny", "拒否")}
+                </button>
+            </div>
+        </div>
+    );
+}
+
+/** Lists shim commands held by session approval mode, oldest first. */
+export function CommandApprovalPanel() {
+    const {language, t} = useI18n();
+    const pending = useCommandApprovalStore((state) => state.pending);
+
+    if (pending.length === 0) return null;
+
+    return (
+        <div className="command-approval-panel" role="region" aria-live="polite">
+            <div className="command-approval-title">
+                {language === "en"
+                    ? `Commands waiting for approval (${pending.length})`
+                    : t("approval.title", "承認待ちのコマンド ({count})", {count: pending.length})}
+            </div>
+            {pending.map((approval) => (
+                <ApprovalItem key={approval.id} approval={approval}/>
+            ))}
+        </div>
+    );
+}
@@ -2,6 +2,7 @@ import {useCallback, useEffect, useState} from "react";
 import {api} from "../api";
 import {useEscapeClose} from "../hooks/useEscapeClose";
 import {useI18n} from "../i18n";
+import {useNotificationStore} from "../stores/notificationStore";
 import {logFrontendEventSafe} from "../utils/logFrontendEventSafe";
 
 /**
@@ -14,8 +15,13 @@ interface WorktreeStatusResult {
     has_unpushed: boolean;
     branch_name: string;
     is_detached: boolean;
+    locked: boolean;
+    lock_reason?: string;
 }
 
+/** Reason prefix of locks placed by the backend itself; cleanup releases them. */
+const AUTO_LOCK_REASON_PREFIX = "myT-x auto-lock: ";
+
 interface KillSessionDialogProps {
     open: boolean;
     sessionName: string;
@@ -52,7 +58,7 @@ export function KillSessionDialog({open, sessionName, onClose, onKilled}: KillSe
             })
             .catch((err) => {
                 // Safe fallback: assume worktree with uncommitted changes to prevent data loss.
-                setStatus({has_worktree: true, has_uncommitted: true, has_unpushed: true, branch_name: "", is_detached: false});
+                setStatus({has_worktree: true, has_uncommitted: true, has_unpushed: true, branch_name: "", is_detached: false, locked: false});
                 setPhase("ready");
                 const raw = String(err);
                 setError(
@@ -71,6 +77,20 @@ export function KillSessionDialog({open, sessionName, onClose, onKilled}: KillSe
     useEscapeClose(open && phase !== "processing", onClose);
 
     const shouldDeleteWt = deleteWorktree && (status?.has_worktree ?? false);
+    const lockReason = status?.lock_reason ?? "";
+    const userLocked = (status?.locked ?? false) && !lockReason.startsWith(AUTO_LOCK_REASON_PREFIX);
+
+    const handleUnlock = useCallback(async () => {
+        setPhase("processing");
+        setError("");
+        try {
+            await api.SetWorktreeLock(sessionName, false, "");
+            setStatus((prev) => (prev ? {...prev, locked: false, lock_reason: ""} : prev));
+        } catch (err) {
+            setError(String(err));
+        }
+        setPhase("ready");
+    }, [sessionName]);
 
     const handleKillOnly = useCallback(async () => {
         setPhase("processing");
@@ -85,15 +105,44 @@ export function KillSessionDialog({open, sessionName, onClose, onKilled}: KillSe
         }
     }, [sessionName, shouldDeleteWt, onKilled, onClose]);
 
+    // The stash lives in the repository, so it survives worktree deletion.
+    const handleStashAndKill = useCallback(async () => {
+        setPhase("processing");
+        setError("");
+        try {
+            await api.StashWorktree(sessionName, commitMessage.trim());
+            await api.KillSession(sessionName, shouldDeleteWt);
+            onKilled();
+            onClose();
+        } catch (err) {
+            setError(String(err));
+            setPhase("ready");
+        }
+    }, [sessionName, commitMessage, shouldDeleteWt, onKilled, onClose]);
+
     const handleCommitAndKill = useCallback(async (push: boolean) => {
         setPhase("processing");
         setError("");
         try {
             const msg = commitMessage.trim();
-            if (msg) {
-                await api.CommitAndPushWorktree(sessionName, msg, push);
-            } else if (push) {
-                await api.CommitAndPushWorktree(sessionName, "", push);
+            if (msg || push) {
+                const result = await api.CommitAndPushWorktree(sessionName, msg, push);
+                // The session closes right away, so the pull request is reported as a toast.
+                if (result?.pull_request?.url) {
+                    const url = result.pull_request.url;
+                    useNotificationStore.getState().addNotification(
+                        isEn ? `Pull request opened: ${url}` : t("killSession.pullRequest.opened", "プルリクエストを作成しました: {url}", {url}),
+                        "info",
+                    );
+                } else if (result?.pull_request_error) {
+                    const error = result.pull_request_error;
+                    useNotificationStore.getState().addNotification(
+                        isEn
+                            ? `Pushed, but opening the pull request failed: ${error}`
+                            : t("killSession.pullRequest.failed", "push しましたが、プルリクエストの作成に失敗しました: {error}", {error}),
+                        "warn",
+                    );
+                }
             }
             await api.KillSession(sessionName, shouldDeleteWt);
             onKilled();
@@ -102,7 +151,7 @@ export function KillSessionDialog({open, sessionName, onClose, onKilled}: KillSe
             setError(String(err));
             setPhase("ready");
         }
-    }, [sessionName, commitMessage, shouldDeleteWt, onKilled, onClose]);
+    }, [sessionName, commitMessage, shouldDeleteWt, onKilled, onClose, isEn, t]);
 
     if (!open) return null;
 
@@ -207,6 +256,29 @@ export function KillSessionDialog({open, sessionName, onClose, onKilled}: KillSe
                         </div>
                     )}
 
+                    {phase !== "loading" && status?.has_worktree && userLocked && deleteWorktree && (
+                        <div className="form-checkbox-row" style={{marginTop: 4}}>
+                            <p className="form-hint" style={{color: "var(--danger)"}}>
+                                {(() => {
+                                    const reason = lockReason || (isEn
+                                        ? "no reason given"
+                                        : t("killSession.locked.noReason", "理由なし"));
+                                    return isEn
+                                        ? `The worktree is locked and will not be deleted: ${reason}`
+                                        : t("killSession.locked.message", "ワークツリーはロックされているため削除されません: {reason}", {reason});
+                                })()}
+                            </p>
+                            <button
+                                type="button"
+                                className="modal-btn"
+                                onClick={() => void handleUnlock()}
+                                disabled={isProcessing}
+                            >
+                                {isEn ? "Unlock" : t("killSession.action.unlock", "ロック解除")}
+                            </button>
+                        </div>
+                    )}
+
                     {error && <p className="form-error">{error}</p>}
                 </div>
                 <div className="modal-footer">
@@ -232,6 +304,20 @@ export function KillSessionDialog({open, sessionName, onClose, onKilled}: KillSe
                         </button>
                     )}
 
+                    {phase !== "loading" && status?.has_uncommitted && (
+                        <button
+                            type="button"
+                            className="modal-btn"
+                            onClick={() => void handleStashAndKill()}
+                            disabled={isProcessing}
+                            title={isEn
+                                ? "Stash the changes (the commit message is used as the stash message)"
+                                : t("killSession.action.stashThenClose.title", "変更をstashします（コミットメッセージをstashメッセージに使用）")}
+                        >
+                            {isEn ? "Stash then Close" : t("killSession.action.stashThenClose", "Stash して閉じる")}
+                        </button>
+                    )}
+
                     {phase !== "loading" && (() => {
                         if (!needsAction) {
                             return (
@@ -9,6 +9,7 @@ export interface LayoutNodeActions {
     onSplitHorizontal: (paneId: string) => void;
     onToggleZoom: (paneId: string) => void;
     onKillPane: (paneId: string) => void;
+    onCollapsePane?: (paneId: string) => void;
     onRenamePane: (paneId: string, title: string) => void;
     onSwapPane: (sourcePaneId: string, targetPaneId: string) => void;
     onDetachSession: () => void;
@@ -31,12 +32,14 @@ function renderPaneTerminal(
         <TerminalPane
             paneId={pane.id}
             paneTitle={pane.title}
+            paneMouse={pane.mouse}
             active={active}
             onFocus={actions.onFocusPane}
             onSplitVertical={actions.onSplitVertical}
             onSplitHorizontal={actions.onSplitHorizontal}
             onToggleZoom={actions.onToggleZoom}
             onKillPane={actions.onKillPane}
+            onCollapsePane={actions.onCollapsePane}
             onRenamePane={actions.onRenamePane}
             onSwapPane={actions.onSwapPane}
             onDetach={actions.onDetachSession}
@@ -27,11 +27,13 @@ export function LayoutRenderer(props: LayoutRendererProps) {
             onSplitHorizontal: props.onSplitHorizontal,
             onToggleZoom: props.onToggleZoom,
             onKillPane: props.onKillPane,
+            onCollapsePane: props.onCollapsePane,
             onRenamePane: props.onRenamePane,
             onSwapPane: props.onSwapPane,
             onDetachSession: props.onDetachSession,
         }),
         [
+            props.onCollapsePane,
             props.onDetachSession,
             props.onFocusPane,
             props.onKillPane,
@@ -59,6 +61,7 @@ export function LayoutRenderer(props: LayoutRendererProps) {
                 <TerminalPane
                     paneId={pane.id}
                     paneTitle={pane.title}
+                    paneMouse={pane.mouse}
                     active={true}
                     onFocus={actions.onFocusPane}
                     onSplitVertical={actions.onSplitVertical}
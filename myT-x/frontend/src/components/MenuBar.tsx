@@ -1,5 +1,8 @@
-import type {Ref} from "react";
+import {useState, type Ref} from "react";
+import {BrowserOpenURL} from "../../wailsjs/runtime/runtime";
+import {api} from "../api";
 import {useI18n} from "../i18n";
+import {useNotificationStore} from "../stores/notificationStore";
 import {useTmuxStore} from "../stores/tmuxStore";
 import {QUICK_SEARCH_DIALOG_ID, QUICK_SEARCH_SHORTCUT_DISPLAY} from "./quickSearchShared";
 
@@ -18,6 +21,28 @@ export function MenuBar({
 }: MenuBarProps) {
   const {language, setLanguage, t} = useI18n();
   const triggerImeReset = useTmuxStore((s) => s.triggerImeReset);
+  const [creatingBugReport, setCreatingBugReport] = useState(false);
+
+  const createBugReport = () => {
+    setCreatingBugReport(true);
+    void api.CreateBugReport().then((result) => {
+      useNotificationStore.getState().addNotification(
+        t("menu.bugReport.created", "バグレポートを作成しました: {path}", {path: result.path}),
+        "info",
+      );
+      if (window.confirm(t("menu.bugReport.openIssue", "GitHub の Issue 作成ページを開きますか？ zip は手動で添付してください。"))) {
+        BrowserOpenURL(result.issue_url);
+      }
+    }).catch((error: unknown) => {
+      const message = error instanceof Error ? error.message : String(error);
+      useNotificationStore.getState().addNotification(
+        t("menu.bugReport.failed", "バグレポートの作成に失敗しました: {message}", {message}),
+        "warn",
+      );
+    }).finally(() => {
+      setCreatingBugReport(false);
+    });
+  };
 
   return (
     <nav className="menu-bar">
@@ -60,6 +85,17 @@ export function MenuBar({
         </button>
       </div>
       <div className="menu-bar-group menu-bar-group--end">
+        <div className="menu-bar-item">
+          <button
+            type="button"
+            className="menu-bar-trigger menu-bar-bug-report"
+            title={t("menu.bugReport.title", "ログと設定 (秘匿情報は除去) を zip にまとめます")}
+            onClick={createBugReport}
+            disabled={creatingBugReport}
+          >
+            {t("menu.bugReport", "バグ報告")}
+          </button>
+        </div>
         <div className="menu-bar-item">
           <button
               type="button"
@@ -9,15 +9,20 @@ import type {git} from "../../wailsjs/go/models";
 import {INITIAL_STATE, newSessionReducer} from "./new-session/newSessionReducer";
 import {buildCreateSessionWithWorktreeOptions} from "./new-session/createSessionOptions";
 import {NewSessionForm} from "./new-session/NewSessionForm";
+import {DirectoryTrustPrompt} from "./new-session/DirectoryTrustPrompt";
+import {RecentDirectoryList} from "./new-session/RecentDirectoryList";
+import {RepoStatsSummary} from "./new-session/RepoStatsSummary";
 import {WorktreeOptions} from "./new-session/WorktreeOptions";
 
 interface NewSessionModalProps {
     open: boolean;
+    /** Turns on "Use Git Worktree" once the picked folder is a git repository. */
+    preferWorktree?: boolean;
     onClose: () => void;
     onCreated: (sessionName: string) => void;
 }
 
-export function NewSessionModal({open, onClose, onCreated}: NewSessionModalProps) {
+export function NewSessionModal({open, preferWorktree = false, onClose, onCreated}: NewSessionModalProps) {
     const {language, t} = useI18n();
     const isEn = language === "en";
 
@@ -36,12 +41,18 @@ export function NewSessionModal({open, onClose, onCreated}: NewSessionModalProps
                 }
                 dispatch({type: "SET_FIELD", field: "shimAvailable", value: false});
             });
-        // NOTE: On config load failure, useClaudeEnv / usePaneEnv fall back to false
+        // NOTE: On config load failure, useClaudeEnv / usePaneEnv / isolateShellHistory fall back to false
         // (conservative default). Session pane scope defaults to true independently.
         api.GetConfig()
             .then((cfg) => {
                 dispatch({type: "SET_FIELD", field: "useClaudeEnv", value: cfg.claude_env?.default_enabled ?? false});
                 dispatch({type: "SET_FIELD", field: "usePaneEnv", value: cfg.pane_env_default_enabled ?? false});
+                dispatch({
+                    type: "SET_FIELD",
+                    field: "isolateShellHistory",
+                    value: cfg.shell_history_isolation_default_enabled ?? false,
+                });
+                dispatch({type: "SET_FIELD", field: "allowSharedWorktree", value: cfg.worktree?.allow_shared ?? false});
             })
             .catch((err) => {
                 if (import.meta.env.DEV) {
@@ -96,11 +107,8 @@ export function NewSessionModal({open, onClose, onCreated}: NewSessionModalProps
     // not as reactive triggers. Directory change resets them via PICK_DIRECTORY action.
     }, [s.useWorktree, s.isGitRepo, s.directory]);
 
-    const handlePickDirectory = useCallback(async () => {
+    const selectDirectory = useCallback(async (dir: string) => {
         try {
-            const dir = await api.PickSessionDirectory();
-            if (!dir) return;
-
             dispatch({
                 type: "PICK_DIRECTORY",
                 directory: dir,
@@ -134,6 +142,9 @@ export function NewSessionModal({open, onClose, onCreated}: NewSessionModalProps
                         return "";
                     });
                     dispatch({type: "SET_FIELD", field: "currentBranch", value: curBranch});
+                    if (preferWorktree) {
+                        dispatch({type: "SET_FIELD", field: "useWorktree", value: true});
+                    }
                 } else {
                     dispatch({type: "SET_FIELD", field: "currentBranch", value: ""});
                 }
@@ -152,7 +163,26 @@ export function NewSessionModal({open, onClose, onCreated}: NewSessionModalProps
                 ),
             });
         }
-    }, [language, t]);
+    }, [language, preferWorktree, t]);
+
+    const handlePickDirectory = useCallback(async () => {
+        try {
+            // Start the picker at the current choice so siblings are one click away.
+            const dir = await api.BrowseForDirectory(s.directory);
+            if (dir) await selectDirectory(dir);
+        } catch (err) {
+            dispatch({
+                type: "SET_FIELD",
+                field: "error",
+                value: formatWorktreeErrorMessage(
+                    err,
+                    {language, t},
+                    "フォルダの選択に失敗しました。",
+                    "Failed to select a folder.",
+                ),
+            });
+        }
+    }, [language, s.directory, selectDirectory, t]);
 
     const worktreeCheckSeqRef = useRef(0);
     const handleSelectWorktree = useCallback(async (wt: git.WorktreeInfo) => {
@@ -191,6 +221,7 @@ export function NewSessionModal({open, onClose, onCreated}: NewSessionModalProps
                             use_claude_env: s.useClaudeEnv,
                             use_pane_env: s.usePaneEnv,
                             use_session_pane_scope: s.useSessionPaneScope,
+                            isolate_shell_history: s.isolateShellHistory,
                         });
                 } else {
                     const opts = buildCreateSessionWithWorktreeOptions(s);
@@ -202,6 +233,8 @@ export function NewSessionModal({open, onClose, onCreated}: NewSessionModalProps
                     use_claude_env: s.useClaudeEnv,
                     use_pane_env: s.usePaneEnv,
                     use_session_pane_scope: s.useSessionPaneScope,
+                    isolate_shell_history: s.isolateShellHistory,
+                    ephemeral: s.ephemeral,
                 });
             }
             onCreated(created.name);
@@ -226,11 +259,11 @@ export function NewSessionModal({open, onClose, onCreated}: NewSessionModalProps
         if (!s.directory || !s.sessionName.trim() || s.loading || s.worktreeDataLoading || s.gitCheckLoading) return false;
         if (!s.useWorktree) return !s.directoryConflict;
         if (s.worktreeSource === "existing") {
-            return !!s.selectedWorktree && !s.worktreeConflict;
+            return !!s.selectedWorktree && (!s.worktreeConflict || s.allowSharedWorktree);
         }
         // new worktree: always requires branch name
         return !!s.branchName.trim();
-    }, [s.directory, s.sessionName, s.loading, s.worktreeDataLoading, s.gitCheckLoading, s.useWorktree, s.directoryConflict, s.worktreeSource, s.selectedWorktree, s.worktreeConflict, s.branchName]);
+    }, [s.directory, s.sessionName, s.loading, s.worktreeDataLoading, s.gitCheckLoading, s.useWorktree, s.directoryConflict, s.worktreeSource, s.selectedWorktree, s.worktreeConflict, s.allowSharedWorktree, s.branchName]);
 
     if (!open) return null;
 
@@ -272,8 +305,12 @@ export function NewSessionModal({open, onClose, onCreated}: NewSessionModalProps
                                     ? "Select folder..."
                                     : t("newSession.directory.selectButton", "フォルダを選択..."))}
                         </button>
+                        <RecentDirectoryList selected={s.directory} onSelect={(dir) => void selectDirectory(dir)} />
+                        {s.directory && <RepoStatsSummary directory={s.directory} />}
                     </div>
 
+                    {s.directory && <DirectoryTrustPrompt directory={s.directory} />}
+
                     {/* Git repository check loading indicator */}
                     {s.directory && s.gitCheckLoading && (
                         <div className="form-inline-loading">
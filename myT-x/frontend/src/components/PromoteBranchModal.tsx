@@ -7,33 +7,46 @@ import {formatWorktreeErrorMessage} from "../utils/worktreeErrorMessage";
 interface PromoteBranchModalProps {
     open: boolean;
     sessionName: string;
+    // baseBranch is the session's base branch, the default rebase target.
+    baseBranch: string;
     onClose: () => void;
     onPromoted: () => void;
 }
 
-export function PromoteBranchModal({open, sessionName, onClose, onPromoted}: PromoteBranchModalProps) {
+export function PromoteBranchModal({open, sessionName, baseBranch, onClose, onPromoted}: PromoteBranchModalProps) {
     const {language, t} = useI18n();
     const [branchName, setBranchName] = useState("");
+    const [rebaseOntoBase, setRebaseOntoBase] = useState(false);
+    const [rebaseBase, setRebaseBase] = useState(baseBranch);
     const [loading, setLoading] = useState(false);
     const [error, setError] = useState("");
 
     useEffect(() => {
         if (!open) {
             setBranchName("");
+            setRebaseOntoBase(false);
             setLoading(false);
             setError("");
         }
     }, [open]);
 
+    useEffect(() => {
+        setRebaseBase(baseBranch);
+    }, [baseBranch]);
+
     useEscapeClose(open, onClose);
 
     const handleSubmit = useCallback(async () => {
         const name = branchName.trim();
         if (!name) return;
+        if (rebaseOntoBase && !rebaseBase.trim()) return;
         setLoading(true);
         setError("");
         try {
-            await api.PromoteWorktreeToBranch(sessionName, name);
+            await api.PromoteWorktreeToBranch(sessionName, name, {
+                rebase_onto_base: rebaseOntoBase,
+                base_branch: rebaseOntoBase ? rebaseBase.trim() : undefined,
+            });
             onPromoted();
             onClose();
         } catch (err) {
@@ -48,7 +61,9 @@ export function PromoteBranchModal({open, sessionName, onClose, onPromoted}: Pro
         } finally {
             setLoading(false);
         }
-    }, [branchName, language, onClose, onPromoted, sessionName, t]);
+    }, [branchName, language, onClose, onPromoted, rebaseBase, rebaseOntoBase, sessionName, t]);
+
+    const canSubmit = Boolean(branchName.trim()) && (!rebaseOntoBase || Boolean(rebaseBase.trim()));
 
     if (!open) return null;
 
@@ -79,7 +94,7 @@ export function PromoteBranchModal({open, sessionName, onClose, onPromoted}: Pro
                             value={branchName}
                             onChange={(e) => setBranchName(e.target.value)}
                             onKeyDown={(e) => {
-                                if (e.key === "Enter" && branchName.trim()) void handleSubmit();
+                                if (e.key === "Enter" && canSubmit) void handleSubmit();
                             }}
                             placeholder={
                                 language === "en"
@@ -89,6 +104,36 @@ export function PromoteBranchModal({open, sessionName, onClose, onPromoted}: Pro
                             autoFocus
                         />
                     </div>
+                    <div className="form-checkbox-row">
+                        <input
+                            type="checkbox"
+                            id="promote-rebase-onto-base"
+                            checked={rebaseOntoBase}
+                            onChange={(e) => setRebaseOntoBase(e.target.checked)}
+                            disabled={loading}
+                        />
+                        <label htmlFor="promote-rebase-onto-base">
+                            {language === "en"
+                                ? "Rebase onto the latest base branch first"
+                                : t("promoteBranch.rebaseOntoBase.label", "先に最新のベースブランチへ rebase する")}
+                        </label>
+                    </div>
+                    {rebaseOntoBase && (
+                        <div className="form-group">
+                            <span className="form-label">
+                                {language === "en"
+                                    ? "Base Branch"
+                                    : t("promoteBranch.baseBranch.label", "ベースブランチ")}
+                            </span>
+                            <input
+                                className="form-input"
+                                value={rebaseBase}
+                                onChange={(e) => setRebaseBase(e.target.value)}
+                                placeholder="main"
+                                disabled={loading}
+                            />
+                        </div>
+                    )}
                     {error && <p className="form-error">{error}</p>}
                 </div>
                 <div className="modal-footer">
@@ -101,7 +146,7 @@ export function PromoteBranchModal({open, sessionName, onClose, onPromoted}: Pro
                         type="button"
                         className="modal-btn primary"
                         onClick={handleSubmit}
-                        disabled={!branchName.trim() || loading}
+                        disabled={!canSubmit || loading}
                     >
                         {loading
                             ? (language === "en"
@@ -0,0 +1,243 @@
+import {useCallback, useEffect, useState} from "react";
+import {api} from "../api";
+import {useEscapeClose} from "../hooks/useEscapeClose";
+import {useI18n} from "../i18n";
+import {toErrorMessage} from "../utils/errorUtils";
+import type {worktree} from "../../wailsjs/go/models";
+
+interface RepoHygieneModalProps {
+    open: boolean;
+    repoPath: string;
+    onClose: () => void;
+}
+
+interface HygieneSelection {
+    branches: Set<string>;
+    pruneWorktrees: boolean;
+    stashes: Set<string>;
+    untracked: Set<string>;
+}
+
+function emptySelection(): HygieneSelection {
+    return {branches: new Set(), pruneWorktrees: false, stashes: new Set(), untracked: new Set()};
+}
+
+function toggle(set: Set<string>, value: string): Set<string> {
+    const next = new Set(set);
+    if (!next.delete(value)) next.add(value);
+    return next;
+}
+
+function formatBytes(size: number): string {
+    if (size >= 1 << 30) return `${(size / (1 << 30)).toFixed(1)} GiB`;
+    return `${(size / (1 << 20)).toFixed(1)} MiB`;
+}
+
+function formatDate(unixSeconds: number): string {
+    return unixSeconds > 0 ? new Date(unixSeconds * 1000).toLocaleDateString() : "";
+}
+
+export function RepoHygieneModal({open, repoPath, onClose}: RepoHygieneModalProps) {
+    const {language, t} = useI18n();
+    const [report, setReport] = useState<worktree.RepoHygiene | null>(null);
+    const [selection, setSelection] = useState<HygieneSelection>(emptySelection);
+    const [loading, setLoading] = useState(false);
+    const [cleaning, setCleaning] = useState(false);
+    const [error, setError] = useState("");
+    const [failures, setFailures] = useState<string[]>([]);
+
+    const load = useCallback(async () => {
+        setLoading(true);
+        setError("");
+        try {
+            setReport(await api.GetRepoHygiene(repoPath));
+            setSelection(emptySelection());
+        } catch (err) {
+            setError(toErrorMessage(err, "Failed to inspect the repository."));
+        } finally {
+            setLoading(false);
+        }
+    }, [repoPath]);
+
+    useEffect(() => {
+        if (!open) {
+            setReport(null);
+            setSelection(emptySelection());
+            setError("");
+            setFailures([]);
+            return;
+        }
+        void load();
+    }, [open, load]);
+
+    useEscapeClose(open, onClose);
+
+    const selectedCount = selection.branches.size + selection.stashes.size + selection.untracked.size
+        + (selection.pruneWorktrees ? 1 : 0);
+
+    const handleCleanup = useCallback(async () => {
+        setCleaning(true);
+        setError("");
+        try {
+            const result = await api.CleanupRepoHygiene(repoPath, {
+                deleteBranches: [...selection.branches],
+                pruneWorktrees: selection.pruneWorktrees,
+                dropStashes: [...selection.stashes],
+                deleteUntracked: [...selection.untracked],
+            });
+            setFailures(result.failures ?? []);
+            await load();
+        } catch (err) {
+            setError(toErrorMessage(err, "Cleanup failed."));
+        } finally {
+            setCleaning(false);
+        }
+    }, [load, repoPath, selection]);
+
+    if (!open) return null;
+
+    const staleBranches = report?.staleBranches ?? [];
+    const prunableWorktrees = report?.prunableWorktrees ?? [];
+    const largeUntracked = report?.largeUntracked ?? [];
+    const oldStashes = report?.oldStashes ?? [];
+    const isClean = report !== null && staleBranches.length + prunableWorktrees.length
+        + largeUntracked.length + oldStashes.length === 0;
+
+    return (
+        <div className="modal-overlay" onClick={onClose}>
+            <div className="modal-panel repo-hygiene-modal" onClick={(e) => e.stopPropagation()}>
+                <div className="modal-header">
+                    <h2>
+                        {language === "en"
+                            ? "Repository Hygiene"
+                            : t("repoHygiene.title", "リポジトリの整理")}
+                    </h2>
+                </div>
+                <div className="modal-body">
+                    <p className="repo-hygiene-path" title={repoPath}>{repoPath}</p>
+                    {loading && !report && (
+                        <p className="repo-hygiene-empty">
+                            {language === "en" ? "Inspecting..." : t("repoHygiene.loading", "確認中...")}
+                        </p>
+                    )}
+                    {isClean && (
+                        <p className="repo-hygiene-empty">
+                            {language === "en" ? "Nothing to clean up." : t("repoHygiene.clean", "整理対象はありません。")}
+                        </p>
+                    )}
+
+                    {staleBranches.length > 0 && (
+                        <section className="repo-hygiene-section">
+                            <h3>
+                                {language === "en"
+                                    ? "Merged branches without upstream"
+                                    : t("repoHygiene.staleBranches", "マージ済みでアップストリームのないブランチ")}
+                            </h3>
+                            {staleBranches.map((branch) => (
+                                <label key={branch.name} className="repo-hygiene-item">
+                                    <input
+                                        type="checkbox"
+                                        checked={selection.branches.has(branch.name)}
+                                        onChange={() => setSelection((s) => ({...s, branches: toggle(s.branches, branch.name)}))}
+                                    />
+                                    <span className="repo-hygiene-name">{branch.name}</span>
+                                    <span className="repo-hygiene-detail">
+                                        {branch.upstreamGone ? "[gone] " : ""}{formatDate(branch.lastCommitUnix)}
+                                    </span>
+                                </label>
+                            ))}
+                        </section>
+                    )}
+
+                    {prunableWorktrees.length > 0 && (
+                        <section className="repo-hygiene-section">
+                            <h3>
+                                {language === "en"
+                                    ? "Prunable worktrees"
+                                    : t("repoHygiene.prunableWorktrees", "削除可能な worktree")}
+                            </h3>
+                            <label className="repo-hygiene-item">
+                                <input
+                                    type="checkbox"
+                                    checked={selection.pruneWorktrees}
+                                    onChange={() => setSelection((s) => ({...s, pruneWorktrees: !s.pruneWorktrees}))}
+                                />
+                                <span className="repo-hygiene-name">git worktree prune</span>
+                            </label>
+                            {prunableWorktrees.map((wt) => (
+                                <div key={wt.path} className="repo-hygiene-item repo-hygiene-item--nested">
+                                    <span className="repo-hygiene-name" title={wt.path}>{wt.path}</span>
+                                    <span className="repo-hygiene-detail">{wt.reason}</span>
+                                </div>
+                            ))}
+                        </section>
+                    )}
+
+                    {largeUntracked.length > 0 && (
+                        <section className="repo-hygiene-section">
+                            <h3>
+                                {language === "en"
+                                    ? "Large untracked files"
+                                    : t("repoHygiene.largeUntracked", "大きな未追跡ファイル")}
+                            </h3>
+                            {largeUntracked.map((file) => (
+                                <label key={file.path} className="repo-hygiene-item">
+                                    <input
+                                        type="checkbox"
+                                        checked={selection.untracked.has(file.path)}
+                                        onChange={() => setSelection((s) => ({...s, untracked: toggle(s.untracked, file.path)}))}
+                                    />
+                                    <span className="repo-hygiene-name" title={file.path}>{file.path}</span>
+                                    <span className="repo-hygiene-detail">{formatBytes(file.size)}</span>
+                                </label>
+                            ))}
+                        </section>
+                    )}
+
+                    {oldStashes.length > 0 && (
+                        <section className="repo-hygiene-section">
+                            <h3>
+                                {language === "en"
+                                    ? "Stashes older than 30 days"
+                                    : t("repoHygiene.oldStashes", "30日以上前の stash")}
+                            </h3>
+                            {oldStashes.map((stash) => (
+                                <label key={stash.commit} className="repo-hygiene-item">
+                                    <input
+                                        type="checkbox"
+                                        checked={selection.stashes.has(stash.commit)}
+                                        onChange={() => setSelection((s) => ({...s, stashes: toggle(s.stashes, stash.commit)}))}
+                                    />
+                                    <span className="repo-hygiene-name" title={stash.message}>{stash.message}</span>
+                                    <span className="repo-hygiene-detail">{formatDate(stash.createdUnix)}</span>
+                                </label>
+                            ))}
+                        </section>
+                    )}
+
+                    {(report?.warnings ?? []).concat(failures).map((message) => (
+                        <p key={message} className="form-error">{message}</p>
+                    ))}
+                    {error && <p className="form-error">{error}</p>}
+                </div>
+                <div className="modal-footer">
+                    <button type="button" className="modal-btn" onClick={onClose} disabled={cleaning}>
+                        {language === "en" ? "Close" : t("common.close", "閉じる")}
+                    </button>
+                    <button
+                        type="button"
+                        className="modal-btn primary"
+                        onClick={() => void handleCleanup()}
+                        disabled={selectedCount === 0 || cleaning || loading}
+                    >
+                        {cleaning
+                            ? (language === "en" ? "Cleaning up..." : t("repoHygiene.action.cleaning", "整理中..."))
+                            : (language === "en"
+                                ? `Clean up selected (${selectedCount})`
+                                : t("repoHygiene.action.cleanup", "選択項目を整理 ({count})", {count: selectedCount}))}
+                    </button>
+                </div>
+            </div>
+        </div>
+    );
+}
@@ -7,6 +7,7 @@ import {notifyAndLog} from "../utils/notifyUtils";
 import {useI18n} from "../i18n";
 import {LayoutPresetSelector} from "./LayoutPresetSelector";
 import {LayoutRenderer} from "./LayoutRenderer";
+import {CollapsedPaneBar} from "./CollapsedPaneBar";
 import {CanvasModeToggle} from "./canvas/CanvasModeToggle";
 import {ReactFlowProvider} from "@xyflow/react";
 import {CanvasView} from "./canvas/CanvasView";
@@ -35,6 +36,11 @@ export function SessionView(props: SessionViewProps) {
         [activeWindow],
     );
 
+    const collapsedPanes = useMemo(
+        () => paneList.filter((pane) => pane.collapsed),
+        [paneList],
+    );
+
     const activePaneId = useMemo(
         () => resolveActivePane(activeWindow)?.id ?? null,
         [activeWindow],
@@ -96,6 +102,31 @@ export function SessionView(props: SessionViewProps) {
         });
     }, []);
 
+    const onCollapsePane = useCallback((paneId: string) => {
+        void api.CollapsePane(paneId).catch((err: unknown) => {
+            console.warn("[session-view] CollapsePane failed", err);
+            notifyAndLog("Collapse pane", "warn", err, "SessionView");
+        });
+    }, []);
+
+    const onExpandPane = useCallback((paneId: string) => {
+        void api.ExpandPane(paneId).catch((err: unknown) => {
+            console.warn("[session-view] ExpandPane failed", err);
+            notifyAndLog("Expand pane", "warn", err, "SessionView");
+        });
+    }, []);
+
+    const onCollapseIdlePanes = useCallback(() => {
+        const sessionName = props.session?.name;
+        if (!sessionName) {
+            return;
+        }
+        void api.CollapseIdlePanes(sessionName).catch((err: unknown) => {
+            console.warn("[session-view] CollapseIdlePanes failed", err);
+            notifyAndLog("Collapse idle panes", "warn", err, "SessionView");
+        });
+    }, [props.session?.name]);
+
     const onRenamePane = useCallback((paneId: string, title: string) => {
         void api.RenamePane(paneId, title).catch((err: unknown) => {
             console.warn("[session-view] RenamePane failed", err);
@@ -283,7 +314,34 @@ export function SessionView(props: SessionViewProps) {
                             </span>
                         </button>
                     )}
+                    {canvasMode !== "canvas" && paneList.length >= 2 && (
+                        <button
+                            type="button"
+                            className="terminal-toolbar-btn"
+                            title={
+                                language === "en"
+                                    ? "Collapse idle panes to headers"
+                                    : t("sessionView.collapseIdle.title", "アイドル中のペインをヘッダーに折りたたむ")
+                            }
+                            aria-label={
+                                language === "en"
+                                    ? "Collapse idle panes"
+                                    : t("sessionView.collapseIdle.aria", "アイドル中のペインを折りたたむ")
+                            }
+                            onClick={onCollapseIdlePanes}
+                        >
+                            <svg width="14" height="14" viewBox="0 0 14 14" fill="none" stroke="currentColor"
+                                 strokeWidth="1.4">
+                                <rect x="1" y="1" width="12" height="3" rx="1"/>
+                                <rect x="1" y="5.5" width="12" height="3" rx="1"/>
+                                <polyline points="4.5,12.5 7,10.5 9.5,12.5"/>
+                            </svg>
+                        </button>
+                    )}
                 </div>
+                {canvasMode !== "canvas" && (
+                    <CollapsedPaneBar panes={collapsedPanes} onExpandPane={onExpandPane}/>
+                )}
                 <div className="session-view-body">
                     {canvasMode === "canvas" ? (
                         <ReactFlowProvider>
@@ -312,6 +370,7 @@ export function SessionView(props: SessionViewProps) {
                             onSplitHorizontal={onSplitHorizontal}
                             onToggleZoom={onToggleZoom}
                             onKillPane={onKillPane}
+                            onCollapsePane={onCollapsePane}
                             onRenamePane={onRenamePane}
                             onSwapPane={onSwapPane}
                             onDetachSession={onDetachSession}
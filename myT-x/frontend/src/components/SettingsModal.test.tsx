@@ -14,6 +14,8 @@ vi.mock("../api", () => ({
         GetAllowedShells: () => getAllowedShellsMock(),
         GetValidationRules: () => getValidationRulesMock(),
         SaveConfig: vi.fn(),
+        ApplyConfigPatch: vi.fn(),
+        GetConfigPolicy: () => Promise.resolve({path: "", locked_keys: []}),
     },
 }));
 
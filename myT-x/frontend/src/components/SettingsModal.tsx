@@ -29,6 +29,7 @@ export function SettingsModal({open, onClose}: SettingsModalProps) {
     const {t} = useSettingsI18n();
     const [s, dispatch] = useReducer(formReducer, INITIAL_FORM);
     const [validationRules, setValidationRules] = useState<ValidationRules | null>(null);
+    const [policyLockedKeys, setPolicyLockedKeys] = useState<string[]>([]);
     const panelRef = useRef<HTMLDivElement | null>(null);
     const previouslyFocusedRef = useRef<HTMLElement | null>(null);
     const prevOpenForResetRef = useRef(false);
@@ -42,6 +43,7 @@ export function SettingsModal({open, onClose}: SettingsModalProps) {
         if (open && !prevOpenForResetRef.current) {
             dispatch({type: "RESET_FOR_LOAD"});
             setValidationRules(null);
+            setPolicyLockedKeys([]);
         }
         prevOpenForResetRef.current = open;
     }, [open]);
@@ -59,12 +61,17 @@ export function SettingsModal({open, onClose}: SettingsModalProps) {
                 logFrontendEventSafe("warn", `GetValidationRules failed: ${err instanceof Error ? err.message : String(err)}`, "SettingsModal");
                 return null;
             }),
+            api.GetConfigPolicy().catch((err: unknown) => {
+                console.warn("[settings] GetConfigPolicy failed (non-fatal)", err);
+                return null;
+            }),
         ])
-            .then(([cfg, shells, rules]) => {
+            .then(([cfg, shells, rules, policy]) => {
                 if (cancelled) return;
 
                 dispatch({type: "LOAD_CONFIG", config: cfg, shells});
                 setValidationRules(rules);
+                setPolicyLockedKeys(policy?.locked_keys ?? []);
                 const minOverrideNameLen =
                     typeof rules?.min_override_name_len === "number" &&
                     Number.isFinite(rules.min_override_name_len)
@@ -161,6 +168,17 @@ export function SettingsModal({open, onClose}: SettingsModalProps) {
                     <h2 id="settings-modal-title">{t("settings.modal.title", "設定(config.yaml)", "Settings (config.yaml)")}</h2>
                 </div>
 
+                {policyLockedKeys.length > 0 && (
+                    <div className="settings-policy-note" role="note">
+                        {t(
+                            "settings.modal.policyLocked",
+                            "管理者ポリシーにより次の設定は変更できません: {keys}",
+                            "These settings are locked by the administrator policy: {keys}",
+                            {keys: policyLockedKeys.join(", ")},
+                        )}
+                    </div>
+                )}
+
                 {s.loading ? (
                     <div className="modal-loading settings-loading">
                         {t("settings.modal.loading", "設定を読み込み中...", "Loading settings...")}
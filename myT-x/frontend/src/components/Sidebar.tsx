@@ -4,6 +4,7 @@ import {FixedSizeList} from "react-window";
 import {api} from "../api";
 import {makeScrollStableOuter} from "./viewer/views/shared/TreeOuter";
 import {useContainerHeight} from "../hooks/useContainerHeight";
+import {useSessionSearch} from "../hooks/useSessionSearch";
 import {useNotificationStore} from "../stores/notificationStore";
 import {useTmuxStore} from "../stores/tmuxStore";
 import {useDiffReviewStore} from "../stores/diffReviewStore";
@@ -15,13 +16,18 @@ import {logFrontendEventSafe} from "../utils/logFrontendEventSafe";
 import {KillSessionDialog} from "./KillSessionDialog";
 import {NewSessionModal} from "./NewSessionModal";
 import {PromoteBranchModal} from "./PromoteBranchModal";
+import {RepoHygieneModal} from "./RepoHygieneModal";
 import {SidebarHeader} from "./SidebarHeader";
 import {SessionRow, sessionRowHeight, type SessionRowData, type SessionVisualState} from "./SidebarSessionItem";
 
+const sessionBadgeRefreshIntervalMs = 60_000;
+
 interface SidebarProps {
     sessions: SessionSnapshot[];
     activeSession: string | null;
     newSessionSignal: number;
+    /** Pre-selects "Use Git Worktree" when newSessionSignal opens the dialog. */
+    newSessionWorktree?: boolean;
 }
 
 export function Sidebar(props: SidebarProps) {
@@ -37,8 +43,12 @@ export function Sidebar(props: SidebarProps) {
     // that would otherwise cause scroll jitter in the session list.
     const listHeight = useContainerHeight(listHostRef, sessionRowHeight, {noiseThresholdPx: 1});
     const [showNewSession, setShowNewSession] = useState(false);
+    const [newSessionPreferWorktree, setNewSessionPreferWorktree] = useState(false);
     const [killTarget, setKillTarget] = useState<string | null>(null);
     const [promoteTarget, setPromoteTarget] = useState<string | null>(null);
+    const [hygieneRepoPath, setHygieneRepoPath] = useState<string | null>(null);
+    const [searchQuery, setSearchQuery] = useState("");
+    const visibleSessions = useSessionSearch(searchQuery, props.sessions);
     const activeSessionRef = useRef(props.activeSession);
     const lastNewSessionSignalRef = useRef(props.newSessionSignal);
     const renameInFlightRef = useRef<Set<string>>(new Set());
@@ -71,6 +81,22 @@ export function Sidebar(props: SidebarProps) {
         activeSessionRef.current = props.activeSession;
     }, [props.activeSession]);
 
+    // Branch and dirty state change outside the app, so rule-based badges are
+    // re-evaluated periodically and whenever the window regains focus.
+    useEffect(() => {
+        const refresh = () => {
+            void api.RefreshSessionBadges().catch((error: unknown) => {
+                console.warn("[sidebar] RefreshSessionBadges failed", error);
+            });
+        };
+        const timer = window.setInterval(refresh, sessionBadgeRefreshIntervalMs);
+        window.addEventListener("focus", refresh);
+        return () => {
+            window.clearInterval(timer);
+            window.removeEventListener("focus", refresh);
+        };
+    }, []);
+
     useEffect(() => {
         if (props.newSessionSignal === lastNewSessionSignalRef.current) {
             return;
@@ -79,8 +105,9 @@ export function Sidebar(props: SidebarProps) {
         if (props.newSessionSignal <= 0) {
             return;
         }
+        setNewSessionPreferWorktree(props.newSessionWorktree ?? false);
         setShowNewSession(true);
-    }, [props.newSessionSignal]);
+    }, [props.newSessionSignal, props.newSessionWorktree]);
 
     const activateSession = useCallback(
         async (sessionName: string) => {
@@ -154,6 +181,7 @@ export function Sidebar(props: SidebarProps) {
     );
 
     const handleNewSession = useCallback(() => {
+        setNewSessionPreferWorktree(false);
         setShowNewSession(true);
     }, []);
 
@@ -177,6 +205,10 @@ export function Sidebar(props: SidebarProps) {
         setPromoteTarget(sessionName);
     }, []);
 
+    const handleOpenRepoHygiene = useCallback((repoPath: string) => {
+        setHygieneRepoPath(repoPath);
+    }, []);
+
     const handleKillDone = useCallback(() => {
         const killed = killTarget;
         setKillTarget(null);
@@ -197,9 +229,27 @@ export function Sidebar(props: SidebarProps) {
         }
     }, [activateSession, clearDiffReviewSessionState, killTarget, props.activeSession, props.sessions, setActiveSession]);
 
+    // Rows are indexed within visibleSessions; reorder by the matching
+    // positions in the full session order.
+    const handleReorder = useCallback(
+        (fromIndex: number, toIndex: number) => {
+            if (visibleSessions === props.sessions) {
+                reorderSession(fromIndex, toIndex);
+                return;
+            }
+            const indexOf = (i: number) => props.sessions.findIndex((s) => s.name === visibleSessions[i]?.name);
+            const from = indexOf(fromIndex);
+            const to = indexOf(toIndex);
+            if (from >= 0 && to >= 0) {
+                reorderSession(from, to);
+            }
+        },
+        [props.sessions, reorderSession, visibleSessions],
+    );
+
     const rowData = useMemo<SessionRowData>(
         () => ({
-            sessions: props.sessions,
+            sessions: visibleSessions,
             activeSession: props.activeSession,
             editingSession,
             onActivate: activateSession,
@@ -207,17 +257,23 @@ export function Sidebar(props: SidebarProps) {
             onCommitRename: commitRename,
             onKill: handleKillClick,
             onPromote: handlePromote,
+            onOpenRepoHygiene: handleOpenRepoHygiene,
             onOpenDirectory: handleOpenDirectory,
             labelForSessionState,
-            onReorder: reorderSession,
+            onReorder: handleReorder,
         }),
-        [props.sessions, props.activeSession, editingSession, activateSession, startRename,
-            commitRename, handleKillClick, handlePromote, handleOpenDirectory, labelForSessionState, reorderSession],
+        [visibleSessions, props.activeSession, editingSession, activateSession, startRename,
+            commitRename, handleKillClick, handlePromote, handleOpenRepoHygiene, handleOpenDirectory, labelForSessionState,
+            handleReorder],
     );
 
     return (
         <aside className="sidebar">
-            <SidebarHeader onNewSession={handleNewSession}/>
+            <SidebarHeader
+                onNewSession={handleNewSession}
+                searchQuery={searchQuery}
+                onSearchChange={setSearchQuery}
+            />
 
             <div className="session-list" ref={listHostRef}>
                 {/* NOTE: height starts at 0 until ResizeObserver reports; guard prevents empty FixedSizeList render. */}
@@ -225,7 +281,7 @@ export function Sidebar(props: SidebarProps) {
                     <FixedSizeList
                         height={listHeight}
                         width="100%"
-                        itemCount={props.sessions.length}
+                        itemCount={visibleSessions.length}
                         itemSize={sessionRowHeight}
                         itemData={rowData}
                         overscanCount={6}
@@ -238,6 +294,7 @@ export function Sidebar(props: SidebarProps) {
 
             <NewSessionModal
                 open={showNewSession}
+                preferWorktree={newSessionPreferWorktree}
                 onClose={() => setShowNewSession(false)}
                 onCreated={(name) => {
                     void activateSession(name);
@@ -254,11 +311,18 @@ export function Sidebar(props: SidebarProps) {
             <PromoteBranchModal
                 open={promoteTarget !== null}
                 sessionName={promoteTarget || ""}
+                baseBranch={props.sessions.find((session) => session.name === promoteTarget)?.worktree?.base_branch ?? ""}
                 onClose={() => setPromoteTarget(null)}
                 onPromoted={() => {
                     /* snapshot will update via event */
                 }}
             />
+
+            <RepoHygieneModal
+                open={hygieneRepoPath !== null}
+                repoPath={hygieneRepoPath || ""}
+                onClose={() => setHygieneRepoPath(null)}
+            />
         </aside>
     );
 }
@@ -2,9 +2,11 @@ import {useI18n} from "../i18n";
 
 interface SidebarHeaderProps {
     readonly onNewSession: () => void;
+    readonly searchQuery: string;
+    readonly onSearchChange: (query: string) => void;
 }
 
-export function SidebarHeader({onNewSession}: SidebarHeaderProps) {
+export function SidebarHeader({onNewSession, searchQuery, onSearchChange}: SidebarHeaderProps) {
     const {language, t} = useI18n();
     return (
         <>
@@ -27,6 +29,23 @@ export function SidebarHeader({onNewSession}: SidebarHeaderProps) {
                         ? "+ New Session"
                         : t("sidebar.action.newSession", "+ 新規セッション")}
                 </button>
+                <input
+                    type="search"
+                    className="sidebar-search"
+                    value={searchQuery}
+                    onChange={(e) => onSearchChange(e.target.value)}
+                    onKeyDown={(e) => {
+                        if (e.key === "Escape" && searchQuery) {
+                            e.stopPropagation();
+                            onSearchChange("");
+                        }
+                    }}
+                    placeholder={language === "en"
+                        ? "Search sessions, branches, repos"
+                        : t("sidebar.search.placeholder", "セッション・ブランチ・リポジトリを検索")}
+                    aria-label={language === "en" ? "Search sessions" : t("sidebar.search.aria", "セッションを検索")}
+                    spellCheck={false}
+                />
             </div>
         </>
     );
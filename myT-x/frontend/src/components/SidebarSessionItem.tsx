@@ -1,6 +1,15 @@
-import {memo, useEffect, useRef, type ReactElement} from "react";
+import {memo, useEffect, useMemo, useRef, useState, type CSSProperties, type ReactElement} from "react";
 import type {ListChildComponentProps} from "react-window";
+import {BrowserOpenURL} from "../../wailsjs/runtime/runtime";
+import {api} from "../api";
 import {useI18n} from "../i18n";
+import {useCommandApprovalStore} from "../stores/commandApprovalStore";
+import {useGitStatusStore} from "../stores/gitStatusStore";
+import {useNotificationStore} from "../stores/notificationStore";
+import {summarizePaneActivity, usePaneActivityStore} from "../stores/paneActivityStore";
+import {useSessionPortsStore} from "../stores/sessionPortsStore";
+import {useTmuxStore} from "../stores/tmuxStore";
+import {useWorktreeBaseStore} from "../stores/worktreeBaseStore";
 import type {SessionSnapshot} from "../types/tmux";
 
 export type SessionVisualState = "running" | "idle" | "selected";
@@ -28,6 +37,7 @@ export interface SessionRowData {
     readonly onCommitRename: (oldName: string, newName: string) => void;
     readonly onKill: (e: React.MouseEvent, name: string) => void;
     readonly onPromote: (name: string) => void;
+    readonly onOpenRepoHygiene: (repoPath: string) => void;
     readonly onOpenDirectory: (name: string) => void;
     readonly labelForSessionState: (state: SessionVisualState) => string;
     readonly onReorder: (fromIndex: number, toIndex: number) => void;
@@ -78,6 +88,342 @@ function SessionBadges({session}: { readonly session: SessionSnapshot }) {
     );
 }
 
+// --- SessionPortLinks: one-click links to dev servers started in the session ---
+
+const maxVisibleSessionPorts = 2;
+
+function SessionPortLinks({sessionName}: { readonly sessionName: string }) {
+    const {language, t} = useI18n();
+    const ports = useSessionPortsStore((state) => state.ports[sessionName]);
+
+    // Seed from the backend registry: ports opened before this row mounted
+    // (e.g. after a frontend reload) were announced while nobody listened.
+    useEffect(() => {
+        let cancelled = false;
+        void api.GetSessionPorts(sessionName).then((result) => {
+            if (cancelled) return;
+            useSessionPortsStore.getState().setSessionPorts(sessionName, (result ?? []).map((port) => ({
+                sessionName,
+                paneId: port.pane_id,
+                port: port.port,
+                process: port.process ?? "",
+                url: port.url,
+            })));
+        }).catch((error: unknown) => {
+            console.warn("[sidebar] GetSessionPorts failed", {sessionName, error});
+        });
+        return () => {
+            cancelled = true;
+        };
+    }, [sessionName]);
+
+    if (!ports || ports.length === 0) {
+        return null;
+    }
+    return (
+        <>
+            {ports.slice(0, maxVisibleSessionPorts).map((port) => (
+                <button
+                    key={port.port}
+                    type="button"
+                    className="session-port-link"
+                    onClick={(e) => {
+                        e.stopPropagation();
+                        BrowserOpenURL(port.url);
+                    }}
+                    title={
+                        language === "en"
+                            ? `Open ${port.url}${port.process ? ` (${port.process})` : ""}`
+                            : t("sidebar.action.openPort.title", "{url} を開く", {url: port.url})
+                    }
+                >
+                    {`:${port.port}`}
+                </button>
+            ))}
+        </>
+    );
+}
+
+// --- SessionGitStatus: commits ahead/behind upstream and uncommitted files ---
+
+function SessionGitStatus({sessionName}: { readonly sessionName: string }) {
+    const {language, t} = useI18n();
+    const status = useGitStatusStore((state) => state.statuses[sessionName]);
+    if (!status || (status.ahead === 0 && status.behind === 0 && status.dirty === 0)) {
+        return null;
+    }
+    const upstream = status.upstream || "upstream";
+    const title = language === "en"
+        ? `${status.dirty} uncommitted file(s); ${status.ahead} ahead, ${status.behind} behind ${upstream}`
+        : t("sidebar.gitStatus.title", "未コミット {dirty} 件 / {upstream} より {ahead} 進み {behind} 遅れ", {
+            dirty: status.dirty,
+            upstream,
+            ahead: status.ahead,
+            behind: status.behind,
+        });
+    return (
+        <span className="session-git-status" title={title}>
+            {status.ahead > 0 && <span>{`\u2191${status.ahead}`}</span>}
+            {status.behind > 0 && <span>{`\u2193${status.behind}`}</span>}
+            {status.dirty > 0 && <span className="dirty">{`\u25CF${status.dirty}`}</span>}
+        </span>
+    );
+}
+
+// --- SessionPaneActivity: agents that wait for input or are working ---
+
+function SessionPaneActivity({session}: { readonly session: SessionSnapshot }) {
+    const {language, t} = useI18n();
+    const paneIds = useMemo(
+        () => session.windows.flatMap((window) => window.panes.map((pane) => pane.id)),
+        [session.windows],
+    );
+    const activity = usePaneActivityStore((state) => summarizePaneActivity(state.states, paneIds));
+    if (activity === null) {
+        return null;
+    }
+    const title = activity === "waiting"
+        ? (language === "en"
+            ? "A pane is waiting for input"
+            : t("sidebar.paneActivity.waiting", "入力待ちのペインがあります"))
+        : (language === "en"
+            ? "A pane is working"
+            : t("sidebar.paneActivity.busy", "実行中のペインがあります"));
+    return (
+        <span className={`session-pane-activity ${activity}`} title={title} aria-label={title}>
+            {activity === "waiting" ? "?" : "\u25CF"}
+        </span>
+    );
+}
+
+// --- SessionBaseRefreshButton: offers to rebase onto an advanced base branch ---
+
+function SessionBaseRefreshButton({sessionName}: { readonly sessionName: string }) {
+    const {language, t} = useI18n();
+    const advance = useWorktreeBaseStore((state) => state.advanced[sessionName]);
+    if (!advance) {
+        return null;
+    }
+    const title = language === "en"
+        ? `${advance.baseRef} is ${advance.behind} commit(s) ahead. Rebase onto it`
+        : t("sidebar.action.refreshBase.title", "{baseRef} が {behind} コミット進んでいます。rebase で取り込む", {
+            baseRef: advance.baseRef,
+            behind: advance.behind,
+        });
+
+    return (
+        <button
+            type="button"
+            className="modal-btn session-promote-btn"
+            onClick={(e) => {
+                e.stopPropagation();
+                // The result arrives as worktree:base-refreshed.
+                void api.RefreshWorktreeBase(sessionName, "rebase").catch((error: unknown) => {
+                    console.warn("[sidebar] RefreshWorktreeBase failed", {sessionName, error});
+                    useNotificationStore.getState().addNotification(String(error), "warn");
+                });
+            }}
+            title={title}
+        >
+            {language === "en"
+                ? "Rebase"
+                : t("sidebar.action.refreshBase.button", "Rebase")}
+        </button>
+    );
+}
+
+// --- SessionPullRequestButton: opens a pull request for the pushed branch ---
+
+function SessionPullRequestButton({sessionName}: { readonly sessionName: string }) {
+    const {language, t} = useI18n();
+    const forgeConfigured = useTmuxStore((state) => state.config?.forge != null);
+    if (!forgeConfigured) {
+        return null;
+    }
+
+    return (
+        <button
+            type="button"
+            className="modal-btn session-promote-btn"
+            onClick={(e) => {
+                e.stopPropagation();
+                void api.CreatePullRequestForSession(sessionName, {}).then((pr) => {
+                    const url = pr.url;
+                    const message = pr.existing
+                        ? (language === "en"
+                            ? `A pull request is already open: ${url}`
+                            : t("sidebar.action.pullRequest.existing", "プルリクエストは作成済みです: {url}", {url}))
+                        : (language === "en"
+                            ? `Pull request opened: ${url}`
+                            : t("sidebar.action.pullRequest.opened", "プルリクエストを作成しました: {url}", {url}));
+                    useNotificationStore.getState().addNotification(message, "info");
+                    BrowserOpenURL(url);
+                }).catch((error: unknown) => {
+                    console.warn("[sidebar] CreatePullRequestForSession failed", {sessionName, error});
+                    useNotificationStore.getState().addNotification(String(error), "warn");
+                });
+            }}
+            title={
+                language === "en"
+                    ? "Open a pull request for the pushed branch"
+                    : t("sidebar.action.pullRequest.title", "push 済みのブランチでプルリクエストを作成")
+            }
+        >
+            {language === "en"
+                ? "PR"
+                : t("sidebar.action.pullRequest.button", "PR")}
+        </button>
+    );
+}
+
+// --- SessionApprovalToggle: turns command approval mode on or off ---
+
+function SessionApprovalToggle({sessionName}: { readonly sessionName: string }) {
+    const {language, t} = useI18n();
+    const enabled = useCommandApprovalStore((state) => state.enabledSessions[sessionName] === true);
+    const pendingCount = useCommandApprovalStore(
+        (state) => state.pending.filter((item) => item.sessionName === sessionName).length,
+    );
+
+    useEffect(() => {
+        let cancelled = false;
+        void api.GetSessionApprovalMode(sessionName).then((result) => {
+            if (cancelled) return;
+            useCommandApprovalStore.getState().setSessionEnabled(sessionName, result?.enabled === true);
+        }).catch((error: unknown) => {
+            console.warn("[sidebar] GetSessionApprovalMode failed", {sessionName, error});
+        });
+        return () => {
+            cancelled = true;
+        };
+    }, [sessionName]);
+
+    const title = enabled
+        ? (language === "en"
+            ? "Approval mode on: agent commands wait for your decision (click to turn off)"
+            : t("sidebar.action.approvalMode.onTitle", "承認モード ON: エージェントのコマンドは承認待ちになります (クリックで OFF)"))
+        : (language === "en"
+            ? "Approval mode off (click to hold agent commands for approval)"
+            : t("sidebar.action.approvalMode.offTitle", "承認モード OFF (クリックでエージェントのコマンドを承認制に)"));
+
+    return (
+        <button
+            type="button"
+            className={`session-approval-toggle${enabled ? " enabled" : ""}`}
+            aria-pressed={enabled}
+            title={title}
+            onClick={(e) => {
+                e.stopPropagation();
+                const next = !enabled;
+                void api.SetSessionApprovalMode(sessionName, next).then(() => {
+                    useCommandApprovalStore.getState().setSessionEnabled(sessionName, next);
+                }).catch((error: unknown) => {
+                    console.warn("[sidebar] SetSessionApprovalMode failed", {sessionName, error});
+                });
+            }}
+        >
+            {"\u{1F6E1}"}
+            {pendingCount > 0 && <span className="session-approval-count">{pendingCount}</span>}
+        </button>
+    );
+}
+
+// --- SessionTaskbarAlertToggle: opts a session out of taskbar alerts ---
+
+function SessionTaskbarAlertToggle({sessionName, muted}: {
+    readonly sessionName: string;
+    readonly muted: boolean;
+}) {
+    const {language, t} = useI18n();
+    const title = muted
+        ? (language === "en"
+            ? "Taskbar alerts off for this session (click to turn on)"
+            : t("sidebar.action.taskbarAlerts.mutedTitle", "このセッションのタスクバー通知 OFF (クリックで ON)"))
+        : (language === "en"
+            ? "Flash the taskbar when this session needs input while the window is in the background (click to turn off)"
+            : t("sidebar.action.taskbarAlerts.onTitle", "ウィンドウが背面にある間、入力待ちになるとタスクバーで通知します (クリックで OFF)"));
+
+    return (
+        <button
+            type="button"
+            className={`session-taskbar-toggle${muted ? " muted" : ""}`}
+            aria-pressed={muted}
+            title={title}
+            onClick={(e) => {
+                e.stopPropagation();
+                void api.SetSessionTaskbarAlertsMuted(sessionName, !muted).catch((error: unknown) => {
+                    console.warn("[sidebar] SetSessionTaskbarAlertsMuted failed", {sessionName, error});
+                });
+            }}
+        >
+            {muted ? "\u{1F515}" : "\u{1F514}"}
+        </button>
+    );
+}
+
+// --- SessionNetworkCaptureToggle: records the session's HTTP traffic for debugging ---
+
+function SessionNetworkCaptureToggle({sessionName}: { readonly sessionName: string }) {
+    const {language, t} = useI18n();
+    const [status, setStatus] = useState<{ enabled: boolean; proxyURL: string; captured: number }>({
+        enabled: false,
+        proxyURL: "",
+        captured: 0,
+    });
+
+    useEffect(() => {
+        let cancelled = false;
+        void api.GetNetworkCaptureStatus(sessionName).then((result) => {
+            if (cancelled) return;
+            setStatus({
+                enabled: result?.enabled === true,
+                proxyURL: result?.proxy_url ?? "",
+                captured: result?.captured ?? 0,
+            });
+        }).catch((error: unknown) => {
+            console.warn("[sidebar] GetNetworkCaptureStatus failed", {sessionName, error});
+        });
+        return () => {
+            cancelled = true;
+        };
+    }, [sessionName]);
+
+    const title = status.enabled
+        ? (language === "en"
+            ? `Network capture on via ${status.proxyURL}: ${status.captured} request(s) recorded. Applies to panes started after it was turned on (click to turn off)`
+            : t("sidebar.action.networkCapture.onTitle", "通信キャプチャ ON ({proxyURL}): {captured} 件記録済み。ON にした後に起動したペインが対象です (クリックで OFF)", {
+                proxyURL: status.proxyURL,
+                captured: status.captured,
+            }))
+        : (language === "en"
+            ? "Network capture off (click to record HTTP requests of panes started afterwards)"
+            : t("sidebar.action.networkCapture.offTitle", "通信キャプチャ OFF (クリックで以降に起動したペインの HTTP 通信を記録)"));
+
+    return (
+        <button
+            type="button"
+            className={`session-netcapture-toggle${status.enabled ? " enabled" : ""}`}
+            aria-pressed={status.enabled}
+            title={title}
+            onClick={(e) => {
+                e.stopPropagation();
+                void api.SetNetworkCapture(sessionName, !status.enabled, false).then((result) => {
+                    setStatus({
+                        enabled: result?.enabled === true,
+                        proxyURL: result?.proxy_url ?? "",
+                        captured: result?.captured ?? 0,
+                    });
+                }).catch((error: unknown) => {
+                    console.warn("[sidebar] SetNetworkCapture failed", {sessionName, error});
+                    useNotificationStore.getState().addNotification(String(error), "warn");
+                });
+            }}
+        >
+            {"\u{1F4E1}"}
+        </button>
+    );
+}
+
 // --- SidebarSessionItem: single session item rendering ---
 
 interface SidebarSessionItemProps {
@@ -90,6 +436,7 @@ interface SidebarSessionItemProps {
     readonly onCommitRename: (oldName: string, newName: string) => void;
     readonly onKill: (e: React.MouseEvent, name: string) => void;
     readonly onPromote: (name: string) => void;
+    readonly onOpenRepoHygiene: (repoPath: string) => void;
     readonly onOpenDirectory: (name: string) => void;
 }
 
@@ -103,6 +450,7 @@ export function SidebarSessionItem({
     onCommitRename,
     onKill,
     onPromote,
+    onOpenRepoHygiene,
     onOpenDirectory,
 }: SidebarSessionItemProps): ReactElement {
     const {language, t} = useI18n();
@@ -122,7 +470,8 @@ export function SidebarSessionItem({
         <div
             role="button"
             tabIndex={0}
-            className={`session-item ${sessionState}`}
+            className={`session-item ${sessionState}${session.detached ? " detached" : ""}${session.badge?.color ? " has-badge-color" : ""}`}
+            style={session.badge?.color ? {"--session-badge-color": session.badge.color} as CSSProperties : undefined}
             onClick={() => {
                 if (isEditing) return;
                 onActivate(session.name);
@@ -147,6 +496,16 @@ export function SidebarSessionItem({
                 <span className={`session-type-mark ${session.is_agent_team ? "agent" : "session"}`}>
                     {session.is_agent_team ? "A" : "S"}
                 </span>
+                {session.badge?.emoji && (
+                    <span
+                        className={`session-badge-emoji${session.badge.auto ? " auto" : ""}`}
+                        title={session.badge.auto
+                            ? (language === "en" ? "Badge from rules" : t("sidebar.badge.auto", "ルールによるバッジ"))
+                            : undefined}
+                    >
+                        {session.badge.emoji}
+                    </span>
+                )}
                 {isEditing ? (
                     <input
                         key={session.name}
@@ -173,6 +532,12 @@ export function SidebarSessionItem({
                 ) : (
                     <span className="session-name">{session.name}</span>
                 )}
+                <SessionPaneActivity session={session}/>
+                <SessionGitStatus sessionName={session.name}/>
+                <SessionPortLinks sessionName={session.name}/>
+                <SessionApprovalToggle sessionName={session.name}/>
+                <SessionTaskbarAlertToggle sessionName={session.name} muted={session.taskbar_alerts_muted === true}/>
+                <SessionNetworkCaptureToggle sessionName={session.name}/>
                 <span className={`session-state ${sessionState}`}>
                     {sessionStateLabel}
                 </span>
@@ -180,6 +545,12 @@ export function SidebarSessionItem({
             {(session.worktree?.repo_path || session.worktree?.is_detached) && (
                 <span className="session-meta">
                     <SessionBadges session={session}/>
+                    {Boolean(session.worktree?.base_branch?.trim()) && !session.worktree?.is_detached && (
+                        <SessionBaseRefreshButton sessionName={session.name}/>
+                    )}
+                    {Boolean(session.worktree?.path?.trim()) && !session.worktree?.is_detached && (
+                        <SessionPullRequestButton sessionName={session.name}/>
+                    )}
                     {session.worktree?.is_detached && Boolean(session.worktree?.path?.trim()) && (
                         <button
                             type="button"
@@ -199,6 +570,25 @@ export function SidebarSessionItem({
                                 : t("sidebar.action.promoteBranch.button", "Promote")}
                         </button>
                     )}
+                    {Boolean(session.worktree?.repo_path?.trim()) && (
+                        <button
+                            type="button"
+                            className="modal-btn session-promote-btn"
+                            onClick={(e) => {
+                                e.stopPropagation();
+                                onOpenRepoHygiene(session.worktree?.repo_path?.trim() ?? "");
+                            }}
+                            title={
+                                language === "en"
+                                    ? "Stale branches, prunable worktrees, large files, and old stashes"
+                                    : t("sidebar.action.repoHygiene.title", "古いブランチ・worktree・大きなファイル・stash を確認")
+                            }
+                        >
+                            {language === "en"
+                                ? "Tidy"
+                                : t("sidebar.action.repoHygiene.button", "整理")}
+                        </button>
+                    )}
                 </span>
             )}
             {(session.root_path?.trim() || session.worktree?.path?.trim()) && (
@@ -303,6 +693,7 @@ export const SessionRow = memo(function SessionRow({index, style, data}: ListChi
                     onCommitRename={data.onCommitRename}
                     onKill={data.onKill}
                     onPromote={data.onPromote}
+                    onOpenRepoHygiene={data.onOpenRepoHygiene}
                     onOpenDirectory={data.onOpenDirectory}
                 />
             </div>
@@ -330,7 +721,8 @@ export const SessionRow = memo(function SessionRow({index, style, data}: ListChi
         //
         // Intentionally omitted from comparison (all useCallback-wrapped with
         // stable deps in Sidebar, so reference never changes across renders):
-        //   onStartRename, onKill, onPromote, onOpenDirectory, labelForSessionState
+        //   onStartRename, onKill, onPromote, onOpenRepoHygiene, onOpenDirectory,
+        //   labelForSessionState
         // If any of these callbacks' useCallback wrapping is removed in Sidebar,
         // add them to this comparison to prevent stale-callback rendering.
         pd.onActivate === nd.onActivate &&
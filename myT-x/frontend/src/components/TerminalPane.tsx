@@ -18,18 +18,22 @@ import {useTerminalSetup} from "../hooks/useTerminalSetup";
 import {useTerminalEvents} from "../hooks/useTerminalEvents";
 import {useTerminalResize} from "../hooks/useTerminalResize";
 import {useTerminalFontSize} from "../hooks/useTerminalFontSize";
+import {useTerminalMouseMode} from "../hooks/useTerminalMouseMode";
 import {useI18n} from "../i18n";
 import type {AppConfigAutoStartCommand} from "../types/tmux";
 
 interface TerminalPaneProps {
     paneId: string;
     paneTitle?: string;
+    /** Per-pane mouse override ("on" / "off"); omitted follows the mouse config. */
+    paneMouse?: string;
     active: boolean;
     onFocus: (paneId: string) => void;
     onSplitVertical: (paneId: string) => void;
     onSplitHorizontal: (paneId: string) => void;
     onToggleZoom: (paneId: string) => void;
     onKillPane: (paneId: string) => void;
+    onCollapsePane?: (paneId: string) => void;
     onRenamePane: (paneId: string, title: string) => void | Promise<void>;
     onSwapPane: (sourcePaneId: string, targetPaneId: string) => void | Promise<void>;
     onDetach: () => void;
@@ -68,6 +72,8 @@ function TerminalPaneComponent(props: TerminalPaneProps) {
     const activeSession = useTmuxStore((s) => s.activeSession);
     const autoStartEntries = useTmuxStore((s) => s.config?.auto_start ?? EMPTY_AUTO_START_ENTRIES);
     const hasAutoStartEntries = autoStartEntries.some((entry) => entry.command.trim());
+    const mouseDefault = useTmuxStore((s) => s.config?.mouse !== "off");
+    const mouseEnabled = props.paneMouse === "on" || (props.paneMouse !== "off" && mouseDefault);
     const canvasMode = useCanvasStore((s) => s.mode);
     const rootPaneId = useCanvasStore((s) => s.rootPaneId);
     const setRootPaneId = useCanvasStore((s) => s.setRootPaneId);
@@ -184,6 +190,13 @@ function TerminalPaneComponent(props: TerminalPaneProps) {
         fontSizeRef,
     });
 
+    // --- マウスモード（アプリケーションのマウストラッキング要求の可否） ---
+    useTerminalMouseMode({
+        paneId: props.paneId,
+        terminalRef,
+        enabled: mouseEnabled,
+    });
+
     const preventTerminalFocusSteal = useCallback((event: ReactMouseEvent<HTMLElement>): void => {
         event.preventDefault();
         event.stopPropagation();
@@ -333,6 +346,14 @@ function TerminalPaneComponent(props: TerminalPaneProps) {
                     props.onSplitHorizontal(props.paneId);
                 }}
                 onAddMember={handleAddMember}
+                mouseEnabled={mouseEnabled}
+                onToggleMouse={() => {
+                    void api.SetPaneMouse(props.paneId, mouseEnabled ? "off" : "on").catch((err: unknown) => {
+                        console.warn("[pane] set mouse mode failed", err);
+                        notifyAndLog("Toggle mouse mode", "warn", err, "TerminalPane");
+                    });
+                }}
+                onCollapse={props.onCollapsePane ? () => props.onCollapsePane?.(props.paneId) : undefined}
                 onClose={() => setPendingPaneCloseConfirm(true)}
                 preventTerminalFocusSteal={preventTerminalFocusSteal}
             />
@@ -424,7 +445,7 @@ function TerminalPaneComponent(props: TerminalPaneProps) {
  * カスタム比較関数: paneId / active / paneTitle のみを比較対象とする。
  *
  * 前提: onFocus / onSplitVertical / onSplitHorizontal / onToggleZoom /
- *       onKillPane / onRenamePane / onSwapPane / onDetach は、
+ *       onKillPane / onCollapsePane / onRenamePane / onSwapPane / onDetach は、
  *       親コンポーネントが useCallback で安定参照を維持していること。
  * これらの関数 props を比較から除外しているため、親が useCallback を
  * 使わない場合は不要な再レンダリングが抑制されなくなる。
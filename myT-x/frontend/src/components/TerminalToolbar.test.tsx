@@ -18,6 +18,8 @@ function renderToolbar(root: Root): void {
             onAutoClick={vi.fn()}
             onAutoStartClick={vi.fn()}
             autoStartDisabled={false}
+            mouseEnabled={false}
+            onToggleMouse={vi.fn()}
             onSplitVertical={vi.fn()}
             onSplitHorizontal={vi.fn()}
             onAddMember={vi.fn()}
@@ -77,6 +79,8 @@ describe("TerminalToolbar", () => {
                     onAutoClick={vi.fn()}
                     onAutoStartClick={vi.fn()}
                     autoStartDisabled={false}
+                    mouseEnabled={false}
+                    onToggleMouse={vi.fn()}
                     onRootToggle={onRootToggle}
                     onSplitVertical={vi.fn()}
                     onSplitHorizontal={vi.fn()}
@@ -117,6 +121,8 @@ describe("TerminalToolbar", () => {
                         onAutoClick={vi.fn()}
                         onAutoStartClick={vi.fn()}
                         autoStartDisabled={false}
+                        mouseEnabled={false}
+                        onToggleMouse={vi.fn()}
                         onSplitVertical={vi.fn()}
                         onSplitHorizontal={vi.fn()}
                         onAddMember={vi.fn()}
@@ -155,6 +161,8 @@ describe("TerminalToolbar", () => {
                     onAutoClick={vi.fn()}
                     onAutoStartClick={vi.fn()}
                     autoStartDisabled={false}
+                    mouseEnabled={false}
+                    onToggleMouse={vi.fn()}
                     onRootToggle={vi.fn()}
                     onSplitVertical={vi.fn()}
                     onSplitHorizontal={vi.fn()}
@@ -187,6 +195,8 @@ describe("TerminalToolbar", () => {
                     onAutoClick={vi.fn()}
                     onAutoStartClick={onAutoStartClick}
                     autoStartDisabled={false}
+                    mouseEnabled={false}
+                    onToggleMouse={vi.fn()}
                     onSplitVertical={vi.fn()}
                     onSplitHorizontal={vi.fn()}
                     onAddMember={vi.fn()}
@@ -211,6 +221,44 @@ describe("TerminalToolbar", () => {
         expect(onAutoStartClick).toHaveBeenCalledTimes(1);
     });
 
+    it("reflects and toggles the pane mouse mode", () => {
+        const onToggleMouse = vi.fn();
+
+        act(() => {
+            root.render(
+                <TerminalToolbar
+                    paneId="%1"
+                    titleDraft="Pane"
+                    renameBusy={false}
+                    autoRunning={false}
+                    onTitleEditStart={vi.fn()}
+                    onTitleChange={vi.fn()}
+                    onTitleCommit={vi.fn()}
+                    onTitleCancel={vi.fn()}
+                    onAutoClick={vi.fn()}
+                    onAutoStartClick={vi.fn()}
+                    autoStartDisabled={false}
+                    mouseEnabled
+                    onToggleMouse={onToggleMouse}
+                    onSplitVertical={vi.fn()}
+                    onSplitHorizontal={vi.fn()}
+                    onAddMember={vi.fn()}
+                    onClose={vi.fn()}
+                    preventTerminalFocusSteal={vi.fn()}
+                />,
+            );
+        });
+
+        const button = container.querySelector('[aria-label="Toggle mouse mode for pane %1"]') as HTMLButtonElement;
+        expect(button).not.toBeNull();
+        expect(button.getAttribute("aria-pressed")).toBe("true");
+        expect(button.classList.contains("terminal-toolbar-btn-mouse-active")).toBe(true);
+        act(() => {
+            button.click();
+        });
+        expect(onToggleMouse).toHaveBeenCalledTimes(1);
+    });
+
     it("keeps AutoStart visible but disabled without entries", () => {
         const onAutoStartClick = vi.fn();
 
@@ -228,6 +276,8 @@ describe("TerminalToolbar", () => {
                     onAutoClick={vi.fn()}
                     onAutoStartClick={onAutoStartClick}
                     autoStartDisabled
+                    mouseEnabled={false}
+                    onToggleMouse={vi.fn()}
                     onSplitVertical={vi.fn()}
                     onSplitHorizontal={vi.fn()}
                     onAddMember={vi.fn()}
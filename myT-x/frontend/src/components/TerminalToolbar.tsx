@@ -17,10 +17,15 @@ interface TerminalToolbarProps {
     readonly onAutoClick: () => void;
     readonly onAutoStartClick: () => void;
     readonly autoStartDisabled: boolean;
+    /** Whether mouse events reach the pane application (tmux "mouse on"). */
+    readonly mouseEnabled: boolean;
+    readonly onToggleMouse: () => void;
     readonly onRootToggle?: () => void;
     readonly onSplitVertical: () => void;
     readonly onSplitHorizontal: () => void;
     readonly onAddMember: () => void;
+    /** Collapses the pane to a header. The button is hidden when omitted. */
+    readonly onCollapse?: () => void;
     readonly onClose: () => void;
     readonly preventTerminalFocusSteal: (event: ReactMouseEvent<HTMLElement>) => void;
 }
@@ -39,10 +44,13 @@ export const TerminalToolbar = memo(function TerminalToolbar({
     onAutoClick,
     onAutoStartClick,
     autoStartDisabled,
+    mouseEnabled,
+    onToggleMouse,
     onRootToggle,
     onSplitVertical,
     onSplitHorizontal,
     onAddMember,
+    onCollapse,
     onClose,
     preventTerminalFocusSteal,
 }: TerminalToolbarProps) {
@@ -66,6 +74,9 @@ export const TerminalToolbar = memo(function TerminalToolbar({
     const autoButtonClass = autoRunning
         ? "terminal-toolbar-btn terminal-toolbar-btn-auto-active"
         : "terminal-toolbar-btn";
+    const mouseButtonClass = mouseEnabled
+        ? "terminal-toolbar-btn terminal-toolbar-btn-mouse-active"
+        : "terminal-toolbar-btn";
     const rootButtonClass = isRootPane
         ? "terminal-toolbar-btn terminal-toolbar-btn-root-active"
         : "terminal-toolbar-btn";
@@ -184,6 +195,42 @@ export const TerminalToolbar = memo(function TerminalToolbar({
                         <polyline points="7,0.5 7,2.5 9,2.5"/>
                     </svg>
                 </button>
+                <button
+                    type="button"
+                    className={mouseButtonClass}
+                    draggable={false}
+                    aria-pressed={mouseEnabled}
+                    title={
+                        isEn
+                            ? (mouseEnabled
+                                ? "Mouse: on (events go to the application; Shift+drag selects)"
+                                : "Mouse: off (drag selects text)")
+                            : t(
+                                mouseEnabled
+                                    ? "terminalPane.action.mouseOn.title"
+                                    : "terminalPane.action.mouseOff.title",
+                                mouseEnabled
+                                    ? "マウス: オン (アプリにイベントを送信、Shift+ドラッグで選択)"
+                                    : "マウス: オフ (ドラッグでテキスト選択)",
+                            )
+                    }
+                    aria-label={
+                        isEn
+                            ? `Toggle mouse mode for pane ${paneId}`
+                            : t("terminalPane.action.mouse.aria", "ペイン {paneId} のマウスモードを切り替え", {paneId})
+                    }
+                    onMouseDown={preventTerminalFocusSteal}
+                    onClick={(e) => {
+                        e.stopPropagation();
+                        onToggleMouse();
+                    }}
+                >
+                    <svg width="14" height="14" viewBox="0 0 14 14" fill="none" stroke="currentColor"
+                         strokeWidth="1.5">
+                        <rect x="3.5" y="1" width="7" height="12" rx="3.5"/>
+                        <line x1="7" y1="1" x2="7" y2="5.5"/>
+                    </svg>
+                </button>
                 <button
                     type="button"
                     className="terminal-toolbar-btn"
@@ -293,6 +340,35 @@ export const TerminalToolbar = memo(function TerminalToolbar({
                         <path d="M5 5l3 2-3 2z" fill="currentColor" stroke="none"/>
                     </svg>
                 </button>
+                {onCollapse && (
+                    <button
+                        type="button"
+                        className="terminal-toolbar-btn"
+                        draggable={false}
+                        title={
+                            isEn
+                                ? "Collapse to header (expands on output)"
+                                : t("terminalPane.action.collapse.title", "ヘッダーに折りたたむ (出力があると展開)")
+                        }
+                        aria-label={
+                            isEn
+                                ? `Collapse pane ${paneId}`
+                                : t("terminalPane.action.collapse.aria", "ペイン {paneId} を折りたたむ", {paneId})
+                        }
+                        onMouseDown={preventTerminalFocusSteal}
+                        onClick={(e) => {
+                            e.stopPropagation();
+                            onCollapse();
+                        }}
+                    >
+                        <svg width="14" height="14" viewBox="0 0 14 14" fill="none" stroke="currentColor"
+                             strokeWidth="1.5">
+                            <rect x="1" y="1" width="12" height="12" rx="1.5"/>
+                            <line x1="1" y1="4.5" x2="13" y2="4.5"/>
+                            <polyline points="4.5,10.5 7,8 9.5,10.5"/>
+                        </svg>
+                    </button>
+                )}
                 <button
                     type="button"
                     className="terminal-toolbar-btn terminal-toolbar-btn-danger terminal-toolbar-btn-close"
@@ -1,5 +1,36 @@
+import { useState } from "react";
 import { useI18n } from "../i18n";
-import { useNotificationStore } from "../stores/notificationStore";
+import { useNotificationStore, type Notification } from "../stores/notificationStore";
+
+function ToastGroupBody({ notification }: { notification: Notification }) {
+  const { language, t } = useI18n();
+  const [showDetails, setShowDetails] = useState(false);
+  const group = notification.group;
+  if (!group) return null;
+
+  return (
+    <div className="toast-group">
+      {group.hint !== "" && <span className="toast-hint">{group.hint}</span>}
+      <button
+        type="button"
+        className="toast-details-toggle"
+        onClick={() => setShowDetails((v) => !v)}
+        aria-expanded={showDetails}
+      >
+        {showDetails
+          ? t("toast.hideDetails", language === "ja" ? "詳細を隠す" : "Hide details")
+          : t("toast.viewDetails", language === "ja" ? "詳細を表示" : "View details")}
+      </button>
+      {showDetails && (
+        <pre className="toast-details">
+          {[`code: ${group.code}`, group.source !== "" ? `source: ${group.source}` : "", group.detail]
+            .filter((line) => line !== "")
+            .join("\n")}
+        </pre>
+      )}
+    </div>
+  );
+}
 
 export function ToastContainer() {
   const { language, t } = useI18n();
@@ -12,7 +43,13 @@ export function ToastContainer() {
     <div className="toast-container">
       {notifications.map((n) => (
         <div key={n.id} className={`toast toast-${n.level}`}>
-          <span className="toast-message">{n.message}</span>
+          <div className="toast-message">
+            <span>{n.message}</span>
+            {n.group && n.group.count > 1 && (
+              <span className="toast-count">×{n.group.count}</span>
+            )}
+            <ToastGroupBody notification={n} />
+          </div>
           <button
             type="button"
             className="toast-close"
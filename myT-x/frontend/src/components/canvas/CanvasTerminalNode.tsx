@@ -8,6 +8,7 @@ import {useI18n} from "../../i18n";
 interface CanvasTerminalNodeData {
     paneId: string;
     paneTitle: string;
+    paneMouse?: string;
     active: boolean;
     unregistered?: boolean;
     onEnlist?: (paneId: string) => void;
@@ -82,6 +83,7 @@ function CanvasTerminalNodeComponent(props: NodeProps) {
                 <TerminalPane
                     paneId={data.paneId}
                     paneTitle={data.paneTitle}
+                    paneMouse={data.paneMouse}
                     active={data.active}
                     onFocus={data.onFocus}
                     onSplitVertical={data.onSplitVertical}
@@ -88,6 +88,7 @@ export function CanvasView(props: CanvasViewProps) {
                 data: {
                     paneId: pane.id,
                     paneTitle: pane.title ?? "",
+                    paneMouse: pane.mouse,
                     active: pane.id === props.activePaneId,
                     unregistered: unregisteredPaneMap.has(pane.id),
                     onEnlist: setEnlistPaneId,
@@ -0,0 +1,104 @@
+import {useEffect, useState} from "react";
+import {api} from "../../api";
+import {useI18n} from "../../i18n";
+import type {repoconfig} from "../../../wailsjs/go/models";
+
+interface DirectoryTrustPromptProps {
+    directory: string;
+}
+
+/**
+ * First-open prompt for a folder whose .mytx.yaml carries setup scripts or a
+ * startup command. Nothing from the file runs until the folder is trusted.
+ */
+export function DirectoryTrustPrompt({directory}: DirectoryTrustPromptProps) {
+    const {language, t} = useI18n();
+    const isEn = language === "en";
+    const [status, setStatus] = useState<repoconfig.Status | null>(null);
+    const [saving, setSaving] = useState(false);
+    const [error, setError] = useState("");
+
+    useEffect(() => {
+        let cancelled = false;
+        setStatus(null);
+        setError("");
+        api.GetDirectoryTrust(directory)
+            .then((result) => {
+                if (!cancelled) setStatus(result);
+            })
+            .catch((err: unknown) => {
+                if (import.meta.env.DEV) {
+                    console.warn("[DirectoryTrustPrompt] GetDirectoryTrust failed", err);
+                }
+            });
+        return () => { cancelled = true; };
+    }, [directory]);
+
+    const config = status?.config;
+    const scripts = config?.setup_scripts ?? [];
+    const startupCommand = config?.startup_command ?? "";
+    if (!status || status.level !== "" || (scripts.length === 0 && startupCommand === "")) {
+        return null;
+    }
+
+    const decide = async (level: "trusted" | "untrusted") => {
+        setSaving(true);
+        setError("");
+        try {
+            await api.SetDirectoryTrust(status.path, level);
+            // The decision is stored; the prompt is not shown again for this folder.
+            setStatus(null);
+        } catch (err: unknown) {
+            setError(String(err));
+        } finally {
+            setSaving(false);
+        }
+    };
+
+    return (
+        <div className="directory-trust-prompt" role="alert">
+            <p className="directory-trust-title">
+                {isEn
+                    ? "This folder's .mytx.yaml wants to run commands. Do you trust its authors?"
+                    : t("newSession.trust.title", "このフォルダの .mytx.yaml はコマンドを実行します。作成者を信頼しますか？")}
+            </p>
+            <ul className="directory-trust-commands">
+                {startupCommand !== "" && (
+                    <li>
+                        {isEn ? "Startup command: " : t("newSession.trust.startupCommand", "起動コマンド: ")}
+                        <code>{startupCommand}</code>
+                    </li>
+                )}
+                {scripts.map((script, index) => (
+                    <li key={`${index}-${script}`}>
+                        {isEn ? "Setup script: " : t("newSession.trust.setupScript", "セットアップスクリプト: ")}
+                        <code>{script}</code>
+                    </li>
+                ))}
+            </ul>
+            {status.signature === "valid" && (
+                <p className="directory-trust-signature">
+                    {isEn
+                        ? `Signed by ${status.signed_by ?? ""}`
+                        : t("newSession.trust.signedBy", "{signer} が署名済み", {signer: status.signed_by ?? ""})}
+                </p>
+            )}
+            {status.signature === "unverified" && (
+                <p className="directory-trust-signature">
+                    {isEn
+                        ? "Signed, but no signing keys are configured to verify it."
+                        : t("newSession.trust.unverified", "署名されていますが、検証用の署名鍵が設定されていません。")}
+                </p>
+            )}
+            {error && <p className="form-error">{error}</p>}
+            <div className="directory-trust-actions">
+                <button type="button" className="modal-btn" disabled={saving} onClick={() => void decide("untrusted")}>
+                    {isEn ? "Don't Trust" : t("newSession.trust.deny", "信頼しない")}
+                </button>
+                <button type="button" className="modal-btn primary" disabled={saving} onClick={() => void decide("trusted")}>
+                    {isEn ? "Trust Folder" : t("newSession.trust.allow", "フォルダを信頼する")}
+                </button>
+            </div>
+        </div>
+    );
+}
@@ -100,6 +100,38 @@ export function NewSessionForm({s, dispatch, canSubmit, onSubmit}: NewSessionFor
                         : t("newSession.env.sessionPaneScope", "セッション単位ペイン管理を利用する")}
                 </label>
             </div>
+
+            {/* Shell history isolation option */}
+            <div className="form-checkbox-row">
+                <input
+                    type="checkbox"
+                    id="isolate-shell-history"
+                    checked={s.isolateShellHistory}
+                    onChange={(e) => dispatch({type: "SET_FIELD", field: "isolateShellHistory", value: e.target.checked})}
+                />
+                <label htmlFor="isolate-shell-history">
+                    {isEn
+                        ? "Keep shell history separate from the global history"
+                        : t("newSession.env.shellHistory", "シェル履歴をグローバル履歴から分離する")}
+                </label>
+            </div>
+
+            {/* Ephemeral preview option (not for existing worktrees, which the user owns) */}
+            {!(s.useWorktree && s.worktreeSource === "existing") && (
+                <div className="form-checkbox-row">
+                    <input
+                        type="checkbox"
+                        id="ephemeral-session"
+                        checked={s.ephemeral}
+                        onChange={(e) => dispatch({type: "SET_FIELD", field: "ephemeral", value: e.target.checked})}
+                    />
+                    <label htmlFor="ephemeral-session">
+                        {isEn
+                            ? "Preview session: remove it with its worktree and history after a while or on exit"
+                            : t("newSession.env.ephemeral", "プレビューセッション: 一定時間後または終了時にワークツリー・履歴ごと削除する")}
+                    </label>
+                </div>
+            )}
         </>
     );
 }
@@ -0,0 +1,97 @@
+import {useCallback, useEffect, useState} from "react";
+import {api} from "../../api";
+import {useI18n} from "../../i18n";
+import type {recentdirs} from "../../../wailsjs/go/models";
+
+interface RecentDirectoryListProps {
+    /** Currently picked directory, highlighted in the list. */
+    selected: string;
+    onSelect: (directory: string) => void;
+}
+
+/**
+ * Recent session directories, pinned first. Missing folders stay listed
+ * (disabled) so they can be removed or come back when the drive reappears.
+ */
+export function RecentDirectoryList({selected, onSelect}: RecentDirectoryListProps) {
+    const {language, t} = useI18n();
+    const isEn = language === "en";
+    const [directories, setDirectories] = useState<recentdirs.Directory[]>([]);
+
+    const reload = useCallback(() => {
+        api.ListRecentDirectories()
+            .then((result) => setDirectories(result ?? []))
+            .catch((err: unknown) => {
+                if (import.meta.env.DEV) {
+                    console.warn("[RecentDirectoryList] ListRecentDirectories failed", err);
+                }
+            });
+    }, []);
+
+    useEffect(() => {
+        reload();
+    }, [reload]);
+
+    const togglePin = (dir: recentdirs.Directory) => {
+        void api.SetRecentDirectoryPinned(dir.path, !dir.pinned)
+            .then(reload)
+            .catch((err: unknown) => {
+                console.warn("[RecentDirectoryList] SetRecentDirectoryPinned failed", err);
+            });
+    };
+
+    const remove = (dir: recentdirs.Directory) => {
+        void api.RemoveRecentDirectory(dir.path)
+            .then(reload)
+            .catch((err: unknown) => {
+                console.warn("[RecentDirectoryList] RemoveRecentDirectory failed", err);
+            });
+    };
+
+    if (directories.length === 0) {
+        return null;
+    }
+
+    return (
+        <ul className="recent-directory-list" aria-label={isEn ? "Recent folders" : t("newSession.recent.label", "最近使ったフォルダ")}>
+            {directories.map((dir) => (
+                <li
+                    key={dir.path}
+                    className={`recent-directory-item${dir.path === selected ? " selected" : ""}${dir.exists ? "" : " missing"}`}
+                >
+                    <button
+                        type="button"
+                        className="recent-directory-path"
+                        disabled={!dir.exists}
+                        title={dir.exists
+                            ? dir.path
+                            : (isEn ? "Folder not found" : t("newSession.recent.missing", "フォルダが見つかりません"))}
+                        onClick={() => onSelect(dir.path)}
+                    >
+                        {dir.path}
+                    </button>
+                    {dir.is_git_repo && <span className="recent-directory-badge">git</span>}
+                    <button
+                        type="button"
+                        className={`recent-directory-action${dir.pinned ? " active" : ""}`}
+                        aria-pressed={dir.pinned}
+                        title={dir.pinned
+                            ? (isEn ? "Unpin" : t("newSession.recent.unpin", "ピン留めを解除"))
+                            : (isEn ? "Pin" : t("newSession.recent.pin", "ピン留め"))}
+                        onClick={() => togglePin(dir)}
+                    >
+                        {dir.pinned ? "★" : "☆"}
+                    </button>
+                    <button
+                        type="button"
+                        className="recent-directory-action"
+                        title={isEn ? "Remove from list" : t("newSession.recent.remove", "一覧から削除")}
+                        onClick={() => remove(dir)}
+                    >
+                        ×
+                    </button>
+                </li>
+            ))}
+        </ul>
+    );
+}
@@ -0,0 +1,69 @@
+import {useEffect, useState} from "react";
+import {api} from "../../api";
+import {useI18n} from "../../i18n";
+import type {repostats} from "../../../wailsjs/go/models";
+
+/** Languages shown individually; the rest are folded into "+N more". */
+const MAX_LISTED_LANGUAGES = 4;
+
+interface RepoStatsSummaryProps {
+    directory: string;
+}
+
+/** One-line language breakdown of the picked directory. Hidden on failure. */
+export function RepoStatsSummary({directory}: RepoStatsSummaryProps) {
+    const {language, t} = useI18n();
+    const isEn = language === "en";
+    const [stats, setStats] = useState<repostats.RepoStats | null>(null);
+
+    useEffect(() => {
+        let cancelled = false;
+        setStats(null);
+        api.GetRepoStats(directory)
+            .then((result) => {
+                if (!cancelled) setStats(result);
+            })
+            .catch((err: unknown) => {
+                if (import.meta.env.DEV) {
+                    console.warn("[RepoStatsSummary] GetRepoStats failed", err);
+                }
+            });
+        return () => { cancelled = true; };
+    }, [directory]);
+
+    if (!stats || stats.total_files === 0) {
+        return null;
+    }
+
+    const languages = stats.languages ?? [];
+    const listed = languages.slice(0, MAX_LISTED_LANGUAGES);
+    const hidden = languages.length - listed.length;
+    const formatPercent = (lines: number) =>
+        stats.total_lines > 0 ? `${Math.round((lines / stats.total_lines) * 100)}%` : "";
+
+    return (
+        <div className="repo-stats-summary">
+            <span className="repo-stats-totals">
+                {isEn
+                    ? `${stats.total_files.toLocaleString()} files · ${stats.total_lines.toLocaleString()} lines`
+                    : t("newSession.repoStats.totals", "{files} ファイル · {lines} 行", {
+                        files: stats.total_files.toLocaleString(),
+                        lines: stats.total_lines.toLocaleString(),
+                    })}
+                {stats.truncated && (isEn ? " (partial)" : t("newSession.repoStats.truncated", "（一部）"))}
+            </span>
+            <span className="repo-stats-languages">
+                {listed.map((entry) => (
+                    <span key={entry.language} className="repo-stats-language">
+                        {entry.language} {formatPercent(entry.lines)}
+                    </span>
+                ))}
+                {hidden > 0 && (
+                    <span className="repo-stats-language">
+                        {isEn ? `+${hidden} more` : t("newSession.repoStats.more", "他 {count} 件", {count: hidden})}
+                    </span>
+                )}
+            </span>
+        </div>
+    );
+}
@@ -91,7 +91,16 @@ export function WorktreeOptions({s, dispatch, onSelectWorktree}: WorktreeOptions
                                             </option>
                                         ))}
                                     </select>
-                                    {s.worktreeConflict && (
+                                    {s.worktreeConflict && s.allowSharedWorktree && (
+                                        <p className="form-warning">
+                                            {isEn
+                                                ? `This worktree is shared with session "${s.worktreeConflict}". Checking out another branch affects both sessions.`
+                                                : t("newSession.worktree.shared", "このworktreeはセッション「{sessionName}」と共有されます。別のブランチをチェックアウトすると両方のセッションに影響します", {
+                                                    sessionName: s.worktreeConflict,
+                                                })}
+                                        </p>
+                                    )}
+                                    {s.worktreeConflict && !s.allowSharedWorktree && (
                                         <p className="form-error">
                                             {isEn
                                                 ? `This worktree is already used by session "${s.worktreeConflict}".`
@@ -10,6 +10,8 @@ type NewWorktreeSessionOptionFields = Pick<
     | "useClaudeEnv"
     | "usePaneEnv"
     | "useSessionPaneScope"
+    | "isolateShellHistory"
+    | "ephemeral"
 >;
 
 export function buildCreateSessionWithWorktreeOptions(state: NewWorktreeSessionOptionFields) {
@@ -22,5 +24,7 @@ export function buildCreateSessionWithWorktreeOptions(state: NewWorktreeSessionO
         use_claude_env: state.useClaudeEnv,
         use_pane_env: state.usePaneEnv,
         use_session_pane_scope: state.useSessionPaneScope,
+        isolate_shell_history: state.isolateShellHistory,
+        ephemeral: state.ephemeral,
     };
 }
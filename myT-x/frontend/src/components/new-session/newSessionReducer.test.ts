@@ -219,6 +219,7 @@ describe("newSessionReducer", () => {
             expect(INITIAL_STATE.worktreeSource).toBe("new");
             expect(INITIAL_STATE.selectedWorktree).toBeNull();
             expect(INITIAL_STATE.worktreeConflict).toBe("");
+            expect(INITIAL_STATE.allowSharedWorktree).toBe(false);
             expect(INITIAL_STATE.directoryConflict).toBe("");
             expect(INITIAL_STATE.baseBranch).toBe("");
             expect(INITIAL_STATE.branchName).toBe("");
@@ -11,6 +11,7 @@ export const INITIAL_STATE: NewSessionState = {
     worktreeSource: "new",
     selectedWorktree: null,
     worktreeConflict: "",
+    allowSharedWorktree: false,
     directoryConflict: "",
     baseBranch: "",
     branchName: "",
@@ -20,6 +21,8 @@ export const INITIAL_STATE: NewSessionState = {
     useClaudeEnv: false,
     usePaneEnv: false,
     useSessionPaneScope: true,
+    isolateShellHistory: false,
+    ephemeral: false,
     shimAvailable: false,
     loading: false,
     gitCheckLoading: false,
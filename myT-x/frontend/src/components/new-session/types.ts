@@ -19,6 +19,9 @@ export interface NewSessionState {
     readonly worktreeSource: WorktreeSource;
     readonly selectedWorktree: git.WorktreeInfo | null;
     readonly worktreeConflict: string;
+    // allowSharedWorktree mirrors worktree.allow_shared: a worktreeConflict
+    // is then a warning rather than a blocker.
+    readonly allowSharedWorktree: boolean;
     readonly directoryConflict: string;
     readonly baseBranch: string;
     readonly branchName: string;
@@ -30,6 +33,10 @@ export interface NewSessionState {
     readonly useClaudeEnv: boolean;
     readonly usePaneEnv: boolean;
     readonly useSessionPaneScope: boolean;
+    readonly isolateShellHistory: boolean;
+    // ephemeral removes the session (and its new worktree) after the
+    // configured TTL or on app exit.
+    readonly ephemeral: boolean;
     readonly shimAvailable: boolean;
 
     // Loading / error
@@ -125,6 +125,107 @@ export function GeneralSettings({s, dispatch, validationRules}: GeneralSettingsP
                 {globalHotkeyError && <span className="settings-field-error">{globalHotkeyError}</span>}
             </div>
 
+            <div className="form-checkbox-row">
+                <input
+                    type="checkbox"
+                    id="focus-follows-activity"
+                    checked={s.focusFollowsActivity}
+                    onChange={(e) => dispatch({type: "SET_FIELD", field: "focusFollowsActivity", value: e.target.checked})}
+                />
+                <label htmlFor="focus-follows-activity">
+                    {t("settings.general.focusFollowsActivity.label", "アクティビティに追従", "Focus follows activity")}
+                </label>
+            </div>
+            <span className="settings-desc">
+                {t(
+                    "settings.general.focusFollowsActivity.description",
+                    "現在のセッションがアイドルのとき、入力待ちのセッション（ベル、または未読出力後にアイドル）へ自動で切り替えます。Prefix+a で手動ジャンプ",
+                    "When the current session is idle, switch to a session waiting for input (bell, or idle after unseen output). Prefix+a jumps manually.",
+                )}
+            </span>
+
+            <div className="form-checkbox-row">
+                <input
+                    type="checkbox"
+                    id="output-folding"
+                    checked={s.outputFolding}
+                    onChange={(e) => dispatch({type: "SET_FIELD", field: "outputFolding", value: e.target.checked})}
+                />
+                <label htmlFor="output-folding">
+                    {t("settings.general.outputFolding.label", "繰り返し出力を折りたたむ", "Fold repeated output")}
+                </label>
+            </div>
+            <span className="settings-desc">
+                {t(
+                    "settings.general.outputFolding.description",
+                    "スピナーやポーリングで繰り返される行を capture-pane の履歴で「[line xN]」にまとめます。新しいペインから有効です。画面表示とログは変更されません",
+                    "Collapses lines repeated by spinners or polling loops into \"[line xN]\" in capture-pane history. Applies to new panes; the terminal view and logs are unchanged.",
+                )}
+            </span>
+
+            <div className="form-checkbox-row">
+                <input
+                    type="checkbox"
+                    id="shell-history-isolation-default"
+                    checked={s.shellHistoryIsolationDefaultEnabled}
+                    onChange={(e) =>
+                        dispatch({type: "SET_FIELD", field: "shellHistoryIsolationDefaultEnabled", value: e.target.checked})
+                    }
+                />
+                <label htmlFor="shell-history-isolation-default">
+                    {t(
+                        "settings.general.shellHistoryIsolation.label",
+                        "新しいセッションのシェル履歴を既定で分離する",
+                        "Isolate shell history of new sessions by default",
+                    )}
+                </label>
+            </div>
+            <span className="settings-desc">
+                {t(
+                    "settings.general.shellHistoryIsolation.description",
+                    "HISTFILE と PSReadLine の履歴保存先をセッションの状態ディレクトリに向け、使い捨てのエージェントセッションでの試行がグローバルなシェル履歴に残らないようにします。セッション作成ダイアログの既定値です",
+                    "Points HISTFILE and the PSReadLine history path into the session's state directory so experiments in throwaway agent sessions stay out of your global shell history. Sets the default of the new session dialog.",
+                )}
+            </span>
+
+            <div className="form-checkbox-row">
+                <input
+                    type="checkbox"
+                    id="mouse-mode"
+                    checked={s.mouse}
+                    onChange={(e) => dispatch({type: "SET_FIELD", field: "mouse", value: e.target.checked})}
+                />
+                <label htmlFor="mouse-mode">
+                    {t("settings.general.mouse.label", "マウスイベントをペインのアプリに送る", "Send mouse events to pane applications")}
+                </label>
+            </div>
+            <span className="settings-desc">
+                {t(
+                    "settings.general.mouse.description",
+                    "tmux の mouse on と同様に、マウス操作を要求するアプリ (htop、vim、lazygit など) にクリック・ホイールを渡します。オフにするとドラッグは常にテキスト選択になります。ペインごとにツールバーで切り替えられます",
+                    "Like tmux's mouse on, passes clicks and wheel to applications that request them (htop, vim, lazygit). When off, dragging always selects text. Each pane can override this from its toolbar.",
+                )}
+            </span>
+
+            <div className="form-checkbox-row">
+                <input
+                    type="checkbox"
+                    id="paste-confirm"
+                    checked={s.pasteConfirm}
+                    onChange={(e) => dispatch({type: "SET_FIELD", field: "pasteConfirm", value: e.target.checked})}
+                />
+                <label htmlFor="paste-confirm">
+                    {t("settings.general.pasteConfirm.label", "シェルへの複数行貼り付けの前に確認する", "Confirm multi-line pastes into shells")}
+                </label>
+            </div>
+            <span className="settings-desc">
+                {t(
+                    "settings.general.pasteConfirm.description",
+                    "改行を含む貼り付けがそのままコマンドとして実行される場合に確認します。ブラケットペーストに対応したシェルや全画面アプリ (vim など) では確認しません。制御文字は常に取り除かれます",
+                    "Asks before a paste whose line breaks would run commands. Shells with bracketed paste and full-screen applications (vim) are not affected. Control characters are always stripped.",
+                )}
+            </span>
+
             <div className="form-group">
                 <label className="form-label" htmlFor={defaultSessionDirInputId}>
                     {t(
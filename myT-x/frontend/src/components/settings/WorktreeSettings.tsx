@@ -74,6 +74,23 @@ export function WorktreeSettings({s, dispatch}: WorktreeSettingsProps) {
                 )}
             </span>
 
+            <div className="form-checkbox-row" style={{marginTop: 8}}>
+                <input
+                    type="checkbox"
+                    id="wt-allow-shared"
+                    checked={s.wtAllowShared}
+                    onChange={(e) => dispatch({type: "SET_FIELD", field: "wtAllowShared", value: e.target.checked})}
+                />
+                <label htmlFor="wt-allow-shared">{t("settings.worktree.allowShared.label", "worktreeの共有を許可", "Allow shared worktrees")}</label>
+            </div>
+            <span className="settings-desc">
+                {t(
+                    "settings.worktree.allowShared.description",
+                    "複数のセッションで同じ既存worktreeを開けるようにします。各セッションは開いた時点のブランチを記録し、別のブランチがチェックアウトされると警告します。",
+                    "Let several sessions open the same existing worktree. Each session remembers the branch it was opened on and warns when another branch is checked out.",
+                )}
+            </span>
+
             <div className="form-group" style={{marginTop: 10}}>
                 <label className="form-label">{t("settings.worktree.setupScripts.label", "セットアップスクリプト", "Setup scripts")}</label>
                 <span className="settings-desc">
@@ -151,6 +168,32 @@ export function WorktreeSettings({s, dispatch}: WorktreeSettingsProps) {
                 )}
             </div>
 
+            <div className="form-group" style={{marginTop: 6}}>
+                <label className="form-label" htmlFor="wt-copy-files-eol-select">
+                    {t("settings.worktree.copyFilesEOL.label", "コピーファイルの改行コード", "Copied file line endings")}
+                </label>
+                <select
+                    id="wt-copy-files-eol-select"
+                    className="form-select"
+                    value={s.wtCopyFilesEOL}
+                    onChange={(e) => dispatch({type: "SET_FIELD", field: "wtCopyFilesEOL", value: e.target.value})}
+                >
+                    <option value="">{t("settings.worktree.copyFilesEOL.verbatim", "変換しない", "Keep as is")}</option>
+                    <option value="gitattributes">
+                        {t("settings.worktree.copyFilesEOL.gitattributes", ".gitattributes に従う", "Follow .gitattributes")}
+                    </option>
+                    <option value="lf">LF</option>
+                    <option value="crlf">CRLF</option>
+                </select>
+                <span className="settings-desc">
+                    {t(
+                        "settings.worktree.copyFilesEOL.description",
+                        "コピーファイルの改行コードを変換します。バイナリファイルはそのままコピーされます。",
+                        "Converts line endings of copied files. Binary files are copied as is.",
+                    )}
+                </span>
+            </div>
+
             <div className="form-group" style={{marginTop: 6}}>
                 <label className="form-label">{t("settings.worktree.copyDirs.label", "コピーディレクトリ", "Copy directories")}</label>
                 <span className="settings-desc">
@@ -171,6 +214,27 @@ export function WorktreeSettings({s, dispatch}: WorktreeSettingsProps) {
                     <span className="form-error">{s.validationErrors["wt_copy_dirs"]}</span>
                 )}
             </div>
+
+            <div className="form-group" style={{marginTop: 6}}>
+                <label className="form-label" htmlFor="wt-merge-tool-input">
+                    {t("settings.worktree.mergeTool.label", "マージツール", "Merge tool")}
+                </label>
+                <input
+                    id="wt-merge-tool-input"
+                    className="form-input"
+                    type="text"
+                    value={s.wtMergeTool}
+                    onChange={(e) => dispatch({type: "SET_FIELD", field: "wtMergeTool", value: e.target.value})}
+                    placeholder={t("settings.worktree.mergeTool.placeholderExample", "例: vscode", "e.g. vscode")}
+                />
+                <span className="settings-desc">
+                    {t(
+                        "settings.worktree.mergeTool.description",
+                        "コンフリクトしたファイルを開くツール名（git mergetool --tool）またはコマンド（$LOCAL $REMOTE $BASE $MERGED を使用可）。未設定時は git config の merge.tool を使用します。",
+                        "Tool name (git mergetool --tool) or command ($LOCAL $REMOTE $BASE $MERGED available) used to open conflicted files. Uses git config merge.tool when empty.",
+                    )}
+                </span>
+            </div>
         </div>
     );
 }
@@ -3,12 +3,18 @@ import {generateId} from "./types";
 import {DEFAULT_SETUP_SCRIPT_TIMEOUT_SECONDS, EFFORT_LEVEL_KEY, MIN_OVERRIDE_NAME_LEN_FALLBACK} from "./constants";
 import {normalizeViewerShortcutConfig} from "../viewer/viewerShortcutDefinitions";
 import {normalizeViewerSidebarMode} from "../../utils/viewerSidebarMode";
+import {buildSettingsSavePayload} from "./useSettingsSave";
 
 export const INITIAL_FORM: FormState = {
     shell: "powershell.exe",
     prefix: "Ctrl+b",
     quakeMode: true,
     globalHotkey: "Ctrl+Shift+F12",
+    focusFollowsActivity: false,
+    outputFolding: false,
+    shellHistoryIsolationDefaultEnabled: false,
+    mouse: true,
+    pasteConfirm: true,
     autoStart: [],
     viewerSidebarMode: "overlay",
     keys: {},
@@ -21,6 +27,9 @@ export const INITIAL_FORM: FormState = {
     wtSetupScriptTimeoutSeconds: DEFAULT_SETUP_SCRIPT_TIMEOUT_SECONDS,
     wtCopyFiles: [],
     wtCopyDirs: [],
+    wtCopyFilesEOL: "",
+    wtMergeTool: "",
+    wtAllowShared: false,
     mcpServers: [],
     mcpServersLoaded: false,
     agentFrom: "",
@@ -41,6 +50,7 @@ export const INITIAL_FORM: FormState = {
     error: "",
     validationErrors: {},
     activeCategory: "general",
+    loadedPayload: null,
 };
 
 export function formReducer(state: FormState, action: FormAction): FormState {
@@ -81,12 +91,17 @@ export function formReducer(state: FormState, action: FormAction): FormState {
                     })),
                 }
                 : undefined;
-            return {
+            const loaded: FormState = {
                 ...state,
                 shell: cfg.shell || "powershell.exe",
                 prefix: cfg.prefix || "Ctrl+b",
                 quakeMode: cfg.quake_mode ?? true,
                 globalHotkey: cfg.global_hotkey || "Ctrl+Shift+F12",
+                focusFollowsActivity: cfg.focus_follows_activity ?? false,
+                outputFolding: cfg.output_folding ?? false,
+                shellHistoryIsolationDefaultEnabled: cfg.shell_history_isolation_default_enabled ?? false,
+                mouse: cfg.mouse !== "off",
+                pasteConfirm: cfg.paste_confirm !== "off",
                 autoStart,
                 viewerSidebarMode: normalizeViewerSidebarMode(cfg.viewer_sidebar_mode),
                 keys: cfg.keys || {},
@@ -102,6 +117,9 @@ export function formReducer(state: FormState, action: FormAction): FormState {
                         : DEFAULT_SETUP_SCRIPT_TIMEOUT_SECONDS,
                 wtCopyFiles: wt?.copy_files || [],
                 wtCopyDirs: wt?.copy_dirs || [],
+                wtCopyFilesEOL: wt?.copy_files_eol || "",
+                wtMergeTool: wt?.merge_tool || "",
+                wtAllowShared: wt?.allow_shared ?? false,
                 mcpServers: (cfg.mcp_servers ?? []).map((server) => ({
                     id: server.id,
                     name: server.name,
@@ -139,6 +157,7 @@ export function formReducer(state: FormState, action: FormAction): FormState {
                 loadFailed: false,
                 error: "",
             };
+            return {...loaded, loadedPayload: buildSettingsSavePayload(loaded)};
         }
         case "UPDATE_KEY":
             return {...state, keys: {...state.keys, [action.key]: action.value}};
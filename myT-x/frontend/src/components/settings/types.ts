@@ -4,6 +4,7 @@ import type {
     AppConfigAutoStartCommand,
     AppConfigMCPServerConfig,
     AppConfigTaskScheduler,
+    WailsConfigInput,
 } from "../../types/tmux";
 import type {ViewerSidebarMode} from "../../utils/viewerSidebarMode";
 
@@ -29,6 +30,11 @@ export interface FormState {
     prefix: string;
     quakeMode: boolean;
     globalHotkey: string;
+    focusFollowsActivity: boolean;
+    outputFolding: boolean;
+    shellHistoryIsolationDefaultEnabled: boolean;
+    mouse: boolean;
+    pasteConfirm: boolean;
     autoStart: AutoStartEntry[];
     viewerSidebarMode: ViewerSidebarMode;
     keys: Record<string, string>;
@@ -41,6 +47,9 @@ export interface FormState {
     wtSetupScriptTimeoutSeconds: number;
     wtCopyFiles: string[];
     wtCopyDirs: string[];
+    wtCopyFilesEOL: string;
+    wtMergeTool: string;
+    wtAllowShared: boolean;
     mcpServers: AppConfigMCPServerConfig[];
     mcpServersLoaded: boolean;
     agentFrom: string;
@@ -61,6 +70,8 @@ export interface FormState {
     error: string;
     validationErrors: Record<string, string>;
     activeCategory: SettingsCategory;
+    /** Save payload of the config as loaded; saves send only the difference. */
+    loadedPayload: WailsConfigInput | null;
 }
 
 export type SetFieldAction = {
@@ -1,5 +1,7 @@
 import {describe, expect, it} from "vitest";
-import {INITIAL_FORM} from "./settingsReducer";
+import type {AppConfig} from "../../types/tmux";
+import {createMergePatch} from "../../utils/mergePatch";
+import {formReducer, INITIAL_FORM} from "./settingsReducer";
 import {buildSettingsSavePayload, selectSettingsValidationCategory} from "./useSettingsSave";
 
 describe("buildSettingsSavePayload", () => {
@@ -73,3 +75,27 @@ describe("selectSettingsValidationCategory", () => {
         })).toBe("auto-start");
     });
 });
+
+describe("loadedPayload", () => {
+    it("lets saves send only the fields edited after loading", () => {
+        const loaded = formReducer(INITIAL_FORM, {
+            type: "LOAD_CONFIG",
+            config: {
+                shell: "cmd.exe",
+                prefix: "Ctrl+a",
+                keys: {"split-horizontal": "%"},
+                quake_mode: false,
+                global_hotkey: "",
+                worktree: {enabled: true, force_cleanup: false},
+            } as AppConfig,
+            shells: ["cmd.exe"],
+        });
+        expect(loaded.loadedPayload).toEqual(buildSettingsSavePayload(loaded));
+
+        const edited = {...loaded, wtForceCleanup: true, keys: {}};
+        expect(createMergePatch(loaded.loadedPayload!, buildSettingsSavePayload(edited))).toEqual({
+            keys: {"split-horizontal": null},
+            worktree: {force_cleanup: true},
+        });
+    });
+});
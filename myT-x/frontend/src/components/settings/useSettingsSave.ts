@@ -2,6 +2,7 @@ import {useCallback} from "react";
 import {config} from "../../../wailsjs/go/models";
 import {api} from "../../api";
 import {useNotificationStore} from "../../stores/notificationStore";
+import {createMergePatch} from "../../utils/mergePatch";
 import {serializeViewerSidebarMode} from "../../utils/viewerSidebarMode";
 import {normalizeViewerShortcutConfig} from "../viewer/viewerShortcutDefinitions";
 import {normalizeShortcut} from "../viewer/viewerShortcutUtils";
@@ -78,6 +79,11 @@ export function buildSettingsSavePayload(s: FormState): WailsConfigInput {
         keys: s.keys,
         quake_mode: s.quakeMode,
         global_hotkey: s.globalHotkey,
+        focus_follows_activity: s.focusFollowsActivity || undefined,
+        output_folding: s.outputFolding || undefined,
+        shell_history_isolation_default_enabled: s.shellHistoryIsolationDefaultEnabled || undefined,
+        mouse: s.mouse ? undefined : "off",
+        paste_confirm: s.pasteConfirm ? undefined : "off",
         auto_start: s.autoStart
             .map((entry) => ({
                 name: entry.name.trim(),
@@ -93,6 +99,9 @@ export function buildSettingsSavePayload(s: FormState): WailsConfigInput {
             setup_script_timeout_seconds: s.wtSetupScriptTimeoutSeconds,
             copy_files: s.wtCopyFiles.filter((v) => v.trim()),
             copy_dirs: s.wtCopyDirs.filter((v) => v.trim()),
+            copy_files_eol: s.wtCopyFilesEOL || undefined,
+            merge_tool: s.wtMergeTool.trim() || undefined,
+            allow_shared: s.wtAllowShared || undefined,
         },
         // SaveConfig is full-overwrite, so explicit empty MCP collections must
         // be preserved after the config load establishes that the user really
@@ -218,15 +227,19 @@ export function useSettingsSave(
         }
         dispatch({type: "START_SAVE"});
 
-        // NOTE: SaveConfig performs full overwrite (not merge), so the payload
-        // must round-trip fields that are not editable in this modal.
-        // Go-side config.Save marshals the entire Config struct and writes it
-        // atomically to disk.
         const payload = buildSettingsSavePayload(s);
 
         try {
-            const cfg = config.Config.createFrom(payload);
-            await api.SaveConfig(cfg);
+            if (s.loadedPayload) {
+                // Send only the fields edited since load so that settings
+                // changed elsewhere in the meantime are not overwritten.
+                await api.ApplyConfigPatch(createMergePatch(s.loadedPayload, payload));
+            } else {
+                // NOTE: SaveConfig performs full overwrite (not merge), so the
+                // payload must round-trip fields that are not editable in this
+                // modal.
+                await api.SaveConfig(config.Config.createFrom(payload));
+            }
             const addNotification = useNotificationStore.getState().addNotification;
             addNotification(
                 t("settings.modal.notification.saved", "設定を保存しました", "Settings saved."),
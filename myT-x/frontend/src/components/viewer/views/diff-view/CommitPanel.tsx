@@ -10,6 +10,7 @@ interface CommitPanelProps {
     onPush: () => Promise<void>;
     onPull: () => Promise<void>;
     onFetch: () => Promise<void>;
+    onOpenMergeTool: (path: string) => Promise<void>;
     operationInFlight: OperationType;
     stagedCount: number;
 }
@@ -23,6 +24,7 @@ export function CommitPanel({
     onPush,
     onPull,
     onFetch,
+    onOpenMergeTool,
     operationInFlight,
     stagedCount,
 }: CommitPanelProps) {
@@ -95,7 +97,23 @@ export function CommitPanel({
             )}
             {hasConflicts && branchInfo && (
                 <div className="commit-conflict-warning" title="Resolve conflicts before committing">
-                    {branchInfo.conflicted.length} conflicted file(s) — resolve in terminal
+                    {branchInfo.conflicted.length} conflicted file(s)
+                    <ul className="commit-conflict-list">
+                        {branchInfo.conflicted.map((path) => (
+                            <li key={path} className="commit-conflict-item">
+                                <span className="commit-conflict-path" title={path}>{path}</span>
+                                <button
+                                    type="button"
+                                    className="commit-btn commit-btn--small"
+                                    disabled={isDisabled}
+                                    title="Open in merge tool (worktree.merge_tool or git merge.tool)"
+                                    onClick={() => void onOpenMergeTool(path)}
+                                >
+                                    Merge
+                                </button>
+                            </li>
+                        ))}
+                    </ul>
                 </div>
             )}
             <textarea
@@ -59,6 +59,7 @@ export function DiffView() {
         push,
         pull,
         fetch: fetch_,
+        openMergeTool,
         // Commit message
         commitMessage,
         setCommitMessage,
@@ -189,7 +190,7 @@ export function DiffView() {
                                     onPush={push}
                                     onPull={pull}
                                     onFetch={fetch_}
-                                    operationInFlight={operationInFlight}
+                                    onOpenMergeTool={openMergeTool}
                                     stagedCount={stagedCount}
                                 />
                             </div>
@@ -215,7 +216,7 @@ export function DiffView() {
                                 onPush={push}
                                 onPull={pull}
                                 onFetch={fetch_}
-                            />
+                                onOpenMergeTool={openMergeTool}
                         )}
                         <div className="diff-view-content">
                             <DiffContentViewer file={selectedFile}/>
@@ -29,6 +29,7 @@ interface StagingFlatViewProps {
     onPush: () => Promise<void>;
     onPull: () => Promise<void>;
     onFetch: () => Promise<void>;
+    onOpenMergeTool: (path: string) => Promise<void>;
 }
 
 const ROW_HEIGHT = 28;
@@ -55,6 +56,7 @@ export function StagingFlatView({
     onPush,
     onPull,
     onFetch,
+    onOpenMergeTool,
 }: StagingFlatViewProps) {
     const containerRef = useRef<HTMLDivElement>(null);
     const height = useContainerHeight(containerRef, ROW_HEIGHT, {noiseThresholdPx: 1});
@@ -127,6 +129,7 @@ export function StagingFlatView({
                 onPush={onPush}
                 onPull={onPull}
                 onFetch={onFetch}
+                onOpenMergeTool={onOpenMergeTool}
                 operationInFlight={operationInFlight}
                 stagedCount={stagedCount}
             />
@@ -35,6 +35,7 @@ export type OperationType =
     | "push"
     | "pull"
     | "fetch"
+    | "mergeTool"
     | null;
 
 /** Branch information from DevPanelGitStatus. */
@@ -40,6 +40,7 @@ export interface UseDiffViewResult {
     readonly push: () => Promise<void>;
     readonly pull: () => Promise<void>;
     readonly fetch: () => Promise<void>;
+    readonly openMergeTool: (path: string) => Promise<void>;
 
     // --- New: commit message ---
     readonly commitMessage: string;
@@ -94,6 +95,7 @@ export function useDiffView(): UseDiffViewResult {
         push: git.push,
         pull: git.pull,
         fetch: git.fetch,
+        openMergeTool: git.openMergeTool,
         // Commit message
         commitMessage: git.commitMessage,
         setCommitMessage: git.setCommitMessage,
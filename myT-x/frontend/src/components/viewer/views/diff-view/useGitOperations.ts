@@ -1,6 +1,7 @@
 import type {MutableRefObject, Dispatch, SetStateAction} from "react";
 import {useCallback, useEffect, useRef, useState} from "react";
 import {api} from "../../../../api";
+import {EventsOn} from "../../../../../wailsjs/runtime";
 import {toErrorMessage} from "../../../../utils/errorUtils";
 import {notifyAndLog} from "../../../../utils/notifyUtils";
 import type {BranchInfo, OperationType} from "./sourceControlTypes";
@@ -33,6 +34,8 @@ export interface UseGitOperationsResult {
     readonly push: () => Promise<void>;
     readonly pull: () => Promise<void>;
     readonly fetch: () => Promise<void>;
+    /** Opens a conflicted file in the configured merge tool. Status refreshes when the tool exits. */
+    readonly openMergeTool: (path: string) => Promise<void>;
     readonly commitMessage: string;
     readonly setCommitMessage: (msg: string) => void;
 }
@@ -272,6 +275,28 @@ export function useGitOperations({
         [withOperation],
     );
 
+    // OpenInMergeTool returns once the tool has started; the finished event
+    // below refreshes status again when the tool exits.
+    const openMergeTool = useCallback(
+        (path: string) => withOperation("mergeTool", (session) => api.OpenInMergeTool(session, path))(),
+        [withOperation],
+    );
+
+    useEffect(() => {
+        return EventsOn("devpanel:merge-tool-finished", (event: {
+            session_name?: string;
+            path?: string;
+            resolved?: boolean;
+            error?: string;
+        }) => {
+            if (!event || event.session_name !== sessionRef.current) return;
+            if (event.error) {
+                notifyAndLog(`Merge tool (${event.path ?? ""})`, "warn", new Error(event.error), "GitOperations");
+            }
+            loadDiff(undefined, true);
+        });
+    }, [loadDiff, sessionRef]);
+
     return {
         operationInFlight,
         stageFile,
@@ -284,6 +309,7 @@ export function useGitOperations({
         push,
         pull,
         fetch: fetch_,
+        openMergeTool,
         commitMessage,
         setCommitMessage,
     };
@@ -0,0 +1,91 @@
+import {useEffect} from "react";
+import {api} from "../../api";
+import {useCommandApprovalStore, type PendingApproval} from "../../stores/commandApprovalStore";
+import {asObject} from "../../utils/typeGuards";
+import {cleanupEventListeners, createEventSubscriber, notifyWarn, tr} from "./eventHelpers";
+
+// Payload types are compile-time documentation only.
+interface CommandApprovalEventMap {
+    "session:approval-pending": {
+        id?: string;
+        session_name?: string;
+        pane_id?: string;
+        command?: string;
+        command_line?: string;
+        expires_at?: string;
+    };
+    "session:approval-resolved": {id?: string; session_name?: string; decision?: string; reason?: string};
+}
+
+export function toPendingApproval(payload: unknown): PendingApproval | null {
+    const event = asObject<Record<string, unknown>>(payload);
+    if (!event || typeof event.id !== "string" || event.id.trim() === "") {
+        return null;
+    }
+    const text = (value: unknown) => (typeof value === "string" ? value : "");
+    return {
+        id: event.id,
+        sessionName: text(event.session_name),
+        paneId: text(event.pane_id),
+        command: text(event.command),
+        commandLine: text(event.command_line) || text(event.command),
+        expiresAt: text(event.expires_at),
+    };
+}
+
+/**
+ * Tracks shim commands held by session approval mode.
+ * Seeds from the backend on mount so commands held before a frontend reload
+ * are still shown, then follows pending/resolved events.
+ */
+export function useCommandApprovalSync(): void {
+    useEffect(() => {
+        let cancelled = false;
+        const cleanupFns: Array<() => void> = [];
+        const onEvent = createEventSubscriber<CommandApprovalEventMap>(cleanupFns);
+
+        onEvent("session:approval-pending", (payload) => {
+            const approval = toPendingApproval(payload);
+            if (!approval) {
+                if (import.meta.env.DEV) {
+                    console.warn("[SYNC] session:approval-pending: invalid payload", payload);
+                }
+                return;
+            }
+            useCommandApprovalStore.getState().addPending(approval);
+        });
+
+        onEvent("session:approval-resolved", (payload) => {
+            const event = asObject<{id?: unknown; reason?: unknown; session_name?: unknown}>(payload);
+            if (!event || typeof event.id !== "string") {
+                return;
+            }
+            useCommandApprovalStore.getState().removePending(event.id);
+            if (event.reason === "timeout") {
+                notifyWarn(tr(
+                    "approval.timedOut",
+                    "承認待ちのコマンドがタイムアウトし拒否されました ({sessionName})",
+                    "A command waiting for approval timed out and was denied ({sessionName})",
+                    {sessionName: typeof event.session_name === "string" ? event.session_name : ""},
+                ));
+            }
+        });
+
+        void api.GetPendingCommandApprovals().then((result) => {
+            if (cancelled) return;
+            const pending = (result ?? [])
+                .map(toPendingApproval)
+                .filter((item): item is PendingApproval => item !== null);
+            useCommandApprovalStore.getState().setPending(pending);
+        }).catch((err: unknown) => {
+            if (import.meta.env.DEV) {
+                console.warn("[SYNC] GetPendingCommandApprovals failed:", err);
+            }
+        });
+
+        return () => {
+            cancelled = true;
+            cleanupEventListeners(cleanupFns);
+        };
+    }, []);
+}
@@ -3,7 +3,7 @@ import {api} from "../../api";
 import {useTmuxStore} from "../../stores/tmuxStore";
 import type {ParsedConfigUpdatedEvent} from "../../types/tmux";
 import {logFrontendEventSafe} from "../../utils/logFrontendEventSafe";
-import {asObject} from "../../utils/typeGuards";
+import {asArray, asObject} from "../../utils/typeGuards";
 import {parseConfigUpdatedPayload} from "./configUpdatedEvent";
 import {cleanupEventListeners, createEventSubscriber, notifyWarn, tr} from "./eventHelpers";
 
@@ -11,6 +11,7 @@ import {cleanupEventListeners, createEventSubscriber, notifyWarn, tr} from "./ev
 interface ConfigEventMap {
     "config:load-failed": {message: string};
     "config:updated": ParsedConfigUpdatedEvent;
+    "config:applied": {version?: number; immediate?: string[]; next_use?: string[]; requires_restart?: string[]};
 }
 
 /**
@@ -125,6 +126,30 @@ export function useConfigSync(): void {
             setConfig(event.config);
         });
 
+        // Settings that only take effect after a restart are surfaced so the
+        // user is not left wondering why a saved change has no visible effect.
+        onEvent("config:applied", (payload) => {
+            const event = asObject<{requires_restart?: unknown}>(payload);
+            if (!event) {
+                if (import.meta.env.DEV) {
+                    console.warn("[SYNC] config:applied: invalid payload", payload);
+                }
+                return;
+            }
+            const restartKeys = (asArray<unknown>(event.requires_restart) ?? [])
+                .filter((key): key is string => typeof key === "string" && key !== "");
+            if (restartKeys.length === 0) {
+                return;
+            }
+            notifyWarn(
+                tr(
+                    "sync.notifications.configRequiresRestart",
+                    `次の設定はアプリの再起動後に反映されます: ${restartKeys.join(", ")}`,
+                    `These settings take effect after restarting the app: ${restartKeys.join(", ")}`,
+                ),
+            );
+        });
+
         return () => {
             isMountedRef.current = false;
             // Reset for StrictMode re-mount — ensures the initial API fetch
@@ -0,0 +1,115 @@
+import {useEffect} from "react";
+import {useNotificationStore, type Notification, type NotificationGroup} from "../../stores/notificationStore";
+import {asArray, asObject} from "../../utils/typeGuards";
+import {cleanupEventListeners, createEventSubscriber, notifyWarn, tr} from "./eventHelpers";
+
+// Payload types are compile-time documentation only.
+interface ErrorReportEventMap {
+    "app:errors": {
+        groups?: {
+            key?: string;
+            code?: string;
+            severity?: string;
+            source?: string;
+            message?: string;
+            detail?: string;
+            hint?: string;
+            count?: number;
+        }[];
+        suppressed?: number;
+    };
+}
+
+interface ErrorGroupToast {
+    message: string;
+    level: Notification["level"];
+    group: NotificationGroup;
+}
+
+/** Localized remediation hints by error code; unknown codes keep the backend hint. */
+function localizedHint(code: string, fallback: string): string {
+    switch (code) {
+        case "worker.panic":
+            return tr(
+                "errors.hint.workerPanic",
+                "ワーカーは自動的に再起動されました。繰り返し発生する場合は診断情報をエクスポートして報告してください。",
+                fallback,
+            );
+        case "worker.stopped":
+            return tr("errors.hint.workerStopped", "このワーカーは myT-x を再起動するまで停止したままです。", fallback);
+        case "session.cleanup_degraded":
+            return tr(
+                "errors.hint.sessionCleanup",
+                "閉じたセッションの状態の一部が残っています。myT-x を再起動すると解消されます。",
+                fallback,
+            );
+        default:
+            return fallback;
+    }
+}
+
+export function toErrorGroupToast(payload: unknown): ErrorGroupToast | null {
+    const event = asObject<Record<string, unknown>>(payload);
+    if (!event || typeof event.key !== "string" || event.key === "" ||
+        typeof event.message !== "string" || event.message.trim() === "") {
+        return null;
+    }
+    const text = (value: unknown) => (typeof value === "string" ? value : "");
+    const code = text(event.code) || "unknown";
+    return {
+        message: event.message.trim(),
+        level: event.severity === "warn" ? "warn" : "error",
+        group: {
+            key: event.key,
+            code,
+            source: text(event.source),
+            detail: text(event.detail),
+            hint: localizedHint(code, text(event.hint)),
+            count: typeof event.count === "number" && event.count > 0 ? event.count : 1,
+        },
+    };
+}
+
+/**
+ * Renders the deduplicated backend error batches of app:errors as grouped
+ * toasts. A repeated error updates the count of its existing toast.
+ */
+export function useErrorReportSync(): void {
+    useEffect(() => {
+        const cleanupFns: Array<() => void> = [];
+        const onEvent = createEventSubscriber<ErrorReportEventMap>(cleanupFns);
+
+        onEvent("app:errors", (payload) => {
+            const batch = asObject<{groups?: unknown; suppressed?: unknown}>(payload);
+            if (!batch) {
+                if (import.meta.env.DEV) {
+                    console.warn("[SYNC] app:errors: invalid payload", payload);
+                }
+                return;
+            }
+            const upsert = useNotificationStore.getState().upsertGroupNotification;
+            for (const item of asArray<unknown>(batch.groups) ?? []) {
+                const toast = toErrorGroupToast(item);
+                if (!toast) {
+                    if (import.meta.env.DEV) {
+                        console.warn("[SYNC] app:errors: invalid group", item);
+                    }
+                    continue;
+                }
+                upsert(toast.message, toast.level, toast.group);
+            }
+            if (typeof batch.suppressed === "number" && batch.suppressed > 0) {
+                notifyWarn(tr(
+                    "errors.suppressed",
+                    "エラーが多発しているため、他の {count} 件は表示されませんでした。",
+                    "{count} more errors were not shown because too many occurred.",
+                    {count: batch.suppressed},
+                ));
+            }
+        });
+
+        return () => {
+            cleanupEventListeners(cleanupFns);
+        };
+    }, []);
+}
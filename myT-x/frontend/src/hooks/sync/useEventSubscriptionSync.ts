@@ -0,0 +1,56 @@
+import {useEffect} from "react";
+import {api} from "../../api";
+import {useCanvasStore} from "../../stores/canvasStore";
+import {usePaneReplayStore} from "../../stores/paneReplayStore";
+import {useTmuxStore} from "../../stores/tmuxStore";
+import {
+    buildEventSubscriptionSpec,
+    newlySubscribedPanes,
+    sameEventSubscriptionSpec,
+    type EventSubscriptionSpec,
+} from "../../utils/eventSubscriptions";
+
+/**
+ * Declares to the backend which panes the session view renders, so output of
+ * hidden sessions and collapsed panes is not sent over the bridge. Panes that
+ * come back into view reload from GetPaneReplay to cover what was filtered.
+ */
+export function useEventSubscriptionSync(): void {
+    useEffect(() => {
+        let declared: EventSubscriptionSpec | null = null;
+        let disposed = false;
+
+        const sync = () => {
+            const {sessions, activeSession} = useTmuxStore.getState();
+            const canvasMode = useCanvasStore.getState().mode === "canvas";
+            const next = buildEventSubscriptionSpec(sessions, activeSession, canvasMode);
+            if (sameEventSubscriptionSpec(declared, next)) {
+                return;
+            }
+            const replay = newlySubscribedPanes(declared, next);
+            declared = next;
+            void api.SetEventSubscriptions(next)
+                .then(() => {
+                    if (!disposed && replay.length > 0) {
+                        usePaneReplayStore.getState().requestReplay(replay);
+                    }
+                })
+                .catch((err: unknown) => {
+                    if (import.meta.env.DEV) {
+                        console.warn("[SYNC] SetEventSubscriptions failed", err);
+                    }
+                });
+        };
+
+        sync();
+        const unsubscribeTmux = useTmuxStore.subscribe(sync);
+        const unsubscribeCanvas = useCanvasStore.subscribe(sync);
+        return () => {
+            disposed = true;
+            unsubscribeTmux();
+            unsubscribeCanvas();
+            // Let everything through again while nothing declares what it renders.
+            void api.SetEventSubscriptions({}).catch(() => undefined);
+        };
+    }, []);
+}
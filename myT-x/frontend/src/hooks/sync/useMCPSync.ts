@@ -21,6 +21,12 @@ interface MCPEventMap {
     "mcp:state-changed": {session_name?: string; mcp_id?: string};
     // MCP manager lifecycle event emitted by backend on shutdown/close.
     "mcp:manager-closed": null;
+    // Launch refused because the server's permissions manifest was violated.
+    "mcp:permission-violation": {
+        session_name?: string;
+        mcp_id?: string;
+        violations?: Array<{kind?: string; detail?: string}>;
+    };
 }
 
 /**
@@ -148,6 +154,25 @@ export function useMCPSync(): void {
             scheduleMCPRefresh(sessionName, mcpID === "" ? null : mcpID);
         });
 
+        onEvent("mcp:permission-violation", (payload) => {
+            if (!isMountedRef.current) return;
+            const event = asObject<{session_name?: unknown; mcp_id?: unknown; violations?: unknown}>(payload);
+            if (!event) {
+                if (import.meta.env.DEV) {
+                    console.warn("[SYNC] mcp:permission-violation: invalid payload", payload);
+                }
+                return;
+            }
+            const mcpID = typeof event.mcp_id === "string" ? event.mcp_id : "";
+            const violations = Array.isArray(event.violations) ? event.violations : [];
+            const details = violations
+                .map((violation) => asObject<{detail?: unknown}>(violation)?.detail)
+                .filter((detail): detail is string => typeof detail === "string");
+            const message = `MCP ${mcpID} blocked by its permissions: ${details.join("; ")}`;
+            notifyWarn(message);
+            logFrontendEventSafe("warn", message, "frontend/mcp");
+        });
+
         onEvent("mcp:manager-closed", () => {
             if (!isMountedRef.current) return;
             for (const timer of debounceTimers.values()) {
@@ -1,11 +1,17 @@
 import {useCallback, useEffect, useRef} from "react";
 import {api} from "../../api";
 import {connect as connectPaneStream, disconnect as disconnectPaneStream} from "../../services/paneDataStream";
+import {decodeSnapshotPayload, hydrateCompactUpserts, supportedSnapshotEncodings} from "../../services/snapshotPayload";
 import {useMCPStore} from "../../stores/mcpStore";
 import {useNotificationStore} from "../../stores/notificationStore";
 import {useCanvasStore} from "../../stores/canvasStore";
 import {useDiffReviewStore} from "../../stores/diffReviewStore";
+import {toGitStatus, useGitStatusStore, type GitStatus} from "../../stores/gitStatusStore";
+import {toPaneActivityState, usePaneActivityStore, type PaneActivityState} from "../../stores/paneActivityStore";
+import {usePaneReplayStore} from "../../stores/paneReplayStore";
 import {buildSessionMemoDraftKey, useSessionMemoStore} from "../../stores/sessionMemoStore";
+import {useSessionPortsStore} from "../../stores/sessionPortsStore";
+import {useWorktreeBaseStore} from "../../stores/worktreeBaseStore";
 import {useTmuxStore} from "../../stores/tmuxStore";
 import type {SessionSnapshot, SessionSnapshotDelta} from "../../types/tmux";
 import {logFrontendEventSafe} from "../../utils/logFrontendEventSafe";
@@ -18,6 +24,8 @@ import {buildDiffReviewSessionKey} from "../../components/viewer/views/diff-view
 // validate with asObject/asArray before accessing properties.
 interface SnapshotEventMap {
     "session:cleanup-degraded": {component?: string; session_name?: string; message?: string};
+    "session-info:recovered": {session_name?: string; quarantined_path?: string; dropped?: unknown[]};
+    // Either payload may arrive as a {encoding: "gzip-json", data} envelope.
     "tmux:snapshot": SessionSnapshot[];
     "tmux:snapshot-delta": Partial<SessionSnapshotDelta>;
     "tmux:active-session": {name?: string};
@@ -27,12 +35,33 @@ interface SnapshotEventMap {
     "tmux:session-renamed": {oldName?: string; newName?: string};
     "tmux:shim-installed": {installed_path?: string};
     "worktree:setup-complete": {sessionName?: string; success?: boolean; error?: string};
+    "worktree:setup-watch-ran": {sessionName?: string; dir?: string; script?: string; success?: boolean; error?: string};
     "worktree:cleanup-failed": {sessionName?: string; path?: string; error?: string};
+    "session:ephemeral-expired": {sessionName?: string};
+    "session:copy-progress": {id?: string; src_session?: string; dst_session?: string; destination?: string; copied_files?: number; total_files?: number; done?: boolean; error?: string};
     "worktree:copy-files-failed": {sessionName?: string; files?: string[]};
     "worktree:copy-dirs-failed": {sessionName?: string; dirs?: string[]};
     "worktree:pull-failed": {sessionName?: string; message?: string; error?: string};
+    "worktree:branch-conflict": {worktree_path?: string; session_name?: string; expected_branch?: string; actual_branch?: string; shared_with?: string[]};
+    "worktree:external-detected": {repo_path?: string; path?: string; branch_name?: string; is_detached?: boolean};
+    "worktree:base-advanced": {session_name?: string; base_branch?: string; base_ref?: string; base_commit?: string; behind?: number};
+    "worktree:base-refreshed": {session_name?: string; base_ref?: string; strategy?: string; trigger?: string; behind?: number; refreshed?: boolean; conflicts?: string[]};
+    "pane:command-finished": {paneId?: string; sessionName?: string; exitCode?: number; notify?: boolean};
+    "pane:notification": {paneId?: string; sessionName?: string; title?: string; body?: string};
+    "output-watch:matched": {watch_id?: string; pane_id?: string; session_name?: string; pattern?: string; action?: string; line?: string};
+    "session-bringup:status": {operation?: string; running?: boolean; templates?: {name?: string; state?: string; error?: string}[]};
+    "session-ports:opened": {session_name?: string; pane_id?: string; port?: number; process?: string; url?: string};
+    "session-ports:closed": {session_name?: string; port?: number};
+    "git:status-changed": {session_name?: string; status?: Record<string, unknown> | null};
+    "pane:activity-changed": {pane_id?: string; session_name?: string; state?: string; previous_state?: string};
+    "pane:output-spilled": string[];
+    "app:deep-link-failed": {link?: string; message?: string};
+    "repo-config:trust-required": {path?: string};
+    "repo-config:signature-rejected": {path?: string; config_error?: string};
     "tmux:worker-panic": {worker?: string};
     "tmux:worker-fatal": {worker?: string; maxRetries?: number};
+    "rendering:fallback-armed": {reason?: string; driver_resets?: {time?: string; provider?: string; event_id?: number}[]};
+    "system:resumed": {reason?: string; away_sec?: number; panes_checked?: number; dead_panes?: {session_name?: string; pane_id?: string; closed?: boolean}[]};
 }
 
 /**
@@ -40,6 +69,7 @@ interface SnapshotEventMap {
  * stream (WebSocket), and handles worktree and worker notification events.
  *
  * Initial data: ListSessions + GetActiveSession (Promise.allSettled).
+ * Snapshot wire encodings are negotiated via NegotiateSnapshotEncoding.
  */
 export function useSnapshotSync(): void {
     const setSessions = useTmuxStore((s) => s.setSessions);
@@ -139,16 +169,63 @@ export function useSnapshotSync(): void {
             }
         });
 
-        // --- Snapshot events ---
+        // Pane activity is tracked in the background; seed the current states
+        // and follow pane:activity-changed from here on.
+        void api.GetPaneActivity().then((result) => {
+            if (!isMountedRef.current) return;
+            const states: Record<string, PaneActivityState> = {};
+            for (const activity of result ?? []) {
+                const state = toPaneActivityState(activity.state);
+                if (state && state !== "idle") {
+                    states[activity.pane_id] = state;
+                }
+            }
+            usePaneActivityStore.getState().setAll(states);
+        }).catch((err: unknown) => {
+            if (import.meta.env.DEV) {
+                console.warn("[SYNC] GetPaneActivity failed:", err);
+            }
+        });
 
-        onEvent("tmux:snapshot", (payload) => {
-            const snapshots = asArray<ReturnType<typeof useTmuxStore.getState>["sessions"][number]>(payload);
-            if (!snapshots) {
-                if (import.meta.env.DEV) {
-                    console.warn("[SYNC] tmux:snapshot: payload is not an array", payload);
+        // Git status is polled in the background; seed what the watcher has
+        // so far and follow git:status-changed from here on.
+        void api.GetGitStatuses().then((result) => {
+            if (!isMountedRef.current) return;
+            const statuses: Record<string, GitStatus> = {};
+            for (const [sessionName, raw] of Object.entries(result ?? {})) {
+                const status = toGitStatus(raw);
+                if (status) {
+                    statuses[sessionName] = status;
                 }
-                return;
             }
+            useGitStatusStore.getState().setAll(statuses);
+        }).catch((err: unknown) => {
+            if (import.meta.env.DEV) {
+                console.warn("[SYNC] GetGitStatuses failed:", err);
+            }
+        });
+
+        // --- Snapshot events ---
+
+        // Compressed payloads decode asynchronously, so every snapshot event is
+        // applied through one promise chain to keep emission order.
+        let snapshotQueue: Promise<void> = Promise.resolve();
+        const enqueueSnapshotEvent = (eventName: string, payload: unknown, apply: (decoded: unknown) => void | Promise<void>) => {
+            snapshotQueue = snapshotQueue
+                .then(() => decodeSnapshotPayload(payload))
+                .then((decoded) => {
+                    if (!isMountedRef.current) return;
+                    return apply(decoded);
+                })
+                .catch((err: unknown) => {
+                    if (import.meta.env.DEV) {
+                        console.warn(`[SYNC] ${eventName}: failed to apply payload`, err);
+                    }
+                    logFrontendEventSafe("warn", `${eventName} payload could not be applied`, "frontend/sync");
+                });
+        };
+
+        const applySnapshots = (snapshots: SessionSnapshot[]) => {
             sessionKeyByNameRef.current = new Map(
                 snapshots.map((session) => [session.name, buildDiffReviewSessionKey(session.id)]),
             );
@@ -156,41 +233,72 @@ export function useSnapshotSync(): void {
                 snapshots.map((session) => [session.name, buildSessionMemoDraftKey(session.name, session.id)]),
             );
             setSessions(snapshots);
+        };
+
+        void api.NegotiateSnapshotEncoding(supportedSnapshotEncodings()).catch((err: unknown) => {
+            // Payloads stay plain JSON when negotiation fails.
+            if (import.meta.env.DEV) {
+                console.warn("[SYNC] NegotiateSnapshotEncoding failed", err);
+            }
+        });
+
+        onEvent("tmux:snapshot", (payload) => {
+            enqueueSnapshotEvent("tmux:snapshot", payload, (decoded) => {
+                const snapshots = asArray<SessionSnapshot>(decoded);
+                if (!snapshots) {
+                    if (import.meta.env.DEV) {
+                        console.warn("[SYNC] tmux:snapshot: payload is not an array", decoded);
+                    }
+                    return;
+                }
+                applySnapshots(snapshots);
+            });
         });
 
         onEvent("tmux:snapshot-delta", (payload) => {
-            const delta = asObject<Partial<SessionSnapshotDelta>>(payload);
-            if (!delta) {
-                if (import.meta.env.DEV) {
-                    console.warn("[SYNC] tmux:snapshot-delta: payload is null/invalid", payload);
+            enqueueSnapshotEvent("tmux:snapshot-delta", payload, async (decoded) => {
+                const delta = asObject<Partial<SessionSnapshotDelta>>(decoded);
+                if (!delta) {
+                    if (import.meta.env.DEV) {
+                        console.warn("[SYNC] tmux:snapshot-delta: payload is null/invalid", decoded);
+                    }
+                    return;
                 }
-                return;
-            }
-            // I-04: Default to empty arrays so that "upsert-only" or "remove-only"
-            // deltas are not silently discarded. Only skip when both are empty.
-            const upserts = asArray<SessionSnapshotDelta["upserts"][number]>(delta.upserts) ?? [];
-            const removed = (asArray<unknown>(delta.removed) ?? []).filter((name): name is string => typeof name === "string");
-            if (upserts.length === 0 && removed.length === 0) {
-                return;
-            }
-            for (const session of upserts) {
-                getSessionKeyByNameMap().set(session.name, buildDiffReviewSessionKey(session.id));
-                getSessionMemoKeyByNameMap().set(session.name, buildSessionMemoDraftKey(session.name, session.id));
-            }
-            applySessionDelta(upserts, removed);
-            // 削除されたセッションのキャンバスデータをクリーンアップ
-            // NOTE: mcpStore/canvasStore は低頻度クリーンアップのため getState() で直接呼び出す。
-            // tmuxStore アクションは高頻度かつ主データフローのため deps 経由で参照する。
-            for (const name of removed) {
-                if (name !== "") {
-                    const removedMemoKey = getSessionMemoKeyByNameMap().get(name);
-                    if (removedMemoKey != null) {
-                        useSessionMemoStore.getState().removeDraft(removedMemoKey);
-                        getSessionMemoKeyByNameMap().delete(name);
+                // I-04: Default to empty arrays so that "upsert-only" or "remove-only"
+                // deltas are not silently discarded. Only skip when both are empty.
+                const compactUpserts = asArray<SessionSnapshotDelta["upserts"][number]>(delta.upserts) ?? [];
+                const removed = (asArray<unknown>(delta.removed) ?? []).filter((name): name is string => typeof name === "string");
+                if (compactUpserts.length === 0 && removed.length === 0) {
+                    return;
+                }
+                const upserts = hydrateCompactUpserts(compactUpserts, useTmuxStore.getState().sessions);
+                if (!upserts) {
+                    // A referenced pane or layout is unknown locally: fetch the full list on demand.
+                    const snapshots = await api.ListSessions();
+                    if (isMountedRef.current) {
+                        applySnapshots(snapshots ?? []);
                     }
-                    useCanvasStore.getState().clearSessionData(name);
+                    return;
                 }
-            }
+                for (const session of upserts) {
+                    getSessionKeyByNameMap().set(session.name, buildDiffReviewSessionKey(session.id));
+                    getSessionMemoKeyByNameMap().set(session.name, buildSessionMemoDraftKey(session.name, session.id));
+                }
+                applySessionDelta(upserts, removed);
+                // 削除されたセッションのキャンバスデータをクリーンアップ
+                // NOTE: mcpStore/canvasStore は低頻度クリーンアップのため getState() で直接呼び出す。
+                // tmuxStore アクションは高頻度かつ主データフローのため deps 経由で参照する。
+                for (const name of removed) {
+                    if (name !== "") {
+                        const removedMemoKey = getSessionMemoKeyByNameMap().get(name);
+                        if (removedMemoKey != null) {
+                            useSessionMemoStore.getState().removeDraft(removedMemoKey);
+                            getSessionMemoKeyByNameMap().delete(name);
+                        }
+                        useCanvasStore.getState().clearSessionData(name);
+                    }
+                }
+            });
         });
 
         onEvent("tmux:active-session", (payload) => {
@@ -329,7 +437,12 @@ export function useSnapshotSync(): void {
                 return;
             }
 
-            notifyWarn(`Session cleanup was only partially completed for ${sessionName} (${component}): ${message}`);
+            // The toast is rendered from the grouped app:errors batch.
+            logFrontendEventSafe(
+                "warn",
+                `Session cleanup was only partially completed for ${sessionName} (${component}): ${message}`,
+                "frontend/session",
+            );
         });
 
         onEvent("tmux:shim-installed", (payload) => {
@@ -365,6 +478,94 @@ export function useSnapshotSync(): void {
             }
         });
 
+        onEvent("worktree:setup-watch-ran", (payload) => {
+            const event = asObject<{sessionName?: unknown; script?: unknown; success?: unknown; error?: unknown}>(payload);
+            if (!event || typeof event.sessionName !== "string" || typeof event.script !== "string") {
+                if (import.meta.env.DEV) {
+                    console.warn("[worktree] setup-watch-ran: invalid payload", payload);
+                }
+                return;
+            }
+            const error = typeof event.error === "string" ? event.error : "";
+
+            // [DEBUG:worktree] setup-watch-ran event
+            if (import.meta.env.DEV) {
+                console.log("[worktree] setup-watch-ran:", event.sessionName, event.script, event.success, error);
+            }
+            if (event.success === false) {
+                notifyWarn(
+                    tr(
+                        "sync.notifications.setupWatchFailed",
+                        `依存ファイルの変更後のセットアップ再実行に失敗しました (${event.sessionName}): ${error || "不明なエラー"}`,
+                        `Setup re-run after a dependency change failed (${event.sessionName}): ${error || "Unknown error"}`,
+                    ),
+                );
+                return;
+            }
+            useNotificationStore.getState().addNotification(
+                tr(
+                    "sync.notifications.setupWatchRan",
+                    `依存ファイルの変更を検知し再実行しました (${event.sessionName}): ${event.script}`,
+                    `Re-ran after a dependency change (${event.sessionName}): ${event.script}`,
+                ),
+                "info",
+            );
+        });
+
+        onEvent("session:ephemeral-expired", (payload) => {
+            const event = asObject<{sessionName?: unknown}>(payload);
+            if (!event || typeof event.sessionName !== "string") {
+                if (import.meta.env.DEV) {
+                    console.warn("[session] ephemeral-expired: invalid payload", payload);
+                }
+                return;
+            }
+            useNotificationStore.getState().addNotification(
+                tr(
+                    "sync.notifications.ephemeralExpired",
+                    `プレビューセッションの期限が切れたため削除しました: ${event.sessionName}`,
+                    `Removed the expired preview session: ${event.sessionName}`,
+                ),
+                "info",
+            );
+        });
+
+        onEvent("session:copy-progress", (payload) => {
+            const event = asObject<{src_session?: unknown; dst_session?: unknown; destination?: unknown; copied_files?: unknown; done?: unknown; error?: unknown}>(payload);
+            if (!event || typeof event.dst_session !== "string") {
+                if (import.meta.env.DEV) {
+                    console.warn("[session] copy-progress: invalid payload", payload);
+                }
+                return;
+            }
+            // Only the final event is surfaced; running progress stays in the payload stream.
+            if (event.done !== true) {
+                return;
+            }
+            const srcSession = typeof event.src_session === "string" ? event.src_session : "";
+            const error = typeof event.error === "string" ? event.error : "";
+            if (error !== "") {
+                notifyWarn(
+                    tr(
+                        "sync.notifications.sessionCopyFailed",
+                        `セッション間コピーに失敗しました (${srcSession} → ${event.dst_session}): ${error}`,
+                        `Copy between sessions failed (${srcSession} → ${event.dst_session}): ${error}`,
+                    ),
+                );
+                return;
+            }
+            const destination = typeof event.destination === "string" ? event.destination : "";
+            const files = typeof event.copied_files === "number" ? event.copied_files : 0;
+            useNotificationStore.getState().addNotification(
+                tr(
+                    "sync.notifications.sessionCopied",
+                    `${files} 件のファイルを ${event.dst_session} にコピーしました: ${destination}`,
+                    `Copied ${files} file(s) to ${event.dst_session}: ${destination}`,
+                ),
+                "info",
+            );
+        });
+
         onEvent("worktree:cleanup-failed", (payload) => {
             const event = asObject<{sessionName?: unknown; path?: unknown; error?: unknown}>(payload);
             if (!event) {
@@ -462,7 +663,413 @@ export function useSnapshotSync(): void {
             );
         });
 
+        onEvent("worktree:branch-conflict", (payload) => {
+            const event = asObject<{session_name?: unknown; expected_branch?: unknown; actual_branch?: unknown; shared_with?: unknown}>(payload);
+            if (!event) {
+                if (import.meta.env.DEV) {
+                    console.warn("[worktree] branch-conflict: invalid payload", payload);
+                }
+                return;
+            }
+            const sessionName = typeof event.session_name === "string" ? event.session_name : "";
+            const expected = typeof event.expected_branch === "string" ? event.expected_branch : "";
+            const actual = typeof event.actual_branch === "string" ? event.actual_branch : "";
+            const sharedWith = (asArray<string>(event.shared_with) ?? []).join(", ");
+
+            notifyWarn(
+                tr(
+                    "sync.notifications.worktreeBranchConflict",
+                    `共有worktreeのブランチが ${actual} に切り替わりました。セッション ${sessionName} は ${expected} で作業しています (共有: ${sharedWith})`,
+                    `The shared worktree switched to ${actual}, but session ${sessionName} works on ${expected} (shared with ${sharedWith}).`,
+                ),
+            );
+        });
+
+        onEvent("worktree:external-detected", (payload) => {
+            const event = asObject<{path?: unknown; branch_name?: unknown}>(payload);
+            if (!event || typeof event.path !== "string") {
+                if (import.meta.env.DEV) {
+                    console.warn("[worktree] external-detected: invalid payload", payload);
+                }
+                return;
+            }
+            const branch = typeof event.branch_name === "string" && event.branch_name !== "" ? ` (${event.branch_name})` : "";
+
+            notifyWarn(
+                tr(
+                    "sync.notifications.worktreeExternalDetected",
+                    `myT-x の外で作成された worktree を検出しました: ${event.path}${branch}。既存 worktree としてセッションを作成できます。`,
+                    `Detected a worktree created outside myT-x: ${event.path}${branch}. You can open it as a session from an existing worktree.`,
+                ),
+            );
+        });
+
+        onEvent("worktree:base-advanced", (payload) => {
+            const event = asObject<{session_name?: unknown; base_ref?: unknown; behind?: unknown}>(payload);
+            if (!event || typeof event.session_name !== "string" || typeof event.base_ref !== "string") {
+                if (import.meta.env.DEV) {
+                    console.warn("[worktree] base-advanced: invalid payload", payload);
+                }
+                return;
+            }
+            const behind = typeof event.behind === "number" ? event.behind : 0;
+            useWorktreeBaseStore.getState().setAdvanced({
+                sessionName: event.session_name,
+                baseRef: event.base_ref,
+                behind,
+            });
+            useNotificationStore.getState().addNotification(
+                tr(
+                    "sync.notifications.worktreeBaseAdvanced",
+                    "{session} のベースブランチ {base} が {count} コミット進みました。サイドバーから取り込めます。",
+                    "The base branch {base} of {session} is {count} commit(s) ahead. You can bring them in from the sidebar.",
+                    {session: event.session_name, base: event.base_ref, count: behind},
+                ),
+                "info",
+            );
+        });
+
+        onEvent("worktree:base-refreshed", (payload) => {
+            const event = asObject<{session_name?: unknown; base_ref?: unknown; strategy?: unknown; refreshed?: unknown; conflicts?: unknown}>(payload);
+            if (!event || typeof event.session_name !== "string") {
+                if (import.meta.env.DEV) {
+                    console.warn("[worktree] base-refreshed: invalid payload", payload);
+                }
+                return;
+            }
+            const base = typeof event.base_ref === "string" ? event.base_ref : "";
+            const strategy = typeof event.strategy === "string" ? event.strategy : "";
+            const conflicts = asArray<string>(event.conflicts) ?? [];
+            if (conflicts.length > 0) {
+                notifyWarn(
+                    tr(
+                        "sync.notifications.worktreeBaseConflicts",
+                        "{session} の {base} への {strategy} は競合したため中止しました: {files}",
+                        "The {strategy} of {session} onto {base} conflicted and was aborted: {files}",
+                        {session: event.session_name, base, strategy, files: conflicts.join(", ")},
+                    ),
+                );
+                return;
+            }
+            if (event.refreshed !== true) {
+                return;
+            }
+            useWorktreeBaseStore.getState().clearAdvanced(event.session_name);
+            useNotificationStore.getState().addNotification(
+                tr(
+                    "sync.notifications.worktreeBaseRefreshed",
+                    "{session} に {base} を取り込みました ({strategy})。",
+                    "Brought {base} into {session} ({strategy}).",
+                    {session: event.session_name, base, strategy},
+                ),
+                "info",
+            );
+        });
+
+        onEvent("pane:output-spilled", (payload) => {
+            const paneIds = asArray<unknown>(payload)?.filter((id): id is string => typeof id === "string") ?? [];
+            if (paneIds.length === 0) {
+                if (import.meta.env.DEV) {
+                    console.warn("[pane] output-spilled: invalid payload", payload);
+                }
+                return;
+            }
+            usePaneReplayStore.getState().requestReplay(paneIds);
+        });
+
+        onEvent("rendering:fallback-armed", (payload) => {
+            const event = asObject<{driver_resets?: unknown}>(payload);
+            if (!event) {
+                if (import.meta.env.DEV) {
+                    console.warn("[rendering] fallback-armed: invalid payload", payload);
+                }
+                return;
+            }
+            const resetCount = (asArray<unknown>(event.driver_resets) ?? []).length;
+
+            notifyWarn(
+                tr(
+                    "sync.notifications.renderingFallbackArmed",
+                    `GPUドライバのリセットを検出しました (${resetCount}件)。次回起動からソフトウェアレンダリングを使用します`,
+                    `Detected ${resetCount} GPU driver reset(s). myT-x will use software rendering from the next launch.`,
+                ),
+            );
+        });
+
+        onEvent("session-info:recovered", (payload) => {
+            const event = asObject<{session_name?: unknown; quarantined_path?: unknown; dropped?: unknown}>(payload);
+            if (!event) {
+                if (import.meta.env.DEV) {
+                    console.warn("[session-info] recovered: invalid payload", payload);
+                }
+                return;
+            }
+            const sessionName = typeof event.session_name === "string" ? event.session_name : "";
+            const quarantinedPath = typeof event.quarantined_path === "string" ? event.quarantined_path : "";
+            const droppedCount = (asArray<unknown>(event.dropped) ?? []).length;
+
+            if (import.meta.env.DEV) {
+                console.warn("[session-info] recovered:", sessionName, quarantinedPath, event.dropped);
+            }
+            notifyWarn(
+                tr(
+                    "sync.notifications.sessionInfoRecovered",
+                    `破損した保存データを ${droppedCount} 件破棄しました (${sessionName})。元のファイル: ${quarantinedPath}`,
+                    `Dropped ${droppedCount} corrupt saved record(s) (${sessionName}). Original file kept at: ${quarantinedPath}`,
+                ),
+            );
+        });
+
+        // --- Command trigger events ---
+
+        onEvent("pane:command-finished", (payload) => {
+            const event = asObject<{paneId?: unknown; sessionName?: unknown; exitCode?: unknown; notify?: unknown}>(payload);
+            if (!event || typeof event.paneId !== "string" || typeof event.exitCode !== "number") {
+                if (import.meta.env.DEV) {
+                    console.warn("[trigger] command-finished: invalid payload", payload);
+                }
+                return;
+            }
+            if (event.notify !== true) {
+                return;
+            }
+            const sessionName = typeof event.sessionName === "string" ? event.sessionName : "";
+            const params = {pane: event.paneId, session: sessionName, code: event.exitCode};
+            if (event.exitCode === 0) {
+                useNotificationStore.getState().addNotification(
+                    tr(
+                        "sync.notifications.commandSucceeded",
+                        "コマンドが成功しました ({session} {pane})。",
+                        "Command succeeded ({session} {pane}).",
+                        params,
+                    ),
+                    "info",
+                );
+                return;
+            }
+            notifyWarn(
+                tr(
+                    "sync.notifications.commandFailed",
+                    "コマンドが終了コード {code} で失敗しました ({session} {pane})。",
+                    "Command failed with exit code {code} ({session} {pane}).",
+                    params,
+                ),
+            );
+        });
+
+        // --- Pane notification events (OSC 9 / OSC 777) ---
+
+        onEvent("pane:notification", (payload) => {
+            const event = asObject<{paneId?: unknown; sessionName?: unknown; title?: unknown; body?: unknown}>(payload);
+            if (!event || typeof event.paneId !== "string") {
+                if (import.meta.env.DEV) {
+                    console.warn("[pane-notification] invalid payload", payload);
+                }
+                return;
+            }
+            const title = typeof event.title === "string" ? event.title : "";
+            const body = typeof event.body === "string" ? event.body : "";
+            const params = {
+                pane: event.paneId,
+                session: typeof event.sessionName === "string" ? event.sessionName : "",
+                title,
+                body,
+            };
+            useNotificationStore.getState().addNotification(
+                title === ""
+                    ? tr("sync.notifications.paneNotification", "{session} {pane}: {body}", "{session} {pane}: {body}", params)
+                    : tr(
+                        "sync.notifications.paneNotificationWithTitle",
+                        "{session} {pane}: {title} — {body}",
+                        "{session} {pane}: {title} — {body}",
+                        params,
+                    ),
+                "info",
+            );
+        });
+
+        // --- Output watch events ---
+
+        onEvent("output-watch:matched", (payload) => {
+            const event = asObject<{pane_id?: unknown; session_name?: unknown; action?: unknown; line?: unknown}>(payload);
+            if (!event || typeof event.pane_id !== "string") {
+                if (import.meta.env.DEV) {
+                    console.warn("[output-watch] matched: invalid payload", payload);
+                }
+                return;
+            }
+            // Mark and hook actions surface through session attention and the hook itself.
+            if (event.action !== "notify") {
+                return;
+            }
+            useNotificationStore.getState().addNotification(
+                tr(
+                    "sync.notifications.outputWatchMatched",
+                    "出力ウォッチに一致しました ({session} {pane}): {line}",
+                    "Output watch matched ({session} {pane}): {line}",
+                    {
+                        pane: event.pane_id,
+                        session: typeof event.session_name === "string" ? event.session_name : "",
+                        line: typeof event.line === "string" ? event.line : "",
+                    },
+                ),
+                "info",
+            );
+        });
+
+        // --- Session bring-up events ---
+
+        onEvent("session-bringup:status", (payload) => {
+            const event = asObject<{operation?: unknown; running?: unknown; templates?: unknown}>(payload);
+            if (!event || event.running !== false) {
+                return;
+            }
+            const failed: string[] = [];
+            for (const item of asArray<unknown>(event.templates) ?? []) {
+                const template = asObject<{name?: unknown; state?: unknown}>(item);
+                if (template && template.state === "failed" && typeof template.name === "string") {
+                    failed.push(template.name);
+                }
+            }
+            if (failed.length === 0) {
+                return;
+            }
+            const names = failed.join(", ");
+            if (event.operation === "down") {
+                notifyWarn(
+                    tr(
+                        "sync.notifications.sessionTearDownFailed",
+                        "セッションを停止できませんでした: {names}",
+                        "Failed to tear down sessions: {names}",
+                        {names},
+                    ),
+                );
+                return;
+            }
+            notifyWarn(
+                tr(
+                    "sync.notifications.sessionBringUpFailed",
+                    "セッションの起動に失敗しました: {names}",
+                    "Session bring-up failed: {names}",
+                    {names},
+                ),
+            );
+        });
+
+        // --- Session port events ---
+
+        onEvent("session-ports:opened", (payload) => {
+            const event = asObject<{session_name?: unknown; pane_id?: unknown; port?: unknown; process?: unknown; url?: unknown}>(payload);
+            if (!event || typeof event.session_name !== "string" || typeof event.port !== "number" || typeof event.url !== "string") {
+                if (import.meta.env.DEV) {
+                    console.warn("[ports] opened: invalid payload", payload);
+                }
+                return;
+            }
+            useSessionPortsStore.getState().addPort({
+                sessionName: event.session_name,
+                paneId: typeof event.pane_id === "string" ? event.pane_id : "",
+                port: event.port,
+                process: typeof event.process === "string" ? event.process : "",
+                url: event.url,
+            });
+            useNotificationStore.getState().addNotification(
+                tr(
+                    "sync.notifications.sessionPortOpened",
+                    "{session} で {url} が待ち受けを開始しました。",
+                    "{session} is listening on {url}.",
+                    {session: event.session_name, url: event.url},
+                ),
+                "info",
+            );
+        });
+
+        onEvent("session-ports:closed", (payload) => {
+            const event = asObject<{session_name?: unknown; port?: unknown}>(payload);
+            if (!event || typeof event.session_name !== "string" || typeof event.port !== "number") {
+                return;
+            }
+            useSessionPortsStore.getState().removePort(event.session_name, event.port);
+        });
+
+        // --- Git status events ---
+
+        onEvent("git:status-changed", (payload) => {
+            const event = asObject<{session_name?: unknown; status?: unknown}>(payload);
+            if (!event || typeof event.session_name !== "string") {
+                if (import.meta.env.DEV) {
+                    console.warn("[git] status-changed: invalid payload", payload);
+                }
+                return;
+            }
+            useGitStatusStore.getState().setStatus(event.session_name, toGitStatus(event.status));
+        });
+
+        // --- Pane activity events ---
+
+        onEvent("pane:activity-changed", (payload) => {
+            const event = asObject<{pane_id?: unknown; state?: unknown}>(payload);
+            if (!event || typeof event.pane_id !== "string") {
+                if (import.meta.env.DEV) {
+                    console.warn("[pane-activity] changed: invalid payload", payload);
+                }
+                return;
+            }
+            usePaneActivityStore.getState().setState(event.pane_id, toPaneActivityState(event.state));
+        });
+
+        // --- Deep link events ---
+
+        onEvent("app:deep-link-failed", (payload) => {
+            const event = asObject<{link?: unknown; message?: unknown}>(payload);
+            if (!event || typeof event.link !== "string") {
+                return;
+            }
+            notifyWarn(
+                tr(
+                    "sync.notifications.deepLinkFailed",
+                    "リンクを開けませんでした ({link}): {message}",
+                    "Could not open link ({link}): {message}",
+                    {link: event.link, message: typeof event.message === "string" ? event.message : "Unknown error"},
+                ),
+            );
+        });
+
+        // --- Repository config trust ---
+
+        onEvent("repo-config:trust-required", (payload) => {
+            const event = asObject<{path?: unknown}>(payload);
+            if (!event || typeof event.path !== "string") {
+                return;
+            }
+            notifyWarn(
+                tr(
+                    "sync.notifications.repoConfigTrustRequired",
+                    "{path} の .mytx.yaml は、フォルダが信頼されていないため実行されませんでした。新規セッション画面でフォルダを選択して信頼できます。",
+                    ".mytx.yaml in {path} was not run because the folder is not trusted. Select the folder in the New Session dialog to trust it.",
+                    {path: event.path},
+                ),
+            );
+        });
+
+        onEvent("repo-config:signature-rejected", (payload) => {
+            const event = asObject<{path?: unknown; config_error?: unknown}>(payload);
+            if (!event || typeof event.path !== "string") {
+                return;
+            }
+            notifyWarn(
+                tr(
+                    "sync.notifications.repoConfigSignatureRejected",
+                    "{path} の .mytx.yaml は署名を検証できなかったため実行されませんでした: {error}",
+                    ".mytx.yaml in {path} was not run because its signature could not be verified: {error}",
+                    {path: event.path, error: typeof event.config_error === "string" ? event.config_error : ""},
+                ),
+            );
+        });
+
         // --- Worker lifecycle events ---
+        // Toasts for worker panics and stops are rendered from the grouped
+        // app:errors batch; these handlers only persist to the session log.
 
         onEvent("tmux:worker-panic", (payload) => {
             const event = asObject<{worker?: unknown}>(payload);
@@ -470,7 +1077,6 @@ export function useSnapshotSync(): void {
                 if (import.meta.env.DEV) {
                     console.warn("[SYNC] tmux:worker-panic: invalid payload", payload);
                 }
-                notifyWarn("A background worker panic was recovered.");
                 // NOTE: Also persist to session log. The backend emits this event after
                 // recovering from a panic; the Go-side slog record may not always be
                 // present (e.g. if the panic occurred before the log write).
@@ -481,7 +1087,6 @@ export function useSnapshotSync(): void {
             if (import.meta.env.DEV) {
                 console.warn("[SYNC] tmux:worker-panic:", workerName);
             }
-            notifyWarn(`A background worker panic was recovered (${workerName}).`);
             logFrontendEventSafe("warn", `A background worker panic was recovered (${workerName})`, "frontend/worker");
         });
 
@@ -493,7 +1098,6 @@ export function useSnapshotSync(): void {
                 if (import.meta.env.DEV) {
                     console.warn("[SYNC] tmux:worker-fatal: invalid payload", payload);
                 }
-                notifyWarn("A background worker has permanently stopped after exceeding max retries.");
                 // NOTE: Also persist to session log — fatal worker stops are high-severity events.
                 logFrontendEventSafe("error", "A background worker has permanently stopped after exceeding max retries", "frontend/worker");
                 return;
@@ -504,10 +1108,37 @@ export function useSnapshotSync(): void {
             const fatalMsg =
                 `Background worker "${workerName}" has permanently stopped` +
                 (maxRetries != null ? ` after ${maxRetries} retries` : "");
-            notifyWarn(fatalMsg + ".");
             logFrontendEventSafe("error", fatalMsg, "frontend/worker");
         });
 
+        // --- System power events ---
+
+        onEvent("system:resumed", (payload) => {
+            const event = asObject<{reason?: unknown; panes_checked?: unknown; dead_panes?: unknown}>(payload);
+            if (!event) {
+                if (import.meta.env.DEV) {
+                    console.warn("[SYNC] system:resumed: invalid payload", payload);
+                }
+                return;
+            }
+            const deadPanes = asArray<{session_name?: string; pane_id?: string; closed?: boolean}>(event.dead_panes) ?? [];
+            if (import.meta.env.DEV) {
+                console.info("[SYNC] system:resumed:", event.reason, "checked:", event.panes_checked, "dead:", deadPanes);
+            }
+            if (deadPanes.length === 0) {
+                return;
+            }
+            const sessionNames = [...new Set(deadPanes.map((pane) => pane.session_name ?? "").filter((name) => name !== ""))];
+            notifyWarn(
+                tr(
+                    "sync.notifications.systemResumedDeadPanes",
+                    "スリープ中に終了した {count} 個のペインを整理しました: {sessions}",
+                    "Cleaned up {count} pane(s) whose process ended while the system was asleep: {sessions}",
+                    {count: deadPanes.length, sessions: sessionNames.join(", ")},
+                ),
+            );
+        });
+
         return () => {
             isMountedRef.current = false;
             disconnectPaneStream(); // WebSocket切断 + タイマークリア (#96)
@@ -1,4 +1,7 @@
+import {useCommandApprovalSync} from "./sync/useCommandApprovalSync";
 import {useConfigSync} from "./sync/useConfigSync";
+import {useErrorReportSync} from "./sync/useErrorReportSync";
+import {useEventSubscriptionSync} from "./sync/useEventSubscriptionSync";
 import {useInputHistorySync} from "./sync/useInputHistorySync";
 import {useMCPSync} from "./sync/useMCPSync";
 import {useSessionLogSync} from "./sync/useSessionLogSync";
@@ -13,6 +16,9 @@ import {useSnapshotSync} from "./sync/useSnapshotSync";
  * - useSessionLogSync: Session error log (ping + fetch pattern)
  * - useInputHistorySync: Input history (ping + fetch pattern)
  * - useMCPSync: MCP server state changes
+ * - useCommandApprovalSync: Shim commands held by session approval mode
+ * - useErrorReportSync: Grouped backend error toasts (app:errors)
+ * - useEventSubscriptionSync: Declares the rendered panes so hidden output is not sent
  */
 export function useBackendSync(): void {
     useSnapshotSync();
@@ -20,4 +26,7 @@ export function useBackendSync(): void {
     useSessionLogSync();
     useInputHistorySync();
     useMCPSync();
+    useCommandApprovalSync();
+    useErrorReportSync();
+    useEventSubscriptionSync();
 }
@@ -1,18 +1,23 @@
 import { useEffect } from "react";
 import { OnFileDrop, OnFileDropOff } from "../../wailsjs/runtime/runtime";
 import { api } from "../api";
+import { translate } from "../i18n";
 import { notifyAndLog } from "../utils/notifyUtils";
 
-/** ファイルパスをシェル安全にクォートする */
-function quotePathForShell(path: string): string {
-
-  const escaped = path.replace(/[`$]/g, "`$&");
-  return `"${escaped}"`;
+/** 既存ファイルの上書き確認 */
+function confirmOverwrite(conflicts: string[]): boolean {
+  return window.confirm(translate(
+    "fileDrop.confirmOverwrite",
+    "ペインのディレクトリに同名のファイルがあります: {names}\n上書きしますか？",
+    { names: conflicts.join(", ") },
+  ));
 }
 
 /**
  * Wails OnFileDrop をグローバルに1回だけ登録し、
- * ドロップされたファイルパスをアクティブペインに送信する。
+ * ドロップされたファイルをアクティブペインに渡す。
+ * モード (パス入力/コピー/リンク) とパスの入力有無は設定 file_drop に従い、
+ * 既存ファイルと衝突した場合は確認してから上書きする。
  *
  * OnFileDrop はグローバルシングルトンのため、
  * 各TerminalPaneではなくApp等の親コンポーネントで呼ぶ。
@@ -21,9 +26,14 @@ export function useFileDrop(activePaneId: string | null) {
   useEffect(() => {
     OnFileDrop((_x: number, _y: number, paths: string[]) => {
       if (paths.length === 0 || !activePaneId) return;
-      const quoted = paths.map(quotePathForShell).join(" ");
-      void api.SendInput(activePaneId, quoted).catch((err) => {
-        console.warn("[file-drop] SendInput failed", err);
+      void (async () => {
+        const result = await api.HandleFileDrop(activePaneId, paths, "", false);
+        if (result.needs_confirmation) {
+          if (!confirmOverwrite(result.conflicts ?? [])) return;
+          await api.HandleFileDrop(activePaneId, paths, result.mode, true);
+        }
+      })().catch((err) => {
+        console.warn("[file-drop] HandleFileDrop failed", err);
         notifyAndLog("File drop", "warn", err, "FileDrop");
       });
     }, true);
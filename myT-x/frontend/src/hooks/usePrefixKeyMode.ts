@@ -168,6 +168,13 @@ export function usePrefixKeyMode(options: UsePrefixKeyModeOptions) {
                 setPendingPrefixKillPaneId(paneId);
                 return;
             }
+            if (lowerKey === "a") {
+                void api.FocusNextActiveSession().catch((err: unknown) => {
+                    console.warn("[prefix] focus next active session failed", err);
+                    notifyAndLog("Focus next active session", "warn", err, "PrefixKey");
+                });
+                return;
+            }
             if (lowerKey === "d") {
                 if (activeSession) {
                     void api.DetachSession(activeSession).catch((err: unknown) => {
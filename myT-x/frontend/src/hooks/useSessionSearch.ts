@@ -0,0 +1,52 @@
+import {useEffect, useMemo, useState} from "react";
+import {api} from "../api";
+import type {SessionSnapshot} from "../types/tmux";
+
+const searchDebounceMs = 150;
+// Matches sessionquery.MaxLimit so one request covers the whole list.
+const searchPageLimit = 500;
+
+/**
+ * Filters the sidebar sessions through the backend session query engine, so
+ * the sidebar search matches exactly what API clients get from QuerySessions.
+ * Returns sessions unchanged while query is blank. Matches keep the sidebar
+ * order.
+ */
+export function useSessionSearch(query: string, sessions: SessionSnapshot[]): SessionSnapshot[] {
+    const text = query.trim();
+    const [matchedNames, setMatchedNames] = useState<Set<string> | null>(null);
+    // Re-query when sessions are added, removed, or renamed, but not on every
+    // snapshot update.
+    const namesKey = useMemo(() => sessions.map((session) => session.name).join("\n"), [sessions]);
+
+    useEffect(() => {
+        if (!text) {
+            setMatchedNames(null);
+            return;
+        }
+        let cancelled = false;
+        const timer = window.setTimeout(() => {
+            api.QuerySessions({text}, {}, {limit: searchPageLimit})
+                .then((result) => {
+                    if (!cancelled) {
+                        setMatchedNames(new Set((result.sessions ?? []).map((session) => session.name)));
+                    }
+                })
+                .catch((error: unknown) => {
+                    console.warn("[sidebar] QuerySessions failed", error);
+                    if (!cancelled) {
+                        setMatchedNames(null);
+                    }
+                });
+        }, searchDebounceMs);
+        return () => {
+            cancelled = true;
+            window.clearTimeout(timer);
+        };
+    }, [text, namesKey]);
+
+    return useMemo(
+        () => (text && matchedNames ? sessions.filter((session) => matchedNames.has(session.name)) : sessions),
+        [matchedNames, sessions, text],
+    );
+}
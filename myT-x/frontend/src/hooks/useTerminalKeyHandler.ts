@@ -2,11 +2,12 @@ import type {Dispatch, MutableRefObject, SetStateAction} from "react";
 import {ClipboardGetText} from "../../wailsjs/runtime/runtime";
 import {writeClipboardText} from "../utils/clipboardUtils";
 import {api} from "../api";
+import {translate} from "../i18n";
 import {useTmuxStore} from "../stores/tmuxStore";
 import {createConsecutiveFailureCounter, notifyAndLog} from "../utils/notifyUtils";
 import {shouldRecoverTerminalFocus, type TerminalFocusRecoveryReason} from "../utils/terminalFocus";
 import {shouldLetXtermHandleImeEvent} from "../utils/terminalIme";
-import {pasteTextSafely} from "../utils/terminalPaste";
+import {type PanePasteClient, pasteTextSafely, pasteToPaneSafely} from "../utils/terminalPaste";
 import {resolveActivePaneID} from "../utils/session";
 
 // Used as rate limiters (threshold=1): every failure fires, but cooldown prevents
@@ -34,6 +35,15 @@ function notifyPasteFailureThrottled(err: unknown): void {
     });
 }
 
+const panePasteClient: PanePasteClient = {
+    pasteToPane: (paneId, text, confirmed) => api.PasteToPane(paneId, text, confirmed),
+    confirmPaste: (lines) => window.confirm(translate(
+        "terminal.paste.confirmMultiline",
+        "{lines} 行を貼り付けます。改行ごとにコマンドとして実行されます。続行しますか？",
+        {lines},
+    )),
+};
+
 /** Shared mutable state between terminal event sub-systems within a single useEffect lifetime. */
 export interface TerminalEventShared {
     disposed: boolean;
@@ -270,11 +280,21 @@ export function setupKeyHandler({
             ime.finishComposition(false);
         }
         void ClipboardGetText()
-            .then((text) => {
+            .then(async (text) => {
                 if (shared.disposed) return;
                 clipboardReadFailureCounter.recordSuccess();
-                const pasted = pasteTextSafely(term, text);
-                if (text.length > 0 && !pasted) {
+                // Sync input broadcasts through onData, so it keeps the xterm
+                // paste path. Single-pane pastes go through the backend, which
+                // strips control characters and guards multi-line shell pastes.
+                if (syncInputModeRef.current) {
+                    const pasted = pasteTextSafely(term, text);
+                    if (text.length > 0 && !pasted) {
+                        console.warn("[terminal] paste discarded after normalization", {paneId, source, textLen: text.length});
+                    }
+                    return;
+                }
+                const outcome = await pasteToPaneSafely(panePasteClient, paneId, text);
+                if (text.length > 0 && outcome === "empty") {
                     console.warn("[terminal] paste discarded after normalization", {paneId, source, textLen: text.length});
                 }
             })
@@ -0,0 +1,97 @@
+import {type MutableRefObject, useEffect, useRef} from "react";
+import type {IDisposable, Terminal} from "@xterm/xterm";
+
+// DEC private modes with which an application requests mouse reports
+// (X10, normal, button-event, and any-event tracking). Report encodings such
+// as SGR (1006) only take effect while one of these is set, so they are
+// always passed through.
+const MOUSE_TRACKING_MODES = new Set([9, 1000, 1002, 1003]);
+
+interface MouseModeState {
+    // Tracking modes the application currently requests.
+    requested: Set<number>;
+    // Writes of our own DECSET/DECRST that are still being parsed; they must
+    // not change `requested`.
+    localWrites: number;
+}
+
+interface UseTerminalMouseModeOptions {
+    paneId: string;
+    terminalRef: MutableRefObject<Terminal | null>;
+    /** Whether mouse events are forwarded to the pane application (tmux "mouse on"). */
+    enabled: boolean;
+}
+
+/**
+ * Gates the mouse tracking requested by pane applications, like tmux's
+ * mouse option. While enabled, xterm.js applies the application's DECSET and
+ * reports mouse events (SGR-encoded when requested) through onData. While
+ * disabled, the DECSET is swallowed so the mouse keeps selecting and
+ * scrolling locally. Toggling replays the application's requested modes.
+ *
+ * INVARIANT: useTerminalSetup must be called before this hook so that
+ * terminalRef.current is populated and the handlers are registered before
+ * replayed output is parsed. See TerminalPane.tsx for the hook ordering contract.
+ */
+export function useTerminalMouseMode({paneId, terminalRef, enabled}: UseTerminalMouseModeOptions): void {
+    const enabledRef = useRef(enabled);
+    const stateRef = useRef<MouseModeState | null>(null);
+
+    useEffect(() => {
+        const term = terminalRef.current;
+        if (!term) return;
+        const state: MouseModeState = {requested: new Set(), localWrites: 0};
+        stateRef.current = state;
+
+        const handleModes = (params: (number | number[])[], set: boolean): boolean => {
+            const modes = params.map((param) => (Array.isArray(param) ? param[0] : param));
+            const tracking = modes.filter((mode) => MOUSE_TRACKING_MODES.has(mode));
+            if (tracking.length === 0) {
+                return false;
+            }
+            if (state.localWrites === 0) {
+                for (const mode of tracking) {
+                    if (set) {
+                        state.requested.add(mode);
+                    } else {
+                        state.requested.delete(mode);
+                    }
+                }
+            }
+            if (!set || enabledRef.current) {
+                return false;
+            }
+            // Swallow the sequence, but keep the other modes it sets.
+            const others = modes.filter((mode) => !MOUSE_TRACKING_MODES.has(mode));
+            if (others.length > 0) {
+                term.write(`\x1b[?${others.join(";")}h`);
+            }
+            return true;
+        };
+
+        const disposables: IDisposable[] = [
+            term.parser.registerCsiHandler({prefix: "?", final: "h"}, (params) => handleModes(params, true)),
+            term.parser.registerCsiHandler({prefix: "?", final: "l"}, (params) => handleModes(params, false)),
+        ];
+        return () => {
+            for (const disposable of disposables) {
+                disposable.dispose();
+            }
+            stateRef.current = null;
+        };
+        // eslint-disable-next-line react-hooks/exhaustive-deps -- terminalRef is a stable MutableRefObject
+    }, [paneId]);
+
+    useEffect(() => {
+        enabledRef.current = enabled;
+        const term = terminalRef.current;
+        const state = stateRef.current;
+        if (!term || !state || state.requested.size === 0) return;
+        const modes = [...state.requested].join(";");
+        state.localWrites++;
+        term.write(enabled ? `\x1b[?${modes}h` : `\x1b[?${modes}l`, () => {
+            state.localWrites--;
+        });
+        // eslint-disable-next-line react-hooks/exhaustive-deps -- terminalRef is a stable MutableRefObject
+    }, [enabled, paneId]);
+}
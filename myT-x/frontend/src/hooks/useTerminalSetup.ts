@@ -1,10 +1,11 @@
-import {type MutableRefObject, useEffect} from "react";
+import {type MutableRefObject, useEffect, useRef} from "react";
 import {FitAddon} from "@xterm/addon-fit";
 import {SearchAddon} from "@xterm/addon-search";
 import {WebLinksAddon} from "@xterm/addon-web-links";
 import {Terminal} from "@xterm/xterm";
 import {BrowserOpenURL} from "../../wailsjs/runtime/runtime";
 import {api} from "../api";
+import {usePaneReplayStore} from "../stores/paneReplayStore";
 import {useTmuxStore} from "../stores/tmuxStore";
 import {suppressNextTerminalFocusImeRecovery} from "../utils/imeRecovery";
 import {
@@ -113,9 +114,14 @@ export function useTerminalSetup({
     searchAddonRef,
     fitAddonRef,
 }: UseTerminalSetupOptions): void {
+    const replayGeneration = usePaneReplayStore((state) => state.generations[paneId] ?? 0);
+    // Replay generation already covered by the replay of the open terminal.
+    const replayedGenerationRef = useRef(replayGeneration);
+
     useEffect(() => {
         // Read font size at open time because this effect only recreates the terminal when paneId changes.
         const currentFontSize = useTmuxStore.getState().fontSize;
+        replayedGenerationRef.current = usePaneReplayStore.getState().generations[paneId] ?? 0;
 
         const term = new Terminal({
             convertEol: true,
@@ -261,4 +267,36 @@ export function useTerminalSetup({
         // by explicit user/window focus paths.
         // eslint-disable-next-line react-hooks/exhaustive-deps
     }, [paneId]);
+
+    // Output spilled to disk while the window was hidden or the stream was
+    // down may exceed what the terminal kept, so the open terminal restarts
+    // from the full spilled history. Declared after the setup effect so a
+    // terminal opened in the same commit is not replayed twice.
+    useEffect(() => {
+        if (replayGeneration === replayedGenerationRef.current) {
+            return;
+        }
+        replayedGenerationRef.current = replayGeneration;
+        let cancelled = false;
+        void api.GetPaneReplay(paneId)
+            .then((replay) => {
+                const term = terminalRef.current;
+                if (cancelled || !term || !replay) return;
+                try {
+                    const replayOutput = sanitizeTerminalReplay(replay).output;
+                    term.reset();
+                    if (replayOutput.length > 0) {
+                        term.write(replayOutput);
+                    }
+                } catch (err: unknown) {
+                    console.warn(`[terminal] spilled replay write failed for pane=${paneId}`, err);
+                }
+            })
+            .catch((err: unknown) => {
+                console.warn(`[terminal] spilled replay load failed for pane=${paneId}`, err);
+            });
+        return () => {
+            cancelled = true;
+        };
+    }, [paneId, replayGeneration, terminalRef]);
 }
@@ -13,6 +13,7 @@ const EN_TRANSLATIONS: Record<string, string> = {
     "app.closePane.title": "Close pane",
     "app.confirm.closePane.message": "Close pane \"{paneId}\"?",
     "app.confirm.closePane.title": "Close pane",
+    "app.changelog.updated": "Updated to v{version}: {changes}",
     "common.cancel": "Cancel",
     "common.save": "Save",
     "common.saving": "Saving...",
@@ -69,6 +70,11 @@ const EN_TRANSLATIONS: Record<string, string> = {
     "viewer.sessionMemo.notification.saved": "Session memo saved.",
     "viewer.sessionMemo.error.load": "Failed to load session memo.",
     "viewer.sessionMemo.error.save": "Failed to save session memo.",
+    "menu.bugReport": "Report bug",
+    "menu.bugReport.created": "Bug report created: {path}",
+    "menu.bugReport.failed": "Failed to create bug report: {message}",
+    "menu.bugReport.openIssue": "Open the GitHub new issue page? Attach the zip by hand.",
+    "menu.bugReport.title": "Bundle logs and config (secrets removed) into a zip",
     "menu.imeReset.aria": "Reset IME",
     "menu.imeReset.title": "Reset IME (Fix input conversion)",
     "menu.language": "Language",
@@ -105,6 +111,16 @@ const EN_TRANSLATIONS: Record<string, string> = {
     "settings.general.prefix.description": "tmux-compatible prefix key. Enter an action key after this key to operate the app.",
     "settings.general.quakeMode.label": "Quake Mode",
     "settings.general.quakeMode.description": "Toggle window visibility with a global hotkey.",
+    "settings.general.focusFollowsActivity.label": "Focus follows activity",
+    "settings.general.focusFollowsActivity.description": "When the current session is idle, switch to a session waiting for input (bell, or idle after unseen output). Prefix+a jumps manually.",
+    "settings.general.outputFolding.label": "Fold repeated output",
+    "settings.general.outputFolding.description": "Collapses lines repeated by spinners or polling loops into \"[line xN]\" in capture-pane history. Applies to new panes; the terminal view and logs are unchanged.",
+    "settings.general.shellHistoryIsolation.label": "Isolate shell history of new sessions by default",
+    "settings.general.shellHistoryIsolation.description": "Points HISTFILE and the PSReadLine history path into the session's state directory so experiments in throwaway agent sessions stay out of your global shell history. Sets the default of the new session dialog.",
+    "settings.general.mouse.label": "Send mouse events to pane applications",
+    "settings.general.mouse.description": "Like tmux's mouse on, passes clicks and wheel to applications that request them (htop, vim, lazygit). When off, dragging always selects text. Each pane can override this from its toolbar.",
+    "settings.general.pasteConfirm.label": "Confirm multi-line pastes into shells",
+    "settings.general.pasteConfirm.description": "Asks before a paste whose line breaks would run commands. Shells with bracketed paste and full-screen applications (vim) are not affected. Control characters are always stripped.",
     "settings.general.globalHotkey.label": "Global Hotkey",
     "settings.general.globalHotkey.aria": "Global hotkey shortcut",
     "settings.general.globalHotkey.description": "Toggle key for Quake mode (used only when Quake mode is enabled) (default: Ctrl+Shift+F12)",
@@ -131,6 +147,8 @@ const EN_TRANSLATIONS: Record<string, string> = {
     "settings.worktree.enabled.description": "Create and manage Git worktrees per session.",
     "settings.worktree.forceCleanup.label": "Force Cleanup",
     "settings.worktree.forceCleanup.description": "Force-delete worktrees when closing sessions.",
+    "settings.worktree.allowShared.label": "Allow Shared Worktrees",
+    "settings.worktree.allowShared.description": "Let several sessions open the same existing worktree and warn when it is checked out to another branch.",
     "settings.worktree.setupScripts.label": "Setup Scripts",
     "settings.worktree.setupScripts.description": "Commands to run after creating a worktree.",
     "settings.worktree.setupScripts.placeholderExample": "Example: npm install",
@@ -139,10 +157,17 @@ const EN_TRANSLATIONS: Record<string, string> = {
     "settings.worktree.copyFiles.description": "Files copied into new worktrees.",
     "settings.worktree.copyFiles.placeholderExample": "Example: .env",
     "settings.worktree.copyFiles.add": "Add File",
+    "settings.worktree.copyFilesEOL.label": "Copied File Line Endings",
+    "settings.worktree.copyFilesEOL.description": "Line endings applied to copied files. Binary files are copied unchanged.",
+    "settings.worktree.copyFilesEOL.verbatim": "Keep As Is",
+    "settings.worktree.copyFilesEOL.gitattributes": "Follow .gitattributes",
     "settings.worktree.copyDirs.label": "Directories to Copy",
     "settings.worktree.copyDirs.description": "Directories copied into new worktrees.",
     "settings.worktree.copyDirs.placeholderExample": "Example: .vscode",
     "settings.worktree.copyDirs.add": "Add Directory",
+    "settings.worktree.mergeTool.label": "Merge Tool",
+    "settings.worktree.mergeTool.description": "Tool name or command used to open conflicted files. Uses git config merge.tool when empty.",
+    "settings.worktree.mergeTool.placeholderExample": "Example: vscode",
 
     "settings.agentModel.title": "Agent Model",
     "settings.agentModel.description": "Remap source models to target models and configure per-agent overrides.",
@@ -251,12 +276,23 @@ const EN_TRANSLATIONS: Record<string, string> = {
 
     "sidebar.worktree.baseBranchFrom": "Base branch: {baseBranch}",
     "sidebar.worktree.detached": "detached",
+    "sidebar.badge.auto": "Badge from rules",
+    "sidebar.action.openPort.title": "Open {url}",
+    "sidebar.gitStatus.title": "{dirty} uncommitted file(s); {ahead} ahead, {behind} behind {upstream}",
+    "sidebar.paneActivity.waiting": "A pane is waiting for input",
+    "sidebar.paneActivity.busy": "A pane is working",
     "sidebar.sessionState.selected": "Selected",
     "sidebar.sessionState.stopped": "Stopped",
     "sidebar.sessionState.running": "Running",
     "sidebar.aria.sessionsList": "Sessions",
     "sidebar.error.activateFailed": "Failed to activate session \"{sessionName}\".",
     "sidebar.error.renameFailed": "Failed to rename session \"{oldName}\".",
+    "sidebar.action.refreshBase.title": "{baseRef} is {behind} commit(s) ahead. Rebase onto it",
+    "sidebar.action.refreshBase.button": "Rebase",
+    "sidebar.action.pullRequest.title": "Open a pull request for the pushed branch",
+    "sidebar.action.pullRequest.button": "PR",
+    "sidebar.action.pullRequest.opened": "Pull request opened: {url}",
+    "sidebar.action.pullRequest.existing": "A pull request is already open: {url}",
     "sidebar.action.promoteBranch.title": "Promote to Branch",
     "sidebar.action.promoteBranch.button": "Promote",
     "sidebar.error.openDirectoryFailed": "Could not open directory: {sessionName}",
@@ -266,6 +302,10 @@ const EN_TRANSLATIONS: Record<string, string> = {
     "sidebar.action.closeSession.aria": "Close session {sessionName}",
     "sidebar.subtitle": "Terminal Multiplexer",
     "sidebar.action.newSession": "+ New Session",
+    "sidebar.search.placeholder": "Search sessions, branches, repos",
+    "sidebar.search.aria": "Search sessions",
+    "sidebar.action.taskbarAlerts.mutedTitle": "Taskbar alerts off for this session (click to turn on)",
+    "sidebar.action.taskbarAlerts.onTitle": "Flash the taskbar when this session needs input while the window is in the background (click to turn off)",
 
     "sessionView.error.unknown": "Unknown error",
     "sessionView.empty.createSession": "Create a session.",
@@ -294,6 +334,8 @@ const EN_TRANSLATIONS: Record<string, string> = {
     "promoteBranch.description": "Convert detached HEAD in session \"{sessionName}\" to a named branch.",
     "promoteBranch.branchName.label": "Branch Name",
     "promoteBranch.branchName.placeholder": "feature/my-branch",
+    "promoteBranch.rebaseOntoBase.label": "Rebase onto the latest base branch first",
+    "promoteBranch.baseBranch.label": "Base Branch",
     "promoteBranch.action.promoting": "Promoting...",
     "promoteBranch.action.promote": "Promote",
 
@@ -302,18 +344,35 @@ const EN_TRANSLATIONS: Record<string, string> = {
     "newSession.warning.configLoadFailedLine2": "Claude Code environment variables and additional pane environment variables will be OFF for creation.",
     "newSession.directory.label": "Working Directory",
     "newSession.directory.selectButton": "Select folder...",
+    "newSession.recent.label": "Recent folders",
+    "newSession.recent.missing": "Folder not found",
+    "newSession.recent.pin": "Pin",
+    "newSession.recent.unpin": "Unpin",
+    "newSession.recent.remove": "Remove from list",
+    "newSession.repoStats.totals": "{files} files · {lines} lines",
+    "newSession.repoStats.truncated": " (partial)",
+    "newSession.repoStats.more": "+{count} more",
+    "newSession.trust.title": "This folder's .mytx.yaml wants to run commands. Do you trust its authors?",
+    "newSession.trust.startupCommand": "Startup command: ",
+    "newSession.trust.setupScript": "Setup script: ",
+    "newSession.trust.deny": "Don't Trust",
+    "newSession.trust.allow": "Trust Folder",
+    "newSession.trust.signedBy": "Signed by {signer}",
+    "newSession.trust.unverified": "Signed, but no signing keys are configured to verify it.",
     "newSession.sessionName.label": "Session Name",
     "newSession.sessionName.placeholder": "Enter session name",
     "newSession.agentTeam.enable": "Start as Agent Team",
     "newSession.agentTeam.shimMissing": "(shim not installed)",
     "newSession.env.claude": "Use Claude Code environment variables",
     "newSession.env.pane": "Use additional pane environment variables",
+    "newSession.env.shellHistory": "Keep shell history separate from the global history",
     "newSession.git.currentBranch": "Current branch:",
     "newSession.worktree.enable": "Use Git Worktree",
     "newSession.worktree.source.existing": "Use existing worktree",
     "newSession.worktree.select.placeholder": "Please select...",
     "newSession.worktree.detached": "(detached)",
     "newSession.worktree.conflict": "This worktree is already used by session \"{sessionName}\"",
+    "newSession.worktree.shared": "This worktree is shared with session \"{sessionName}\". Checking out another branch affects both sessions.",
     "newSession.worktree.source.new": "Create new worktree",
     "newSession.worktree.pullBefore": "Pull before creating",
     "newSession.worktree.baseBranch.label": "Base Branch",
@@ -334,11 +393,18 @@ const EN_TRANSLATIONS: Record<string, string> = {
     "killSession.warning.unpushed": "There are unpushed commits",
     "killSession.deleteWorktree.label": "Delete worktree",
     "killSession.deleteWorktree.hint": "If unchecked, the worktree will be kept",
+    "killSession.locked.message": "The worktree is locked and will not be deleted: {reason}",
+    "killSession.locked.noReason": "no reason given",
+    "killSession.action.unlock": "Unlock",
+    "killSession.action.stashThenClose": "Stash then Close",
+    "killSession.action.stashThenClose.title": "Stash the changes (the commit message is used as the stash message)",
     "killSession.action.closeWithoutSaving": "Close without saving",
     "killSession.action.close": "Close",
     "killSession.action.commitAndPushThenClose": "Commit & Push then Close",
     "killSession.action.commitThenClose": "Commit then Close",
     "killSession.action.pushThenClose": "Push then Close",
+    "killSession.pullRequest.opened": "Pull request opened: {url}",
+    "killSession.pullRequest.failed": "Pushed, but opening the pull request failed: {error}",
 
     "terminalSearch.placeholder": "Search...",
     "terminalSearch.prev.title": "Previous (Shift+Enter)",
@@ -682,6 +748,8 @@ const EN_TRANSLATIONS: Record<string, string> = {
     "sync.worker.panicRecovered": "A worker panic was recovered: {message}",
     "sync.mcp.detailRefreshFailed": "Failed to refresh MCP details.",
     "sync.paneStream.connectionFailed": "Failed to connect terminal output. Please restart the app.",
+    "terminal.paste.confirmMultiline": "Paste {lines} lines? Each line break runs a command.",
+    "fileDrop.confirmOverwrite": "Files with the same name already exist in the pane's directory: {names}\nOverwrite them?",
 };
 
 const listeners = new Set<() => void>();
@@ -0,0 +1,48 @@
+import {describe, expect, it} from "vitest";
+import type {SessionSnapshot} from "../types/tmux";
+import {decodeSnapshotPayload, hydrateCompactUpserts} from "./snapshotPayload";
+
+const session: SessionSnapshot = {
+    id: 1,
+    name: "s1",
+    created_at: "",
+    is_idle: false,
+    active_window_id: 0,
+    windows: [{
+        id: 0,
+        name: "main",
+        layout: {type: "leaf", pane_id: 0},
+        active_pane: 0,
+        panes: [{id: "%0", index: 0, active: true, width: 80, height: 24}],
+    }],
+};
+
+function compact(windowPatch: Record<string, unknown>): SessionSnapshot {
+    return {...session, windows: [{...session.windows[0], ...windowPatch}]} as unknown as SessionSnapshot;
+}
+
+describe("snapshotPayload", () => {
+    it("passes plain payloads through unchanged", async () => {
+        const payload = {upserts: [], removed: ["s1"]};
+        await expect(decodeSnapshotPayload(payload)).resolves.toBe(payload);
+        await expect(decodeSnapshotPayload(null)).resolves.toBeNull();
+    });
+
+    it("resolves pane and layout references from the current sessions", () => {
+        const hydrated = hydrateCompactUpserts(
+            [compact({layout: null, layout_unchanged: true, panes: [{id: "%0", unchanged: true}]})],
+            [session],
+        );
+        expect(hydrated).toEqual([session]);
+        expect(hydrated?.[0]?.windows[0]).not.toHaveProperty("layout_unchanged");
+    });
+
+    it("returns plain upserts as is", () => {
+        expect(hydrateCompactUpserts([session], [])).toEqual([session]);
+    });
+
+    it("returns null when a reference cannot be resolved", () => {
+        expect(hydrateCompactUpserts([compact({panes: [{id: "%9", unchanged: true}]})], [session])).toBeNull();
+        expect(hydrateCompactUpserts([compact({layout: null, layout_unchanged: true})], [])).toBeNull();
+    });
+});
@@ -0,0 +1,94 @@
+/**
+ * Decoding for the negotiated snapshot wire encodings (see the backend's
+ * internal/snapshot/encoding.go).
+ *
+ * - gzip: large payloads arrive as {encoding: "gzip-json", data: base64}.
+ * - compact-delta: delta upserts reference panes ({id, unchanged: true}) and
+ *   window layouts (layout_unchanged: true) that did not change; they are
+ *   hydrated from the sessions already in the store.
+ */
+import type {PaneSnapshot, SessionSnapshot, WindowSnapshot} from "../types/tmux";
+
+const GZIP_ENVELOPE_ENCODING = "gzip-json";
+
+/** Encodings this frontend can decode, in the names the backend negotiates. */
+export function supportedSnapshotEncodings(): string[] {
+    const encodings = ["compact-delta"];
+    if (typeof DecompressionStream !== "undefined") {
+        encodings.push("gzip");
+    }
+    return encodings;
+}
+
+function isGzipEnvelope(payload: unknown): payload is {encoding: string; data: string} {
+    if (payload === null || typeof payload !== "object" || Array.isArray(payload)) {
+        return false;
+    }
+    const envelope = payload as {encoding?: unknown; data?: unknown};
+    return envelope.encoding === GZIP_ENVELOPE_ENCODING && typeof envelope.data === "string";
+}
+
+/** Returns payload unchanged, or the decoded JSON value of a gzip envelope. */
+export async function decodeSnapshotPayload(payload: unknown): Promise<unknown> {
+    if (!isGzipEnvelope(payload)) {
+        return payload;
+    }
+    const binary = atob(payload.data);
+    const bytes = new Uint8Array(binary.length);
+    for (let i = 0; i < binary.length; i++) {
+        bytes[i] = binary.charCodeAt(i);
+    }
+    const stream = new Blob([bytes]).stream().pipeThrough(new DecompressionStream("gzip"));
+    return JSON.parse(await new Response(stream).text()) as unknown;
+}
+
+interface CompactWindow extends Omit<WindowSnapshot, "panes"> {
+    layout_unchanged?: boolean;
+    panes: Array<PaneSnapshot | {id: string; unchanged: true}>;
+}
+
+/**
+ * Resolves compact-delta references against the current sessions.
+ * Returns null when a referenced pane or layout is not known locally; the
+ * caller must then fetch the full session list.
+ */
+export function hydrateCompactUpserts(
+    upserts: SessionSnapshot[],
+    current: readonly SessionSnapshot[],
+): SessionSnapshot[] | null {
+    const currentByName = new Map(current.map((session) => [session.name, session]));
+    const hydrated: SessionSnapshot[] = [];
+    for (const session of upserts) {
+        const previous = currentByName.get(session.name);
+        const previousWindows = new Map((previous?.windows ?? []).map((window) => [window.id, window]));
+        const previousPanes = new Map<string, PaneSnapshot>();
+        for (const window of previous?.windows ?? []) {
+            for (const pane of window.panes ?? []) {
+                previousPanes.set(pane.id, pane);
+            }
+        }
+
+        const windows: WindowSnapshot[] = [];
+        for (const rawWindow of (session.windows ?? []) as unknown as CompactWindow[]) {
+            const {layout_unchanged: layoutUnchanged, ...window} = rawWindow;
+            let layout = window.layout;
+            if (layoutUnchanged) {
+                layout = previousWindows.get(window.id)?.layout;
+                if (!layout) return null;
+            }
+            const panes: PaneSnapshot[] = [];
+            for (const pane of window.panes ?? []) {
+                if ("unchanged" in pane && pane.unchanged) {
+                    const previousPane = previousPanes.get(pane.id);
+                    if (!previousPane) return null;
+                    panes.push(previousPane);
+                } else {
+                    panes.push(pane as PaneSnapshot);
+                }
+            }
+            windows.push({...window, layout, panes});
+        }
+        hydrated.push({...session, windows});
+    }
+    return hydrated;
+}
@@ -0,0 +1,42 @@
+import {create} from "zustand";
+
+export interface PendingApproval {
+    readonly id: string;
+    readonly sessionName: string;
+    readonly paneId: string;
+    readonly command: string;
+    readonly commandLine: string;
+    readonly expiresAt: string;
+}
+
+interface CommandApprovalState {
+    readonly pending: readonly PendingApproval[];
+    // Sessions with approval mode on, seeded per sidebar row.
+    readonly enabledSessions: Readonly<Record<string, boolean>>;
+    setPending: (pending: readonly PendingApproval[]) => void;
+    addPending: (approval: PendingApproval) => void;
+    removePending: (id: string) => void;
+    setSessionEnabled: (sessionName: string, enabled: boolean) => void;
+}
+
+// Mirrors the backend command approval gate. Seeded by
+// GetPendingCommandApprovals and kept current by session:approval-pending /
+// session:approval-resolved events.
+export const useCommandApprovalStore = create<CommandApprovalState>((set) => ({
+    pending: [],
+    enabledSessions: {},
+    setPending: (pending) => set({pending: [...pending]}),
+    addPending: (approval) => set((state) => ({
+        pending: [...state.pending.filter((item) => item.id !== approval.id), approval],
+    })),
+    removePending: (id) => set((state) => ({
+        pending: state.pending.filter((item) => item.id !== id),
+    })),
+    setSessionEnabled: (sessionName, enabled) => set((state) => {
+        if (enabled) {
+            return {enabledSessions: {...state.enabledSessions, [sessionName]: true}};
+        }
+        const {[sessionName]: _, ...rest} = state.enabledSessions;
+        return {enabledSessions: rest};
+    }),
+}));
@@ -0,0 +1,48 @@
+import {beforeEach, describe, expect, it} from "vitest";
+import {toGitStatus, useGitStatusStore} from "./gitStatusStore";
+
+const clean = {
+    branch: "main",
+    detached: false,
+    upstream: "origin/main",
+    ahead: 0,
+    behind: 0,
+    staged: 0,
+    unstaged: 0,
+    untracked: 0,
+    conflicted: 0,
+    dirty: 0,
+};
+
+beforeEach(() => {
+    useGitStatusStore.setState({...useGitStatusStore.getState(), statuses: {}}, true);
+});
+
+describe("toGitStatus", () => {
+    it("fills missing fields and rejects malformed payloads", () => {
+        expect(toGitStatus({branch: "dev", ahead: 2, dirty: -1})).toEqual({
+            ...clean,
+            branch: "dev",
+            upstream: "",
+            ahead: 2,
+        });
+        expect(toGitStatus(null)).toBeNull();
+        expect(toGitStatus({ahead: 1})).toBeNull();
+    });
+});
+
+describe("useGitStatusStore", () => {
+    it("sets and removes session statuses", () => {
+        const store = useGitStatusStore.getState();
+        store.setAll({api: clean});
+        store.setStatus("web", {...clean, dirty: 3});
+        expect(Object.keys(useGitStatusStore.getState().statuses).sort()).toEqual(["api", "web"]);
+
+        store.setStatus("api", null);
+        expect(useGitStatusStore.getState().statuses).toEqual({web: {...clean, dirty: 3}});
+
+        const before = useGitStatusStore.getState().statuses;
+        store.setStatus("missing", null);
+        expect(useGitStatusStore.getState().statuses).toBe(before);
+    });
+});
@@ -0,0 +1,64 @@
+import {create} from "zustand";
+
+export interface GitStatus {
+    readonly branch: string;
+    readonly detached: boolean;
+    readonly upstream: string;
+    readonly ahead: number;
+    readonly behind: number;
+    readonly staged: number;
+    readonly unstaged: number;
+    readonly untracked: number;
+    readonly conflicted: number;
+    readonly dirty: number;
+}
+
+interface GitStatusState {
+    readonly statuses: Readonly<Record<string, GitStatus>>;
+    setAll: (statuses: Readonly<Record<string, GitStatus>>) => void;
+    setStatus: (sessionName: string, status: GitStatus | null) => void;
+}
+
+// Mirrors the backend git status watcher. Seeded by GetGitStatuses and kept
+// current by git:status-changed events; a null status removes the session.
+export const useGitStatusStore = create<GitStatusState>((set) => ({
+    statuses: {},
+    setAll: (statuses) => set({statuses: {...statuses}}),
+    setStatus: (sessionName, status) => set((state) => {
+        if (status === null) {
+            if (!(sessionName in state.statuses)) {
+                return state;
+            }
+            const {[sessionName]: _, ...rest} = state.statuses;
+            return {statuses: rest};
+        }
+        return {statuses: {...state.statuses, [sessionName]: status}};
+    }),
+}));
+
+function count(value: unknown): number {
+    return typeof value === "number" && Number.isFinite(value) && value > 0 ? value : 0;
+}
+
+// toGitStatus validates a status from the backend; null when malformed.
+export function toGitStatus(value: unknown): GitStatus | null {
+    if (typeof value !== "object" || value === null) {
+        return null;
+    }
+    const raw = value as Record<string, unknown>;
+    if (typeof raw.branch !== "string") {
+        return null;
+    }
+    return {
+        branch: raw.branch,
+        detached: raw.detached === true,
+        upstream: typeof raw.upstream === "string" ? raw.upstream : "",
+        ahead: count(raw.ahead),
+        behind: count(raw.behind),
+        staged: count(raw.staged),
+        unstaged: count(raw.unstaged),
+        untracked: count(raw.untracked),
+        conflicted: count(raw.conflicted),
+        dirty: count(raw.dirty),
+    };
+}
@@ -1,37 +1,75 @@
 import { create } from "zustand";
 
+/** Grouped backend error carried by toasts from the app:errors event. */
+export interface NotificationGroup {
+  key: string;
+  code: string;
+  source: string;
+  detail: string;
+  hint: string;
+  count: number;
+}
+
 export interface Notification {
   id: string;
   message: string;
   level: "info" | "warn" | "error";
   timestamp: number;
+  group?: NotificationGroup;
 }
 
 interface NotificationState {
   notifications: Notification[];
   addNotification: (message: string, level: Notification["level"]) => void;
+  /** Adds a grouped toast, or updates the toast of the same group.key in place. */
+  upsertGroupNotification: (message: string, level: Notification["level"], group: NotificationGroup) => void;
   removeNotification: (id: string) => void;
 }
 
+// Auto-dismiss after 8 seconds.
+const AUTO_DISMISS_MS = 8000;
+
 // Use timestamp-based IDs to avoid issues with HMR resetting module scope.
 let nextId = Date.now();
+const dismissTimers = new Map<string, ReturnType<typeof setTimeout>>();
+
+export const useNotificationStore = create<NotificationState>((set, get) => {
+  const scheduleDismiss = (id: string) => {
+    const pending = dismissTimers.get(id);
+    if (pending !== undefined) clearTimeout(pending);
+    dismissTimers.set(id, setTimeout(() => get().removeNotification(id), AUTO_DISMISS_MS));
+  };
 
-export const useNotificationStore = create<NotificationState>((set) => ({
-  notifications: [],
-  addNotification: (message, level) => {
-    const id = String(nextId++);
-    set((state) => ({
-      notifications: [...state.notifications, { id, message, level, timestamp: Date.now() }],
-    }));
-    // Auto-dismiss after 8 seconds.
-    setTimeout(() => {
+  return {
+    notifications: [],
+    addNotification: (message, level) => {
+      const id = String(nextId++);
+      set((state) => ({
+        notifications: [...state.notifications, { id, message, level, timestamp: Date.now() }],
+      }));
+      scheduleDismiss(id);
+    },
+    upsertGroupNotification: (message, level, group) => {
+      const existing = get().notifications.find((n) => n.group?.key === group.key);
+      const id = existing?.id ?? String(nextId++);
+      const next: Notification = { id, message, level, timestamp: Date.now(), group };
+      set((state) => ({
+        notifications: existing
+          ? state.notifications.map((n) => (n.id === id ? next : n))
+          : [...state.notifications, next],
+      }));
+      // A repeat keeps the toast visible for another full period.
+      scheduleDismiss(id);
+    },
+    removeNotification: (id) => {
+      const pending = dismissTimers.get(id);
+      if (pending !== undefined) {
+        clearTimeout(pending);
+        dismissTimers.delete(id);
+      }
       set((state) => ({
         notifications: state.notifications.filter((n) => n.id !== id),
       }));
-    }, 8000);
-  },
-  removeNotification: (id) =>
-    set((state) => ({
-      notifications: state.notifications.filter((n) => n.id !== id),
-    })),
-}));
+    },
+  };
+});
@@ -0,0 +1,28 @@
+import {beforeEach, describe, expect, it} from "vitest";
+import {summarizePaneActivity, toPaneActivityState, usePaneActivityStore} from "./paneActivityStore";
+
+beforeEach(() => {
+    usePaneActivityStore.setState({...usePaneActivityStore.getState(), states: {}}, true);
+});
+
+describe("usePaneActivityStore", () => {
+    it("stores busy and waiting panes and drops idle and closed ones", () => {
+        const store = usePaneActivityStore.getState();
+        store.setState("%1", "busy");
+        store.setState("%2", "waiting");
+        expect(usePaneActivityStore.getState().states).toEqual({"%1": "busy", "%2": "waiting"});
+
+        store.setState("%1", "idle");
+        store.setState("%2", toPaneActivityState("closed"));
+        expect(usePaneActivityStore.getState().states).toEqual({});
+    });
+});
+
+describe("summarizePaneActivity", () => {
+    it("prefers waiting over busy", () => {
+        const states = {"%1": "busy", "%2": "waiting"} as const;
+        expect(summarizePaneActivity(states, ["%1"])).toBe("busy");
+        expect(summarizePaneActivity(states, ["%1", "%2"])).toBe("waiting");
+        expect(summarizePaneActivity(states, ["%3"])).toBeNull();
+    });
+});
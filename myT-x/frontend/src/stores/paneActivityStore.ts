@@ -0,0 +1,56 @@
+import {create} from "zustand";
+
+export type PaneActivityState = "busy" | "idle" | "waiting";
+
+interface PaneActivityStoreState {
+    // Keyed by pane ID. Idle panes are not stored.
+    readonly states: Readonly<Record<string, PaneActivityState>>;
+    setAll: (states: Readonly<Record<string, PaneActivityState>>) => void;
+    setState: (paneId: string, state: PaneActivityState | null) => void;
+}
+
+// Mirrors the backend pane activity monitor. Seeded by GetPaneActivity and
+// kept current by pane:activity-changed events; idle and closed panes are
+// removed.
+export const usePaneActivityStore = create<PaneActivityStoreState>((set) => ({
+    states: {},
+    setAll: (states) => set({states: {...states}}),
+    setState: (paneId, state) => set((current) => {
+        if (state === null || state === "idle") {
+            if (!(paneId in current.states)) {
+                return current;
+            }
+            const {[paneId]: _, ...rest} = current.states;
+            return {states: rest};
+        }
+        if (current.states[paneId] === state) {
+            return current;
+        }
+        return {states: {...current.states, [paneId]: state}};
+    }),
+}));
+
+// toPaneActivityState validates a state from the backend; null for closed
+// panes and malformed values.
+export function toPaneActivityState(value: unknown): PaneActivityState | null {
+    return value === "busy" || value === "idle" || value === "waiting" ? value : null;
+}
+
+// summarizePaneActivity returns the state that needs the most attention
+// among paneIds: waiting, then busy. Null when every pane is idle.
+export function summarizePaneActivity(
+    states: Readonly<Record<string, PaneActivityState>>,
+    paneIds: readonly string[],
+): PaneActivityState | null {
+    let summary: PaneActivityState | null = null;
+    for (const paneId of paneIds) {
+        const state = states[paneId];
+        if (state === "waiting") {
+            return "waiting";
+        }
+        if (state === "busy") {
+            summary = "busy";
+        }
+    }
+    return summary;
+}
@@ -0,0 +1,21 @@
+import {beforeEach, describe, expect, it} from "vitest";
+import {usePaneReplayStore} from "./paneReplayStore";
+
+beforeEach(() => {
+    usePaneReplayStore.setState({...usePaneReplayStore.getState(), generations: {}}, true);
+});
+
+describe("usePaneReplayStore", () => {
+    it("bumps the replay generation of each spilled pane", () => {
+        const store = usePaneReplayStore.getState();
+        store.requestReplay(["%1", "%2"]);
+        store.requestReplay(["%1"]);
+        expect(usePaneReplayStore.getState().generations).toEqual({"%1": 2, "%2": 1});
+    });
+
+    it("keeps the state for an empty request", () => {
+        const before = usePaneReplayStore.getState().generations;
+        usePaneReplayStore.getState().requestReplay([]);
+        expect(usePaneReplayStore.getState().generations).toBe(before);
+    });
+});
@@ -0,0 +1,24 @@
+import {create} from "zustand";
+
+interface PaneReplayState {
+    /** Per pane, bumped each time spilled output waits to be replayed. */
+    readonly generations: Readonly<Record<string, number>>;
+    requestReplay: (paneIds: readonly string[]) => void;
+}
+
+// Panes whose output was spilled to disk while the window was hidden or the
+// pane stream was disconnected, from pane:output-spilled events. Mounted
+// terminals watch their generation and reload from GetPaneReplay.
+export const usePaneReplayStore = create<PaneReplayState>((set) => ({
+    generations: {},
+    requestReplay: (paneIds) => set((state) => {
+        if (paneIds.length === 0) {
+            return state;
+        }
+        const generations = {...state.generations};
+        for (const paneId of paneIds) {
+            generations[paneId] = (generations[paneId] ?? 0) + 1;
+        }
+        return {generations};
+    }),
+}));
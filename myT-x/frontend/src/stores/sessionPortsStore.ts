@@ -0,0 +1,45 @@
+import {create} from "zustand";
+
+export interface SessionPort {
+    readonly sessionName: string;
+    readonly paneId: string;
+    readonly port: number;
+    readonly process: string;
+    readonly url: string;
+}
+
+interface SessionPortsState {
+    readonly ports: Readonly<Record<string, readonly SessionPort[]>>;
+    setSessionPorts: (sessionName: string, ports: readonly SessionPort[]) => void;
+    addPort: (port: SessionPort) => void;
+    removePort: (sessionName: string, port: number) => void;
+}
+
+function sortedByPort(ports: readonly SessionPort[]): SessionPort[] {
+    return [...ports].sort((a, b) => a.port - b.port);
+}
+
+// Mirrors the backend sessionports registry. Seeded by GetSessionPorts and
+// kept current by session-ports:opened / session-ports:closed events.
+export const useSessionPortsStore = create<SessionPortsState>((set) => ({
+    ports: {},
+    setSessionPorts: (sessionName, ports) => set((state) => ({
+        ports: {...state.ports, [sessionName]: sortedByPort(ports)},
+    })),
+    addPort: (port) => set((state) => {
+        const existing = (state.ports[port.sessionName] ?? []).filter((item) => item.port !== port.port);
+        return {ports: {...state.ports, [port.sessionName]: sortedByPort([...existing, port])}};
+    }),
+    removePort: (sessionName, port) => set((state) => {
+        const existing = state.ports[sessionName];
+        if (!existing) {
+            return state;
+        }
+        const next = existing.filter((item) => item.port !== port);
+        if (next.length === 0) {
+            const {[sessionName]: _, ...rest} = state.ports;
+            return {ports: rest};
+        }
+        return {ports: {...state.ports, [sessionName]: next}};
+    }),
+}));
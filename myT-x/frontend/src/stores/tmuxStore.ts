@@ -93,7 +93,8 @@ function resolveSessionState(
     const activeSession =
         prevActiveSession && sessions.some((s) => s.name === prevActiveSession)
             ? prevActiveSession
-            : sessions[0]?.name ?? null;
+            // Detached (new-session -d) sessions are never auto-selected.
+            : sessions.find((s) => !s.detached)?.name ?? null;
     // C-02: Resolve active window ID from the active session snapshot.
     const activeSessionSnapshot = activeSession
         ? sessions.find((s) => s.name === activeSession) ?? null
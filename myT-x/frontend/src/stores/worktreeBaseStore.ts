@@ -0,0 +1,30 @@
+import {create} from "zustand";
+
+export interface WorktreeBaseAdvance {
+    readonly sessionName: string;
+    readonly baseRef: string;
+    readonly behind: number;
+}
+
+interface WorktreeBaseState {
+    readonly advanced: Readonly<Record<string, WorktreeBaseAdvance>>;
+    setAdvanced: (advance: WorktreeBaseAdvance) => void;
+    clearAdvanced: (sessionName: string) => void;
+}
+
+// Worktree sessions whose base branch gained commits, from
+// worktree:base-advanced events. A successful worktree:base-refreshed clears
+// the entry.
+export const useWorktreeBaseStore = create<WorktreeBaseState>((set) => ({
+    advanced: {},
+    setAdvanced: (advance) => set((state) => ({
+        advanced: {...state.advanced, [advance.sessionName]: advance},
+    })),
+    clearAdvanced: (sessionName) => set((state) => {
+        if (!(sessionName in state.advanced)) {
+            return state;
+        }
+        const {[sessionName]: _, ...rest} = state.advanced;
+        return {advanced: rest};
+    }),
+}));
@@ -50,7 +50,7 @@ type AssertTrue<T extends true> = T;
 
 export type AppConfigWorktree = Pick<
     wailsConfig.WorktreeConfig,
-    "enabled" | "force_cleanup" | "setup_scripts" | "setup_script_timeout_seconds" | "copy_files" | "copy_dirs"
+    "enabled" | "force_cleanup" | "setup_scripts" | "setup_script_timeout_seconds" | "copy_files" | "copy_dirs" | "copy_files_eol" | "merge_tool" | "allow_shared"
 >;
 
 export type AppConfigAgentModelOverride = Pick<wailsConfig.AgentModelOverride, "name" | "model">;
@@ -113,6 +113,8 @@ type AppConfigBase = Pick<
 
 export type AppConfigClaudeEnv = Pick<wailsConfig.ClaudeEnvConfig, "default_enabled" | "vars">;
 
+export type AppConfigForge = Pick<wailsConfig.ForgeConfig, "kind" | "draft" | "create_on_push">;
+
 export type AppConfig = AppConfigBase & {
     worktree: AppConfigWorktree;
     auto_start: AppConfigAutoStartCommand[];
@@ -123,6 +125,12 @@ export type AppConfig = AppConfigBase & {
     task_scheduler?: AppConfigTaskScheduler;
     viewer_shortcuts?: Record<string, string>;
     mcp_servers?: AppConfigMCPServerConfig[];
+    focus_follows_activity?: boolean;
+    output_folding?: boolean;
+    shell_history_isolation_default_enabled?: boolean;
+    mouse?: string;
+    paste_confirm?: string;
+    forge?: AppConfigForge;
 };
 
 export type WailsConfigInput = {
@@ -144,6 +152,11 @@ export type WailsConfigInput = {
     mcp_servers: AppConfigMCPServerConfig[] | undefined;
     chat_overlay_percentage: number | undefined;
     task_scheduler: AppConfigTaskScheduler | undefined;
+    focus_follows_activity: boolean | undefined;
+    output_folding: boolean | undefined;
+    shell_history_isolation_default_enabled: boolean | undefined;
+    mouse: string | undefined;
+    paste_confirm: string | undefined;
 };
 
 type WailsConfigInputKeyShape = {
@@ -165,6 +178,11 @@ type WailsConfigInputKeyShape = {
     mcp_servers: true;
     chat_overlay_percentage: true;
     task_scheduler: true;
+    focus_follows_activity: true;
+    output_folding: true;
+    shell_history_isolation_default_enabled: true;
+    mouse: true;
+    paste_confirm: true;
 };
 
 type _WailsConfigInputKeyGuard =
@@ -216,6 +234,19 @@ export interface PaneSnapshot {
     active: boolean;
     width: number;
     height: number;
+    // Collapsed panes are absent from WindowSnapshot.layout.
+    collapsed?: boolean;
+    // Mouse overrides the config mouse default: "on" or "off". Omitted follows the default.
+    mouse?: string;
+    // Modes the pane application has enabled, tracked from its output.
+    terminalModes?: PaneTerminalModes;
+}
+
+export interface PaneTerminalModes {
+    alternateScreen?: boolean;
+    appCursorKeys?: boolean;
+    appKeypad?: boolean;
+    bracketedPaste?: boolean;
 }
 
 export interface WindowSnapshot {
@@ -226,6 +257,13 @@ export interface WindowSnapshot {
     panes: PaneSnapshot[];
 }
 
+export interface SessionBadge {
+    color?: string;
+    emoji?: string;
+    // True when resolved from session_badge_rules rather than set by the user.
+    auto?: boolean;
+}
+
 export interface SessionSnapshot {
     id: number;
     name: string;
@@ -236,9 +274,18 @@ export interface SessionSnapshot {
     active_window_id: number;
     // Backend omits false via omitempty, so undefined means false.
     is_agent_team?: boolean;
+    // Set for sessions created with new-session -d until they are attached.
+    // Backend omits false via omitempty, so undefined means attached.
+    detached?: boolean;
+    // Effective badge; omitted when the session has none.
+    badge?: SessionBadge;
     windows: WindowSnapshot[];
     worktree?: SessionWorktreeInfo;
     root_path?: string;
+    // Set when the session is excluded from taskbar alerts. Backend omits false.
+    taskbar_alerts_muted?: boolean;
+    // Session group (multi-repo workspace). Backend omits it when ungrouped.
+    group?: string;
 }
 
 export interface SessionWorktreeInfo {
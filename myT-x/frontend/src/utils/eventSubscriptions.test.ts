@@ -0,0 +1,50 @@
+import {describe, expect, it} from "vitest";
+import type {SessionSnapshot} from "../types/tmux";
+import {buildEventSubscriptionSpec, newlySubscribedPanes, sameEventSubscriptionSpec} from "./eventSubscriptions";
+
+function session(name: string, panes: {id: string; collapsed?: boolean}[]): SessionSnapshot {
+    return {
+        name,
+        windows: [{id: 0, name: "main", active_pane: 0, panes}],
+    } as unknown as SessionSnapshot;
+}
+
+describe("buildEventSubscriptionSpec", () => {
+    const sessions = [
+        session("alpha", [{id: "%2"}, {id: "%1"}, {id: "%3", collapsed: true}]),
+        session("beta", [{id: "%4"}]),
+    ];
+
+    it("declares the visible panes of the active session", () => {
+        expect(buildEventSubscriptionSpec(sessions, "alpha", false))
+            .toEqual({panes: ["%1", "%2"]});
+    });
+
+    it("keeps collapsed panes in canvas mode", () => {
+        expect(buildEventSubscriptionSpec(sessions, "alpha", true).panes).toEqual(["%1", "%2", "%3"]);
+    });
+
+    it("allows everything without an active session", () => {
+        expect(buildEventSubscriptionSpec(sessions, null, false)).toEqual({});
+    });
+});
+
+describe("newlySubscribedPanes", () => {
+    it("returns panes that were filtered before", () => {
+        const previous = {panes: ["%1"]};
+        const next = {panes: ["%1", "%3"]};
+        expect(newlySubscribedPanes(previous, next)).toEqual(["%3"]);
+        expect(newlySubscribedPanes(null, next)).toEqual([]);
+        expect(newlySubscribedPanes({}, next)).toEqual([]);
+    });
+});
+
+describe("sameEventSubscriptionSpec", () => {
+    it("compares every list", () => {
+        const spec = {panes: ["%1"]};
+        expect(sameEventSubscriptionSpec(spec, {panes: ["%1"]})).toBe(true);
+        expect(sameEventSubscriptionSpec(spec, {panes: []})).toBe(false);
+        expect(sameEventSubscriptionSpec({}, {})).toBe(true);
+        expect(sameEventSubscriptionSpec(null, spec)).toBe(false);
+    });
+});
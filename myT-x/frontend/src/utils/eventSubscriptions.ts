@@ -0,0 +1,58 @@
+import type {SessionSnapshot} from "../types/tmux";
+
+/** Mirrors eventsub.Spec: an omitted list allows everything, an empty list nothing. */
+export interface EventSubscriptionSpec {
+    classes?: string[];
+    sessions?: string[];
+    panes?: string[];
+}
+
+/**
+ * Builds the subscriptions for what the session view renders: the panes of
+ * the active session, without collapsed panes outside canvas mode (canvas
+ * mode shows them as nodes). Session-scoped events stay subscribed for every
+ * session because the sidebar shows all of them. Before a session is active
+ * everything is allowed.
+ */
+export function buildEventSubscriptionSpec(
+    sessions: readonly SessionSnapshot[],
+    activeSession: string | null,
+    canvasMode: boolean,
+): EventSubscriptionSpec {
+    const session = sessions.find((s) => s.name === activeSession);
+    if (!session) {
+        return {};
+    }
+    const panes: string[] = [];
+    for (const window of session.windows ?? []) {
+        for (const pane of window.panes ?? []) {
+            if (canvasMode || !pane.collapsed) {
+                panes.push(pane.id);
+            }
+        }
+    }
+    panes.sort();
+    return {panes};
+}
+
+/** Returns the panes that next allows and previous did not. */
+export function newlySubscribedPanes(
+    previous: EventSubscriptionSpec | null,
+    next: EventSubscriptionSpec,
+): string[] {
+    if (!previous?.panes || !next.panes) {
+        return [];
+    }
+    const before = new Set(previous.panes);
+    return next.panes.filter((paneId) => !before.has(paneId));
+}
+
+/** Compares two specs, treating lists as already sorted. */
+export function sameEventSubscriptionSpec(a: EventSubscriptionSpec | null, b: EventSubscriptionSpec): boolean {
+    if (!a) {
+        return false;
+    }
+    const sameList = (x: string[] | undefined, y: string[] | undefined) =>
+        x === y || (x !== undefined && y !== undefined && x.length === y.length && x.every((v, i) => v === y[i]));
+    return sameList(a.classes, b.classes) && sameList(a.sessions, b.sessions) && sameList(a.panes, b.panes);
+}
@@ -0,0 +1,30 @@
+import {describe, expect, it} from "vitest";
+import {createMergePatch} from "./mergePatch";
+
+describe("createMergePatch", () => {
+    it("returns an empty patch for equal values", () => {
+        const value = {shell: "cmd.exe", worktree: {enabled: true, copy_files: [".env"]}, pane_env: undefined};
+        expect(createMergePatch(value, structuredClone(value))).toEqual({});
+    });
+
+    it("includes only changed leaves of nested objects", () => {
+        expect(createMergePatch(
+            {shell: "cmd.exe", worktree: {enabled: true, force_cleanup: false}},
+            {shell: "cmd.exe", worktree: {enabled: true, force_cleanup: true}},
+        )).toEqual({worktree: {force_cleanup: true}});
+    });
+
+    it("removes cleared values and deleted map keys with null", () => {
+        expect(createMergePatch(
+            {keys: {a: "1", b: "2"}, agent_model: {from: "x", to: "y"}, default_session_dir: "C:\\work"},
+            {keys: {a: "1"}, agent_model: undefined, default_session_dir: undefined},
+        )).toEqual({keys: {b: null}, agent_model: null, default_session_dir: null});
+    });
+
+    it("replaces arrays as a whole", () => {
+        expect(createMergePatch(
+            {auto_start: [{name: "a", command: "x"}]},
+            {auto_start: [{name: "a", command: "x"}, {name: "b", command: "y"}]},
+        )).toEqual({auto_start: [{name: "a", command: "x"}, {name: "b", command: "y"}]});
+    });
+});
@@ -0,0 +1,32 @@
+type JsonObject = Record<string, unknown>;
+
+function isPlainObject(value: unknown): value is JsonObject {
+    return typeof value === "object" && value !== null && !Array.isArray(value);
+}
+
+// createMergePatch builds the JSON merge patch (RFC 7386) that turns original
+// into updated, for the backend ApplyConfigPatch. Undefined properties count
+// as absent and are sent as null; arrays are replaced as a whole.
+export function createMergePatch(original: JsonObject, updated: JsonObject): JsonObject {
+    const patch: JsonObject = {};
+    for (const [key, oldValue] of Object.entries(original)) {
+        if (oldValue !== undefined && updated[key] === undefined) {
+            patch[key] = null;
+        }
+    }
+    for (const [key, newValue] of Object.entries(updated)) {
+        if (newValue === undefined) {
+            continue;
+        }
+        const oldValue = original[key];
+        if (isPlainObject(oldValue) && isPlainObject(newValue)) {
+            const nested = createMergePatch(oldValue, newValue);
+            if (Object.keys(nested).length > 0) {
+                patch[key] = nested;
+            }
+        } else if (JSON.stringify(oldValue) !== JSON.stringify(newValue)) {
+            patch[key] = newValue;
+        }
+    }
+    return patch;
+}
@@ -28,3 +28,38 @@ export function pasteTextSafely(target: TerminalPasteTarget, text: string): bool
     }
     return true;
 }
+
+export type PanePasteOutcome = "pasted" | "empty" | "declined";
+
+export interface PanePasteResult {
+    pasted: boolean;
+    needs_confirmation: boolean;
+    lines: number;
+}
+
+/** Backend paste (App.PasteToPane) plus the user prompt for held-back pastes. */
+export interface PanePasteClient {
+    pasteToPane(paneId: string, text: string, confirmed: boolean): Promise<PanePasteResult>;
+    confirmPaste(lines: number): boolean;
+}
+
+/**
+ * Pastes normalized text through the backend, which strips control
+ * characters and applies bracketed paste. When the backend holds a
+ * multi-line shell paste back, the user is asked before it is resent with
+ * confirmation.
+ */
+export async function pasteToPaneSafely(client: PanePasteClient, paneId: string, text: string): Promise<PanePasteOutcome> {
+    const normalized = normalizeTerminalPasteText(text);
+    if (normalized.length === 0) {
+        return "empty";
+    }
+    let result = await client.pasteToPane(paneId, normalized, false);
+    if (result.needs_confirmation) {
+        if (!client.confirmPaste(result.lines)) {
+            return "declined";
+        }
+        result = await client.pasteToPane(paneId, normalized, true);
+    }
+    return result.pasted ? "pasted" : "empty";
+}
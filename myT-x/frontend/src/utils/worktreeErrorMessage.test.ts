@@ -57,6 +57,17 @@ describe("formatWorktreeErrorMessage", () => {
         expect(result).toBe("HEAD の状態を確認できませんでした: fatal: bad revision");
     });
 
+    it("localizes rebase conflicts before promotion", () => {
+        const result = formatWorktreeErrorMessage(
+            "rebase onto origin/main conflicted, HEAD was restored: a.go, b.go",
+            enContext,
+            "ブランチへの昇格に失敗しました。",
+            "Failed to promote the worktree to a branch.",
+        );
+
+        expect(result).toBe("Rebasing onto origin/main conflicted, so it was aborted and HEAD was restored: a.go, b.go");
+    });
+
     it("preserves unknown backend detail", () => {
         const result = formatWorktreeErrorMessage(
             "unexpected backend failure",
@@ -143,6 +143,16 @@ export function formatWorktreeErrorMessage(
             {error: detail || raw},
         );
     }
+    const rebaseConflict = /^rebase onto (\S+) conflicted, HEAD was restored: (.+)$/.exec(raw);
+    if (rebaseConflict) {
+        return localize(
+            ctx,
+            "worktree.error.promoteRebaseConflict",
+            "{base} への rebase が競合したため中止し、HEAD を元に戻しました: {files}",
+            "Rebasing onto {base} conflicted, so it was aborted and HEAD was restored: {files}",
+            {base: rebaseConflict[1], files: rebaseConflict[2]},
+        );
+    }
     if (raw.includes(" is not a detached worktree")) {
         return localize(
             ctx,
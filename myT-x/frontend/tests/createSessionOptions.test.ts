@@ -14,6 +14,8 @@ describe("buildCreateSessionWithWorktreeOptions", () => {
             useClaudeEnv: true,
             usePaneEnv: false,
             useSessionPaneScope: true,
+            isolateShellHistory: true,
+            ephemeral: true,
         });
 
         expect(payload).toEqual({
@@ -25,6 +27,8 @@ describe("buildCreateSessionWithWorktreeOptions", () => {
             use_claude_env: true,
             use_pane_env: false,
             use_session_pane_scope: true,
+            isolate_shell_history: true,
+            ephemeral: true,
         });
     });
 });
@@ -16,6 +16,7 @@ describe("settingsReducer INITIAL_FORM guard", () => {
             "defaultSessionDir",
             "effortLevel",
             "error",
+            "focusFollowsActivity",
             "globalHotkey",
             "keys",
             "loadFailed",
@@ -23,22 +24,29 @@ describe("settingsReducer INITIAL_FORM guard", () => {
             "mcpServers",
             "mcpServersLoaded",
             "minOverrideNameLen",
+            "mouse",
+            "outputFolding",
             "overrides",
             "paneEnvDefaultEnabled",
             "paneEnvEntries",
+            "pasteConfirm",
             "prefix",
             "quakeMode",
             "saving",
             "shell",
+            "shellHistoryIsolationDefaultEnabled",
             "taskScheduler",
             "validationErrors",
             "viewerSidebarMode",
             "viewerShortcuts",
             "websocketPort",
+            "wtAllowShared",
             "wtCopyDirs",
+            "wtCopyFilesEOL",
             "wtCopyFiles",
             "wtEnabled",
             "wtForceCleanup",
+            "wtMergeTool",
             "wtSetupScripts",
             "wtSetupScriptTimeoutSeconds",
         ].sort());
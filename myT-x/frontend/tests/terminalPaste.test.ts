@@ -1,5 +1,5 @@
 import {describe, expect, it, vi} from "vitest";
-import {normalizeTerminalPasteText, pasteTextSafely} from "../src/utils/terminalPaste";
+import {normalizeTerminalPasteText, pasteTextSafely, pasteToPaneSafely} from "../src/utils/terminalPaste";
 
 describe("normalizeTerminalPasteText", () => {
     it("keeps plain single-line text unchanged", () => {
@@ -68,3 +68,53 @@ describe("pasteTextSafely", () => {
         expect(target.paste).toHaveBeenCalledWith("hello");
     });
 });
+
+describe("pasteToPaneSafely", () => {
+    const result = (overrides: Partial<{pasted: boolean; needs_confirmation: boolean; lines: number}> = {}) => ({
+        pasted: true,
+        needs_confirmation: false,
+        lines: 1,
+        ...overrides,
+    });
+
+    it("pastes normalized text through the backend", async () => {
+        const client = {pasteToPane: vi.fn(async () => result()), confirmPaste: vi.fn(() => true)};
+
+        await expect(pasteToPaneSafely(client, "%1", "echo hi\n")).resolves.toBe("pasted");
+
+        expect(client.pasteToPane).toHaveBeenCalledWith("%1", "echo hi", false);
+        expect(client.confirmPaste).not.toHaveBeenCalled();
+    });
+
+    it("resends with confirmation when the user accepts", async () => {
+        const client = {
+            pasteToPane: vi.fn(async (_paneId: string, _text: string, confirmed: boolean) =>
+                confirmed ? result() : result({pasted: false, needs_confirmation: true, lines: 2})),
+            confirmPaste: vi.fn(() => true),
+        };
+
+        await expect(pasteToPaneSafely(client, "%1", "a\nb")).resolves.toBe("pasted");
+
+        expect(client.confirmPaste).toHaveBeenCalledWith(2);
+        expect(client.pasteToPane).toHaveBeenLastCalledWith("%1", "a\nb", true);
+    });
+
+    it("does not resend when the user declines", async () => {
+        const client = {
+            pasteToPane: vi.fn(async () => result({pasted: false, needs_confirmation: true, lines: 2})),
+            confirmPaste: vi.fn(() => false),
+        };
+
+        await expect(pasteToPaneSafely(client, "%1", "a\nb")).resolves.toBe("declined");
+
+        expect(client.pasteToPane).toHaveBeenCalledTimes(1);
+    });
+
+    it("skips the backend for an empty paste", async () => {
+        const client = {pasteToPane: vi.fn(async () => result()), confirmPaste: vi.fn(() => true)};
+
+        await expect(pasteToPaneSafely(client, "%1", "\n")).resolves.toBe("empty");
+
+        expect(client.pasteToPane).not.toHaveBeenCalled();
+    });
+});
@@ -0,0 +1,105 @@
+import {act} from "react";
+import {createRoot, type Root} from "react-dom/client";
+import {afterEach, beforeEach, describe, expect, it, vi} from "vitest";
+import {useCommandApprovalSync} from "../src/hooks/sync/useCommandApprovalSync";
+import {useCommandApprovalStore} from "../src/stores/commandApprovalStore";
+
+const runtimeMock = vi.hoisted(() => ({
+    EventsOn: vi.fn(),
+}));
+
+const apiMock = vi.hoisted(() => ({
+    GetPendingCommandApprovals: vi.fn<() => Promise<unknown[]>>(),
+}));
+
+vi.mock("../wailsjs/runtime/runtime", () => runtimeMock);
+
+vi.mock("../src/api", () => ({
+    api: {
+        GetPendingCommandApprovals: () => apiMock.GetPendingCommandApprovals(),
+    },
+}));
+
+function CommandApprovalSyncProbe() {
+    useCommandApprovalSync();
+    return null;
+}
+
+async function flushEffects(): Promise<void> {
+    await act(async () => {
+        await Promise.resolve();
+        await Promise.resolve();
+    });
+}
+
+describe("useCommandApprovalSync", () => {
+    let container: HTMLDivElement;
+    let root: Root;
+    let eventHandlers: Map<string, (payload: unknown) => void>;
+
+    beforeEach(() => {
+        eventHandlers = new Map<string, (payload: unknown) => void>();
+        runtimeMock.EventsOn.mockImplementation((eventName: string, handler: (payload: unknown) => void) => {
+            eventHandlers.set(eventName, handler);
+            return () => {
+                eventHandlers.delete(eventName);
+            };
+        });
+        apiMock.GetPendingCommandApprovals.mockReset();
+        apiMock.GetPendingCommandApprovals.mockResolvedValue([]);
+        useCommandApprovalStore.setState({pending: [], enabledSessions: {}});
+
+        container = document.createElement("div");
+        document.body.appendChild(container);
+        root = createRoot(container);
+        (globalThis as { IS_REACT_ACT_ENVIRONMENT?: boolean }).IS_REACT_ACT_ENVIRONMENT = true;
+    });
+
+    afterEach(() => {
+        act(() => {
+            root.unmount();
+        });
+        container.remove();
+        vi.restoreAllMocks();
+        (globalThis as { IS_REACT_ACT_ENVIRONMENT?: boolean }).IS_REACT_ACT_ENVIRONMENT = false;
+    });
+
+    it("seeds pending approvals held before mount", async () => {
+        apiMock.GetPendingCommandApprovals.mockResolvedValue([
+            {id: "approval-1", session_name: "agent", pane_id: "%1", command: "kill-pane", command_line: "kill-pane -t %2"},
+            {session_name: "invalid"},
+        ]);
+
+        act(() => {
+            root.render(<CommandApprovalSyncProbe/>);
+        });
+        await flushEffects();
+
+        const pending = useCommandApprovalStore.getState().pending;
+        expect(pending).toHaveLength(1);
+        expect(pending[0]).toMatchObject({id: "approval-1", sessionName: "agent", commandLine: "kill-pane -t %2"});
+    });
+
+    it("adds held commands and removes them when resolved", async () => {
+        act(() => {
+            root.render(<CommandApprovalSyncProbe/>);
+        });
+        await flushEffects();
+
+        act(() => {
+            eventHandlers.get("session:approval-pending")?.({
+                id: "approval-2",
+                session_name: "agent",
+                pane_id: "%1",
+                command: "send-keys",
+                command_line: "send-keys -t %3 ls Enter",
+            });
+        });
+        expect(useCommandApprovalStore.getState().pending.map((item) => item.id)).toEqual(["approval-2"]);
+
+        act(() => {
+            eventHandlers.get("session:approval-resolved")?.({id: "approval-2", decision: "allow", reason: "user"});
+        });
+        expect(useCommandApprovalStore.getState().pending).toHaveLength(0);
+    });
+});
@@ -4,6 +4,7 @@ import {afterEach, beforeEach, describe, expect, it, vi} from "vitest";
 import {useSnapshotSync} from "../src/hooks/sync/useSnapshotSync";
 import {useCanvasStore} from "../src/stores/canvasStore";
 import {useDiffReviewStore} from "../src/stores/diffReviewStore";
+import {useGitStatusStore} from "../src/stores/gitStatusStore";
 import {useMCPStore} from "../src/stores/mcpStore";
 import {useNotificationStore} from "../src/stores/notificationStore";
 import {buildSessionMemoDraftKey, useSessionMemoStore} from "../src/stores/sessionMemoStore";
@@ -21,8 +22,10 @@ const paneDataStreamMock = vi.hoisted(() => ({
 
 const apiMock = vi.hoisted(() => ({
     GetActiveSession: vi.fn<() => Promise<string>>(),
+    GetGitStatuses: vi.fn<() => Promise<Record<string, unknown>>>(),
     GetWebSocketURL: vi.fn<() => Promise<string>>(),
     ListSessions: vi.fn<() => Promise<unknown[]>>(),
+    NegotiateSnapshotEncoding: vi.fn<(accepted: string[]) => Promise<string[]>>(),
 }));
 
 vi.mock("../wailsjs/runtime/runtime", () => runtimeMock);
@@ -30,8 +33,10 @@ vi.mock("../wailsjs/runtime/runtime", () => runtimeMock);
 vi.mock("../src/api", () => ({
     api: {
         GetActiveSession: () => apiMock.GetActiveSession(),
+        GetGitStatuses: () => apiMock.GetGitStatuses(),
         GetWebSocketURL: () => apiMock.GetWebSocketURL(),
         ListSessions: () => apiMock.ListSessions(),
+        NegotiateSnapshotEncoding: (accepted: string[]) => apiMock.NegotiateSnapshotEncoding(accepted),
     },
 }));
 
@@ -58,6 +63,31 @@ async function flushEffects(): Promise<void> {
     });
 }
 
+// Snapshot events are applied through a promise chain; let it drain.
+async function flushSnapshotQueue(): Promise<void> {
+    await act(async () => {
+        await new Promise((resolve) => setTimeout(resolve, 0));
+    });
+}
+
+const compactTestSession = {
+    id: 1,
+    name: "s1",
+    created_at: "",
+    is_idle: false,
+    active_window_id: 0,
+    windows: [{
+        id: 0,
+        name: "main",
+        layout: {type: "leaf", pane_id: 0},
+        active_pane: 0,
+        panes: [
+            {id: "%0", index: 0, title: "agent", active: true, width: 80, height: 24},
+            {id: "%1", index: 1, active: false, width: 80, height: 24},
+        ],
+    }],
+};
+
 describe("useSnapshotSync", () => {
     let container: HTMLDivElement;
     let root: Root;
@@ -76,8 +106,13 @@ describe("useSnapshotSync", () => {
         apiMock.ListSessions.mockResolvedValue([]);
         apiMock.GetActiveSession.mockReset();
         apiMock.GetActiveSession.mockResolvedValue("");
+        apiMock.GetGitStatuses.mockReset();
+        apiMock.GetGitStatuses.mockResolvedValue({});
+        useGitStatusStore.setState({...useGitStatusStore.getState(), statuses: {}}, true);
         apiMock.GetWebSocketURL.mockReset();
         apiMock.GetWebSocketURL.mockResolvedValue("");
+        apiMock.NegotiateSnapshotEncoding.mockReset();
+        apiMock.NegotiateSnapshotEncoding.mockImplementation(async (accepted) => accepted);
         paneDataStreamMock.connect.mockReset();
         paneDataStreamMock.disconnect.mockReset();
         vi.spyOn(console, "warn").mockImplementation(() => undefined);
@@ -154,6 +189,73 @@ describe("useSnapshotSync", () => {
         expect(notification?.message).toContain("fatal: remote update rejected");
     });
 
+    it("adds a warning notification when a shared worktree switches branch", async () => {
+        act(() => {
+            root.render(<SnapshotSyncProbe/>);
+        });
+        await flushEffects();
+
+        const handler = eventHandlers.get("worktree:branch-conflict");
+        expect(handler).toBeTypeOf("function");
+
+        act(() => {
+            handler?.({
+                worktree_path: "C:\\wt",
+                session_name: "api",
+                expected_branch: "main",
+                actual_branch: "feature",
+                shared_with: ["web"],
+            });
+        });
+
+        const [notification] = useNotificationStore.getState().notifications;
+        expect(notification?.level).toBe("warn");
+        expect(notification?.message).toContain("feature");
+        expect(notification?.message).toContain("api");
+        expect(notification?.message).toContain("web");
+    });
+
+    it("warns when a GPU driver reset arms software rendering", async () => {
+        act(() => {
+            root.render(<SnapshotSyncProbe/>);
+        });
+        await flushEffects();
+
+        const handler = eventHandlers.get("rendering:fallback-armed");
+        expect(handler).toBeTypeOf("function");
+
+        act(() => {
+            handler?.({
+                reason: "driver_reset",
+                driver_resets: [{time: "2026-05-16T10:05:00Z", provider: "Display", event_id: 4101}],
+            });
+        });
+
+        const [notification] = useNotificationStore.getState().notifications;
+        expect(notification?.level).toBe("warn");
+        expect(notification?.message).toContain("1");
+    });
+
+    it("seeds git statuses and follows git:status-changed", async () => {
+        apiMock.GetGitStatuses.mockResolvedValueOnce({api: {branch: "main", ahead: 1}});
+        act(() => {
+            root.render(<SnapshotSyncProbe/>);
+        });
+        await flushEffects();
+        expect(useGitStatusStore.getState().statuses.api?.ahead).toBe(1);
+
+        const handler = eventHandlers.get("git:status-changed");
+        expect(handler).toBeTypeOf("function");
+        act(() => {
+            handler?.({session_name: "web", status: {branch: "dev", dirty: 2}});
+            handler?.({session_name: "api", status: null});
+        });
+
+        const {statuses} = useGitStatusStore.getState();
+        expect(Object.keys(statuses)).toEqual(["web"]);
+        expect(statuses.web?.dirty).toBe(2);
+    });
+
     it("ignores malformed worktree pull failure payloads", async () => {
         act(() => {
             root.render(<SnapshotSyncProbe/>);
@@ -273,6 +375,75 @@ describe("useSnapshotSync", () => {
         expect(useSessionMemoStore.getState().drafts[buildSessionMemoDraftKey("renamed-session", 7)]?.content).toBe("memo draft");
     });
 
+    it("negotiates snapshot encodings on mount", async () => {
+        act(() => {
+            root.render(<SnapshotSyncProbe/>);
+        });
+        await flushEffects();
+
+        expect(apiMock.NegotiateSnapshotEncoding).toHaveBeenCalledTimes(1);
+        expect(apiMock.NegotiateSnapshotEncoding.mock.calls[0]?.[0]).toContain("compact-delta");
+    });
+
+    it("hydrates compact snapshot deltas from the current sessions", async () => {
+        act(() => {
+            root.render(<SnapshotSyncProbe/>);
+        });
+        await flushEffects();
+        await flushSnapshotQueue();
+        useTmuxStore.getState().setSessions([compactTestSession]);
+
+        act(() => {
+            eventHandlers.get("tmux:snapshot-delta")?.({
+                upserts: [{
+                    ...compactTestSession,
+                    is_idle: true,
+                    windows: [{
+                        ...compactTestSession.windows[0],
+                        layout: null,
+                        layout_unchanged: true,
+                        panes: [
+                            {id: "%0", unchanged: true},
+                            {id: "%1", index: 1, title: "renamed", active: false, width: 80, height: 24},
+                        ],
+                    }],
+                }],
+                removed: [],
+            });
+        });
+        await flushSnapshotQueue();
+
+        const session = useTmuxStore.getState().sessions[0];
+        expect(session?.is_idle).toBe(true);
+        expect(session?.windows[0]?.layout).toEqual({type: "leaf", pane_id: 0});
+        expect(session?.windows[0]?.panes[0]).toEqual(compactTestSession.windows[0].panes[0]);
+        expect(session?.windows[0]?.panes[1]?.title).toBe("renamed");
+        expect(apiMock.ListSessions).toHaveBeenCalledTimes(1);
+    });
+
+    it("fetches the full session list when a compact delta references an unknown pane", async () => {
+        act(() => {
+            root.render(<SnapshotSyncProbe/>);
+        });
+        await flushEffects();
+        await flushSnapshotQueue();
+        apiMock.ListSessions.mockResolvedValueOnce([compactTestSession]);
+
+        act(() => {
+            eventHandlers.get("tmux:snapshot-delta")?.({
+                upserts: [{
+                    ...compactTestSession,
+                    windows: [{...compactTestSession.windows[0], panes: [{id: "%0", unchanged: true}]}],
+                }],
+                removed: [],
+            });
+        });
+        await flushSnapshotQueue();
+
+        expect(apiMock.ListSessions).toHaveBeenCalledTimes(2);
+        expect(useTmuxStore.getState().sessions).toEqual([compactTestSession]);
+    });
+
     it("clears diff review state when tmux:session-destroyed arrives", async () => {
         apiMock.ListSessions.mockResolvedValueOnce([
             {id: 7, name: "old-session", created_at: "", is_idle: false, active_window_id: 1, windows: []},
@@ -9,6 +9,7 @@ const writeClipboardTextMock = vi.hoisted(() => vi.fn<(text: string) => Promise<
 const notifyAndLogMock = vi.hoisted(() => vi.fn());
 const shouldRecoverTerminalFocusMock = vi.hoisted(() => vi.fn(() => false));
 const pasteTextSafelyMock = vi.hoisted(() => vi.fn(() => true));
+const pasteToPaneSafelyMock = vi.hoisted(() => vi.fn(() => Promise.resolve("pasted")));
 const resolveActivePaneIDMock = vi.hoisted(() => vi.fn(() => "pane-1"));
 const tmuxStoreStateMock = vi.hoisted(() => vi.fn(() => ({
     activeSession: "session-1",
@@ -44,6 +45,7 @@ vi.mock("../src/utils/terminalFocus", () => ({
 
 vi.mock("../src/utils/terminalPaste", () => ({
     pasteTextSafely: (...args: unknown[]) => pasteTextSafelyMock(...args),
+    pasteToPaneSafely: (...args: unknown[]) => pasteToPaneSafelyMock(...args),
 }));
 
 vi.mock("../src/utils/session", () => ({
@@ -17,39 +17,101 @@ import {install} from '../models';
 import {git} from '../models';
 import {promptpresets} from '../models';
 import {ipc} from '../models';
+import {cmdqueue} from '../models';
+import {bringup} from '../models';
+import {layoutpreset} from '../models';
+import {sessionports} from '../models';
+import {repostats} from '../models';
+import {repoconfig} from '../models';
+import {preopsnapshot} from '../models';
+import {maintenance} from '../models';
+import {cmdapproval} from '../models';
+import {recentdirs} from '../models';
+import {outputwatch} from '../models';
+import {forge} from '../models';
+import {sessionquery} from '../models';
+import {session} from '../models';
+import {rendering} from '../models';
+import {sessiongroup} from '../models';
+import {paneactivity} from '../models';
+import {eventsub} from '../models';
+import {resourcelimit} from '../models';
+import {ephemeral} from '../models';
+import {sessioncopy} from '../models';
+import {netcapture} from '../models';
+
+export function AcknowledgeChangelog():Promise<void>;
 
 export function AddMemberToUnaffiliatedTeam(arg1:orchestrator.TeamMember,arg2:string,arg3:string):Promise<void>;
 
+export function AddOutputWatch(arg1:string,arg2:string,arg3:string):Promise<outputwatch.Watch>;
+
+export function AddSessionToGroup(arg1:string,arg2:string):Promise<void>;
+
 export function AddSingleTaskRunnerItem(arg1:string,arg2:string,arg3:string,arg4:string,arg5:boolean,arg6:string):Promise<void>;
 
 export function AddTaskSchedulerItem(arg1:string,arg2:string,arg3:string,arg4:string,arg5:boolean,arg6:string):Promise<void>;
 
+export function AdoptExternalWorktree(arg1:string,arg2:string,arg3:string,arg4:main.CreateSessionOptions):Promise<tmux.SessionSnapshot>;
+
+export function ApplyConfigPatch(arg1:Record<string, any>):Promise<main.ConfigPatchResult>;
+
 export function ApplyLayoutPreset(arg1:string,arg2:string):Promise<void>;
 
 export function BootstrapMemberToPane(arg1:orchestrator.BootstrapMemberToPaneRequest):Promise<orchestrator.BootstrapMemberToPaneResult>;
 
+export function BringUpSessions(arg1:Array<string>):Promise<bringup.Status>;
+
+export function BrowseForDirectory(arg1:string):Promise<string>;
+
 export function BuildStatusLine():Promise<string>;
 
+export function CancelBringUp():Promise<void>;
+
+export function CancelCommandQueue(arg1:string):Promise<void>;
+
 export function CheckDirectoryConflict(arg1:string):Promise<string>;
 
 export function CheckTaskSchedulerOrchestratorReady(arg1:string):Promise<main.TaskSchedulerOrchestratorReadiness>;
 
+export function CheckWorktreeBase(arg1:string):Promise<worktree.BaseBranchStatus>;
+
 export function CheckWorktreePathConflict(arg1:string):Promise<string>;
 
 export function CheckWorktreeStatus(arg1:string):Promise<worktree.WorktreeStatus>;
 
+export function CleanupRepoHygiene(arg1:string,arg2:worktree.RepoHygieneCleanup):Promise<worktree.RepoHygieneCleanupResult>;
+
 export function CleanupWorktree(arg1:string):Promise<void>;
 
-export function CommitAndPushWorktree(arg1:string,arg2:string,arg3:boolean):Promise<void>;
+export function ClearNetworkCaptures(arg1:string):Promise<void>;
+
+export function CollapseIdlePanes(arg1:string):Promise<Array<string>>;
+
+export function CollapsePane(arg1:string):Promise<void>;
+
+export function CommitAndPushWorktree(arg1:string,arg2:string,arg3:boolean):Promise<worktree.CommitAndPushResult>;
+
+export function CopyBetweenSessions(arg1:string,arg2:string,arg3:string,arg4:string):Promise<sessioncopy.Result>;
+
+export function CreateBugReport():Promise<main.BugReportResult>;
 
 export function CreatePaneInSession(arg1:string):Promise<string>;
 
+export function CreatePullRequestForSession(arg1:string,arg2:worktree.CreatePullRequestOptions):Promise<forge.PullRequest>;
+
 export function CreateSession(arg1:string,arg2:string,arg3:main.CreateSessionOptions):Promise<tmux.SessionSnapshot>;
 
+export function CreateSessionFromTemplate(arg1:string,arg2:string):Promise<tmux.SessionSnapshot>;
+
+export function CreateSessionGroup(arg1:string,arg2:Array<sessiongroup.Member>):Promise<Array<tmux.SessionSnapshot>>;
+
 export function CreateSessionWithExistingWorktree(arg1:string,arg2:string,arg3:string,arg4:main.CreateSessionOptions):Promise<tmux.SessionSnapshot>;
 
 export function CreateSessionWithWorktree(arg1:string,arg2:string,arg3:worktree.WorktreeSessionOptions):Promise<tmux.SessionSnapshot>;
 
+export function DeleteLayoutPreset(arg1:string,arg2:string,arg3:boolean):Promise<void>;
+
 export function DeleteOrchestratorTeam(arg1:string,arg2:string,arg3:string):Promise<void>;
 
 export function DeletePromptPreset(arg1:string,arg2:string,arg3:string):Promise<void>;
@@ -120,40 +182,90 @@ export function DevPanelWriteFile(arg1:string,arg2:string,arg3:string):Promise<d
 
 export function EnlistPane(arg1:orchestrator.EnlistPaneRequest):Promise<orchestrator.EnlistPaneResult>;
 
+export function EnqueueCommands(arg1:string,arg2:Array<string>,arg3:cmdqueue.Options):Promise<cmdqueue.QueueStatus>;
+
 export function EnsureUnaffiliatedTeam(arg1:string,arg2:string):Promise<orchestrator.TeamDefinition>;
 
+export function ExpandPane(arg1:string):Promise<void>;
+
+export function ExtendEphemeralSession(arg1:string,arg2:number):Promise<ephemeral.Entry>;
+
+export function FocusNextActiveSession():Promise<string>;
+
 export function FocusPane(arg1:string):Promise<void>;
 
+export function GenerateActivityDigest():Promise<string>;
+
 export function GetActiveSession():Promise<string>;
 
 export function GetAllowedShells():Promise<Array<string>>;
 
+export function GetBringUpStatus():Promise<bringup.Status>;
+
+export function GetChangelog():Promise<main.ChangelogPayload>;
+
 export function GetClaudeEnvVarDescriptions():Promise<Record<string, string>>;
 
+export function GetCommandQueue(arg1:string):Promise<cmdqueue.QueueStatus>;
+
 export function GetConfig():Promise<config.Config>;
 
 export function GetConfigAndFlushWarnings():Promise<config.Config>;
 
+export function GetConfigPolicy():Promise<config.PolicyInfo>;
+
 export function GetCurrentBranch(arg1:string):Promise<string>;
 
+export function GetDirectoryTrust(arg1:string):Promise<repoconfig.Status>;
+
+export function GetGitStatuses():Promise<Record<string, git.WorkingTreeStatus>>;
+
 export function GetInputHistory():Promise<Array<inputhistory.Entry>>;
 
 export function GetInputHistoryFilePath():Promise<string>;
 
 export function GetInputHistoryForSession(arg1:string):Promise<inputhistory.Snapshot>;
 
+export function GetLogLevel():Promise<string>;
+
 export function GetMCPDetail(arg1:string,arg2:string):Promise<mcp.Snapshot>;
 
+export function GetMaintenanceJobs():Promise<Array<maintenance.JobStatus>>;
+
+export function GetMetrics():Promise<main.AppMetrics>;
+
+export function GetMetricsURL():Promise<string>;
+
+export function GetNetworkCaptureStatus(arg1:string):Promise<netcapture.Status>;
+
 export function GetOrchestratorTaskDetail(arg1:string,arg2:string):Promise<main.OrchestratorTaskDetail>;
 
+export function GetPaneActivity():Promise<Array<paneactivity.Activity>>;
+
 export function GetPaneEnv(arg1:string):Promise<Record<string, string>>;
 
 export function GetPaneProcessStatus(arg1:string):Promise<Array<main.PaneProcessStatus>>;
 
 export function GetPaneReplay(arg1:string):Promise<string>;
 
+export function GetPaneStreamURL():Promise<string>;
+
+export function GetPendingCommandApprovals():Promise<Array<cmdapproval.PendingCommand>>;
+
+export function GetPreOpSnapshots(arg1:string):Promise<Array<preopsnapshot.Snapshot>>;
+
+export function GetRenderingDiagnostics():Promise<rendering.Diagnostics>;
+
+export function GetRepoHygiene(arg1:string):Promise<worktree.RepoHygiene>;
+
+export function GetRepoStats(arg1:string):Promise<repostats.RepoStats>;
+
 export function GetSchedulerStatuses():Promise<Array<scheduler.EntryStatus>>;
 
+export function GetScreenSyncURL():Promise<string>;
+
+export function GetSessionApprovalMode(arg1:string):Promise<cmdapproval.SessionApproval>;
+
 export function GetSessionEnlistmentContext(arg1:string):Promise<orchestrator.SessionEnlistmentContext>;
 
 export function GetSessionEnv(arg1:string):Promise<Record<string, string>>;
@@ -162,6 +274,12 @@ export function GetSessionErrorLog():Promise<Array<sessionlog.Entry>>;
 
 export function GetSessionLogFilePath():Promise<string>;
 
+export function GetSessionPorts(arg1:string):Promise<Array<sessionports.Port>>;
+
+export function GetSessionResourceUsage(arg1:string):Promise<resourcelimit.SessionUsage>;
+
+export function GetSessionToolPaths(arg1:string):Promise<main.SessionToolPaths>;
+
 export function GetSingleTaskRunnerClearDelay(arg1:string):Promise<number>;
 
 export function GetSingleTaskRunnerStatus(arg1:string):Promise<singletaskrunner.QueueStatus>;
@@ -176,26 +294,46 @@ export function GetValidationRules():Promise<main.ValidationRules>;
 
 export function GetWebSocketURL():Promise<string>;
 
+export function HandleFileDrop(arg1:string,arg2:Array<string>,arg3:string,arg4:boolean):Promise<main.FileDropResult>;
+
 export function InstallTmuxShim():Promise<install.ShimInstallResult>;
 
 export function IsAgentTeamsAvailable():Promise<boolean>;
 
 export function IsGitRepository(arg1:string):Promise<boolean>;
 
+export function KeepEphemeralSession(arg1:string):Promise<void>;
+
 export function KillPane(arg1:string):Promise<void>;
 
 export function KillSession(arg1:string,arg2:boolean):Promise<void>;
 
+export function KillSessionGroup(arg1:string,arg2:boolean):Promise<void>;
+
 export function ListBranches(arg1:string):Promise<Array<string>>;
 
+export function ListEphemeralSessions():Promise<Array<ephemeral.Entry>>;
+
+export function ListExternalWorktrees(arg1:string):Promise<Array<worktree.ExternalWorktree>>;
+
+export function ListFeatureFlags():Promise<Array<main.FeatureFlagState>>;
+
+export function ListLayoutPresets(arg1:string):Promise<Array<layoutpreset.Preset>>;
+
 export function ListMCPServers(arg1:string):Promise<Array<mcp.Snapshot>>;
 
+export function ListNetworkCaptures(arg1:string,arg2:number):Promise<Array<netcapture.Record>>;
+
 export function ListOrchestratorAgents(arg1:string):Promise<Array<main.OrchestratorAgent>>;
 
 export function ListOrchestratorTasks(arg1:string):Promise<Array<main.OrchestratorTask>>;
 
 export function ListOrphanedWorktrees(arg1:string):Promise<Array<worktree.OrphanedWorktree>>;
 
+export function ListOutputWatches():Promise<Array<outputwatch.Watch>>;
+
+export function ListRecentDirectories():Promise<Array<recentdirs.Directory>>;
+
 export function ListSessions():Promise<Array<tmux.SessionSnapshot>>;
 
 export function ListWorktreesByRepo(arg1:string):Promise<Array<git.WorktreeInfo>>;
@@ -210,18 +348,40 @@ export function LoadSessionMemo(arg1:string):Promise<string>;
 
 export function LogFrontendEvent(arg1:string,arg2:string,arg3:string):Promise<void>;
 
+export function NegotiateSnapshotEncoding(arg1:Array<string>):Promise<Array<string>>;
+
 export function OpenDirectoryInExplorer(arg1:string):Promise<void>;
 
+export function OpenInMergeTool(sessionName:string,path:string):Promise<void>;
+
+export function PasteToPane(arg1:string,arg2:string,arg3:boolean):Promise<main.PasteResult>;
+
 export function PauseTaskScheduler(arg1:string):Promise<void>;
 
 export function PickSessionDirectory():Promise<string>;
 
-export function PromoteWorktreeToBranch(arg1:string,arg2:string):Promise<void>;
+export function PopWorktreeStash(arg1:string):Promise<worktree.WorktreeStashPopResult>;
+
+export function PromoteWorktreeToBranch(arg1:string,arg2:string,arg3:worktree.PromoteWorktreeOptions):Promise<void>;
+
+export function PruneOrphanedWorktrees(arg1:string,arg2:boolean):Promise<worktree.OrphanPruneResult>;
+
+export function QuerySessions(arg1:sessionquery.Filter,arg2:sessionquery.Sort,arg3:sessionquery.Page):Promise<session.QueryResult>;
 
 export function QuickStartSession():Promise<tmux.SessionSnapshot>;
 
 export function RecoverIMEWindowFocus():Promise<void>;
 
+export function RefreshSessionBadges():Promise<void>;
+
+export function RefreshWorktreeBase(arg1:string,arg2:string):Promise<worktree.BaseRefreshResult>;
+
+export function RelaunchApp():Promise<void>;
+
+export function RemoveOutputWatch(arg1:string):Promise<void>;
+
+export function RemoveRecentDirectory(arg1:string):Promise<void>;
+
 export function RemoveSingleTaskRunnerItem(arg1:string,arg2:string):Promise<void>;
 
 export function RemoveTaskSchedulerItem(arg1:string,arg2:string):Promise<void>;
@@ -240,14 +400,28 @@ export function ReorderTaskSchedulerItems(arg1:string,arg2:Array<string>):Promis
 
 export function ResizePane(arg1:string,arg2:number,arg3:number):Promise<void>;
 
+export function ResolveCommandApproval(arg1:string,arg2:string):Promise<void>;
+
 export function ResolveMCPStdio(arg1:string,arg2:string):Promise<ipc.MCPStdioResolvePayload>;
 
+export function RestartPipeServer():Promise<ipc.RestartReport>;
+
 export function ResumeScheduler(arg1:string):Promise<void>;
 
 export function ResumeTaskScheduler(arg1:string):Promise<void>;
 
+export function RunFindReplace(arg1:string,arg2:string,arg3:string,arg4:devpanel.FindReplaceOptions):Promise<devpanel.FindReplaceResult>;
+
+export function RunMaintenanceJob(arg1:string):Promise<void>;
+
+export function RunPathDoctor():Promise<install.PathDoctorReport>;
+
 export function SaveConfig(arg1:config.Config):Promise<void>;
 
+export function SaveGlobalLayoutPreset(arg1:string,arg2:string):Promise<void>;
+
+export function SaveLayoutPreset(arg1:string,arg2:string):Promise<void>;
+
 export function SaveOrchestratorTeam(arg1:orchestrator.TeamDefinition,arg2:string):Promise<void>;
 
 export function SavePromptPreset(arg1:promptpresets.PromptPreset,arg2:string):Promise<void>;
@@ -270,8 +444,32 @@ export function SendSyncInput(arg1:string,arg2:string):Promise<void>;
 
 export function SetActiveSession(arg1:string):Promise<void>;
 
+export function SetDirectoryTrust(arg1:string,arg2:string):Promise<void>;
+
+export function SetEventSubscriptions(arg1:eventsub.Spec):Promise<void>;
+
+export function SetFeatureFlag(arg1:string,arg2:boolean):Promise<void>;
+
+export function SetLogLevel(arg1:string):Promise<void>;
+
+export function SetNetworkCapture(arg1:string,arg2:boolean,arg3:boolean):Promise<netcapture.Status>;
+
+export function SetPaneMouse(arg1:string,arg2:string):Promise<void>;
+
+export function SetRecentDirectoryPinned(arg1:string,arg2:boolean):Promise<void>;
+
+export function SetSessionApprovalMode(arg1:string,arg2:boolean):Promise<void>;
+
+export function SetSessionBadge(arg1:string,arg2:string,arg3:string):Promise<void>;
+
+export function SetSessionTaskbarAlertsMuted(arg1:string,arg2:boolean):Promise<void>;
+
 export function SetSingleTaskRunnerClearDelay(arg1:string,arg2:number):Promise<void>;
 
+export function SetSoftwareRendering(arg1:boolean):Promise<void>;
+
+export function SetWorktreeLock(arg1:string,arg2:boolean,arg3:string):Promise<void>;
+
 export function SplitPane(arg1:string,arg2:boolean):Promise<string>;
 
 export function StartAutoStartCommand(arg1:string,arg2:config.AutoStartCommand):Promise<string>;
@@ -284,6 +482,8 @@ export function StartSingleTaskRunner(arg1:string):Promise<void>;
 
 export function StartTaskScheduler(arg1:string,arg2:taskscheduler.QueueConfig,arg3:Array<taskscheduler.QueueItem>):Promise<void>;
 
+export function StashWorktree(arg1:string,arg2:string):Promise<git.StashEntry>;
+
 export function StopAllSchedulers():Promise<void>;
 
 export function StopScheduler(arg1:string):Promise<void>;
@@ -294,6 +494,8 @@ export function StopTaskScheduler(arg1:string):Promise<void>;
 
 export function SwapPanes(arg1:string,arg2:string):Promise<void>;
 
+export function TearDownSessions(arg1:Array<string>):Promise<bringup.Status>;
+
 export function ToggleMCPServer(arg1:string,arg2:string,arg3:boolean):Promise<void>;
 
 export function ToggleViewerSidebarMode():Promise<void>;
@@ -2,10 +2,22 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function AcknowledgeChangelog() {
+  return window['go']['main']['App']['AcknowledgeChangelog']();
+}
+
 export function AddMemberToUnaffiliatedTeam(arg1, arg2, arg3) {
   return window['go']['main']['App']['AddMemberToUnaffiliatedTeam'](arg1, arg2, arg3);
 }
 
+export function AddOutputWatch(arg1, arg2, arg3) {
+  return window['go']['main']['App']['AddOutputWatch'](arg1, arg2, arg3);
+}
+
+export function AddSessionToGroup(arg1, arg2) {
+  return window['go']['main']['App']['AddSessionToGroup'](arg1, arg2);
+}
+
 export function AddSingleTaskRunnerItem(arg1, arg2, arg3, arg4, arg5, arg6) {
   return window['go']['main']['App']['AddSingleTaskRunnerItem'](arg1, arg2, arg3, arg4, arg5, arg6);
 }
@@ -14,6 +26,14 @@ export function AddTaskSchedulerItem(arg1, arg2, arg3, arg4, arg5, arg6) {
   return window['go']['main']['App']['AddTaskSchedulerItem'](arg1, arg2, arg3, arg4, arg5, arg6);
 }
 
+export function AdoptExternalWorktree(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['AdoptExternalWorktree'](arg1, arg2, arg3, arg4);
+}
+
+export function ApplyConfigPatch(arg1,  any>) {
+  return window['go']['main']['App']['ApplyConfigPatch'](arg1,  any>);
+}
+
 export function ApplyLayoutPreset(arg1, arg2) {
   return window['go']['main']['App']['ApplyLayoutPreset'](arg1, arg2);
 }
@@ -22,10 +42,26 @@ export function BootstrapMemberToPane(arg1) {
   return window['go']['main']['App']['BootstrapMemberToPane'](arg1);
 }
 
+export function BringUpSessions(arg1) {
+  return window['go']['main']['App']['BringUpSessions'](arg1);
+}
+
+export function BrowseForDirectory(arg1) {
+  return window['go']['main']['App']['BrowseForDirectory'](arg1);
+}
+
 export function BuildStatusLine() {
   return window['go']['main']['App']['BuildStatusLine']();
 }
 
+export function CancelBringUp() {
+  return window['go']['main']['App']['CancelBringUp']();
+}
+
+export function CancelCommandQueue(arg1) {
+  return window['go']['main']['App']['CancelCommandQueue'](arg1);
+}
+
 export function CheckDirectoryConflict(arg1) {
   return window['go']['main']['App']['CheckDirectoryConflict'](arg1);
 }
@@ -34,6 +70,10 @@ export function CheckTaskSchedulerOrchestratorReady(arg1) {
   return window['go']['main']['App']['CheckTaskSchedulerOrchestratorReady'](arg1);
 }
 
+export function CheckWorktreeBase(arg1) {
+  return window['go']['main']['App']['CheckWorktreeBase'](arg1);
+}
+
 export function CheckWorktreePathConflict(arg1) {
   return window['go']['main']['App']['CheckWorktreePathConflict'](arg1);
 }
@@ -42,22 +82,58 @@ export function CheckWorktreeStatus(arg1) {
   return window['go']['main']['App']['CheckWorktreeStatus'](arg1);
 }
 
+export function CleanupRepoHygiene(arg1, arg2) {
+  return window['go']['main']['App']['CleanupRepoHygiene'](arg1, arg2);
+}
+
 export function CleanupWorktree(arg1) {
   return window['go']['main']['App']['CleanupWorktree'](arg1);
 }
 
+export function ClearNetworkCaptures(arg1) {
+  return window['go']['main']['App']['ClearNetworkCaptures'](arg1);
+}
+
+export function CollapseIdlePanes(arg1) {
+  return window['go']['main']['App']['CollapseIdlePanes'](arg1);
+}
+
+export function CollapsePane(arg1) {
+  return window['go']['main']['App']['CollapsePane'](arg1);
+}
+
 export function CommitAndPushWorktree(arg1, arg2, arg3) {
   return window['go']['main']['App']['CommitAndPushWorktree'](arg1, arg2, arg3);
 }
 
+export function CopyBetweenSessions(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['CopyBetweenSessions'](arg1, arg2, arg3, arg4);
+}
+
+export function CreateBugReport() {
+  return window['go']['main']['App']['CreateBugReport']();
+}
+
 export function CreatePaneInSession(arg1) {
   return window['go']['main']['App']['CreatePaneInSession'](arg1);
 }
 
+export function CreatePullRequestForSession(arg1, arg2) {
+  return window['go']['main']['App']['CreatePullRequestForSession'](arg1, arg2);
+}
+
 export function CreateSession(arg1, arg2, arg3) {
   return window['go']['main']['App']['CreateSession'](arg1, arg2, arg3);
 }
 
+export function CreateSessionFromTemplate(arg1, arg2) {
+  return window['go']['main']['App']['CreateSessionFromTemplate'](arg1, arg2);
+}
+
+export function CreateSessionGroup(arg1, arg2) {
+  return window['go']['main']['App']['CreateSessionGroup'](arg1, arg2);
+}
+
 export function CreateSessionWithExistingWorktree(arg1, arg2, arg3, arg4) {
   return window['go']['main']['App']['CreateSessionWithExistingWorktree'](arg1, arg2, arg3, arg4);
 }
@@ -66,6 +142,10 @@ export function CreateSessionWithWorktree(arg1, arg2, arg3) {
   return window['go']['main']['App']['CreateSessionWithWorktree'](arg1, arg2, arg3);
 }
 
+export function DeleteLayoutPreset(arg1, arg2, arg3) {
+  return window['go']['main']['App']['DeleteLayoutPreset'](arg1, arg2, arg3);
+}
+
 export function DeleteOrchestratorTeam(arg1, arg2, arg3) {
   return window['go']['main']['App']['DeleteOrchestratorTeam'](arg1, arg2, arg3);
 }
@@ -206,14 +286,34 @@ export function EnlistPane(arg1) {
   return window['go']['main']['App']['EnlistPane'](arg1);
 }
 
+export function EnqueueCommands(arg1, arg2, arg3) {
+  return window['go']['main']['App']['EnqueueCommands'](arg1, arg2, arg3);
+}
+
 export function EnsureUnaffiliatedTeam(arg1, arg2) {
   return window['go']['main']['App']['EnsureUnaffiliatedTeam'](arg1, arg2);
 }
 
+export function ExpandPane(arg1) {
+  return window['go']['main']['App']['ExpandPane'](arg1);
+}
+
+export function ExtendEphemeralSession(arg1, arg2) {
+  return window['go']['main']['App']['ExtendEphemeralSession'](arg1, arg2);
+}
+
+export function FocusNextActiveSession() {
+  return window['go']['main']['App']['FocusNextActiveSession']();
+}
+
 export function FocusPane(arg1) {
   return window['go']['main']['App']['FocusPane'](arg1);
 }
 
+export function GenerateActivityDigest() {
+  return window['go']['main']['App']['GenerateActivityDigest']();
+}
+
 export function GetActiveSession() {
   return window['go']['main']['App']['GetActiveSession']();
 }
@@ -222,10 +322,22 @@ export function GetAllowedShells() {
   return window['go']['main']['App']['GetAllowedShells']();
 }
 
+export function GetBringUpStatus() {
+  return window['go']['main']['App']['GetBringUpStatus']();
+}
+
+export function GetChangelog() {
+  return window['go']['main']['App']['GetChangelog']();
+}
+
 export function GetClaudeEnvVarDescriptions() {
   return window['go']['main']['App']['GetClaudeEnvVarDescriptions']();
 }
 
+export function GetCommandQueue(arg1) {
+  return window['go']['main']['App']['GetCommandQueue'](arg1);
+}
+
 export function GetConfig() {
   return window['go']['main']['App']['GetConfig']();
 }
@@ -234,10 +346,22 @@ export function GetConfigAndFlushWarnings() {
   return window['go']['main']['App']['GetConfigAndFlushWarnings']();
 }
 
+export function GetConfigPolicy() {
+  return window['go']['main']['App']['GetConfigPolicy']();
+}
+
 export function GetCurrentBranch(arg1) {
   return window['go']['main']['App']['GetCurrentBranch'](arg1);
 }
 
+export function GetDirectoryTrust(arg1) {
+  return window['go']['main']['App']['GetDirectoryTrust'](arg1);
+}
+
+export function GetGitStatuses() {
+  return window['go']['main']['App']['GetGitStatuses']();
+}
+
 export function GetInputHistory() {
   return window['go']['main']['App']['GetInputHistory']();
 }
@@ -250,14 +374,38 @@ export function GetInputHistoryForSession(arg1) {
   return window['go']['main']['App']['GetInputHistoryForSession'](arg1);
 }
 
+export function GetLogLevel() {
+  return window['go']['main']['App']['GetLogLevel']();
+}
+
 export function GetMCPDetail(arg1, arg2) {
   return window['go']['main']['App']['GetMCPDetail'](arg1, arg2);
 }
 
+export function GetMaintenanceJobs() {
+  return window['go']['main']['App']['GetMaintenanceJobs']();
+}
+
+export function GetMetrics() {
+  return window['go']['main']['App']['GetMetrics']();
+}
+
+export function GetMetricsURL() {
+  return window['go']['main']['App']['GetMetricsURL']();
+}
+
+export function GetNetworkCaptureStatus(arg1) {
+  return window['go']['main']['App']['GetNetworkCaptureStatus'](arg1);
+}
+
 export function GetOrchestratorTaskDetail(arg1, arg2) {
   return window['go']['main']['App']['GetOrchestratorTaskDetail'](arg1, arg2);
 }
 
+export function GetPaneActivity() {
+  return window['go']['main']['App']['GetPaneActivity']();
+}
+
 export function GetPaneEnv(arg1) {
   return window['go']['main']['App']['GetPaneEnv'](arg1);
 }
@@ -270,10 +418,42 @@ export function GetPaneReplay(arg1) {
   return window['go']['main']['App']['GetPaneReplay'](arg1);
 }
 
+export function GetPaneStreamURL() {
+  return window['go']['main']['App']['GetPaneStreamURL']();
+}
+
+export function GetPendingCommandApprovals() {
+  return window['go']['main']['App']['GetPendingCommandApprovals']();
+}
+
+export function GetPreOpSnapshots(arg1) {
+  return window['go']['main']['App']['GetPreOpSnapshots'](arg1);
+}
+
+export function GetRenderingDiagnostics() {
+  return window['go']['main']['App']['GetRenderingDiagnostics']();
+}
+
+export function GetRepoHygiene(arg1) {
+  return window['go']['main']['App']['GetRepoHygiene'](arg1);
+}
+
+export function GetRepoStats(arg1) {
+  return window['go']['main']['App']['GetRepoStats'](arg1);
+}
+
 export function GetSchedulerStatuses() {
   return window['go']['main']['App']['GetSchedulerStatuses']();
 }
 
+export function GetScreenSyncURL() {
+  return window['go']['main']['App']['GetScreenSyncURL']();
+}
+
+export function GetSessionApprovalMode(arg1) {
+  return window['go']['main']['App']['GetSessionApprovalMode'](arg1);
+}
+
 export function GetSessionEnlistmentContext(arg1) {
   return window['go']['main']['App']['GetSessionEnlistmentContext'](arg1);
 }
@@ -290,6 +470,18 @@ export function GetSessionLogFilePath() {
   return window['go']['main']['App']['GetSessionLogFilePath']();
 }
 
+export function GetSessionPorts(arg1) {
+  return window['go']['main']['App']['GetSessionPorts'](arg1);
+}
+
+export function GetSessionResourceUsage(arg1) {
+  return window['go']['main']['App']['GetSessionResourceUsage'](arg1);
+}
+
+export function GetSessionToolPaths(arg1) {
+  return window['go']['main']['App']['GetSessionToolPaths'](arg1);
+}
+
 export function GetSingleTaskRunnerClearDelay(arg1) {
   return window['go']['main']['App']['GetSingleTaskRunnerClearDelay'](arg1);
 }
@@ -318,6 +510,10 @@ export function GetWebSocketURL() {
   return window['go']['main']['App']['GetWebSocketURL']();
 }
 
+export function HandleFileDrop(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['HandleFileDrop'](arg1, arg2, arg3, arg4);
+}
+
 export function InstallTmuxShim() {
   return window['go']['main']['App']['InstallTmuxShim']();
 }
@@ -330,6 +526,10 @@ export function IsGitRepository(arg1) {
   return window['go']['main']['App']['IsGitRepository'](arg1);
 }
 
+export function KeepEphemeralSession(arg1) {
+  return window['go']['main']['App']['KeepEphemeralSession'](arg1);
+}
+
 export function KillPane(arg1) {
   return window['go']['main']['App']['KillPane'](arg1);
 }
@@ -338,14 +538,38 @@ export function KillSession(arg1, arg2) {
   return window['go']['main']['App']['KillSession'](arg1, arg2);
 }
 
+export function KillSessionGroup(arg1, arg2) {
+  return window['go']['main']['App']['KillSessionGroup'](arg1, arg2);
+}
+
 export function ListBranches(arg1) {
   return window['go']['main']['App']['ListBranches'](arg1);
 }
 
+export function ListEphemeralSessions() {
+  return window['go']['main']['App']['ListEphemeralSessions']();
+}
+
+export function ListExternalWorktrees(arg1) {
+  return window['go']['main']['App']['ListExternalWorktrees'](arg1);
+}
+
+export function ListFeatureFlags() {
+  return window['go']['main']['App']['ListFeatureFlags']();
+}
+
+export function ListLayoutPresets(arg1) {
+  return window['go']['main']['App']['ListLayoutPresets'](arg1);
+}
+
 export function ListMCPServers(arg1) {
   return window['go']['main']['App']['ListMCPServers'](arg1);
 }
 
+export function ListNetworkCaptures(arg1, arg2) {
+  return window['go']['main']['App']['ListNetworkCaptures'](arg1, arg2);
+}
+
 export function ListOrchestratorAgents(arg1) {
   return window['go']['main']['App']['ListOrchestratorAgents'](arg1);
 }
@@ -358,6 +582,14 @@ export function ListOrphanedWorktrees(arg1) {
   return window['go']['main']['App']['ListOrphanedWorktrees'](arg1);
 }
 
+export function ListOutputWatches() {
+  return window['go']['main']['App']['ListOutputWatches']();
+}
+
+export function ListRecentDirectories() {
+  return window['go']['main']['App']['ListRecentDirectories']();
+}
+
 export function ListSessions() {
   return window['go']['main']['App']['ListSessions']();
 }
@@ -386,10 +618,22 @@ export function LogFrontendEvent(arg1, arg2, arg3) {
   return window['go']['main']['App']['LogFrontendEvent'](arg1, arg2, arg3);
 }
 
+export function NegotiateSnapshotEncoding(arg1) {
+  return window['go']['main']['App']['NegotiateSnapshotEncoding'](arg1);
+}
+
 export function OpenDirectoryInExplorer(arg1) {
   return window['go']['main']['App']['OpenDirectoryInExplorer'](arg1);
 }
 
+export function OpenInMergeTool(sessionName, path) {
+  return window['go']['main']['App']['OpenInMergeTool'](sessionName, path);
+}
+
+export function PasteToPane(arg1, arg2, arg3) {
+  return window['go']['main']['App']['PasteToPane'](arg1, arg2, arg3);
+}
+
 export function PauseTaskScheduler(arg1) {
   return window['go']['main']['App']['PauseTaskScheduler'](arg1);
 }
@@ -398,8 +642,20 @@ export function PickSessionDirectory() {
   return window['go']['main']['App']['PickSessionDirectory']();
 }
 
-export function PromoteWorktreeToBranch(arg1, arg2) {
-  return window['go']['main']['App']['PromoteWorktreeToBranch'](arg1, arg2);
+export function PopWorktreeStash(arg1) {
+  return window['go']['main']['App']['PopWorktreeStash'](arg1);
+}
+
+export function PromoteWorktreeToBranch(arg1, arg2, arg3) {
+  return window['go']['main']['App']['PromoteWorktreeToBranch'](arg1, arg2, arg3);
+}
+
+export function PruneOrphanedWorktrees(arg1, arg2) {
+  return window['go']['main']['App']['PruneOrphanedWorktrees'](arg1, arg2);
+}
+
+export function QuerySessions(arg1, arg2, arg3) {
+  return window['go']['main']['App']['QuerySessions'](arg1, arg2, arg3);
 }
 
 export function QuickStartSession() {
@@ -410,6 +666,26 @@ export function RecoverIMEWindowFocus() {
   return window['go']['main']['App']['RecoverIMEWindowFocus']();
 }
 
+export function RefreshSessionBadges() {
+  return window['go']['main']['App']['RefreshSessionBadges']();
+}
+
+export function RefreshWorktreeBase(arg1, arg2) {
+  return window['go']['main']['App']['RefreshWorktreeBase'](arg1, arg2);
+}
+
+export function RelaunchApp() {
+  return window['go']['main']['App']['RelaunchApp']();
+}
+
+export function RemoveOutputWatch(arg1) {
+  return window['go']['main']['App']['RemoveOutputWatch'](arg1);
+}
+
+export function RemoveRecentDirectory(arg1) {
+  return window['go']['main']['App']['RemoveRecentDirectory'](arg1);
+}
+
 export function RemoveSingleTaskRunnerItem(arg1, arg2) {
   return window['go']['main']['App']['RemoveSingleTaskRunnerItem'](arg1, arg2);
 }
@@ -446,10 +722,18 @@ export function ResizePane(arg1, arg2, arg3) {
   return window['go']['main']['App']['ResizePane'](arg1, arg2, arg3);
 }
 
+export function ResolveCommandApproval(arg1, arg2) {
+  return window['go']['main']['App']['ResolveCommandApproval'](arg1, arg2);
+}
+
 export function ResolveMCPStdio(arg1, arg2) {
   return window['go']['main']['App']['ResolveMCPStdio'](arg1, arg2);
 }
 
+export function RestartPipeServer() {
+  return window['go']['main']['App']['RestartPipeServer']();
+}
+
 export function ResumeScheduler(arg1) {
   return window['go']['main']['App']['ResumeScheduler'](arg1);
 }
@@ -458,10 +742,30 @@ export function ResumeTaskScheduler(arg1) {
   return window['go']['main']['App']['ResumeTaskScheduler'](arg1);
 }
 
+export function RunFindReplace(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['RunFindReplace'](arg1, arg2, arg3, arg4);
+}
+
+export function RunMaintenanceJob(arg1) {
+  return window['go']['main']['App']['RunMaintenanceJob'](arg1);
+}
+
+export function RunPathDoctor() {
+  return window['go']['main']['App']['RunPathDoctor']();
+}
+
 export function SaveConfig(arg1) {
   return window['go']['main']['App']['SaveConfig'](arg1);
 }
 
+export function SaveGlobalLayoutPreset(arg1, arg2) {
+  return window['go']['main']['App']['SaveGlobalLayoutPreset'](arg1, arg2);
+}
+
+export function SaveLayoutPreset(arg1, arg2) {
+  return window['go']['main']['App']['SaveLayoutPreset'](arg1, arg2);
+}
+
 export function SaveOrchestratorTeam(arg1, arg2) {
   return window['go']['main']['App']['SaveOrchestratorTeam'](arg1, arg2);
 }
@@ -506,10 +810,58 @@ export function SetActiveSession(arg1) {
   return window['go']['main']['App']['SetActiveSession'](arg1);
 }
 
+export function SetDirectoryTrust(arg1, arg2) {
+  return window['go']['main']['App']['SetDirectoryTrust'](arg1, arg2);
+}
+
+export function SetEventSubscriptions(arg1) {
+  return window['go']['main']['App']['SetEventSubscriptions'](arg1);
+}
+
+export function SetFeatureFlag(arg1, arg2) {
+  return window['go']['main']['App']['SetFeatureFlag'](arg1, arg2);
+}
+
+export function SetLogLevel(arg1) {
+  return window['go']['main']['App']['SetLogLevel'](arg1);
+}
+
+export function SetNetworkCapture(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SetNetworkCapture'](arg1, arg2, arg3);
+}
+
+export function SetPaneMouse(arg1, arg2) {
+  return window['go']['main']['App']['SetPaneMouse'](arg1, arg2);
+}
+
+export function SetRecentDirectoryPinned(arg1, arg2) {
+  return window['go']['main']['App']['SetRecentDirectoryPinned'](arg1, arg2);
+}
+
+export function SetSessionApprovalMode(arg1, arg2) {
+  return window['go']['main']['App']['SetSessionApprovalMode'](arg1, arg2);
+}
+
+export function SetSessionBadge(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SetSessionBadge'](arg1, arg2, arg3);
+}
+
+export function SetSessionTaskbarAlertsMuted(arg1, arg2) {
+  return window['go']['main']['App']['SetSessionTaskbarAlertsMuted'](arg1, arg2);
+}
+
 export function SetSingleTaskRunnerClearDelay(arg1, arg2) {
   return window['go']['main']['App']['SetSingleTaskRunnerClearDelay'](arg1, arg2);
 }
 
+export function SetSoftwareRendering(arg1) {
+  return window['go']['main']['App']['SetSoftwareRendering'](arg1);
+}
+
+export function SetWorktreeLock(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SetWorktreeLock'](arg1, arg2, arg3);
+}
+
 export function SplitPane(arg1, arg2) {
   return window['go']['main']['App']['SplitPane'](arg1, arg2);
 }
@@ -534,6 +886,10 @@ export function StartTaskScheduler(arg1, arg2, arg3) {
   return window['go']['main']['App']['StartTaskScheduler'](arg1, arg2, arg3);
 }
 
+export function StashWorktree(arg1, arg2) {
+  return window['go']['main']['App']['StashWorktree'](arg1, arg2);
+}
+
 export function StopAllSchedulers() {
   return window['go']['main']['App']['StopAllSchedulers']();
 }
@@ -554,6 +910,10 @@ export function SwapPanes(arg1, arg2) {
   return window['go']['main']['App']['SwapPanes'](arg1, arg2);
 }
 
+export function TearDownSessions(arg1) {
+  return window['go']['main']['App']['TearDownSessions'](arg1);
+}
+
 export function ToggleMCPServer(arg1, arg2, arg3) {
   return window['go']['main']['App']['ToggleMCPServer'](arg1, arg2, arg3);
 }
@@ -1,33 +1,37 @@
-export namespace config {
+export namespace bringup {
 	
-	export class AgentModelOverride {
+	export class TemplateStatus {
 	    name: string;
-	    model: string;
+	    state: string;
+	    reused?: boolean;
+	    error?: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new AgentModelOverride(source);
+	        return new TemplateStatus(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.name = source["name"];
-	        this.model = source["model"];
+	        this.state = source["state"];
+	        this.reused = source["reused"];
+	        this.error = source["error"];
 	    }
 	}
-	export class AgentModel {
-	    from: string;
-	    to: string;
-	    overrides?: AgentModelOverride[];
+	export class Status {
+	    operation?: string;
+	    running: boolean;
+	    templates: TemplateStatus[];
 	
 	    static createFrom(source: any = {}) {
-	        return new AgentModel(source);
+	        return new Status(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.from = source["from"];
-	        this.to = source["to"];
-	        this.overrides = this.convertValues(source["overrides"], AgentModelOverride);
+	        this.operation = source["operation"];
+	        this.running = source["running"];
+	        this.templates = this.convertValues(source["templates"], TemplateStatus);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -48,69 +52,105 @@ export namespace config {
 		    return a;
 		}
 	}
+
+}
+
+export namespace bugreport {
 	
-	export class AutoStartCommand {
+	export class File {
 	    name: string;
-	    command: string;
-	    args?: string;
+	    size: number;
+	    source?: string;
+	    truncated?: boolean;
 	
 	    static createFrom(source: any = {}) {
-	        return new AutoStartCommand(source);
+	        return new File(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.name = source["name"];
-	        this.command = source["command"];
-	        this.args = source["args"];
+	        this.size = source["size"];
+	        this.source = source["source"];
+	        this.truncated = source["truncated"];
 	    }
 	}
-	export class ClaudeEnvConfig {
-	    default_enabled: boolean;
-	    vars?: Record<string, string>;
+	export class Manifest {
+	    version: string;
+	    os: string;
+	    arch: string;
+	    go_version: string;
+	    // Go type: time
+	    created_at: any;
+	    files: File[];
+	    skipped?: string[];
 	
 	    static createFrom(source: any = {}) {
-	        return new ClaudeEnvConfig(source);
+	        return new Manifest(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.default_enabled = source["default_enabled"];
-	        this.vars = source["vars"];
+	        this.version = source["version"];
+	        this.os = source["os"];
+	        this.arch = source["arch"];
+	        this.go_version = source["go_version"];
+	        this.created_at = this.convertValues(source["created_at"], null);
+	        this.files = this.convertValues(source["files"], File);
+	        this.skipped = source["skipped"];
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
-	export class MessageTemplate {
-	    name: string;
-	    message: string;
+
+}
+
+export namespace changelog {
+	
+	export class Change {
+	    ja: string;
+	    en: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new MessageTemplate(source);
+	        return new Change(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.name = source["name"];
-	        this.message = source["message"];
+	        this.ja = source["ja"];
+	        this.en = source["en"];
 	    }
 	}
-	export class TaskSchedulerConfig {
-	    pre_exec_reset_delay_s: number;
-	    pre_exec_idle_timeout_s: number;
-	    pre_exec_target_mode: string;
-	    message_templates?: MessageTemplate[];
+	export class Release {
+	    version: string;
+	    changes: Change[];
 	
 	    static createFrom(source: any = {}) {
-	        return new TaskSchedulerConfig(source);
+	        return new Release(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.pre_exec_reset_delay_s = source["pre_exec_reset_delay_s"];
-	        this.pre_exec_idle_timeout_s = source["pre_exec_idle_timeout_s"];
-	        this.pre_exec_target_mode = source["pre_exec_target_mode"];
-	        this.message_templates = this.convertValues(source["message_templates"], MessageTemplate);
+	        this.version = source["version"];
+	        this.changes = this.convertValues(source["changes"], Change);
 	    }
-	
+
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
 		    if (!a) {
 		        return a;
@@ -129,52 +169,148 @@ export namespace config {
 		    return a;
 		}
 	}
-	export class MCPServerConfigParam {
-	    key: string;
-	    label: string;
-	    default_value: string;
-	    description?: string;
+
+}
+
+export namespace cmdapproval {
+	
+	export class PendingCommand {
+	    id: string;
+	    session_name: string;
+	    pane_id: string;
+	    command: string;
+	    command_line: string;
+	    requested_at: string;
+	    expires_at: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new MCPServerConfigParam(source);
+	        return new PendingCommand(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.key = source["key"];
-	        this.label = source["label"];
-	        this.default_value = source["default_value"];
-	        this.description = source["description"];
+	        this.id = source["id"];
+	        this.session_name = source["session_name"];
+	        this.pane_id = source["pane_id"];
+	        this.command = source["command"];
+	        this.command_line = source["command_line"];
+	        this.requested_at = source["requested_at"];
+	        this.expires_at = source["expires_at"];
 	    }
 	}
-	export class MCPServerConfig {
+	export class SessionApproval {
+	    session_name: string;
+	    enabled: boolean;
+	    allowed_commands: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SessionApproval(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.session_name = source["session_name"];
+	        this.enabled = source["enabled"];
+	        this.allowed_commands = source["allowed_commands"];
+	    }
+	}
+
+}
+
+export namespace cmdqueue {
+	
+	export class ItemStatus {
 	    id: string;
-	    name: string;
-	    description?: string;
-	    kind?: string;
 	    command: string;
-	    args?: string[];
-	    env?: Record<string, string>;
-	    enabled: boolean;
-	    usage_sample?: string;
-	    config_params?: MCPServerConfigParam[];
+	    state: string;
+	    after_pane_id?: string;
+	    exit_code?: number;
+	    error?: string;
+	    signals?: string[];
 	
 	    static createFrom(source: any = {}) {
-	        return new MCPServerConfig(source);
+	        return new ItemStatus(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.id = source["id"];
-	        this.name = source["name"];
-	        this.description = source["description"];
-	        this.kind = source["kind"];
 	        this.command = source["command"];
-	        this.args = source["args"];
-	        this.env = source["env"];
-	        this.enabled = source["enabled"];
-	        this.usage_sample = source["usage_sample"];
-	        this.config_params = this.convertValues(source["config_params"], MCPServerConfigParam);
+	        this.state = source["state"];
+	        this.after_pane_id = source["after_pane_id"];
+	        this.exit_code = source["exit_code"];
+	        this.error = source["error"];
+	        this.signals = source["signals"];
+	    }
+	}
+	export class KillPolicy {
+	    signals?: string[];
+	    grace_ms?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new KillPolicy(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.signals = source["signals"];
+	        this.grace_ms = source["grace_ms"];
+	    }
+	}
+	export class Options {
+	    timeout_ms?: number;
+	    stop_on_error?: boolean;
+	    require_prompt?: boolean;
+	    after_pane_id?: string;
+	    kill_policy?: KillPolicy;
+	
+	    static createFrom(source: any = {}) {
+	        return new Options(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.timeout_ms = source["timeout_ms"];
+	        this.stop_on_error = source["stop_on_error"];
+	        this.require_prompt = source["require_prompt"];
+	        this.after_pane_id = source["after_pane_id"];
+	        this.kill_policy = this.convertValues(source["kill_policy"], KillPolicy);
+	    }
+	
+	convertValues(a: any, classs: any, asMap: boolean = false): any {
+	    if (!a) {
+	        return a;
+	    }
+	    if (a.slice && a.map) {
+	        return (a as any[]).map(elem => this.convertValues(elem, classs));
+	    } else if ("object" === typeof a) {
+	        if (asMap) {
+	            for (const key of Object.keys(a)) {
+	                a[key] = new classs(a[key]);
+	            }
+	            return a;
+	        }
+	        return new classs(a);
+	    }
+	    return a;
+	}
+}
+	export class QueueStatus {
+	    pane_id: string;
+	    running: boolean;
+	    shell_integration: boolean;
+	    items: ItemStatus[];
+	
+	    static createFrom(source: any = {}) {
+	        return new QueueStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.pane_id = source["pane_id"];
+	        this.running = source["running"];
+	        this.shell_integration = source["shell_integration"];
+	        this.items = this.convertValues(source["items"], ItemStatus);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -195,72 +331,53 @@ export namespace config {
 		    return a;
 		}
 	}
-	export class WorktreeConfig {
-	    enabled: boolean;
-	    force_cleanup: boolean;
-	    setup_scripts: string[];
-	    setup_script_timeout_seconds: number;
-	    copy_files: string[];
-	    copy_dirs: string[];
+
+}
+
+export namespace config {
+	
+	export class ActivityDigestConfig {
+	    dir?: string;
+	    cost_per_active_hour?: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new WorktreeConfig(source);
+	        return new ActivityDigestConfig(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.enabled = source["enabled"];
-	        this.force_cleanup = source["force_cleanup"];
-	        this.setup_scripts = source["setup_scripts"];
-	        this.setup_script_timeout_seconds = source["setup_script_timeout_seconds"];
-	        this.copy_files = source["copy_files"];
-	        this.copy_dirs = source["copy_dirs"];
+	        this.dir = source["dir"];
+	        this.cost_per_active_hour = source["cost_per_active_hour"];
 	    }
 	}
-	export class Config {
-	    shell: string;
-	    prefix: string;
-	    keys: Record<string, string>;
-	    quake_mode: boolean;
-	    global_hotkey: string;
-	    auto_start: AutoStartCommand[];
-	    worktree: WorktreeConfig;
-	    agent_model?: AgentModel;
-	    pane_env?: Record<string, string>;
-	    pane_env_default_enabled: boolean;
-	    claude_env?: ClaudeEnvConfig;
-	    websocket_port: number;
-	    viewer_shortcuts?: Record<string, string>;
-	    viewer_sidebar_mode?: string;
-	    default_session_dir?: string;
-	    mcp_servers?: MCPServerConfig[];
-	    chat_overlay_percentage?: number;
-	    task_scheduler?: TaskSchedulerConfig;
+	export class AgentModelOverride {
+	    name: string;
+	    model: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new Config(source);
+	        return new AgentModelOverride(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.shell = source["shell"];
-	        this.prefix = source["prefix"];
-	        this.keys = source["keys"];
-	        this.quake_mode = source["quake_mode"];
-	        this.global_hotkey = source["global_hotkey"];
-	        this.auto_start = this.convertValues(source["auto_start"], AutoStartCommand);
-	        this.worktree = this.convertValues(source["worktree"], WorktreeConfig);
-	        this.agent_model = this.convertValues(source["agent_model"], AgentModel);
-	        this.pane_env = source["pane_env"];
-	        this.pane_env_default_enabled = source["pane_env_default_enabled"];
-	        this.claude_env = this.convertValues(source["claude_env"], ClaudeEnvConfig);
-	        this.websocket_port = source["websocket_port"];
-	        this.viewer_shortcuts = source["viewer_shortcuts"];
-	        this.viewer_sidebar_mode = source["viewer_sidebar_mode"];
-	        this.default_session_dir = source["default_session_dir"];
-	        this.mcp_servers = this.convertValues(source["mcp_servers"], MCPServerConfig);
-	        this.chat_overlay_percentage = source["chat_overlay_percentage"];
-	        this.task_scheduler = this.convertValues(source["task_scheduler"], TaskSchedulerConfig);
+	        this.name = source["name"];
+	        this.model = source["model"];
+	    }
+	}
+	export class AgentModel {
+	    from: string;
+	    to: string;
+	    overrides?: AgentModelOverride[];
+	
+	    static createFrom(source: any = {}) {
+	        return new AgentModel(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.from = source["from"];
+	        this.to = source["to"];
+	        this.overrides = this.convertValues(source["overrides"], AgentModelOverride);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -282,214 +399,280 @@ export namespace config {
 		}
 	}
 	
+	export class AutoStartCommand {
+	    name: string;
+	    command: string;
+	    args?: string;
 	
+	    static createFrom(source: any = {}) {
+	        return new AutoStartCommand(source);
+	    }
 	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.command = source["command"];
+	        this.args = source["args"];
+	    }
+	}
+	export class ClaudeEnvConfig {
+	    default_enabled: boolean;
+	    vars?: Record<string, string>;
 	
-
-}
-
-export namespace devpanel {
-	
-	export class BinaryFileContent {
-	    path: string;
-	    data: string;
-	    mime: string;
+	    static createFrom(source: any = {}) {
+	        return new ClaudeEnvConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.default_enabled = source["default_enabled"];
+	        this.vars = source["vars"];
+	    }
+	}
+	export class CommandTriggerAction {
+	    notify?: boolean;
+	    hook?: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new BinaryFileContent(source);
+	        return new CommandTriggerAction(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.path = source["path"];
-	        this.data = source["data"];
-	        this.mime = source["mime"];
+	        this.notify = source["notify"];
+	        this.hook = source["hook"];
 	    }
 	}
-	export class CommitResult {
-	    hash: string;
-	    message: string;
+	export class CommandTriggers {
+	    on_failure?: CommandTriggerAction;
+	    on_success?: CommandTriggerAction;
 	
 	    static createFrom(source: any = {}) {
-	        return new CommitResult(source);
+	        return new CommandTriggers(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.hash = source["hash"];
-	        this.message = source["message"];
+	        this.on_failure = this.convertValues(source["on_failure"], CommandTriggerAction);
+	        this.on_success = this.convertValues(source["on_success"], CommandTriggerAction);
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
-	export class FileContent {
-	    path: string;
-	    content: string;
-	    line_count: number;
-	    size: number;
-	    truncated: boolean;
-	    binary: boolean;
+	export class CommandTriggersConfig {
+	    on_failure?: CommandTriggerAction;
+	    on_success?: CommandTriggerAction;
+	    sessions?: Record<string, CommandTriggers>;
 	
 	    static createFrom(source: any = {}) {
-	        return new FileContent(source);
+	        return new CommandTriggersConfig(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.path = source["path"];
-	        this.content = source["content"];
-	        this.line_count = source["line_count"];
-	        this.size = source["size"];
-	        this.truncated = source["truncated"];
-	        this.binary = source["binary"];
+	        this.on_failure = this.convertValues(source["on_failure"], CommandTriggerAction);
+	        this.on_success = this.convertValues(source["on_success"], CommandTriggerAction);
+	        this.sessions = this.convertValues(source["sessions"], CommandTriggers, true);
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
-	export class FileEntry {
+	export class GitIdentity {
+	    session?: string;
+	    repo?: string;
 	    name: string;
-	    path: string;
-	    is_dir: boolean;
-	    size: number;
-	    has_children: boolean;
-	    has_view_target: boolean;
+	    email: string;
+	    signing_key?: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new FileEntry(source);
+	        return new GitIdentity(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.session = source["session"];
+	        this.repo = source["repo"];
 	        this.name = source["name"];
-	        this.path = source["path"];
-	        this.is_dir = source["is_dir"];
-	        this.size = source["size"];
-	        this.has_children = source["has_children"];
-	        this.has_view_target = source["has_view_target"];
+	        this.email = source["email"];
+	        this.signing_key = source["signing_key"];
 	    }
 	}
-	export class FileMetadata {
-	    path: string;
-	    size: number;
-	    is_dir: boolean;
+	export class KeyChange {
+	    key: string;
+	    subsystem: string;
+	    apply: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new FileMetadata(source);
+	        return new KeyChange(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.path = source["path"];
-	        this.size = source["size"];
-	        this.is_dir = source["is_dir"];
+	        this.key = source["key"];
+	        this.subsystem = source["subsystem"];
+	        this.apply = source["apply"];
 	    }
 	}
-	export class GitGraphCommit {
-	    hash: string;
-	    full_hash: string;
-	    parents: string[];
-	    subject: string;
-	    author_name: string;
-	    author_date: string;
-	    refs: string[];
+	export class MessageTemplate {
+	    name: string;
+	    message: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new GitGraphCommit(source);
+	        return new MessageTemplate(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.hash = source["hash"];
-	        this.full_hash = source["full_hash"];
-	        this.parents = source["parents"];
-	        this.subject = source["subject"];
-	        this.author_name = source["author_name"];
-	        this.author_date = source["author_date"];
-	        this.refs = source["refs"];
+	        this.name = source["name"];
+	        this.message = source["message"];
 	    }
 	}
-	export class GitStatusResult {
-	    branch: string;
-	    modified: string[];
-	    staged: string[];
-	    untracked: string[];
-	    conflicted: string[];
-	    ahead: number;
-	    behind: number;
-	    upstream_configured: boolean;
+	export class PolicyInfo {
+	    path: string;
+	    locked_keys: string[];
 	
 	    static createFrom(source: any = {}) {
-	        return new GitStatusResult(source);
+	        return new PolicyInfo(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.branch = source["branch"];
-	        this.modified = source["modified"];
-	        this.staged = source["staged"];
-	        this.untracked = source["untracked"];
-	        this.conflicted = source["conflicted"];
-	        this.ahead = source["ahead"];
-	        this.behind = source["behind"];
-	        this.upstream_configured = source["upstream_configured"];
+	        this.path = source["path"];
+	        this.locked_keys = source["locked_keys"];
 	    }
 	}
-	export class PullResult {
-	    updated: boolean;
-	    summary: string;
+	export class TaskSchedulerConfig {
+	    pre_exec_reset_delay_s: number;
+	    pre_exec_idle_timeout_s: number;
+	    pre_exec_target_mode: string;
+	    message_templates?: MessageTemplate[];
 	
 	    static createFrom(source: any = {}) {
-	        return new PullResult(source);
+	        return new TaskSchedulerConfig(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.updated = source["updated"];
-	        this.summary = source["summary"];
+	        this.pre_exec_reset_delay_s = source["pre_exec_reset_delay_s"];
+	        this.pre_exec_idle_timeout_s = source["pre_exec_idle_timeout_s"];
+	        this.pre_exec_target_mode = source["pre_exec_target_mode"];
+	        this.message_templates = this.convertValues(source["message_templates"], MessageTemplate);
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
-	export class PushResult {
-	    remote_name: string;
-	    branch_name: string;
-	    upstream_set: boolean;
+	export class MCPServerConfigParam {
+	    key: string;
+	    label: string;
+	    default_value: string;
+	    description?: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new PushResult(source);
+	        return new MCPServerConfigParam(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.remote_name = source["remote_name"];
-	        this.branch_name = source["branch_name"];
-	        this.upstream_set = source["upstream_set"];
+	        this.key = source["key"];
+	        this.label = source["label"];
+	        this.default_value = source["default_value"];
+	        this.description = source["description"];
 	    }
 	}
-	export class SearchContentLine {
-	    line: number;
-	    content: string;
+	export class MCPServerPermissions {
+	    filesystem_paths?: string[];
+	    network_hosts?: string[];
+	    env_keys?: string[];
 	
 	    static createFrom(source: any = {}) {
-	        return new SearchContentLine(source);
+	        return new MCPServerPermissions(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.line = source["line"];
-	        this.content = source["content"];
+	        this.filesystem_paths = source["filesystem_paths"];
+	        this.network_hosts = source["network_hosts"];
+	        this.env_keys = source["env_keys"];
 	    }
 	}
-	export class SearchFileResult {
-	    path: string;
+	export class MCPServerConfig {
+	    id: string;
 	    name: string;
-	    is_name_match: boolean;
-	    content_lines: SearchContentLine[];
+	    description?: string;
+	    kind?: string;
+	    command: string;
+	    args?: string[];
+	    env?: Record<string, string>;
+	    enabled: boolean;
+	    usage_sample?: string;
+	    config_params?: MCPServerConfigParam[];
+	    permissions?: MCPServerPermissions;
 	
 	    static createFrom(source: any = {}) {
-	        return new SearchFileResult(source);
+	        return new MCPServerConfig(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.path = source["path"];
+	        this.id = source["id"];
 	        this.name = source["name"];
-	        this.is_name_match = source["is_name_match"];
-	        this.content_lines = this.convertValues(source["content_lines"], SearchContentLine);
+	        this.description = source["description"];
+	        this.kind = source["kind"];
+	        this.command = source["command"];
+	        this.args = source["args"];
+	        this.env = source["env"];
+	        this.enabled = source["enabled"];
+	        this.usage_sample = source["usage_sample"];
+	        this.config_params = this.convertValues(source["config_params"], MCPServerConfigParam);
+	        this.permissions = this.convertValues(source["permissions"], MCPServerPermissions);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -510,74 +693,112 @@ export namespace devpanel {
 		    return a;
 		}
 	}
-	export class SqliteColumnInfo {
-	    name: string;
-	    type: string;
-	    not_null: boolean;
-	    primary_key: boolean;
+	export class RepoConfigSettings {
+	    signing_keys?: string[];
+	    require_signature?: boolean;
 	
 	    static createFrom(source: any = {}) {
-	        return new SqliteColumnInfo(source);
+	        return new RepoConfigSettings(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.name = source["name"];
-	        this.type = source["type"];
-	        this.not_null = source["not_null"];
-	        this.primary_key = source["primary_key"];
+	        this.signing_keys = source["signing_keys"];
+	        this.require_signature = source["require_signature"];
 	    }
 	}
-	export class SqliteExportResult {
-	    path: string;
-	    row_count: number;
+	export class SessionBadgeRule {
+	    branch_prefix?: string;
+	    repo?: string;
+	    dirty?: boolean;
+	    color?: string;
+	    emoji?: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new SqliteExportResult(source);
+	        return new SessionBadgeRule(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.path = source["path"];
-	        this.row_count = source["row_count"];
+	        this.branch_prefix = source["branch_prefix"];
+	        this.repo = source["repo"];
+	        this.dirty = source["dirty"];
+	        this.color = source["color"];
+	        this.emoji = source["emoji"];
 	    }
 	}
-	export class SqliteQueryResult {
-	    columns: string[];
-	    rows: string[][];
-	    null_masks: boolean[][];
-	    offset: number;
-	    total_rows: number;
-	    truncated: boolean;
+	export class SessionHealthProbe {
+	    port?: number;
+	    command?: string;
+	    interval_seconds?: number;
+	    timeout_seconds?: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new SqliteQueryResult(source);
+	        return new SessionHealthProbe(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.columns = source["columns"];
-	        this.rows = source["rows"];
-	        this.null_masks = source["null_masks"];
-	        this.offset = source["offset"];
-	        this.total_rows = source["total_rows"];
-	        this.truncated = source["truncated"];
+	        this.port = source["port"];
+	        this.command = source["command"];
+	        this.interval_seconds = source["interval_seconds"];
+	        this.timeout_seconds = source["timeout_seconds"];
 	    }
 	}
-	export class SqliteTableInfo {
+	export class SessionPoolConfig {
+	    size?: number;
+	    max_idle_minutes?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SessionPoolConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.size = source["size"];
+	        this.max_idle_minutes = source["max_idle_minutes"];
+	    }
+	}
+	export class SessionTemplatePane {
+	    command?: string;
+	    env?: Record<string, string>;
+	    split?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SessionTemplatePane(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.command = source["command"];
+	        this.env = source["env"];
+	        this.split = source["split"];
+	    }
+	}
+	export class SessionTemplate {
 	    name: string;
-	    columns: SqliteColumnInfo[];
-	    row_count: number;
+	    dir?: string;
+	    command?: string;
+	    depends_on?: string[];
+	    health?: SessionHealthProbe;
+	    env?: Record<string, string>;
+	    panes?: SessionTemplatePane[];
+	    layout?: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new SqliteTableInfo(source);
+	        return new SessionTemplate(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.name = source["name"];
-	        this.columns = this.convertValues(source["columns"], SqliteColumnInfo);
-	        this.row_count = source["row_count"];
+	        this.dir = source["dir"];
+	        this.command = source["command"];
+	        this.depends_on = source["depends_on"];
+	        this.health = this.convertValues(source["health"], SessionHealthProbe);
+	        this.env = source["env"];
+	        this.panes = this.convertValues(source["panes"], SessionTemplatePane);
+	        this.layout = source["layout"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -598,174 +819,140 @@ export namespace devpanel {
 		    return a;
 		}
 	}
-	export class WorkingDiffFile {
-	    path: string;
-	    old_path: string;
-	    status: string;
-	    additions: number;
-	    deletions: number;
-	    diff: string;
+	export class ShellRule {
+	    dir?: string;
+	    repo?: string;
+	    shell: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new WorkingDiffFile(source);
+	        return new ShellRule(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.path = source["path"];
-	        this.old_path = source["old_path"];
-	        this.status = source["status"];
-	        this.additions = source["additions"];
-	        this.deletions = source["deletions"];
-	        this.diff = source["diff"];
+	        this.dir = source["dir"];
+	        this.repo = source["repo"];
+	        this.shell = source["shell"];
 	    }
 	}
-	export class WorkingDiffResult {
-	    files: WorkingDiffFile[];
-	    total_added: number;
-	    total_deleted: number;
-	    truncated: boolean;
+	export class StartupCommandsConfig {
+	    session?: string;
+	    window?: string;
+	    remain_on_exit?: boolean;
 	
 	    static createFrom(source: any = {}) {
-	        return new WorkingDiffResult(source);
+	        return new StartupCommandsConfig(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.files = this.convertValues(source["files"], WorkingDiffFile);
-	        this.total_added = source["total_added"];
-	        this.total_deleted = source["total_deleted"];
-	        this.truncated = source["truncated"];
+	        this.session = source["session"];
+	        this.window = source["window"];
+	        this.remain_on_exit = source["remain_on_exit"];
 	    }
-	
-		convertValues(a: any, classs: any, asMap: boolean = false): any {
-		    if (!a) {
-		        return a;
-		    }
-		    if (a.slice && a.map) {
-		        return (a as any[]).map(elem => this.convertValues(elem, classs));
-		    } else if ("object" === typeof a) {
-		        if (asMap) {
-		            for (const key of Object.keys(a)) {
-		                a[key] = new classs(a[key]);
-		            }
-		            return a;
-		        }
-		        return new classs(a);
-		    }
-		    return a;
-		}
 	}
-	export class WriteFileResult {
+	export class TrustedShell {
 	    path: string;
-	    size: number;
+	    sha256?: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new WriteFileResult(source);
+	        return new TrustedShell(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.path = source["path"];
-	        this.size = source["size"];
+	        this.sha256 = source["sha256"];
 	    }
 	}
-
-}
-
-export namespace git {
-	
-	export class WorktreeHealth {
-	    isHealthy: boolean;
-	    issues?: string[];
+	export class ToolPathsConfig {
+	    kinds?: string[];
 	
 	    static createFrom(source: any = {}) {
-	        return new WorktreeHealth(source);
+	        return new ToolPathsConfig(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.isHealthy = source["isHealthy"];
-	        this.issues = source["issues"];
+	        this.kinds = source["kinds"];
 	    }
 	}
-	export class WorktreeInfo {
-	    path: string;
-	    branch: string;
-	    isMain: boolean;
-	    isDetached: boolean;
-	    health?: WorktreeHealth;
+	export class Webhook {
+	    url: string;
+	    events: string[];
+	    secret?: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new WorktreeInfo(source);
+	        return new Webhook(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.path = source["path"];
-	        this.branch = source["branch"];
-	        this.isMain = source["isMain"];
-	        this.isDetached = source["isDetached"];
-	        this.health = this.convertValues(source["health"], WorktreeHealth);
+	        this.url = source["url"];
+	        this.events = source["events"];
+	        this.secret = source["secret"];
 	    }
-	
-		convertValues(a: any, classs: any, asMap: boolean = false): any {
-		    if (!a) {
-		        return a;
-		    }
-		    if (a.slice && a.map) {
-		        return (a as any[]).map(elem => this.convertValues(elem, classs));
-		    } else if ("object" === typeof a) {
-		        if (asMap) {
-		            for (const key of Object.keys(a)) {
-		                a[key] = new classs(a[key]);
-		            }
-		            return a;
-		        }
-		        return new classs(a);
-		    }
-		    return a;
-		}
 	}
-
-}
-
-export namespace inputhistory {
+	export class SetupWatcher {
+	    script: string;
+	    files: string[];
 	
-	export class Entry {
-	    seq: number;
-	    ts: string;
-	    pane_id: string;
-	    input: string;
-	    source: string;
-	    session: string;
+	    static createFrom(source: any = {}) {
+	        return new SetupWatcher(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.script = source["script"];
+	        this.files = source["files"];
+	    }
+	}
+	export class WorktreeBaseRefreshConfig {
+	    mode: string;
+	    on_focus?: boolean;
+	    interval_minutes?: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new Entry(source);
+	        return new WorktreeBaseRefreshConfig(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.seq = source["seq"];
-	        this.ts = source["ts"];
-	        this.pane_id = source["pane_id"];
-	        this.input = source["input"];
-	        this.source = source["source"];
-	        this.session = source["session"];
+	        this.mode = source["mode"];
+	        this.on_focus = source["on_focus"];
+	        this.interval_minutes = source["interval_minutes"];
 	    }
 	}
-	export class Snapshot {
-	    scope_key: string;
-	    entries: Entry[];
+	export class WorktreeConfig {
+	    enabled: boolean;
+	    force_cleanup: boolean;
+	    setup_scripts: string[];
+	    setup_script_timeout_seconds: number;
+	    copy_files: string[];
+	    copy_dirs: string[];
+	    copy_files_eol?: string;
+	    merge_tool?: string;
+	    allow_shared?: boolean;
+	    base_refresh?: WorktreeBaseRefreshConfig;
+	    setup_watchers?: SetupWatcher[];
 	
 	    static createFrom(source: any = {}) {
-	        return new Snapshot(source);
+	        return new WorktreeConfig(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.scope_key = source["scope_key"];
-	        this.entries = this.convertValues(source["entries"], Entry);
+	        this.enabled = source["enabled"];
+	        this.force_cleanup = source["force_cleanup"];
+	        this.setup_scripts = source["setup_scripts"];
+	        this.setup_script_timeout_seconds = source["setup_script_timeout_seconds"];
+	        this.copy_files = source["copy_files"];
+	        this.copy_dirs = source["copy_dirs"];
+	        this.copy_files_eol = source["copy_files_eol"];
+	        this.merge_tool = source["merge_tool"];
+	        this.allow_shared = source["allow_shared"];
+	        this.base_refresh = this.convertValues(source["base_refresh"], WorktreeBaseRefreshConfig);
+	        this.setup_watchers = this.convertValues(source["setup_watchers"], SetupWatcher);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -786,292 +973,2152 @@ export namespace inputhistory {
 		    return a;
 		}
 	}
-
-}
-
-export namespace install {
-	
-	export class ShimInstallResult {
-	    installed_path: string;
-	    path_updated: boolean;
-	    restart_needed: boolean;
-	    message: string;
+	export class ForgeConfig {
+	    kind?: string;
+	    api_url?: string;
+	    token?: string;
+	    token_env?: string;
+	    draft?: boolean;
+	    create_on_push?: boolean;
 	
 	    static createFrom(source: any = {}) {
-	        return new ShimInstallResult(source);
+	        return new ForgeConfig(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.installed_path = source["installed_path"];
-	        this.path_updated = source["path_updated"];
-	        this.restart_needed = source["restart_needed"];
-	        this.message = source["message"];
+	        this.kind = source["kind"];
+	        this.api_url = source["api_url"];
+	        this.token = source["token"];
+	        this.token_env = source["token_env"];
+	        this.draft = source["draft"];
+	        this.create_on_push = source["create_on_push"];
 	    }
 	}
-
-}
-
-export namespace ipc {
-	
-	export class MCPStdioResolvePayload {
-	    session_name: string;
-	    mcp_id: string;
-	    pipe_path: string;
+	export class LoggingConfig {
+	    level?: string;
+	    format?: string;
+	    max_file_mb?: number;
+	    keep_files?: number;
+	    disable_files?: boolean;
 	
 	    static createFrom(source: any = {}) {
-	        return new MCPStdioResolvePayload(source);
+	        return new LoggingConfig(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.session_name = source["session_name"];
-	        this.mcp_id = source["mcp_id"];
-	        this.pipe_path = source["pipe_path"];
+	        this.level = source["level"];
+	        this.format = source["format"];
+	        this.max_file_mb = source["max_file_mb"];
+	        this.keep_files = source["keep_files"];
+	        this.disable_files = source["disable_files"];
 	    }
 	}
-
-}
-
-export namespace main {
-	
-	export class CreateSessionOptions {
-	    enable_agent_team: boolean;
-	    use_claude_env: boolean;
-	    use_pane_env: boolean;
-	    use_session_pane_scope: boolean;
+	export class MetricsConfig {
+	    enabled?: boolean;
+	    port?: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new CreateSessionOptions(source);
+	        return new MetricsConfig(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.enable_agent_team = source["enable_agent_team"];
-	        this.use_claude_env = source["use_claude_env"];
-	        this.use_pane_env = source["use_pane_env"];
-	        this.use_session_pane_scope = source["use_session_pane_scope"];
+	        this.enabled = source["enabled"];
+	        this.port = source["port"];
 	    }
 	}
-	export class OrchestratorAgent {
-	    name: string;
-	    pane_id: string;
-	    role: string;
+	export class ResourceLimits {
+	    memory_mb?: number;
+	    cpu_percent?: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new OrchestratorAgent(source);
+	        return new ResourceLimits(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.name = source["name"];
-	        this.pane_id = source["pane_id"];
-	        this.role = source["role"];
+	        this.memory_mb = source["memory_mb"];
+	        this.cpu_percent = source["cpu_percent"];
 	    }
 	}
-	export class OrchestratorTask {
-	    task_id: string;
-	    agent_name: string;
-	    assignee_pane_id: string;
-	    sender_pane_id: string;
-	    sender_name: string;
-	    status: string;
-	    sent_at: string;
-	    completed_at: string;
-	    message_preview: string;
-	    response_preview: string;
+	export class FileDropConfig {
+	    mode?: string;
+	    type_path?: string;
+	    max_size_mb?: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new OrchestratorTask(source);
+	        return new FileDropConfig(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.task_id = source["task_id"];
-	        this.agent_name = source["agent_name"];
-	        this.assignee_pane_id = source["assignee_pane_id"];
-	        this.sender_pane_id = source["sender_pane_id"];
-	        this.sender_name = source["sender_name"];
-	        this.status = source["status"];
-	        this.sent_at = source["sent_at"];
-	        this.completed_at = source["completed_at"];
-	        this.message_preview = source["message_preview"];
-	        this.response_preview = source["response_preview"];
+	        this.mode = source["mode"];
+	        this.type_path = source["type_path"];
+	        this.max_size_mb = source["max_size_mb"];
 	    }
 	}
-	export class OrchestratorTaskDetail {
-	    task_id: string;
+	export class ResourceLimitsConfig {
+	    memory_mb?: number;
+	    cpu_percent?: number;
+	    sessions?: Record<string, ResourceLimits>;
+	
+	    static createFrom(source: any = {}) {
+	        return new ResourceLimitsConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.memory_mb = source["memory_mb"];
+	        this.cpu_percent = source["cpu_percent"];
+	        this.sessions = this.convertValues(source["sessions"], ResourceLimits, true);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Config {
+	    shell: string;
+	    prefix: string;
+	    keys: Record<string, string>;
+	    quake_mode: boolean;
+	    global_hotkey: string;
+	    auto_start: AutoStartCommand[];
+	    worktree: WorktreeConfig;
+	    agent_model?: AgentModel;
+	    pane_env?: Record<string, string>;
+	    pane_env_default_enabled: boolean;
+	    claude_env?: ClaudeEnvConfig;
+	    shell_history_isolation_default_enabled?: boolean;
+	    websocket_port: number;
+	    viewer_shortcuts?: Record<string, string>;
+	    viewer_sidebar_mode?: string;
+	    default_session_dir?: string;
+	    mcp_servers?: MCPServerConfig[];
+	    chat_overlay_percentage?: number;
+	    task_scheduler?: TaskSchedulerConfig;
+	    shell_rules?: ShellRule[];
+	    trusted_shells?: TrustedShell[];
+	    startup_commands?: StartupCommandsConfig;
+	    session_badge_rules?: SessionBadgeRule[];
+	    focus_follows_activity?: boolean;
+	    command_triggers?: CommandTriggersConfig;
+	    session_templates?: SessionTemplate[];
+	    repo_config?: RepoConfigSettings;
+	    session_pool?: SessionPoolConfig;
+	    output_folding?: boolean;
+	    git_identities?: GitIdentity[];
+	    webhooks?: Webhook[];
+	    tool_paths?: ToolPathsConfig;
+	    tmux_compat?: string;
+	    mouse?: string;
+	    paste_confirm?: string;
+	    activity_digest?: ActivityDigestConfig;
+	    feature_flags?: Record<string, boolean>;
+	    forge?: ForgeConfig;
+	    logging?: LoggingConfig;
+	    metrics?: MetricsConfig;
+	    resource_limits?: ResourceLimitsConfig;
+	    file_drop?: FileDropConfig;
+	    ephemeral_session_ttl_minutes?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Config(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.shell = source["shell"];
+	        this.prefix = source["prefix"];
+	        this.keys = source["keys"];
+	        this.quake_mode = source["quake_mode"];
+	        this.global_hotkey = source["global_hotkey"];
+	        this.auto_start = this.convertValues(source["auto_start"], AutoStartCommand);
+	        this.worktree = this.convertValues(source["worktree"], WorktreeConfig);
+	        this.agent_model = this.convertValues(source["agent_model"], AgentModel);
+	        this.pane_env = source["pane_env"];
+	        this.pane_env_default_enabled = source["pane_env_default_enabled"];
+	        this.claude_env = this.convertValues(source["claude_env"], ClaudeEnvConfig);
+	        this.shell_history_isolation_default_enabled = source["shell_history_isolation_default_enabled"];
+	        this.websocket_port = source["websocket_port"];
+	        this.viewer_shortcuts = source["viewer_shortcuts"];
+	        this.viewer_sidebar_mode = source["viewer_sidebar_mode"];
+	        this.default_session_dir = source["default_session_dir"];
+	        this.mcp_servers = this.convertValues(source["mcp_servers"], MCPServerConfig);
+	        this.chat_overlay_percentage = source["chat_overlay_percentage"];
+	        this.task_scheduler = this.convertValues(source["task_scheduler"], TaskSchedulerConfig);
+	        this.shell_rules = this.convertValues(source["shell_rules"], ShellRule);
+	        this.trusted_shells = this.convertValues(source["trusted_shells"], TrustedShell);
+	        this.startup_commands = this.convertValues(source["startup_commands"], StartupCommandsConfig);
+	        this.session_badge_rules = this.convertValues(source["session_badge_rules"], SessionBadgeRule);
+	        this.focus_follows_activity = source["focus_follows_activity"];
+	        this.command_triggers = this.convertValues(source["command_triggers"], CommandTriggersConfig);
+	        this.session_templates = this.convertValues(source["session_templates"], SessionTemplate);
+	        this.repo_config = this.convertValues(source["repo_config"], RepoConfigSettings);
+	        this.session_pool = this.convertValues(source["session_pool"], SessionPoolConfig);
+	        this.output_folding = source["output_folding"];
+	        this.git_identities = this.convertValues(source["git_identities"], GitIdentity);
+	        this.webhooks = this.convertValues(source["webhooks"], Webhook);
+	        this.tool_paths = this.convertValues(source["tool_paths"], ToolPathsConfig);
+	        this.tmux_compat = source["tmux_compat"];
+	        this.mouse = source["mouse"];
+	        this.paste_confirm = source["paste_confirm"];
+	        this.activity_digest = this.convertValues(source["activity_digest"], ActivityDigestConfig);
+	        this.feature_flags = source["feature_flags"];
+	        this.forge = this.convertValues(source["forge"], ForgeConfig);
+	        this.logging = this.convertValues(source["logging"], LoggingConfig);
+	        this.metrics = this.convertValues(source["metrics"], MetricsConfig);
+	        this.resource_limits = this.convertValues(source["resource_limits"], ResourceLimitsConfig);
+	        this.file_drop = this.convertValues(source["file_drop"], FileDropConfig);
+	        this.ephemeral_session_ttl_minutes = source["ephemeral_session_ttl_minutes"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	
+	
+
+}
+
+export namespace devpanel {
+	
+	export class BinaryFileContent {
+	    path: string;
+	    data: string;
+	    mime: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new BinaryFileContent(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.data = source["data"];
+	        this.mime = source["mime"];
+	    }
+	}
+	export class CommitResult {
+	    hash: string;
+	    message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CommitResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.hash = source["hash"];
+	        this.message = source["message"];
+	    }
+	}
+	export class FileContent {
+	    path: string;
+	    content: string;
+	    line_count: number;
+	    size: number;
+	    truncated: boolean;
+	    binary: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new FileContent(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.content = source["content"];
+	        this.line_count = source["line_count"];
+	        this.size = source["size"];
+	        this.truncated = source["truncated"];
+	        this.binary = source["binary"];
+	    }
+	}
+	export class FileEntry {
+	    name: string;
+	    path: string;
+	    is_dir: boolean;
+	    size: number;
+	    has_children: boolean;
+	    has_view_target: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new FileEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.path = source["path"];
+	        this.is_dir = source["is_dir"];
+	        this.size = source["size"];
+	        this.has_children = source["has_children"];
+	        this.has_view_target = source["has_view_target"];
+	    }
+	}
+	export class FileMetadata {
+	    path: string;
+	    size: number;
+	    is_dir: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new FileMetadata(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.size = source["size"];
+	        this.is_dir = source["is_dir"];
+	    }
+	}
+	export class FindReplaceMatch {
+	    line: number;
+	    column: number;
+	    line_text: string;
+	    match: string;
+	    replacement: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new FindReplaceMatch(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.line = source["line"];
+	        this.column = source["column"];
+	        this.line_text = source["line_text"];
+	        this.match = source["match"];
+	        this.replacement = source["replacement"];
+	    }
+	}
+	export class FindReplaceFile {
+	    path: string;
+	    matches: number;
+	    preview: FindReplaceMatch[];
+	    preview_truncated: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new FindReplaceFile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.matches = source["matches"];
+	        this.preview = this.convertValues(source["preview"], FindReplaceMatch);
+	        this.preview_truncated = source["preview_truncated"];
+	    }
+	
+	convertValues(a: any, classs: any, asMap: boolean = false): any {
+	    if (!a) {
+	        return a;
+	    }
+	    if (a.slice && a.map) {
+	        return (a as any[]).map(elem => this.convertValues(elem, classs));
+	    } else if ("object" === typeof a) {
+	        if (asMap) {
+	            for (const key of Object.keys(a)) {
+	                a[key] = new classs(a[key]);
+	            }
+	            return a;
+	        }
+	        return new classs(a);
+	    }
+	    return a;
+	}
+	}
+	export class FindReplaceOptions {
+	    regex: boolean;
+	    case_sensitive: boolean;
+	    whole_word: boolean;
+	    include?: string[];
+	    exclude?: string[];
+	    apply: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new FindReplaceOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.regex = source["regex"];
+	        this.case_sensitive = source["case_sensitive"];
+	        this.whole_word = source["whole_word"];
+	        this.include = source["include"];
+	        this.exclude = source["exclude"];
+	        this.apply = source["apply"];
+	    }
+	}
+	export class FindReplaceResult {
+	    applied: boolean;
+	    files: FindReplaceFile[];
+	    files_scanned: number;
+	    files_matched: number;
+	    total_matches: number;
+	    truncated: boolean;
+	    backup_dir?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new FindReplaceResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.applied = source["applied"];
+	        this.files = this.convertValues(source["files"], FindReplaceFile);
+	        this.files_scanned = source["files_scanned"];
+	        this.files_matched = source["files_matched"];
+	        this.total_matches = source["total_matches"];
+	        this.truncated = source["truncated"];
+	        this.backup_dir = source["backup_dir"];
+	    }
+	
+	convertValues(a: any, classs: any, asMap: boolean = false): any {
+	    if (!a) {
+	        return a;
+	    }
+	    if (a.slice && a.map) {
+	        return (a as any[]).map(elem => this.convertValues(elem, classs));
+	    } else if ("object" === typeof a) {
+	        if (asMap) {
+	            for (const key of Object.keys(a)) {
+	                a[key] = new classs(a[key]);
+	            }
+	            return a;
+	        }
+	        return new classs(a);
+	    }
+	    return a;
+	}
+	}
+	export class GitGraphCommit {
+	    hash: string;
+	    full_hash: string;
+	    parents: string[];
+	    subject: string;
+	    author_name: string;
+	    author_date: string;
+	    refs: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new GitGraphCommit(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.hash = source["hash"];
+	        this.full_hash = source["full_hash"];
+	        this.parents = source["parents"];
+	        this.subject = source["subject"];
+	        this.author_name = source["author_name"];
+	        this.author_date = source["author_date"];
+	        this.refs = source["refs"];
+	    }
+	}
+	export class GitStatusResult {
+	    branch: string;
+	    modified: string[];
+	    staged: string[];
+	    untracked: string[];
+	    conflicted: string[];
+	    ahead: number;
+	    behind: number;
+	    upstream_configured: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new GitStatusResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.branch = source["branch"];
+	        this.modified = source["modified"];
+	        this.staged = source["staged"];
+	        this.untracked = source["untracked"];
+	        this.conflicted = source["conflicted"];
+	        this.ahead = source["ahead"];
+	        this.behind = source["behind"];
+	        this.upstream_configured = source["upstream_configured"];
+	    }
+	}
+	export class PullResult {
+	    updated: boolean;
+	    summary: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PullResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.updated = source["updated"];
+	        this.summary = source["summary"];
+	    }
+	}
+	export class PushResult {
+	    remote_name: string;
+	    branch_name: string;
+	    upstream_set: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new PushResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.remote_name = source["remote_name"];
+	        this.branch_name = source["branch_name"];
+	        this.upstream_set = source["upstream_set"];
+	    }
+	}
+	export class SearchContentLine {
+	    line: number;
+	    content: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SearchContentLine(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.line = source["line"];
+	        this.content = source["content"];
+	    }
+	}
+	export class SearchFileResult {
+	    path: string;
+	    name: string;
+	    is_name_match: boolean;
+	    content_lines: SearchContentLine[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SearchFileResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.name = source["name"];
+	        this.is_name_match = source["is_name_match"];
+	        this.content_lines = this.convertValues(source["content_lines"], SearchContentLine);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SqliteColumnInfo {
+	    name: string;
+	    type: string;
+	    not_null: boolean;
+	    primary_key: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new SqliteColumnInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.type = source["type"];
+	        this.not_null = source["not_null"];
+	        this.primary_key = source["primary_key"];
+	    }
+	}
+	export class SqliteExportResult {
+	    path: string;
+	    row_count: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SqliteExportResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.row_count = source["row_count"];
+	    }
+	}
+	export class SqliteQueryResult {
+	    columns: string[];
+	    rows: string[][];
+	    null_masks: boolean[][];
+	    offset: number;
+	    total_rows: number;
+	    truncated: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new SqliteQueryResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.columns = source["columns"];
+	        this.rows = source["rows"];
+	        this.null_masks = source["null_masks"];
+	        this.offset = source["offset"];
+	        this.total_rows = source["total_rows"];
+	        this.truncated = source["truncated"];
+	    }
+	}
+	export class SqliteTableInfo {
+	    name: string;
+	    columns: SqliteColumnInfo[];
+	    row_count: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SqliteTableInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.columns = this.convertValues(source["columns"], SqliteColumnInfo);
+	        this.row_count = source["row_count"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class WorkingDiffFile {
+	    path: string;
+	    old_path: string;
+	    status: string;
+	    additions: number;
+	    deletions: number;
+	    diff: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new WorkingDiffFile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.old_path = source["old_path"];
+	        this.status = source["status"];
+	        this.additions = source["additions"];
+	        this.deletions = source["deletions"];
+	        this.diff = source["diff"];
+	    }
+	}
+	export class WorkingDiffResult {
+	    files: WorkingDiffFile[];
+	    total_added: number;
+	    total_deleted: number;
+	    truncated: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new WorkingDiffResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.files = this.convertValues(source["files"], WorkingDiffFile);
+	        this.total_added = source["total_added"];
+	        this.total_deleted = source["total_deleted"];
+	        this.truncated = source["truncated"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class WriteFileResult {
+	    path: string;
+	    size: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new WriteFileResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.size = source["size"];
+	    }
+	}
+
+}
+
+export namespace ephemeral {
+	
+	export class Worktree {
+	    path: string;
+	    repo_path: string;
+	    branch_name?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Worktree(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.repo_path = source["repo_path"];
+	        this.branch_name = source["branch_name"];
+	    }
+	}
+	export class Entry {
+	    session_name: string;
+	    work_dir?: string;
+	    temp_dir?: boolean;
+	    worktree?: Worktree;
+	    // Go type: time
+	    created_at: any;
+	    // Go type: time
+	    expires_at: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new Entry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.session_name = source["session_name"];
+	        this.work_dir = source["work_dir"];
+	        this.temp_dir = source["temp_dir"];
+	        this.worktree = this.convertValues(source["worktree"], Worktree);
+	        this.created_at = this.convertValues(source["created_at"], null);
+	        this.expires_at = this.convertValues(source["expires_at"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace eventsub {
+	
+	export class Spec {
+	    classes?: string[];
+	    sessions?: string[];
+	    panes?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Spec(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.classes = source["classes"];
+	        this.sessions = source["sessions"];
+	        this.panes = source["panes"];
+	    }
+	}
+
+}
+
+export namespace forge {
+	
+	export class PullRequest {
+	    url: string;
+	    number: number;
+	    existing: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new PullRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.url = source["url"];
+	        this.number = source["number"];
+	        this.existing = source["existing"];
+	    }
+	}
+
+}
+
+export namespace git {
+	
+	export class PrunableWorktree {
+	    path: string;
+	    reason: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PrunableWorktree(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.reason = source["reason"];
+	    }
+	}
+	export class StaleBranch {
+	    name: string;
+	    upstreamGone: boolean;
+	    lastCommitUnix: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new StaleBranch(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.upstreamGone = source["upstreamGone"];
+	        this.lastCommitUnix = source["lastCommitUnix"];
+	    }
+	}
+	export class StashEntry {
+	    ref: string;
+	    commit: string;
+	    message: string;
+	    createdUnix: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new StashEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ref = source["ref"];
+	        this.commit = source["commit"];
+	        this.message = source["message"];
+	        this.createdUnix = source["createdUnix"];
+	    }
+	}
+	export class WorkingTreeStatus {
+	    branch: string;
+	    detached: boolean;
+	    upstream?: string;
+	    ahead: number;
+	    behind: number;
+	    staged: number;
+	    unstaged: number;
+	    untracked: number;
+	    conflicted: number;
+	    dirty: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new WorkingTreeStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.branch = source["branch"];
+	        this.detached = source["detached"];
+	        this.upstream = source["upstream"];
+	        this.ahead = source["ahead"];
+	        this.behind = source["behind"];
+	        this.staged = source["staged"];
+	        this.unstaged = source["unstaged"];
+	        this.untracked = source["untracked"];
+	        this.conflicted = source["conflicted"];
+	        this.dirty = source["dirty"];
+	    }
+	}
+	export class WorktreeHealth {
+	    isHealthy: boolean;
+	    issues?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new WorktreeHealth(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.isHealthy = source["isHealthy"];
+	        this.issues = source["issues"];
+	    }
+	}
+	export class WorktreeInfo {
+	    path: string;
+	    branch: string;
+	    isMain: boolean;
+	    isDetached: boolean;
+	    locked: boolean;
+	    lockReason?: string;
+	    health?: WorktreeHealth;
+	
+	    static createFrom(source: any = {}) {
+	        return new WorktreeInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.branch = source["branch"];
+	        this.isMain = source["isMain"];
+	        this.isDetached = source["isDetached"];
+	        this.locked = source["locked"];
+	        this.lockReason = source["lockReason"];
+	        this.health = this.convertValues(source["health"], WorktreeHealth);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace inputhistory {
+	
+	export class Entry {
+	    seq: number;
+	    ts: string;
+	    pane_id: string;
+	    input: string;
+	    source: string;
+	    session: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Entry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.seq = source["seq"];
+	        this.ts = source["ts"];
+	        this.pane_id = source["pane_id"];
+	        this.input = source["input"];
+	        this.source = source["source"];
+	        this.session = source["session"];
+	    }
+	}
+	export class Snapshot {
+	    scope_key: string;
+	    entries: Entry[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Snapshot(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.scope_key = source["scope_key"];
+	        this.entries = this.convertValues(source["entries"], Entry);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace install {
+	
+	export class PathFinding {
+	    code: string;
+	    severity: string;
+	    message: string;
+	    fix?: string;
+	    path?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PathFinding(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.code = source["code"];
+	        this.severity = source["severity"];
+	        this.message = source["message"];
+	        this.fix = source["fix"];
+	        this.path = source["path"];
+	    }
+	}
+	export class TmuxCandidate {
+	    path: string;
+	    source: string;
+	    path_index: number;
+	    shadows_shim: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new TmuxCandidate(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.source = source["source"];
+	        this.path_index = source["path_index"];
+	        this.shadows_shim = source["shadows_shim"];
+	    }
+	}
+	export class PathDoctorReport {
+	    shim_path: string;
+	    shim_installed: boolean;
+	    shim_on_process_path: boolean;
+	    shim_on_user_path: boolean;
+	    candidates: TmuxCandidate[];
+	    findings: PathFinding[];
+	
+	    static createFrom(source: any = {}) {
+	        return new PathDoctorReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.shim_path = source["shim_path"];
+	        this.shim_installed = source["shim_installed"];
+	        this.shim_on_process_path = source["shim_on_process_path"];
+	        this.shim_on_user_path = source["shim_on_user_path"];
+	        this.candidates = this.convertValues(source["candidates"], TmuxCandidate);
+	        this.findings = this.convertValues(source["findings"], PathFinding);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ShimInstallResult {
+	    installed_path: string;
+	    path_updated: boolean;
+	    restart_needed: boolean;
+	    message: string;
+	    arch: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ShimInstallResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.installed_path = source["installed_path"];
+	        this.path_updated = source["path_updated"];
+	        this.restart_needed = source["restart_needed"];
+	        this.message = source["message"];
+	        this.arch = source["arch"];
+	    }
+	}
+
+}
+
+export namespace ipc {
+	
+	export class MCPStdioResolvePayload {
+	    session_name: string;
+	    mcp_id: string;
+	    pipe_path: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new MCPStdioResolvePayload(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.session_name = source["session_name"];
+	        this.mcp_id = source["mcp_id"];
+	        this.pipe_path = source["pipe_path"];
+	    }
+	}
+	export class RestartReport {
+	    restarts: number;
+	    drained: number;
+	    dropped: number;
+	    handed_off: number;
+	    duration_ms: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new RestartReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.restarts = source["restarts"];
+	        this.drained = source["drained"];
+	        this.dropped = source["dropped"];
+	        this.handed_off = source["handed_off"];
+	        this.duration_ms = source["duration_ms"];
+	    }
+	}
+
+}
+
+export namespace layoutpreset {
+	
+	export class Preset {
+	    name: string;
+	    layout: string;
+	    pane_count: number;
+	    global?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Preset(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.layout = source["layout"];
+	        this.pane_count = source["pane_count"];
+	        this.global = source["global"];
+	    }
+	}
+
+}
+
+export namespace main {
+	
+	export class AppMetrics {
+	    startup: startupmetrics.Report;
+	    pipe_server_restart?: ipc.RestartReport;
+	
+	    static createFrom(source: any = {}) {
+	        return new AppMetrics(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.startup = this.convertValues(source["startup"], startupmetrics.Report);
+	        this.pipe_server_restart = this.convertValues(source["pipe_server_restart"], ipc.RestartReport);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BugReportResult {
+	    path: string;
+	    issue_url: string;
+	    manifest: bugreport.Manifest;
+	
+	    static createFrom(source: any = {}) {
+	        return new BugReportResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.issue_url = source["issue_url"];
+	        this.manifest = this.convertValues(source["manifest"], bugreport.Manifest);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ChangelogPayload {
+	    current_version: string;
+	    previous_version: string;
+	    updated: boolean;
+	    releases: changelog.Release[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ChangelogPayload(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.current_version = source["current_version"];
+	        this.previous_version = source["previous_version"];
+	        this.updated = source["updated"];
+	        this.releases = this.convertValues(source["releases"], changelog.Release);
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ConfigPatchResult {
+	    config: config.Config;
+	    changes: config.KeyChange[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ConfigPatchResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.config = this.convertValues(source["config"], config.Config);
+	        this.changes = this.convertValues(source["changes"], config.KeyChange);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CreateSessionOptions {
+	    enable_agent_team: boolean;
+	    use_claude_env: boolean;
+	    use_pane_env: boolean;
+	    use_session_pane_scope: boolean;
+	    startup_command?: string;
+	    remain_on_exit?: boolean;
+	    isolate_shell_history?: boolean;
+	    ephemeral?: boolean;
+	    detached?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new CreateSessionOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enable_agent_team = source["enable_agent_team"];
+	        this.use_claude_env = source["use_claude_env"];
+	        this.use_pane_env = source["use_pane_env"];
+	        this.use_session_pane_scope = source["use_session_pane_scope"];
+	        this.startup_command = source["startup_command"];
+	        this.remain_on_exit = source["remain_on_exit"];
+	        this.isolate_shell_history = source["isolate_shell_history"];
+	        this.ephemeral = source["ephemeral"];
+	        this.detached = source["detached"];
+	    }
+	}
+	export class FeatureFlagState {
+	    name: string;
+	    description: string;
+	    default: boolean;
+	    apply: string;
+	    enabled: boolean;
+	    overridden: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new FeatureFlagState(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.default = source["default"];
+	        this.apply = source["apply"];
+	        this.enabled = source["enabled"];
+	        this.overridden = source["overridden"];
+	    }
+	}
+	export class OrchestratorAgent {
+	    name: string;
+	    pane_id: string;
+	    role: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new OrchestratorAgent(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.pane_id = source["pane_id"];
+	        this.role = source["role"];
+	    }
+	}
+	export class OrchestratorTask {
+	    task_id: string;
+	    agent_name: string;
+	    assignee_pane_id: string;
+	    sender_pane_id: string;
+	    sender_name: string;
+	    status: string;
+	    sent_at: string;
+	    completed_at: string;
+	    message_preview: string;
+	    response_preview: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new OrchestratorTask(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.task_id = source["task_id"];
+	        this.agent_name = source["agent_name"];
+	        this.assignee_pane_id = source["assignee_pane_id"];
+	        this.sender_pane_id = source["sender_pane_id"];
+	        this.sender_name = source["sender_name"];
+	        this.status = source["status"];
+	        this.sent_at = source["sent_at"];
+	        this.completed_at = source["completed_at"];
+	        this.message_preview = source["message_preview"];
+	        this.response_preview = source["response_preview"];
+	    }
+	}
+	export class OrchestratorTaskDetail {
+	    task_id: string;
 	    agent_name: string;
 	    sender_name: string;
 	    status: string;
-	    sent_at: string;
-	    completed_at: string;
-	    message_content: string;
-	    message_preview: string;
-	    message_storage_mode: string;
-	    message_artifact_paths: string[];
-	    message_part_count: number;
-	    message_content_chars: number;
-	    message_sha256: string;
-	    response_content: string;
-	    response_preview: string;
-	    response_storage_mode: string;
-	    response_artifact_paths: string[];
-	    response_part_count: number;
-	    response_content_chars: number;
-	    response_sha256: string;
+	    sent_at: string;
+	    completed_at: string;
+	    message_content: string;
+	    message_preview: string;
+	    message_storage_mode: string;
+	    message_artifact_paths: string[];
+	    message_part_count: number;
+	    message_content_chars: number;
+	    message_sha256: string;
+	    response_content: string;
+	    response_preview: string;
+	    response_storage_mode: string;
+	    response_artifact_paths: string[];
+	    response_part_count: number;
+	    response_content_chars: number;
+	    response_sha256: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new OrchestratorTaskDetail(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.task_id = source["task_id"];
+	        this.agent_name = source["agent_name"];
+	        this.sender_name = source["sender_name"];
+	        this.status = source["status"];
+	        this.sent_at = source["sent_at"];
+	        this.completed_at = source["completed_at"];
+	        this.message_content = source["message_content"];
+	        this.message_preview = source["message_preview"];
+	        this.message_storage_mode = source["message_storage_mode"];
+	        this.message_artifact_paths = source["message_artifact_paths"];
+	        this.message_part_count = source["message_part_count"];
+	        this.message_content_chars = source["message_content_chars"];
+	        this.message_sha256 = source["message_sha256"];
+	        this.response_content = source["response_content"];
+	        this.response_preview = source["response_preview"];
+	        this.response_storage_mode = source["response_storage_mode"];
+	        this.response_artifact_paths = source["response_artifact_paths"];
+	        this.response_part_count = source["response_part_count"];
+	        this.response_content_chars = source["response_content_chars"];
+	        this.response_sha256 = source["response_sha256"];
+	    }
+	}
+	export class PaneProcessStatus {
+	    pane_id: string;
+	    has_child_process: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new PaneProcessStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.pane_id = source["pane_id"];
+	        this.has_child_process = source["has_child_process"];
+	    }
+	}
+	export class FileDropResult {
+	    mode: string;
+	    paths: string[];
+	    needs_confirmation: boolean;
+	    conflicts: string[];
+	    typed: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new FileDropResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.mode = source["mode"];
+	        this.paths = source["paths"];
+	        this.needs_confirmation = source["needs_confirmation"];
+	        this.conflicts = source["conflicts"];
+	        this.typed = source["typed"];
+	    }
+	}
+	export class PasteResult {
+	    pasted: boolean;
+	    needs_confirmation: boolean;
+	    lines: number;
+	    bracketed: boolean;
+	    stripped_controls: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new PasteResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.pasted = source["pasted"];
+	        this.needs_confirmation = source["needs_confirmation"];
+	        this.lines = source["lines"];
+	        this.bracketed = source["bracketed"];
+	        this.stripped_controls = source["stripped_controls"];
+	    }
+	}
+	export class SessionToolPaths {
+	    enabled: boolean;
+	    root: string;
+	    entries: toolpaths.Entry[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SessionToolPaths(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.root = source["root"];
+	        this.entries = this.convertValues(source["entries"], toolpaths.Entry);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TaskSchedulerOrchestratorReadiness {
+	    ready: boolean;
+	    db_exists: boolean;
+	    agent_count: number;
+	    has_panes: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new TaskSchedulerOrchestratorReadiness(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ready = source["ready"];
+	        this.db_exists = source["db_exists"];
+	        this.agent_count = source["agent_count"];
+	        this.has_panes = source["has_panes"];
+	    }
+	}
+	export class ValidationRules {
+	    min_override_name_len: number;
+	    min_pre_exec_reset_delay: number;
+	    max_pre_exec_reset_delay: number;
+	    min_pre_exec_idle_timeout: number;
+	    max_pre_exec_idle_timeout: number;
+	    max_message_templates: number;
+	    max_template_name_len: number;
+	    max_template_message_len: number;
+	    min_single_task_runner_clear_delay: number;
+	    max_single_task_runner_clear_delay: number;
+	    min_chat_overlay_percentage: number;
+	    max_chat_overlay_percentage: number;
+	    default_chat_overlay_percentage: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ValidationRules(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.min_override_name_len = source["min_override_name_len"];
+	        this.min_pre_exec_reset_delay = source["min_pre_exec_reset_delay"];
+	        this.max_pre_exec_reset_delay = source["max_pre_exec_reset_delay"];
+	        this.min_pre_exec_idle_timeout = source["min_pre_exec_idle_timeout"];
+	        this.max_pre_exec_idle_timeout = source["max_pre_exec_idle_timeout"];
+	        this.max_message_templates = source["max_message_templates"];
+	        this.max_template_name_len = source["max_template_name_len"];
+	        this.max_template_message_len = source["max_template_message_len"];
+	        this.min_single_task_runner_clear_delay = source["min_single_task_runner_clear_delay"];
+	        this.max_single_task_runner_clear_delay = source["max_single_task_runner_clear_delay"];
+	        this.min_chat_overlay_percentage = source["min_chat_overlay_percentage"];
+	        this.max_chat_overlay_percentage = source["max_chat_overlay_percentage"];
+	        this.default_chat_overlay_percentage = source["default_chat_overlay_percentage"];
+	    }
+	}
+
+}
+
+export namespace maintenance {
+	
+	export class JobStatus {
+	    name: string;
+	    description: string;
+	    interval_sec: number;
+	    last_run?: string;
+	    last_duration_ms?: number;
+	    last_error?: string;
+	    running: boolean;
+	    next_due?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new JobStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.interval_sec = source["interval_sec"];
+	        this.last_run = source["last_run"];
+	        this.last_duration_ms = source["last_duration_ms"];
+	        this.last_error = source["last_error"];
+	        this.running = source["running"];
+	        this.next_due = source["next_due"];
+	    }
+	}
+
+}
+
+export namespace mcp {
+	
+	export class ConfigParam {
+	    key: string;
+	    label: string;
+	    default_value: string;
+	    description?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConfigParam(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.key = source["key"];
+	        this.label = source["label"];
+	        this.default_value = source["default_value"];
+	        this.description = source["description"];
+	    }
+	}
+	export class PermissionViolation {
+	    kind: string;
+	    detail: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PermissionViolation(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.kind = source["kind"];
+	        this.detail = source["detail"];
+	    }
+	}
+	export class Snapshot {
+	    id: string;
+	    name: string;
+	    description: string;
+	    enabled: boolean;
+	    status: string;
+	    error?: string;
+	    usage_sample?: string;
+	    config_params?: ConfigParam[];
+	    pipe_path?: string;
+	    bridge_command?: string;
+	    bridge_args?: string[];
+	    kind?: string;
+	    restricted?: boolean;
+	    violations?: PermissionViolation[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Snapshot(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.enabled = source["enabled"];
+	        this.status = source["status"];
+	        this.error = source["error"];
+	        this.usage_sample = source["usage_sample"];
+	        this.config_params = this.convertValues(source["config_params"], ConfigParam);
+	        this.pipe_path = source["pipe_path"];
+	        this.bridge_command = source["bridge_command"];
+	        this.bridge_args = source["bridge_args"];
+	        this.kind = source["kind"];
+	        this.restricted = source["restricted"];
+	        this.violations = this.convertValues(source["violations"], PermissionViolation);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace netcapture {
+	
+	export class Record {
+	    id: string;
+	    session: string;
+	    started_at: string;
+	    duration_ms: number;
+	    method: string;
+	    scheme: string;
+	    host: string;
+	    path?: string;
+	    status?: number;
+	    request_bytes: number;
+	    response_bytes: number;
+	    content_type?: string;
+	    error?: string;
+	    request_body?: string;
+	    response_body?: string;
+	    body_truncated?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Record(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.session = source["session"];
+	        this.started_at = source["started_at"];
+	        this.duration_ms = source["duration_ms"];
+	        this.method = source["method"];
+	        this.scheme = source["scheme"];
+	        this.host = source["host"];
+	        this.path = source["path"];
+	        this.status = source["status"];
+	        this.request_bytes = source["request_bytes"];
+	        this.response_bytes = source["response_bytes"];
+	        this.content_type = source["content_type"];
+	        this.error = source["error"];
+	        this.request_body = source["request_body"];
+	        this.response_body = source["response_body"];
+	        this.body_truncated = source["body_truncated"];
+	    }
+	}
+	export class Status {
+	    session_name: string;
+	    enabled: boolean;
+	    proxy_url?: string;
+	    capture_bodies: boolean;
+	    captured: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Status(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.session_name = source["session_name"];
+	        this.enabled = source["enabled"];
+	        this.proxy_url = source["proxy_url"];
+	        this.capture_bodies = source["capture_bodies"];
+	        this.captured = source["captured"];
+	    }
+	}
+
+}
+
+export namespace orchestrator {
+	
+	export class TeamMemberSkill {
+	    name: string;
+	    description?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TeamMemberSkill(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.description = source["description"];
+	    }
+	}
+	export class TeamMember {
+	    id: string;
+	    team_id: string;
+	    order: number;
+	    pane_title: string;
+	    role: string;
+	    command: string;
+	    args: string[];
+	    custom_message: string;
+	    skills?: TeamMemberSkill[];
+	
+	    static createFrom(source: any = {}) {
+	        return new TeamMember(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.team_id = source["team_id"];
+	        this.order = source["order"];
+	        this.pane_title = source["pane_title"];
+	        this.role = source["role"];
+	        this.command = source["command"];
+	        this.args = source["args"];
+	        this.custom_message = source["custom_message"];
+	        this.skills = this.convertValues(source["skills"], TeamMemberSkill);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BootstrapMemberToPaneRequest {
+	    pane_id: string;
+	    pane_state: string;
+	    team_name: string;
+	    member: TeamMember;
+	    bootstrap_delay_ms: number;
+	    session_name: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new OrchestratorTaskDetail(source);
+	        return new BootstrapMemberToPaneRequest(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.task_id = source["task_id"];
-	        this.agent_name = source["agent_name"];
-	        this.sender_name = source["sender_name"];
-	        this.status = source["status"];
-	        this.sent_at = source["sent_at"];
-	        this.completed_at = source["completed_at"];
-	        this.message_content = source["message_content"];
-	        this.message_preview = source["message_preview"];
-	        this.message_storage_mode = source["message_storage_mode"];
-	        this.message_artifact_paths = source["message_artifact_paths"];
-	        this.message_part_count = source["message_part_count"];
-	        this.message_content_chars = source["message_content_chars"];
-	        this.message_sha256 = source["message_sha256"];
-	        this.response_content = source["response_content"];
-	        this.response_preview = source["response_preview"];
-	        this.response_storage_mode = source["response_storage_mode"];
-	        this.response_artifact_paths = source["response_artifact_paths"];
-	        this.response_part_count = source["response_part_count"];
-	        this.response_content_chars = source["response_content_chars"];
-	        this.response_sha256 = source["response_sha256"];
+	        this.pane_id = source["pane_id"];
+	        this.pane_state = source["pane_state"];
+	        this.team_name = source["team_name"];
+	        this.member = this.convertValues(source["member"], TeamMember);
+	        this.bootstrap_delay_ms = source["bootstrap_delay_ms"];
+	        this.session_name = source["session_name"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BootstrapMemberToPaneResult {
+	    warnings: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new BootstrapMemberToPaneResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.warnings = source["warnings"];
 	    }
 	}
-	export class PaneProcessStatus {
+	export class EnlistPaneRequest {
+	    session_name: string;
 	    pane_id: string;
-	    has_child_process: boolean;
+	    team_id: string;
+	    storage_location?: string;
+	    pane_state: string;
+	    bootstrap_delay_ms: number;
+	    member: TeamMember;
 	
 	    static createFrom(source: any = {}) {
-	        return new PaneProcessStatus(source);
+	        return new EnlistPaneRequest(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.session_name = source["session_name"];
 	        this.pane_id = source["pane_id"];
-	        this.has_child_process = source["has_child_process"];
+	        this.team_id = source["team_id"];
+	        this.storage_location = source["storage_location"];
+	        this.pane_state = source["pane_state"];
+	        this.bootstrap_delay_ms = source["bootstrap_delay_ms"];
+	        this.member = this.convertValues(source["member"], TeamMember);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class EnlistPaneResult {
+	    warnings: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new EnlistPaneResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.warnings = source["warnings"];
 	    }
 	}
-	export class TaskSchedulerOrchestratorReadiness {
-	    ready: boolean;
-	    db_exists: boolean;
-	    agent_count: number;
-	    has_panes: boolean;
+	export class TeamDefinition {
+	    id: string;
+	    name: string;
+	    description?: string;
+	    order: number;
+	    bootstrap_delay_ms?: number;
+	    storage_location?: string;
+	    members: TeamMember[];
 	
 	    static createFrom(source: any = {}) {
-	        return new TaskSchedulerOrchestratorReadiness(source);
+	        return new TeamDefinition(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.ready = source["ready"];
-	        this.db_exists = source["db_exists"];
-	        this.agent_count = source["agent_count"];
-	        this.has_panes = source["has_panes"];
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.order = source["order"];
+	        this.bootstrap_delay_ms = source["bootstrap_delay_ms"];
+	        this.storage_location = source["storage_location"];
+	        this.members = this.convertValues(source["members"], TeamMember);
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
-	export class ValidationRules {
-	    min_override_name_len: number;
-	    min_pre_exec_reset_delay: number;
-	    max_pre_exec_reset_delay: number;
-	    min_pre_exec_idle_timeout: number;
-	    max_pre_exec_idle_timeout: number;
-	    max_message_templates: number;
-	    max_template_name_len: number;
-	    max_template_message_len: number;
-	    min_single_task_runner_clear_delay: number;
-	    max_single_task_runner_clear_delay: number;
-	    min_chat_overlay_percentage: number;
-	    max_chat_overlay_percentage: number;
-	    default_chat_overlay_percentage: number;
+	export class SessionEnlistmentContext {
+	    teams: TeamDefinition[];
+	    unaffiliated_members: TeamMember[];
+	    role_catalog: string[];
+	    skill_catalog: TeamMemberSkill[];
+	    registered_pane_ids?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SessionEnlistmentContext(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.teams = this.convertValues(source["teams"], TeamDefinition);
+	        this.unaffiliated_members = this.convertValues(source["unaffiliated_members"], TeamMember);
+	        this.role_catalog = source["role_catalog"];
+	        this.skill_catalog = this.convertValues(source["skill_catalog"], TeamMemberSkill);
+	        this.registered_pane_ids = source["registered_pane_ids"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class StartTeamRequest {
+	    team_id: string;
+	    launch_mode: string;
+	    source_session_name: string;
+	    new_session_name: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new StartTeamRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.team_id = source["team_id"];
+	        this.launch_mode = source["launch_mode"];
+	        this.source_session_name = source["source_session_name"];
+	        this.new_session_name = source["new_session_name"];
+	    }
+	}
+	export class StartTeamResult {
+	    session_name: string;
+	    launch_mode: string;
+	    member_pane_ids: Record<string, string>;
+	    warnings: string[];
 	
 	    static createFrom(source: any = {}) {
-	        return new ValidationRules(source);
+	        return new StartTeamResult(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.min_override_name_len = source["min_override_name_len"];
-	        this.min_pre_exec_reset_delay = source["min_pre_exec_reset_delay"];
-	        this.max_pre_exec_reset_delay = source["max_pre_exec_reset_delay"];
-	        this.min_pre_exec_idle_timeout = source["min_pre_exec_idle_timeout"];
-	        this.max_pre_exec_idle_timeout = source["max_pre_exec_idle_timeout"];
-	        this.max_message_templates = source["max_message_templates"];
-	        this.max_template_name_len = source["max_template_name_len"];
-	        this.max_template_message_len = source["max_template_message_len"];
-	        this.min_single_task_runner_clear_delay = source["min_single_task_runner_clear_delay"];
-	        this.max_single_task_runner_clear_delay = source["max_single_task_runner_clear_delay"];
-	        this.min_chat_overlay_percentage = source["min_chat_overlay_percentage"];
-	        this.max_chat_overlay_percentage = source["max_chat_overlay_percentage"];
-	        this.default_chat_overlay_percentage = source["default_chat_overlay_percentage"];
+	        this.session_name = source["session_name"];
+	        this.launch_mode = source["launch_mode"];
+	        this.member_pane_ids = source["member_pane_ids"];
+	        this.warnings = source["warnings"];
 	    }
 	}
+	
+	
 
 }
 
-export namespace mcp {
-	
-	export class ConfigParam {
-	    key: string;
-	    label: string;
-	    default_value: string;
-	    description?: string;
-	
-	    static createFrom(source: any = {}) {
-	        return new ConfigParam(source);
-	    }
+export namespace outputwatch {
 	
-	    constructor(source: any = {}) {
-	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.key = source["key"];
-	        this.label = source["label"];
-	        this.default_value = source["default_value"];
-	        this.description = source["description"];
-	    }
-	}
-	export class Snapshot {
+	export class Watch {
 	    id: string;
-	    name: string;
-	    description: string;
-	    enabled: boolean;
-	    status: string;
-	    error?: string;
-	    usage_sample?: string;
-	    config_params?: ConfigParam[];
-	    pipe_path?: string;
-	    bridge_command?: string;
-	    bridge_args?: string[];
-	    kind?: string;
+	    target: string;
+	    pattern: string;
+	    action: string;
+	    hook?: string;
+	    // Go type: time
+	    created_at: any;
 	
 	    static createFrom(source: any = {}) {
-	        return new Snapshot(source);
+	        return new Watch(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.id = source["id"];
-	        this.name = source["name"];
-	        this.description = source["description"];
-	        this.enabled = source["enabled"];
-	        this.status = source["status"];
-	        this.error = source["error"];
-	        this.usage_sample = source["usage_sample"];
-	        this.config_params = this.convertValues(source["config_params"], ConfigParam);
-	        this.pipe_path = source["pipe_path"];
-	        this.bridge_command = source["bridge_command"];
-	        this.bridge_args = source["bridge_args"];
-	        this.kind = source["kind"];
+	        this.target = source["target"];
+	        this.pattern = source["pattern"];
+	        this.action = source["action"];
+	        this.hook = source["hook"];
+	        this.created_at = this.convertValues(source["created_at"], null);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -1095,48 +3142,27 @@ export namespace mcp {
 
 }
 
-export namespace orchestrator {
-	
-	export class TeamMemberSkill {
-	    name: string;
-	    description?: string;
-	
-	    static createFrom(source: any = {}) {
-	        return new TeamMemberSkill(source);
-	    }
+export namespace paneactivity {
 	
-	    constructor(source: any = {}) {
-	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.name = source["name"];
-	        this.description = source["description"];
-	    }
-	}
-	export class TeamMember {
-	    id: string;
-	    team_id: string;
-	    order: number;
-	    pane_title: string;
-	    role: string;
-	    command: string;
-	    args: string[];
-	    custom_message: string;
-	    skills?: TeamMemberSkill[];
+	export class Activity {
+	    pane_id: string;
+	    session_name: string;
+	    state: string;
+	    // Go type: time
+	    since: any;
+	    has_child_process: boolean;
 	
 	    static createFrom(source: any = {}) {
-	        return new TeamMember(source);
+	        return new Activity(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.id = source["id"];
-	        this.team_id = source["team_id"];
-	        this.order = source["order"];
-	        this.pane_title = source["pane_title"];
-	        this.role = source["role"];
-	        this.command = source["command"];
-	        this.args = source["args"];
-	        this.custom_message = source["custom_message"];
-	        this.skills = this.convertValues(source["skills"], TeamMemberSkill);
+	        this.pane_id = source["pane_id"];
+	        this.session_name = source["session_name"];
+	        this.state = source["state"];
+	        this.since = this.convertValues(source["since"], null);
+	        this.has_child_process = source["has_child_process"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -1157,26 +3183,57 @@ export namespace orchestrator {
 		    return a;
 		}
 	}
-	export class BootstrapMemberToPaneRequest {
+
+}
+
+export namespace preopsnapshot {
+	
+	export class PaneTail {
 	    pane_id: string;
-	    pane_state: string;
-	    team_name: string;
-	    member: TeamMember;
-	    bootstrap_delay_ms: number;
-	    session_name: string;
+	    window?: string;
+	    title?: string;
+	    lines: string[];
 	
 	    static createFrom(source: any = {}) {
-	        return new BootstrapMemberToPaneRequest(source);
+	        return new PaneTail(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.pane_id = source["pane_id"];
-	        this.pane_state = source["pane_state"];
-	        this.team_name = source["team_name"];
-	        this.member = this.convertValues(source["member"], TeamMember);
-	        this.bootstrap_delay_ms = source["bootstrap_delay_ms"];
-	        this.session_name = source["session_name"];
+	        this.window = source["window"];
+	        this.title = source["title"];
+	        this.lines = source["lines"];
+	    }
+	}
+	export class Snapshot {
+	    id: string;
+	    session: string;
+	    operation: string;
+	    detail?: string;
+	    captured_at: string;
+	    work_dir?: string;
+	    layout?: string;
+	    git_status?: string;
+	    panes?: PaneTail[];
+	    errors?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Snapshot(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.session = source["session"];
+	        this.operation = source["operation"];
+	        this.detail = source["detail"];
+	        this.captured_at = source["captured_at"];
+	        this.work_dir = source["work_dir"];
+	        this.layout = source["layout"];
+	        this.git_status = source["git_status"];
+	        this.panes = this.convertValues(source["panes"], PaneTail);
+	        this.errors = source["errors"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -1197,40 +3254,43 @@ export namespace orchestrator {
 		    return a;
 		}
 	}
-	export class BootstrapMemberToPaneResult {
-	    warnings: string[];
+
+}
+
+export namespace promptpresets {
+	
+	export class PromptPreset {
+	    id: string;
+	    name: string;
+	    body: string;
+	    order: number;
+	    storage_location?: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new BootstrapMemberToPaneResult(source);
+	        return new PromptPreset(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.warnings = source["warnings"];
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.body = source["body"];
+	        this.order = source["order"];
+	        this.storage_location = source["storage_location"];
 	    }
 	}
-	export class EnlistPaneRequest {
-	    session_name: string;
-	    pane_id: string;
-	    team_id: string;
-	    storage_location?: string;
-	    pane_state: string;
-	    bootstrap_delay_ms: number;
-	    member: TeamMember;
+	export class LoadResult {
+	    presets: PromptPreset[];
+	    warnings?: string[];
 	
 	    static createFrom(source: any = {}) {
-	        return new EnlistPaneRequest(source);
+	        return new LoadResult(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.session_name = source["session_name"];
-	        this.pane_id = source["pane_id"];
-	        this.team_id = source["team_id"];
-	        this.storage_location = source["storage_location"];
-	        this.pane_state = source["pane_state"];
-	        this.bootstrap_delay_ms = source["bootstrap_delay_ms"];
-	        this.member = this.convertValues(source["member"], TeamMember);
+	        this.presets = this.convertValues(source["presets"], PromptPreset);
+	        this.warnings = source["warnings"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -1251,40 +3311,76 @@ export namespace orchestrator {
 		    return a;
 		}
 	}
-	export class EnlistPaneResult {
-	    warnings: string[];
+
+}
+
+export namespace recentdirs {
+	
+	export class Directory {
+	    path: string;
+	    pinned: boolean;
+	    last_used: string;
+	    exists: boolean;
+	    is_git_repo: boolean;
 	
 	    static createFrom(source: any = {}) {
-	        return new EnlistPaneResult(source);
+	        return new Directory(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.warnings = source["warnings"];
+	        this.path = source["path"];
+	        this.pinned = source["pinned"];
+	        this.last_used = source["last_used"];
+	        this.exists = source["exists"];
+	        this.is_git_repo = source["is_git_repo"];
 	    }
 	}
-	export class TeamDefinition {
-	    id: string;
-	    name: string;
-	    description?: string;
-	    order: number;
-	    bootstrap_delay_ms?: number;
-	    storage_location?: string;
-	    members: TeamMember[];
+
+}
+
+export namespace rendering {
+	
+	export class DriverReset {
+	    time: string;
+	    provider: string;
+	    event_id: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new TeamDefinition(source);
+	        return new DriverReset(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.id = source["id"];
-	        this.name = source["name"];
-	        this.description = source["description"];
-	        this.order = source["order"];
-	        this.bootstrap_delay_ms = source["bootstrap_delay_ms"];
-	        this.storage_location = source["storage_location"];
-	        this.members = this.convertValues(source["members"], TeamMember);
+	        this.time = source["time"];
+	        this.provider = source["provider"];
+	        this.event_id = source["event_id"];
+	    }
+	}
+	export class Diagnostics {
+	    software_rendering: boolean;
+	    software_rendering_next_launch: boolean;
+	    reason?: string;
+	    fallback_since?: string;
+	    unready_launches: number;
+	    ready: boolean;
+	    driver_resets: DriverReset[];
+	    driver_resets_error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Diagnostics(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.software_rendering = source["software_rendering"];
+	        this.software_rendering_next_launch = source["software_rendering_next_launch"];
+	        this.reason = source["reason"];
+	        this.fallback_since = source["fallback_since"];
+	        this.unready_launches = source["unready_launches"];
+	        this.ready = source["ready"];
+	        this.driver_resets = this.convertValues(source["driver_resets"], DriverReset);
+	        this.driver_resets_error = source["driver_resets_error"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -1305,24 +3401,47 @@ export namespace orchestrator {
 		    return a;
 		}
 	}
-	export class SessionEnlistmentContext {
-	    teams: TeamDefinition[];
-	    unaffiliated_members: TeamMember[];
-	    role_catalog: string[];
-	    skill_catalog: TeamMemberSkill[];
-	    registered_pane_ids?: string[];
+
+}
+
+export namespace repoconfig {
+	
+	export class Config {
+	    setup_scripts: string[];
+	    startup_command: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new SessionEnlistmentContext(source);
+	        return new Config(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.teams = this.convertValues(source["teams"], TeamDefinition);
-	        this.unaffiliated_members = this.convertValues(source["unaffiliated_members"], TeamMember);
-	        this.role_catalog = source["role_catalog"];
-	        this.skill_catalog = this.convertValues(source["skill_catalog"], TeamMemberSkill);
-	        this.registered_pane_ids = source["registered_pane_ids"];
+	        this.setup_scripts = source["setup_scripts"];
+	        this.startup_command = source["startup_command"];
+	    }
+	}
+	export class Status {
+	    path: string;
+	    level: string;
+	    trusted_by?: string;
+	    config?: Config;
+	    config_error?: string;
+	    signature?: string;
+	    signed_by?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Status(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.level = source["level"];
+	        this.trusted_by = source["trusted_by"];
+	        this.config = this.convertValues(source["config"], Config);
+	        this.config_error = source["config_error"];
+	        this.signature = source["signature"];
+	        this.signed_by = source["signed_by"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -1343,81 +3462,124 @@ export namespace orchestrator {
 		    return a;
 		}
 	}
-	export class StartTeamRequest {
-	    team_id: string;
-	    launch_mode: string;
-	    source_session_name: string;
-	    new_session_name: string;
+
+}
+
+export namespace repostats {
+	
+	export class LanguageStats {
+	    language: string;
+	    files: number;
+	    lines: number;
+	    bytes: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new StartTeamRequest(source);
+	        return new LanguageStats(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.team_id = source["team_id"];
-	        this.launch_mode = source["launch_mode"];
-	        this.source_session_name = source["source_session_name"];
-	        this.new_session_name = source["new_session_name"];
+	        this.language = source["language"];
+	        this.files = source["files"];
+	        this.lines = source["lines"];
+	        this.bytes = source["bytes"];
 	    }
 	}
-	export class StartTeamResult {
-	    session_name: string;
-	    launch_mode: string;
-	    member_pane_ids: Record<string, string>;
-	    warnings: string[];
+	export class RepoStats {
+	    repo_path: string;
+	    is_git_repo: boolean;
+	    languages: LanguageStats[];
+	    total_files: number;
+	    total_lines: number;
+	    total_bytes: number;
+	    truncated: boolean;
 	
 	    static createFrom(source: any = {}) {
-	        return new StartTeamResult(source);
+	        return new RepoStats(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.session_name = source["session_name"];
-	        this.launch_mode = source["launch_mode"];
-	        this.member_pane_ids = source["member_pane_ids"];
-	        this.warnings = source["warnings"];
+	        this.repo_path = source["repo_path"];
+	        this.is_git_repo = source["is_git_repo"];
+	        this.languages = this.convertValues(source["languages"], LanguageStats);
+	        this.total_files = source["total_files"];
+	        this.total_lines = source["total_lines"];
+	        this.total_bytes = source["total_bytes"];
+	        this.truncated = source["truncated"];
 	    }
-	}
-	
 	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 
 }
 
-export namespace promptpresets {
+export namespace resourcelimit {
 	
-	export class PromptPreset {
-	    id: string;
-	    name: string;
-	    body: string;
-	    order: number;
-	    storage_location?: string;
+	export class Limits {
+	    memory_mb: number;
+	    cpu_percent: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new PromptPreset(source);
+	        return new Limits(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.id = source["id"];
-	        this.name = source["name"];
-	        this.body = source["body"];
-	        this.order = source["order"];
-	        this.storage_location = source["storage_location"];
+	        this.memory_mb = source["memory_mb"];
+	        this.cpu_percent = source["cpu_percent"];
 	    }
 	}
-	export class LoadResult {
-	    presets: PromptPreset[];
-	    warnings?: string[];
+	export class PaneUsage {
+	    pane_id: string;
+	    pid: number;
+	    processes: number;
+	    cpu_percent: number;
+	    rss_bytes: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new LoadResult(source);
+	        return new PaneUsage(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.presets = this.convertValues(source["presets"], PromptPreset);
-	        this.warnings = source["warnings"];
+	        this.pane_id = source["pane_id"];
+	        this.pid = source["pid"];
+	        this.processes = source["processes"];
+	        this.cpu_percent = source["cpu_percent"];
+	        this.rss_bytes = source["rss_bytes"];
+	    }
+	}
+	export class SessionUsage {
+	    session_name: string;
+	    limits: Limits;
+	    panes: PaneUsage[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SessionUsage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.session_name = source["session_name"];
+	        this.limits = this.convertValues(source["limits"], Limits);
+	        this.panes = this.convertValues(source["panes"], PaneUsage);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -1492,6 +3654,109 @@ export namespace scheduler {
 
 }
 
+export namespace session {
+	
+	export class QueryResult {
+	    sessions: tmux.SessionSnapshot[];
+	    total: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new QueryResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.sessions = this.convertValues(source["sessions"], tmux.SessionSnapshot);
+	        this.total = source["total"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace sessioncopy {
+	
+	export class Result {
+	    id: string;
+	    source: string;
+	    destination: string;
+	    files: number;
+	    bytes: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Result(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.source = source["source"];
+	        this.destination = source["destination"];
+	        this.files = source["files"];
+	        this.bytes = source["bytes"];
+	    }
+	}
+
+}
+
+export namespace sessiongroup {
+	
+	export class Member {
+	    repo_path: string;
+	    session_name: string;
+	    worktree?: worktree.WorktreeSessionOptions;
+	    options: worktree.SessionEnvOptions;
+	
+	    static createFrom(source: any = {}) {
+	        return new Member(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.repo_path = source["repo_path"];
+	        this.session_name = source["session_name"];
+	        this.worktree = this.convertValues(source["worktree"], worktree.WorktreeSessionOptions);
+	        this.options = this.convertValues(source["options"], worktree.SessionEnvOptions);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
 export namespace sessionlog {
 	
 	export class Entry {
@@ -1517,6 +3782,92 @@ export namespace sessionlog {
 
 }
 
+export namespace sessionports {
+	
+	export class Port {
+	    session_name: string;
+	    pane_id: string;
+	    port: number;
+	    pid: number;
+	    process?: string;
+	    address: string;
+	    url: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Port(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.session_name = source["session_name"];
+	        this.pane_id = source["pane_id"];
+	        this.port = source["port"];
+	        this.pid = source["pid"];
+	        this.process = source["process"];
+	        this.address = source["address"];
+	        this.url = source["url"];
+	    }
+	}
+
+}
+
+export namespace sessionquery {
+	
+	export class Filter {
+	    text?: string;
+	    repo?: string;
+	    branch?: string;
+	    status?: string;
+	    badge?: string;
+	    active_within_seconds?: number;
+	    include_detached?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Filter(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.text = source["text"];
+	        this.repo = source["repo"];
+	        this.branch = source["branch"];
+	        this.status = source["status"];
+	        this.badge = source["badge"];
+	        this.active_within_seconds = source["active_within_seconds"];
+	        this.include_detached = source["include_detached"];
+	    }
+	}
+	export class Page {
+	    offset?: number;
+	    limit?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Page(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.offset = source["offset"];
+	        this.limit = source["limit"];
+	    }
+	}
+	export class Sort {
+	    by?: string;
+	    descending?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Sort(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.by = source["by"];
+	        this.descending = source["descending"];
+	    }
+	}
+
+}
+
 export namespace singletaskrunner {
 	
 	export class QueueItem {
@@ -1565,18 +3916,79 @@ export namespace singletaskrunner {
 	    last_stop_reason?: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new QueueStatus(source);
+	        return new QueueStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.items = this.convertValues(source["items"], QueueItem);
+	        this.run_status = source["run_status"];
+	        this.current_index = source["current_index"];
+	        this.session_name = source["session_name"];
+	        this.generation_id = source["generation_id"];
+	        this.clear_delay_sec = source["clear_delay_sec"];
+	        this.last_stop_reason = source["last_stop_reason"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace startupmetrics {
+	
+	export class Phase {
+	    name: string;
+	    start_ms: number;
+	    duration_ms: number;
+	    background?: boolean;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Phase(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.start_ms = source["start_ms"];
+	        this.duration_ms = source["duration_ms"];
+	        this.background = source["background"];
+	        this.error = source["error"];
+	    }
+	}
+	export class Report {
+	    startup_ms: number;
+	    dom_ready_ms: number;
+	    phases: Phase[];
+	    pending: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Report(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.items = this.convertValues(source["items"], QueueItem);
-	        this.run_status = source["run_status"];
-	        this.current_index = source["current_index"];
-	        this.session_name = source["session_name"];
-	        this.generation_id = source["generation_id"];
-	        this.clear_delay_sec = source["clear_delay_sec"];
-	        this.last_stop_reason = source["last_stop_reason"];
+	        this.startup_ms = source["startup_ms"];
+	        this.dom_ready_ms = source["dom_ready_ms"];
+	        this.phases = this.convertValues(source["phases"], Phase);
+	        this.pending = source["pending"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -1763,6 +4175,22 @@ export namespace tmux {
 	        this.height = source["height"];
 	    }
 	}
+	export class SessionBadge {
+	    color?: string;
+	    emoji?: string;
+	    auto?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new SessionBadge(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.color = source["color"];
+	        this.emoji = source["emoji"];
+	        this.auto = source["auto"];
+	    }
+	}
 	export class SessionWorktreeInfo {
 	    path?: string;
 	    repo_path?: string;
@@ -1781,6 +4209,8 @@ export namespace tmux {
 	        this.branch_name = source["branch_name"];
 	        this.base_branch = source["base_branch"];
 	        this.is_detached = source["is_detached"];
+	        this.locked = source["locked"];
+	        this.lock_reason = source["lock_reason"];
 	    }
 	}
 	export class WindowSnapshot {
@@ -1829,9 +4259,13 @@ export namespace tmux {
 	    is_idle: boolean;
 	    active_window_id: number;
 	    is_agent_team?: boolean;
+	    detached?: boolean;
+	    badge?: SessionBadge;
 	    windows: WindowSnapshot[];
 	    worktree?: SessionWorktreeInfo;
 	    root_path?: string;
+	    taskbar_alerts_muted?: boolean;
+	    group?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new SessionSnapshot(source);
@@ -1845,9 +4279,13 @@ export namespace tmux {
 	        this.is_idle = source["is_idle"];
 	        this.active_window_id = source["active_window_id"];
 	        this.is_agent_team = source["is_agent_team"];
+	        this.detached = source["detached"];
+	        this.badge = this.convertValues(source["badge"], SessionBadge);
 	        this.windows = this.convertValues(source["windows"], WindowSnapshot);
 	        this.worktree = this.convertValues(source["worktree"], SessionWorktreeInfo);
 	        this.root_path = source["root_path"];
+	        this.taskbar_alerts_muted = source["taskbar_alerts_muted"];
+	        this.group = source["group"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -1872,6 +4310,27 @@ export namespace tmux {
 
 }
 
+export namespace toolpaths {
+	
+	export class Entry {
+	    kind: string;
+	    manifest: string;
+	    dir: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Entry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.kind = source["kind"];
+	        this.manifest = source["manifest"];
+	        this.dir = source["dir"];
+	    }
+	}
+
+}
+
 export namespace usagedashboard {
 	
 	export class SourceHealth {
@@ -2144,10 +4603,160 @@ export namespace usagedashboard {
 
 export namespace worktree {
 	
+	export class BaseBranchStatus {
+	    session_name: string;
+	    base_branch: string;
+	    base_ref: string;
+	    base_commit: string;
+	    behind: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new BaseBranchStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.session_name = source["session_name"];
+	        this.base_branch = source["base_branch"];
+	        this.base_ref = source["base_ref"];
+	        this.base_commit = source["base_commit"];
+	        this.behind = source["behind"];
+	    }
+	}
+	export class BaseRefreshResult {
+	    session_name: string;
+	    base_ref: string;
+	    strategy: string;
+	    trigger: string;
+	    behind: number;
+	    refreshed: boolean;
+	    conflicts: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new BaseRefreshResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.session_name = source["session_name"];
+	        this.base_ref = source["base_ref"];
+	        this.strategy = source["strategy"];
+	        this.trigger = source["trigger"];
+	        this.behind = source["behind"];
+	        this.refreshed = source["refreshed"];
+	        this.conflicts = source["conflicts"];
+	    }
+	}
+	export class CommitAndPushResult {
+	    pull_request?: forge.PullRequest;
+	    pull_request_error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CommitAndPushResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.pull_request = this.convertValues(source["pull_request"], forge.PullRequest);
+	        this.pull_request_error = source["pull_request_error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CreatePullRequestOptions {
+	    title?: string;
+	    body?: string;
+	    base?: string;
+	    draft?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new CreatePullRequestOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.title = source["title"];
+	        this.body = source["body"];
+	        this.base = source["base"];
+	        this.draft = source["draft"];
+	    }
+	}
+	export class ExternalWorktree {
+	    repo_path: string;
+	    path: string;
+	    branch_name: string;
+	    is_detached: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ExternalWorktree(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.repo_path = source["repo_path"];
+	        this.path = source["path"];
+	        this.branch_name = source["branch_name"];
+	        this.is_detached = source["is_detached"];
+	    }
+	}
+	export class OrphanPruneResult {
+	    dryRun: boolean;
+	    removed: string[];
+	    skipped?: SkippedOrphan[];
+	    failures?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new OrphanPruneResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.dryRun = source["dryRun"];
+	        this.removed = source["removed"];
+	        this.skipped = this.convertValues(source["skipped"], SkippedOrphan);
+	        this.failures = source["failures"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class OrphanedWorktree {
 	    path: string;
 	    branchName: string;
 	    hasChanges: boolean;
+	    locked: boolean;
+	    untracked: boolean;
 	    health?: git.WorktreeHealth;
 	
 	    static createFrom(source: any = {}) {
@@ -2159,6 +4768,8 @@ export namespace worktree {
 	        this.path = source["path"];
 	        this.branchName = source["branchName"];
 	        this.hasChanges = source["hasChanges"];
+	        this.locked = source["locked"];
+	        this.untracked = source["untracked"];
 	        this.health = this.convertValues(source["health"], git.WorktreeHealth);
 	    }
 	
@@ -2180,6 +4791,152 @@ export namespace worktree {
 		    return a;
 		}
 	}
+	export class PromoteWorktreeOptions {
+	    rebase_onto_base: boolean;
+	    base_branch?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PromoteWorktreeOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.rebase_onto_base = source["rebase_onto_base"];
+	        this.base_branch = source["base_branch"];
+	    }
+	}
+	export class RepoHygiene {
+	    repoPath: string;
+	    staleBranches: git.StaleBranch[];
+	    prunableWorktrees: git.PrunableWorktree[];
+	    largeUntracked: UntrackedArtifact[];
+	    oldStashes: git.StashEntry[];
+	    warnings?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new RepoHygiene(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.repoPath = source["repoPath"];
+	        this.staleBranches = this.convertValues(source["staleBranches"], git.StaleBranch);
+	        this.prunableWorktrees = this.convertValues(source["prunableWorktrees"], git.PrunableWorktree);
+	        this.largeUntracked = this.convertValues(source["largeUntracked"], UntrackedArtifact);
+	        this.oldStashes = this.convertValues(source["oldStashes"], git.StashEntry);
+	        this.warnings = source["warnings"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class RepoHygieneCleanup {
+	    deleteBranches: string[];
+	    pruneWorktrees: boolean;
+	    dropStashes: string[];
+	    deleteUntracked: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new RepoHygieneCleanup(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.deleteBranches = source["deleteBranches"];
+	        this.pruneWorktrees = source["pruneWorktrees"];
+	        this.dropStashes = source["dropStashes"];
+	        this.deleteUntracked = source["deleteUntracked"];
+	    }
+	}
+	export class RepoHygieneCleanupResult {
+	    deletedBranches: string[];
+	    prunedWorktrees: boolean;
+	    droppedStashes: string[];
+	    deletedUntracked: string[];
+	    failures?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new RepoHygieneCleanupResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.deletedBranches = source["deletedBranches"];
+	        this.prunedWorktrees = source["prunedWorktrees"];
+	        this.droppedStashes = source["droppedStashes"];
+	        this.deletedUntracked = source["deletedUntracked"];
+	        this.failures = source["failures"];
+	    }
+	}
+	export class SessionEnvOptions {
+	    enable_agent_team: boolean;
+	    use_claude_env: boolean;
+	    use_pane_env: boolean;
+	    use_session_pane_scope: boolean;
+	    startup_command?: string;
+	    remain_on_exit?: boolean;
+	    isolate_shell_history?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new SessionEnvOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enable_agent_team = source["enable_agent_team"];
+	        this.use_claude_env = source["use_claude_env"];
+	        this.use_pane_env = source["use_pane_env"];
+	        this.use_session_pane_scope = source["use_session_pane_scope"];
+	        this.startup_command = source["startup_command"];
+	        this.remain_on_exit = source["remain_on_exit"];
+	        this.isolate_shell_history = source["isolate_shell_history"];
+	    }
+	}
+	export class SkippedOrphan {
+	    path: string;
+	    reason: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SkippedOrphan(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.reason = source["reason"];
+	    }
+	}
+	export class UntrackedArtifact {
+	    path: string;
+	    worktreePath: string;
+	    size: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new UntrackedArtifact(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.worktreePath = source["worktreePath"];
+	        this.size = source["size"];
+	    }
+	}
 	export class WorktreeSessionOptions {
 	    branch_name: string;
 	    base_branch: string;
@@ -2189,6 +4946,10 @@ export namespace worktree {
 	    use_claude_env: boolean;
 	    use_pane_env: boolean;
 	    use_session_pane_scope: boolean;
+	    startup_command?: string;
+	    remain_on_exit?: boolean;
+	    isolate_shell_history?: boolean;
+	    ephemeral?: boolean;
 	
 	    static createFrom(source: any = {}) {
 	        return new WorktreeSessionOptions(source);
@@ -2204,7 +4965,45 @@ export namespace worktree {
 	        this.use_claude_env = source["use_claude_env"];
 	        this.use_pane_env = source["use_pane_env"];
 	        this.use_session_pane_scope = source["use_session_pane_scope"];
+	        this.startup_command = source["startup_command"];
+	        this.remain_on_exit = source["remain_on_exit"];
+	        this.isolate_shell_history = source["isolate_shell_history"];
+	        this.ephemeral = source["ephemeral"];
+	    }
+	}
+	export class WorktreeStashPopResult {
+	    stash: git.StashEntry;
+	    conflicts: string[];
+	    dropped: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new WorktreeStashPopResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.stash = this.convertValues(source["stash"], git.StashEntry);
+	        this.conflicts = source["conflicts"];
+	        this.dropped = source["dropped"];
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
 	export class WorktreeStatus {
 	    has_worktree: boolean;
@@ -2212,6 +5011,8 @@ export namespace worktree {
 	    has_unpushed: boolean;
 	    branch_name: string;
 	    is_detached: boolean;
+	    locked: boolean;
+	    lock_reason?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new WorktreeStatus(source);
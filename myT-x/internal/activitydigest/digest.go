@@ -0,0 +1,190 @@
+package activitydigest
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	gitpkg "myT-x/internal/git"
+)
+
+// Digest is the activity of every session on one local calendar day.
+type Digest struct {
+	Date     string          `json:"date"`
+	Sessions []SessionDigest `json:"sessions"`
+}
+
+// SessionDigest is the activity of one session.
+type SessionDigest struct {
+	SessionName string `json:"session_name"`
+	WorkDir     string `json:"work_dir"`
+	// Commands lists the first commands run; CommandCount counts all of them.
+	Commands     []string `json:"commands"`
+	CommandCount int      `json:"command_count"`
+	// FailedCommands counts non-zero exit codes reported by shell integration.
+	FailedCommands int                    `json:"failed_commands"`
+	Commits        []gitpkg.CommitSummary `json:"commits"`
+	TestsPassed    int                    `json:"tests_passed"`
+	TestsFailed    int                    `json:"tests_failed"`
+	ActiveMinutes  int                    `json:"active_minutes"`
+	// CostEstimate is ActiveMinutes priced at cost_per_active_hour; zero when
+	// no rate is configured.
+	CostEstimate float64 `json:"cost_estimate"`
+}
+
+func (d SessionDigest) idle() bool {
+	return d.ActiveMinutes == 0 && d.CommandCount == 0 && d.FailedCommands == 0 &&
+		len(d.Commits) == 0 && d.TestsPassed == 0 && d.TestsFailed == 0
+}
+
+// GeneratedPayload is the payload of GeneratedEvent.
+type GeneratedPayload struct {
+	Date string `json:"date"`
+	Path string `json:"path"`
+	// Summary is a one-line overview; webhooks use it as the message text.
+	Summary  string `json:"summary"`
+	Markdown string `json:"markdown"`
+	Digest   Digest `json:"digest"`
+}
+
+// testCommandPattern matches the test runners of common toolchains at the
+// start of a command or after a shell separator.
+var testCommandPattern = regexp.MustCompile(`(?i)(?:^|[;&|(]\s*)(?:\S*[\\/])?(?:` +
+	`go test|cargo (?:test|nextest)|(?:npm|pnpm|yarn|bun)(?: run)? test|` +
+	`npx (?:vitest|jest)|vitest|jest|(?:python3? -m )?pytest|dotnet test|` +
+	`(?:mvnw?|gradlew?)(?:\.cmd|\.bat)?(?: \S+)* test|make test|rspec|phpunit)\b`)
+
+// IsTestCommand reports whether command runs a test suite.
+func IsTestCommand(command string) bool {
+	return testCommandPattern.MatchString(strings.TrimSpace(command))
+}
+
+// Summary returns a one-line overview of digest.
+func Summary(digest Digest) string {
+	var commits, passed, failed, minutes int
+	for _, session := range digest.Sessions {
+		commits += len(session.Commits)
+		passed += session.TestsPassed
+		failed += session.TestsFailed
+		minutes += session.ActiveMinutes
+	}
+	return fmt.Sprintf("Activity digest %s: %d session(s), %s active, %d commit(s), tests %d passed / %d failed",
+		digest.Date, len(digest.Sessions), formatMinutes(minutes), commits, passed, failed)
+}
+
+// RenderMarkdown renders digest as a Markdown document. withCost adds the
+// cost estimate column and lines.
+func RenderMarkdown(digest Digest, withCost bool) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Activity digest %s\n\n", digest.Date)
+	if len(digest.Sessions) == 0 {
+		b.WriteString("No session activity.\n")
+		return b.String()
+	}
+
+	b.WriteString("| Session | Active | Commands | Commits | Tests |")
+	if withCost {
+		b.WriteString(" Cost |")
+	}
+	b.WriteString("\n|---|---|---|---|---|")
+	if withCost {
+		b.WriteString("---|")
+	}
+	b.WriteString("\n")
+	for _, session := range digest.Sessions {
+		fmt.Fprintf(&b, "| %s | %s | %s | %d | %s |",
+			escapeTableCell(session.SessionName), formatMinutes(session.ActiveMinutes),
+			formatCommandCount(session), len(session.Commits), formatTests(session))
+		if withCost {
+			fmt.Fprintf(&b, " %.2f |", session.CostEstimate)
+		}
+		b.WriteString("\n")
+	}
+
+	for _, session := range digest.Sessions {
+		fmt.Fprintf(&b, "\n## %s\n\n", session.SessionName)
+		if session.WorkDir != "" {
+			fmt.Fprintf(&b, "- Directory: %s\n", inlineCode(session.WorkDir))
+		}
+		fmt.Fprintf(&b, "- Active: %s\n", formatMinutes(session.ActiveMinutes))
+		fmt.Fprintf(&b, "- Commands: %s\n", formatCommandCount(session))
+		fmt.Fprintf(&b, "- Tests: %s\n", formatTests(session))
+		if withCost {
+			fmt.Fprintf(&b, "- Cost estimate: %.2f\n", session.CostEstimate)
+		}
+		if len(session.Commits) > 0 {
+			b.WriteString("\n### Commits\n\n")
+			for _, commit := range session.Commits {
+				fmt.Fprintf(&b, "- %s %s\n", inlineCode(commit.Commit[:min(len(commit.Commit), 7)]), commit.Subject)
+			}
+		}
+		if len(session.Commands) > 0 {
+			b.WriteString("\n### Commands\n\n")
+			writeCodeBlock(&b, session.Commands)
+			if more := session.CommandCount - len(session.Commands); more > 0 {
+				fmt.Fprintf(&b, "\n…and %d more.\n", more)
+			}
+		}
+	}
+	return b.String()
+}
+
+func formatMinutes(minutes int) string {
+	return fmt.Sprintf("%dh %02dm", minutes/60, minutes%60)
+}
+
+func formatCommandCount(session SessionDigest) string {
+	if session.FailedCommands == 0 {
+		return fmt.Sprintf("%d", session.CommandCount)
+	}
+	return fmt.Sprintf("%d (%d failed)", session.CommandCount, session.FailedCommands)
+}
+
+func formatTests(session SessionDigest) string {
+	if session.TestsPassed == 0 && session.TestsFailed == 0 {
+		return "none"
+	}
+	return fmt.Sprintf("%d passed, %d failed", session.TestsPassed, session.TestsFailed)
+}
+
+func escapeTableCell(text string) string {
+	return strings.ReplaceAll(text, "|", `\|`)
+}
+
+// inlineCode wraps text in a code span whose fence outlasts any backtick
+// run inside it.
+func inlineCode(text string) string {
+	fence := strings.Repeat("`", longestBacktickRun(text)+1)
+	if strings.HasPrefix(text, "`") || strings.HasSuffix(text, "`") {
+		return fence + " " + text + " " + fence
+	}
+	return fence + text + fence
+}
+
+// writeCodeBlock writes lines as a fenced code block. Commands are typed
+// text, so the fence is made longer than any backtick run they contain.
+func writeCodeBlock(b *strings.Builder, lines []string) {
+	longest := 0
+	for _, line := range lines {
+		longest = max(longest, longestBacktickRun(line))
+	}
+	fence := strings.Repeat("`", max(3, longest+1))
+	b.WriteString(fence + "text\n")
+	for _, line := range lines {
+		b.WriteString(strings.ReplaceAll(line, "\n", " ") + "\n")
+	}
+	b.WriteString(fence + "\n")
+}
+
+func longestBacktickRun(text string) int {
+	longest, run := 0, 0
+	for _, r := range text {
+		if r == '`' {
+			run++
+			longest = max(longest, run)
+			continue
+		}
+		run = 0
+	}
+	return longest
+}
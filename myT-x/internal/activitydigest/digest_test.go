@@ -0,0 +1,62 @@
+package activitydigest
+
+import (
+	"strings"
+	"testing"
+
+	gitpkg "myT-x/internal/git"
+)
+
+func TestIsTestCommand(t *testing.T) {
+	tests := []struct {
+		command string
+		want    bool
+	}{
+		{"go test ./...", true},
+		{"  npm run test -- --watch=false", true},
+		{"cd web && pnpm test", true},
+		{`.\gradlew.bat clean test`, true},
+		{"python -m pytest -q", true},
+		{"npx vitest run", true},
+		{"go build ./...", false},
+		{"echo go test", false},
+		{"git commit -m 'add jest config'", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := IsTestCommand(tt.command); got != tt.want {
+			t.Errorf("IsTestCommand(%q) = %v, want %v", tt.command, got, tt.want)
+		}
+	}
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	digest := Digest{Date: "2026-05-16", Sessions: []SessionDigest{{
+		SessionName:   "a|b",
+		WorkDir:       `C:\work`,
+		Commands:      []string{"echo ```x```"},
+		CommandCount:  3,
+		Commits:       []gitpkg.CommitSummary{{Commit: "0123456789", Subject: "fix"}},
+		ActiveMinutes: 75,
+	}}}
+	got := RenderMarkdown(digest, false)
+	for _, want := range []string{
+		"# Activity digest 2026-05-16\n",
+		"| a\\|b | 1h 15m | 3 | 1 | none |\n",
+		"- Directory: `C:\\work`\n",
+		"- `0123456` fix\n",
+		"````text\necho ```x```\n````\n",
+		"…and 2 more.",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("markdown missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "Cost") {
+		t.Errorf("markdown has a cost column without a rate:\n%s", got)
+	}
+
+	if got := RenderMarkdown(Digest{Date: "2026-05-16"}, true); !strings.Contains(got, "No session activity.") {
+		t.Errorf("empty digest markdown = %q", got)
+	}
+}
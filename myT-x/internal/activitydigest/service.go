@@ -0,0 +1,452 @@
+// Package activitydigest compiles a daily record of what each session did:
+// commands run, commits made, test results, active time, and a cost
+// estimate. Digests are saved as Markdown and announced as a runtime event
+// that webhooks can forward.
+package activitydigest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"myT-x/internal/apptypes"
+	"myT-x/internal/config"
+	gitpkg "myT-x/internal/git"
+)
+
+const (
+	// GeneratedEvent is emitted with a GeneratedPayload after the digest of a
+	// finished day has been saved.
+	GeneratedEvent = "activity-digest:generated"
+	// StateFileName is the JSON file the counters of the current day are
+	// persisted to, so a restart does not lose them.
+	StateFileName = "activity-digest-state.json"
+	// dateLayout names digest files and keys the day state.
+	dateLayout = "2006-01-02"
+	// defaultSampleInterval is how often Run samples session activity.
+	defaultSampleInterval = time.Minute
+	// maxCommandsListed bounds the commands listed per session; the count
+	// still covers all of them.
+	maxCommandsListed = 50
+)
+
+// ErrDisabled is returned by GenerateNow when activity_digest is not configured.
+var ErrDisabled = errors.New("activity_digest is not configured")
+
+// SessionInfo describes one live session at sampling time.
+type SessionInfo struct {
+	Name    string
+	WorkDir string
+	// LastActivity is the time of the session's latest pane output.
+	LastActivity time.Time
+}
+
+// Deps holds external dependencies injected at construction time.
+type Deps struct {
+	// Settings returns the activity_digest config; nil disables the digest.
+	Settings func() *config.ActivityDigestConfig
+
+	// DefaultDir returns the digest directory used when Settings().Dir is empty.
+	DefaultDir func() (string, error)
+
+	// Sessions returns the live sessions.
+	Sessions func() []SessionInfo
+
+	// Commands returns the commands sessionName ran in workDir on the local
+	// calendar day of day, oldest first.
+	// Optional: the digest lists no commands if nil.
+	Commands func(workDir, sessionName string, day time.Time) ([]string, error)
+
+	// Commits returns the commits made in workDir in [since, until).
+	// Optional: defaults to the commits reachable from HEAD.
+	Commits func(workDir string, since, until time.Time) ([]gitpkg.CommitSummary, error)
+
+	// StatePath returns the path of the day state file.
+	// Optional: counters are kept in memory only if nil.
+	StatePath func() (string, error)
+
+	// Emitter emits GeneratedEvent. Optional: defaults to a no-op emitter.
+	Emitter apptypes.RuntimeEventEmitter
+
+	// SampleInterval is how often Run samples session activity.
+	// Optional: defaults to one minute.
+	SampleInterval time.Duration
+
+	// Now returns the current time. Optional: defaults to time.Now.
+	Now func() time.Time
+}
+
+// sessionCounters is the persisted per-session tally of one day.
+type sessionCounters struct {
+	WorkDir        string `json:"work_dir,omitempty"`
+	ActiveMinutes  int    `json:"active_minutes,omitempty"`
+	FailedCommands int    `json:"failed_commands,omitempty"`
+	TestsPassed    int    `json:"tests_passed,omitempty"`
+	TestsFailed    int    `json:"tests_failed,omitempty"`
+}
+
+// dayState is the persisted tally of the day being recorded.
+type dayState struct {
+	Date     string                      `json:"date"`
+	Sessions map[string]*sessionCounters `json:"sessions"`
+}
+
+func newDayState(date string) dayState {
+	return dayState{Date: date, Sessions: map[string]*sessionCounters{}}
+}
+
+// session returns the counters of name, creating them on first use.
+func (d *dayState) session(name string) *sessionCounters {
+	if d.Sessions == nil {
+		d.Sessions = map[string]*sessionCounters{}
+	}
+	counters := d.Sessions[name]
+	if counters == nil {
+		counters = &sessionCounters{}
+		d.Sessions[name] = counters
+	}
+	return counters
+}
+
+// Service records session activity and writes one digest per day.
+//
+// Thread-safety is managed internally via mu. No external locking is required.
+type Service struct {
+	deps Deps
+
+	// genMu serializes digest generation.
+	genMu sync.Mutex
+
+	mu     sync.Mutex
+	state  dayState
+	loaded bool
+}
+
+// NewService creates an activity digest service.
+// Panics if Settings, DefaultDir, or Sessions is nil.
+func NewService(deps Deps) *Service {
+	if deps.Settings == nil {
+		panic("activitydigest.NewService: Settings must be non-nil")
+	}
+	if deps.DefaultDir == nil {
+		panic("activitydigest.NewService: DefaultDir must be non-nil")
+	}
+	if deps.Sessions == nil {
+		panic("activitydigest.NewService: Sessions must be non-nil")
+	}
+	if deps.Commits == nil {
+		deps.Commits = headCommitsBetween
+	}
+	if deps.Emitter == nil {
+		deps.Emitter = apptypes.NoopEmitter{}
+	}
+	if deps.SampleInterval <= 0 {
+		deps.SampleInterval = defaultSampleInterval
+	}
+	if deps.Now == nil {
+		deps.Now = time.Now
+	}
+	return &Service{deps: deps}
+}
+
+// Run samples session activity every SampleInterval until ctx is cancelled,
+// and publishes the digest of the previous day once the local date changes.
+func (s *Service) Run(ctx context.Context) {
+	// A digest left over from a day the app was closed is published at once.
+	s.Tick()
+	ticker := time.NewTicker(s.deps.SampleInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.Tick()
+		}
+	}
+}
+
+// Tick samples which sessions were active during the last interval. When
+// the recorded day has ended, it first publishes that day's digest.
+func (s *Service) Tick() {
+	settings := s.deps.Settings()
+	if settings == nil {
+		return
+	}
+	now := s.deps.Now()
+	today := now.Format(dateLayout)
+
+	s.mu.Lock()
+	s.loadLocked()
+	var finished *dayState
+	if s.state.Date != "" && s.state.Date != today {
+		ended := s.state
+		finished = &ended
+	}
+	s.mu.Unlock()
+
+	if finished != nil {
+		if _, err := s.publish(*finished, *settings, true); err != nil {
+			slog.Warn("[WARN-DIGEST] failed to write activity digest", "date", finished.Date, "error", err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state.Date != today {
+		s.state = newDayState(today)
+	}
+	activeMinutes := max(1, int(s.deps.SampleInterval/time.Minute))
+	for _, info := range s.deps.Sessions() {
+		counters := s.state.session(info.Name)
+		if info.WorkDir != "" {
+			counters.WorkDir = info.WorkDir
+		}
+		if !info.LastActivity.IsZero() && now.Sub(info.LastActivity) < s.deps.SampleInterval {
+			counters.ActiveMinutes += activeMinutes
+		}
+	}
+	if err := s.saveLocked(); err != nil {
+		slog.Warn("[WARN-DIGEST] failed to save activity digest state", "error", err)
+	}
+}
+
+// RecordCommandFinished counts a command a session finished with exitCode,
+// as reported by shell integration. command is the command line, used to
+// recognize test runs; it may be empty when unknown.
+func (s *Service) RecordCommandFinished(sessionName, workDir, command string, exitCode int) {
+	if sessionName == "" || s.deps.Settings() == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.loadLocked()
+	if s.state.Date == "" {
+		s.state = newDayState(s.deps.Now().Format(dateLayout))
+	}
+	counters := s.state.session(sessionName)
+	if workDir != "" && counters.WorkDir == "" {
+		counters.WorkDir = workDir
+	}
+	if exitCode != 0 {
+		counters.FailedCommands++
+	}
+	if IsTestCommand(command) {
+		if exitCode == 0 {
+			counters.TestsPassed++
+		} else {
+			counters.TestsFailed++
+		}
+	}
+	if err := s.saveLocked(); err != nil {
+		slog.Warn("[WARN-DIGEST] failed to save activity digest state", "error", err)
+	}
+}
+
+// GenerateNow writes the digest of the current day so far and returns its
+// path. Unlike the end-of-day digest it emits no event.
+func (s *Service) GenerateNow() (string, error) {
+	settings := s.deps.Settings()
+	if settings == nil {
+		return "", ErrDisabled
+	}
+	s.mu.Lock()
+	s.loadLocked()
+	current := s.state
+	current.Sessions = make(map[string]*sessionCounters, len(s.state.Sessions))
+	for name, counters := range s.state.Sessions {
+		countersCopy := *counters
+		current.Sessions[name] = &countersCopy
+	}
+	s.mu.Unlock()
+	if current.Date == "" {
+		current = newDayState(s.deps.Now().Format(dateLayout))
+	}
+	return s.publish(current, *settings, false)
+}
+
+// publish builds and saves the digest of state and, when announce is set,
+// emits GeneratedEvent. Days without any recorded session are skipped.
+func (s *Service) publish(state dayState, settings config.ActivityDigestConfig, announce bool) (string, error) {
+	s.genMu.Lock()
+	defer s.genMu.Unlock()
+
+	day, err := time.ParseInLocation(dateLayout, state.Date, s.deps.Now().Location())
+	if err != nil {
+		return "", fmt.Errorf("invalid digest date %q: %w", state.Date, err)
+	}
+	digest := s.buildDigest(day, state, settings)
+	if len(digest.Sessions) == 0 && announce {
+		slog.Debug("[DEBUG-DIGEST] no session activity, skipping digest", "date", state.Date)
+		return "", nil
+	}
+
+	dir := settings.Dir
+	if dir == "" {
+		if dir, err = s.deps.DefaultDir(); err != nil {
+			return "", fmt.Errorf("resolve digest directory: %w", err)
+		}
+	}
+	path := filepath.Join(dir, state.Date+".md")
+	markdown := RenderMarkdown(digest, settings.CostPerActiveHour > 0)
+	if err := writeFileAtomic(path, []byte(markdown)); err != nil {
+		return "", err
+	}
+	slog.Info("[DIGEST] activity digest written", "date", state.Date, "path", path, "sessions", len(digest.Sessions))
+
+	if announce {
+		s.deps.Emitter.Emit(GeneratedEvent, GeneratedPayload{
+			Date:     digest.Date,
+			Path:     path,
+			Summary:  Summary(digest),
+			Markdown: markdown,
+			Digest:   digest,
+		})
+	}
+	return path, nil
+}
+
+// buildDigest combines the counters of state with the commands and commits
+// of each session on day.
+func (s *Service) buildDigest(day time.Time, state dayState, settings config.ActivityDigestConfig) Digest {
+	start := day
+	end := day.AddDate(0, 0, 1)
+	digest := Digest{Date: state.Date, Sessions: []SessionDigest{}}
+	names := make([]string, 0, len(state.Sessions))
+	for name := range state.Sessions {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		counters := state.Sessions[name]
+		session := SessionDigest{
+			SessionName:    name,
+			WorkDir:        counters.WorkDir,
+			Commands:       []string{},
+			FailedCommands: counters.FailedCommands,
+			Commits:        []gitpkg.CommitSummary{},
+			TestsPassed:    counters.TestsPassed,
+			TestsFailed:    counters.TestsFailed,
+			ActiveMinutes:  counters.ActiveMinutes,
+		}
+		if counters.WorkDir != "" {
+			if s.deps.Commands != nil {
+				commands, err := s.deps.Commands(counters.WorkDir, name, day)
+				if err != nil {
+					slog.Debug("[DEBUG-DIGEST] commands unavailable", "session", name, "error", err)
+				}
+				session.CommandCount = len(commands)
+				session.Commands = commands[:min(len(commands), maxCommandsListed)]
+			}
+			commits, err := s.deps.Commits(counters.WorkDir, start, end)
+			if err != nil {
+				slog.Debug("[DEBUG-DIGEST] commits unavailable", "session", name, "error", err)
+			}
+			if commits != nil {
+				session.Commits = commits
+			}
+		}
+		if settings.CostPerActiveHour > 0 {
+			cost := settings.CostPerActiveHour * float64(session.ActiveMinutes) / 60
+			session.CostEstimate = math.Round(cost*100) / 100
+		}
+		if session.idle() {
+			continue
+		}
+		digest.Sessions = append(digest.Sessions, session)
+	}
+	return digest
+}
+
+// headCommitsBetween is the default Deps.Commits. Directories that are not
+// git repositories have no commits.
+func headCommitsBetween(workDir string, since, until time.Time) ([]gitpkg.CommitSummary, error) {
+	repo, err := gitpkg.Open(workDir)
+	if err != nil {
+		return nil, nil
+	}
+	return repo.CommitsBetween(since, until)
+}
+
+// loadLocked reads the day state file once.
+//
+// REQUIRES: s.mu must be held by the caller.
+func (s *Service) loadLocked() {
+	if s.loaded {
+		return
+	}
+	s.loaded = true
+	if s.deps.StatePath == nil {
+		return
+	}
+	path, err := s.deps.StatePath()
+	if err != nil {
+		slog.Warn("[WARN-DIGEST] activity digest state unavailable", "error", err)
+		return
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			slog.Warn("[WARN-DIGEST] failed to read activity digest state", "path", path, "error", err)
+		}
+		return
+	}
+	var state dayState
+	if err := json.Unmarshal(data, &state); err != nil {
+		slog.Warn("[WARN-DIGEST] ignoring unreadable activity digest state", "path", path, "error", err)
+		return
+	}
+	if _, err := time.Parse(dateLayout, state.Date); err != nil {
+		slog.Warn("[WARN-DIGEST] ignoring activity digest state with invalid date", "path", path, "date", state.Date)
+		return
+	}
+	for name, counters := range state.Sessions {
+		if counters == nil {
+			delete(state.Sessions, name)
+		}
+	}
+	s.state = state
+}
+
+// saveLocked writes the day state file atomically.
+//
+// REQUIRES: s.mu must be held by the caller.
+func (s *Service) saveLocked() error {
+	if s.deps.StatePath == nil {
+		return nil
+	}
+	path, err := s.deps.StatePath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s.state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal activity digest state: %w", err)
+	}
+	return writeFileAtomic(path, data)
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("write %s: %w", filepath.Base(path), err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("replace %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
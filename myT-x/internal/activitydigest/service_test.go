@@ -0,0 +1,147 @@
+package activitydigest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"myT-x/internal/apptypes"
+	"myT-x/internal/config"
+	gitpkg "myT-x/internal/git"
+)
+
+type digestHarness struct {
+	svc      *Service
+	now      time.Time
+	settings *config.ActivityDigestConfig
+	sessions []SessionInfo
+	events   []GeneratedPayload
+	dir      string
+	state    string
+}
+
+func newDigestHarness(t *testing.T) *digestHarness {
+	t.Helper()
+	h := &digestHarness{
+		now:      time.Date(2026, 5, 16, 10, 0, 0, 0, time.Local),
+		settings: &config.ActivityDigestConfig{CostPerActiveHour: 6},
+		dir:      t.TempDir(),
+	}
+	h.state = filepath.Join(h.dir, StateFileName)
+	h.svc = h.newService()
+	return h
+}
+
+// newService returns a service sharing the harness state, like an app restart.
+func (h *digestHarness) newService() *Service {
+	return NewService(Deps{
+		Settings:   func() *config.ActivityDigestConfig { return h.settings },
+		DefaultDir: func() (string, error) { return filepath.Join(h.dir, "digests"), nil },
+		Sessions:   func() []SessionInfo { return h.sessions },
+		Commands: func(workDir, sessionName string, day time.Time) ([]string, error) {
+			if sessionName != "api" || day.Format(dateLayout) != "2026-05-16" {
+				return nil, nil
+			}
+			return []string{"go test ./...", "git commit -m fix"}, nil
+		},
+		Commits: func(workDir string, since, until time.Time) ([]gitpkg.CommitSummary, error) {
+			if workDir != `C:\api` || !since.Equal(time.Date(2026, 5, 16, 0, 0, 0, 0, time.Local)) || !until.Equal(since.AddDate(0, 0, 1)) {
+				return nil, nil
+			}
+			return []gitpkg.CommitSummary{{Commit: "0123456789abcdef", Subject: "fix"}}, nil
+		},
+		StatePath: func() (string, error) { return h.state, nil },
+		Emitter: apptypes.EventEmitterFunc(func(name string, payload any) {
+			if name == GeneratedEvent {
+				h.events = append(h.events, payload.(GeneratedPayload))
+			}
+		}),
+		Now: func() time.Time { return h.now },
+	})
+}
+
+func TestServicePublishesDigestAfterDayEnds(t *testing.T) {
+	h := newDigestHarness(t)
+	h.sessions = []SessionInfo{
+		{Name: "api", WorkDir: `C:\api`, LastActivity: h.now.Add(-30 * time.Second)},
+		{Name: "idle", WorkDir: `C:\idle`},
+	}
+	h.svc.Tick()
+	h.now = h.now.Add(time.Minute)
+	h.sessions[0].LastActivity = h.now.Add(-10 * time.Second)
+	h.svc.Tick()
+	h.svc.RecordCommandFinished("api", "", "go test ./...", 1)
+	h.svc.RecordCommandFinished("api", "", "go test ./...", 0)
+	h.svc.RecordCommandFinished("api", "", "ls", 2)
+
+	// Counters survive a restart through the state file.
+	h.svc = h.newService()
+	h.now = time.Date(2026, 5, 17, 0, 0, 30, 0, time.Local)
+	h.sessions = nil
+	h.svc.Tick()
+
+	if len(h.events) != 1 {
+		t.Fatalf("events = %d, want 1", len(h.events))
+	}
+	payload := h.events[0]
+	if payload.Date != "2026-05-16" || len(payload.Digest.Sessions) != 1 {
+		t.Fatalf("payload = %+v, want only the api session of 2026-05-16", payload.Digest)
+	}
+	got := payload.Digest.Sessions[0]
+	if got.SessionName != "api" || got.ActiveMinutes != 2 || got.CommandCount != 2 || got.FailedCommands != 2 ||
+		got.TestsPassed != 1 || got.TestsFailed != 1 || len(got.Commits) != 1 || got.CostEstimate != 0.2 {
+		t.Fatalf("session digest = %+v", got)
+	}
+	wantPath := filepath.Join(h.dir, "digests", "2026-05-16.md")
+	if payload.Path != wantPath {
+		t.Fatalf("path = %q, want %q", payload.Path, wantPath)
+	}
+	data, err := os.ReadFile(wantPath)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if string(data) != payload.Markdown || !strings.Contains(payload.Markdown, "| api | 0h 02m | 2 (2 failed) | 1 | 1 passed, 1 failed | 0.20 |") {
+		t.Fatalf("markdown =\n%s", data)
+	}
+
+	// The new day starts empty, so the next tick publishes nothing.
+	h.now = h.now.Add(time.Minute)
+	h.svc.Tick()
+	if len(h.events) != 1 {
+		t.Fatalf("events after the next tick = %d, want 1", len(h.events))
+	}
+}
+
+func TestServiceDisabled(t *testing.T) {
+	h := newDigestHarness(t)
+	h.settings = nil
+	h.sessions = []SessionInfo{{Name: "api", WorkDir: `C:\api`, LastActivity: h.now}}
+	h.svc.Tick()
+	h.svc.RecordCommandFinished("api", `C:\api`, "go test ./...", 0)
+	if _, err := os.Stat(h.state); !os.IsNotExist(err) {
+		t.Fatalf("state file written while disabled: %v", err)
+	}
+	if _, err := h.svc.GenerateNow(); err != ErrDisabled {
+		t.Fatalf("GenerateNow() error = %v, want ErrDisabled", err)
+	}
+}
+
+func TestServiceGenerateNow(t *testing.T) {
+	h := newDigestHarness(t)
+	h.settings.Dir = filepath.Join(h.dir, "custom")
+	h.sessions = []SessionInfo{{Name: "api", WorkDir: `C:\api`, LastActivity: h.now}}
+	h.svc.Tick()
+
+	path, err := h.svc.GenerateNow()
+	if err != nil {
+		t.Fatalf("GenerateNow() error = %v", err)
+	}
+	if path != filepath.Join(h.dir, "custom", "2026-05-16.md") {
+		t.Fatalf("path = %q", path)
+	}
+	if len(h.events) != 0 {
+		t.Fatalf("GenerateNow() emitted %d events, want none", len(h.events))
+	}
+}
@@ -0,0 +1,136 @@
+package bringup
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"myT-x/internal/config"
+)
+
+// StartOrder returns the templates needed to bring up names, including their
+// transitive dependencies, with every template after its dependencies.
+// Ties keep config order. Empty names selects every template.
+func StartOrder(templates []config.SessionTemplate, names []string) ([]config.SessionTemplate, error) {
+	byName, err := indexTemplates(templates)
+	if err != nil {
+		return nil, err
+	}
+	roots, err := resolveNames(templates, byName, names)
+	if err != nil {
+		return nil, err
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	marks := make(map[string]int, len(templates))
+	order := make([]config.SessionTemplate, 0, len(templates))
+	var path []string
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch marks[name] {
+		case visited:
+			return nil
+		case visiting:
+			start := slices.Index(path, name)
+			cycle := append(slices.Clone(path[start:]), name)
+			return fmt.Errorf("dependency cycle: %s", strings.Join(cycle, " -> "))
+		}
+		marks[name] = visiting
+		path = append(path, name)
+		template := byName[name]
+		for _, dep := range template.DependsOn {
+			if _, ok := byName[dep]; !ok {
+				return fmt.Errorf("template %q depends on unknown template %q", name, dep)
+			}
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		marks[name] = visited
+		order = append(order, template)
+		return nil
+	}
+	for _, name := range roots {
+		if err := visit(name); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// StopOrder returns the templates to tear down for names, including every
+// template that transitively depends on them, with dependents first.
+// Empty names selects every template.
+func StopOrder(templates []config.SessionTemplate, names []string) ([]config.SessionTemplate, error) {
+	all, err := StartOrder(templates, nil)
+	if err != nil {
+		return nil, err
+	}
+	byName, _ := indexTemplates(templates)
+	roots, err := resolveNames(templates, byName, names)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := make(map[string]bool, len(all))
+	for _, name := range roots {
+		selected[name] = true
+	}
+	// all is in start order, so one pass propagates selection to dependents.
+	for _, template := range all {
+		for _, dep := range template.DependsOn {
+			if selected[dep] {
+				selected[template.Name] = true
+			}
+		}
+	}
+
+	order := make([]config.SessionTemplate, 0, len(selected))
+	for i := len(all) - 1; i >= 0; i-- {
+		if selected[all[i].Name] {
+			order = append(order, all[i])
+		}
+	}
+	return order, nil
+}
+
+func indexTemplates(templates []config.SessionTemplate) (map[string]config.SessionTemplate, error) {
+	byName := make(map[string]config.SessionTemplate, len(templates))
+	for _, template := range templates {
+		if _, dup := byName[template.Name]; dup {
+			return nil, fmt.Errorf("duplicate template %q", template.Name)
+		}
+		byName[template.Name] = template
+	}
+	return byName, nil
+}
+
+// resolveNames validates requested names; empty names selects all templates
+// in config order.
+func resolveNames(templates []config.SessionTemplate, byName map[string]config.SessionTemplate, names []string) ([]string, error) {
+	if len(names) == 0 {
+		if len(templates) == 0 {
+			return nil, errors.New("no session templates are configured")
+		}
+		all := make([]string, 0, len(templates))
+		for _, template := range templates {
+			all = append(all, template.Name)
+		}
+		return all, nil
+	}
+	resolved := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if _, ok := byName[name]; !ok {
+			return nil, fmt.Errorf("unknown session template %q", name)
+		}
+		resolved = append(resolved, name)
+	}
+	return resolved, nil
+}
@@ -0,0 +1,105 @@
+package bringup
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"myT-x/internal/config"
+)
+
+func templateNames(templates []config.SessionTemplate) []string {
+	names := make([]string, 0, len(templates))
+	for _, template := range templates {
+		names = append(names, template.Name)
+	}
+	return names
+}
+
+func stackTemplates() []config.SessionTemplate {
+	return []config.SessionTemplate{
+		{Name: "frontend", DependsOn: []string{"backend"}},
+		{Name: "backend", DependsOn: []string{"db"}},
+		{Name: "db"},
+		{Name: "docs"},
+	}
+}
+
+func TestStartOrder(t *testing.T) {
+	tests := []struct {
+		name  string
+		names []string
+		want  []string
+	}{
+		{name: "all templates", want: []string{"db", "backend", "frontend", "docs"}},
+		{name: "named template pulls in dependencies", names: []string{"frontend"}, want: []string{"db", "backend", "frontend"}},
+		{name: "independent template alone", names: []string{"docs"}, want: []string{"docs"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order, err := StartOrder(stackTemplates(), tt.names)
+			if err != nil {
+				t.Fatalf("StartOrder() error = %v", err)
+			}
+			if got := templateNames(order); !slices.Equal(got, tt.want) {
+				t.Fatalf("StartOrder() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStartOrderErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		templates []config.SessionTemplate
+		names     []string
+		wantErr   string
+	}{
+		{name: "no templates", wantErr: "no session templates"},
+		{name: "unknown name", templates: stackTemplates(), names: []string{"nope"}, wantErr: `unknown session template "nope"`},
+		{
+			name:      "unknown dependency",
+			templates: []config.SessionTemplate{{Name: "api", DependsOn: []string{"db"}}},
+			wantErr:   `depends on unknown template "db"`,
+		},
+		{
+			name: "cycle",
+			templates: []config.SessionTemplate{
+				{Name: "a", DependsOn: []string{"b"}},
+				{Name: "b", DependsOn: []string{"a"}},
+			},
+			wantErr: "dependency cycle: a -> b -> a",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := StartOrder(tt.templates, tt.names)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("StartOrder() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestStopOrder(t *testing.T) {
+	tests := []struct {
+		name  string
+		names []string
+		want  []string
+	}{
+		{name: "all templates in reverse", want: []string{"docs", "frontend", "backend", "db"}},
+		{name: "named template takes dependents down", names: []string{"backend"}, want: []string{"frontend", "backend"}},
+		{name: "leaf template alone", names: []string{"frontend"}, want: []string{"frontend"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order, err := StopOrder(stackTemplates(), tt.names)
+			if err != nil {
+				t.Fatalf("StopOrder() error = %v", err)
+			}
+			if got := templateNames(order); !slices.Equal(got, tt.want) {
+				t.Fatalf("StopOrder() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
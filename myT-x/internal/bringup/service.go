@@ -0,0 +1,389 @@
+// Package bringup brings session templates (config session_templates) up and
+// down in dependency order, docker-compose style: a template starts only
+// after every template it depends on is ready, where ready means its health
+// probe passed. Tear-down stops dependents before their dependencies.
+package bringup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os/exec"
+	"strconv"
+	"sync"
+	"time"
+
+	"myT-x/internal/apptypes"
+	"myT-x/internal/config"
+	"myT-x/internal/procutil"
+	"myT-x/internal/workerutil"
+)
+
+// Deps holds external dependencies injected at construction time.
+type Deps struct {
+	// Emitter sends runtime events to the frontend.
+	// Optional: defaults to a no-op emitter if nil.
+	Emitter apptypes.RuntimeEventEmitter
+
+	// Templates returns the configured session templates.
+	Templates func() []config.SessionTemplate
+
+	// SessionExists reports whether a session with the given name is running.
+	SessionExists func(name string) bool
+
+	// CreateSession creates the session for a template.
+	CreateSession func(template config.SessionTemplate) error
+
+	// KillSession closes a session.
+	KillSession func(name string) error
+
+	// DialPort checks that a TCP port on 127.0.0.1 accepts connections.
+	// Optional: defaults to net.Dialer.
+	DialPort func(ctx context.Context, port int) error
+
+	// RunProbeCommand runs a health probe command in dir and returns nil on
+	// exit code 0. Optional: defaults to cmd.exe /C with a hidden window.
+	RunProbeCommand func(ctx context.Context, command, dir string) error
+
+	// NewContext creates a cancellable context for a run.
+	NewContext func() (context.Context, context.CancelFunc)
+
+	// LaunchWorker starts a background goroutine with panic recovery.
+	LaunchWorker func(name string, ctx context.Context, fn func(ctx context.Context), opts workerutil.RecoveryOptions)
+
+	// BaseRecoveryOptions returns the default RecoveryOptions for workers.
+	BaseRecoveryOptions func() workerutil.RecoveryOptions
+}
+
+// Service runs one bring-up or tear-down at a time.
+//
+// Thread-safety is managed internally via mu. No external locking is required.
+type Service struct {
+	deps Deps
+
+	mu     sync.Mutex
+	status Status
+	cancel context.CancelFunc
+	// token identifies the current run; stale workers compare and exit.
+	token uint64
+}
+
+// NewService creates a bring-up service.
+// Panics if any required function field in deps is nil.
+func NewService(deps Deps) *Service {
+	if deps.Templates == nil || deps.SessionExists == nil || deps.CreateSession == nil ||
+		deps.KillSession == nil || deps.NewContext == nil || deps.LaunchWorker == nil ||
+		deps.BaseRecoveryOptions == nil {
+		panic("bringup.NewService: required function fields in Deps must be non-nil " +
+			"(Templates, SessionExists, CreateSession, KillSession, NewContext, LaunchWorker, BaseRecoveryOptions)")
+	}
+	if deps.Emitter == nil {
+		deps.Emitter = apptypes.NoopEmitter{}
+	}
+	if deps.DialPort == nil {
+		deps.DialPort = func(ctx context.Context, port int) error {
+			var dialer net.Dialer
+			conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+			if err != nil {
+				return err
+			}
+			return conn.Close()
+		}
+	}
+	if deps.RunProbeCommand == nil {
+		deps.RunProbeCommand = func(ctx context.Context, command, dir string) error {
+			cmd := exec.CommandContext(ctx, "cmd.exe", "/C", command)
+			cmd.Dir = dir
+			procutil.HideWindow(cmd)
+			return cmd.Run()
+		}
+	}
+	return &Service{
+		deps:   deps,
+		status: Status{Templates: []TemplateStatus{}},
+	}
+}
+
+// BringUp starts the named templates and their dependencies in dependency
+// order. Empty names brings up every template. Sessions that already exist
+// are reused and only probed.
+func (s *Service) BringUp(names []string) (Status, error) {
+	order, err := StartOrder(s.deps.Templates(), names)
+	if err != nil {
+		return Status{}, err
+	}
+	return s.start(OperationUp, order)
+}
+
+// TearDown stops the named templates and every template that depends on
+// them, dependents first. Empty names tears down every template.
+func (s *Service) TearDown(names []string) (Status, error) {
+	order, err := StopOrder(s.deps.Templates(), names)
+	if err != nil {
+		return Status{}, err
+	}
+	return s.start(OperationDown, order)
+}
+
+// Status returns the status of the latest run.
+func (s *Service) Status() Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.statusLocked()
+}
+
+// Cancel stops the running bring-up or tear-down. Sessions already created
+// keep running.
+func (s *Service) Cancel() error {
+	s.mu.Lock()
+	if !s.status.Running {
+		s.mu.Unlock()
+		return errors.New("no bring-up or tear-down is running")
+	}
+	for i := range s.status.Templates {
+		if !s.status.Templates[i].State.finished() {
+			s.status.Templates[i].State = StateCanceled
+		}
+	}
+	cancel := s.cancel
+	s.cancel = nil
+	s.status.Running = false
+	s.token++
+	status := s.statusLocked()
+	s.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	slog.Info("[BRINGUP] cancelled", "operation", status.Operation)
+	s.emitStatus(status)
+	return nil
+}
+
+func (s *Service) start(operation Operation, order []config.SessionTemplate) (Status, error) {
+	s.mu.Lock()
+	if s.status.Running {
+		s.mu.Unlock()
+		return Status{}, errors.New("a bring-up or tear-down is already running")
+	}
+	templates := make([]TemplateStatus, 0, len(order))
+	for _, template := range order {
+		templates = append(templates, TemplateStatus{Name: template.Name, State: StatePending})
+	}
+	ctx, cancel := s.deps.NewContext()
+	s.token++
+	token := s.token
+	s.cancel = cancel
+	s.status = Status{Operation: operation, Running: true, Templates: templates}
+	status := s.statusLocked()
+	s.mu.Unlock()
+
+	recoveryOpts := s.deps.BaseRecoveryOptions()
+	recoveryOpts.MaxRetries = 1 // No retry on panic; fail the run instead.
+	origOnFatal := recoveryOpts.OnFatal
+	recoveryOpts.OnFatal = func(worker string, maxRetries int) {
+		s.failRun(token, "internal panic")
+		if origOnFatal != nil {
+			origOnFatal(worker, maxRetries)
+		}
+	}
+	s.deps.LaunchWorker("bringup-"+string(operation), ctx, func(ctx context.Context) {
+		if operation == OperationUp {
+			s.runUp(ctx, token, order)
+		} else {
+			s.runDown(ctx, token, order)
+		}
+		s.finishRun(token)
+	}, recoveryOpts)
+
+	slog.Info("[BRINGUP] started", "operation", operation, "templates", len(order))
+	s.emitStatus(status)
+	return status, nil
+}
+
+func (s *Service) runUp(ctx context.Context, token uint64, order []config.SessionTemplate) {
+	notReady := map[string]bool{}
+	for i, template := range order {
+		if ctx.Err() != nil {
+			return
+		}
+		if dep := firstNotReady(template.DependsOn, notReady); dep != "" {
+			notReady[template.Name] = true
+			s.setState(token, i, StateSkipped, false, fmt.Sprintf("dependency %q is not ready", dep))
+			continue
+		}
+
+		reused := s.deps.SessionExists(template.Name)
+		if !s.setState(token, i, StateStarting, reused, "") {
+			return
+		}
+		if !reused {
+			if err := s.deps.CreateSession(template); err != nil {
+				notReady[template.Name] = true
+				s.setState(token, i, StateFailed, reused, err.Error())
+				continue
+			}
+		}
+
+		if template.Health != nil {
+			if !s.setState(token, i, StateProbing, reused, "") {
+				return
+			}
+			if err := s.waitHealthy(ctx, *template.Health, template.Dir); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				notReady[template.Name] = true
+				s.setState(token, i, StateFailed, reused, err.Error())
+				continue
+			}
+		}
+		s.setState(token, i, StateReady, reused, "")
+	}
+}
+
+func (s *Service) runDown(ctx context.Context, token uint64, order []config.SessionTemplate) {
+	for i, template := range order {
+		if ctx.Err() != nil {
+			return
+		}
+		if !s.deps.SessionExists(template.Name) {
+			s.setState(token, i, StateStopped, false, "")
+			continue
+		}
+		if !s.setState(token, i, StateStopping, false, "") {
+			return
+		}
+		// Tear-down is best-effort: a failed kill does not keep the
+		// remaining sessions running.
+		if err := s.deps.KillSession(template.Name); err != nil {
+			s.setState(token, i, StateFailed, false, err.Error())
+			continue
+		}
+		s.setState(token, i, StateStopped, false, "")
+	}
+}
+
+// waitHealthy polls probe until it passes or its timeout elapses.
+func (s *Service) waitHealthy(ctx context.Context, probe config.SessionHealthProbe, dir string) error {
+	interval := time.Duration(probe.IntervalSeconds) * time.Second
+	if probe.IntervalSeconds == 0 {
+		interval = config.DefaultHealthIntervalSeconds * time.Second
+	}
+	timeout := time.Duration(probe.TimeoutSeconds) * time.Second
+	if probe.TimeoutSeconds == 0 {
+		timeout = config.DefaultHealthTimeoutSeconds * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		lastErr := s.probeOnce(ctx, probe, dir)
+		if lastErr == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("not healthy after %s: %w", timeout, lastErr)
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Service) probeOnce(ctx context.Context, probe config.SessionHealthProbe, dir string) error {
+	if probe.Port != 0 {
+		if err := s.deps.DialPort(ctx, probe.Port); err != nil {
+			return fmt.Errorf("port %d: %w", probe.Port, err)
+		}
+	}
+	if probe.Command != "" {
+		if err := s.deps.RunProbeCommand(ctx, probe.Command, dir); err != nil {
+			return fmt.Errorf("command %q: %w", probe.Command, err)
+		}
+	}
+	return nil
+}
+
+// setState updates one template and emits the status. It returns false when
+// the run was cancelled or replaced.
+func (s *Service) setState(token uint64, index int, state State, reused bool, errText string) bool {
+	s.mu.Lock()
+	if s.token != token {
+		s.mu.Unlock()
+		return false
+	}
+	template := &s.status.Templates[index]
+	template.State = state
+	template.Reused = reused
+	template.Error = errText
+	name := template.Name
+	status := s.statusLocked()
+	s.mu.Unlock()
+
+	if state == StateFailed {
+		slog.Warn("[BRINGUP] template failed", "template", name, "error", errText)
+	} else {
+		slog.Debug("[DEBUG-BRINGUP] template state changed", "template", name, "state", state)
+	}
+	s.emitStatus(status)
+	return true
+}
+
+func (s *Service) finishRun(token uint64) {
+	s.mu.Lock()
+	if s.token != token {
+		s.mu.Unlock()
+		return
+	}
+	s.status.Running = false
+	s.cancel = nil
+	s.token++
+	status := s.statusLocked()
+	s.mu.Unlock()
+
+	slog.Info("[BRINGUP] finished", "operation", status.Operation)
+	s.emitStatus(status)
+}
+
+func (s *Service) failRun(token uint64, reason string) {
+	s.mu.Lock()
+	if s.token != token {
+		s.mu.Unlock()
+		return
+	}
+	for i := range s.status.Templates {
+		if !s.status.Templates[i].State.finished() {
+			s.status.Templates[i].State = StateFailed
+			s.status.Templates[i].Error = reason
+		}
+	}
+	s.status.Running = false
+	s.cancel = nil
+	s.token++
+	status := s.statusLocked()
+	s.mu.Unlock()
+	s.emitStatus(status)
+}
+
+func (s *Service) emitStatus(status Status) {
+	s.deps.Emitter.Emit(StatusEvent, status)
+}
+
+func (s *Service) statusLocked() Status {
+	status := s.status
+	status.Templates = append([]TemplateStatus{}, s.status.Templates...)
+	return status
+}
+
+func firstNotReady(dependsOn []string, notReady map[string]bool) string {
+	for _, dep := range dependsOn {
+		if notReady[dep] {
+			return dep
+		}
+	}
+	return ""
+}
@@ -0,0 +1,235 @@
+package bringup
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"myT-x/internal/config"
+	"myT-x/internal/workerutil"
+)
+
+// fakeSessions records session lifecycle calls made by the service.
+type fakeSessions struct {
+	mu        sync.Mutex
+	running   map[string]bool
+	created   []string
+	killed    []string
+	createErr map[string]error
+	healthy   map[string]bool // keyed by probe command
+}
+
+func newFakeSessions(running ...string) *fakeSessions {
+	f := &fakeSessions{running: map[string]bool{}, createErr: map[string]error{}, healthy: map[string]bool{}}
+	for _, name := range running {
+		f.running[name] = true
+	}
+	return f
+}
+
+func (f *fakeSessions) setHealthy(command string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.healthy[command] = true
+}
+
+func testDeps(templates []config.SessionTemplate, sessions *fakeSessions) Deps {
+	return Deps{
+		Templates: func() []config.SessionTemplate { return templates },
+		SessionExists: func(name string) bool {
+			sessions.mu.Lock()
+			defer sessions.mu.Unlock()
+			return sessions.running[name]
+		},
+		CreateSession: func(template config.SessionTemplate) error {
+			sessions.mu.Lock()
+			defer sessions.mu.Unlock()
+			if err := sessions.createErr[template.Name]; err != nil {
+				return err
+			}
+			sessions.running[template.Name] = true
+			sessions.created = append(sessions.created, template.Name)
+			return nil
+		},
+		KillSession: func(name string) error {
+			sessions.mu.Lock()
+			defer sessions.mu.Unlock()
+			delete(sessions.running, name)
+			sessions.killed = append(sessions.killed, name)
+			return nil
+		},
+		DialPort: func(context.Context, int) error { return errors.New("connection refused") },
+		RunProbeCommand: func(_ context.Context, command, _ string) error {
+			sessions.mu.Lock()
+			defer sessions.mu.Unlock()
+			if sessions.healthy[command] {
+				return nil
+			}
+			return errors.New("exit status 1")
+		},
+		NewContext: func() (context.Context, context.CancelFunc) {
+			return context.WithCancel(context.Background())
+		},
+		LaunchWorker: func(name string, ctx context.Context, fn func(ctx context.Context), opts workerutil.RecoveryOptions) {
+			go fn(ctx)
+		},
+		BaseRecoveryOptions: func() workerutil.RecoveryOptions {
+			return workerutil.RecoveryOptions{MaxRetries: 1}
+		},
+	}
+}
+
+func waitForRun(t *testing.T, service *Service, done func(Status) bool) Status {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		status := service.Status()
+		if done(status) {
+			return status
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("run did not reach expected state: %+v", status)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func notRunning(s Status) bool { return !s.Running }
+
+func statesByName(status Status) map[string]State {
+	states := make(map[string]State, len(status.Templates))
+	for _, template := range status.Templates {
+		states[template.Name] = template.State
+	}
+	return states
+}
+
+func TestNewServicePanicsOnMissingDeps(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic for missing deps")
+		}
+	}()
+	NewService(Deps{})
+}
+
+func TestBringUpWaitsForHealthyDependency(t *testing.T) {
+	templates := []config.SessionTemplate{
+		{Name: "frontend", Command: "npm run dev", DependsOn: []string{"backend"}},
+		{Name: "backend", Health: &config.SessionHealthProbe{Command: "check-backend", IntervalSeconds: 1}},
+	}
+	sessions := newFakeSessions()
+	service := NewService(testDeps(templates, sessions))
+
+	if _, err := service.BringUp([]string{"frontend"}); err != nil {
+		t.Fatalf("BringUp() error = %v", err)
+	}
+	waitForRun(t, service, func(s Status) bool { return statesByName(s)["backend"] == StateProbing })
+	sessions.mu.Lock()
+	created := slices.Clone(sessions.created)
+	sessions.mu.Unlock()
+	if !slices.Equal(created, []string{"backend"}) {
+		t.Fatalf("created = %v, want only backend before it is healthy", created)
+	}
+
+	sessions.setHealthy("check-backend")
+	status := waitForRun(t, service, notRunning)
+	if states := statesByName(status); states["backend"] != StateReady || states["frontend"] != StateReady {
+		t.Fatalf("states = %v, want both ready", states)
+	}
+	if !slices.Equal(sessions.created, []string{"backend", "frontend"}) {
+		t.Fatalf("created = %v, want backend then frontend", sessions.created)
+	}
+}
+
+func TestBringUpSkipsDependentsOfFailedTemplate(t *testing.T) {
+	templates := []config.SessionTemplate{
+		{Name: "db"},
+		{Name: "api", DependsOn: []string{"db"}},
+		{Name: "docs"},
+	}
+	sessions := newFakeSessions()
+	sessions.createErr["db"] = errors.New("root path is required")
+	service := NewService(testDeps(templates, sessions))
+
+	if _, err := service.BringUp(nil); err != nil {
+		t.Fatalf("BringUp() error = %v", err)
+	}
+	status := waitForRun(t, service, notRunning)
+	states := statesByName(status)
+	if states["db"] != StateFailed || states["api"] != StateSkipped || states["docs"] != StateReady {
+		t.Fatalf("states = %v, want db failed, api skipped, docs ready", states)
+	}
+	if !strings.Contains(status.Templates[1].Error, `"db"`) {
+		t.Fatalf("api error = %q, want mention of db", status.Templates[1].Error)
+	}
+}
+
+func TestBringUpReusesRunningSession(t *testing.T) {
+	templates := []config.SessionTemplate{{Name: "api"}}
+	sessions := newFakeSessions("api")
+	service := NewService(testDeps(templates, sessions))
+
+	if _, err := service.BringUp(nil); err != nil {
+		t.Fatalf("BringUp() error = %v", err)
+	}
+	status := waitForRun(t, service, notRunning)
+	if status.Templates[0].State != StateReady || !status.Templates[0].Reused {
+		t.Fatalf("status = %+v, want reused and ready", status.Templates[0])
+	}
+	if len(sessions.created) != 0 {
+		t.Fatalf("created = %v, want none", sessions.created)
+	}
+}
+
+func TestBringUpRejectsConcurrentRun(t *testing.T) {
+	templates := []config.SessionTemplate{
+		{Name: "api", Health: &config.SessionHealthProbe{Command: "never"}},
+	}
+	service := NewService(testDeps(templates, newFakeSessions()))
+
+	if _, err := service.BringUp(nil); err != nil {
+		t.Fatalf("BringUp() error = %v", err)
+	}
+	if _, err := service.TearDown(nil); err == nil || !strings.Contains(err.Error(), "already running") {
+		t.Fatalf("TearDown() error = %v, want already running", err)
+	}
+	if err := service.Cancel(); err != nil {
+		t.Fatalf("Cancel() error = %v", err)
+	}
+	status := service.Status()
+	if status.Running || status.Templates[0].State != StateCanceled {
+		t.Fatalf("status = %+v, want cancelled", status)
+	}
+	if err := service.Cancel(); err == nil {
+		t.Fatal("Cancel() error = nil, want error when idle")
+	}
+}
+
+func TestTearDownStopsDependentsFirst(t *testing.T) {
+	templates := []config.SessionTemplate{
+		{Name: "frontend", DependsOn: []string{"backend"}},
+		{Name: "backend"},
+		{Name: "docs"},
+	}
+	sessions := newFakeSessions("frontend", "backend", "docs")
+	service := NewService(testDeps(templates, sessions))
+
+	if _, err := service.TearDown([]string{"backend"}); err != nil {
+		t.Fatalf("TearDown() error = %v", err)
+	}
+	status := waitForRun(t, service, notRunning)
+	if status.Operation != OperationDown {
+		t.Fatalf("operation = %q, want down", status.Operation)
+	}
+	if !slices.Equal(sessions.killed, []string{"frontend", "backend"}) {
+		t.Fatalf("killed = %v, want frontend then backend", sessions.killed)
+	}
+	if !sessions.running["docs"] {
+		t.Fatal("docs was torn down, want it left running")
+	}
+}
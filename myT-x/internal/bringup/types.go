@@ -0,0 +1,57 @@
+package bringup
+
+// StatusEvent carries the Status after every state change.
+const StatusEvent = "session-bringup:status"
+
+// Operation is the kind of run.
+type Operation string
+
+const (
+	OperationUp   Operation = "up"
+	OperationDown Operation = "down"
+)
+
+// State is the lifecycle state of one template within a run.
+type State string
+
+const (
+	StatePending  State = "pending"
+	StateStarting State = "starting"
+	// StateProbing means the session is up and its health probe is polling.
+	StateProbing State = "probing"
+	// StateReady means the health probe passed, or the session started when
+	// the template has no probe.
+	StateReady  State = "ready"
+	StateFailed State = "failed"
+	// StateSkipped means a dependency did not become ready.
+	StateSkipped  State = "skipped"
+	StateStopping State = "stopping"
+	StateStopped  State = "stopped"
+	StateCanceled State = "cancelled"
+)
+
+// finished reports whether the state is terminal.
+func (s State) finished() bool {
+	switch s {
+	case StateReady, StateFailed, StateSkipped, StateStopped, StateCanceled:
+		return true
+	default:
+		return false
+	}
+}
+
+// TemplateStatus is the frontend-safe status of one template.
+type TemplateStatus struct {
+	Name  string `json:"name"`
+	State State  `json:"state"`
+	// Reused is true when bring-up found the session already running.
+	Reused bool   `json:"reused,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Status is the frontend-safe status of the latest run.
+type Status struct {
+	Operation Operation        `json:"operation,omitempty"`
+	Running   bool             `json:"running"`
+	Templates []TemplateStatus `json:"templates"`
+}
@@ -0,0 +1,331 @@
+// Package bugreport bundles what a bug report needs into one zip: the tails
+// of the log files, the redacted config, a session summary, recent errors,
+// and recent audit entries, described by a manifest. Users attach the zip
+// instead of collecting each piece on request.
+package bugreport
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"myT-x/internal/logging"
+)
+
+// ManifestName is the manifest entry of the zip.
+const ManifestName = "manifest.json"
+
+const (
+	// DefaultMaxLogBytes is how much of the end of each log file is kept.
+	DefaultMaxLogBytes = 1024 * 1024
+	// DefaultRotatedGenerations is how many rotated generations are kept
+	// per log file, newest first.
+	DefaultRotatedGenerations = 1
+)
+
+// Input is the material of a report. Nil values are left out.
+type Input struct {
+	Version   string
+	CreatedAt time.Time
+	// LogFiles are active log files. Their newest rotated generations
+	// (<stem>-<unixtime>.log next to them) are included too. Missing files
+	// are listed in Manifest.Skipped.
+	LogFiles []string
+	// Config, Sessions, Errors, and Audit are written as JSON after Redact.
+	Config   any
+	Sessions any
+	Errors   any
+	Audit    any
+	// HomeDir is replaced by "~" in the JSON documents, the log files, and
+	// the log paths of the manifest.
+	HomeDir string
+	// MaxLogBytes <= 0 uses DefaultMaxLogBytes.
+	MaxLogBytes int64
+	// RotatedGenerations < 0 includes none; 0 uses DefaultRotatedGenerations.
+	RotatedGenerations int
+}
+
+// Manifest describes a report.
+type Manifest struct {
+	Version   string    `json:"version"`
+	OS        string    `json:"os"`
+	Arch      string    `json:"arch"`
+	GoVersion string    `json:"go_version"`
+	CreatedAt time.Time `json:"created_at"`
+	Files     []File    `json:"files"`
+	// Skipped lists material that could not be collected, with the reason.
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+// File is one entry of the zip.
+type File struct {
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+	// Source is the path the entry was read from, for log files.
+	Source string `json:"source,omitempty"`
+	// Truncated is true when only the end of the source was kept.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// Write writes the zip of in to w and returns its manifest. Material that
+// cannot be read is skipped and listed in the manifest; only failures to
+// write the zip are returned.
+func Write(w io.Writer, in Input) (Manifest, error) {
+	manifest := Manifest{
+		Version:   in.Version,
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+		GoVersion: runtime.Version(),
+		CreatedAt: in.CreatedAt.UTC(),
+		Files:     []File{},
+	}
+	maxLogBytes := in.MaxLogBytes
+	if maxLogBytes <= 0 {
+		maxLogBytes = DefaultMaxLogBytes
+	}
+	generations := in.RotatedGenerations
+	if generations == 0 {
+		generations = DefaultRotatedGenerations
+	}
+
+	archive := zip.NewWriter(w)
+	for _, doc := range []struct {
+		name  string
+		value any
+	}{
+		{"config.json", in.Config},
+		{"sessions.json", in.Sessions},
+		{"errors.json", in.Errors},
+		{"audit.json", in.Audit},
+	} {
+		if doc.value == nil {
+			continue
+		}
+		data, err := redactedJSON(doc.value, in.HomeDir)
+		if err != nil {
+			manifest.Skipped = append(manifest.Skipped, fmt.Sprintf("%s: %v", doc.name, err))
+			continue
+		}
+		if err := writeEntry(archive, doc.name, in.CreatedAt, data); err != nil {
+			return Manifest{}, err
+		}
+		manifest.Files = append(manifest.Files, File{Name: doc.name, Size: int64(len(data))})
+	}
+
+	used := map[string]bool{}
+	for _, path := range logSources(in.LogFiles, generations, &manifest.Skipped) {
+		data, truncated, err := readTail(path, maxLogBytes)
+		if err != nil {
+			manifest.Skipped = append(manifest.Skipped, fmt.Sprintf("%s: %v", path, err))
+			continue
+		}
+		// Logs quote commands, URLs and environment values verbatim.
+		data = []byte(RedactText(string(data), in.HomeDir))
+		name := entryName(path, used)
+		if err := writeEntry(archive, name, in.CreatedAt, data); err != nil {
+			return Manifest{}, err
+		}
+		manifest.Files = append(manifest.Files, File{
+			Name:      name,
+			Size:      int64(len(data)),
+			Source:    RedactText(path, in.HomeDir),
+			Truncated: truncated,
+		})
+	}
+
+	for i, skipped := range manifest.Skipped {
+		manifest.Skipped[i] = RedactText(skipped, in.HomeDir)
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return Manifest{}, err
+	}
+	if err := writeEntry(archive, ManifestName, in.CreatedAt, data); err != nil {
+		return Manifest{}, err
+	}
+	if err := archive.Close(); err != nil {
+		return Manifest{}, err
+	}
+	return manifest, nil
+}
+
+// WriteFile writes the zip of in to path, replacing a partial file on
+// failure.
+func WriteFile(path string, in Input) (Manifest, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return Manifest{}, fmt.Errorf("create bug report directory: %w", err)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return Manifest{}, fmt.Errorf("create bug report: %w", err)
+	}
+	manifest, err := Write(file, in)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(path)
+		return Manifest{}, err
+	}
+	return manifest, nil
+}
+
+func writeEntry(archive *zip.Writer, name string, modified time.Time, data []byte) error {
+	entry, err := archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: modified})
+	if err != nil {
+		return fmt.Errorf("add %s: %w", name, err)
+	}
+	if _, err := entry.Write(data); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	return nil
+}
+
+func redactedJSON(value any, homeDir string) ([]byte, error) {
+	redacted, err := Redact(value, homeDir)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(redacted, "", "  ")
+}
+
+// ActiveLogs returns the active *.log files of dir, leaving out rotated
+// generations (<stem>-<unixtime>.log of another file in dir). A missing dir
+// yields no files.
+func ActiveLogs(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	names := map[string]bool{}
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".log") {
+			names[entry.Name()] = true
+		}
+	}
+	var active []string
+	for name := range names {
+		if isRotated(name, names) {
+			continue
+		}
+		active = append(active, filepath.Join(dir, name))
+	}
+	sort.Strings(active)
+	return active
+}
+
+func isRotated(name string, names map[string]bool) bool {
+	stem := strings.TrimSuffix(name, ".log")
+	i := strings.LastIndex(stem, "-")
+	if i <= 0 || !names[stem[:i]+".log"] {
+		return false
+	}
+	_, ok := logging.ParseRotatedUnix(stem[:i], name)
+	return ok
+}
+
+// logSources returns each active log file followed by its newest rotated
+// generations. Missing active files are recorded in skipped.
+func logSources(active []string, generations int, skipped *[]string) []string {
+	var sources []string
+	seen := map[string]bool{}
+	for _, path := range active {
+		path = filepath.Clean(path)
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+		if _, err := os.Stat(path); err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				*skipped = append(*skipped, path+": not found")
+			} else {
+				*skipped = append(*skipped, fmt.Sprintf("%s: %v", path, err))
+			}
+			continue
+		}
+		sources = append(sources, path)
+		sources = append(sources, rotatedGenerations(path, generations)...)
+	}
+	return sources
+}
+
+func rotatedGenerations(path string, generations int) []string {
+	if generations <= 0 {
+		return nil
+	}
+	dir := filepath.Dir(path)
+	stem := strings.TrimSuffix(filepath.Base(path), ".log")
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	type rotated struct {
+		path      string
+		timestamp int64
+	}
+	var found []rotated
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		if timestamp, ok := logging.ParseRotatedUnix(stem, entry.Name()); ok {
+			found = append(found, rotated{path: filepath.Join(dir, entry.Name()), timestamp: timestamp})
+		}
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].timestamp > found[j].timestamp })
+	paths := make([]string, 0, min(generations, len(found)))
+	for _, r := range found[:min(generations, len(found))] {
+		paths = append(paths, r.path)
+	}
+	return paths
+}
+
+// readTail returns the last maxBytes of path, starting after the first line
+// break of the kept part when it was truncated.
+func readTail(path string, maxBytes int64) ([]byte, bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, false, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return nil, false, err
+	}
+	if info.Size() <= maxBytes {
+		data, err := io.ReadAll(file)
+		return data, false, err
+	}
+	if _, err := file.Seek(info.Size()-maxBytes, io.SeekStart); err != nil {
+		return nil, false, err
+	}
+	data, err := io.ReadAll(io.LimitReader(file, maxBytes))
+	if err != nil {
+		return nil, false, err
+	}
+	if i := slices.Index(data, '\n'); i >= 0 && i+1 < len(data) {
+		data = data[i+1:]
+	}
+	return data, true, nil
+}
+
+// entryName names a log file in the zip: logs/<base>, with a numeric
+// suffix when two directories hold files of the same name.
+func entryName(path string, used map[string]bool) string {
+	base := filepath.Base(path)
+	name := "logs/" + base
+	for i := 2; used[name]; i++ {
+		name = fmt.Sprintf("logs/%s-%d%s", strings.TrimSuffix(base, filepath.Ext(base)), i, filepath.Ext(base))
+	}
+	used[name] = true
+	return name
+}
@@ -0,0 +1,176 @@
+package bugreport
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteBundlesDocumentsAndLogs(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "app.log"), "line 1\nline 2\nline 3\n")
+	writeFile(t, filepath.Join(dir, "app-100.log"), "old\n")
+	writeFile(t, filepath.Join(dir, "app-200.log"), "newer\n")
+
+	var buf bytes.Buffer
+	manifest, err := Write(&buf, Input{
+		Version:     "1.2.3",
+		CreatedAt:   time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		LogFiles:    []string{filepath.Join(dir, "app.log"), filepath.Join(dir, "missing.log")},
+		Config:      map[string]any{"shell": "pwsh", "forge": map[string]any{"token": "ghp_secret"}},
+		Sessions:    []string{"alpha"},
+		MaxLogBytes: 10,
+	})
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	entries := readZip(t, buf.Bytes())
+	if got := entries["logs/app.log"]; got != "line 3\n" {
+		t.Fatalf("logs/app.log = %q, want the tail after the first line break", got)
+	}
+	if got := entries["logs/app-200.log"]; got != "newer\n" {
+		t.Fatalf("logs/app-200.log = %q", got)
+	}
+	if _, ok := entries["logs/app-100.log"]; ok {
+		t.Fatal("only the newest rotated generation should be included")
+	}
+	if strings.Contains(entries["config.json"], "ghp_secret") {
+		t.Fatalf("config.json = %s, token should be redacted", entries["config.json"])
+	}
+	if _, ok := entries["audit.json"]; ok {
+		t.Fatal("nil Audit should be left out")
+	}
+
+	var stored Manifest
+	if err := json.Unmarshal([]byte(entries[ManifestName]), &stored); err != nil {
+		t.Fatalf("manifest.json: %v", err)
+	}
+	if stored.Version != "1.2.3" || len(stored.Files) != len(manifest.Files) {
+		t.Fatalf("manifest = %+v, returned %+v", stored, manifest)
+	}
+	index := slices.IndexFunc(stored.Files, func(f File) bool { return f.Name == "logs/app.log" })
+	if index < 0 || !stored.Files[index].Truncated {
+		t.Fatalf("manifest files = %+v, want truncated logs/app.log", stored.Files)
+	}
+	if len(stored.Skipped) != 1 || !strings.Contains(stored.Skipped[0], "missing.log") {
+		t.Fatalf("Skipped = %v", stored.Skipped)
+	}
+}
+
+func TestWriteRedactsLogs(t *testing.T) {
+	home := filepath.Join(t.TempDir(), "Users", "alice")
+	dir := filepath.Join(home, "logs")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	lines := []string{
+		`level=INFO msg="[WEBHOOK] delivered" url=https://hooks.example.com/services/T0/B0/hooksecret`,
+		`level=DEBUG msg="[AUTH] refreshed" token=ghp_tokensecret api_key: "sk-keysecret"`,
+		`{"level":"DEBUG","password":"pwsecret","token_env":"GITHUB_TOKEN"}`,
+		`request header Authorization: Bearer bearersecret`,
+		`opened ` + filepath.Join(home, "repo", "main.go"),
+	}
+	writeFile(t, filepath.Join(dir, "app.log"), strings.Join(lines, "\n")+"\n")
+
+	var buf bytes.Buffer
+	manifest, err := Write(&buf, Input{
+		LogFiles: []string{filepath.Join(dir, "app.log"), filepath.Join(dir, "missing.log")},
+		HomeDir:  home,
+	})
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	log := readZip(t, buf.Bytes())["logs/app.log"]
+	for _, secret := range []string{"hooksecret", "ghp_tokensecret", "sk-keysecret", "pwsecret", "bearersecret", home} {
+		if strings.Contains(log, secret) {
+			t.Fatalf("logs/app.log still contains %q:\n%s", secret, log)
+		}
+	}
+	for _, kept := range []string{"https://hooks.example.com/" + Redacted, `"token_env":"GITHUB_TOKEN"`, filepath.Join("~", "repo", "main.go")} {
+		if !strings.Contains(log, kept) {
+			t.Fatalf("logs/app.log lacks %q:\n%s", kept, log)
+		}
+	}
+	for _, file := range manifest.Files {
+		if strings.Contains(file.Source, home) {
+			t.Fatalf("manifest source %q contains the home directory", file.Source)
+		}
+	}
+	for _, skipped := range manifest.Skipped {
+		if strings.Contains(skipped, home) {
+			t.Fatalf("manifest skipped entry %q contains the home directory", skipped)
+		}
+	}
+}
+
+func TestWriteFileSkipsUnencodableDocuments(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "reports", "report.zip")
+	if _, err := WriteFile(path, Input{Config: make(chan int)}); err != nil {
+		t.Fatalf("WriteFile() error = %v, unencodable documents should be skipped", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+}
+
+func TestActiveLogs(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"app.log", "app-100.log", "git.log", "shim-debug.log", "notes.txt"} {
+		writeFile(t, filepath.Join(dir, name), "x")
+	}
+	got := ActiveLogs(dir)
+	want := []string{filepath.Join(dir, "app.log"), filepath.Join(dir, "git.log"), filepath.Join(dir, "shim-debug.log")}
+	if !slices.Equal(got, want) {
+		t.Fatalf("ActiveLogs() = %v, want %v", got, want)
+	}
+	if got := ActiveLogs(filepath.Join(dir, "missing")); got != nil {
+		t.Fatalf("ActiveLogs(missing) = %v, want nil", got)
+	}
+}
+
+func TestEntryNameDisambiguates(t *testing.T) {
+	used := map[string]bool{}
+	first := entryName(filepath.Join("a", "app.log"), used)
+	second := entryName(filepath.Join("b", "app.log"), used)
+	if first != "logs/app.log" || second != "logs/app-2.log" {
+		t.Fatalf("entryName() = %q, %q", first, second)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readZip(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("zip.NewReader() error = %v", err)
+	}
+	entries := map[string]string{}
+	for _, file := range reader.File {
+		rc, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries[file.Name] = string(content)
+	}
+	return entries
+}
@@ -0,0 +1,155 @@
+package bugreport
+
+import (
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Redacted replaces removed values.
+const Redacted = "[REDACTED]"
+
+// secretKeyParts mark keys whose values are removed.
+var secretKeyParts = []string{"token", "secret", "password", "passwd", "apikey", "api_key", "authorization", "credential", "private_key", "signing_key"}
+
+// Patterns of secrets in free text such as log lines.
+var (
+	// logBearerPattern matches HTTP credentials, e.g. "Bearer ghp_...".
+	logBearerPattern = regexp.MustCompile(`(?i)\b(bearer|basic)\s+[A-Za-z0-9._~+/=-]+`)
+	// logSecretPattern matches key=value, key: value and "key":"value"
+	// pairs whose key contains one of secretKeyParts.
+	logSecretPattern = regexp.MustCompile(`(?i)([A-Za-z0-9_.-]*(?:` + strings.Join(secretKeyParts, "|") + `)[A-Za-z0-9_.-]*)("?\s*[=:]\s*)("(?:[^"\\]|\\.)*"|[^\s,}]+)`)
+	// logURLPattern matches http(s) URLs.
+	logURLPattern = regexp.MustCompile(`(?i)https?://[^\s"'<>]+`)
+)
+
+// envKeys name maps of environment variables; their values are removed and
+// their names kept.
+var envKeys = map[string]bool{"env": true, "vars": true, "pane_env": true}
+
+// Redact returns value as generic JSON with secrets removed:
+//   - values of keys that look like secrets (token, password, ...)
+//   - values of environment variable maps (env, vars, pane_env)
+//   - the path, query, and user info of http(s) URLs, which often carry
+//     webhook secrets or credentials
+//
+// Occurrences of homeDir are replaced by "~". value must be JSON-encodable.
+func Redact(value any, homeDir string) (any, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var generic any
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, err
+	}
+	homeDir = strings.TrimRight(homeDir, `\/`)
+	return redactValue(generic, "", homeDir), nil
+}
+
+func redactValue(value any, key string, homeDir string) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for childKey, child := range v {
+			if envKeys[strings.ToLower(key)] {
+				if s, ok := child.(string); ok && s != "" {
+					v[childKey] = Redacted
+				}
+				continue
+			}
+			v[childKey] = redactValue(child, childKey, homeDir)
+		}
+		return v
+	case []any:
+		for i, child := range v {
+			v[i] = redactValue(child, key, homeDir)
+		}
+		return v
+	case string:
+		if v == "" {
+			return v
+		}
+		if isSecretKey(key) {
+			return Redacted
+		}
+		return redactString(v, homeDir)
+	default:
+		return v
+	}
+}
+
+func isSecretKey(key string) bool {
+	key = strings.ToLower(key)
+	if strings.HasSuffix(key, "_env") {
+		// Names an environment variable holding the secret, e.g. token_env.
+		return false
+	}
+	for _, part := range secretKeyParts {
+		if strings.Contains(key, part) {
+			return true
+		}
+	}
+	return false
+}
+
+func redactString(s string, homeDir string) string {
+	if strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://") {
+		if u, err := url.Parse(s); err == nil && u.Host != "" {
+			redacted := u.Scheme + "://" + u.Host
+			if (u.Path != "" && u.Path != "/") || u.RawQuery != "" || u.User != nil {
+				redacted += "/" + Redacted
+			}
+			return redacted
+		}
+	}
+	if homeDir != "" && len(homeDir) > 1 {
+		s = replaceFold(s, homeDir, "~")
+	}
+	return s
+}
+
+// RedactText removes secrets from free text such as log lines: credentials
+// after "Bearer" or "Basic", values of key=value pairs whose key looks like a
+// secret, and the path, query, and user info of http(s) URLs. Occurrences of
+// homeDir are replaced by "~".
+func RedactText(s string, homeDir string) string {
+	s = logBearerPattern.ReplaceAllString(s, "$1 "+Redacted)
+	s = logSecretPattern.ReplaceAllStringFunc(s, func(match string) string {
+		parts := logSecretPattern.FindStringSubmatch(match)
+		if !isSecretKey(parts[1]) {
+			return match
+		}
+		if strings.HasPrefix(parts[3], `"`) {
+			return parts[1] + parts[2] + `"` + Redacted + `"`
+		}
+		return parts[1] + parts[2] + Redacted
+	})
+	s = logURLPattern.ReplaceAllStringFunc(s, func(match string) string {
+		return redactString(match, "")
+	})
+	homeDir = strings.TrimRight(homeDir, `\/`)
+	if homeDir != "" && len(homeDir) > 1 {
+		s = replaceFold(s, homeDir, "~")
+	}
+	return s
+}
+
+// replaceFold replaces old in s ignoring ASCII case, since Windows paths
+// are case-insensitive.
+func replaceFold(s, old, replacement string) string {
+	lowerS := strings.ToLower(s)
+	lowerOld := strings.ToLower(old)
+	var b strings.Builder
+	for {
+		i := strings.Index(lowerS, lowerOld)
+		if i < 0 {
+			b.WriteString(s)
+			return b.String()
+		}
+		b.WriteString(s[:i])
+		b.WriteString(replacement)
+		s = s[i+len(old):]
+		lowerS = lowerS[i+len(old):]
+	}
+}
@@ -0,0 +1,42 @@
+package bugreport
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	input := map[string]any{
+		"shell":    "pwsh",
+		"pane_env": map[string]string{"GITHUB_TOKEN": "ghp_x", "EMPTY": ""},
+		"forge":    map[string]any{"token": "ghp_y", "token_env": "GH_TOKEN", "api_url": "https://api.github.com"},
+		"webhooks": []any{map[string]any{"url": "https://hooks.example.com/T0/B0/secret?x=1", "secret": "s"}},
+		"mcp":      map[string]any{"env": map[string]any{"API_KEY": "k", "PORT": 1}},
+		"cwd":      `C:\Users\Alice\src\repo`,
+		"signing":  map[string]any{"signing_keys": []any{"k1", "k2"}},
+	}
+	redacted, err := Redact(input, `c:\users\alice\`)
+	if err != nil {
+		t.Fatalf("Redact() error = %v", err)
+	}
+	data, _ := json.Marshal(redacted)
+	text := string(data)
+	for _, secret := range []string{"ghp_x", "ghp_y", "/T0/B0", "x=1", `"s"`, `"k"`, "Alice", "k1"} {
+		if strings.Contains(text, secret) {
+			t.Fatalf("Redact() = %s, leaked %s", text, secret)
+		}
+	}
+	for _, kept := range []string{`"shell":"pwsh"`, `"GITHUB_TOKEN":"[REDACTED]"`, `"EMPTY":""`, `"token_env":"GH_TOKEN"`,
+		`"api_url":"https://api.github.com"`, `"url":"https://hooks.example.com/[REDACTED]"`, `"PORT":1`, `"cwd":"~\\src\\repo"`} {
+		if !strings.Contains(text, kept) {
+			t.Fatalf("Redact() = %s, want %s", text, kept)
+		}
+	}
+}
+
+func TestRedactRejectsUnencodableValues(t *testing.T) {
+	if _, err := Redact(func() {}, ""); err == nil {
+		t.Fatal("Redact(func) should fail")
+	}
+}
@@ -0,0 +1,111 @@
+// Package changelog holds the release notes shipped with the app and picks
+// the ones to show after an update.
+package changelog
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+//go:embed changelog.json
+var releasesJSON []byte
+
+// Change is one line of release notes in each UI language.
+type Change struct {
+	Ja string `json:"ja"`
+	En string `json:"en"`
+}
+
+// Release lists the changes of one app version.
+type Release struct {
+	Version string   `json:"version"`
+	Changes []Change `json:"changes"`
+}
+
+// Releases returns the embedded release notes, newest first.
+func Releases() ([]Release, error) {
+	return parseReleases(releasesJSON)
+}
+
+func parseReleases(raw []byte) ([]Release, error) {
+	var releases []Release
+	if err := json.Unmarshal(raw, &releases); err != nil {
+		return nil, fmt.Errorf("parse changelog: %w", err)
+	}
+	for _, release := range releases {
+		if _, ok := parseVersion(release.Version); !ok {
+			return nil, fmt.Errorf("parse changelog: invalid version %q", release.Version)
+		}
+	}
+	slices.SortStableFunc(releases, func(a, b Release) int {
+		return CompareVersions(b.Version, a.Version)
+	})
+	return releases, nil
+}
+
+// Between returns the releases newer than previous and not newer than
+// current, newest first.
+func Between(releases []Release, previous, current string) []Release {
+	var picked []Release
+	for _, release := range releases {
+		if CompareVersions(release.Version, previous) > 0 && CompareVersions(release.Version, current) <= 0 {
+			picked = append(picked, release)
+		}
+	}
+	return picked
+}
+
+// CompareVersions compares dotted numeric versions such as "1.10.2" and
+// returns -1, 0, or +1. Missing parts count as zero; an invalid version
+// sorts before every valid one.
+func CompareVersions(a, b string) int {
+	va, okA := parseVersion(a)
+	vb, okB := parseVersion(b)
+	switch {
+	case !okA || !okB:
+		if okA == okB {
+			return 0
+		}
+		if okA {
+			return 1
+		}
+		return -1
+	}
+	for i := range max(len(va), len(vb)) {
+		var pa, pb int
+		if i < len(va) {
+			pa = va[i]
+		}
+		if i < len(vb) {
+			pb = vb[i]
+		}
+		if pa != pb {
+			if pa < pb {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+func parseVersion(version string) ([]int, bool) {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if version == "" {
+		return nil, false
+	}
+	parts := strings.Split(version, ".")
+	numbers := make([]int, len(parts))
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, false
+		}
+		numbers[i] = n
+	}
+	return numbers, true
+}
@@ -0,0 +1,63 @@
+package changelog
+
+import (
+	"testing"
+)
+
+func TestReleasesParsesEmbeddedChangelog(t *testing.T) {
+	releases, err := Releases()
+	if err != nil {
+		t.Fatalf("Releases() error = %v", err)
+	}
+	if len(releases) == 0 {
+		t.Fatal("Releases() returned no releases")
+	}
+	for _, release := range releases {
+		for _, change := range release.Changes {
+			if change.Ja == "" || change.En == "" {
+				t.Errorf("release %s has a change without both languages: %+v", release.Version, change)
+			}
+		}
+	}
+}
+
+func TestBetweenPicksReleasesSincePreviousVersion(t *testing.T) {
+	releases, err := parseReleases([]byte(`[
+		{"version": "1.2.0"}, {"version": "1.10.0"}, {"version": "1.1.3"}, {"version": "1.11.0"}
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := Between(releases, "1.1.3", "1.10.0")
+	if len(got) != 2 || got[0].Version != "1.10.0" || got[1].Version != "1.2.0" {
+		t.Fatalf("Between() = %+v, want 1.10.0 and 1.2.0", got)
+	}
+	if got := Between(releases, "1.11.0", "1.11.0"); len(got) != 0 {
+		t.Fatalf("Between() without an update = %+v, want none", got)
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.1.3", "1.1.3", 0},
+		{"1.1", "1.1.0", 0},
+		{"v1.2.0", "1.10.0", -1},
+		{"2.0.0", "1.99.99", 1},
+		{"", "0.0.1", -1},
+		{"1.0.0-beta", "", 0},
+	}
+	for _, tt := range tests {
+		if got := CompareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestParseReleasesRejectsInvalidVersion(t *testing.T) {
+	if _, err := parseReleases([]byte(`[{"version": "next"}]`)); err == nil {
+		t.Fatal("parseReleases() with an invalid version error = nil")
+	}
+}
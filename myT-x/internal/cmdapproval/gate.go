@@ -0,0 +1,512 @@
+// Package cmdapproval holds tmux commands issued by shim clients in selected
+// sessions until the user allows or denies them. It is a safety harness for
+// fully autonomous agent runs: every mutating command an agent sends from a
+// pane of a gated session is surfaced to the frontend before it executes.
+package cmdapproval
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"myT-x/internal/apptypes"
+	"myT-x/internal/ipc"
+)
+
+const (
+	// PendingEvent is emitted with a PendingCommand when a command is held.
+	PendingEvent = "session:approval-pending"
+	// ResolvedEvent is emitted with a Resolution when a held command is
+	// allowed, denied, or expires.
+	ResolvedEvent = "session:approval-resolved"
+	// DefaultTimeout is how long a command waits for a decision before it is
+	// denied, used when Deps.Timeout is zero.
+	DefaultTimeout = 5 * time.Minute
+)
+
+// Decision is the user's answer to a held command.
+type Decision string
+
+const (
+	DecisionAllow       Decision = "allow"
+	DecisionDeny        Decision = "deny"
+	DecisionAllowAlways Decision = "allow-always"
+)
+
+// Resolution reasons reported in ResolvedEvent.
+const (
+	ReasonUser          = "user"
+	ReasonTimeout       = "timeout"
+	ReasonSessionClosed = "session-closed"
+	ReasonCanceled      = "canceled"
+)
+
+// readOnlyCommands never change session state and are not held.
+// mcp-resolve-stdio and resolve-session-by-cwd are shim plumbing;
+// activate-window is sent by a second app launch and only raises the window.
+var readOnlyCommands = map[string]struct{}{
+	"activate-window":        {},
+	"capture-pane":           {},
+	"display-message":        {},
+	"has-session":            {},
+	"list-buffers":           {},
+	"list-panes":             {},
+	"list-sessions":          {},
+	"list-windows":           {},
+	"mcp-resolve-stdio":      {},
+	"resolve-session-by-cwd": {},
+	"show":                   {},
+	"show-environment":       {},
+	"show-options":           {},
+}
+
+// PendingCommand is a command held for approval.
+type PendingCommand struct {
+	ID          string `json:"id"`
+	SessionName string `json:"session_name"`
+	PaneID      string `json:"pane_id"`
+	Command     string `json:"command"`
+	// CommandLine is a readable rendering of the command with its flags and args.
+	CommandLine string `json:"command_line"`
+	RequestedAt string `json:"requested_at"`
+	ExpiresAt   string `json:"expires_at"`
+}
+
+// Resolution reports how a held command was settled.
+type Resolution struct {
+	ID          string   `json:"id"`
+	SessionName string   `json:"session_name"`
+	Decision    Decision `json:"decision"`
+	Reason      string   `json:"reason"`
+}
+
+// SessionApproval is the approval mode of one session.
+type SessionApproval struct {
+	SessionName string `json:"session_name"`
+	Enabled     bool   `json:"enabled"`
+	// AllowedCommands were answered with allow-always and are no longer held.
+	AllowedCommands []string `json:"allowed_commands"`
+}
+
+// Deps holds external dependencies injected at construction time.
+type Deps struct {
+	// Next executes a command once it is allowed.
+	Next func(req ipc.TmuxRequest) ipc.TmuxResponse
+
+	// NextStream executes an allowed command whose client asked for
+	// streamed output. Optional: defaults to Next, which returns the whole
+	// output in the response.
+	NextStream func(ctx context.Context, req ipc.TmuxRequest, stdout, stderr io.Writer) ipc.TmuxResponse
+
+	// SessionForPane returns the session owning the caller pane ("%N").
+	SessionForPane func(callerPane string) (string, bool)
+
+	// PaneForPID returns the pane ("%N") whose process tree contains pid,
+	// the client process observed by the IPC server. Optional: without it,
+	// or when the transport reports no PID, the client-supplied CallerPane
+	// is used.
+	PaneForPID func(pid uint32) (string, bool)
+
+	// Emitter sends approval events. Optional: defaults to apptypes.NoopEmitter.
+	Emitter apptypes.RuntimeEventEmitter
+
+	// Timeout bounds the wait for a decision. Optional: defaults to DefaultTimeout.
+	Timeout time.Duration
+
+	// Now returns the current time. Optional: defaults to time.Now.
+	Now func() time.Time
+
+	// OnResolved is called after every decision, e.g. to keep an audit log.
+	// Optional.
+	OnResolved func(command PendingCommand, resolution Resolution)
+}
+
+// pendingEntry is a held command and the channel its decision is sent on.
+type pendingEntry struct {
+	seq      uint64
+	command  PendingCommand
+	decision chan Resolution
+}
+
+// sessionState is the approval configuration of one session.
+type sessionState struct {
+	enabled bool
+	allowed map[string]struct{}
+}
+
+// Gate wraps a command executor and holds commands from gated sessions until
+// they are resolved. It implements ipc.StreamingExecutor and
+// ipc.ContextExecutor.
+//
+// Thread-safety is managed internally via mu. No external locking is required.
+type Gate struct {
+	deps   Deps
+	nextID atomic.Uint64
+
+	mu       sync.Mutex
+	sessions map[string]*sessionState
+	pending  map[string]*pendingEntry
+}
+
+// NewGate creates an approval gate.
+// Panics if Next or SessionForPane is nil.
+func NewGate(deps Deps) *Gate {
+	if deps.Next == nil {
+		panic("cmdapproval.NewGate: Next must be non-nil")
+	}
+	if deps.SessionForPane == nil {
+		panic("cmdapproval.NewGate: SessionForPane must be non-nil")
+	}
+	if deps.Emitter == nil {
+		deps.Emitter = apptypes.NoopEmitter{}
+	}
+	if deps.Timeout <= 0 {
+		deps.Timeout = DefaultTimeout
+	}
+	if deps.Now == nil {
+		deps.Now = time.Now
+	}
+	return &Gate{
+		deps:     deps,
+		sessions: map[string]*sessionState{},
+		pending:  map[string]*pendingEntry{},
+	}
+}
+
+// Execute runs req, first holding it for approval when it comes from a pane
+// of a gated session and is not read-only or already allowed.
+func (g *Gate) Execute(req ipc.TmuxRequest) ipc.TmuxResponse {
+	return g.ExecuteContext(context.Background(), req)
+}
+
+// ExecuteContext is Execute for a request the server may abandon. A command
+// held for approval is denied when ctx is canceled.
+func (g *Gate) ExecuteContext(ctx context.Context, req ipc.TmuxRequest) ipc.TmuxResponse {
+	if denied, ok := g.authorize(ctx, req); !ok {
+		return denied
+	}
+	return g.deps.Next(req)
+}
+
+// ExecuteStream is Execute for clients that asked for streamed output. A
+// command held for approval is denied when ctx is canceled.
+func (g *Gate) ExecuteStream(ctx context.Context, req ipc.TmuxRequest, stdout, stderr io.Writer) ipc.TmuxResponse {
+	if denied, ok := g.authorize(ctx, req); !ok {
+		return denied
+	}
+	if g.deps.NextStream == nil {
+		return g.deps.Next(req)
+	}
+	return g.deps.NextStream(ctx, req, stdout, stderr)
+}
+
+// authorize holds req for approval when required. ok is false when the
+// command was denied; denied is then the response to return.
+//
+// While any session is gated, a mutating command whose caller cannot be
+// attributed to a live pane is denied: an agent could otherwise escape the
+// gate by unsetting or spoofing TMUX_PANE.
+func (g *Gate) authorize(ctx context.Context, req ipc.TmuxRequest) (denied ipc.TmuxResponse, ok bool) {
+	command := strings.TrimSpace(req.Command)
+	if _, readOnly := readOnlyCommands[command]; readOnly || !g.anyGated() {
+		return ipc.TmuxResponse{}, true
+	}
+	req.CallerPane = g.callerPane(req)
+	sessionName, ok := "", false
+	if req.CallerPane != "" {
+		sessionName, ok = g.deps.SessionForPane(req.CallerPane)
+	}
+	if !ok {
+		slog.Warn("[APPROVAL] command from an unidentified caller denied",
+			"command", command, "pid", req.CallerPID)
+		return ipc.TmuxResponse{
+			ExitCode: 1,
+			Stderr:   fmt.Sprintf("%s denied: approval mode is active and the calling pane could not be identified\n", command),
+		}, false
+	}
+	if !g.requiresApproval(sessionName, command) {
+		return ipc.TmuxResponse{}, true
+	}
+
+	entry := g.hold(sessionName, command, req)
+	timer := time.NewTimer(g.deps.Timeout)
+	defer timer.Stop()
+
+	var resolution Resolution
+	select {
+	case resolution = <-entry.decision:
+	case <-timer.C:
+		resolution = g.settleUnanswered(entry, ReasonTimeout)
+	case <-ctx.Done():
+		resolution = g.settleUnanswered(entry, ReasonCanceled)
+	}
+
+	if resolution.Decision == DecisionDeny {
+		slog.Info("[APPROVAL] command denied",
+			"session", sessionName, "command", command, "reason", resolution.Reason)
+		return ipc.TmuxResponse{
+			ExitCode: 1,
+			Stderr:   fmt.Sprintf("%s denied by user (approval mode, %s)\n", command, resolution.Reason),
+		}, false
+	}
+	return ipc.TmuxResponse{}, true
+}
+
+// SetEnabled turns approval mode on or off for sessionName. Turning it off
+// keeps already held commands pending until they are resolved.
+func (g *Gate) SetEnabled(sessionName string, enabled bool) error {
+	sessionName = strings.TrimSpace(sessionName)
+	if sessionName == "" {
+		return errors.New("session name is required")
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !enabled {
+		delete(g.sessions, sessionName)
+		return nil
+	}
+	if _, ok := g.sessions[sessionName]; !ok {
+		g.sessions[sessionName] = &sessionState{enabled: true, allowed: map[string]struct{}{}}
+	}
+	return nil
+}
+
+// Session returns the approval mode of sessionName.
+func (g *Gate) Session(sessionName string) SessionApproval {
+	sessionName = strings.TrimSpace(sessionName)
+	approval := SessionApproval{SessionName: sessionName, AllowedCommands: []string{}}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if state, ok := g.sessions[sessionName]; ok {
+		approval.Enabled = state.enabled
+		for command := range state.allowed {
+			approval.AllowedCommands = append(approval.AllowedCommands, command)
+		}
+		slices.Sort(approval.AllowedCommands)
+	}
+	return approval
+}
+
+// Pending returns the held commands, oldest first.
+func (g *Gate) Pending() []PendingCommand {
+	g.mu.Lock()
+	entries := make([]*pendingEntry, 0, len(g.pending))
+	for _, entry := range g.pending {
+		entries = append(entries, entry)
+	}
+	slices.SortFunc(entries, func(a, b *pendingEntry) int { return cmp.Compare(a.seq, b.seq) })
+	pending := make([]PendingCommand, 0, len(entries))
+	for _, entry := range entries {
+		pending = append(pending, entry.command)
+	}
+	g.mu.Unlock()
+	return pending
+}
+
+// Resolve settles the held command id. allow-always also stops holding that
+// command for the rest of the session's approval mode.
+func (g *Gate) Resolve(id string, decision Decision) error {
+	switch decision {
+	case DecisionAllow, DecisionDeny, DecisionAllowAlways:
+	default:
+		return fmt.Errorf("unknown approval decision %q", decision)
+	}
+	if !g.settle(strings.TrimSpace(id), Resolution{Decision: decision, Reason: ReasonUser}) {
+		return fmt.Errorf("approval request %q is not pending", id)
+	}
+	return nil
+}
+
+// CleanupSession denies the session's held commands and forgets its mode.
+func (g *Gate) CleanupSession(sessionName string) error {
+	sessionName = strings.TrimSpace(sessionName)
+	if sessionName == "" {
+		return nil
+	}
+	g.mu.Lock()
+	delete(g.sessions, sessionName)
+	var ids []string
+	for id, entry := range g.pending {
+		if entry.command.SessionName == sessionName {
+			ids = append(ids, id)
+		}
+	}
+	g.mu.Unlock()
+
+	for _, id := range ids {
+		g.settle(id, Resolution{Decision: DecisionDeny, Reason: ReasonSessionClosed})
+	}
+	return nil
+}
+
+// RenameSession moves approval state and held commands to newName.
+func (g *Gate) RenameSession(oldName, newName string) error {
+	oldName = strings.TrimSpace(oldName)
+	newName = strings.TrimSpace(newName)
+	if oldName == "" || newName == "" || oldName == newName {
+		return nil
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if state, ok := g.sessions[oldName]; ok {
+		delete(g.sessions, oldName)
+		g.sessions[newName] = state
+	}
+	for _, entry := range g.pending {
+		if entry.command.SessionName == oldName {
+			entry.command.SessionName = newName
+		}
+	}
+	return nil
+}
+
+// callerPane returns the pane req was sent from. The pane resolved from the
+// client process wins over the CallerPane the client reports.
+func (g *Gate) callerPane(req ipc.TmuxRequest) string {
+	claimed := strings.TrimSpace(req.CallerPane)
+	if g.deps.PaneForPID == nil || req.CallerPID == 0 {
+		return claimed
+	}
+	pane, ok := g.deps.PaneForPID(req.CallerPID)
+	if !ok {
+		return ""
+	}
+	if claimed != "" && claimed != pane {
+		slog.Warn("[APPROVAL] caller pane does not match the client process",
+			"claimed", claimed, "pane", pane, "pid", req.CallerPID)
+	}
+	return pane
+}
+
+// anyGated reports whether approval mode is on for at least one session.
+func (g *Gate) anyGated() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, state := range g.sessions {
+		if state.enabled {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *Gate) requiresApproval(sessionName, command string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	state, ok := g.sessions[sessionName]
+	if !ok || !state.enabled {
+		return false
+	}
+	_, allowed := state.allowed[command]
+	return !allowed
+}
+
+// hold registers a pending command and announces it to the frontend.
+func (g *Gate) hold(sessionName, command string, req ipc.TmuxRequest) *pendingEntry {
+	now := g.deps.Now()
+	seq := g.nextID.Add(1)
+	entry := &pendingEntry{
+		seq: seq,
+		command: PendingCommand{
+			ID:          "approval-" + strconv.FormatUint(seq, 10),
+			SessionName: sessionName,
+			PaneID:      strings.TrimSpace(req.CallerPane),
+			Command:     command,
+			CommandLine: formatCommandLine(command, req),
+			RequestedAt: now.UTC().Format(time.RFC3339Nano),
+			ExpiresAt:   now.Add(g.deps.Timeout).UTC().Format(time.RFC3339Nano),
+		},
+		// Buffered so settle never blocks on a waiter that already left.
+		decision: make(chan Resolution, 1),
+	}
+	g.mu.Lock()
+	g.pending[entry.command.ID] = entry
+	g.mu.Unlock()
+
+	slog.Info("[APPROVAL] command held for approval",
+		"session", sessionName, "pane", entry.command.PaneID, "command", command)
+	g.deps.Emitter.Emit(PendingEvent, entry.command)
+	return entry
+}
+
+// settleUnanswered denies entry for reason unless a decision raced it, in
+// which case that decision is returned.
+func (g *Gate) settleUnanswered(entry *pendingEntry, reason string) Resolution {
+	resolution := Resolution{Decision: DecisionDeny, Reason: reason}
+	if !g.settle(entry.command.ID, resolution) {
+		// The decision is already buffered.
+		resolution = <-entry.decision
+	}
+	return resolution
+}
+
+// settle removes the pending command id and delivers resolution to its
+// waiter. It returns false when id is not pending.
+func (g *Gate) settle(id string, resolution Resolution) bool {
+	g.mu.Lock()
+	entry, ok := g.pending[id]
+	if !ok {
+		g.mu.Unlock()
+		return false
+	}
+	delete(g.pending, id)
+	resolution.ID = id
+	resolution.SessionName = entry.command.SessionName
+	if resolution.Decision == DecisionAllowAlways {
+		if state, gated := g.sessions[resolution.SessionName]; gated {
+			state.allowed[entry.command.Command] = struct{}{}
+		}
+	}
+	g.mu.Unlock()
+
+	entry.decision <- resolution
+	g.deps.Emitter.Emit(ResolvedEvent, resolution)
+	if g.deps.OnResolved != nil {
+		g.deps.OnResolved(entry.command, resolution)
+	}
+	return true
+}
+
+// formatCommandLine renders req as a shell-like command line for display.
+func formatCommandLine(command string, req ipc.TmuxRequest) string {
+	parts := []string{command}
+	flags := make([]string, 0, len(req.Flags))
+	for flag := range req.Flags {
+		flags = append(flags, flag)
+	}
+	slices.Sort(flags)
+	for _, flag := range flags {
+		switch value := req.Flags[flag].(type) {
+		case bool:
+			if value {
+				parts = append(parts, flag)
+			}
+		case string:
+			parts = append(parts, flag, quoteArg(value))
+		case float64:
+			parts = append(parts, flag, strconv.FormatFloat(value, 'f', -1, 64))
+		default:
+			parts = append(parts, flag, quoteArg(fmt.Sprint(value)))
+		}
+	}
+	for _, arg := range req.Args {
+		parts = append(parts, quoteArg(arg))
+	}
+	return strings.Join(parts, " ")
+}
+
+func quoteArg(value string) string {
+	if value == "" || strings.ContainsAny(value, " \t\r\n\"'") {
+		return strconv.Quote(value)
+	}
+	return value
+}
@@ -0,0 +1,454 @@
+package cmdapproval
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"myT-x/internal/apptypes"
+	"myT-x/internal/ipc"
+)
+
+type recordedEvent struct {
+	name    string
+	payload any
+}
+
+type testHarness struct {
+	gate *Gate
+
+	mu       sync.Mutex
+	executed []string
+	events   []recordedEvent
+	held     chan PendingCommand
+}
+
+func newTestHarness(t *testing.T, timeout time.Duration) *testHarness {
+	t.Helper()
+	h := &testHarness{held: make(chan PendingCommand, 8)}
+	h.gate = NewGate(Deps{
+		Next: func(req ipc.TmuxRequest) ipc.TmuxResponse {
+			h.mu.Lock()
+			h.executed = append(h.executed, req.Command)
+			h.mu.Unlock()
+			return ipc.TmuxResponse{Stdout: "ok\n"}
+		},
+		SessionForPane: func(callerPane string) (string, bool) {
+			switch callerPane {
+			case "%1":
+				return "agent", true
+			case "%2":
+				return "other", true
+			}
+			return "", false
+		},
+		Emitter: apptypes.EventEmitterFunc(func(name string, payload any) {
+			h.mu.Lock()
+			h.events = append(h.events, recordedEvent{name: name, payload: payload})
+			h.mu.Unlock()
+			if pending, ok := payload.(PendingCommand); ok && name == PendingEvent {
+				h.held <- pending
+			}
+		}),
+		Timeout: timeout,
+	})
+	return h
+}
+
+func (h *testHarness) executedCommands() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]string(nil), h.executed...)
+}
+
+// executeAsync runs req through the gate and returns its response channel.
+func (h *testHarness) executeAsync(req ipc.TmuxRequest) <-chan ipc.TmuxResponse {
+	result := make(chan ipc.TmuxResponse, 1)
+	go func() { result <- h.gate.Execute(req) }()
+	return result
+}
+
+func (h *testHarness) waitHeld(t *testing.T) PendingCommand {
+	t.Helper()
+	select {
+	case pending := <-h.held:
+		return pending
+	case <-time.After(2 * time.Second):
+		t.Fatal("command was not held for approval")
+		return PendingCommand{}
+	}
+}
+
+func waitResponse(t *testing.T, result <-chan ipc.TmuxResponse) ipc.TmuxResponse {
+	t.Helper()
+	select {
+	case resp := <-result:
+		return resp
+	case <-time.After(2 * time.Second):
+		t.Fatal("Execute() did not return")
+		return ipc.TmuxResponse{}
+	}
+}
+
+func sendKeys(pane string) ipc.TmuxRequest {
+	return ipc.TmuxRequest{
+		Command:    "send-keys",
+		Flags:      map[string]any{"-t": "%3"},
+		Args:       []string{"rm -rf build", "Enter"},
+		CallerPane: pane,
+	}
+}
+
+func TestNewGatePanicsWithoutRequiredDeps(t *testing.T) {
+	tests := []struct {
+		name string
+		deps Deps
+	}{
+		{name: "nil Next", deps: Deps{SessionForPane: func(string) (string, bool) { return "", false }}},
+		{name: "nil SessionForPane", deps: Deps{Next: func(ipc.TmuxRequest) ipc.TmuxResponse { return ipc.TmuxResponse{} }}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Fatal("NewGate() did not panic")
+				}
+			}()
+			NewGate(tt.deps)
+		})
+	}
+}
+
+func TestExecutePassesThroughUngatedRequests(t *testing.T) {
+	h := newTestHarness(t, time.Minute)
+	if err := h.gate.SetEnabled("agent", true); err != nil {
+		t.Fatal(err)
+	}
+
+	requests := []ipc.TmuxRequest{
+		sendKeys("%2"), // other session
+		{Command: "list-panes", CallerPane: "%1"},
+		{Command: "activate-window"},
+	}
+	for _, req := range requests {
+		if resp := h.gate.Execute(req); resp.ExitCode != 0 {
+			t.Fatalf("Execute(%+v) = %+v, want pass-through", req, resp)
+		}
+	}
+	if got := h.executedCommands(); len(got) != len(requests) {
+		t.Fatalf("executed = %v, want %d pass-through commands", got, len(requests))
+	}
+	if pending := h.gate.Pending(); len(pending) != 0 {
+		t.Fatalf("Pending() = %+v, want none", pending)
+	}
+}
+
+func TestExecutePassesThroughUnidentifiedCallersWithoutGatedSessions(t *testing.T) {
+	h := newTestHarness(t, time.Minute)
+	for _, req := range []ipc.TmuxRequest{sendKeys(""), sendKeys("%9")} {
+		if resp := h.gate.Execute(req); resp.ExitCode != 0 {
+			t.Fatalf("Execute(%+v) = %+v, want pass-through", req, resp)
+		}
+	}
+}
+
+func TestExecuteDeniesUnidentifiedCallersWhileGated(t *testing.T) {
+	h := newTestHarness(t, time.Minute)
+	if err := h.gate.SetEnabled("agent", true); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, req := range []ipc.TmuxRequest{
+		sendKeys(""),   // TMUX_PANE unset
+		sendKeys(" "),  // blank
+		sendKeys("%9"), // unknown pane
+	} {
+		resp := h.gate.Execute(req)
+		if resp.ExitCode == 0 || !strings.Contains(resp.Stderr, "could not be identified") {
+			t.Fatalf("Execute(%+v) = %+v, want denial of an unidentified caller", req, resp)
+		}
+	}
+	if got := h.executedCommands(); len(got) != 0 {
+		t.Fatalf("executed = %v, want none", got)
+	}
+	if pending := h.gate.Pending(); len(pending) != 0 {
+		t.Fatalf("Pending() = %+v, want none", pending)
+	}
+}
+
+func TestExecuteIdentifiesCallerByProcess(t *testing.T) {
+	h := newTestHarness(t, time.Minute)
+	h.gate.deps.PaneForPID = func(pid uint32) (string, bool) {
+		if pid == 100 {
+			return "%1", true
+		}
+		return "", false
+	}
+	if err := h.gate.SetEnabled("agent", true); err != nil {
+		t.Fatal(err)
+	}
+
+	// A pane of the gated session claiming to be a pane of another session.
+	spoofed := sendKeys("%2")
+	spoofed.CallerPID = 100
+	result := h.executeAsync(spoofed)
+	pending := h.waitHeld(t)
+	if pending.SessionName != "agent" || pending.PaneID != "%1" {
+		t.Fatalf("held = %+v, want the command attributed to pane %%1 of agent", pending)
+	}
+	if err := h.gate.Resolve(pending.ID, DecisionDeny); err != nil {
+		t.Fatal(err)
+	}
+	if resp := waitResponse(t, result); resp.ExitCode == 0 {
+		t.Fatalf("response = %+v, want denial", resp)
+	}
+
+	// A process outside every pane, whatever it claims.
+	outside := sendKeys("%2")
+	outside.CallerPID = 200
+	if resp := h.gate.Execute(outside); resp.ExitCode == 0 {
+		t.Fatalf("Execute(outside) = %+v, want denial", resp)
+	}
+	if got := h.executedCommands(); len(got) != 0 {
+		t.Fatalf("executed = %v, want none", got)
+	}
+}
+
+func TestExecuteHoldsUntilAllowed(t *testing.T) {
+	h := newTestHarness(t, time.Minute)
+	if err := h.gate.SetEnabled("agent", true); err != nil {
+		t.Fatal(err)
+	}
+
+	result := h.executeAsync(sendKeys("%1"))
+	held := h.waitHeld(t)
+	if held.SessionName != "agent" || held.PaneID != "%1" || held.Command != "send-keys" {
+		t.Fatalf("held = %+v", held)
+	}
+	if want := `send-keys -t %3 "rm -rf build" Enter`; held.CommandLine != want {
+		t.Fatalf("CommandLine = %q, want %q", held.CommandLine, want)
+	}
+	if pending := h.gate.Pending(); len(pending) != 1 || pending[0].ID != held.ID {
+		t.Fatalf("Pending() = %+v, want the held command", pending)
+	}
+	if got := h.executedCommands(); len(got) != 0 {
+		t.Fatalf("executed before approval = %v", got)
+	}
+
+	if err := h.gate.Resolve(held.ID, DecisionAllow); err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if resp := waitResponse(t, result); resp.ExitCode != 0 || resp.Stdout != "ok\n" {
+		t.Fatalf("response = %+v, want executed", resp)
+	}
+	if err := h.gate.Resolve(held.ID, DecisionAllow); err == nil {
+		t.Fatal("second Resolve() error = nil, want not pending")
+	}
+}
+
+func TestExecuteDeniedAndTimedOut(t *testing.T) {
+	h := newTestHarness(t, 50*time.Millisecond)
+	if err := h.gate.SetEnabled("agent", true); err != nil {
+		t.Fatal(err)
+	}
+
+	result := h.executeAsync(sendKeys("%1"))
+	held := h.waitHeld(t)
+	if err := h.gate.Resolve(held.ID, DecisionDeny); err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if resp := waitResponse(t, result); resp.ExitCode != 1 || !strings.Contains(resp.Stderr, "denied") {
+		t.Fatalf("denied response = %+v", resp)
+	}
+
+	resp := h.gate.Execute(sendKeys("%1"))
+	if resp.ExitCode != 1 || !strings.Contains(resp.Stderr, ReasonTimeout) {
+		t.Fatalf("timed out response = %+v", resp)
+	}
+	if got := h.executedCommands(); len(got) != 0 {
+		t.Fatalf("executed = %v, want nothing", got)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var reasons []string
+	for _, event := range h.events {
+		if resolution, ok := event.payload.(Resolution); ok && event.name == ResolvedEvent {
+			reasons = append(reasons, resolution.Reason)
+		}
+	}
+	if len(reasons) != 2 || reasons[0] != ReasonUser || reasons[1] != ReasonTimeout {
+		t.Fatalf("resolved reasons = %v, want [user timeout]", reasons)
+	}
+}
+
+func TestExecuteStream(t *testing.T) {
+	var streamed strings.Builder
+	gate := NewGate(Deps{
+		Next: func(ipc.TmuxRequest) ipc.TmuxResponse {
+			return ipc.TmuxResponse{Stdout: "whole\n"}
+		},
+		NextStream: func(_ context.Context, _ ipc.TmuxRequest, stdout, _ io.Writer) ipc.TmuxResponse {
+			_, _ = io.WriteString(stdout, "chunk\n")
+			return ipc.TmuxResponse{}
+		},
+		SessionForPane: func(string) (string, bool) { return "agent", true },
+		Timeout:        50 * time.Millisecond,
+	})
+
+	if resp := gate.ExecuteStream(context.Background(), sendKeys("%1"), &streamed, io.Discard); resp.ExitCode != 0 || streamed.String() != "chunk\n" {
+		t.Fatalf("ExecuteStream() = %+v, streamed %q", resp, streamed.String())
+	}
+
+	if err := gate.SetEnabled("agent", true); err != nil {
+		t.Fatal(err)
+	}
+	streamed.Reset()
+	resp := gate.ExecuteStream(context.Background(), sendKeys("%1"), &streamed, io.Discard)
+	if resp.ExitCode != 1 || !strings.Contains(resp.Stderr, ReasonTimeout) || streamed.Len() != 0 {
+		t.Fatalf("timed out ExecuteStream() = %+v, streamed %q", resp, streamed.String())
+	}
+}
+
+func TestExecuteStreamFallsBackToNext(t *testing.T) {
+	h := newTestHarness(t, time.Minute)
+	var streamed strings.Builder
+	resp := h.gate.ExecuteStream(context.Background(), sendKeys("%1"), &streamed, io.Discard)
+	if resp.Stdout != "ok\n" || streamed.Len() != 0 {
+		t.Fatalf("ExecuteStream() = %+v, streamed %q, want whole output from Next", resp, streamed.String())
+	}
+}
+
+func TestExecuteStreamCanceledWhileHeld(t *testing.T) {
+	h := newTestHarness(t, time.Minute)
+	if err := h.gate.SetEnabled("agent", true); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan ipc.TmuxResponse, 1)
+	go func() { result <- h.gate.ExecuteStream(ctx, sendKeys("%1"), io.Discard, io.Discard) }()
+	h.waitHeld(t)
+	cancel()
+
+	if resp := waitResponse(t, result); resp.ExitCode != 1 || !strings.Contains(resp.Stderr, ReasonCanceled) {
+		t.Fatalf("canceled response = %+v", resp)
+	}
+	if pending := h.gate.Pending(); len(pending) != 0 {
+		t.Fatalf("Pending() = %+v, want none after cancel", pending)
+	}
+	if got := h.executedCommands(); len(got) != 0 {
+		t.Fatalf("executed = %v, want nothing", got)
+	}
+}
+
+func TestAllowAlwaysStopsHoldingCommand(t *testing.T) {
+	h := newTestHarness(t, time.Minute)
+	if err := h.gate.SetEnabled("agent", true); err != nil {
+		t.Fatal(err)
+	}
+
+	result := h.executeAsync(sendKeys("%1"))
+	if err := h.gate.Resolve(h.waitHeld(t).ID, DecisionAllowAlways); err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	waitResponse(t, result)
+
+	if resp := h.gate.Execute(sendKeys("%1")); resp.ExitCode != 0 {
+		t.Fatalf("allowed command response = %+v", resp)
+	}
+	if got := h.gate.Session("agent").AllowedCommands; len(got) != 1 || got[0] != "send-keys" {
+		t.Fatalf("AllowedCommands = %v, want [send-keys]", got)
+	}
+
+	// Other commands are still held.
+	result = h.executeAsync(ipc.TmuxRequest{Command: "kill-pane", CallerPane: "%1"})
+	if err := h.gate.Resolve(h.waitHeld(t).ID, DecisionDeny); err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	waitResponse(t, result)
+
+	// Re-enabling after disabling starts from a clean allow list.
+	if err := h.gate.SetEnabled("agent", false); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.gate.SetEnabled("agent", true); err != nil {
+		t.Fatal(err)
+	}
+	if got := h.gate.Session("agent"); !got.Enabled || len(got.AllowedCommands) != 0 {
+		t.Fatalf("Session() after re-enable = %+v", got)
+	}
+}
+
+func TestResolveRejectsUnknownDecision(t *testing.T) {
+	h := newTestHarness(t, time.Minute)
+	if err := h.gate.Resolve("approval-1", Decision("maybe")); err == nil {
+		t.Fatal("Resolve() error = nil, want unknown decision error")
+	}
+	if err := h.gate.SetEnabled(" ", true); err == nil {
+		t.Fatal("SetEnabled() error = nil, want error for empty session")
+	}
+}
+
+func TestCleanupAndRenameSession(t *testing.T) {
+	h := newTestHarness(t, time.Minute)
+	if err := h.gate.SetEnabled("agent", true); err != nil {
+		t.Fatal(err)
+	}
+
+	result := h.executeAsync(sendKeys("%1"))
+	held := h.waitHeld(t)
+	if err := h.gate.RenameSession("agent", "agent-2"); err != nil {
+		t.Fatal(err)
+	}
+	if got := h.gate.Session("agent-2"); !got.Enabled {
+		t.Fatalf("Session(agent-2) = %+v, want enabled after rename", got)
+	}
+	if pending := h.gate.Pending(); len(pending) != 1 || pending[0].SessionName != "agent-2" {
+		t.Fatalf("Pending() after rename = %+v", pending)
+	}
+
+	if err := h.gate.CleanupSession("agent-2"); err != nil {
+		t.Fatal(err)
+	}
+	resp := waitResponse(t, result)
+	if resp.ExitCode != 1 || !strings.Contains(resp.Stderr, ReasonSessionClosed) {
+		t.Fatalf("response after cleanup = %+v", resp)
+	}
+	if got := h.gate.Session("agent-2"); got.Enabled {
+		t.Fatalf("Session() after cleanup = %+v, want disabled", got)
+	}
+	if err := h.gate.Resolve(held.ID, DecisionAllow); err == nil {
+		t.Fatal("Resolve() after cleanup error = nil")
+	}
+}
+
+func TestOnResolvedReceivesCommandAndDecision(t *testing.T) {
+	resolved := make(chan Resolution, 1)
+	var gotCommand PendingCommand
+	gate := NewGate(Deps{
+		Next:           func(ipc.TmuxRequest) ipc.TmuxResponse { return ipc.TmuxResponse{} },
+		SessionForPane: func(string) (string, bool) { return "agent", true },
+		Timeout:        20 * time.Millisecond,
+		OnResolved: func(command PendingCommand, resolution Resolution) {
+			gotCommand = command
+			resolved <- resolution
+		},
+	})
+	if err := gate.SetEnabled("agent", true); err != nil {
+		t.Fatal(err)
+	}
+
+	gate.Execute(sendKeys("%1"))
+	select {
+	case resolution := <-resolved:
+		if resolution.Reason != ReasonTimeout || gotCommand.Command != "send-keys" {
+			t.Fatalf("OnResolved(%+v, %+v), want timed out send-keys", gotCommand, resolution)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("OnResolved was not called")
+	}
+}
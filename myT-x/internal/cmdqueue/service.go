@@ -0,0 +1,650 @@
+// Package cmdqueue runs commands in a pane one at a time, waiting for the
+// shell to finish each command before sending the next.
+//
+// Completion is detected from shell integration markers (see package
+// shellintegration): a command-finished marker, or a prompt-start marker
+// after a command-executed marker. Panes without shell integration fall back
+// to the per-command timeout as pacing.
+//
+// A batch enqueued with Options.AfterPaneID waits for the exit status of the
+// next command in another pane, which chains work across panes ("run tests,
+// then deploy if they pass").
+//
+// A batch enqueued with Options.KillPolicy is supervised: a command that is
+// still running in the foreground at its timeout is sent the policy's
+// signals (Ctrl-C by default) and reported via TimeoutEvent, which guards
+// unattended agents against commands that never return.
+package cmdqueue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"myT-x/internal/apptypes"
+	"myT-x/internal/shellintegration"
+	"myT-x/internal/workerutil"
+)
+
+// markerBufferSize bounds markers buffered for a waiting command. Markers
+// beyond it are dropped; a waiting command needs only the first few.
+const markerBufferSize = 16
+
+// Deps holds external dependencies injected at construction time.
+// All function fields except Emitter must be non-nil.
+type Deps struct {
+	// Emitter sends runtime events to the frontend.
+	// Optional: defaults to a no-op emitter if nil.
+	Emitter apptypes.RuntimeEventEmitter
+
+	// CheckPaneAlive returns nil if the pane exists.
+	CheckPaneAlive func(paneID string) error
+
+	// SendCommand types command into the pane followed by Enter.
+	SendCommand func(paneID, command string) error
+
+	// SendKey sends one send-keys key name (e.g. "C-c") to the pane.
+	SendKey func(paneID, key string) error
+
+	// NewContext creates a cancellable context for a queue worker.
+	NewContext func() (context.Context, context.CancelFunc)
+
+	// LaunchWorker starts a background goroutine with panic recovery.
+	LaunchWorker func(name string, ctx context.Context, fn func(ctx context.Context), opts workerutil.RecoveryOptions)
+
+	// BaseRecoveryOptions returns the default RecoveryOptions for workers.
+	BaseRecoveryOptions func() workerutil.RecoveryOptions
+}
+
+type item struct {
+	status  ItemStatus
+	options Options
+}
+
+// paneQueue is the queue of one pane. Protected by Service.mu.
+type paneQueue struct {
+	items   []*item
+	running bool
+	// token identifies the current worker; stale workers compare and exit.
+	token  uint64
+	cancel context.CancelFunc
+	// markers receives shell integration markers while a command is running.
+	markers     chan shellintegration.Marker
+	integration bool
+}
+
+// Service manages per-pane command queues.
+//
+// Thread-safety is managed internally via mu. No external locking is required.
+type Service struct {
+	deps   Deps
+	mu     sync.Mutex
+	queues map[string]*paneQueue
+	nextID uint64
+}
+
+// NewService creates a command queue service.
+// Panics if any required function field in deps is nil.
+func NewService(deps Deps) *Service {
+	if deps.CheckPaneAlive == nil || deps.SendCommand == nil || deps.SendKey == nil || deps.NewContext == nil ||
+		deps.LaunchWorker == nil || deps.BaseRecoveryOptions == nil {
+		panic("cmdqueue.NewService: required function fields in Deps must be non-nil " +
+			"(CheckPaneAlive, SendCommand, SendKey, NewContext, LaunchWorker, BaseRecoveryOptions)")
+	}
+	if deps.Emitter == nil {
+		deps.Emitter = apptypes.NoopEmitter{}
+	}
+	return &Service{
+		deps:   deps,
+		queues: map[string]*paneQueue{},
+	}
+}
+
+// Enqueue appends commands to the pane's queue and starts the queue worker
+// if it is idle.
+func (s *Service) Enqueue(paneID string, commands []string, options Options) (QueueStatus, error) {
+	paneID = strings.TrimSpace(paneID)
+	if paneID == "" {
+		return QueueStatus{}, errors.New("pane id is required")
+	}
+	commands, err := normalizeCommands(commands)
+	if err != nil {
+		return QueueStatus{}, err
+	}
+	if err := options.Validate(); err != nil {
+		return QueueStatus{}, err
+	}
+	options.AfterPaneID = strings.TrimSpace(options.AfterPaneID)
+	if options.AfterPaneID == paneID {
+		return QueueStatus{}, errors.New("a batch cannot wait for its own pane")
+	}
+	if err := s.deps.CheckPaneAlive(paneID); err != nil {
+		return QueueStatus{}, err
+	}
+	if options.AfterPaneID != "" {
+		if err := s.deps.CheckPaneAlive(options.AfterPaneID); err != nil {
+			return QueueStatus{}, fmt.Errorf("after pane: %w", err)
+		}
+	}
+
+	initial := ItemPending
+	if options.AfterPaneID != "" {
+		initial = ItemWaiting
+	}
+	s.mu.Lock()
+	q := s.queues[paneID]
+	if q == nil {
+		q = &paneQueue{}
+		s.queues[paneID] = q
+	}
+	for _, command := range commands {
+		s.nextID++
+		q.items = append(q.items, &item{
+			status: ItemStatus{
+				ID:          strconv.FormatUint(s.nextID, 10),
+				Command:     command,
+				State:       initial,
+				AfterPaneID: options.AfterPaneID,
+			},
+			options: options,
+		})
+	}
+	ctx, token := s.startWorkerLocked(q)
+	status := q.statusLocked(paneID)
+	s.mu.Unlock()
+
+	if ctx != nil {
+		s.launchWorker(paneID, token, ctx)
+	}
+	slog.Debug("[DEBUG-CMDQUEUE] enqueued commands",
+		"paneID", paneID, "count", len(commands), "afterPaneID", options.AfterPaneID)
+	s.emitUpdated(status)
+	return status, nil
+}
+
+// Status returns the queue of a pane. A pane without a queue yields an
+// empty, idle status.
+func (s *Service) Status(paneID string) QueueStatus {
+	paneID = strings.TrimSpace(paneID)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	q := s.queues[paneID]
+	if q == nil {
+		return QueueStatus{PaneID: paneID, Items: []ItemStatus{}}
+	}
+	return q.statusLocked(paneID)
+}
+
+// Cancel cancels pending commands and stops waiting for the running one.
+// A command already sent to the shell keeps running there.
+func (s *Service) Cancel(paneID string) error {
+	paneID = strings.TrimSpace(paneID)
+	if paneID == "" {
+		return errors.New("pane id is required")
+	}
+
+	s.mu.Lock()
+	q := s.queues[paneID]
+	if q == nil {
+		s.mu.Unlock()
+		return fmt.Errorf("no command queue for pane %s", paneID)
+	}
+	for _, it := range q.items {
+		switch it.status.State {
+		case ItemPending, ItemWaiting:
+			it.status.State = ItemCancelled
+		case ItemRunning:
+			it.status.State = ItemCancelled
+			it.status.Error = "cancelled while running; the command may still be executing"
+		}
+	}
+	cancel := q.cancel
+	q.cancel = nil
+	q.running = false
+	q.markers = nil
+	q.token++
+	q.trimFinishedLocked()
+	status := q.statusLocked(paneID)
+	s.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	slog.Info("[CMDQUEUE] cancelled", "paneID", paneID)
+	s.emitUpdated(status)
+	return nil
+}
+
+// HandleShellMarkers forwards shell integration markers to the command that
+// is waiting in the pane's queue, if any, and resolves batches of other panes
+// that wait for a command in this pane.
+func (s *Service) HandleShellMarkers(paneID string, markers []shellintegration.Marker) {
+	for _, marker := range markers {
+		if marker.Kind == shellintegration.MarkerCommandFinished && marker.HasExitCode {
+			s.resolveWaiting(paneID, marker.ExitCode)
+		}
+	}
+
+	s.mu.Lock()
+	q := s.queues[paneID]
+	if q == nil {
+		s.mu.Unlock()
+		return
+	}
+	q.integration = true
+	ch := q.markers
+	s.mu.Unlock()
+	if ch == nil {
+		return
+	}
+	for _, marker := range markers {
+		select {
+		case ch <- marker:
+		default:
+			slog.Debug("[DEBUG-CMDQUEUE] marker buffer full, dropping marker", "paneID", paneID, "kind", marker.Kind)
+		}
+	}
+}
+
+// Forget drops idle queues of panes that no longer exist and cancels
+// batches that wait for them.
+func (s *Service) Forget(paneIDs []string) {
+	s.mu.Lock()
+	removed := make(map[string]struct{}, len(paneIDs))
+	for _, paneID := range paneIDs {
+		removed[paneID] = struct{}{}
+		if q := s.queues[paneID]; q != nil && !q.running {
+			delete(s.queues, paneID)
+		}
+	}
+	var statuses []QueueStatus
+	for paneID, q := range s.queues {
+		changed := false
+		for _, it := range q.items {
+			if it.status.State != ItemWaiting {
+				continue
+			}
+			if _, gone := removed[it.status.AfterPaneID]; gone {
+				it.status.State = ItemCancelled
+				it.status.Error = fmt.Sprintf("cancelled because pane %s was closed", it.status.AfterPaneID)
+				changed = true
+			}
+		}
+		if changed {
+			q.trimFinishedLocked()
+			statuses = append(statuses, q.statusLocked(paneID))
+		}
+	}
+	s.mu.Unlock()
+
+	for _, status := range statuses {
+		s.emitUpdated(status)
+	}
+}
+
+// resolveWaiting releases (exitCode 0) or cancels every batch that waits for
+// sourcePaneID, starting idle workers for released batches.
+func (s *Service) resolveWaiting(sourcePaneID string, exitCode int) {
+	type launch struct {
+		paneID string
+		token  uint64
+		ctx    context.Context
+	}
+	var (
+		statuses []QueueStatus
+		launches []launch
+	)
+	s.mu.Lock()
+	for paneID, q := range s.queues {
+		changed := false
+		for _, it := range q.items {
+			if it.status.State != ItemWaiting || it.status.AfterPaneID != sourcePaneID {
+				continue
+			}
+			changed = true
+			if exitCode == 0 {
+				it.status.State = ItemPending
+				continue
+			}
+			it.status.State = ItemCancelled
+			it.status.Error = fmt.Sprintf("cancelled because a command in pane %s exited with %d", sourcePaneID, exitCode)
+		}
+		if !changed {
+			continue
+		}
+		q.trimFinishedLocked()
+		if ctx, token := s.startWorkerLocked(q); ctx != nil {
+			launches = append(launches, launch{paneID: paneID, token: token, ctx: ctx})
+		}
+		statuses = append(statuses, q.statusLocked(paneID))
+	}
+	s.mu.Unlock()
+
+	for _, l := range launches {
+		s.launchWorker(l.paneID, l.token, l.ctx)
+	}
+	for _, status := range statuses {
+		s.emitUpdated(status)
+	}
+	if len(statuses) > 0 {
+		slog.Debug("[DEBUG-CMDQUEUE] resolved waiting batches",
+			"sourcePaneID", sourcePaneID, "exitCode", exitCode, "queues", len(statuses))
+	}
+}
+
+// startWorkerLocked marks q as running and returns the worker context and
+// token when q is idle and its next item is runnable. Otherwise it returns
+// a nil context.
+func (s *Service) startWorkerLocked(q *paneQueue) (context.Context, uint64) {
+	if q.running || q.nextPendingLocked() == nil {
+		return nil, 0
+	}
+	ctx, cancel := s.deps.NewContext()
+	q.running = true
+	q.token++
+	q.cancel = cancel
+	return ctx, q.token
+}
+
+func (s *Service) launchWorker(paneID string, token uint64, ctx context.Context) {
+	recoveryOpts := s.deps.BaseRecoveryOptions()
+	recoveryOpts.MaxRetries = 1 // No retry on panic; fail the queue instead.
+	origOnFatal := recoveryOpts.OnFatal
+	recoveryOpts.OnFatal = func(worker string, maxRetries int) {
+		s.failQueue(paneID, token, "internal panic")
+		if origOnFatal != nil {
+			origOnFatal(worker, maxRetries)
+		}
+	}
+	s.deps.LaunchWorker("cmdqueue-"+paneID, ctx, func(ctx context.Context) {
+		s.runQueue(ctx, paneID, token)
+	}, recoveryOpts)
+}
+
+// runQueue is the worker body: it runs pending items in order until the
+// queue is empty, cancelled, or stopped by a failure.
+func (s *Service) runQueue(ctx context.Context, paneID string, token uint64) {
+	for {
+		s.mu.Lock()
+		q := s.queues[paneID]
+		if q == nil || q.token != token {
+			s.mu.Unlock()
+			return
+		}
+		next := q.nextPendingLocked()
+		if next == nil {
+			q.running = false
+			q.cancel = nil
+			q.token++
+			status := q.statusLocked(paneID)
+			s.mu.Unlock()
+			s.emitUpdated(status)
+			return
+		}
+		next.status.State = ItemRunning
+		markers := make(chan shellintegration.Marker, markerBufferSize)
+		q.markers = markers
+		itemID := next.status.ID
+		command := next.status.Command
+		options := next.options
+		status := q.statusLocked(paneID)
+		s.mu.Unlock()
+		s.emitUpdated(status)
+
+		result := s.runCommand(ctx, paneID, itemID, command, options, markers)
+
+		s.mu.Lock()
+		q = s.queues[paneID]
+		if q == nil || q.token != token {
+			// Cancelled (or replaced) while the command was running.
+			s.mu.Unlock()
+			return
+		}
+		q.markers = nil
+		next.status.State = result.State
+		next.status.ExitCode = result.ExitCode
+		next.status.Error = result.Error
+		next.status.Signals = result.Signals
+		if stopsQueue(result, options) {
+			reason := fmt.Sprintf("cancelled because command %q %s", command, result.State)
+			for _, it := range q.items {
+				if it.status.State == ItemPending || it.status.State == ItemWaiting {
+					it.status.State = ItemCancelled
+					it.status.Error = reason
+				}
+			}
+		}
+		q.trimFinishedLocked()
+		s.mu.Unlock()
+
+		slog.Debug("[DEBUG-CMDQUEUE] command finished",
+			"paneID", paneID, "state", result.State, "exitCode", result.ExitCode)
+	}
+}
+
+// runCommand sends one command and waits for it to finish.
+func (s *Service) runCommand(
+	ctx context.Context,
+	paneID string,
+	itemID string,
+	command string,
+	options Options,
+	markers <-chan shellintegration.Marker,
+) ItemStatus {
+	if err := s.deps.SendCommand(paneID, command); err != nil {
+		return ItemStatus{State: ItemFailed, Error: fmt.Sprintf("send failed: %v", err)}
+	}
+
+	timer := time.NewTimer(options.Timeout())
+	defer timer.Stop()
+	executed := false
+	for {
+		select {
+		case <-ctx.Done():
+			return ItemStatus{State: ItemCancelled}
+		case <-timer.C:
+			if options.KillPolicy != nil && executed {
+				return s.killCommand(ctx, paneID, itemID, command, options, markers)
+			}
+			if options.RequirePrompt {
+				return ItemStatus{State: ItemTimedOut, Error: "no shell prompt within timeout"}
+			}
+			return ItemStatus{State: ItemTimedOut}
+		case marker := <-markers:
+			if result, done := commandResult(marker, executed); done {
+				return result
+			}
+			if marker.Kind == shellintegration.MarkerCommandExecuted {
+				executed = true
+			}
+		}
+	}
+}
+
+// killCommand applies options.KillPolicy to a command that is still running
+// at its timeout: it sends each signal and waits the grace period for the
+// shell to report completion before sending the next.
+func (s *Service) killCommand(
+	ctx context.Context,
+	paneID string,
+	itemID string,
+	command string,
+	options Options,
+	markers <-chan shellintegration.Marker,
+) ItemStatus {
+	policy := *options.KillPolicy
+	report := TimeoutReport{
+		PaneID:    paneID,
+		ItemID:    itemID,
+		Command:   command,
+		TimeoutMs: options.Timeout().Milliseconds(),
+		Signals:   []string{},
+	}
+	result := ItemStatus{
+		State: ItemFailed,
+		Error: "still running after kill signals; remaining commands cancelled",
+	}
+
+	grace := time.NewTimer(policy.Grace())
+	defer grace.Stop()
+signals:
+	for _, signal := range policy.SignalsOrDefault() {
+		if err := s.deps.SendKey(paneID, signalKeys[signal]); err != nil {
+			slog.Warn("[CMDQUEUE] failed to send kill signal", "paneID", paneID, "signal", signal, "error", err)
+			continue
+		}
+		report.Signals = append(report.Signals, signal)
+		grace.Reset(policy.Grace())
+		for {
+			select {
+			case <-ctx.Done():
+				return ItemStatus{State: ItemCancelled, Signals: report.Signals}
+			case <-grace.C:
+				continue signals
+			case marker := <-markers:
+				// The command is known to be executing, so any prompt ends it.
+				finished, done := commandResult(marker, true)
+				if !done {
+					continue
+				}
+				report.Stopped = true
+				result = ItemStatus{State: ItemKilled, ExitCode: finished.ExitCode}
+				break signals
+			}
+		}
+	}
+	result.Signals = report.Signals
+
+	slog.Info("[CMDQUEUE] command timed out",
+		"paneID", paneID, "itemID", itemID, "signals", report.Signals, "stopped", report.Stopped)
+	s.deps.Emitter.Emit(TimeoutEvent, report)
+	return result
+}
+
+// commandResult maps a completion marker to the command's result. done is
+// false for markers that do not end the command.
+func commandResult(marker shellintegration.Marker, executed bool) (ItemStatus, bool) {
+	switch marker.Kind {
+	case shellintegration.MarkerCommandFinished:
+		if !marker.HasExitCode {
+			return ItemStatus{State: ItemSucceeded}, true
+		}
+		exitCode := marker.ExitCode
+		if exitCode != 0 {
+			return ItemStatus{State: ItemFailed, ExitCode: &exitCode}, true
+		}
+		return ItemStatus{State: ItemSucceeded, ExitCode: &exitCode}, true
+	case shellintegration.MarkerPromptStart:
+		// A prompt without a preceding command-executed marker may
+		// belong to output flushed before the command was sent.
+		if executed {
+			return ItemStatus{State: ItemSucceeded}, true
+		}
+	}
+	return ItemStatus{}, false
+}
+
+// stopsQueue reports whether the remaining items must be cancelled after r.
+func stopsQueue(r ItemStatus, options Options) bool {
+	switch r.State {
+	case ItemFailed:
+		// Failures without an exit status always stop: either the send
+		// failed and the pane is likely gone, or a killed command is still
+		// holding the shell.
+		return options.StopOnError || r.ExitCode == nil
+	case ItemKilled:
+		return options.StopOnError
+	case ItemTimedOut:
+		return options.RequirePrompt && options.StopOnError
+	default:
+		return false
+	}
+}
+
+func (s *Service) failQueue(paneID string, token uint64, reason string) {
+	s.mu.Lock()
+	q := s.queues[paneID]
+	if q == nil || q.token != token {
+		s.mu.Unlock()
+		return
+	}
+	for _, it := range q.items {
+		if !it.status.State.Finished() {
+			it.status.State = ItemFailed
+			it.status.Error = reason
+		}
+	}
+	q.running = false
+	q.cancel = nil
+	q.markers = nil
+	q.token++
+	status := q.statusLocked(paneID)
+	s.mu.Unlock()
+	s.emitUpdated(status)
+}
+
+func (s *Service) emitUpdated(status QueueStatus) {
+	s.deps.Emitter.Emit(UpdatedEvent, status)
+}
+
+// nextPendingLocked returns the first unfinished item if it can run now.
+// A waiting item blocks the items behind it to keep the queue order.
+func (q *paneQueue) nextPendingLocked() *item {
+	for _, it := range q.items {
+		switch it.status.State {
+		case ItemPending:
+			return it
+		case ItemWaiting, ItemRunning:
+			return nil
+		}
+	}
+	return nil
+}
+
+// trimFinishedLocked keeps at most maxFinishedItems finished items.
+func (q *paneQueue) trimFinishedLocked() {
+	finished := 0
+	for _, it := range q.items {
+		if it.status.State.Finished() {
+			finished++
+		}
+	}
+	if finished <= maxFinishedItems {
+		return
+	}
+	drop := finished - maxFinishedItems
+	kept := q.items[:0]
+	for _, it := range q.items {
+		if drop > 0 && it.status.State.Finished() {
+			drop--
+			continue
+		}
+		kept = append(kept, it)
+	}
+	q.items = kept
+}
+
+func (q *paneQueue) statusLocked(paneID string) QueueStatus {
+	items := make([]ItemStatus, 0, len(q.items))
+	for _, it := range q.items {
+		status := it.status
+		if status.ExitCode != nil {
+			exitCode := *status.ExitCode
+			status.ExitCode = &exitCode
+		}
+		status.Signals = slices.Clone(status.Signals)
+		items = append(items, status)
+	}
+	return QueueStatus{
+		PaneID:           paneID,
+		Running:          q.running,
+		ShellIntegration: q.integration,
+		Items:            items,
+	}
+}
@@ -0,0 +1,431 @@
+package cmdqueue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"myT-x/internal/apptypes"
+	"myT-x/internal/shellintegration"
+	"myT-x/internal/workerutil"
+)
+
+// fakeShell records sent commands and lets tests answer with markers.
+type fakeShell struct {
+	mu      sync.Mutex
+	sent    []string
+	sendErr error
+	sentCh  chan string
+	keys    []string
+	keyCh   chan string
+}
+
+func newFakeShell() *fakeShell {
+	return &fakeShell{sentCh: make(chan string, 16), keyCh: make(chan string, 16)}
+}
+
+func (f *fakeShell) sendKey(_ string, key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.keys = append(f.keys, key)
+	f.keyCh <- key
+	return nil
+}
+
+func (f *fakeShell) waitKey(t *testing.T) string {
+	t.Helper()
+	select {
+	case key := <-f.keyCh:
+		return key
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for a key to be sent")
+		return ""
+	}
+}
+
+func (f *fakeShell) send(_ string, command string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.sendErr != nil {
+		return f.sendErr
+	}
+	f.sent = append(f.sent, command)
+	f.sentCh <- command
+	return nil
+}
+
+func (f *fakeShell) waitSent(t *testing.T) string {
+	t.Helper()
+	select {
+	case command := <-f.sentCh:
+		return command
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for a command to be sent")
+		return ""
+	}
+}
+
+func testDeps(shell *fakeShell) Deps {
+	return Deps{
+		CheckPaneAlive: func(paneID string) error {
+			if paneID == "%1" || paneID == "%2" {
+				return nil
+			}
+			return fmt.Errorf("pane %s does not exist", paneID)
+		},
+		SendCommand: shell.send,
+		SendKey:     shell.sendKey,
+		NewContext: func() (context.Context, context.CancelFunc) {
+			return context.WithCancel(context.Background())
+		},
+		LaunchWorker: func(name string, ctx context.Context, fn func(ctx context.Context), opts workerutil.RecoveryOptions) {
+			go fn(ctx)
+		},
+		BaseRecoveryOptions: func() workerutil.RecoveryOptions {
+			return workerutil.RecoveryOptions{MaxRetries: 1}
+		},
+	}
+}
+
+func waitForQueue(t *testing.T, service *Service, paneID string, done func(QueueStatus) bool) QueueStatus {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		status := service.Status(paneID)
+		if done(status) {
+			return status
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("queue did not reach expected state: %+v", status)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func finished(marker shellintegration.Marker) []shellintegration.Marker {
+	return []shellintegration.Marker{{Kind: shellintegration.MarkerCommandExecuted}, marker}
+}
+
+func TestNewServicePanicsOnMissingDeps(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic for missing deps")
+		}
+	}()
+	NewService(Deps{})
+}
+
+func TestEnqueueRunsCommandsSequentially(t *testing.T) {
+	shell := newFakeShell()
+	service := NewService(testDeps(shell))
+
+	if _, err := service.Enqueue("%1", []string{"go build ./...", "go test ./...\r\n"}, Options{}); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+	if got := shell.waitSent(t); got != "go build ./..." {
+		t.Fatalf("first command = %q", got)
+	}
+	// The second command must wait for the first to finish.
+	select {
+	case command := <-shell.sentCh:
+		t.Fatalf("second command %q sent before the first finished", command)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	service.HandleShellMarkers("%1", finished(shellintegration.Marker{Kind: shellintegration.MarkerCommandFinished, HasExitCode: true}))
+	if got := shell.waitSent(t); got != "go test ./..." {
+		t.Fatalf("second command = %q", got)
+	}
+	service.HandleShellMarkers("%1", finished(shellintegration.Marker{Kind: shellintegration.MarkerCommandFinished, ExitCode: 1, HasExitCode: true}))
+
+	status := waitForQueue(t, service, "%1", func(s QueueStatus) bool { return !s.Running })
+	if !status.ShellIntegration || len(status.Items) != 2 {
+		t.Fatalf("status = %+v, want two items with shell integration", status)
+	}
+	if status.Items[0].State != ItemSucceeded || status.Items[0].ExitCode == nil || *status.Items[0].ExitCode != 0 {
+		t.Fatalf("item 0 = %+v, want succeeded with exit 0", status.Items[0])
+	}
+	if status.Items[1].State != ItemFailed || status.Items[1].ExitCode == nil || *status.Items[1].ExitCode != 1 {
+		t.Fatalf("item 1 = %+v, want failed with exit 1", status.Items[1])
+	}
+}
+
+func TestStopOnErrorCancelsRemainingCommands(t *testing.T) {
+	shell := newFakeShell()
+	service := NewService(testDeps(shell))
+
+	if _, err := service.Enqueue("%1", []string{"false", "deploy"}, Options{StopOnError: true}); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+	shell.waitSent(t)
+	service.HandleShellMarkers("%1", finished(shellintegration.Marker{Kind: shellintegration.MarkerCommandFinished, ExitCode: 2, HasExitCode: true}))
+
+	status := waitForQueue(t, service, "%1", func(s QueueStatus) bool { return !s.Running })
+	if status.Items[1].State != ItemCancelled || !strings.Contains(status.Items[1].Error, "false") {
+		t.Fatalf("item 1 = %+v, want cancelled because of the failed command", status.Items[1])
+	}
+	if len(shell.sent) != 1 {
+		t.Fatalf("sent = %v, want only the failing command", shell.sent)
+	}
+}
+
+func TestPromptWithoutExecutedMarkerIsIgnored(t *testing.T) {
+	shell := newFakeShell()
+	service := NewService(testDeps(shell))
+
+	if _, err := service.Enqueue("%1", []string{"sleep 1"}, Options{TimeoutMs: 200}); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+	shell.waitSent(t)
+	// A stale prompt from before the command was sent must not complete it.
+	service.HandleShellMarkers("%1", []shellintegration.Marker{{Kind: shellintegration.MarkerPromptStart}})
+	status := waitForQueue(t, service, "%1", func(s QueueStatus) bool { return !s.Running })
+	if status.Items[0].State != ItemTimedOut || status.Items[0].Error != "" {
+		t.Fatalf("item = %+v, want timed out", status.Items[0])
+	}
+}
+
+func TestTimeoutPacesQueueWithoutShellIntegration(t *testing.T) {
+	shell := newFakeShell()
+	service := NewService(testDeps(shell))
+
+	if _, err := service.Enqueue("%1", []string{"a", "b"}, Options{TimeoutMs: 100}); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+	status := waitForQueue(t, service, "%1", func(s QueueStatus) bool { return !s.Running })
+	if status.ShellIntegration || status.Items[0].State != ItemTimedOut || status.Items[1].State != ItemTimedOut {
+		t.Fatalf("status = %+v, want both commands paced by timeout", status)
+	}
+
+	// RequirePrompt with StopOnError turns the timeout into a stop.
+	if _, err := service.Enqueue("%1", []string{"c", "d"}, Options{TimeoutMs: 100, RequirePrompt: true, StopOnError: true}); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+	status = waitForQueue(t, service, "%1", func(s QueueStatus) bool { return !s.Running })
+	if got := status.Items[len(status.Items)-1]; got.Command != "d" || got.State != ItemCancelled {
+		t.Fatalf("last item = %+v, want d cancelled", got)
+	}
+}
+
+func TestKillPolicyInterruptsHungCommand(t *testing.T) {
+	shell := newFakeShell()
+	deps := testDeps(shell)
+	reports := make(chan TimeoutReport, 1)
+	deps.Emitter = apptypes.EventEmitterFunc(func(name string, payload any) {
+		if name == TimeoutEvent {
+			reports <- payload.(TimeoutReport)
+		}
+	})
+	service := NewService(deps)
+
+	options := Options{TimeoutMs: 100, KillPolicy: &KillPolicy{Signals: []string{SignalInterrupt, SignalQuit}, GraceMs: 100}}
+	if _, err := service.Enqueue("%1", []string{"agent --watch", "next"}, options); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+	shell.waitSent(t)
+	service.HandleShellMarkers("%1", []shellintegration.Marker{{Kind: shellintegration.MarkerCommandExecuted}})
+
+	// Ctrl-C is ignored; the quit signal stops the command.
+	if got := shell.waitKey(t); got != "C-c" {
+		t.Fatalf("first key = %q, want C-c", got)
+	}
+	if got := shell.waitKey(t); got != "C-\\" {
+		t.Fatalf("second key = %q, want C-\\", got)
+	}
+	service.HandleShellMarkers("%1", []shellintegration.Marker{{Kind: shellintegration.MarkerCommandFinished, ExitCode: 131, HasExitCode: true}})
+
+	report := <-reports
+	if !report.Stopped || report.Command != "agent --watch" || len(report.Signals) != 2 {
+		t.Fatalf("report = %+v, want stopped after two signals", report)
+	}
+	if got := shell.waitSent(t); got != "next" {
+		t.Fatalf("command after kill = %q, want next", got)
+	}
+	status := service.Status("%1")
+	killed := status.Items[0]
+	if killed.State != ItemKilled || killed.ExitCode == nil || *killed.ExitCode != 131 || len(killed.Signals) != 2 {
+		t.Fatalf("item 0 = %+v, want killed with exit 131", killed)
+	}
+}
+
+func TestKillPolicyStopsQueueWhenCommandKeepsRunning(t *testing.T) {
+	shell := newFakeShell()
+	service := NewService(testDeps(shell))
+
+	options := Options{TimeoutMs: 100, KillPolicy: &KillPolicy{GraceMs: 100}}
+	if _, err := service.Enqueue("%1", []string{"hang", "next"}, options); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+	shell.waitSent(t)
+	service.HandleShellMarkers("%1", []shellintegration.Marker{{Kind: shellintegration.MarkerCommandExecuted}})
+
+	status := waitForQueue(t, service, "%1", func(s QueueStatus) bool { return !s.Running })
+	if got := status.Items[0]; got.State != ItemFailed || got.ExitCode != nil || len(got.Signals) != 1 || got.Signals[0] != SignalInterrupt {
+		t.Fatalf("item 0 = %+v, want failed after interrupt", got)
+	}
+	if status.Items[1].State != ItemCancelled {
+		t.Fatalf("item 1 = %+v, want cancelled while the shell is busy", status.Items[1])
+	}
+}
+
+func TestKillPolicyNeedsShellIntegration(t *testing.T) {
+	shell := newFakeShell()
+	service := NewService(testDeps(shell))
+
+	if _, err := service.Enqueue("%1", []string{"a"}, Options{TimeoutMs: 100, KillPolicy: &KillPolicy{}}); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+	status := waitForQueue(t, service, "%1", func(s QueueStatus) bool { return !s.Running })
+	if status.Items[0].State != ItemTimedOut || len(shell.keys) != 0 {
+		t.Fatalf("status = %+v keys = %v, want plain timeout without signals", status, shell.keys)
+	}
+}
+
+func TestCancelStopsQueue(t *testing.T) {
+	shell := newFakeShell()
+	service := NewService(testDeps(shell))
+
+	if _, err := service.Enqueue("%1", []string{"long", "next"}, Options{}); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+	shell.waitSent(t)
+	if err := service.Cancel("%1"); err != nil {
+		t.Fatalf("Cancel() error = %v", err)
+	}
+	status := service.Status("%1")
+	if status.Running || status.Items[0].State != ItemCancelled || status.Items[1].State != ItemCancelled {
+		t.Fatalf("status = %+v, want idle queue with cancelled items", status)
+	}
+
+	// The queue is usable again after cancel.
+	if _, err := service.Enqueue("%1", []string{"again"}, Options{}); err != nil {
+		t.Fatalf("Enqueue() after cancel error = %v", err)
+	}
+	if got := shell.waitSent(t); got != "again" {
+		t.Fatalf("command after cancel = %q, want again", got)
+	}
+	if err := service.Cancel("%9"); err == nil {
+		t.Fatal("Cancel() of unknown pane error = nil, want error")
+	}
+}
+
+func TestSendFailureStopsQueue(t *testing.T) {
+	shell := newFakeShell()
+	shell.sendErr = errors.New("pane closed")
+	service := NewService(testDeps(shell))
+
+	if _, err := service.Enqueue("%1", []string{"a", "b"}, Options{}); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+	status := waitForQueue(t, service, "%1", func(s QueueStatus) bool { return !s.Running })
+	if status.Items[0].State != ItemFailed || !strings.Contains(status.Items[0].Error, "pane closed") {
+		t.Fatalf("item 0 = %+v, want send failure", status.Items[0])
+	}
+	if status.Items[1].State != ItemCancelled {
+		t.Fatalf("item 1 = %+v, want cancelled", status.Items[1])
+	}
+}
+
+func TestEnqueueValidation(t *testing.T) {
+	service := NewService(testDeps(newFakeShell()))
+	tests := []struct {
+		name     string
+		paneID   string
+		commands []string
+		options  Options
+		wantErr  string
+	}{
+		{name: "missing pane", paneID: " ", commands: []string{"ls"}, wantErr: "pane id is required"},
+		{name: "unknown pane", paneID: "%9", commands: []string{"ls"}, wantErr: "does not exist"},
+		{name: "no commands", paneID: "%1", wantErr: "at least one command"},
+		{name: "empty command", paneID: "%1", commands: []string{"ls", "  "}, wantErr: "command 2 is empty"},
+		{name: "multi-line command", paneID: "%1", commands: []string{"a\nb"}, wantErr: "single line"},
+		{name: "timeout too small", paneID: "%1", commands: []string{"ls"}, options: Options{TimeoutMs: 10}, wantErr: "timeout_ms"},
+		{name: "after own pane", paneID: "%1", commands: []string{"ls"}, options: Options{AfterPaneID: "%1"}, wantErr: "own pane"},
+		{name: "after unknown pane", paneID: "%1", commands: []string{"ls"}, options: Options{AfterPaneID: "%9"}, wantErr: "after pane"},
+		{name: "unknown kill signal", paneID: "%1", commands: []string{"ls"}, options: Options{KillPolicy: &KillPolicy{Signals: []string{"term"}}}, wantErr: "unknown kill signal"},
+		{name: "kill grace too large", paneID: "%1", commands: []string{"ls"}, options: Options{KillPolicy: &KillPolicy{GraceMs: 120000}}, wantErr: "grace_ms"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.Enqueue(tt.paneID, tt.commands, tt.options)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Enqueue() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAfterPaneSuccessReleasesWaitingBatch(t *testing.T) {
+	shell := newFakeShell()
+	service := NewService(testDeps(shell))
+
+	status, err := service.Enqueue("%2", []string{"deploy"}, Options{AfterPaneID: "%1"})
+	if err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+	if status.Running || status.Items[0].State != ItemWaiting || status.Items[0].AfterPaneID != "%1" {
+		t.Fatalf("status = %+v, want an idle queue with a waiting item", status)
+	}
+	// Commands queued behind a waiting batch keep the queue order.
+	if _, err := service.Enqueue("%2", []string{"echo done"}, Options{}); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+	select {
+	case command := <-shell.sentCh:
+		t.Fatalf("command %q sent while the batch was waiting", command)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	service.HandleShellMarkers("%1", finished(shellintegration.Marker{Kind: shellintegration.MarkerCommandFinished, HasExitCode: true}))
+	if got := shell.waitSent(t); got != "deploy" {
+		t.Fatalf("first command = %q, want deploy", got)
+	}
+	service.HandleShellMarkers("%2", finished(shellintegration.Marker{Kind: shellintegration.MarkerCommandFinished, HasExitCode: true}))
+	if got := shell.waitSent(t); got != "echo done" {
+		t.Fatalf("second command = %q, want echo done", got)
+	}
+}
+
+func TestAfterPaneFailureCancelsWaitingBatch(t *testing.T) {
+	shell := newFakeShell()
+	service := NewService(testDeps(shell))
+
+	if _, err := service.Enqueue("%2", []string{"deploy", "notify"}, Options{AfterPaneID: "%1"}); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+	service.HandleShellMarkers("%1", finished(shellintegration.Marker{Kind: shellintegration.MarkerCommandFinished, ExitCode: 2, HasExitCode: true}))
+
+	status := service.Status("%2")
+	if status.Running || len(status.Items) != 2 {
+		t.Fatalf("status = %+v, want two idle items", status)
+	}
+	for _, it := range status.Items {
+		if it.State != ItemCancelled || !strings.Contains(it.Error, "exited with 2") {
+			t.Fatalf("item = %+v, want cancelled by exit 2", it)
+		}
+	}
+	select {
+	case command := <-shell.sentCh:
+		t.Fatalf("command %q sent after upstream failure", command)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestForgetCancelsBatchesWaitingForRemovedPane(t *testing.T) {
+	service := NewService(testDeps(newFakeShell()))
+
+	if _, err := service.Enqueue("%2", []string{"deploy"}, Options{AfterPaneID: "%1"}); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+	service.Forget([]string{"%1"})
+
+	status := service.Status("%2")
+	if len(status.Items) != 1 || status.Items[0].State != ItemCancelled {
+		t.Fatalf("status = %+v, want the waiting item cancelled", status)
+	}
+}
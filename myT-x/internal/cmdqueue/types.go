@@ -0,0 +1,229 @@
+package cmdqueue
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultTimeout is the per-command wait used when Options.TimeoutMs is 0.
+	DefaultTimeout = 60 * time.Second
+	// MinTimeout and MaxTimeout bound Options.TimeoutMs.
+	MinTimeout = 100 * time.Millisecond
+	MaxTimeout = time.Hour
+
+	// MaxCommandsPerEnqueue bounds a single EnqueueCommands call.
+	MaxCommandsPerEnqueue = 100
+
+	// maxFinishedItems is how many finished items are kept per pane for inspection.
+	maxFinishedItems = 50
+
+	// UpdatedEvent carries the QueueStatus of a pane after every change.
+	UpdatedEvent = "command-queue:updated"
+	// TimeoutEvent carries a TimeoutReport after a supervised command hit
+	// its timeout and the kill policy was applied.
+	TimeoutEvent = "command-queue:timeout"
+
+	// DefaultKillGrace is the wait after each kill signal when
+	// KillPolicy.GraceMs is 0.
+	DefaultKillGrace = 2 * time.Second
+	// MaxKillGrace bounds KillPolicy.GraceMs; the lower bound is MinTimeout.
+	MaxKillGrace = time.Minute
+)
+
+// Kill signals, sent to the pane as the control keys a terminal uses for them.
+const (
+	// SignalInterrupt is Ctrl-C (SIGINT, or CTRL_C_EVENT under ConPTY).
+	SignalInterrupt = "interrupt"
+	// SignalQuit is Ctrl-\ (SIGQUIT on Unix shells).
+	SignalQuit = "quit"
+	// SignalEOF is Ctrl-D, which ends programs waiting for input.
+	SignalEOF = "eof"
+)
+
+// signalKeys maps kill signals to send-keys key names.
+var signalKeys = map[string]string{
+	SignalInterrupt: "C-c",
+	SignalQuit:      "C-\\",
+	SignalEOF:       "C-d",
+}
+
+// ItemState is the lifecycle state of a queued command.
+type ItemState string
+
+const (
+	ItemPending ItemState = "pending"
+	// ItemWaiting means the batch waits for a command in Options.AfterPaneID
+	// to finish: success releases it, failure cancels it.
+	ItemWaiting   ItemState = "waiting"
+	ItemRunning   ItemState = "running"
+	ItemSucceeded ItemState = "succeeded"
+	ItemFailed    ItemState = "failed"
+	// ItemTimedOut means no prompt marker arrived within the timeout. Without
+	// shell integration this is the normal outcome and the queue moves on
+	// unless Options.RequirePrompt is set.
+	ItemTimedOut ItemState = "timed_out"
+	// ItemKilled means the command was still running in the foreground at
+	// the timeout and Options.KillPolicy signals were sent to it.
+	ItemKilled    ItemState = "killed"
+	ItemCancelled ItemState = "cancelled"
+)
+
+// Finished reports whether the state is terminal.
+func (s ItemState) Finished() bool {
+	return s != ItemPending && s != ItemWaiting && s != ItemRunning
+}
+
+// Options controls how a batch of queued commands is executed.
+type Options struct {
+	// TimeoutMs is the maximum wait for the shell prompt after each command.
+	// 0 selects DefaultTimeout.
+	TimeoutMs int `json:"timeout_ms,omitempty"`
+	// StopOnError cancels the rest of the queue when a command reports a
+	// non-zero exit status.
+	StopOnError bool `json:"stop_on_error,omitempty"`
+	// RequirePrompt treats a timeout as a failure (and, with StopOnError,
+	// cancels the rest of the queue) instead of moving on.
+	RequirePrompt bool `json:"require_prompt,omitempty"`
+	// AfterPaneID holds the batch until the next command in that pane
+	// finishes with an exit status (reported by shell integration). Exit
+	// code 0 releases the batch; any other code cancels it. Commands queued
+	// behind a waiting batch wait with it.
+	AfterPaneID string `json:"after_pane_id,omitempty"`
+	// KillPolicy enables supervised execution: a command that shell
+	// integration reports as still running at the timeout is sent the
+	// policy's signals. Without shell integration the policy is not applied,
+	// since a running command cannot be told apart from a quiet shell.
+	KillPolicy *KillPolicy `json:"kill_policy,omitempty"`
+}
+
+// KillPolicy controls how a supervised command that exceeds its timeout is
+// stopped.
+type KillPolicy struct {
+	// Signals are sent in order until the shell reports that the command
+	// finished. Empty selects SignalInterrupt.
+	Signals []string `json:"signals,omitempty"`
+	// GraceMs is the wait for the shell after each signal. 0 selects
+	// DefaultKillGrace.
+	GraceMs int `json:"grace_ms,omitempty"`
+}
+
+// SignalsOrDefault returns the signals to send.
+func (p KillPolicy) SignalsOrDefault() []string {
+	if len(p.Signals) == 0 {
+		return []string{SignalInterrupt}
+	}
+	return p.Signals
+}
+
+// Grace returns the effective wait after each signal.
+func (p KillPolicy) Grace() time.Duration {
+	if p.GraceMs == 0 {
+		return DefaultKillGrace
+	}
+	return time.Duration(p.GraceMs) * time.Millisecond
+}
+
+// Validate checks signal names and grace bounds.
+func (p KillPolicy) Validate() error {
+	for _, signal := range p.Signals {
+		if _, ok := signalKeys[signal]; !ok {
+			return fmt.Errorf("unknown kill signal %q (want %s, %s or %s)", signal, SignalInterrupt, SignalQuit, SignalEOF)
+		}
+	}
+	if p.GraceMs == 0 {
+		return nil
+	}
+	grace := p.Grace()
+	if p.GraceMs < 0 || grace < MinTimeout || grace > MaxKillGrace {
+		return fmt.Errorf("grace_ms must be between %d and %d", MinTimeout.Milliseconds(), MaxKillGrace.Milliseconds())
+	}
+	return nil
+}
+
+// Timeout returns the effective per-command timeout.
+func (o Options) Timeout() time.Duration {
+	if o.TimeoutMs == 0 {
+		return DefaultTimeout
+	}
+	return time.Duration(o.TimeoutMs) * time.Millisecond
+}
+
+// Validate checks option bounds.
+func (o Options) Validate() error {
+	if o.KillPolicy != nil {
+		if err := o.KillPolicy.Validate(); err != nil {
+			return fmt.Errorf("kill_policy: %w", err)
+		}
+	}
+	if o.TimeoutMs == 0 {
+		return nil
+	}
+	timeout := o.Timeout()
+	if o.TimeoutMs < 0 || timeout < MinTimeout || timeout > MaxTimeout {
+		return fmt.Errorf("timeout_ms must be between %d and %d", MinTimeout.Milliseconds(), MaxTimeout.Milliseconds())
+	}
+	return nil
+}
+
+// ItemStatus is the frontend-safe representation of a queued command.
+type ItemStatus struct {
+	ID      string    `json:"id"`
+	Command string    `json:"command"`
+	State   ItemState `json:"state"`
+	// AfterPaneID is the pane this command's batch waits for, if any.
+	AfterPaneID string `json:"after_pane_id,omitempty"`
+	// ExitCode is reported by shell integration; nil when unknown.
+	ExitCode *int   `json:"exit_code,omitempty"`
+	Error    string `json:"error,omitempty"`
+	// Signals lists the kill signals sent to a command that timed out.
+	Signals []string `json:"signals,omitempty"`
+}
+
+// TimeoutReport describes a supervised command that exceeded its timeout.
+type TimeoutReport struct {
+	PaneID    string `json:"pane_id"`
+	ItemID    string `json:"item_id"`
+	Command   string `json:"command"`
+	TimeoutMs int64  `json:"timeout_ms"`
+	// Signals lists the kill signals sent, in order.
+	Signals []string `json:"signals"`
+	// Stopped is true when the shell reported the command finished after a
+	// signal. When false the command is still running and the rest of the
+	// queue was cancelled.
+	Stopped bool `json:"stopped"`
+}
+
+// QueueStatus is the frontend-safe representation of a pane's queue.
+type QueueStatus struct {
+	PaneID  string `json:"pane_id"`
+	Running bool   `json:"running"`
+	// ShellIntegration is true once shell integration markers were seen
+	// from the pane while it had a queue.
+	ShellIntegration bool         `json:"shell_integration"`
+	Items            []ItemStatus `json:"items"`
+}
+
+// normalizeCommands trims line endings and rejects empty or multi-line commands.
+func normalizeCommands(commands []string) ([]string, error) {
+	if len(commands) == 0 {
+		return nil, errors.New("at least one command is required")
+	}
+	if len(commands) > MaxCommandsPerEnqueue {
+		return nil, fmt.Errorf("at most %d commands can be queued at once", MaxCommandsPerEnqueue)
+	}
+	normalized := make([]string, 0, len(commands))
+	for i, command := range commands {
+		command = strings.TrimRight(command, "\r\n")
+		if strings.TrimSpace(command) == "" {
+			return nil, fmt.Errorf("command %d is empty", i+1)
+		}
+		if strings.ContainsAny(command, "\r\n") {
+			return nil, fmt.Errorf("command %d must be a single line", i+1)
+		}
+		normalized = append(normalized, command)
+	}
+	return normalized, nil
+}
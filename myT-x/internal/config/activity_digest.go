@@ -0,0 +1,27 @@
+package config
+
+import (
+	"log/slog"
+	"math"
+	"path/filepath"
+	"strings"
+)
+
+// sanitizeActivityDigest normalizes activity_digest in place. A relative dir
+// is dropped so digests never land next to the process working directory.
+func sanitizeActivityDigest(cfg *Config) {
+	digest := cfg.ActivityDigest
+	if digest == nil {
+		return
+	}
+	digest.Dir = strings.TrimSpace(digest.Dir)
+	if digest.Dir != "" && !filepath.IsAbs(digest.Dir) {
+		slog.Warn("[WARN-CONFIG] activity_digest dir must be an absolute path, using the default", "dir", digest.Dir)
+		digest.Dir = ""
+	}
+	if math.IsNaN(digest.CostPerActiveHour) || math.IsInf(digest.CostPerActiveHour, 0) || digest.CostPerActiveHour < 0 {
+		slog.Warn("[WARN-CONFIG] activity_digest cost_per_active_hour must be a non-negative number, ignoring",
+			"costPerActiveHour", digest.CostPerActiveHour)
+		digest.CostPerActiveHour = 0
+	}
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"math"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestActivityDigestConfigFieldCountGuard(t *testing.T) {
+	if got := reflect.TypeFor[ActivityDigestConfig]().NumField(); got != 2 {
+		t.Fatalf("ActivityDigestConfig field count = %d, want 2; update sanitizeActivityDigest, Clone, and this assertion", got)
+	}
+}
+
+func TestSanitizeActivityDigest(t *testing.T) {
+	absDir, err := filepath.Abs("digests")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		in   ActivityDigestConfig
+		want ActivityDigestConfig
+	}{
+		{name: "valid", in: ActivityDigestConfig{Dir: " " + absDir + " ", CostPerActiveHour: 2.5}, want: ActivityDigestConfig{Dir: absDir, CostPerActiveHour: 2.5}},
+		{name: "relative dir", in: ActivityDigestConfig{Dir: "digests"}, want: ActivityDigestConfig{}},
+		{name: "negative cost", in: ActivityDigestConfig{CostPerActiveHour: -1}, want: ActivityDigestConfig{}},
+		{name: "NaN cost", in: ActivityDigestConfig{CostPerActiveHour: math.NaN()}, want: ActivityDigestConfig{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			digest := tt.in
+			cfg := Config{ActivityDigest: &digest}
+			sanitizeActivityDigest(&cfg)
+			if *cfg.ActivityDigest != tt.want {
+				t.Fatalf("ActivityDigest = %+v, want %+v", *cfg.ActivityDigest, tt.want)
+			}
+		})
+	}
+
+	cfg := Config{}
+	sanitizeActivityDigest(&cfg)
+	if cfg.ActivityDigest != nil {
+		t.Fatalf("ActivityDigest = %+v, want nil", cfg.ActivityDigest)
+	}
+}
+
+func TestCloneActivityDigest(t *testing.T) {
+	src := Config{ActivityDigest: &ActivityDigestConfig{CostPerActiveHour: 1}}
+	cloned := Clone(src)
+	cloned.ActivityDigest.CostPerActiveHour = 3
+	if src.ActivityDigest.CostPerActiveHour != 1 {
+		t.Fatalf("source ActivityDigest mutated: %+v", src.ActivityDigest)
+	}
+}
@@ -11,11 +11,25 @@ func Clone(src Config) Config {
 		dst.Keys = make(map[string]string, len(src.Keys))
 		maps.Copy(dst.Keys, src.Keys)
 	}
+	if src.FeatureFlags != nil {
+		dst.FeatureFlags = maps.Clone(src.FeatureFlags)
+	}
 
 	dst.Worktree.SetupScripts = cloneStringSlice(src.Worktree.SetupScripts)
 	dst.Worktree.CopyFiles = cloneStringSlice(src.Worktree.CopyFiles)
 	dst.Worktree.CopyDirs = cloneStringSlice(src.Worktree.CopyDirs)
+	if src.Worktree.BaseRefresh != nil {
+		refreshCopy := *src.Worktree.BaseRefresh
+		dst.Worktree.BaseRefresh = &refreshCopy
+	}
+	dst.Worktree.SetupWatchers = cloneSetupWatchers(src.Worktree.SetupWatchers)
 	dst.AutoStart = cloneAutoStartCommands(src.AutoStart)
+	dst.ShellRules = cloneShellRules(src.ShellRules)
+	dst.TrustedShells = cloneTrustedShells(src.TrustedShells)
+	dst.SessionBadgeRules = cloneSessionBadgeRules(src.SessionBadgeRules)
+	dst.SessionTemplates = cloneSessionTemplates(src.SessionTemplates)
+	dst.GitIdentities = cloneGitIdentities(src.GitIdentities)
+	dst.Webhooks = cloneWebhooks(src.Webhooks)
 
 	if src.AgentModel != nil {
 		agentModelCopy := *src.AgentModel
@@ -56,6 +70,13 @@ func Clone(src Config) Config {
 			if s.ConfigParams != nil {
 				dst.MCPServers[i].ConfigParams = cloneMCPServerConfigParams(s.ConfigParams)
 			}
+			if s.Permissions != nil {
+				dst.MCPServers[i].Permissions = &MCPServerPermissions{
+					FilesystemPaths: cloneStringSlice(s.Permissions.FilesystemPaths),
+					NetworkHosts:    cloneStringSlice(s.Permissions.NetworkHosts),
+					EnvKeys:         cloneStringSlice(s.Permissions.EnvKeys),
+				}
+			}
 		}
 	}
 
@@ -65,6 +86,63 @@ func Clone(src Config) Config {
 		dst.TaskScheduler = &tsCopy
 	}
 
+	if src.RepoConfig != nil {
+		repoConfigCopy := *src.RepoConfig
+		repoConfigCopy.SigningKeys = cloneStringSlice(src.RepoConfig.SigningKeys)
+		dst.RepoConfig = &repoConfigCopy
+	}
+
+	if src.SessionPool != nil {
+		poolCopy := *src.SessionPool
+		dst.SessionPool = &poolCopy
+	}
+	if src.ToolPaths != nil {
+		toolPathsCopy := *src.ToolPaths
+		toolPathsCopy.Kinds = cloneStringSlice(src.ToolPaths.Kinds)
+		dst.ToolPaths = &toolPathsCopy
+	}
+	if src.Forge != nil {
+		forgeCopy := *src.Forge
+		dst.Forge = &forgeCopy
+	}
+	if src.Logging != nil {
+		loggingCopy := *src.Logging
+		dst.Logging = &loggingCopy
+	}
+	if src.Metrics != nil {
+		metricsCopy := *src.Metrics
+		dst.Metrics = &metricsCopy
+	}
+	if src.ResourceLimits != nil {
+		limitsCopy := *src.ResourceLimits
+		if src.ResourceLimits.Sessions != nil {
+			limitsCopy.Sessions = make(map[string]ResourceLimits, len(src.ResourceLimits.Sessions))
+			maps.Copy(limitsCopy.Sessions, src.ResourceLimits.Sessions)
+		}
+		dst.ResourceLimits = &limitsCopy
+	}
+	if src.FileDrop != nil {
+		fileDropCopy := *src.FileDrop
+		dst.FileDrop = &fileDropCopy
+	}
+	if src.ActivityDigest != nil {
+		digestCopy := *src.ActivityDigest
+		dst.ActivityDigest = &digestCopy
+	}
+	if src.StartupCommands != nil {
+		startupCopy := *src.StartupCommands
+		dst.StartupCommands = &startupCopy
+	}
+
+	if src.CommandTriggers != nil {
+		triggersCopy := *src.CommandTriggers
+		if src.CommandTriggers.Sessions != nil {
+			triggersCopy.Sessions = make(map[string]CommandTriggers, len(src.CommandTriggers.Sessions))
+			maps.Copy(triggersCopy.Sessions, src.CommandTriggers.Sessions)
+		}
+		dst.CommandTriggers = &triggersCopy
+	}
+
 	return dst
 }
 
@@ -77,6 +155,54 @@ func cloneAutoStartCommands(src []AutoStartCommand) []AutoStartCommand {
 	return dst
 }
 
+func cloneShellRules(src []ShellRule) []ShellRule {
+	if src == nil {
+		return nil
+	}
+	dst := make([]ShellRule, len(src))
+	copy(dst, src)
+	return dst
+}
+
+func cloneSessionBadgeRules(src []SessionBadgeRule) []SessionBadgeRule {
+	if src == nil {
+		return nil
+	}
+	dst := make([]SessionBadgeRule, len(src))
+	copy(dst, src)
+	return dst
+}
+
+func cloneGitIdentities(src []GitIdentity) []GitIdentity {
+	if src == nil {
+		return nil
+	}
+	dst := make([]GitIdentity, len(src))
+	copy(dst, src)
+	return dst
+}
+
+func cloneWebhooks(src []Webhook) []Webhook {
+	if src == nil {
+		return nil
+	}
+	dst := make([]Webhook, len(src))
+	for i, hook := range src {
+		dst[i] = hook
+		dst[i].Events = cloneStringSlice(hook.Events)
+	}
+	return dst
+}
+
+func cloneTrustedShells(src []TrustedShell) []TrustedShell {
+	if src == nil {
+		return nil
+	}
+	dst := make([]TrustedShell, len(src))
+	copy(dst, src)
+	return dst
+}
+
 func cloneMessageTemplates(src []MessageTemplate) []MessageTemplate {
 	if src == nil {
 		return nil
@@ -112,3 +238,39 @@ func cloneMCPServerConfigParams(src []MCPServerConfigParam) []MCPServerConfigPar
 	copy(dst, src)
 	return dst
 }
+
+func cloneSetupWatchers(src []SetupWatcher) []SetupWatcher {
+	if src == nil {
+		return nil
+	}
+	dst := make([]SetupWatcher, len(src))
+	for i, watcher := range src {
+		dst[i] = watcher
+		dst[i].Files = cloneStringSlice(watcher.Files)
+	}
+	return dst
+}
+
+func cloneSessionTemplates(src []SessionTemplate) []SessionTemplate {
+	if src == nil {
+		return nil
+	}
+	dst := make([]SessionTemplate, len(src))
+	for i, template := range src {
+		dst[i] = template
+		dst[i].DependsOn = cloneStringSlice(template.DependsOn)
+		dst[i].Env = maps.Clone(template.Env)
+		if template.Panes != nil {
+			dst[i].Panes = make([]SessionTemplatePane, len(template.Panes))
+			for j, pane := range template.Panes {
+				dst[i].Panes[j] = pane
+				dst[i].Panes[j].Env = maps.Clone(pane.Env)
+			}
+		}
+		if template.Health != nil {
+			healthCopy := *template.Health
+			dst[i].Health = &healthCopy
+		}
+	}
+	return dst
+}
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"log/slog"
+	"strings"
+)
+
+// MaxCommandTriggerSessions caps command_triggers.sessions entries.
+const MaxCommandTriggerSessions = 100
+
+// IsZero reports whether the action does nothing.
+func (a CommandTriggerAction) IsZero() bool {
+	return !a.Notify && a.Hook == ""
+}
+
+// IsZero reports whether no action is configured.
+func (t CommandTriggers) IsZero() bool {
+	return t.OnFailure.IsZero() && t.OnSuccess.IsZero()
+}
+
+// ForSession returns the triggers for sessionName: the session entry when
+// one exists, otherwise the global triggers. A nil receiver yields none.
+func (c *CommandTriggersConfig) ForSession(sessionName string) CommandTriggers {
+	if c == nil {
+		return CommandTriggers{}
+	}
+	if triggers, ok := c.Sessions[sessionName]; ok {
+		return triggers
+	}
+	return CommandTriggers{OnFailure: c.OnFailure, OnSuccess: c.OnSuccess}
+}
+
+// NormalizeCommandTriggers trims hooks and reports whether every hook can be
+// run as a single line (see IsValidStartupCommand).
+func NormalizeCommandTriggers(triggers CommandTriggers) (CommandTriggers, bool) {
+	triggers.OnFailure.Hook = NormalizeStartupCommand(triggers.OnFailure.Hook)
+	triggers.OnSuccess.Hook = NormalizeStartupCommand(triggers.OnSuccess.Hook)
+	return triggers, IsValidStartupCommand(triggers.OnFailure.Hook) && IsValidStartupCommand(triggers.OnSuccess.Hook)
+}
+
+// sanitizeCommandTriggers validates command_triggers in place. Invalid hooks
+// are cleared with a warning; session entries with an empty name are
+// dropped; the block is dropped when nothing remains.
+func sanitizeCommandTriggers(cfg *Config) {
+	ct := cfg.CommandTriggers
+	if ct == nil {
+		return
+	}
+	global := sanitizeCommandTriggerSet("global", CommandTriggers{OnFailure: ct.OnFailure, OnSuccess: ct.OnSuccess})
+	ct.OnFailure, ct.OnSuccess = global.OnFailure, global.OnSuccess
+
+	if len(ct.Sessions) > 0 {
+		sessions := make(map[string]CommandTriggers, len(ct.Sessions))
+		for name, triggers := range ct.Sessions {
+			trimmed := strings.TrimSpace(name)
+			if trimmed == "" {
+				slog.Warn("[WARN-CONFIG] command_triggers session entry has no name, ignoring")
+				continue
+			}
+			if len(sessions) >= MaxCommandTriggerSessions {
+				slog.Warn("[WARN-CONFIG] command_triggers.sessions exceeds limit, ignoring extra entries",
+					"max", MaxCommandTriggerSessions)
+				break
+			}
+			// A session entry with no actions is kept: it disables the
+			// global triggers for that session.
+			sessions[trimmed] = sanitizeCommandTriggerSet("sessions."+trimmed, triggers)
+		}
+		ct.Sessions = sessions
+	}
+	if len(ct.Sessions) == 0 {
+		ct.Sessions = nil
+	}
+	if global.IsZero() && ct.Sessions == nil {
+		cfg.CommandTriggers = nil
+	}
+}
+
+func sanitizeCommandTriggerSet(scope string, triggers CommandTriggers) CommandTriggers {
+	triggers.OnFailure.Hook = sanitizeCommandTriggerHook(scope+".on_failure", triggers.OnFailure.Hook)
+	triggers.OnSuccess.Hook = sanitizeCommandTriggerHook(scope+".on_success", triggers.OnSuccess.Hook)
+	return triggers
+}
+
+func sanitizeCommandTriggerHook(field, hook string) string {
+	hook = NormalizeStartupCommand(hook)
+	if hook == "" {
+		return ""
+	}
+	if !IsValidStartupCommand(hook) {
+		slog.Warn("[WARN-CONFIG] command_triggers hook is invalid, ignoring",
+			"field", field, "maxLen", MaxStartupCommandLen)
+		return ""
+	}
+	return hook
+}
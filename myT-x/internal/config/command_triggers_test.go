@@ -0,0 +1,111 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCommandTriggersConfigFieldCountGuard(t *testing.T) {
+	if got := reflect.TypeFor[CommandTriggersConfig]().NumField(); got != 3 {
+		t.Fatalf("CommandTriggersConfig field count = %d, want 3; update sanitizeCommandTriggers, Clone, and this assertion", got)
+	}
+	if got := reflect.TypeFor[CommandTriggerAction]().NumField(); got != 2 {
+		t.Fatalf("CommandTriggerAction field count = %d, want 2; update IsZero and sanitizeCommandTriggerSet", got)
+	}
+}
+
+func TestSanitizeCommandTriggers(t *testing.T) {
+	tests := []struct {
+		name string
+		in   *CommandTriggersConfig
+		want *CommandTriggersConfig
+	}{
+		{name: "nil stays nil", in: nil, want: nil},
+		{
+			name: "trims hooks",
+			in:   &CommandTriggersConfig{OnFailure: CommandTriggerAction{Notify: true, Hook: "  notify.cmd "}},
+			want: &CommandTriggersConfig{OnFailure: CommandTriggerAction{Notify: true, Hook: "notify.cmd"}},
+		},
+		{
+			name: "multi-line hook is cleared",
+			in: &CommandTriggersConfig{
+				OnFailure: CommandTriggerAction{Hook: "echo a\necho b"},
+				OnSuccess: CommandTriggerAction{Notify: true},
+			},
+			want: &CommandTriggersConfig{OnSuccess: CommandTriggerAction{Notify: true}},
+		},
+		{
+			name: "oversized hook is cleared and empty block dropped",
+			in:   &CommandTriggersConfig{OnSuccess: CommandTriggerAction{Hook: strings.Repeat("x", MaxStartupCommandLen+1)}},
+			want: nil,
+		},
+		{
+			name: "session names are trimmed and empty names dropped",
+			in: &CommandTriggersConfig{Sessions: map[string]CommandTriggers{
+				" api ": {OnFailure: CommandTriggerAction{Notify: true}},
+				"  ":    {OnFailure: CommandTriggerAction{Notify: true}},
+			}},
+			want: &CommandTriggersConfig{Sessions: map[string]CommandTriggers{
+				"api": {OnFailure: CommandTriggerAction{Notify: true}},
+			}},
+		},
+		{
+			name: "empty session entry is kept to disable global triggers",
+			in: &CommandTriggersConfig{
+				OnFailure: CommandTriggerAction{Notify: true},
+				Sessions:  map[string]CommandTriggers{"quiet": {}},
+			},
+			want: &CommandTriggersConfig{
+				OnFailure: CommandTriggerAction{Notify: true},
+				Sessions:  map[string]CommandTriggers{"quiet": {}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Config{CommandTriggers: tt.in}
+			sanitizeCommandTriggers(&cfg)
+			if !reflect.DeepEqual(cfg.CommandTriggers, tt.want) {
+				t.Fatalf("CommandTriggers = %#v, want %#v", cfg.CommandTriggers, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommandTriggersForSession(t *testing.T) {
+	var nilConfig *CommandTriggersConfig
+	if got := nilConfig.ForSession("any"); !got.IsZero() {
+		t.Fatalf("nil ForSession = %#v, want zero", got)
+	}
+
+	ct := &CommandTriggersConfig{
+		OnFailure: CommandTriggerAction{Notify: true},
+		Sessions: map[string]CommandTriggers{
+			"deploy": {OnSuccess: CommandTriggerAction{Hook: "deploy.cmd"}},
+		},
+	}
+	if got := ct.ForSession("other"); got != (CommandTriggers{OnFailure: CommandTriggerAction{Notify: true}}) {
+		t.Fatalf("ForSession(other) = %#v, want global triggers", got)
+	}
+	want := CommandTriggers{OnSuccess: CommandTriggerAction{Hook: "deploy.cmd"}}
+	if got := ct.ForSession("deploy"); got != want {
+		t.Fatalf("ForSession(deploy) = %#v, want %#v", got, want)
+	}
+}
+
+func TestCloneCommandTriggers(t *testing.T) {
+	src := Config{CommandTriggers: &CommandTriggersConfig{
+		OnFailure: CommandTriggerAction{Notify: true},
+		Sessions:  map[string]CommandTriggers{"api": {}},
+	}}
+	dst := Clone(src)
+	dst.CommandTriggers.OnFailure.Notify = false
+	dst.CommandTriggers.Sessions["web"] = CommandTriggers{}
+	if !src.CommandTriggers.OnFailure.Notify {
+		t.Fatal("Clone shares CommandTriggers pointer")
+	}
+	if _, ok := src.CommandTriggers.Sessions["web"]; ok {
+		t.Fatal("Clone shares CommandTriggers.Sessions map")
+	}
+}
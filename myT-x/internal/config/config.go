@@ -24,6 +24,9 @@ type Config struct {
 	PaneEnv               map[string]string  `yaml:"pane_env,omitempty" json:"pane_env,omitempty"`
 	PaneEnvDefaultEnabled bool               `yaml:"pane_env_default_enabled" json:"pane_env_default_enabled"`
 	ClaudeEnv             *ClaudeEnvConfig   `yaml:"claude_env,omitempty" json:"claude_env,omitempty"`
+	// ShellHistoryIsolationDefaultEnabled pre-checks "isolate shell history"
+	// in the new session dialog.
+	ShellHistoryIsolationDefaultEnabled bool `yaml:"shell_history_isolation_default_enabled,omitempty" json:"shell_history_isolation_default_enabled,omitempty"`
 	// WebSocketPort is the port for the local WebSocket server used for
 	// high-throughput pane data streaming. 0 (default) lets the OS assign
 	// an available port, which is recommended to avoid port conflicts.
@@ -50,6 +53,86 @@ type Config struct {
 	// TaskScheduler holds persisted task scheduler settings.
 	// nil means no custom settings; the backend returns the effective defaults.
 	TaskScheduler *TaskSchedulerConfig `yaml:"task_scheduler,omitempty" json:"task_scheduler,omitempty"`
+	// ShellRules overrides Shell for panes whose working directory matches a
+	// directory glob or repository. Evaluated at pane spawn time; the first
+	// matching rule wins and Shell is the fallback.
+	ShellRules []ShellRule `yaml:"shell_rules,omitempty" json:"shell_rules,omitempty"`
+	// TrustedShells extends the built-in shell allowlist with absolute paths.
+	// Entries that are missing or fail their hash check are logged and ignored
+	// rather than failing Load/Save.
+	TrustedShells []TrustedShell `yaml:"trusted_shells,omitempty" json:"trusted_shells,omitempty"`
+	// StartupCommands configures commands run in the first pane of new
+	// sessions and windows. nil means no startup command.
+	StartupCommands *StartupCommandsConfig `yaml:"startup_commands,omitempty" json:"startup_commands,omitempty"`
+	// SessionBadgeRules assigns automatic color/emoji badges to sessions by
+	// branch prefix, repository, or dirty state. User-set badges win.
+	SessionBadgeRules []SessionBadgeRule `yaml:"session_badge_rules,omitempty" json:"session_badge_rules,omitempty"`
+	// FocusFollowsActivity switches to a session that needs input (bell, or
+	// idle after unseen output) when the active session is idle.
+	FocusFollowsActivity bool `yaml:"focus_follows_activity,omitempty" json:"focus_follows_activity,omitempty"`
+	// CommandTriggers notifies or runs hooks when a shell command finishes,
+	// based on its exit status. nil means no triggers.
+	CommandTriggers *CommandTriggersConfig `yaml:"command_triggers,omitempty" json:"command_triggers,omitempty"`
+	// SessionTemplates declares sessions with dependencies and health probes
+	// for orchestrated bring-up and tear-down.
+	SessionTemplates []SessionTemplate `yaml:"session_templates,omitempty" json:"session_templates,omitempty"`
+	// RepoConfig lists the keys allowed to sign per-repository .mytx.yaml
+	// files. nil means repo config is gated by directory trust only.
+	RepoConfig *RepoConfigSettings `yaml:"repo_config,omitempty" json:"repo_config,omitempty"`
+	// SessionPool keeps pre-started shells ready so new sessions appear
+	// without waiting for shell startup. nil disables the pool.
+	SessionPool *SessionPoolConfig `yaml:"session_pool,omitempty" json:"session_pool,omitempty"`
+	// OutputFolding collapses runs of near-identical lines (spinners, polling
+	// loops) in the capture-pane history of new panes into "[line xN]"
+	// markers. The live terminal and pane logs are unaffected.
+	OutputFolding bool `yaml:"output_folding,omitempty" json:"output_folding,omitempty"`
+	// GitIdentities sets the git author, committer, and signing key of
+	// matching sessions' panes and worktree commits.
+	GitIdentities []GitIdentity `yaml:"git_identities,omitempty" json:"git_identities,omitempty"`
+	// Webhooks POST selected runtime events to external endpoints.
+	Webhooks []Webhook `yaml:"webhooks,omitempty" json:"webhooks,omitempty"`
+	// ToolPaths prepends repo-local tool directories (node_modules/.bin,
+	// asdf shims, GOBIN) to the PATH of new panes, based on the tool
+	// manifests in the session root. nil disables it.
+	ToolPaths *ToolPathsConfig `yaml:"tool_paths,omitempty" json:"tool_paths,omitempty"`
+	// TmuxCompat selects how the tmux shim treats tmux commands and flags
+	// myT-x does not support: "strict" (default) fails like real tmux,
+	// "lenient" ignores them with a warning.
+	TmuxCompat string `yaml:"tmux_compat,omitempty" json:"tmux_compat,omitempty"`
+	// Mouse is the default mouse mode of panes, like tmux's "mouse" option:
+	// "on" (default) forwards mouse events to pane applications that request
+	// them, "off" keeps the mouse for selection. Panes can override it.
+	Mouse string `yaml:"mouse,omitempty" json:"mouse,omitempty"`
+	// PasteConfirm asks before pasting text whose line breaks would submit
+	// commands to a shell: "on" (default) or "off". Control characters are
+	// stripped from pastes either way.
+	PasteConfirm string `yaml:"paste_confirm,omitempty" json:"paste_confirm,omitempty"`
+	// ActivityDigest writes a daily Markdown digest of each session's
+	// activity (commands, commits, tests, active time, cost estimate) and
+	// emits it as the activity-digest:generated event. nil disables it.
+	ActivityDigest *ActivityDigestConfig `yaml:"activity_digest,omitempty" json:"activity_digest,omitempty"`
+	// FeatureFlags enables or disables experimental subsystems by flag name
+	// (see FeatureFlagDefinitions). Flags not listed use their default.
+	FeatureFlags map[string]bool `yaml:"feature_flags,omitempty" json:"feature_flags,omitempty"`
+	// Forge lets worktree sessions open pull requests (GitHub) or merge
+	// requests (GitLab) for their pushed branch. nil disables it.
+	Forge *ForgeConfig `yaml:"forge,omitempty" json:"forge,omitempty"`
+	// Logging sets the level and format of the app log and its per-component
+	// files. nil uses info level, text format, and files with default limits.
+	Logging *LoggingConfig `yaml:"logging,omitempty" json:"logging,omitempty"`
+	// Metrics serves Prometheus metrics on a localhost HTTP endpoint. nil
+	// disables it.
+	Metrics *MetricsConfig `yaml:"metrics,omitempty" json:"metrics,omitempty"`
+	// ResourceLimits caps the CPU and memory of each session's pane processes
+	// (Windows job objects). nil leaves sessions unlimited.
+	ResourceLimits *ResourceLimitsConfig `yaml:"resource_limits,omitempty" json:"resource_limits,omitempty"`
+	// FileDrop sets what dropping files on a pane does: type their paths
+	// (default), or copy or link them into the pane's directory first.
+	FileDrop *FileDropConfig `yaml:"file_drop,omitempty" json:"file_drop,omitempty"`
+	// EphemeralSessionTTLMinutes is the lifetime of ephemeral sessions; they
+	// are removed with their worktree, temp directory, and history when it
+	// runs out or the app exits. 0 uses DefaultEphemeralSessionTTLMinutes.
+	EphemeralSessionTTLMinutes int `yaml:"ephemeral_session_ttl_minutes,omitempty" json:"ephemeral_session_ttl_minutes,omitempty"`
 }
 
 // DefaultConfig returns default values aligned with spec.
@@ -206,6 +206,12 @@ func TestIsZeroConfig(t *testing.T) {
 				cfg.PaneEnvDefaultEnabled = true
 			},
 		},
+		{
+			name: "shell history isolation default enabled",
+			mutate: func(cfg *Config) {
+				cfg.ShellHistoryIsolationDefaultEnabled = true
+			},
+		},
 		{
 			name: "claude env set",
 			mutate: func(cfg *Config) {
@@ -1418,14 +1424,14 @@ func TestAllowedShellListIsSorted(t *testing.T) {
 }
 
 func TestConfigStructFieldCounts(t *testing.T) {
-	if got := reflect.TypeFor[Config]().NumField(); got != 18 {
-		t.Fatalf("Config field count = %d, want 18; update isZeroConfig tests for new fields", got)
+	if got := reflect.TypeFor[Config]().NumField(); got != 43 {
+		t.Fatalf("Config field count = %d, want 43; update isZeroConfig tests for new fields", got)
 	}
 	if got := reflect.TypeFor[AutoStartCommand]().NumField(); got != 3 {
 		t.Fatalf("AutoStartCommand field count = %d, want 3; update Clone, validation, and payload builders", got)
 	}
-	if got := reflect.TypeFor[WorktreeConfig]().NumField(); got != 6 {
-		t.Fatalf("WorktreeConfig field count = %d, want 6 (enabled, force_cleanup, setup_scripts, setup_script_timeout_seconds, copy_files, copy_dirs)", got)
+	if got := reflect.TypeFor[WorktreeConfig]().NumField(); got != 11 {
+		t.Fatalf("WorktreeConfig field count = %d, want 11 (enabled, force_cleanup, setup_scripts, setup_script_timeout_seconds, copy_files, copy_dirs, copy_files_eol, merge_tool, allow_shared, base_refresh, setup_watchers)", got)
 	}
 	if got := reflect.TypeFor[ClaudeEnvConfig]().NumField(); got != 2 {
 		t.Fatalf("ClaudeEnvConfig field count = %d, want 2 (default_enabled, vars); update Clone/sanitize for new fields", got)
@@ -2629,6 +2635,11 @@ func TestCloneMCPServers(t *testing.T) {
 						Description:  "Execution mode",
 					},
 				},
+				Permissions: &MCPServerPermissions{
+					FilesystemPaths: []string{"."},
+					NetworkHosts:    []string{"api.example.com"},
+					EnvKeys:         []string{"HOME"},
+				},
 			},
 			{
 				ID:      "simple-server",
@@ -2665,6 +2676,12 @@ func TestCloneMCPServers(t *testing.T) {
 			t.Fatalf("source MCPServers[0].ConfigParams mutated: %q", src.MCPServers[0].ConfigParams[0].Label)
 		}
 
+		// Mutate cloned Permissions — source must stay unchanged.
+		dst.MCPServers[0].Permissions.NetworkHosts[0] = "changed.example.com"
+		if src.MCPServers[0].Permissions.NetworkHosts[0] != "api.example.com" {
+			t.Fatalf("source MCPServers[0].Permissions mutated: %q", src.MCPServers[0].Permissions.NetworkHosts[0])
+		}
+
 		// Append to cloned slice — source length must stay unchanged.
 		dst.MCPServers = append(dst.MCPServers, MCPServerConfig{ID: "extra"})
 		if len(src.MCPServers) != 2 {
@@ -2950,9 +2967,56 @@ func TestSaveRoundTripMCPServers(t *testing.T) {
 	}
 }
 
+func TestLoadSanitizesMCPServerPermissions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	raw := []byte(`
+mcp_servers:
+  - id: restricted
+    name: Restricted
+    command: npx
+    permissions:
+      filesystem_paths: [" ./data/ ", "", "data"]
+      network_hosts: [" API.Example.com ", "https://bad.example.com", "*.example.org"]
+      env_keys: [" HOME ", "BAD=KEY", "HOME"]
+  - id: empty-manifest
+    name: Empty
+    command: npx
+    permissions: {}
+  - id: unrestricted
+    name: Unrestricted
+    command: npx
+`)
+	if err := os.WriteFile(path, raw, 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(cfg.MCPServers) != 3 {
+		t.Fatalf("MCPServers length = %d, want 3", len(cfg.MCPServers))
+	}
+
+	want := &MCPServerPermissions{
+		FilesystemPaths: []string{"data"},
+		NetworkHosts:    []string{"api.example.com", "*.example.org"},
+		EnvKeys:         []string{"HOME"},
+	}
+	if got := cfg.MCPServers[0].Permissions; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Permissions = %#v, want %#v", got, want)
+	}
+	if cfg.MCPServers[1].Permissions == nil {
+		t.Fatal("empty permissions block was dropped; it must stay a deny-all manifest")
+	}
+	if cfg.MCPServers[2].Permissions != nil {
+		t.Fatalf("Permissions = %#v, want nil for unrestricted server", cfg.MCPServers[2].Permissions)
+	}
+}
+
 func TestMCPServerConfigStructFieldCount(t *testing.T) {
-	if got := reflect.TypeFor[MCPServerConfig]().NumField(); got != 10 {
-		t.Fatalf("MCPServerConfig field count = %d, want 10; update Clone for new fields", got)
+	if got := reflect.TypeFor[MCPServerConfig]().NumField(); got != 11 {
+		t.Fatalf("MCPServerConfig field count = %d, want 11; update Clone for new fields", got)
 	}
 }
 
@@ -0,0 +1,31 @@
+package config
+
+import (
+	"log/slog"
+	"strings"
+)
+
+// Line ending policies for worktree.copy_files_eol.
+const (
+	// CopyFilesEOLGitAttributes converts files whose eol attribute is set in
+	// the repository's .gitattributes and copies the rest verbatim.
+	CopyFilesEOLGitAttributes = "gitattributes"
+	// CopyFilesEOLLF converts every copied text file to LF.
+	CopyFilesEOLLF = "lf"
+	// CopyFilesEOLCRLF converts every copied text file to CRLF.
+	CopyFilesEOLCRLF = "crlf"
+)
+
+// sanitizeCopyFilesEOL normalizes worktree.copy_files_eol in place. Unknown
+// values fall back to verbatim copying.
+func sanitizeCopyFilesEOL(cfg *Config) {
+	policy := strings.ToLower(strings.TrimSpace(cfg.Worktree.CopyFilesEOL))
+	switch policy {
+	case "", CopyFilesEOLGitAttributes, CopyFilesEOLLF, CopyFilesEOLCRLF:
+	default:
+		slog.Warn("[WARN-CONFIG] unknown worktree.copy_files_eol, copying files verbatim",
+			"configured", cfg.Worktree.CopyFilesEOL)
+		policy = ""
+	}
+	cfg.Worktree.CopyFilesEOL = policy
+}
@@ -0,0 +1,24 @@
+package config
+
+import "testing"
+
+func TestSanitizeCopyFilesEOL(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "", want: ""},
+		{in: "gitattributes", want: CopyFilesEOLGitAttributes},
+		{in: " LF ", want: CopyFilesEOLLF},
+		{in: "CRLF", want: CopyFilesEOLCRLF},
+		{in: "native", want: ""},
+	}
+	for _, tt := range tests {
+		cfg := DefaultConfig()
+		cfg.Worktree.CopyFilesEOL = tt.in
+		sanitizeCopyFilesEOL(&cfg)
+		if cfg.Worktree.CopyFilesEOL != tt.want {
+			t.Errorf("sanitizeCopyFilesEOL(%q) = %q, want %q", tt.in, cfg.Worktree.CopyFilesEOL, tt.want)
+		}
+	}
+}
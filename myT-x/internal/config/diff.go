@@ -0,0 +1,223 @@
+package config
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Subsystem identifies a runtime consumer that reacts to config changes.
+type Subsystem string
+
+const (
+	// SubsystemHotkey is the global quake-mode hotkey registration.
+	SubsystemHotkey Subsystem = "hotkey"
+	// SubsystemRouterEnv is the router's pane_env / claude_env defaults.
+	SubsystemRouterEnv Subsystem = "router_env"
+	// SubsystemFrontend covers settings read directly by the UI
+	// (key bindings, viewer layout, status line re-render).
+	SubsystemFrontend Subsystem = "frontend"
+	// SubsystemPaneSpawn covers settings read when a pane or session is created.
+	SubsystemPaneSpawn Subsystem = "pane_spawn"
+	// SubsystemWorktree covers worktree creation and cleanup settings.
+	SubsystemWorktree Subsystem = "worktree"
+	// SubsystemShim covers settings the tmux shim re-reads on every invocation.
+	SubsystemShim Subsystem = "shim"
+	// SubsystemMCP is the MCP registry loaded at startup.
+	SubsystemMCP Subsystem = "mcp"
+	// SubsystemWebSocket is the pane data stream server bound at startup.
+	SubsystemWebSocket Subsystem = "websocket"
+	// SubsystemSessionBadges is the automatic session badge evaluation.
+	SubsystemSessionBadges Subsystem = "session_badges"
+	// SubsystemNavigation is activity-driven session navigation.
+	SubsystemNavigation Subsystem = "navigation"
+	// SubsystemCommandTriggers is the exit-status trigger dispatch.
+	SubsystemCommandTriggers Subsystem = "command_triggers"
+	// SubsystemBringUp is the orchestrated session bring-up.
+	SubsystemBringUp Subsystem = "bringup"
+	// SubsystemSessionPool is the warm shell pool used by new sessions.
+	SubsystemSessionPool Subsystem = "session_pool"
+	// SubsystemWebhooks is the event webhook delivery.
+	SubsystemWebhooks Subsystem = "webhooks"
+	// SubsystemPaste is the pane paste safety check and file drops.
+	SubsystemPaste Subsystem = "paste"
+	// SubsystemActivityDigest is the daily activity digest generator.
+	SubsystemActivityDigest Subsystem = "activity_digest"
+	// SubsystemFeatureFlags gates experimental subsystems.
+	SubsystemFeatureFlags Subsystem = "feature_flags"
+	// SubsystemLogging is the app log level, format, and files.
+	SubsystemLogging Subsystem = "logging"
+	// SubsystemMetrics is the Prometheus metrics endpoint.
+	SubsystemMetrics Subsystem = "metrics"
+	// SubsystemResourceLimits is the per-session CPU and memory limits.
+	SubsystemResourceLimits Subsystem = "resource_limits"
+)
+
+// ApplyMode describes when a changed key takes effect.
+type ApplyMode string
+
+const (
+	// ApplyImmediate changes take effect as soon as the save completes.
+	ApplyImmediate ApplyMode = "immediate"
+	// ApplyNextUse changes are picked up by the next pane, session, or
+	// worktree created; existing ones keep their current values.
+	ApplyNextUse ApplyMode = "next_use"
+	// ApplyRestart changes require an app restart.
+	ApplyRestart ApplyMode = "restart"
+)
+
+type keyEffect struct {
+	subsystem Subsystem
+	apply     ApplyMode
+}
+
+// keyEffects maps each top-level Config JSON key to its consumer.
+// TestKeyEffectsCoverAllConfigFields fails when a Config field is added
+// without an entry here.
+var keyEffects = map[string]keyEffect{
+	"shell":                    {SubsystemPaneSpawn, ApplyNextUse},
+	"prefix":                   {SubsystemFrontend, ApplyImmediate},
+	"keys":                     {SubsystemFrontend, ApplyImmediate},
+	"quake_mode":               {SubsystemHotkey, ApplyImmediate},
+	"global_hotkey":            {SubsystemHotkey, ApplyImmediate},
+	"auto_start":               {SubsystemPaneSpawn, ApplyNextUse},
+	"worktree":                 {SubsystemWorktree, ApplyNextUse},
+	"agent_model":              {SubsystemShim, ApplyImmediate},
+	"pane_env":                 {SubsystemRouterEnv, ApplyImmediate},
+	"pane_env_default_enabled": {SubsystemFrontend, ApplyImmediate},
+	"claude_env":               {SubsystemRouterEnv, ApplyImmediate},
+	"websocket_port":           {SubsystemWebSocket, ApplyRestart},
+	"viewer_shortcuts":         {SubsystemFrontend, ApplyImmediate},
+	"viewer_sidebar_mode":      {SubsystemFrontend, ApplyImmediate},
+	"default_session_dir":      {SubsystemPaneSpawn, ApplyNextUse},
+	"mcp_servers":              {SubsystemMCP, ApplyRestart},
+	"chat_overlay_percentage":  {SubsystemFrontend, ApplyImmediate},
+	"task_scheduler":           {SubsystemFrontend, ApplyImmediate},
+	"shell_rules":              {SubsystemPaneSpawn, ApplyNextUse},
+	"trusted_shells":           {SubsystemPaneSpawn, ApplyNextUse},
+	"startup_commands":         {SubsystemPaneSpawn, ApplyNextUse},
+	"session_badge_rules":      {SubsystemSessionBadges, ApplyImmediate},
+	"focus_follows_activity":   {SubsystemNavigation, ApplyImmediate},
+	"command_triggers":         {SubsystemCommandTriggers, ApplyImmediate},
+	"session_templates":        {SubsystemBringUp, ApplyNextUse},
+	"repo_config":              {SubsystemWorktree, ApplyNextUse},
+	"session_pool":             {SubsystemSessionPool, ApplyImmediate},
+	"output_folding":           {SubsystemPaneSpawn, ApplyNextUse},
+	"git_identities":           {SubsystemPaneSpawn, ApplyNextUse},
+	"webhooks":                 {SubsystemWebhooks, ApplyImmediate},
+	"tool_paths":               {SubsystemPaneSpawn, ApplyNextUse},
+	"tmux_compat":              {SubsystemShim, ApplyImmediate},
+	"mouse":                    {SubsystemFrontend, ApplyImmediate},
+	"paste_confirm":            {SubsystemPaste, ApplyImmediate},
+	"activity_digest":          {SubsystemActivityDigest, ApplyImmediate},
+	"feature_flags":            {SubsystemFeatureFlags, ApplyImmediate},
+	"forge":                    {SubsystemWorktree, ApplyImmediate},
+	"logging":                  {SubsystemLogging, ApplyImmediate},
+	"metrics":                  {SubsystemMetrics, ApplyImmediate},
+	"resource_limits":          {SubsystemResourceLimits, ApplyImmediate},
+	"file_drop":                {SubsystemPaste, ApplyImmediate},
+	"shell_history_isolation_default_enabled": {SubsystemFrontend, ApplyImmediate},
+	"ephemeral_session_ttl_minutes":           {SubsystemPaneSpawn, ApplyNextUse},
+}
+
+// KeyChange records one changed top-level config key.
+type KeyChange struct {
+	Key       string    `json:"key"`
+	Subsystem Subsystem `json:"subsystem"`
+	Apply     ApplyMode `json:"apply"`
+}
+
+// Diff is the set of top-level keys that differ between two configs,
+// sorted by key.
+type Diff struct {
+	Changes []KeyChange `json:"changes"`
+}
+
+// DiffConfigs compares old and updated key by key.
+// nil and empty maps/slices are treated as equal so that normalization
+// differences do not register as changes.
+func DiffConfigs(old, updated Config) Diff {
+	oldValue := reflect.ValueOf(old)
+	newValue := reflect.ValueOf(updated)
+	configType := oldValue.Type()
+
+	var changes []KeyChange
+	for i := range configType.NumField() {
+		key := configJSONKey(configType.Field(i))
+		if key == "" {
+			continue
+		}
+		if configFieldEqual(oldValue.Field(i), newValue.Field(i)) {
+			continue
+		}
+		effect, ok := keyEffects[key]
+		if !ok {
+			// Unknown keys are treated conservatively.
+			effect = keyEffect{subsystem: SubsystemFrontend, apply: ApplyRestart}
+		}
+		changes = append(changes, KeyChange{Key: key, Subsystem: effect.subsystem, Apply: effect.apply})
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	return Diff{Changes: changes}
+}
+
+// Empty reports whether no keys changed.
+func (d Diff) Empty() bool {
+	return len(d.Changes) == 0
+}
+
+// Affects reports whether any changed key belongs to subsystem.
+func (d Diff) Affects(subsystem Subsystem) bool {
+	for _, change := range d.Changes {
+		if change.Subsystem == subsystem {
+			return true
+		}
+	}
+	return false
+}
+
+// SubsystemKeys returns the config keys consumed by subsystem, sorted. It
+// lets StateService.Subscribe follow a whole subsystem.
+func SubsystemKeys(subsystem Subsystem) []string {
+	keys := []string{}
+	for key, effect := range keyEffects {
+		if effect.subsystem == subsystem {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// KeysWithMode returns the changed keys whose ApplyMode is mode.
+func (d Diff) KeysWithMode(mode ApplyMode) []string {
+	keys := []string{}
+	for _, change := range d.Changes {
+		if change.Apply == mode {
+			keys = append(keys, change.Key)
+		}
+	}
+	return keys
+}
+
+func configJSONKey(field reflect.StructField) string {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return ""
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+func configFieldEqual(a, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.Map, reflect.Slice:
+		if a.Len() == 0 && b.Len() == 0 {
+			return true
+		}
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
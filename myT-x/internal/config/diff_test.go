@@ -0,0 +1,102 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestKeyEffectsCoverAllConfigFields(t *testing.T) {
+	configType := reflect.TypeFor[Config]()
+	seen := map[string]bool{}
+	for i := range configType.NumField() {
+		key := configJSONKey(configType.Field(i))
+		seen[key] = true
+		if _, ok := keyEffects[key]; !ok {
+			t.Errorf("Config key %q has no keyEffects entry", key)
+		}
+	}
+	for key := range keyEffects {
+		if !seen[key] {
+			t.Errorf("keyEffects entry %q does not match any Config field", key)
+		}
+	}
+}
+
+func TestDiffConfigs(t *testing.T) {
+	base := DefaultConfig()
+
+	tests := []struct {
+		name   string
+		mutate func(*Config)
+		want   []string
+	}{
+		{
+			name:   "no change",
+			mutate: func(*Config) {},
+			want:   nil,
+		},
+		{
+			name: "nil and empty collections are equal",
+			mutate: func(cfg *Config) {
+				cfg.PaneEnv = map[string]string{}
+				cfg.ShellRules = []ShellRule{}
+			},
+			want: nil,
+		},
+		{
+			name: "nested change reports top-level key",
+			mutate: func(cfg *Config) {
+				cfg.Worktree.CopyFiles = append(cfg.Worktree.CopyFiles, ".env")
+			},
+			want: []string{"worktree"},
+		},
+		{
+			name: "multiple keys sorted",
+			mutate: func(cfg *Config) {
+				cfg.Shell = "cmd.exe"
+				cfg.QuakeMode = !cfg.QuakeMode
+				cfg.PaneEnv = map[string]string{"A": "1"}
+			},
+			want: []string{"pane_env", "quake_mode", "shell"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated := Clone(base)
+			tt.mutate(&updated)
+			diff := DiffConfigs(base, updated)
+			var got []string
+			for _, change := range diff.Changes {
+				got = append(got, change.Key)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("changed keys = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffHelpers(t *testing.T) {
+	base := DefaultConfig()
+	updated := Clone(base)
+	updated.GlobalHotkey = "Ctrl+Alt+H"
+	updated.MCPServers = []MCPServerConfig{{ID: "x", Name: "x", Command: "x"}}
+	updated.DefaultSessionDir = `C:\work`
+
+	diff := DiffConfigs(base, updated)
+	if !diff.Affects(SubsystemHotkey) {
+		t.Error("Affects(hotkey) = false, want true")
+	}
+	if diff.Affects(SubsystemRouterEnv) {
+		t.Error("Affects(router_env) = true, want false")
+	}
+	if got := diff.KeysWithMode(ApplyImmediate); !reflect.DeepEqual(got, []string{"global_hotkey"}) {
+		t.Errorf("KeysWithMode(immediate) = %v", got)
+	}
+	if got := diff.KeysWithMode(ApplyNextUse); !reflect.DeepEqual(got, []string{"default_session_dir"}) {
+		t.Errorf("KeysWithMode(next_use) = %v", got)
+	}
+	if got := diff.KeysWithMode(ApplyRestart); !reflect.DeepEqual(got, []string{"mcp_servers"}) {
+		t.Errorf("KeysWithMode(restart) = %v", got)
+	}
+}
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"log/slog"
+	"time"
+)
+
+const (
+	// DefaultEphemeralSessionTTLMinutes is the lifetime of ephemeral
+	// sessions when ephemeral_session_ttl_minutes is unset.
+	DefaultEphemeralSessionTTLMinutes = 120
+
+	// MaxEphemeralSessionTTLMinutes is the longest accepted lifetime (7 days).
+	MaxEphemeralSessionTTLMinutes = 7 * 24 * 60
+)
+
+// sanitizeEphemeralSessionTTL drops an out-of-range
+// ephemeral_session_ttl_minutes so the default is used.
+func sanitizeEphemeralSessionTTL(cfg *Config) {
+	if cfg.EphemeralSessionTTLMinutes < 0 || cfg.EphemeralSessionTTLMinutes > MaxEphemeralSessionTTLMinutes {
+		slog.Warn("[WARN-CONFIG] ephemeral_session_ttl_minutes is out of range, using the default",
+			"configured", cfg.EphemeralSessionTTLMinutes, "max", MaxEphemeralSessionTTLMinutes,
+			"default", DefaultEphemeralSessionTTLMinutes)
+		cfg.EphemeralSessionTTLMinutes = 0
+	}
+}
+
+// EphemeralSessionTTL returns the lifetime of ephemeral sessions.
+func EphemeralSessionTTL(cfg Config) time.Duration {
+	minutes := cfg.EphemeralSessionTTLMinutes
+	if minutes <= 0 {
+		minutes = DefaultEphemeralSessionTTLMinutes
+	}
+	return time.Duration(minutes) * time.Minute
+}
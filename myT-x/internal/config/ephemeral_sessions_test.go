@@ -0,0 +1,25 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEphemeralSessionTTL(t *testing.T) {
+	tests := []struct {
+		configured int
+		want       time.Duration
+	}{
+		{configured: 0, want: DefaultEphemeralSessionTTLMinutes * time.Minute},
+		{configured: 30, want: 30 * time.Minute},
+		{configured: -5, want: DefaultEphemeralSessionTTLMinutes * time.Minute},
+		{configured: MaxEphemeralSessionTTLMinutes + 1, want: DefaultEphemeralSessionTTLMinutes * time.Minute},
+	}
+	for _, tt := range tests {
+		cfg := Config{EphemeralSessionTTLMinutes: tt.configured}
+		sanitizeEphemeralSessionTTL(&cfg)
+		if got := EphemeralSessionTTL(cfg); got != tt.want {
+			t.Errorf("EphemeralSessionTTL(%d) = %v, want %v", tt.configured, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"log/slog"
+	"maps"
+	"slices"
+)
+
+// Feature flag names. Experimental subsystems ship disabled ("dark") and are
+// enabled per user through feature_flags in config.yaml or SetFeatureFlag.
+const (
+	// FeatureControlMode gates tmux control mode (`tmux -C` / `-CC`).
+	FeatureControlMode = "control_mode"
+	// FeatureExternalStream gates the /stream WebSocket endpoint that
+	// external tools use to follow pane output.
+	FeatureExternalStream = "external_stream"
+)
+
+// FeatureFlagDefinition describes one feature flag. Defaults are local: no
+// flag is ever fetched from a remote service.
+type FeatureFlagDefinition struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Default is the value used when feature_flags does not set the flag.
+	Default bool `json:"default"`
+	// Apply tells when a change of the flag takes effect.
+	Apply ApplyMode `json:"apply"`
+}
+
+// featureFlagDefinitions lists the known flags ordered by name.
+var featureFlagDefinitions = []FeatureFlagDefinition{
+	{
+		Name:        FeatureControlMode,
+		Description: "tmux control mode (tmux -C / -CC) for control-mode clients such as iTerm2",
+		Default:     false,
+		Apply:       ApplyImmediate,
+	},
+	{
+		Name:        FeatureExternalStream,
+		Description: "WebSocket /stream endpoint for following pane output from external tools",
+		Default:     false,
+		Apply:       ApplyImmediate,
+	},
+}
+
+// FeatureFlagDefinitions returns the known feature flags ordered by name.
+func FeatureFlagDefinitions() []FeatureFlagDefinition {
+	return slices.Clone(featureFlagDefinitions)
+}
+
+// LookupFeatureFlag returns the definition of the flag name.
+func LookupFeatureFlag(name string) (FeatureFlagDefinition, bool) {
+	idx := slices.IndexFunc(featureFlagDefinitions, func(def FeatureFlagDefinition) bool {
+		return def.Name == name
+	})
+	if idx < 0 {
+		return FeatureFlagDefinition{}, false
+	}
+	return featureFlagDefinitions[idx], true
+}
+
+// FeatureEnabled reports whether the flag name is enabled in cfg, falling
+// back to its default. Unknown flags are disabled.
+func FeatureEnabled(cfg Config, name string) bool {
+	def, ok := LookupFeatureFlag(name)
+	if !ok {
+		return false
+	}
+	if enabled, set := cfg.FeatureFlags[name]; set {
+		return enabled
+	}
+	return def.Default
+}
+
+// sanitizeFeatureFlags drops unknown flags, e.g. ones removed after their
+// feature graduated, with a warning.
+func sanitizeFeatureFlags(cfg *Config) {
+	if len(cfg.FeatureFlags) == 0 {
+		cfg.FeatureFlags = nil
+		return
+	}
+	flags := maps.Clone(cfg.FeatureFlags)
+	for name := range flags {
+		if _, ok := LookupFeatureFlag(name); !ok {
+			slog.Warn("[WARN-CONFIG] feature_flags entry is unknown, ignoring it", "flag", name)
+			delete(flags, name)
+		}
+	}
+	if len(flags) == 0 {
+		flags = nil
+	}
+	cfg.FeatureFlags = flags
+}
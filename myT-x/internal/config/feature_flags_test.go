@@ -0,0 +1,47 @@
+package config
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestFeatureEnabledFallsBackToDefault(t *testing.T) {
+	cfg := DefaultConfig()
+	if FeatureEnabled(cfg, FeatureControlMode) {
+		t.Fatal("control_mode should ship disabled")
+	}
+	cfg.FeatureFlags = map[string]bool{FeatureControlMode: true}
+	if !FeatureEnabled(cfg, FeatureControlMode) {
+		t.Fatal("control_mode enabled in feature_flags reported disabled")
+	}
+	if FeatureEnabled(cfg, "no_such_flag") {
+		t.Fatal("unknown flag reported enabled")
+	}
+}
+
+func TestFeatureFlagDefinitionsSortedAndKnown(t *testing.T) {
+	defs := FeatureFlagDefinitions()
+	if !slices.IsSortedFunc(defs, func(a, b FeatureFlagDefinition) int { return strings.Compare(a.Name, b.Name) }) {
+		t.Fatalf("definitions not sorted by name: %+v", defs)
+	}
+	for _, def := range defs {
+		if def.Description == "" || def.Apply == "" {
+			t.Errorf("definition %q lacks description or apply mode", def.Name)
+		}
+	}
+}
+
+func TestSanitizeFeatureFlagsDropsUnknownFlags(t *testing.T) {
+	cfg := Config{FeatureFlags: map[string]bool{FeatureExternalStream: true, "graduated": true}}
+	sanitizeFeatureFlags(&cfg)
+	if len(cfg.FeatureFlags) != 1 || !cfg.FeatureFlags[FeatureExternalStream] {
+		t.Fatalf("FeatureFlags = %v, want only external_stream", cfg.FeatureFlags)
+	}
+
+	cfg = Config{FeatureFlags: map[string]bool{"graduated": false}}
+	sanitizeFeatureFlags(&cfg)
+	if cfg.FeatureFlags != nil {
+		t.Fatalf("FeatureFlags = %v, want nil", cfg.FeatureFlags)
+	}
+}
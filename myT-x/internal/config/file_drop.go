@@ -0,0 +1,80 @@
+package config
+
+import (
+	"log/slog"
+	"strings"
+)
+
+// file_drop.mode values.
+const (
+	// FileDropModePath types the dropped paths into the pane.
+	FileDropModePath = "path"
+	// FileDropModeCopy copies the dropped files into the pane's directory.
+	FileDropModeCopy = "copy"
+	// FileDropModeLink links the dropped files into the pane's directory.
+	FileDropModeLink = "link"
+)
+
+const (
+	// DefaultFileDropMaxSizeMB caps one copy when file_drop.max_size_mb is
+	// unset.
+	DefaultFileDropMaxSizeMB = 100
+
+	// MaxFileDropMaxSizeMB is the largest accepted file_drop.max_size_mb.
+	MaxFileDropMaxSizeMB = 10240
+)
+
+// sanitizeFileDrop normalizes file_drop in place. Invalid values fall back to
+// their defaults with a warning.
+func sanitizeFileDrop(cfg *Config) {
+	fd := cfg.FileDrop
+	if fd == nil {
+		return
+	}
+	mode := strings.ToLower(strings.TrimSpace(fd.Mode))
+	switch mode {
+	case "", FileDropModePath, FileDropModeCopy, FileDropModeLink:
+		fd.Mode = mode
+	default:
+		slog.Warn("[WARN-CONFIG] file_drop mode is invalid, falling back to path",
+			"configured", fd.Mode)
+		fd.Mode = ""
+	}
+	typePath := strings.ToLower(strings.TrimSpace(fd.TypePath))
+	switch typePath {
+	case "", "on", "off":
+		fd.TypePath = typePath
+	default:
+		slog.Warn("[WARN-CONFIG] file_drop type_path is invalid, falling back to on",
+			"configured", fd.TypePath)
+		fd.TypePath = ""
+	}
+	if fd.MaxSizeMB < 0 || fd.MaxSizeMB > MaxFileDropMaxSizeMB {
+		slog.Warn("[WARN-CONFIG] file_drop max_size_mb is out of range, using the default",
+			"configured", fd.MaxSizeMB, "max", MaxFileDropMaxSizeMB, "default", DefaultFileDropMaxSizeMB)
+		fd.MaxSizeMB = 0
+	}
+}
+
+// EffectiveMode returns the file drop mode, FileDropModePath when
+// unset.
+func (c *FileDropConfig) EffectiveMode() string {
+	if c == nil || c.Mode == "" {
+		return FileDropModePath
+	}
+	return c.Mode
+}
+
+// TypesPath reports whether dropped paths are typed into the pane.
+func (c *FileDropConfig) TypesPath() bool {
+	return c == nil || c.TypePath != "off"
+}
+
+// MaxSizeBytes returns the size cap of one copy.
+func (c *FileDropConfig) MaxSizeBytes() int64 {
+	mb := DefaultFileDropMaxSizeMB
+	if c != nil && c.MaxSizeMB > 0 {
+		mb = c.MaxSizeMB
+	}
+	return int64(mb) << 20
+}
@@ -0,0 +1,34 @@
+package config
+
+import "testing"
+
+func TestSanitizeFileDrop(t *testing.T) {
+	cfg := Config{FileDrop: &FileDropConfig{Mode: " Copy ", TypePath: "OFF", MaxSizeMB: 25}}
+	sanitizeFileDrop(&cfg)
+	if got := cfg.FileDrop.EffectiveMode(); got != FileDropModeCopy {
+		t.Errorf("EffectiveMode() = %q, want %q", got, FileDropModeCopy)
+	}
+	if cfg.FileDrop.TypesPath() {
+		t.Error("TypesPath() = true, want false")
+	}
+	if got := cfg.FileDrop.MaxSizeBytes(); got != 25<<20 {
+		t.Errorf("MaxSizeBytes() = %d, want %d", got, 25<<20)
+	}
+
+	cfg = Config{FileDrop: &FileDropConfig{Mode: "move", TypePath: "maybe", MaxSizeMB: -1}}
+	sanitizeFileDrop(&cfg)
+	if got := cfg.FileDrop.EffectiveMode(); got != FileDropModePath {
+		t.Errorf("invalid mode: EffectiveMode() = %q, want %q", got, FileDropModePath)
+	}
+	if !cfg.FileDrop.TypesPath() {
+		t.Error("invalid type_path: TypesPath() = false, want true")
+	}
+	if got := cfg.FileDrop.MaxSizeBytes(); got != DefaultFileDropMaxSizeMB<<20 {
+		t.Errorf("invalid max_size_mb: MaxSizeBytes() = %d, want the default", got)
+	}
+
+	var unset *FileDropConfig
+	if unset.EffectiveMode() != FileDropModePath || !unset.TypesPath() || unset.MaxSizeBytes() != DefaultFileDropMaxSizeMB<<20 {
+		t.Error("nil FileDropConfig does not yield the defaults")
+	}
+}
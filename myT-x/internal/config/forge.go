@@ -0,0 +1,53 @@
+package config
+
+import (
+	"log/slog"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Forge kinds of forge.kind.
+const (
+	ForgeGitHub = "github"
+	ForgeGitLab = "gitlab"
+)
+
+// ResolveToken returns the API token: Token when set, otherwise the value of
+// the environment variable TokenEnv.
+func (cfg ForgeConfig) ResolveToken() string {
+	if cfg.Token != "" {
+		return cfg.Token
+	}
+	if cfg.TokenEnv == "" {
+		return ""
+	}
+	return strings.TrimSpace(os.Getenv(cfg.TokenEnv))
+}
+
+// sanitizeForge normalizes forge in place. An unknown kind falls back to
+// detection from the remote host; an api_url that is not an absolute https
+// URL is dropped so the token is never sent in clear text.
+func sanitizeForge(cfg *Config) {
+	forge := cfg.Forge
+	if forge == nil {
+		return
+	}
+	forge.Kind = strings.ToLower(strings.TrimSpace(forge.Kind))
+	switch forge.Kind {
+	case "", ForgeGitHub, ForgeGitLab:
+	default:
+		slog.Warn("[WARN-CONFIG] forge kind must be github or gitlab, detecting it from the remote instead",
+			"kind", forge.Kind)
+		forge.Kind = ""
+	}
+	forge.APIURL = strings.TrimRight(strings.TrimSpace(forge.APIURL), "/")
+	if forge.APIURL != "" {
+		if u, err := url.Parse(forge.APIURL); err != nil || u.Scheme != "https" || u.Host == "" {
+			slog.Warn("[WARN-CONFIG] forge api_url must be an absolute https URL, ignoring it", "apiURL", forge.APIURL)
+			forge.APIURL = ""
+		}
+	}
+	forge.Token = strings.TrimSpace(forge.Token)
+	forge.TokenEnv = strings.TrimSpace(forge.TokenEnv)
+}
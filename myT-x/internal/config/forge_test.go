@@ -0,0 +1,30 @@
+package config
+
+import "testing"
+
+func TestSanitizeForge(t *testing.T) {
+	cfg := Config{Forge: &ForgeConfig{Kind: " GitLab ", APIURL: "https://git.example.com/api/v4/", Token: " glpat "}}
+	sanitizeForge(&cfg)
+	if got := *cfg.Forge; got.Kind != ForgeGitLab || got.APIURL != "https://git.example.com/api/v4" || got.Token != "glpat" {
+		t.Fatalf("sanitized forge = %+v", got)
+	}
+
+	cfg = Config{Forge: &ForgeConfig{Kind: "bitbucket", APIURL: "http://git.example.com/api"}}
+	sanitizeForge(&cfg)
+	if cfg.Forge.Kind != "" || cfg.Forge.APIURL != "" {
+		t.Fatalf("sanitized forge = %+v, want kind and api_url dropped", *cfg.Forge)
+	}
+}
+
+func TestForgeResolveToken(t *testing.T) {
+	t.Setenv("MYTX_TEST_FORGE_TOKEN", " from-env ")
+	if got := (ForgeConfig{Token: "inline", TokenEnv: "MYTX_TEST_FORGE_TOKEN"}).ResolveToken(); got != "inline" {
+		t.Fatalf("ResolveToken() = %q, want inline token", got)
+	}
+	if got := (ForgeConfig{TokenEnv: "MYTX_TEST_FORGE_TOKEN"}).ResolveToken(); got != "from-env" {
+		t.Fatalf("ResolveToken() = %q, want token from environment", got)
+	}
+	if got := (ForgeConfig{}).ResolveToken(); got != "" {
+		t.Fatalf("ResolveToken() = %q, want empty", got)
+	}
+}
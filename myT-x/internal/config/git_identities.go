@@ -0,0 +1,158 @@
+package config
+
+import (
+	"errors"
+	"log/slog"
+	"path"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+const (
+	// MaxGitIdentities caps git_identities entries.
+	MaxGitIdentities = 50
+	// maxGitIdentityFieldBytes bounds each identity value.
+	maxGitIdentityFieldBytes = 512
+)
+
+// GitIdentityFacts is the session state that git_identities match against.
+type GitIdentityFacts struct {
+	SessionName string
+	// RepoPath is the main repository of a worktree session. When empty, the
+	// repository containing WorkDir is used.
+	RepoPath string
+	WorkDir  string
+}
+
+// sanitizeGitIdentities validates git_identities entries in place.
+// Entries without a matcher, without a name and email, or with an invalid
+// value are dropped with a warning so that a single bad entry never blocks
+// startup.
+func sanitizeGitIdentities(cfg *Config) {
+	if len(cfg.GitIdentities) == 0 {
+		cfg.GitIdentities = nil
+		return
+	}
+
+	filtered := make([]GitIdentity, 0, min(len(cfg.GitIdentities), MaxGitIdentities))
+	for i, identity := range cfg.GitIdentities {
+		identity.Session = strings.TrimSpace(identity.Session)
+		identity.Repo = strings.TrimSpace(identity.Repo)
+		identity.Name = strings.TrimSpace(identity.Name)
+		identity.Email = strings.TrimSpace(identity.Email)
+		identity.SigningKey = strings.TrimSpace(identity.SigningKey)
+
+		if identity.Session == "" && identity.Repo == "" {
+			slog.Warn("[WARN-CONFIG] git_identities entry has neither session nor repo, skipping", "index", i)
+			continue
+		}
+		if identity.Session != "" {
+			if _, err := path.Match(identity.Session, ""); err != nil {
+				slog.Warn("[WARN-CONFIG] git_identities entry session is not a valid glob, skipping",
+					"index", i, "session", identity.Session, "error", err)
+				continue
+			}
+		}
+		if err := validateGitIdentity(identity); err != nil {
+			slog.Warn("[WARN-CONFIG] git_identities entry is invalid, skipping", "index", i, "error", err)
+			continue
+		}
+		if identity.Repo != "" && filepath.IsAbs(identity.Repo) {
+			identity.Repo = filepath.Clean(identity.Repo)
+		}
+
+		filtered = append(filtered, identity)
+		if len(filtered) == MaxGitIdentities {
+			if i < len(cfg.GitIdentities)-1 {
+				slog.Warn("[WARN-CONFIG] git_identities exceeds maximum, truncating",
+					"count", len(cfg.GitIdentities), "max", MaxGitIdentities)
+			}
+			break
+		}
+	}
+	if len(filtered) == 0 {
+		cfg.GitIdentities = nil
+		return
+	}
+	cfg.GitIdentities = filtered
+}
+
+// validateGitIdentity checks the values of a trimmed identity.
+func validateGitIdentity(identity GitIdentity) error {
+	if identity.Name == "" || identity.Email == "" {
+		return errors.New("name and email are required")
+	}
+	if strings.ContainsFunc(identity.Email, unicode.IsSpace) || !strings.Contains(identity.Email, "@") {
+		return errors.New("email must be an address without whitespace")
+	}
+	// git rejects "<" and ">" in identities; they delimit the email in commits.
+	if strings.ContainsAny(identity.Name+identity.Email, "<>") {
+		return errors.New("name and email must not contain angle brackets")
+	}
+	for _, value := range []string{identity.Name, identity.Email, identity.SigningKey} {
+		if len(value) > maxGitIdentityFieldBytes {
+			return errors.New("value is too long")
+		}
+		if strings.ContainsFunc(value, unicode.IsControl) {
+			return errors.New("value must not contain control characters")
+		}
+	}
+	return nil
+}
+
+// ResolveGitIdentity returns the first git_identities entry matching facts.
+// ok is false when no entry matches.
+//
+// cfg is expected to be normalized by Load/Save.
+func ResolveGitIdentity(cfg Config, facts GitIdentityFacts) (identity GitIdentity, ok bool) {
+	if len(cfg.GitIdentities) == 0 {
+		return GitIdentity{}, false
+	}
+	repoRoot := ""
+	if facts.RepoPath != "" {
+		repoRoot = filepath.Clean(facts.RepoPath)
+	}
+	repoResolved := repoRoot != ""
+	for _, identity := range cfg.GitIdentities {
+		if identity.Session != "" {
+			if matched, err := path.Match(identity.Session, facts.SessionName); err != nil || !matched {
+				continue
+			}
+		}
+		if identity.Repo != "" {
+			// Repo lookup runs git; do it at most once per resolution.
+			if !repoResolved {
+				if workDir := strings.TrimSpace(facts.WorkDir); workDir != "" {
+					repoRoot = findRepoRoot(filepath.Clean(workDir))
+				}
+				repoResolved = true
+			}
+			if !shellRuleRepoMatches(identity.Repo, repoRoot) {
+				continue
+			}
+		}
+		return identity, true
+	}
+	return GitIdentity{}, false
+}
+
+// Env returns the environment variables that make git author and commit as
+// the identity. A signing key is passed through GIT_CONFIG_* so commits are
+// signed with it.
+func (identity GitIdentity) Env() map[string]string {
+	env := map[string]string{
+		"GIT_AUTHOR_NAME":     identity.Name,
+		"GIT_AUTHOR_EMAIL":    identity.Email,
+		"GIT_COMMITTER_NAME":  identity.Name,
+		"GIT_COMMITTER_EMAIL": identity.Email,
+	}
+	if identity.SigningKey != "" {
+		env["GIT_CONFIG_COUNT"] = "2"
+		env["GIT_CONFIG_KEY_0"] = "user.signingkey"
+		env["GIT_CONFIG_VALUE_0"] = identity.SigningKey
+		env["GIT_CONFIG_KEY_1"] = "commit.gpgsign"
+		env["GIT_CONFIG_VALUE_1"] = "true"
+	}
+	return env
+}
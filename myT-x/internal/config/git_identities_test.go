@@ -0,0 +1,133 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestGitIdentityFieldCountGuard(t *testing.T) {
+	if got := reflect.TypeFor[GitIdentity]().NumField(); got != 5 {
+		t.Fatalf("GitIdentity field count = %d, want 5; update sanitizeGitIdentities, ResolveGitIdentity, Env, and this assertion", got)
+	}
+}
+
+func TestSanitizeGitIdentities(t *testing.T) {
+	cfg := Config{GitIdentities: []GitIdentity{
+		{Session: " work-* ", Name: " Jane Doe ", Email: " jane@corp.example ", SigningKey: " ABCD1234 "},
+		{Name: "No Matcher", Email: "x@example.com"},                 // no matcher
+		{Repo: "app", Name: "No Email"},                              // no email
+		{Repo: "app", Name: "Bad", Email: "not an email"},            // invalid email
+		{Repo: "app", Name: "Jane <Doe>", Email: "jane@example.com"}, // angle brackets
+		{Session: "[", Name: "Bad Glob", Email: "x@example.com"},     // invalid glob
+		{Repo: "app", Name: "Line\nBreak", Email: "x@example.com"},   // control character
+		{Repo: "oss", Name: "Jane", Email: "jane@users.noreply.example.com"},
+	}}
+	sanitizeGitIdentities(&cfg)
+	want := []GitIdentity{
+		{Session: "work-*", Name: "Jane Doe", Email: "jane@corp.example", SigningKey: "ABCD1234"},
+		{Repo: "oss", Name: "Jane", Email: "jane@users.noreply.example.com"},
+	}
+	if !reflect.DeepEqual(cfg.GitIdentities, want) {
+		t.Fatalf("GitIdentities = %+v, want %+v", cfg.GitIdentities, want)
+	}
+
+	cfg = Config{GitIdentities: []GitIdentity{{Name: "x", Email: "x@example.com"}}}
+	sanitizeGitIdentities(&cfg)
+	if cfg.GitIdentities != nil {
+		t.Fatalf("all-invalid identities = %+v, want nil", cfg.GitIdentities)
+	}
+}
+
+func TestSanitizeGitIdentitiesTruncates(t *testing.T) {
+	identities := make([]GitIdentity, MaxGitIdentities+5)
+	for i := range identities {
+		identities[i] = GitIdentity{Session: "*", Name: "x", Email: "x@example.com"}
+	}
+	cfg := Config{GitIdentities: identities}
+	sanitizeGitIdentities(&cfg)
+	if len(cfg.GitIdentities) != MaxGitIdentities {
+		t.Fatalf("len(GitIdentities) = %d, want %d", len(cfg.GitIdentities), MaxGitIdentities)
+	}
+}
+
+func TestResolveGitIdentity(t *testing.T) {
+	repoRoot := filepath.Join(t.TempDir(), "Backend")
+	initTestRepo(t, repoRoot)
+	subDir := filepath.Join(repoRoot, "cmd")
+	if err := os.MkdirAll(subDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	cfg := Config{GitIdentities: []GitIdentity{
+		{Session: "oss-*", Repo: "backend", Name: "OSS Backend", Email: "oss-backend@example.com"},
+		{Session: "oss-*", Name: "OSS", Email: "oss@example.com"},
+		{Repo: "backend", Name: "Work", Email: "work@example.com"},
+	}}
+
+	tests := []struct {
+		name   string
+		facts  GitIdentityFacts
+		want   GitIdentity
+		wantOK bool
+	}{
+		{
+			name:   "session and repo",
+			facts:  GitIdentityFacts{SessionName: "oss-api", WorkDir: subDir},
+			want:   cfg.GitIdentities[0],
+			wantOK: true,
+		},
+		{
+			name:   "session outside repo",
+			facts:  GitIdentityFacts{SessionName: "oss-docs", WorkDir: t.TempDir()},
+			want:   cfg.GitIdentities[1],
+			wantOK: true,
+		},
+		{
+			name:   "worktree uses main repository",
+			facts:  GitIdentityFacts{SessionName: "feature", RepoPath: repoRoot, WorkDir: t.TempDir()},
+			want:   cfg.GitIdentities[2],
+			wantOK: true,
+		},
+		{
+			name:  "no match",
+			facts: GitIdentityFacts{SessionName: "feature", WorkDir: t.TempDir()},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ResolveGitIdentity(cfg, tt.facts)
+			if ok != tt.wantOK || got != tt.want {
+				t.Fatalf("ResolveGitIdentity() = (%+v, %v), want (%+v, %v)", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestGitIdentityEnv(t *testing.T) {
+	identity := GitIdentity{Name: "Jane", Email: "jane@example.com"}
+	want := map[string]string{
+		"GIT_AUTHOR_NAME":     "Jane",
+		"GIT_AUTHOR_EMAIL":    "jane@example.com",
+		"GIT_COMMITTER_NAME":  "Jane",
+		"GIT_COMMITTER_EMAIL": "jane@example.com",
+	}
+	if got := identity.Env(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Env() = %v, want %v", got, want)
+	}
+
+	identity.SigningKey = "ABCD1234"
+	got := identity.Env()
+	if got["GIT_CONFIG_COUNT"] != "2" || got["GIT_CONFIG_VALUE_0"] != "ABCD1234" || got["GIT_CONFIG_VALUE_1"] != "true" {
+		t.Fatalf("Env() with signing key = %v", got)
+	}
+}
+
+func TestCloneGitIdentities(t *testing.T) {
+	src := Config{GitIdentities: []GitIdentity{{Session: "*", Name: "a", Email: "a@example.com"}}}
+	dst := Clone(src)
+	dst.GitIdentities[0].Name = "b"
+	if src.GitIdentities[0].Name != "a" {
+		t.Fatalf("source GitIdentities mutated: %+v", src.GitIdentities)
+	}
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"log/slog"
+	"strings"
+)
+
+// Log levels and formats of logging.
+const (
+	LogLevelDebug = "debug"
+	LogLevelInfo  = "info"
+	LogLevelWarn  = "warn"
+	LogLevelError = "error"
+
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+
+	// MaxLogFileMB caps logging.max_file_mb.
+	MaxLogFileMB = 100
+	// MaxLogKeepFiles caps logging.keep_files.
+	MaxLogKeepFiles = 32
+)
+
+// sanitizeLogging normalizes logging in place. Unknown levels and formats
+// fall back to the defaults; sizes are clamped to their limits.
+func sanitizeLogging(cfg *Config) {
+	logging := cfg.Logging
+	if logging == nil {
+		return
+	}
+	logging.Level = strings.ToLower(strings.TrimSpace(logging.Level))
+	if logging.Level == "warning" {
+		logging.Level = LogLevelWarn
+	}
+	switch logging.Level {
+	case "", LogLevelDebug, LogLevelInfo, LogLevelWarn, LogLevelError:
+	default:
+		slog.Warn("[WARN-CONFIG] logging level must be debug, info, warn, or error, using info",
+			"level", logging.Level)
+		logging.Level = ""
+	}
+	logging.Format = strings.ToLower(strings.TrimSpace(logging.Format))
+	switch logging.Format {
+	case "", LogFormatText, LogFormatJSON:
+	default:
+		slog.Warn("[WARN-CONFIG] logging format must be text or json, using text", "format", logging.Format)
+		logging.Format = ""
+	}
+	if logging.MaxFileMB < 0 {
+		logging.MaxFileMB = 0
+	}
+	if logging.MaxFileMB > MaxLogFileMB {
+		slog.Warn("[WARN-CONFIG] logging max_file_mb exceeds limit, clamping",
+			"maxFileMB", logging.MaxFileMB, "max", MaxLogFileMB)
+		logging.MaxFileMB = MaxLogFileMB
+	}
+	if logging.KeepFiles < 0 {
+		logging.KeepFiles = 0
+	}
+	if logging.KeepFiles > MaxLogKeepFiles {
+		slog.Warn("[WARN-CONFIG] logging keep_files exceeds limit, clamping",
+			"keepFiles", logging.KeepFiles, "max", MaxLogKeepFiles)
+		logging.KeepFiles = MaxLogKeepFiles
+	}
+}
@@ -0,0 +1,17 @@
+package config
+
+import "testing"
+
+func TestSanitizeLogging(t *testing.T) {
+	cfg := Config{Logging: &LoggingConfig{Level: " Warning ", Format: "JSON", MaxFileMB: 500, KeepFiles: -1}}
+	sanitizeLogging(&cfg)
+	if got := *cfg.Logging; got.Level != LogLevelWarn || got.Format != LogFormatJSON || got.MaxFileMB != MaxLogFileMB || got.KeepFiles != 0 {
+		t.Fatalf("sanitized logging = %+v", got)
+	}
+
+	cfg = Config{Logging: &LoggingConfig{Level: "verbose", Format: "xml"}}
+	sanitizeLogging(&cfg)
+	if cfg.Logging.Level != "" || cfg.Logging.Format != "" {
+		t.Fatalf("sanitized logging = %+v, want unknown level and format dropped", *cfg.Logging)
+	}
+}
@@ -0,0 +1,22 @@
+package config
+
+import (
+	"log/slog"
+	"strings"
+	"unicode"
+)
+
+// maxMergeToolBytes bounds worktree.merge_tool.
+const maxMergeToolBytes = 1024
+
+// sanitizeMergeTool trims worktree.merge_tool in place. Values with control
+// characters or over maxMergeToolBytes fall back to git's merge.tool.
+func sanitizeMergeTool(cfg *Config) {
+	tool := strings.TrimSpace(cfg.Worktree.MergeTool)
+	if strings.ContainsFunc(tool, unicode.IsControl) || len(tool) > maxMergeToolBytes {
+		slog.Warn("[WARN-CONFIG] invalid worktree.merge_tool, using git merge.tool",
+			"length", len(tool))
+		tool = ""
+	}
+	cfg.Worktree.MergeTool = tool
+}
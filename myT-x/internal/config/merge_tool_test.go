@@ -0,0 +1,27 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSanitizeMergeTool(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "", want: ""},
+		{in: " vscode ", want: "vscode"},
+		{in: `"C:\Tools\p4merge.exe" "$BASE" "$LOCAL" "$REMOTE" "$MERGED"`, want: `"C:\Tools\p4merge.exe" "$BASE" "$LOCAL" "$REMOTE" "$MERGED"`},
+		{in: "meld\nrm -rf", want: ""},
+		{in: strings.Repeat("x", maxMergeToolBytes+1), want: ""},
+	}
+	for _, tt := range tests {
+		cfg := DefaultConfig()
+		cfg.Worktree.MergeTool = tt.in
+		sanitizeMergeTool(&cfg)
+		if cfg.Worktree.MergeTool != tt.want {
+			t.Errorf("sanitizeMergeTool(%q) = %q, want %q", tt.in, cfg.Worktree.MergeTool, tt.want)
+		}
+	}
+}
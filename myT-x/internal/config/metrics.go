@@ -0,0 +1,20 @@
+package config
+
+import "log/slog"
+
+// DefaultMetricsPort is the port of the metrics endpoint when metrics.port
+// is unset.
+const DefaultMetricsPort = 9464
+
+// sanitizeMetrics drops an out-of-range metrics port so the default is used.
+func sanitizeMetrics(cfg *Config) {
+	metrics := cfg.Metrics
+	if metrics == nil {
+		return
+	}
+	if metrics.Port < 0 || metrics.Port > 65535 {
+		slog.Warn("[WARN-CONFIG] metrics port must be between 1 and 65535, using the default",
+			"port", metrics.Port, "default", DefaultMetricsPort)
+		metrics.Port = 0
+	}
+}
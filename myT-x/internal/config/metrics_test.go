@@ -0,0 +1,18 @@
+package config
+
+import "testing"
+
+func TestSanitizeMetrics(t *testing.T) {
+	cfg := Config{Metrics: &MetricsConfig{Enabled: true, Port: 70000}}
+	sanitizeMetrics(&cfg)
+	if cfg.Metrics.Port != 0 || !cfg.Metrics.Enabled {
+		t.Fatalf("sanitized metrics = %+v, want port reset", *cfg.Metrics)
+	}
+
+	cfg = Config{Metrics: &MetricsConfig{Port: 9100}}
+	sanitizeMetrics(&cfg)
+	if cfg.Metrics.Port != 9100 {
+		t.Fatalf("sanitized metrics = %+v, want port kept", *cfg.Metrics)
+	}
+	sanitizeMetrics(&Config{})
+}
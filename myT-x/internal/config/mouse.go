@@ -0,0 +1,36 @@
+package config
+
+import (
+	"log/slog"
+	"strings"
+)
+
+// mouse modes, named after tmux's "mouse" option.
+const (
+	// MouseOn forwards mouse events to pane applications that request mouse
+	// tracking (htop, vim, lazygit).
+	MouseOn = "on"
+	// MouseOff keeps the mouse for local selection and scrolling; pane
+	// applications never receive mouse events.
+	MouseOff = "off"
+)
+
+// sanitizeMouse normalizes mouse in place. Invalid values fall back to the
+// on default with a warning.
+func sanitizeMouse(cfg *Config) {
+	mode := strings.ToLower(strings.TrimSpace(cfg.Mouse))
+	switch mode {
+	case "", MouseOn, MouseOff:
+		cfg.Mouse = mode
+	default:
+		slog.Warn("[WARN-CONFIG] mouse is invalid, falling back to on",
+			"configured", cfg.Mouse)
+		cfg.Mouse = ""
+	}
+}
+
+// EffectiveMouse reports whether mouse events are forwarded to panes that
+// have no per-pane override.
+func EffectiveMouse(cfg Config) bool {
+	return cfg.Mouse != MouseOff
+}
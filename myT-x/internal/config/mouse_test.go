@@ -0,0 +1,26 @@
+package config
+
+import "testing"
+
+func TestSanitizeMouse(t *testing.T) {
+	tests := []struct {
+		configured string
+		want       string
+		effective  bool
+	}{
+		{configured: "", want: "", effective: true},
+		{configured: " OFF ", want: MouseOff, effective: false},
+		{configured: "on", want: MouseOn, effective: true},
+		{configured: "sometimes", want: "", effective: true},
+	}
+	for _, tt := range tests {
+		cfg := Config{Mouse: tt.configured}
+		sanitizeMouse(&cfg)
+		if cfg.Mouse != tt.want {
+			t.Errorf("sanitizeMouse(%q) = %q, want %q", tt.configured, cfg.Mouse, tt.want)
+		}
+		if got := EffectiveMouse(cfg); got != tt.effective {
+			t.Errorf("EffectiveMouse(%q) = %v, want %v", tt.configured, got, tt.effective)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package config
+
+import (
+	"log/slog"
+	"strings"
+)
+
+// paste_confirm values.
+const (
+	// PasteConfirmOn asks before a paste whose line breaks would submit
+	// commands to a shell.
+	PasteConfirmOn = "on"
+	// PasteConfirmOff pastes without asking. Control characters are still
+	// stripped.
+	PasteConfirmOff = "off"
+)
+
+// sanitizePasteConfirm normalizes paste_confirm in place. Invalid values fall
+// back to the on default with a warning.
+func sanitizePasteConfirm(cfg *Config) {
+	mode := strings.ToLower(strings.TrimSpace(cfg.PasteConfirm))
+	switch mode {
+	case "", PasteConfirmOn, PasteConfirmOff:
+		cfg.PasteConfirm = mode
+	default:
+		slog.Warn("[WARN-CONFIG] paste_confirm is invalid, falling back to on",
+			"configured", cfg.PasteConfirm)
+		cfg.PasteConfirm = ""
+	}
+}
+
+// EffectivePasteConfirm reports whether multi-line pastes into shells need
+// confirmation.
+func EffectivePasteConfirm(cfg Config) bool {
+	return cfg.PasteConfirm != PasteConfirmOff
+}
@@ -0,0 +1,26 @@
+package config
+
+import "testing"
+
+func TestSanitizePasteConfirm(t *testing.T) {
+	tests := []struct {
+		configured string
+		want       string
+		effective  bool
+	}{
+		{configured: "", want: "", effective: true},
+		{configured: " Off ", want: PasteConfirmOff, effective: false},
+		{configured: "on", want: PasteConfirmOn, effective: true},
+		{configured: "always", want: "", effective: true},
+	}
+	for _, tt := range tests {
+		cfg := Config{PasteConfirm: tt.configured}
+		sanitizePasteConfirm(&cfg)
+		if cfg.PasteConfirm != tt.want {
+			t.Errorf("sanitizePasteConfirm(%q) = %q, want %q", tt.configured, cfg.PasteConfirm, tt.want)
+		}
+		if got := EffectivePasteConfirm(cfg); got != tt.effective {
+			t.Errorf("EffectivePasteConfirm(%q) = %v, want %v", tt.configured, got, tt.effective)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// MergePatch applies a JSON merge patch (RFC 7386) to cfg and returns the
+// merged config. A null value removes the key, which resets the field to its
+// default once the config is normalized. Unknown keys are rejected so that
+// typos do not silently disappear. The result is not validated; Save does that.
+func MergePatch(cfg Config, patch []byte) (Config, error) {
+	var patchValue any
+	if err := json.Unmarshal(patch, &patchValue); err != nil {
+		return cfg, fmt.Errorf("config patch: invalid JSON: %w", err)
+	}
+	patchMap, ok := patchValue.(map[string]any)
+	if !ok {
+		return cfg, errors.New("config patch: patch must be a JSON object")
+	}
+
+	current, err := configToJSONMap(cfg)
+	if err != nil {
+		return cfg, fmt.Errorf("config patch: %w", err)
+	}
+	merged, err := json.Marshal(mergePatchValue(current, patchMap))
+	if err != nil {
+		return cfg, fmt.Errorf("config patch: marshal merged config: %w", err)
+	}
+
+	var result Config
+	decoder := json.NewDecoder(bytes.NewReader(merged))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&result); err != nil {
+		return cfg, fmt.Errorf("config patch: %w", err)
+	}
+	return result, nil
+}
+
+// mergePatchValue implements the MergePatch algorithm of RFC 7386.
+func mergePatchValue(target any, patch any) any {
+	patchMap, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	targetMap, ok := target.(map[string]any)
+	if !ok {
+		targetMap = map[string]any{}
+	}
+	for key, value := range patchMap {
+		if value == nil {
+			delete(targetMap, key)
+			continue
+		}
+		targetMap[key] = mergePatchValue(targetMap[key], value)
+	}
+	return targetMap
+}
+
+// jsonValuesEqual compares two decoded JSON values. Absent, empty lists, and
+// empty objects are treated alike, matching DiffConfigs' nil-versus-empty
+// handling.
+func jsonValuesEqual(a, b any) bool {
+	if jsonValueEmpty(a) && jsonValueEmpty(b) {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// lookupJSONPath returns the value at a dotted key path, or nil when any
+// part of the path is absent.
+func lookupJSONPath(values map[string]any, path string) any {
+	var current any = values
+	for key := range strings.SplitSeq(path, ".") {
+		m, ok := current.(map[string]any)
+		if !ok {
+			return nil
+		}
+		current = m[key]
+	}
+	return current
+}
+
+func jsonValueEmpty(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case []any:
+		return len(v) == 0
+	case map[string]any:
+		return len(v) == 0
+	}
+	return false
+}
+
+func joinFieldPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func configToJSONMap(cfg Config) (map[string]any, error) {
+	raw, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("marshal config: %w", err)
+	}
+	var out map[string]any
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return nil, fmt.Errorf("unmarshal config: %w", err)
+	}
+	return out, nil
+}
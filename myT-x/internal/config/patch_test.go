@@ -0,0 +1,122 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMergePatch(t *testing.T) {
+	base := DefaultConfig()
+	base.Shell = "cmd.exe"
+	base.Keys = map[string]string{"split-horizontal": "%", "kill-pane": "x"}
+
+	merged, err := MergePatch(base, []byte(`{
+		"shell": "pwsh.exe",
+		"keys": {"kill-pane": null, "new-window": "c"},
+		"worktree": {"force_cleanup": true}
+	}`))
+	if err != nil {
+		t.Fatalf("MergePatch() error = %v", err)
+	}
+	if merged.Shell != "pwsh.exe" {
+		t.Errorf("Shell = %q, want pwsh.exe", merged.Shell)
+	}
+	if want := map[string]string{"split-horizontal": "%", "new-window": "c"}; !reflect.DeepEqual(merged.Keys, want) {
+		t.Errorf("Keys = %v, want %v", merged.Keys, want)
+	}
+	if !merged.Worktree.ForceCleanup {
+		t.Error("Worktree.ForceCleanup = false, want true")
+	}
+	if merged.Worktree.Enabled != base.Worktree.Enabled || merged.Prefix != base.Prefix {
+		t.Error("fields absent from the patch changed")
+	}
+	if base.Keys["kill-pane"] != "x" {
+		t.Error("MergePatch() mutated the input config")
+	}
+}
+
+func TestMergePatchRejectsInvalidPatches(t *testing.T) {
+	tests := []struct {
+		name  string
+		patch string
+		want  string
+	}{
+		{"invalid JSON", `{"shell":`, "invalid JSON"},
+		{"not an object", `["shell"]`, "must be a JSON object"},
+		{"unknown key", `{"shel": "cmd.exe"}`, "unknown field"},
+		{"unknown nested key", `{"worktree": {"enable": true}}`, "unknown field"},
+		{"wrong type", `{"websocket_port": "80"}`, "websocket_port"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := MergePatch(DefaultConfig(), []byte(tt.patch))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("MergePatch() error = %v, want containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyPatch(t *testing.T) {
+	configPath := newTestConfigPath(t)
+	s := NewStateService()
+	s.Initialize(configPath, DefaultConfig())
+
+	event, err := s.ApplyPatch([]byte(`{"shell": "cmd.exe"}`))
+	if err != nil {
+		t.Fatalf("ApplyPatch() error = %v", err)
+	}
+	if event.Version != 1 || event.Config.Shell != "cmd.exe" {
+		t.Fatalf("event = version %d shell %q, want version 1 shell cmd.exe", event.Version, event.Config.Shell)
+	}
+	if changes := event.Diff.Changes; len(changes) != 1 || changes[0].Key != "shell" {
+		t.Fatalf("event.Diff.Changes = %+v, want one shell change", changes)
+	}
+
+	loaded, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if loaded.Shell != "cmd.exe" {
+		t.Fatalf("persisted Shell = %q, want cmd.exe", loaded.Shell)
+	}
+}
+
+func TestApplyPatchKeepsStateOnValidationError(t *testing.T) {
+	configPath := newTestConfigPath(t)
+	s := NewStateService()
+	s.Initialize(configPath, DefaultConfig())
+
+	if _, err := s.ApplyPatch([]byte(`{"shell": "evil.exe"}`)); err == nil {
+		t.Fatal("ApplyPatch() expected validation error")
+	}
+	if _, err := s.ApplyPatch([]byte(`{"bogus": 1}`)); err == nil {
+		t.Fatal("ApplyPatch() expected unknown key error")
+	}
+	if got := s.EventVersion(); got != 0 {
+		t.Fatalf("EventVersion = %d, want 0", got)
+	}
+	if got := s.Snapshot().Shell; got != DefaultConfig().Shell {
+		t.Fatalf("Shell after failed patches = %q, want default", got)
+	}
+}
+
+func TestApplyPatchNullResetsToDefault(t *testing.T) {
+	configPath := newTestConfigPath(t)
+	s := NewStateService()
+	initial := DefaultConfig()
+	initial.ChatOverlayPercentage = 60
+	s.Initialize(configPath, initial)
+
+	event, err := s.ApplyPatch([]byte(`{"chat_overlay_percentage": null}`))
+	if err != nil {
+		t.Fatalf("ApplyPatch() error = %v", err)
+	}
+	if want := DefaultConfig().ChatOverlayPercentage; event.Config.ChatOverlayPercentage != want {
+		t.Fatalf("ChatOverlayPercentage = %d, want default %d", event.Config.ChatOverlayPercentage, want)
+	}
+	if changes := event.Diff.Changes; len(changes) != 1 || changes[0].Key != "chat_overlay_percentage" {
+		t.Fatalf("event.Diff.Changes = %+v, want chat_overlay_percentage", changes)
+	}
+}
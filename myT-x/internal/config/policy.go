@@ -0,0 +1,201 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// ErrLockedByPolicy reports a save that would change a key locked by the
+// machine policy file.
+var ErrLockedByPolicy = errors.New("config key is locked by policy")
+
+// Policy is the machine-level policy of a managed deployment. Its locked
+// values override the user's config and cannot be changed by Save.
+//
+// The policy file is YAML with a single "locked" mapping written like the
+// config file itself:
+//
+//	locked:
+//	  trusted_shells:
+//	    - path: C:\Tools\bash.exe
+//	  worktree:
+//	    force_cleanup: false
+//
+// Mappings lock only the keys they list; lists and scalar values are locked
+// as a whole.
+type Policy struct {
+	path   string
+	locked map[string]any
+	// lockAll locks every key. Set by FailClosedPolicy.
+	lockAll bool
+}
+
+// lockAllKey is the locked key reported for a fail-closed policy.
+const lockAllKey = "*"
+
+// PolicyInfo describes the active policy for the frontend.
+type PolicyInfo struct {
+	// Path is the policy file; empty when no policy is active.
+	Path string `json:"path"`
+	// LockedKeys are dotted config key paths, e.g. "worktree.force_cleanup".
+	LockedKeys []string `json:"locked_keys"`
+}
+
+// LoadPolicy reads the policy file at path. A missing file or empty path
+// yields an empty policy. Unknown keys and values of the wrong type are
+// rejected so that a broken policy is noticed instead of silently ignored.
+func LoadPolicy(path string) (Policy, error) {
+	if strings.TrimSpace(path) == "" {
+		return Policy{}, nil
+	}
+	raw, err := readLimitedFile(path, maxConfigFileBytes)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Policy{}, nil
+		}
+		return Policy{}, fmt.Errorf("read policy %s: %w", path, err)
+	}
+	var file struct {
+		Locked map[string]any `yaml:"locked"`
+	}
+	if err := yaml.Unmarshal(raw, &file); err != nil {
+		return Policy{}, fmt.Errorf("parse policy %s: %w", path, err)
+	}
+	policy := Policy{path: path, locked: file.Locked}
+	if _, err := policy.apply(DefaultConfig()); err != nil {
+		return Policy{}, fmt.Errorf("policy %s: %w", path, err)
+	}
+	return policy, nil
+}
+
+// FailClosedPolicy returns the policy used when the policy file at path
+// exists but cannot be loaded. Its locked values are unknown, so it locks
+// every key: the config stays as it is until the policy file is fixed.
+func FailClosedPolicy(path string) Policy {
+	return Policy{path: path, lockAll: true}
+}
+
+// Empty reports whether the policy locks nothing.
+func (p Policy) Empty() bool {
+	return !p.lockAll && len(p.locked) == 0
+}
+
+// Info returns the policy path and its locked keys.
+func (p Policy) Info() PolicyInfo {
+	info := PolicyInfo{LockedKeys: []string{}}
+	if p.Empty() {
+		return info
+	}
+	info.Path = p.path
+	if p.lockAll {
+		info.LockedKeys = append(info.LockedKeys, lockAllKey)
+		return info
+	}
+	collectLockedKeys("", p.locked, &info.LockedKeys)
+	sort.Strings(info.LockedKeys)
+	return info
+}
+
+func collectLockedKeys(prefix string, values map[string]any, keys *[]string) {
+	for key, value := range values {
+		path := joinFieldPath(prefix, key)
+		if nested, ok := value.(map[string]any); ok && len(nested) > 0 {
+			collectLockedKeys(path, nested, keys)
+			continue
+		}
+		*keys = append(*keys, path)
+	}
+}
+
+// Enforce overrides the locked keys of cfg with the policy values and
+// normalizes the result. changed lists the key paths whose value differed.
+// A fail-closed policy has no values to enforce and returns cfg unchanged.
+func (p Policy) Enforce(cfg Config) (enforced Config, changed []string, err error) {
+	if p.Empty() || p.lockAll {
+		return cfg, nil, nil
+	}
+	merged, err := p.apply(cfg)
+	if err != nil {
+		return cfg, nil, err
+	}
+	if err := applyDefaultsAndValidate(&merged); err != nil {
+		return cfg, nil, fmt.Errorf("policy %s: %w", p.path, err)
+	}
+	changed, err = p.changedLockedKeys(cfg, merged)
+	if err != nil {
+		return cfg, nil, err
+	}
+	return merged, changed, nil
+}
+
+// CheckSave returns ErrLockedByPolicy when going from previous to updated
+// changes a locked key. Both configs must be normalized.
+func (p Policy) CheckSave(previous, updated Config) error {
+	if p.Empty() {
+		return nil
+	}
+	var violations []string
+	if p.lockAll {
+		for _, change := range DiffConfigs(previous, updated).Changes {
+			violations = append(violations, change.Key)
+		}
+	} else {
+		var err error
+		if violations, err = p.changedLockedKeys(previous, updated); err != nil {
+			return err
+		}
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrLockedByPolicy, strings.Join(violations, ", "))
+}
+
+func (p Policy) apply(cfg Config) (Config, error) {
+	patch, err := json.Marshal(p.locked)
+	if err != nil {
+		return cfg, fmt.Errorf("encode locked values: %w", err)
+	}
+	return MergePatch(cfg, patch)
+}
+
+// changedLockedKeys returns the locked keys whose values differ between old
+// and updated, sorted. DiffConfigs finds the changed top-level keys; a key
+// locked below one of them is then compared at its own path.
+func (p Policy) changedLockedKeys(old, updated Config) ([]string, error) {
+	diff := DiffConfigs(old, updated)
+	if diff.Empty() {
+		return nil, nil
+	}
+	var oldMap, newMap map[string]any
+	var changed []string
+	for _, locked := range p.Info().LockedKeys {
+		top, _, nested := strings.Cut(locked, ".")
+		if !slices.ContainsFunc(diff.Changes, func(change KeyChange) bool { return change.Key == top }) {
+			continue
+		}
+		if nested {
+			if oldMap == nil {
+				var err error
+				if oldMap, err = configToJSONMap(old); err != nil {
+					return nil, err
+				}
+				if newMap, err = configToJSONMap(updated); err != nil {
+					return nil, err
+				}
+			}
+			if jsonValuesEqual(lookupJSONPath(oldMap, locked), lookupJSONPath(newMap, locked)) {
+				continue
+			}
+		}
+		changed = append(changed, locked)
+	}
+	return changed, nil
+}
@@ -0,0 +1,8 @@
+//go:build !windows
+
+package config
+
+// DefaultPolicyPath returns "": machine policies exist only on Windows.
+func DefaultPolicyPath() string {
+	return ""
+}
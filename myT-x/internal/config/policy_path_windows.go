@@ -0,0 +1,21 @@
+//go:build windows
+
+package config
+
+import (
+	"path/filepath"
+
+	"golang.org/x/sys/windows"
+)
+
+// DefaultPolicyPath returns <ProgramData>\myT-x\policy.yaml, or "" when the
+// folder cannot be resolved. ProgramData is resolved through the known-folder
+// API rather than the environment, which any user process can override to
+// point the policy lookup at a file of its own.
+func DefaultPolicyPath() string {
+	base, err := windows.KnownFolderPath(windows.FOLDERID_ProgramData, 0)
+	if err != nil || base == "" {
+		return ""
+	}
+	return filepath.Join(base, "myT-x", "policy.yaml")
+}
@@ -0,0 +1,177 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeTestPolicy(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDefaultPolicyPathIgnoresEnvironment(t *testing.T) {
+	base := t.TempDir()
+	t.Setenv("ProgramData", base)
+	if got := DefaultPolicyPath(); strings.HasPrefix(got, base) {
+		t.Fatalf("DefaultPolicyPath() = %q, want a path not taken from the ProgramData variable", got)
+	}
+}
+
+func TestLoadPolicy(t *testing.T) {
+	for _, path := range []string{"", filepath.Join(t.TempDir(), "missing.yaml")} {
+		policy, err := LoadPolicy(path)
+		if err != nil || !policy.Empty() {
+			t.Fatalf("LoadPolicy(%q) = %+v, %v; want empty policy", path, policy, err)
+		}
+	}
+
+	path := writeTestPolicy(t, "locked:\n  shell: cmd.exe\n  worktree:\n    force_cleanup: false\n  pane_env: null\n")
+	policy, err := LoadPolicy(path)
+	if err != nil {
+		t.Fatalf("LoadPolicy() error = %v", err)
+	}
+	want := PolicyInfo{Path: path, LockedKeys: []string{"pane_env", "shell", "worktree.force_cleanup"}}
+	if got := policy.Info(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Info() = %+v, want %+v", got, want)
+	}
+	if got := (Policy{}).Info(); got.Path != "" || got.LockedKeys == nil || len(got.LockedKeys) != 0 {
+		t.Fatalf("empty Info() = %+v, want empty path and non-nil keys", got)
+	}
+}
+
+func TestLoadPolicyRejectsInvalidFiles(t *testing.T) {
+	tests := map[string]string{
+		"bad yaml":    "locked: [",
+		"unknown key": "locked:\n  shel: cmd.exe\n",
+		"wrong type":  "locked:\n  websocket_port: high\n",
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := LoadPolicy(writeTestPolicy(t, content)); err == nil {
+				t.Fatal("LoadPolicy() error = nil")
+			}
+		})
+	}
+}
+
+func TestPolicyEnforce(t *testing.T) {
+	policy, err := LoadPolicy(writeTestPolicy(t, "locked:\n  shell: cmd.exe\n  worktree:\n    force_cleanup: true\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := DefaultConfig()
+	cfg.Shell = "pwsh.exe"
+	cfg.Worktree.SetupScripts = []string{"npm ci"}
+
+	enforced, changed, err := policy.Enforce(cfg)
+	if err != nil {
+		t.Fatalf("Enforce() error = %v", err)
+	}
+	if enforced.Shell != "cmd.exe" || !enforced.Worktree.ForceCleanup {
+		t.Fatalf("Enforce() shell=%q force_cleanup=%v, want policy values", enforced.Shell, enforced.Worktree.ForceCleanup)
+	}
+	if !reflect.DeepEqual(enforced.Worktree.SetupScripts, []string{"npm ci"}) {
+		t.Fatalf("Enforce() changed unlocked setup_scripts: %v", enforced.Worktree.SetupScripts)
+	}
+	if want := []string{"shell", "worktree.force_cleanup"}; !reflect.DeepEqual(changed, want) {
+		t.Fatalf("Enforce() changed = %v, want %v", changed, want)
+	}
+
+	if _, changed, err := policy.Enforce(enforced); err != nil || len(changed) != 0 {
+		t.Fatalf("Enforce(enforced) = %v, %v; want no changes", changed, err)
+	}
+}
+
+func TestStateServiceRejectsLockedChanges(t *testing.T) {
+	policy, err := LoadPolicy(writeTestPolicy(t, "locked:\n  shell: cmd.exe\n  worktree:\n    force_cleanup: true\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	initial, _, err := policy.Enforce(DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewStateService()
+	s.Initialize(newTestConfigPath(t), initial)
+	s.SetPolicy(policy)
+
+	locked := Clone(initial)
+	locked.Shell = "pwsh.exe"
+	_, err = s.Save(locked)
+	if !errors.Is(err, ErrLockedByPolicy) || !strings.Contains(err.Error(), "shell") {
+		t.Fatalf("Save(locked shell) error = %v, want ErrLockedByPolicy naming shell", err)
+	}
+	if _, err := s.ApplyPatch([]byte(`{"worktree": null}`)); !errors.Is(err, ErrLockedByPolicy) {
+		t.Fatalf("ApplyPatch(worktree: null) error = %v, want ErrLockedByPolicy", err)
+	}
+	if got := s.EventVersion(); got != 0 {
+		t.Fatalf("EventVersion = %d, want 0 after rejected saves", got)
+	}
+
+	if _, err := s.ApplyPatch([]byte(`{"shell": "cmd.exe", "worktree": {"setup_scripts": ["npm ci"]}}`)); err != nil {
+		t.Fatalf("ApplyPatch(unlocked change) error = %v", err)
+	}
+	if got := s.Policy().Info().LockedKeys; !reflect.DeepEqual(got, []string{"shell", "worktree.force_cleanup"}) {
+		t.Fatalf("Policy().Info().LockedKeys = %v", got)
+	}
+}
+
+func TestFailClosedPolicyLocksEveryKey(t *testing.T) {
+	policy := FailClosedPolicy("policy.yaml")
+	if policy.Empty() {
+		t.Fatal("Empty() = true, want a fail-closed policy to lock keys")
+	}
+	if got, want := policy.Info(), (PolicyInfo{Path: "policy.yaml", LockedKeys: []string{"*"}}); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Info() = %+v, want %+v", got, want)
+	}
+	cfg := DefaultConfig()
+	cfg.Shell = "pwsh.exe"
+	if enforced, changed, err := policy.Enforce(cfg); err != nil || len(changed) != 0 || enforced.Shell != "pwsh.exe" {
+		t.Fatalf("Enforce() = %q, %v, %v; want cfg unchanged", enforced.Shell, changed, err)
+	}
+
+	s := NewStateService()
+	s.Initialize(newTestConfigPath(t), cfg)
+	s.SetPolicy(policy)
+	if _, err := s.ApplyPatch([]byte(`{"worktree": {"setup_scripts": ["npm ci"]}}`)); !errors.Is(err, ErrLockedByPolicy) {
+		t.Fatalf("ApplyPatch() error = %v, want ErrLockedByPolicy", err)
+	}
+}
+
+func TestPolicyChangedLockedKeys(t *testing.T) {
+	policy, err := LoadPolicy(writeTestPolicy(t, "locked:\n  shell: cmd.exe\n  worktree:\n    force_cleanup: true\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := DefaultConfig()
+	tests := []struct {
+		name   string
+		update func(*Config)
+		want   []string
+	}{
+		{"unchanged", func(*Config) {}, nil},
+		{"unlocked key", func(cfg *Config) { cfg.ChatOverlayPercentage++ }, nil},
+		{"unlocked nested key", func(cfg *Config) { cfg.Worktree.SetupScripts = []string{"npm ci"} }, nil},
+		{"locked key", func(cfg *Config) { cfg.Shell = "pwsh.exe" }, []string{"shell"}},
+		{"locked nested key", func(cfg *Config) { cfg.Worktree.ForceCleanup = !base.Worktree.ForceCleanup }, []string{"worktree.force_cleanup"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated := Clone(base)
+			tt.update(&updated)
+			got, err := policy.changedLockedKeys(base, updated)
+			if err != nil || !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("changedLockedKeys() = %v, %v; want %v", got, err, tt.want)
+			}
+		})
+	}
+}
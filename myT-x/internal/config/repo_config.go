@@ -0,0 +1,44 @@
+package config
+
+import (
+	"log/slog"
+	"strings"
+
+	"myT-x/internal/sshsig"
+)
+
+// MaxRepoConfigSigningKeys caps repo_config.signing_keys entries.
+const MaxRepoConfigSigningKeys = 20
+
+// sanitizeRepoConfig trims repo_config.signing_keys in place. Keys that do
+// not parse are dropped with a warning; the block is dropped when it carries
+// no key and does not require signatures.
+func sanitizeRepoConfig(cfg *Config) {
+	rc := cfg.RepoConfig
+	if rc == nil {
+		return
+	}
+	keys := make([]string, 0, min(len(rc.SigningKeys), MaxRepoConfigSigningKeys))
+	for i, key := range rc.SigningKeys {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			continue
+		}
+		if _, err := sshsig.ParseAuthorizedKey(key); err != nil {
+			slog.Warn("[WARN-CONFIG] repo_config signing key is invalid, skipping", "index", i, "error", err)
+			continue
+		}
+		if len(keys) == MaxRepoConfigSigningKeys {
+			slog.Warn("[WARN-CONFIG] repo_config signing_keys exceeds limit, truncating", "max", MaxRepoConfigSigningKeys)
+			break
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		keys = nil
+	}
+	rc.SigningKeys = keys
+	if rc.SigningKeys == nil && !rc.RequireSignature {
+		cfg.RepoConfig = nil
+	}
+}
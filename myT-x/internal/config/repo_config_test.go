@@ -0,0 +1,62 @@
+package config
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+const testSigningKey = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIG3mlHicDBLZkem6X7/mGQU6XhGu/e+Hh5lFLiUww+sa team@example"
+
+func TestRepoConfigSettingsFieldCountGuard(t *testing.T) {
+	if got := reflect.TypeFor[RepoConfigSettings]().NumField(); got != 2 {
+		t.Fatalf("RepoConfigSettings field count = %d, want 2; update sanitizeRepoConfig, Clone, and this assertion", got)
+	}
+}
+
+func TestSanitizeRepoConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		in   *RepoConfigSettings
+		want *RepoConfigSettings
+	}{
+		{
+			name: "keys are trimmed and invalid ones dropped",
+			in:   &RepoConfigSettings{SigningKeys: []string{"  " + testSigningKey + "  ", "ssh-ed25519 !!!", ""}},
+			want: &RepoConfigSettings{SigningKeys: []string{testSigningKey}},
+		},
+		{
+			name: "empty block is dropped",
+			in:   &RepoConfigSettings{SigningKeys: []string{"not a key"}},
+			want: nil,
+		},
+		{
+			name: "require_signature alone is kept",
+			in:   &RepoConfigSettings{RequireSignature: true},
+			want: &RepoConfigSettings{RequireSignature: true},
+		},
+		{
+			name: "keys beyond the limit are truncated",
+			in:   &RepoConfigSettings{SigningKeys: slices.Repeat([]string{testSigningKey}, MaxRepoConfigSigningKeys+2)},
+			want: &RepoConfigSettings{SigningKeys: slices.Repeat([]string{testSigningKey}, MaxRepoConfigSigningKeys)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Config{RepoConfig: tt.in}
+			sanitizeRepoConfig(&cfg)
+			if !reflect.DeepEqual(cfg.RepoConfig, tt.want) {
+				t.Fatalf("RepoConfig = %#v, want %#v", cfg.RepoConfig, tt.want)
+			}
+		})
+	}
+}
+
+func TestCloneRepoConfig(t *testing.T) {
+	src := Config{RepoConfig: &RepoConfigSettings{SigningKeys: []string{testSigningKey}}}
+	cloned := Clone(src)
+	cloned.RepoConfig.SigningKeys[0] = "changed"
+	if src.RepoConfig.SigningKeys[0] != testSigningKey {
+		t.Fatalf("source RepoConfig mutated: %q", src.RepoConfig.SigningKeys[0])
+	}
+}
@@ -0,0 +1,94 @@
+package config
+
+import (
+	"log/slog"
+	"strings"
+)
+
+const (
+	// MaxResourceLimitSessions caps resource_limits.sessions entries.
+	MaxResourceLimitSessions = 100
+
+	// MinResourceLimitMemoryMB is the smallest memory limit accepted; a
+	// lower limit would keep the shell itself from starting programs.
+	MinResourceLimitMemoryMB = 64
+)
+
+// IsZero reports whether no limit is set.
+func (l ResourceLimits) IsZero() bool {
+	return l.MemoryMB == 0 && l.CPUPercent == 0
+}
+
+// ForSession returns the limits for sessionName: the session entry when one
+// exists, otherwise the defaults. A nil receiver yields no limits.
+func (c *ResourceLimitsConfig) ForSession(sessionName string) ResourceLimits {
+	if c == nil {
+		return ResourceLimits{}
+	}
+	if limits, ok := c.Sessions[sessionName]; ok {
+		return limits
+	}
+	return ResourceLimits{MemoryMB: c.MemoryMB, CPUPercent: c.CPUPercent}
+}
+
+// sanitizeResourceLimits validates resource_limits in place. Out-of-range
+// values are clamped with a warning; session entries with an empty name are
+// dropped; the block is dropped when nothing remains.
+func sanitizeResourceLimits(cfg *Config) {
+	rl := cfg.ResourceLimits
+	if rl == nil {
+		return
+	}
+	global := sanitizeResourceLimitSet("defaults", ResourceLimits{MemoryMB: rl.MemoryMB, CPUPercent: rl.CPUPercent})
+	rl.MemoryMB, rl.CPUPercent = global.MemoryMB, global.CPUPercent
+
+	if len(rl.Sessions) > 0 {
+		sessions := make(map[string]ResourceLimits, len(rl.Sessions))
+		for name, limits := range rl.Sessions {
+			trimmed := strings.TrimSpace(name)
+			if trimmed == "" {
+				slog.Warn("[WARN-CONFIG] resource_limits session entry has no name, ignoring")
+				continue
+			}
+			if len(sessions) >= MaxResourceLimitSessions {
+				slog.Warn("[WARN-CONFIG] resource_limits.sessions exceeds limit, ignoring extra entries",
+					"max", MaxResourceLimitSessions)
+				break
+			}
+			// A session entry with no limits is kept: it exempts the
+			// session from the defaults.
+			sessions[trimmed] = sanitizeResourceLimitSet("sessions."+trimmed, limits)
+		}
+		rl.Sessions = sessions
+	}
+	if len(rl.Sessions) == 0 {
+		rl.Sessions = nil
+	}
+	if global.IsZero() && rl.Sessions == nil {
+		cfg.ResourceLimits = nil
+	}
+}
+
+func sanitizeResourceLimitSet(scope string, limits ResourceLimits) ResourceLimits {
+	switch {
+	case limits.MemoryMB < 0:
+		slog.Warn("[WARN-CONFIG] resource_limits memory_mb must not be negative, ignoring",
+			"scope", scope, "memoryMB", limits.MemoryMB)
+		limits.MemoryMB = 0
+	case limits.MemoryMB > 0 && limits.MemoryMB < MinResourceLimitMemoryMB:
+		slog.Warn("[WARN-CONFIG] resource_limits memory_mb is below the minimum, raising it",
+			"scope", scope, "memoryMB", limits.MemoryMB, "min", MinResourceLimitMemoryMB)
+		limits.MemoryMB = MinResourceLimitMemoryMB
+	}
+	switch {
+	case limits.CPUPercent < 0:
+		slog.Warn("[WARN-CONFIG] resource_limits cpu_percent must not be negative, ignoring",
+			"scope", scope, "cpuPercent", limits.CPUPercent)
+		limits.CPUPercent = 0
+	case limits.CPUPercent > 100:
+		slog.Warn("[WARN-CONFIG] resource_limits cpu_percent exceeds 100, capping it",
+			"scope", scope, "cpuPercent", limits.CPUPercent)
+		limits.CPUPercent = 100
+	}
+	return limits
+}
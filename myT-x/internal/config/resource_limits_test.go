@@ -0,0 +1,59 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSanitizeResourceLimits(t *testing.T) {
+	cfg := Config{ResourceLimits: &ResourceLimitsConfig{
+		MemoryMB:   10,
+		CPUPercent: 150,
+		Sessions: map[string]ResourceLimits{
+			" agent ": {MemoryMB: -1, CPUPercent: 25},
+			"":        {MemoryMB: 512},
+			"free":    {},
+		},
+	}}
+	sanitizeResourceLimits(&cfg)
+	want := &ResourceLimitsConfig{
+		MemoryMB:   MinResourceLimitMemoryMB,
+		CPUPercent: 100,
+		Sessions: map[string]ResourceLimits{
+			"agent": {CPUPercent: 25},
+			"free":  {},
+		},
+	}
+	if !reflect.DeepEqual(cfg.ResourceLimits, want) {
+		t.Fatalf("sanitized = %+v, want %+v", cfg.ResourceLimits, want)
+	}
+
+	cfg = Config{ResourceLimits: &ResourceLimitsConfig{CPUPercent: -5, Sessions: map[string]ResourceLimits{}}}
+	sanitizeResourceLimits(&cfg)
+	if cfg.ResourceLimits != nil {
+		t.Fatalf("sanitized = %+v, want the empty block dropped", cfg.ResourceLimits)
+	}
+	sanitizeResourceLimits(&Config{})
+}
+
+func TestResourceLimitsForSession(t *testing.T) {
+	var nilConfig *ResourceLimitsConfig
+	if !nilConfig.ForSession("a").IsZero() {
+		t.Fatal("nil config should yield no limits")
+	}
+	rl := &ResourceLimitsConfig{
+		MemoryMB:   2048,
+		CPUPercent: 50,
+		Sessions:   map[string]ResourceLimits{"agent": {CPUPercent: 10}, "free": {}},
+	}
+	tests := map[string]ResourceLimits{
+		"other": {MemoryMB: 2048, CPUPercent: 50},
+		"agent": {CPUPercent: 10},
+		"free":  {},
+	}
+	for session, want := range tests {
+		if got := rl.ForSession(session); got != want {
+			t.Fatalf("ForSession(%q) = %+v, want %+v", session, got, want)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package config
+
+import (
+	"errors"
+	"log/slog"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// MaxSessionBadgeRules caps session_badge_rules entries.
+	MaxSessionBadgeRules = 50
+	// MaxSessionBadgeEmojiRunes caps badge emoji length. Multi-codepoint
+	// emoji (skin tones, ZWJ sequences) need more than one rune.
+	MaxSessionBadgeEmojiRunes = 8
+)
+
+var sessionBadgeColorPattern = regexp.MustCompile(`^#(?:[0-9a-f]{3}|[0-9a-f]{6})$`)
+
+// SessionBadgeFacts is the session state that session_badge_rules match against.
+type SessionBadgeFacts struct {
+	RepoPath string
+	Branch   string
+	Dirty    bool
+}
+
+// NormalizeSessionBadge trims and validates a badge color and emoji.
+// Color must be empty or a #rgb / #rrggbb hex value and is lowercased.
+// Emoji must be empty or at most MaxSessionBadgeEmojiRunes runes without
+// whitespace or control characters.
+func NormalizeSessionBadge(color, emoji string) (string, string, error) {
+	color = strings.ToLower(strings.TrimSpace(color))
+	emoji = strings.TrimSpace(emoji)
+	if color != "" && !sessionBadgeColorPattern.MatchString(color) {
+		return "", "", errors.New("badge color must be a #rgb or #rrggbb hex value")
+	}
+	if utf8.RuneCountInString(emoji) > MaxSessionBadgeEmojiRunes {
+		return "", "", errors.New("badge emoji is too long")
+	}
+	for _, r := range emoji {
+		if unicode.IsControl(r) || unicode.IsSpace(r) {
+			return "", "", errors.New("badge emoji must not contain whitespace or control characters")
+		}
+	}
+	return color, emoji, nil
+}
+
+// sanitizeSessionBadgeRules validates session_badge_rules entries in place.
+// Entries without a matcher, without a badge, or with an invalid badge are
+// dropped with a warning so that a single bad rule never blocks startup.
+func sanitizeSessionBadgeRules(cfg *Config) {
+	if len(cfg.SessionBadgeRules) == 0 {
+		cfg.SessionBadgeRules = nil
+		return
+	}
+
+	filtered := make([]SessionBadgeRule, 0, min(len(cfg.SessionBadgeRules), MaxSessionBadgeRules))
+	for i, rule := range cfg.SessionBadgeRules {
+		rule.BranchPrefix = strings.TrimSpace(rule.BranchPrefix)
+		rule.Repo = strings.TrimSpace(rule.Repo)
+		if rule.BranchPrefix == "" && rule.Repo == "" && !rule.Dirty {
+			slog.Warn("[WARN-CONFIG] session_badge_rules entry has no matcher, skipping", "index", i)
+			continue
+		}
+		color, emoji, err := NormalizeSessionBadge(rule.Color, rule.Emoji)
+		if err != nil {
+			slog.Warn("[WARN-CONFIG] session_badge_rules entry has invalid badge, skipping",
+				"index", i, "error", err)
+			continue
+		}
+		if color == "" && emoji == "" {
+			slog.Warn("[WARN-CONFIG] session_badge_rules entry has neither color nor emoji, skipping", "index", i)
+			continue
+		}
+		rule.Color = color
+		rule.Emoji = emoji
+		if rule.Repo != "" && filepath.IsAbs(rule.Repo) {
+			rule.Repo = filepath.Clean(rule.Repo)
+		}
+
+		filtered = append(filtered, rule)
+		if len(filtered) == MaxSessionBadgeRules {
+			if i < len(cfg.SessionBadgeRules)-1 {
+				slog.Warn("[WARN-CONFIG] session_badge_rules exceeds maximum, truncating",
+					"count", len(cfg.SessionBadgeRules), "max", MaxSessionBadgeRules)
+			}
+			break
+		}
+	}
+	if len(filtered) == 0 {
+		cfg.SessionBadgeRules = nil
+		return
+	}
+	cfg.SessionBadgeRules = filtered
+}
+
+// SessionBadgeRulesNeedDirty reports whether any rule matches on dirty state,
+// letting callers skip the git status call when no rule needs it.
+func SessionBadgeRulesNeedDirty(cfg Config) bool {
+	for _, rule := range cfg.SessionBadgeRules {
+		if rule.Dirty {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveSessionBadge returns the badge of the first session_badge_rules
+// entry matching facts. ok is false when no rule matches.
+//
+// cfg is expected to be normalized by Load/Save.
+func ResolveSessionBadge(cfg Config, facts SessionBadgeFacts) (rule SessionBadgeRule, ok bool) {
+	repoRoot := ""
+	if facts.RepoPath != "" {
+		repoRoot = filepath.Clean(facts.RepoPath)
+	}
+	for _, rule := range cfg.SessionBadgeRules {
+		if rule.BranchPrefix != "" && (facts.Branch == "" || !strings.HasPrefix(facts.Branch, rule.BranchPrefix)) {
+			continue
+		}
+		if rule.Repo != "" && !shellRuleRepoMatches(rule.Repo, repoRoot) {
+			continue
+		}
+		if rule.Dirty && !facts.Dirty {
+			continue
+		}
+		return rule, true
+	}
+	return SessionBadgeRule{}, false
+}
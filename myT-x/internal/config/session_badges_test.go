@@ -0,0 +1,144 @@
+package config
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSessionBadgeRuleFieldCountGuard(t *testing.T) {
+	if got := reflect.TypeFor[SessionBadgeRule]().NumField(); got != 5 {
+		t.Fatalf("SessionBadgeRule field count = %d, want 5; update sanitizeSessionBadgeRules, ResolveSessionBadge, and this assertion", got)
+	}
+}
+
+func TestNormalizeSessionBadge(t *testing.T) {
+	tests := []struct {
+		name      string
+		color     string
+		emoji     string
+		wantColor string
+		wantEmoji string
+		wantErr   bool
+	}{
+		{name: "empty badge", color: "", emoji: ""},
+		{name: "long hex lowercased", color: " #FF8800 ", wantColor: "#ff8800"},
+		{name: "short hex", color: "#abc", wantColor: "#abc"},
+		{name: "emoji trimmed", emoji: " 🚀 ", wantEmoji: "🚀"},
+		{name: "zwj emoji allowed", emoji: "👩‍💻", wantEmoji: "👩‍💻"},
+		{name: "named color rejected", color: "red", wantErr: true},
+		{name: "css injection rejected", color: "#fff;background:url(x)", wantErr: true},
+		{name: "emoji too long", emoji: "abcdefghi", wantErr: true},
+		{name: "emoji with space", emoji: "a b", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			color, emoji, err := NormalizeSessionBadge(tt.color, tt.emoji)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NormalizeSessionBadge() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if color != tt.wantColor || emoji != tt.wantEmoji {
+				t.Fatalf("NormalizeSessionBadge() = (%q, %q), want (%q, %q)", color, emoji, tt.wantColor, tt.wantEmoji)
+			}
+		})
+	}
+}
+
+func TestSanitizeSessionBadgeRules(t *testing.T) {
+	cfg := Config{SessionBadgeRules: []SessionBadgeRule{
+		{BranchPrefix: " feature/ ", Color: "#0F0"},
+		{Color: "#fff"},              // no matcher
+		{Repo: "app"},                // no badge
+		{Dirty: true, Color: "blue"}, // invalid color
+		{Repo: "app", Dirty: true, Emoji: "✏️"},
+	}}
+	sanitizeSessionBadgeRules(&cfg)
+	want := []SessionBadgeRule{
+		{BranchPrefix: "feature/", Color: "#0f0"},
+		{Repo: "app", Dirty: true, Emoji: "✏️"},
+	}
+	if !reflect.DeepEqual(cfg.SessionBadgeRules, want) {
+		t.Fatalf("SessionBadgeRules = %+v, want %+v", cfg.SessionBadgeRules, want)
+	}
+
+	cfg = Config{SessionBadgeRules: []SessionBadgeRule{{Color: "#fff"}}}
+	sanitizeSessionBadgeRules(&cfg)
+	if cfg.SessionBadgeRules != nil {
+		t.Fatalf("all-invalid rules = %+v, want nil", cfg.SessionBadgeRules)
+	}
+}
+
+func TestSanitizeSessionBadgeRulesTruncates(t *testing.T) {
+	rules := make([]SessionBadgeRule, MaxSessionBadgeRules+5)
+	for i := range rules {
+		rules[i] = SessionBadgeRule{Dirty: true, Color: "#000"}
+	}
+	cfg := Config{SessionBadgeRules: rules}
+	sanitizeSessionBadgeRules(&cfg)
+	if len(cfg.SessionBadgeRules) != MaxSessionBadgeRules {
+		t.Fatalf("len(SessionBadgeRules) = %d, want %d", len(cfg.SessionBadgeRules), MaxSessionBadgeRules)
+	}
+}
+
+func TestResolveSessionBadge(t *testing.T) {
+	repoRoot := filepath.Join(t.TempDir(), "Backend")
+	cfg := Config{SessionBadgeRules: []SessionBadgeRule{
+		{Dirty: true, BranchPrefix: "hotfix/", Emoji: "🔥"},
+		{BranchPrefix: "hotfix/", Color: "#f00"},
+		{Repo: "backend", Color: "#00f"},
+		{Dirty: true, Emoji: "✏️"},
+	}}
+
+	tests := []struct {
+		name   string
+		facts  SessionBadgeFacts
+		want   SessionBadgeRule
+		wantOK bool
+	}{
+		{
+			name:   "dirty hotfix picks first rule",
+			facts:  SessionBadgeFacts{RepoPath: repoRoot, Branch: "hotfix/login", Dirty: true},
+			want:   cfg.SessionBadgeRules[0],
+			wantOK: true,
+		},
+		{
+			name:   "clean hotfix skips dirty rule",
+			facts:  SessionBadgeFacts{RepoPath: repoRoot, Branch: "hotfix/login"},
+			want:   cfg.SessionBadgeRules[1],
+			wantOK: true,
+		},
+		{
+			name:   "repo base name is case-insensitive",
+			facts:  SessionBadgeFacts{RepoPath: repoRoot, Branch: "main"},
+			want:   cfg.SessionBadgeRules[2],
+			wantOK: true,
+		},
+		{
+			name:   "dirty outside repo",
+			facts:  SessionBadgeFacts{Dirty: true},
+			want:   cfg.SessionBadgeRules[3],
+			wantOK: true,
+		},
+		{
+			name:  "no match",
+			facts: SessionBadgeFacts{Branch: "main"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ResolveSessionBadge(cfg, tt.facts)
+			if ok != tt.wantOK || got != tt.want {
+				t.Fatalf("ResolveSessionBadge() = (%+v, %v), want (%+v, %v)", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+	if !SessionBadgeRulesNeedDirty(cfg) {
+		t.Fatal("SessionBadgeRulesNeedDirty() = false, want true")
+	}
+	if SessionBadgeRulesNeedDirty(Config{SessionBadgeRules: cfg.SessionBadgeRules[1:3]}) {
+		t.Fatal("SessionBadgeRulesNeedDirty() = true for rules without dirty, want false")
+	}
+}
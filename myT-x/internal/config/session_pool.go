@@ -0,0 +1,36 @@
+package config
+
+import "log/slog"
+
+const (
+	// MaxSessionPoolSize caps session_pool.size. Every warm shell is a live
+	// process, so the pool stays small.
+	MaxSessionPoolSize = 4
+	// MaxSessionPoolIdleMinutes caps session_pool.max_idle_minutes (one day).
+	MaxSessionPoolIdleMinutes = 24 * 60
+)
+
+// sanitizeSessionPool clamps session_pool values in place. The block is
+// dropped when the pool size is zero, which disables the pool.
+func sanitizeSessionPool(cfg *Config) {
+	pool := cfg.SessionPool
+	if pool == nil {
+		return
+	}
+	if pool.Size > MaxSessionPoolSize {
+		slog.Warn("[WARN-CONFIG] session_pool size exceeds limit, clamping",
+			"size", pool.Size, "max", MaxSessionPoolSize)
+		pool.Size = MaxSessionPoolSize
+	}
+	if pool.MaxIdleMinutes < 0 {
+		pool.MaxIdleMinutes = 0
+	}
+	if pool.MaxIdleMinutes > MaxSessionPoolIdleMinutes {
+		slog.Warn("[WARN-CONFIG] session_pool max_idle_minutes exceeds limit, clamping",
+			"maxIdleMinutes", pool.MaxIdleMinutes, "max", MaxSessionPoolIdleMinutes)
+		pool.MaxIdleMinutes = MaxSessionPoolIdleMinutes
+	}
+	if pool.Size <= 0 {
+		cfg.SessionPool = nil
+	}
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSessionPoolConfigFieldCountGuard(t *testing.T) {
+	if got := reflect.TypeFor[SessionPoolConfig]().NumField(); got != 2 {
+		t.Fatalf("SessionPoolConfig field count = %d, want 2; update sanitizeSessionPool, Clone, and this assertion", got)
+	}
+}
+
+func TestSanitizeSessionPool(t *testing.T) {
+	tests := []struct {
+		name string
+		in   *SessionPoolConfig
+		want *SessionPoolConfig
+	}{
+		{name: "nil stays nil", in: nil, want: nil},
+		{
+			name: "valid values kept",
+			in:   &SessionPoolConfig{Size: 2, MaxIdleMinutes: 30},
+			want: &SessionPoolConfig{Size: 2, MaxIdleMinutes: 30},
+		},
+		{
+			name: "oversized values clamped",
+			in:   &SessionPoolConfig{Size: 50, MaxIdleMinutes: MaxSessionPoolIdleMinutes + 1},
+			want: &SessionPoolConfig{Size: MaxSessionPoolSize, MaxIdleMinutes: MaxSessionPoolIdleMinutes},
+		},
+		{
+			name: "negative idle resets to zero",
+			in:   &SessionPoolConfig{Size: 1, MaxIdleMinutes: -5},
+			want: &SessionPoolConfig{Size: 1},
+		},
+		{
+			name: "zero size disables the pool",
+			in:   &SessionPoolConfig{MaxIdleMinutes: 10},
+			want: nil,
+		},
+		{
+			name: "negative size disables the pool",
+			in:   &SessionPoolConfig{Size: -1},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Config{SessionPool: tt.in}
+			sanitizeSessionPool(&cfg)
+			if !reflect.DeepEqual(cfg.SessionPool, tt.want) {
+				t.Fatalf("SessionPool = %#v, want %#v", cfg.SessionPool, tt.want)
+			}
+		})
+	}
+}
+
+func TestCloneSessionPool(t *testing.T) {
+	src := Config{SessionPool: &SessionPoolConfig{Size: 2}}
+	cloned := Clone(src)
+	cloned.SessionPool.Size = 3
+	if src.SessionPool.Size != 2 {
+		t.Fatalf("source SessionPool mutated: %d", src.SessionPool.Size)
+	}
+}
@@ -0,0 +1,217 @@
+package config
+
+import (
+	"log/slog"
+	"os"
+	"strings"
+)
+
+const (
+	// MaxSessionTemplates caps session_templates entries.
+	MaxSessionTemplates = 50
+	// MaxSessionTemplatePanes caps the panes of one template.
+	MaxSessionTemplatePanes = 16
+	// MaxSessionTemplateLayoutLen bounds the layout of one template.
+	MaxSessionTemplateLayoutLen = 4096
+
+	// SessionTemplateSplitHorizontal and SessionTemplateSplitVertical are the
+	// split values of a template pane.
+	SessionTemplateSplitHorizontal = "horizontal"
+	SessionTemplateSplitVertical   = "vertical"
+
+	// DefaultHealthIntervalSeconds and DefaultHealthTimeoutSeconds apply when
+	// a health probe leaves interval_seconds or timeout_seconds at 0.
+	DefaultHealthIntervalSeconds = 1
+	DefaultHealthTimeoutSeconds  = 60
+	// MaxHealthIntervalSeconds and MaxHealthTimeoutSeconds bound health probes.
+	MaxHealthIntervalSeconds = 60
+	MaxHealthTimeoutSeconds  = 3600
+)
+
+// sanitizeSessionTemplates validates session_templates entries in place.
+// Entries with an invalid name, directory, or command are dropped with a
+// warning. Unknown depends_on names are kept so that bring-up reports them
+// instead of silently starting a session without its dependency.
+func sanitizeSessionTemplates(cfg *Config) {
+	if len(cfg.SessionTemplates) == 0 {
+		cfg.SessionTemplates = nil
+		return
+	}
+
+	filtered := make([]SessionTemplate, 0, min(len(cfg.SessionTemplates), MaxSessionTemplates))
+	seen := make(map[string]struct{}, len(cfg.SessionTemplates))
+	for i, template := range cfg.SessionTemplates {
+		if len(filtered) >= MaxSessionTemplates {
+			slog.Warn("[WARN-CONFIG] session_templates exceeds limit, ignoring extra entries",
+				"max", MaxSessionTemplates)
+			break
+		}
+		template, ok := sanitizeSessionTemplate(i, template)
+		if !ok {
+			continue
+		}
+		if _, dup := seen[template.Name]; dup {
+			slog.Warn("[WARN-CONFIG] session_templates entry has a duplicate name, ignoring",
+				"index", i, "name", template.Name)
+			continue
+		}
+		seen[template.Name] = struct{}{}
+		filtered = append(filtered, template)
+	}
+	if len(filtered) == 0 {
+		filtered = nil
+	}
+	cfg.SessionTemplates = filtered
+}
+
+func sanitizeSessionTemplate(index int, template SessionTemplate) (SessionTemplate, bool) {
+	template.Name = strings.TrimSpace(template.Name)
+	if template.Name == "" || strings.ContainsAny(template.Name, ".:") {
+		// tmux rejects '.' and ':' in session names.
+		slog.Warn("[WARN-CONFIG] session_templates entry has an invalid name, ignoring",
+			"index", index, "name", template.Name)
+		return SessionTemplate{}, false
+	}
+
+	template.Dir = strings.TrimSpace(template.Dir)
+	if template.Dir != "" {
+		dir, err := expandSessionDir(os.UserHomeDir, template.Dir)
+		if err != nil {
+			slog.Warn("[WARN-CONFIG] session_templates entry has an invalid dir, ignoring",
+				"name", template.Name, "dir", template.Dir, "error", err)
+			return SessionTemplate{}, false
+		}
+		template.Dir = dir
+	}
+
+	template.Command = NormalizeStartupCommand(template.Command)
+	if !IsValidStartupCommand(template.Command) {
+		slog.Warn("[WARN-CONFIG] session_templates entry has an invalid command, ignoring",
+			"name", template.Name, "maxLen", MaxStartupCommandLen)
+		return SessionTemplate{}, false
+	}
+
+	template.DependsOn = sanitizeTemplateDependsOn(template.Name, template.DependsOn)
+
+	if template.Health != nil {
+		health, ok := sanitizeSessionHealthProbe(template.Name, *template.Health)
+		if !ok {
+			return SessionTemplate{}, false
+		}
+		template.Health = health
+	}
+
+	template.Env = sanitizeEnvMap(template.Env, "session_templates.env")
+	panes, ok := sanitizeSessionTemplatePanes(template.Name, template.Panes)
+	if !ok {
+		return SessionTemplate{}, false
+	}
+	template.Panes = panes
+
+	// The layout syntax is checked when the session is created; config cannot
+	// depend on the tmux package.
+	template.Layout = strings.TrimSpace(template.Layout)
+	if len(template.Layout) > MaxSessionTemplateLayoutLen || strings.ContainsAny(template.Layout, "\r\n") {
+		slog.Warn("[WARN-CONFIG] session_templates entry has an invalid layout, ignoring layout",
+			"name", template.Name, "maxLen", MaxSessionTemplateLayoutLen)
+		template.Layout = ""
+	}
+	return template, true
+}
+
+// sanitizeSessionTemplatePanes normalizes the additional panes of a template.
+// An invalid pane command drops the whole template: a session missing one of
+// its panes would silently shift the layout. Unknown split values fall back
+// to vertical.
+func sanitizeSessionTemplatePanes(name string, panes []SessionTemplatePane) ([]SessionTemplatePane, bool) {
+	if len(panes) == 0 {
+		return nil, true
+	}
+	if len(panes) > MaxSessionTemplatePanes {
+		slog.Warn("[WARN-CONFIG] session_templates panes exceeds limit, ignoring extra panes",
+			"name", name, "max", MaxSessionTemplatePanes)
+		panes = panes[:MaxSessionTemplatePanes]
+	}
+	filtered := make([]SessionTemplatePane, 0, len(panes))
+	for i, pane := range panes {
+		pane.Command = NormalizeStartupCommand(pane.Command)
+		if !IsValidStartupCommand(pane.Command) {
+			slog.Warn("[WARN-CONFIG] session_templates pane has an invalid command, ignoring template",
+				"name", name, "pane", i, "maxLen", MaxStartupCommandLen)
+			return nil, false
+		}
+		pane.Env = sanitizeEnvMap(pane.Env, "session_templates.panes.env")
+		switch split := strings.ToLower(strings.TrimSpace(pane.Split)); split {
+		case SessionTemplateSplitHorizontal, SessionTemplateSplitVertical:
+			pane.Split = split
+		case "":
+			pane.Split = SessionTemplateSplitVertical
+		default:
+			slog.Warn("[WARN-CONFIG] session_templates pane has an invalid split, using vertical",
+				"name", name, "pane", i, "split", pane.Split)
+			pane.Split = SessionTemplateSplitVertical
+		}
+		filtered = append(filtered, pane)
+	}
+	return filtered, true
+}
+
+// sanitizeTemplateDependsOn trims and deduplicates dependency names and
+// drops self-references.
+func sanitizeTemplateDependsOn(name string, dependsOn []string) []string {
+	if len(dependsOn) == 0 {
+		return nil
+	}
+	filtered := make([]string, 0, len(dependsOn))
+	seen := make(map[string]struct{}, len(dependsOn))
+	for _, dep := range dependsOn {
+		dep = strings.TrimSpace(dep)
+		if dep == "" {
+			continue
+		}
+		if dep == name {
+			slog.Warn("[WARN-CONFIG] session_templates entry depends on itself, ignoring dependency", "name", name)
+			continue
+		}
+		if _, dup := seen[dep]; dup {
+			continue
+		}
+		seen[dep] = struct{}{}
+		filtered = append(filtered, dep)
+	}
+	if len(filtered) == 0 {
+		return nil
+	}
+	return filtered
+}
+
+// sanitizeSessionHealthProbe returns nil when the probe checks nothing.
+// An invalid port or command drops the whole template: starting dependents
+// without a working probe would defeat the dependency.
+func sanitizeSessionHealthProbe(name string, health SessionHealthProbe) (*SessionHealthProbe, bool) {
+	if health.Port < 0 || health.Port > 65535 {
+		slog.Warn("[WARN-CONFIG] session_templates health port is out of range, ignoring template",
+			"name", name, "port", health.Port)
+		return nil, false
+	}
+	health.Command = NormalizeStartupCommand(health.Command)
+	if !IsValidStartupCommand(health.Command) {
+		slog.Warn("[WARN-CONFIG] session_templates health command is invalid, ignoring template",
+			"name", name, "maxLen", MaxStartupCommandLen)
+		return nil, false
+	}
+	if health.Port == 0 && health.Command == "" {
+		return nil, true
+	}
+	if health.IntervalSeconds < 0 || health.IntervalSeconds > MaxHealthIntervalSeconds {
+		slog.Warn("[WARN-CONFIG] session_templates health interval_seconds is out of range, using default",
+			"name", name, "value", health.IntervalSeconds, "default", DefaultHealthIntervalSeconds)
+		health.IntervalSeconds = 0
+	}
+	if health.TimeoutSeconds < 0 || health.TimeoutSeconds > MaxHealthTimeoutSeconds {
+		slog.Warn("[WARN-CONFIG] session_templates health timeout_seconds is out of range, using default",
+			"name", name, "value", health.TimeoutSeconds, "default", DefaultHealthTimeoutSeconds)
+		health.TimeoutSeconds = 0
+	}
+	return &health, true
+}
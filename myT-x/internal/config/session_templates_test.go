@@ -0,0 +1,149 @@
+package config
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSessionTemplateFieldCountGuard(t *testing.T) {
+	if got := reflect.TypeFor[SessionTemplate]().NumField(); got != 8 {
+		t.Fatalf("SessionTemplate field count = %d, want 8; update sanitizeSessionTemplate, cloneSessionTemplates, and this assertion", got)
+	}
+	if got := reflect.TypeFor[SessionTemplatePane]().NumField(); got != 3 {
+		t.Fatalf("SessionTemplatePane field count = %d, want 3; update sanitizeSessionTemplatePanes, cloneSessionTemplates, and this assertion", got)
+	}
+	if got := reflect.TypeFor[SessionHealthProbe]().NumField(); got != 4 {
+		t.Fatalf("SessionHealthProbe field count = %d, want 4; update sanitizeSessionHealthProbe and this assertion", got)
+	}
+}
+
+func TestSanitizeSessionTemplates(t *testing.T) {
+	absDir := t.TempDir()
+	tests := []struct {
+		name string
+		in   []SessionTemplate
+		want []SessionTemplate
+	}{
+		{name: "empty becomes nil", in: []SessionTemplate{}, want: nil},
+		{
+			name: "trims fields and dependencies",
+			in: []SessionTemplate{{
+				Name:      " frontend ",
+				Dir:       absDir,
+				Command:   " npm run dev ",
+				DependsOn: []string{" backend ", "backend", "", "frontend"},
+			}},
+			want: []SessionTemplate{{
+				Name:      "frontend",
+				Dir:       filepath.Clean(absDir),
+				Command:   "npm run dev",
+				DependsOn: []string{"backend"},
+			}},
+		},
+		{
+			name: "invalid names and duplicates are dropped",
+			in: []SessionTemplate{
+				{Name: "api"},
+				{Name: "api"},
+				{Name: "bad.name"},
+				{Name: "  "},
+			},
+			want: []SessionTemplate{{Name: "api"}},
+		},
+		{
+			name: "relative dir drops the template",
+			in:   []SessionTemplate{{Name: "api", Dir: "relative/path"}},
+			want: nil,
+		},
+		{
+			name: "multi-line command drops the template",
+			in:   []SessionTemplate{{Name: "api", Command: "a\nb"}},
+			want: nil,
+		},
+		{
+			name: "empty health probe becomes nil",
+			in:   []SessionTemplate{{Name: "api", Health: &SessionHealthProbe{TimeoutSeconds: 5}}},
+			want: []SessionTemplate{{Name: "api"}},
+		},
+		{
+			name: "out-of-range timing falls back to defaults",
+			in: []SessionTemplate{{Name: "api", Health: &SessionHealthProbe{
+				Port: 8080, IntervalSeconds: -1, TimeoutSeconds: MaxHealthTimeoutSeconds + 1,
+			}}},
+			want: []SessionTemplate{{Name: "api", Health: &SessionHealthProbe{Port: 8080}}},
+		},
+		{
+			name: "normalizes panes, env, and layout",
+			in: []SessionTemplate{{
+				Name: "dev",
+				Env:  map[string]string{" APP_ENV ": " dev ", "BAD=KEY": "x"},
+				Panes: []SessionTemplatePane{
+					{Command: " npm run dev ", Split: " Horizontal "},
+					{Env: map[string]string{"PORT": "3000"}, Split: "diagonal"},
+				},
+				Layout: " tiled ",
+			}},
+			want: []SessionTemplate{{
+				Name: "dev",
+				Env:  map[string]string{"APP_ENV": "dev"},
+				Panes: []SessionTemplatePane{
+					{Command: "npm run dev", Split: SessionTemplateSplitHorizontal},
+					{Env: map[string]string{"PORT": "3000"}, Split: SessionTemplateSplitVertical},
+				},
+				Layout: "tiled",
+			}},
+		},
+		{
+			name: "multi-line pane command drops the template",
+			in:   []SessionTemplate{{Name: "api", Panes: []SessionTemplatePane{{Command: "a\nb"}}}},
+			want: nil,
+		},
+		{
+			name: "multi-line layout is cleared",
+			in:   []SessionTemplate{{Name: "api", Layout: "tiled\nx"}},
+			want: []SessionTemplate{{Name: "api"}},
+		},
+		{
+			name: "invalid port drops the template",
+			in:   []SessionTemplate{{Name: "api", Health: &SessionHealthProbe{Port: 70000}}},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Config{SessionTemplates: tt.in}
+			sanitizeSessionTemplates(&cfg)
+			if !reflect.DeepEqual(cfg.SessionTemplates, tt.want) {
+				t.Fatalf("SessionTemplates = %#v, want %#v", cfg.SessionTemplates, tt.want)
+			}
+		})
+	}
+}
+
+func TestCloneSessionTemplates(t *testing.T) {
+	src := Config{SessionTemplates: []SessionTemplate{{
+		Name:      "frontend",
+		DependsOn: []string{"backend"},
+		Health:    &SessionHealthProbe{Port: 3000},
+		Env:       map[string]string{"APP_ENV": "dev"},
+		Panes:     []SessionTemplatePane{{Env: map[string]string{"PORT": "3000"}}},
+	}}}
+	dst := Clone(src)
+	dst.SessionTemplates[0].DependsOn[0] = "changed"
+	dst.SessionTemplates[0].Health.Port = 1
+	dst.SessionTemplates[0].Env["APP_ENV"] = "changed"
+	dst.SessionTemplates[0].Panes[0].Env["PORT"] = "1"
+	if src.SessionTemplates[0].Env["APP_ENV"] != "dev" {
+		t.Fatal("Clone shares SessionTemplate.Env")
+	}
+	if src.SessionTemplates[0].Panes[0].Env["PORT"] != "3000" {
+		t.Fatal("Clone shares SessionTemplatePane.Env")
+	}
+	if src.SessionTemplates[0].DependsOn[0] != "backend" {
+		t.Fatal("Clone shares SessionTemplate.DependsOn")
+	}
+	if src.SessionTemplates[0].Health.Port != 3000 {
+		t.Fatal("Clone shares SessionTemplate.Health")
+	}
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"log/slog"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// MaxSetupWatchers caps worktree.setup_watchers entries.
+	MaxSetupWatchers = 16
+	// MaxSetupWatcherFiles caps the files of one setup watcher.
+	MaxSetupWatcherFiles = 16
+)
+
+// sanitizeSetupWatchers normalizes worktree.setup_watchers in place. Files
+// must stay inside the worktree; entries without a script or a valid file
+// are dropped with a warning.
+func sanitizeSetupWatchers(cfg *Config) {
+	watchers := cfg.Worktree.SetupWatchers
+	if len(watchers) == 0 {
+		cfg.Worktree.SetupWatchers = nil
+		return
+	}
+	if len(watchers) > MaxSetupWatchers {
+		slog.Warn("[WARN-CONFIG] worktree.setup_watchers exceeds limit, ignoring extra entries",
+			"max", MaxSetupWatchers)
+		watchers = watchers[:MaxSetupWatchers]
+	}
+	kept := make([]SetupWatcher, 0, len(watchers))
+	for i, watcher := range watchers {
+		watcher.Script = strings.TrimSpace(watcher.Script)
+		if watcher.Script == "" || strings.ContainsRune(watcher.Script, 0) {
+			slog.Warn("[WARN-CONFIG] worktree.setup_watchers entry has no valid script, ignoring",
+				"index", i)
+			continue
+		}
+		files := make([]string, 0, len(watcher.Files))
+		for _, file := range watcher.Files {
+			file = filepath.Clean(filepath.FromSlash(strings.TrimSpace(file)))
+			if file == "." || !filepath.IsLocal(file) {
+				slog.Warn("[WARN-CONFIG] worktree.setup_watchers file must be a path inside the worktree, ignoring",
+					"index", i, "file", file)
+				continue
+			}
+			if len(files) >= MaxSetupWatcherFiles {
+				slog.Warn("[WARN-CONFIG] worktree.setup_watchers files exceed limit, ignoring extra files",
+					"index", i, "max", MaxSetupWatcherFiles)
+				break
+			}
+			files = append(files, file)
+		}
+		if len(files) == 0 {
+			slog.Warn("[WARN-CONFIG] worktree.setup_watchers entry has no files, ignoring", "index", i)
+			continue
+		}
+		watcher.Files = files
+		kept = append(kept, watcher)
+	}
+	if len(kept) == 0 {
+		kept = nil
+	}
+	cfg.Worktree.SetupWatchers = kept
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSanitizeSetupWatchers(t *testing.T) {
+	cfg := Config{Worktree: WorktreeConfig{SetupWatchers: []SetupWatcher{
+		{Script: "  npm install  ", Files: []string{" package-lock.json ", "../outside.json", "web/package-lock.json", ""}},
+		{Script: "", Files: []string{"go.sum"}},
+		{Script: "pip install -r requirements.txt", Files: []string{"/etc/passwd"}},
+	}}}
+	sanitizeSetupWatchers(&cfg)
+	want := []SetupWatcher{{
+		Script: "npm install",
+		Files:  []string{"package-lock.json", filepath.Join("web", "package-lock.json")},
+	}}
+	if !reflect.DeepEqual(cfg.Worktree.SetupWatchers, want) {
+		t.Fatalf("sanitized = %+v, want %+v", cfg.Worktree.SetupWatchers, want)
+	}
+
+	cfg = Config{Worktree: WorktreeConfig{SetupWatchers: []SetupWatcher{{Script: "x"}}}}
+	sanitizeSetupWatchers(&cfg)
+	if cfg.Worktree.SetupWatchers != nil {
+		t.Fatalf("sanitized = %+v, want nil", cfg.Worktree.SetupWatchers)
+	}
+}
+
+func TestCloneSetupWatchersIsDeep(t *testing.T) {
+	src := Config{Worktree: WorktreeConfig{SetupWatchers: []SetupWatcher{{Script: "npm ci", Files: []string{"package-lock.json"}}}}}
+	dst := Clone(src)
+	dst.Worktree.SetupWatchers[0].Files[0] = "changed"
+	if src.Worktree.SetupWatchers[0].Files[0] != "package-lock.json" {
+		t.Fatal("Clone() shares setup watcher files")
+	}
+}
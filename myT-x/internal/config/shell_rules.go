@@ -0,0 +1,233 @@
+package config
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"myT-x/internal/git"
+)
+
+// MaxShellRules caps shell_rules entries to keep per-spawn matching cheap.
+const MaxShellRules = 50
+
+const (
+	// repoRootCacheTTL bounds how long a repository lookup is reused, so a
+	// later git init or clone in the same directory is picked up.
+	repoRootCacheTTL        = 30 * time.Second
+	repoRootCacheMaxEntries = 256
+)
+
+type cachedRepoRoot struct {
+	root      string
+	expiresAt time.Time
+}
+
+var (
+	repoRootCacheMu sync.Mutex
+	repoRootCache   = make(map[string]cachedRepoRoot)
+
+	// gitFindRepoRoot is replaced in tests to count git invocations.
+	gitFindRepoRoot = git.FindRepoRoot
+)
+
+// sanitizeShellRules validates and normalizes shell_rules entries in place.
+// Entries whose shell fails the allowlist/trusted_shells check or that have no matcher are
+// dropped with a warning so that a single bad rule never blocks startup.
+func sanitizeShellRules(cfg *Config, trusted trustedShellSet) {
+	sanitizeShellRulesWith(os.UserHomeDir, cfg, trusted)
+}
+
+// sanitizeShellRulesWith is the parameterized implementation of sanitizeShellRules,
+// allowing tests to inject test doubles for os.UserHomeDir.
+func sanitizeShellRulesWith(userHomeDirFn func() (string, error), cfg *Config, trusted trustedShellSet) {
+	if len(cfg.ShellRules) == 0 {
+		cfg.ShellRules = nil
+		return
+	}
+
+	filtered := make([]ShellRule, 0, min(len(cfg.ShellRules), MaxShellRules))
+	for i, rule := range cfg.ShellRules {
+		rule.Dir = strings.TrimSpace(rule.Dir)
+		rule.Repo = strings.TrimSpace(rule.Repo)
+		rule.Shell = strings.TrimSpace(rule.Shell)
+
+		if rule.Dir == "" && rule.Repo == "" {
+			slog.Warn("[WARN-CONFIG] shell_rules entry has neither dir nor repo, skipping", "index", i)
+			continue
+		}
+		if err := validateShell(rule.Shell, trusted); err != nil {
+			slog.Warn("[WARN-CONFIG] shell_rules entry has invalid shell, skipping",
+				"index", i, "shell", rule.Shell, "error", err)
+			continue
+		}
+		if rule.Dir != "" {
+			dir, ok := expandShellRulePath(userHomeDirFn, rule.Dir)
+			if !ok {
+				slog.Warn("[WARN-CONFIG] shell_rules entry dir is not an absolute pattern, skipping",
+					"index", i, "dir", rule.Dir)
+				continue
+			}
+			if _, err := filepath.Match(dir, dir); err != nil {
+				slog.Warn("[WARN-CONFIG] shell_rules entry dir is not a valid glob, skipping",
+					"index", i, "dir", rule.Dir, "error", err)
+				continue
+			}
+			rule.Dir = dir
+		}
+		if rule.Repo != "" && filepath.IsAbs(rule.Repo) {
+			rule.Repo = filepath.Clean(rule.Repo)
+		}
+
+		filtered = append(filtered, rule)
+		if len(filtered) == MaxShellRules {
+			if i < len(cfg.ShellRules)-1 {
+				slog.Warn("[WARN-CONFIG] shell_rules exceeds maximum, truncating",
+					"count", len(cfg.ShellRules), "max", MaxShellRules)
+			}
+			break
+		}
+	}
+	if len(filtered) == 0 {
+		cfg.ShellRules = nil
+		return
+	}
+	cfg.ShellRules = filtered
+}
+
+// expandShellRulePath expands ~ and environment tokens in a dir pattern and
+// reports whether the result is absolute.
+func expandShellRulePath(userHomeDirFn func() (string, error), pattern string) (string, bool) {
+	if strings.HasPrefix(pattern, "~") {
+		home, err := userHomeDirFn()
+		if err != nil {
+			return "", false
+		}
+		pattern = filepath.Join(home, pattern[1:])
+	}
+	pattern = filepath.Clean(expandDefaultSessionDirEnv(pattern))
+	return pattern, filepath.IsAbs(pattern)
+}
+
+// ResolveShell returns the shell for a pane whose working directory is workDir.
+// The first shell_rules entry matching workDir wins; cfg.Shell is returned
+// when no rule matches or workDir is empty.
+//
+// cfg is expected to be normalized by Load/Save so every rule shell has
+// already passed the allowlist check.
+func ResolveShell(cfg Config, workDir string) string {
+	workDir = strings.TrimSpace(workDir)
+	if workDir == "" || len(cfg.ShellRules) == 0 {
+		return cfg.Shell
+	}
+	workDir = filepath.Clean(workDir)
+
+	repoRoot := ""
+	repoResolved := false
+	for _, rule := range cfg.ShellRules {
+		if rule.Dir != "" {
+			if shellRuleDirMatches(rule.Dir, workDir) {
+				return rule.Shell
+			}
+			continue
+		}
+		if rule.Repo == "" {
+			continue
+		}
+		// Repo lookup runs git; do it at most once per resolution.
+		if !repoResolved {
+			repoRoot = findRepoRoot(workDir)
+			repoResolved = true
+		}
+		if shellRuleRepoMatches(rule.Repo, repoRoot) {
+			return rule.Shell
+		}
+	}
+	return cfg.Shell
+}
+
+// shellRuleDirMatches reports whether pattern matches dir or any ancestor of dir.
+// Matching is case-insensitive because pane directories originate from Windows paths.
+func shellRuleDirMatches(pattern, dir string) bool {
+	pattern = strings.ToLower(pattern)
+	current := strings.ToLower(dir)
+	for {
+		if matched, err := filepath.Match(pattern, current); err == nil && matched {
+			return true
+		}
+		parent := filepath.Dir(current)
+		if parent == current {
+			return false
+		}
+		current = parent
+	}
+}
+
+// shellRuleRepoMatches compares a repo rule against the resolved repository root.
+// Absolute rule values match the full path; other values match the base name.
+// repoRoot is expected to come from findRepoRoot and therefore be canonical.
+func shellRuleRepoMatches(repo, repoRoot string) bool {
+	if repoRoot == "" {
+		return false
+	}
+	if filepath.IsAbs(repo) {
+		return strings.EqualFold(canonicalRepoPath(repo), repoRoot)
+	}
+	return strings.EqualFold(repo, filepath.Base(repoRoot))
+}
+
+// canonicalRepoPath resolves symlinks and junctions in p. On Windows
+// EvalSymlinks also expands 8.3 short names and normalizes letter case, so
+// equivalent spellings of one directory compare equal. Paths that cannot be
+// resolved (e.g. a rule for a repository that is not cloned yet) are only
+// cleaned.
+func canonicalRepoPath(p string) string {
+	if resolved, err := filepath.EvalSymlinks(p); err == nil {
+		p = resolved
+	}
+	return filepath.Clean(p)
+}
+
+// findRepoRoot returns the canonical root of the repository containing dir,
+// or "" when dir is not inside one. Linked worktrees resolve to their own
+// root. Results are cached per directory for repoRootCacheTTL because this
+// runs on every pane spawn and each lookup starts a git process.
+func findRepoRoot(dir string) string {
+	now := time.Now()
+	repoRootCacheMu.Lock()
+	cached, ok := repoRootCache[dir]
+	repoRootCacheMu.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached.root
+	}
+
+	root := ""
+	if found, err := gitFindRepoRoot(dir); err == nil {
+		root = canonicalRepoPath(found)
+	}
+
+	repoRootCacheMu.Lock()
+	if len(repoRootCache) >= repoRootCacheMaxEntries {
+		for key, entry := range repoRootCache {
+			if !now.Before(entry.expiresAt) {
+				delete(repoRootCache, key)
+			}
+		}
+		if len(repoRootCache) >= repoRootCacheMaxEntries {
+			clear(repoRootCache)
+		}
+	}
+	repoRootCache[dir] = cachedRepoRoot{root: root, expiresAt: now.Add(repoRootCacheTTL)}
+	repoRootCacheMu.Unlock()
+	return root
+}
+
+// resetRepoRootCache drops all cached repository lookups. Used by tests.
+func resetRepoRootCache() {
+	repoRootCacheMu.Lock()
+	clear(repoRootCache)
+	repoRootCacheMu.Unlock()
+}
@@ -0,0 +1,188 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestShellRuleFieldCountGuard(t *testing.T) {
+	if got := reflect.TypeFor[ShellRule]().NumField(); got != 3 {
+		t.Fatalf("ShellRule field count = %d, want 3; update sanitizeShellRules, Clone, and this assertion", got)
+	}
+}
+
+func TestSanitizeShellRules(t *testing.T) {
+	home := t.TempDir()
+	homeFn := func() (string, error) { return home, nil }
+
+	cfg := Config{ShellRules: []ShellRule{
+		{Dir: "  ~/linux-projects  ", Shell: " wsl.exe "},
+		{Repo: "myT-x", Shell: "bash"},
+		{Dir: "relative/path", Shell: "pwsh.exe"},
+		{Shell: "cmd.exe"},
+		{Dir: "~/other", Shell: "evil.exe"},
+		{Dir: filepath.Join(home, "[bad"), Shell: "cmd.exe"},
+	}}
+	sanitizeShellRulesWith(homeFn, &cfg, nil)
+
+	want := []ShellRule{
+		{Dir: filepath.Join(home, "linux-projects"), Shell: "wsl.exe"},
+		{Repo: "myT-x", Shell: "bash"},
+	}
+	if !reflect.DeepEqual(cfg.ShellRules, want) {
+		t.Fatalf("ShellRules = %#v, want %#v", cfg.ShellRules, want)
+	}
+}
+
+func TestSanitizeShellRulesHomeDirFailureDropsTildeRule(t *testing.T) {
+	cfg := Config{ShellRules: []ShellRule{{Dir: "~/x", Shell: "wsl.exe"}}}
+	sanitizeShellRulesWith(func() (string, error) { return "", errors.New("no home") }, &cfg, nil)
+	if cfg.ShellRules != nil {
+		t.Fatalf("ShellRules = %#v, want nil", cfg.ShellRules)
+	}
+}
+
+func TestSanitizeShellRulesTruncatesToMaximum(t *testing.T) {
+	rules := make([]ShellRule, MaxShellRules+5)
+	for i := range rules {
+		rules[i] = ShellRule{Repo: "repo", Shell: "cmd.exe"}
+	}
+	cfg := Config{ShellRules: rules}
+	sanitizeShellRulesWith(os.UserHomeDir, &cfg, nil)
+	if len(cfg.ShellRules) != MaxShellRules {
+		t.Fatalf("len(ShellRules) = %d, want %d", len(cfg.ShellRules), MaxShellRules)
+	}
+}
+
+func TestResolveShell(t *testing.T) {
+	root := t.TempDir()
+	linuxDir := filepath.Join(root, "linux-projects", "app", "src")
+	repoDir := filepath.Join(root, "work", "myT-x")
+	repoSubDir := filepath.Join(repoDir, "internal")
+	plainDir := filepath.Join(root, "plain")
+	for _, dir := range []string{linuxDir, repoSubDir, plainDir} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	initTestRepo(t, repoDir)
+
+	cfg := Config{
+		Shell: "pwsh.exe",
+		ShellRules: []ShellRule{
+			{Dir: filepath.Join(root, "linux-projects"), Shell: "wsl.exe"},
+			{Repo: "MYT-X", Shell: "bash.exe"},
+			{Dir: filepath.Join(root, "*", "never"), Shell: "cmd.exe"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		workDir string
+		want    string
+	}{
+		{name: "empty dir falls back", workDir: "", want: "pwsh.exe"},
+		{name: "dir rule matches descendant", workDir: linuxDir, want: "wsl.exe"},
+		{name: "repo rule matches by base name", workDir: repoSubDir, want: "bash.exe"},
+		{name: "no match falls back", workDir: plainDir, want: "pwsh.exe"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ResolveShell(cfg, tt.workDir); got != tt.want {
+				t.Fatalf("ResolveShell(%q) = %q, want %q", tt.workDir, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveShellRepoAbsolutePath(t *testing.T) {
+	repoDir := t.TempDir()
+	initTestRepo(t, repoDir)
+	cfg := Config{
+		Shell:      "pwsh.exe",
+		ShellRules: []ShellRule{{Repo: repoDir, Shell: "cmd.exe"}},
+	}
+	if got := ResolveShell(cfg, repoDir); got != "cmd.exe" {
+		t.Fatalf("ResolveShell() = %q, want cmd.exe", got)
+	}
+}
+
+func TestResolveShellRepoAbsolutePathThroughSymlink(t *testing.T) {
+	root := t.TempDir()
+	repoDir := filepath.Join(root, "repo")
+	initTestRepo(t, repoDir)
+	linkDir := filepath.Join(root, "link")
+	if err := os.Symlink(repoDir, linkDir); err != nil {
+		t.Skipf("symlink not supported: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		rule    string
+		workDir string
+	}{
+		{name: "rule names the link", rule: linkDir, workDir: repoDir},
+		{name: "pane opened through the link", rule: repoDir, workDir: linkDir},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetRepoRootCache()
+			cfg := Config{
+				Shell:      "pwsh.exe",
+				ShellRules: []ShellRule{{Repo: tt.rule, Shell: "cmd.exe"}},
+			}
+			if got := ResolveShell(cfg, tt.workDir); got != "cmd.exe" {
+				t.Fatalf("ResolveShell(%q) = %q, want cmd.exe", tt.workDir, got)
+			}
+		})
+	}
+}
+
+func TestFindRepoRootCachesPerDirectory(t *testing.T) {
+	repoDir := t.TempDir()
+	calls := 0
+	original := gitFindRepoRoot
+	gitFindRepoRoot = func(dir string) (string, error) {
+		calls++
+		return dir, nil
+	}
+	resetRepoRootCache()
+	t.Cleanup(func() {
+		gitFindRepoRoot = original
+		resetRepoRootCache()
+	})
+
+	for range 3 {
+		if got := findRepoRoot(repoDir); got == "" {
+			t.Fatal("findRepoRoot() returned empty root")
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("git lookups = %d, want 1", calls)
+	}
+	findRepoRoot(filepath.Join(repoDir, "other"))
+	if calls != 2 {
+		t.Fatalf("git lookups after a new dir = %d, want 2", calls)
+	}
+}
+
+func TestCloneShellRules(t *testing.T) {
+	src := Config{ShellRules: []ShellRule{{Repo: "a", Shell: "cmd.exe"}}}
+	cloned := Clone(src)
+	cloned.ShellRules[0].Shell = "wsl.exe"
+	if src.ShellRules[0].Shell != "cmd.exe" {
+		t.Fatalf("source ShellRules mutated: %q", src.ShellRules[0].Shell)
+	}
+}
+
+// initTestRepo creates an empty git repository at dir.
+func initTestRepo(t *testing.T, dir string) {
+	t.Helper()
+	if out, err := exec.Command("git", "init", "-q", dir).CombinedOutput(); err != nil {
+		t.Fatalf("git init %s: %v\n%s", dir, err, out)
+	}
+}
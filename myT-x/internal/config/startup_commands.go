@@ -0,0 +1,54 @@
+package config
+
+import (
+	"log/slog"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxStartupCommandLen caps startup_commands entries (in runes).
+const MaxStartupCommandLen = 4096
+
+// sanitizeStartupCommands trims startup_commands entries in place.
+// Invalid commands are cleared with a warning; the block is dropped when it
+// carries no command and no remain_on_exit override.
+func sanitizeStartupCommands(cfg *Config) {
+	sc := cfg.StartupCommands
+	if sc == nil {
+		return
+	}
+	sc.Session = sanitizeStartupCommand("session", sc.Session)
+	sc.Window = sanitizeStartupCommand("window", sc.Window)
+	if sc.Session == "" && sc.Window == "" && !sc.RemainOnExit {
+		cfg.StartupCommands = nil
+	}
+}
+
+// sanitizeStartupCommand returns the trimmed command, or "" when it cannot be
+// typed into a shell as a single line.
+func sanitizeStartupCommand(field, command string) string {
+	command = NormalizeStartupCommand(command)
+	if command == "" {
+		return ""
+	}
+	if !IsValidStartupCommand(command) {
+		slog.Warn("[WARN-CONFIG] startup_commands entry is invalid, ignoring",
+			"field", field, "maxLen", MaxStartupCommandLen)
+		return ""
+	}
+	return command
+}
+
+// NormalizeStartupCommand trims surrounding whitespace from a startup command.
+func NormalizeStartupCommand(command string) string {
+	return strings.TrimSpace(command)
+}
+
+// IsValidStartupCommand reports whether command can be typed into a pane as
+// one line: no line breaks or null bytes, and at most MaxStartupCommandLen runes.
+func IsValidStartupCommand(command string) bool {
+	if strings.ContainsAny(command, "\r\n\x00") {
+		return false
+	}
+	return utf8.RuneCountInString(command) <= MaxStartupCommandLen
+}
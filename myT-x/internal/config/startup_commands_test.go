@@ -0,0 +1,66 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStartupCommandsConfigFieldCountGuard(t *testing.T) {
+	if got := reflect.TypeFor[StartupCommandsConfig]().NumField(); got != 3 {
+		t.Fatalf("StartupCommandsConfig field count = %d, want 3; update sanitizeStartupCommands, Clone, and this assertion", got)
+	}
+}
+
+func TestSanitizeStartupCommands(t *testing.T) {
+	tests := []struct {
+		name string
+		in   *StartupCommandsConfig
+		want *StartupCommandsConfig
+	}{
+		{name: "nil stays nil", in: nil, want: nil},
+		{
+			name: "trims commands",
+			in:   &StartupCommandsConfig{Session: "  npm run dev ", Window: " claude "},
+			want: &StartupCommandsConfig{Session: "npm run dev", Window: "claude"},
+		},
+		{
+			name: "multi-line command is cleared",
+			in:   &StartupCommandsConfig{Session: "echo a\necho b", Window: "claude", RemainOnExit: true},
+			want: &StartupCommandsConfig{Window: "claude", RemainOnExit: true},
+		},
+		{
+			name: "oversized command is cleared",
+			in:   &StartupCommandsConfig{Window: strings.Repeat("x", MaxStartupCommandLen+1)},
+			want: nil,
+		},
+		{
+			name: "empty block is dropped",
+			in:   &StartupCommandsConfig{Session: "   "},
+			want: nil,
+		},
+		{
+			name: "remain_on_exit alone is kept",
+			in:   &StartupCommandsConfig{RemainOnExit: true},
+			want: &StartupCommandsConfig{RemainOnExit: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Config{StartupCommands: tt.in}
+			sanitizeStartupCommands(&cfg)
+			if !reflect.DeepEqual(cfg.StartupCommands, tt.want) {
+				t.Fatalf("StartupCommands = %#v, want %#v", cfg.StartupCommands, tt.want)
+			}
+		})
+	}
+}
+
+func TestCloneStartupCommands(t *testing.T) {
+	src := Config{StartupCommands: &StartupCommandsConfig{Session: "npm run dev"}}
+	cloned := Clone(src)
+	cloned.StartupCommands.Session = "claude"
+	if src.StartupCommands.Session != "npm run dev" {
+		t.Fatalf("source StartupCommands mutated: %q", src.StartupCommands.Session)
+	}
+}
@@ -13,25 +13,38 @@ type UpdatedEvent struct {
 	Config             Config `json:"config"`
 	Version            uint64 `json:"version"`
 	UpdatedAtUnixMilli int64  `json:"updated_at_unix_milli"`
+	// Diff lists the keys that changed relative to the previous snapshot.
+	// Backend-only: consumers use it to re-apply just the affected subsystems.
+	Diff Diff `json:"-"`
 }
 
 // StateService manages in-memory config state with thread-safe access,
 // serialized persistence, and monotonic event versioning.
 //
+// The current config is published read-copy-update style: a save builds a
+// new Config and swaps the pointer, so a published Config is never modified
+// and readers see either the old or the new config in full. Snapshot deep
+// copies it; Read lends it to a callback without copying.
+//
 // Thread-safety:
-//   - mu (RWMutex) protects the cfg field.
+//   - current (atomic.Pointer) holds the published config.
+//   - mu (RWMutex) protects the policy and subscriptions fields.
 //   - saveMu (Mutex) serializes save operations.
-//   - Lock ordering (outer -> inner): saveMu -> mu.
+//   - notifyMu (Mutex) serializes subscriber callbacks.
+//   - Lock ordering (outer -> inner): saveMu -> notifyMu -> mu.
 //   - eventVersion (atomic.Uint64) is independently safe.
 //
 // configPath is write-once during Initialize; safe to read without mutex
 // after initialization completes.
 type StateService struct {
-	mu           sync.RWMutex
-	saveMu       sync.Mutex
-	eventVersion atomic.Uint64
-	cfg          Config
-	configPath   string
+	mu            sync.RWMutex
+	saveMu        sync.Mutex
+	notifyMu      sync.Mutex
+	eventVersion  atomic.Uint64
+	current       atomic.Pointer[Config]
+	policy        Policy
+	subscriptions []*subscription
+	configPath    string
 }
 
 // NewStateService creates an uninitialized config state service.
@@ -53,12 +66,26 @@ func (s *StateService) Initialize(configPath string, initial Config) {
 	s.setSnapshotNoClone(Clone(initial))
 }
 
-// Snapshot returns a deep-copied config protected by RWMutex.
+// Snapshot returns a deep copy of the current config.
 // All read access to the current config should go through this method.
 func (s *StateService) Snapshot() Config {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-	return Clone(s.cfg)
+	return Clone(*s.published())
+}
+
+// published returns the current config, which must not be modified.
+func (s *StateService) published() *Config {
+	if cfg := s.current.Load(); cfg != nil {
+		return cfg
+	}
+	return &Config{}
+}
+
+// Read calls fn with the current config and returns its result, without the
+// deep copy Snapshot makes. Use it for reads of a few fields on hot paths.
+// fn must not modify the config, and maps, slices, and pointers reached
+// from it must be copied before they escape fn.
+func Read[T any](s *StateService, fn func(cfg *Config) T) T {
+	return fn(s.published())
 }
 
 // unsafeSnapshot returns the current config without cloning.
@@ -69,25 +96,34 @@ func (s *StateService) Snapshot() Config {
 // Callers that retain values or pass config data to long-lived goroutines
 // must use Snapshot instead.
 func (s *StateService) unsafeSnapshot() Config {
+	return *s.published()
+}
+
+// SetPolicy installs the machine policy checked by Save and Update.
+// The initial snapshot should already be enforced with Policy.Enforce.
+func (s *StateService) SetPolicy(policy Policy) {
+	s.mu.Lock()
+	s.policy = policy
+	s.mu.Unlock()
+}
+
+// Policy returns the installed machine policy.
+func (s *StateService) Policy() Policy {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
-	return s.cfg
+	return s.policy
 }
 
-// SetSnapshot stores a deep-copied config protected by Mutex.
+// SetSnapshot publishes a deep copy of cfg without notifying subscribers.
 // Exported for test setup. Production code should use Save or Update.
 func (s *StateService) SetSnapshot(cfg Config) {
-	s.mu.Lock()
-	s.cfg = Clone(cfg)
-	s.mu.Unlock()
+	s.setSnapshotNoClone(Clone(cfg))
 }
 
-// setSnapshotNoClone stores cfg directly without cloning.
+// setSnapshotNoClone publishes cfg directly without cloning.
 // REQUIRES: caller guarantees cfg is not shared with any other goroutine.
 func (s *StateService) setSnapshotNoClone(cfg Config) {
-	s.mu.Lock()
-	s.cfg = cfg
-	s.mu.Unlock()
+	s.current.Store(&cfg)
 }
 
 // Save validates and persists cfg to disk, then updates the in-memory
@@ -99,8 +135,9 @@ func (s *StateService) setSnapshotNoClone(cfg Config) {
 // On failure the in-memory snapshot and event version are unchanged.
 func (s *StateService) Save(cfg Config) (UpdatedEvent, error) {
 	s.saveMu.Lock()
-	defer s.saveMu.Unlock()
-	return s.saveLocked(cfg)
+	event, err := s.saveLocked(cfg)
+	s.unlockSaveAndNotify(event, err)
+	return event, err
 }
 
 // Update performs a read-modify-write cycle under saveMu.
@@ -108,21 +145,55 @@ func (s *StateService) Save(cfg Config) (UpdatedEvent, error) {
 // The modified config is then saved and an UpdatedEvent is returned.
 func (s *StateService) Update(fn func(*Config)) (UpdatedEvent, error) {
 	s.saveMu.Lock()
-	defer s.saveMu.Unlock()
-
 	current := s.Snapshot()
 	fn(&current)
-	return s.saveLocked(current)
+	event, err := s.saveLocked(current)
+	s.unlockSaveAndNotify(event, err)
+	return event, err
+}
+
+// ApplyPatch merges a JSON merge patch into the current config under saveMu,
+// then validates and saves the result like Save. Patching the latest snapshot
+// keeps concurrent edits to other fields intact. The event's Diff lists the
+// keys that differ from the previous snapshot after normalization.
+//
+// On failure the in-memory snapshot, the file, and the event version are
+// unchanged.
+func (s *StateService) ApplyPatch(patch []byte) (UpdatedEvent, error) {
+	s.saveMu.Lock()
+	merged, err := MergePatch(s.Snapshot(), patch)
+	if err != nil {
+		s.saveMu.Unlock()
+		return UpdatedEvent{}, err
+	}
+	event, err := s.saveLocked(merged)
+	s.unlockSaveAndNotify(event, err)
+	if err != nil {
+		return UpdatedEvent{}, err
+	}
+	return event, nil
 }
 
 // saveLocked persists cfg and updates the in-memory snapshot.
 // REQUIRES: s.saveMu must be held by the caller.
 func (s *StateService) saveLocked(cfg Config) (UpdatedEvent, error) {
+	previous := s.unsafeSnapshot()
+	if policy := s.Policy(); !policy.Empty() {
+		candidate := Clone(cfg)
+		// Invalid configs fall through to Save, which reports the validation error.
+		if applyDefaultsAndValidate(&candidate) == nil {
+			if err := policy.CheckSave(previous, candidate); err != nil {
+				return UpdatedEvent{}, err
+			}
+		}
+	}
 	normalized, err := Save(s.configPath, cfg)
 	if err != nil {
 		return UpdatedEvent{}, err
 	}
-	// Clone once: internal snapshot gets the clone, event payload gets the
+	// previous is only read here; saveMu prevents a concurrent replacement.
+	diff := DiffConfigs(previous, normalized)
+	// Clone once: the published config gets the clone, event payload gets the
 	// original normalized value. This is safe because Save() returns a fresh
 	// value not shared with any other goroutine.
 	s.setSnapshotNoClone(Clone(normalized))
@@ -132,6 +203,7 @@ func (s *StateService) saveLocked(cfg Config) (UpdatedEvent, error) {
 		Config:             normalized,
 		Version:            version,
 		UpdatedAtUnixMilli: time.Now().UnixMilli(),
+		Diff:               diff,
 	}, nil
 }
 
@@ -341,6 +341,39 @@ func TestUpdate(t *testing.T) {
 	}
 }
 
+func TestUpdateReportsDiffAgainstPreviousSnapshot(t *testing.T) {
+	configPath := newTestConfigPath(t)
+	s := NewStateService()
+	s.Initialize(configPath, DefaultConfig())
+
+	// First save normalizes the initial snapshot; diff from then on is exact.
+	if _, err := s.Save(DefaultConfig()); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	event, err := s.Update(func(cfg *Config) {
+		cfg.GlobalHotkey = "Ctrl+Shift+T"
+		cfg.WebSocketPort = 40123
+	})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	want := []KeyChange{
+		{Key: "global_hotkey", Subsystem: SubsystemHotkey, Apply: ApplyImmediate},
+		{Key: "websocket_port", Subsystem: SubsystemWebSocket, Apply: ApplyRestart},
+	}
+	if !reflect.DeepEqual(event.Diff.Changes, want) {
+		t.Fatalf("Diff.Changes = %+v, want %+v", event.Diff.Changes, want)
+	}
+
+	event, err = s.Update(func(*Config) {})
+	if err != nil {
+		t.Fatalf("no-op Update() error = %v", err)
+	}
+	if !event.Diff.Empty() {
+		t.Fatalf("no-op Diff.Changes = %+v, want empty", event.Diff.Changes)
+	}
+}
+
 func TestUpdateFailsWithInvalidConfigPath(t *testing.T) {
 	s := NewStateService()
 	s.Initialize("   ", DefaultConfig())
@@ -432,8 +465,8 @@ func TestSetEventVersion(t *testing.T) {
 // --- Field count guard ---
 
 func TestUpdatedEventFieldCount(t *testing.T) {
-	if got := reflect.TypeFor[UpdatedEvent]().NumField(); got != 3 {
-		t.Fatalf("UpdatedEvent field count = %d, want 3; update emit payload and tests for new fields", got)
+	if got := reflect.TypeFor[UpdatedEvent]().NumField(); got != 4 {
+		t.Fatalf("UpdatedEvent field count = %d, want 4; update emit payload and tests for new fields", got)
 	}
 }
 
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+)
+
+// subscription is one callback registered with StateService.Subscribe.
+type subscription struct {
+	// keys are the top-level config keys fn is called for; nil means all.
+	keys map[string]struct{}
+	fn   func(UpdatedEvent)
+}
+
+// wants reports whether diff changes one of the keys of sub.
+func (sub *subscription) wants(diff Diff) bool {
+	if sub.keys == nil {
+		return !diff.Empty()
+	}
+	for _, change := range diff.Changes {
+		if _, ok := sub.keys[change.Key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Subscribe registers fn to be called after every save that changes one of
+// keys (top-level config JSON keys such as "pane_env"), or any key when keys
+// is empty. It returns a func that removes the subscription.
+//
+// Callbacks run on the saving goroutine before Save, Update, or ApplyPatch
+// returns, one at a time and in version order, after the new config is
+// published. event.Config is shared by all callbacks and the caller of the
+// save, so callbacks must not modify it; they must not save the config
+// either, which would deadlock.
+//
+// Subscribe panics when a key is not a Config key (programming error).
+func (s *StateService) Subscribe(fn func(event UpdatedEvent), keys ...string) (unsubscribe func()) {
+	sub := &subscription{fn: fn}
+	if len(keys) > 0 {
+		sub.keys = make(map[string]struct{}, len(keys))
+		for _, key := range keys {
+			if _, ok := keyEffects[key]; !ok {
+				panic(fmt.Sprintf("config.StateService.Subscribe: unknown config key %q", key))
+			}
+			sub.keys[key] = struct{}{}
+		}
+	}
+
+	s.mu.Lock()
+	s.subscriptions = append(s.subscriptions, sub)
+	s.mu.Unlock()
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.subscriptions = slices.DeleteFunc(s.subscriptions, func(other *subscription) bool {
+			return other == sub
+		})
+	}
+}
+
+// unlockSaveAndNotify releases saveMu and, after a successful save, calls
+// the subscribers of the changed keys. notifyMu is taken before saveMu is
+// released, so a later save cannot notify before this one.
+// REQUIRES: s.saveMu must be held by the caller.
+func (s *StateService) unlockSaveAndNotify(event UpdatedEvent, err error) {
+	if err != nil || event.Diff.Empty() {
+		s.saveMu.Unlock()
+		return
+	}
+	s.notifyMu.Lock()
+	s.saveMu.Unlock()
+	defer s.notifyMu.Unlock()
+
+	s.mu.RLock()
+	subs := slices.Clone(s.subscriptions)
+	s.mu.RUnlock()
+	for _, sub := range subs {
+		if sub.wants(event.Diff) {
+			sub.fn(event)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestSubscribeCallsSubscribersOfChangedKeys(t *testing.T) {
+	s := NewStateService()
+	s.Initialize(newTestConfigPath(t), DefaultConfig())
+
+	var hotkey, all []uint64
+	var hotkeyValues []string
+	unsubscribe := s.Subscribe(func(event UpdatedEvent) {
+		hotkey = append(hotkey, event.Version)
+		// The new config is published before subscribers run.
+		hotkeyValues = append(hotkeyValues, Read(s, func(cfg *Config) string { return cfg.GlobalHotkey }))
+	}, SubsystemKeys(SubsystemHotkey)...)
+	s.Subscribe(func(event UpdatedEvent) { all = append(all, event.Version) })
+
+	if _, err := s.Update(func(cfg *Config) { cfg.GlobalHotkey = "Ctrl+Alt+M" }); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Update(func(cfg *Config) { cfg.Shell = "cmd.exe" }); err != nil {
+		t.Fatal(err)
+	}
+	// A save without changes notifies nobody.
+	if _, err := s.Update(func(*Config) {}); err != nil {
+		t.Fatal(err)
+	}
+	unsubscribe()
+	if _, err := s.Update(func(cfg *Config) { cfg.GlobalHotkey = "Ctrl+Alt+N" }); err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(hotkey, []uint64{1}) || !slices.Equal(hotkeyValues, []string{"Ctrl+Alt+M"}) {
+		t.Fatalf("hotkey subscriber got versions %v (hotkeys %v), want [1]", hotkey, hotkeyValues)
+	}
+	if !slices.Equal(all, []uint64{1, 2, 4}) {
+		t.Fatalf("catch-all subscriber got versions %v, want [1 2 4]", all)
+	}
+}
+
+func TestSubscribeDeliversConcurrentSavesInVersionOrder(t *testing.T) {
+	s := NewStateService()
+	s.Initialize(newTestConfigPath(t), DefaultConfig())
+
+	var versions []uint64
+	s.Subscribe(func(event UpdatedEvent) { versions = append(versions, event.Version) }, "global_hotkey")
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Go(func() {
+			if _, err := s.Update(func(cfg *Config) { cfg.GlobalHotkey = fmt.Sprintf("Ctrl+Alt+%d", i) }); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+	wg.Wait()
+
+	if len(versions) != 8 || !slices.IsSorted(versions) {
+		t.Fatalf("versions = %v, want 8 in ascending order", versions)
+	}
+}
+
+func TestSubscribePanicsOnUnknownKey(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Subscribe() with an unknown key did not panic")
+		}
+	}()
+	NewStateService().Subscribe(func(UpdatedEvent) {}, "no_such_key")
+}
+
+func TestReadSeesPublishedConfigWithoutCopy(t *testing.T) {
+	s := NewStateService()
+	if got := Read(s, func(cfg *Config) string { return cfg.Shell }); got != "" {
+		t.Fatalf("Read() before Initialize = %q, want empty", got)
+	}
+	cfg := DefaultConfig()
+	cfg.Shell = "pwsh.exe"
+	s.SetSnapshot(cfg)
+	if got := Read(s, func(cfg *Config) string { return cfg.Shell }); got != "pwsh.exe" {
+		t.Fatalf("Read() = %q, want pwsh.exe", got)
+	}
+}
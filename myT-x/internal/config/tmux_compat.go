@@ -0,0 +1,38 @@
+package config
+
+import (
+	"log/slog"
+	"strings"
+)
+
+// tmux_compat modes.
+const (
+	// TmuxCompatStrict rejects unsupported tmux commands and flags with the
+	// error text and exit code of real tmux.
+	TmuxCompatStrict = "strict"
+	// TmuxCompatLenient ignores unsupported tmux commands and flags with a
+	// warning on stderr.
+	TmuxCompatLenient = "lenient"
+)
+
+// sanitizeTmuxCompat normalizes tmux_compat in place. Invalid values fall
+// back to the strict default with a warning.
+func sanitizeTmuxCompat(cfg *Config) {
+	mode := strings.ToLower(strings.TrimSpace(cfg.TmuxCompat))
+	switch mode {
+	case "", TmuxCompatStrict, TmuxCompatLenient:
+		cfg.TmuxCompat = mode
+	default:
+		slog.Warn("[WARN-CONFIG] tmux_compat is invalid, falling back to strict",
+			"configured", cfg.TmuxCompat)
+		cfg.TmuxCompat = ""
+	}
+}
+
+// EffectiveTmuxCompat returns the tmux_compat mode in effect.
+func EffectiveTmuxCompat(cfg Config) string {
+	if cfg.TmuxCompat == TmuxCompatLenient {
+		return TmuxCompatLenient
+	}
+	return TmuxCompatStrict
+}
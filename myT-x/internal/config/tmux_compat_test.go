@@ -0,0 +1,26 @@
+package config
+
+import "testing"
+
+func TestSanitizeTmuxCompat(t *testing.T) {
+	tests := []struct {
+		configured string
+		want       string
+		effective  string
+	}{
+		{configured: "", want: "", effective: TmuxCompatStrict},
+		{configured: " Lenient ", want: TmuxCompatLenient, effective: TmuxCompatLenient},
+		{configured: "strict", want: TmuxCompatStrict, effective: TmuxCompatStrict},
+		{configured: "loose", want: "", effective: TmuxCompatStrict},
+	}
+	for _, tt := range tests {
+		cfg := Config{TmuxCompat: tt.configured}
+		sanitizeTmuxCompat(&cfg)
+		if cfg.TmuxCompat != tt.want {
+			t.Errorf("sanitizeTmuxCompat(%q) = %q, want %q", tt.configured, cfg.TmuxCompat, tt.want)
+		}
+		if got := EffectiveTmuxCompat(cfg); got != tt.effective {
+			t.Errorf("EffectiveTmuxCompat(%q) = %q, want %q", tt.configured, got, tt.effective)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"log/slog"
+	"slices"
+	"strings"
+)
+
+// Tool manifest kinds recognized by tool_paths.kinds.
+const (
+	// ToolPathKindNode adds node_modules/.bin for a package.json.
+	ToolPathKindNode = "node"
+	// ToolPathKindAsdf adds the asdf shims directory for a .tool-versions.
+	ToolPathKindAsdf = "asdf"
+	// ToolPathKindGo adds GOBIN for a go.mod.
+	ToolPathKindGo = "go"
+)
+
+// ToolPathKinds lists every kind in the order their directories are
+// prepended to PATH; repo-local directories come first.
+var ToolPathKinds = []string{ToolPathKindNode, ToolPathKindAsdf, ToolPathKindGo}
+
+// sanitizeToolPaths normalizes tool_paths.kinds in place. Unknown kinds are
+// dropped with a warning. The block itself stays even when no kind is left,
+// which keeps the feature enabled for all kinds.
+func sanitizeToolPaths(cfg *Config) {
+	if cfg.ToolPaths == nil {
+		return
+	}
+	var kinds []string
+	for _, kind := range cfg.ToolPaths.Kinds {
+		kind = strings.ToLower(strings.TrimSpace(kind))
+		if !slices.Contains(ToolPathKinds, kind) {
+			slog.Warn("[WARN-CONFIG] tool_paths has unsupported kind, ignoring", "kind", kind)
+			continue
+		}
+		if !slices.Contains(kinds, kind) {
+			kinds = append(kinds, kind)
+		}
+	}
+	cfg.ToolPaths.Kinds = kinds
+}
+
+// EnabledToolPathKinds returns the kinds tool_paths enables in PATH order,
+// or nil when tool_paths is not configured.
+func EnabledToolPathKinds(cfg Config) []string {
+	if cfg.ToolPaths == nil {
+		return nil
+	}
+	if len(cfg.ToolPaths.Kinds) == 0 {
+		return slices.Clone(ToolPathKinds)
+	}
+	var kinds []string
+	for _, kind := range ToolPathKinds {
+		if slices.Contains(cfg.ToolPaths.Kinds, kind) {
+			kinds = append(kinds, kind)
+		}
+	}
+	return kinds
+}
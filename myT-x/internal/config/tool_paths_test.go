@@ -0,0 +1,54 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestToolPathsConfigFieldCountGuard(t *testing.T) {
+	if got := reflect.TypeFor[ToolPathsConfig]().NumField(); got != 1 {
+		t.Fatalf("ToolPathsConfig field count = %d, want 1; update sanitizeToolPaths, Clone, and this assertion", got)
+	}
+}
+
+func TestSanitizeToolPaths(t *testing.T) {
+	cfg := Config{ToolPaths: &ToolPathsConfig{Kinds: []string{" Go", "node", "cargo", "go"}}}
+	sanitizeToolPaths(&cfg)
+	if want := []string{"go", "node"}; !reflect.DeepEqual(cfg.ToolPaths.Kinds, want) {
+		t.Fatalf("Kinds = %v, want %v", cfg.ToolPaths.Kinds, want)
+	}
+
+	cfg = Config{}
+	sanitizeToolPaths(&cfg)
+	if cfg.ToolPaths != nil {
+		t.Fatalf("ToolPaths = %+v, want nil", cfg.ToolPaths)
+	}
+}
+
+func TestEnabledToolPathKinds(t *testing.T) {
+	tests := []struct {
+		name string
+		in   *ToolPathsConfig
+		want []string
+	}{
+		{name: "not configured", in: nil, want: nil},
+		{name: "all kinds", in: &ToolPathsConfig{}, want: ToolPathKinds},
+		{name: "selected kinds in PATH order", in: &ToolPathsConfig{Kinds: []string{"go", "node"}}, want: []string{"node", "go"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EnabledToolPathKinds(Config{ToolPaths: tt.in}); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("EnabledToolPathKinds() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCloneToolPaths(t *testing.T) {
+	src := Config{ToolPaths: &ToolPathsConfig{Kinds: []string{"node"}}}
+	cloned := Clone(src)
+	cloned.ToolPaths.Kinds[0] = "go"
+	if src.ToolPaths.Kinds[0] != "node" {
+		t.Fatalf("source ToolPaths mutated: %v", src.ToolPaths.Kinds)
+	}
+}
@@ -0,0 +1,244 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MaxTrustedShells caps trusted_shells entries.
+const MaxTrustedShells = 20
+
+// maxShellHashCacheEntries bounds shellHashes; the cache is emptied when a
+// new path would exceed it.
+const maxShellHashCacheEntries = 4 * MaxTrustedShells
+
+// shellHashes caches the hashes of pinned trusted shells. Every validation
+// and every AllowedShellListFor call verifies trusted_shells, and shells
+// such as bash.exe are large enough that re-reading them each time is felt.
+var shellHashes = &fileHashCache{hashFn: hashFileSHA256}
+
+// trustedShellSet holds verified trusted shell paths keyed by
+// trustedShellKey. A nil set trusts nothing beyond allowedShells.
+type trustedShellSet map[string]struct{}
+
+// trustedShellKey normalizes an absolute shell path for case-insensitive
+// comparison (Windows file system semantics).
+func trustedShellKey(path string) string {
+	return strings.ToLower(filepath.Clean(path))
+}
+
+func (s trustedShellSet) contains(shell string) bool {
+	if len(s) == 0 || !filepath.IsAbs(shell) {
+		return false
+	}
+	_, ok := s[trustedShellKey(shell)]
+	return ok
+}
+
+// sanitizeTrustedShells normalizes trusted_shells entries in place and returns
+// the set of entries that passed verification.
+//
+// Entries are kept in the config even when verification fails (missing file,
+// hash mismatch) so a temporarily unavailable drive does not silently erase
+// user configuration; such entries are logged and excluded from the effective
+// allowlist. Only structurally invalid entries (empty, relative, null bytes,
+// malformed hash) are dropped.
+func sanitizeTrustedShells(cfg *Config) trustedShellSet {
+	return sanitizeTrustedShellsWith(shellHashes.hash, cfg)
+}
+
+// sanitizeTrustedShellsWith is the parameterized implementation of
+// sanitizeTrustedShells, allowing tests to inject a file hasher.
+func sanitizeTrustedShellsWith(hashFileFn func(path string) (string, error), cfg *Config) trustedShellSet {
+	if len(cfg.TrustedShells) == 0 {
+		cfg.TrustedShells = nil
+		return nil
+	}
+
+	trusted := make(trustedShellSet, len(cfg.TrustedShells))
+	filtered := make([]TrustedShell, 0, min(len(cfg.TrustedShells), MaxTrustedShells))
+	seen := make(map[string]struct{}, len(cfg.TrustedShells))
+	for i, entry := range cfg.TrustedShells {
+		entry.Path = strings.TrimSpace(entry.Path)
+		entry.SHA256 = strings.ToLower(strings.TrimSpace(entry.SHA256))
+
+		if entry.Path == "" {
+			slog.Warn("[WARN-CONFIG] trusted_shells entry has empty path, skipping", "index", i)
+			continue
+		}
+		if strings.ContainsRune(entry.Path, '\x00') {
+			slog.Warn("[WARN-CONFIG] trusted_shells entry path contains null byte, skipping", "index", i)
+			continue
+		}
+		if !filepath.IsAbs(entry.Path) {
+			slog.Warn("[WARN-CONFIG] trusted_shells entry must be an absolute path, skipping",
+				"index", i, "path", entry.Path)
+			continue
+		}
+		entry.Path = filepath.Clean(entry.Path)
+		if entry.SHA256 != "" && !isSHA256Hex(entry.SHA256) {
+			slog.Warn("[WARN-CONFIG] trusted_shells entry sha256 is not a hex digest, skipping",
+				"index", i, "path", entry.Path)
+			continue
+		}
+		key := trustedShellKey(entry.Path)
+		if _, exists := seen[key]; exists {
+			slog.Warn("[WARN-CONFIG] trusted_shells entry duplicates another path, skipping",
+				"index", i, "path", entry.Path)
+			continue
+		}
+		seen[key] = struct{}{}
+		filtered = append(filtered, entry)
+
+		if err := verifyTrustedShell(hashFileFn, entry); err != nil {
+			slog.Warn("[WARN-CONFIG] trusted_shells entry failed verification, not adding to allowlist",
+				"path", entry.Path, "error", err)
+		} else {
+			trusted[key] = struct{}{}
+		}
+
+		if len(filtered) == MaxTrustedShells {
+			if i < len(cfg.TrustedShells)-1 {
+				slog.Warn("[WARN-CONFIG] trusted_shells exceeds maximum, truncating",
+					"count", len(cfg.TrustedShells), "max", MaxTrustedShells)
+			}
+			break
+		}
+	}
+	if len(filtered) == 0 {
+		cfg.TrustedShells = nil
+	} else {
+		cfg.TrustedShells = filtered
+	}
+	return trusted
+}
+
+// isUnverifiedTrustedShell reports whether shell is listed in trusted_shells
+// but did not pass verification.
+func isUnverifiedTrustedShell(cfg *Config, shell string, trusted trustedShellSet) bool {
+	shell = strings.TrimSpace(shell)
+	if !filepath.IsAbs(shell) || trusted.contains(shell) {
+		return false
+	}
+	key := trustedShellKey(shell)
+	for _, entry := range cfg.TrustedShells {
+		if trustedShellKey(entry.Path) == key {
+			return true
+		}
+	}
+	return false
+}
+
+// verifyTrustedShell checks that entry points to an existing regular file and,
+// when a hash is pinned, that the file content matches it.
+func verifyTrustedShell(hashFileFn func(path string) (string, error), entry TrustedShell) error {
+	info, err := os.Stat(entry.Path)
+	if err != nil {
+		return fmt.Errorf("shell path does not exist: %w", err)
+	}
+	if info.IsDir() {
+		return errors.New("shell path is a directory")
+	}
+	if entry.SHA256 == "" {
+		return nil
+	}
+	actual, err := hashFileFn(entry.Path)
+	if err != nil {
+		return fmt.Errorf("hash shell file: %w", err)
+	}
+	if !strings.EqualFold(actual, entry.SHA256) {
+		return fmt.Errorf("sha256 mismatch: got %s", actual)
+	}
+	return nil
+}
+
+func hashFileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// fileHashCache remembers file hashes by path, size, and modification time,
+// so an unchanged file is hashed once. A replaced or rewritten file changes
+// its size or modification time and is hashed again.
+type fileHashCache struct {
+	hashFn func(path string) (string, error)
+
+	mu      sync.Mutex
+	entries map[string]fileHashEntry
+}
+
+type fileHashEntry struct {
+	size    int64
+	modTime time.Time
+	sum     string
+}
+
+func (c *fileHashCache) hash(path string) (string, error) {
+	// Stat before hashing: a write during hashing then leaves a newer
+	// modification time behind, and the next call hashes again.
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	c.mu.Lock()
+	entry, ok := c.entries[path]
+	c.mu.Unlock()
+	if ok && entry.size == info.Size() && entry.modTime.Equal(info.ModTime()) {
+		return entry.sum, nil
+	}
+
+	sum, err := c.hashFn(path)
+	if err != nil {
+		return "", err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil || (len(c.entries) >= maxShellHashCacheEntries && !ok) {
+		c.entries = make(map[string]fileHashEntry)
+	}
+	c.entries[path] = fileHashEntry{size: info.Size(), modTime: info.ModTime(), sum: sum}
+	return sum, nil
+}
+
+func isSHA256Hex(value string) bool {
+	if len(value) != sha256.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(value)
+	return err == nil
+}
+
+// AllowedShellListFor returns the built-in allowlist merged with the verified
+// trusted_shells paths from cfg, for UI display. Built-in names come first in
+// alphabetical order, followed by trusted paths in config order.
+func AllowedShellListFor(cfg Config) []string {
+	shells := AllowedShellList()
+	if len(cfg.TrustedShells) == 0 {
+		return shells
+	}
+	probe := Config{TrustedShells: append([]TrustedShell(nil), cfg.TrustedShells...)}
+	trusted := sanitizeTrustedShells(&probe)
+	for _, entry := range probe.TrustedShells {
+		if trusted.contains(entry.Path) {
+			shells = append(shells, entry.Path)
+		}
+	}
+	return shells
+}
@@ -0,0 +1,186 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTrustedShellFieldCountGuard(t *testing.T) {
+	if got := reflect.TypeFor[TrustedShell]().NumField(); got != 2 {
+		t.Fatalf("TrustedShell field count = %d, want 2; update sanitizeTrustedShells, Clone, and this assertion", got)
+	}
+}
+
+func writeFakeShell(t *testing.T, dir, name string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte("#!fake-shell\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestSanitizeTrustedShells(t *testing.T) {
+	dir := t.TempDir()
+	nu := writeFakeShell(t, dir, "nu.exe")
+	gitBash := writeFakeShell(t, dir, "git-bash.exe")
+	missing := filepath.Join(dir, "missing.exe")
+	goodHash, err := hashFileSHA256(gitBash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := Config{TrustedShells: []TrustedShell{
+		{Path: "  " + nu + "  "},
+		{Path: gitBash, SHA256: strings.ToUpper(goodHash)},
+		{Path: missing},
+		{Path: "relative\\nu.exe"},
+		{Path: ""},
+		{Path: strings.ToUpper(nu)},
+		{Path: nu + ".bak", SHA256: "not-a-hash"},
+	}}
+	trusted := sanitizeTrustedShellsWith(hashFileSHA256, &cfg)
+
+	wantEntries := []TrustedShell{
+		{Path: nu},
+		{Path: gitBash, SHA256: goodHash},
+		{Path: missing},
+	}
+	if !reflect.DeepEqual(cfg.TrustedShells, wantEntries) {
+		t.Fatalf("TrustedShells = %#v, want %#v", cfg.TrustedShells, wantEntries)
+	}
+	if !trusted.contains(nu) || !trusted.contains(gitBash) {
+		t.Fatalf("trusted set missing verified entries: %#v", trusted)
+	}
+	if trusted.contains(missing) {
+		t.Fatal("missing path must be kept in config but not trusted")
+	}
+}
+
+func TestSanitizeTrustedShellsHashMismatchIsNotTrusted(t *testing.T) {
+	shell := writeFakeShell(t, t.TempDir(), "nu.exe")
+	cfg := Config{TrustedShells: []TrustedShell{{Path: shell, SHA256: strings.Repeat("a", 64)}}}
+	trusted := sanitizeTrustedShellsWith(hashFileSHA256, &cfg)
+	if trusted.contains(shell) {
+		t.Fatal("hash mismatch must not be trusted")
+	}
+	if len(cfg.TrustedShells) != 1 {
+		t.Fatalf("TrustedShells = %#v, want entry preserved", cfg.TrustedShells)
+	}
+}
+
+func TestSanitizeTrustedShellsHashErrorIsNotTrusted(t *testing.T) {
+	shell := writeFakeShell(t, t.TempDir(), "nu.exe")
+	cfg := Config{TrustedShells: []TrustedShell{{Path: shell, SHA256: strings.Repeat("b", 64)}}}
+	trusted := sanitizeTrustedShellsWith(func(string) (string, error) {
+		return "", errors.New("locked")
+	}, &cfg)
+	if trusted.contains(shell) {
+		t.Fatal("hash failure must not be trusted")
+	}
+}
+
+func TestApplyDefaultsAndValidate_TrustedShellAcceptedAsShell(t *testing.T) {
+	shell := writeFakeShell(t, t.TempDir(), "nu.exe")
+	cfg := newValidConfigWithTaskScheduler()
+	cfg.Shell = shell
+	cfg.TrustedShells = []TrustedShell{{Path: shell}}
+	cfg.ShellRules = []ShellRule{{Repo: "repo", Shell: shell}}
+
+	if err := applyDefaultsAndValidate(&cfg); err != nil {
+		t.Fatalf("applyDefaultsAndValidate: %v", err)
+	}
+	if cfg.Shell != shell {
+		t.Fatalf("Shell = %q, want %q", cfg.Shell, shell)
+	}
+	if len(cfg.ShellRules) != 1 {
+		t.Fatalf("ShellRules = %#v, want trusted rule kept", cfg.ShellRules)
+	}
+}
+
+func TestApplyDefaultsAndValidate_UntrustedCustomShellRejected(t *testing.T) {
+	shell := writeFakeShell(t, t.TempDir(), "nu.exe")
+	cfg := newValidConfigWithTaskScheduler()
+	cfg.Shell = shell
+
+	if err := applyDefaultsAndValidate(&cfg); err == nil {
+		t.Fatal("applyDefaultsAndValidate() expected allowlist error for untrusted shell")
+	}
+}
+
+func TestApplyDefaultsAndValidate_MissingTrustedShellFallsBackToDefault(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "nu.exe")
+	cfg := newValidConfigWithTaskScheduler()
+	cfg.Shell = missing
+	cfg.TrustedShells = []TrustedShell{{Path: missing}}
+
+	if err := applyDefaultsAndValidate(&cfg); err != nil {
+		t.Fatalf("applyDefaultsAndValidate: %v", err)
+	}
+	if cfg.Shell != DefaultConfig().Shell {
+		t.Fatalf("Shell = %q, want default %q", cfg.Shell, DefaultConfig().Shell)
+	}
+	if len(cfg.TrustedShells) != 1 {
+		t.Fatalf("TrustedShells = %#v, want missing entry preserved", cfg.TrustedShells)
+	}
+}
+
+func TestAllowedShellListForAppendsVerifiedTrustedShells(t *testing.T) {
+	dir := t.TempDir()
+	shell := writeFakeShell(t, dir, "nu.exe")
+	cfg := Config{TrustedShells: []TrustedShell{
+		{Path: shell},
+		{Path: filepath.Join(dir, "missing.exe")},
+	}}
+
+	got := AllowedShellListFor(cfg)
+	base := AllowedShellList()
+	want := append(base, shell)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("AllowedShellListFor() = %#v, want %#v", got, want)
+	}
+	if len(cfg.TrustedShells) != 2 {
+		t.Fatal("AllowedShellListFor must not mutate the input config")
+	}
+}
+
+func TestFileHashCacheRehashesOnlyChangedFiles(t *testing.T) {
+	shell := writeFakeShell(t, t.TempDir(), "bash.exe")
+	calls := 0
+	cache := &fileHashCache{hashFn: func(path string) (string, error) {
+		calls++
+		return hashFileSHA256(path)
+	}}
+
+	first, err := cache.hash(shell)
+	if err != nil {
+		t.Fatalf("hash() error = %v", err)
+	}
+	if second, err := cache.hash(shell); err != nil || second != first || calls != 1 {
+		t.Fatalf("second hash() = %q, %v after %d hashes; want the cached sum after 1", second, err, calls)
+	}
+
+	if err := os.WriteFile(shell, []byte("#!replaced-shell\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(shell, later, later); err != nil {
+		t.Fatal(err)
+	}
+	replaced, err := cache.hash(shell)
+	if err != nil || replaced == first || calls != 2 {
+		t.Fatalf("hash() after replacing the file = %q, %v after %d hashes; want a new sum", replaced, err, calls)
+	}
+
+	if err := os.Remove(shell); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.hash(shell); err == nil {
+		t.Fatal("hash() of a removed file should fail instead of returning the cached sum")
+	}
+}
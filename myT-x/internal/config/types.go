@@ -21,6 +21,248 @@ type AutoStartCommand struct {
 	Args    string `yaml:"args,omitempty" json:"args,omitempty"`
 }
 
+// ShellRule maps a directory glob or repository to a shell executable.
+// Exactly one of Dir or Repo is expected per rule; Dir takes precedence when
+// both are set. Rules are evaluated in order and the first match wins.
+//
+// Dir is a filepath.Match pattern (after ~ and environment expansion) that is
+// tested against the pane working directory and each of its ancestors, so
+// "~/linux-projects" also matches "~/linux-projects/app/src".
+// Repo matches the base name (case-insensitive) or absolute path of the
+// nearest ancestor directory that contains a .git entry.
+type ShellRule struct {
+	Dir   string `yaml:"dir,omitempty" json:"dir,omitempty"`
+	Repo  string `yaml:"repo,omitempty" json:"repo,omitempty"`
+	Shell string `yaml:"shell" json:"shell"`
+}
+
+// SessionBadgeRule assigns an automatic badge to sessions whose git state
+// matches. Every non-empty matcher must match; rules are evaluated in order
+// and the first match wins. A badge set by the user always takes precedence.
+//
+// BranchPrefix matches the start of the session branch name.
+// Repo matches the repository base name (case-insensitive) or absolute path,
+// like shell_rules.repo. Dirty restricts the rule to sessions whose working
+// tree has uncommitted changes.
+type SessionBadgeRule struct {
+	BranchPrefix string `yaml:"branch_prefix,omitempty" json:"branch_prefix,omitempty"`
+	Repo         string `yaml:"repo,omitempty" json:"repo,omitempty"`
+	Dirty        bool   `yaml:"dirty,omitempty" json:"dirty,omitempty"`
+	Color        string `yaml:"color,omitempty" json:"color,omitempty"`
+	Emoji        string `yaml:"emoji,omitempty" json:"emoji,omitempty"`
+}
+
+// GitIdentity is a git author/committer profile applied to the panes and
+// worktree commits of matching sessions. Every non-empty matcher must match;
+// entries are evaluated in order and the first match wins.
+//
+// Session is a glob matched against the session name. Repo matches the
+// repository base name (case-insensitive) or absolute path, like
+// shell_rules.repo; for worktree sessions the main repository is used.
+// SigningKey, when set, enables commit signing with that key.
+type GitIdentity struct {
+	Session    string `yaml:"session,omitempty" json:"session,omitempty"`
+	Repo       string `yaml:"repo,omitempty" json:"repo,omitempty"`
+	Name       string `yaml:"name" json:"name"`
+	Email      string `yaml:"email" json:"email"`
+	SigningKey string `yaml:"signing_key,omitempty" json:"signing_key,omitempty"`
+}
+
+// Webhook POSTs the listed runtime events as JSON to URL. Events holds event
+// names (see WebhookEvents) or "*" for all of them. When Secret is set, each
+// body is signed with HMAC-SHA256 in the X-MyTX-Signature-256 header.
+type Webhook struct {
+	URL    string   `yaml:"url" json:"url"`
+	Events []string `yaml:"events" json:"events"`
+	Secret string   `yaml:"secret,omitempty" json:"secret,omitempty"`
+}
+
+// TrustedShell is a shell executable outside the built-in allowlist that the
+// user explicitly trusts (e.g. nushell or Git Bash at a non-standard path).
+// Path must be absolute. When SHA256 is set, the file content must match the
+// hex digest for the entry to be honored.
+type TrustedShell struct {
+	Path   string `yaml:"path" json:"path"`
+	SHA256 string `yaml:"sha256,omitempty" json:"sha256,omitempty"`
+}
+
+// StartupCommandsConfig holds commands typed into the first pane of new
+// sessions and windows. Session applies to sessions created from the UI when
+// CreateSessionOptions carries no command; Window applies to new-window
+// requests without a positional shell command.
+// RemainOnExit keeps the pane open after the command exits so failures stay
+// inspectable.
+type StartupCommandsConfig struct {
+	Session      string `yaml:"session,omitempty" json:"session,omitempty"`
+	Window       string `yaml:"window,omitempty" json:"window,omitempty"`
+	RemainOnExit bool   `yaml:"remain_on_exit,omitempty" json:"remain_on_exit,omitempty"`
+}
+
+// SessionPoolConfig sizes the warm shell pool. Size is the number of idle
+// shells kept running for the default shell; MaxIdleMinutes recycles a warm
+// shell that has waited longer than that (0 keeps it until used or drained).
+type SessionPoolConfig struct {
+	Size           int `yaml:"size,omitempty" json:"size,omitempty"`
+	MaxIdleMinutes int `yaml:"max_idle_minutes,omitempty" json:"max_idle_minutes,omitempty"`
+}
+
+// ToolPathsConfig selects the tool manifests that add PATH entries to new
+// panes. Kinds lists manifest kinds ("node", "asdf", "go"); empty means all.
+// The repo-local node_modules/.bin is only added for directories trusted in
+// the repo config trust store.
+type ToolPathsConfig struct {
+	Kinds []string `yaml:"kinds,omitempty" json:"kinds,omitempty"`
+}
+
+// ForgeConfig configures pull request creation. Kind is "github" or "gitlab";
+// empty detects it from the host of the pushed remote. APIURL overrides the
+// REST API root, e.g. for GitHub Enterprise Server. The token is read from
+// Token, or from the environment variable TokenEnv when Token is empty.
+// CreateOnPush opens a pull request after every push from the commit dialog.
+type ForgeConfig struct {
+	Kind         string `yaml:"kind,omitempty" json:"kind,omitempty"`
+	APIURL       string `yaml:"api_url,omitempty" json:"api_url,omitempty"`
+	Token        string `yaml:"token,omitempty" json:"token,omitempty"`
+	TokenEnv     string `yaml:"token_env,omitempty" json:"token_env,omitempty"`
+	Draft        bool   `yaml:"draft,omitempty" json:"draft,omitempty"`
+	CreateOnPush bool   `yaml:"create_on_push,omitempty" json:"create_on_push,omitempty"`
+}
+
+// LoggingConfig configures the app log. Level is debug, info (default),
+// warn, or error; Format is text (default) or json. Unless DisableFiles is
+// set, records are also written to <component>.log files in the "logs"
+// directory next to the config file, rotated at MaxFileMB and keeping
+// KeepFiles generations each.
+type LoggingConfig struct {
+	Level        string `yaml:"level,omitempty" json:"level,omitempty"`
+	Format       string `yaml:"format,omitempty" json:"format,omitempty"`
+	MaxFileMB    int    `yaml:"max_file_mb,omitempty" json:"max_file_mb,omitempty"`
+	KeepFiles    int    `yaml:"keep_files,omitempty" json:"keep_files,omitempty"`
+	DisableFiles bool   `yaml:"disable_files,omitempty" json:"disable_files,omitempty"`
+}
+
+// MetricsConfig configures the Prometheus metrics endpoint. When Enabled,
+// http://127.0.0.1:<Port>/metrics serves the host metrics; Port 0 uses
+// DefaultMetricsPort.
+type MetricsConfig struct {
+	Enabled bool `yaml:"enabled,omitempty" json:"enabled,omitempty"`
+	Port    int  `yaml:"port,omitempty" json:"port,omitempty"`
+}
+
+// FileDropConfig configures files dropped on a pane. Mode is "path"
+// (default), "copy", or "link"; TypePath is "on" (default) or "off" and
+// decides whether the resulting paths are typed into the pane; MaxSizeMB
+// caps the total size of one copy, 0 using DefaultFileDropMaxSizeMB.
+type FileDropConfig struct {
+	Mode      string `yaml:"mode,omitempty" json:"mode,omitempty"`
+	TypePath  string `yaml:"type_path,omitempty" json:"type_path,omitempty"`
+	MaxSizeMB int    `yaml:"max_size_mb,omitempty" json:"max_size_mb,omitempty"`
+}
+
+// ResourceLimits caps the CPU and memory of a session's pane processes.
+// Zero fields are unlimited.
+type ResourceLimits struct {
+	// MemoryMB limits the committed memory of all the session's processes
+	// together.
+	MemoryMB int `yaml:"memory_mb,omitempty" json:"memory_mb,omitempty"`
+	// CPUPercent caps the session's processes to this share of the whole
+	// machine (all cores), from 1 to 100.
+	CPUPercent int `yaml:"cpu_percent,omitempty" json:"cpu_percent,omitempty"`
+}
+
+// ResourceLimitsConfig holds the default session resource limits and
+// per-session overrides keyed by session name. A session entry replaces the
+// defaults as a whole.
+type ResourceLimitsConfig struct {
+	MemoryMB   int                       `yaml:"memory_mb,omitempty" json:"memory_mb,omitempty"`
+	CPUPercent int                       `yaml:"cpu_percent,omitempty" json:"cpu_percent,omitempty"`
+	Sessions   map[string]ResourceLimits `yaml:"sessions,omitempty" json:"sessions,omitempty"`
+}
+
+// ActivityDigestConfig configures the daily activity digest.
+type ActivityDigestConfig struct {
+	// Dir is where the digests are saved. Empty uses the "digests"
+	// directory next to config.yaml.
+	Dir string `yaml:"dir,omitempty" json:"dir,omitempty"`
+	// CostPerActiveHour estimates each session's cost from its active time.
+	// Zero omits the estimate.
+	CostPerActiveHour float64 `yaml:"cost_per_active_hour,omitempty" json:"cost_per_active_hour,omitempty"`
+}
+
+// RepoConfigSettings controls signature checks on per-repository .mytx.yaml
+// files. SigningKeys are authorized_keys-style public keys ("ssh-ed25519
+// AAAA... comment"); once any are configured, a repo config is only honored
+// when its .mytx.yaml.sig signature verifies against one of them, and
+// unsigned repo configs are rejected. RequireSignature rejects unsigned repo
+// configs even without keys.
+type RepoConfigSettings struct {
+	SigningKeys      []string `yaml:"signing_keys,omitempty" json:"signing_keys,omitempty"`
+	RequireSignature bool     `yaml:"require_signature,omitempty" json:"require_signature,omitempty"`
+}
+
+// SessionTemplate declares a session that the orchestrated bring-up creates.
+// Name is the session name. Command runs in the initial pane. DependsOn
+// lists templates that must be up (and healthy, when they declare Health)
+// before this one starts. Dir defaults to default_session_dir.
+// Env is set on every pane of the session. Panes are split off after the
+// initial pane, in order, and Layout (a built-in preset name or a tmux layout
+// string) is applied once they all exist.
+type SessionTemplate struct {
+	Name      string                `yaml:"name" json:"name"`
+	Dir       string                `yaml:"dir,omitempty" json:"dir,omitempty"`
+	Command   string                `yaml:"command,omitempty" json:"command,omitempty"`
+	DependsOn []string              `yaml:"depends_on,omitempty" json:"depends_on,omitempty"`
+	Health    *SessionHealthProbe   `yaml:"health,omitempty" json:"health,omitempty"`
+	Env       map[string]string     `yaml:"env,omitempty" json:"env,omitempty"`
+	Panes     []SessionTemplatePane `yaml:"panes,omitempty" json:"panes,omitempty"`
+	Layout    string                `yaml:"layout,omitempty" json:"layout,omitempty"`
+}
+
+// SessionTemplatePane is an additional pane of a session template. Split is
+// "horizontal" (left/right) or "vertical" (top/bottom, the default) and
+// splits the previously created pane. Env is applied on top of the template
+// Env.
+type SessionTemplatePane struct {
+	Command string            `yaml:"command,omitempty" json:"command,omitempty"`
+	Env     map[string]string `yaml:"env,omitempty" json:"env,omitempty"`
+	Split   string            `yaml:"split,omitempty" json:"split,omitempty"`
+}
+
+// SessionHealthProbe decides when a template session is healthy: the TCP
+// Port on 127.0.0.1 accepts connections and/or Command exits 0. Both are
+// polled every IntervalSeconds until TimeoutSeconds elapse.
+type SessionHealthProbe struct {
+	Port            int    `yaml:"port,omitempty" json:"port,omitempty"`
+	Command         string `yaml:"command,omitempty" json:"command,omitempty"`
+	IntervalSeconds int    `yaml:"interval_seconds,omitempty" json:"interval_seconds,omitempty"`
+	TimeoutSeconds  int    `yaml:"timeout_seconds,omitempty" json:"timeout_seconds,omitempty"`
+}
+
+// CommandTriggerAction is what happens when a shell command in a pane
+// finishes. Exit codes come from shell integration markers, so panes whose
+// shell does not emit them never fire triggers.
+// Hook is a single-line command run through cmd.exe with MYTX_PANE_ID,
+// MYTX_SESSION, and MYTX_EXIT_CODE set.
+type CommandTriggerAction struct {
+	Notify bool   `yaml:"notify,omitempty" json:"notify,omitempty"`
+	Hook   string `yaml:"hook,omitempty" json:"hook,omitempty"`
+}
+
+// CommandTriggers holds the actions for failed and successful commands.
+type CommandTriggers struct {
+	OnFailure CommandTriggerAction `yaml:"on_failure,omitempty" json:"on_failure,omitempty"`
+	OnSuccess CommandTriggerAction `yaml:"on_success,omitempty" json:"on_success,omitempty"`
+}
+
+// CommandTriggersConfig holds the global command triggers and per-session
+// overrides keyed by session name. A session entry replaces the global
+// triggers as a whole.
+type CommandTriggersConfig struct {
+	OnFailure CommandTriggerAction       `yaml:"on_failure,omitempty" json:"on_failure,omitempty"`
+	OnSuccess CommandTriggerAction       `yaml:"on_success,omitempty" json:"on_success,omitempty"`
+	Sessions  map[string]CommandTriggers `yaml:"sessions,omitempty" json:"sessions,omitempty"`
+}
+
 // ClaudeEnvConfig holds Claude Code environment variable settings.
 // Vars contains key-value pairs applied to terminal panes.
 // DefaultEnabled controls the checkbox default in the new session modal.
@@ -41,6 +283,23 @@ type MCPServerConfig struct {
 	Enabled      bool                   `yaml:"enabled" json:"enabled"`
 	UsageSample  string                 `yaml:"usage_sample,omitempty" json:"usage_sample,omitempty"`
 	ConfigParams []MCPServerConfigParam `yaml:"config_params,omitempty" json:"config_params,omitempty"`
+	// Permissions restricts the server process. Nil leaves it unrestricted.
+	Permissions *MCPServerPermissions `yaml:"permissions,omitempty" json:"permissions,omitempty"`
+}
+
+// MCPServerPermissions is the launch manifest of an MCP server. EnvKeys is
+// enforced by filtering the process environment. FilesystemPaths and
+// NetworkHosts are a launch-time lint of the work directory, arguments, and
+// environment: the running process is not prevented from reaching other
+// paths or hosts.
+// Relative filesystem paths are resolved against the session work directory.
+// Network hosts may use a leading "*." wildcard for subdomains.
+type MCPServerPermissions struct {
+	FilesystemPaths []string `yaml:"filesystem_paths,omitempty" json:"filesystem_paths,omitempty"`
+	NetworkHosts    []string `yaml:"network_hosts,omitempty" json:"network_hosts,omitempty"`
+	// EnvKeys lists the inherited environment variables visible to the
+	// process. Variables set in Env are always visible.
+	EnvKeys []string `yaml:"env_keys,omitempty" json:"env_keys,omitempty"`
 }
 
 // MCPServerConfigParam describes a user-configurable parameter for an MCP
@@ -84,6 +343,8 @@ type AgentModelOverride struct {
 // These fields are editable through the settings UI modal, which writes back
 // to the same protected config file. This is the intended configuration flow.
 // Do NOT expose these fields to untrusted sources (e.g. session metadata from git).
+// Repository-provided scripts live in .mytx.yaml (internal/repoconfig) and only
+// run once the user has trusted the directory.
 type WorktreeConfig struct {
 	Enabled                   bool     `yaml:"enabled" json:"enabled"`
 	ForceCleanup              bool     `yaml:"force_cleanup" json:"force_cleanup"`                               // Skip uncommitted changes check when removing worktree
@@ -91,6 +352,41 @@ type WorktreeConfig struct {
 	SetupScriptTimeoutSeconds int      `yaml:"setup_script_timeout_seconds" json:"setup_script_timeout_seconds"` // Per-script timeout for setup_scripts
 	CopyFiles                 []string `yaml:"copy_files" json:"copy_files"`
 	CopyDirs                  []string `yaml:"copy_dirs" json:"copy_dirs"` // Directories to recursively copy from repo to worktree
+	// CopyFilesEOL is the line ending policy for copy_files: empty copies
+	// bytes verbatim; see the CopyFilesEOL* constants for the other values.
+	CopyFilesEOL string `yaml:"copy_files_eol,omitempty" json:"copy_files_eol,omitempty"`
+	// MergeTool resolves conflicted files: a git mergetool name (e.g.
+	// "vscode") or a command using $BASE, $LOCAL, $REMOTE, and $MERGED.
+	// Empty uses merge.tool from git config.
+	MergeTool string `yaml:"merge_tool,omitempty" json:"merge_tool,omitempty"`
+	// AllowShared lets several sessions open the same existing worktree.
+	// Each session keeps the branch it was opened on as its branch context,
+	// and a checkout that moves the worktree away from it is reported.
+	AllowShared bool `yaml:"allow_shared,omitempty" json:"allow_shared,omitempty"`
+	// BaseRefresh keeps worktree branches current with their base branch.
+	// Nil disables it.
+	BaseRefresh *WorktreeBaseRefreshConfig `yaml:"base_refresh,omitempty" json:"base_refresh,omitempty"`
+	// SetupWatchers re-run scripts in worktree sessions when the dependency
+	// files they install from change, keeping dependencies current.
+	SetupWatchers []SetupWatcher `yaml:"setup_watchers,omitempty" json:"setup_watchers,omitempty"`
+}
+
+// SetupWatcher re-runs Script in a worktree after one of Files (paths
+// relative to the worktree root, e.g. "package-lock.json") changes. The
+// script usually repeats a setup_scripts entry, e.g. "npm install".
+type SetupWatcher struct {
+	Script string   `yaml:"script" json:"script"`
+	Files  []string `yaml:"files" json:"files"`
+}
+
+// WorktreeBaseRefreshConfig configures checks for a worktree's base branch
+// advancing upstream. Mode is one of the BaseRefresh* constants. The check
+// runs when a worktree session is activated (OnFocus) and every
+// IntervalMinutes (0 disables the schedule).
+type WorktreeBaseRefreshConfig struct {
+	Mode            string `yaml:"mode" json:"mode"`
+	OnFocus         bool   `yaml:"on_focus,omitempty" json:"on_focus,omitempty"`
+	IntervalMinutes int    `yaml:"interval_minutes,omitempty" json:"interval_minutes,omitempty"`
 }
 
 // SetupScriptTimeout returns the configured per-script timeout with defaults
@@ -149,9 +149,18 @@ func applyDefaultsAndValidate(cfg *Config) error {
 	if cfg.Shell == "" {
 		cfg.Shell = defaults.Shell
 	}
-	if err := validateShell(cfg.Shell); err != nil {
+	trustedShells := sanitizeTrustedShells(cfg)
+	if isUnverifiedTrustedShell(cfg, cfg.Shell, trustedShells) {
+		// A configured trusted shell that is temporarily missing or fails its
+		// hash check must not block startup; fall back to the default shell.
+		slog.Warn("[WARN-CONFIG] shell is a trusted_shells entry that failed verification, falling back to default",
+			"configured", cfg.Shell, "default", defaults.Shell)
+		cfg.Shell = defaults.Shell
+	}
+	if err := validateShell(cfg.Shell, trustedShells); err != nil {
 		return err
 	}
+	sanitizeShellRules(cfg, trustedShells)
 	if cfg.Prefix == "" {
 		cfg.Prefix = defaults.Prefix
 	}
@@ -196,6 +205,30 @@ func applyDefaultsAndValidate(cfg *Config) error {
 	sanitizeClaudeEnv(cfg)
 	sanitizeMCPServers(cfg)
 	sanitizeTaskScheduler(cfg)
+	sanitizeStartupCommands(cfg)
+	sanitizeSessionBadgeRules(cfg)
+	sanitizeCommandTriggers(cfg)
+	sanitizeSessionTemplates(cfg)
+	sanitizeRepoConfig(cfg)
+	sanitizeSessionPool(cfg)
+	sanitizeCopyFilesEOL(cfg)
+	sanitizeMergeTool(cfg)
+	sanitizeGitIdentities(cfg)
+	sanitizeWebhooks(cfg)
+	sanitizeToolPaths(cfg)
+	sanitizeTmuxCompat(cfg)
+	sanitizeMouse(cfg)
+	sanitizePasteConfirm(cfg)
+	sanitizeActivityDigest(cfg)
+	sanitizeWorktreeBaseRefresh(cfg)
+	sanitizeSetupWatchers(cfg)
+	sanitizeFeatureFlags(cfg)
+	sanitizeForge(cfg)
+	sanitizeLogging(cfg)
+	sanitizeMetrics(cfg)
+	sanitizeResourceLimits(cfg)
+	sanitizeFileDrop(cfg)
+	sanitizeEphemeralSessionTTL(cfg)
 	validateDefaultSessionDir(cfg)
 	return nil
 }
@@ -365,14 +398,23 @@ func validateDefaultSessionDirWith(userHomeDirFn func() (string, error), cfg *Co
 		cfg.DefaultSessionDir = ""
 		return
 	}
+	expanded, err := expandSessionDir(userHomeDirFn, dir)
+	if err != nil {
+		slog.Warn("[WARN-CONFIG] default_session_dir is invalid, ignoring", "path", dir, "error", err)
+		cfg.DefaultSessionDir = ""
+		return
+	}
+	cfg.DefaultSessionDir = expanded
+}
+
+// expandSessionDir expands a ~ prefix and environment variables in dir and
+// requires the result to be an absolute path.
+func expandSessionDir(userHomeDirFn func() (string, error), dir string) (string, error) {
 	// Expand ~ prefix to user home directory.
 	if strings.HasPrefix(dir, "~") {
 		home, err := userHomeDirFn()
 		if err != nil {
-			slog.Warn("[WARN-CONFIG] default_session_dir: failed to expand ~, ignoring",
-				"path", dir, "error", err)
-			cfg.DefaultSessionDir = ""
-			return
+			return "", fmt.Errorf("failed to expand ~: %w", err)
 		}
 		dir = filepath.Join(home, dir[1:])
 	}
@@ -380,18 +422,17 @@ func validateDefaultSessionDirWith(userHomeDirFn func() (string, error), cfg *Co
 	dir = expandDefaultSessionDirEnv(dir)
 	dir = filepath.Clean(dir)
 	if !filepath.IsAbs(dir) {
-		slog.Warn("[WARN-CONFIG] default_session_dir is not an absolute path, ignoring", "path", dir)
-		cfg.DefaultSessionDir = ""
-		return
+		return "", errors.New("not an absolute path")
 	}
-	cfg.DefaultSessionDir = dir
+	return dir, nil
 }
 
 // validateShell ensures the configured shell is safe for process creation.
-// It rejects null bytes, verifies the base name against allowedShells,
-// confirms absolute paths exist on disk, and rejects relative paths that
-// could resolve to unintended executables.
-func validateShell(shell string) error {
+// It rejects null bytes, verifies the base name against allowedShells (or the
+// absolute path against verified trusted_shells entries), confirms absolute
+// paths exist on disk, and rejects relative paths that could resolve to
+// unintended executables.
+func validateShell(shell string, trusted trustedShellSet) error {
 	shell = strings.TrimSpace(shell)
 	if shell == "" {
 		return errors.New("shell is required")
@@ -400,9 +441,14 @@ func validateShell(shell string) error {
 		return errors.New("shell contains invalid null byte")
 	}
 
+	if trusted.contains(shell) {
+		// Existence was verified by sanitizeTrustedShells.
+		return nil
+	}
+
 	baseName := CanonicalShellBaseName(shell)
 	if _, ok := allowedShells[baseName]; !ok {
-		return fmt.Errorf("shell %q is not in the allowlist", shell)
+		return fmt.Errorf("shell %q is not in the allowlist or trusted_shells", shell)
 	}
 
 	if filepath.IsAbs(shell) {
@@ -757,6 +803,7 @@ func sanitizeMCPServers(cfg *Config) {
 		}
 		server.Env = sanitizeEnvMap(server.Env, fmt.Sprintf("mcp_servers[%d].env", i))
 		server.ConfigParams = sanitizeMCPServerConfigParams(server.ConfigParams, server.ID)
+		server.Permissions = sanitizeMCPServerPermissions(server.Permissions, server.ID)
 
 		filtered = append(filtered, server)
 	}
@@ -796,6 +843,64 @@ func sanitizeMCPServerConfigParams(params []MCPServerConfigParam, mcpID string)
 	return filtered
 }
 
+// sanitizeMCPServerPermissions trims and de-duplicates a permissions block.
+// Invalid items are dropped; the block itself is kept even when it ends up
+// empty, because an empty manifest still means "no inherited env, workspace
+// and hosts denied" rather than "unrestricted".
+func sanitizeMCPServerPermissions(perms *MCPServerPermissions, mcpID string) *MCPServerPermissions {
+	if perms == nil {
+		return nil
+	}
+	var paths, hosts, keys []string
+	for _, path := range uniqueTrimmed(perms.FilesystemPaths) {
+		if strings.ContainsRune(path, 0) {
+			slog.Warn("[WARN-CONFIG] mcp_servers permissions filesystem path contains null byte, skipping", "id", mcpID)
+			continue
+		}
+		paths = append(paths, filepath.Clean(path))
+	}
+	for _, host := range uniqueTrimmed(perms.NetworkHosts) {
+		host = strings.ToLower(host)
+		if strings.ContainsAny(host, "/\\ \t@") {
+			slog.Warn("[WARN-CONFIG] mcp_servers permissions network host is not a host name, skipping", "id", mcpID, "host", host)
+			continue
+		}
+		hosts = append(hosts, host)
+	}
+	for _, key := range uniqueTrimmed(perms.EnvKeys) {
+		if strings.ContainsAny(key, "=\x00") {
+			slog.Warn("[WARN-CONFIG] mcp_servers permissions env key is invalid, skipping", "id", mcpID, "key", key)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	// Normalization can make distinct inputs equal, so de-duplicate again.
+	return &MCPServerPermissions{
+		FilesystemPaths: uniqueTrimmed(paths),
+		NetworkHosts:    uniqueTrimmed(hosts),
+		EnvKeys:         keys,
+	}
+}
+
+// uniqueTrimmed returns the non-empty trimmed items of values in order,
+// without duplicates.
+func uniqueTrimmed(values []string) []string {
+	var result []string
+	seen := make(map[string]struct{}, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		if _, ok := seen[value]; ok {
+			continue
+		}
+		seen[value] = struct{}{}
+		result = append(result, value)
+	}
+	return result
+}
+
 // sanitizeEnvMap validates and cleans environment variable entries.
 // It removes entries with empty keys, null bytes in keys, '=' in keys,
 // and strips null bytes from values. Values are trimmed but allowed to be empty
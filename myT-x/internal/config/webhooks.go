@@ -0,0 +1,103 @@
+package config
+
+import (
+	"log/slog"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+const (
+	// MaxWebhooks caps webhooks entries.
+	MaxWebhooks = 20
+	// WebhookAllEvents subscribes an entry to every event in WebhookEvents.
+	WebhookAllEvents = "*"
+)
+
+// WebhookEvents lists the runtime events that webhooks may subscribe to.
+// Chatty events (pane output, snapshots) are deliberately excluded.
+var WebhookEvents = []string{
+	"worktree:setup-complete",
+	"worktree:cleanup-failed",
+	"worktree:pull-failed",
+	"worktree:copy-files-failed",
+	"worktree:copy-dirs-failed",
+	"pane:command-finished",
+	"tmux:pane-exited",
+	"session:approval-pending",
+	"session:cleanup-degraded",
+	"output-watch:matched",
+	"task-scheduler:stopped",
+	"single-task-runner:stopped",
+	"scheduler:stopped",
+	"activity-digest:generated",
+}
+
+// sanitizeWebhooks validates webhooks entries in place.
+// Entries with an invalid URL or without a supported event are dropped with a
+// warning so that a single bad entry never blocks startup.
+func sanitizeWebhooks(cfg *Config) {
+	if len(cfg.Webhooks) == 0 {
+		cfg.Webhooks = nil
+		return
+	}
+
+	filtered := make([]Webhook, 0, min(len(cfg.Webhooks), MaxWebhooks))
+	for i, hook := range cfg.Webhooks {
+		hook.URL = strings.TrimSpace(hook.URL)
+		hook.Secret = strings.TrimSpace(hook.Secret)
+
+		if !isValidWebhookURL(hook.URL) {
+			// The URL often carries a credential; do not log it.
+			slog.Warn("[WARN-CONFIG] webhooks entry url must be an absolute http(s) URL, skipping", "index", i)
+			continue
+		}
+		events := make([]string, 0, len(hook.Events))
+		for _, event := range hook.Events {
+			event = strings.TrimSpace(event)
+			if event != WebhookAllEvents && !slices.Contains(WebhookEvents, event) {
+				slog.Warn("[WARN-CONFIG] webhooks entry has unsupported event, ignoring", "index", i, "event", event)
+				continue
+			}
+			if !slices.Contains(events, event) {
+				events = append(events, event)
+			}
+		}
+		if len(events) == 0 {
+			slog.Warn("[WARN-CONFIG] webhooks entry has no supported events, skipping", "index", i)
+			continue
+		}
+		hook.Events = events
+
+		filtered = append(filtered, hook)
+		if len(filtered) == MaxWebhooks {
+			if i < len(cfg.Webhooks)-1 {
+				slog.Warn("[WARN-CONFIG] webhooks exceeds maximum, truncating",
+					"count", len(cfg.Webhooks), "max", MaxWebhooks)
+			}
+			break
+		}
+	}
+	if len(filtered) == 0 {
+		cfg.Webhooks = nil
+		return
+	}
+	cfg.Webhooks = filtered
+}
+
+func isValidWebhookURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// SubscribedEvents returns the event names the webhook receives, expanding
+// WebhookAllEvents.
+func (hook Webhook) SubscribedEvents() []string {
+	if slices.Contains(hook.Events, WebhookAllEvents) {
+		return slices.Clone(WebhookEvents)
+	}
+	return slices.Clone(hook.Events)
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWebhookFieldCountGuard(t *testing.T) {
+	if got := reflect.TypeFor[Webhook]().NumField(); got != 3 {
+		t.Fatalf("Webhook field count = %d, want 3; update sanitizeWebhooks, cloneWebhooks, and this assertion", got)
+	}
+}
+
+func TestSanitizeWebhooks(t *testing.T) {
+	cfg := Config{Webhooks: []Webhook{
+		{URL: " https://hooks.example.com/a ", Events: []string{" worktree:setup-complete ", "worktree:setup-complete", "tmux:pane-output"}, Secret: " s "},
+		{URL: "ftp://hooks.example.com", Events: []string{"*"}},          // unsupported scheme
+		{URL: "https:///path-only", Events: []string{"*"}},               // no host
+		{URL: "http://localhost:5678/hook", Events: []string{"unknown"}}, // no supported events
+		{URL: "http://localhost:5678/hook", Events: []string{"*"}},
+	}}
+	sanitizeWebhooks(&cfg)
+	want := []Webhook{
+		{URL: "https://hooks.example.com/a", Events: []string{"worktree:setup-complete"}, Secret: "s"},
+		{URL: "http://localhost:5678/hook", Events: []string{"*"}},
+	}
+	if !reflect.DeepEqual(cfg.Webhooks, want) {
+		t.Fatalf("Webhooks = %+v, want %+v", cfg.Webhooks, want)
+	}
+
+	cfg = Config{Webhooks: []Webhook{{URL: "not a url", Events: []string{"*"}}}}
+	sanitizeWebhooks(&cfg)
+	if cfg.Webhooks != nil {
+		t.Fatalf("all-invalid webhooks = %+v, want nil", cfg.Webhooks)
+	}
+}
+
+func TestSanitizeWebhooksTruncates(t *testing.T) {
+	hooks := make([]Webhook, MaxWebhooks+5)
+	for i := range hooks {
+		hooks[i] = Webhook{URL: "https://example.com", Events: []string{"*"}}
+	}
+	cfg := Config{Webhooks: hooks}
+	sanitizeWebhooks(&cfg)
+	if len(cfg.Webhooks) != MaxWebhooks {
+		t.Fatalf("len(Webhooks) = %d, want %d", len(cfg.Webhooks), MaxWebhooks)
+	}
+}
+
+func TestWebhookSubscribedEvents(t *testing.T) {
+	all := Webhook{Events: []string{WebhookAllEvents}}.SubscribedEvents()
+	if !reflect.DeepEqual(all, WebhookEvents) {
+		t.Fatalf("SubscribedEvents(*) = %v, want %v", all, WebhookEvents)
+	}
+	all[0] = "mutated"
+	if WebhookEvents[0] == "mutated" {
+		t.Fatal("SubscribedEvents must not alias WebhookEvents")
+	}
+
+	got := Webhook{Events: []string{"pane:command-finished"}}.SubscribedEvents()
+	if !reflect.DeepEqual(got, []string{"pane:command-finished"}) {
+		t.Fatalf("SubscribedEvents = %v", got)
+	}
+}
+
+func TestCloneWebhooksDeepCopy(t *testing.T) {
+	src := Config{Webhooks: []Webhook{{URL: "https://example.com", Events: []string{"*"}}}}
+	dst := Clone(src)
+	dst.Webhooks[0].Events[0] = "mutated"
+	if src.Webhooks[0].Events[0] != "*" {
+		t.Fatal("Clone() should deep-copy Webhooks events")
+	}
+}
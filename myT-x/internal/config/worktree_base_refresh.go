@@ -0,0 +1,48 @@
+package config
+
+import (
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// Modes of worktree.base_refresh.
+const (
+	// BaseRefreshNotify reports that the base branch advanced and leaves the
+	// rebase or merge to the user.
+	BaseRefreshNotify = "notify"
+	// BaseRefreshRebase rebases the worktree branch onto the advanced base.
+	BaseRefreshRebase = "rebase"
+	// BaseRefreshMerge merges the advanced base into the worktree branch.
+	BaseRefreshMerge = "merge"
+)
+
+// Interval returns the period of scheduled checks, or 0 when only focus
+// triggers them.
+func (cfg WorktreeBaseRefreshConfig) Interval() time.Duration {
+	return time.Duration(cfg.IntervalMinutes) * time.Minute
+}
+
+// sanitizeWorktreeBaseRefresh normalizes worktree.base_refresh in place. An
+// unknown mode disables the policy rather than guessing between notifying
+// and rewriting branches.
+func sanitizeWorktreeBaseRefresh(cfg *Config) {
+	refresh := cfg.Worktree.BaseRefresh
+	if refresh == nil {
+		return
+	}
+	refresh.Mode = strings.ToLower(strings.TrimSpace(refresh.Mode))
+	switch refresh.Mode {
+	case BaseRefreshNotify, BaseRefreshRebase, BaseRefreshMerge:
+	default:
+		slog.Warn("[WARN-CONFIG] worktree.base_refresh mode must be notify, rebase, or merge, disabling it",
+			"mode", refresh.Mode)
+		cfg.Worktree.BaseRefresh = nil
+		return
+	}
+	if refresh.IntervalMinutes < 0 {
+		slog.Warn("[WARN-CONFIG] worktree.base_refresh interval_minutes must not be negative, disabling the schedule",
+			"intervalMinutes", refresh.IntervalMinutes)
+		refresh.IntervalMinutes = 0
+	}
+}
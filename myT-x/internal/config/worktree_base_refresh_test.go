@@ -0,0 +1,42 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWorktreeBaseRefreshConfigFieldCountGuard(t *testing.T) {
+	if got := reflect.TypeFor[WorktreeBaseRefreshConfig]().NumField(); got != 3 {
+		t.Fatalf("WorktreeBaseRefreshConfig field count = %d, want 3; update sanitizeWorktreeBaseRefresh, Clone, and this assertion", got)
+	}
+}
+
+func TestSanitizeWorktreeBaseRefresh(t *testing.T) {
+	refresh := WorktreeBaseRefreshConfig{Mode: " Rebase ", OnFocus: true, IntervalMinutes: -5}
+	cfg := Config{Worktree: WorktreeConfig{BaseRefresh: &refresh}}
+	sanitizeWorktreeBaseRefresh(&cfg)
+	want := WorktreeBaseRefreshConfig{Mode: BaseRefreshRebase, OnFocus: true}
+	if *cfg.Worktree.BaseRefresh != want {
+		t.Fatalf("BaseRefresh = %+v, want %+v", *cfg.Worktree.BaseRefresh, want)
+	}
+
+	cfg = Config{Worktree: WorktreeConfig{BaseRefresh: &WorktreeBaseRefreshConfig{Mode: "squash"}}}
+	sanitizeWorktreeBaseRefresh(&cfg)
+	if cfg.Worktree.BaseRefresh != nil {
+		t.Fatalf("BaseRefresh with an unknown mode = %+v, want nil", cfg.Worktree.BaseRefresh)
+	}
+
+	if got := (WorktreeBaseRefreshConfig{IntervalMinutes: 30}).Interval(); got != 30*time.Minute {
+		t.Fatalf("Interval() = %v, want 30m", got)
+	}
+}
+
+func TestCloneWorktreeBaseRefresh(t *testing.T) {
+	src := Config{Worktree: WorktreeConfig{BaseRefresh: &WorktreeBaseRefreshConfig{Mode: BaseRefreshNotify}}}
+	dst := Clone(src)
+	dst.Worktree.BaseRefresh.Mode = BaseRefreshMerge
+	if src.Worktree.BaseRefresh.Mode != BaseRefreshNotify {
+		t.Fatal("Clone() shares worktree.base_refresh with the source")
+	}
+}
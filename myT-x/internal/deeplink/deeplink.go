@@ -0,0 +1,143 @@
+// Package deeplink parses mytx:// URLs and registers the protocol handler.
+//
+// Supported links:
+//
+//	mytx://open?path=C:\repo   focus the session rooted at C:\repo, or create it
+//	mytx://session/<name>      focus an existing session
+//	mytx://action/<action>     run a UI action (new-session, new-worktree-session)
+package deeplink
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Scheme is the registered URL scheme.
+const Scheme = "mytx"
+
+// maxLinkLen bounds links taken from the command line.
+const maxLinkLen = 2048
+
+// Kind is the action a link requests.
+type Kind string
+
+const (
+	KindOpen    Kind = "open"
+	KindSession Kind = "session"
+	KindAction  Kind = "action"
+)
+
+// Action is a UI action requested by a KindAction link. Actions are
+// performed by the frontend; the backend only validates and forwards them.
+type Action string
+
+const (
+	ActionNewSession         Action = "new-session"
+	ActionNewWorktreeSession Action = "new-worktree-session"
+)
+
+// Link is a parsed mytx:// URL.
+type Link struct {
+	Kind Kind
+	// Path is the directory of a KindOpen link.
+	Path string
+	// Session is the session name of a KindSession link.
+	Session string
+	// Action is the UI action of a KindAction link.
+	Action Action
+}
+
+// SessionURL returns the link that focuses sessionName.
+func SessionURL(sessionName string) string {
+	return Scheme + "://" + string(KindSession) + "/" + url.PathEscape(sessionName)
+}
+
+// ActionURL returns the link that runs action.
+func ActionURL(action Action) string {
+	return Scheme + "://" + string(KindAction) + "/" + string(action)
+}
+
+// FromArgs returns the first mytx:// argument. Windows passes the clicked
+// URL as a command-line argument ("myT-x.exe" "mytx://...").
+func FromArgs(args []string) (string, bool) {
+	for _, arg := range args {
+		arg = strings.TrimSpace(arg)
+		if len(arg) > len(Scheme) && strings.EqualFold(arg[:len(Scheme)+1], Scheme+":") {
+			return arg, true
+		}
+	}
+	return "", false
+}
+
+// Parse validates raw and returns the requested action.
+func Parse(raw string) (Link, error) {
+	raw = strings.TrimSpace(raw)
+	if len(raw) > maxLinkLen {
+		return Link{}, fmt.Errorf("link is longer than %d characters", maxLinkLen)
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return Link{}, fmt.Errorf("invalid link: %w", err)
+	}
+	if !strings.EqualFold(u.Scheme, Scheme) {
+		return Link{}, fmt.Errorf("unsupported scheme %q", u.Scheme)
+	}
+
+	switch Kind(strings.ToLower(u.Host)) {
+	case KindOpen:
+		if strings.Trim(u.Path, "/") != "" {
+			return Link{}, fmt.Errorf("unexpected path %q in open link", u.Path)
+		}
+		path := strings.TrimSpace(u.Query().Get("path"))
+		if err := validateOpenPath(path); err != nil {
+			return Link{}, err
+		}
+		return Link{Kind: KindOpen, Path: path}, nil
+	case KindSession:
+		name := strings.TrimSpace(strings.Trim(u.Path, "/"))
+		if name == "" {
+			return Link{}, errors.New("session link has no session name")
+		}
+		if strings.Contains(name, "/") {
+			return Link{}, fmt.Errorf("invalid session name %q", name)
+		}
+		return Link{Kind: KindSession, Session: name}, nil
+	case KindAction:
+		action := Action(strings.ToLower(strings.Trim(u.Path, "/")))
+		switch action {
+		case ActionNewSession, ActionNewWorktreeSession:
+			return Link{Kind: KindAction, Action: action}, nil
+		default:
+			return Link{}, fmt.Errorf("unsupported action %q", action)
+		}
+	default:
+		return Link{}, fmt.Errorf("unsupported link action %q", u.Host)
+	}
+}
+
+// validateOpenPath accepts drive-absolute Windows paths only. Network paths
+// are rejected: a link from a web page must not start a shell on a share.
+func validateOpenPath(path string) error {
+	if path == "" {
+		return errors.New("open link has no path parameter")
+	}
+	if strings.HasPrefix(path, `\\`) || strings.HasPrefix(path, "//") {
+		return fmt.Errorf("network paths are not allowed: %q", path)
+	}
+	if len(path) < 3 || !isDriveLetter(path[0]) || path[1] != ':' || (path[2] != '\\' && path[2] != '/') {
+		return fmt.Errorf("path must be an absolute drive path: %q", path)
+	}
+	return nil
+}
+
+func isDriveLetter(c byte) bool {
+	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+// commandLine is the shell\open\command value that launches exePath with
+// the clicked URL.
+func commandLine(exePath string) string {
+	return `"` + exePath + `" "%1"`
+}
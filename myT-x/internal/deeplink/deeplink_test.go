@@ -0,0 +1,84 @@
+package deeplink
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want Link
+	}{
+		{raw: `mytx://open?path=C:\repo`, want: Link{Kind: KindOpen, Path: `C:\repo`}},
+		{raw: `MYTX://Open/?path=C%3A%5Cwork%5Cmy%20app`, want: Link{Kind: KindOpen, Path: `C:\work\my app`}},
+		{raw: `mytx://open?path=D:/src/api`, want: Link{Kind: KindOpen, Path: `D:/src/api`}},
+		{raw: `mytx://session/frontend`, want: Link{Kind: KindSession, Session: "frontend"}},
+		{raw: `mytx://session/my%20app/`, want: Link{Kind: KindSession, Session: "my app"}},
+		{raw: `mytx://action/new-session`, want: Link{Kind: KindAction, Action: ActionNewSession}},
+		{raw: `mytx://action/New-Worktree-Session/`, want: Link{Kind: KindAction, Action: ActionNewWorktreeSession}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := Parse(tt.raw)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		raw     string
+		wantErr string
+	}{
+		{raw: `https://example.com`, wantErr: "unsupported scheme"},
+		{raw: `mytx://delete?path=C:\repo`, wantErr: "unsupported link action"},
+		{raw: `mytx://open`, wantErr: "no path parameter"},
+		{raw: `mytx://open?path=repo`, wantErr: "absolute drive path"},
+		{raw: `mytx://open?path=\\server\share`, wantErr: "network paths"},
+		{raw: `mytx://open/extra?path=C:\repo`, wantErr: "unexpected path"},
+		{raw: `mytx://session/`, wantErr: "no session name"},
+		{raw: `mytx://session/a/b`, wantErr: "invalid session name"},
+		{raw: `mytx://action/kill-server`, wantErr: "unsupported action"},
+		{raw: "mytx://session/" + strings.Repeat("a", maxLinkLen), wantErr: "longer than"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			_, err := Parse(tt.raw)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Parse() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestURLBuildersRoundTrip(t *testing.T) {
+	link, err := Parse(SessionURL("my app#1"))
+	if err != nil || link != (Link{Kind: KindSession, Session: "my app#1"}) {
+		t.Fatalf("Parse(SessionURL()) = %+v, %v", link, err)
+	}
+	link, err = Parse(ActionURL(ActionNewWorktreeSession))
+	if err != nil || link != (Link{Kind: KindAction, Action: ActionNewWorktreeSession}) {
+		t.Fatalf("Parse(ActionURL()) = %+v, %v", link, err)
+	}
+}
+
+func TestFromArgs(t *testing.T) {
+	if got, ok := FromArgs([]string{"--flag", " MyTx://session/a "}); !ok || got != "MyTx://session/a" {
+		t.Fatalf("FromArgs() = %q, %v", got, ok)
+	}
+	if _, ok := FromArgs([]string{"mcp", "stdio", "mytx"}); ok {
+		t.Fatal("FromArgs() found a link in args without one")
+	}
+}
+
+func TestCommandLine(t *testing.T) {
+	if got := commandLine(`C:\Apps\myT-x.exe`); got != `"C:\Apps\myT-x.exe" "%1"` {
+		t.Fatalf("commandLine() = %q", got)
+	}
+}
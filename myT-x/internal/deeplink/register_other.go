@@ -0,0 +1,8 @@
+//go:build !windows
+
+package deeplink
+
+// EnsureRegistered is a no-op on non-Windows platforms.
+func EnsureRegistered(_ string) (bool, error) {
+	return false, nil
+}
@@ -0,0 +1,61 @@
+//go:build windows
+
+package deeplink
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// classesKeyPath is the per-user protocol registration; HKCU needs no
+// elevation and overrides HKLM.
+const classesKeyPath = `Software\Classes\` + Scheme
+
+// EnsureRegistered registers the mytx:// protocol for the current user so
+// that it launches exePath. It reports whether the registry was changed;
+// an up-to-date registration is left untouched.
+func EnsureRegistered(exePath string) (bool, error) {
+	command := commandLine(exePath)
+	if current, err := readDefault(classesKeyPath + `\shell\open\command`); err == nil && current == command {
+		return false, nil
+	}
+
+	if err := writeValues(classesKeyPath, map[string]string{
+		"":             "URL:myT-x Protocol",
+		"URL Protocol": "",
+	}); err != nil {
+		return false, err
+	}
+	if err := writeValues(classesKeyPath+`\DefaultIcon`, map[string]string{"": `"` + exePath + `",0`}); err != nil {
+		return false, err
+	}
+	if err := writeValues(classesKeyPath+`\shell\open\command`, map[string]string{"": command}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func readDefault(path string) (string, error) {
+	key, err := registry.OpenKey(registry.CURRENT_USER, path, registry.QUERY_VALUE)
+	if err != nil {
+		return "", err
+	}
+	defer key.Close()
+	value, _, err := key.GetStringValue("")
+	return value, err
+}
+
+func writeValues(path string, values map[string]string) error {
+	key, _, err := registry.CreateKey(registry.CURRENT_USER, path, registry.SET_VALUE)
+	if err != nil {
+		return fmt.Errorf("create registry key %s: %w", path, err)
+	}
+	defer key.Close()
+	for name, value := range values {
+		if err := key.SetStringValue(name, value); err != nil {
+			return fmt.Errorf("set registry value %s\\%s: %w", path, name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,386 @@
+package devpanel
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	gitpkg "myT-x/internal/git"
+)
+
+// ── Find and Replace ──
+
+// maxFindReplaceFiles is the maximum number of matching files. A search that
+// matches more files is reported as truncated and cannot be applied.
+const maxFindReplaceFiles = 500
+
+// maxFindReplacePreviewPerFile is the maximum number of previewed matches per file.
+const maxFindReplacePreviewPerFile = 20
+
+// maxFindReplaceQueryLength bounds the query and replacement strings.
+const maxFindReplaceQueryLength = 1000
+
+// findReplaceBackupDirName is the directory under the config directory that
+// holds one backup directory per applied replacement.
+const findReplaceBackupDirName = "find-replace-backups"
+
+// findReplaceFile is a matched file held in memory between planning and apply.
+type findReplaceFile struct {
+	relPath  string
+	absPath  string
+	perm     os.FileMode
+	original []byte
+	replaced []byte
+	summary  FindReplaceFile
+}
+
+// FindReplace searches the session's working directory for query and, when
+// options.Apply is set, replaces every match with replacement.
+//
+// Git repositories search tracked and untracked files that .gitignore does
+// not exclude; other directories are walked with the ListDir exclusions.
+// Binary files, symbolic links, and files over 1 MB are skipped. Applying
+// first copies every file to be changed into a backup directory, then
+// rewrites each file atomically; if any write fails, files already written
+// are restored and no change is left behind.
+func (s *Service) FindReplace(sessionName, query, replacement string, options FindReplaceOptions) (FindReplaceResult, error) {
+	sessionName = strings.TrimSpace(sessionName)
+	if sessionName == "" {
+		return FindReplaceResult{}, errors.New("session name is required")
+	}
+	if query == "" {
+		return FindReplaceResult{}, errors.New("query is required")
+	}
+	if len(query) > maxFindReplaceQueryLength || len(replacement) > maxFindReplaceQueryLength {
+		return FindReplaceResult{}, fmt.Errorf("query and replacement must be at most %d bytes", maxFindReplaceQueryLength)
+	}
+	pattern, err := compileFindReplacePattern(query, options)
+	if err != nil {
+		return FindReplaceResult{}, err
+	}
+	for _, glob := range slices.Concat(options.Include, options.Exclude) {
+		if _, err := path.Match(glob, ""); err != nil {
+			return FindReplaceResult{}, fmt.Errorf("invalid glob %q: %w", glob, err)
+		}
+	}
+
+	rootDir, err := s.resolveSessionWorkDir(sessionName)
+	if err != nil {
+		return FindReplaceResult{}, err
+	}
+	rootDir, err = resolveMutationRoot(rootDir)
+	if err != nil {
+		return FindReplaceResult{}, err
+	}
+
+	candidates, err := listFindReplaceCandidates(rootDir)
+	if err != nil {
+		return FindReplaceResult{}, err
+	}
+
+	result := FindReplaceResult{Files: []FindReplaceFile{}}
+	var matched []*findReplaceFile
+	for _, relPath := range candidates {
+		if !matchesFindReplaceGlobs(relPath, options) {
+			continue
+		}
+		file, ok := planFindReplaceFile(rootDir, relPath, pattern, replacement, options.Regex)
+		if !ok {
+			continue
+		}
+		result.FilesScanned++
+		if file == nil {
+			continue
+		}
+		if len(matched) >= maxFindReplaceFiles {
+			result.Truncated = true
+			break
+		}
+		matched = append(matched, file)
+		result.Files = append(result.Files, file.summary)
+		result.TotalMatches += file.summary.Matches
+	}
+	result.FilesMatched = len(matched)
+
+	if !options.Apply || len(matched) == 0 {
+		return result, nil
+	}
+	if result.Truncated {
+		return FindReplaceResult{}, fmt.Errorf("more than %d files match; narrow the search before applying", maxFindReplaceFiles)
+	}
+
+	backupDir, err := s.backupFindReplaceFiles(matched)
+	if err != nil {
+		return FindReplaceResult{}, err
+	}
+	if err := s.applyFindReplaceFiles(sessionName, matched); err != nil {
+		return FindReplaceResult{}, fmt.Errorf("%w (originals are in %s)", err, backupDir)
+	}
+	result.Applied = true
+	result.BackupDir = backupDir
+	slog.Info("[DEVPANEL] find and replace applied",
+		"session", sessionName, "files", result.FilesMatched, "matches", result.TotalMatches, "backup", backupDir)
+	return result, nil
+}
+
+// compileFindReplacePattern builds the search expression. Literal queries
+// are quoted; WholeWord and case-insensitivity wrap the expression.
+func compileFindReplacePattern(query string, options FindReplaceOptions) (*regexp.Regexp, error) {
+	expr := query
+	if !options.Regex {
+		expr = regexp.QuoteMeta(query)
+	}
+	if options.WholeWord {
+		expr = `\b(?:` + expr + `)\b`
+	}
+	if !options.CaseSensitive {
+		expr = `(?i)` + expr
+	}
+	pattern, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regular expression: %w", err)
+	}
+	if pattern.MatchString("") {
+		return nil, errors.New("query must not match empty text")
+	}
+	return pattern, nil
+}
+
+// matchesFindReplaceGlobs applies Include and Exclude globs. A glob matches
+// either the root-relative path or the file name.
+func matchesFindReplaceGlobs(relPath string, options FindReplaceOptions) bool {
+	matchAny := func(globs []string) bool {
+		for _, glob := range globs {
+			if ok, _ := path.Match(glob, relPath); ok {
+				return true
+			}
+			if ok, _ := path.Match(glob, path.Base(relPath)); ok {
+				return true
+			}
+		}
+		return false
+	}
+	if len(options.Include) > 0 && !matchAny(options.Include) {
+		return false
+	}
+	return !matchAny(options.Exclude)
+}
+
+// listFindReplaceCandidates returns root-relative forward-slash paths of the
+// files to search, sorted.
+func listFindReplaceCandidates(rootDir string) ([]string, error) {
+	if gitpkg.IsGitRepository(rootDir) {
+		output, err := gitpkg.RunGitCLIPublic(rootDir, []string{
+			"-c", "core.quotepath=false",
+			"ls-files", "--cached", "--others", "--exclude-standard", "-z",
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list repository files: %w", err)
+		}
+		paths := parseNULSeparatedGitPaths(output)
+		slices.Sort(paths)
+		// --cached lists a path once per merge stage during conflicts.
+		return slices.Compact(paths), nil
+	}
+
+	var paths []string
+	walkErr := filepath.WalkDir(rootDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // skip inaccessible entries
+		}
+		if d.IsDir() {
+			if slices.Contains(excludedDirs, d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		relPath, relErr := filepath.Rel(rootDir, p)
+		if relErr != nil {
+			return nil
+		}
+		paths = append(paths, filepath.ToSlash(relPath))
+		return nil
+	})
+	if walkErr != nil {
+		return nil, fmt.Errorf("failed to walk directory: %w", walkErr)
+	}
+	slices.Sort(paths)
+	return paths, nil
+}
+
+// planFindReplaceFile reads one file and computes its replaced content.
+// ok is false when the file is skipped (missing, binary, too large, not a
+// regular file); a nil file with ok true means no match.
+func planFindReplaceFile(rootDir, relPath string, pattern *regexp.Regexp, replacement string, expand bool) (file *findReplaceFile, ok bool) {
+	if !filepath.IsLocal(filepath.FromSlash(relPath)) {
+		return nil, false
+	}
+	absPath := filepath.Join(rootDir, filepath.FromSlash(relPath))
+	info, err := os.Lstat(absPath)
+	if err != nil || !info.Mode().IsRegular() || info.Size() > maxFileSize {
+		return nil, false
+	}
+	data, err := os.ReadFile(absPath)
+	if err != nil {
+		slog.Debug("[DEVPANEL] find and replace: skipping unreadable file", "path", relPath, "error", err)
+		return nil, false
+	}
+	if bytes.IndexByte(data[:min(len(data), binaryProbeSize)], 0) >= 0 {
+		return nil, false
+	}
+
+	matches := pattern.FindAllSubmatchIndex(data, -1)
+	if len(matches) == 0 {
+		return nil, true
+	}
+
+	summary := FindReplaceFile{
+		Path:    relPath,
+		Matches: len(matches),
+		Preview: make([]FindReplaceMatch, 0, min(len(matches), maxFindReplacePreviewPerFile)),
+	}
+	var replaced bytes.Buffer
+	replaced.Grow(len(data))
+	last, line, lineStart := 0, 1, 0
+	for _, match := range matches {
+		start, end := match[0], match[1]
+		var value []byte
+		if expand {
+			value = pattern.Expand(nil, []byte(replacement), data, match)
+		} else {
+			value = []byte(replacement)
+		}
+		replaced.Write(data[last:start])
+		replaced.Write(value)
+		last = end
+
+		if len(summary.Preview) >= maxFindReplacePreviewPerFile {
+			continue
+		}
+		for i := lineStart; i < start; i++ {
+			if data[i] == '\n' {
+				line++
+				lineStart = i + 1
+			}
+		}
+		lineEnd := bytes.IndexByte(data[lineStart:], '\n')
+		if lineEnd < 0 {
+			lineEnd = len(data)
+		} else {
+			lineEnd += lineStart
+		}
+		summary.Preview = append(summary.Preview, FindReplaceMatch{
+			Line:        line,
+			Column:      utf8.RuneCount(data[lineStart:start]) + 1,
+			LineText:    truncateUTF8(strings.TrimRight(string(data[lineStart:lineEnd]), "\r"), maxContentLineLength),
+			Match:       truncateUTF8(string(data[start:end]), maxContentLineLength),
+			Replacement: truncateUTF8(string(value), maxContentLineLength),
+		})
+	}
+	replaced.Write(data[last:])
+	summary.PreviewTruncated = len(summary.Preview) < len(matches)
+
+	if bytes.Equal(replaced.Bytes(), data) {
+		// Every match is replaced by itself; nothing would change.
+		return nil, true
+	}
+	return &findReplaceFile{
+		relPath:  relPath,
+		absPath:  absPath,
+		perm:     info.Mode().Perm(),
+		original: data,
+		replaced: replaced.Bytes(),
+		summary:  summary,
+	}, true
+}
+
+// backupFindReplaceFiles copies the original contents of files into a new
+// timestamped directory under the config directory and returns its path.
+func (s *Service) backupFindReplaceFiles(files []*findReplaceFile) (string, error) {
+	if s.deps.ConfigDir == nil {
+		return "", errors.New("backup directory is not configured")
+	}
+	configDir, err := s.deps.ConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve backup directory: %w", err)
+	}
+	backupRoot := filepath.Join(configDir, findReplaceBackupDirName)
+	if err := os.MkdirAll(backupRoot, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create backup directory: %w", err)
+	}
+	backupDir, err := os.MkdirTemp(backupRoot, time.Now().Format("20060102-150405-"))
+	if err != nil {
+		return "", fmt.Errorf("failed to create backup directory: %w", err)
+	}
+	for _, file := range files {
+		target := filepath.Join(backupDir, filepath.FromSlash(file.relPath))
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return "", fmt.Errorf("failed to back up %s: %w", file.relPath, err)
+		}
+		if err := os.WriteFile(target, file.original, 0o644); err != nil {
+			return "", fmt.Errorf("failed to back up %s: %w", file.relPath, err)
+		}
+	}
+	return backupDir, nil
+}
+
+// applyFindReplaceFiles writes the replaced contents. A file changed since it
+// was read, or a failed write, restores the files already written.
+func (s *Service) applyFindReplaceFiles(sessionName string, files []*findReplaceFile) error {
+	paths := make([]string, 0, len(files))
+	for _, file := range files {
+		paths = append(paths, file.relPath)
+	}
+	s.suppressWatcherPaths(sessionName, paths...)
+
+	var applyErr error
+	written := 0
+	for _, file := range files {
+		current, err := os.ReadFile(file.absPath)
+		if err != nil {
+			applyErr = fmt.Errorf("failed to re-read %s: %w", file.relPath, err)
+			break
+		}
+		if !bytes.Equal(current, file.original) {
+			applyErr = fmt.Errorf("%s changed during find and replace", file.relPath)
+			break
+		}
+		if err := retryFileOperation(func() error {
+			return atomicWriteFile(file.absPath, file.replaced, file.perm)
+		}, "write file"); err != nil {
+			applyErr = fmt.Errorf("failed to write %s: %w", file.relPath, err)
+			break
+		}
+		written++
+	}
+
+	if applyErr != nil {
+		for _, file := range files[:written] {
+			if err := retryFileOperation(func() error {
+				return atomicWriteFile(file.absPath, file.original, file.perm)
+			}, "restore file"); err != nil {
+				slog.Warn("[DEVPANEL] find and replace: failed to restore file",
+					"path", file.relPath, "error", err)
+			}
+		}
+		s.unsuppressWatcherPaths(sessionName, paths...)
+		if written == 0 {
+			return applyErr
+		}
+	}
+	s.invalidateDirCache(sessionName, paths...)
+	return applyErr
+}
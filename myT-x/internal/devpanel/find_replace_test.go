@@ -0,0 +1,206 @@
+package devpanel
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newFindReplaceTestService(t *testing.T, rootDir string) (*Service, string) {
+	t.Helper()
+	configDir := t.TempDir()
+	svc := NewService(Deps{
+		ResolveSessionDir: newTestSessionResolver(map[string]string{"test": rootDir}).resolveSessionDir,
+		IsPathWithinBase:  testIsPathWithinBase,
+		ConfigDir:         func() (string, error) { return configDir, nil },
+	})
+	return svc, configDir
+}
+
+func writeFindReplaceFiles(t *testing.T, rootDir string, files map[string]string) {
+	t.Helper()
+	for relPath, content := range files {
+		target := filepath.Join(rootDir, filepath.FromSlash(relPath))
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(target, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func readFindReplaceFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestFindReplacePreviewDoesNotWrite(t *testing.T) {
+	rootDir := t.TempDir()
+	writeFindReplaceFiles(t, rootDir, map[string]string{
+		"a.go":                "package a\n\nvar Old = old()\n",
+		"docs/b.md":           "nothing here\n",
+		"node_modules/c.js":   "Old",
+		"bin/data.bin":        "Old\x00\x01",
+		"docs/notes/old.txt":  "x := OLD\r\n",
+		"docs/notes/keep.txt": "Older",
+	})
+	svc, _ := newFindReplaceTestService(t, rootDir)
+
+	result, err := svc.FindReplace("test", "old", "new", FindReplaceOptions{})
+	if err != nil {
+		t.Fatalf("FindReplace() error = %v", err)
+	}
+	if result.Applied || result.BackupDir != "" {
+		t.Fatalf("preview result = %+v, want not applied", result)
+	}
+	if result.FilesMatched != 3 || result.TotalMatches != 4 {
+		t.Fatalf("matched files = %d matches = %d, want 3 and 4: %+v", result.FilesMatched, result.TotalMatches, result.Files)
+	}
+	first := result.Files[0]
+	if first.Path != "a.go" || len(first.Preview) != 2 {
+		t.Fatalf("first file = %+v, want a.go with two previewed matches", first)
+	}
+	if got := first.Preview[1]; got.Line != 3 || got.Column != 11 || got.Match != "old" || got.Replacement != "new" || got.LineText != "var Old = old()" {
+		t.Fatalf("preview = %+v", got)
+	}
+	if got := result.Files[1].Preview[0]; result.Files[1].Path != "docs/notes/keep.txt" || got.LineText != "Older" {
+		t.Fatalf("second file = %+v", result.Files[1])
+	}
+	if got := result.Files[2].Preview[0].LineText; got != "x := OLD" {
+		t.Fatalf("CRLF line text = %q, want trailing CR trimmed", got)
+	}
+	if got := readFindReplaceFile(t, filepath.Join(rootDir, "a.go")); !strings.Contains(got, "Old") {
+		t.Fatalf("preview modified a.go: %q", got)
+	}
+}
+
+func TestFindReplaceApplyWritesFilesAndBackup(t *testing.T) {
+	rootDir := t.TempDir()
+	initGitRepo(t, rootDir)
+	writeFindReplaceFiles(t, rootDir, map[string]string{
+		".gitignore":       "ignored/\n",
+		"main.go":          "fooBar := foo(1)\nfood := 2\n",
+		"untracked.txt":    "foo",
+		"ignored/skip.txt": "foo",
+	})
+	gitRun(t, rootDir, "add", ".gitignore", "main.go")
+	svc, configDir := newFindReplaceTestService(t, rootDir)
+
+	options := FindReplaceOptions{Regex: true, CaseSensitive: true, Apply: true}
+	result, err := svc.FindReplace("test", `foo\((\d)\)`, "bar($1)", options)
+	if err != nil {
+		t.Fatalf("FindReplace() error = %v", err)
+	}
+	if !result.Applied || result.FilesMatched != 1 || result.TotalMatches != 1 {
+		t.Fatalf("result = %+v, want one applied match", result)
+	}
+	if got := readFindReplaceFile(t, filepath.Join(rootDir, "main.go")); got != "fooBar := bar(1)\nfood := 2\n" {
+		t.Fatalf("main.go = %q", got)
+	}
+	if !strings.HasPrefix(result.BackupDir, filepath.Join(configDir, findReplaceBackupDirName)) {
+		t.Fatalf("backup dir = %q, want under config dir", result.BackupDir)
+	}
+	if got := readFindReplaceFile(t, filepath.Join(result.BackupDir, "main.go")); got != "fooBar := foo(1)\nfood := 2\n" {
+		t.Fatalf("backup = %q, want original content", got)
+	}
+
+	// Word-boundary literal search covers untracked files but not ignored ones.
+	result, err = svc.FindReplace("test", "foo", "baz", FindReplaceOptions{WholeWord: true, Apply: true})
+	if err != nil {
+		t.Fatalf("FindReplace() error = %v", err)
+	}
+	if result.FilesMatched != 1 || result.Files[0].Path != "untracked.txt" {
+		t.Fatalf("files = %+v, want only untracked.txt", result.Files)
+	}
+	if got := readFindReplaceFile(t, filepath.Join(rootDir, "ignored", "skip.txt")); got != "foo" {
+		t.Fatalf("ignored file changed: %q", got)
+	}
+}
+
+func TestFindReplaceGlobs(t *testing.T) {
+	rootDir := t.TempDir()
+	writeFindReplaceFiles(t, rootDir, map[string]string{
+		"a.go":        "token",
+		"a_test.go":   "token",
+		"docs/a.md":   "token",
+		"docs/b.yaml": "token",
+	})
+	svc, _ := newFindReplaceTestService(t, rootDir)
+
+	result, err := svc.FindReplace("test", "token", "x", FindReplaceOptions{
+		Include: []string{"*.go", "docs/*.md"},
+		Exclude: []string{"*_test.go"},
+	})
+	if err != nil {
+		t.Fatalf("FindReplace() error = %v", err)
+	}
+	var paths []string
+	for _, file := range result.Files {
+		paths = append(paths, file.Path)
+	}
+	if strings.Join(paths, ",") != "a.go,docs/a.md" {
+		t.Fatalf("paths = %v, want a.go and docs/a.md", paths)
+	}
+}
+
+func TestFindReplaceValidation(t *testing.T) {
+	svc, _ := newFindReplaceTestService(t, t.TempDir())
+	tests := []struct {
+		name    string
+		session string
+		query   string
+		options FindReplaceOptions
+		wantErr string
+	}{
+		{name: "missing session", session: " ", query: "a", wantErr: "session name is required"},
+		{name: "empty query", session: "test", query: "", wantErr: "query is required"},
+		{name: "invalid regex", session: "test", query: "(", options: FindReplaceOptions{Regex: true}, wantErr: "invalid regular expression"},
+		{name: "matches empty text", session: "test", query: "a*", options: FindReplaceOptions{Regex: true}, wantErr: "empty text"},
+		{name: "invalid glob", session: "test", query: "a", options: FindReplaceOptions{Include: []string{"["}}, wantErr: "invalid glob"},
+		{name: "unknown session", session: "missing", query: "a", wantErr: "session not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.FindReplace(tt.session, tt.query, "b", tt.options)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("FindReplace() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestApplyFindReplaceFilesRestoresOnConflict(t *testing.T) {
+	rootDir := t.TempDir()
+	writeFindReplaceFiles(t, rootDir, map[string]string{"a.txt": "one", "b.txt": "one"})
+	svc, _ := newFindReplaceTestService(t, rootDir)
+	pattern, err := compileFindReplacePattern("one", FindReplaceOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var files []*findReplaceFile
+	for _, name := range []string{"a.txt", "b.txt"} {
+		file, ok := planFindReplaceFile(rootDir, name, pattern, "two", false)
+		if !ok || file == nil {
+			t.Fatalf("planFindReplaceFile(%s) = %v, %v", name, file, ok)
+		}
+		files = append(files, file)
+	}
+	// b.txt changes after planning, so a.txt must be rolled back.
+	writeFindReplaceFiles(t, rootDir, map[string]string{"b.txt": "edited"})
+
+	if err := svc.applyFindReplaceFiles("test", files); err == nil || !strings.Contains(err.Error(), "changed during") {
+		t.Fatalf("applyFindReplaceFiles() error = %v, want conflict", err)
+	}
+	if got := readFindReplaceFile(t, filepath.Join(rootDir, "a.txt")); got != "one" {
+		t.Fatalf("a.txt = %q, want restored original", got)
+	}
+	if got := readFindReplaceFile(t, filepath.Join(rootDir, "b.txt")); got != "edited" {
+		t.Fatalf("b.txt = %q, want concurrent edit kept", got)
+	}
+}
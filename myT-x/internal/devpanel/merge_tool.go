@@ -0,0 +1,169 @@
+package devpanel
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	gitpkg "myT-x/internal/git"
+	"myT-x/internal/procutil"
+)
+
+const mergeToolFinishedEventName = "devpanel:merge-tool-finished"
+
+// mergeToolCommandName registers a worktree.merge_tool command with git
+// mergetool for the duration of one run.
+const mergeToolCommandName = "mytx"
+
+// MergeToolFinishedEvent reports that a merge tool started by OpenInMergeTool
+// exited. Resolved is true when the file is no longer conflicted.
+type MergeToolFinishedEvent struct {
+	SessionName string `json:"session_name"`
+	Path        string `json:"path"`
+	Resolved    bool   `json:"resolved"`
+	Error       string `json:"error,omitempty"`
+}
+
+// startMergeToolProcess starts git with args in workDir and returns a
+// function that waits for it to exit.
+func startMergeToolProcess(workDir string, args []string) (func() error, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = workDir
+	procutil.HideWindow(cmd)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	return func() error {
+		if err := cmd.Wait(); err != nil {
+			if msg := strings.TrimSpace(stderr.String()); msg != "" {
+				return fmt.Errorf("%w: %s", err, msg)
+			}
+			return err
+		}
+		return nil
+	}, nil
+}
+
+// OpenInMergeTool opens one conflicted file of the session's working tree in
+// a merge tool through git mergetool and returns once the tool has started.
+// tool is worktree.merge_tool; empty uses merge.tool from git config.
+// MergeToolFinishedEvent is emitted when the tool exits.
+//
+// git mergetool writes the BASE/LOCAL/REMOTE versions to the system temp
+// directory and removes them afterwards; no .orig backup is kept.
+func (s *Service) OpenInMergeTool(sessionName, path, tool string) error {
+	workDir, err := s.resolveAndValidateGitSession(sessionName)
+	if err != nil {
+		return err
+	}
+	if err := validateGitFilePath(path); err != nil {
+		return err
+	}
+	gitPath := filepath.ToSlash(filepath.Clean(strings.TrimSpace(path)))
+
+	conflicted, err := listConflictedFiles(workDir)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(conflicted, gitPath) {
+		return fmt.Errorf("file is not conflicted: %s", gitPath)
+	}
+
+	tool = strings.TrimSpace(tool)
+	if tool == "" {
+		if _, err := gitpkg.RunGitCLIPublic(workDir, []string{"config", "--get", "merge.tool"}); err != nil {
+			if gitpkg.IsGitConfigKeyNotFound(err) {
+				return errors.New("no merge tool configured: set worktree.merge_tool or git config merge.tool")
+			}
+			return fmt.Errorf("failed to read merge.tool: %w", err)
+		}
+	}
+
+	key := workDir + "\x00" + gitPath
+	s.mergeToolMu.Lock()
+	if _, running := s.mergeToolsRunning[key]; running {
+		s.mergeToolMu.Unlock()
+		return fmt.Errorf("merge tool is already open for %s", gitPath)
+	}
+	s.mergeToolsRunning[key] = struct{}{}
+	s.mergeToolMu.Unlock()
+
+	wait, err := s.startMergeTool(workDir, mergeToolArgs(tool, gitPath))
+	if err != nil {
+		s.finishMergeTool(key)
+		return fmt.Errorf("failed to start merge tool: %w", err)
+	}
+	slog.Debug("[DEVPANEL-GIT] merge tool started", "session", sessionName, "path", gitPath)
+
+	go func() {
+		defer s.finishMergeTool(key)
+		event := MergeToolFinishedEvent{SessionName: sessionName, Path: gitPath}
+		if waitErr := wait(); waitErr != nil {
+			event.Error = waitErr.Error()
+		}
+		remaining, listErr := listConflictedFiles(workDir)
+		if listErr != nil && event.Error == "" {
+			event.Error = listErr.Error()
+		}
+		event.Resolved = listErr == nil && !slices.Contains(remaining, gitPath)
+		slog.Debug("[DEVPANEL-GIT] merge tool finished",
+			"session", sessionName, "path", gitPath, "resolved", event.Resolved, "error", event.Error)
+		s.deps.Emitter.Emit(mergeToolFinishedEventName, event)
+	}()
+	return nil
+}
+
+func (s *Service) finishMergeTool(key string) {
+	s.mergeToolMu.Lock()
+	delete(s.mergeToolsRunning, key)
+	s.mergeToolMu.Unlock()
+}
+
+// mergeToolArgs builds the git mergetool arguments for one file. A tool
+// containing whitespace or "$" is a command template; anything else names a
+// tool known to git mergetool.
+func mergeToolArgs(tool, gitPath string) []string {
+	args := []string{
+		"-c", "mergetool.writeToTemp=true",
+		"-c", "mergetool.keepBackup=false",
+		"-c", "mergetool.keepTemporaries=false",
+	}
+	var toolArgs []string
+	switch {
+	case tool == "":
+	case strings.ContainsAny(tool, " \t$"):
+		args = append(args,
+			"-c", "mergetool."+mergeToolCommandName+".cmd="+tool,
+			"-c", "mergetool."+mergeToolCommandName+".trustExitCode=true",
+		)
+		toolArgs = []string{"--tool=" + mergeToolCommandName}
+	default:
+		toolArgs = []string{"--tool=" + tool}
+	}
+	args = append(args, "mergetool", "--no-prompt")
+	args = append(args, toolArgs...)
+	return append(args, "--", gitPath)
+}
+
+// listConflictedFiles returns the unmerged paths under workDir, slash-separated
+// and relative to workDir.
+func listConflictedFiles(workDir string) ([]string, error) {
+	output, err := gitpkg.RunGitCLIPublic(workDir, []string{"diff", "--name-only", "--relative", "-z", "--diff-filter=U"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list conflicted files: %w", err)
+	}
+	var paths []string
+	for path := range strings.SplitSeq(string(output), "\x00") {
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths, nil
+}
@@ -0,0 +1,138 @@
+package devpanel
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"myT-x/internal/testutil"
+)
+
+func TestMergeToolArgs(t *testing.T) {
+	base := []string{
+		"-c", "mergetool.writeToTemp=true",
+		"-c", "mergetool.keepBackup=false",
+		"-c", "mergetool.keepTemporaries=false",
+	}
+	tests := []struct {
+		name string
+		tool string
+		want []string
+	}{
+		{
+			name: "git merge.tool",
+			want: append(append([]string(nil), base...), "mergetool", "--no-prompt", "--", "a.txt"),
+		},
+		{
+			name: "named tool",
+			tool: "vscode",
+			want: append(append([]string(nil), base...), "mergetool", "--no-prompt", "--tool=vscode", "--", "a.txt"),
+		},
+		{
+			name: "command template",
+			tool: `meld "$LOCAL" "$MERGED" "$REMOTE"`,
+			want: append(append([]string(nil), base...),
+				"-c", `mergetool.mytx.cmd=meld "$LOCAL" "$MERGED" "$REMOTE"`,
+				"-c", "mergetool.mytx.trustExitCode=true",
+				"mergetool", "--no-prompt", "--tool=mytx", "--", "a.txt"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mergeToolArgs(tt.tool, "a.txt"); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("mergeToolArgs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// setupMergeConflict creates a repository with conflict.txt left unmerged.
+func setupMergeConflict(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	defaultBranch := initGitRepo(t, dir)
+	writeAndCommit := func(content, message string) {
+		if err := os.WriteFile(filepath.Join(dir, "conflict.txt"), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		gitRun(t, dir, "add", "conflict.txt")
+		gitRun(t, dir, "commit", "-m", message)
+	}
+	writeAndCommit("base\n", "base")
+	gitRun(t, dir, "checkout", "-b", "feature")
+	writeAndCommit("feature\n", "feature")
+	gitRun(t, dir, "checkout", defaultBranch)
+	writeAndCommit("main\n", "main")
+	cmd := gitCmd("git", "merge", "feature")
+	cmd.Dir = dir
+	_ = cmd.Run() // Expected to fail with a conflict.
+	return dir
+}
+
+func TestOpenInMergeTool(t *testing.T) {
+	testutil.SkipIfNoGit(t)
+
+	dir := setupMergeConflict(t)
+	emitter := &testEmitter{}
+	svc := NewService(Deps{
+		ResolveSessionDir: newTestSessionResolver(map[string]string{"s": dir}).resolveSessionDir,
+		IsPathWithinBase:  testIsPathWithinBase,
+		Emitter:           emitter,
+	})
+	release := make(chan struct{})
+	var gotArgs []string
+	svc.startMergeTool = func(workDir string, args []string) (func() error, error) {
+		gotArgs = args
+		return func() error {
+			<-release
+			// Resolve the conflict as a merge tool would.
+			if err := os.WriteFile(filepath.Join(workDir, "conflict.txt"), []byte("merged\n"), 0o644); err != nil {
+				return err
+			}
+			cmd := gitCmd("git", "add", "conflict.txt")
+			cmd.Dir = workDir
+			return cmd.Run()
+		}, nil
+	}
+
+	if err := svc.OpenInMergeTool("s", "missing.txt", "vscode"); err == nil || !strings.Contains(err.Error(), "not conflicted") {
+		t.Fatalf("OpenInMergeTool(non-conflicted) error = %v, want not conflicted", err)
+	}
+	if err := svc.OpenInMergeTool("s", "conflict.txt", "vscode"); err != nil {
+		t.Fatalf("OpenInMergeTool() error = %v", err)
+	}
+	if gotArgs[len(gotArgs)-1] != "conflict.txt" {
+		t.Fatalf("merge tool args = %q, want file last", gotArgs)
+	}
+	if err := svc.OpenInMergeTool("s", "conflict.txt", "vscode"); err == nil || !strings.Contains(err.Error(), "already open") {
+		t.Fatalf("second OpenInMergeTool() error = %v, want already open", err)
+	}
+
+	close(release)
+	event := emitter.waitForEvent(t, mergeToolFinishedEventName, 5*time.Second)
+	want := MergeToolFinishedEvent{SessionName: "s", Path: "conflict.txt", Resolved: true}
+	if got, ok := event.payload.(MergeToolFinishedEvent); !ok || got != want {
+		t.Fatalf("finished event = %#v, want %#v", event.payload, want)
+	}
+}
+
+func TestOpenInMergeToolRequiresConfiguredTool(t *testing.T) {
+	testutil.SkipIfNoGit(t)
+
+	dir := setupMergeConflict(t)
+	// Keep a user's global merge.tool from leaking into the test.
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	svc := newTestService("s", dir)
+	svc.startMergeTool = func(string, []string) (func() error, error) {
+		t.Fatal("merge tool must not start without a configured tool")
+		return nil, nil
+	}
+	err := svc.OpenInMergeTool("s", "conflict.txt", "")
+	if err == nil || !strings.Contains(err.Error(), "no merge tool configured") {
+		t.Fatalf("OpenInMergeTool() error = %v, want no merge tool configured", err)
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 	"unicode/utf8"
@@ -122,6 +123,10 @@ type Deps struct {
 	// Emitter broadcasts frontend runtime events.
 	// Defaults to a no-op emitter when nil.
 	Emitter apptypes.RuntimeEventEmitter
+
+	// ConfigDir returns the app config directory, which holds find-and-replace
+	// backups. Optional: FindReplace refuses to apply edits when nil.
+	ConfigDir func() (string, error)
 }
 
 // Service provides developer panel file/directory browsing and git operations.
@@ -131,6 +136,12 @@ type Service struct {
 	deps           Deps
 	dirCache       *DirCache
 	watcherManager *watcherManager
+
+	// mergeToolMu guards mergeToolsRunning, keyed by work dir and file path.
+	mergeToolMu       sync.Mutex
+	mergeToolsRunning map[string]struct{}
+	// startMergeTool is a test seam for launching git mergetool.
+	startMergeTool func(workDir string, args []string) (wait func() error, err error)
 }
 
 // NewService creates a new devpanel Service with the given dependencies.
@@ -145,9 +156,11 @@ func NewService(deps Deps) *Service {
 
 	dirCache := NewDirCache(defaultDirCacheTTL)
 	return &Service{
-		deps:           deps,
-		dirCache:       dirCache,
-		watcherManager: newWatcherManager(dirCache, deps.Emitter),
+		deps:              deps,
+		dirCache:          dirCache,
+		watcherManager:    newWatcherManager(dirCache, deps.Emitter),
+		mergeToolsRunning: map[string]struct{}{},
+		startMergeTool:    startMergeToolProcess,
 	}
 }
 
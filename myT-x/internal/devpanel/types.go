@@ -142,3 +142,43 @@ type SearchContentLine struct {
 	Line    int    `json:"line"`    // 1-based line number
 	Content string `json:"content"` // line text (truncated to 500 chars)
 }
+
+// FindReplaceOptions controls FindReplace.
+type FindReplaceOptions struct {
+	Regex         bool `json:"regex"`          // query is a Go regular expression; replacement may use $1 / ${name}
+	CaseSensitive bool `json:"case_sensitive"` // default is case-insensitive
+	WholeWord     bool `json:"whole_word"`     // match only at word boundaries
+	// Include and Exclude are globs matched against the root-relative path or
+	// the file name (e.g. "*.go", "docs/*.md"). Empty Include searches all files.
+	Include []string `json:"include,omitempty"`
+	Exclude []string `json:"exclude,omitempty"`
+	Apply   bool     `json:"apply"` // false previews matches without writing
+}
+
+// FindReplaceResult is the change summary of a FindReplace preview or apply.
+type FindReplaceResult struct {
+	Applied      bool              `json:"applied"`
+	Files        []FindReplaceFile `json:"files"`
+	FilesScanned int               `json:"files_scanned"` // text files searched
+	FilesMatched int               `json:"files_matched"`
+	TotalMatches int               `json:"total_matches"`
+	Truncated    bool              `json:"truncated"`            // more files matched than are listed; cannot be applied
+	BackupDir    string            `json:"backup_dir,omitempty"` // original contents of the changed files (apply only)
+}
+
+// FindReplaceFile is one file with matches.
+type FindReplaceFile struct {
+	Path             string             `json:"path"`    // root-relative forward-slash path
+	Matches          int                `json:"matches"` // total matches in the file
+	Preview          []FindReplaceMatch `json:"preview"`
+	PreviewTruncated bool               `json:"preview_truncated"` // true if Preview lists fewer than Matches
+}
+
+// FindReplaceMatch is one previewed match.
+type FindReplaceMatch struct {
+	Line        int    `json:"line"`        // 1-based line of the match start
+	Column      int    `json:"column"`      // 1-based rune column of the match start
+	LineText    string `json:"line_text"`   // text of the line (truncated to 500 chars)
+	Match       string `json:"match"`       // matched text
+	Replacement string `json:"replacement"` // text the match is replaced with
+}
@@ -0,0 +1,9 @@
+// Package e2e holds the end-to-end integration suite that exercises the
+// tmux-shim binary, the Named Pipe server, and the command router together.
+//
+// The tests build cmd/tmux-shim, start a real ipc.PipeServer on a private
+// pipe name, and run the shim as a subprocess. They spawn ConPTY shells and
+// are therefore Windows-only and excluded from the default test run:
+//
+//	go test -tags e2e ./internal/e2e/
+package e2e
@@ -0,0 +1,214 @@
+//go:build windows && e2e
+
+package e2e
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"myT-x/internal/ipc"
+	"myT-x/internal/snapshot"
+	"myT-x/internal/tmux"
+)
+
+const (
+	shimRunTimeout  = 30 * time.Second
+	pollInterval    = 100 * time.Millisecond
+	capturePollWait = 20 * time.Second
+)
+
+// shimPath is the tmux-shim binary built once by TestMain.
+var shimPath string
+
+func TestMain(m *testing.M) {
+	os.Exit(runMain(m))
+}
+
+func runMain(m *testing.M) int {
+	dir, err := os.MkdirTemp("", "mytx-e2e-*")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "e2e: create temp dir: %v\n", err)
+		return 1
+	}
+	defer os.RemoveAll(dir)
+
+	shimPath = filepath.Join(dir, "tmux.exe")
+	// The test binary runs in internal/e2e; the shim package is two levels up.
+	build := exec.Command("go", "build", "-o", shimPath, "./cmd/tmux-shim")
+	build.Dir = filepath.Join("..", "..")
+	if out, err := build.CombinedOutput(); err != nil {
+		fmt.Fprintf(os.Stderr, "e2e: build tmux-shim: %v\n%s", err, out)
+		return 1
+	}
+	return m.Run()
+}
+
+// recordedEvent is one event emitted by the router.
+type recordedEvent struct {
+	name    string
+	payload any
+}
+
+// eventRecorder is a thread-safe tmux.EventEmitter.
+type eventRecorder struct {
+	mu     sync.Mutex
+	events []recordedEvent
+}
+
+func (r *eventRecorder) Emit(name string, payload any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, recordedEvent{name: name, payload: payload})
+}
+
+func (r *eventRecorder) EmitWithContext(_ context.Context, name string, payload any) {
+	r.Emit(name, payload)
+}
+
+func (r *eventRecorder) names() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]string, 0, len(r.events))
+	for _, ev := range r.events {
+		out = append(out, ev.name)
+	}
+	return out
+}
+
+func (r *eventRecorder) has(name string) bool {
+	for _, n := range r.names() {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// harness wires the three layers the way App does: SessionManager +
+// CommandRouter behind a real PipeServer, reached through the shim binary.
+type harness struct {
+	t        *testing.T
+	sessions *tmux.SessionManager
+	router   *tmux.CommandRouter
+	events   *eventRecorder
+	pipeName string
+	appData  string
+}
+
+func newHarness(t *testing.T) *harness {
+	t.Helper()
+
+	pipeName := fmt.Sprintf(`\\.\pipe\myT-x-e2e-%d-%d`, os.Getpid(), time.Now().UnixNano())
+	// The server publishes its auth token under %LOCALAPPDATA% and the shim
+	// reads it from there, so both must see the same private directory.
+	appData := t.TempDir()
+	t.Setenv("LOCALAPPDATA", appData)
+	sessions := tmux.NewSessionManager()
+	events := &eventRecorder{}
+	router := tmux.NewCommandRouter(sessions, events, tmux.RouterOptions{
+		DefaultShell:  "cmd.exe",
+		PipeName:      pipeName,
+		HostPID:       os.Getpid(),
+		ShimAvailable: true,
+	})
+	server := ipc.NewPipeServer(pipeName, router)
+	if err := server.Start(); err != nil {
+		t.Fatalf("PipeServer.Start() error = %v", err)
+	}
+	t.Cleanup(func() {
+		if err := server.Stop(); err != nil {
+			t.Errorf("PipeServer.Stop() error = %v", err)
+		}
+		sessions.Close()
+	})
+
+	return &harness{
+		t:        t,
+		sessions: sessions,
+		router:   router,
+		events:   events,
+		pipeName: pipeName,
+		appData:  appData,
+	}
+}
+
+// shimResult is the observable outcome of one shim invocation.
+type shimResult struct {
+	stdout   string
+	stderr   string
+	exitCode int
+}
+
+// tmux runs the built shim with args against the harness pipe.
+func (h *harness) tmux(args ...string) shimResult {
+	h.t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), shimRunTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, shimPath, args...)
+	cmd.Env = append(os.Environ(),
+		"GO_TMUX_PIPE="+h.pipeName,
+		// Keep shim debug logs out of the real %LOCALAPPDATA%.
+		"LOCALAPPDATA="+h.appData,
+		"TMUX_PANE=",
+	)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	result := shimResult{stdout: stdout.String(), stderr: stderr.String()}
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+	case errors.As(err, &exitErr):
+		result.exitCode = exitErr.ExitCode()
+	default:
+		h.t.Fatalf("run shim %v: %v", args, err)
+	}
+	return result
+}
+
+// mustTmux runs the shim and fails the test on a non-zero exit code.
+func (h *harness) mustTmux(args ...string) shimResult {
+	h.t.Helper()
+	result := h.tmux(args...)
+	if result.exitCode != 0 {
+		h.t.Fatalf("tmux %s exit = %d, stderr = %q", strings.Join(args, " "), result.exitCode, result.stderr)
+	}
+	return result
+}
+
+// waitFor polls cond until it holds or timeout elapses.
+func (h *harness) waitFor(timeout time.Duration, what string, cond func() bool) {
+	h.t.Helper()
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			h.t.Fatalf("timed out after %s waiting for %s", timeout, what)
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+// requireSnapshotEvent asserts the router emitted name and that the App's
+// snapshot policy reacts to it, so the frontend would be refreshed.
+func (h *harness) requireSnapshotEvent(name string) {
+	h.t.Helper()
+	if !h.events.has(name) {
+		h.t.Fatalf("events = %v, want %s", h.events.names(), name)
+	}
+	if !snapshot.ShouldEmitSnapshotForEvent(name) {
+		h.t.Fatalf("snapshot policy does not trigger on %s", name)
+	}
+}
@@ -0,0 +1,28 @@
+//go:build windows && e2e
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestE2ESendKeysRunsCommandInPane drives a pane the way automation scripts
+// do: send-keys with an explicit pane target, the text and Enter as separate
+// key arguments, then capture-pane to read the result back.
+func TestE2ESendKeysRunsCommandInPane(t *testing.T) {
+	h := newHarness(t)
+	h.mustTmux("new-session", "-d", "-s", "keys")
+
+	// The shell prints 42345 only when Enter ran the line, so the echoed
+	// input alone never matches.
+	h.mustTmux("send-keys", "-t", "keys:0.0", "set /a 40000+2345", "Enter")
+	h.waitFor(capturePollWait, "command result in capture-pane output", func() bool {
+		captured := h.tmux("capture-pane", "-p", "-t", "keys:0.0")
+		return captured.exitCode == 0 && strings.Contains(captured.stdout, "42345")
+	})
+
+	if result := h.tmux("send-keys", "-t", "keys:0.9", "x"); result.exitCode != 1 {
+		t.Fatalf("send-keys to a missing pane exit = %d, want 1", result.exitCode)
+	}
+}
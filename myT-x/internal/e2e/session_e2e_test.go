@@ -0,0 +1,75 @@
+//go:build windows && e2e
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestE2ESessionLifecycle(t *testing.T) {
+	h := newHarness(t)
+
+	created := h.mustTmux("new-session", "-d", "-s", "e2e", "-P", "-F", "#{session_name}")
+	if got := strings.TrimSpace(created.stdout); got != "e2e" {
+		t.Fatalf("new-session -P stdout = %q, want %q", got, "e2e")
+	}
+	session, ok := h.sessions.GetSession("e2e")
+	if !ok {
+		t.Fatal("session e2e missing from SessionManager after new-session")
+	}
+	if !session.Detached {
+		t.Fatal("new-session -d must create a detached session")
+	}
+	h.requireSnapshotEvent("tmux:session-created")
+
+	h.mustTmux("has-session", "-t", "e2e")
+
+	const marker = "mytx-e2e-marker"
+	h.mustTmux("send-keys", "-t", "e2e", "echo "+marker, "Enter")
+	h.waitFor(capturePollWait, "marker in capture-pane output", func() bool {
+		captured := h.tmux("capture-pane", "-p", "-t", "e2e")
+		// The echoed line appears twice: the typed command and its output.
+		return captured.exitCode == 0 && strings.Count(captured.stdout, marker) >= 2
+	})
+
+	h.mustTmux("kill-session", "-t", "e2e")
+	if h.sessions.HasSession("e2e") {
+		t.Fatal("session e2e still present after kill-session")
+	}
+	h.requireSnapshotEvent("tmux:session-destroyed")
+
+	if result := h.tmux("has-session", "-t", "e2e"); result.exitCode != 1 {
+		t.Fatalf("has-session after kill exit = %d, want 1", result.exitCode)
+	}
+}
+
+func TestE2EErrorsPropagateToShimExitCode(t *testing.T) {
+	h := newHarness(t)
+
+	result := h.tmux("kill-session", "-t", "missing")
+	if result.exitCode != 1 {
+		t.Fatalf("kill-session missing exit = %d, want 1", result.exitCode)
+	}
+	if !strings.Contains(result.stderr, "missing") {
+		t.Fatalf("stderr = %q, want session name in error", result.stderr)
+	}
+
+	// Parse errors are reported by the shim without reaching the pipe.
+	if parsed := h.tmux("new-session", "-x", "not-a-number"); parsed.exitCode == 0 {
+		t.Fatal("invalid -x value must fail in the shim")
+	}
+}
+
+func TestE2EShimReportsMissingServer(t *testing.T) {
+	h := newHarness(t)
+	h.pipeName = `\\.\pipe\myT-x-e2e-no-server`
+
+	result := h.tmux("list-sessions")
+	if result.exitCode != 1 {
+		t.Fatalf("exit = %d, want 1 without a pipe server", result.exitCode)
+	}
+	if !strings.Contains(result.stderr, "no server running") {
+		t.Fatalf("stderr = %q, want no server running", result.stderr)
+	}
+}
@@ -0,0 +1,428 @@
+// Package ephemeral tracks preview sessions that remove themselves: when
+// their lifetime runs out or the app exits, the session is killed and its
+// worktree, temp directory, and session history are deleted. Entries are
+// persisted so that a crash does not leave them behind; whatever is left
+// over is removed on the next start.
+package ephemeral
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"myT-x/internal/apptypes"
+	"myT-x/internal/statestore"
+)
+
+const (
+	// CheckInterval is the period of Run.
+	CheckInterval = time.Minute
+
+	// ExpiredEvent carries the session name after an expired session was
+	// removed.
+	ExpiredEvent = "session:ephemeral-expired"
+)
+
+// Worktree identifies the git worktree of an ephemeral session.
+type Worktree struct {
+	Path       string `json:"path"`
+	RepoPath   string `json:"repo_path"`
+	BranchName string `json:"branch_name,omitempty"`
+}
+
+// Entry is one ephemeral session.
+type Entry struct {
+	SessionName string `json:"session_name"`
+	// WorkDir is the directory the session owns: its worktree or temp
+	// directory. The session history stored for it is deleted with the
+	// session. Empty when the session runs in a directory it does not own.
+	WorkDir string `json:"work_dir,omitempty"`
+	// TempDir is set when WorkDir was created for the session and is
+	// removed with it.
+	TempDir   bool      `json:"temp_dir,omitempty"`
+	Worktree  *Worktree `json:"worktree,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Deps holds external dependencies injected at construction time.
+type Deps struct {
+	// Store returns the state store the entries are persisted in.
+	Store func() (statestore.Store, error)
+
+	// SessionExists reports whether the session is live.
+	SessionExists func(sessionName string) bool
+
+	// KillSession kills a live session and deletes its worktree. The
+	// session's destroy hooks are expected to call CleanupSession.
+	KillSession func(sessionName string) error
+
+	// RemoveWorktree deletes the worktree of a session that is gone, e.g.
+	// one left behind by a crash.
+	RemoveWorktree func(sessionName string, worktree Worktree)
+
+	// RemoveSessionData deletes the session history stored for workDir.
+	RemoveSessionData func(workDir string) error
+
+	// Emitter sends runtime events to the frontend.
+	// Optional: defaults to a no-op emitter if nil.
+	Emitter apptypes.RuntimeEventEmitter
+
+	// Now returns the current time. Optional: defaults to time.Now.
+	Now func() time.Time
+}
+
+// Service keeps the ephemeral session entries and removes expired sessions.
+//
+// Thread-safety is managed internally via mu, which serializes
+// read-modify-write cycles on the stored entries. Session kills run outside
+// mu because they call back into CleanupSession.
+type Service struct {
+	deps Deps
+	mu   sync.Mutex
+}
+
+// NewService creates an ephemeral session service.
+// Panics if Store, SessionExists, KillSession, RemoveWorktree, or
+// RemoveSessionData is nil.
+func NewService(deps Deps) *Service {
+	if deps.Store == nil {
+		panic("ephemeral.NewService: Store must be non-nil")
+	}
+	if deps.SessionExists == nil {
+		panic("ephemeral.NewService: SessionExists must be non-nil")
+	}
+	if deps.KillSession == nil {
+		panic("ephemeral.NewService: KillSession must be non-nil")
+	}
+	if deps.RemoveWorktree == nil {
+		panic("ephemeral.NewService: RemoveWorktree must be non-nil")
+	}
+	if deps.RemoveSessionData == nil {
+		panic("ephemeral.NewService: RemoveSessionData must be non-nil")
+	}
+	if deps.Emitter == nil {
+		deps.Emitter = apptypes.NoopEmitter{}
+	}
+	if deps.Now == nil {
+		deps.Now = time.Now
+	}
+	return &Service{deps: deps}
+}
+
+// Register marks a created session as ephemeral. CreatedAt defaults to now.
+func (s *Service) Register(entry Entry) error {
+	entry.SessionName = strings.TrimSpace(entry.SessionName)
+	if entry.SessionName == "" {
+		return errors.New("session name is required")
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = s.deps.Now()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.put(entry)
+}
+
+// Lookup returns the entry of sessionName.
+func (s *Service) Lookup(sessionName string) (Entry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, err := s.get(sessionName)
+	if err != nil {
+		if !errors.Is(err, statestore.ErrNotFound) {
+			slog.Warn("[WARN-EPHEMERAL] failed to read ephemeral session", "session", sessionName, "error", err)
+		}
+		return Entry{}, false
+	}
+	return entry, true
+}
+
+// List returns the entries of the live ephemeral sessions, soonest expiry
+// first.
+func (s *Service) List() ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	live := make([]Entry, 0, len(entries))
+	for _, entry := range entries {
+		if s.deps.SessionExists(entry.SessionName) {
+			live = append(live, entry)
+		}
+	}
+	slices.SortFunc(live, func(a, b Entry) int { return a.ExpiresAt.Compare(b.ExpiresAt) })
+	return live, nil
+}
+
+// Keep turns an ephemeral session into a regular one; nothing is removed
+// when it ends.
+func (s *Service) Keep(sessionName string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.delete(sessionName)
+}
+
+// Extend moves the expiry of sessionName to ttl from now.
+func (s *Service) Extend(sessionName string, ttl time.Duration) (Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, err := s.get(sessionName)
+	if errors.Is(err, statestore.ErrNotFound) {
+		return Entry{}, fmt.Errorf("session %q is not ephemeral", sessionName)
+	}
+	if err != nil {
+		return Entry{}, err
+	}
+	entry.ExpiresAt = s.deps.Now().Add(ttl)
+	return entry, s.put(entry)
+}
+
+// CleanupSession removes the temp directory and session history of a
+// destroyed ephemeral session and forgets it. Non-ephemeral sessions are
+// ignored. A temp directory that cannot be removed yet (e.g. still held by
+// an exiting process) is retried by the next Expire.
+func (s *Service) CleanupSession(sessionName string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, err := s.get(sessionName)
+	if errors.Is(err, statestore.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	// The worktree follows the kill's own choice; never remove it later.
+	entry.Worktree = nil
+	if err := s.removeFiles(entry); err != nil {
+		slog.Warn("[WARN-EPHEMERAL] ephemeral session files not removed yet, will retry",
+			"session", sessionName, "dir", entry.WorkDir, "error", err)
+		return s.put(entry)
+	}
+	return s.delete(sessionName)
+}
+
+// Rename moves the entry of oldName to newName.
+func (s *Service) Rename(oldName, newName string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, err := s.get(oldName)
+	if errors.Is(err, statestore.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	entry.SessionName = newName
+	if err := s.put(entry); err != nil {
+		return err
+	}
+	return s.delete(oldName)
+}
+
+// RemoveLeftovers removes the worktrees and files of ephemeral sessions
+// that are gone, e.g. after a crash. Call it at startup before any session
+// is created, so that a new session cannot take over a leftover's name.
+func (s *Service) RemoveLeftovers() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries, err := s.load()
+	if err != nil {
+		slog.Warn("[WARN-EPHEMERAL] failed to read ephemeral sessions", "error", err)
+		return
+	}
+	for _, entry := range entries {
+		if !s.deps.SessionExists(entry.SessionName) {
+			s.removeLeftover(entry)
+		}
+	}
+}
+
+// Expire kills the expired live sessions and retries the removal of files
+// that destroyed sessions left behind.
+func (s *Service) Expire() {
+	s.mu.Lock()
+	entries, err := s.load()
+	if err != nil {
+		s.mu.Unlock()
+		slog.Warn("[WARN-EPHEMERAL] failed to read ephemeral sessions", "error", err)
+		return
+	}
+	now := s.deps.Now()
+	var expired []string
+	for _, entry := range entries {
+		if !s.deps.SessionExists(entry.SessionName) {
+			// An entry that still names its worktree belongs to a session
+			// whose destroy hooks have not run yet; CleanupSession handles it.
+			if entry.Worktree == nil {
+				s.removeLeftover(entry)
+			}
+			continue
+		}
+		if !now.Before(entry.ExpiresAt) {
+			expired = append(expired, entry.SessionName)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, name := range expired {
+		slog.Info("[EPHEMERAL] removing expired ephemeral session", "session", name)
+		if err := s.deps.KillSession(name); err != nil {
+			slog.Warn("[WARN-EPHEMERAL] failed to kill expired ephemeral session", "session", name, "error", err)
+			continue
+		}
+		s.deps.Emitter.Emit(ExpiredEvent, map[string]any{"sessionName": name})
+	}
+}
+
+// Shutdown kills every live ephemeral session; called on app exit.
+func (s *Service) Shutdown() {
+	s.mu.Lock()
+	entries, err := s.load()
+	s.mu.Unlock()
+	if err != nil {
+		slog.Warn("[WARN-EPHEMERAL] failed to read ephemeral sessions on shutdown", "error", err)
+		return
+	}
+	for _, entry := range entries {
+		if !s.deps.SessionExists(entry.SessionName) {
+			continue
+		}
+		if err := s.deps.KillSession(entry.SessionName); err != nil {
+			slog.Warn("[WARN-EPHEMERAL] failed to kill ephemeral session on shutdown",
+				"session", entry.SessionName, "error", err)
+		}
+	}
+}
+
+// Run expires sessions every CheckInterval until ctx is canceled.
+func (s *Service) Run(ctx context.Context) {
+	s.Expire()
+	ticker := time.NewTicker(CheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.Expire()
+		}
+	}
+}
+
+// removeLeftover removes the worktree and files of a session that is gone
+// and forgets it once everything is removed. Caller must hold mu.
+func (s *Service) removeLeftover(entry Entry) {
+	if entry.Worktree != nil {
+		slog.Info("[EPHEMERAL] removing leftover ephemeral worktree",
+			"session", entry.SessionName, "path", entry.Worktree.Path)
+		s.deps.RemoveWorktree(entry.SessionName, *entry.Worktree)
+		entry.Worktree = nil
+	}
+	if err := s.removeFiles(entry); err != nil {
+		slog.Debug("[DEBUG-EPHEMERAL] leftover ephemeral session files not removed yet",
+			"session", entry.SessionName, "error", err)
+		if err := s.put(entry); err != nil {
+			slog.Warn("[WARN-EPHEMERAL] failed to update ephemeral session", "session", entry.SessionName, "error", err)
+		}
+		return
+	}
+	if err := s.delete(entry.SessionName); err != nil {
+		slog.Warn("[WARN-EPHEMERAL] failed to forget ephemeral session", "session", entry.SessionName, "error", err)
+	}
+}
+
+// removeFiles deletes the session history of entry.WorkDir and, for temp
+// directories, the directory itself.
+func (s *Service) removeFiles(entry Entry) error {
+	if entry.WorkDir == "" {
+		return nil
+	}
+	var errs []error
+	if err := s.deps.RemoveSessionData(entry.WorkDir); err != nil {
+		errs = append(errs, fmt.Errorf("remove session history: %w", err))
+	}
+	if entry.TempDir {
+		if err := os.RemoveAll(entry.WorkDir); err != nil {
+			errs = append(errs, fmt.Errorf("remove temp directory: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// load returns every stored entry. Caller must hold mu.
+func (s *Service) load() ([]Entry, error) {
+	store, err := s.deps.Store()
+	if err != nil {
+		return nil, err
+	}
+	records, err := store.List(context.Background(), statestore.BucketEphemeralSessions)
+	if err != nil {
+		return nil, fmt.Errorf("read ephemeral sessions: %w", err)
+	}
+	entries := make([]Entry, 0, len(records))
+	for _, record := range records {
+		var entry Entry
+		if err := json.Unmarshal(record.Value, &entry); err != nil || entry.SessionName == "" {
+			slog.Warn("[WARN-EPHEMERAL] skipping corrupt ephemeral session record", "key", record.Key, "error", err)
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// get returns the stored entry of sessionName or statestore.ErrNotFound.
+// Caller must hold mu.
+func (s *Service) get(sessionName string) (Entry, error) {
+	store, err := s.deps.Store()
+	if err != nil {
+		return Entry{}, err
+	}
+	data, err := store.Get(context.Background(), statestore.BucketEphemeralSessions, sessionName)
+	if err != nil {
+		return Entry{}, err
+	}
+	var entry Entry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return Entry{}, fmt.Errorf("decode ephemeral session %s: %w", sessionName, err)
+	}
+	return entry, nil
+}
+
+// put stores entry. Caller must hold mu.
+func (s *Service) put(entry Entry) error {
+	store, err := s.deps.Store()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("marshal ephemeral session: %w", err)
+	}
+	if err := store.Put(context.Background(), statestore.BucketEphemeralSessions, entry.SessionName, data); err != nil {
+		return fmt.Errorf("write ephemeral session: %w", err)
+	}
+	return nil
+}
+
+// delete forgets sessionName. Caller must hold mu.
+func (s *Service) delete(sessionName string) error {
+	store, err := s.deps.Store()
+	if err != nil {
+		return err
+	}
+	if err := store.Delete(context.Background(), statestore.BucketEphemeralSessions, sessionName); err != nil {
+		return fmt.Errorf("delete ephemeral session: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,262 @@
+package ephemeral
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"myT-x/internal/statestore"
+)
+
+// fakeSessions is a live session set whose kills run the destroy hook like
+// the session service does.
+type fakeSessions struct {
+	mu        sync.Mutex
+	live      map[string]bool
+	killed    []string
+	worktrees []string
+	removed   []string
+	removeErr error
+	svc       *Service
+}
+
+func (f *fakeSessions) exists(name string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.live[name]
+}
+
+func (f *fakeSessions) kill(name string) error {
+	f.mu.Lock()
+	delete(f.live, name)
+	f.killed = append(f.killed, name)
+	f.mu.Unlock()
+	return f.svc.CleanupSession(name)
+}
+
+func (f *fakeSessions) removeWorktree(_ string, wt Worktree) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.worktrees = append(f.worktrees, wt.Path)
+}
+
+func (f *fakeSessions) removeData(workDir string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.removeErr != nil {
+		return f.removeErr
+	}
+	f.removed = append(f.removed, workDir)
+	return nil
+}
+
+type recordingEmitter struct {
+	mu      sync.Mutex
+	expired []string
+}
+
+func (e *recordingEmitter) Emit(name string, payload any) {
+	if name != ExpiredEvent {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.expired = append(e.expired, payload.(map[string]any)["sessionName"].(string))
+}
+
+func (e *recordingEmitter) EmitWithContext(_ context.Context, name string, payload any) {
+	e.Emit(name, payload)
+}
+
+func newTestService(t *testing.T, store statestore.Store, now *time.Time, live ...string) (*Service, *fakeSessions, *recordingEmitter) {
+	t.Helper()
+	sessions := &fakeSessions{live: map[string]bool{}}
+	for _, name := range live {
+		sessions.live[name] = true
+	}
+	emitter := &recordingEmitter{}
+	svc := NewService(Deps{
+		Store:             func() (statestore.Store, error) { return store, nil },
+		SessionExists:     sessions.exists,
+		KillSession:       sessions.kill,
+		RemoveWorktree:    sessions.removeWorktree,
+		RemoveSessionData: sessions.removeData,
+		Emitter:           emitter,
+		Now:               func() time.Time { return *now },
+	})
+	sessions.svc = svc
+	return svc, sessions, emitter
+}
+
+func TestNewServicePanicsOnMissingDeps(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("NewService() should panic without Store")
+		}
+	}()
+	NewService(Deps{})
+}
+
+func TestExpireKillsExpiredSessionsAndRemovesTheirFiles(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	store := statestore.NewMemoryStore()
+	svc, sessions, emitter := newTestService(t, store, &now, "old", "fresh")
+
+	tempDir := filepath.Join(t.TempDir(), "preview")
+	if err := os.Mkdir(tempDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.Register(Entry{SessionName: "old", WorkDir: tempDir, TempDir: true, ExpiresAt: now.Add(time.Minute)}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if err := svc.Register(Entry{SessionName: "fresh", ExpiresAt: now.Add(time.Hour)}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	svc.Expire()
+	if len(sessions.killed) != 0 {
+		t.Fatalf("killed = %v before expiry, want none", sessions.killed)
+	}
+
+	now = now.Add(2 * time.Minute)
+	svc.Expire()
+	if !slices.Equal(sessions.killed, []string{"old"}) {
+		t.Fatalf("killed = %v, want [old]", sessions.killed)
+	}
+	if !slices.Equal(emitter.expired, []string{"old"}) {
+		t.Fatalf("expired events = %v, want [old]", emitter.expired)
+	}
+	if !slices.Equal(sessions.removed, []string{tempDir}) {
+		t.Fatalf("removed session data = %v, want [%s]", sessions.removed, tempDir)
+	}
+	if _, err := os.Stat(tempDir); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("temp dir should be removed, stat error = %v", err)
+	}
+	if _, ok := svc.Lookup("old"); ok {
+		t.Fatal("expired session should be forgotten")
+	}
+	live, err := svc.List()
+	if err != nil || len(live) != 1 || live[0].SessionName != "fresh" {
+		t.Fatalf("List() = %+v, %v; want [fresh]", live, err)
+	}
+}
+
+func TestCleanupSessionRetriesFailedRemovalWithoutTouchingWorktree(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	store := statestore.NewMemoryStore()
+	svc, sessions, _ := newTestService(t, store, &now)
+
+	err := svc.Register(Entry{
+		SessionName: "wt",
+		WorkDir:     "/repo/.wt/wt",
+		Worktree:    &Worktree{Path: "/repo/.wt/wt", RepoPath: "/repo"},
+		ExpiresAt:   now.Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	sessions.removeErr = errors.New("file in use")
+	if err := svc.CleanupSession("wt"); err != nil {
+		t.Fatalf("CleanupSession() error = %v", err)
+	}
+	entry, ok := svc.Lookup("wt")
+	if !ok || entry.Worktree != nil {
+		t.Fatalf("Lookup() = %+v, %v; want a kept entry without worktree", entry, ok)
+	}
+
+	sessions.removeErr = nil
+	svc.Expire()
+	if len(sessions.worktrees) != 0 {
+		t.Fatalf("removed worktrees = %v; the kill decides about the worktree", sessions.worktrees)
+	}
+	if _, ok := svc.Lookup("wt"); ok {
+		t.Fatal("entry should be forgotten after the retry")
+	}
+}
+
+func TestRemoveLeftoversRemovesWorktreesOfGoneSessions(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	store := statestore.NewMemoryStore()
+	svc, sessions, _ := newTestService(t, store, &now)
+
+	for _, name := range []string{"gone", "plain"} {
+		entry := Entry{SessionName: name, ExpiresAt: now.Add(time.Hour)}
+		if name == "gone" {
+			entry.WorkDir = "/repo/.wt/gone"
+			entry.Worktree = &Worktree{Path: "/repo/.wt/gone", RepoPath: "/repo"}
+		}
+		if err := svc.Register(entry); err != nil {
+			t.Fatalf("Register() error = %v", err)
+		}
+	}
+
+	svc.RemoveLeftovers()
+	if !slices.Equal(sessions.worktrees, []string{"/repo/.wt/gone"}) {
+		t.Fatalf("removed worktrees = %v, want [/repo/.wt/gone]", sessions.worktrees)
+	}
+	records, err := store.List(context.Background(), statestore.BucketEphemeralSessions)
+	if err != nil || len(records) != 0 {
+		t.Fatalf("stored entries = %d, %v; want none", len(records), err)
+	}
+}
+
+func TestKeepExtendAndRename(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	store := statestore.NewMemoryStore()
+	svc, sessions, _ := newTestService(t, store, &now, "a", "b")
+
+	for _, name := range []string{"a", "b"} {
+		if err := svc.Register(Entry{SessionName: name, ExpiresAt: now.Add(time.Minute)}); err != nil {
+			t.Fatalf("Register() error = %v", err)
+		}
+	}
+
+	entry, err := svc.Extend("a", time.Hour)
+	if err != nil || !entry.ExpiresAt.Equal(now.Add(time.Hour)) {
+		t.Fatalf("Extend() = %+v, %v; want expiry in an hour", entry, err)
+	}
+	if _, err := svc.Extend("missing", time.Hour); err == nil {
+		t.Fatal("Extend() of a regular session should fail")
+	}
+	if err := svc.Keep("b"); err != nil {
+		t.Fatalf("Keep() error = %v", err)
+	}
+
+	sessions.live["renamed"] = true
+	delete(sessions.live, "a")
+	if err := svc.Rename("a", "renamed"); err != nil {
+		t.Fatalf("Rename() error = %v", err)
+	}
+	if _, ok := svc.Lookup("a"); ok {
+		t.Fatal("old name should be forgotten after Rename")
+	}
+
+	now = now.Add(2 * time.Minute)
+	svc.Expire()
+	if len(sessions.killed) != 0 {
+		t.Fatalf("killed = %v; extended and kept sessions must not expire", sessions.killed)
+	}
+}
+
+func TestShutdownKillsLiveSessions(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	store := statestore.NewMemoryStore()
+	svc, sessions, _ := newTestService(t, store, &now, "a")
+
+	if err := svc.Register(Entry{SessionName: "a", ExpiresAt: now.Add(time.Hour)}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	svc.Shutdown()
+	if !slices.Equal(sessions.killed, []string{"a"}) {
+		t.Fatalf("killed = %v, want [a]", sessions.killed)
+	}
+	if _, ok := svc.Lookup("a"); ok {
+		t.Fatal("killed session should be forgotten")
+	}
+}
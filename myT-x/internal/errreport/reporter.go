@@ -0,0 +1,287 @@
+// Package errreport is the channel through which backend errors reach the
+// UI. Identical errors are merged within a dedup window, floods of distinct
+// errors are rate-limited, and pending changes are emitted as one
+// consolidated Event that the frontend renders as grouped toasts.
+package errreport
+
+import (
+	"context"
+	"hash/fnv"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Event is emitted with a Batch whenever reported errors changed.
+const Event = "app:errors"
+
+// Severities of a report.
+const (
+	SeverityWarn  = "warn"
+	SeverityError = "error"
+)
+
+// Error codes. Codes are stable identifiers: the frontend localizes hints by
+// code and falls back to the English hint in the group.
+const (
+	CodeUnknown        = "unknown"
+	CodeWorkerPanic    = "worker.panic"
+	CodeWorkerStopped  = "worker.stopped"
+	CodeSessionCleanup = "session.cleanup_degraded"
+)
+
+// defaultHints are the remediation hints of codes whose report has none.
+var defaultHints = map[string]string{
+	CodeWorkerPanic:    "The worker restarted automatically. If this keeps happening, export diagnostics and report it.",
+	CodeWorkerStopped:  "The worker stays stopped until myT-x restarts.",
+	CodeSessionCleanup: "Some state of the closed session was left behind. Restarting myT-x clears it.",
+}
+
+const (
+	defaultDedupWindow   = time.Minute
+	defaultFlushInterval = 500 * time.Millisecond
+	defaultRateWindow    = time.Minute
+	defaultMaxGroups     = 10
+)
+
+// Report is one error reported by a backend subsystem.
+type Report struct {
+	// Code is one of the Code constants. Empty means CodeUnknown.
+	Code string
+	// Severity is SeverityWarn or SeverityError. Empty means SeverityError.
+	Severity string
+	// Source names the subsystem, e.g. the worker name.
+	Source string
+	// Message is the one-line summary shown in the toast.
+	Message string
+	// Detail is the full error text behind "view details".
+	Detail string
+	// Hint overrides the default remediation hint of Code.
+	Hint string
+}
+
+// Group is one deduplicated error in a Batch. Reports with the same code,
+// source, and message share a group; Detail is the latest one.
+type Group struct {
+	// Key identifies the group across batches so the UI updates its toast.
+	Key      string    `json:"key"`
+	Code     string    `json:"code"`
+	Severity string    `json:"severity"`
+	Source   string    `json:"source,omitempty"`
+	Message  string    `json:"message"`
+	Detail   string    `json:"detail,omitempty"`
+	Hint     string    `json:"hint,omitempty"`
+	Count    int       `json:"count"`
+	FirstAt  time.Time `json:"first_at"`
+	LastAt   time.Time `json:"last_at"`
+}
+
+// Batch is the payload of Event.
+type Batch struct {
+	// Groups are the groups that were created or updated since the last batch.
+	Groups []Group `json:"groups"`
+	// Suppressed counts distinct errors dropped by the rate limit since the
+	// last batch.
+	Suppressed int `json:"suppressed,omitempty"`
+}
+
+// Deps holds external dependencies injected at construction time.
+type Deps struct {
+	// Emit sends Event to the frontend. Required.
+	Emit func(name string, payload any)
+
+	// Now returns the current time. Optional: defaults to time.Now.
+	Now func() time.Time
+}
+
+// Options tunes deduplication and rate limiting. Zero fields use defaults.
+type Options struct {
+	// DedupWindow is how long a group absorbs repeats after its last report.
+	DedupWindow time.Duration
+	// FlushInterval is how long Run collects reports before emitting a batch.
+	FlushInterval time.Duration
+	// MaxGroups bounds the groups created per RateWindow; further distinct
+	// errors are only counted as suppressed.
+	MaxGroups  int
+	RateWindow time.Duration
+}
+
+// Reporter deduplicates and rate-limits reports and emits them in batches.
+//
+// Thread-safety: all state is guarded by mu; Report may be called from any
+// goroutine.
+type Reporter struct {
+	deps Deps
+	opts Options
+
+	mu         sync.Mutex
+	groups     map[string]*Group
+	dirty      []string
+	admitted   []time.Time
+	suppressed int
+
+	notify chan struct{}
+}
+
+// New creates a Reporter. Reports are emitted by Run, or by Flush.
+func New(deps Deps, opts Options) *Reporter {
+	if deps.Emit == nil {
+		panic("errreport.New: Emit must not be nil")
+	}
+	if deps.Now == nil {
+		deps.Now = time.Now
+	}
+	if opts.DedupWindow <= 0 {
+		opts.DedupWindow = defaultDedupWindow
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = defaultFlushInterval
+	}
+	if opts.MaxGroups <= 0 {
+		opts.MaxGroups = defaultMaxGroups
+	}
+	if opts.RateWindow <= 0 {
+		opts.RateWindow = defaultRateWindow
+	}
+	return &Reporter{
+		deps:   deps,
+		opts:   opts,
+		groups: make(map[string]*Group),
+		notify: make(chan struct{}, 1),
+	}
+}
+
+// Report records one error. A repeat of a live group only bumps its count;
+// a new group is dropped as suppressed when the rate limit is reached.
+func (r *Reporter) Report(report Report) {
+	report = normalize(report)
+	key := groupKey(report)
+	now := r.deps.Now()
+
+	r.mu.Lock()
+	r.pruneLocked(now)
+	if group, ok := r.groups[key]; ok {
+		group.Count++
+		group.LastAt = now
+		if report.Detail != "" {
+			group.Detail = report.Detail
+		}
+		r.markDirtyLocked(key)
+	} else if len(r.admitted) >= r.opts.MaxGroups {
+		r.suppressed++
+	} else {
+		r.admitted = append(r.admitted, now)
+		r.groups[key] = &Group{
+			Key:      key,
+			Code:     report.Code,
+			Severity: report.Severity,
+			Source:   report.Source,
+			Message:  report.Message,
+			Detail:   report.Detail,
+			Hint:     report.Hint,
+			Count:    1,
+			FirstAt:  now,
+			LastAt:   now,
+		}
+		r.markDirtyLocked(key)
+	}
+	r.mu.Unlock()
+
+	select {
+	case r.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Run emits a batch FlushInterval after the first pending report until ctx
+// is canceled, so a burst of reports becomes one event.
+func (r *Reporter) Run(ctx context.Context) {
+	timer := time.NewTimer(r.opts.FlushInterval)
+	timer.Stop()
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.notify:
+			timer.Reset(r.opts.FlushInterval)
+			select {
+			case <-ctx.Done():
+				return
+			case <-timer.C:
+			}
+			r.Flush()
+		}
+	}
+}
+
+// Flush emits the pending changes as one Batch, if there are any.
+func (r *Reporter) Flush() {
+	r.mu.Lock()
+	batch := Batch{Groups: make([]Group, 0, len(r.dirty)), Suppressed: r.suppressed}
+	for _, key := range r.dirty {
+		if group, ok := r.groups[key]; ok {
+			batch.Groups = append(batch.Groups, *group)
+		}
+	}
+	r.dirty = nil
+	r.suppressed = 0
+	r.mu.Unlock()
+
+	if len(batch.Groups) == 0 && batch.Suppressed == 0 {
+		return
+	}
+	r.deps.Emit(Event, batch)
+}
+
+func (r *Reporter) markDirtyLocked(key string) {
+	if !slices.Contains(r.dirty, key) {
+		r.dirty = append(r.dirty, key)
+	}
+}
+
+// pruneLocked forgets groups past their dedup window and admissions past
+// the rate window.
+func (r *Reporter) pruneLocked(now time.Time) {
+	for key, group := range r.groups {
+		if now.Sub(group.LastAt) >= r.opts.DedupWindow {
+			delete(r.groups, key)
+		}
+	}
+	kept := r.admitted[:0]
+	for _, at := range r.admitted {
+		if now.Sub(at) < r.opts.RateWindow {
+			kept = append(kept, at)
+		}
+	}
+	r.admitted = kept
+}
+
+func normalize(report Report) Report {
+	report.Code = strings.TrimSpace(report.Code)
+	if report.Code == "" {
+		report.Code = CodeUnknown
+	}
+	if report.Severity != SeverityWarn {
+		report.Severity = SeverityError
+	}
+	report.Source = strings.TrimSpace(report.Source)
+	report.Message = strings.TrimSpace(report.Message)
+	report.Detail = strings.TrimSpace(report.Detail)
+	report.Hint = strings.TrimSpace(report.Hint)
+	if report.Hint == "" {
+		report.Hint = defaultHints[report.Code]
+	}
+	return report
+}
+
+func groupKey(report Report) string {
+	h := fnv.New64a()
+	for _, part := range []string{report.Code, report.Source, report.Message} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return strconv.FormatUint(h.Sum64(), 36)
+}
@@ -0,0 +1,149 @@
+package errreport
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+type emitRecorder struct {
+	mu      sync.Mutex
+	batches []Batch
+}
+
+func (e *emitRecorder) emit(name string, payload any) {
+	if name != Event {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.batches = append(e.batches, payload.(Batch))
+}
+
+func (e *emitRecorder) all() []Batch {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]Batch(nil), e.batches...)
+}
+
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func newTestReporter(opts Options) (*Reporter, *emitRecorder, *fakeClock) {
+	rec := &emitRecorder{}
+	clock := &fakeClock{now: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)}
+	return New(Deps{Emit: rec.emit, Now: clock.Now}, opts), rec, clock
+}
+
+func TestReportDeduplicatesWithinWindow(t *testing.T) {
+	r, rec, clock := newTestReporter(Options{DedupWindow: time.Minute})
+
+	r.Report(Report{Code: CodeWorkerPanic, Source: "ports", Message: "worker panicked", Detail: "first"})
+	clock.now = clock.now.Add(10 * time.Second)
+	r.Report(Report{Code: CodeWorkerPanic, Source: "ports", Message: "worker panicked", Detail: "second"})
+	r.Report(Report{Code: CodeWorkerPanic, Source: "jump-list", Message: "worker panicked"})
+	r.Flush()
+
+	batches := rec.all()
+	if len(batches) != 1 || len(batches[0].Groups) != 2 {
+		t.Fatalf("batches = %+v, want one batch with two groups", batches)
+	}
+	ports := batches[0].Groups[0]
+	if ports.Count != 2 || ports.Detail != "second" || ports.Severity != SeverityError {
+		t.Fatalf("ports group = %+v, want count 2 with the latest detail", ports)
+	}
+	if ports.Hint != defaultHints[CodeWorkerPanic] {
+		t.Fatalf("Hint = %q, want the default hint of the code", ports.Hint)
+	}
+	if !ports.LastAt.After(ports.FirstAt) {
+		t.Fatalf("LastAt %v should be after FirstAt %v", ports.LastAt, ports.FirstAt)
+	}
+
+	// A repeat updates the same group; an expired group starts over.
+	r.Report(Report{Code: CodeWorkerPanic, Source: "ports", Message: "worker panicked"})
+	r.Flush()
+	if got := rec.all()[1].Groups; len(got) != 1 || got[0].Key != ports.Key || got[0].Count != 3 {
+		t.Fatalf("repeat batch = %+v, want the ports group with count 3", got)
+	}
+	clock.now = clock.now.Add(2 * time.Minute)
+	r.Report(Report{Code: CodeWorkerPanic, Source: "ports", Message: "worker panicked"})
+	r.Flush()
+	if got := rec.all()[2].Groups; len(got) != 1 || got[0].Count != 1 {
+		t.Fatalf("batch after dedup window = %+v, want a fresh group", got)
+	}
+}
+
+func TestReportRateLimitsDistinctErrors(t *testing.T) {
+	r, rec, clock := newTestReporter(Options{MaxGroups: 2, RateWindow: time.Minute})
+
+	for _, message := range []string{"a", "b", "c", "d"} {
+		r.Report(Report{Message: message})
+	}
+	// Repeats of admitted groups are not rate-limited.
+	r.Report(Report{Message: "a"})
+	r.Flush()
+
+	batch := rec.all()[0]
+	if len(batch.Groups) != 2 || batch.Suppressed != 2 {
+		t.Fatalf("batch = %+v, want 2 groups and 2 suppressed", batch)
+	}
+	if batch.Groups[0].Count != 2 || batch.Groups[0].Code != CodeUnknown {
+		t.Fatalf("first group = %+v, want count 2 and the unknown code", batch.Groups[0])
+	}
+
+	clock.now = clock.now.Add(time.Minute)
+	r.Report(Report{Message: "e"})
+	r.Flush()
+	if got := rec.all()[1]; len(got.Groups) != 1 || got.Suppressed != 0 {
+		t.Fatalf("batch after rate window = %+v, want the new group admitted", got)
+	}
+}
+
+func TestFlushWithoutChangesEmitsNothing(t *testing.T) {
+	r, rec, _ := newTestReporter(Options{})
+	r.Flush()
+	if got := rec.all(); len(got) != 0 {
+		t.Fatalf("batches = %+v, want none", got)
+	}
+}
+
+func TestReportKeepsExplicitHintAndWarnSeverity(t *testing.T) {
+	r, rec, _ := newTestReporter(Options{})
+	r.Report(Report{Code: CodeWorkerPanic, Severity: SeverityWarn, Message: "m", Hint: "custom"})
+	r.Flush()
+	group := rec.all()[0].Groups[0]
+	if group.Hint != "custom" || group.Severity != SeverityWarn {
+		t.Fatalf("group = %+v, want the explicit hint and warn severity", group)
+	}
+}
+
+func TestRunCoalescesBurstIntoOneBatch(t *testing.T) {
+	rec := &emitRecorder{}
+	r := New(Deps{Emit: rec.emit}, Options{FlushInterval: 20 * time.Millisecond})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		r.Run(ctx)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	for range 50 {
+		r.Report(Report{Code: CodeSessionCleanup, Message: "cleanup failed"})
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for len(rec.all()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("Run did not emit a batch")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := rec.all()[0].Groups; len(got) != 1 || got[0].Count != 50 {
+		t.Fatalf("first batch = %+v, want one group with count 50", got)
+	}
+}
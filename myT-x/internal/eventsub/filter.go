@@ -0,0 +1,179 @@
+// Package eventsub filters the runtime events sent to the frontend by what
+// it currently renders. The frontend declares event classes, sessions, and
+// panes it shows; events outside of them are not sent over the bridge, so a
+// large fleet of sessions does not flood a window that shows one of them.
+package eventsub
+
+import (
+	"reflect"
+	"strings"
+	"sync/atomic"
+)
+
+// alwaysDelivered lists the event classes the frontend needs regardless of
+// what it renders: session snapshots and lifecycle, app state, and config.
+var alwaysDelivered = map[string]struct{}{
+	"tmux":   {},
+	"app":    {},
+	"config": {},
+	"system": {},
+}
+
+// sessionKeys are the payload fields naming the session an event is about.
+var sessionKeys = []string{"session_name", "sessionName", "session"}
+
+// Spec declares what the frontend renders. A nil list allows everything;
+// an empty list allows nothing.
+type Spec struct {
+	// Classes lists the event classes to deliver. The class of an event is
+	// the part of its name before the first ':', e.g. "worktree".
+	Classes []string `json:"classes,omitempty"`
+	// Sessions lists the sessions whose events are delivered. Events that
+	// name no session are always delivered.
+	Sessions []string `json:"sessions,omitempty"`
+	// Panes lists the panes whose output is delivered.
+	Panes []string `json:"panes,omitempty"`
+}
+
+// compiledSpec is a Spec turned into sets. A nil set allows everything.
+type compiledSpec struct {
+	classes  map[string]struct{}
+	sessions map[string]struct{}
+	panes    map[string]struct{}
+}
+
+// Filter holds the current Spec. The zero value allows everything.
+//
+// Thread-safety: the spec is swapped atomically; all methods are safe for
+// concurrent use.
+type Filter struct {
+	spec atomic.Pointer[compiledSpec]
+}
+
+// NewFilter creates a filter that allows everything until Set is called.
+func NewFilter() *Filter {
+	return &Filter{}
+}
+
+// Set replaces the declared spec.
+func (f *Filter) Set(spec Spec) {
+	f.spec.Store(&compiledSpec{
+		classes:  toSet(spec.Classes),
+		sessions: toSet(spec.Sessions),
+		panes:    toSet(spec.Panes),
+	})
+}
+
+// Reset allows everything again, e.g. when the frontend reloads.
+func (f *Filter) Reset() {
+	f.spec.Store(nil)
+}
+
+// Allows reports whether the event should be sent to the frontend.
+func (f *Filter) Allows(name string, payload any) bool {
+	spec := f.spec.Load()
+	if spec == nil {
+		return true
+	}
+	class, _, _ := strings.Cut(name, ":")
+	if _, ok := alwaysDelivered[class]; ok {
+		return true
+	}
+	if spec.classes != nil {
+		if _, ok := spec.classes[class]; !ok {
+			return false
+		}
+	}
+	if spec.sessions != nil {
+		if session, ok := payloadSession(payload); ok {
+			if _, ok := spec.sessions[session]; !ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// AllowsPaneOutput reports whether the output of paneID should be sent to
+// the frontend.
+func (f *Filter) AllowsPaneOutput(paneID string) bool {
+	spec := f.spec.Load()
+	if spec == nil || spec.panes == nil {
+		return true
+	}
+	_, ok := spec.panes[paneID]
+	return ok
+}
+
+func toSet(values []string) map[string]struct{} {
+	if values == nil {
+		return nil
+	}
+	set := make(map[string]struct{}, len(values))
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			set[value] = struct{}{}
+		}
+	}
+	return set
+}
+
+// payloadSession returns the session named by an event payload: a map or a
+// struct (or a pointer to one) with a session_name, sessionName, or session
+// string field. It reports false when the payload names none.
+func payloadSession(payload any) (string, bool) {
+	v := reflect.ValueOf(payload)
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return "", false
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return "", false
+		}
+		for _, key := range sessionKeys {
+			value := v.MapIndex(reflect.ValueOf(key).Convert(v.Type().Key()))
+			if session, ok := stringValue(value); ok {
+				return session, true
+			}
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for _, key := range sessionKeys {
+			for i := range t.NumField() {
+				field := t.Field(i)
+				if !field.IsExported() || jsonName(field) != key {
+					continue
+				}
+				if session, ok := stringValue(v.Field(i)); ok {
+					return session, true
+				}
+			}
+		}
+	}
+	return "", false
+}
+
+func stringValue(v reflect.Value) (string, bool) {
+	if !v.IsValid() {
+		return "", false
+	}
+	if v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.String || v.String() == "" {
+		return "", false
+	}
+	return v.String(), true
+}
+
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
@@ -0,0 +1,57 @@
+package eventsub
+
+import "testing"
+
+type sessionPayload struct {
+	SessionName string `json:"session_name"`
+	PaneID      string `json:"pane_id"`
+}
+
+func TestFilterAllows(t *testing.T) {
+	f := NewFilter()
+	if !f.Allows("worktree:pull-failed", nil) || !f.AllowsPaneOutput("%1") {
+		t.Fatal("a filter without a spec should allow everything")
+	}
+
+	f.Set(Spec{Classes: []string{"pane", "git"}, Sessions: []string{"alpha"}, Panes: []string{"%1"}})
+	tests := []struct {
+		name    string
+		event   string
+		payload any
+		want    bool
+	}{
+		{"always delivered class", "tmux:session-created", map[string]any{"session_name": "beta"}, true},
+		{"config class", "config:updated", nil, true},
+		{"class not declared", "worktree:pull-failed", map[string]any{"session_name": "alpha"}, false},
+		{"declared session in map", "git:status-changed", map[string]any{"session_name": "alpha"}, true},
+		{"other session in map", "git:status-changed", map[string]string{"sessionName": "beta"}, false},
+		{"declared session in struct", "pane:activity-changed", sessionPayload{SessionName: "alpha"}, true},
+		{"other session in struct pointer", "pane:activity-changed", &sessionPayload{SessionName: "beta"}, false},
+		{"payload without session", "pane:notification", map[string]any{"pane_id": "%2"}, true},
+		{"non-object payload", "pane:data:%2", "output", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := f.Allows(tt.event, tt.payload); got != tt.want {
+				t.Fatalf("Allows(%q, %#v) = %v, want %v", tt.event, tt.payload, got, tt.want)
+			}
+		})
+	}
+
+	if !f.AllowsPaneOutput("%1") || f.AllowsPaneOutput("%2") {
+		t.Fatal("AllowsPaneOutput() should follow the declared panes")
+	}
+
+	f.Set(Spec{Classes: []string{}, Panes: []string{}})
+	if f.Allows("git:status-changed", nil) || f.AllowsPaneOutput("%1") {
+		t.Fatal("empty lists should allow nothing")
+	}
+	if !f.Allows("app:errors", nil) {
+		t.Fatal("app events should be delivered even with no declared class")
+	}
+
+	f.Reset()
+	if !f.Allows("git:status-changed", nil) || !f.AllowsPaneOutput("%2") {
+		t.Fatal("Reset() should allow everything again")
+	}
+}
@@ -0,0 +1,218 @@
+// Package faultinject is a development-only mode that injects latency,
+// dropped responses, and partial failures into the IPC layer and git
+// operations. It is off unless the MYTX_DEV_FAULTS environment variable is
+// set, e.g.
+//
+//	MYTX_DEV_FAULTS=latency=200ms,jitter=300ms,drop=0.05,fail=0.1,partial=0.05,scope=ipc
+//
+// Keys:
+//   - latency: fixed delay added before every operation (Go duration).
+//   - jitter:  random extra delay in [0, jitter).
+//   - drop:    probability the operation gets no response at all.
+//   - fail:    probability the operation fails with ErrInjected.
+//   - partial: probability the operation returns truncated output and fails.
+//   - scope:   "ipc", "git", or "ipc|git" (default both).
+//
+// Probabilities are in [0, 1] and are checked in the order drop, fail,
+// partial. Git operations cannot be dropped; a drop counts as a failure.
+package faultinject
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EnvVar enables fault injection when set to a non-empty spec.
+const EnvVar = "MYTX_DEV_FAULTS"
+
+// ErrInjected is returned for failures produced by the injector.
+var ErrInjected = errors.New("injected fault (" + EnvVar + ")")
+
+// Scope selects which layer a fault applies to.
+type Scope string
+
+const (
+	ScopeIPC Scope = "ipc"
+	ScopeGit Scope = "git"
+)
+
+// Kind is the failure mode chosen for one operation.
+type Kind int
+
+const (
+	// None lets the operation through unchanged (apart from Delay).
+	None Kind = iota
+	// Drop closes the connection without a response.
+	Drop
+	// Fail replaces the result with ErrInjected.
+	Fail
+	// Partial truncates the output and reports ErrInjected.
+	Partial
+)
+
+// Fault is the injected behavior for one operation.
+type Fault struct {
+	Delay time.Duration
+	Kind  Kind
+}
+
+// Wait sleeps for f.Delay or until ctx is done.
+func (f Fault) Wait(ctx context.Context) error {
+	if f.Delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(f.Delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Injector decides the fault for each operation.
+// A nil *Injector injects nothing.
+//
+// Thread-safety is managed internally via mu. No external locking is required.
+type Injector struct {
+	latency time.Duration
+	jitter  time.Duration
+	drop    float64
+	fail    float64
+	partial float64
+	scopes  map[Scope]bool
+
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// Parse builds an Injector from spec. rng defaults to a randomly seeded
+// source when nil; tests pass a seeded one for reproducible faults.
+func Parse(spec string, rng *rand.Rand) (*Injector, error) {
+	if rng == nil {
+		rng = rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+	}
+	inj := &Injector{
+		scopes: map[Scope]bool{ScopeIPC: true, ScopeGit: true},
+		rng:    rng,
+	}
+	for field := range strings.SplitSeq(spec, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return nil, fmt.Errorf("%s: %q is not key=value", EnvVar, field)
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		var err error
+		switch key {
+		case "latency":
+			inj.latency, err = parseDuration(value)
+		case "jitter":
+			inj.jitter, err = parseDuration(value)
+		case "drop":
+			inj.drop, err = parseProbability(value)
+		case "fail":
+			inj.fail, err = parseProbability(value)
+		case "partial":
+			inj.partial, err = parseProbability(value)
+		case "scope":
+			inj.scopes, err = parseScopes(value)
+		default:
+			err = errors.New("unknown key")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s: %w", EnvVar, key, err)
+		}
+	}
+	if inj.drop+inj.fail+inj.partial > 1 {
+		return nil, fmt.Errorf("%s: drop+fail+partial must not exceed 1", EnvVar)
+	}
+	return inj, nil
+}
+
+func parseDuration(value string) (time.Duration, error) {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if d < 0 {
+		return 0, errors.New("must not be negative")
+	}
+	return d, nil
+}
+
+func parseProbability(value string) (float64, error) {
+	p, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, err
+	}
+	if p < 0 || p > 1 {
+		return 0, errors.New("must be between 0 and 1")
+	}
+	return p, nil
+}
+
+func parseScopes(value string) (map[Scope]bool, error) {
+	scopes := map[Scope]bool{}
+	for name := range strings.SplitSeq(value, "|") {
+		switch scope := Scope(strings.TrimSpace(name)); scope {
+		case ScopeIPC, ScopeGit:
+			scopes[scope] = true
+		default:
+			return nil, fmt.Errorf("unknown scope %q", name)
+		}
+	}
+	return scopes, nil
+}
+
+// FromEnv returns the injector configured by EnvVar, or nil when the
+// variable is unset or invalid. The environment is read once per process.
+var FromEnv = sync.OnceValue(func() *Injector {
+	spec := strings.TrimSpace(os.Getenv(EnvVar))
+	if spec == "" {
+		return nil
+	}
+	inj, err := Parse(spec, nil)
+	if err != nil {
+		slog.Warn("[DEV-FAULTS] ignoring invalid fault injection spec", "error", err)
+		return nil
+	}
+	slog.Warn("[DEV-FAULTS] fault injection enabled; do not use in production", "spec", spec)
+	return inj
+})
+
+// Next returns the fault for the next operation in scope.
+func (i *Injector) Next(scope Scope) Fault {
+	if i == nil || !i.scopes[scope] {
+		return Fault{}
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	fault := Fault{Delay: i.latency}
+	if i.jitter > 0 {
+		fault.Delay += time.Duration(i.rng.Int64N(int64(i.jitter)))
+	}
+	roll := i.rng.Float64()
+	switch {
+	case roll < i.drop:
+		fault.Kind = Drop
+	case roll < i.drop+i.fail:
+		fault.Kind = Fail
+	case roll < i.drop+i.fail+i.partial:
+		fault.Kind = Partial
+	}
+	return fault
+}
@@ -0,0 +1,86 @@
+package faultinject
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	inj, err := Parse(" latency=200ms, jitter=1s,drop=0.1,fail=0.2,partial=0.3,scope=git ", nil)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if inj.latency != 200*time.Millisecond || inj.jitter != time.Second {
+		t.Fatalf("latency=%v jitter=%v, want 200ms and 1s", inj.latency, inj.jitter)
+	}
+	if inj.drop != 0.1 || inj.fail != 0.2 || inj.partial != 0.3 {
+		t.Fatalf("drop=%v fail=%v partial=%v", inj.drop, inj.fail, inj.partial)
+	}
+	if inj.scopes[ScopeIPC] || !inj.scopes[ScopeGit] {
+		t.Fatalf("scopes = %v, want git only", inj.scopes)
+	}
+}
+
+func TestParseRejectsInvalidSpecs(t *testing.T) {
+	for _, spec := range []string{
+		"latency",
+		"latency=fast",
+		"jitter=-1s",
+		"drop=2",
+		"fail=-0.1",
+		"scope=ws",
+		"speed=1",
+		"drop=0.6,fail=0.6",
+	} {
+		if _, err := Parse(spec, nil); err == nil {
+			t.Errorf("Parse(%q) error = nil", spec)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	var nilInjector *Injector
+	if got := nilInjector.Next(ScopeIPC); got != (Fault{}) {
+		t.Fatalf("nil Next() = %+v, want zero fault", got)
+	}
+
+	inj, err := Parse("latency=10ms,jitter=5ms,drop=0.25,fail=0.25,partial=0.25", rand.New(rand.NewPCG(1, 2)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := map[Kind]int{}
+	for range 1000 {
+		fault := inj.Next(ScopeGit)
+		if fault.Delay < 10*time.Millisecond || fault.Delay >= 15*time.Millisecond {
+			t.Fatalf("Delay = %v, want in [10ms, 15ms)", fault.Delay)
+		}
+		counts[fault.Kind]++
+	}
+	for _, kind := range []Kind{None, Drop, Fail, Partial} {
+		if counts[kind] < 150 || counts[kind] > 350 {
+			t.Errorf("kind %d chosen %d times in 1000, want about 250", kind, counts[kind])
+		}
+	}
+
+	gitOnly, err := Parse("scope=git,fail=1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := gitOnly.Next(ScopeIPC); got.Kind != None {
+		t.Fatalf("Next(ipc) with scope=git = %+v, want no fault", got)
+	}
+}
+
+func TestFaultWait(t *testing.T) {
+	if err := (Fault{}).Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() without delay error = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := (Fault{Delay: time.Hour}).Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Wait() on canceled context error = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,352 @@
+// Package filedrop places files dropped on a pane into the pane's working
+// directory, either as copies or as links to the originals.
+package filedrop
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MaxSources caps the number of entries in one drop.
+const MaxSources = 100
+
+// ErrTooLarge is returned when the files to copy exceed Options.MaxBytes.
+var ErrTooLarge = errors.New("dropped files exceed the size limit")
+
+// Options controls Place.
+type Options struct {
+	// DestDir is the existing directory the files are placed in.
+	DestDir string
+	// Link links to the sources instead of copying them.
+	Link bool
+	// Overwrite replaces existing destination entries. Without it Place
+	// places nothing and reports the conflicts.
+	Overwrite bool
+	// MaxBytes caps the total size of copied files. Zero means no cap.
+	MaxBytes int64
+}
+
+// Result reports the outcome of Place.
+type Result struct {
+	// Paths are the placed paths in the order of the sources. A source that
+	// already is in DestDir is reported as is.
+	Paths []string
+	// Conflicts are the names in DestDir that already exist. When set and
+	// Overwrite is false, nothing was placed.
+	Conflicts []string
+}
+
+// placement is one source and its destination.
+type placement struct {
+	src  string
+	dst  string
+	info fs.FileInfo
+}
+
+// Place copies or links sources into opts.DestDir. Every source is checked
+// (existence, name clashes, total size, conflicts and, when overwriting,
+// whether each existing entry can be replaced) before anything is written,
+// so a rejected drop leaves DestDir untouched.
+func Place(sources []string, opts Options) (Result, error) {
+	if len(sources) == 0 {
+		return Result{}, errors.New("no files to place")
+	}
+	if len(sources) > MaxSources {
+		return Result{}, fmt.Errorf("too many files dropped: %d (max %d)", len(sources), MaxSources)
+	}
+	destDir := filepath.Clean(strings.TrimSpace(opts.DestDir))
+	if !filepath.IsAbs(destDir) {
+		return Result{}, fmt.Errorf("destination directory must be absolute: %q", opts.DestDir)
+	}
+	if info, err := os.Stat(destDir); err != nil {
+		return Result{}, fmt.Errorf("destination directory: %w", err)
+	} else if !info.IsDir() {
+		return Result{}, fmt.Errorf("destination is not a directory: %s", destDir)
+	}
+
+	placements, err := plan(sources, destDir)
+	if err != nil {
+		return Result{}, err
+	}
+	if !opts.Link && opts.MaxBytes > 0 {
+		if err := checkSize(placements, opts.MaxBytes); err != nil {
+			return Result{}, err
+		}
+	}
+
+	var result Result
+	for _, p := range placements {
+		if p.dst == "" {
+			continue
+		}
+		existing, err := os.Lstat(p.dst)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return Result{}, fmt.Errorf("check destination %s: %w", p.dst, err)
+		}
+		result.Conflicts = append(result.Conflicts, filepath.Base(p.dst))
+		if opts.Overwrite {
+			if err := checkReplace(p, existing, opts.Link); err != nil {
+				return Result{}, fmt.Errorf("place %s: %w", filepath.Base(p.src), err)
+			}
+		}
+	}
+	if len(result.Conflicts) > 0 && !opts.Overwrite {
+		return result, nil
+	}
+
+	for _, p := range placements {
+		if p.dst == "" {
+			result.Paths = append(result.Paths, p.src)
+			continue
+		}
+		if opts.Link {
+			err = link(p)
+		} else {
+			err = copyEntry(p)
+		}
+		if err != nil {
+			return result, fmt.Errorf("place %s: %w", filepath.Base(p.src), err)
+		}
+		result.Paths = append(result.Paths, p.dst)
+	}
+	return result, nil
+}
+
+// plan resolves the destination of every source. A source already in
+// destDir gets an empty dst.
+func plan(sources []string, destDir string) ([]placement, error) {
+	placements := make([]placement, 0, len(sources))
+	names := make(map[string]struct{}, len(sources))
+	for _, source := range sources {
+		src := filepath.Clean(strings.TrimSpace(source))
+		if !filepath.IsAbs(src) {
+			return nil, fmt.Errorf("dropped path must be absolute: %q", source)
+		}
+		info, err := os.Stat(src)
+		if err != nil {
+			return nil, fmt.Errorf("dropped file: %w", err)
+		}
+		name := filepath.Base(src)
+		key := strings.ToLower(name)
+		if _, dup := names[key]; dup {
+			return nil, fmt.Errorf("more than one dropped file is named %q", name)
+		}
+		names[key] = struct{}{}
+
+		dst := filepath.Join(destDir, name)
+		if strings.EqualFold(dst, src) {
+			placements = append(placements, placement{src: src, info: info})
+			continue
+		}
+		if info.IsDir() && isWithin(destDir, src) {
+			return nil, fmt.Errorf("cannot place %s inside itself", src)
+		}
+		placements = append(placements, placement{src: src, dst: dst, info: info})
+	}
+	return placements, nil
+}
+
+// checkSize fails when the regular files under the sources exceed maxBytes.
+func checkSize(placements []placement, maxBytes int64) error {
+	var total int64
+	for _, p := range placements {
+		if p.dst == "" {
+			continue
+		}
+		if !p.info.IsDir() {
+			total += p.info.Size()
+		} else if err := filepath.WalkDir(p.src, func(_ string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.Type().IsRegular() {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			total += info.Size()
+			if total > maxBytes {
+				return fs.SkipAll
+			}
+			return nil
+		}); err != nil {
+			return fmt.Errorf("measure %s: %w", filepath.Base(p.src), err)
+		}
+		if total > maxBytes {
+			return fmt.Errorf("%w: more than %d MB", ErrTooLarge, maxBytes>>20)
+		}
+	}
+	return nil
+}
+
+// link creates a symbolic link to the source, falling back to a hard link
+// for files when symbolic links are not permitted (Windows without
+// Developer Mode). An existing directory is never replaced by a link.
+func link(p placement) error {
+	if err := removeForReplace(p.dst, false); err != nil {
+		return err
+	}
+	err := os.Symlink(p.src, p.dst)
+	if err == nil || p.info.IsDir() {
+		return err
+	}
+	if hardErr := os.Link(p.src, p.dst); hardErr != nil {
+		return errors.Join(err, hardErr)
+	}
+	return nil
+}
+
+// copyEntry copies a file, or a directory tree merged into an existing
+// destination directory. Symbolic links inside a directory are skipped.
+func copyEntry(p placement) error {
+	if !p.info.IsDir() {
+		if err := removeForReplace(p.dst, false); err != nil {
+			return err
+		}
+		return copyFile(p.src, p.dst, p.info.Mode().Perm())
+	}
+	if err := removeForReplace(p.dst, true); err != nil {
+		return err
+	}
+	return filepath.WalkDir(p.src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(p.src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(p.dst, rel)
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, 0o755)
+		case d.Type().IsRegular():
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			return copyFile(path, target, info.Mode().Perm())
+		default:
+			return nil
+		}
+	})
+}
+
+// removeForReplace clears dst before it is replaced. A directory is kept
+// when keepDir is set (a copied directory merges into it) and refused
+// otherwise.
+func removeForReplace(dst string, keepDir bool) error {
+	info, err := os.Lstat(dst)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := replaceConflict(info, filepath.Base(dst), keepDir); err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return nil
+	}
+	return os.Remove(dst)
+}
+
+// replaceConflict reports an existing entry that removeForReplace refuses:
+// a directory unless keepDir is set, or a file when it is.
+func replaceConflict(existing fs.FileInfo, name string, keepDir bool) error {
+	if existing.IsDir() && !keepDir {
+		return fmt.Errorf("a directory named %s already exists", name)
+	}
+	if !existing.IsDir() && keepDir {
+		return fmt.Errorf("a file named %s already exists", name)
+	}
+	return nil
+}
+
+// checkReplace fails when p cannot overwrite the existing entry at its
+// destination. A copied directory is merged, so every entry of the source
+// tree is checked against the destination tree as well: a file where the
+// copy needs a directory, or the other way round, would stop the copy
+// halfway.
+func checkReplace(p placement, existing fs.FileInfo, link bool) error {
+	keepDir := !link && p.info.IsDir()
+	if err := replaceConflict(existing, filepath.Base(p.dst), keepDir); err != nil {
+		return err
+	}
+	if !keepDir {
+		return nil
+	}
+	return filepath.WalkDir(p.src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		// copyEntry skips everything but directories and regular files.
+		if path == p.src || (!d.IsDir() && !d.Type().IsRegular()) {
+			return nil
+		}
+		rel, err := filepath.Rel(p.src, path)
+		if err != nil {
+			return err
+		}
+		target, err := os.Lstat(filepath.Join(p.dst, rel))
+		if errors.Is(err, fs.ErrNotExist) {
+			if d.IsDir() {
+				// Nothing below a missing directory can clash.
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return replaceConflict(target, filepath.Join(filepath.Base(p.dst), rel), d.IsDir())
+	})
+}
+
+// copyFile copies src to dst through a temporary file in dst's directory so
+// an interrupted copy never leaves a truncated dst.
+func copyFile(src, dst string, perm fs.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	tmp, err := os.CreateTemp(filepath.Dir(dst), "."+filepath.Base(dst)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	_, copyErr := io.Copy(tmp, in)
+	closeErr := tmp.Close()
+	if err := errors.Join(copyErr, closeErr); err != nil {
+		_ = os.Remove(tmpName)
+		return err
+	}
+	if err := os.Chmod(tmpName, perm|0o200); err != nil {
+		_ = os.Remove(tmpName)
+		return err
+	}
+	if err := os.Rename(tmpName, dst); err != nil {
+		_ = os.Remove(tmpName)
+		return err
+	}
+	return nil
+}
+
+// isWithin reports whether path is dir or inside it.
+func isWithin(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && filepath.IsLocal(rel)
+}
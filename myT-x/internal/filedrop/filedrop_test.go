@@ -0,0 +1,211 @@
+package filedrop
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile(%s) error = %v", path, err)
+	}
+	return string(data)
+}
+
+func TestPlaceCopiesFilesAndDirectories(t *testing.T) {
+	src := t.TempDir()
+	dest := t.TempDir()
+	writeFile(t, filepath.Join(src, "spec.md"), "spec")
+	writeFile(t, filepath.Join(src, "assets", "logo.svg"), "logo")
+	writeFile(t, filepath.Join(src, "assets", "icons", "a.svg"), "a")
+
+	result, err := Place([]string{filepath.Join(src, "spec.md"), filepath.Join(src, "assets")}, Options{DestDir: dest})
+	if err != nil {
+		t.Fatalf("Place() error = %v", err)
+	}
+	want := []string{filepath.Join(dest, "spec.md"), filepath.Join(dest, "assets")}
+	if !slices.Equal(result.Paths, want) || len(result.Conflicts) != 0 {
+		t.Fatalf("Place() = %+v, want paths %v", result, want)
+	}
+	if got := readFile(t, filepath.Join(dest, "spec.md")); got != "spec" {
+		t.Errorf("copied file = %q, want spec", got)
+	}
+	if got := readFile(t, filepath.Join(dest, "assets", "icons", "a.svg")); got != "a" {
+		t.Errorf("copied nested file = %q, want a", got)
+	}
+}
+
+func TestPlaceReportsConflictsBeforeWriting(t *testing.T) {
+	src := t.TempDir()
+	dest := t.TempDir()
+	writeFile(t, filepath.Join(src, "a.txt"), "new a")
+	writeFile(t, filepath.Join(src, "b.txt"), "new b")
+	writeFile(t, filepath.Join(dest, "b.txt"), "old b")
+	sources := []string{filepath.Join(src, "a.txt"), filepath.Join(src, "b.txt")}
+
+	result, err := Place(sources, Options{DestDir: dest})
+	if err != nil {
+		t.Fatalf("Place() error = %v", err)
+	}
+	if !slices.Equal(result.Conflicts, []string{"b.txt"}) || len(result.Paths) != 0 {
+		t.Fatalf("Place() = %+v, want only the b.txt conflict", result)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "a.txt")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("a.txt was placed despite the conflict: %v", err)
+	}
+
+	result, err = Place(sources, Options{DestDir: dest, Overwrite: true})
+	if err != nil {
+		t.Fatalf("Place(overwrite) error = %v", err)
+	}
+	if len(result.Paths) != 2 {
+		t.Fatalf("Place(overwrite) paths = %v, want 2", result.Paths)
+	}
+	if got := readFile(t, filepath.Join(dest, "b.txt")); got != "new b" {
+		t.Errorf("overwritten file = %q, want new b", got)
+	}
+}
+
+func TestPlaceEnforcesSizeLimit(t *testing.T) {
+	src := t.TempDir()
+	dest := t.TempDir()
+	writeFile(t, filepath.Join(src, "big", "a.bin"), "0123456789")
+	writeFile(t, filepath.Join(src, "big", "b.bin"), "0123456789")
+
+	_, err := Place([]string{filepath.Join(src, "big")}, Options{DestDir: dest, MaxBytes: 15})
+	if !errors.Is(err, ErrTooLarge) {
+		t.Fatalf("Place() error = %v, want ErrTooLarge", err)
+	}
+	if entries, _ := os.ReadDir(dest); len(entries) != 0 {
+		t.Fatalf("destination has %d entries after a rejected drop, want 0", len(entries))
+	}
+	if _, err := Place([]string{filepath.Join(src, "big")}, Options{DestDir: dest, MaxBytes: 15, Link: true}); err != nil {
+		t.Fatalf("Place(link) error = %v, links are not size limited", err)
+	}
+}
+
+func TestPlaceLinksFiles(t *testing.T) {
+	src := t.TempDir()
+	dest := t.TempDir()
+	writeFile(t, filepath.Join(src, "data.csv"), "a,b")
+
+	result, err := Place([]string{filepath.Join(src, "data.csv")}, Options{DestDir: dest, Link: true})
+	if err != nil {
+		t.Fatalf("Place() error = %v", err)
+	}
+	if got := readFile(t, result.Paths[0]); got != "a,b" {
+		t.Fatalf("linked file = %q, want a,b", got)
+	}
+}
+
+func TestPlaceRejectsInvalidDrops(t *testing.T) {
+	src := t.TempDir()
+	dest := filepath.Join(src, "project")
+	writeFile(t, filepath.Join(src, "one", "x.txt"), "1")
+	writeFile(t, filepath.Join(src, "two", "x.txt"), "2")
+	writeFile(t, filepath.Join(dest, "main.go"), "package main")
+
+	tests := []struct {
+		name    string
+		sources []string
+		opts    Options
+	}{
+		{name: "no sources", opts: Options{DestDir: dest}},
+		{name: "relative source", sources: []string{"x.txt"}, opts: Options{DestDir: dest}},
+		{name: "missing source", sources: []string{filepath.Join(src, "missing")}, opts: Options{DestDir: dest}},
+		{name: "relative destination", sources: []string{filepath.Join(src, "one", "x.txt")}, opts: Options{DestDir: "project"}},
+		{name: "duplicate names", sources: []string{filepath.Join(src, "one", "x.txt"), filepath.Join(src, "two", "x.txt")}, opts: Options{DestDir: dest}},
+		{name: "directory into itself", sources: []string{src}, opts: Options{DestDir: dest}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Place(tt.sources, tt.opts); err == nil {
+				t.Fatal("Place() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestPlaceKeepsSourcesAlreadyInDestination(t *testing.T) {
+	dest := t.TempDir()
+	writeFile(t, filepath.Join(dest, "notes.md"), "notes")
+
+	result, err := Place([]string{filepath.Join(dest, "notes.md")}, Options{DestDir: dest})
+	if err != nil {
+		t.Fatalf("Place() error = %v", err)
+	}
+	if !slices.Equal(result.Paths, []string{filepath.Join(dest, "notes.md")}) || len(result.Conflicts) != 0 {
+		t.Fatalf("Place() = %+v, want the source itself", result)
+	}
+}
+
+func TestPlaceOverwriteChecksEveryConflictBeforeWriting(t *testing.T) {
+	tests := []struct {
+		name string
+		// setup creates the sources in src and the clashing entries in dest.
+		setup func(t *testing.T, src, dest string)
+		link  bool
+	}{
+		{
+			name: "file over directory",
+			setup: func(t *testing.T, src, dest string) {
+				writeFile(t, filepath.Join(src, "z.txt"), "new z")
+				writeFile(t, filepath.Join(dest, "z.txt", "keep"), "old")
+			},
+		},
+		{
+			name: "directory over file",
+			setup: func(t *testing.T, src, dest string) {
+				writeFile(t, filepath.Join(src, "z.txt", "inner"), "new")
+				writeFile(t, filepath.Join(dest, "z.txt"), "old z")
+			},
+		},
+		{
+			name: "nested file over directory",
+			setup: func(t *testing.T, src, dest string) {
+				writeFile(t, filepath.Join(src, "z.txt", "docs", "readme"), "new")
+				writeFile(t, filepath.Join(dest, "z.txt", "docs", "readme", "keep"), "old")
+			},
+		},
+		{
+			name: "link over directory",
+			setup: func(t *testing.T, src, dest string) {
+				writeFile(t, filepath.Join(src, "z.txt"), "new z")
+				writeFile(t, filepath.Join(dest, "z.txt", "keep"), "old")
+			},
+			link: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := t.TempDir()
+			dest := t.TempDir()
+			writeFile(t, filepath.Join(src, "a.txt"), "new a")
+			writeFile(t, filepath.Join(dest, "a.txt"), "old a")
+			tt.setup(t, src, dest)
+
+			sources := []string{filepath.Join(src, "a.txt"), filepath.Join(src, "z.txt")}
+			if _, err := Place(sources, Options{DestDir: dest, Overwrite: true, Link: tt.link}); err == nil {
+				t.Fatal("Place() error = nil, want the conflict")
+			}
+			if got := readFile(t, filepath.Join(dest, "a.txt")); got != "old a" {
+				t.Fatalf("a.txt = %q after a rejected drop, want it untouched", got)
+			}
+		})
+	}
+}
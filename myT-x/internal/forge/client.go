@@ -0,0 +1,264 @@
+// Package forge opens pull requests on GitHub and merge requests on GitLab
+// through their REST APIs, so a pushed worktree branch can go up for review
+// without leaving myT-x. When a request for the branch is already open, it
+// is returned instead of failing.
+package forge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// RequestTimeout bounds one API request.
+	RequestTimeout = 30 * time.Second
+	// maxResponseBytes bounds the response body read from the API.
+	maxResponseBytes = 1 << 20
+	// maxErrorMessageBytes bounds the API message kept in an APIError.
+	maxErrorMessageBytes = 512
+)
+
+// Target says where to open a pull request.
+type Target struct {
+	// Kind is KindGitHub or KindGitLab.
+	Kind string
+	// APIURL is the REST API root, see APIBaseURL.
+	APIURL string
+	Token  string
+	Remote Remote
+}
+
+// PullRequestOptions describes the pull request to open.
+type PullRequestOptions struct {
+	// Head is the branch with the changes.
+	Head string
+	// Base is the branch the changes should go into.
+	Base  string
+	Title string
+	Body  string
+	Draft bool
+}
+
+// PullRequest is an open pull request (GitHub) or merge request (GitLab).
+type PullRequest struct {
+	URL    string `json:"url"`
+	Number int    `json:"number"`
+	// Existing is true when the request was already open for the branch.
+	Existing bool `json:"existing"`
+}
+
+// APIError is a failed API request.
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("forge API returned HTTP %d", e.StatusCode)
+	}
+	return fmt.Sprintf("forge API returned HTTP %d: %s", e.StatusCode, e.Message)
+}
+
+// Deps holds external dependencies injected at construction time.
+type Deps struct {
+	// Do sends one request. Optional: defaults to an http.Client with
+	// RequestTimeout.
+	Do func(*http.Request) (*http.Response, error)
+}
+
+// Client opens pull requests. It holds no state and is safe for concurrent use.
+type Client struct {
+	deps Deps
+}
+
+// NewClient creates a forge client.
+func NewClient(deps Deps) *Client {
+	if deps.Do == nil {
+		client := &http.Client{Timeout: RequestTimeout}
+		deps.Do = client.Do
+	}
+	return &Client{deps: deps}
+}
+
+// CreatePullRequest opens a pull request for opts.Head into opts.Base, or
+// returns the one already open for them.
+func (c *Client) CreatePullRequest(ctx context.Context, target Target, opts PullRequestOptions) (PullRequest, error) {
+	if strings.TrimSpace(target.Token) == "" {
+		return PullRequest{}, errors.New("forge token is not configured")
+	}
+	if opts.Head == "" || opts.Base == "" || strings.TrimSpace(opts.Title) == "" {
+		return PullRequest{}, errors.New("head branch, base branch, and title are required")
+	}
+	switch target.Kind {
+	case KindGitHub:
+		return c.createGitHubPullRequest(ctx, target, opts)
+	case KindGitLab:
+		return c.createGitLabMergeRequest(ctx, target, opts)
+	default:
+		return PullRequest{}, fmt.Errorf("unknown forge kind %q", target.Kind)
+	}
+}
+
+type githubPull struct {
+	HTMLURL string `json:"html_url"`
+	Number  int    `json:"number"`
+}
+
+func (c *Client) createGitHubPullRequest(ctx context.Context, target Target, opts PullRequestOptions) (PullRequest, error) {
+	repoURL := strings.TrimRight(target.APIURL, "/") + "/repos/" + target.Remote.Path + "/pulls"
+	var created githubPull
+	err := c.call(ctx, target, http.MethodPost, repoURL, map[string]any{
+		"title": opts.Title,
+		"head":  opts.Head,
+		"base":  opts.Base,
+		"body":  opts.Body,
+		"draft": opts.Draft,
+	}, &created)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnprocessableEntity &&
+		strings.Contains(apiErr.Message, "already exists") {
+		owner, _, _ := strings.Cut(target.Remote.Path, "/")
+		query := url.Values{"head": {owner + ":" + opts.Head}, "base": {opts.Base}, "state": {"open"}}
+		var open []githubPull
+		if listErr := c.call(ctx, target, http.MethodGet, repoURL+"?"+query.Encode(), nil, &open); listErr != nil {
+			return PullRequest{}, errors.Join(err, listErr)
+		}
+		if len(open) == 0 {
+			return PullRequest{}, err
+		}
+		return PullRequest{URL: open[0].HTMLURL, Number: open[0].Number, Existing: true}, nil
+	}
+	if err != nil {
+		return PullRequest{}, err
+	}
+	return PullRequest{URL: created.HTMLURL, Number: created.Number}, nil
+}
+
+type gitlabMergeRequest struct {
+	WebURL string `json:"web_url"`
+	IID    int    `json:"iid"`
+}
+
+func (c *Client) createGitLabMergeRequest(ctx context.Context, target Target, opts PullRequestOptions) (PullRequest, error) {
+	mrURL := strings.TrimRight(target.APIURL, "/") + "/projects/" + url.PathEscape(target.Remote.Path) + "/merge_requests"
+	title := opts.Title
+	if opts.Draft {
+		title = "Draft: " + title
+	}
+	var created gitlabMergeRequest
+	err := c.call(ctx, target, http.MethodPost, mrURL, map[string]any{
+		"source_branch": opts.Head,
+		"target_branch": opts.Base,
+		"title":         title,
+		"description":   opts.Body,
+	}, &created)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusConflict {
+		query := url.Values{"source_branch": {opts.Head}, "target_branch": {opts.Base}, "state": {"opened"}}
+		var open []gitlabMergeRequest
+		if listErr := c.call(ctx, target, http.MethodGet, mrURL+"?"+query.Encode(), nil, &open); listErr != nil {
+			return PullRequest{}, errors.Join(err, listErr)
+		}
+		if len(open) == 0 {
+			return PullRequest{}, err
+		}
+		return PullRequest{URL: open[0].WebURL, Number: open[0].IID, Existing: true}, nil
+	}
+	if err != nil {
+		return PullRequest{}, err
+	}
+	return PullRequest{URL: created.WebURL, Number: created.IID}, nil
+}
+
+// call sends one API request with a JSON body (unless nil) and decodes the
+// JSON response into out. Non-2xx responses become an *APIError.
+func (c *Client) call(ctx context.Context, target Target, method, endpoint string, body any, out any) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode forge request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return fmt.Errorf("failed to build forge request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	switch target.Kind {
+	case KindGitHub:
+		req.Header.Set("Accept", "application/vnd.github+json")
+		req.Header.Set("Authorization", "Bearer "+target.Token)
+		req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	case KindGitLab:
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("PRIVATE-TOKEN", target.Token)
+	}
+
+	resp, err := c.deps.Do(req)
+	if err != nil {
+		return fmt.Errorf("forge request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return fmt.Errorf("failed to read forge response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &APIError{StatusCode: resp.StatusCode, Message: apiErrorMessage(data)}
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode forge response: %w", err)
+	}
+	return nil
+}
+
+// apiErrorMessage extracts the human-readable part of an error response.
+// GitHub reports {"message", "errors": [{"message"}]}, GitLab {"message"}
+// with a string or list, or {"error"}.
+func apiErrorMessage(data []byte) string {
+	var parsed struct {
+		Message any `json:"message"`
+		Error   any `json:"error"`
+		Errors  []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	var parts []string
+	if json.Unmarshal(data, &parsed) == nil {
+		for _, v := range []any{parsed.Message, parsed.Error} {
+			switch v := v.(type) {
+			case string:
+				parts = append(parts, v)
+			case []any:
+				for _, item := range v {
+					parts = append(parts, fmt.Sprint(item))
+				}
+			}
+		}
+		for _, e := range parsed.Errors {
+			if e.Message != "" {
+				parts = append(parts, e.Message)
+			}
+		}
+	} else {
+		parts = append(parts, strings.TrimSpace(string(data)))
+	}
+	message := strings.Join(parts, "; ")
+	if len(message) > maxErrorMessageBytes {
+		message = message[:maxErrorMessageBytes]
+	}
+	return message
+}
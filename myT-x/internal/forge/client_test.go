@@ -0,0 +1,122 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type recordedRequest struct {
+	method string
+	path   string
+	query  string
+	header http.Header
+	body   map[string]any
+}
+
+// newForgeServer answers requests with handler and records them.
+func newForgeServer(t *testing.T, handler func(r *http.Request) (int, any)) (*httptest.Server, *[]recordedRequest) {
+	t.Helper()
+	var requests []recordedRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := recordedRequest{method: r.Method, path: r.URL.EscapedPath(), query: r.URL.RawQuery, header: r.Header.Clone()}
+		if data, _ := io.ReadAll(r.Body); len(data) > 0 {
+			_ = json.Unmarshal(data, &rec.body)
+		}
+		requests = append(requests, rec)
+		status, body := handler(r)
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+func TestCreatePullRequestGitHub(t *testing.T) {
+	srv, requests := newForgeServer(t, func(*http.Request) (int, any) {
+		return http.StatusCreated, map[string]any{"html_url": "https://github.com/owner/repo/pull/7", "number": 7}
+	})
+	target := Target{Kind: KindGitHub, APIURL: srv.URL, Token: "ghp_test", Remote: Remote{Host: "github.com", Path: "owner/repo"}}
+
+	pr, err := NewClient(Deps{}).CreatePullRequest(context.Background(), target, PullRequestOptions{
+		Head: "feature", Base: "main", Title: "Add feature", Body: "Details", Draft: true,
+	})
+	if err != nil {
+		t.Fatalf("CreatePullRequest() error = %v", err)
+	}
+	if pr != (PullRequest{URL: "https://github.com/owner/repo/pull/7", Number: 7}) {
+		t.Fatalf("CreatePullRequest() = %+v", pr)
+	}
+	req := (*requests)[0]
+	if req.method != http.MethodPost || req.path != "/repos/owner/repo/pulls" ||
+		req.header.Get("Authorization") != "Bearer ghp_test" {
+		t.Fatalf("request = %s %s auth %q", req.method, req.path, req.header.Get("Authorization"))
+	}
+	if req.body["head"] != "feature" || req.body["base"] != "main" || req.body["draft"] != true {
+		t.Fatalf("request body = %v", req.body)
+	}
+}
+
+func TestCreatePullRequestGitHubReturnsExisting(t *testing.T) {
+	srv, requests := newForgeServer(t, func(r *http.Request) (int, any) {
+		if r.Method == http.MethodPost {
+			return http.StatusUnprocessableEntity, map[string]any{
+				"message": "Validation Failed",
+				"errors":  []map[string]any{{"message": "A pull request already exists for owner:feature."}},
+			}
+		}
+		return http.StatusOK, []map[string]any{{"html_url": "https://github.com/owner/repo/pull/3", "number": 3}}
+	})
+	target := Target{Kind: KindGitHub, APIURL: srv.URL, Token: "t", Remote: Remote{Host: "github.com", Path: "owner/repo"}}
+
+	pr, err := NewClient(Deps{}).CreatePullRequest(context.Background(), target, PullRequestOptions{Head: "feature", Base: "main", Title: "x"})
+	if err != nil || !pr.Existing || pr.Number != 3 {
+		t.Fatalf("CreatePullRequest() = %+v, %v; want existing #3", pr, err)
+	}
+	if got := (*requests)[1].query; got != "base=main&head=owner%3Afeature&state=open" {
+		t.Fatalf("lookup query = %q", got)
+	}
+}
+
+func TestCreatePullRequestGitLab(t *testing.T) {
+	srv, requests := newForgeServer(t, func(*http.Request) (int, any) {
+		return http.StatusCreated, map[string]any{"web_url": "https://gitlab.com/group/sub/project/-/merge_requests/5", "iid": 5}
+	})
+	target := Target{Kind: KindGitLab, APIURL: srv.URL, Token: "glpat", Remote: Remote{Host: "gitlab.com", Path: "group/sub/project"}}
+
+	pr, err := NewClient(Deps{}).CreatePullRequest(context.Background(), target, PullRequestOptions{
+		Head: "feature", Base: "main", Title: "Add feature", Draft: true,
+	})
+	if err != nil || pr.Number != 5 || pr.Existing {
+		t.Fatalf("CreatePullRequest() = %+v, %v", pr, err)
+	}
+	req := (*requests)[0]
+	if req.path != "/projects/group%2Fsub%2Fproject/merge_requests" || req.header.Get("PRIVATE-TOKEN") != "glpat" {
+		t.Fatalf("request = %s token %q", req.path, req.header.Get("PRIVATE-TOKEN"))
+	}
+	if req.body["title"] != "Draft: Add feature" || req.body["source_branch"] != "feature" || req.body["target_branch"] != "main" {
+		t.Fatalf("request body = %v", req.body)
+	}
+}
+
+func TestCreatePullRequestReportsAPIError(t *testing.T) {
+	srv, _ := newForgeServer(t, func(*http.Request) (int, any) {
+		return http.StatusUnauthorized, map[string]any{"message": "401 Unauthorized"}
+	})
+	target := Target{Kind: KindGitLab, APIURL: srv.URL, Token: "bad", Remote: Remote{Host: "gitlab.com", Path: "g/p"}}
+
+	_, err := NewClient(Deps{}).CreatePullRequest(context.Background(), target, PullRequestOptions{Head: "f", Base: "main", Title: "x"})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized || apiErr.Message != "401 Unauthorized" {
+		t.Fatalf("CreatePullRequest() error = %v, want APIError 401", err)
+	}
+
+	target.Token = ""
+	if _, err := NewClient(Deps{}).CreatePullRequest(context.Background(), target, PullRequestOptions{Head: "f", Base: "main", Title: "x"}); err == nil {
+		t.Fatal("CreatePullRequest() without a token should fail")
+	}
+}
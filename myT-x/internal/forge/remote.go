@@ -0,0 +1,89 @@
+package forge
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Forge kinds.
+const (
+	// KindGitHub opens pull requests on GitHub or GitHub Enterprise Server.
+	KindGitHub = "github"
+	// KindGitLab opens merge requests on GitLab.
+	KindGitLab = "gitlab"
+)
+
+// Remote identifies a repository on a forge.
+type Remote struct {
+	// Host is the host name without port, e.g. "github.com".
+	Host string
+	// Path is the repository path without ".git", e.g. "owner/repo" or
+	// "group/subgroup/project".
+	Path string
+}
+
+// ParseRemoteURL parses the URL of a git remote in any of the forms forges
+// hand out: https://host/owner/repo.git, ssh://git@host:22/owner/repo.git,
+// and the scp-like git@host:owner/repo.git.
+func ParseRemoteURL(raw string) (Remote, error) {
+	raw = strings.TrimSpace(raw)
+	var host, path string
+	if strings.Contains(raw, "://") {
+		u, err := url.Parse(raw)
+		if err != nil {
+			return Remote{}, fmt.Errorf("invalid remote URL %q: %w", raw, err)
+		}
+		switch u.Scheme {
+		case "https", "http", "ssh", "git+ssh":
+		default:
+			return Remote{}, fmt.Errorf("remote URL %q is not on a forge (scheme %s)", raw, u.Scheme)
+		}
+		host, path = u.Hostname(), u.Path
+	} else {
+		// scp-like syntax: [user@]host:path
+		hostPart, pathPart, ok := strings.Cut(raw, ":")
+		if !ok || strings.ContainsAny(hostPart, `/\`) {
+			return Remote{}, fmt.Errorf("remote URL %q is not on a forge", raw)
+		}
+		if _, h, found := strings.Cut(hostPart, "@"); found {
+			hostPart = h
+		}
+		host, path = hostPart, pathPart
+	}
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	if host == "" || !strings.Contains(path, "/") {
+		return Remote{}, fmt.Errorf("remote URL %q does not name an owner and repository", raw)
+	}
+	return Remote{Host: strings.ToLower(host), Path: path}, nil
+}
+
+// DetectKind guesses the forge kind from the host name. It returns "" for
+// hosts that name neither forge, e.g. a self-hosted server under a custom
+// domain, which need an explicit kind.
+func DetectKind(host string) string {
+	host = strings.ToLower(host)
+	switch {
+	case host == "github.com" || strings.Contains(host, "github"):
+		return KindGitHub
+	case host == "gitlab.com" || strings.Contains(host, "gitlab"):
+		return KindGitLab
+	default:
+		return ""
+	}
+}
+
+// APIBaseURL returns the REST API root of kind on host.
+func APIBaseURL(kind, host string) (string, error) {
+	switch kind {
+	case KindGitHub:
+		if host == "github.com" {
+			return "https://api.github.com", nil
+		}
+		return "https://" + host + "/api/v3", nil
+	case KindGitLab:
+		return "https://" + host + "/api/v4", nil
+	default:
+		return "", fmt.Errorf("unknown forge kind %q", kind)
+	}
+}
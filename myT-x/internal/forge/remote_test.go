@@ -0,0 +1,52 @@
+package forge
+
+import "testing"
+
+func TestParseRemoteURL(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want Remote
+	}{
+		{"https://github.com/owner/repo.git", Remote{Host: "github.com", Path: "owner/repo"}},
+		{"https://user@GitHub.com/owner/repo/", Remote{Host: "github.com", Path: "owner/repo"}},
+		{"git@github.com:owner/repo.git", Remote{Host: "github.com", Path: "owner/repo"}},
+		{"ssh://git@gitlab.example.com:2222/group/sub/project.git", Remote{Host: "gitlab.example.com", Path: "group/sub/project"}},
+		{"gitlab.com:group/project", Remote{Host: "gitlab.com", Path: "group/project"}},
+	}
+	for _, tt := range tests {
+		got, err := ParseRemoteURL(tt.raw)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseRemoteURL(%q) = %+v, %v; want %+v", tt.raw, got, err, tt.want)
+		}
+	}
+	for _, raw := range []string{"", `C:\repos\bare.git`, "/srv/git/repo.git", "file:///srv/git/repo.git", "https://github.com/repo"} {
+		if got, err := ParseRemoteURL(raw); err == nil {
+			t.Errorf("ParseRemoteURL(%q) = %+v, want error", raw, got)
+		}
+	}
+}
+
+func TestDetectKindAndAPIBaseURL(t *testing.T) {
+	for host, want := range map[string]string{
+		"github.com":         KindGitHub,
+		"github.example.com": KindGitHub,
+		"gitlab.com":         KindGitLab,
+		"git.example.com":    "",
+	} {
+		if got := DetectKind(host); got != want {
+			t.Errorf("DetectKind(%q) = %q, want %q", host, got, want)
+		}
+	}
+	for _, tt := range []struct{ kind, host, want string }{
+		{KindGitHub, "github.com", "https://api.github.com"},
+		{KindGitHub, "github.example.com", "https://github.example.com/api/v3"},
+		{KindGitLab, "gitlab.com", "https://gitlab.com/api/v4"},
+	} {
+		if got, err := APIBaseURL(tt.kind, tt.host); err != nil || got != tt.want {
+			t.Errorf("APIBaseURL(%q, %q) = %q, %v; want %q", tt.kind, tt.host, got, err, tt.want)
+		}
+	}
+	if _, err := APIBaseURL("bitbucket", "bitbucket.org"); err == nil {
+		t.Error("APIBaseURL() should reject an unknown kind")
+	}
+}
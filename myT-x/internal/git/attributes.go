@@ -0,0 +1,60 @@
+package git
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Line endings returned by EOLAttribute.
+const (
+	EOLLF   = "lf"
+	EOLCRLF = "crlf"
+)
+
+// EOLAttribute returns the line ending .gitattributes assigns to path, which
+// is relative to repoPath: EOLLF, EOLCRLF, or "" when no eol is set or the
+// file is marked binary (-text). The file does not need to be tracked.
+func EOLAttribute(repoPath, path string) (string, error) {
+	output, err := runGitCLI(repoPath, []string{"check-attr", "eol", "text", "--", filepath.ToSlash(path)})
+	if err != nil {
+		return "", fmt.Errorf("check eol attribute of %s: %w", path, err)
+	}
+	return parseEOLAttribute(string(output)), nil
+}
+
+// parseEOLAttribute reads "git check-attr eol text" output, one
+// "<path>: <attribute>: <value>" line per attribute.
+func parseEOLAttribute(output string) string {
+	values := make(map[string]string, 2)
+	for line := range strings.Lines(output) {
+		line = strings.TrimRight(line, "\r\n")
+		// Split from the right: the path itself may contain ": ".
+		rest, value, found := cutLast(line, ": ")
+		if !found {
+			continue
+		}
+		_, attribute, found := cutLast(rest, ": ")
+		if !found {
+			continue
+		}
+		values[attribute] = value
+	}
+	if values["text"] == "unset" {
+		return ""
+	}
+	switch eol := values["eol"]; eol {
+	case EOLLF, EOLCRLF:
+		return eol
+	default:
+		return ""
+	}
+}
+
+func cutLast(s, sep string) (before, after string, found bool) {
+	idx := strings.LastIndex(s, sep)
+	if idx < 0 {
+		return s, "", false
+	}
+	return s[:idx], s[idx+len(sep):], true
+}
@@ -0,0 +1,54 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"myT-x/internal/testutil"
+)
+
+func TestParseEOLAttribute(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   string
+	}{
+		{name: "lf", output: ".env: eol: lf\n.env: text: set\n", want: EOLLF},
+		{name: "crlf with auto text", output: "run.bat: eol: crlf\nrun.bat: text: auto\n", want: EOLCRLF},
+		{name: "unspecified", output: "a.txt: eol: unspecified\na.txt: text: unspecified\n", want: ""},
+		{name: "binary wins over eol", output: "x.bin: eol: lf\nx.bin: text: unset\n", want: ""},
+		{name: "path containing separator", output: "dir: a/b.sh: eol: lf\r\ndir: a/b.sh: text: set\r\n", want: EOLLF},
+		{name: "empty", output: "", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseEOLAttribute(tt.output); got != tt.want {
+				t.Fatalf("parseEOLAttribute() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEOLAttribute(t *testing.T) {
+	dir := testutil.CreateTempGitRepo(t)
+	attributes := "*.sh text eol=lf\n*.bat text eol=crlf\n*.png -text\n"
+	if err := os.WriteFile(filepath.Join(dir, ".gitattributes"), []byte(attributes), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for path, want := range map[string]string{
+		filepath.Join("scripts", "setup.sh"): EOLLF,
+		"run.bat":                            EOLCRLF,
+		"logo.png":                           "",
+		".env":                               "",
+	} {
+		got, err := EOLAttribute(dir, path)
+		if err != nil {
+			t.Fatalf("EOLAttribute(%q) error = %v", path, err)
+		}
+		if got != want {
+			t.Errorf("EOLAttribute(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
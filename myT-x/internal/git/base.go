@@ -0,0 +1,133 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Strategies for IntegrateBase.
+const (
+	// IntegrateRebase replays the branch onto the base.
+	IntegrateRebase = "rebase"
+	// IntegrateMerge merges the base into the branch.
+	IntegrateMerge = "merge"
+)
+
+// ResolveBaseRef returns the ref that tracks where base is heading and the
+// remote to fetch before comparing against it:
+//   - a local branch with an upstream resolves to the upstream ("origin/main")
+//     and its remote;
+//   - a remote-tracking branch ("origin/main") resolves to itself and the
+//     remote in its name;
+//   - anything else (a local branch without upstream, a tag, a commit)
+//     resolves to itself with no remote.
+func (r *Repository) ResolveBaseRef(base string) (ref, remote string, err error) {
+	base = strings.TrimSpace(base)
+	if err := ValidateCommitish(base); err != nil {
+		return "", "", err
+	}
+	if upstream, err := r.runGitCommand("rev-parse", "--abbrev-ref", "--symbolic-full-name", base+"@{upstream}"); err == nil && upstream != "" {
+		remote, err := ResolveRemoteName(r.path, base)
+		if err != nil {
+			return "", "", err
+		}
+		return upstream, remote, nil
+	}
+	if _, err := r.runGitCommand("rev-parse", "--verify", "--quiet", "refs/remotes/"+base); err == nil {
+		remoteNames, err := r.listRemoteNames()
+		if err != nil {
+			return "", "", err
+		}
+		for _, name := range remoteNames {
+			if strings.HasPrefix(base, name+"/") {
+				return base, name, nil
+			}
+		}
+		return base, "", nil
+	}
+	return base, "", nil
+}
+
+// Fetch updates the remote-tracking branches of remote.
+func (r *Repository) Fetch(remote string) error {
+	remote = strings.TrimSpace(remote)
+	if remote == "" || strings.HasPrefix(remote, "-") {
+		return fmt.Errorf("invalid remote name: %q", remote)
+	}
+	if _, err := r.runGitCommand("fetch", "--quiet", remote); err != nil {
+		return fmt.Errorf("failed to fetch %s: %w", remote, err)
+	}
+	return nil
+}
+
+// ResolveCommit returns the commit hash ref points to.
+func (r *Repository) ResolveCommit(ref string) (string, error) {
+	commit, err := r.runGitCommand("rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", ref, err)
+	}
+	return commit, nil
+}
+
+// CountCommitsBehind returns the number of commits of ref that HEAD does
+// not contain.
+func (r *Repository) CountCommitsBehind(ref string) (int, error) {
+	output, err := r.runGitCommand("rev-list", "--count", "HEAD.."+ref)
+	if err != nil {
+		return 0, fmt.Errorf("failed to compare HEAD with %s: %w", ref, err)
+	}
+	count, err := strconv.Atoi(output)
+	if err != nil {
+		return 0, fmt.Errorf("unexpected rev-list output %q: %w", output, err)
+	}
+	return count, nil
+}
+
+// IntegrateBase brings the commits of ref into the current branch with
+// strategy (IntegrateRebase or IntegrateMerge). The working tree must be
+// clean. On conflicts the rebase or merge is aborted, leaving the branch as
+// it was, and the conflicted paths are returned with a nil error.
+func (r *Repository) IntegrateBase(ref, strategy string) (conflicts []string, err error) {
+	if err := ValidateCommitish(ref); err != nil {
+		return nil, err
+	}
+	var args, abortArgs []string
+	switch strategy {
+	case IntegrateRebase:
+		args = []string{"rebase", "--quiet", ref}
+		abortArgs = []string{"rebase", "--abort"}
+	case IntegrateMerge:
+		args = []string{"merge", "--quiet", "--no-edit", ref}
+		abortArgs = []string{"merge", "--abort"}
+	default:
+		return nil, fmt.Errorf("unknown integration strategy: %q", strategy)
+	}
+	status, err := r.WorkingTreeStatus()
+	if err != nil {
+		return nil, err
+	}
+	if status.Dirty > 0 {
+		return nil, ErrWorktreeHasUncommittedChanges
+	}
+
+	_, integrateErr := r.runGitCommand(args...)
+	if integrateErr == nil {
+		return nil, nil
+	}
+	conflicts, listErr := r.listConflictedPaths()
+	if listErr != nil {
+		conflicts = nil
+	}
+	if _, abortErr := r.runGitCommand(abortArgs...); abortErr != nil {
+		return conflicts, errors.Join(
+			fmt.Errorf("failed to %s onto %s: %w", strategy, ref, integrateErr),
+			fmt.Errorf("abort also failed: %w", abortErr),
+		)
+	}
+	if len(conflicts) > 0 {
+		return conflicts, nil
+	}
+	return nil, fmt.Errorf("failed to %s onto %s: %w", strategy, ref, integrateErr)
+}
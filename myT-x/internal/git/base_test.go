@@ -0,0 +1,138 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"myT-x/internal/testutil"
+)
+
+// pushToBase commits file on the base branch of bareDir from a separate
+// clone, as another developer would.
+func pushToBase(t *testing.T, bareDir, file, content string) {
+	t.Helper()
+	otherDir := testutil.ResolvePath(t.TempDir())
+	runGitCommandInDir(t, otherDir, "clone", "--quiet", bareDir, ".")
+	runGitCommandInDir(t, otherDir, "config", "user.email", "other@test.com")
+	runGitCommandInDir(t, otherDir, "config", "user.name", "Other")
+	if err := os.WriteFile(filepath.Join(otherDir, file), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runGitCommandInDir(t, otherDir, "add", ".")
+	runGitCommandInDir(t, otherDir, "commit", "--quiet", "-m", "advance base")
+	runGitCommandInDir(t, otherDir, "push", "--quiet", "origin", "HEAD")
+}
+
+func TestIntegrateBaseRebasesOntoAdvancedUpstream(t *testing.T) {
+	bareDir, cloneDir := createBareAndClone(t)
+	repo, err := Open(cloneDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	base, err := repo.CurrentBranch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	runGitCommandInDir(t, cloneDir, "checkout", "--quiet", "-b", "feature")
+	if err := os.WriteFile(filepath.Join(cloneDir, "feature.txt"), []byte("feature"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.CommitAll("feature work"); err != nil {
+		t.Fatal(err)
+	}
+	pushToBase(t, bareDir, "other.txt", "other")
+
+	ref, remote, err := repo.ResolveBaseRef(base)
+	if err != nil || ref != "origin/"+base || remote != "origin" {
+		t.Fatalf("ResolveBaseRef(%q) = %q, %q, %v", base, ref, remote, err)
+	}
+	if behind, err := repo.CountCommitsBehind(ref); err != nil || behind != 0 {
+		t.Fatalf("CountCommitsBehind() before fetch = %d, %v; want 0", behind, err)
+	}
+	if err := repo.Fetch(remote); err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if behind, err := repo.CountCommitsBehind(ref); err != nil || behind != 1 {
+		t.Fatalf("CountCommitsBehind() after fetch = %d, %v; want 1", behind, err)
+	}
+
+	conflicts, err := repo.IntegrateBase(ref, IntegrateRebase)
+	if err != nil || len(conflicts) != 0 {
+		t.Fatalf("IntegrateBase() = %v, %v; want a clean rebase", conflicts, err)
+	}
+	if behind, _ := repo.CountCommitsBehind(ref); behind != 0 {
+		t.Fatalf("CountCommitsBehind() after rebase = %d, want 0", behind)
+	}
+	if _, err := os.Stat(filepath.Join(cloneDir, "other.txt")); err != nil {
+		t.Fatalf("other.txt missing after rebase: %v", err)
+	}
+}
+
+func TestIntegrateBaseConflictAborts(t *testing.T) {
+	bareDir, cloneDir := createBareAndClone(t)
+	repo, err := Open(cloneDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	base, err := repo.CurrentBranch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	runGitCommandInDir(t, cloneDir, "checkout", "--quiet", "-b", "feature")
+	if err := os.WriteFile(filepath.Join(cloneDir, "develop-README.md"), []byte("feature"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.CommitAll("feature edit"); err != nil {
+		t.Fatal(err)
+	}
+	head, err := repo.ResolveCommit("HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pushToBase(t, bareDir, "develop-README.md", "upstream")
+	ref, remote, err := repo.ResolveBaseRef(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Fetch(remote); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, strategy := range []string{IntegrateMerge, IntegrateRebase} {
+		conflicts, err := repo.IntegrateBase(ref, strategy)
+		if err != nil || !slices.Equal(conflicts, []string{"develop-README.md"}) {
+			t.Fatalf("IntegrateBase(%s) = %v, %v; want the README conflict", strategy, conflicts, err)
+		}
+		if after, _ := repo.ResolveCommit("HEAD"); after != head {
+			t.Fatalf("HEAD moved after aborted %s: %s, want %s", strategy, after, head)
+		}
+		if status, _ := repo.WorkingTreeStatus(); status.Dirty != 0 {
+			t.Fatalf("working tree dirty after aborted %s: %+v", strategy, status)
+		}
+	}
+
+	if _, err := repo.IntegrateBase(ref, "squash"); err == nil {
+		t.Fatal("IntegrateBase() with an unknown strategy should fail")
+	}
+}
+
+func TestResolveBaseRefRemoteTrackingAndLocal(t *testing.T) {
+	_, cloneDir := createBareAndClone(t)
+	repo, err := Open(cloneDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	base, err := repo.CurrentBranch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ref, remote, err := repo.ResolveBaseRef("origin/" + base); err != nil || ref != "origin/"+base || remote != "origin" {
+		t.Fatalf("ResolveBaseRef(origin/%s) = %q, %q, %v", base, ref, remote, err)
+	}
+	runGitCommandInDir(t, cloneDir, "branch", "local-only")
+	if ref, remote, err := repo.ResolveBaseRef("local-only"); err != nil || ref != "local-only" || remote != "" {
+		t.Fatalf("ResolveBaseRef(local-only) = %q, %q, %v", ref, remote, err)
+	}
+}
@@ -5,12 +5,15 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"maps"
 	"os"
 	"os/exec"
 	"runtime"
+	"slices"
 	"strings"
 	"time"
 
+	"myT-x/internal/faultinject"
 	"myT-x/internal/procutil"
 )
 
@@ -125,6 +128,32 @@ func defaultGitCommandRunner(ctx context.Context, dir string, args []string, env
 	return stdout.Bytes(), stderr.String(), err
 }
 
+// faultInjectingRunner wraps runner with the dev-mode fault injector.
+// Drops count as failures because a git call cannot go unanswered; partial
+// failures run the command and then report an error with truncated output.
+func faultInjectingRunner(inj *faultinject.Injector, runner gitCommandRunner) gitCommandRunner {
+	if inj == nil {
+		return runner
+	}
+	return func(ctx context.Context, dir string, args []string, env []string) ([]byte, string, error) {
+		fault := inj.Next(faultinject.ScopeGit)
+		if err := fault.Wait(ctx); err != nil {
+			return nil, "", err
+		}
+		switch fault.Kind {
+		case faultinject.Drop, faultinject.Fail:
+			return nil, faultinject.ErrInjected.Error(), faultinject.ErrInjected
+		case faultinject.Partial:
+			stdout, stderrText, err := runner(ctx, dir, args, env)
+			if err != nil {
+				return stdout, stderrText, err
+			}
+			return stdout[:len(stdout)/2], faultinject.ErrInjected.Error(), faultinject.ErrInjected
+		}
+		return runner(ctx, dir, args, env)
+	}
+}
+
 func waitForGitRetryBackoff(ctx context.Context, backoff time.Duration) error {
 	timer := time.NewTimer(backoff)
 	defer timer.Stop()
@@ -210,6 +239,12 @@ func runGitCLI(dir string, args []string) ([]byte, error) {
 
 // runGitCLIWithContext runs git commands with cancellation support via context.
 func runGitCLIWithContext(ctx context.Context, dir string, args []string) ([]byte, error) {
+	return runGitCLIWithEnv(ctx, dir, args, nil)
+}
+
+// runGitCLIWithEnv runs git commands with extraEnv set over the process
+// environment.
+func runGitCLIWithEnv(ctx context.Context, dir string, args []string, extraEnv map[string]string) ([]byte, error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
@@ -228,12 +263,15 @@ func runGitCLIWithContext(ctx context.Context, dir string, args []string) ([]byt
 	}()
 
 	env := localeNeutralGitEnv(os.Environ())
+	for _, key := range slices.Sorted(maps.Keys(extraEnv)) {
+		env = upsertEnvVar(env, key, extraEnv[key])
+	}
 	return runGitCLIWithContextAndDeps(
 		ctx,
 		dir,
 		args,
 		env,
-		defaultGitCommandRunner,
+		faultInjectingRunner(faultinject.FromEnv(), defaultGitCommandRunner),
 		waitForGitRetryBackoff,
 	)
 }
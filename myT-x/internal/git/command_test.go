@@ -8,6 +8,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"myT-x/internal/faultinject"
 )
 
 func TestIsLockFileConflict(t *testing.T) {
@@ -318,3 +320,42 @@ func TestRunGitCLIWithContextAndDepsStopsWhenBackoffWaitFails(t *testing.T) {
 		t.Fatalf("wait calls = %d, want 1", waitCalls)
 	}
 }
+
+func TestFaultInjectingRunner(t *testing.T) {
+	calls := 0
+	runner := func(_ context.Context, _ string, _ []string, _ []string) ([]byte, string, error) {
+		calls++
+		return []byte("abcd"), "", nil
+	}
+	if got := faultInjectingRunner(nil, runner); got == nil {
+		t.Fatal("faultInjectingRunner(nil) = nil, want runner")
+	}
+
+	tests := []struct {
+		spec      string
+		wantOut   string
+		wantErr   bool
+		wantCalls int
+	}{
+		{"fail=1", "", true, 0},
+		{"drop=1", "", true, 0},
+		{"partial=1", "ab", true, 1},
+		{"scope=ipc,fail=1", "abcd", false, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			inj, err := faultinject.Parse(tt.spec, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			calls = 0
+			out, _, err := faultInjectingRunner(inj, runner)(context.Background(), ".", []string{"status"}, nil)
+			if string(out) != tt.wantOut || (err != nil) != tt.wantErr || calls != tt.wantCalls {
+				t.Fatalf("out=%q err=%v calls=%d; want %q, error %v, %d calls", out, err, calls, tt.wantOut, tt.wantErr, tt.wantCalls)
+			}
+			if tt.wantErr && !errors.Is(err, faultinject.ErrInjected) {
+				t.Fatalf("error = %v, want ErrInjected", err)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package git
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log/slog"
@@ -14,6 +15,9 @@ import (
 // ErrWorktreeHasUncommittedChanges reports that removal would discard local edits.
 var ErrWorktreeHasUncommittedChanges = errors.New("worktree has uncommitted changes")
 
+// ErrWorktreeLocked reports that a worktree is locked with `git worktree lock`.
+var ErrWorktreeLocked = errors.New("worktree is locked")
+
 // Open opens an existing git repository using CLI-only detection.
 func Open(path string) (*Repository, error) {
 	path = strings.TrimSpace(path)
@@ -379,6 +383,18 @@ func (r *Repository) CheckoutDetachedHead() error {
 	return nil
 }
 
+// CheckoutDetachedAt switches the repository to a detached HEAD at commit,
+// e.g. to move HEAD back after a finished rebase is rolled back.
+func (r *Repository) CheckoutDetachedAt(commit string) error {
+	if err := ValidateCommitish(commit); err != nil {
+		return err
+	}
+	if _, err := r.runGitCommand("checkout", "--quiet", "--detach", commit); err != nil {
+		return fmt.Errorf("failed to checkout detached HEAD at %s: %w", commit, err)
+	}
+	return nil
+}
+
 // DeleteLocalBranch deletes a local branch.
 // When force is true, "-D" is used instead of "-d".
 func (r *Repository) DeleteLocalBranch(branchName string, force bool) error {
@@ -433,13 +449,19 @@ func (r *Repository) Pull() error {
 // NOTE: If "git commit" fails after a successful "git add -A", staged changes
 // remain in the index for user inspection/retry.
 func (r *Repository) CommitAll(message string) error {
+	return r.CommitAllWithEnv(message, nil)
+}
+
+// CommitAllWithEnv is CommitAll with env set for "git commit", e.g. the
+// GIT_AUTHOR_* and GIT_COMMITTER_* variables of a configured identity.
+func (r *Repository) CommitAllWithEnv(message string, env map[string]string) error {
 	if strings.TrimSpace(message) == "" {
 		return fmt.Errorf("commit message must not be empty")
 	}
 	if _, err := r.runGitCommand("add", "-A"); err != nil {
 		return fmt.Errorf("git add failed: %w", err)
 	}
-	if _, err := r.runGitCommand("commit", "-m", message); err != nil {
+	if _, err := runGitCLIWithEnv(context.Background(), r.path, []string{"commit", "-m", message}, env); err != nil {
 		return fmt.Errorf("git commit failed: %w", err)
 	}
 	return nil
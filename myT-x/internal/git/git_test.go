@@ -526,6 +526,37 @@ func TestCommitAll(t *testing.T) {
 	}
 }
 
+func TestCommitAllWithEnv(t *testing.T) {
+	testutil.SkipIfNoGit(t)
+
+	dir := testutil.CreateTempGitRepo(t)
+	repo, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "identity.txt"), []byte("hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	env := map[string]string{
+		"GIT_AUTHOR_NAME":     "Other Author",
+		"GIT_AUTHOR_EMAIL":    "other@example.com",
+		"GIT_COMMITTER_NAME":  "Other Committer",
+		"GIT_COMMITTER_EMAIL": "committer@example.com",
+	}
+	if err := repo.CommitAllWithEnv("commit as other identity", env); err != nil {
+		t.Fatalf("CommitAllWithEnv() error = %v", err)
+	}
+
+	got, err := repo.runGitCommand("log", "-1", "--format=%an <%ae>|%cn <%ce>")
+	if err != nil {
+		t.Fatalf("git log error = %v", err)
+	}
+	if want := "Other Author <other@example.com>|Other Committer <committer@example.com>"; got != want {
+		t.Fatalf("commit identity = %q, want %q", got, want)
+	}
+}
+
 func TestCommitAllEmptyMessage(t *testing.T) {
 	testutil.SkipIfNoGit(t)
 
@@ -0,0 +1,203 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// StaleBranch is a local branch fully merged into HEAD that has no live
+// upstream and is not checked out in any worktree.
+type StaleBranch struct {
+	Name string `json:"name"`
+	// UpstreamGone is true when the branch tracked an upstream that no longer
+	// exists, false when it never had one.
+	UpstreamGone bool `json:"upstreamGone"`
+	// LastCommitUnix is the committer date of the branch tip in Unix seconds.
+	LastCommitUnix int64 `json:"lastCommitUnix"`
+}
+
+// PrunableWorktree is a worktree entry that `git worktree prune` would remove.
+type PrunableWorktree struct {
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
+}
+
+// StashEntry is one entry of `git stash list`.
+type StashEntry struct {
+	// Ref is the reflog selector (stash@{N}); it shifts as stashes are dropped.
+	Ref string `json:"ref"`
+	// Commit identifies the stash independently of its position.
+	Commit      string `json:"commit"`
+	Message     string `json:"message"`
+	CreatedUnix int64  `json:"createdUnix"`
+}
+
+// UntrackedFile is an untracked, non-ignored file of a working tree.
+type UntrackedFile struct {
+	// Path is relative to the working tree root, slash-separated.
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+}
+
+var stashCommitPattern = regexp.MustCompile(`^[0-9a-f]{40}([0-9a-f]{24})?$`)
+
+// ListStaleBranches returns local branches that are merged into HEAD, have no
+// live upstream, and are not checked out in any worktree. These can be deleted
+// with `git branch -d` without losing commits.
+// A repository without commits has no stale branches.
+func (r *Repository) ListStaleBranches() ([]StaleBranch, error) {
+	if _, err := r.runGitCommand("rev-parse", "--verify", "--quiet", "HEAD"); err != nil {
+		return nil, nil
+	}
+	output, err := r.runGitCommandRaw(
+		"for-each-ref",
+		"--merged=HEAD",
+		"--format=%(refname:short)\t%(upstream:short)\t%(upstream:track)\t%(committerdate:unix)",
+		"refs/heads",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list merged branches: %w", err)
+	}
+
+	worktrees, err := r.ListWorktreesWithInfo()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	checkedOut := make(map[string]struct{}, len(worktrees))
+	for _, wt := range worktrees {
+		if wt.Branch != "" {
+			checkedOut[wt.Branch] = struct{}{}
+		}
+	}
+
+	var stale []StaleBranch
+	for line := range strings.SplitSeq(output, "\n") {
+		line = strings.TrimRight(line, "\r")
+		parts := strings.Split(line, "\t")
+		if len(parts) != 4 || parts[0] == "" {
+			continue
+		}
+		info := branchTrackingInfo{Name: parts[0], Upstream: parts[1], UpstreamTrack: parts[2]}
+		if info.hasLiveUpstream() {
+			continue
+		}
+		if _, ok := checkedOut[info.Name]; ok {
+			continue
+		}
+		lastCommit, _ := strconv.ParseInt(parts[3], 10, 64)
+		stale = append(stale, StaleBranch{
+			Name:           info.Name,
+			UpstreamGone:   info.Upstream != "",
+			LastCommitUnix: lastCommit,
+		})
+	}
+	return stale, nil
+}
+
+// ListPrunableWorktrees returns the worktree entries git reports as prunable,
+// typically because their directory was deleted without `git worktree remove`.
+func (r *Repository) ListPrunableWorktrees() ([]PrunableWorktree, error) {
+	output, err := r.runGitCommand("worktree", "list", "--porcelain")
+	if err != nil {
+		return nil, err
+	}
+	return parsePrunableWorktrees(output), nil
+}
+
+func parsePrunableWorktrees(output string) []PrunableWorktree {
+	var prunable []PrunableWorktree
+	current := ""
+	for line := range strings.SplitSeq(output, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if after, ok := strings.CutPrefix(line, "worktree "); ok {
+			// git returns forward slashes on Windows; normalize to OS path separator.
+			current = filepath.FromSlash(after)
+			continue
+		}
+		if current == "" {
+			continue
+		}
+		if line == "prunable" || strings.HasPrefix(line, "prunable ") {
+			prunable = append(prunable, PrunableWorktree{
+				Path:   current,
+				Reason: strings.TrimSpace(strings.TrimPrefix(line, "prunable")),
+			})
+		}
+	}
+	return prunable
+}
+
+// ListStashes returns the stash entries, newest first.
+func (r *Repository) ListStashes() ([]StashEntry, error) {
+	output, err := r.runGitCommandRaw("stash", "list", "--format=%gd%x1f%H%x1f%ct%x1f%gs")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stashes: %w", err)
+	}
+	return parseStashList(output), nil
+}
+
+func parseStashList(output string) []StashEntry {
+	var stashes []StashEntry
+	for line := range strings.SplitSeq(output, "\n") {
+		line = strings.TrimRight(line, "\r")
+		parts := strings.SplitN(line, "\x1f", 4)
+		if len(parts) != 4 || parts[0] == "" {
+			continue
+		}
+		created, _ := strconv.ParseInt(parts[2], 10, 64)
+		stashes = append(stashes, StashEntry{
+			Ref:         parts[0],
+			Commit:      parts[1],
+			Message:     parts[3],
+			CreatedUnix: created,
+		})
+	}
+	return stashes
+}
+
+// DropStash drops the stash entry whose commit is commit. Stashes are looked
+// up by commit because stash@{N} selectors shift after every drop.
+func (r *Repository) DropStash(commit string) error {
+	if !stashCommitPattern.MatchString(commit) {
+		return fmt.Errorf("invalid stash commit: %q", commit)
+	}
+	stashes, err := r.ListStashes()
+	if err != nil {
+		return err
+	}
+	for _, stash := range stashes {
+		if stash.Commit != commit {
+			continue
+		}
+		if _, err := r.runGitCommand("stash", "drop", "--quiet", stash.Ref); err != nil {
+			return fmt.Errorf("failed to drop stash %s: %w", stash.Ref, err)
+		}
+		return nil
+	}
+	return fmt.Errorf("stash not found: %s", commit)
+}
+
+// ListUntrackedFiles returns the untracked files of the working tree that are
+// not excluded by .gitignore. Files removed while listing are skipped.
+func (r *Repository) ListUntrackedFiles() ([]UntrackedFile, error) {
+	output, err := r.runGitCommandRaw("ls-files", "--others", "--exclude-standard", "-z")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list untracked files: %w", err)
+	}
+	var files []UntrackedFile
+	for path := range strings.SplitSeq(output, "\x00") {
+		if path == "" {
+			continue
+		}
+		info, err := os.Lstat(filepath.Join(r.path, filepath.FromSlash(path)))
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		files = append(files, UntrackedFile{Path: path, Size: info.Size()})
+	}
+	return files, nil
+}
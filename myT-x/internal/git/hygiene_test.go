@@ -0,0 +1,159 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"myT-x/internal/testutil"
+)
+
+func TestListStaleBranches(t *testing.T) {
+	testutil.SkipIfNoGit(t)
+
+	repoDir := testutil.CreateTempGitRepo(t)
+	repo, err := Open(repoDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// merged: merged into HEAD, never had an upstream.
+	runGitCommandInDir(t, repoDir, "branch", "merged")
+	// unmerged: has a commit HEAD does not contain.
+	runGitCommandInDir(t, repoDir, "checkout", "-q", "-b", "unmerged")
+	runGitCommandInDir(t, repoDir, "commit", "--allow-empty", "-q", "-m", "unmerged")
+	runGitCommandInDir(t, repoDir, "checkout", "-q", "-")
+	// in-worktree: merged but checked out in a linked worktree.
+	wtPath := filepath.Join(t.TempDir(), "wt")
+	runGitCommandInDir(t, repoDir, "worktree", "add", "-q", "-b", "in-worktree", wtPath)
+
+	stale, err := repo.ListStaleBranches()
+	if err != nil {
+		t.Fatalf("ListStaleBranches() error = %v", err)
+	}
+	if len(stale) != 1 || stale[0].Name != "merged" || stale[0].UpstreamGone || stale[0].LastCommitUnix == 0 {
+		t.Fatalf("ListStaleBranches() = %+v, want only merged", stale)
+	}
+}
+
+func TestParsePrunableWorktrees(t *testing.T) {
+	output := strings.Join([]string{
+		"worktree /repo",
+		"HEAD 0123",
+		"branch refs/heads/main",
+		"",
+		"worktree /repo/.wt/gone",
+		"HEAD 4567",
+		"branch refs/heads/gone",
+		"prunable gitdir file points to non-existent location",
+		"",
+		"worktree /repo/.wt/ok",
+		"HEAD 89ab",
+		"detached",
+	}, "\n")
+	want := []PrunableWorktree{{
+		Path:   filepath.FromSlash("/repo/.wt/gone"),
+		Reason: "gitdir file points to non-existent location",
+	}}
+	if got := parsePrunableWorktrees(output); !reflect.DeepEqual(got, want) {
+		t.Fatalf("parsePrunableWorktrees() = %+v, want %+v", got, want)
+	}
+}
+
+func TestListPrunableWorktrees(t *testing.T) {
+	testutil.SkipIfNoGit(t)
+
+	repoDir := testutil.CreateTempGitRepo(t)
+	repo, err := Open(repoDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wtPath := filepath.Join(t.TempDir(), "wt")
+	runGitCommandInDir(t, repoDir, "worktree", "add", "-q", "--detach", wtPath)
+	if err := os.RemoveAll(wtPath); err != nil {
+		t.Fatal(err)
+	}
+
+	prunable, err := repo.ListPrunableWorktrees()
+	if err != nil {
+		t.Fatalf("ListPrunableWorktrees() error = %v", err)
+	}
+	if len(prunable) != 1 || !strings.HasSuffix(prunable[0].Path, "wt") || prunable[0].Reason == "" {
+		t.Fatalf("ListPrunableWorktrees() = %+v, want the removed worktree", prunable)
+	}
+}
+
+func TestStashes(t *testing.T) {
+	testutil.SkipIfNoGit(t)
+
+	repoDir := testutil.CreateTempGitRepo(t)
+	repo, err := Open(repoDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	readme := filepath.Join(repoDir, "develop-README.md")
+	for _, content := range []string{"first", "second"} {
+		if err := os.WriteFile(readme, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		runGitCommandInDir(t, repoDir, "stash", "push", "-q", "-m", content)
+	}
+
+	stashes, err := repo.ListStashes()
+	if err != nil {
+		t.Fatalf("ListStashes() error = %v", err)
+	}
+	if len(stashes) != 2 || stashes[0].Ref != "stash@{0}" || !strings.HasSuffix(stashes[0].Message, "second") || stashes[0].CreatedUnix == 0 {
+		t.Fatalf("ListStashes() = %+v", stashes)
+	}
+
+	if err := repo.DropStash(stashes[1].Commit); err != nil {
+		t.Fatalf("DropStash() error = %v", err)
+	}
+	remaining, err := repo.ListStashes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(remaining) != 1 || remaining[0].Commit != stashes[0].Commit {
+		t.Fatalf("stashes after drop = %+v, want only %s", remaining, stashes[0].Commit)
+	}
+
+	if err := repo.DropStash(stashes[1].Commit); err == nil {
+		t.Fatal("DropStash() of a dropped stash should fail")
+	}
+	if err := repo.DropStash("stash@{0}"); err == nil {
+		t.Fatal("DropStash() should reject a non-commit argument")
+	}
+}
+
+func TestListUntrackedFiles(t *testing.T) {
+	testutil.SkipIfNoGit(t)
+
+	repoDir := testutil.CreateTempGitRepo(t)
+	repo, err := Open(repoDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repoDir, ".gitignore"), []byte("*.log\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(repoDir, "out"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repoDir, "out", "bundle.bin"), []byte("12345"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repoDir, "debug.log"), []byte("ignored"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := repo.ListUntrackedFiles()
+	if err != nil {
+		t.Fatalf("ListUntrackedFiles() error = %v", err)
+	}
+	want := []UntrackedFile{{Path: ".gitignore", Size: 6}, {Path: "out/bundle.bin", Size: 5}}
+	if !reflect.DeepEqual(files, want) {
+		t.Fatalf("ListUntrackedFiles() = %+v, want %+v", files, want)
+	}
+}
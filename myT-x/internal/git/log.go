@@ -0,0 +1,63 @@
+package git
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CommitSummary is one commit of CommitsBetween.
+type CommitSummary struct {
+	Commit        string `json:"commit"`
+	Subject       string `json:"subject"`
+	CommittedUnix int64  `json:"committedUnix"`
+}
+
+// CommitsBetween returns the commits reachable from HEAD whose committer date
+// is in [since, until), newest first. A repository without commits has none.
+func (r *Repository) CommitsBetween(since, until time.Time) ([]CommitSummary, error) {
+	if _, err := r.runGitCommand("rev-parse", "--quiet", "--verify", "HEAD"); err != nil {
+		return nil, nil
+	}
+	output, err := r.runGitCommandRaw("log",
+		"--format=%H%x1f%ct%x1f%s",
+		"--since="+since.Format(time.RFC3339),
+		"--until="+until.Add(-time.Second).Format(time.RFC3339),
+		"HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commits: %w", err)
+	}
+	return parseCommitLog(output), nil
+}
+
+func parseCommitLog(output string) []CommitSummary {
+	var commits []CommitSummary
+	for line := range strings.SplitSeq(output, "\n") {
+		line = strings.TrimRight(line, "\r")
+		parts := strings.SplitN(line, "\x1f", 3)
+		if len(parts) != 3 || parts[0] == "" {
+			continue
+		}
+		committed, _ := strconv.ParseInt(parts[1], 10, 64)
+		commits = append(commits, CommitSummary{
+			Commit:        parts[0],
+			Subject:       parts[2],
+			CommittedUnix: committed,
+		})
+	}
+	return commits
+}
+
+// CommitMessage returns the subject and body of the commit ref points to.
+func (r *Repository) CommitMessage(ref string) (subject, body string, err error) {
+	if err := ValidateCommitish(ref); err != nil {
+		return "", "", err
+	}
+	output, err := r.runGitCommandRaw("log", "-1", "--format=%s%x1f%b", ref, "--")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read commit message of %s: %w", ref, err)
+	}
+	subject, body, _ = strings.Cut(output, "\x1f")
+	return strings.TrimSpace(subject), strings.TrimSpace(body), nil
+}
@@ -0,0 +1,52 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"myT-x/internal/testutil"
+)
+
+func TestCommitsBetween(t *testing.T) {
+	repoDir := testutil.CreateTempGitRepo(t)
+	repo, err := Open(repoDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	day := time.Date(2026, 5, 16, 0, 0, 0, 0, time.UTC)
+	for i, at := range []time.Time{day.Add(-time.Minute), day.Add(9 * time.Hour), day.Add(24 * time.Hour)} {
+		if err := os.WriteFile(filepath.Join(repoDir, "develop-README.md"), []byte{byte('a' + i)}, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv("GIT_COMMITTER_DATE", at.Format(time.RFC3339))
+		runGitCommandInDir(t, repoDir, "commit", "-q", "-am", at.Format(time.Kitchen))
+	}
+
+	commits, err := repo.CommitsBetween(day, day.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("CommitsBetween() error = %v", err)
+	}
+	if len(commits) != 1 || commits[0].Subject != "9:00AM" || commits[0].CommittedUnix != day.Add(9*time.Hour).Unix() {
+		t.Fatalf("CommitsBetween() = %+v, want only the 9:00AM commit", commits)
+	}
+}
+
+func TestCommitMessage(t *testing.T) {
+	repoDir := testutil.CreateTempGitRepo(t)
+	repo, err := Open(repoDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repoDir, "a.txt"), []byte("a"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.CommitAll("Add a\n\nExplain why a exists."); err != nil {
+		t.Fatal(err)
+	}
+	subject, body, err := repo.CommitMessage("HEAD")
+	if err != nil || subject != "Add a" || body != "Explain why a exists." {
+		t.Fatalf("CommitMessage(HEAD) = %q, %q, %v", subject, body, err)
+	}
+}
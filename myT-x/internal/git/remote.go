@@ -0,0 +1,37 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RemoteURL returns the fetch URL of remote.
+func (r *Repository) RemoteURL(remote string) (string, error) {
+	remote = strings.TrimSpace(remote)
+	if remote == "" || strings.HasPrefix(remote, "-") {
+		return "", fmt.Errorf("invalid remote name: %q", remote)
+	}
+	url, err := r.runGitCommand("remote", "get-url", remote)
+	if err != nil {
+		return "", fmt.Errorf("failed to read URL of remote %s: %w", remote, err)
+	}
+	return url, nil
+}
+
+// RemoteDefaultBranch returns the branch the HEAD of remote points to, e.g.
+// "main", as recorded by clone or `git remote set-head`.
+func (r *Repository) RemoteDefaultBranch(remote string) (string, error) {
+	remote = strings.TrimSpace(remote)
+	if remote == "" || strings.HasPrefix(remote, "-") {
+		return "", fmt.Errorf("invalid remote name: %q", remote)
+	}
+	ref, err := r.runGitCommand("symbolic-ref", "--quiet", "--short", "refs/remotes/"+remote+"/HEAD")
+	if err != nil {
+		return "", fmt.Errorf("remote %s has no default branch recorded: %w", remote, err)
+	}
+	branch, ok := strings.CutPrefix(ref, remote+"/")
+	if !ok || branch == "" {
+		return "", fmt.Errorf("unexpected default branch ref %q of remote %s", ref, remote)
+	}
+	return branch, nil
+}
@@ -0,0 +1,30 @@
+package git
+
+import "testing"
+
+func TestRemoteURLAndDefaultBranch(t *testing.T) {
+	bareDir, cloneDir := createBareAndClone(t)
+	repo, err := Open(cloneDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if url, err := repo.RemoteURL("origin"); err != nil || url != bareDir {
+		t.Fatalf("RemoteURL(origin) = %q, %v; want %q", url, err, bareDir)
+	}
+	if _, err := repo.RemoteURL("--upload-pack=x"); err == nil {
+		t.Fatal("RemoteURL() should reject an option-like remote name")
+	}
+
+	base, err := repo.CurrentBranch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	runGitCommandInDir(t, cloneDir, "remote", "set-head", "origin", base)
+	if branch, err := repo.RemoteDefaultBranch("origin"); err != nil || branch != base {
+		t.Fatalf("RemoteDefaultBranch(origin) = %q, %v; want %q", branch, err, base)
+	}
+	runGitCommandInDir(t, cloneDir, "remote", "set-head", "origin", "--delete")
+	if _, err := repo.RemoteDefaultBranch("origin"); err == nil {
+		t.Fatal("RemoteDefaultBranch() without origin/HEAD should fail")
+	}
+}
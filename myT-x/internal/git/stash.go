@@ -0,0 +1,94 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNothingToStash is returned by StashPush when the working tree has no
+// changes to save.
+var ErrNothingToStash = errors.New("no local changes to stash")
+
+// StashPush stashes tracked and untracked changes under message and returns
+// the new entry.
+func (r *Repository) StashPush(message string) (StashEntry, error) {
+	if strings.ContainsAny(message, "\r\n") {
+		return StashEntry{}, errors.New("stash message must be a single line")
+	}
+	// rev-parse fails when there is no stash yet; before is then empty.
+	before, _ := r.runGitCommand("rev-parse", "--quiet", "--verify", "refs/stash")
+	if _, err := r.runGitCommand("stash", "push", "--quiet", "--include-untracked", "--message", message); err != nil {
+		return StashEntry{}, fmt.Errorf("failed to stash changes: %w", err)
+	}
+	stashes, err := r.ListStashes()
+	if err != nil {
+		return StashEntry{}, err
+	}
+	// `git stash push` succeeds without creating an entry on a clean tree.
+	if len(stashes) == 0 || stashes[0].Commit == before {
+		return StashEntry{}, ErrNothingToStash
+	}
+	return stashes[0], nil
+}
+
+// ApplyStash applies the stash entry whose commit is commit without dropping
+// it. The working tree must be clean so that a failed apply can be rolled
+// back: on conflicts the tree is reset to HEAD and the conflicted paths are
+// returned with a nil error.
+func (r *Repository) ApplyStash(commit string) (conflicts []string, err error) {
+	if !stashCommitPattern.MatchString(commit) {
+		return nil, fmt.Errorf("invalid stash commit: %q", commit)
+	}
+	status, err := r.WorkingTreeStatus()
+	if err != nil {
+		return nil, err
+	}
+	if status.Dirty > 0 {
+		return nil, ErrWorktreeHasUncommittedChanges
+	}
+
+	_, applyErr := r.runGitCommand("stash", "apply", "--quiet", commit)
+	if applyErr == nil {
+		return nil, nil
+	}
+	conflicts, listErr := r.listConflictedPaths()
+	if listErr != nil {
+		conflicts = nil
+	}
+	if rollbackErr := r.resetToCleanHead(); rollbackErr != nil {
+		return conflicts, fmt.Errorf("failed to apply stash: %w (rollback also failed: %v)", applyErr, rollbackErr)
+	}
+	if len(conflicts) > 0 {
+		return conflicts, nil
+	}
+	return nil, fmt.Errorf("failed to apply stash: %w", applyErr)
+}
+
+// listConflictedPaths returns the unmerged paths, slash-separated and
+// relative to the repository root.
+func (r *Repository) listConflictedPaths() ([]string, error) {
+	output, err := r.runGitCommandRaw("diff", "--name-only", "-z", "--diff-filter=U")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list conflicted files: %w", err)
+	}
+	var paths []string
+	for path := range strings.SplitSeq(output, "\x00") {
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths, nil
+}
+
+// resetToCleanHead discards every change in a working tree that was clean
+// before, including untracked files a stash restored. Ignored files are kept.
+func (r *Repository) resetToCleanHead() error {
+	if _, err := r.runGitCommand("reset", "--hard", "--quiet", "HEAD"); err != nil {
+		return fmt.Errorf("failed to reset working tree: %w", err)
+	}
+	if _, err := r.runGitCommand("clean", "-fd", "--quiet"); err != nil {
+		return fmt.Errorf("failed to remove untracked files: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"myT-x/internal/testutil"
+)
+
+func TestStashPushAndApply(t *testing.T) {
+	repoDir := testutil.CreateTempGitRepo(t)
+	repo, err := Open(repoDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	readme := filepath.Join(repoDir, "develop-README.md")
+	notes := filepath.Join(repoDir, "notes.txt")
+
+	if _, err := repo.StashPush("clean"); !errors.Is(err, ErrNothingToStash) {
+		t.Fatalf("StashPush() on a clean tree error = %v, want ErrNothingToStash", err)
+	}
+	if _, err := repo.StashPush("two\nlines"); err == nil {
+		t.Fatal("StashPush() should reject a multi-line message")
+	}
+
+	if err := os.WriteFile(readme, []byte("edited"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(notes, []byte("untracked"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	entry, err := repo.StashPush("wip")
+	if err != nil {
+		t.Fatalf("StashPush() error = %v", err)
+	}
+	if !strings.HasSuffix(entry.Message, "wip") || entry.Commit == "" {
+		t.Fatalf("StashPush() = %+v", entry)
+	}
+	if _, err := os.Stat(notes); !os.IsNotExist(err) {
+		t.Fatalf("untracked file still present after stash: %v", err)
+	}
+
+	conflicts, err := repo.ApplyStash(entry.Commit)
+	if err != nil || len(conflicts) != 0 {
+		t.Fatalf("ApplyStash() = %v, %v; want a clean apply", conflicts, err)
+	}
+	if data, _ := os.ReadFile(notes); string(data) != "untracked" {
+		t.Fatalf("notes.txt = %q, want the stashed content", data)
+	}
+
+	// The tree is dirty again, so a second apply must refuse to run.
+	if _, err := repo.ApplyStash(entry.Commit); !errors.Is(err, ErrWorktreeHasUncommittedChanges) {
+		t.Fatalf("ApplyStash() on a dirty tree error = %v", err)
+	}
+}
+
+func TestApplyStashConflictRollsBack(t *testing.T) {
+	repoDir := testutil.CreateTempGitRepo(t)
+	repo, err := Open(repoDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	readme := filepath.Join(repoDir, "develop-README.md")
+	if err := os.WriteFile(readme, []byte("stashed"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	entry, err := repo.StashPush("wip")
+	if err != nil {
+		t.Fatalf("StashPush() error = %v", err)
+	}
+	if err := os.WriteFile(readme, []byte("committed"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runGitCommandInDir(t, repoDir, "commit", "-q", "-am", "diverge")
+
+	conflicts, err := repo.ApplyStash(entry.Commit)
+	if err != nil {
+		t.Fatalf("ApplyStash() error = %v", err)
+	}
+	if !slices.Equal(conflicts, []string{"develop-README.md"}) {
+		t.Fatalf("conflicts = %q, want develop-README.md", conflicts)
+	}
+	if data, _ := os.ReadFile(readme); string(data) != "committed" {
+		t.Fatalf("develop-README.md = %q, want it rolled back to HEAD", data)
+	}
+	status, err := repo.WorkingTreeStatus()
+	if err != nil || status.Dirty != 0 {
+		t.Fatalf("status after rollback = %+v, %v; want clean", status, err)
+	}
+	if stashes, _ := repo.ListStashes(); len(stashes) != 1 {
+		t.Fatalf("stashes = %+v, want the conflicting stash kept", stashes)
+	}
+}
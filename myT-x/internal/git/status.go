@@ -0,0 +1,101 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"strconv"
+	"strings"
+)
+
+// WorkingTreeStatus summarizes `git status` for a worktree: the branch, how
+// far it is from its upstream, and how many files are changed.
+type WorkingTreeStatus struct {
+	// Branch is the current branch; empty on a detached HEAD.
+	Branch   string `json:"branch"`
+	Detached bool   `json:"detached"`
+	// Upstream is the tracking branch, e.g. "origin/main"; empty without one.
+	Upstream string `json:"upstream,omitempty"`
+	// Ahead and Behind count commits relative to Upstream.
+	Ahead  int `json:"ahead"`
+	Behind int `json:"behind"`
+	// Staged and Unstaged count tracked files with index and worktree
+	// changes; a file changed in both is counted in both.
+	Staged     int `json:"staged"`
+	Unstaged   int `json:"unstaged"`
+	Untracked  int `json:"untracked"`
+	Conflicted int `json:"conflicted"`
+	// Dirty counts every file with uncommitted changes of any kind.
+	Dirty int `json:"dirty"`
+}
+
+// WorkingTreeStatus reads the status of the repository worktree.
+//
+// Optional locks are disabled so that a background poll never takes
+// index.lock away from a git command the user runs at the same time.
+func (r *Repository) WorkingTreeStatus() (WorkingTreeStatus, error) {
+	output, err := runGitCLIWithEnv(context.Background(), r.path,
+		[]string{"status", "--porcelain=v2", "--branch"},
+		map[string]string{"GIT_OPTIONAL_LOCKS": "0"})
+	if err != nil {
+		return WorkingTreeStatus{}, err
+	}
+	return parseStatusPorcelainV2(output), nil
+}
+
+// ReadWorkingTreeStatus opens the repository at dir and reads its status.
+func ReadWorkingTreeStatus(dir string) (WorkingTreeStatus, error) {
+	repo, err := Open(dir)
+	if err != nil {
+		return WorkingTreeStatus{}, err
+	}
+	return repo.WorkingTreeStatus()
+}
+
+// parseStatusPorcelainV2 parses `git status --porcelain=v2 --branch`.
+// Unknown lines are ignored.
+func parseStatusPorcelainV2(output []byte) WorkingTreeStatus {
+	var status WorkingTreeStatus
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	scanner.Buffer(make([]byte, 0, 4096), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "# branch.head "):
+			head := strings.TrimPrefix(line, "# branch.head ")
+			if head == "(detached)" {
+				status.Detached = true
+			} else {
+				status.Branch = head
+			}
+		case strings.HasPrefix(line, "# branch.upstream "):
+			status.Upstream = strings.TrimPrefix(line, "# branch.upstream ")
+		case strings.HasPrefix(line, "# branch.ab "):
+			fields := strings.Fields(strings.TrimPrefix(line, "# branch.ab "))
+			if len(fields) == 2 {
+				status.Ahead, _ = strconv.Atoi(strings.TrimPrefix(fields[0], "+"))
+				status.Behind, _ = strconv.Atoi(strings.TrimPrefix(fields[1], "-"))
+			}
+		case strings.HasPrefix(line, "1 "), strings.HasPrefix(line, "2 "):
+			// "<type> <XY> ...": X is the index state, Y the worktree state,
+			// and "." means unchanged.
+			if len(line) < 4 {
+				continue
+			}
+			status.Dirty++
+			if line[2] != '.' {
+				status.Staged++
+			}
+			if line[3] != '.' {
+				status.Unstaged++
+			}
+		case strings.HasPrefix(line, "u "):
+			status.Conflicted++
+			status.Dirty++
+		case strings.HasPrefix(line, "? "):
+			status.Untracked++
+			status.Dirty++
+		}
+	}
+	return status
+}
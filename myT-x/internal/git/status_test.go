@@ -0,0 +1,74 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"myT-x/internal/testutil"
+)
+
+func TestParseStatusPorcelainV2(t *testing.T) {
+	output := []byte(`# branch.oid 1234567890abcdef1234567890abcdef12345678
+# branch.head feature/x
+# branch.upstream origin/feature/x
+# branch.ab +2 -3
+1 M. N... 100644 100644 100644 aaa bbb staged.go
+1 .M N... 100644 100644 100644 aaa bbb unstaged.go
+1 MM N... 100644 100644 100644 aaa bbb both.go
+2 R. N... 100644 100644 100644 aaa bbb R100 new.go	old.go
+u UU N... 100644 100644 100644 100644 aaa bbb ccc conflict.go
+? untracked.txt
+! ignored.log
+`)
+	got := parseStatusPorcelainV2(output)
+	want := WorkingTreeStatus{
+		Branch:     "feature/x",
+		Upstream:   "origin/feature/x",
+		Ahead:      2,
+		Behind:     3,
+		Staged:     3,
+		Unstaged:   2,
+		Untracked:  1,
+		Conflicted: 1,
+		Dirty:      6,
+	}
+	if got != want {
+		t.Fatalf("parseStatusPorcelainV2() = %+v, want %+v", got, want)
+	}
+
+	detached := parseStatusPorcelainV2([]byte("# branch.oid abc\n# branch.head (detached)\n"))
+	if !detached.Detached || detached.Branch != "" || detached.Dirty != 0 {
+		t.Fatalf("detached status = %+v", detached)
+	}
+}
+
+func TestReadWorkingTreeStatus(t *testing.T) {
+	dir := testutil.CreateTempGitRepo(t)
+	if err := os.WriteFile(filepath.Join(dir, "new.txt"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "develop-README.md"), []byte("# changed"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	status, err := ReadWorkingTreeStatus(dir)
+	if err != nil {
+		t.Fatalf("ReadWorkingTreeStatus() error = %v", err)
+	}
+	branch, err := exec.Command("git", "-C", dir, "branch", "--show-current").Output()
+	if err != nil {
+		t.Fatalf("git branch --show-current: %v", err)
+	}
+	if want := string(branch[:len(branch)-1]); status.Branch != want {
+		t.Fatalf("Branch = %q, want %q", status.Branch, want)
+	}
+	if status.Unstaged != 1 || status.Untracked != 1 || status.Dirty != 2 || status.Upstream != "" {
+		t.Fatalf("status = %+v, want one unstaged and one untracked file without upstream", status)
+	}
+
+	if _, err := ReadWorkingTreeStatus(t.TempDir()); err == nil {
+		t.Fatal("ReadWorkingTreeStatus() succeeded outside a repository")
+	}
+}
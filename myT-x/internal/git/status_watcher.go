@@ -0,0 +1,186 @@
+package git
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"myT-x/internal/apptypes"
+)
+
+// StatusChangedEvent carries a StatusChange when the worktree status of a
+// session changes.
+const StatusChangedEvent = "git:status-changed"
+
+// DefaultStatusPollInterval is the polling period used when
+// StatusWatcherDeps.PollInterval is zero.
+const DefaultStatusPollInterval = 5 * time.Second
+
+// StatusTarget is the worktree directory of one session.
+type StatusTarget struct {
+	SessionName string
+	Dir         string
+}
+
+// StatusChange is the payload of StatusChangedEvent. Status is nil when the
+// session closed or its directory is no longer a git worktree.
+type StatusChange struct {
+	SessionName string             `json:"session_name"`
+	Status      *WorkingTreeStatus `json:"status"`
+}
+
+// StatusWatcherDeps holds external dependencies injected at construction time.
+type StatusWatcherDeps struct {
+	// Emitter sends runtime events to the frontend.
+	// Optional: defaults to a no-op emitter if nil.
+	Emitter apptypes.RuntimeEventEmitter
+
+	// Targets returns the worktree directory of every session.
+	Targets func() []StatusTarget
+
+	// ReadStatus reads the status of the worktree at dir.
+	// Optional: defaults to ReadWorkingTreeStatus.
+	ReadStatus func(dir string) (WorkingTreeStatus, error)
+
+	// PollInterval is the period of Run.
+	// Optional: defaults to DefaultStatusPollInterval.
+	PollInterval time.Duration
+
+	// Paused reports whether Run should skip polls, e.g. while the system
+	// sleeps. Optional: defaults to never paused.
+	Paused func() bool
+
+	// OnPoll receives the statuses of every session after each poll, e.g. to
+	// compare sessions that share a worktree. Optional.
+	OnPoll func(statuses map[string]WorkingTreeStatus)
+}
+
+// StatusWatcher keeps the latest worktree status of every session and emits
+// StatusChangedEvent for changes, so the frontend does not have to query
+// each session.
+//
+// Thread-safety is managed internally via mu. No external locking is required.
+type StatusWatcher struct {
+	deps StatusWatcherDeps
+
+	// pollMu serializes Poll so that concurrent polls cannot emit changes
+	// out of order.
+	pollMu   sync.Mutex
+	mu       sync.Mutex
+	statuses map[string]WorkingTreeStatus
+}
+
+// NewStatusWatcher creates a status watcher.
+// Panics if Targets is nil.
+func NewStatusWatcher(deps StatusWatcherDeps) *StatusWatcher {
+	if deps.Targets == nil {
+		panic("git.NewStatusWatcher: Targets must be non-nil")
+	}
+	if deps.Emitter == nil {
+		deps.Emitter = apptypes.NoopEmitter{}
+	}
+	if deps.ReadStatus == nil {
+		deps.ReadStatus = ReadWorkingTreeStatus
+	}
+	if deps.PollInterval <= 0 {
+		deps.PollInterval = DefaultStatusPollInterval
+	}
+	if deps.Paused == nil {
+		deps.Paused = func() bool { return false }
+	}
+	return &StatusWatcher{
+		deps:     deps,
+		statuses: map[string]WorkingTreeStatus{},
+	}
+}
+
+// Run polls immediately and then every PollInterval until ctx is cancelled,
+// skipping ticks while paused.
+func (w *StatusWatcher) Run(ctx context.Context) {
+	w.Poll()
+	ticker := time.NewTicker(w.deps.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if w.deps.Paused() {
+				continue
+			}
+			w.Poll()
+		}
+	}
+}
+
+// Poll reads the status of every target once, updates the cache, and emits
+// StatusChangedEvent for each session whose status changed, appeared, or went
+// away. Sessions sharing a directory share one git invocation. OnPoll runs
+// before the events are emitted.
+func (w *StatusWatcher) Poll() {
+	w.pollMu.Lock()
+	defer w.pollMu.Unlock()
+
+	type result struct {
+		status WorkingTreeStatus
+		ok     bool
+	}
+	byDir := map[string]result{}
+	next := map[string]WorkingTreeStatus{}
+	for _, target := range w.deps.Targets() {
+		dir := strings.TrimSpace(target.Dir)
+		if target.SessionName == "" || dir == "" {
+			continue
+		}
+		res, seen := byDir[dir]
+		if !seen {
+			status, err := w.deps.ReadStatus(dir)
+			if err != nil {
+				// Not a repository, or removed from under the session.
+				slog.Debug("[DEBUG-GIT] status poll skipped directory", "dir", dir, "error", err)
+			}
+			res = result{status: status, ok: err == nil}
+			byDir[dir] = res
+		}
+		if res.ok {
+			next[target.SessionName] = res.status
+		}
+	}
+
+	w.mu.Lock()
+	var changes []StatusChange
+	for name, status := range next {
+		if previous, ok := w.statuses[name]; !ok || previous != status {
+			changes = append(changes, StatusChange{SessionName: name, Status: &status})
+		}
+	}
+	for name := range w.statuses {
+		if _, ok := next[name]; !ok {
+			changes = append(changes, StatusChange{SessionName: name})
+		}
+	}
+	w.statuses = next
+	w.mu.Unlock()
+
+	if w.deps.OnPoll != nil {
+		w.deps.OnPoll(maps.Clone(next))
+	}
+
+	slices.SortFunc(changes, func(a, b StatusChange) int {
+		return strings.Compare(a.SessionName, b.SessionName)
+	})
+	for _, change := range changes {
+		w.deps.Emitter.Emit(StatusChangedEvent, change)
+	}
+}
+
+// Statuses returns the latest status of every session with a git worktree.
+func (w *StatusWatcher) Statuses() map[string]WorkingTreeStatus {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return maps.Clone(w.statuses)
+}
@@ -0,0 +1,112 @@
+package git
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"myT-x/internal/apptypes"
+)
+
+func TestNewStatusWatcherPanicsWithoutTargets(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic for missing Targets")
+		}
+	}()
+	NewStatusWatcher(StatusWatcherDeps{})
+}
+
+func TestStatusWatcherPoll(t *testing.T) {
+	var mu sync.Mutex
+	var changes []StatusChange
+	targets := []StatusTarget{
+		{SessionName: "api", Dir: `C:\repo`},
+		{SessionName: "web", Dir: `C:\repo`},
+		{SessionName: "notes", Dir: `C:\notes`},
+	}
+	statuses := map[string]WorkingTreeStatus{`C:\repo`: {Branch: "main"}}
+	reads := 0
+	watcher := NewStatusWatcher(StatusWatcherDeps{
+		Emitter: apptypes.EventEmitterFunc(func(name string, payload any) {
+			if name != StatusChangedEvent {
+				t.Errorf("event = %q, want %q", name, StatusChangedEvent)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			changes = append(changes, payload.(StatusChange))
+		}),
+		Targets: func() []StatusTarget { return targets },
+		ReadStatus: func(dir string) (WorkingTreeStatus, error) {
+			reads++
+			status, ok := statuses[dir]
+			if !ok {
+				return WorkingTreeStatus{}, errors.New("not a git repository")
+			}
+			return status, nil
+		},
+	})
+	take := func() []StatusChange {
+		mu.Lock()
+		defer mu.Unlock()
+		taken := changes
+		changes = nil
+		return taken
+	}
+
+	watcher.Poll()
+	if reads != 2 {
+		t.Fatalf("reads = %d, want 2 (one per directory)", reads)
+	}
+	got := take()
+	if len(got) != 2 || got[0].SessionName != "api" || got[1].SessionName != "web" || got[0].Status.Branch != "main" {
+		t.Fatalf("first poll changes = %+v, want api and web", got)
+	}
+
+	watcher.Poll()
+	if got := take(); len(got) != 0 {
+		t.Fatalf("unchanged poll emitted %+v", got)
+	}
+
+	statuses[`C:\repo`] = WorkingTreeStatus{Branch: "main", Dirty: 1, Unstaged: 1}
+	targets = targets[1:]
+	watcher.Poll()
+	got = take()
+	if len(got) != 2 {
+		t.Fatalf("changes = %+v, want api removed and web changed", got)
+	}
+	if got[0].SessionName != "api" || got[0].Status != nil {
+		t.Fatalf("api change = %+v, want nil status", got[0])
+	}
+	if got[1].SessionName != "web" || got[1].Status == nil || got[1].Status.Dirty != 1 {
+		t.Fatalf("web change = %+v, want dirty status", got[1])
+	}
+
+	if all := watcher.Statuses(); len(all) != 1 || all["web"].Dirty != 1 {
+		t.Fatalf("Statuses() = %+v, want only web", all)
+	}
+}
+
+func TestStatusWatcherOnPoll(t *testing.T) {
+	var polled []map[string]WorkingTreeStatus
+	watcher := NewStatusWatcher(StatusWatcherDeps{
+		Targets: func() []StatusTarget {
+			return []StatusTarget{{SessionName: "api", Dir: `C:\repo`}}
+		},
+		ReadStatus: func(string) (WorkingTreeStatus, error) {
+			return WorkingTreeStatus{Branch: "main"}, nil
+		},
+		OnPoll: func(statuses map[string]WorkingTreeStatus) {
+			polled = append(polled, statuses)
+		},
+	})
+
+	watcher.Poll()
+	watcher.Poll()
+	if len(polled) != 2 {
+		t.Fatalf("OnPoll calls = %d, want one per poll", len(polled))
+	}
+	if polled[1]["api"].Branch != "main" {
+		t.Fatalf("OnPoll statuses = %+v, want api on main", polled[1])
+	}
+}
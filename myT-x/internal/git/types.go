@@ -6,6 +6,8 @@ type WorktreeInfo struct {
 	Branch     string          `json:"branch"`
 	IsMain     bool            `json:"isMain"`
 	IsDetached bool            `json:"isDetached"`
+	Locked     bool            `json:"locked"`
+	LockReason string          `json:"lockReason,omitempty"`
 	Health     *WorktreeHealth `json:"health,omitempty"`
 }
 
@@ -1,10 +1,13 @@
 package git
 
 import (
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
+	"unicode"
 )
 
 // CreateWorktree creates a new worktree with a new branch from the specified base branch.
@@ -89,6 +92,94 @@ func (r *Repository) removeWorktree(worktreePath string, force bool) error {
 	return nil
 }
 
+// AutoLockReasonPrefix starts the reason of locks placed by myT-x itself.
+// Cleanup releases these locks; locks with any other reason belong to the user.
+const AutoLockReasonPrefix = "myT-x auto-lock: "
+
+// LockWorktree locks a worktree so that git does not prune or remove it.
+// Executes: git worktree lock [--reason <reason>] -- <path>
+func (r *Repository) LockWorktree(worktreePath, reason string) error {
+	if err := ValidateWorktreePath(worktreePath); err != nil {
+		return fmt.Errorf("invalid worktree path: %w", err)
+	}
+	reason = strings.TrimSpace(reason)
+	if strings.ContainsFunc(reason, unicode.IsControl) {
+		return errors.New("lock reason must not contain control characters")
+	}
+	args := []string{"worktree", "lock"}
+	if reason != "" {
+		args = append(args, "--reason", reason)
+	}
+	args = append(args, "--", worktreePath)
+	if _, err := r.runGitCommand(args...); err != nil {
+		return fmt.Errorf("failed to lock worktree %q: %w", worktreePath, err)
+	}
+	return nil
+}
+
+// UnlockWorktree removes the lock of a worktree.
+// Executes: git worktree unlock -- <path>
+func (r *Repository) UnlockWorktree(worktreePath string) error {
+	if err := ValidateWorktreePath(worktreePath); err != nil {
+		return fmt.Errorf("invalid worktree path: %w", err)
+	}
+	if _, err := r.runGitCommand("worktree", "unlock", "--", worktreePath); err != nil {
+		return fmt.Errorf("failed to unlock worktree %q: %w", worktreePath, err)
+	}
+	return nil
+}
+
+// FindWorktreeInfo returns the entry of `git worktree list` for worktreePath.
+// found is false when git does not know the path.
+func (r *Repository) FindWorktreeInfo(worktreePath string) (info WorktreeInfo, found bool, err error) {
+	infos, err := r.ListWorktreesWithInfo()
+	if err != nil {
+		return WorktreeInfo{}, false, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	target := filepath.Clean(worktreePath)
+	for _, info := range infos {
+		if strings.EqualFold(filepath.Clean(info.Path), target) {
+			return info, true, nil
+		}
+	}
+	return WorktreeInfo{}, false, nil
+}
+
+// WorktreeLockState reports whether the worktree at worktreePath is locked
+// and with which reason. Unknown paths are reported as unlocked.
+func (r *Repository) WorktreeLockState(worktreePath string) (locked bool, reason string, err error) {
+	info, _, err := r.FindWorktreeInfo(worktreePath)
+	if err != nil {
+		return false, "", err
+	}
+	return info.Locked, info.LockReason, nil
+}
+
+// ReleaseWorktreeLockForRemoval prepares a worktree for removal. Locks placed
+// by myT-x (AutoLockReasonPrefix) are released; any other lock is reported as
+// ErrWorktreeLocked so the user's lock is never overridden.
+func (r *Repository) ReleaseWorktreeLockForRemoval(worktreePath string) error {
+	locked, reason, err := r.WorktreeLockState(worktreePath)
+	if err != nil {
+		return err
+	}
+	if !locked {
+		return nil
+	}
+	if !strings.HasPrefix(reason, AutoLockReasonPrefix) {
+		if reason == "" {
+			return fmt.Errorf("%w; unlock it before removing", ErrWorktreeLocked)
+		}
+		return fmt.Errorf("%w (%s); unlock it before removing", ErrWorktreeLocked, reason)
+	}
+	if err := r.UnlockWorktree(worktreePath); err != nil {
+		return err
+	}
+	slog.Debug("[DEBUG-GIT] released automatic worktree lock before removal",
+		"path", worktreePath, "reason", reason)
+	return nil
+}
+
 // ListWorktrees returns a list of worktree paths.
 func (r *Repository) ListWorktrees() ([]string, error) {
 	output, err := r.runGitCommand("worktree", "list", "--porcelain")
@@ -136,6 +227,9 @@ func (r *Repository) ListWorktreesWithInfo() ([]WorktreeInfo, error) {
 			current.Branch = strings.TrimPrefix(line, "branch refs/heads/")
 		case line == "detached":
 			current.IsDetached = true
+		case line == "locked" || strings.HasPrefix(line, "locked "):
+			current.Locked = true
+			current.LockReason = strings.TrimPrefix(strings.TrimPrefix(line, "locked"), " ")
 		case line == "bare":
 			current.Path = ""
 		}
@@ -278,6 +278,69 @@ func TestListWorktreesWithInfo(t *testing.T) {
 	}
 }
 
+func TestWorktreeLocking(t *testing.T) {
+	testutil.SkipIfNoGit(t)
+
+	repoDir := testutil.CreateTempGitRepo(t)
+	repo, err := Open(repoDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wtDir := GenerateWorktreeDirPath(repoDir)
+	if err := os.MkdirAll(wtDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	wtPath := filepath.Join(wtDir, "locked-wt")
+	if err := repo.CreateWorktreeDetached(wtPath, "HEAD"); err != nil {
+		t.Fatal(err)
+	}
+
+	assertLock := func(wantLocked bool, wantReason string) {
+		t.Helper()
+		locked, reason, err := repo.WorktreeLockState(wtPath)
+		if err != nil {
+			t.Fatalf("WorktreeLockState() error = %v", err)
+		}
+		if locked != wantLocked || reason != wantReason {
+			t.Fatalf("WorktreeLockState() = %v, %q; want %v, %q", locked, reason, wantLocked, wantReason)
+		}
+	}
+
+	assertLock(false, "")
+	if err := repo.LockWorktree(wtPath, "bad\nreason"); err == nil {
+		t.Fatal("LockWorktree() with control characters error = nil")
+	}
+
+	if err := repo.LockWorktree(wtPath, "on USB stick"); err != nil {
+		t.Fatalf("LockWorktree() error = %v", err)
+	}
+	assertLock(true, "on USB stick")
+	if err := repo.ReleaseWorktreeLockForRemoval(wtPath); !errors.Is(err, ErrWorktreeLocked) {
+		t.Fatalf("ReleaseWorktreeLockForRemoval() error = %v, want ErrWorktreeLocked", err)
+	} else if !strings.Contains(err.Error(), "on USB stick") {
+		t.Fatalf("ReleaseWorktreeLockForRemoval() error = %v, want lock reason", err)
+	}
+	if err := repo.RemoveWorktreeForced(wtPath); err == nil {
+		t.Fatal("RemoveWorktreeForced() removed a user-locked worktree")
+	}
+
+	if err := repo.UnlockWorktree(wtPath); err != nil {
+		t.Fatalf("UnlockWorktree() error = %v", err)
+	}
+	if err := repo.LockWorktree(wtPath, AutoLockReasonPrefix+"removable drive"); err != nil {
+		t.Fatalf("LockWorktree() error = %v", err)
+	}
+	if err := repo.ReleaseWorktreeLockForRemoval(wtPath); err != nil {
+		t.Fatalf("ReleaseWorktreeLockForRemoval() error = %v", err)
+	}
+	assertLock(false, "")
+
+	if err := repo.LockWorktree(wtPath, ""); err != nil {
+		t.Fatalf("LockWorktree() without reason error = %v", err)
+	}
+	assertLock(true, "")
+}
+
 func TestRemoveWorktreeForced(t *testing.T) {
 	testutil.SkipIfNoGit(t)
 
@@ -562,8 +625,8 @@ func TestCheckWorktreeHealth(t *testing.T) {
 }
 
 func TestWorktreeStructFieldCounts(t *testing.T) {
-	if got := reflect.TypeFor[WorktreeInfo]().NumField(); got != 5 {
-		t.Fatalf("WorktreeInfo field count = %d, want 5; update tests for new fields", got)
+	if got := reflect.TypeFor[WorktreeInfo]().NumField(); got != 7 {
+		t.Fatalf("WorktreeInfo field count = %d, want 7; update tests for new fields", got)
 	}
 	if got := reflect.TypeFor[WorktreeHealth]().NumField(); got != 2 {
 		t.Fatalf("WorktreeHealth field count = %d, want 2; update tests for new fields", got)
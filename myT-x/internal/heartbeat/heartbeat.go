@@ -0,0 +1,278 @@
+// Package heartbeat periodically writes a small JSON status file so that
+// external tools (monitoring scripts, status bar widgets, the CLI) can tell
+// whether the myT-x host is alive without connecting to its pipe.
+//
+// The file is replaced atomically, so readers never observe a partial write.
+// A host that exits cleanly leaves the file with State "stopped"; a host that
+// crashed leaves a "running" file whose UpdatedAt stops advancing, which
+// Status.Healthy treats as unhealthy.
+//
+// The file is a stable interface: fields are only added, never renamed or
+// repurposed without bumping SchemaVersion. Status bar widgets and editor
+// statuslines should read it (or `mytx-state status -json`) rather than the
+// frontend snapshot schema, which changes between releases.
+package heartbeat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+)
+
+const (
+	// FileName is the heartbeat file name under the config directory.
+	FileName = "heartbeat.json"
+	// SchemaVersion is bumped when a field changes meaning.
+	SchemaVersion = 1
+	// defaultInterval is how often Run rewrites the file.
+	defaultInterval = 10 * time.Second
+	// staleIntervals is how many missed writes make a heartbeat stale.
+	staleIntervals = 3
+
+	maxRenameRetry       = 5
+	renameRetryBaseDelay = 20 * time.Millisecond
+)
+
+// Host states.
+const (
+	StateRunning = "running"
+	StateStopped = "stopped"
+)
+
+// Session summarizes one session in the heartbeat.
+type Session struct {
+	Name    string `json:"name"`
+	Windows int    `json:"windows"`
+	Panes   int    `json:"panes"`
+	Idle    bool   `json:"idle"`
+	Active  bool   `json:"active"`
+	// Branch is the git branch of the session's work directory; empty outside
+	// a repository or on a detached HEAD.
+	Branch string `json:"branch,omitempty"`
+	// Dirty reports uncommitted changes in the session's work directory.
+	Dirty bool `json:"dirty"`
+	// LastActivity is when a pane of the session last produced output.
+	LastActivity time.Time `json:"last_activity,omitzero"`
+	// NeedsInput reports a pane waiting for the user, e.g. an agent prompt.
+	NeedsInput bool `json:"needs_input"`
+	// Badge is the session's effective badge, if any.
+	Badge *Badge `json:"badge,omitempty"`
+}
+
+// Badge is the color and emoji marking a session in the sidebar.
+type Badge struct {
+	Color string `json:"color,omitempty"`
+	Emoji string `json:"emoji,omitempty"`
+}
+
+// Status is the heartbeat file content.
+type Status struct {
+	SchemaVersion int    `json:"schema_version"`
+	State         string `json:"state"`
+	PID           int    `json:"pid"`
+	Version       string `json:"version"`
+	PipeName      string `json:"pipe_name"`
+	// StreamURL is the pane output stream WebSocket URL with its access
+	// token, if it is served.
+	StreamURL string    `json:"stream_url,omitempty"`
+	StartedAt time.Time `json:"started_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// IntervalSec is the write interval readers use to judge staleness.
+	IntervalSec int       `json:"interval_sec"`
+	Sessions    []Session `json:"sessions"`
+}
+
+// Healthy reports whether the host is running and wrote the heartbeat within
+// the last few intervals.
+func (s Status) Healthy(now time.Time) bool {
+	if s.State != StateRunning {
+		return false
+	}
+	interval := time.Duration(s.IntervalSec) * time.Second
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	return now.Sub(s.UpdatedAt) <= staleIntervals*interval
+}
+
+// Summary is the subset of Status that status bar integrations display. It
+// is the output of `mytx-state status -json`.
+type Summary struct {
+	SchemaVersion int       `json:"schema_version"`
+	Healthy       bool      `json:"healthy"`
+	Version       string    `json:"version,omitempty"`
+	UpdatedAt     time.Time `json:"updated_at,omitzero"`
+	Sessions      []Session `json:"sessions"`
+}
+
+// Summary returns the status bar view of s. An unhealthy host reports no
+// sessions, so widgets never show the state of a crashed host as live.
+func (s Status) Summary(now time.Time) Summary {
+	summary := Summary{
+		SchemaVersion: SchemaVersion,
+		Healthy:       s.Healthy(now),
+		Version:       s.Version,
+		UpdatedAt:     s.UpdatedAt,
+		Sessions:      []Session{},
+	}
+	if summary.Healthy && s.Sessions != nil {
+		summary.Sessions = s.Sessions
+	}
+	return summary
+}
+
+// Read parses the heartbeat file at path.
+func Read(path string) (Status, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Status{}, err
+	}
+	var status Status
+	if err := json.Unmarshal(data, &status); err != nil {
+		return Status{}, fmt.Errorf("parse heartbeat %s: %w", path, err)
+	}
+	return status, nil
+}
+
+// Deps holds external dependencies injected at construction time.
+type Deps struct {
+	// Path returns the heartbeat file path.
+	Path func() (string, error)
+	// Sessions returns the current sessions.
+	Sessions func() []Session
+
+	// Version is the app version written to the file.
+	Version string
+	// PipeName is the tmux IPC pipe name written to the file.
+	PipeName string
+	// StreamURL is the pane output stream URL written to the file. Optional.
+	StreamURL string
+
+	// Interval is how often Run rewrites the file.
+	// Optional: defaults to 10 seconds.
+	Interval time.Duration
+	// Now returns the current time. Optional: defaults to time.Now.
+	Now func() time.Time
+}
+
+// Writer writes the heartbeat file. Run is the single long-lived writer;
+// Write is exposed for one-off refreshes.
+type Writer struct {
+	deps      Deps
+	pid       int
+	startedAt time.Time
+}
+
+// NewWriter creates a heartbeat writer.
+// Panics if Path or Sessions is nil.
+func NewWriter(deps Deps) *Writer {
+	if deps.Path == nil || deps.Sessions == nil {
+		panic("heartbeat.NewWriter: required function fields in Deps must be non-nil (Path, Sessions)")
+	}
+	if deps.Interval <= 0 {
+		deps.Interval = defaultInterval
+	}
+	if deps.Now == nil {
+		deps.Now = time.Now
+	}
+	return &Writer{deps: deps, pid: os.Getpid(), startedAt: deps.Now().UTC()}
+}
+
+// Run writes the heartbeat immediately and then every Interval until ctx is
+// cancelled, when it writes a final "stopped" heartbeat.
+func (w *Writer) Run(ctx context.Context) {
+	w.writeLogged(StateRunning)
+	ticker := time.NewTicker(w.deps.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			w.writeLogged(StateStopped)
+			return
+		case <-ticker.C:
+			w.writeLogged(StateRunning)
+		}
+	}
+}
+
+// Write replaces the heartbeat file with the current status in state.
+func (w *Writer) Write(state string) error {
+	path, err := w.deps.Path()
+	if err != nil {
+		return err
+	}
+	status := Status{
+		SchemaVersion: SchemaVersion,
+		State:         state,
+		PID:           w.pid,
+		Version:       w.deps.Version,
+		PipeName:      w.deps.PipeName,
+		StreamURL:     w.deps.StreamURL,
+		StartedAt:     w.startedAt,
+		UpdatedAt:     w.deps.Now().UTC(),
+		IntervalSec:   int(w.deps.Interval / time.Second),
+		Sessions:      []Session{},
+	}
+	if state == StateRunning {
+		if sessions := w.deps.Sessions(); sessions != nil {
+			status.Sessions = sessions
+		}
+	}
+	data, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal heartbeat: %w", err)
+	}
+	return writeFileAtomic(path, append(data, '\n'))
+}
+
+func (w *Writer) writeLogged(state string) {
+	if err := w.Write(state); err != nil {
+		slog.Warn("[WARN-HEARTBEAT] failed to write heartbeat", "state", state, "error", err)
+	}
+}
+
+// writeFileAtomic writes data to a temp file in the target directory and
+// renames it over path. Rename is retried on Windows, where a reader holding
+// the file open makes it fail transiently.
+func writeFileAtomic(path string, data []byte) (err error) {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create heartbeat directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, ".heartbeat.json.tmp.*")
+	if err != nil {
+		return fmt.Errorf("create heartbeat temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer func() {
+		if err != nil {
+			if removeErr := os.Remove(tmpPath); removeErr != nil && !errors.Is(removeErr, os.ErrNotExist) {
+				slog.Debug("[DEBUG-HEARTBEAT] failed to remove temp file", "path", tmpPath, "error", removeErr)
+			}
+		}
+	}()
+
+	if _, err = tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("write heartbeat: %w", err)
+	}
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("close heartbeat temp file: %w", err)
+	}
+	for attempt := range maxRenameRetry {
+		if err = os.Rename(tmpPath, path); err == nil || runtime.GOOS != "windows" {
+			break
+		}
+		time.Sleep(time.Duration(attempt+1) * renameRetryBaseDelay)
+	}
+	if err != nil {
+		return fmt.Errorf("replace heartbeat: %w", err)
+	}
+	return nil
+}
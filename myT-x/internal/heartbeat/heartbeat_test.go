@@ -0,0 +1,184 @@
+package heartbeat
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func newTestWriter(t *testing.T, path string, now *time.Time) *Writer {
+	t.Helper()
+	return NewWriter(Deps{
+		Path: func() (string, error) { return path, nil },
+		Sessions: func() []Session {
+			return testSessions()
+		},
+		Version:   "1.2.3",
+		PipeName:  `\\.\pipe\myT-x-test`,
+		StreamURL: "ws://127.0.0.1:1234/stream",
+		Interval:  5 * time.Second,
+		Now:       func() time.Time { return *now },
+	})
+}
+
+func testSessions() []Session {
+	return []Session{{
+		Name: "main", Windows: 2, Panes: 3, Active: true,
+		Branch: "feature/x", Dirty: true, NeedsInput: true,
+		LastActivity: time.Date(2026, 1, 2, 3, 0, 0, 0, time.UTC),
+		Badge:        &Badge{Color: "#ff0000", Emoji: "🔥"},
+	}}
+}
+
+func TestNewWriterPanicsOnMissingDeps(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("NewWriter() did not panic with nil Path/Sessions")
+		}
+	}()
+	NewWriter(Deps{})
+}
+
+func TestWriteAndRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", FileName)
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	writer := newTestWriter(t, path, &now)
+
+	if err := writer.Write(StateRunning); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	status, err := Read(path)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if status.State != StateRunning || status.PID != os.Getpid() || status.Version != "1.2.3" ||
+		status.PipeName != `\\.\pipe\myT-x-test` || status.IntervalSec != 5 || status.SchemaVersion != SchemaVersion ||
+		status.StreamURL != "ws://127.0.0.1:1234/stream" {
+		t.Fatalf("Read() = %+v", status)
+	}
+	if !status.UpdatedAt.Equal(now) {
+		t.Fatalf("UpdatedAt = %v, want %v", status.UpdatedAt, now)
+	}
+	if want := testSessions(); !reflect.DeepEqual(status.Sessions, want) {
+		t.Fatalf("Sessions = %+v, want %+v", status.Sessions, want)
+	}
+
+	if err := writer.Write(StateStopped); err != nil {
+		t.Fatalf("Write(stopped) error = %v", err)
+	}
+	status, err = Read(path)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if status.State != StateStopped || len(status.Sessions) != 0 {
+		t.Fatalf("stopped status = %+v", status)
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("directory has %d entries, want only the heartbeat file", len(entries))
+	}
+}
+
+func TestReadRejectsCorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	if err := os.WriteFile(path, []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Read(path); err == nil {
+		t.Fatal("Read() expected error for corrupt file")
+	}
+}
+
+func TestStatusHealthy(t *testing.T) {
+	updated := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name   string
+		status Status
+		now    time.Time
+		want   bool
+	}{
+		{"fresh", Status{State: StateRunning, UpdatedAt: updated, IntervalSec: 10}, updated.Add(20 * time.Second), true},
+		{"stale", Status{State: StateRunning, UpdatedAt: updated, IntervalSec: 10}, updated.Add(31 * time.Second), false},
+		{"stopped", Status{State: StateStopped, UpdatedAt: updated, IntervalSec: 10}, updated, false},
+		{"default interval", Status{State: StateRunning, UpdatedAt: updated}, updated.Add(25 * time.Second), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.status.Healthy(tt.now); got != tt.want {
+				t.Fatalf("Healthy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatusSummary(t *testing.T) {
+	updated := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	status := Status{
+		SchemaVersion: SchemaVersion, State: StateRunning, PID: 7, Version: "1.2.3",
+		UpdatedAt: updated, IntervalSec: 10, Sessions: testSessions(),
+	}
+
+	got := status.Summary(updated)
+	want := Summary{SchemaVersion: SchemaVersion, Healthy: true, Version: "1.2.3", UpdatedAt: updated, Sessions: testSessions()}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Summary() = %+v, want %+v", got, want)
+	}
+
+	got = status.Summary(updated.Add(time.Hour))
+	if got.Healthy || got.Sessions == nil || len(got.Sessions) != 0 {
+		t.Fatalf("stale Summary() = %+v, want unhealthy with no sessions", got)
+	}
+}
+
+func TestSessionJSONOmitsUnsetOptionalFields(t *testing.T) {
+	data, err := json.Marshal(Session{Name: "main"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"name":"main","windows":0,"panes":0,"idle":false,"active":false,"dirty":false,"needs_input":false}`
+	if string(data) != want {
+		t.Fatalf("json = %s, want %s", data, want)
+	}
+}
+
+func TestRunWritesStoppedOnCancel(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	now := time.Now()
+	writer := newTestWriter(t, path, &now)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		writer.Run(ctx)
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if status, err := Read(path); err == nil && status.State == StateRunning {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Run() did not write a running heartbeat")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	status, err := Read(path)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if status.State != StateStopped {
+		t.Fatalf("State after cancel = %q, want %q", status.State, StateStopped)
+	}
+}
@@ -2,7 +2,9 @@ package inputhistory
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
@@ -626,7 +628,7 @@ func (s *Service) WriteEntry(entry Entry) {
 		return
 	}
 
-	if err := s.ensureScopeLoaded(scope); err != nil {
+	if err := s.ensureScopeLoaded(scope, entry.Session); err != nil {
 		writeDiagnostic("[input-history] dropped entry: load scope %q: %v\n", scope.key, err)
 		return
 	}
@@ -644,7 +646,7 @@ func (s *Service) WriteEntry(entry Entry) {
 	nextSeq := scope.seq + 1
 	entry.Seq = nextSeq
 
-	raw, err := json.Marshal(entry)
+	raw, err := sessioninfo.EncodeRecordLine(entry)
 	if err != nil {
 		s.mu.Unlock()
 		writeDiagnostic("[input-history] failed to marshal entry: %v\n", err)
@@ -766,7 +768,7 @@ func (s *Service) SnapshotForSession(sessionName string) Snapshot {
 		return Snapshot{Entries: []Entry{}}
 	}
 
-	if err := s.ensureScopeLoaded(scope); err != nil {
+	if err := s.ensureScopeLoaded(scope, sessionName); err != nil {
 		slog.Warn("[input-history] failed to load input history scope", "scopeKey", scope.key, "error", err)
 		return Snapshot{ScopeKey: scope.key, Entries: []Entry{}}
 	}
@@ -776,6 +778,78 @@ func (s *Service) SnapshotForSession(sessionName string) Snapshot {
 	return Snapshot{ScopeKey: scope.key, Entries: scope.entries.snapshot()}
 }
 
+// LastInputForPane returns the newest input recorded for paneID in the
+// session's scope, or "" when there is none.
+func (s *Service) LastInputForPane(sessionName, paneID string) string {
+	if s.resolveWorkDir == nil {
+		return ""
+	}
+	scope, err := s.resolveScope(sessionName)
+	if err != nil {
+		return ""
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return scope.entries.lastInput(paneID)
+}
+
+// EntriesForDay reads the entries sessionName recorded on the local calendar
+// day of day from the daily file of workDir's scope. Unlike
+// SnapshotForSession it reads the disk, so it also works after the session
+// has been closed.
+func (s *Service) EntriesForDay(workDir, sessionName string, day time.Time) ([]Entry, error) {
+	configDir, err := s.currentConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	baseDir, err := sessioninfo.DirectoryPath(configDir, workDir)
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(baseDir, Dir, fmt.Sprintf("input-%s.jsonl", day.Format("20060102")))
+	// Read-only: the file may be the open daily file of a live scope, so
+	// corrupt lines are skipped here and repaired by the next scope load.
+	loaded, dropped, _, err := readDailyFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(dropped) > 0 {
+		writeDiagnostic("[input-history] skipped %d corrupt entries in %q\n", len(dropped), path)
+	}
+	all := newRingBuffer(maxEntries)
+	pushEntries(&all, loaded, 0)
+	entries := []Entry{}
+	for _, entry := range all.snapshot() {
+		if entry.Session == sessionName {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// ForgetWorkDir closes the daily file of workDir's scope and drops the
+// scope, so that its directory can be deleted (Windows refuses to delete an
+// open file). A later entry for workDir opens a fresh scope.
+func (s *Service) ForgetWorkDir(workDir string) error {
+	key, err := sessioninfo.FolderKey(workDir)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	scope := s.scopes[key]
+	delete(s.scopes, key)
+	var file *os.File
+	if scope != nil {
+		file = scope.file
+		scope.file = nil
+	}
+	s.mu.Unlock()
+	if file != nil {
+		return file.Close()
+	}
+	return nil
+}
+
 // FilePath returns the current history file path.
 func (s *Service) FilePath() string {
 	s.mu.RLock()
@@ -888,7 +962,7 @@ func (s *Service) scopeForDir(key, historyDir string) *scopeState {
 	return scope
 }
 
-func (s *Service) ensureScopeLoaded(scope *scopeState) error {
+func (s *Service) ensureScopeLoaded(scope *scopeState, sessionName string) error {
 	s.mu.RLock()
 	if scope.loaded {
 		s.mu.RUnlock()
@@ -897,10 +971,13 @@ func (s *Service) ensureScopeLoaded(scope *scopeState) error {
 	dir := scope.dir
 	s.mu.RUnlock()
 
-	entries, maxSeq, loadErrors, err := s.loadScopeFromDisk(dir)
+	entries, maxSeq, recoveries, loadErrors, err := s.loadScopeFromDisk(dir)
 	for _, loadErr := range loadErrors {
 		writeDiagnostic("[input-history] failed to load daily history file: %v\n", loadErr)
 	}
+	for _, recovery := range recoveries {
+		s.reportRecovery(sessionName, recovery)
+	}
 	if err != nil {
 		return err
 	}
@@ -918,13 +995,14 @@ func (s *Service) ensureScopeLoaded(scope *scopeState) error {
 	return nil
 }
 
-func (s *Service) loadScopeFromDisk(dir string) (ringBuffer, uint64, []error, error) {
+func (s *Service) loadScopeFromDisk(dir string) (ringBuffer, uint64, []*sessioninfo.Recovery, []error, error) {
 	entries := newRingBuffer(maxEntries)
 	files, err := listDailyFiles(dir)
 	if err != nil {
-		return entries, 0, nil, err
+		return entries, 0, nil, nil, err
 	}
 
+	var recoveries []*sessioninfo.Recovery
 	var loadErrors []error
 	var maxSeq uint64
 	minDate := dateOnly(s.now()).AddDate(0, 0, -(LoadWindowDays - 1))
@@ -932,22 +1010,92 @@ func (s *Service) loadScopeFromDisk(dir string) (ringBuffer, uint64, []error, er
 		if file.date.Before(minDate) {
 			continue
 		}
-		nextSeq, err := loadDailyFile(&entries, file.path, maxSeq)
+		nextSeq, recovery, err := loadDailyFile(&entries, file.path, maxSeq)
 		maxSeq = nextSeq
+		if recovery != nil {
+			recoveries = append(recoveries, recovery)
+		}
 		if err != nil {
 			loadErrors = append(loadErrors, err)
 		}
 	}
-	return entries, maxSeq, loadErrors, nil
+	return entries, maxSeq, recoveries, loadErrors, nil
+}
+
+// reportRecovery logs and emits a recovery event after loadDailyFile dropped
+// corrupt entries. No-op when recovery is nil.
+func (s *Service) reportRecovery(sessionName string, recovery *sessioninfo.Recovery) {
+	if recovery == nil {
+		return
+	}
+	recovery.SessionName = sessionName
+	writeDiagnostic("[input-history] dropped %d corrupt entries from %q; original kept at %q\n",
+		len(recovery.Dropped), recovery.File, recovery.QuarantinedPath)
+	if s.emitter != nil {
+		s.emitter.Emit(sessioninfo.RecoveredEvent, *recovery)
+	}
+}
+
+// loadDailyFile pushes the entries of the daily file at path onto entries.
+// Entries that fail their checksum or do not parse are dropped: the file is
+// then quarantined for diagnostics and rewritten with the remaining entries,
+// and the returned Recovery lists what was dropped. A file that contains
+// entries from a newer version is only read: its newer entries are skipped
+// and nothing is quarantined or rewritten, so a downgrade keeps the data.
+func loadDailyFile(entries *ringBuffer, path string, maxSeq uint64) (uint64, *sessioninfo.Recovery, error) {
+	loaded, dropped, newer, err := readDailyFile(path)
+	if err != nil {
+		return maxSeq, nil, err
+	}
+
+	var recovery *sessioninfo.Recovery
+	if newer > 0 {
+		writeDiagnostic("[input-history] skipped %d entries from a newer version in %q; file left untouched\n", newer, path)
+		if len(dropped) > 0 {
+			writeDiagnostic("[input-history] skipped %d corrupt entries in %q\n", len(dropped), path)
+		}
+	} else if len(dropped) > 0 {
+		quarantined, err := sessioninfo.QuarantineFile(path, time.Now())
+		if err != nil {
+			// Keep the file untouched; the corrupt entries are skipped again
+			// on the next load.
+			writeDiagnostic("[input-history] failed to quarantine corrupt history file %q: %v\n", path, err)
+		} else {
+			if err := writeDailyFile(path, loaded); err != nil {
+				writeDiagnostic("[input-history] failed to rewrite recovered history file %q: %v\n", path, err)
+			}
+			recovery = &sessioninfo.Recovery{File: path, QuarantinedPath: quarantined, Dropped: dropped}
+		}
+	}
+
+	return pushEntries(entries, loaded, maxSeq), recovery, nil
+}
+
+// pushEntries pushes loaded onto entries, numbering entries without a Seq
+// after maxSeq, and returns the highest Seq seen.
+func pushEntries(entries *ringBuffer, loaded []Entry, maxSeq uint64) uint64 {
+	for _, entry := range loaded {
+		if entry.Seq == 0 {
+			maxSeq++
+			entry.Seq = maxSeq
+		} else if entry.Seq > maxSeq {
+			maxSeq = entry.Seq
+		}
+		entries.push(entry)
+	}
+	return maxSeq
 }
 
-func loadDailyFile(entries *ringBuffer, path string, maxSeq uint64) (uint64, error) {
+// readDailyFile decodes the daily file at path. Lines that fail verification
+// are returned in dropped, indexed by line number from 0; newer counts lines
+// written by a newer version, which are skipped without being dropped.
+func readDailyFile(path string) (loaded []Entry, dropped []sessioninfo.DroppedRecord, newer int, err error) {
 	file, err := os.Open(path)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return maxSeq, nil
+			return nil, nil, 0, nil
 		}
-		return maxSeq, err
+		return nil, nil, 0, err
 	}
 	defer func() {
 		if err := file.Close(); err != nil {
@@ -957,24 +1105,49 @@ func loadDailyFile(entries *ringBuffer, path string, maxSeq uint64) (uint64, err
 
 	scanner := bufio.NewScanner(file)
 	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
-	for scanner.Scan() {
-		var entry Entry
-		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
-			writeDiagnostic("[input-history] skipped malformed history line in %q: %v\n", path, err)
+	for index := 0; scanner.Scan(); index++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
 			continue
 		}
-		if entry.Seq == 0 {
-			maxSeq++
-			entry.Seq = maxSeq
-		} else if entry.Seq > maxSeq {
-			maxSeq = entry.Seq
+		entry, err := sessioninfo.DecodeRecordLine[Entry](line)
+		if errors.Is(err, sessioninfo.ErrNewerRecordVersion) {
+			newer++
+			continue
 		}
-		entries.push(entry)
+		if err != nil {
+			dropped = append(dropped, sessioninfo.DroppedRecord{Index: index, Reason: err.Error()})
+			continue
+		}
+		loaded = append(loaded, entry)
 	}
 	if err := scanner.Err(); err != nil {
-		return maxSeq, err
+		return nil, nil, 0, err
 	}
-	return maxSeq, nil
+	return loaded, dropped, newer, nil
+}
+
+// writeDailyFile replaces the daily file at path with entries.
+func writeDailyFile(path string, entries []Entry) error {
+	var buf bytes.Buffer
+	for _, entry := range entries {
+		line, err := sessioninfo.EncodeRecordLine(entry)
+		if err != nil {
+			return err
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("write temp file: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		// Clean up temp file on rename failure (best-effort).
+		os.Remove(tmp)
+		return fmt.Errorf("rename temp file: %w", err)
+	}
+	return nil
 }
 
 func (s *Service) ensureDailyFileLocked(scope *scopeState) error {
@@ -314,9 +314,9 @@ func TestWriteEntry_EmptySessionUsesFallbackDailyFile(t *testing.T) {
 	if err != nil {
 		t.Fatalf("ReadFile(fallback): %v", err)
 	}
-	var parsed Entry
-	if err := json.Unmarshal([]byte(strings.TrimSpace(string(content))), &parsed); err != nil {
-		t.Fatalf("Unmarshal(fallback): %v", err)
+	parsed, err := sessioninfo.DecodeRecordLine[Entry]([]byte(strings.TrimSpace(string(content))))
+	if err != nil {
+		t.Fatalf("DecodeRecordLine(fallback): %v", err)
 	}
 	if parsed.Input != "fallback" {
 		t.Fatalf("fallback input = %q, want fallback", parsed.Input)
@@ -495,6 +495,122 @@ func TestSnapshotForSession_PreservesPersistedSeq(t *testing.T) {
 	}
 }
 
+func TestSnapshotForSession_QuarantinesCorruptDailyFile(t *testing.T) {
+	configDir := t.TempDir()
+	workDir := filepath.Join(t.TempDir(), "workspace")
+	now := time.Date(2026, 5, 16, 12, 0, 0, 0, time.Local)
+	baseDir, err := sessioninfo.DirectoryPath(configDir, workDir)
+	if err != nil {
+		t.Fatalf("DirectoryPath(): %v", err)
+	}
+	historyDir := filepath.Join(baseDir, Dir)
+	kept, err := sessioninfo.EncodeRecordLine(Entry{Seq: 1, Input: "kept", Timestamp: "20260516120000", Session: "session-a"})
+	if err != nil {
+		t.Fatalf("EncodeRecordLine(kept): %v", err)
+	}
+	tampered, err := sessioninfo.EncodeRecordLine(Entry{Seq: 2, Input: "tampered", Timestamp: "20260516120100", Session: "session-a"})
+	if err != nil {
+		t.Fatalf("EncodeRecordLine(tampered): %v", err)
+	}
+	writeDailyHistoryFile(t, historyDir, "20260516", []string{
+		string(kept),
+		strings.Replace(string(tampered), "tampered", "tempered", 1),
+		`{"sha256":"`,
+	})
+
+	em := &mockEmitter{}
+	svc := NewService(em, func() bool { return false },
+		WithSessionScopeResolver(func(sessionName string) (string, error) {
+			return workDir, nil
+		}, func() (string, error) {
+			return configDir, nil
+		}),
+		WithClock(func() time.Time { return now }),
+	)
+	defer svc.Close()
+
+	snapshot := svc.SnapshotForSession("session-a")
+	if len(snapshot.Entries) != 1 || snapshot.Entries[0].Input != "kept" {
+		t.Fatalf("snapshot entries = %+v, want only kept", snapshot.Entries)
+	}
+
+	em.mu.Lock()
+	calls := append([]string(nil), em.calls...)
+	em.mu.Unlock()
+	if len(calls) != 1 || calls[0] != sessioninfo.RecoveredEvent {
+		t.Fatalf("emitted events = %v, want [%s]", calls, sessioninfo.RecoveredEvent)
+	}
+
+	path := filepath.Join(historyDir, "input-20260516.jsonl")
+	quarantined, err := filepath.Glob(path + ".corrupt-*")
+	if err != nil || len(quarantined) != 1 {
+		t.Fatalf("quarantined files = %v (err=%v), want 1", quarantined, err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile(rewritten): %v", err)
+	}
+	if got := strings.TrimSpace(string(content)); got != string(kept) {
+		t.Fatalf("rewritten file = %q, want only the kept line", got)
+	}
+}
+
+func TestSnapshotForSession_LeavesNewerVersionFileUntouched(t *testing.T) {
+	configDir := t.TempDir()
+	workDir := filepath.Join(t.TempDir(), "workspace")
+	now := time.Date(2026, 5, 16, 12, 0, 0, 0, time.Local)
+	baseDir, err := sessioninfo.DirectoryPath(configDir, workDir)
+	if err != nil {
+		t.Fatalf("DirectoryPath(): %v", err)
+	}
+	historyDir := filepath.Join(baseDir, Dir)
+	kept, err := sessioninfo.EncodeRecordLine(Entry{Seq: 1, Input: "kept", Timestamp: "20260516120000", Session: "session-a"})
+	if err != nil {
+		t.Fatalf("EncodeRecordLine(kept): %v", err)
+	}
+	writeDailyHistoryFile(t, historyDir, "20260516", []string{
+		string(kept),
+		`{"v":2,"sha256":"future","data":{"input":"from a newer build"}}`,
+		`{"sha256":"`,
+	})
+	path := filepath.Join(historyDir, "input-20260516.jsonl")
+	before, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile(before): %v", err)
+	}
+
+	em := &mockEmitter{}
+	svc := NewService(em, func() bool { return false },
+		WithSessionScopeResolver(func(sessionName string) (string, error) {
+			return workDir, nil
+		}, func() (string, error) {
+			return configDir, nil
+		}),
+		WithClock(func() time.Time { return now }),
+	)
+	defer svc.Close()
+
+	snapshot := svc.SnapshotForSession("session-a")
+	if len(snapshot.Entries) != 1 || snapshot.Entries[0].Input != "kept" {
+		t.Fatalf("snapshot entries = %+v, want only kept", snapshot.Entries)
+	}
+	after, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile(after): %v", err)
+	}
+	if string(after) != string(before) {
+		t.Fatalf("file was rewritten:\n%s", after)
+	}
+	if quarantined, _ := filepath.Glob(path + ".corrupt-*"); len(quarantined) != 0 {
+		t.Fatalf("quarantined files = %v, want none", quarantined)
+	}
+	em.mu.Lock()
+	defer em.mu.Unlock()
+	if len(em.calls) != 0 {
+		t.Fatalf("emitted events = %v, want none", em.calls)
+	}
+}
+
 func TestCleanupOldFiles_DeletesExpiredSessionInfoDailyFiles(t *testing.T) {
 	configDir := t.TempDir()
 	workDir := filepath.Join(t.TempDir(), "workspace")
@@ -799,3 +915,104 @@ func TestLineBuffer_StopTimer_StopsAndNils(t *testing.T) {
 		t.Error("expected timer to be nil after stopTimer")
 	}
 }
+
+func TestEntriesForDay_ReadsSessionEntriesFromDisk(t *testing.T) {
+	configDir := t.TempDir()
+	workDir := filepath.Join(t.TempDir(), "workspace")
+	baseDir, err := sessioninfo.DirectoryPath(configDir, workDir)
+	if err != nil {
+		t.Fatalf("DirectoryPath(): %v", err)
+	}
+	historyDir := filepath.Join(baseDir, Dir)
+	if err := os.MkdirAll(historyDir, 0o700); err != nil {
+		t.Fatalf("MkdirAll(): %v", err)
+	}
+	writeDailyHistoryFile(t, historyDir, "20260516", []string{
+		`{"input":"go test ./...","ts":"20260516120000","pane_id":"%1","source":"keyboard","session":"session-a"}`,
+		`{"input":"other","ts":"20260516120100","pane_id":"%2","source":"keyboard","session":"session-b"}`,
+	})
+
+	// The session is no longer resolvable; the entries come from disk.
+	svc := NewService(nil, nil,
+		WithSessionScopeResolver(func(sessionName string) (string, error) {
+			return "", fmt.Errorf("unknown session %s", sessionName)
+		}, func() (string, error) {
+			return configDir, nil
+		}),
+	)
+	defer svc.Close()
+
+	entries, err := svc.EntriesForDay(workDir, "session-a", time.Date(2026, 5, 16, 23, 0, 0, 0, time.Local))
+	if err != nil {
+		t.Fatalf("EntriesForDay() error = %v", err)
+	}
+	if len(entries) != 1 || entries[0].Input != "go test ./..." {
+		t.Fatalf("EntriesForDay() = %+v, want only session-a's entry", entries)
+	}
+
+	entries, err = svc.EntriesForDay(workDir, "session-a", time.Date(2026, 5, 15, 12, 0, 0, 0, time.Local))
+	if err != nil || len(entries) != 0 {
+		t.Fatalf("EntriesForDay() for a day without a file = %+v, %v; want none", entries, err)
+	}
+}
+
+func TestForgetWorkDir_ReleasesScopeFile(t *testing.T) {
+	configDir := t.TempDir()
+	workDir := filepath.Join(t.TempDir(), "workspace")
+	svc := NewService(nil, nil,
+		WithSessionScopeResolver(func(string) (string, error) {
+			return workDir, nil
+		}, func() (string, error) {
+			return configDir, nil
+		}),
+	)
+	defer svc.Close()
+
+	svc.WriteEntry(Entry{Timestamp: "20260516090000", Input: "first", Session: "session-a"})
+	key, err := sessioninfo.FolderKey(workDir)
+	if err != nil {
+		t.Fatalf("FolderKey(): %v", err)
+	}
+	svc.mu.RLock()
+	scope := svc.scopes[key]
+	svc.mu.RUnlock()
+	if scope == nil || scope.file == nil {
+		t.Fatal("expected an open scope file after WriteEntry")
+	}
+
+	if err := svc.ForgetWorkDir(workDir); err != nil {
+		t.Fatalf("ForgetWorkDir() error = %v", err)
+	}
+	svc.mu.RLock()
+	_, ok := svc.scopes[key]
+	svc.mu.RUnlock()
+	if ok || scope.file != nil {
+		t.Fatal("ForgetWorkDir() should close and drop the scope")
+	}
+	if err := svc.ForgetWorkDir(workDir); err != nil {
+		t.Fatalf("ForgetWorkDir() for an unknown scope error = %v", err)
+	}
+}
+
+func TestLastInputForPane(t *testing.T) {
+	configDir := t.TempDir()
+	workDir := filepath.Join(t.TempDir(), "workspace")
+	svc := NewService(nil, nil,
+		WithSessionScopeResolver(func(sessionName string) (string, error) {
+			return workDir, nil
+		}, func() (string, error) {
+			return configDir, nil
+		}),
+	)
+	defer svc.Close()
+
+	svc.WriteEntry(Entry{Timestamp: "20260516090000", PaneID: "%1", Input: "go test ./...", Session: "session-a"})
+	svc.WriteEntry(Entry{Timestamp: "20260516090100", PaneID: "%2", Input: "ls", Session: "session-a"})
+
+	if got := svc.LastInputForPane("session-a", "%1"); got != "go test ./..." {
+		t.Fatalf("LastInputForPane(%%1) = %q", got)
+	}
+	if got := svc.LastInputForPane("session-a", "%9"); got != "" {
+		t.Fatalf("LastInputForPane(%%9) = %q, want empty", got)
+	}
+}
@@ -174,3 +174,14 @@ func (rb *ringBuffer) snapshot() []Entry {
 	}
 	return out
 }
+
+// lastInput returns the input of the newest entry of paneID, or "".
+func (rb *ringBuffer) lastInput(paneID string) string {
+	bufCap := len(rb.buf)
+	for i := rb.count - 1; i >= 0; i-- {
+		if entry := rb.buf[(rb.head+i)%bufCap]; entry.PaneID == paneID {
+			return entry.Input
+		}
+	}
+	return ""
+}
@@ -0,0 +1,343 @@
+package install
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// PathFindingSeverity ranks a PATH doctor finding.
+type PathFindingSeverity string
+
+const (
+	PathFindingInfo    PathFindingSeverity = "info"
+	PathFindingWarning PathFindingSeverity = "warning"
+	// PathFindingError means tmux commands in panes will not reach the shim.
+	PathFindingError PathFindingSeverity = "error"
+)
+
+// Tmux candidate sources, derived from the directory a tmux executable lives in.
+const (
+	TmuxSourceShim        = "myt-x"
+	TmuxSourceGitBash     = "git-bash"
+	TmuxSourceMSYS2       = "msys2"
+	TmuxSourceCygwin      = "cygwin"
+	TmuxSourceScoop       = "scoop"
+	TmuxSourceChocolatey  = "chocolatey"
+	TmuxSourceWindowsApps = "windows-apps"
+	TmuxSourceOther       = "other"
+)
+
+// TmuxCandidate is a tmux executable found on the process PATH.
+type TmuxCandidate struct {
+	Path   string `json:"path"`
+	Source string `json:"source"`
+	// PathIndex is the position of the containing directory in PATH.
+	PathIndex int `json:"path_index"`
+	// ShadowsShim is true when the candidate comes before the myT-x shim on
+	// PATH (or the shim is not on PATH at all).
+	ShadowsShim bool `json:"shadows_shim"`
+}
+
+// PathFinding is one actionable result of the PATH doctor.
+type PathFinding struct {
+	Code     string              `json:"code"`
+	Severity PathFindingSeverity `json:"severity"`
+	Message  string              `json:"message"`
+	// Fix is a short instruction that resolves the finding.
+	Fix  string `json:"fix,omitempty"`
+	Path string `json:"path,omitempty"`
+}
+
+// PathDoctorReport is the result of RunPathDoctor.
+type PathDoctorReport struct {
+	ShimPath          string          `json:"shim_path"`
+	ShimInstalled     bool            `json:"shim_installed"`
+	ShimOnProcessPath bool            `json:"shim_on_process_path"`
+	ShimOnUserPath    bool            `json:"shim_on_user_path"`
+	Candidates        []TmuxCandidate `json:"candidates"`
+	Findings          []PathFinding   `json:"findings"`
+}
+
+// tmuxExecutableNames are the file names that resolve a bare "tmux" command
+// in cmd.exe/PowerShell (PATHEXT) and in MSYS-based bash ("tmux").
+var tmuxExecutableNames = []string{"tmux.exe", "tmux.cmd", "tmux.bat", "tmux.ps1", "tmux"}
+
+var (
+	// tmuxAliasPattern matches PowerShell and bash definitions that make
+	// "tmux" an alias or function.
+	tmuxAliasPattern = regexp.MustCompile(`(?i)^\s*(?:` +
+		`(?:set-alias|new-alias|sal|nal)\s+(?:-name\s+)?['"]?tmux['"]?(?:\s|$)` +
+		`|function\s+(?:global:)?tmux\b` +
+		`|alias\s+tmux=` +
+		`|tmux\s*\(\s*\)` +
+		`)`)
+	wslCommandPattern = regexp.MustCompile(`(?i)\bwsl(?:\.exe)?\b`)
+)
+
+// aliasLookahead is how many lines after a tmux alias or function header
+// are searched for a wsl invocation.
+const aliasLookahead = 3
+
+// pathDoctorInput holds everything diagnosePath inspects, so the
+// diagnosis itself is platform-independent.
+type pathDoctorInput struct {
+	shimDir     string
+	processPath string
+	userPath    string
+	userPathErr error
+	// shell is the configured pane shell (config "shell").
+	shell string
+	// profiles are shell startup scripts checked for tmux aliases.
+	profiles   []string
+	fileExists func(path string) bool
+	readFile   func(path string) ([]byte, error)
+}
+
+func diagnosePath(in pathDoctorInput) PathDoctorReport {
+	shimPath := joinWindowsPath(in.shimDir, "tmux.exe")
+	shimKey := normalizeWindowsPath(in.shimDir)
+	report := PathDoctorReport{
+		ShimPath:      shimPath,
+		ShimInstalled: in.fileExists(shimPath),
+		Candidates:    []TmuxCandidate{},
+		Findings:      []PathFinding{},
+	}
+	add := func(finding PathFinding) {
+		report.Findings = append(report.Findings, finding)
+	}
+
+	if !report.ShimInstalled {
+		add(PathFinding{
+			Code:     "shim_missing",
+			Severity: PathFindingError,
+			Message:  "The myT-x tmux shim is not installed.",
+			Fix:      "Run \"Install tmux shim\" or restart myT-x.",
+			Path:     shimPath,
+		})
+	}
+
+	shimIndex := -1
+	for i, dir := range splitPathList(in.processPath) {
+		key := normalizeWindowsPath(dir)
+		if key == shimKey {
+			if shimIndex < 0 {
+				shimIndex = i
+			}
+			continue
+		}
+		for _, name := range tmuxExecutableNames {
+			path := joinWindowsPath(dir, name)
+			if !in.fileExists(path) {
+				continue
+			}
+			report.Candidates = append(report.Candidates, TmuxCandidate{
+				Path:        path,
+				Source:      classifyTmuxSource(dir),
+				PathIndex:   i,
+				ShadowsShim: shimIndex < 0,
+			})
+		}
+	}
+	report.ShimOnProcessPath = shimIndex >= 0
+	if !report.ShimOnProcessPath {
+		add(PathFinding{
+			Code:     "shim_not_on_process_path",
+			Severity: PathFindingError,
+			Message:  "The shim directory is not on the PATH that panes inherit.",
+			Fix:      "Restart myT-x so it can add the shim directory to PATH.",
+			Path:     in.shimDir,
+		})
+	}
+
+	switch {
+	case in.userPathErr != nil:
+		add(PathFinding{
+			Code:     "user_path_unreadable",
+			Severity: PathFindingWarning,
+			Message:  fmt.Sprintf("The user PATH could not be read: %v", in.userPathErr),
+		})
+	case containsWindowsPathEntry(in.userPath, in.shimDir):
+		report.ShimOnUserPath = true
+	default:
+		add(PathFinding{
+			Code:     "shim_not_on_user_path",
+			Severity: PathFindingWarning,
+			Message:  "The shim directory is not on the user PATH, so terminals started outside myT-x cannot find it.",
+			Fix:      "Run \"Install tmux shim\" to register the directory.",
+			Path:     in.shimDir,
+		})
+	}
+
+	for _, candidate := range report.Candidates {
+		add(candidateFinding(candidate, in.shimDir))
+	}
+	for _, profile := range in.profiles {
+		if finding, ok := profileAliasFinding(profile, in.readFile); ok {
+			add(finding)
+		}
+	}
+	if finding, ok := shellFinding(in.shell); ok {
+		add(finding)
+	}
+	return report
+}
+
+func candidateFinding(candidate TmuxCandidate, shimDir string) PathFinding {
+	sep := strings.LastIndexByte(candidate.Path, '\\')
+	dir, name := candidate.Path[:sep], candidate.Path[sep+1:]
+	// cmd.exe and PowerShell only run files with a PATHEXT extension; an
+	// extensionless tmux is picked up by bash alone.
+	extensionless := !strings.Contains(name, ".")
+	if candidate.ShadowsShim && !extensionless {
+		return PathFinding{
+			Code:     "tmux_shadows_shim",
+			Severity: PathFindingError,
+			Message:  fmt.Sprintf("%s (%s) comes before the myT-x shim on PATH; tmux commands in panes run it instead.", candidate.Path, candidate.Source),
+			Fix:      shadowFix(candidate, dir, shimDir),
+			Path:     candidate.Path,
+		}
+	}
+	// MSYS-based bash login shells prepend /usr/bin to PATH, so their tmux
+	// wins in bash panes even when the Windows PATH order is right.
+	msys := candidate.Source == TmuxSourceGitBash || candidate.Source == TmuxSourceMSYS2 ||
+		candidate.Source == TmuxSourceCygwin
+	if candidate.ShadowsShim || msys {
+		return PathFinding{
+			Code:     "bash_tmux_shadows_shim",
+			Severity: PathFindingWarning,
+			Message:  fmt.Sprintf("%s (%s) shadows the shim in bash panes.", candidate.Path, candidate.Source),
+			Fix:      "Rename or remove this tmux, or use PowerShell or cmd panes.",
+			Path:     candidate.Path,
+		}
+	}
+	return PathFinding{
+		Code:     "tmux_other_installation",
+		Severity: PathFindingInfo,
+		Message:  fmt.Sprintf("%s (%s) is on PATH after the shim and is not used by panes.", candidate.Path, candidate.Source),
+		Path:     candidate.Path,
+	}
+}
+
+func shadowFix(candidate TmuxCandidate, dir, shimDir string) string {
+	switch candidate.Source {
+	case TmuxSourceScoop:
+		return "Run \"scoop uninstall tmux\", or move " + shimDir + " before " + dir + " in PATH."
+	case TmuxSourceChocolatey:
+		return "Run \"choco uninstall tmux\", or move " + shimDir + " before " + dir + " in PATH."
+	case TmuxSourceWindowsApps:
+		return "Turn off the tmux alias in Settings > Apps > Advanced app settings > App execution aliases."
+	default:
+		return "Move " + shimDir + " before " + dir + " in PATH, or remove " + dir + " from PATH."
+	}
+}
+
+// profileAliasFinding reports a tmux alias or function in a shell profile.
+// Aliases that call wsl are the common "tmux runs WSL tmux" setup.
+func profileAliasFinding(profile string, readFile func(string) ([]byte, error)) (PathFinding, bool) {
+	data, err := readFile(profile)
+	if err != nil {
+		return PathFinding{}, false
+	}
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") || !tmuxAliasPattern.MatchString(line) {
+			continue
+		}
+		window := strings.Join(lines[i:min(len(lines), i+1+aliasLookahead)], "\n")
+		if wslCommandPattern.MatchString(window) {
+			return PathFinding{
+				Code:     "wsl_tmux_alias",
+				Severity: PathFindingError,
+				Message:  fmt.Sprintf("%s (line %d) redirects tmux to WSL, so panes using this shell bypass the shim.", profile, i+1),
+				Fix:      "Remove the alias or function, or skip it when TMUX_PANE is set (myT-x panes set it).",
+				Path:     profile,
+			}, true
+		}
+		return PathFinding{
+			Code:     "tmux_alias",
+			Severity: PathFindingWarning,
+			Message:  fmt.Sprintf("%s (line %d) defines tmux as an alias or function, which takes precedence over PATH.", profile, i+1),
+			Fix:      "Remove the alias or function so tmux resolves to the shim.",
+			Path:     profile,
+		}, true
+	}
+	return PathFinding{}, false
+}
+
+// shellFinding reports pane shells that run inside WSL, where tmux resolves
+// to the Linux tmux and the Windows shim is unreachable.
+func shellFinding(shell string) (PathFinding, bool) {
+	fields := strings.Fields(shell)
+	if len(fields) == 0 {
+		return PathFinding{}, false
+	}
+	exe := normalizeWindowsPath(fields[0])
+	exe = exe[strings.LastIndexByte(exe, '\\')+1:]
+	if exe != "wsl" && exe != "wsl.exe" && !strings.HasSuffix(normalizeWindowsPath(fields[0]), `\system32\bash.exe`) {
+		return PathFinding{}, false
+	}
+	return PathFinding{
+		Code:     "wsl_shell",
+		Severity: PathFindingWarning,
+		Message:  fmt.Sprintf("The pane shell %q runs inside WSL, where tmux is the Linux tmux, not the myT-x shim.", shell),
+		Fix:      "Use PowerShell, cmd, or Git Bash for panes that should use myT-x tmux features.",
+	}, true
+}
+
+func classifyTmuxSource(dir string) string {
+	key := normalizeWindowsPath(dir)
+	switch {
+	case strings.Contains(key, `\scoop\`):
+		return TmuxSourceScoop
+	case strings.Contains(key, `\chocolatey\`):
+		return TmuxSourceChocolatey
+	case strings.Contains(key, `\microsoft\windowsapps`):
+		return TmuxSourceWindowsApps
+	case strings.Contains(key, `\msys64\`), strings.Contains(key, `\msys32\`):
+		return TmuxSourceMSYS2
+	case strings.Contains(key, `\cygwin`):
+		return TmuxSourceCygwin
+	case strings.Contains(key, `\git\`):
+		return TmuxSourceGitBash
+	default:
+		return TmuxSourceOther
+	}
+}
+
+// splitPathList splits a Windows PATH value, dropping empty entries and
+// surrounding quotes.
+func splitPathList(pathValue string) []string {
+	var dirs []string
+	for item := range strings.SplitSeq(pathValue, ";") {
+		item = strings.Trim(strings.TrimSpace(item), `"`)
+		if item != "" {
+			dirs = append(dirs, item)
+		}
+	}
+	return dirs
+}
+
+// containsWindowsPathEntry is a case- and separator-insensitive PATH lookup
+// that works on every build platform (containsPathEntry is Windows-only).
+func containsWindowsPathEntry(pathValue, entry string) bool {
+	key := normalizeWindowsPath(entry)
+	if key == "" {
+		return false
+	}
+	for _, dir := range splitPathList(pathValue) {
+		if normalizeWindowsPath(dir) == key {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeWindowsPath(path string) string {
+	path = strings.ReplaceAll(strings.TrimSpace(path), "/", `\`)
+	return strings.ToLower(strings.TrimRight(path, `\`))
+}
+
+func joinWindowsPath(dir, name string) string {
+	return strings.TrimRight(dir, `\/`) + `\` + name
+}
@@ -0,0 +1,15 @@
+//go:build !windows
+
+package install
+
+// RunPathDoctor reports that PATH diagnosis is Windows-only.
+func RunPathDoctor(_ string) (PathDoctorReport, error) {
+	return PathDoctorReport{
+		Candidates: []TmuxCandidate{},
+		Findings: []PathFinding{{
+			Code:     "unsupported_platform",
+			Severity: PathFindingInfo,
+			Message:  "The PATH doctor is available only on Windows.",
+		}},
+	}, nil
+}
@@ -0,0 +1,132 @@
+package install
+
+import (
+	"errors"
+	"os"
+	"slices"
+	"testing"
+)
+
+const testShimDir = `C:\Users\me\AppData\Local\myT-x\bin`
+
+func doctorInput(processPath string, files ...string) pathDoctorInput {
+	existing := map[string]bool{}
+	for _, file := range files {
+		existing[file] = true
+	}
+	return pathDoctorInput{
+		shimDir:     testShimDir,
+		processPath: processPath,
+		userPath:    testShimDir,
+		fileExists:  func(path string) bool { return existing[path] },
+		readFile:    func(string) ([]byte, error) { return nil, os.ErrNotExist },
+	}
+}
+
+func findingCodes(report PathDoctorReport) []string {
+	codes := make([]string, 0, len(report.Findings))
+	for _, finding := range report.Findings {
+		codes = append(codes, finding.Code)
+	}
+	return codes
+}
+
+func TestDiagnosePathHealthy(t *testing.T) {
+	report := diagnosePath(doctorInput(`C:\Windows\system32;`+testShimDir+`\`, testShimDir+`\tmux.exe`))
+	if !report.ShimInstalled || !report.ShimOnProcessPath || !report.ShimOnUserPath {
+		t.Fatalf("report = %+v, want shim installed and on both PATHs", report)
+	}
+	if len(report.Findings) != 0 {
+		t.Fatalf("findings = %v, want none", findingCodes(report))
+	}
+}
+
+func TestDiagnosePathCandidates(t *testing.T) {
+	const (
+		scoop   = `C:\Users\me\scoop\shims`
+		gitBash = `C:\Program Files\Git\usr\bin`
+		other   = `D:\tools`
+	)
+	report := diagnosePath(doctorInput(
+		scoop+";"+testShimDir+";"+gitBash+";"+other,
+		testShimDir+`\tmux.exe`, scoop+`\tmux.exe`, gitBash+`\tmux.exe`, other+`\tmux.cmd`,
+	))
+
+	want := []TmuxCandidate{
+		{Path: scoop + `\tmux.exe`, Source: TmuxSourceScoop, PathIndex: 0, ShadowsShim: true},
+		{Path: gitBash + `\tmux.exe`, Source: TmuxSourceGitBash, PathIndex: 2},
+		{Path: other + `\tmux.cmd`, Source: TmuxSourceOther, PathIndex: 3},
+	}
+	if !slices.Equal(report.Candidates, want) {
+		t.Fatalf("candidates = %+v, want %+v", report.Candidates, want)
+	}
+	wantCodes := []string{"tmux_shadows_shim", "bash_tmux_shadows_shim", "tmux_other_installation"}
+	if got := findingCodes(report); !slices.Equal(got, wantCodes) {
+		t.Fatalf("findings = %v, want %v", got, wantCodes)
+	}
+	if report.Findings[0].Severity != PathFindingError {
+		t.Fatalf("shadow severity = %q, want error", report.Findings[0].Severity)
+	}
+}
+
+func TestDiagnosePathExtensionlessTmuxOnlyShadowsBash(t *testing.T) {
+	report := diagnosePath(doctorInput(`D:\bin;`+testShimDir, testShimDir+`\tmux.exe`, `D:\bin\tmux`))
+	if got := findingCodes(report); !slices.Equal(got, []string{"bash_tmux_shadows_shim"}) {
+		t.Fatalf("findings = %v, want bash_tmux_shadows_shim only", got)
+	}
+}
+
+func TestDiagnosePathShimProblems(t *testing.T) {
+	in := doctorInput(`C:\Windows\system32`)
+	in.userPathErr = errors.New("access denied")
+	report := diagnosePath(in)
+	want := []string{"shim_missing", "shim_not_on_process_path", "user_path_unreadable"}
+	if got := findingCodes(report); !slices.Equal(got, want) {
+		t.Fatalf("findings = %v, want %v", got, want)
+	}
+
+	in = doctorInput(testShimDir, testShimDir+`\tmux.exe`)
+	in.userPath = `C:\other`
+	if got := findingCodes(diagnosePath(in)); !slices.Equal(got, []string{"shim_not_on_user_path"}) {
+		t.Fatalf("findings = %v, want shim_not_on_user_path", got)
+	}
+}
+
+func TestDiagnosePathProfileAliases(t *testing.T) {
+	profiles := map[string]string{
+		`ps.ps1`:   "# Set-Alias tmux foo\nfunction tmux {\n    wsl.exe tmux @args\n}\n",
+		`.bashrc`:  "alias ll='ls -l'\nalias tmux='tmux -2'\n",
+		`.profile`: "export EDITOR=vim\n",
+	}
+	in := doctorInput(testShimDir, testShimDir+`\tmux.exe`)
+	in.profiles = []string{`ps.ps1`, `.bashrc`, `.profile`, `missing.ps1`}
+	in.readFile = func(path string) ([]byte, error) {
+		content, ok := profiles[path]
+		if !ok {
+			return nil, os.ErrNotExist
+		}
+		return []byte(content), nil
+	}
+	report := diagnosePath(in)
+	if got := findingCodes(report); !slices.Equal(got, []string{"wsl_tmux_alias", "tmux_alias"}) {
+		t.Fatalf("findings = %v, want wsl_tmux_alias then tmux_alias", got)
+	}
+	if report.Findings[0].Path != `ps.ps1` || report.Findings[1].Path != `.bashrc` {
+		t.Fatalf("finding paths = %q, %q", report.Findings[0].Path, report.Findings[1].Path)
+	}
+}
+
+func TestDiagnosePathWSLShell(t *testing.T) {
+	for _, shell := range []string{"wsl.exe", `C:\Windows\System32\wsl.exe -d Ubuntu`, `C:\Windows\System32\bash.exe`} {
+		in := doctorInput(testShimDir, testShimDir+`\tmux.exe`)
+		in.shell = shell
+		if got := findingCodes(diagnosePath(in)); !slices.Equal(got, []string{"wsl_shell"}) {
+			t.Fatalf("shell %q findings = %v, want wsl_shell", shell, got)
+		}
+	}
+	in := doctorInput(testShimDir, testShimDir+`\tmux.exe`)
+	in.shell = `C:\Program Files\Git\bin\bash.exe`
+	if got := findingCodes(diagnosePath(in)); len(got) != 0 {
+		t.Fatalf("git bash shell findings = %v, want none", got)
+	}
+}
@@ -0,0 +1,56 @@
+//go:build windows
+
+package install
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/sys/windows"
+)
+
+// RunPathDoctor inspects the process PATH, the user PATH, shell profiles,
+// and the configured pane shell for setups where a bare "tmux" in a pane
+// does not reach the myT-x shim.
+func RunPathDoctor(shell string) (PathDoctorReport, error) {
+	shimDir, err := ResolveInstallDir()
+	if err != nil {
+		return PathDoctorReport{}, err
+	}
+	userPath, userPathErr := readUserPathFromRegistry()
+	return diagnosePath(pathDoctorInput{
+		shimDir:     shimDir,
+		processPath: os.Getenv("PATH"),
+		userPath:    userPath,
+		userPathErr: userPathErr,
+		shell:       shell,
+		profiles:    shellProfilePaths(),
+		fileExists: func(path string) bool {
+			info, err := os.Stat(path)
+			return err == nil && !info.IsDir()
+		},
+		readFile: os.ReadFile,
+	}), nil
+}
+
+// shellProfilePaths returns the PowerShell and bash startup scripts of the
+// current user. Documents is resolved through the known-folder API because
+// it is often redirected to OneDrive.
+func shellProfilePaths() []string {
+	var profiles []string
+	if documents, err := windows.KnownFolderPath(windows.FOLDERID_Documents, 0); err == nil {
+		for _, edition := range []string{"PowerShell", "WindowsPowerShell"} {
+			profiles = append(profiles,
+				filepath.Join(documents, edition, "Microsoft.PowerShell_profile.ps1"),
+				filepath.Join(documents, edition, "profile.ps1"),
+			)
+		}
+	}
+	if home := strings.TrimSpace(os.Getenv("USERPROFILE")); home != "" {
+		for _, name := range []string{".bashrc", ".bash_profile", ".profile"} {
+			profiles = append(profiles, filepath.Join(home, name))
+		}
+	}
+	return profiles
+}
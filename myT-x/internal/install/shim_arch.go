@@ -0,0 +1,33 @@
+package install
+
+// Shim architectures shipped in the embedded bundle. Values match GOARCH so
+// the build (GOARCH=<arch> go build) and runtime selection share one name.
+const (
+	ShimArchAMD64 = "amd64"
+	ShimArchARM64 = "arm64"
+)
+
+// PE machine types reported by IsWow64Process2 (IMAGE_FILE_MACHINE_*).
+const (
+	imageFileMachineAMD64 uint16 = 0x8664
+	imageFileMachineARM64 uint16 = 0xAA64
+)
+
+// shimArchForMachine maps a PE machine type to a shim architecture.
+// Unknown machines return "" so callers fall back to the process architecture.
+func shimArchForMachine(machine uint16) string {
+	switch machine {
+	case imageFileMachineAMD64:
+		return ShimArchAMD64
+	case imageFileMachineARM64:
+		return ShimArchARM64
+	default:
+		return ""
+	}
+}
+
+// shimBinaryName returns the architecture-specific shim file name produced
+// by make build-shim (e.g. tmux-shim-arm64.exe).
+func shimBinaryName(arch string) string {
+	return "tmux-shim-" + arch + ".exe"
+}
@@ -0,0 +1,29 @@
+package install
+
+import "testing"
+
+func TestShimArchForMachine(t *testing.T) {
+	tests := []struct {
+		name    string
+		machine uint16
+		want    string
+	}{
+		{"amd64", imageFileMachineAMD64, ShimArchAMD64},
+		{"arm64", imageFileMachineARM64, ShimArchARM64},
+		{"i386 is unsupported", 0x014c, ""},
+		{"unknown", 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := shimArchForMachine(tt.machine); got != tt.want {
+				t.Fatalf("shimArchForMachine(%#x) = %q, want %q", tt.machine, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShimBinaryName(t *testing.T) {
+	if got := shimBinaryName(ShimArchARM64); got != "tmux-shim-arm64.exe" {
+		t.Fatalf("shimBinaryName(arm64) = %q, want tmux-shim-arm64.exe", got)
+	}
+}
@@ -0,0 +1,33 @@
+//go:build windows
+
+package install
+
+import (
+	"log/slog"
+	"runtime"
+
+	"golang.org/x/sys/windows"
+)
+
+// hostShimArchFn is replaceable in tests.
+var hostShimArchFn = hostShimArch
+
+// hostShimArch returns the native architecture of the host and whether this
+// process runs under emulation (x64 myT-x on an ARM64 laptop).
+//
+// IsWow64Process2 reports the native machine even for x64-on-ARM64 emulation,
+// which is not WOW64 and therefore invisible to runtime.GOARCH. On systems
+// without IsWow64Process2 (pre Windows 10 1709) the process architecture is used.
+func hostShimArch() (arch string, emulated bool) {
+	var processMachine, nativeMachine uint16
+	if err := windows.IsWow64Process2(windows.CurrentProcess(), &processMachine, &nativeMachine); err != nil {
+		slog.Debug("[DEBUG-SHIM] IsWow64Process2 unavailable; using process architecture",
+			"goarch", runtime.GOARCH, "error", err)
+		return runtime.GOARCH, false
+	}
+	native := shimArchForMachine(nativeMachine)
+	if native == "" {
+		return runtime.GOARCH, false
+	}
+	return native, native != runtime.GOARCH
+}
@@ -2,12 +2,61 @@
 
 package install
 
+import (
+	"errors"
+	"io/fs"
+	"log/slog"
+	"path"
+)
+
+// embeddedShimDir is the embed root populated by make prepare-embed.
+const embeddedShimDir = "embedded/shimbin"
+
 // HasEmbeddedShim reports whether a shim binary is embedded in this build.
 func HasEmbeddedShim() bool {
-	return len(embeddedShimBinary) > 0
+	return len(GetEmbeddedShim()) > 0
 }
 
-// GetEmbeddedShim returns the embedded shim binary bytes, or nil if not embedded.
+// GetEmbeddedShim returns the embedded shim binary for the host architecture,
+// or nil if not embedded.
 func GetEmbeddedShim() []byte {
-	return embeddedShimBinary
+	arch, _ := hostShimArchFn()
+	data, _ := selectEmbeddedShim(arch)
+	return data
+}
+
+// selectEmbeddedShim returns the arch-specific embedded shim, falling back to
+// the generic tmux-shim.exe (built for the app's own GOARCH). exact reports
+// whether the returned binary was built for arch.
+func selectEmbeddedShim(arch string) (data []byte, exact bool) {
+	if data := embeddedShimArchBinaries[arch]; len(data) > 0 {
+		return data, true
+	}
+	return embeddedShimBinary, false
+}
+
+// loadEmbeddedShims reads the generic and arch-specific shim binaries from
+// fsys. Missing files are skipped so single-arch bundles keep working.
+func loadEmbeddedShims(fsys fs.FS, dir string) (generic []byte, byArch map[string][]byte) {
+	read := func(name string) []byte {
+		data, err := fs.ReadFile(fsys, path.Join(dir, name))
+		if err != nil {
+			if !errors.Is(err, fs.ErrNotExist) {
+				slog.Warn("[WARN-SHIM] failed to read embedded shim", "name", name, "error", err)
+			}
+			return nil
+		}
+		return data
+	}
+
+	generic = read("tmux-shim.exe")
+	for _, arch := range []string{ShimArchAMD64, ShimArchARM64} {
+		if data := read(shimBinaryName(arch)); len(data) > 0 {
+			if byArch == nil {
+				byArch = make(map[string][]byte, 2)
+			}
+			byArch[arch] = data
+		}
+	}
+	return generic, byArch
 }
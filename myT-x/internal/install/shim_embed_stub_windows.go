@@ -5,3 +5,6 @@ package install
 // embeddedShimBinary is nil when the embed_shim build tag is not set.
 // In dev mode, the shim is resolved from the file system instead.
 var embeddedShimBinary []byte
+
+// embeddedShimArchBinaries is nil when the embed_shim build tag is not set.
+var embeddedShimArchBinaries map[string][]byte
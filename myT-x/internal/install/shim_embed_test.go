@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"testing/fstest"
 )
 
 func TestHasEmbeddedShim_Stub(t *testing.T) {
@@ -52,6 +53,68 @@ func TestGetEmbeddedShim_WithData(t *testing.T) {
 	}
 }
 
+func TestLoadEmbeddedShims(t *testing.T) {
+	fsys := fstest.MapFS{
+		"embedded/shimbin/tmux-shim.exe":       {Data: []byte("generic")},
+		"embedded/shimbin/tmux-shim-arm64.exe": {Data: []byte("arm64")},
+	}
+	generic, byArch := loadEmbeddedShims(fsys, embeddedShimDir)
+	if string(generic) != "generic" {
+		t.Fatalf("generic = %q, want %q", generic, "generic")
+	}
+	if string(byArch[ShimArchARM64]) != "arm64" {
+		t.Fatalf("byArch[arm64] = %q, want %q", byArch[ShimArchARM64], "arm64")
+	}
+	if _, ok := byArch[ShimArchAMD64]; ok {
+		t.Fatal("byArch[amd64] must be absent when the file is not bundled")
+	}
+}
+
+func TestSelectEmbeddedShim(t *testing.T) {
+	savedGeneric, savedArch := embeddedShimBinary, embeddedShimArchBinaries
+	t.Cleanup(func() { embeddedShimBinary, embeddedShimArchBinaries = savedGeneric, savedArch })
+
+	embeddedShimBinary = []byte("generic")
+	embeddedShimArchBinaries = map[string][]byte{ShimArchARM64: []byte("arm64")}
+
+	if data, exact := selectEmbeddedShim(ShimArchARM64); string(data) != "arm64" || !exact {
+		t.Fatalf("selectEmbeddedShim(arm64) = (%q, %v), want (arm64, true)", data, exact)
+	}
+	if data, exact := selectEmbeddedShim(ShimArchAMD64); string(data) != "generic" || exact {
+		t.Fatalf("selectEmbeddedShim(amd64) = (%q, %v), want (generic, false)", data, exact)
+	}
+}
+
+func TestEnsureShimInstalled_SelectsNativeArchUnderEmulation(t *testing.T) {
+	savedGeneric, savedArch, savedHost := embeddedShimBinary, embeddedShimArchBinaries, hostShimArchFn
+	t.Cleanup(func() {
+		embeddedShimBinary, embeddedShimArchBinaries, hostShimArchFn = savedGeneric, savedArch, savedHost
+	})
+
+	embeddedShimBinary = []byte("MZ-generic")
+	embeddedShimArchBinaries = map[string][]byte{
+		ShimArchAMD64: []byte("MZ-amd64"),
+		ShimArchARM64: []byte("MZ-arm64"),
+	}
+	hostShimArchFn = func() (string, bool) { return ShimArchARM64, true }
+
+	installDir := setupEmbedTestEnv(t)
+	result, err := ensureShimInstalledWith(testEnsurePathFn(), "")
+	if err != nil {
+		t.Fatalf("ensureShimInstalledWith() error = %v", err)
+	}
+	if result.Arch != ShimArchARM64 {
+		t.Fatalf("Arch = %q, want %q", result.Arch, ShimArchARM64)
+	}
+	got, err := os.ReadFile(filepath.Join(installDir, "tmux.exe"))
+	if err != nil {
+		t.Fatalf("ReadFile(tmux.exe) error = %v", err)
+	}
+	if string(got) != "MZ-arm64" {
+		t.Fatalf("installed shim = %q, want arm64 build", got)
+	}
+}
+
 // testEnsurePathFn returns a process-local PATH updater for use as the
 // ensurePathFn parameter to ensureShimInstalledWith (no registry writes).
 func testEnsurePathFn() func(string) (bool, error) {
@@ -2,7 +2,12 @@
 
 package install
 
-import _ "embed"
+import "embed"
 
-//go:embed embedded/shimbin/tmux-shim.exe
-var embeddedShimBinary []byte
+// embeddedShimFS holds tmux-shim.exe and, for dual-architecture bundles,
+// tmux-shim-amd64.exe / tmux-shim-arm64.exe.
+//
+//go:embed embedded/shimbin
+var embeddedShimFS embed.FS
+
+var embeddedShimBinary, embeddedShimArchBinaries = loadEmbeddedShims(embeddedShimFS, embeddedShimDir)
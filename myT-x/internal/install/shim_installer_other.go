@@ -8,6 +8,8 @@ type ShimInstallResult struct {
 	PathUpdated    bool   `json:"path_updated"`
 	RestartNeeded  bool   `json:"restart_needed"`
 	InstallMessage string `json:"message"`
+	// Arch is the host architecture the installed shim was selected for.
+	Arch string `json:"arch"`
 }
 
 // EnsureShimInstalled is a no-op on non-Windows platforms.
@@ -11,6 +11,7 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 )
 
@@ -20,6 +21,8 @@ type ShimInstallResult struct {
 	PathUpdated    bool   `json:"path_updated"`
 	RestartNeeded  bool   `json:"restart_needed"`
 	InstallMessage string `json:"message"`
+	// Arch is the host architecture the installed shim was selected for.
+	Arch string `json:"arch"`
 }
 
 // EnsureShimInstalled installs tmux shim and adds install dir to user PATH.
@@ -40,8 +43,17 @@ func ensureShimInstalledWith(ensurePathFn func(string) (bool, error), workspaceR
 	target := filepath.Join(installDir, "tmux.exe")
 	hashFile := target + ".sha256"
 
-	if HasEmbeddedShim() {
-		shimBytes := GetEmbeddedShim()
+	arch, emulated := hostShimArchFn()
+	if emulated {
+		slog.Info("[shim] myT-x runs under emulation; installing shim for the native architecture",
+			"hostArch", arch, "processArch", runtime.GOARCH)
+	}
+
+	if shimBytes, exact := selectEmbeddedShim(arch); len(shimBytes) > 0 {
+		if !exact && arch != runtime.GOARCH {
+			slog.Warn("[WARN-SHIM] no embedded shim for host architecture; installed shim will run under emulation",
+				"hostArch", arch, "shimArch", runtime.GOARCH)
+		}
 		sourceHash := sha256Hex(shimBytes)
 		if err := installShimIfChanged(hashFile, sourceHash, target, func() error {
 			slog.Debug("[DEBUG-SHIM] writing embedded shim binary", "target", target, "size", len(shimBytes))
@@ -50,7 +62,7 @@ func ensureShimInstalledWith(ensurePathFn func(string) (bool, error), workspaceR
 			return ShimInstallResult{}, fmt.Errorf("write embedded shim: %w", err)
 		}
 	} else {
-		source, err := findShimSource(workspaceRoot)
+		source, err := findShimSource(workspaceRoot, arch)
 		if err != nil {
 			return ShimInstallResult{}, err
 		}
@@ -79,6 +91,7 @@ func ensureShimInstalledWith(ensurePathFn func(string) (bool, error), workspaceR
 		PathUpdated:    updated,
 		RestartNeeded:  updated,
 		InstallMessage: msg,
+		Arch:           arch,
 	}, nil
 }
 
@@ -15,15 +15,24 @@ import (
 	"myT-x/internal/procutil"
 )
 
-func findShimSource(workspaceRoot string) (string, error) {
+// findShimSource locates a shim binary on disk for arch. An arch-specific
+// tmux-shim-<arch>.exe next to the executable wins over the generic
+// tmux-shim.exe; the dev fallback builds the shim for arch from workspaceRoot.
+func findShimSource(workspaceRoot, arch string) (string, error) {
 	exePath, exeErr := os.Executable()
 	if exeErr != nil {
 		slog.Debug("[DEBUG-SHIM] os.Executable failed while locating shim source", "error", exeErr)
 	}
 	if exeErr == nil && exePath != "" {
-		candidate := filepath.Join(filepath.Dir(exePath), "tmux-shim.exe")
-		if fileExists(candidate) {
-			return candidate, nil
+		candidates := []string{"tmux-shim.exe"}
+		if arch != "" {
+			candidates = []string{shimBinaryName(arch), "tmux-shim.exe"}
+		}
+		for _, name := range candidates {
+			candidate := filepath.Join(filepath.Dir(exePath), name)
+			if fileExists(candidate) {
+				return candidate, nil
+			}
 		}
 	}
 
@@ -33,6 +42,10 @@ func findShimSource(workspaceRoot string) (string, error) {
 		cmd := exec.Command("go", "build", "-o", target, "./cmd/tmux-shim")
 		procutil.HideWindow(cmd)
 		cmd.Dir = workspaceRoot
+		if arch != "" {
+			// Build for the host, not the (possibly emulated) process architecture.
+			cmd.Env = append(os.Environ(), "GOOS=windows", "GOARCH="+arch)
+		}
 		if output, err := cmd.CombinedOutput(); err == nil {
 			return target, nil
 		} else {
@@ -26,7 +26,7 @@ func TestFindShimSourceAdjacentToExecutable(t *testing.T) {
 		os.Remove(candidate)
 	})
 
-	got, err := findShimSource("")
+	got, err := findShimSource("", "")
 	if err != nil {
 		t.Fatalf("findShimSource() error = %v", err)
 	}
@@ -48,7 +48,7 @@ func TestFindShimSourceWorkspaceRootEmpty(t *testing.T) {
 		t.Skip("tmux-shim.exe already exists next to test binary, skipping")
 	}
 
-	_, err = findShimSource("")
+	_, err = findShimSource("", "")
 	if err == nil {
 		t.Fatal("findShimSource(\"\") expected error when no source found")
 	}
@@ -66,7 +66,7 @@ func TestFindShimSourceWorkspaceRootNoGoMod(t *testing.T) {
 	}
 
 	tmpDir := t.TempDir()
-	_, err = findShimSource(tmpDir)
+	_, err = findShimSource(tmpDir, "")
 	if err == nil {
 		t.Fatal("findShimSource(noGoMod) expected error")
 	}
@@ -0,0 +1,107 @@
+package ipc
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// authTokenFileSuffix is appended to the pipe's base name to form the name
+// of the file that holds the server's auth token.
+const authTokenFileSuffix = ".token"
+
+// The transport already restricts the pipe to the current user. The auth
+// token adds a second check: every request must carry the token of the
+// running server, which is published in a file only the current user can
+// read (see authTokenPath). A process of another user that reaches the pipe
+// anyway, e.g. through a misconfigured ACL, cannot inject commands.
+
+// newAuthToken returns a random token for one server run.
+func newAuthToken() string {
+	return rand.Text()
+}
+
+// publishAuthToken writes token to the token file of pipeName and returns a
+// func that removes it again. The file is replaced atomically so a client
+// never reads a partial token.
+func publishAuthToken(pipeName, token string) (func(), error) {
+	path, err := authTokenPath(pipeName)
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("create token dir: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*")
+	if err != nil {
+		return nil, fmt.Errorf("create token file: %w", err)
+	}
+	_, writeErr := tmp.WriteString(token)
+	closeErr := tmp.Close()
+	if err := errors.Join(writeErr, closeErr); err != nil {
+		_ = os.Remove(tmp.Name())
+		return nil, fmt.Errorf("write token file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return nil, fmt.Errorf("install token file: %w", err)
+	}
+	return func() { removeAuthToken(path, token) }, nil
+}
+
+// removeAuthToken removes the token file at path if it still holds token;
+// a server started since then may have published its own.
+func removeAuthToken(path, token string) {
+	current, err := os.ReadFile(path)
+	if err != nil || string(current) != token {
+		return
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		slog.Debug("[ipc] failed to remove auth token file", "path", path, "error", err)
+	}
+}
+
+// readAuthToken returns the token published for pipeName, or "" when no
+// server published one.
+func readAuthToken(pipeName string) string {
+	path, err := authTokenPath(pipeName)
+	if err != nil {
+		slog.Debug("[ipc] auth token path unavailable", "error", err)
+		return ""
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			slog.Debug("[ipc] failed to read auth token", "path", path, "error", err)
+		}
+		return ""
+	}
+	return strings.TrimSpace(string(raw))
+}
+
+// withAuthToken sets the token of req from the token file of pipeName
+// unless the caller already set one.
+func withAuthToken(pipeName string, req TmuxRequest) TmuxRequest {
+	if req.Token == "" {
+		req.Token = readAuthToken(pipeName)
+	}
+	return req
+}
+
+// unauthorizedResponse answers a request without the server's token.
+var unauthorizedResponse = TmuxResponse{
+	ExitCode: 1,
+	Stderr:   "unauthorized: request does not carry the server's auth token\n",
+}
+
+// authorized reports whether req carries the server's token.
+func (s *PipeServer) authorized(req TmuxRequest) bool {
+	return subtle.ConstantTimeCompare([]byte(req.Token), []byte(s.token)) == 1
+}
@@ -0,0 +1,55 @@
+//go:build !windows
+
+package ipc
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"testing"
+)
+
+func TestHandleConnectionRejectsRequestWithoutValidToken(t *testing.T) {
+	client := serveOnPipe(t, echoExecutor{}, TmuxRequest{Command: "kill-server", Token: "guessed"})
+
+	resp, err := readResponse(client, nil)
+	if err != nil {
+		t.Fatalf("readResponse() error = %v", err)
+	}
+	if resp != unauthorizedResponse {
+		t.Fatalf("response = %+v, want unauthorized", resp)
+	}
+}
+
+func TestSendAuthenticatesWithPublishedToken(t *testing.T) {
+	address := shortSocketPath(t)
+	s := NewPipeServer(address, echoExecutor{})
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = s.Stop() })
+
+	tokenPath, _ := authTokenPath(address)
+	info, err := os.Stat(tokenPath)
+	if err != nil {
+		t.Fatalf("token file: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Fatalf("token file mode = %v, want 0600", perm)
+	}
+
+	if resp, err := Send(address, TmuxRequest{Command: "list-sessions"}); err != nil || resp.Stdout != "list-sessions" {
+		t.Fatalf("Send() = %+v, %v", resp, err)
+	}
+	resp, err := Send(address, TmuxRequest{Command: "list-sessions", Token: "stale"})
+	if err != nil || resp.ExitCode != 1 {
+		t.Fatalf("Send() with a wrong token = %+v, %v; want rejected", resp, err)
+	}
+
+	if err := s.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(tokenPath); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("token file after Stop: %v, want removed", err)
+	}
+}
@@ -0,0 +1,105 @@
+package ipc
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+// BatchClient sends requests one after another over a single kept-open
+// connection, saving the dial and the pipe instance per command. Requests
+// are never streamed: output arrives with the final response.
+//
+// A BatchClient is not safe for concurrent use.
+type BatchClient struct {
+	pipeName string
+	conn     net.Conn
+	reader   *bufio.Reader
+	// reused is true once conn has carried a request.
+	reused bool
+}
+
+// NewBatchClient returns a client for pipeName. It connects on the first Send.
+func NewBatchClient(pipeName string) *BatchClient {
+	if pipeName == "" {
+		pipeName = DefaultPipeName()
+	}
+	return &BatchClient{pipeName: pipeName}
+}
+
+// errStaleConnection is returned by sendOnce when a kept-open connection
+// was closed before the request could be written.
+var errStaleConnection = errors.New("kept-open connection was closed")
+
+// Send sends req and waits for its response.
+//
+// A request the server did not execute is resent over a new connection:
+// after a retry frame (restart, or an idle connection the server gave up)
+// and when a kept-open connection is already closed.
+func (c *BatchClient) Send(req TmuxRequest) (TmuxResponse, error) {
+	req.Stream = false
+	req.ID = ""
+	req.KeepOpen = true
+	req = withAuthToken(c.pipeName, req)
+	for redial := 0; ; redial++ {
+		resp, err := c.sendOnce(req, redial > 0)
+		if !errors.Is(err, errServerRestarting) && !errors.Is(err, errStaleConnection) || redial >= maxRestartRedials {
+			return resp, err
+		}
+	}
+}
+
+// sendOnce writes req to the current connection, dialing first if there is
+// none, and reads the response. The connection is closed unless the server
+// confirmed that it stays open.
+func (c *BatchClient) sendOnce(req TmuxRequest, redial bool) (TmuxResponse, error) {
+	if c.conn == nil {
+		conn, err := dialPipe(c.pipeName, redial)
+		if err != nil {
+			return TmuxResponse{}, err
+		}
+		c.conn = conn
+		c.reader = bufio.NewReaderSize(conn, maxPipeResponseBytes+1)
+		c.reused = false
+	}
+
+	frame, err := c.roundTrip(req)
+	if err != nil || !frame.KeepOpen {
+		_ = c.Close()
+		return frame.TmuxResponse, err
+	}
+	c.reused = true
+	return frame.TmuxResponse, nil
+}
+
+func (c *BatchClient) roundTrip(req TmuxRequest) (responseFrame, error) {
+	if err := c.conn.SetDeadline(time.Now().Add(defaultPipeRWTimeout)); err != nil {
+		return responseFrame{}, fmt.Errorf("set deadline: %w", err)
+	}
+	rawReq, err := encodeRequest(req)
+	if err != nil {
+		return responseFrame{}, err
+	}
+	if _, err := c.conn.Write(append(rawReq, '\n')); err != nil {
+		if c.reused {
+			return responseFrame{}, fmt.Errorf("%w: %v", errStaleConnection, err)
+		}
+		return responseFrame{}, err
+	}
+	return readFinalFrame(c.conn, c.reader, nil)
+}
+
+// Close closes the connection. The client can still be used; the next Send
+// connects again.
+func (c *BatchClient) Close() error {
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn = nil
+	c.reader = nil
+	c.reused = false
+	return err
+}
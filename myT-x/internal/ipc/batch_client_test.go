@@ -0,0 +1,68 @@
+//go:build !windows
+
+package ipc
+
+import (
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type echoExecutor struct{}
+
+func (echoExecutor) Execute(req TmuxRequest) TmuxResponse {
+	return TmuxResponse{Stdout: req.Command}
+}
+
+// countingListener counts the accepted connections.
+type countingListener struct {
+	net.Listener
+	accepted *atomic.Int32
+}
+
+func (l countingListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err == nil {
+		l.accepted.Add(1)
+	}
+	return conn, err
+}
+
+func TestBatchClientReusesConnection(t *testing.T) {
+	address := shortSocketPath(t)
+	var accepted atomic.Int32
+	s := NewPipeServer(address, echoExecutor{})
+	s.listen = func(address string) (net.Listener, error) {
+		l, err := PlatformTransport().Listen(address)
+		return countingListener{Listener: l, accepted: &accepted}, err
+	}
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = s.Stop() })
+
+	client := NewBatchClient(address)
+	defer client.Close()
+	for _, command := range []string{"one", "two", "three"} {
+		resp, err := client.Send(TmuxRequest{Command: command})
+		if err != nil || resp.Stdout != command {
+			t.Fatalf("Send(%s) = %+v, %v", command, resp, err)
+		}
+	}
+	if got := accepted.Load(); got != 1 {
+		t.Fatalf("accepted connections = %d, want 1", got)
+	}
+
+	// A restart gives up the idle connection; the next request is resent
+	// to the restarted listener.
+	if _, err := s.Restart(time.Second); err != nil {
+		t.Fatalf("Restart() error = %v", err)
+	}
+	if resp, err := client.Send(TmuxRequest{Command: "after-restart"}); err != nil || resp.Stdout != "after-restart" {
+		t.Fatalf("Send() after restart = %+v, %v", resp, err)
+	}
+	if got := accepted.Load(); got != 2 {
+		t.Fatalf("accepted connections = %d, want 2", got)
+	}
+}
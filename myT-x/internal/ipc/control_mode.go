@@ -0,0 +1,34 @@
+package ipc
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ControlModeFlag marks an attach-session or new-session request from
+// `tmux -C` or `tmux -CC`. The shim sends it as a streaming request; the
+// server runs the command and keeps the request open, streaming control mode
+// notifications until the client detaches.
+const ControlModeFlag = "-C"
+
+// WriteControlModeBlock writes the %begin/%end block that frames the output
+// of command number in tmux control mode. A failed command ends with %error
+// and carries its stderr instead of its stdout.
+func WriteControlModeBlock(out io.Writer, number int, resp TmuxResponse) error {
+	now := time.Now().Unix()
+	var b strings.Builder
+	fmt.Fprintf(&b, "%%begin %d %d 1\n", now, number)
+	body, end := resp.Stdout, "%end"
+	if resp.ExitCode != 0 {
+		body, end = resp.Stderr, "%error"
+	}
+	b.WriteString(body)
+	if body != "" && !strings.HasSuffix(body, "\n") {
+		b.WriteByte('\n')
+	}
+	fmt.Fprintf(&b, "%s %d %d 1\n", end, now, number)
+	_, err := io.WriteString(out, b.String())
+	return err
+}
@@ -0,0 +1,38 @@
+package ipc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteControlModeBlock(t *testing.T) {
+	tests := []struct {
+		name  string
+		resp  TmuxResponse
+		body  string
+		final string
+	}{
+		{name: "success", resp: TmuxResponse{Stdout: "a\nb"}, body: "a\nb\n", final: "%end"},
+		{name: "empty output", resp: TmuxResponse{}, body: "", final: "%end"},
+		{name: "failure", resp: TmuxResponse{ExitCode: 1, Stdout: "ignored", Stderr: "bad\n"}, body: "bad\n", final: "%error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			if err := WriteControlModeBlock(&b, 7, tt.resp); err != nil {
+				t.Fatalf("WriteControlModeBlock() error = %v", err)
+			}
+			lines := strings.SplitAfter(b.String(), "\n")
+			begin, end := lines[0], lines[len(lines)-2]
+			if !strings.HasPrefix(begin, "%begin ") || !strings.HasSuffix(begin, " 7 1\n") {
+				t.Fatalf("begin line = %q", begin)
+			}
+			if !strings.HasPrefix(end, tt.final+" ") || strings.TrimPrefix(end, tt.final) != strings.TrimPrefix(begin, "%begin") {
+				t.Fatalf("end line = %q, want %s matching %q", end, tt.final, begin)
+			}
+			if body := strings.Join(lines[1:len(lines)-2], ""); body != tt.body {
+				t.Fatalf("body = %q, want %q", body, tt.body)
+			}
+		})
+	}
+}
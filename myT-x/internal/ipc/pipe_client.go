@@ -2,29 +2,81 @@ package ipc
 
 import (
 	"bufio"
+	"context"
+	"crypto/rand"
 	"errors"
 	"fmt"
 	"io"
 	"net"
+	"os"
 	"time"
-
-	"github.com/Microsoft/go-winio"
 )
 
 const (
 	defaultPipeDialTimeout = 3 * time.Second
 	defaultPipeRWTimeout   = 15 * time.Second
 	maxPipeResponseBytes   = 64 * 1024
+	// maxRestartRedials bounds how often one request follows a retry frame.
+	maxRestartRedials = 3
+	// restartRedialTimeout is how long a redial after a retry frame waits
+	// for the restarted listener to create the pipe again.
+	restartRedialTimeout  = 5 * time.Second
+	restartRedialInterval = 20 * time.Millisecond
 )
 
+// errServerRestarting is returned by readResponse for a retry frame.
+var errServerRestarting = errors.New("pipe server is restarting")
+
 // Send sends one request and waits for one response.
 func Send(pipeName string, req TmuxRequest) (TmuxResponse, error) {
+	req.Stream = false
+	return roundTrip(context.Background(), pipeName, req, nil)
+}
+
+// SendStream sends one request with streaming enabled and copies output to
+// stdout and stderr as the server sends it. The returned response carries the
+// exit code and any output that was not streamed; callers print it after the
+// streamed output. Write errors on stdout or stderr are ignored so the exit
+// code is still received.
+//
+// When ctx is canceled before the response arrives, SendStream asks the
+// server to abort the request and keeps reading until the server's final
+// response, which reports how the command ended.
+func SendStream(ctx context.Context, pipeName string, req TmuxRequest, stdout, stderr io.Writer) (TmuxResponse, error) {
+	req.Stream = true
+	if req.ID == "" {
+		req.ID = rand.Text()
+	}
+	return roundTrip(ctx, pipeName, req, func(chunk outputChunk) error {
+		switch chunk.Stream {
+		case streamStdout:
+			_, _ = stdout.Write(chunk.Data)
+		case streamStderr:
+			_, _ = stderr.Write(chunk.Data)
+		default:
+			return fmt.Errorf("unknown output stream %q", chunk.Stream)
+		}
+		return nil
+	})
+}
+
+func roundTrip(ctx context.Context, pipeName string, req TmuxRequest, onChunk func(outputChunk) error) (TmuxResponse, error) {
 	if pipeName == "" {
 		pipeName = DefaultPipeName()
 	}
+	req = withAuthToken(pipeName, req)
+	for redial := 0; ; redial++ {
+		resp, err := roundTripOnce(ctx, pipeName, req, onChunk, redial > 0)
+		if !errors.Is(err, errServerRestarting) || redial >= maxRestartRedials {
+			return resp, err
+		}
+	}
+}
 
-	dialTimeout := defaultPipeDialTimeout
-	conn, err := winio.DialPipe(pipeName, &dialTimeout)
+// roundTripOnce sends req over a new connection. After a retry frame,
+// redial is true and the dial waits for the restarted listener.
+func roundTripOnce(ctx context.Context, pipeName string, req TmuxRequest, onChunk func(outputChunk) error, redial bool) (TmuxResponse, error) {
+	conn, err := dialPipe(pipeName, redial)
 	if err != nil {
 		return TmuxResponse{}, err
 	}
@@ -45,17 +97,75 @@ func Send(pipeName string, req TmuxRequest) (TmuxResponse, error) {
 	if _, err := conn.Write([]byte{'\n'}); err != nil {
 		return TmuxResponse{}, err
 	}
+	if req.ID != "" {
+		stop := context.AfterFunc(ctx, func() { writeCancel(conn, req.ID) })
+		defer stop()
+	}
+	return readResponse(conn, onChunk)
+}
+
+// dialPipe connects to pipeName. A missing pipe fails at once unless
+// waitForRestart is set, in which case the dial retries until the server has
+// created the pipe again or restartRedialTimeout passes.
+func dialPipe(pipeName string, waitForRestart bool) (net.Conn, error) {
+	deadline := time.Now().Add(restartRedialTimeout)
+	for {
+		conn, err := platformTransport.Dial(pipeName, defaultPipeDialTimeout)
+		if err == nil || !waitForRestart || !errors.Is(err, os.ErrNotExist) || time.Now().After(deadline) {
+			return conn, err
+		}
+		time.Sleep(restartRedialInterval)
+	}
+}
 
-	respRaw, err := readDelimitedFrame(bufio.NewReaderSize(conn, maxPipeResponseBytes+1), maxPipeResponseBytes)
+// writeCancel asks the server to abort request id. Failures are ignored: the
+// response read reports a broken connection.
+func writeCancel(conn net.Conn, id string) {
+	raw, err := encodeCancel(id)
 	if err != nil {
-		return TmuxResponse{}, err
+		return
 	}
+	_, _ = conn.Write(append(raw, '\n'))
+}
 
-	resp, err := decodeResponse(respRaw)
-	if err != nil {
-		return TmuxResponse{}, fmt.Errorf("invalid response: %w", err)
+// readResponse reads frames until the final response. Keepalive and chunk
+// frames extend the deadline; chunks are passed to onChunk.
+func readResponse(conn net.Conn, onChunk func(outputChunk) error) (TmuxResponse, error) {
+	frame, err := readFinalFrame(conn, bufio.NewReaderSize(conn, maxPipeResponseBytes+1), onChunk)
+	return frame.TmuxResponse, err
+}
+
+// readFinalFrame is readResponse with a reader that outlives the request, as
+// on a kept-open connection. It returns the whole final frame.
+func readFinalFrame(conn net.Conn, reader *bufio.Reader, onChunk func(outputChunk) error) (responseFrame, error) {
+	for {
+		respRaw, err := readDelimitedFrame(reader, maxPipeResponseBytes)
+		if err != nil {
+			return responseFrame{}, err
+		}
+		frame, err := decodeFrame(respRaw)
+		if err != nil {
+			return responseFrame{}, fmt.Errorf("invalid response: %w", err)
+		}
+		switch {
+		case frame.Retry:
+			return responseFrame{}, errServerRestarting
+		case frame.Chunk != nil:
+			if onChunk == nil {
+				return responseFrame{}, errors.New("invalid response: unexpected output chunk")
+			}
+			if err := onChunk(*frame.Chunk); err != nil {
+				return responseFrame{}, fmt.Errorf("invalid response: %w", err)
+			}
+		case !frame.Pending:
+			return frame, nil
+		}
+		// The server is still executing the request (e.g. awaiting approval
+		// or producing output).
+		if err := conn.SetDeadline(time.Now().Add(defaultPipeRWTimeout)); err != nil {
+			return responseFrame{}, fmt.Errorf("set deadline: %w", err)
+		}
 	}
-	return resp, nil
 }
 
 func readDelimitedFrame(reader *bufio.Reader, maxBytes int) ([]byte, error) {
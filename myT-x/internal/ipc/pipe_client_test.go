@@ -3,6 +3,7 @@ package ipc
 import (
 	"bufio"
 	"io"
+	"net"
 	"strings"
 	"testing"
 )
@@ -58,3 +59,17 @@ func TestReadDelimitedFrameAcceptsEOFWithPartialData(t *testing.T) {
 		t.Fatalf("readDelimitedFrame() = %q, want %q", string(raw), payload)
 	}
 }
+
+func TestReadResponseRejectsUnexpectedChunk(t *testing.T) {
+	server, client := net.Pipe()
+	defer client.Close()
+	go func() {
+		defer server.Close()
+		raw, _ := encodeChunk(streamStdout, []byte("x"))
+		_, _ = server.Write(append(raw, '\n'))
+	}()
+
+	if _, err := readResponse(client, nil); err == nil {
+		t.Fatal("readResponse() error = nil, want error for chunk without stream handler")
+	}
+}
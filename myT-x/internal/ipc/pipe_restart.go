@@ -0,0 +1,336 @@
+package ipc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"time"
+)
+
+const (
+	// restartHandoffTimeout bounds how long Restart waits for connections
+	// that were told to redial to close before it re-creates the pipe.
+	restartHandoffTimeout = 2 * time.Second
+	restartPollInterval   = 10 * time.Millisecond
+	// restartListenAttempts bounds re-creating the pipe: a client instance
+	// closing late can keep the name busy briefly.
+	restartListenAttempts = 5
+)
+
+// restartListenBackoff is the wait after the first failed re-listen; it
+// doubles after every further failure. A variable so tests can shorten it.
+var restartListenBackoff = 100 * time.Millisecond
+
+// ErrRestartListenFailed is returned by Restart when the listener could not
+// be re-created. The server is stopped by then and has to be replaced.
+var ErrRestartListenFailed = errors.New("pipe server could not re-create its listener")
+
+// connState is the stage of an accepted connection.
+type connState int
+
+const (
+	// connReading connections have not finished sending their request.
+	connReading connState = iota
+	// connActive connections are executing their request.
+	connActive
+	// connParked connections arrived during a restart and wait for the handoff.
+	connParked
+	// connIdle connections were kept open by a KeepOpen request and wait for
+	// the next one.
+	connIdle
+)
+
+// activeRequest is the request a connActive connection is executing.
+type activeRequest struct {
+	command string
+	frames  *frameWriter
+	cancel  context.CancelFunc
+}
+
+// RestartReport summarizes one Restart.
+type RestartReport struct {
+	// Restarts counts the completed restarts of this server, including this one.
+	Restarts int `json:"restarts"`
+	// Drained requests were in flight when the restart began and finished
+	// before the drain deadline.
+	Drained int `json:"drained"`
+	// Dropped requests were still running at the drain deadline. They were
+	// answered with an error and canceled, which denies a command still
+	// awaiting approval.
+	Dropped int `json:"dropped"`
+	// HandedOff requests arrived during the drain and were told to resend
+	// to the restarted listener.
+	HandedOff  int   `json:"handed_off"`
+	DurationMs int64 `json:"duration_ms"`
+}
+
+// Restart re-creates the listener without losing queued requests:
+//
+//  1. Requests accepted from now on are parked instead of executed.
+//  2. In-flight requests get up to drainTimeout to finish; the rest are
+//     answered with an error and canceled.
+//  3. The old listener is closed and every parked request receives a
+//     retry frame, on which the client redials and resends.
+//  4. A new listener is created, retrying with backoff, and accepting
+//     resumes.
+//
+// The client waits for the pipe to reappear after a retry frame, so only
+// requests dropped in step 2 fail. When step 4 keeps failing, the server is
+// stopped and ErrRestartListenFailed is returned.
+func (s *PipeServer) Restart(drainTimeout time.Duration) (RestartReport, error) {
+	s.restartMu.Lock()
+	defer s.restartMu.Unlock()
+	started := time.Now()
+
+	s.mu.Lock()
+	if !s.started || s.listener == nil {
+		s.mu.Unlock()
+		return RestartReport{}, errors.New("pipe server is not running")
+	}
+	s.draining = true
+	s.handoff = make(chan struct{})
+	s.handedOff = 0
+	inFlight := s.countConnsLocked(connActive)
+	// Idle kept-open connections have no request to drain; they send a
+	// retry frame and close.
+	s.expireConnsLocked(connIdle)
+	s.mu.Unlock()
+
+	var report RestartReport
+	report.Dropped = s.waitForConns(drainTimeout, connActive)
+	report.Drained = max(inFlight-report.Dropped, 0)
+
+	s.mu.Lock()
+	oldListener, acceptDone := s.listener, s.acceptDone
+	s.listener = nil
+	stuck := s.requestsLocked()
+	s.mu.Unlock()
+	s.abortRequests(stuck)
+
+	if oldListener != nil {
+		if err := oldListener.Close(); err != nil {
+			slog.Debug("[ipc] failed to close pipe listener during restart", "error", err)
+		}
+	}
+	// Once the old accept loop has returned, no more connections can arrive
+	// until the new listener exists.
+	<-acceptDone
+
+	s.mu.Lock()
+	close(s.handoff)
+	s.mu.Unlock()
+	// A pipe cannot be re-created while instances of it are still open.
+	if left := s.waitForConns(restartHandoffTimeout, connReading, connParked, connIdle); left > 0 {
+		slog.Warn("[ipc] closing connections that did not finish the restart handoff", "count", left)
+		s.mu.Lock()
+		s.closeConnsLocked(connReading, connParked, connIdle)
+		s.mu.Unlock()
+	}
+
+	listener, listenErr := s.relisten()
+
+	s.mu.Lock()
+	s.draining = false
+	report.HandedOff = s.handedOff
+	report.DurationMs = time.Since(started).Milliseconds()
+	if !s.started {
+		// Stop ran while the restart was draining.
+		s.mu.Unlock()
+		if listener != nil {
+			if err := listener.Close(); err != nil {
+				slog.Debug("[ipc] failed to close pipe listener after stop", "error", err)
+			}
+		}
+		return report, errors.New("pipe server stopped during restart")
+	}
+	if listenErr != nil {
+		s.mu.Unlock()
+		// A started server without a listener would look healthy while
+		// every client fails to connect.
+		slog.Error("[ipc] pipe server stopped: restart could not re-create the listener", "error", listenErr)
+		if err := s.Stop(); err != nil {
+			slog.Debug("[ipc] failed to stop pipe server after restart failure", "error", err)
+		}
+		return report, fmt.Errorf("%w: %s: %w", ErrRestartListenFailed, s.pipeName, listenErr)
+	}
+	defer s.mu.Unlock()
+	s.restarts++
+	report.Restarts = s.restarts
+	s.startAcceptLoopLocked(listener)
+
+	slog.Info("[ipc] pipe server restarted",
+		"restarts", report.Restarts,
+		"drained", report.Drained,
+		"dropped", report.Dropped,
+		"handedOff", report.HandedOff,
+		"duration_ms", report.DurationMs)
+	return report, nil
+}
+
+// relisten re-creates the listener, retrying with backoff while Stop has not
+// run.
+func (s *PipeServer) relisten() (net.Listener, error) {
+	backoff := restartListenBackoff
+	var err error
+	for attempt := 1; ; attempt++ {
+		var listener net.Listener
+		listener, err = s.listen(s.pipeName)
+		if err == nil {
+			return listener, nil
+		}
+		if attempt == restartListenAttempts {
+			return nil, err
+		}
+		slog.Warn("[ipc] restart listen failed; retrying", "attempt", attempt, "error", err)
+		select {
+		case <-time.After(backoff):
+		case <-s.ctx.Done():
+			return nil, err
+		}
+		backoff *= 2
+	}
+}
+
+// requestsLocked returns the requests still executing. The caller must hold
+// s.mu.
+func (s *PipeServer) requestsLocked() []activeRequest {
+	var active []activeRequest
+	for conn, state := range s.conns {
+		if state != connActive {
+			continue
+		}
+		if req, ok := s.requests[conn]; ok {
+			active = append(active, req)
+		}
+	}
+	return active
+}
+
+// abortRequests answers requests with an error, then cancels them and
+// closes their connections. Answering first keeps the executor's own late
+// response (e.g. an approval denied by the cancellation) from reaching the
+// client. Writes run in the background so a client that stopped reading
+// cannot hold up the restart.
+func (s *PipeServer) abortRequests(requests []activeRequest) {
+	if len(requests) > 0 {
+		slog.Warn("[ipc] aborting requests still running at the restart drain deadline", "count", len(requests))
+	}
+	for _, req := range requests {
+		s.wg.Go(func() {
+			req.frames.writeResponse(TmuxResponse{
+				ExitCode: 1,
+				Stderr:   fmt.Sprintf("%s aborted: the tmux server restarted before the command finished\n", req.command),
+			})
+			req.cancel()
+			if err := req.frames.conn.Close(); err != nil {
+				slog.Debug("[ipc] failed to close connection during restart", "error", err)
+			}
+		})
+	}
+}
+
+func (s *PipeServer) trackConn(conn net.Conn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conns == nil {
+		s.conns = make(map[net.Conn]connState)
+	}
+	s.conns[conn] = connReading
+}
+
+func (s *PipeServer) untrackConn(conn net.Conn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.conns, conn)
+	delete(s.requests, conn)
+}
+
+// idleConn marks conn as waiting for its next kept-open request. It reports
+// false while a restart drains; the handler then sends a retry frame so
+// that the client resends to the restarted listener.
+func (s *PipeServer) idleConn(conn net.Conn) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.requests, conn)
+	if s.draining {
+		return false
+	}
+	s.conns[conn] = connIdle
+	return true
+}
+
+// beginRequest marks conn as executing req and returns ok. While a restart
+// is draining it parks conn instead and returns the channel that is closed
+// at the handoff.
+func (s *PipeServer) beginRequest(conn net.Conn, req activeRequest) (handoff <-chan struct{}, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.draining {
+		s.conns[conn] = connParked
+		s.handedOff++
+		return s.handoff, false
+	}
+	s.conns[conn] = connActive
+	if s.requests == nil {
+		s.requests = make(map[net.Conn]activeRequest)
+	}
+	s.requests[conn] = req
+	return nil, true
+}
+
+func (s *PipeServer) countConnsLocked(states ...connState) int {
+	count := 0
+	for _, state := range s.conns {
+		for _, want := range states {
+			if state == want {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// closeConnsLocked closes the connections in states. Their handlers see the
+// closed connection and untrack them.
+func (s *PipeServer) closeConnsLocked(states ...connState) {
+	for conn, state := range s.conns {
+		for _, want := range states {
+			if state == want {
+				if err := conn.Close(); err != nil {
+					slog.Debug("[ipc] failed to close connection during restart", "error", err)
+				}
+			}
+		}
+	}
+}
+
+// expireConnsLocked makes the pending reads of the connections in states
+// fail at once, so their handlers finish like on an idle timeout.
+func (s *PipeServer) expireConnsLocked(states ...connState) {
+	for conn, state := range s.conns {
+		for _, want := range states {
+			if state == want {
+				if err := conn.SetReadDeadline(time.Now()); err != nil {
+					slog.Debug("[ipc] failed to expire connection during restart", "error", err)
+				}
+			}
+		}
+	}
+}
+
+// waitForConns waits until no connection is in states or timeout passes,
+// and returns how many are left.
+func (s *PipeServer) waitForConns(timeout time.Duration, states ...connState) int {
+	deadline := time.Now().Add(timeout)
+	for {
+		s.mu.Lock()
+		left := s.countConnsLocked(states...)
+		s.mu.Unlock()
+		if left == 0 || !time.Now().Before(deadline) {
+			return left
+		}
+		time.Sleep(restartPollInterval)
+	}
+}
@@ -0,0 +1,276 @@
+package ipc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// chanListener is an in-memory listener whose connections come from dial.
+type chanListener struct {
+	conns     chan net.Conn
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func newChanListener() *chanListener {
+	return &chanListener{conns: make(chan net.Conn), closed: make(chan struct{})}
+}
+
+func (l *chanListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.closed:
+		return nil, net.ErrClosed
+	}
+}
+
+func (l *chanListener) Close() error {
+	l.closeOnce.Do(func() { close(l.closed) })
+	return nil
+}
+
+func (l *chanListener) Addr() net.Addr { return &net.UnixAddr{Name: "test", Net: "memory"} }
+
+func (l *chanListener) dial(t *testing.T) net.Conn {
+	t.Helper()
+	server, client := net.Pipe()
+	select {
+	case l.conns <- server:
+	case <-time.After(5 * time.Second):
+		t.Fatal("listener did not accept")
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+// gatedExecutor answers every request once release is closed.
+type gatedExecutor struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (e gatedExecutor) Execute(req TmuxRequest) TmuxResponse {
+	e.started <- struct{}{}
+	<-e.release
+	return TmuxResponse{Stdout: req.Command}
+}
+
+func (e gatedExecutor) ExecuteStream(_ context.Context, req TmuxRequest, _, _ io.Writer) TmuxResponse {
+	return e.Execute(req)
+}
+
+func sendRequest(t *testing.T, conn net.Conn, command string) {
+	t.Helper()
+	raw, err := encodeRequest(TmuxRequest{Command: command, Token: testAuthToken})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.Write(append(raw, '\n')); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// startRestartableServer starts a PipeServer whose every listen creates a
+// new chanListener, published on the returned channel.
+func startRestartableServer(t *testing.T, executor CommandExecutor) (*PipeServer, chan *chanListener) {
+	t.Helper()
+	listeners := make(chan *chanListener, 4)
+	s := NewPipeServer(`\\.\pipe\myT-x-test`, executor)
+	s.token = testAuthToken
+	s.publishToken = func(string, string) (func(), error) { return func() {}, nil }
+	s.listen = func(string) (net.Listener, error) {
+		l := newChanListener()
+		listeners <- l
+		return l, nil
+	}
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = s.Stop() })
+	return s, listeners
+}
+
+func TestRestartDrainsInFlightAndHandsOffQueuedRequests(t *testing.T) {
+	executor := gatedExecutor{started: make(chan struct{}, 1), release: make(chan struct{})}
+	s, listeners := startRestartableServer(t, executor)
+	first := <-listeners
+
+	inFlight := first.dial(t)
+	sendRequest(t, inFlight, "in-flight")
+	<-executor.started
+
+	type result struct {
+		report RestartReport
+		err    error
+	}
+	restarted := make(chan result, 1)
+	go func() {
+		report, err := s.Restart(5 * time.Second)
+		restarted <- result{report, err}
+	}()
+
+	// Wait until the restart is draining, then queue a second request.
+	for {
+		s.mu.Lock()
+		draining := s.draining
+		s.mu.Unlock()
+		if draining {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	queued := first.dial(t)
+	sendRequest(t, queued, "queued")
+
+	close(executor.release)
+	resp, err := readResponse(inFlight, nil)
+	if err != nil || resp.Stdout != "in-flight" {
+		t.Fatalf("in-flight response = %+v, %v; want completed request", resp, err)
+	}
+	if _, err := readResponse(queued, nil); !errors.Is(err, errServerRestarting) {
+		t.Fatalf("queued response error = %v, want errServerRestarting", err)
+	}
+
+	got := <-restarted
+	if got.err != nil {
+		t.Fatalf("Restart() error = %v", got.err)
+	}
+	if got.report.Restarts != 1 || got.report.Drained != 1 || got.report.Dropped != 0 || got.report.HandedOff != 1 {
+		t.Fatalf("report = %+v, want 1 restart, 1 drained, 1 handed off", got.report)
+	}
+
+	// The resent request is served by the new listener.
+	second := <-listeners
+	resent := second.dial(t)
+	sendRequest(t, resent, "queued")
+	<-executor.started
+	if resp, err := readResponse(resent, nil); err != nil || resp.Stdout != "queued" {
+		t.Fatalf("resent response = %+v, %v", resp, err)
+	}
+}
+
+func TestRestartDropsRequestsPastDrainDeadline(t *testing.T) {
+	executor := gatedExecutor{started: make(chan struct{}, 1), release: make(chan struct{})}
+	s, listeners := startRestartableServer(t, executor)
+	defer close(executor.release)
+
+	conn := (<-listeners).dial(t)
+	sendRequest(t, conn, "stuck")
+	<-executor.started
+
+	report, err := s.Restart(20 * time.Millisecond)
+	if err != nil {
+		t.Fatalf("Restart() error = %v", err)
+	}
+	if report.Dropped != 1 || report.Drained != 0 {
+		t.Fatalf("report = %+v, want 1 dropped", report)
+	}
+	resp, err := readResponse(conn, nil)
+	if err != nil || resp.ExitCode != 1 || !strings.Contains(resp.Stderr, "stuck aborted") {
+		t.Fatalf("dropped response = %+v, %v; want an explicit abort error", resp, err)
+	}
+}
+
+// heldExecutor holds every request until its context is canceled, like a
+// command awaiting approval.
+type heldExecutor struct {
+	started  chan struct{}
+	canceled chan struct{}
+}
+
+func (e heldExecutor) Execute(req TmuxRequest) TmuxResponse {
+	return e.ExecuteContext(context.Background(), req)
+}
+
+func (e heldExecutor) ExecuteContext(ctx context.Context, req TmuxRequest) TmuxResponse {
+	e.started <- struct{}{}
+	<-ctx.Done()
+	close(e.canceled)
+	return TmuxResponse{ExitCode: 1, Stderr: req.Command + " denied\n"}
+}
+
+func TestRestartCancelsHeldRequestsPastDrainDeadline(t *testing.T) {
+	executor := heldExecutor{started: make(chan struct{}, 1), canceled: make(chan struct{})}
+	s, listeners := startRestartableServer(t, executor)
+
+	conn := (<-listeners).dial(t)
+	sendRequest(t, conn, "held")
+	<-executor.started
+
+	if _, err := s.Restart(20 * time.Millisecond); err != nil {
+		t.Fatalf("Restart() error = %v", err)
+	}
+	resp, err := readResponse(conn, nil)
+	if err != nil || !strings.Contains(resp.Stderr, "held aborted") {
+		t.Fatalf("held response = %+v, %v; want the abort error, not the executor's late answer", resp, err)
+	}
+	select {
+	case <-executor.canceled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("held request was not canceled at the drain deadline")
+	}
+}
+
+func TestRestartRetriesListen(t *testing.T) {
+	restartListenBackoff = time.Millisecond
+	t.Cleanup(func() { restartListenBackoff = 100 * time.Millisecond })
+	s, listeners := startRestartableServer(t, streamingExecutorStub{})
+	<-listeners
+	listen := s.listen
+	failures := 2
+	s.listen = func(name string) (net.Listener, error) {
+		if failures > 0 {
+			failures--
+			return nil, errors.New("pipe busy")
+		}
+		return listen(name)
+	}
+
+	report, err := s.Restart(time.Second)
+	if err != nil || report.Restarts != 1 {
+		t.Fatalf("Restart() = %+v, %v; want a restart after retries", report, err)
+	}
+	<-listeners
+}
+
+func TestRestartStopsServerWhenListenKeepsFailing(t *testing.T) {
+	restartListenBackoff = time.Millisecond
+	t.Cleanup(func() { restartListenBackoff = 100 * time.Millisecond })
+	s, listeners := startRestartableServer(t, streamingExecutorStub{})
+	<-listeners
+	attempts := 0
+	s.listen = func(string) (net.Listener, error) {
+		attempts++
+		return nil, errors.New("pipe busy")
+	}
+
+	if _, err := s.Restart(time.Second); !errors.Is(err, ErrRestartListenFailed) {
+		t.Fatalf("Restart() error = %v, want ErrRestartListenFailed", err)
+	}
+	if attempts != restartListenAttempts {
+		t.Fatalf("listen attempts = %d, want %d", attempts, restartListenAttempts)
+	}
+	s.mu.Lock()
+	started := s.started
+	s.mu.Unlock()
+	if started {
+		t.Fatal("server still reports started without a listener")
+	}
+	if _, err := s.Restart(time.Second); err == nil {
+		t.Fatal("Restart() of the stopped server error = nil")
+	}
+}
+
+func TestRestartRequiresRunningServer(t *testing.T) {
+	s := NewPipeServer(`\\.\pipe\myT-x-test`, streamingExecutorStub{})
+	if _, err := s.Restart(time.Second); err == nil {
+		t.Fatal("Restart() before Start error = nil")
+	}
+}
@@ -8,13 +8,10 @@ import (
 	"io"
 	"log/slog"
 	"net"
-	"os/user"
-	"regexp"
-	"strings"
 	"sync"
 	"time"
 
-	"github.com/Microsoft/go-winio"
+	"myT-x/internal/faultinject"
 )
 
 const (
@@ -22,21 +19,53 @@ const (
 	maxPipeRequestBytes                 = 64 * 1024 // limits request size to prevent memory exhaustion
 	defaultPipeMaxConcurrentConnections = 64
 	connSlotAcquireTimeout              = 5 * time.Second
+	// pendingKeepaliveInterval is how often a pendingFrame is written while a
+	// request is still executing. It must stay well below the client's
+	// defaultPipeRWTimeout.
+	pendingKeepaliveInterval = 5 * time.Second
+	// keepOpenIdleTimeout is how long a KeepOpen connection may wait for its
+	// next request before the server closes it.
+	keepOpenIdleTimeout = 10 * time.Second
 )
 
-// PipeServer receives requests from tmux shim clients over Named Pipe.
+// PipeServer receives requests from tmux shim clients over the platform
+// transport: a Named Pipe on Windows, a Unix domain socket elsewhere.
 type PipeServer struct {
 	pipeName string
 	router   CommandExecutor
+	// faults is the dev-mode fault injector; nil unless MYTX_DEV_FAULTS is set.
+	faults *faultinject.Injector
 
 	ctx    context.Context
 	cancel context.CancelFunc
 
-	mu        sync.Mutex
-	listener  net.Listener
-	started   bool
-	wg        sync.WaitGroup
-	connSlots chan struct{}
+	// listen creates the listener; tests replace it.
+	listen func(pipeName string) (net.Listener, error)
+
+	// token authenticates requests. Start publishes it with publishToken,
+	// which tests replace; withdrawToken undoes that on Stop.
+	token         string
+	publishToken  func(pipeName, token string) (func(), error)
+	withdrawToken func()
+
+	mu         sync.Mutex
+	listener   net.Listener
+	acceptDone chan struct{}
+	started    bool
+	wg         sync.WaitGroup
+	connSlots  chan struct{}
+	// conns tracks accepted connections for Restart.
+	conns map[net.Conn]connState
+	// requests holds the executing request of each connActive connection so
+	// that Restart can answer and cancel it at the drain deadline.
+	requests map[net.Conn]activeRequest
+
+	// Restart state. restartMu serializes restarts; the rest is guarded by mu.
+	restartMu sync.Mutex
+	draining  bool
+	handoff   chan struct{}
+	handedOff int
+	restarts  int
 }
 
 // NewPipeServer constructs a PipeServer.
@@ -46,11 +75,15 @@ func NewPipeServer(pipeName string, router CommandExecutor) *PipeServer {
 		pipeName = DefaultPipeName()
 	}
 	return &PipeServer{
-		pipeName:  pipeName,
-		router:    router,
-		ctx:       ctx,
-		cancel:    cancel,
-		connSlots: make(chan struct{}, defaultPipeMaxConcurrentConnections),
+		pipeName:     pipeName,
+		router:       router,
+		faults:       faultinject.FromEnv(),
+		listen:       platformTransport.Listen,
+		token:        newAuthToken(),
+		publishToken: publishAuthToken,
+		ctx:          ctx,
+		cancel:       cancel,
+		connSlots:    make(chan struct{}, defaultPipeMaxConcurrentConnections),
 	}
 }
 
@@ -71,17 +104,34 @@ func (s *PipeServer) Start() error {
 		return errors.New("pipe server requires router")
 	}
 
-	listener, err := listenPipeWithCurrentUserDACL(s.pipeName)
+	listener, err := s.listen(s.pipeName)
 	if err != nil {
 		return fmt.Errorf("listen %s: %w", s.pipeName, err)
 	}
+	withdraw, err := s.publishToken(s.pipeName, s.token)
+	if err != nil {
+		_ = listener.Close()
+		return fmt.Errorf("publish auth token: %w", err)
+	}
+	s.withdrawToken = withdraw
 
-	s.listener = listener
 	s.started = true
-	s.wg.Go(s.acceptLoop)
+	s.startAcceptLoopLocked(listener)
 	return nil
 }
 
+// startAcceptLoopLocked makes listener current and starts accepting on it.
+// The caller must hold s.mu.
+func (s *PipeServer) startAcceptLoopLocked(listener net.Listener) {
+	done := make(chan struct{})
+	s.listener = listener
+	s.acceptDone = done
+	s.wg.Go(func() {
+		defer close(done)
+		s.acceptLoop(listener)
+	})
+}
+
 // Stop gracefully shuts down the server.
 func (s *PipeServer) Stop() error {
 	s.mu.Lock()
@@ -93,6 +143,10 @@ func (s *PipeServer) Stop() error {
 	s.cancel()
 	listener := s.listener
 	s.listener = nil
+	// Idle kept-open connections would otherwise hold Stop until they time out.
+	s.closeConnsLocked(connIdle)
+	withdraw := s.withdrawToken
+	s.withdrawToken = nil
 	s.mu.Unlock()
 
 	if listener != nil {
@@ -100,17 +154,21 @@ func (s *PipeServer) Stop() error {
 			slog.Warn("[ipc] failed to close pipe listener during shutdown", "error", err)
 		}
 	}
+	if withdraw != nil {
+		withdraw()
+	}
 	s.wg.Wait()
 	return nil
 }
 
-func (s *PipeServer) acceptLoop() {
+// acceptLoop accepts on listener until it is no longer the current one.
+func (s *PipeServer) acceptLoop(listener net.Listener) {
 	consecutiveErrors := 0
 	for {
 		s.mu.Lock()
-		listener := s.listener
+		current := s.listener
 		s.mu.Unlock()
-		if listener == nil {
+		if current != listener {
 			return
 		}
 
@@ -133,10 +191,7 @@ func (s *PipeServer) acceptLoop() {
 		consecutiveErrors = 0
 
 		if !s.acquireConnectionSlot() {
-			s.writeResponse(conn, TmuxResponse{
-				ExitCode: 1,
-				Stderr:   "server busy, try again later\n",
-			})
+			s.writeResponse(conn, serverBusyResponse)
 			if closeErr := conn.Close(); closeErr != nil {
 				slog.Debug("[ipc] failed to close rejected connection", "error", closeErr)
 			}
@@ -154,39 +209,92 @@ func (s *PipeServer) acceptLoop() {
 	}
 }
 
-// handleConnection processes a single client connection (one command per connection).
-// A deadline of defaultPipeConnTimeout is enforced and requests exceeding
-// maxPipeRequestBytes are rejected with an error response.
+// serverBusyResponse answers a connection beyond the connection limit.
+var serverBusyResponse = TmuxResponse{
+	ExitCode: 1,
+	Stderr:   "server busy, try again later\n",
+}
+
+// invalidRequestMessage prefixes the error of a request that cannot be read
+// or decoded.
+const invalidRequestMessage = "invalid request"
+
+// handleConnection serves one client connection. A connection carries one
+// request unless the request sets KeepOpen, in which case the next request
+// is read once the response is written. A deadline of defaultPipeConnTimeout
+// (keepOpenIdleTimeout between kept-open requests) is enforced. Requests
+// exceeding maxPipeRequestBytes or without the server's auth token are
+// rejected with an error response.
 func (s *PipeServer) handleConnection(conn net.Conn) {
 	defer conn.Close()
+	s.trackConn(conn)
+	defer s.untrackConn(conn)
 	if err := conn.SetDeadline(time.Now().Add(defaultPipeConnTimeout)); err != nil {
 		slog.Warn("[ipc] failed to set connection deadline", "error", err)
 		return
 	}
 
 	reader := bufio.NewReaderSize(conn, maxPipeRequestBytes+1)
-	rawReq, err := readRequestFrame(reader)
-	if errors.Is(err, io.EOF) {
-		slog.Debug("[ipc] client disconnected without sending data")
-		return
-	}
-	if err != nil {
-		s.writeResponse(conn, TmuxResponse{
-			ExitCode: 1,
-			Stderr:   fmt.Sprintf("invalid request: %v\n", err),
-		})
-		return
-	}
+	for served := 0; ; served++ {
+		rawReq, err := readRequestFrame(reader)
+		if errors.Is(err, io.EOF) {
+			if served == 0 {
+				slog.Debug("[ipc] client disconnected without sending data")
+			}
+			return
+		}
+		if err != nil && served > 0 {
+			// The idle kept-open connection timed out or a restart expired
+			// it. A request the client sends meanwhile was not read; the
+			// retry frame makes the client resend it on a new connection.
+			slog.Debug("[ipc] closing idle kept-open connection", "requests", served, "error", err)
+			(&frameWriter{conn: conn}).writeFrame([]byte(retryFrame))
+			return
+		}
+		if err != nil {
+			s.writeResponse(conn, TmuxResponse{
+				ExitCode: 1,
+				Stderr:   fmt.Sprintf("%s: %v\n", invalidRequestMessage, err),
+			})
+			return
+		}
 
-	req, err := decodeRequest(rawReq)
-	if err != nil {
-		s.writeResponse(conn, TmuxResponse{
-			ExitCode: 1,
-			Stderr:   fmt.Sprintf("invalid request: %v\n", err),
-		})
-		return
+		req, err := decodeRequest(rawReq)
+		if err != nil {
+			s.writeResponse(conn, TmuxResponse{
+				ExitCode: 1,
+				Stderr:   fmt.Sprintf("%s: %v\n", invalidRequestMessage, err),
+			})
+			return
+		}
+		if !s.authorized(req) {
+			slog.Warn("[ipc] rejected request without a valid auth token", "command", req.Command)
+			s.writeResponse(conn, unauthorizedResponse)
+			return
+		}
+		req.CallerPID = peerProcessID(conn)
+
+		// A streaming request owns the rest of the connection for its
+		// cancel frames, so it cannot be followed by another request.
+		keepOpen := req.KeepOpen && !req.Stream
+		if !s.serveRequest(conn, reader, req, keepOpen) || !keepOpen {
+			return
+		}
+		if !s.idleConn(conn) {
+			// A restart began while the request executed.
+			(&frameWriter{conn: conn}).writeFrame([]byte(retryFrame))
+			return
+		}
+		if err := conn.SetDeadline(time.Now().Add(keepOpenIdleTimeout)); err != nil {
+			slog.Debug("[ipc] failed to set idle connection deadline", "error", err)
+			return
+		}
 	}
+}
 
+// serveRequest executes req and writes its response, confirming keepOpen in
+// the final frame. It reports whether the response was written.
+func (s *PipeServer) serveRequest(conn net.Conn, reader *bufio.Reader, req TmuxRequest, keepOpen bool) bool {
 	slog.Debug("[DEBUG-IPC-PIPE] received request from shim",
 		"command", req.Command,
 		"callerPane", req.CallerPane,
@@ -194,23 +302,189 @@ func (s *PipeServer) handleConnection(conn net.Conn) {
 		"flags", fmt.Sprintf("%v", req.Flags),
 	)
 
-	resp := s.router.Execute(req)
-	s.writeResponse(conn, resp)
+	ctx, cancel := context.WithCancel(s.ctx)
+	defer cancel()
+	frames := &frameWriter{conn: conn}
+	if handoff, ok := s.beginRequest(conn, activeRequest{command: req.Command, frames: frames, cancel: cancel}); !ok {
+		// Accepted while a restart drains: hand the request over to the
+		// restarted listener instead of executing it here.
+		select {
+		case <-handoff:
+			(&frameWriter{conn: conn}).writeFrame([]byte(retryFrame))
+		case <-s.ctx.Done():
+		}
+		return false
+	}
+
+	fault := s.faults.Next(faultinject.ScopeIPC)
+	if err := fault.Wait(s.ctx); err != nil {
+		return false
+	}
+	switch fault.Kind {
+	case faultinject.Drop:
+		slog.Debug("[DEV-FAULTS] dropping pipe request", "command", req.Command)
+		return false
+	case faultinject.Fail:
+		s.writeResponse(conn, TmuxResponse{ExitCode: 1, Stderr: faultinject.ErrInjected.Error() + "\n"})
+		return false
+	}
+
+	execute := func() TmuxResponse { return s.router.Execute(req) }
+	if executor, ok := s.router.(ContextExecutor); ok {
+		execute = func() TmuxResponse { return executor.ExecuteContext(ctx, req) }
+	}
+	if streaming, ok := s.router.(StreamingExecutor); ok && req.Stream {
+		if req.ID != "" {
+			// Returns once the deferred conn.Close unblocks its read.
+			s.wg.Go(func() { watchCancel(reader, req.ID, cancel) })
+		}
+		stdout := chunkWriter{frames: frames, stream: streamStdout}
+		stderr := chunkWriter{frames: frames, stream: streamStderr}
+		execute = func() TmuxResponse { return streaming.ExecuteStream(ctx, req, stdout, stderr) }
+	}
+	resp := s.executeWithKeepalive(frames, execute)
+	if fault.Kind == faultinject.Partial {
+		resp = partialResponse(resp)
+	}
+	if keepOpen {
+		return frames.writeKeptOpenResponse(resp)
+	}
+	return frames.writeResponse(resp)
+}
+
+// partialResponse truncates resp to simulate a command that failed halfway.
+func partialResponse(resp TmuxResponse) TmuxResponse {
+	resp.Stdout = resp.Stdout[:len(resp.Stdout)/2]
+	resp.Stderr += faultinject.ErrInjected.Error() + "\n"
+	resp.ExitCode = 1
+	return resp
+}
+
+// executeWithKeepalive runs execute and, while it is still executing,
+// writes a pendingFrame every pendingKeepaliveInterval. Every frame extends
+// the connection deadline. Most commands finish before the first keepalive;
+// only long-held requests (e.g. awaiting user approval) produce keepalives.
+func (s *PipeServer) executeWithKeepalive(frames *frameWriter, execute func() TmuxResponse) TmuxResponse {
+	result := make(chan TmuxResponse, 1)
+	go func() {
+		result <- execute()
+	}()
+
+	ticker := time.NewTicker(pendingKeepaliveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case resp := <-result:
+			return resp
+		case <-ticker.C:
+			if !frames.writeFrame([]byte(pendingFrame)) {
+				// The client is gone; keep waiting so Execute's result is not
+				// abandoned mid-flight, but stop sending keepalives.
+				return <-result
+			}
+		}
+	}
+}
+
+// watchCancel reads the frames a streaming client sends after its request
+// and calls cancel when a cancel frame names id. It returns on the first
+// read error, which includes the connection being closed.
+func watchCancel(reader *bufio.Reader, id string, cancel context.CancelFunc) {
+	for {
+		raw, err := readRequestFrame(reader)
+		if err != nil {
+			return
+		}
+		cancelID, err := decodeCancel(raw)
+		if err != nil || cancelID != id {
+			slog.Debug("[ipc] ignoring unexpected client frame", "error", err)
+			continue
+		}
+		slog.Debug("[DEBUG-IPC-PIPE] request canceled by client", "id", id)
+		cancel()
+		return
+	}
 }
 
 func (s *PipeServer) writeResponse(conn net.Conn, resp TmuxResponse) {
+	(&frameWriter{conn: conn}).writeResponse(resp)
+}
+
+// frameWriter serializes the frames written to one connection by the
+// executing command, the keepalive loop and a restart aborting the request.
+// Once the final response is written or a write fails, later frames are
+// dropped.
+type frameWriter struct {
+	conn net.Conn
+	mu   sync.Mutex
+	done bool
+}
+
+// writeFrame writes raw and its delimiter, extending the connection deadline.
+// It reports whether the frame was written.
+func (w *frameWriter) writeFrame(raw []byte) bool {
+	return w.write(raw, false)
+}
+
+// write writes one frame; final marks the request as answered.
+func (w *frameWriter) write(raw []byte, final bool) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.done {
+		return false
+	}
+	if err := w.conn.SetDeadline(time.Now().Add(defaultPipeConnTimeout)); err != nil {
+		slog.Debug("[ipc] failed to extend connection deadline", "error", err)
+	}
+	if _, err := w.conn.Write(append(raw[:len(raw):len(raw)], '\n')); err != nil {
+		slog.Debug("[ipc] failed to write frame", "error", err)
+		w.done = true
+		return false
+	}
+	w.done = final
+	return true
+}
+
+// writeResponse writes the final frame and reports whether it was written.
+func (w *frameWriter) writeResponse(resp TmuxResponse) bool {
 	rawResp, err := encodeResponse(resp)
 	if err != nil {
 		slog.Warn("[ipc] failed to encode response", "error", err, "exitCode", resp.ExitCode)
 		rawResp = []byte(`{"exit_code":1,"stderr":"internal encode error\n"}`)
 	}
-	if _, err := conn.Write(rawResp); err != nil {
-		slog.Debug("[ipc] failed to write response", "error", err)
-		return
+	return w.write(rawResp, true)
+}
+
+// writeKeptOpenResponse is writeResponse for a kept-open connection.
+func (w *frameWriter) writeKeptOpenResponse(resp TmuxResponse) bool {
+	rawResp, err := encodeKeptOpenResponse(resp)
+	if err != nil {
+		slog.Warn("[ipc] failed to encode response", "error", err, "exitCode", resp.ExitCode)
+		return w.writeResponse(TmuxResponse{ExitCode: 1, Stderr: "internal encode error\n"})
 	}
-	if _, err := conn.Write([]byte{'\n'}); err != nil {
-		slog.Debug("[ipc] failed to write response delimiter", "error", err)
+	return w.write(rawResp, true)
+}
+
+// chunkWriter sends output of a streaming command as chunk frames. It never
+// fails: once the client is gone the output is discarded so the command
+// still runs to completion.
+type chunkWriter struct {
+	frames *frameWriter
+	stream string
+}
+
+func (w chunkWriter) Write(p []byte) (int, error) {
+	for data := p; len(data) > 0; {
+		n := min(len(data), maxChunkDataBytes)
+		raw, err := encodeChunk(w.stream, data[:n])
+		if err != nil {
+			slog.Debug("[ipc] failed to encode output chunk", "error", err)
+		} else {
+			w.frames.writeFrame(raw)
+		}
+		data = data[n:]
 	}
+	return len(p), nil
 }
 
 func readRequestFrame(reader *bufio.Reader) ([]byte, error) {
@@ -257,39 +531,3 @@ func (s *PipeServer) releaseConnectionSlot() {
 		slog.Warn("[ipc] releaseConnectionSlot: no slot to release (possible double-release)")
 	}
 }
-
-// listenPipeWithCurrentUserDACL creates a Named Pipe listener restricted to the
-// current user. The DACL grants full access only to SYSTEM and the current
-// user's SID, preventing other local users from connecting.
-func listenPipeWithCurrentUserDACL(pipeName string) (net.Listener, error) {
-	securityDescriptor, err := pipeSecurityDescriptor()
-	if err != nil {
-		return nil, err
-	}
-	return winio.ListenPipe(pipeName, &winio.PipeConfig{
-		SecurityDescriptor: securityDescriptor,
-		MessageMode:        false,
-		InputBufferSize:    int32(maxPipeRequestBytes),
-		OutputBufferSize:   int32(maxPipeResponseBytes),
-	})
-}
-
-var validSIDPattern = regexp.MustCompile(`^S-1(-\d+)+$`)
-
-func pipeSecurityDescriptor() (string, error) {
-	current, err := user.Current()
-	if err != nil {
-		return "", fmt.Errorf("resolve current user: %w", err)
-	}
-	sid := strings.TrimSpace(current.Uid)
-	if sid == "" {
-		return "", errors.New("current user SID is unavailable")
-	}
-	if !validSIDPattern.MatchString(sid) {
-		return "", fmt.Errorf("current user SID has unexpected format: %s", sid)
-	}
-	// SDDL: D:P = protected DACL (no inheritance)
-	// (A;;GA;;;SY) = full access for SYSTEM
-	// (A;;GA;;;%s) = full access for current user SID
-	return fmt.Sprintf("D:P(A;;GA;;;SY)(A;;GA;;;%s)", sid), nil
-}
@@ -2,9 +2,13 @@ package ipc
 
 import (
 	"bufio"
+	"context"
 	"io"
+	"net"
 	"strings"
 	"testing"
+
+	"myT-x/internal/faultinject"
 )
 
 func TestReadRequestFrameWithinLimit(t *testing.T) {
@@ -50,3 +54,192 @@ func TestReadRequestFrameReturnsEOFOnEmptyInput(t *testing.T) {
 		t.Fatalf("readRequestFrame() error = %v, want io.EOF", err)
 	}
 }
+
+type streamingExecutorStub struct {
+	output []byte
+}
+
+func (e streamingExecutorStub) Execute(TmuxRequest) TmuxResponse {
+	return TmuxResponse{Stdout: string(e.output)}
+}
+
+func (e streamingExecutorStub) ExecuteStream(_ context.Context, _ TmuxRequest, stdout, stderr io.Writer) TmuxResponse {
+	_, _ = stdout.Write(e.output)
+	_, _ = stderr.Write([]byte("warn\n"))
+	return TmuxResponse{ExitCode: 3}
+}
+
+// blockingExecutorStub runs until its request is canceled.
+type blockingExecutorStub struct{}
+
+func (blockingExecutorStub) Execute(TmuxRequest) TmuxResponse {
+	return TmuxResponse{ExitCode: 1, Stderr: "not streamed\n"}
+}
+
+func (blockingExecutorStub) ExecuteStream(ctx context.Context, _ TmuxRequest, _, _ io.Writer) TmuxResponse {
+	<-ctx.Done()
+	return TmuxResponse{ExitCode: 130, Stderr: "canceled\n"}
+}
+
+// testAuthToken is the server token of tests that do not publish one.
+const testAuthToken = "test-token"
+
+// serveOnPipe runs handleConnection for one request and returns the client
+// end of the connection.
+func serveOnPipe(t *testing.T, executor CommandExecutor, req TmuxRequest) net.Conn {
+	t.Helper()
+	return serveOnPipeWithFaults(t, executor, nil, req)
+}
+
+// serveOnPipeWithFaults is serveOnPipe with a fault injector.
+func serveOnPipeWithFaults(t *testing.T, executor CommandExecutor, faults *faultinject.Injector, req TmuxRequest) net.Conn {
+	t.Helper()
+	server, client := net.Pipe()
+	s := NewPipeServer(`\\.\pipe\myT-x-test`, executor)
+	s.token = testAuthToken
+	s.faults = faults
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.handleConnection(server)
+	}()
+	t.Cleanup(func() {
+		client.Close()
+		<-done
+	})
+	if req.Token == "" {
+		req.Token = testAuthToken
+	}
+	raw, err := encodeRequest(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Write(append(raw, '\n')); err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestHandleConnectionStreamsChunks(t *testing.T) {
+	// Large enough to be split into several chunks.
+	output := []byte(strings.Repeat("日本語 output\n", maxChunkDataBytes/8))
+	client := serveOnPipe(t, streamingExecutorStub{output: output}, TmuxRequest{Command: "run-shell", Stream: true})
+
+	var stdout, stderr strings.Builder
+	chunks := 0
+	resp, err := readResponse(client, func(chunk outputChunk) error {
+		chunks++
+		if chunk.Stream == streamStderr {
+			stderr.Write(chunk.Data)
+		} else {
+			stdout.Write(chunk.Data)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("readResponse() error = %v", err)
+	}
+	if resp.ExitCode != 3 || resp.Stdout != "" {
+		t.Fatalf("response = %+v, want exit 3 without stdout", resp)
+	}
+	if stdout.String() != string(output) || stderr.String() != "warn\n" {
+		t.Fatalf("streamed stdout %d bytes, stderr %q; want %d bytes and %q",
+			stdout.Len(), stderr.String(), len(output), "warn\n")
+	}
+	if chunks < 3 {
+		t.Fatalf("chunks = %d, want output split across several chunks", chunks)
+	}
+}
+
+func TestHandleConnectionWithoutStreamReturnsWholeOutput(t *testing.T) {
+	client := serveOnPipe(t, streamingExecutorStub{output: []byte("all\n")}, TmuxRequest{Command: "run-shell"})
+
+	resp, err := readResponse(client, nil)
+	if err != nil {
+		t.Fatalf("readResponse() error = %v", err)
+	}
+	if resp.Stdout != "all\n" {
+		t.Fatalf("response = %+v, want whole output", resp)
+	}
+}
+
+func TestHandleConnectionInjectsFaults(t *testing.T) {
+	tests := []struct {
+		spec string
+		want TmuxResponse
+	}{
+		{"fail=1", TmuxResponse{ExitCode: 1, Stderr: faultinject.ErrInjected.Error() + "\n"}},
+		{"partial=1", TmuxResponse{ExitCode: 1, Stdout: "ab", Stderr: faultinject.ErrInjected.Error() + "\n"}},
+		{"scope=git,fail=1", TmuxResponse{Stdout: "abcd"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			faults, err := faultinject.Parse(tt.spec, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			client := serveOnPipeWithFaults(t, streamingExecutorStub{output: []byte("abcd")}, faults, TmuxRequest{Command: "run-shell"})
+			resp, err := readResponse(client, nil)
+			if err != nil {
+				t.Fatalf("readResponse() error = %v", err)
+			}
+			if resp != tt.want {
+				t.Fatalf("response = %+v, want %+v", resp, tt.want)
+			}
+		})
+	}
+
+	t.Run("drop", func(t *testing.T) {
+		faults, err := faultinject.Parse("drop=1", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		client := serveOnPipeWithFaults(t, streamingExecutorStub{output: []byte("abcd")}, faults, TmuxRequest{Command: "run-shell"})
+		if _, err := readResponse(client, nil); err == nil {
+			t.Fatal("readResponse() error = nil, want closed connection")
+		}
+	})
+}
+
+func TestHandleConnectionCancelsStreamingRequest(t *testing.T) {
+	client := serveOnPipe(t, blockingExecutorStub{}, TmuxRequest{Command: "run-shell", Stream: true, ID: "req-1"})
+
+	// A cancel frame for another request is ignored.
+	writeCancel(client, "req-0")
+	writeCancel(client, "req-1")
+
+	resp, err := readResponse(client, func(outputChunk) error { return nil })
+	if err != nil {
+		t.Fatalf("readResponse() error = %v", err)
+	}
+	if resp.ExitCode != 130 || resp.Stderr != "canceled\n" {
+		t.Fatalf("response = %+v, want canceled command", resp)
+	}
+}
+
+func TestHandleConnectionKeepOpenServesFollowingRequests(t *testing.T) {
+	client := serveOnPipe(t, streamingExecutorStub{output: []byte("out\n")}, TmuxRequest{Command: "first", KeepOpen: true})
+	reader := bufio.NewReader(client)
+
+	frame, err := readFinalFrame(client, reader, nil)
+	if err != nil || !frame.KeepOpen || frame.Stdout != "out\n" {
+		t.Fatalf("first frame = %+v, %v; want kept-open response", frame, err)
+	}
+
+	// A streaming request ends the kept-open connection.
+	streamReq := TmuxRequest{Command: "second", KeepOpen: true, Stream: true, Token: testAuthToken}
+	raw, err := encodeRequest(streamReq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Write(append(raw, '\n')); err != nil {
+		t.Fatal(err)
+	}
+	frame, err = readFinalFrame(client, reader, func(outputChunk) error { return nil })
+	if err != nil || frame.KeepOpen || frame.ExitCode != 3 {
+		t.Fatalf("second frame = %+v, %v; want final streamed response", frame, err)
+	}
+	if _, err := readDelimitedFrame(reader, maxPipeResponseBytes); err != io.EOF {
+		t.Fatalf("read after streaming request error = %v, want io.EOF", err)
+	}
+}
@@ -1,20 +1,18 @@
 package ipc
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"io"
 	"log/slog"
 	"os"
 	"os/user"
-	"regexp"
 	"strings"
 
 	"myT-x/internal/userutil"
 )
 
-var pipeNamePattern = regexp.MustCompile(`(?i)^\\\\\.\\pipe\\myT-x-[a-z0-9._-]{1,128}$`)
-
-const defaultPipePrefix = `\\.\pipe\myT-x-`
-
 // TmuxRequest is a single tmux-compatible command request.
 type TmuxRequest struct {
 	Command    string            `json:"command"`
@@ -22,6 +20,23 @@ type TmuxRequest struct {
 	Args       []string          `json:"args,omitempty"`
 	Env        map[string]string `json:"env,omitempty"`
 	CallerPane string            `json:"caller_pane,omitempty"`
+	// Stream asks the server to send output as chunk frames while the
+	// command runs. Servers without streaming support ignore it and send
+	// the whole output in the final response.
+	Stream bool `json:"stream,omitempty"`
+	// ID identifies the request in a cancel frame. SendStream sets it.
+	ID string `json:"id,omitempty"`
+	// KeepOpen asks the server to read another request from the connection
+	// after the response instead of closing it. The server confirms it in
+	// the final frame. BatchClient sets it.
+	KeepOpen bool `json:"keep_open,omitempty"`
+	// Token authenticates the request to the server. The clients of this
+	// package set it from the token file the server publishes.
+	Token string `json:"token,omitempty"`
+	// CallerPID is the process ID of the connected client as reported by
+	// the OS, or 0 when the transport cannot tell. The server sets it; it is
+	// never read from the wire, so unlike CallerPane a client cannot spoof it.
+	CallerPID uint32 `json:"-"`
 }
 
 // TmuxResponse is a tmux-compatible command response.
@@ -44,11 +59,32 @@ type CommandExecutor interface {
 	Execute(req TmuxRequest) TmuxResponse
 }
 
+// StreamingExecutor is a CommandExecutor that can write the output of
+// long-running commands to stdout and stderr as it is produced. Output
+// written there must not be repeated in the returned response, which carries
+// the exit code and any output that was not streamed. ctx is canceled when
+// the client cancels the request or the server stops; the executor should
+// abort the command and still return a response.
+type StreamingExecutor interface {
+	CommandExecutor
+	ExecuteStream(ctx context.Context, req TmuxRequest, stdout, stderr io.Writer) TmuxResponse
+}
+
+// ContextExecutor is a CommandExecutor that can abandon a request: ctx is
+// canceled when the server gives up on it (a restart's drain deadline or
+// Stop). The executor should then stop waiting, e.g. for user approval, and
+// return.
+type ContextExecutor interface {
+	CommandExecutor
+	ExecuteContext(ctx context.Context, req TmuxRequest) TmuxResponse
+}
+
 func sanitizeUsername(value string) string {
 	return userutil.SanitizeUsername(value)
 }
 
-// DefaultPipeName returns the pipe path to use. If the GO_TMUX_PIPE
+// DefaultPipeName returns the IPC address to use: a Named Pipe path on
+// Windows, a Unix domain socket path elsewhere. If the GO_TMUX_PIPE
 // environment variable is set and passes pattern validation, its value is
 // used; otherwise a per-user default is constructed from the current username.
 func DefaultPipeName() string {
@@ -72,7 +108,7 @@ func DefaultPipeName() string {
 				"error", err)
 		}
 	}
-	return defaultPipePrefix + sanitizeUsername(username)
+	return pipeAddress(sanitizeUsername(username))
 }
 
 func trustedPipeNameFromEnv() (string, bool) {
@@ -80,7 +116,7 @@ func trustedPipeNameFromEnv() (string, bool) {
 	if value == "" {
 		return "", false
 	}
-	if !pipeNamePattern.MatchString(value) {
+	if !validPipeName(value) {
 		slog.Warn("[ipc] GO_TMUX_PIPE rejected: value does not match allowed pattern", "value", value)
 		return "", false
 	}
@@ -117,11 +153,85 @@ func encodeResponse(resp TmuxResponse) ([]byte, error) {
 	return json.Marshal(resp)
 }
 
-func decodeResponse(raw []byte) (TmuxResponse, error) {
-	var resp TmuxResponse
-	err := json.Unmarshal(raw, &resp)
-	if err != nil {
-		return TmuxResponse{}, err
+// encodeKeptOpenResponse encodes a final response that keeps the connection
+// open for the next request.
+func encodeKeptOpenResponse(resp TmuxResponse) ([]byte, error) {
+	return json.Marshal(responseFrame{TmuxResponse: resp, KeepOpen: true})
+}
+
+// pendingFrame is written by the server while a request is still executing
+// (for example, held for user approval) so the client extends its deadline
+// and keeps reading until the real response arrives.
+const pendingFrame = `{"pending":true}`
+
+// retryFrame is the final frame for a request the server did not execute
+// because it is restarting its listener, or because it gave up the idle
+// kept-open connection the request arrived on. The client redials and
+// resends.
+const retryFrame = `{"retry":true}`
+
+// Output stream names of a chunk frame.
+const (
+	streamStdout = "stdout"
+	streamStderr = "stderr"
+)
+
+// maxChunkDataBytes bounds the output carried by one chunk frame. Data is
+// base64-encoded, so a frame stays well below maxPipeResponseBytes.
+const maxChunkDataBytes = 16 * 1024
+
+// outputChunk is output written by a streaming command. Data is raw bytes
+// (base64 in JSON) so a chunk boundary may split a UTF-8 sequence safely.
+type outputChunk struct {
+	Stream string `json:"stream"`
+	Data   []byte `json:"data"`
+}
+
+// responseFrame is one frame read by the client: a keepalive, an output
+// chunk, a retry request, or the final response.
+type responseFrame struct {
+	TmuxResponse
+	Pending bool         `json:"pending,omitempty"`
+	Retry   bool         `json:"retry,omitempty"`
+	Chunk   *outputChunk `json:"chunk,omitempty"`
+	// KeepOpen on the final response confirms that the server reads the
+	// next request from the connection. Without it the client must redial.
+	KeepOpen bool `json:"keep_open,omitempty"`
+}
+
+func encodeChunk(stream string, data []byte) ([]byte, error) {
+	return json.Marshal(responseFrame{Chunk: &outputChunk{Stream: stream, Data: data}})
+}
+
+// decodeFrame decodes one response frame. The frame is final when none of
+// Pending, Retry, and Chunk is set.
+func decodeFrame(raw []byte) (responseFrame, error) {
+	var frame responseFrame
+	if err := json.Unmarshal(raw, &frame); err != nil {
+		return responseFrame{}, err
+	}
+	return frame, nil
+}
+
+// cancelFrame is written by a streaming client after its request to abort
+// it, for example when the user presses Ctrl+C. The server cancels the
+// request named by Cancel and still sends its final response.
+type cancelFrame struct {
+	Cancel string `json:"cancel"`
+}
+
+func encodeCancel(id string) ([]byte, error) {
+	return json.Marshal(cancelFrame{Cancel: id})
+}
+
+// decodeCancel returns the request ID named by a cancel frame.
+func decodeCancel(raw []byte) (string, error) {
+	var frame cancelFrame
+	if err := json.Unmarshal(raw, &frame); err != nil {
+		return "", err
+	}
+	if frame.Cancel == "" {
+		return "", errors.New("cancel frame without request id")
 	}
-	return resp, nil
+	return frame.Cancel, nil
 }
@@ -2,60 +2,9 @@ package ipc
 
 import (
 	"encoding/json"
-	"strings"
 	"testing"
 )
 
-func TestDefaultPipeNameHonorsTrustedEnvOverride(t *testing.T) {
-	t.Setenv("GO_TMUX_PIPE", `\\.\pipe\myT-x-ci_pipe`)
-
-	if got := DefaultPipeName(); got != `\\.\pipe\myT-x-ci_pipe` {
-		t.Fatalf("DefaultPipeName() = %q, want trusted env override", got)
-	}
-}
-
-func TestDefaultPipeNameRejectsUntrustedEnvOverride(t *testing.T) {
-	t.Setenv("GO_TMUX_PIPE", `\\.\pipe\other-app`)
-	t.Setenv("USERNAME", "unit-tester")
-
-	got := DefaultPipeName()
-	if got == `\\.\pipe\other-app` {
-		t.Fatalf("DefaultPipeName() unexpectedly accepted untrusted env override")
-	}
-	if !strings.HasPrefix(got, defaultPipePrefix) {
-		t.Fatalf("DefaultPipeName() = %q, want %q prefix", got, defaultPipePrefix)
-	}
-}
-
-func TestDefaultPipeNameSanitizesUsername(t *testing.T) {
-	t.Setenv("GO_TMUX_PIPE", "")
-	t.Setenv("USERNAME", "unit user!")
-
-	got := DefaultPipeName()
-	want := `\\.\pipe\myT-x-unit_user_`
-	if got != want {
-		t.Fatalf("DefaultPipeName() = %q, want %q", got, want)
-	}
-}
-
-func TestDefaultPipeNameFallbackWhenUsernameEmpty(t *testing.T) {
-	t.Setenv("GO_TMUX_PIPE", "")
-	t.Setenv("USERNAME", "")
-
-	got := DefaultPipeName()
-
-	// When USERNAME is empty, user.Current() may succeed (returning OS user)
-	// or fail (returning "unknown" via sanitizeUsername fallback).
-	// Either way the pipe name must have a non-empty suffix after the prefix.
-	if !strings.HasPrefix(got, defaultPipePrefix) {
-		t.Fatalf("DefaultPipeName() = %q, want prefix %q", got, defaultPipePrefix)
-	}
-	suffix := strings.TrimPrefix(got, defaultPipePrefix)
-	if suffix == "" {
-		t.Fatalf("DefaultPipeName() = %q, suffix after prefix must not be empty", got)
-	}
-}
-
 func TestDecodeRequest_NilFieldsInitializedToEmpty(t *testing.T) {
 	// JSON with only "command" — Flags, Args, Env are absent (will be nil after unmarshal).
 	raw, err := json.Marshal(map[string]any{"command": "list-sessions"})
@@ -117,3 +66,53 @@ func TestDecodeRequest_PreservesExplicitValues(t *testing.T) {
 		t.Errorf("decodeRequest: Env = %v, want 1 entry", req.Env)
 	}
 }
+
+func TestDecodeFrame_DistinguishesFrameKinds(t *testing.T) {
+	if frame, err := decodeFrame([]byte(pendingFrame)); err != nil || !frame.Pending || frame.Chunk != nil {
+		t.Fatalf("decodeFrame(pendingFrame) = %+v, err = %v, want pending", frame, err)
+	}
+	if frame, err := decodeFrame([]byte(retryFrame)); err != nil || !frame.Retry || frame.Pending {
+		t.Fatalf("decodeFrame(retryFrame) = %+v, err = %v, want retry", frame, err)
+	}
+
+	raw, err := encodeResponse(TmuxResponse{ExitCode: 1, Stderr: "denied\n"})
+	if err != nil {
+		t.Fatalf("encodeResponse error = %v", err)
+	}
+	frame, err := decodeFrame(raw)
+	if err != nil || frame.Pending || frame.Retry || frame.Chunk != nil {
+		t.Fatalf("decodeFrame(response) = %+v, err = %v", frame, err)
+	}
+	if frame.ExitCode != 1 || frame.Stderr != "denied\n" {
+		t.Fatalf("decodeFrame(response) = %+v", frame.TmuxResponse)
+	}
+
+	// A chunk may end inside a multi-byte UTF-8 sequence.
+	partial := []byte("日本")[:4]
+	raw, err = encodeChunk(streamStdout, partial)
+	if err != nil {
+		t.Fatalf("encodeChunk error = %v", err)
+	}
+	frame, err = decodeFrame(raw)
+	if err != nil || frame.Chunk == nil {
+		t.Fatalf("decodeFrame(chunk) = %+v, err = %v, want chunk", frame, err)
+	}
+	if frame.Chunk.Stream != streamStdout || string(frame.Chunk.Data) != string(partial) {
+		t.Fatalf("decodeFrame(chunk).Chunk = %+v, want stdout %q", frame.Chunk, partial)
+	}
+}
+
+func TestDecodeCancel(t *testing.T) {
+	raw, err := encodeCancel("req-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id, err := decodeCancel(raw); err != nil || id != "req-1" {
+		t.Fatalf("decodeCancel(%s) = %q, %v; want req-1", raw, id, err)
+	}
+	for _, raw := range []string{`{}`, `{"cancel":""}`, `not json`} {
+		if _, err := decodeCancel([]byte(raw)); err == nil {
+			t.Fatalf("decodeCancel(%s) error = nil, want error", raw)
+		}
+	}
+}
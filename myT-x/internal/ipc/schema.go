@@ -0,0 +1,162 @@
+package ipc
+
+import (
+	"reflect"
+	"strings"
+)
+
+// JSONSchemaDialect is the JSON Schema version of ProtocolSchemas.
+const JSONSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// ProtocolError is a failure the server reports before it runs a command.
+// Like a failed command, it arrives as a TmuxResponse with exit code 1.
+type ProtocolError struct {
+	Name string `json:"name"`
+	// Message is the start of the response's stderr.
+	Message     string `json:"message"`
+	Description string `json:"description"`
+}
+
+// ProtocolErrors lists the failures of the transport itself, independent of
+// the command sent.
+func ProtocolErrors() []ProtocolError {
+	return []ProtocolError{
+		{
+			Name:        "InvalidRequest",
+			Message:     invalidRequestMessage,
+			Description: "The request frame could not be read or is not a JSON TmuxRequest.",
+		},
+		{
+			Name:        "Unauthorized",
+			Message:     strings.TrimSpace(unauthorizedResponse.Stderr),
+			Description: "The request does not carry the token the server publishes next to its pipe.",
+		},
+		{
+			Name:        "ServerBusy",
+			Message:     strings.TrimSpace(serverBusyResponse.Stderr),
+			Description: "The server already serves its maximum number of connections.",
+		},
+	}
+}
+
+// fieldDescriptions documents the wire fields in the exported schemas, by
+// "Type.json_name".
+var fieldDescriptions = map[string]string{
+	"TmuxRequest.command":     "Command name, e.g. split-window.",
+	"TmuxRequest.flags":       "Flags by name including the dash, e.g. {\"-t\": \"%1\"}. Values are strings, booleans, or numbers.",
+	"TmuxRequest.args":        "Positional arguments.",
+	"TmuxRequest.env":         "Environment for panes the command creates (-e).",
+	"TmuxRequest.caller_pane": "TMUX_PANE of the caller; the default target.",
+	"TmuxRequest.stream":      "Send output as chunk frames while the command runs.",
+	"TmuxRequest.id":          "Request ID named by a cancel frame.",
+	"TmuxRequest.keep_open":   "Read another request from the connection after the response.",
+	"TmuxRequest.token":       "Auth token from the file the server publishes next to its pipe.",
+	"TmuxResponse.exit_code":  "0 on success, 1 on failure.",
+	"ResponseFrame.pending":   "Keepalive: the request is still running, e.g. held for approval.",
+	"ResponseFrame.retry":     "The request was not run; redial and resend it.",
+	"ResponseFrame.chunk":     "Output of a streaming request.",
+	"ResponseFrame.keep_open": "On the final frame: the server reads the next request from the connection.",
+	"CancelFrame.cancel":      "ID of the streaming request to abort.",
+	"OutputChunk.stream":      "stdout or stderr.",
+}
+
+// ProtocolSchemas returns the JSON Schemas of the messages exchanged over
+// the pipe, by name, generated from the Go types the server decodes. Every
+// frame is one JSON document; see ResponseFrame for what the client reads.
+func ProtocolSchemas() map[string]any {
+	return map[string]any{
+		"TmuxRequest":            objectSchema("TmuxRequest", reflect.TypeFor[TmuxRequest]()),
+		"TmuxResponse":           objectSchema("TmuxResponse", reflect.TypeFor[TmuxResponse]()),
+		"ResponseFrame":          objectSchema("ResponseFrame", reflect.TypeFor[responseFrame]()),
+		"CancelFrame":            objectSchema("CancelFrame", reflect.TypeFor[cancelFrame]()),
+		"ServerInfo":             objectSchema("ServerInfo", reflect.TypeFor[ServerInfo]()),
+		"MCPStdioResolvePayload": objectSchema("MCPStdioResolvePayload", reflect.TypeFor[MCPStdioResolvePayload]()),
+	}
+}
+
+// schemaNames names the nested struct types in the exported schemas.
+var schemaNames = map[reflect.Type]string{
+	reflect.TypeFor[outputChunk]():   "OutputChunk",
+	reflect.TypeFor[ServerSession](): "ServerSession",
+}
+
+// objectSchema describes a struct by its JSON encoding: fields without
+// omitempty are required, embedded structs are flattened.
+func objectSchema(name string, t reflect.Type) map[string]any {
+	properties := map[string]any{}
+	var required []string
+	addStructFields(name, t, properties, &required)
+	schema := map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func addStructFields(name string, t reflect.Type, properties map[string]any, required *[]string) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		jsonName, options, _ := strings.Cut(tag, ",")
+		if field.Anonymous && jsonName == "" {
+			addStructFields(name, field.Type, properties, required)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if jsonName == "" {
+			jsonName = field.Name
+		}
+		schema := typeSchema(field.Type)
+		if description, ok := fieldDescriptions[name+"."+jsonName]; ok {
+			schema["description"] = description
+		}
+		properties[jsonName] = schema
+		if !strings.Contains(options, "omitempty") {
+			*required = append(*required, jsonName)
+		}
+	}
+}
+
+func typeSchema(t reflect.Type) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "contentEncoding": "base64"}
+		}
+		return map[string]any{"type": "array", "items": typeSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": typeSchema(t.Elem())}
+	case reflect.Interface:
+		// Flag values: string, bool, or JSON number.
+		return map[string]any{"type": []string{"string", "boolean", "number"}}
+	case reflect.Struct:
+		name := schemaNames[t]
+		if name == "" {
+			name = t.Name()
+		}
+		return objectSchema(name, t)
+	default:
+		return map[string]any{}
+	}
+}
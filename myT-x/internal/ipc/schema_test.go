@@ -0,0 +1,49 @@
+package ipc
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestProtocolSchemasFollowJSONTags(t *testing.T) {
+	schemas := ProtocolSchemas()
+
+	request := schemas["TmuxRequest"].(map[string]any)
+	properties := request["properties"].(map[string]any)
+	for _, name := range []string{"command", "flags", "args", "env", "caller_pane", "stream", "id", "keep_open", "token"} {
+		if _, ok := properties[name]; !ok {
+			t.Fatalf("TmuxRequest schema is missing %q", name)
+		}
+	}
+	if required, _ := request["required"].([]string); !slices.Contains(required, "command") {
+		t.Fatalf("TmuxRequest required = %v, want command", required)
+	}
+
+	// ResponseFrame embeds TmuxResponse; its fields are flattened.
+	frame := schemas["ResponseFrame"].(map[string]any)["properties"].(map[string]any)
+	for _, name := range []string{"exit_code", "stdout", "stderr", "chunk", "pending"} {
+		if _, ok := frame[name]; !ok {
+			t.Fatalf("ResponseFrame schema is missing %q", name)
+		}
+	}
+}
+
+func TestProtocolErrorsMatchResponses(t *testing.T) {
+	for _, protocolErr := range ProtocolErrors() {
+		if protocolErr.Name == "" || protocolErr.Message == "" {
+			t.Fatalf("incomplete protocol error %#v", protocolErr)
+		}
+	}
+	errs := ProtocolErrors()
+	byName := make(map[string]ProtocolError, len(errs))
+	for _, protocolErr := range errs {
+		byName[protocolErr.Name] = protocolErr
+	}
+	if !strings.HasPrefix(serverBusyResponse.Stderr, byName["ServerBusy"].Message) {
+		t.Fatalf("ServerBusy message %q does not match %q", byName["ServerBusy"].Message, serverBusyResponse.Stderr)
+	}
+	if !strings.HasPrefix(unauthorizedResponse.Stderr, byName["Unauthorized"].Message) {
+		t.Fatalf("Unauthorized message %q does not match %q", byName["Unauthorized"].Message, unauthorizedResponse.Stderr)
+	}
+}
@@ -0,0 +1,65 @@
+package ipc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ServerInfoCommand asks the server that owns the pipe to describe itself.
+const ServerInfoCommand = "server-info"
+
+// ErrNoServer is returned by ProbeServer when nothing listens on the pipe.
+var ErrNoServer = errors.New("no server is listening on the pipe")
+
+// ServerInfo describes a live server and the sessions it owns.
+type ServerInfo struct {
+	// PID is the process that serves the pipe and owns the pane processes.
+	PID      int             `json:"pid"`
+	Sessions []ServerSession `json:"sessions"`
+}
+
+// ServerSession is one session of a live server.
+type ServerSession struct {
+	Name  string   `json:"name"`
+	Panes []string `json:"panes"`
+}
+
+// PaneCount returns the number of panes across all sessions.
+func (info ServerInfo) PaneCount() int {
+	count := 0
+	for _, session := range info.Sessions {
+		count += len(session.Panes)
+	}
+	return count
+}
+
+// ProbeServer asks the server on pipeName for its ServerInfo. It returns
+// ErrNoServer when the pipe does not exist or refuses the connection.
+func ProbeServer(pipeName string) (ServerInfo, error) {
+	resp, err := Send(pipeName, TmuxRequest{Command: ServerInfoCommand})
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) || IsConnectionError(err) {
+			return ServerInfo{}, ErrNoServer
+		}
+		return ServerInfo{}, fmt.Errorf("probe server: %w", err)
+	}
+	return decodeServerInfo(resp)
+}
+
+func decodeServerInfo(resp TmuxResponse) (ServerInfo, error) {
+	if resp.ExitCode != 0 {
+		return ServerInfo{}, fmt.Errorf("probe server: %s exited %d: %s",
+			ServerInfoCommand, resp.ExitCode, strings.TrimSpace(resp.Stderr))
+	}
+	var info ServerInfo
+	if err := json.Unmarshal([]byte(resp.Stdout), &info); err != nil {
+		return ServerInfo{}, fmt.Errorf("probe server: decode %s: %w", ServerInfoCommand, err)
+	}
+	if info.PID <= 0 {
+		return ServerInfo{}, fmt.Errorf("probe server: invalid pid %d", info.PID)
+	}
+	return info, nil
+}
@@ -0,0 +1,37 @@
+package ipc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecodeServerInfo(t *testing.T) {
+	info, err := decodeServerInfo(TmuxResponse{
+		Stdout: `{"pid":42,"sessions":[{"name":"api","panes":["%1","%2"]},{"name":"web","panes":["%3"]}]}` + "\n",
+	})
+	if err != nil {
+		t.Fatalf("decodeServerInfo() error = %v", err)
+	}
+	want := ServerInfo{PID: 42, Sessions: []ServerSession{
+		{Name: "api", Panes: []string{"%1", "%2"}},
+		{Name: "web", Panes: []string{"%3"}},
+	}}
+	if !reflect.DeepEqual(info, want) {
+		t.Fatalf("decodeServerInfo() = %+v, want %+v", info, want)
+	}
+	if got := info.PaneCount(); got != 3 {
+		t.Fatalf("PaneCount() = %d, want 3", got)
+	}
+}
+
+func TestDecodeServerInfoRejectsInvalidResponses(t *testing.T) {
+	for name, resp := range map[string]TmuxResponse{
+		"unknown command": {ExitCode: 1, Stderr: "unknown command: server-info\n"},
+		"not json":        {Stdout: "ok\n"},
+		"missing pid":     {Stdout: `{"sessions":[]}`},
+	} {
+		if _, err := decodeServerInfo(resp); err == nil {
+			t.Errorf("%s: decodeServerInfo() error = nil", name)
+		}
+	}
+}
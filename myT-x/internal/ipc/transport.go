@@ -0,0 +1,29 @@
+package ipc
+
+import (
+	"net"
+	"time"
+)
+
+// Listener creates the server side of the IPC channel at address.
+type Listener interface {
+	Listen(address string) (net.Listener, error)
+}
+
+// Dialer connects to the IPC channel at address.
+type Dialer interface {
+	Dial(address string, timeout time.Duration) (net.Conn, error)
+}
+
+// Transport carries the IPC channel between the tmux shim and the server.
+// Windows uses Named Pipes and other platforms Unix domain sockets; the
+// address is a pipe name or a socket path as returned by DefaultPipeName.
+type Transport interface {
+	Listener
+	Dialer
+}
+
+// PlatformTransport returns the transport selected for the running GOOS.
+func PlatformTransport() Transport {
+	return platformTransport
+}
@@ -0,0 +1,146 @@
+//go:build !windows
+
+package ipc
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
+)
+
+var socketNamePattern = regexp.MustCompile(`(?i)^myT-x-[a-z0-9._-]{1,128}\.sock$`)
+
+const (
+	socketNamePrefix = "myT-x-"
+	socketNameSuffix = ".sock"
+	// maxSocketPathLen is the sun_path limit of macOS (104 bytes, including
+	// the terminating NUL); Linux allows 108.
+	maxSocketPathLen = 103
+)
+
+var platformTransport Transport = unixSocketTransport{}
+
+// unixSocketTransport carries the IPC channel over Unix domain sockets.
+type unixSocketTransport struct{}
+
+// Listen creates the socket at address, readable only by the current user.
+// A socket file left behind by a crashed server is replaced; one that still
+// accepts connections is reported as in use.
+func (unixSocketTransport) Listen(address string) (net.Listener, error) {
+	if err := ensureSocketDir(filepath.Dir(address)); err != nil {
+		return nil, err
+	}
+	if err := removeStaleSocket(address); err != nil {
+		return nil, err
+	}
+	listener, err := net.Listen("unix", address)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(address, 0o600); err != nil {
+		_ = listener.Close()
+		return nil, fmt.Errorf("restrict socket permissions: %w", err)
+	}
+	return listener, nil
+}
+
+func (unixSocketTransport) Dial(address string, timeout time.Duration) (net.Conn, error) {
+	return net.DialTimeout("unix", address, timeout)
+}
+
+// pipeAddress returns the default socket path of username. The socket lives
+// in $XDG_RUNTIME_DIR/myT-x, or in a per-uid directory under the temp dir
+// when that is unset or the path would exceed the socket path limit.
+func pipeAddress(username string) string {
+	name := socketNamePrefix + username + socketNameSuffix
+	for _, dir := range socketDirCandidates() {
+		if path := filepath.Join(dir, name); len(path) <= maxSocketPathLen {
+			return path
+		}
+	}
+	// Long usernames still get a usable path; Listen reports the error.
+	return filepath.Join("/tmp", fmt.Sprintf("myT-x-%d", os.Getuid()), name)
+}
+
+func socketDirCandidates() []string {
+	uidDir := fmt.Sprintf("myT-x-%d", os.Getuid())
+	var dirs []string
+	if runtimeDir := strings.TrimSpace(os.Getenv("XDG_RUNTIME_DIR")); filepath.IsAbs(runtimeDir) {
+		dirs = append(dirs, filepath.Join(runtimeDir, "myT-x"))
+	}
+	return append(dirs, filepath.Join(os.TempDir(), uidDir), filepath.Join("/tmp", uidDir))
+}
+
+// authTokenPath returns the token file of the socket at pipeName. It lives
+// next to the socket, in the directory ensureSocketDir restricts to the
+// current user.
+func authTokenPath(pipeName string) (string, error) {
+	return pipeName + authTokenFileSuffix, nil
+}
+
+// validPipeName reports whether name may be used as a GO_TMUX_PIPE override.
+func validPipeName(name string) bool {
+	return filepath.IsAbs(name) && len(name) <= maxSocketPathLen &&
+		socketNamePattern.MatchString(filepath.Base(name))
+}
+
+// ensureSocketDir creates dir for the current user only. An existing dir
+// must belong to the current user and must not be writable by others, so
+// another local user cannot plant a socket there.
+func ensureSocketDir(dir string) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("create socket dir: %w", err)
+	}
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return fmt.Errorf("stat socket dir: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("socket dir %s is not a directory", dir)
+	}
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok && int(stat.Uid) != os.Getuid() {
+		return fmt.Errorf("socket dir %s is owned by uid %d", dir, stat.Uid)
+	}
+	if info.Mode().Perm()&0o022 != 0 {
+		return fmt.Errorf("socket dir %s is writable by other users", dir)
+	}
+	return nil
+}
+
+// removeStaleSocket removes a socket file at address that no server accepts
+// connections on anymore.
+func removeStaleSocket(address string) error {
+	info, err := os.Lstat(address)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("stat socket: %w", err)
+	}
+	if info.Mode().Type() != fs.ModeSocket {
+		return fmt.Errorf("%s exists and is not a socket", address)
+	}
+	if conn, err := net.DialTimeout("unix", address, defaultPipeDialTimeout); err == nil {
+		_ = conn.Close()
+		return fmt.Errorf("%s is in use by another server", address)
+	}
+	slog.Debug("[ipc] removing stale socket", "path", address)
+	if err := os.Remove(address); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("remove stale socket: %w", err)
+	}
+	return nil
+}
+
+// peerProcessID returns 0: callers are identified by process only on
+// Windows, where pane processes are mapped to panes.
+func peerProcessID(net.Conn) uint32 {
+	return 0
+}
@@ -0,0 +1,174 @@
+//go:build !windows
+
+package ipc
+
+import (
+	"errors"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDefaultPipeNameUsesRuntimeDirSocket(t *testing.T) {
+	runtimeDir := t.TempDir()
+	t.Setenv("GO_TMUX_PIPE", "")
+	t.Setenv("XDG_RUNTIME_DIR", runtimeDir)
+	t.Setenv("USERNAME", "unit user!")
+
+	got := DefaultPipeName()
+	want := filepath.Join(runtimeDir, "myT-x", "myT-x-unit_user_.sock")
+	if got != want {
+		t.Fatalf("DefaultPipeName() = %q, want %q", got, want)
+	}
+}
+
+func TestDefaultPipeNameFallsBackWithoutRuntimeDir(t *testing.T) {
+	t.Setenv("GO_TMUX_PIPE", "")
+	t.Setenv("XDG_RUNTIME_DIR", "")
+	t.Setenv("USERNAME", "unit-tester")
+
+	got := DefaultPipeName()
+	if !filepath.IsAbs(got) || filepath.Base(got) != "myT-x-unit-tester.sock" {
+		t.Fatalf("DefaultPipeName() = %q, want an absolute myT-x-unit-tester.sock path", got)
+	}
+	if len(got) > maxSocketPathLen {
+		t.Fatalf("DefaultPipeName() = %q exceeds %d bytes", got, maxSocketPathLen)
+	}
+}
+
+func TestDefaultPipeNameShortensLongRuntimeDir(t *testing.T) {
+	t.Setenv("GO_TMUX_PIPE", "")
+	t.Setenv("XDG_RUNTIME_DIR", "/"+strings.Repeat("d", maxSocketPathLen))
+	t.Setenv("USERNAME", "unit-tester")
+
+	if got := DefaultPipeName(); len(got) > maxSocketPathLen {
+		t.Fatalf("DefaultPipeName() = %q exceeds %d bytes", got, maxSocketPathLen)
+	}
+}
+
+func TestDefaultPipeNameEnvOverride(t *testing.T) {
+	t.Setenv("USERNAME", "unit-tester")
+	t.Setenv("GO_TMUX_PIPE", "/run/user/1000/myT-x/myT-x-ci_pipe.sock")
+	if got := DefaultPipeName(); got != "/run/user/1000/myT-x/myT-x-ci_pipe.sock" {
+		t.Fatalf("DefaultPipeName() = %q, want trusted env override", got)
+	}
+
+	for _, untrusted := range []string{"relative/myT-x-ci.sock", "/tmp/other-app.sock", `\\.\pipe\myT-x-ci`} {
+		t.Setenv("GO_TMUX_PIPE", untrusted)
+		if got := DefaultPipeName(); got == untrusted {
+			t.Fatalf("DefaultPipeName() accepted untrusted override %q", untrusted)
+		}
+	}
+}
+
+// shortSocketPath returns a socket path under a short temp dir; t.TempDir
+// paths can exceed the socket path limit on macOS.
+func shortSocketPath(t *testing.T) string {
+	t.Helper()
+	dir, err := os.MkdirTemp("", "mytx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return filepath.Join(dir, "sock", "myT-x-test.sock")
+}
+
+func TestUnixSocketTransportListenAndDial(t *testing.T) {
+	address := shortSocketPath(t)
+	transport := PlatformTransport()
+
+	if _, err := transport.Dial(address, time.Second); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("Dial() before Listen error = %v, want ErrNotExist", err)
+	}
+
+	listener, err := transport.Listen(address)
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	info, err := os.Stat(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Fatalf("socket mode = %v, want 0600", perm)
+	}
+	if dirInfo, err := os.Stat(filepath.Dir(address)); err != nil || dirInfo.Mode().Perm() != 0o700 {
+		t.Fatalf("socket dir mode = %v, %v; want 0700", dirInfo.Mode().Perm(), err)
+	}
+
+	if _, err := transport.Listen(address); err == nil {
+		t.Fatal("second Listen() on a live socket error = nil")
+	}
+
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err == nil {
+			accepted <- conn
+		}
+	}()
+	conn, err := transport.Dial(address, time.Second)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	conn.Close()
+	(<-accepted).Close()
+
+	if err := listener.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(address); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("socket file after Close: %v, want removed", err)
+	}
+}
+
+func TestUnixSocketTransportReplacesStaleSocket(t *testing.T) {
+	address := shortSocketPath(t)
+	if err := ensureSocketDir(filepath.Dir(address)); err != nil {
+		t.Fatal(err)
+	}
+	// A listener that does not unlink its socket on close leaves the file
+	// behind, like a crashed server.
+	stale, err := net.ListenUnix("unix", &net.UnixAddr{Name: address, Net: "unix"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale.SetUnlinkOnClose(false)
+	stale.Close()
+
+	listener, err := PlatformTransport().Listen(address)
+	if err != nil {
+		t.Fatalf("Listen() over a stale socket error = %v", err)
+	}
+	listener.Close()
+}
+
+func TestUnixSocketTransportRejectsNonSocketFile(t *testing.T) {
+	address := shortSocketPath(t)
+	if err := ensureSocketDir(filepath.Dir(address)); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(address, []byte("x"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := PlatformTransport().Listen(address); err == nil {
+		t.Fatal("Listen() over a regular file error = nil")
+	}
+}
+
+func TestEnsureSocketDirRejectsSharedDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "shared")
+	if err := os.Mkdir(dir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(dir, 0o777); err != nil {
+		t.Fatal(err)
+	}
+	if err := ensureSocketDir(dir); err == nil {
+		t.Fatal("ensureSocketDir() on a world-writable dir error = nil")
+	}
+}
@@ -0,0 +1,108 @@
+//go:build windows
+
+package ipc
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"os/user"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Microsoft/go-winio"
+	"golang.org/x/sys/windows"
+)
+
+var pipeNamePattern = regexp.MustCompile(`(?i)^\\\\\.\\pipe\\myT-x-[a-z0-9._-]{1,128}$`)
+
+const defaultPipePrefix = `\\.\pipe\myT-x-`
+
+var platformTransport Transport = namedPipeTransport{}
+
+// namedPipeTransport carries the IPC channel over Windows Named Pipes.
+type namedPipeTransport struct{}
+
+func (namedPipeTransport) Listen(address string) (net.Listener, error) {
+	return listenPipeWithCurrentUserDACL(address)
+}
+
+func (namedPipeTransport) Dial(address string, timeout time.Duration) (net.Conn, error) {
+	return winio.DialPipe(address, &timeout)
+}
+
+// pipeAddress returns the default pipe name of username.
+func pipeAddress(username string) string {
+	return defaultPipePrefix + username
+}
+
+// authTokenPath returns the token file of the pipe pipeName. It lives in the
+// user's local application data, which is not readable by other
+// (non-administrator) users.
+func authTokenPath(pipeName string) (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve token dir: %w", err)
+	}
+	return filepath.Join(dir, "myT-x", "ipc", filepath.Base(pipeName)+authTokenFileSuffix), nil
+}
+
+// validPipeName reports whether name may be used as a GO_TMUX_PIPE override.
+func validPipeName(name string) bool {
+	return pipeNamePattern.MatchString(name)
+}
+
+// listenPipeWithCurrentUserDACL creates a Named Pipe listener restricted to the
+// current user. The DACL grants full access only to SYSTEM and the current
+// user's SID, preventing other local users from connecting.
+func listenPipeWithCurrentUserDACL(pipeName string) (net.Listener, error) {
+	securityDescriptor, err := pipeSecurityDescriptor()
+	if err != nil {
+		return nil, err
+	}
+	return winio.ListenPipe(pipeName, &winio.PipeConfig{
+		SecurityDescriptor: securityDescriptor,
+		MessageMode:        false,
+		InputBufferSize:    int32(maxPipeRequestBytes),
+		OutputBufferSize:   int32(maxPipeResponseBytes),
+	})
+}
+
+var validSIDPattern = regexp.MustCompile(`^S-1(-\d+)+$`)
+
+func pipeSecurityDescriptor() (string, error) {
+	current, err := user.Current()
+	if err != nil {
+		return "", fmt.Errorf("resolve current user: %w", err)
+	}
+	sid := strings.TrimSpace(current.Uid)
+	if sid == "" {
+		return "", errors.New("current user SID is unavailable")
+	}
+	if !validSIDPattern.MatchString(sid) {
+		return "", fmt.Errorf("current user SID has unexpected format: %s", sid)
+	}
+	// SDDL: D:P = protected DACL (no inheritance)
+	// (A;;GA;;;SY) = full access for SYSTEM
+	// (A;;GA;;;%s) = full access for current user SID
+	return fmt.Sprintf("D:P(A;;GA;;;SY)(A;;GA;;;%s)", sid), nil
+}
+
+// peerProcessID returns the process ID of the client connected on conn, or 0
+// when it cannot be queried.
+func peerProcessID(conn net.Conn) uint32 {
+	pipe, ok := conn.(interface{ Fd() uintptr })
+	if !ok {
+		return 0
+	}
+	var pid uint32
+	if err := windows.GetNamedPipeClientProcessId(windows.Handle(pipe.Fd()), &pid); err != nil {
+		slog.Debug("[ipc] failed to query pipe client process", "error", err)
+		return 0
+	}
+	return pid
+}
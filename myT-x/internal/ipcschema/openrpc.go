@@ -0,0 +1,188 @@
+// Package ipcschema describes the IPC protocol between tmux clients and
+// myT-x as an OpenRPC document, built from the Go definitions the server
+// uses: the wire types of package ipc and the command registry of package
+// tmux. Third-party clients can generate or validate requests against it
+// instead of reverse-engineering the shim.
+package ipcschema
+
+import (
+	"strings"
+
+	"myT-x/internal/ipc"
+	"myT-x/internal/tmux"
+)
+
+// OpenRPCVersion is the OpenRPC specification version of Document.
+const OpenRPCVersion = "1.3.2"
+
+// commandFailedError names the error of a command that ran and failed.
+const commandFailedError = "CommandFailed"
+
+// Document is an OpenRPC document. Each method is a command: a TmuxRequest
+// whose command field is the method name and whose other fields are the
+// params. The result is the TmuxResponse.
+type Document struct {
+	OpenRPC    string     `json:"openrpc"`
+	Info       Info       `json:"info"`
+	Methods    []Method   `json:"methods"`
+	Components Components `json:"components"`
+}
+
+// Info describes the API.
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description"`
+}
+
+// Method is one command.
+type Method struct {
+	Name           string  `json:"name"`
+	Summary        string  `json:"summary,omitempty"`
+	Description    string  `json:"description,omitempty"`
+	ParamStructure string  `json:"paramStructure"`
+	Params         []Param `json:"params"`
+	Result         Param   `json:"result"`
+	Errors         []Ref   `json:"errors"`
+	// Internal commands are used between myT-x processes and may change
+	// without notice.
+	Internal bool `json:"x-internal,omitempty"`
+}
+
+// Param is a named value with its JSON Schema.
+type Param struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Required    bool           `json:"required,omitempty"`
+	Schema      map[string]any `json:"schema"`
+}
+
+// Ref is a JSON reference into Components.
+type Ref struct {
+	Ref string `json:"$ref"`
+}
+
+// Components holds the shared schemas and errors.
+type Components struct {
+	Schemas map[string]any   `json:"schemas"`
+	Errors  map[string]Error `json:"errors"`
+}
+
+// Error is an OpenRPC error. Code is the response exit code.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    string `json:"data,omitempty"`
+}
+
+// Build returns the document for the protocol of this build. version is
+// reported as the API version.
+func Build(version string) Document {
+	commands := tmux.CommandSchemas()
+	schemas := ipc.ProtocolSchemas()
+
+	names := make([]string, 0, len(commands))
+	for _, command := range commands {
+		names = append(names, command.Name)
+	}
+	if request, ok := schemas["TmuxRequest"].(map[string]any); ok {
+		if properties, ok := request["properties"].(map[string]any); ok {
+			if field, ok := properties["command"].(map[string]any); ok {
+				field["enum"] = names
+			}
+		}
+	}
+
+	errors := map[string]Error{
+		commandFailedError: {Code: 1, Message: "command failed", Data: "The response stderr says why."},
+	}
+	errorRefs := []Ref{{Ref: "#/components/errors/" + commandFailedError}}
+	for _, protocolErr := range ipc.ProtocolErrors() {
+		errors[protocolErr.Name] = Error{Code: 1, Message: protocolErr.Message, Data: protocolErr.Description}
+		errorRefs = append(errorRefs, Ref{Ref: "#/components/errors/" + protocolErr.Name})
+	}
+
+	methods := make([]Method, 0, len(commands))
+	for _, command := range commands {
+		methods = append(methods, Method{
+			Name:           command.Name,
+			Summary:        command.Description,
+			Description:    methodDescription(command),
+			ParamStructure: "by-name",
+			Params:         methodParams(command),
+			Result:         methodResult(command.Name),
+			Errors:         errorRefs,
+			Internal:       command.Internal,
+		})
+	}
+
+	return Document{
+		OpenRPC: OpenRPCVersion,
+		Info: Info{
+			Title:   "myT-x IPC",
+			Version: version,
+			Description: "tmux-compatible commands served by myT-x over a named pipe (Windows) or Unix socket. " +
+				"Each method is sent as one JSON TmuxRequest frame with the method name in command and the params as its fields. " +
+				"The server answers with ResponseFrame frames until the final one, which carries the TmuxResponse. " +
+				"Schemas follow " + ipc.JSONSchemaDialect + ".",
+		},
+		Methods: methods,
+		Components: Components{
+			Schemas: schemas,
+			Errors:  errors,
+		},
+	}
+}
+
+func methodDescription(command tmux.CommandSchema) string {
+	var b strings.Builder
+	b.WriteString("usage: ")
+	b.WriteString(command.Usage)
+	for _, note := range command.Notes {
+		b.WriteString("\n- ")
+		b.WriteString(note)
+	}
+	return b.String()
+}
+
+func methodParams(command tmux.CommandSchema) []Param {
+	flags := map[string]any{"type": "object"}
+	if command.Flags == nil {
+		flags["additionalProperties"] = map[string]any{"type": []string{"string", "boolean", "number"}}
+	} else {
+		properties := make(map[string]any, len(command.Flags))
+		for flag, kind := range command.Flags {
+			if kind == tmux.FlagTypeBoolean {
+				properties[flag] = map[string]any{"type": "boolean"}
+			} else {
+				// Numeric values such as -x 120 may also be sent as numbers.
+				properties[flag] = map[string]any{"type": []string{"string", "number"}}
+			}
+		}
+		flags["properties"] = properties
+		flags["additionalProperties"] = false
+	}
+	return []Param{
+		{Name: "flags", Schema: flags},
+		{Name: "args", Schema: map[string]any{"type": "array", "items": map[string]any{"type": "string"}}},
+		{Name: "env", Schema: map[string]any{"type": "object", "additionalProperties": map[string]any{"type": "string"}}},
+		{Name: "caller_pane", Description: "TMUX_PANE of the caller; the default target.", Schema: map[string]any{"type": "string"}},
+	}
+}
+
+// stdoutSchemas names the schema of the stdout of commands that answer in JSON.
+var stdoutSchemas = map[string]string{
+	ipc.ServerInfoCommand: "ServerInfo",
+	"mcp-resolve-stdio":   "MCPStdioResolvePayload",
+}
+
+func methodResult(name string) Param {
+	result := Param{
+		Name:   "response",
+		Schema: map[string]any{"$ref": "#/components/schemas/TmuxResponse"},
+	}
+	if schema, ok := stdoutSchemas[name]; ok {
+		result.Description = "stdout is a JSON " + schema + " (#/components/schemas/" + schema + ")."
+	}
+	return result
+}
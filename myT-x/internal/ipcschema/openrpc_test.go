@@ -0,0 +1,96 @@
+package ipcschema
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"myT-x/internal/ipc"
+	"myT-x/internal/tmux"
+)
+
+func TestBuildListsEveryCommand(t *testing.T) {
+	doc := Build("v1.2.3")
+	if doc.OpenRPC != OpenRPCVersion || doc.Info.Version != "v1.2.3" {
+		t.Fatalf("header = %q %q", doc.OpenRPC, doc.Info.Version)
+	}
+
+	commands := tmux.CommandSchemas()
+	if len(doc.Methods) != len(commands) {
+		t.Fatalf("len(Methods) = %d, want %d", len(doc.Methods), len(commands))
+	}
+	byName := make(map[string]Method, len(doc.Methods))
+	for _, method := range doc.Methods {
+		byName[method.Name] = method
+	}
+	for _, command := range commands {
+		method, ok := byName[command.Name]
+		if !ok {
+			t.Fatalf("command %q has no method", command.Name)
+		}
+		if method.Internal != command.Internal {
+			t.Fatalf("%s: Internal = %v, want %v", command.Name, method.Internal, command.Internal)
+		}
+	}
+
+	split, ok := byName["split-window"]
+	if !ok {
+		t.Fatal("split-window has no method")
+	}
+	flags := split.Params[0].Schema
+	properties, ok := flags["properties"].(map[string]any)
+	if !ok {
+		t.Fatalf("split-window flags = %#v, want known properties", flags)
+	}
+	if got := properties["-h"]; !jsonEqual(got, map[string]any{"type": "boolean"}) {
+		t.Fatalf("split-window -h = %#v, want boolean", got)
+	}
+	if _, ok := properties["-t"]; !ok {
+		t.Fatal("split-window should document -t")
+	}
+
+	if got := byName[ipc.ServerInfoCommand].Result.Description; got == "" {
+		t.Fatal("server-info result should name the ServerInfo stdout schema")
+	}
+}
+
+func TestBuildComponents(t *testing.T) {
+	doc := Build("devel")
+
+	request, ok := doc.Components.Schemas["TmuxRequest"].(map[string]any)
+	if !ok {
+		t.Fatal("missing TmuxRequest schema")
+	}
+	command := request["properties"].(map[string]any)["command"].(map[string]any)
+	names, ok := command["enum"].([]string)
+	if !ok || !slices.Contains(names, "split-window") {
+		t.Fatalf("TmuxRequest.command enum = %#v", command["enum"])
+	}
+
+	for _, protocolErr := range ipc.ProtocolErrors() {
+		if _, ok := doc.Components.Errors[protocolErr.Name]; !ok {
+			t.Fatalf("missing error %q", protocolErr.Name)
+		}
+	}
+	if _, ok := doc.Components.Errors[commandFailedError]; !ok {
+		t.Fatal("missing CommandFailed error")
+	}
+	for _, method := range doc.Methods {
+		for _, ref := range method.Errors {
+			name := ref.Ref[len("#/components/errors/"):]
+			if _, ok := doc.Components.Errors[name]; !ok {
+				t.Fatalf("%s refers to unknown error %q", method.Name, name)
+			}
+		}
+	}
+
+	if _, err := json.Marshal(doc); err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+}
+
+func jsonEqual(a, b any) bool {
+	left, errLeft := json.Marshal(a)
+	right, errRight := json.Marshal(b)
+	return errLeft == nil && errRight == nil && string(left) == string(right)
+}
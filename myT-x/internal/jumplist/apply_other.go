@@ -0,0 +1,8 @@
+//go:build !windows
+
+package jumplist
+
+// apply is a no-op: jump lists are a Windows taskbar feature.
+func apply(List) error {
+	return nil
+}
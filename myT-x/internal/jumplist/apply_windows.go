@@ -0,0 +1,339 @@
+//go:build windows
+
+package jumplist
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	ole32 = windows.NewLazySystemDLL("ole32.dll")
+
+	procCoCreateInstance = ole32.NewProc("CoCreateInstance")
+)
+
+var (
+	clsidDestinationList            = windows.GUID{Data1: 0x77f10cf0, Data2: 0x3db5, Data3: 0x4966, Data4: [8]byte{0xb5, 0x20, 0xb7, 0xc5, 0x4f, 0xd3, 0x5e, 0xd6}}
+	clsidEnumerableObjectCollection = windows.GUID{Data1: 0x2d3468c1, Data2: 0x36a7, Data3: 0x43b6, Data4: [8]byte{0xac, 0x24, 0xd3, 0xf0, 0x2f, 0xd9, 0x60, 0x7a}}
+	clsidShellLink                  = windows.GUID{Data1: 0x00021401, Data4: [8]byte{0xc0, 0, 0, 0, 0, 0, 0, 0x46}}
+
+	iidICustomDestinationList = windows.GUID{Data1: 0x6332debf, Data2: 0x87b5, Data3: 0x4670, Data4: [8]byte{0x90, 0xc0, 0x5e, 0x57, 0xb4, 0x08, 0xa4, 0x9e}}
+	iidIObjectArray           = windows.GUID{Data1: 0x92ca9dcd, Data2: 0x5622, Data3: 0x4bba, Data4: [8]byte{0xa8, 0x05, 0x5e, 0x9f, 0x54, 0x1b, 0xd8, 0xc9}}
+	iidIObjectCollection      = windows.GUID{Data1: 0x5632b1a4, Data2: 0xe38a, Data3: 0x400a, Data4: [8]byte{0x92, 0x8a, 0xd4, 0xcd, 0x63, 0x23, 0x02, 0x95}}
+	iidIShellLinkW            = windows.GUID{Data1: 0x000214f9, Data4: [8]byte{0xc0, 0, 0, 0, 0, 0, 0, 0x46}}
+	iidIPropertyStore         = windows.GUID{Data1: 0x886d8eeb, Data2: 0x8cf2, Data3: 0x4446, Data4: [8]byte{0x8d, 0x02, 0xcd, 0xba, 0x1d, 0xbd, 0xcf, 0x99}}
+
+	// pkeyTitle is PKEY_Title, the display name of a jump list task link.
+	pkeyTitle = _PROPERTYKEY{
+		fmtid: windows.GUID{Data1: 0xf29f85e0, Data2: 0x4ff9, Data3: 0x1068, Data4: [8]byte{0xab, 0x91, 0x08, 0x00, 0x2b, 0x27, 0xb3, 0xd9}},
+		pid:   2,
+	}
+)
+
+// COM vtable slots. Every interface starts with the three IUnknown methods.
+const (
+	slotQueryInterface = 0
+	slotRelease        = 2
+
+	// ICustomDestinationList
+	slotBeginList      = 4
+	slotAppendCategory = 5
+	slotAddUserTasks   = 7
+	slotCommitList     = 8
+	slotAbortList      = 11
+
+	// IObjectArray / IObjectCollection
+	slotGetCount  = 3
+	slotGetAt     = 4
+	slotAddObject = 5
+
+	// IShellLinkW
+	slotSetDescription  = 7
+	slotGetArguments    = 10
+	slotSetArguments    = 11
+	slotSetIconLocation = 17
+	slotSetPath         = 20
+
+	// IPropertyStore
+	slotSetValue = 6
+	slotCommit   = 7
+)
+
+const (
+	_VT_LPWSTR = 31
+
+	// argumentsBufferLen is the IShellLinkW argument buffer size, large
+	// enough for any link deeplink.Parse accepts.
+	argumentsBufferLen = 4096
+)
+
+type _PROPERTYKEY struct {
+	fmtid windows.GUID
+	pid   uint32
+}
+
+// _PROPVARIANT mirrors PROPVARIANT for pointer-sized values.
+type _PROPVARIANT struct {
+	vt       uint16
+	reserved [3]uint16
+	val      uintptr
+	_        uintptr
+}
+
+// comObject is a COM interface pointer.
+type comObject struct {
+	ptr unsafe.Pointer
+}
+
+// comCall invokes vtable slot method of obj and returns the HRESULT.
+//
+//go:uintptrescapes
+func comCall(obj unsafe.Pointer, method int, args ...uintptr) uintptr {
+	vtbl := *(*unsafe.Pointer)(obj)
+	fn := *(*uintptr)(unsafe.Add(vtbl, uintptr(method)*unsafe.Sizeof(uintptr(0))))
+	hr, _, _ := syscall.SyscallN(fn, append([]uintptr{uintptr(obj)}, args...)...)
+	return hr
+}
+
+// call invokes a method that returns an HRESULT.
+//
+//go:uintptrescapes
+func (o comObject) call(name string, method int, args ...uintptr) error {
+	if hr := comCall(o.ptr, method, args...); int32(hr) < 0 {
+		return fmt.Errorf("%s: %w", name, syscall.Errno(hr))
+	}
+	return nil
+}
+
+func (o comObject) release() {
+	if o.ptr != nil {
+		comCall(o.ptr, slotRelease)
+	}
+}
+
+func (o comObject) queryInterface(iid *windows.GUID) (comObject, error) {
+	var out unsafe.Pointer
+	if err := o.call("QueryInterface", slotQueryInterface, uintptr(unsafe.Pointer(iid)), uintptr(unsafe.Pointer(&out))); err != nil {
+		return comObject{}, err
+	}
+	return comObject{ptr: out}, nil
+}
+
+func createInstance(clsid, iid *windows.GUID) (comObject, error) {
+	var out unsafe.Pointer
+	hr, _, _ := procCoCreateInstance.Call(
+		uintptr(unsafe.Pointer(clsid)),
+		0,
+		windows.CLSCTX_INPROC_SERVER,
+		uintptr(unsafe.Pointer(iid)),
+		uintptr(unsafe.Pointer(&out)),
+	)
+	if int32(hr) < 0 {
+		return comObject{}, fmt.Errorf("CoCreateInstance(%s): %w", clsid, syscall.Errno(hr))
+	}
+	return comObject{ptr: out}, nil
+}
+
+// apply replaces the jump list of the current process through
+// ICustomDestinationList. COM requires the calls to stay on one thread.
+func apply(list List) error {
+	exePath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("resolve executable path: %w", err)
+	}
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	switch err := windows.CoInitializeEx(0, windows.COINIT_APARTMENTTHREADED); {
+	case err == nil, errors.Is(err, syscall.Errno(windows.S_FALSE)):
+		defer windows.CoUninitialize()
+	case errors.Is(err, syscall.Errno(windows.RPC_E_CHANGED_MODE)):
+		// Already initialized as MTA on this thread; the list API works there too.
+	default:
+		return fmt.Errorf("CoInitializeEx: %w", err)
+	}
+
+	destinations, err := createInstance(&clsidDestinationList, &iidICustomDestinationList)
+	if err != nil {
+		return err
+	}
+	defer destinations.release()
+
+	var minSlots uint32
+	var removedPtr unsafe.Pointer
+	if err := destinations.call("BeginList", slotBeginList,
+		uintptr(unsafe.Pointer(&minSlots)),
+		uintptr(unsafe.Pointer(&iidIObjectArray)),
+		uintptr(unsafe.Pointer(&removedPtr)),
+	); err != nil {
+		return err
+	}
+	removed := comObject{ptr: removedPtr}
+	defer removed.release()
+
+	committed := false
+	defer func() {
+		if !committed {
+			_ = destinations.call("AbortList", slotAbortList)
+		}
+	}()
+
+	// AppendCategory fails for the whole category when it contains an entry
+	// the user removed from the jump list, so those sessions are skipped.
+	removedArgs := linkArguments(removed)
+	recent := make([]Item, 0, len(list.Recent))
+	for _, item := range list.Recent {
+		if !removedArgs[quoteArgument(item.Arguments)] {
+			recent = append(recent, item)
+		}
+	}
+
+	if len(recent) > 0 {
+		items, err := newObjectArray(exePath, recent)
+		if err != nil {
+			return err
+		}
+		title, err := windows.UTF16PtrFromString(RecentCategory)
+		if err != nil {
+			items.release()
+			return err
+		}
+		err = destinations.call("AppendCategory", slotAppendCategory, uintptr(unsafe.Pointer(title)), uintptr(items.ptr))
+		items.release()
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(list.Tasks) > 0 {
+		tasks, err := newObjectArray(exePath, list.Tasks)
+		if err != nil {
+			return err
+		}
+		err = destinations.call("AddUserTasks", slotAddUserTasks, uintptr(tasks.ptr))
+		tasks.release()
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := destinations.call("CommitList", slotCommitList); err != nil {
+		return err
+	}
+	committed = true
+	return nil
+}
+
+// linkArguments returns the argument strings of the shell links in array.
+// Entries that are not shell links are ignored.
+func linkArguments(array comObject) map[string]bool {
+	args := map[string]bool{}
+	var count uint32
+	if array.ptr == nil || array.call("GetCount", slotGetCount, uintptr(unsafe.Pointer(&count))) != nil {
+		return args
+	}
+	buf := make([]uint16, argumentsBufferLen)
+	for i := range count {
+		var linkPtr unsafe.Pointer
+		if array.call("GetAt", slotGetAt, uintptr(i), uintptr(unsafe.Pointer(&iidIShellLinkW)), uintptr(unsafe.Pointer(&linkPtr))) != nil {
+			continue
+		}
+		link := comObject{ptr: linkPtr}
+		if link.call("GetArguments", slotGetArguments, uintptr(unsafe.Pointer(&buf[0])), uintptr(len(buf))) == nil {
+			args[windows.UTF16ToString(buf)] = true
+		}
+		link.release()
+	}
+	return args
+}
+
+// newObjectArray returns an IObjectArray of shell links for items.
+func newObjectArray(exePath string, items []Item) (comObject, error) {
+	collection, err := createInstance(&clsidEnumerableObjectCollection, &iidIObjectCollection)
+	if err != nil {
+		return comObject{}, err
+	}
+	defer collection.release()
+
+	for _, item := range items {
+		link, err := newShellLink(exePath, item)
+		if err != nil {
+			return comObject{}, err
+		}
+		err = collection.call("AddObject", slotAddObject, uintptr(link.ptr))
+		link.release()
+		if err != nil {
+			return comObject{}, err
+		}
+	}
+	return collection.queryInterface(&iidIObjectArray)
+}
+
+// newShellLink returns an IShellLinkW that starts exePath with item's link.
+func newShellLink(exePath string, item Item) (comObject, error) {
+	link, err := createInstance(&clsidShellLink, &iidIShellLinkW)
+	if err != nil {
+		return comObject{}, err
+	}
+	if err := setShellLink(link, exePath, item); err != nil {
+		link.release()
+		return comObject{}, err
+	}
+	return link, nil
+}
+
+func setShellLink(link comObject, exePath string, item Item) error {
+	path, err := windows.UTF16PtrFromString(exePath)
+	if err != nil {
+		return err
+	}
+	arguments, err := windows.UTF16PtrFromString(quoteArgument(item.Arguments))
+	if err != nil {
+		return err
+	}
+	description, err := windows.UTF16PtrFromString(item.Description)
+	if err != nil {
+		return err
+	}
+	title, err := windows.UTF16PtrFromString(item.Title)
+	if err != nil {
+		return err
+	}
+
+	if err := link.call("SetPath", slotSetPath, uintptr(unsafe.Pointer(path))); err != nil {
+		return err
+	}
+	if err := link.call("SetArguments", slotSetArguments, uintptr(unsafe.Pointer(arguments))); err != nil {
+		return err
+	}
+	if err := link.call("SetDescription", slotSetDescription, uintptr(unsafe.Pointer(description))); err != nil {
+		return err
+	}
+	if err := link.call("SetIconLocation", slotSetIconLocation, uintptr(unsafe.Pointer(path)), 0); err != nil {
+		return err
+	}
+
+	props, err := link.queryInterface(&iidIPropertyStore)
+	if err != nil {
+		return err
+	}
+	defer props.release()
+	value := _PROPVARIANT{vt: _VT_LPWSTR, val: uintptr(unsafe.Pointer(title))}
+	if err := props.call("SetValue", slotSetValue, uintptr(unsafe.Pointer(&pkeyTitle)), uintptr(unsafe.Pointer(&value))); err != nil {
+		return err
+	}
+	runtime.KeepAlive(title)
+	return props.call("Commit", slotCommit)
+}
+
+// quoteArgument wraps a link in quotes so the shell passes it as one argument.
+func quoteArgument(link string) string {
+	return `"` + link + `"`
+}
@@ -0,0 +1,214 @@
+// Package jumplist keeps the Windows taskbar jump list in sync with the
+// session list. Every entry launches the executable with a mytx:// link, so a
+// click reaches the running instance through the single-instance
+// activate-window IPC flow, or is opened after startup when no instance runs.
+package jumplist
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"myT-x/internal/deeplink"
+)
+
+const (
+	// DefaultMaxRecent bounds the "Recent sessions" category when
+	// Deps.MaxRecent is zero. Windows itself shows about ten entries.
+	DefaultMaxRecent = 8
+
+	// DefaultPollInterval is the session polling period used when
+	// Deps.PollInterval is zero.
+	DefaultPollInterval = 2 * time.Second
+
+	// RecentCategory is the custom category title of recent sessions.
+	RecentCategory = "Recent sessions"
+)
+
+// Item is one jump list entry.
+type Item struct {
+	Title       string
+	Description string
+	// Arguments is the mytx:// link passed to the executable.
+	Arguments string
+}
+
+// List is the complete jump list content.
+type List struct {
+	// Recent is shown under RecentCategory, most recent first.
+	Recent []Item
+	// Tasks is the fixed "Tasks" category.
+	Tasks []Item
+}
+
+// Deps holds external dependencies injected at construction time.
+type Deps struct {
+	// SessionNames returns the names of all live sessions.
+	SessionNames func() []string
+
+	// ActiveSession returns the name of the active session, or "".
+	ActiveSession func() string
+
+	// Apply replaces the OS jump list.
+	// Optional: defaults to ICustomDestinationList on Windows and a no-op elsewhere.
+	Apply func(List) error
+
+	// MaxRecent bounds List.Recent. Optional: defaults to DefaultMaxRecent.
+	MaxRecent int
+
+	// PollInterval is the period of Run. Optional: defaults to DefaultPollInterval.
+	PollInterval time.Duration
+}
+
+// Service tracks session recency and rewrites the jump list when it changes.
+//
+// Thread-safety is managed internally via mu. No external locking is required.
+type Service struct {
+	deps Deps
+
+	mu sync.Mutex
+	// recent holds live session names, most recently activated first.
+	recent []string
+	// applied is the last list written successfully; nil forces a write.
+	applied *List
+	// lastApplyErr suppresses repeated warnings for the same failure.
+	lastApplyErr string
+}
+
+// NewService creates a jump list service.
+// Panics if SessionNames or ActiveSession is nil.
+func NewService(deps Deps) *Service {
+	if deps.SessionNames == nil {
+		panic("jumplist.NewService: SessionNames must be non-nil")
+	}
+	if deps.ActiveSession == nil {
+		panic("jumplist.NewService: ActiveSession must be non-nil")
+	}
+	if deps.Apply == nil {
+		deps.Apply = apply
+	}
+	if deps.MaxRecent <= 0 {
+		deps.MaxRecent = DefaultMaxRecent
+	}
+	if deps.PollInterval <= 0 {
+		deps.PollInterval = DefaultPollInterval
+	}
+	return &Service{deps: deps}
+}
+
+// Run writes the initial jump list and then polls until ctx is cancelled.
+// Apply failures are logged once until the failure changes or clears, and
+// the write is retried on the next poll.
+func (s *Service) Run(ctx context.Context) {
+	s.logApplyError(s.Poll())
+	ticker := time.NewTicker(s.deps.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.logApplyError(s.Poll())
+		}
+	}
+}
+
+// Poll updates session recency and applies the jump list when its content
+// changed since the last successful write.
+func (s *Service) Poll() error {
+	names := s.deps.SessionNames()
+	active := s.deps.ActiveSession()
+
+	s.mu.Lock()
+	s.recent = updateRecent(s.recent, names, active)
+	list := buildList(s.recent, s.deps.MaxRecent)
+	if s.applied != nil && listsEqual(*s.applied, list) {
+		s.mu.Unlock()
+		return nil
+	}
+	s.mu.Unlock()
+
+	if err := s.deps.Apply(list); err != nil {
+		return err
+	}
+	slog.Debug("[DEBUG-JUMPLIST] jump list updated", "recent", len(list.Recent))
+
+	s.mu.Lock()
+	s.applied = &list
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *Service) logApplyError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err == nil {
+		s.lastApplyErr = ""
+		return
+	}
+	if msg := err.Error(); msg != s.lastApplyErr {
+		s.lastApplyErr = msg
+		slog.Warn("[WARN-JUMPLIST] jump list update failed", "error", err)
+	}
+}
+
+// updateRecent returns the recency order for the live sessions in names.
+// The active session moves to the front, sessions not seen before are
+// inserted at the front (they were just created), and closed sessions drop out.
+func updateRecent(recent, names []string, active string) []string {
+	live := make(map[string]bool, len(names))
+	for _, name := range names {
+		live[name] = true
+	}
+	known := make(map[string]bool, len(recent))
+	next := make([]string, 0, len(names))
+	for _, name := range recent {
+		known[name] = true
+		if live[name] && name != active {
+			next = append(next, name)
+		}
+	}
+	var added []string
+	for _, name := range names {
+		if !known[name] && name != active {
+			added = append(added, name)
+		}
+	}
+	next = append(added, next...)
+	if live[active] {
+		next = append([]string{active}, next...)
+	}
+	return next
+}
+
+// buildList returns the jump list for recent, capped at maxRecent entries.
+func buildList(recent []string, maxRecent int) List {
+	list := List{
+		Tasks: []Item{
+			{
+				Title:       "New Session",
+				Description: "Create a new session",
+				Arguments:   deeplink.ActionURL(deeplink.ActionNewSession),
+			},
+			{
+				Title:       "New Worktree Session",
+				Description: "Create a new session on a git worktree",
+				Arguments:   deeplink.ActionURL(deeplink.ActionNewWorktreeSession),
+			},
+		},
+	}
+	for _, name := range recent[:min(len(recent), maxRecent)] {
+		list.Recent = append(list.Recent, Item{
+			Title:       name,
+			Description: "Switch to session " + name,
+			Arguments:   deeplink.SessionURL(name),
+		})
+	}
+	return list
+}
+
+func listsEqual(a, b List) bool {
+	return slices.Equal(a.Recent, b.Recent) && slices.Equal(a.Tasks, b.Tasks)
+}
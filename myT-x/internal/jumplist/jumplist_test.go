@@ -0,0 +1,95 @@
+package jumplist
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"myT-x/internal/deeplink"
+)
+
+func TestUpdateRecent(t *testing.T) {
+	tests := []struct {
+		name   string
+		recent []string
+		names  []string
+		active string
+		want   []string
+	}{
+		{name: "initial order follows snapshot", names: []string{"a", "b"}, want: []string{"a", "b"}},
+		{name: "active moves to front", recent: []string{"a", "b", "c"}, names: []string{"a", "b", "c"}, active: "c", want: []string{"c", "a", "b"}},
+		{name: "new session goes after active", recent: []string{"a", "b"}, names: []string{"a", "b", "c"}, active: "a", want: []string{"a", "c", "b"}},
+		{name: "closed session drops out", recent: []string{"a", "b", "c"}, names: []string{"a", "c"}, want: []string{"a", "c"}},
+		{name: "unknown active is ignored", recent: []string{"a"}, names: []string{"a"}, active: "gone", want: []string{"a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := updateRecent(tt.recent, tt.names, tt.active); !slices.Equal(got, tt.want) {
+				t.Fatalf("updateRecent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildList(t *testing.T) {
+	list := buildList([]string{"api", "web", "docs"}, 2)
+	if len(list.Recent) != 2 {
+		t.Fatalf("len(Recent) = %d, want 2", len(list.Recent))
+	}
+	if got := list.Recent[0]; got.Title != "api" || got.Arguments != deeplink.SessionURL("api") {
+		t.Fatalf("Recent[0] = %+v", got)
+	}
+	wantTasks := []string{
+		deeplink.ActionURL(deeplink.ActionNewSession),
+		deeplink.ActionURL(deeplink.ActionNewWorktreeSession),
+	}
+	var gotTasks []string
+	for _, task := range list.Tasks {
+		gotTasks = append(gotTasks, task.Arguments)
+	}
+	if !slices.Equal(gotTasks, wantTasks) {
+		t.Fatalf("task arguments = %v, want %v", gotTasks, wantTasks)
+	}
+}
+
+func TestPollAppliesOnlyChanges(t *testing.T) {
+	names := []string{"a", "b"}
+	active := "a"
+	var applied []List
+	applyErr := errors.New("taskbar unavailable")
+	failNext := false
+	svc := NewService(Deps{
+		SessionNames:  func() []string { return names },
+		ActiveSession: func() string { return active },
+		Apply: func(list List) error {
+			if failNext {
+				failNext = false
+				return applyErr
+			}
+			applied = append(applied, list)
+			return nil
+		},
+	})
+
+	if err := svc.Poll(); err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+	if err := svc.Poll(); err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+	if len(applied) != 1 {
+		t.Fatalf("unchanged sessions applied %d times, want 1", len(applied))
+	}
+
+	active = "b"
+	failNext = true
+	if err := svc.Poll(); !errors.Is(err, applyErr) {
+		t.Fatalf("Poll() error = %v, want %v", err, applyErr)
+	}
+	if err := svc.Poll(); err != nil {
+		t.Fatalf("Poll() retry error = %v", err)
+	}
+	if len(applied) != 2 || applied[1].Recent[0].Title != "b" {
+		t.Fatalf("failed write was not retried: %+v", applied)
+	}
+}
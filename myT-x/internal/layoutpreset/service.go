@@ -0,0 +1,239 @@
+package layoutpreset
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"myT-x/internal/sessioninfo"
+)
+
+const presetFileName = "layout-presets.json"
+
+// Preset is a named window layout in tmux's layout dump format.
+type Preset struct {
+	Name      string `json:"name"`
+	Layout    string `json:"layout"`
+	PaneCount int    `json:"pane_count"`
+	// Global is set by List/Find to report the scope; it is not persisted.
+	Global bool `json:"global,omitempty"`
+}
+
+// Deps contains App-level functions required by the layout preset service.
+type Deps struct {
+	ResolveSessionWorkDir func(sessionName string) (string, error)
+	ConfigDir             func() (string, error)
+}
+
+// Service persists named layout presets. Session-scoped presets live in the
+// session-info directory of the session's working directory; global presets
+// live directly under the config directory.
+type Service struct {
+	deps Deps
+	// fileIOMu serializes reads and replacement of preset files.
+	fileIOMu sync.Mutex
+}
+
+// NewService creates a layout preset service.
+func NewService(deps Deps) *Service {
+	if deps.ResolveSessionWorkDir == nil || deps.ConfigDir == nil {
+		panic("layoutpreset.NewService: required function fields in Deps must be non-nil (ResolveSessionWorkDir, ConfigDir)")
+	}
+	return &Service{deps: deps}
+}
+
+// List returns the session presets followed by the global presets.
+func (s *Service) List(sessionName string) ([]Preset, error) {
+	sessionPresets, err := s.load(sessionName, false)
+	if err != nil {
+		return nil, err
+	}
+	globalPresets, err := s.load("", true)
+	if err != nil {
+		return nil, err
+	}
+	return append(sessionPresets, globalPresets...), nil
+}
+
+// Find looks up a preset by name, preferring the session scope over the
+// global scope.
+func (s *Service) Find(sessionName, name string) (Preset, bool, error) {
+	name = strings.TrimSpace(name)
+	for _, global := range []bool{false, true} {
+		presets, err := s.load(sessionName, global)
+		if err != nil {
+			return Preset{}, false, err
+		}
+		for _, preset := range presets {
+			if preset.Name == name {
+				return preset, true, nil
+			}
+		}
+	}
+	return Preset{}, false, nil
+}
+
+// Save adds or replaces the preset with the same name in the given scope.
+// Callers are expected to validate the preset beforehand.
+func (s *Service) Save(sessionName string, preset Preset, global bool) error {
+	path, err := s.resolvePath(sessionName, global)
+	if err != nil {
+		return err
+	}
+	preset.Name = strings.TrimSpace(preset.Name)
+	preset.Global = false
+
+	s.fileIOMu.Lock()
+	defer s.fileIOMu.Unlock()
+	presets, err := readPresets(path)
+	if err != nil {
+		return fmt.Errorf("read layout presets: %w", err)
+	}
+	replaced := false
+	for i := range presets {
+		if presets[i].Name == preset.Name {
+			presets[i] = preset
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		presets = append(presets, preset)
+	}
+	if err := writePresets(path, presets); err != nil {
+		return fmt.Errorf("write layout presets: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a preset from the given scope.
+func (s *Service) Delete(sessionName, name string, global bool) error {
+	path, err := s.resolvePath(sessionName, global)
+	if err != nil {
+		return err
+	}
+	name = strings.TrimSpace(name)
+
+	s.fileIOMu.Lock()
+	defer s.fileIOMu.Unlock()
+	presets, err := readPresets(path)
+	if err != nil {
+		return fmt.Errorf("read layout presets: %w", err)
+	}
+	for i := range presets {
+		if presets[i].Name != name {
+			continue
+		}
+		presets = append(presets[:i], presets[i+1:]...)
+		if err := writePresets(path, presets); err != nil {
+			return fmt.Errorf("write layout presets: %w", err)
+		}
+		return nil
+	}
+	return fmt.Errorf("layout preset not found: %s", name)
+}
+
+func (s *Service) load(sessionName string, global bool) ([]Preset, error) {
+	path, err := s.resolvePath(sessionName, global)
+	if err != nil {
+		return nil, err
+	}
+	s.fileIOMu.Lock()
+	presets, err := readPresets(path)
+	s.fileIOMu.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("read layout presets: %w", err)
+	}
+	for i := range presets {
+		presets[i].Global = global
+	}
+	return presets, nil
+}
+
+func (s *Service) resolvePath(sessionName string, global bool) (string, error) {
+	configDir, err := s.deps.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	if global {
+		return filepath.Join(configDir, presetFileName), nil
+	}
+	sessionName = strings.TrimSpace(sessionName)
+	if sessionName == "" {
+		return "", errors.New("session name is required for session layout presets")
+	}
+	workDir, err := s.deps.ResolveSessionWorkDir(sessionName)
+	if err != nil {
+		return "", err
+	}
+	return sessioninfo.FilePath(configDir, workDir, presetFileName)
+}
+
+// readPresets reads presets from path and verifies their checksums.
+// Returns an empty slice if the file does not exist. Corrupt records are
+// dropped: the original file is quarantined and the survivors rewritten.
+// A file written by a newer version is left untouched and reported as an
+// error.
+func readPresets(path string) ([]Preset, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []Preset{}, nil
+		}
+		return nil, err
+	}
+
+	presets, dropped, err := sessioninfo.DecodeRecords[Preset](data)
+	if errors.Is(err, sessioninfo.ErrNewerRecordVersion) {
+		return nil, fmt.Errorf("read layout presets %s: %w", path, err)
+	}
+	if err != nil {
+		presets = []Preset{}
+		dropped = []sessioninfo.DroppedRecord{{Index: -1, Reason: err.Error()}}
+	}
+	if len(dropped) == 0 {
+		return presets, nil
+	}
+
+	quarantined, err := sessioninfo.QuarantineFile(path, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("quarantine corrupt layout presets: %w", err)
+	}
+	if err := writePresets(path, presets); err != nil {
+		return nil, fmt.Errorf("rewrite recovered layout presets: %w", err)
+	}
+	slog.Warn("[WARN-LAYOUT-PRESET] dropped corrupt layout presets and quarantined the original file",
+		"path", path,
+		"quarantined_path", quarantined,
+		"dropped", len(dropped),
+	)
+	return presets, nil
+}
+
+func writePresets(path string, presets []Preset) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create directory %s: %w", dir, err)
+	}
+
+	data, err := sessioninfo.EncodeRecords(presets)
+	if err != nil {
+		return fmt.Errorf("marshal layout presets: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("write temp file: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		// Clean up temp file on rename failure (best-effort).
+		os.Remove(tmp)
+		return fmt.Errorf("rename temp file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package layoutpreset
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"myT-x/internal/sessioninfo"
+)
+
+func newTestService(t *testing.T) (*Service, string, string) {
+	t.Helper()
+	rootPath := filepath.Join(t.TempDir(), "workspace")
+	configDir := filepath.Join(t.TempDir(), "config")
+	service := NewService(Deps{
+		ResolveSessionWorkDir: func(string) (string, error) {
+			return rootPath, nil
+		},
+		ConfigDir: func() (string, error) {
+			return configDir, nil
+		},
+	})
+	return service, rootPath, configDir
+}
+
+func TestNewServicePanicsOnMissingDeps(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic for missing deps")
+		}
+	}()
+	NewService(Deps{})
+}
+
+func TestSaveListFindDelete(t *testing.T) {
+	service, rootPath, configDir := newTestService(t)
+	sessionPreset := Preset{Name: "review", Layout: "b25d,80x24,0,0,0", PaneCount: 1}
+	globalPreset := Preset{Name: "review", Layout: "d463,159x48,0,0{79x48,0,0,0,79x48,80,0,1}", PaneCount: 2}
+
+	if err := service.Save("alpha", globalPreset, true); err != nil {
+		t.Fatalf("Save(global) error = %v", err)
+	}
+	if got, ok, err := service.Find("alpha", "review"); err != nil || !ok || !got.Global {
+		t.Fatalf("Find() = %+v, %v, %v, want global preset", got, ok, err)
+	}
+
+	if err := service.Save("alpha", sessionPreset, false); err != nil {
+		t.Fatalf("Save(session) error = %v", err)
+	}
+	sessionPath, err := sessioninfo.FilePath(configDir, rootPath, presetFileName)
+	if err != nil {
+		t.Fatalf("FilePath() error = %v", err)
+	}
+	if _, err := os.Stat(sessionPath); err != nil {
+		t.Fatalf("session preset file missing: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(configDir, presetFileName)); err != nil {
+		t.Fatalf("global preset file missing: %v", err)
+	}
+
+	got, ok, err := service.Find("alpha", " review ")
+	if err != nil || !ok {
+		t.Fatalf("Find() = %v, %v, want found", ok, err)
+	}
+	if got.Global || got.Layout != sessionPreset.Layout {
+		t.Fatalf("Find() = %+v, want session preset to shadow global", got)
+	}
+
+	// Saving under an existing name replaces the preset.
+	sessionPreset.PaneCount = 3
+	if err := service.Save("alpha", sessionPreset, false); err != nil {
+		t.Fatalf("Save(replace) error = %v", err)
+	}
+	presets, err := service.List("alpha")
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(presets) != 2 || presets[0].Global || presets[0].PaneCount != 3 || !presets[1].Global {
+		t.Fatalf("List() = %+v, want replaced session preset then global preset", presets)
+	}
+
+	if err := service.Delete("alpha", "review", false); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := service.Delete("alpha", "review", false); err == nil {
+		t.Fatal("Delete() of missing preset error = nil, want error")
+	}
+	if got, ok, _ := service.Find("alpha", "review"); !ok || !got.Global {
+		t.Fatalf("Find() after delete = %+v, %v, want global preset", got, ok)
+	}
+}
+
+func TestListQuarantinesCorruptFile(t *testing.T) {
+	service, _, configDir := newTestService(t)
+	path := filepath.Join(configDir, presetFileName)
+	if err := os.MkdirAll(configDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	presets, err := service.List("alpha")
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(presets) != 0 {
+		t.Fatalf("List() = %+v, want empty after recovery", presets)
+	}
+	matches, _ := filepath.Glob(path + ".corrupt-*")
+	if len(matches) != 1 {
+		t.Fatalf("quarantined files = %v, want 1", matches)
+	}
+}
+
+func TestSessionScopeRequiresSessionName(t *testing.T) {
+	service, _, _ := newTestService(t)
+	if err := service.Save(" ", Preset{Name: "x", Layout: "b25d,80x24,0,0,0", PaneCount: 1}, false); err == nil {
+		t.Fatal("Save() with empty session error = nil, want error")
+	}
+}
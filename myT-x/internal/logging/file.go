@@ -0,0 +1,71 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// File is an io.Writer appending to a log file that rotates by size.
+//
+// Every Write opens and closes the file, so several processes (e.g. parallel
+// tmux shim invocations) can append to the same file and rotate it under each
+// other; Windows refuses to rename a file another process holds open.
+//
+// Thread-safety: all methods are safe for concurrent use.
+type File struct {
+	rotator *rotator
+	now     func() time.Time
+
+	mu sync.Mutex
+}
+
+// NewFile returns a File writing to path. maxBytes <= 0 disables rotation;
+// keep <= 0 keeps every rotated generation.
+func NewFile(path string, maxBytes int64, keep int) *File {
+	return NewFileWith(path, maxBytes, keep, DefaultFileOps())
+}
+
+// NewFileWith is NewFile with injectable file operations.
+func NewFileWith(path string, maxBytes int64, keep int, ops FileOps) *File {
+	return &File{
+		rotator: newRotator(path, maxBytes, keep, ops),
+		now:     time.Now,
+	}
+}
+
+// Path returns the path of the active log file.
+func (f *File) Path() string {
+	return f.rotator.path
+}
+
+// SetLimits changes the rotation size and the number of kept generations.
+func (f *File) SetLimits(maxBytes int64, keep int) {
+	f.rotator.setLimits(maxBytes, keep)
+}
+
+// Write appends p, rotating the file first when it reached its size limit.
+func (f *File) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := f.rotator.path
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return 0, fmt.Errorf("create log directory %q: %w", filepath.Dir(path), err)
+	}
+	if err := f.rotator.rotateIfNeeded(f.now().Unix()); err != nil {
+		return 0, fmt.Errorf("rotate log file %q: %w", path, err)
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return 0, fmt.Errorf("open log file %q: %w", path, err)
+	}
+	n, writeErr := file.Write(p)
+	closeErr := file.Close()
+	if writeErr != nil {
+		return n, writeErr
+	}
+	return n, closeErr
+}
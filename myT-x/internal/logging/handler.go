@@ -0,0 +1,219 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+// Output formats.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// ParseLevel parses debug, info, warn (or warning), or error, ignoring case.
+func ParseLevel(s string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return slog.LevelInfo, fmt.Errorf("unknown log level %q: want debug, info, warn, or error", s)
+	}
+}
+
+// LevelName returns the name ParseLevel accepts for level.
+func LevelName(level slog.Level) string {
+	switch {
+	case level < slog.LevelInfo:
+		return "debug"
+	case level < slog.LevelWarn:
+		return "info"
+	case level < slog.LevelError:
+		return "warn"
+	default:
+		return "error"
+	}
+}
+
+// NormalizeFormat returns FormatJSON for "json" and FormatText otherwise.
+func NormalizeFormat(format string) string {
+	if strings.EqualFold(strings.TrimSpace(format), FormatJSON) {
+		return FormatJSON
+	}
+	return FormatText
+}
+
+// NewHandler returns a text or JSON handler writing to w at level.
+func NewHandler(w io.Writer, format string, level slog.Leveler) slog.Handler {
+	opts := &slog.HandlerOptions{Level: level}
+	if NormalizeFormat(format) == FormatJSON {
+		return slog.NewJSONHandler(w, opts)
+	}
+	return slog.NewTextHandler(w, opts)
+}
+
+// switchHandler writes through a text or a JSON handler, chosen per record by
+// the current format, so the format can change without replacing loggers.
+type switchHandler struct {
+	text   slog.Handler
+	json   slog.Handler
+	format *atomic.Value
+}
+
+func newSwitchHandler(w io.Writer, level slog.Leveler, format *atomic.Value) *switchHandler {
+	opts := &slog.HandlerOptions{Level: level}
+	return &switchHandler{
+		text:   slog.NewTextHandler(w, opts),
+		json:   slog.NewJSONHandler(w, opts),
+		format: format,
+	}
+}
+
+func (h *switchHandler) current() slog.Handler {
+	if format, _ := h.format.Load().(string); format == FormatJSON {
+		return h.json
+	}
+	return h.text
+}
+
+func (h *switchHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.current().Enabled(ctx, level)
+}
+
+func (h *switchHandler) Handle(ctx context.Context, record slog.Record) error {
+	return h.current().Handle(ctx, record)
+}
+
+func (h *switchHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return &switchHandler{text: h.text.WithAttrs(attrs), json: h.json.WithAttrs(attrs), format: h.format}
+}
+
+func (h *switchHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &switchHandler{text: h.text.WithGroup(name), json: h.json.WithGroup(name), format: h.format}
+}
+
+// fanoutHandler sends each record to every handler enabled for its level.
+type fanoutHandler struct {
+	handlers []slog.Handler
+}
+
+// Fanout returns a handler that sends each record to every given handler
+// enabled for its level.
+func Fanout(handlers ...slog.Handler) slog.Handler {
+	return &fanoutHandler{handlers: handlers}
+}
+
+func (h *fanoutHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, handler := range h.handlers {
+		if handler.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *fanoutHandler) Handle(ctx context.Context, record slog.Record) error {
+	var errs []error
+	for _, handler := range h.handlers {
+		if !handler.Enabled(ctx, record.Level) {
+			continue
+		}
+		if err := handler.Handle(ctx, record.Clone()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (h *fanoutHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	handlers := make([]slog.Handler, len(h.handlers))
+	for i, handler := range h.handlers {
+		handlers[i] = handler.WithAttrs(attrs)
+	}
+	return &fanoutHandler{handlers: handlers}
+}
+
+func (h *fanoutHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	handlers := make([]slog.Handler, len(h.handlers))
+	for i, handler := range h.handlers {
+		handlers[i] = handler.WithGroup(name)
+	}
+	return &fanoutHandler{handlers: handlers}
+}
+
+// Component returns the component a log message belongs to, from the tag
+// the repo's log messages start with: "[WARN-GIT] ..." and "[DEBUG-GIT] ..."
+// belong to "git", "[SESSION] ..." to "session". Messages without a tag
+// belong to DefaultComponent.
+func Component(message string) string {
+	if !strings.HasPrefix(message, "[") {
+		return DefaultComponent
+	}
+	end := strings.IndexByte(message, ']')
+	if end <= 1 {
+		return DefaultComponent
+	}
+	tag := strings.ToLower(message[1:end])
+	for _, prefix := range []string{"debug-", "info-", "warn-", "error-"} {
+		tag = strings.TrimPrefix(tag, prefix)
+	}
+	if tag == "" {
+		return DefaultComponent
+	}
+	for _, r := range tag {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' && r != '_' {
+			return DefaultComponent
+		}
+	}
+	return tag
+}
+
+// Environment variables that set the level and format of processes without
+// access to the app's runtime settings, such as the tmux shim.
+const (
+	EnvLevel  = "MYTX_LOG_LEVEL"
+	EnvFormat = "MYTX_LOG_FORMAT"
+)
+
+// LevelFromEnv returns the level named by EnvLevel, or fallback when it is
+// unset or invalid.
+func LevelFromEnv(fallback slog.Level) slog.Level {
+	value := strings.TrimSpace(os.Getenv(EnvLevel))
+	if value == "" {
+		return fallback
+	}
+	level, err := ParseLevel(value)
+	if err != nil {
+		return fallback
+	}
+	return level
+}
+
+// FormatFromEnv returns the format named by EnvFormat, FormatText by default.
+func FormatFromEnv() string {
+	return NormalizeFormat(os.Getenv(EnvFormat))
+}
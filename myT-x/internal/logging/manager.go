@@ -0,0 +1,192 @@
+// Package logging is the shared logging setup of myT-x processes: leveled
+// text or JSON slog handlers, per-component log files rotated by size, and a
+// level that can change while the process runs.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	// DefaultComponent is the log file of messages without a component tag.
+	DefaultComponent = "app"
+	// DefaultMaxBytes is the size at which a component file rotates.
+	DefaultMaxBytes = 5 * 1024 * 1024
+	// DefaultKeep is the number of rotated generations kept per component.
+	DefaultKeep = 4
+)
+
+// Options configures a Manager.
+type Options struct {
+	// Dir receives one <component>.log per component. Empty disables the
+	// component files.
+	Dir string
+	// Format is FormatText or FormatJSON.
+	Format string
+	Level  slog.Level
+	// MaxBytes is the rotation size of each file; <= 0 uses DefaultMaxBytes.
+	MaxBytes int64
+	// Keep is the number of rotated generations per file; <= 0 uses DefaultKeep.
+	Keep int
+	// DisableFiles stops writing the component files without forgetting Dir.
+	DisableFiles bool
+}
+
+// Manager owns the level, format, and component files of a process.
+//
+// Thread-safety: all methods are safe for concurrent use.
+type Manager struct {
+	level         slog.LevelVar
+	format        atomic.Value // string
+	filesDisabled atomic.Bool
+
+	mu       sync.Mutex
+	dir      string
+	maxBytes int64
+	keep     int
+	files    map[string]*componentFile
+}
+
+type componentFile struct {
+	file    *File
+	handler slog.Handler
+}
+
+// NewManager creates a Manager.
+func NewManager(opts Options) *Manager {
+	m := &Manager{
+		dir:   opts.Dir,
+		files: map[string]*componentFile{},
+	}
+	m.Apply(opts)
+	return m
+}
+
+// Apply changes the level, format, rotation limits, and whether the
+// component files are written. The directory is fixed for the lifetime of
+// the Manager.
+func (m *Manager) Apply(opts Options) {
+	m.level.Set(opts.Level)
+	m.format.Store(NormalizeFormat(opts.Format))
+	m.filesDisabled.Store(opts.DisableFiles)
+
+	maxBytes := opts.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxBytes
+	}
+	keep := opts.Keep
+	if keep <= 0 {
+		keep = DefaultKeep
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.maxBytes = maxBytes
+	m.keep = keep
+	for _, component := range m.files {
+		component.file.SetLimits(maxBytes, keep)
+	}
+}
+
+// Level returns the current level.
+func (m *Manager) Level() slog.Level {
+	return m.level.Level()
+}
+
+// SetLevel changes the level of every handler of m.
+func (m *Manager) SetLevel(level slog.Level) {
+	m.level.Set(level)
+}
+
+// Format returns the current format.
+func (m *Manager) Format() string {
+	format, _ := m.format.Load().(string)
+	return format
+}
+
+// Dir returns the directory of the component files, or "" when disabled.
+func (m *Manager) Dir() string {
+	return m.dir
+}
+
+// Handler returns a handler writing every record at the current level and
+// format to console, and to the file of its component when files are
+// enabled. A nil console writes to the files only.
+func (m *Manager) Handler(console io.Writer) slog.Handler {
+	var handlers []slog.Handler
+	if console != nil {
+		handlers = append(handlers, newSwitchHandler(console, &m.level, &m.format))
+	}
+	if m.dir != "" {
+		handlers = append(handlers, &componentHandler{manager: m})
+	}
+	return Fanout(handlers...)
+}
+
+// ComponentPath returns the active log file of component, or "" when files
+// are disabled.
+func (m *Manager) ComponentPath(component string) string {
+	if m.dir == "" {
+		return ""
+	}
+	return filepath.Join(m.dir, component+".log")
+}
+
+func (m *Manager) componentFile(component string) *componentFile {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if existing, ok := m.files[component]; ok {
+		return existing
+	}
+	file := NewFile(m.ComponentPath(component), m.maxBytes, m.keep)
+	created := &componentFile{
+		file:    file,
+		handler: newSwitchHandler(file, &m.level, &m.format),
+	}
+	m.files[component] = created
+	return created
+}
+
+// componentHandler writes each record to the file of its component. Attrs
+// and groups are replayed onto the per-file handler.
+type componentHandler struct {
+	manager *Manager
+	// wrap applies WithAttrs/WithGroup calls in order.
+	wrap []func(slog.Handler) slog.Handler
+}
+
+func (h *componentHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return !h.manager.filesDisabled.Load() && level >= h.manager.level.Level()
+}
+
+func (h *componentHandler) Handle(ctx context.Context, record slog.Record) error {
+	handler := h.manager.componentFile(Component(record.Message)).handler
+	for _, wrap := range h.wrap {
+		handler = wrap(handler)
+	}
+	return handler.Handle(ctx, record)
+}
+
+func (h *componentHandler) with(wrap func(slog.Handler) slog.Handler) *componentHandler {
+	next := &componentHandler{manager: h.manager, wrap: make([]func(slog.Handler) slog.Handler, 0, len(h.wrap)+1)}
+	next.wrap = append(append(next.wrap, h.wrap...), wrap)
+	return next
+}
+
+func (h *componentHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return h.with(func(handler slog.Handler) slog.Handler { return handler.WithAttrs(attrs) })
+}
+
+func (h *componentHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return h.with(func(handler slog.Handler) slog.Handler { return handler.WithGroup(name) })
+}
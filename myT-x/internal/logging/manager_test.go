@@ -0,0 +1,116 @@
+package logging
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	for input, want := range map[string]slog.Level{
+		"debug":   slog.LevelDebug,
+		" INFO ":  slog.LevelInfo,
+		"warning": slog.LevelWarn,
+		"Error":   slog.LevelError,
+	} {
+		got, err := ParseLevel(input)
+		if err != nil || got != want {
+			t.Fatalf("ParseLevel(%q) = %v, %v, want %v", input, got, err, want)
+		}
+		if parsed, _ := ParseLevel(LevelName(got)); parsed != got {
+			t.Fatalf("LevelName(%v) = %q does not round-trip", got, LevelName(got))
+		}
+	}
+	if _, err := ParseLevel("verbose"); err == nil {
+		t.Fatal("ParseLevel(verbose) should fail")
+	}
+}
+
+func TestComponent(t *testing.T) {
+	tests := map[string]string{
+		"[WARN-GIT] fetch failed":   "git",
+		"[DEBUG-MCP] registered":    "mcp",
+		"[SESSION] cleanup failed":  "session",
+		"[session-log] panicked":    "session-log",
+		"no tag":                    DefaultComponent,
+		"[] empty":                  DefaultComponent,
+		"[WARN-] empty after level": DefaultComponent,
+		"[../escape] path":          DefaultComponent,
+	}
+	for message, want := range tests {
+		if got := Component(message); got != want {
+			t.Fatalf("Component(%q) = %q, want %q", message, got, want)
+		}
+	}
+}
+
+func TestManagerWritesComponentFiles(t *testing.T) {
+	dir := t.TempDir()
+	m := NewManager(Options{Dir: dir, Level: slog.LevelInfo})
+	var console bytes.Buffer
+	logger := slog.New(m.Handler(&console)).With("run", 1)
+
+	logger.Info("[WARN-GIT] fetch failed", "repo", "a")
+	logger.Info("started")
+	logger.Debug("[DEBUG-GIT] hidden")
+
+	gitLog := readFile(t, filepath.Join(dir, "git.log"))
+	if !strings.Contains(gitLog, "fetch failed") || !strings.Contains(gitLog, "run=1") || !strings.Contains(gitLog, "repo=a") {
+		t.Fatalf("git.log = %q", gitLog)
+	}
+	if strings.Contains(gitLog, "hidden") {
+		t.Fatalf("git.log = %q, debug record should be filtered", gitLog)
+	}
+	if appLog := readFile(t, filepath.Join(dir, "app.log")); !strings.Contains(appLog, "started") {
+		t.Fatalf("app.log = %q", appLog)
+	}
+	if !strings.Contains(console.String(), "fetch failed") || !strings.Contains(console.String(), "started") {
+		t.Fatalf("console = %q", console.String())
+	}
+
+	m.SetLevel(slog.LevelDebug)
+	m.Apply(Options{Level: slog.LevelDebug, Format: FormatJSON})
+	logger.Debug("[DEBUG-GIT] now visible")
+	gitLog = readFile(t, filepath.Join(dir, "git.log"))
+	if !strings.Contains(gitLog, `"msg":"[DEBUG-GIT] now visible"`) {
+		t.Fatalf("git.log = %q, want JSON debug record after Apply", gitLog)
+	}
+	if m.Level() != slog.LevelDebug || m.Format() != FormatJSON {
+		t.Fatalf("Level() = %v, Format() = %q", m.Level(), m.Format())
+	}
+
+	m.Apply(Options{Level: slog.LevelDebug, DisableFiles: true})
+	logger.Info("[WARN-GIT] console only")
+	if strings.Contains(readFile(t, filepath.Join(dir, "git.log")), "console only") {
+		t.Fatal("disabled files should not be written")
+	}
+	if !strings.Contains(console.String(), "console only") {
+		t.Fatalf("console = %q", console.String())
+	}
+}
+
+func TestManagerWithoutDirWritesConsoleOnly(t *testing.T) {
+	m := NewManager(Options{Level: slog.LevelWarn})
+	var console bytes.Buffer
+	logger := slog.New(m.Handler(&console))
+	logger.Info("dropped")
+	logger.Warn("kept")
+	if got := console.String(); strings.Contains(got, "dropped") || !strings.Contains(got, "kept") {
+		t.Fatalf("console = %q", got)
+	}
+	if m.ComponentPath("app") != "" {
+		t.Fatal("ComponentPath() should be empty without a directory")
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile(%s) error = %v", path, err)
+	}
+	return string(data)
+}
@@ -0,0 +1,248 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileOps holds injectable file operations for log rotation and pruning.
+// Tests create custom instances instead of mutating package-level state.
+type FileOps struct {
+	Rename func(oldPath, newPath string) error
+	Remove func(string) error
+}
+
+// DefaultFileOps returns production file operations.
+func DefaultFileOps() FileOps {
+	return FileOps{
+		Rename: os.Rename,
+		Remove: os.Remove,
+	}
+}
+
+// rotator rotates one log file by size. Rotated generations are named
+// <stem>-<unixtime>.log next to the active <stem>.log.
+type rotator struct {
+	path string
+	ops  FileOps
+
+	mu       sync.Mutex
+	maxBytes int64
+	keep     int
+	// rotatedCount caches the number of rotated generations to avoid a
+	// directory scan per rotation; -1 until the first scan.
+	rotatedCount int
+}
+
+func newRotator(path string, maxBytes int64, keep int, ops FileOps) *rotator {
+	return &rotator{
+		path:         path,
+		ops:          ops,
+		maxBytes:     maxBytes,
+		keep:         keep,
+		rotatedCount: -1,
+	}
+}
+
+func (r *rotator) setLimits(maxBytes int64, keep int) {
+	r.mu.Lock()
+	r.maxBytes = maxBytes
+	r.keep = keep
+	r.mu.Unlock()
+}
+
+func (r *rotator) stem() string {
+	return strings.TrimSuffix(filepath.Base(r.path), ".log")
+}
+
+// rotateIfNeeded renames the active file to a new generation once it reached
+// maxBytes and prunes generations beyond keep. A file missing because
+// another process already rotated it is not an error.
+//
+// IMPORTANT: This function MUST NOT log through a handler that writes to the
+// rotated file: that would recurse back into rotation. Prune diagnostics go
+// to stderr via warnf instead.
+func (r *rotator) rotateIfNeeded(unixTime int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.maxBytes <= 0 {
+		return nil
+	}
+
+	info, err := os.Stat(r.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	if info.Size() < r.maxBytes {
+		return nil
+	}
+
+	logDir := filepath.Dir(r.path)
+	for retry := range 4 {
+		nextPath, err := r.nextRotatedPath(unixTime + int64(retry))
+		if err != nil {
+			return err
+		}
+		err = r.ops.Rename(r.path, nextPath)
+		if err == nil {
+			if r.shouldPrune() {
+				if cleanupErr := r.prune(); cleanupErr != nil {
+					warnf("prune rotated log files in %q: %v", logDir, cleanupErr)
+				} else {
+					r.rotatedCount = r.keep
+				}
+			}
+			return nil
+		}
+		if errors.Is(err, os.ErrExist) {
+			continue
+		}
+		if errors.Is(err, os.ErrNotExist) {
+			// Another process already rotated/deleted it.
+			return nil
+		}
+		return err
+	}
+	return fmt.Errorf("failed to rotate log file after retries: %s", r.path)
+}
+
+func (r *rotator) nextRotatedPath(unixTime int64) (string, error) {
+	// keep=32 is the largest steady state in use, and 64 keeps 2x headroom
+	// for short timestamp collisions during concurrent rotations.
+	const maxAttempts = 64
+	logDir := filepath.Dir(r.path)
+	stem := r.stem()
+	for offset := range int64(maxAttempts) {
+		candidate := filepath.Join(logDir, fmt.Sprintf("%s-%d.log", stem, unixTime+offset))
+		_, err := os.Stat(candidate)
+		if errors.Is(err, os.ErrNotExist) {
+			return candidate, nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return "", fmt.Errorf("unable to allocate rotated log path from unix=%d", unixTime)
+}
+
+// shouldPrune updates the cached generation count after a rotation and
+// reports whether prune should run. Callers hold r.mu.
+func (r *rotator) shouldPrune() bool {
+	if r.keep <= 0 {
+		return false
+	}
+	if r.rotatedCount >= 0 {
+		r.rotatedCount++
+		return r.rotatedCount > r.keep
+	}
+	count, err := r.countRotated()
+	if err != nil {
+		return true
+	}
+	r.rotatedCount = count
+	return count > r.keep
+}
+
+// countRotated returns the number of valid rotated generations.
+func (r *rotator) countRotated() (int, error) {
+	entries, err := os.ReadDir(filepath.Dir(r.path))
+	if err != nil {
+		return 0, err
+	}
+	stem := r.stem()
+	count := 0
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		if _, ok := ParseRotatedUnix(stem, entry.Name()); ok {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// prune keeps the newest keep generations and removes older ones. Remove
+// failures do not stop the remaining removals. Callers hold r.mu.
+func (r *rotator) prune() error {
+	if r.keep <= 0 {
+		return nil
+	}
+
+	logDir := filepath.Dir(r.path)
+	entries, err := os.ReadDir(logDir)
+	if err != nil {
+		return err
+	}
+
+	stem := r.stem()
+	type rotatedLog struct {
+		path      string
+		timestamp int64
+	}
+	logs := make([]rotatedLog, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		name := entry.Name()
+		timestamp, ok := ParseRotatedUnix(stem, name)
+		if !ok {
+			if strings.HasPrefix(name, stem+"-") && strings.HasSuffix(name, ".log") {
+				warnf("skip rotated log with invalid unix timestamp: %s", name)
+			}
+			continue
+		}
+
+		logs = append(logs, rotatedLog{
+			path:      filepath.Join(logDir, name),
+			timestamp: timestamp,
+		})
+	}
+
+	sort.Slice(logs, func(i, j int) bool {
+		return logs[i].timestamp > logs[j].timestamp
+	})
+
+	var removeErrs []error
+	for i := r.keep; i < len(logs); i++ {
+		if err := r.ops.Remove(logs[i].path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			removeErrs = append(removeErrs, fmt.Errorf("remove %s: %w", logs[i].path, err))
+		}
+	}
+	return errors.Join(removeErrs...)
+}
+
+// ParseRotatedUnix parses <stem>-<unix>.log and returns its unix timestamp.
+func ParseRotatedUnix(stem, path string) (int64, bool) {
+	name := filepath.Base(path)
+	prefix := stem + "-"
+	if !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ".log") {
+		return 0, false
+	}
+	timestampText := strings.TrimSuffix(strings.TrimPrefix(name, prefix), ".log")
+	timestamp, err := strconv.ParseInt(timestampText, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return timestamp, true
+}
+
+// warnf writes a diagnostic of the logging subsystem itself directly to
+// stderr, bypassing slog so a failing log file cannot recurse into itself.
+// This is best-effort: if stderr is unavailable the warning is dropped.
+func warnf(format string, args ...any) {
+	now := time.Now().Format("2006/01/02 15:04:05")
+	fmt.Fprintf(os.Stderr, "[LOGGING] %s %s\n", now, fmt.Sprintf(format, args...))
+}
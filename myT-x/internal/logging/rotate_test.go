@@ -0,0 +1,434 @@
+package logging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testMaxBytes = 1024
+
+// NOT safe for t.Parallel(): this helper temporarily replaces os.Stderr.
+func captureStderr(t *testing.T, run func()) string {
+	t.Helper()
+
+	original := os.Stderr
+	readPipe, writePipe, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe() error = %v", err)
+	}
+
+	os.Stderr = writePipe
+	t.Cleanup(func() {
+		os.Stderr = original
+		_ = writePipe.Close()
+		_ = readPipe.Close()
+	})
+
+	run()
+	_ = writePipe.Close()
+
+	output, readErr := io.ReadAll(readPipe)
+	if readErr != nil {
+		t.Fatalf("ReadAll(stderr pipe) error = %v", readErr)
+	}
+	return string(output)
+}
+
+func TestNextRotatedPathIncrementsOnCollision(t *testing.T) {
+	logDir := t.TempDir()
+	for _, name := range []string{"app-1700000000.log", "app-1700000001.log"} {
+		if err := os.WriteFile(filepath.Join(logDir, name), []byte("x"), 0o644); err != nil {
+			t.Fatalf("failed to create collision file: %v", err)
+		}
+	}
+
+	r := newRotator(filepath.Join(logDir, "app.log"), testMaxBytes, 4, DefaultFileOps())
+	nextPath, err := r.nextRotatedPath(1700000000)
+	if err != nil {
+		t.Fatalf("nextRotatedPath() error = %v", err)
+	}
+	if want := filepath.Join(logDir, "app-1700000002.log"); nextPath != want {
+		t.Fatalf("next path = %q, want %q", nextPath, want)
+	}
+}
+
+func TestNextRotatedPathFailsWhenAttemptsExhausted(t *testing.T) {
+	logDir := t.TempDir()
+	startUnix := int64(1700003000)
+	for ts := startUnix; ts < startUnix+64; ts++ {
+		path := filepath.Join(logDir, fmt.Sprintf("app-%d.log", ts))
+		if err := os.WriteFile(path, []byte("occupied"), 0o644); err != nil {
+			t.Fatalf("failed to create occupied path %s: %v", path, err)
+		}
+	}
+
+	r := newRotator(filepath.Join(logDir, "app.log"), testMaxBytes, 4, DefaultFileOps())
+	if _, err := r.nextRotatedPath(startUnix); err == nil {
+		t.Fatal("nextRotatedPath() expected exhaustion error")
+	}
+}
+
+func TestRotateIfNeededScenarios(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name        string
+		unixTime    int64
+		basePayload []byte
+		wantBase    bool
+		wantRotated bool
+	}{
+		{"rotates at size limit", 1700000100, bytes.Repeat([]byte("a"), testMaxBytes), false, true},
+		{"no-op below size limit", 1700000200, bytes.Repeat([]byte("a"), testMaxBytes-1), true, false},
+		{"rotates above size limit", 1700000250, bytes.Repeat([]byte("a"), testMaxBytes+1), false, true},
+		{"no-op when base file missing", 1700000300, nil, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			logDir := t.TempDir()
+			basePath := filepath.Join(logDir, "app.log")
+			if tt.basePayload != nil {
+				if err := os.WriteFile(basePath, tt.basePayload, 0o644); err != nil {
+					t.Fatalf("failed to create base log: %v", err)
+				}
+			}
+
+			r := newRotator(basePath, testMaxBytes, 4, DefaultFileOps())
+			if err := r.rotateIfNeeded(tt.unixTime); err != nil {
+				t.Fatalf("rotateIfNeeded() error = %v", err)
+			}
+
+			_, baseErr := os.Stat(basePath)
+			if tt.wantBase {
+				if baseErr != nil {
+					t.Fatalf("base log should remain, stat err = %v", baseErr)
+				}
+			} else if !errors.Is(baseErr, os.ErrNotExist) {
+				t.Fatalf("base log should be absent, stat err = %v", baseErr)
+			}
+
+			_, rotatedErr := os.Stat(filepath.Join(logDir, fmt.Sprintf("app-%d.log", tt.unixTime)))
+			if tt.wantRotated {
+				if rotatedErr != nil {
+					t.Fatalf("rotated log missing: %v", rotatedErr)
+				}
+			} else if !errors.Is(rotatedErr, os.ErrNotExist) {
+				t.Fatalf("rotated log should not exist, stat err = %v", rotatedErr)
+			}
+		})
+	}
+}
+
+func TestRotateIfNeededRetriesOnRenameCollision(t *testing.T) {
+	t.Parallel()
+
+	logDir := t.TempDir()
+	basePath := filepath.Join(logDir, "app.log")
+	if err := os.WriteFile(basePath, bytes.Repeat([]byte("a"), testMaxBytes), 0o644); err != nil {
+		t.Fatalf("failed to create base log: %v", err)
+	}
+
+	renameCalls := 0
+	ops := FileOps{
+		Rename: func(oldPath, newPath string) error {
+			renameCalls++
+			if renameCalls < 3 {
+				return os.ErrExist
+			}
+			return os.Rename(oldPath, newPath)
+		},
+		Remove: os.Remove,
+	}
+
+	r := newRotator(basePath, testMaxBytes, 4, ops)
+	if err := r.rotateIfNeeded(1700002100); err != nil {
+		t.Fatalf("rotateIfNeeded() error = %v", err)
+	}
+	if renameCalls != 3 {
+		t.Fatalf("rename call count = %d, want 3", renameCalls)
+	}
+	if _, err := os.Stat(filepath.Join(logDir, "app-1700002102.log")); err != nil {
+		t.Fatalf("expected rotated log, stat err = %v", err)
+	}
+}
+
+func TestRotateIfNeededFailsAfterMaxRenameRetries(t *testing.T) {
+	t.Parallel()
+
+	logDir := t.TempDir()
+	basePath := filepath.Join(logDir, "app.log")
+	if err := os.WriteFile(basePath, bytes.Repeat([]byte("a"), testMaxBytes), 0o644); err != nil {
+		t.Fatalf("failed to create base log: %v", err)
+	}
+
+	renameCalls := 0
+	ops := FileOps{
+		Rename: func(_, _ string) error {
+			renameCalls++
+			return os.ErrExist
+		},
+		Remove: os.Remove,
+	}
+
+	r := newRotator(basePath, testMaxBytes, 4, ops)
+	if err := r.rotateIfNeeded(1700002150); err == nil {
+		t.Fatal("rotateIfNeeded() expected retry exhaustion error")
+	}
+	if renameCalls != 4 {
+		t.Fatalf("rename call count = %d, want 4", renameCalls)
+	}
+}
+
+func TestRotateIfNeededPrunesOldGenerations(t *testing.T) {
+	logDir := t.TempDir()
+	basePath := filepath.Join(logDir, "app.log")
+	if err := os.WriteFile(basePath, bytes.Repeat([]byte("a"), testMaxBytes), 0o644); err != nil {
+		t.Fatalf("failed to create base log: %v", err)
+	}
+	for ts := int64(1700001000); ts < 1700001012; ts++ {
+		path := filepath.Join(logDir, fmt.Sprintf("app-%d.log", ts))
+		if err := os.WriteFile(path, []byte("old"), 0o644); err != nil {
+			t.Fatalf("failed to create rotated log %s: %v", path, err)
+		}
+	}
+	// Generations of other components are left alone.
+	other := filepath.Join(logDir, "git-1700000000.log")
+	if err := os.WriteFile(other, []byte("other"), 0o644); err != nil {
+		t.Fatalf("failed to create other component log: %v", err)
+	}
+
+	const keep = 4
+	r := newRotator(basePath, testMaxBytes, keep, DefaultFileOps())
+	if err := r.rotateIfNeeded(1700002000); err != nil {
+		t.Fatalf("rotateIfNeeded() error = %v", err)
+	}
+
+	rotated, err := filepath.Glob(filepath.Join(logDir, "app-*.log"))
+	if err != nil {
+		t.Fatalf("Glob() error = %v", err)
+	}
+	if len(rotated) != keep {
+		t.Fatalf("rotated log count = %d, want %d", len(rotated), keep)
+	}
+	if _, statErr := os.Stat(filepath.Join(logDir, "app-1700002000.log")); statErr != nil {
+		t.Fatalf("newest rotated log missing: %v", statErr)
+	}
+	if _, statErr := os.Stat(other); statErr != nil {
+		t.Fatalf("other component log should remain: %v", statErr)
+	}
+}
+
+func TestPruneContinuesAfterRemoveError(t *testing.T) {
+	t.Parallel()
+
+	logDir := t.TempDir()
+	log1 := filepath.Join(logDir, "app-1.log")
+	log2 := filepath.Join(logDir, "app-2.log")
+	log3 := filepath.Join(logDir, "app-3.log")
+	for _, path := range []string{log1, log2, log3} {
+		if err := os.WriteFile(path, []byte("old"), 0o644); err != nil {
+			t.Fatalf("failed to create rotated log %s: %v", path, err)
+		}
+	}
+
+	var removed []string
+	ops := FileOps{
+		Rename: os.Rename,
+		Remove: func(path string) error {
+			removed = append(removed, filepath.Base(path))
+			if strings.HasSuffix(path, "app-2.log") {
+				return errors.New("simulated remove failure")
+			}
+			return os.Remove(path)
+		},
+	}
+
+	r := newRotator(filepath.Join(logDir, "app.log"), testMaxBytes, 1, ops)
+	if err := r.prune(); err == nil {
+		t.Fatal("prune() expected aggregated remove error")
+	}
+	if len(removed) != 2 {
+		t.Fatalf("remove calls = %v, want 2 files", removed)
+	}
+	if _, statErr := os.Stat(log2); errors.Is(statErr, os.ErrNotExist) {
+		t.Fatalf("failed file should remain: %s", log2)
+	}
+	if _, statErr := os.Stat(log1); !errors.Is(statErr, os.ErrNotExist) {
+		t.Fatalf("other old file should still be pruned, stat err = %v", statErr)
+	}
+}
+
+func TestPruneNoopWhenKeepIsNonPositive(t *testing.T) {
+	logDir := t.TempDir()
+	logPath := filepath.Join(logDir, "app-1.log")
+	if err := os.WriteFile(logPath, []byte("old"), 0o644); err != nil {
+		t.Fatalf("failed to create rotated log: %v", err)
+	}
+
+	r := newRotator(filepath.Join(logDir, "app.log"), testMaxBytes, 0, DefaultFileOps())
+	if err := r.prune(); err != nil {
+		t.Fatalf("prune() error = %v", err)
+	}
+	if _, err := os.Stat(logPath); err != nil {
+		t.Fatalf("rotated log should remain for keep<=0: %v", err)
+	}
+}
+
+func TestShouldPruneUsesCachedCount(t *testing.T) {
+	logDir := t.TempDir()
+	r := newRotator(filepath.Join(logDir, "app.log"), testMaxBytes, 2, DefaultFileOps())
+
+	if err := os.WriteFile(filepath.Join(logDir, "app-1700001001.log"), []byte("new"), 0o644); err != nil {
+		t.Fatalf("failed to create rotated log: %v", err)
+	}
+	if r.shouldPrune() {
+		t.Fatal("first check should not prune below keep limit")
+	}
+	// The second and third checks count rotations without scanning.
+	if r.shouldPrune() {
+		t.Fatal("second check should not prune at keep limit")
+	}
+	if !r.shouldPrune() {
+		t.Fatal("third check should prune when cached count exceeds keep")
+	}
+}
+
+func TestShouldPruneSkipsBelowLimit(t *testing.T) {
+	logDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(logDir, "app-1700001001.log"), []byte("new"), 0o644); err != nil {
+		t.Fatalf("failed to create rotated log: %v", err)
+	}
+
+	r := newRotator(filepath.Join(logDir, "app.log"), testMaxBytes, 32, DefaultFileOps())
+	if r.shouldPrune() {
+		t.Fatal("shouldPrune() = true, want false below keep limit")
+	}
+}
+
+func TestNextRotatedPathReturnsErrorForInvalidLogDir(t *testing.T) {
+	r := newRotator(filepath.Join(string([]byte{0}), "app.log"), testMaxBytes, 4, DefaultFileOps())
+	if _, err := r.nextRotatedPath(1700004000); err == nil {
+		t.Fatal("nextRotatedPath() expected stat error")
+	}
+}
+
+// With slog writing into the rotated file, a prune warning logged through
+// slog would re-enter rotation and deadlock on the rotator mutex. Warnings
+// must reach stderr instead and the write must complete.
+func TestPruneDoesNotRecurseThroughLogHandler(t *testing.T) {
+	logDir := t.TempDir()
+	for _, name := range []string{"app-1700005000.log", "app-1700005001.log", "app-notanumber.log"} {
+		if err := os.WriteFile(filepath.Join(logDir, name), []byte("old"), 0o644); err != nil {
+			t.Fatalf("failed to create %s: %v", name, err)
+		}
+	}
+	path := filepath.Join(logDir, "app.log")
+	f := NewFile(path, 8, 1)
+	original := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(f, nil)))
+	t.Cleanup(func() { slog.SetDefault(original) })
+
+	output := captureStderr(t, func() {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			slog.Info("first line fills the file")
+			slog.Info("second line rotates and prunes")
+		}()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("logging through the rotated file did not return; prune recursed")
+		}
+	})
+
+	if !strings.Contains(output, "app-notanumber.log") {
+		t.Fatalf("stderr output = %q, want warning about the invalid file name", output)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if strings.Contains(string(data), "notanumber") {
+		t.Fatalf("active log = %q, prune warning must not be written through slog", data)
+	}
+}
+
+// Prune diagnostics must not go through a handler writing to the file being
+// rotated, or an invalid file name would recurse into rotation.
+func TestPruneWarnsOnStderrForInvalidNames(t *testing.T) {
+	logDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(logDir, "app-1700005000.log"), []byte("valid"), 0o644); err != nil {
+		t.Fatalf("failed to create valid rotated log: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(logDir, "app-notanumber.log"), []byte("invalid"), 0o644); err != nil {
+		t.Fatalf("failed to create invalid rotated log: %v", err)
+	}
+
+	r := newRotator(filepath.Join(logDir, "app.log"), testMaxBytes, 10, DefaultFileOps())
+	output := captureStderr(t, func() {
+		if err := r.prune(); err != nil {
+			t.Fatalf("prune() unexpected error = %v", err)
+		}
+	})
+	if !strings.Contains(output, "[LOGGING]") || !strings.Contains(output, "app-notanumber.log") {
+		t.Fatalf("stderr output = %q, want warning about invalid timestamp", output)
+	}
+}
+
+func TestParseRotatedUnix(t *testing.T) {
+	tests := []struct {
+		name      string
+		path      string
+		wantOK    bool
+		wantValue int64
+	}{
+		{"valid filename", "shim-debug-1700000123.log", true, 1700000123},
+		{"valid path with directory", filepath.Join("C:\\logs", "shim-debug-1700000456.log"), true, 1700000456},
+		{"invalid prefix", "debug-1700000123.log", false, 0},
+		{"invalid suffix", "shim-debug-1700000123.txt", false, 0},
+		{"missing timestamp", "shim-debug-.log", false, 0},
+		{"non numeric timestamp", "shim-debug-abc.log", false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotValue, gotOK := ParseRotatedUnix("shim-debug", tt.path)
+			if gotOK != tt.wantOK || gotValue != tt.wantValue {
+				t.Fatalf("ParseRotatedUnix(%q) = %d, %v, want %d, %v", tt.path, gotValue, gotOK, tt.wantValue, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestFileWriteRotates(t *testing.T) {
+	logDir := t.TempDir()
+	path := filepath.Join(logDir, "nested", "app.log")
+	f := NewFile(path, 8, 2)
+
+	for range 3 {
+		if _, err := f.Write([]byte("0123456789\n")); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if string(data) != "0123456789\n" {
+		t.Fatalf("active log = %q, want only the last line", data)
+	}
+	rotated, err := filepath.Glob(filepath.Join(logDir, "nested", "app-*.log"))
+	if err != nil || len(rotated) != 2 {
+		t.Fatalf("rotated = %v (err %v), want 2 generations", rotated, err)
+	}
+}
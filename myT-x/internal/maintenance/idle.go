@@ -0,0 +1,52 @@
+package maintenance
+
+import "sync"
+
+// DefaultCPUThreshold is the system CPU usage (0-1) at or above which the
+// machine is considered busy.
+const DefaultCPUThreshold = 0.3
+
+// IdleProbe reports the app and machine idle when none of this process's
+// windows has focus and system CPU usage since the previous call is below
+// CPUThreshold. The first call only takes a CPU sample and reports busy.
+//
+// Thread-safety is managed internally via mu. No external locking is required.
+type IdleProbe struct {
+	CPUThreshold float64
+
+	// hasFocus and cpuTimes are platform hooks, replaced in tests.
+	hasFocus func() bool
+	cpuTimes func() (idle, total uint64, ok bool)
+
+	mu        sync.Mutex
+	lastIdle  uint64
+	lastTotal uint64
+}
+
+// NewIdleProbe creates a probe using the platform focus and CPU sources.
+// On platforms without them the probe always reports busy.
+func NewIdleProbe() *IdleProbe {
+	return &IdleProbe{
+		CPUThreshold: DefaultCPUThreshold,
+		hasFocus:     processHasForegroundWindow,
+		cpuTimes:     systemCPUTimes,
+	}
+}
+
+// Idle reports whether maintenance may run now.
+func (p *IdleProbe) Idle() bool {
+	idle, total, ok := p.cpuTimes()
+	if !ok {
+		return false
+	}
+	p.mu.Lock()
+	prevIdle, prevTotal := p.lastIdle, p.lastTotal
+	p.lastIdle, p.lastTotal = idle, total
+	p.mu.Unlock()
+
+	if prevTotal == 0 || total <= prevTotal || idle < prevIdle {
+		return false
+	}
+	busy := 1 - float64(idle-prevIdle)/float64(total-prevTotal)
+	return busy < p.CPUThreshold && !p.hasFocus()
+}
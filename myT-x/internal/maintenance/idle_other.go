@@ -0,0 +1,7 @@
+//go:build !windows
+
+package maintenance
+
+func processHasForegroundWindow() bool { return true }
+
+func systemCPUTimes() (idle, total uint64, ok bool) { return 0, 0, false }
@@ -0,0 +1,51 @@
+package maintenance
+
+import "testing"
+
+func TestIdleProbe(t *testing.T) {
+	type sample struct{ idle, total uint64 }
+	tests := []struct {
+		name    string
+		samples []sample
+		focused bool
+		want    bool
+	}{
+		{name: "first sample", samples: []sample{{90, 100}}, want: false},
+		{name: "low cpu unfocused", samples: []sample{{90, 100}, {185, 200}}, want: true},
+		{name: "low cpu focused", samples: []sample{{90, 100}, {185, 200}}, focused: true, want: false},
+		{name: "high cpu", samples: []sample{{90, 100}, {110, 200}}, want: false},
+		{name: "counter reset", samples: []sample{{90, 100}, {5, 10}}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := 0
+			probe := &IdleProbe{
+				CPUThreshold: DefaultCPUThreshold,
+				hasFocus:     func() bool { return tt.focused },
+				cpuTimes: func() (uint64, uint64, bool) {
+					s := tt.samples[i]
+					i++
+					return s.idle, s.total, true
+				},
+			}
+			var got bool
+			for range tt.samples {
+				got = probe.Idle()
+			}
+			if got != tt.want {
+				t.Fatalf("Idle() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIdleProbeUnavailableCPU(t *testing.T) {
+	probe := &IdleProbe{
+		CPUThreshold: DefaultCPUThreshold,
+		hasFocus:     func() bool { return false },
+		cpuTimes:     func() (uint64, uint64, bool) { return 0, 0, false },
+	}
+	if probe.Idle() || probe.Idle() {
+		t.Fatal("Idle() = true without CPU times")
+	}
+}
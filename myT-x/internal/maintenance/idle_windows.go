@@ -0,0 +1,50 @@
+//go:build windows
+
+package maintenance
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	user32                       = syscall.NewLazyDLL("user32.dll")
+	kernel32                     = syscall.NewLazyDLL("kernel32.dll")
+	procGetForegroundWindow      = user32.NewProc("GetForegroundWindow")
+	procGetWindowThreadProcessID = user32.NewProc("GetWindowThreadProcessId")
+	procGetSystemTimes           = kernel32.NewProc("GetSystemTimes")
+)
+
+// processHasForegroundWindow reports whether the foreground window belongs
+// to this process.
+func processHasForegroundWindow() bool {
+	hwnd, _, _ := procGetForegroundWindow.Call()
+	if hwnd == 0 {
+		return false
+	}
+	var pid uint32
+	procGetWindowThreadProcessID.Call(hwnd, uintptr(unsafe.Pointer(&pid)))
+	return pid == uint32(os.Getpid())
+}
+
+// systemCPUTimes returns cumulative idle and total (kernel+user) CPU time.
+// Kernel time already includes idle time.
+func systemCPUTimes() (idle, total uint64, ok bool) {
+	var idleTime, kernelTime, userTime syscall.Filetime
+	r, _, _ := procGetSystemTimes.Call(
+		uintptr(unsafe.Pointer(&idleTime)),
+		uintptr(unsafe.Pointer(&kernelTime)),
+		uintptr(unsafe.Pointer(&userTime)),
+	)
+	if r == 0 {
+		return 0, 0, false
+	}
+	idle = filetimeTicks(idleTime)
+	total = filetimeTicks(kernelTime) + filetimeTicks(userTime)
+	return idle, total, true
+}
+
+func filetimeTicks(ft syscall.Filetime) uint64 {
+	return uint64(ft.HighDateTime)<<32 | uint64(ft.LowDateTime)
+}
@@ -0,0 +1,334 @@
+// Package maintenance runs low-priority housekeeping jobs (log pruning,
+// git gc, cache compaction, backups) only while the app and machine are idle,
+// and keeps persisted last-run bookkeeping per job.
+package maintenance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// StateFileName is the JSON file last-run bookkeeping is persisted to.
+	StateFileName = "maintenance-state.json"
+	// defaultCheckInterval is how often the scheduler looks for due jobs.
+	defaultCheckInterval = time.Minute
+)
+
+// ErrJobNotFound is returned by Trigger for an unregistered job name.
+var ErrJobNotFound = errors.New("maintenance job not found")
+
+// ErrJobRunning is returned by Trigger when the job is already running.
+var ErrJobRunning = errors.New("maintenance job is already running")
+
+// Job is one registered maintenance task.
+type Job struct {
+	Name        string
+	Description string
+	// Interval is the minimum time between two runs.
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// JobStatus describes a registered job and its last run.
+type JobStatus struct {
+	Name           string `json:"name"`
+	Description    string `json:"description"`
+	IntervalSec    int64  `json:"interval_sec"`
+	LastRun        string `json:"last_run,omitempty"`
+	LastDurationMs int64  `json:"last_duration_ms,omitempty"`
+	LastError      string `json:"last_error,omitempty"`
+	Running        bool   `json:"running"`
+	// NextDue is when the job may run again; empty when it is due now.
+	NextDue string `json:"next_due,omitempty"`
+}
+
+// lastRun is the persisted bookkeeping of one job.
+type lastRun struct {
+	At         time.Time `json:"at"`
+	DurationMs int64     `json:"duration_ms"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// Deps holds external dependencies injected at construction time.
+type Deps struct {
+	// IsIdle reports whether scheduled jobs may run now.
+	IsIdle func() bool
+
+	// StatePath returns the path of the bookkeeping file.
+	// Optional: bookkeeping is kept in memory only if nil.
+	StatePath func() (string, error)
+
+	// CheckInterval is how often Run looks for due jobs.
+	// Optional: defaults to one minute.
+	CheckInterval time.Duration
+
+	// Now returns the current time. Optional: defaults to time.Now.
+	Now func() time.Time
+}
+
+// Service is the maintenance job registry and idle-time scheduler.
+//
+// Thread-safety is managed internally via mu. No external locking is required.
+// Jobs run one at a time; a job never runs concurrently with itself.
+type Service struct {
+	deps Deps
+
+	// runMu serializes job execution.
+	runMu sync.Mutex
+
+	mu      sync.Mutex
+	jobs    map[string]Job
+	runs    map[string]lastRun
+	running map[string]bool
+	loaded  bool
+}
+
+// NewService creates a maintenance service.
+// Panics if IsIdle is nil.
+func NewService(deps Deps) *Service {
+	if deps.IsIdle == nil {
+		panic("maintenance.NewService: IsIdle must be non-nil")
+	}
+	if deps.CheckInterval <= 0 {
+		deps.CheckInterval = defaultCheckInterval
+	}
+	if deps.Now == nil {
+		deps.Now = time.Now
+	}
+	return &Service{
+		deps:    deps,
+		jobs:    map[string]Job{},
+		runs:    map[string]lastRun{},
+		running: map[string]bool{},
+	}
+}
+
+// Register adds job to the registry.
+func (s *Service) Register(job Job) error {
+	job.Name = strings.TrimSpace(job.Name)
+	if job.Name == "" {
+		return errors.New("maintenance job name is required")
+	}
+	if job.Run == nil {
+		return fmt.Errorf("maintenance job %q has no Run function", job.Name)
+	}
+	if job.Interval <= 0 {
+		return fmt.Errorf("maintenance job %q interval must be positive", job.Name)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.jobs[job.Name]; exists {
+		return fmt.Errorf("maintenance job %q is already registered", job.Name)
+	}
+	s.jobs[job.Name] = job
+	return nil
+}
+
+// Run checks for due jobs every CheckInterval until ctx is cancelled.
+func (s *Service) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.deps.CheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.RunDue(ctx)
+		}
+	}
+}
+
+// RunDue runs every due job, re-checking idleness before each one so work
+// stops as soon as the user comes back. It returns the number of jobs run.
+func (s *Service) RunDue(ctx context.Context) int {
+	ran := 0
+	for _, job := range s.dueJobs() {
+		if ctx.Err() != nil || !s.deps.IsIdle() {
+			break
+		}
+		if err := s.run(ctx, job); errors.Is(err, ErrJobRunning) {
+			continue
+		}
+		ran++
+	}
+	return ran
+}
+
+// Trigger runs the named job now regardless of idleness or schedule.
+// The returned error is the job's own error.
+func (s *Service) Trigger(ctx context.Context, name string) error {
+	s.mu.Lock()
+	job, ok := s.jobs[name]
+	s.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrJobNotFound, name)
+	}
+	return s.run(ctx, job)
+}
+
+// Status returns all registered jobs sorted by name.
+func (s *Service) Status() []JobStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.loadLocked()
+	now := s.deps.Now()
+	statuses := make([]JobStatus, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		status := JobStatus{
+			Name:        job.Name,
+			Description: job.Description,
+			IntervalSec: int64(job.Interval / time.Second),
+			Running:     s.running[job.Name],
+		}
+		if run, ok := s.runs[job.Name]; ok {
+			status.LastRun = run.At.UTC().Format(time.RFC3339)
+			status.LastDurationMs = run.DurationMs
+			status.LastError = run.Error
+			if next := run.At.Add(job.Interval); next.After(now) {
+				status.NextDue = next.UTC().Format(time.RFC3339)
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	slices.SortFunc(statuses, func(a, b JobStatus) int { return strings.Compare(a.Name, b.Name) })
+	return statuses
+}
+
+func (s *Service) dueJobs() []Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.loadLocked()
+	now := s.deps.Now()
+	var due []Job
+	for _, job := range s.jobs {
+		if run, ok := s.runs[job.Name]; ok && now.Sub(run.At) < job.Interval {
+			continue
+		}
+		due = append(due, job)
+	}
+	slices.SortFunc(due, func(a, b Job) int { return strings.Compare(a.Name, b.Name) })
+	return due
+}
+
+func (s *Service) run(ctx context.Context, job Job) error {
+	s.mu.Lock()
+	if s.running[job.Name] {
+		s.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrJobRunning, job.Name)
+	}
+	s.running[job.Name] = true
+	s.mu.Unlock()
+
+	s.runMu.Lock()
+	started := s.deps.Now()
+	err := runJob(ctx, job)
+	duration := s.deps.Now().Sub(started)
+	s.runMu.Unlock()
+
+	record := lastRun{At: started, DurationMs: duration.Milliseconds()}
+	if err != nil {
+		record.Error = err.Error()
+		slog.Warn("[MAINTENANCE] job failed", "job", job.Name, "durationMs", record.DurationMs, "error", err)
+	} else {
+		slog.Info("[MAINTENANCE] job finished", "job", job.Name, "durationMs", record.DurationMs)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.running, job.Name)
+	s.loadLocked()
+	s.runs[job.Name] = record
+	if saveErr := s.saveLocked(); saveErr != nil {
+		slog.Warn("[MAINTENANCE] failed to save bookkeeping", "error", saveErr)
+	}
+	return err
+}
+
+// runJob runs job.Run, turning a panic into an error so one broken job does
+// not stop the scheduler.
+func runJob(ctx context.Context, job Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("maintenance job %q panicked: %v", job.Name, r)
+		}
+	}()
+	return job.Run(ctx)
+}
+
+// loadLocked reads the bookkeeping file once.
+//
+// REQUIRES: s.mu must be held by the caller.
+func (s *Service) loadLocked() {
+	if s.loaded {
+		return
+	}
+	s.loaded = true
+	path, err := s.statePath()
+	if err != nil || path == "" {
+		if err != nil {
+			slog.Warn("[MAINTENANCE] bookkeeping unavailable", "error", err)
+		}
+		return
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			slog.Warn("[MAINTENANCE] failed to read bookkeeping", "path", path, "error", err)
+		}
+		return
+	}
+	var runs map[string]lastRun
+	if err := json.Unmarshal(data, &runs); err != nil {
+		slog.Warn("[MAINTENANCE] ignoring unreadable bookkeeping", "path", path, "error", err)
+		return
+	}
+	for name, run := range runs {
+		if _, ok := s.runs[name]; !ok {
+			s.runs[name] = run
+		}
+	}
+}
+
+// saveLocked writes the bookkeeping file atomically.
+//
+// REQUIRES: s.mu must be held by the caller.
+func (s *Service) saveLocked() error {
+	path, err := s.statePath()
+	if err != nil || path == "" {
+		return err
+	}
+	data, err := json.MarshalIndent(s.runs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal bookkeeping: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create bookkeeping directory: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("write bookkeeping: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("replace bookkeeping: %w", err)
+	}
+	return nil
+}
+
+func (s *Service) statePath() (string, error) {
+	if s.deps.StatePath == nil {
+		return "", nil
+	}
+	return s.deps.StatePath()
+}
@@ -0,0 +1,196 @@
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestService(t *testing.T, idle *bool, now *time.Time, statePath string) *Service {
+	t.Helper()
+	return NewService(Deps{
+		IsIdle:    func() bool { return *idle },
+		StatePath: func() (string, error) { return statePath, nil },
+		Now:       func() time.Time { return *now },
+	})
+}
+
+func countingJob(name string, interval time.Duration, runs *int) Job {
+	return Job{Name: name, Interval: interval, Run: func(context.Context) error {
+		*runs++
+		return nil
+	}}
+}
+
+func TestNewServicePanicsWithoutIsIdle(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("NewService() did not panic without IsIdle")
+		}
+	}()
+	NewService(Deps{})
+}
+
+func TestRegisterValidation(t *testing.T) {
+	svc := NewService(Deps{IsIdle: func() bool { return true }})
+	noop := func(context.Context) error { return nil }
+	tests := []struct {
+		name string
+		job  Job
+	}{
+		{name: "empty name", job: Job{Name: " ", Interval: time.Hour, Run: noop}},
+		{name: "nil run", job: Job{Name: "a", Interval: time.Hour}},
+		{name: "zero interval", job: Job{Name: "a", Run: noop}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := svc.Register(tt.job); err == nil {
+				t.Fatal("Register() expected error")
+			}
+		})
+	}
+	if err := svc.Register(Job{Name: "a", Interval: time.Hour, Run: noop}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if err := svc.Register(Job{Name: "a", Interval: time.Hour, Run: noop}); err == nil {
+		t.Fatal("Register() expected duplicate error")
+	}
+}
+
+func TestRunDueOnlyWhenIdleAndDue(t *testing.T) {
+	idle := false
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	svc := newTestService(t, &idle, &now, filepath.Join(t.TempDir(), StateFileName))
+	var hourly, daily int
+	if err := svc.Register(countingJob("hourly", time.Hour, &hourly)); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.Register(countingJob("daily", 24*time.Hour, &daily)); err != nil {
+		t.Fatal(err)
+	}
+
+	if ran := svc.RunDue(context.Background()); ran != 0 || hourly != 0 {
+		t.Fatalf("RunDue() while busy ran %d jobs", ran)
+	}
+	idle = true
+	if ran := svc.RunDue(context.Background()); ran != 2 || hourly != 1 || daily != 1 {
+		t.Fatalf("first idle RunDue() = %d (hourly=%d daily=%d), want both", ran, hourly, daily)
+	}
+	now = now.Add(2 * time.Hour)
+	if ran := svc.RunDue(context.Background()); ran != 1 || hourly != 2 || daily != 1 {
+		t.Fatalf("RunDue() after 2h = %d (hourly=%d daily=%d), want hourly only", ran, hourly, daily)
+	}
+}
+
+func TestRunDueStopsWhenNoLongerIdle(t *testing.T) {
+	idle := true
+	now := time.Now()
+	svc := newTestService(t, &idle, &now, "")
+	var ran []string
+	for _, name := range []string{"a", "b"} {
+		if err := svc.Register(Job{Name: name, Interval: time.Hour, Run: func(context.Context) error {
+			ran = append(ran, name)
+			idle = false
+			return nil
+		}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	svc.RunDue(context.Background())
+	if len(ran) != 1 || ran[0] != "a" {
+		t.Fatalf("ran = %v, want only a", ran)
+	}
+}
+
+func TestTriggerIgnoresIdlenessAndRecordsStatus(t *testing.T) {
+	idle := false
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	statePath := filepath.Join(t.TempDir(), StateFileName)
+	svc := newTestService(t, &idle, &now, statePath)
+	if err := svc.Register(Job{Name: "backup", Description: "d", Interval: time.Hour, Run: func(context.Context) error {
+		return errors.New("disk full")
+	}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := svc.Trigger(context.Background(), "missing"); !errors.Is(err, ErrJobNotFound) {
+		t.Fatalf("Trigger(missing) error = %v, want ErrJobNotFound", err)
+	}
+	if err := svc.Trigger(context.Background(), "backup"); err == nil || err.Error() != "disk full" {
+		t.Fatalf("Trigger() error = %v, want job error", err)
+	}
+
+	statuses := svc.Status()
+	if len(statuses) != 1 {
+		t.Fatalf("Status() = %+v", statuses)
+	}
+	got := statuses[0]
+	if got.LastRun != "2026-01-01T12:00:00Z" || got.LastError != "disk full" ||
+		got.NextDue != "2026-01-01T13:00:00Z" || got.IntervalSec != 3600 || got.Running {
+		t.Fatalf("Status()[0] = %+v", got)
+	}
+
+	// A new service picks up the persisted bookkeeping.
+	reloaded := newTestService(t, &idle, &now, statePath)
+	var runs int
+	if err := reloaded.Register(countingJob("backup", time.Hour, &runs)); err != nil {
+		t.Fatal(err)
+	}
+	idle = true
+	if ran := reloaded.RunDue(context.Background()); ran != 0 {
+		t.Fatalf("RunDue() after reload ran %d jobs, want 0 (not due yet)", ran)
+	}
+	if status := reloaded.Status()[0]; status.LastError != "disk full" {
+		t.Fatalf("reloaded status = %+v, want persisted error", status)
+	}
+}
+
+func TestTriggerRecoversFromPanic(t *testing.T) {
+	idle := true
+	now := time.Now()
+	svc := newTestService(t, &idle, &now, "")
+	if err := svc.Register(Job{Name: "boom", Interval: time.Hour, Run: func(context.Context) error {
+		panic("bad")
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	err := svc.Trigger(context.Background(), "boom")
+	if err == nil || !strings.Contains(err.Error(), "panicked") {
+		t.Fatalf("Trigger() error = %v, want panic error", err)
+	}
+	if status := svc.Status()[0]; status.Running || status.LastError == "" {
+		t.Fatalf("status after panic = %+v", status)
+	}
+}
+
+func TestTriggerRejectsConcurrentRun(t *testing.T) {
+	idle := true
+	now := time.Now()
+	svc := newTestService(t, &idle, &now, "")
+	started := make(chan struct{})
+	release := make(chan struct{})
+	if err := svc.Register(Job{Name: "slow", Interval: time.Hour, Run: func(context.Context) error {
+		close(started)
+		<-release
+		return nil
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- svc.Trigger(context.Background(), "slow") }()
+	<-started
+
+	if !svc.Status()[0].Running {
+		t.Fatal("Status() does not report the running job")
+	}
+	if err := svc.Trigger(context.Background(), "slow"); !errors.Is(err, ErrJobRunning) {
+		t.Fatalf("second Trigger() error = %v, want ErrJobRunning", err)
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("first Trigger() error = %v", err)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -31,6 +32,10 @@ type Config struct {
 	LSPArgs []string
 	// RootDir は LSP のワークスペースルートとして扱うディレクトリ。
 	RootDir string
+	// Env は LSP プロセスの環境変数。nil の場合は親プロセスの環境を継承する。
+	Env []string
+	// Restricted は LSP プロセスを権限を落としたサンドボックスで起動するかどうか。
+	Restricted bool
 
 	// LanguageID は拡張子から判定できない場合に使う languageId の既定値。
 	LanguageID string
@@ -93,6 +98,8 @@ func NewRuntime(cfg Config) (*Runtime, error) {
 		RequestTimeout:        normalized.RequestTimeout,
 		OpenDelay:             normalized.OpenDelay,
 		Logger:                normalized.Logger,
+		Env:                   slices.Clone(normalized.Env),
+		Restricted:            normalized.Restricted,
 	})
 
 	registry := tools.BuildRegistry(client, normalized.RootDir, normalized.LSPCommand, normalized.LSPArgs)
@@ -39,6 +39,10 @@ type Config struct {
 	OpenDelay time.Duration
 	// Logger はクライアント内部ログの出力先。
 	Logger *log.Logger
+	// Env は LSP プロセスの環境変数。nil の場合は親プロセスの環境を継承する。
+	Env []string
+	// Restricted は LSP プロセスを権限を落としたサンドボックスで起動するかどうか。
+	Restricted bool
 }
 
 type responseResult struct {
@@ -63,10 +67,11 @@ var (
 type Client struct {
 	cfg Config
 
-	cmd    *exec.Cmd
-	stdin  io.WriteCloser
-	stdout io.ReadCloser
-	stderr io.ReadCloser
+	cmd     *exec.Cmd
+	sandbox *processSandbox
+	stdin   io.WriteCloser
+	stdout  io.ReadCloser
+	stderr  io.ReadCloser
 
 	writerMu sync.Mutex
 
@@ -130,6 +135,15 @@ func (c *Client) Start(ctx context.Context) error {
 
 	cmd := buildLSPExecCommand(ctx, c.cfg.Command, c.cfg.Args)
 	cmd.Dir = c.cfg.RootDir
+	cmd.Env = c.cfg.Env
+
+	var sandbox *processSandbox
+	if c.cfg.Restricted {
+		sandbox, err = newProcessSandbox(cmd)
+		if err != nil {
+			return fmt.Errorf("prepare restricted lsp process: %w", err)
+		}
+	}
 
 	stdin, err := cmd.StdinPipe()
 	if err != nil {
@@ -145,10 +159,25 @@ func (c *Client) Start(ctx context.Context) error {
 	}
 
 	if err := cmd.Start(); err != nil {
+		if sandbox != nil {
+			sandbox.release()
+		}
 		return fmt.Errorf("start lsp process: %w", err)
 	}
+	if sandbox != nil {
+		if err := sandbox.attach(cmd); err != nil {
+			// Releasing the sandbox does not stop a process outside the job.
+			if killErr := cmd.Process.Kill(); killErr != nil {
+				c.logf("failed to kill unconfined lsp process: %v", killErr)
+			}
+			_ = cmd.Wait()
+			sandbox.release()
+			return fmt.Errorf("confine lsp process: %w", err)
+		}
+	}
 
 	c.cmd = cmd
+	c.sandbox = sandbox
 	c.stdin = stdin
 	c.stdout = stdout
 	c.stderr = stderr
@@ -307,10 +336,16 @@ func (c *Client) closeInternal(ctx context.Context) error {
 		}
 	}
 	c.closeMu.Lock()
+	sandbox := c.sandbox
 	c.cmd = nil
+	c.sandbox = nil
 	c.stdout = nil
 	c.stderr = nil
 	c.closeMu.Unlock()
+	if sandbox != nil {
+		// サンドボックス内に残った子プロセスもジョブごと終了させる。
+		sandbox.release()
+	}
 
 	select {
 	case <-c.readLoopDone:
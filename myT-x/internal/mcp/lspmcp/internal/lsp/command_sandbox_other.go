@@ -0,0 +1,17 @@
+//go:build !windows
+
+package lsp
+
+import "os/exec"
+
+// processSandbox is a no-op outside Windows; only environment filtering
+// applies there.
+type processSandbox struct{}
+
+func newProcessSandbox(*exec.Cmd) (*processSandbox, error) {
+	return &processSandbox{}, nil
+}
+
+func (*processSandbox) attach(*exec.Cmd) error { return nil }
+
+func (*processSandbox) release() {}
@@ -0,0 +1,139 @@
+//go:build windows
+
+package lsp
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+const (
+	// CreateRestrictedToken flags.
+	disableMaxPrivilege = 0x1
+	luaToken            = 0x4
+
+	sandboxUIRestrictions = windows.JOB_OBJECT_UILIMIT_DESKTOP |
+		windows.JOB_OBJECT_UILIMIT_DISPLAYSETTINGS |
+		windows.JOB_OBJECT_UILIMIT_EXITWINDOWS |
+		windows.JOB_OBJECT_UILIMIT_GLOBALATOMS |
+		windows.JOB_OBJECT_UILIMIT_HANDLES |
+		windows.JOB_OBJECT_UILIMIT_READCLIPBOARD |
+		windows.JOB_OBJECT_UILIMIT_SYSTEMPARAMETERS |
+		windows.JOB_OBJECT_UILIMIT_WRITECLIPBOARD
+)
+
+var (
+	advapi32                  = windows.NewLazySystemDLL("advapi32.dll")
+	procCreateRestrictedToken = advapi32.NewProc("CreateRestrictedToken")
+
+	ntdll               = windows.NewLazySystemDLL("ntdll.dll")
+	procNtResumeProcess = ntdll.NewProc("NtResumeProcess")
+)
+
+// processSandbox confines a restricted LSP process. The process runs with a
+// token stripped of every privilege and of administrator rights, inside a job
+// object that forbids UI access and kills the whole process tree when the
+// sandbox is released.
+type processSandbox struct {
+	token windows.Token
+	job   windows.Handle
+}
+
+// newProcessSandbox makes cmd start suspended with a restricted token. attach
+// must be called after cmd.Start to confine and resume the process, and
+// release once the process is gone.
+func newProcessSandbox(cmd *exec.Cmd) (*processSandbox, error) {
+	var current windows.Token
+	access := uint32(windows.TOKEN_DUPLICATE | windows.TOKEN_ASSIGN_PRIMARY | windows.TOKEN_QUERY)
+	if err := windows.OpenProcessToken(windows.CurrentProcess(), access, &current); err != nil {
+		return nil, fmt.Errorf("open process token: %w", err)
+	}
+	defer current.Close()
+
+	var restricted windows.Token
+	r1, _, callErr := procCreateRestrictedToken.Call(
+		uintptr(current),
+		disableMaxPrivilege|luaToken,
+		0, 0, 0, 0, 0, 0,
+		uintptr(unsafe.Pointer(&restricted)),
+	)
+	if r1 == 0 {
+		return nil, fmt.Errorf("create restricted token: %w", callErr)
+	}
+
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Token = syscall.Token(restricted)
+	// The process must not run, and so cannot spawn unconfined children,
+	// before attach assigns it to the job.
+	cmd.SysProcAttr.CreationFlags |= windows.CREATE_SUSPENDED
+	return &processSandbox{token: restricted}, nil
+}
+
+// attach places the started, still suspended process in a new job object and
+// then resumes it. On error the process stays suspended and must be killed.
+func (s *processSandbox) attach(cmd *exec.Cmd) error {
+	// The token is only needed to create the process.
+	s.closeToken()
+	if cmd.Process == nil {
+		return errors.New("process is not started")
+	}
+
+	job, err := windows.CreateJobObject(nil, nil)
+	if err != nil {
+		return fmt.Errorf("create job object: %w", err)
+	}
+	s.job = job
+
+	limits := windows.JOBOBJECT_EXTENDED_LIMIT_INFORMATION{}
+	limits.BasicLimitInformation.LimitFlags = windows.JOB_OBJECT_LIMIT_KILL_ON_JOB_CLOSE |
+		windows.JOB_OBJECT_LIMIT_DIE_ON_UNHANDLED_EXCEPTION
+	if _, err := windows.SetInformationJobObject(job, windows.JobObjectExtendedLimitInformation,
+		uintptr(unsafe.Pointer(&limits)), uint32(unsafe.Sizeof(limits))); err != nil {
+		return fmt.Errorf("set job limits: %w", err)
+	}
+	ui := windows.JOBOBJECT_BASIC_UI_RESTRICTIONS{UIRestrictionsClass: sandboxUIRestrictions}
+	if _, err := windows.SetInformationJobObject(job, windows.JobObjectBasicUIRestrictions,
+		uintptr(unsafe.Pointer(&ui)), uint32(unsafe.Sizeof(ui))); err != nil {
+		return fmt.Errorf("set job ui restrictions: %w", err)
+	}
+
+	access := uint32(windows.PROCESS_SET_QUOTA | windows.PROCESS_TERMINATE | windows.PROCESS_SUSPEND_RESUME)
+	process, err := windows.OpenProcess(access, false, uint32(cmd.Process.Pid))
+	if err != nil {
+		return fmt.Errorf("open lsp process: %w", err)
+	}
+	defer windows.CloseHandle(process)
+	if err := windows.AssignProcessToJobObject(job, process); err != nil {
+		return fmt.Errorf("assign lsp process to job: %w", err)
+	}
+	// os/exec closes the primary thread handle, so the process is resumed
+	// as a whole.
+	if status, _, _ := procNtResumeProcess.Call(uintptr(process)); status != 0 {
+		return fmt.Errorf("resume lsp process: NTSTATUS 0x%08x", status)
+	}
+	return nil
+}
+
+// release closes the token and the job. Closing the job terminates any
+// process still running in it.
+func (s *processSandbox) release() {
+	s.closeToken()
+	if s.job != 0 {
+		_ = windows.CloseHandle(s.job)
+		s.job = 0
+	}
+}
+
+func (s *processSandbox) closeToken() {
+	if s.token != 0 {
+		_ = s.token.Close()
+		s.token = 0
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log/slog"
 	"maps"
+	"slices"
 	"strings"
 	"sync"
 
@@ -193,6 +194,7 @@ func (m *Manager) SnapshotForSession(sessionName string) ([]MCPSnapshot, error)
 			snap.Enabled = inst.state.Enabled
 			snap.Status = inst.state.Status
 			snap.Error = inst.state.Error
+			snap.Violations = slices.Clone(inst.state.Violations)
 			if inst.pipe != nil {
 				snap.PipePath = inst.pipe.PipeName()
 			}
@@ -353,6 +355,13 @@ func (m *Manager) startInstanceSync(sessionName, mcpID string, inst *instance, d
 		return nil
 	}
 
+	if !def.Kind.UsesEmbeddedRuntime() {
+		if violations := CheckPermissions(def, rootDir); len(violations) > 0 {
+			m.rejectViolations(sessionName, mcpID, inst, gen, violations, emitState)
+			return fmt.Errorf("permissions manifest violated: %s", violations[0])
+		}
+	}
+
 	pipeName := BuildMCPPipeName(sessionName, mcpID)
 	pipeCfg, err := buildPipeConfig(pipeName, def, pipeConfigContext{
 		rootDir:                 rootDir,
@@ -414,6 +423,7 @@ func (m *Manager) startInstanceSync(sessionName, mcpID string, inst *instance, d
 	}
 	inst.state.Status = StatusRunning
 	inst.state.Error = ""
+	inst.state.Violations = nil
 	inst.pipe = pipe
 	inst.cancel = func() error { return pipe.Stop() }
 	inst.mu.Unlock()
@@ -464,6 +474,7 @@ func (m *Manager) GetDetail(sessionName, mcpID string) (MCPSnapshot, error) {
 		snap.Enabled = inst.state.Enabled
 		snap.Status = inst.state.Status
 		snap.Error = inst.state.Error
+		snap.Violations = slices.Clone(inst.state.Violations)
 		if inst.pipe != nil {
 			snap.PipePath = inst.pipe.PipeName()
 		}
@@ -786,6 +797,40 @@ func (m *Manager) close(emitLifecycleEvent bool) {
 	m.emitMu.Unlock()
 }
 
+// rejectViolations logs a launch blocked by the permissions manifest, records
+// the violations on the instance and notifies the frontend.
+func (m *Manager) rejectViolations(sessionName, mcpID string, inst *instance, gen uint64, violations []PermissionViolation, emitState bool) {
+	for _, violation := range violations {
+		slog.Warn("[WARN-MCP] permissions manifest violated, launch refused",
+			"session", sessionName, "mcp", mcpID, "kind", violation.Kind, "detail", violation.Detail)
+	}
+	inst.mu.Lock()
+	if inst.generation != gen {
+		inst.mu.Unlock()
+		return
+	}
+	inst.state.Status = StatusError
+	inst.state.Error = fmt.Sprintf("permissions manifest violated: %s", violations[0])
+	inst.state.Violations = slices.Clone(violations)
+	inst.mu.Unlock()
+
+	m.emitMu.Lock()
+	m.mu.RLock()
+	closed := m.closed
+	m.mu.RUnlock()
+	if !closed {
+		m.emitFn("mcp:permission-violation", map[string]any{
+			"session_name": sessionName,
+			"mcp_id":       mcpID,
+			"violations":   slices.Clone(violations),
+		})
+	}
+	m.emitMu.Unlock()
+	if emitState {
+		m.emitStateChanged(sessionName, mcpID)
+	}
+}
+
 func (m *Manager) emitStateChanged(sessionName, mcpID string) {
 	m.emitMu.Lock()
 	defer m.emitMu.Unlock()
@@ -813,6 +858,7 @@ func defaultSnapshot(def Definition) Snapshot {
 		UsageSample:  def.UsageSample,
 		ConfigParams: cloneConfigParams(def.ConfigParams),
 		Kind:         def.Kind,
+		Restricted:   def.Permissions != nil && !def.Kind.UsesEmbeddedRuntime(),
 	}
 }
 
@@ -836,6 +882,7 @@ func stopInstance(inst *instance, invalidateGeneration bool) func() error {
 	inst.state.Enabled = false
 	inst.state.Status = StatusStopped
 	inst.state.Error = ""
+	inst.state.Violations = nil
 	inst.mu.Unlock()
 	return cancelFn
 }
@@ -342,6 +342,73 @@ func TestManager_SetEnabled(t *testing.T) {
 	}
 }
 
+func TestManager_SetEnabled_PermissionViolationBlocksLaunch(t *testing.T) {
+	reg := NewRegistry()
+	workDir := t.TempDir()
+	def := MCPDefinition{
+		ID:      "fetch",
+		Name:    "Fetch",
+		Command: "test-command",
+		Args:    []string{"--endpoint=https://evil.example.net/api"},
+		Permissions: &Permissions{
+			FilesystemPaths: []string{"."},
+			NetworkHosts:    []string{"api.example.com"},
+		},
+	}
+	if err := reg.Register(def); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	pipeCreated := make(chan struct{}, 1)
+	ec := &eventCollector{}
+	mgr := NewManager(ManagerConfig{
+		Registry:       reg,
+		EmitFn:         ec.emit,
+		ResolveWorkDir: func(string) (string, error) { return workDir, nil },
+		NewPipeServer: func(cfg MCPPipeConfig) managedPipeServer {
+			pipeCreated <- struct{}{}
+			return &fakeManagedPipeServer{pipeName: cfg.PipeName, startEntered: make(chan struct{})}
+		},
+	})
+
+	if err := mgr.SetEnabled("session-1", "fetch", true); err != nil {
+		t.Fatalf("SetEnabled() error = %v", err)
+	}
+
+	var detail MCPSnapshot
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		var err error
+		detail, err = mgr.GetDetail("session-1", "fetch")
+		if err == nil && detail.Status == StatusError {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if detail.Status != StatusError {
+		t.Fatalf("Status = %q, want %q", detail.Status, StatusError)
+	}
+	if !detail.Restricted {
+		t.Fatal("Restricted = false, want true")
+	}
+	if len(detail.Violations) != 1 || detail.Violations[0].Kind != PermissionKindNetwork {
+		t.Fatalf("Violations = %+v, want one network violation", detail.Violations)
+	}
+	mustNotReceiveWithin(t, pipeCreated, 50*time.Millisecond, "pipe server was created despite the violation")
+
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+	for _, event := range ec.events {
+		if event.Name == "mcp:permission-violation" {
+			if got := payloadMap(t, event.Payload)["mcp_id"]; got != "fetch" {
+				t.Fatalf("violation event mcp_id = %v, want fetch", got)
+			}
+			return
+		}
+	}
+	t.Fatalf("events = %+v, want mcp:permission-violation", ec.events)
+}
+
 func TestManager_SetEnabled_NoOpForExistingSameValue(t *testing.T) {
 	mgr, ec := newTestManager(t, MCPDefinition{ID: "memory", Name: "Memory"})
 	if err := mgr.SetEnabled("session-1", "memory", true); err != nil {
@@ -1434,10 +1501,10 @@ func TestStructFieldCounts(t *testing.T) {
 		got  int
 		want int
 	}{
-		{"MCPDefinition", reflect.TypeFor[MCPDefinition]().NumField(), 10},
+		{"MCPDefinition", reflect.TypeFor[MCPDefinition]().NumField(), 11},
 		{"MCPConfigParam", reflect.TypeFor[MCPConfigParam]().NumField(), 4},
-		{"MCPInstanceState", reflect.TypeFor[MCPInstanceState]().NumField(), 5},
-		{"MCPSnapshot", reflect.TypeFor[MCPSnapshot]().NumField(), 12},
+		{"MCPInstanceState", reflect.TypeFor[MCPInstanceState]().NumField(), 6},
+		{"MCPSnapshot", reflect.TypeFor[MCPSnapshot]().NumField(), 14},
 		{"instance", reflect.TypeFor[instance]().NumField(), 5},
 	}
 	for _, tt := range tests {
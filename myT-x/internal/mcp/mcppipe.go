@@ -8,13 +8,10 @@ import (
 	"log/slog"
 	"net"
 	"os/user"
-	"regexp"
 	"strings"
 	"sync"
 	"time"
 
-	"github.com/Microsoft/go-winio"
-
 	"myT-x/internal/mcp/lspmcp"
 	"myT-x/internal/mcp/pipebridge"
 )
@@ -39,6 +36,11 @@ type MCPPipeConfig struct {
 	LSPArgs []string
 	// RootDir is the workspace root directory for LSP initialization.
 	RootDir string
+	// LSPEnv is the environment of the LSP process. Nil inherits the
+	// environment of myT-x.
+	LSPEnv []string
+	// Restricted runs the LSP process in a privilege-stripped sandbox.
+	Restricted bool
 	// RuntimeFactory, when set, is used instead of the default lspmcp.NewRuntime
 	// path. This allows non-LSP runtimes (e.g. agent-orchestrator) to share the
 	// same pipe server infrastructure.
@@ -214,6 +216,8 @@ func (s *MCPPipeServer) handleConnection(conn net.Conn) {
 			LSPCommand: s.cfg.LSPCommand,
 			LSPArgs:    append([]string(nil), s.cfg.LSPArgs...),
 			RootDir:    s.cfg.RootDir,
+			Env:        s.cfg.LSPEnv,
+			Restricted: s.cfg.Restricted,
 			In:         connReader,
 			Out:        conn,
 		})
@@ -315,21 +319,6 @@ func BuildMCPPipeName(sessionName, mcpID string) string {
 		sanitize(username), sanitize(sessionName), sanitize(mcpID))
 }
 
-// listenMCPPipe creates a Named Pipe listener restricted to the current user.
-// Same DACL security as ipc.PipeServer.
-func listenMCPPipe(pipeName string) (net.Listener, error) {
-	sd, err := mcpPipeSecurityDescriptor()
-	if err != nil {
-		return nil, err
-	}
-	return winio.ListenPipe(pipeName, &winio.PipeConfig{
-		SecurityDescriptor: sd,
-		MessageMode:        false,
-		InputBufferSize:    int32(mcpPipeInputBufferSize),
-		OutputBufferSize:   int32(mcpPipeOutputBufferSize),
-	})
-}
-
 func readConnectionMetadata(conn net.Conn) (*bufio.Reader, string, error) {
 	reader, callerPaneID, err := pipebridge.ReadCallerPaneHandshake(conn)
 	if err != nil {
@@ -337,20 +326,3 @@ func readConnectionMetadata(conn net.Conn) (*bufio.Reader, string, error) {
 	}
 	return reader, callerPaneID, nil
 }
-
-var validMCPPipeSIDPattern = regexp.MustCompile(`^S-1(-\d+)+$`)
-
-func mcpPipeSecurityDescriptor() (string, error) {
-	current, err := user.Current()
-	if err != nil {
-		return "", fmt.Errorf("resolve current user: %w", err)
-	}
-	sid := strings.TrimSpace(current.Uid)
-	if sid == "" {
-		return "", errors.New("current user SID is unavailable")
-	}
-	if !validMCPPipeSIDPattern.MatchString(sid) {
-		return "", fmt.Errorf("current user SID has unexpected format: %s", sid)
-	}
-	return fmt.Sprintf("D:P(A;;GA;;;SY)(A;;GA;;;%s)", sid), nil
-}
@@ -0,0 +1,14 @@
+//go:build !windows
+
+package mcp
+
+import (
+	"errors"
+	"net"
+)
+
+// listenMCPPipe fails on platforms without Named Pipes; MCP pipe servers are
+// Windows-only, so their sessions run without a pipe bridge there.
+func listenMCPPipe(string) (net.Listener, error) {
+	return nil, errors.New("mcp pipes are only supported on Windows")
+}
@@ -0,0 +1,17 @@
+//go:build !windows
+
+package mcp
+
+import "testing"
+
+func TestMCPPipeServerStartUnsupported(t *testing.T) {
+	srv := NewMCPPipeServer(MCPPipeConfig{
+		PipeName:   `\\.\pipe\test-mcp-unsupported`,
+		LSPCommand: "gopls",
+		RootDir:    t.TempDir(),
+	})
+	if err := srv.Start(); err == nil {
+		t.Fatal("Start() error = nil, want unsupported platform error")
+	}
+	srv.Stop()
+}
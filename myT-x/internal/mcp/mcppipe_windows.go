@@ -0,0 +1,46 @@
+//go:build windows
+
+package mcp
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os/user"
+	"regexp"
+	"strings"
+
+	"github.com/Microsoft/go-winio"
+)
+
+// listenMCPPipe creates a Named Pipe listener restricted to the current user.
+// Same DACL security as ipc.PipeServer.
+func listenMCPPipe(pipeName string) (net.Listener, error) {
+	sd, err := mcpPipeSecurityDescriptor()
+	if err != nil {
+		return nil, err
+	}
+	return winio.ListenPipe(pipeName, &winio.PipeConfig{
+		SecurityDescriptor: sd,
+		MessageMode:        false,
+		InputBufferSize:    int32(mcpPipeInputBufferSize),
+		OutputBufferSize:   int32(mcpPipeOutputBufferSize),
+	})
+}
+
+var validMCPPipeSIDPattern = regexp.MustCompile(`^S-1(-\d+)+$`)
+
+func mcpPipeSecurityDescriptor() (string, error) {
+	current, err := user.Current()
+	if err != nil {
+		return "", fmt.Errorf("resolve current user: %w", err)
+	}
+	sid := strings.TrimSpace(current.Uid)
+	if sid == "" {
+		return "", errors.New("current user SID is unavailable")
+	}
+	if !validMCPPipeSIDPattern.MatchString(sid) {
+		return "", fmt.Errorf("current user SID has unexpected format: %s", sid)
+	}
+	return fmt.Sprintf("D:P(A;;GA;;;SY)(A;;GA;;;%s)", sid), nil
+}
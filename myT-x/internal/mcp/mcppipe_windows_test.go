@@ -0,0 +1,48 @@
+//go:build windows
+
+package mcp
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMCPPipeSecurityDescriptor(t *testing.T) {
+	sd, err := mcpPipeSecurityDescriptor()
+	if err != nil {
+		t.Fatalf("mcpPipeSecurityDescriptor() error = %v", err)
+	}
+	// Should contain DACL markers.
+	if !strings.HasPrefix(sd, "D:P(") {
+		t.Errorf("security descriptor should start with D:P(, got %q", sd)
+	}
+	// Should include SYSTEM ACE.
+	if !strings.Contains(sd, "SY") {
+		t.Errorf("security descriptor should include SYSTEM (SY), got %q", sd)
+	}
+}
+
+func TestMCPPipeServer_StartStop(t *testing.T) {
+	pipeName := fmt.Sprintf(`\\.\pipe\test-mcp-start-stop-%d`, time.Now().UnixNano())
+	srv := NewMCPPipeServer(MCPPipeConfig{
+		PipeName:   pipeName,
+		LSPCommand: "gopls",
+		RootDir:    t.TempDir(),
+	})
+
+	if err := srv.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	// Double start should return error.
+	if err := srv.Start(); err == nil {
+		t.Error("double Start() should return error")
+	}
+
+	srv.Stop()
+
+	// Stop is idempotent.
+	srv.Stop()
+}
@@ -112,6 +112,8 @@ func buildPipeConfig(pipeName string, def Definition, ctx pipeConfigContext) (MC
 			LSPCommand: def.Command,
 			LSPArgs:    append([]string(nil), def.Args...),
 			RootDir:    ctx.rootDir,
+			LSPEnv:     restrictedEnv(def),
+			Restricted: def.Permissions != nil,
 		}, nil
 	}
 }
@@ -2,6 +2,7 @@ package mcp
 
 import (
 	"context"
+	"slices"
 	"strings"
 	"testing"
 
@@ -109,6 +110,35 @@ func TestBuildPipeConfig_CustomKindUsesExternalCommand(t *testing.T) {
 	if len(cfg.LSPArgs) != 2 || cfg.LSPArgs[0] != "-y" || cfg.LSPArgs[1] != "@anthropic/memory-server" {
 		t.Fatalf("LSPArgs = %#v, want [-y @anthropic/memory-server]", cfg.LSPArgs)
 	}
+	if cfg.LSPEnv != nil || cfg.Restricted {
+		t.Fatalf("LSPEnv = %#v, Restricted = %v, want inherited unrestricted process", cfg.LSPEnv, cfg.Restricted)
+	}
+}
+
+func TestBuildPipeConfig_PermissionsRestrictProcess(t *testing.T) {
+	t.Setenv("MYTX_TEST_SECRET", "hidden")
+	t.Setenv("MYTX_TEST_VISIBLE", "shown")
+	def := Definition{
+		ID:          "memory",
+		Name:        "Memory Server",
+		Kind:        DefinitionKindCustom,
+		Command:     "npx",
+		DefaultEnv:  map[string]string{"MEM_DIR": "data"},
+		Permissions: &Permissions{EnvKeys: []string{"MYTX_TEST_VISIBLE"}},
+	}
+	cfg, err := buildPipeConfig(`\\.\pipe\test`, def, pipeConfigContext{rootDir: "/root"})
+	if err != nil {
+		t.Fatalf("buildPipeConfig: %v", err)
+	}
+	if !cfg.Restricted {
+		t.Fatal("Restricted = false, want true for a definition with permissions")
+	}
+	if !slices.Contains(cfg.LSPEnv, "MYTX_TEST_VISIBLE=shown") || !slices.Contains(cfg.LSPEnv, "MEM_DIR=data") {
+		t.Fatalf("LSPEnv = %#v, want declared and default variables", cfg.LSPEnv)
+	}
+	if slices.Contains(cfg.LSPEnv, "MYTX_TEST_SECRET=hidden") {
+		t.Fatalf("LSPEnv = %#v, leaked an undeclared variable", cfg.LSPEnv)
+	}
 }
 
 func TestBuildPipeConfig_OrchestratorAllPanesTrue(t *testing.T) {
@@ -0,0 +1,193 @@
+package mcp
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Permission violation kinds.
+const (
+	PermissionKindFilesystem = "filesystem"
+	PermissionKindNetwork    = "network"
+)
+
+// baseEnvKeys are always visible to a restricted server. Windows processes
+// fail in surprising ways without them, and none of them carries secrets.
+var baseEnvKeys = []string{
+	"PATH", "PATHEXT", "SystemRoot", "SystemDrive", "windir", "ComSpec", "TEMP", "TMP",
+}
+
+// PermissionViolation is one launch-time breach of a permissions manifest.
+type PermissionViolation struct {
+	Kind   string `json:"kind"`
+	Detail string `json:"detail"`
+}
+
+// String returns "kind: detail".
+func (v PermissionViolation) String() string {
+	return v.Kind + ": " + v.Detail
+}
+
+func clonePermissions(perms *Permissions) *Permissions {
+	if perms == nil {
+		return nil
+	}
+	return &Permissions{
+		FilesystemPaths: slices.Clone(perms.FilesystemPaths),
+		NetworkHosts:    slices.Clone(perms.NetworkHosts),
+		EnvKeys:         slices.Clone(perms.EnvKeys),
+	}
+}
+
+// CheckPermissions lints def's manifest for a server launched in rootDir and
+// returns the violations. The work directory and every absolute path in the
+// arguments must lie within FilesystemPaths, and every URL in the arguments
+// or default environment must name a host in NetworkHosts. It only inspects
+// the launch parameters; it does not confine what the process does later.
+// A definition without a manifest never violates anything.
+func CheckPermissions(def Definition, rootDir string) []PermissionViolation {
+	perms := def.Permissions
+	if perms == nil {
+		return nil
+	}
+	allowedDirs := make([]string, 0, len(perms.FilesystemPaths))
+	for _, path := range perms.FilesystemPaths {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(rootDir, path)
+		}
+		allowedDirs = append(allowedDirs, filepath.Clean(path))
+	}
+
+	var violations []PermissionViolation
+	checkPath := func(path, source string) {
+		for _, dir := range allowedDirs {
+			if pathWithinDir(path, dir) {
+				return
+			}
+		}
+		violations = append(violations, PermissionViolation{
+			Kind:   PermissionKindFilesystem,
+			Detail: fmt.Sprintf("%s %q is outside the allowed paths", source, path),
+		})
+	}
+	checkURL := func(value, source string) {
+		parsed, err := url.Parse(value)
+		if err != nil || parsed.Hostname() == "" {
+			return
+		}
+		host := strings.ToLower(parsed.Hostname())
+		for _, allowed := range perms.NetworkHosts {
+			if hostMatches(host, allowed) {
+				return
+			}
+		}
+		violations = append(violations, PermissionViolation{
+			Kind:   PermissionKindNetwork,
+			Detail: fmt.Sprintf("%s refers to host %q, which is not allowed", source, host),
+		})
+	}
+
+	checkPath(filepath.Clean(rootDir), "work directory")
+	for _, arg := range def.Args {
+		value := arg
+		// "--flag=value" carries its value after the first '='.
+		if strings.HasPrefix(arg, "-") {
+			_, after, found := strings.Cut(arg, "=")
+			if !found {
+				continue
+			}
+			value = after
+		}
+		switch {
+		case strings.Contains(value, "://"):
+			checkURL(value, "argument")
+		case filepath.IsAbs(value):
+			checkPath(filepath.Clean(value), "argument")
+		}
+	}
+	envKeys := make([]string, 0, len(def.DefaultEnv))
+	for key := range def.DefaultEnv {
+		envKeys = append(envKeys, key)
+	}
+	slices.Sort(envKeys)
+	for _, key := range envKeys {
+		if value := def.DefaultEnv[key]; strings.Contains(value, "://") {
+			checkURL(value, "env "+key)
+		}
+	}
+	return violations
+}
+
+// FilterEnv returns the environment of a server launched under perms: the
+// entries of environ whose key is in baseEnvKeys or perms.EnvKeys, followed by
+// defaults. Keys compare case-insensitively, as on Windows. A nil perms
+// returns nil, which makes exec inherit the full environment.
+func FilterEnv(environ []string, perms *Permissions, defaults map[string]string) []string {
+	if perms == nil {
+		return nil
+	}
+	defaultKeys := make([]string, 0, len(defaults))
+	for key := range defaults {
+		defaultKeys = append(defaultKeys, key)
+	}
+	slices.Sort(defaultKeys)
+	visible := func(key string) bool {
+		match := func(allowed string) bool { return strings.EqualFold(allowed, key) }
+		if slices.ContainsFunc(defaultKeys, match) {
+			// Overridden by defaults below.
+			return false
+		}
+		return slices.ContainsFunc(baseEnvKeys, match) || slices.ContainsFunc(perms.EnvKeys, match)
+	}
+
+	env := make([]string, 0, len(perms.EnvKeys)+len(baseEnvKeys)+len(defaults))
+	for _, entry := range environ {
+		key, _, found := strings.Cut(entry, "=")
+		// Windows keeps per-drive working directories in "=C:" style entries.
+		if !found || key == "" {
+			continue
+		}
+		if visible(key) {
+			env = append(env, entry)
+		}
+	}
+	for _, key := range defaultKeys {
+		env = append(env, key+"="+defaults[key])
+	}
+	return env
+}
+
+// restrictedEnv is FilterEnv over the current process environment.
+func restrictedEnv(def Definition) []string {
+	return FilterEnv(os.Environ(), def.Permissions, def.DefaultEnv)
+}
+
+// hostMatches reports whether host matches the allowed pattern. "*.example.com"
+// matches subdomains of example.com but not example.com itself.
+func hostMatches(host, pattern string) bool {
+	pattern = strings.ToLower(pattern)
+	if suffix, ok := strings.CutPrefix(pattern, "*"); ok && strings.HasPrefix(suffix, ".") {
+		return strings.HasSuffix(host, suffix) && len(host) > len(suffix)
+	}
+	return host == pattern
+}
+
+// pathWithinDir reports whether path is dir or below it. It rejects Windows
+// cross-drive paths because filepath.Rel returns an error for them.
+func pathWithinDir(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	if rel == "." {
+		return true
+	}
+	if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return false
+	}
+	return !filepath.IsAbs(rel)
+}
@@ -0,0 +1,113 @@
+package mcp
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCheckPermissions(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+
+	tests := []struct {
+		name      string
+		def       Definition
+		wantKinds []string
+	}{
+		{
+			name: "no manifest is unrestricted",
+			def:  Definition{Args: []string{outside, "https://anywhere.example"}},
+		},
+		{
+			name: "workspace and declared paths allowed",
+			def: Definition{
+				Args:        []string{"-y", filepath.Join(root, "data"), "--out=" + filepath.Join(outside, "log")},
+				Permissions: &Permissions{FilesystemPaths: []string{".", outside}},
+			},
+		},
+		{
+			name: "work directory outside manifest",
+			def: Definition{
+				Permissions: &Permissions{FilesystemPaths: []string{outside}},
+			},
+			wantKinds: []string{PermissionKindFilesystem},
+		},
+		{
+			name: "path argument outside manifest",
+			def: Definition{
+				Args:        []string{"--db=" + filepath.Join(outside, "db.sqlite")},
+				Permissions: &Permissions{FilesystemPaths: []string{"."}},
+			},
+			wantKinds: []string{PermissionKindFilesystem},
+		},
+		{
+			name: "hosts in args and env checked",
+			def: Definition{
+				Args:       []string{"https://api.example.com/v1", "--mirror=https://cdn.example.org"},
+				DefaultEnv: map[string]string{"PROXY": "http://proxy.internal:8080", "MODE": "strict"},
+				Permissions: &Permissions{
+					FilesystemPaths: []string{"."},
+					NetworkHosts:    []string{"api.example.com", "*.example.org"},
+				},
+			},
+			wantKinds: []string{PermissionKindNetwork},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations := CheckPermissions(tt.def, root)
+			var kinds []string
+			for _, violation := range violations {
+				kinds = append(kinds, violation.Kind)
+			}
+			if !reflect.DeepEqual(kinds, tt.wantKinds) {
+				t.Fatalf("violation kinds = %v (%v), want %v", kinds, violations, tt.wantKinds)
+			}
+		})
+	}
+}
+
+func TestFilterEnv(t *testing.T) {
+	environ := []string{
+		"PATH=/bin",
+		"Home=/home/user",
+		"SECRET_TOKEN=abc",
+		"MEM_DIR=/inherited",
+		"=C:=C:\\work",
+	}
+
+	if got := FilterEnv(environ, nil, map[string]string{"MEM_DIR": "x"}); got != nil {
+		t.Fatalf("FilterEnv(nil perms) = %#v, want nil", got)
+	}
+
+	got := FilterEnv(environ, &Permissions{EnvKeys: []string{"HOME"}}, map[string]string{"MEM_DIR": "/declared"})
+	want := []string{"PATH=/bin", "Home=/home/user", "MEM_DIR=/declared"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("FilterEnv() = %#v, want %#v", got, want)
+	}
+
+	if got := FilterEnv(nil, &Permissions{}, nil); got == nil || len(got) != 0 {
+		t.Fatalf("FilterEnv(empty) = %#v, want empty non-nil slice", got)
+	}
+}
+
+func TestHostMatches(t *testing.T) {
+	tests := []struct {
+		host    string
+		pattern string
+		want    bool
+	}{
+		{"api.example.com", "api.example.com", true},
+		{"api.example.com", "API.Example.com", true},
+		{"api.example.com", "*.example.com", true},
+		{"example.com", "*.example.com", false},
+		{"badexample.com", "*.example.com", false},
+		{"api.example.com", "example.com", false},
+	}
+	for _, tt := range tests {
+		if got := hostMatches(tt.host, tt.pattern); got != tt.want {
+			t.Errorf("hostMatches(%q, %q) = %v, want %v", tt.host, tt.pattern, got, tt.want)
+		}
+	}
+}
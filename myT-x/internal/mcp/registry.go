@@ -113,6 +113,7 @@ func cloneDefinition(def Definition) Definition {
 		maps.Copy(cloned.DefaultEnv, def.DefaultEnv)
 	}
 	cloned.ConfigParams = cloneConfigParams(def.ConfigParams)
+	cloned.Permissions = clonePermissions(def.Permissions)
 	return cloned
 }
 
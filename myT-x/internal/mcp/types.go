@@ -16,6 +16,25 @@ type Definition struct {
 	ConfigParams   []ConfigParam     `json:"config_params,omitempty" yaml:"config_params,omitempty"`
 	// Kind distinguishes MCP server types for startInstance branching.
 	Kind DefinitionKind `json:"kind,omitempty" yaml:"kind,omitempty"`
+	// Permissions is the launch manifest of a command-backed server.
+	// Nil leaves the process unrestricted. Ignored by embedded runtimes.
+	Permissions *Permissions `json:"permissions,omitempty" yaml:"permissions,omitempty"`
+}
+
+// Permissions declares what a command-backed MCP server is launched with.
+// Only EnvKeys is enforced at run time (see FilterEnv). FilesystemPaths and
+// NetworkHosts are linted once at launch by CheckPermissions; nothing stops
+// the running process from opening other paths or connecting to other hosts.
+type Permissions struct {
+	// FilesystemPaths are the directories the server may be launched in or
+	// pointed at. Relative entries are resolved against the session work
+	// directory.
+	FilesystemPaths []string `json:"filesystem_paths,omitempty" yaml:"filesystem_paths,omitempty"`
+	// NetworkHosts are the hosts the server may be pointed at. A leading
+	// "*." matches any subdomain.
+	NetworkHosts []string `json:"network_hosts,omitempty" yaml:"network_hosts,omitempty"`
+	// EnvKeys are the inherited environment variables the server may see.
+	EnvKeys []string `json:"env_keys,omitempty" yaml:"env_keys,omitempty"`
 }
 
 // DefinitionKind identifies the type of MCP server.
@@ -88,6 +107,8 @@ type InstanceState struct {
 	Status    Status `json:"status"`
 	// Error is meaningful only when Status == StatusError.
 	Error string `json:"error,omitempty"`
+	// Violations lists the permission violations that blocked the launch.
+	Violations []PermissionViolation `json:"violations,omitempty"`
 }
 
 // Snapshot is the frontend-safe representation that combines the static
@@ -114,6 +135,10 @@ type Snapshot struct {
 	// Kind distinguishes MCP server types for frontend category rendering.
 	// See DefinitionKind constants for possible values.
 	Kind DefinitionKind `json:"kind,omitempty"`
+	// Restricted reports whether the server runs under a permissions manifest.
+	Restricted bool `json:"restricted,omitempty"`
+	// Violations lists the manifest violations that blocked the last launch.
+	Violations []PermissionViolation `json:"violations,omitempty"`
 }
 
 // Backward-compatible aliases.
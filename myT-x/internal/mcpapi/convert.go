@@ -4,6 +4,7 @@ import (
 	"log/slog"
 	"maps"
 	"os"
+	"slices"
 	"strings"
 
 	"myT-x/internal/config"
@@ -57,6 +58,7 @@ func MCPServerConfigsToDefinitions(configs []config.MCPServerConfig) []mcp.Defin
 			DefaultEnabled: c.Enabled,
 			UsageSample:    c.UsageSample,
 			ConfigParams:   cloneMCPConfigParams(c.ConfigParams),
+			Permissions:    mcpConfigPermissions(c.Permissions),
 		}
 		defs = append(defs, def)
 	}
@@ -116,3 +118,14 @@ func cloneMCPConfigParams(src []config.MCPServerConfigParam) []mcp.ConfigParam {
 	}
 	return dst
 }
+
+func mcpConfigPermissions(src *config.MCPServerPermissions) *mcp.Permissions {
+	if src == nil {
+		return nil
+	}
+	return &mcp.Permissions{
+		FilesystemPaths: slices.Clone(src.FilesystemPaths),
+		NetworkHosts:    slices.Clone(src.NetworkHosts),
+		EnvKeys:         slices.Clone(src.EnvKeys),
+	}
+}
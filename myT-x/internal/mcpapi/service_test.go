@@ -1186,6 +1186,11 @@ func TestMCPServerConfigsToDefinitions(t *testing.T) {
 					Description:  "Execution mode",
 				},
 			},
+			Permissions: &config.MCPServerPermissions{
+				FilesystemPaths: []string{"."},
+				NetworkHosts:    []string{"api.example.com"},
+				EnvKeys:         []string{"HOME"},
+			},
 		},
 	}
 
@@ -1212,6 +1217,11 @@ func TestMCPServerConfigsToDefinitions(t *testing.T) {
 				Description:  "Execution mode",
 			},
 		},
+		Permissions: &mcp.Permissions{
+			FilesystemPaths: []string{"."},
+			NetworkHosts:    []string{"api.example.com"},
+			EnvKeys:         []string{"HOME"},
+		},
 	}
 	if !reflect.DeepEqual(defs[0], want) {
 		t.Fatalf("MCPServerConfigsToDefinitions() mismatch\ngot:  %#v\nwant: %#v", defs[0], want)
@@ -1229,6 +1239,10 @@ func TestMCPServerConfigsToDefinitions(t *testing.T) {
 	if defs[0].ConfigParams[0].Label == "Changed" {
 		t.Fatal("definition config params were aliased to config config_params")
 	}
+	configs[0].Permissions.EnvKeys[0] = "CHANGED"
+	if defs[0].Permissions.EnvKeys[0] == "CHANGED" {
+		t.Fatal("definition permissions were aliased to config permissions")
+	}
 }
 
 func TestMCPServerConfigsToDefinitions_NormalizesSingleTaskRunnerKind(t *testing.T) {
@@ -0,0 +1,227 @@
+// Package metrics counts host activity (IPC requests and their latency,
+// sessions and panes, terminal I/O, and worktree operations) and serves it
+// in the Prometheus text exposition format on a localhost HTTP endpoint.
+//
+// The format is written by hand so the app does not depend on the
+// Prometheus client library.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Result labels of counters.
+const (
+	ResultOK    = "ok"
+	ResultError = "error"
+)
+
+// otherLabel replaces label values beyond maxLabelValues, so that garbage
+// commands cannot grow the registry without bound.
+const (
+	otherLabel     = "other"
+	maxLabelValues = 128
+)
+
+// latencyBuckets are the upper bounds in seconds of the IPC latency
+// histogram. Most commands finish in milliseconds; run-shell and
+// control-mode clients run for as long as the client stays attached.
+var latencyBuckets = []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 5, 30}
+
+// Deps provides the sampled values of a Registry.
+type Deps struct {
+	// Counts returns the number of sessions and panes. Optional: the gauges
+	// are left out when nil.
+	Counts func() (sessions, panes int)
+	// TerminalIO returns the bytes read from and written to all terminals.
+	// Optional: the counters are left out when nil.
+	TerminalIO func() (read, written uint64)
+}
+
+// Registry holds the metrics of the host.
+//
+// Thread-safety: all methods are safe for concurrent use.
+type Registry struct {
+	deps Deps
+
+	mu          sync.Mutex
+	ipcRequests map[resultKey]uint64
+	ipcLatency  map[string]*histogram
+	worktreeOps map[resultKey]uint64
+}
+
+type resultKey struct {
+	name   string
+	result string
+}
+
+type histogram struct {
+	counts []uint64 // per bucket of latencyBuckets, not cumulative
+	sum    float64
+	count  uint64
+}
+
+// NewRegistry creates a Registry.
+func NewRegistry(deps Deps) *Registry {
+	return &Registry{
+		deps:        deps,
+		ipcRequests: map[resultKey]uint64{},
+		ipcLatency:  map[string]*histogram{},
+		worktreeOps: map[resultKey]uint64{},
+	}
+}
+
+// ObserveIPC records one IPC request of command that exited with exitCode
+// after elapsed.
+func (r *Registry) ObserveIPC(command string, exitCode int, elapsed time.Duration) {
+	result := ResultOK
+	if exitCode != 0 {
+		result = ResultError
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	command = r.ipcLabelLocked(command)
+	r.ipcRequests[resultKey{command, result}]++
+	h := r.ipcLatency[command]
+	if h == nil {
+		h = &histogram{counts: make([]uint64, len(latencyBuckets))}
+		r.ipcLatency[command] = h
+	}
+	seconds := elapsed.Seconds()
+	if i := sort.SearchFloat64s(latencyBuckets, seconds); i < len(latencyBuckets) {
+		h.counts[i]++
+	}
+	h.sum += seconds
+	h.count++
+}
+
+// ObserveWorktree records one worktree operation; a non-nil err counts as
+// a failure.
+func (r *Registry) ObserveWorktree(operation string, err error) {
+	result := ResultOK
+	if err != nil {
+		result = ResultError
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.worktreeOps[resultKey{labelOrUnknown(operation), result}]++
+}
+
+func (r *Registry) ipcLabelLocked(command string) string {
+	command = labelOrUnknown(command)
+	if _, ok := r.ipcLatency[command]; ok || len(r.ipcLatency) < maxLabelValues {
+		return command
+	}
+	return otherLabel
+}
+
+func labelOrUnknown(value string) string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "unknown"
+	}
+	return value
+}
+
+// WriteText writes every metric in the Prometheus text exposition format.
+func (r *Registry) WriteText(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	r.mu.Lock()
+	ipcRequests := maps.Clone(r.ipcRequests)
+	worktreeOps := maps.Clone(r.worktreeOps)
+	latency := make(map[string]histogram, len(r.ipcLatency))
+	for command, h := range r.ipcLatency {
+		latency[command] = histogram{counts: slices.Clone(h.counts), sum: h.sum, count: h.count}
+	}
+	r.mu.Unlock()
+
+	writeHeader(bw, "mytx_ipc_requests_total", "counter", "IPC requests handled, by tmux command and result.")
+	writeResultCounts(bw, "mytx_ipc_requests_total", "command", ipcRequests)
+
+	writeHeader(bw, "mytx_ipc_request_duration_seconds", "histogram", "IPC request latency, by tmux command.")
+	for _, command := range sortedKeys(latency) {
+		h := latency[command]
+		var cumulative uint64
+		for i, bound := range latencyBuckets {
+			cumulative += h.counts[i]
+			fmt.Fprintf(bw, "mytx_ipc_request_duration_seconds_bucket{command=%s,le=%s} %d\n",
+				quote(command), quote(formatFloat(bound)), cumulative)
+		}
+		fmt.Fprintf(bw, "mytx_ipc_request_duration_seconds_bucket{command=%s,le=\"+Inf\"} %d\n", quote(command), h.count)
+		fmt.Fprintf(bw, "mytx_ipc_request_duration_seconds_sum{command=%s} %s\n", quote(command), formatFloat(h.sum))
+		fmt.Fprintf(bw, "mytx_ipc_request_duration_seconds_count{command=%s} %d\n", quote(command), h.count)
+	}
+
+	writeHeader(bw, "mytx_worktree_operations_total", "counter", "Worktree operations, by operation and result.")
+	writeResultCounts(bw, "mytx_worktree_operations_total", "operation", worktreeOps)
+
+	if r.deps.Counts != nil {
+		sessions, panes := r.deps.Counts()
+		writeHeader(bw, "mytx_sessions", "gauge", "Active sessions.")
+		fmt.Fprintf(bw, "mytx_sessions %d\n", sessions)
+		writeHeader(bw, "mytx_panes", "gauge", "Active panes.")
+		fmt.Fprintf(bw, "mytx_panes %d\n", panes)
+	}
+	if r.deps.TerminalIO != nil {
+		read, written := r.deps.TerminalIO()
+		writeHeader(bw, "mytx_pty_read_bytes_total", "counter", "Bytes read from pane terminals.")
+		fmt.Fprintf(bw, "mytx_pty_read_bytes_total %d\n", read)
+		writeHeader(bw, "mytx_pty_written_bytes_total", "counter", "Bytes written to pane terminals.")
+		fmt.Fprintf(bw, "mytx_pty_written_bytes_total %d\n", written)
+	}
+	return bw.Flush()
+}
+
+func writeHeader(w io.Writer, name, kind, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func writeResultCounts(w io.Writer, name, label string, counts map[resultKey]uint64) {
+	keys := make([]resultKey, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].name != keys[j].name {
+			return keys[i].name < keys[j].name
+		}
+		return keys[i].result < keys[j].result
+	})
+	for _, key := range keys {
+		fmt.Fprintf(w, "%s{%s=%s,result=%s} %d\n", name, label, quote(key.name), quote(key.result), counts[key])
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// labelEscaper escapes a label value: backslash, double quote, and line feed.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func quote(value string) string {
+	return `"` + labelEscaper.Replace(value) + `"`
+}
+
+func formatFloat(value float64) string {
+	if math.IsInf(value, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
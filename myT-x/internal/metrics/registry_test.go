@@ -0,0 +1,91 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRegistryWriteText(t *testing.T) {
+	registry := NewRegistry(Deps{
+		Counts:     func() (int, int) { return 2, 5 },
+		TerminalIO: func() (uint64, uint64) { return 1024, 64 },
+	})
+	registry.ObserveIPC("send-keys", 0, 2*time.Millisecond)
+	registry.ObserveIPC("send-keys", 1, 200*time.Millisecond)
+	registry.ObserveIPC("run-shell", 0, time.Minute)
+	registry.ObserveWorktree("create", nil)
+	registry.ObserveWorktree("create", errors.New("boom"))
+
+	var out strings.Builder
+	if err := registry.WriteText(&out); err != nil {
+		t.Fatalf("WriteText() error = %v", err)
+	}
+	text := out.String()
+	for _, want := range []string{
+		"# TYPE mytx_ipc_requests_total counter\n",
+		`mytx_ipc_requests_total{command="send-keys",result="ok"} 1`,
+		`mytx_ipc_requests_total{command="send-keys",result="error"} 1`,
+		`mytx_ipc_request_duration_seconds_bucket{command="send-keys",le="0.001"} 0`,
+		`mytx_ipc_request_duration_seconds_bucket{command="send-keys",le="0.005"} 1`,
+		`mytx_ipc_request_duration_seconds_bucket{command="send-keys",le="0.25"} 2`,
+		`mytx_ipc_request_duration_seconds_bucket{command="run-shell",le="30"} 0`,
+		`mytx_ipc_request_duration_seconds_bucket{command="run-shell",le="+Inf"} 1`,
+		`mytx_ipc_request_duration_seconds_count{command="send-keys"} 2`,
+		`mytx_ipc_request_duration_seconds_sum{command="run-shell"} 60`,
+		`mytx_worktree_operations_total{operation="create",result="error"} 1`,
+		`mytx_worktree_operations_total{operation="create",result="ok"} 1`,
+		"mytx_sessions 2\n",
+		"mytx_panes 5\n",
+		"mytx_pty_read_bytes_total 1024\n",
+		"mytx_pty_written_bytes_total 64\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("WriteText() missing %q in:\n%s", want, text)
+		}
+	}
+}
+
+func TestRegistryOmitsUnsampledMetrics(t *testing.T) {
+	var out strings.Builder
+	if err := NewRegistry(Deps{}).WriteText(&out); err != nil {
+		t.Fatalf("WriteText() error = %v", err)
+	}
+	if strings.Contains(out.String(), "mytx_sessions") || strings.Contains(out.String(), "mytx_pty_") {
+		t.Fatalf("WriteText() = %s, want no sampled metrics without deps", out.String())
+	}
+}
+
+func TestRegistryCapsCommandLabels(t *testing.T) {
+	registry := NewRegistry(Deps{})
+	for i := range maxLabelValues + 10 {
+		registry.ObserveIPC(fmt.Sprintf("cmd-%d", i), 0, time.Millisecond)
+	}
+	registry.ObserveIPC("cmd-0", 0, time.Millisecond)
+	registry.ObserveIPC(" ", 0, time.Millisecond)
+
+	var out strings.Builder
+	if err := registry.WriteText(&out); err != nil {
+		t.Fatalf("WriteText() error = %v", err)
+	}
+	text := out.String()
+	for _, want := range []string{
+		`mytx_ipc_requests_total{command="cmd-0",result="ok"} 2`,
+		`mytx_ipc_requests_total{command="other",result="ok"} 11`,
+	} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("WriteText() missing %q", want)
+		}
+	}
+	if strings.Contains(text, fmt.Sprintf(`"cmd-%d"`, maxLabelValues+5)) {
+		t.Fatal("commands beyond the label cap should be reported as other")
+	}
+}
+
+func TestQuoteEscapesLabelValues(t *testing.T) {
+	if got := quote("a\"b\\c\nd"); got != `"a\"b\\c\nd"` {
+		t.Fatalf("quote() = %s", got)
+	}
+}
@@ -0,0 +1,119 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Path is the scrape path of the endpoint.
+const Path = "/metrics"
+
+// contentType is the Prometheus text exposition format, version 0.0.4.
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// shutdownTimeout bounds how long Stop waits for in-flight scrapes.
+const shutdownTimeout = 5 * time.Second
+
+// Server serves a Registry on 127.0.0.1. It can be started and stopped
+// repeatedly, e.g. when the config enables or disables it.
+//
+// Thread-safety: all methods are safe for concurrent use.
+type Server struct {
+	registry *Registry
+
+	mu     sync.Mutex
+	server *http.Server
+	url    string
+}
+
+// NewServer creates a stopped Server for registry.
+// Panics if registry is nil.
+func NewServer(registry *Registry) *Server {
+	if registry == nil {
+		panic("metrics.NewServer: registry must be non-nil")
+	}
+	return &Server{registry: registry}
+}
+
+// Start listens on 127.0.0.1:port (0 picks a free port) and serves the
+// registry at Path. A running server is restarted when port differs and
+// left alone otherwise.
+func (s *Server) Start(port int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.server != nil {
+		if port != 0 && s.url == endpointURL(port) {
+			return nil
+		}
+		s.stopLocked()
+	}
+
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		return fmt.Errorf("metrics: listen: %w", err)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(Path, s.handleMetrics)
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	s.server = server
+	s.url = endpointURL(listener.Addr().(*net.TCPAddr).Port)
+
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("[ERROR-METRICS] server error", "error", err)
+		}
+	}()
+	slog.Info("[METRICS] endpoint listening", "url", s.url)
+	return nil
+}
+
+// Stop shuts the server down. Stopping a stopped server is a no-op.
+func (s *Server) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopLocked()
+}
+
+func (s *Server) stopLocked() {
+	if s.server == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := s.server.Shutdown(ctx); err != nil {
+		slog.Warn("[WARN-METRICS] shutdown failed", "error", err)
+	}
+	slog.Info("[METRICS] endpoint stopped", "url", s.url)
+	s.server = nil
+	s.url = ""
+}
+
+// URL returns the scrape URL (e.g. "http://127.0.0.1:9464/metrics"), or ""
+// when the server is stopped.
+func (s *Server) URL() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.url
+}
+
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	if err := s.registry.WriteText(w); err != nil {
+		slog.Debug("[DEBUG-METRICS] scrape write failed", "error", err)
+	}
+}
+
+func endpointURL(port int) string {
+	return fmt.Sprintf("http://127.0.0.1:%d%s", port, Path)
+}
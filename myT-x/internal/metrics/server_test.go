@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServerServesRegistry(t *testing.T) {
+	registry := NewRegistry(Deps{})
+	registry.ObserveIPC("list-panes", 0, time.Millisecond)
+	server := NewServer(registry)
+	if err := server.Start(0); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	t.Cleanup(server.Stop)
+
+	url := server.URL()
+	if !strings.HasPrefix(url, "http://127.0.0.1:") || !strings.HasSuffix(url, Path) {
+		t.Fatalf("URL() = %q", url)
+	}
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("GET %s error = %v", url, err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != contentType {
+		t.Fatalf("GET status = %d, content type = %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	if !strings.Contains(string(body), `command="list-panes"`) {
+		t.Fatalf("body = %s", body)
+	}
+
+	resp, err = http.Post(url, "text/plain", nil)
+	if err != nil {
+		t.Fatalf("POST error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Fatalf("POST status = %d, want 405", resp.StatusCode)
+	}
+
+	server.Stop()
+	if server.URL() != "" {
+		t.Fatalf("URL() after Stop = %q", server.URL())
+	}
+	if _, err := http.Get(url); err == nil {
+		t.Fatal("GET after Stop should fail")
+	}
+	server.Stop()
+}
+
+func TestNewServerPanicsOnNilRegistry(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("NewServer(nil) should panic")
+		}
+	}()
+	NewServer(nil)
+}
@@ -0,0 +1,400 @@
+package netcapture
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// maxBodyBytes bounds each captured request and response body.
+	maxBodyBytes = 16 << 10
+	// dialTimeout bounds connecting to the target of a request or tunnel.
+	dialTimeout = 10 * time.Second
+	// readHeaderTimeout bounds reading a request header from a pane.
+	readHeaderTimeout = 30 * time.Second
+	// proxyUser is the user name of the proxy credential; only the password
+	// is secret.
+	proxyUser = "myt-x"
+)
+
+// hopHeaders are the hop-by-hop headers a proxy must not forward.
+var hopHeaders = []string{
+	"Connection",
+	"Keep-Alive",
+	"Proxy-Authenticate",
+	"Proxy-Authorization",
+	"Proxy-Connection",
+	"Te",
+	"Trailer",
+	"Transfer-Encoding",
+	"Upgrade",
+}
+
+// proxy is the capture proxy of one session. It only serves clients that
+// present its credential, which is handed to the session's panes through
+// ProxyEnv, so other local processes and other sessions cannot use it.
+type proxy struct {
+	svc  *Service
+	path string
+	// url is the proxy address shown to the user, without the credential.
+	url string
+	// envURL is url with the credential, for the panes' proxy variables.
+	envURL string
+	// authorization is the Proxy-Authorization value clients must send.
+	authorization string
+	listener      net.Listener
+	server        *http.Server
+	transport     *http.Transport
+	captureBodies atomic.Bool
+	captured      atomic.Int64
+
+	// writeMu serializes appends to the capture log.
+	writeMu sync.Mutex
+	// tunnelWG tracks open tunnels so close returns once they are recorded.
+	tunnelWG sync.WaitGroup
+
+	mu          sync.Mutex
+	sessionName string
+	// tunnels holds the connections of open CONNECT tunnels, closed with
+	// the proxy.
+	tunnels map[net.Conn]struct{}
+	closed  bool
+}
+
+func newProxy(svc *Service, sessionName, path string, listener net.Listener, captureBodies bool) *proxy {
+	password := rand.Text()
+	p := &proxy{
+		svc:           svc,
+		path:          path,
+		url:           "http://" + listener.Addr().String(),
+		envURL:        "http://" + proxyUser + ":" + password + "@" + listener.Addr().String(),
+		authorization: "Basic " + base64.StdEncoding.EncodeToString([]byte(proxyUser+":"+password)),
+		listener:      listener,
+		sessionName:   sessionName,
+		tunnels:       map[net.Conn]struct{}{},
+		transport: &http.Transport{
+			// Connect directly: the panes' own proxy variables point here.
+			Proxy:       nil,
+			DialContext: (&net.Dialer{Timeout: dialTimeout}).DialContext,
+			// Pass Accept-Encoding through as the client sent it.
+			DisableCompression: true,
+			MaxIdleConns:       16,
+			IdleConnTimeout:    90 * time.Second,
+		},
+	}
+	p.server = &http.Server{Handler: p, ReadHeaderTimeout: readHeaderTimeout}
+	p.captureBodies.Store(captureBodies)
+	return p
+}
+
+func (p *proxy) serve() {
+	if err := p.server.Serve(p.listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		slog.Warn("[WARN-NET-CAPTURE] capture proxy stopped", "session", p.currentSessionName(), "error", err)
+	}
+}
+
+func (p *proxy) close() {
+	p.mu.Lock()
+	p.closed = true
+	tunnels := p.tunnels
+	p.tunnels = map[net.Conn]struct{}{}
+	p.mu.Unlock()
+
+	_ = p.server.Close()
+	for conn := range tunnels {
+		_ = conn.Close()
+	}
+	p.tunnelWG.Wait()
+	p.transport.CloseIdleConnections()
+}
+
+func (p *proxy) status() Status {
+	return Status{
+		SessionName:   p.currentSessionName(),
+		Enabled:       true,
+		ProxyURL:      p.url,
+		CaptureBodies: p.captureBodies.Load(),
+		Captured:      p.captured.Load(),
+	}
+}
+
+func (p *proxy) currentSessionName() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.sessionName
+}
+
+func (p *proxy) setSessionName(name string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sessionName = name
+}
+
+// ServeHTTP forwards absolute-URL HTTP requests and tunnels CONNECT requests
+// of clients that present the proxy credential.
+func (p *proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !p.authorized(r) {
+		// Rejected requests are not recorded: anyone on the machine can
+		// reach the port, and they must not be able to fill the log.
+		slog.Debug("[DEBUG-NET-CAPTURE] rejected request without the proxy credential", "session", p.currentSessionName(), "method", r.Method)
+		w.Header().Set("Proxy-Authenticate", `Basic realm="myT-x network capture"`)
+		http.Error(w, "myT-x network capture proxy: proxy credential required", http.StatusProxyAuthRequired)
+		return
+	}
+	if r.Method == http.MethodConnect {
+		p.tunnel(w, r)
+		return
+	}
+	if !r.URL.IsAbs() || r.URL.Scheme != "http" {
+		http.Error(w, "myT-x network capture proxy: only absolute http:// URLs and CONNECT are supported", http.StatusBadRequest)
+		return
+	}
+	p.forward(w, r)
+}
+
+func (p *proxy) authorized(r *http.Request) bool {
+	got := r.Header.Get("Proxy-Authorization")
+	return subtle.ConstantTimeCompare([]byte(got), []byte(p.authorization)) == 1
+}
+
+func (p *proxy) forward(w http.ResponseWriter, r *http.Request) {
+	start := p.svc.deps.Now()
+	record := p.newRecord(start, r.Method, "http", r.URL.Host)
+	record.Path = r.URL.Path
+	captureBodies := p.captureBodies.Load()
+
+	out := r.Clone(r.Context())
+	out.RequestURI = ""
+	removeHopHeaders(out.Header)
+	requestBody := newCountingReader(r.Body, captureBodies)
+	if r.Body != nil && r.Body != http.NoBody {
+		out.Body = struct {
+			io.Reader
+			io.Closer
+		}{requestBody, r.Body}
+	}
+
+	resp, err := p.transport.RoundTrip(out)
+	if err != nil {
+		record.Status = http.StatusBadGateway
+		record.Error = err.Error()
+		http.Error(w, "myT-x network capture proxy: "+err.Error(), http.StatusBadGateway)
+		p.finish(record, start, requestBody, nil)
+		return
+	}
+	defer resp.Body.Close()
+
+	removeHopHeaders(resp.Header)
+	for key, values := range resp.Header {
+		for _, value := range values {
+			w.Header().Add(key, value)
+		}
+	}
+	w.WriteHeader(resp.StatusCode)
+	record.Status = resp.StatusCode
+	record.ContentType = resp.Header.Get("Content-Type")
+
+	responseBody := newCountingReader(resp.Body, captureBodies)
+	// Flush every chunk so streamed responses (server-sent events) reach
+	// the client as they arrive.
+	if _, err := io.Copy(flushWriter{w: w, rc: http.NewResponseController(w)}, responseBody); err != nil {
+		record.Error = err.Error()
+	}
+	p.finish(record, start, requestBody, responseBody)
+}
+
+// tunnel connects a CONNECT request to its target and relays bytes both ways
+// until either side closes.
+func (p *proxy) tunnel(w http.ResponseWriter, r *http.Request) {
+	start := p.svc.deps.Now()
+	record := p.newRecord(start, r.Method, "https", r.Host)
+
+	upstream, err := net.DialTimeout("tcp", r.Host, dialTimeout)
+	if err != nil {
+		record.Status = http.StatusBadGateway
+		record.Error = err.Error()
+		http.Error(w, "myT-x network capture proxy: "+err.Error(), http.StatusBadGateway)
+		p.finish(record, start, nil, nil)
+		return
+	}
+	client, buffered, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		_ = upstream.Close()
+		record.Status = http.StatusInternalServerError
+		record.Error = err.Error()
+		http.Error(w, "myT-x network capture proxy: "+err.Error(), http.StatusInternalServerError)
+		p.finish(record, start, nil, nil)
+		return
+	}
+	if !p.trackTunnel(client, upstream) {
+		_ = client.Close()
+		_ = upstream.Close()
+		return
+	}
+	defer p.untrackTunnel(client, upstream)
+
+	record.Status = http.StatusOK
+	if _, err := io.WriteString(client, "HTTP/1.1 200 Connection Established\r\n\r\n"); err != nil {
+		record.Error = err.Error()
+		_ = client.Close()
+		_ = upstream.Close()
+		p.finish(record, start, nil, nil)
+		return
+	}
+
+	sent := newCountingReader(buffered.Reader, false)
+	received := newCountingReader(upstream, false)
+	done := make(chan struct{}, 2)
+	go func() {
+		_, _ = io.Copy(upstream, sent)
+		done <- struct{}{}
+	}()
+	go func() {
+		_, _ = io.Copy(client, received)
+		done <- struct{}{}
+	}()
+	<-done
+	_ = client.Close()
+	_ = upstream.Close()
+	<-done
+	p.finish(record, start, sent, received)
+}
+
+func (p *proxy) trackTunnel(conns ...net.Conn) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return false
+	}
+	for _, conn := range conns {
+		p.tunnels[conn] = struct{}{}
+	}
+	p.tunnelWG.Add(1)
+	return true
+}
+
+func (p *proxy) untrackTunnel(conns ...net.Conn) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, conn := range conns {
+		delete(p.tunnels, conn)
+	}
+	p.tunnelWG.Done()
+}
+
+func (p *proxy) newRecord(start time.Time, method, scheme, host string) Record {
+	return Record{
+		ID:        strconv.FormatInt(start.UnixNano(), 36) + "-" + strconv.FormatUint(p.svc.seq.Add(1), 10),
+		Session:   p.currentSessionName(),
+		StartedAt: start.UTC().Format(time.RFC3339Nano),
+		Method:    method,
+		Scheme:    scheme,
+		Host:      host,
+	}
+}
+
+// finish completes record with the counts of the request and response
+// bodies and appends it to the capture log. Failures are logged only:
+// capturing must never break the agent's request.
+func (p *proxy) finish(record Record, start time.Time, request, response *countingReader) {
+	record.DurationMs = p.svc.deps.Now().Sub(start).Milliseconds()
+	if request != nil {
+		var truncated bool
+		record.RequestBytes, record.RequestBody, truncated = request.result()
+		record.BodyTruncated = truncated
+	}
+	if response != nil {
+		var truncated bool
+		record.ResponseBytes, record.ResponseBody, truncated = response.result()
+		record.BodyTruncated = record.BodyTruncated || truncated
+	}
+	p.captured.Add(1)
+
+	p.writeMu.Lock()
+	defer p.writeMu.Unlock()
+	if err := appendLog(p.path, record); err != nil {
+		slog.Warn("[WARN-NET-CAPTURE] failed to append capture record", "session", record.Session, "host", record.Host, "error", err)
+	}
+}
+
+func removeHopHeaders(header http.Header) {
+	for _, key := range hopHeaders {
+		header.Del(key)
+	}
+}
+
+// countingReader counts the bytes read through it and keeps the first
+// maxBodyBytes when capture is set. The transport may still read a request
+// body after RoundTrip returns, so the counters are guarded by mu.
+type countingReader struct {
+	r io.Reader
+
+	mu        sync.Mutex
+	n         int64
+	capture   *bytes.Buffer
+	truncated bool
+}
+
+func newCountingReader(r io.Reader, capture bool) *countingReader {
+	c := &countingReader{r: r}
+	if capture {
+		c.capture = &bytes.Buffer{}
+	}
+	return c
+}
+
+func (c *countingReader) Read(b []byte) (int, error) {
+	if c.r == nil {
+		return 0, io.EOF
+	}
+	n, err := c.r.Read(b)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.n += int64(n)
+	if c.capture != nil && n > 0 {
+		keep := min(n, maxBodyBytes-c.capture.Len())
+		c.capture.Write(b[:keep])
+		if keep < n {
+			c.truncated = true
+		}
+	}
+	return n, err
+}
+
+// result returns the bytes read so far and the captured body.
+func (c *countingReader) result() (n int64, body string, truncated bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.capture != nil {
+		body = c.capture.String()
+	}
+	return c.n, body, c.truncated
+}
+
+// flushWriter flushes the response after every write.
+type flushWriter struct {
+	w  io.Writer
+	rc *http.ResponseController
+}
+
+func (f flushWriter) Write(b []byte) (int, error) {
+	n, err := f.w.Write(b)
+	if err == nil {
+		if flushErr := f.rc.Flush(); flushErr != nil && !errors.Is(flushErr, http.ErrNotSupported) {
+			return n, fmt.Errorf("flush response: %w", flushErr)
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,347 @@
+// Package netcapture runs an opt-in recording HTTP proxy per session so users
+// can see which external calls an agent actually made. Panes of a session
+// whose capture is on get HTTP_PROXY/HTTPS_PROXY pointing at the session's
+// proxy on 127.0.0.1, and every request through it is appended to a JSON-lines
+// log in the session's data directory: method, host, path, status, sizes and,
+// optionally, bodies.
+//
+// Each proxy requires a random per-session credential, which the panes receive
+// inside the proxy URL of their proxy variables. Requests without it are
+// refused, so the loopback port is not an open proxy for other processes or
+// for the panes of other sessions.
+//
+// HTTPS requests pass through a CONNECT tunnel unchanged; only the host, the
+// duration and the byte counts of the tunnel are recorded. The proxy connects
+// directly and does not chain to an upstream proxy.
+package netcapture
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// LogFileName is the JSON-lines capture log in a session's data directory.
+	LogFileName = "network-capture.jsonl"
+	// maxLogBytes rotates the capture log to LogFileName+".1" once exceeded.
+	maxLogBytes = 4 << 20
+	// maxListRecords bounds the records Records returns.
+	maxListRecords = 1000
+	// noProxy keeps local servers (dev servers, the app's own endpoints) off
+	// the proxy.
+	noProxy = "localhost,127.0.0.1,::1"
+)
+
+// Record is one request made through a capture proxy.
+type Record struct {
+	ID         string `json:"id"`
+	Session    string `json:"session"`
+	StartedAt  string `json:"started_at"`
+	DurationMs int64  `json:"duration_ms"`
+	Method     string `json:"method"`
+	// Scheme is "http" for forwarded requests and "https" for CONNECT tunnels.
+	Scheme string `json:"scheme"`
+	Host   string `json:"host"`
+	// Path excludes the query, which may carry credentials. Empty for tunnels.
+	Path          string `json:"path,omitempty"`
+	Status        int    `json:"status,omitempty"`
+	RequestBytes  int64  `json:"request_bytes"`
+	ResponseBytes int64  `json:"response_bytes"`
+	ContentType   string `json:"content_type,omitempty"`
+	Error         string `json:"error,omitempty"`
+	// RequestBody and ResponseBody are kept only when body capture is on,
+	// up to maxBodyBytes each.
+	RequestBody   string `json:"request_body,omitempty"`
+	ResponseBody  string `json:"response_body,omitempty"`
+	BodyTruncated bool   `json:"body_truncated,omitempty"`
+}
+
+// Status is the capture state of a session.
+type Status struct {
+	SessionName string `json:"session_name"`
+	Enabled     bool   `json:"enabled"`
+	// ProxyURL is the proxy address without its credential.
+	ProxyURL      string `json:"proxy_url,omitempty"`
+	CaptureBodies bool   `json:"capture_bodies"`
+	// Captured counts the requests recorded since capture was turned on.
+	Captured int64 `json:"captured"`
+}
+
+// Deps holds external dependencies injected at construction time.
+type Deps struct {
+	// LogPath returns the capture log file of a session.
+	LogPath func(sessionName string) (string, error)
+
+	// Now returns the current time. Optional: defaults to time.Now.
+	Now func() time.Time
+}
+
+// Service owns the capture proxies of all sessions.
+//
+// Thread-safety is managed internally via mu. No external locking is required.
+type Service struct {
+	deps Deps
+	seq  atomic.Uint64
+
+	mu      sync.Mutex
+	proxies map[string]*proxy
+}
+
+// NewService creates a network capture service.
+// Panics if LogPath is nil.
+func NewService(deps Deps) *Service {
+	if deps.LogPath == nil {
+		panic("netcapture.NewService: LogPath must be non-nil")
+	}
+	if deps.Now == nil {
+		deps.Now = time.Now
+	}
+	return &Service{deps: deps, proxies: map[string]*proxy{}}
+}
+
+// Enable starts the capture proxy of sessionName, or updates captureBodies
+// when it already runs. Only panes started afterwards use the proxy.
+func (s *Service) Enable(sessionName string, captureBodies bool) (Status, error) {
+	sessionName = strings.TrimSpace(sessionName)
+	if sessionName == "" {
+		return Status{}, errors.New("session name is required")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if p, ok := s.proxies[sessionName]; ok {
+		p.captureBodies.Store(captureBodies)
+		return p.status(), nil
+	}
+	path, err := s.deps.LogPath(sessionName)
+	if err != nil {
+		return Status{}, fmt.Errorf("resolve capture log: %w", err)
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return Status{}, fmt.Errorf("start capture proxy: %w", err)
+	}
+	p := newProxy(s, sessionName, path, listener, captureBodies)
+	s.proxies[sessionName] = p
+	go p.serve()
+	slog.Info("[NET-CAPTURE] capture proxy started", "session", sessionName, "url", p.url, "bodies", captureBodies)
+	return p.status(), nil
+}
+
+// Disable stops the capture proxy of sessionName and closes its open tunnels.
+// The capture log is kept.
+func (s *Service) Disable(sessionName string) error {
+	s.mu.Lock()
+	p, ok := s.proxies[sessionName]
+	delete(s.proxies, sessionName)
+	s.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("network capture is not on for session %q", sessionName)
+	}
+	p.close()
+	slog.Info("[NET-CAPTURE] capture proxy stopped", "session", sessionName, "captured", p.captured.Load())
+	return nil
+}
+
+// Status returns the capture state of sessionName.
+func (s *Service) Status(sessionName string) Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if p, ok := s.proxies[sessionName]; ok {
+		return p.status()
+	}
+	return Status{SessionName: sessionName}
+}
+
+// ProxyEnv returns the proxy variables for new panes of sessionName, or nil
+// when its capture is off. The proxy URLs carry the session's credential.
+func (s *Service) ProxyEnv(sessionName string) map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.proxies[sessionName]
+	if !ok {
+		return nil
+	}
+	return map[string]string{
+		"HTTP_PROXY":  p.envURL,
+		"HTTPS_PROXY": p.envURL,
+		"NO_PROXY":    noProxy,
+	}
+}
+
+// Records returns up to limit captured requests of sessionName, newest
+// first. limit <= 0 means maxListRecords.
+func (s *Service) Records(sessionName string, limit int) ([]Record, error) {
+	if limit <= 0 || limit > maxListRecords {
+		limit = maxListRecords
+	}
+	path, err := s.logPath(sessionName)
+	if err != nil {
+		return nil, err
+	}
+	var records []Record
+	for _, name := range []string{path + ".1", path} {
+		read, err := readLog(name)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, read...)
+	}
+	slices.Reverse(records)
+	if len(records) > limit {
+		records = records[:limit]
+	}
+	return records, nil
+}
+
+// Clear deletes the capture log of sessionName.
+func (s *Service) Clear(sessionName string) error {
+	path, err := s.logPath(sessionName)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	p := s.proxies[sessionName]
+	s.mu.Unlock()
+	if p != nil {
+		p.writeMu.Lock()
+		defer p.writeMu.Unlock()
+	}
+	for _, name := range []string{path, path + ".1"} {
+		if err := os.Remove(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("remove capture log: %w", err)
+		}
+	}
+	return nil
+}
+
+// CleanupSession stops the capture proxy of a destroyed session.
+func (s *Service) CleanupSession(sessionName string) error {
+	s.mu.Lock()
+	_, ok := s.proxies[sessionName]
+	s.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	return s.Disable(sessionName)
+}
+
+// Rename moves the capture proxy of oldName to newName. Running panes keep
+// using it; later records carry the new name.
+func (s *Service) Rename(oldName, newName string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.proxies[oldName]
+	if !ok {
+		return nil
+	}
+	if _, exists := s.proxies[newName]; exists {
+		return fmt.Errorf("network capture already runs for session %q", newName)
+	}
+	delete(s.proxies, oldName)
+	s.proxies[newName] = p
+	p.setSessionName(newName)
+	return nil
+}
+
+// Shutdown stops every capture proxy.
+func (s *Service) Shutdown() {
+	s.mu.Lock()
+	proxies := s.proxies
+	s.proxies = map[string]*proxy{}
+	s.mu.Unlock()
+	for _, p := range proxies {
+		p.close()
+	}
+}
+
+// logPath returns the log of a running proxy, which stays valid while its
+// session is being renamed, or asks LogPath.
+func (s *Service) logPath(sessionName string) (string, error) {
+	s.mu.Lock()
+	p, ok := s.proxies[sessionName]
+	s.mu.Unlock()
+	if ok {
+		return p.path, nil
+	}
+	path, err := s.deps.LogPath(sessionName)
+	if err != nil {
+		return "", fmt.Errorf("resolve capture log: %w", err)
+	}
+	return path, nil
+}
+
+// readLog reads one capture log file. A missing file has no records and
+// unreadable lines are skipped so a torn write does not hide the rest.
+func readLog(path string) ([]Record, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("open capture log: %w", err)
+	}
+	defer f.Close()
+
+	var records []Record
+	reader := bufio.NewReader(f)
+	for {
+		line, readErr := reader.ReadBytes('\n')
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			var record Record
+			if err := json.Unmarshal(line, &record); err != nil {
+				slog.Debug("[DEBUG-NET-CAPTURE] skipping unreadable capture log line", "error", err)
+			} else {
+				records = append(records, record)
+			}
+		}
+		if readErr != nil {
+			if !errors.Is(readErr, io.EOF) {
+				return records, fmt.Errorf("read capture log: %w", readErr)
+			}
+			return records, nil
+		}
+	}
+}
+
+// appendLog appends record as one JSON line, rotating the log first when it
+// has grown past maxLogBytes.
+func appendLog(path string, record Record) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create capture log directory: %w", err)
+	}
+	if info, err := os.Stat(path); err == nil && info.Size() > maxLogBytes {
+		if err := os.Rename(path, path+".1"); err != nil {
+			return fmt.Errorf("rotate capture log: %w", err)
+		}
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("marshal capture record: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("open capture log: %w", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("write capture log: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("close capture log: %w", err)
+	}
+	return nil
+}
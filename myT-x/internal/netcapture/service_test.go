@@ -0,0 +1,256 @@
+package netcapture
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestService(t *testing.T) *Service {
+	t.Helper()
+	dir := t.TempDir()
+	svc := NewService(Deps{
+		LogPath: func(sessionName string) (string, error) {
+			if sessionName == "missing" {
+				return "", fmt.Errorf("session %q not found", sessionName)
+			}
+			return filepath.Join(dir, sessionName, LogFileName), nil
+		},
+	})
+	t.Cleanup(svc.Shutdown)
+	return svc
+}
+
+// proxyClient returns a client that sends every request through the
+// capture proxy of sessionName, as a pane with its ProxyEnv would.
+func proxyClient(t *testing.T, svc *Service, sessionName string, base *http.Transport) *http.Client {
+	t.Helper()
+	env := svc.ProxyEnv(sessionName)
+	if env["HTTP_PROXY"] == "" || env["HTTPS_PROXY"] != env["HTTP_PROXY"] || env["NO_PROXY"] == "" {
+		t.Fatalf("ProxyEnv() = %v, want proxy variables", env)
+	}
+	proxyURL, err := url.Parse(env["HTTP_PROXY"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if base == nil {
+		base = &http.Transport{}
+	}
+	base.Proxy = http.ProxyURL(proxyURL)
+	t.Cleanup(base.CloseIdleConnections)
+	return &http.Client{Transport: base}
+}
+
+func TestNewServicePanicsWithoutLogPath(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("NewService() should panic without LogPath")
+		}
+	}()
+	NewService(Deps{})
+}
+
+func TestCaptureRecordsHTTPRequestMetadataAndBodies(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = fmt.Fprintf(w, "got %s", body)
+	}))
+	defer backend.Close()
+
+	svc := newTestService(t)
+	status, err := svc.Enable("agent", true)
+	if err != nil {
+		t.Fatalf("Enable() error = %v", err)
+	}
+	if !status.Enabled || !status.CaptureBodies || !strings.HasPrefix(status.ProxyURL, "http://127.0.0.1:") {
+		t.Fatalf("Enable() = %+v", status)
+	}
+
+	client := proxyClient(t, svc, "agent", nil)
+	resp, err := client.Post(backend.URL+"/v1/items?token=secret", "text/plain", strings.NewReader("hello"))
+	if err != nil {
+		t.Fatalf("POST through proxy error = %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated || string(body) != "got hello" {
+		t.Fatalf("response = %d %q", resp.StatusCode, body)
+	}
+
+	records, err := svc.Records("agent", 0)
+	if err != nil || len(records) != 1 {
+		t.Fatalf("Records() = %+v, %v; want one record", records, err)
+	}
+	got := records[0]
+	if got.Session != "agent" || got.Method != http.MethodPost || got.Scheme != "http" || got.Path != "/v1/items" ||
+		got.Status != http.StatusCreated || got.ContentType != "text/plain" {
+		t.Fatalf("record = %+v", got)
+	}
+	if got.RequestBytes != 5 || got.ResponseBytes != 9 || got.RequestBody != "hello" || got.ResponseBody != "got hello" {
+		t.Fatalf("record sizes/bodies = %+v", got)
+	}
+	if strings.Contains(got.Path, "secret") {
+		t.Fatalf("the query must not be recorded: %+v", got)
+	}
+	if svc.Status("agent").Captured != 1 {
+		t.Fatalf("Status().Captured = %d, want 1", svc.Status("agent").Captured)
+	}
+}
+
+func TestCaptureRecordsHTTPSTunnelWithoutBodies(t *testing.T) {
+	backend := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, "secure")
+	}))
+	defer backend.Close()
+
+	svc := newTestService(t)
+	if _, err := svc.Enable("agent", true); err != nil {
+		t.Fatalf("Enable() error = %v", err)
+	}
+	client := proxyClient(t, svc, "agent", &http.Transport{
+		TLSClientConfig: &tls.Config{RootCAs: backend.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs},
+	})
+	resp, err := client.Get(backend.URL + "/private")
+	if err != nil {
+		t.Fatalf("GET through tunnel error = %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if string(body) != "secure" {
+		t.Fatalf("body = %q", body)
+	}
+	// The tunnel is recorded once it closes.
+	client.CloseIdleConnections()
+	svc.Shutdown()
+
+	records, err := svc.Records("agent", 0)
+	if err != nil || len(records) != 1 {
+		t.Fatalf("Records() = %+v, %v; want one record", records, err)
+	}
+	got := records[0]
+	host := strings.TrimPrefix(backend.URL, "https://")
+	if got.Method != http.MethodConnect || got.Scheme != "https" || got.Host != host || got.Status != http.StatusOK {
+		t.Fatalf("record = %+v", got)
+	}
+	if got.Path != "" || got.RequestBody != "" || got.ResponseBody != "" || got.RequestBytes == 0 || got.ResponseBytes == 0 {
+		t.Fatalf("tunnel record must carry sizes only: %+v", got)
+	}
+}
+
+func TestProxyRequiresSessionCredential(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+	defer backend.Close()
+
+	svc := newTestService(t)
+	status, err := svc.Enable("agent", false)
+	if err != nil {
+		t.Fatalf("Enable() error = %v", err)
+	}
+	if _, err := svc.Enable("other", false); err != nil {
+		t.Fatalf("Enable() error = %v", err)
+	}
+	envURL, err := url.Parse(svc.ProxyEnv("agent")["HTTP_PROXY"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if envURL.User == nil {
+		t.Fatalf("ProxyEnv() URL %q carries no credential", envURL.Redacted())
+	}
+	if strings.Contains(status.ProxyURL, "@") {
+		t.Fatalf("Status().ProxyURL = %q, want no credential", status.ProxyURL)
+	}
+
+	otherURL, err := url.Parse(svc.ProxyEnv("other")["HTTP_PROXY"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	proxyAddr, err := url.Parse(status.ProxyURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherSession := &url.URL{Scheme: "http", User: otherURL.User, Host: proxyAddr.Host}
+	for name, proxyURL := range map[string]*url.URL{
+		"no credential":            proxyAddr,
+		"other session credential": otherSession,
+	} {
+		t.Run(name, func(t *testing.T) {
+			transport := &http.Transport{Proxy: http.ProxyURL(proxyURL)}
+			defer transport.CloseIdleConnections()
+			resp, err := (&http.Client{Transport: transport}).Get(backend.URL)
+			if err != nil {
+				t.Fatalf("GET through proxy error = %v", err)
+			}
+			_ = resp.Body.Close()
+			if resp.StatusCode != http.StatusProxyAuthRequired || resp.Header.Get("Proxy-Authenticate") == "" {
+				t.Fatalf("status = %d, want 407 with Proxy-Authenticate", resp.StatusCode)
+			}
+
+			tunnel := &http.Transport{Proxy: http.ProxyURL(proxyURL)}
+			defer tunnel.CloseIdleConnections()
+			if _, err := (&http.Client{Transport: tunnel}).Get("https://" + strings.TrimPrefix(backend.URL, "http://")); err == nil {
+				t.Fatal("CONNECT without the session credential should fail")
+			}
+		})
+	}
+	if records, err := svc.Records("agent", 0); err != nil || len(records) != 0 {
+		t.Fatalf("Records() = %+v, %v; rejected requests must not be recorded", records, err)
+	}
+}
+
+func TestDisableRenameAndClear(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+	defer backend.Close()
+
+	svc := newTestService(t)
+	if _, err := svc.Enable("missing", false); err == nil {
+		t.Fatal("Enable() of an unknown session should fail")
+	}
+	if _, err := svc.Enable("old", false); err != nil {
+		t.Fatalf("Enable() error = %v", err)
+	}
+	if err := svc.Rename("old", "new"); err != nil {
+		t.Fatalf("Rename() error = %v", err)
+	}
+	if svc.ProxyEnv("old") != nil {
+		t.Fatal("ProxyEnv() of the old name should be nil after Rename")
+	}
+	client := proxyClient(t, svc, "new", nil)
+	resp, err := client.Get(backend.URL)
+	if err != nil {
+		t.Fatalf("GET through proxy error = %v", err)
+	}
+	_ = resp.Body.Close()
+
+	records, err := svc.Records("new", 0)
+	if err != nil || len(records) != 1 || records[0].Session != "new" || records[0].RequestBody != "" {
+		t.Fatalf("Records() = %+v, %v; want one record of the renamed session without bodies", records, err)
+	}
+
+	if err := svc.Disable("new"); err != nil {
+		t.Fatalf("Disable() error = %v", err)
+	}
+	if svc.Status("new").Enabled || svc.ProxyEnv("new") != nil {
+		t.Fatal("capture should be off after Disable")
+	}
+	if err := svc.Disable("new"); err == nil {
+		t.Fatal("Disable() twice should fail")
+	}
+	if err := svc.CleanupSession("new"); err != nil {
+		t.Fatalf("CleanupSession() of a session without capture error = %v", err)
+	}
+
+	if err := svc.Clear("new"); err != nil {
+		t.Fatalf("Clear() error = %v", err)
+	}
+	if records, err := svc.Records("new", 0); err != nil || len(records) != 0 {
+		t.Fatalf("Records() after Clear = %+v, %v", records, err)
+	}
+}
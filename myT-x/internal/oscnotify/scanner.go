@@ -0,0 +1,201 @@
+// Package oscnotify detects desktop notification escape sequences in pane
+// output, so programs that notify through the terminal work inside panes:
+//
+//	ESC ] 9 ; <body> ST                       iTerm2 / Windows Terminal
+//	ESC ] 777 ; notify ; <title> ; <body> ST  rxvt-unicode / VTE
+//
+// ConEmu reuses OSC 9 for commands whose payload starts with a number
+// (9;4 progress, 9;9 working directory); those are not notifications and are
+// ignored. ST is either BEL or ESC \.
+package oscnotify
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Notification is one notification requested by a program in a pane.
+type Notification struct {
+	// Title is empty for OSC 9, which carries only a body.
+	Title string
+	Body  string
+}
+
+// maxPayloadLength bounds how much of an OSC payload is kept. Longer
+// payloads are truncated; the notification is still reported.
+const maxPayloadLength = 1024
+
+type scanState uint8
+
+const (
+	scanGround scanState = iota
+	scanEscape
+	scanOSC
+	scanOSCEscape
+)
+
+type paneScan struct {
+	state   scanState
+	payload []byte
+}
+
+// Scanner tracks OSC parser state per pane across output chunks.
+// The zero value is ready to use.
+type Scanner struct {
+	mu    sync.Mutex
+	panes map[string]*paneScan
+}
+
+// Scan feeds chunk for paneID and returns the notifications it completed,
+// in order.
+func (s *Scanner) Scan(paneID string, chunk []byte) []Notification {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	scan := s.panes[paneID]
+	if scan == nil {
+		// Fast path: nothing to do for chunks without ESC outside an OSC.
+		if bytes.IndexByte(chunk, 0x1b) < 0 {
+			return nil
+		}
+		scan = &paneScan{}
+	}
+
+	var notifications []Notification
+	for _, c := range chunk {
+		switch scan.state {
+		case scanGround:
+			if c == 0x1b {
+				scan.state = scanEscape
+			}
+		case scanEscape:
+			switch c {
+			case ']':
+				scan.state = scanOSC
+				scan.payload = scan.payload[:0]
+			case 0x1b:
+				// Stay in escape state for a repeated ESC.
+			default:
+				scan.state = scanGround
+			}
+		case scanOSC:
+			switch c {
+			case 0x07:
+				notifications = scan.finish(notifications)
+			case 0x1b:
+				scan.state = scanOSCEscape
+			default:
+				if len(scan.payload) < maxPayloadLength {
+					scan.payload = append(scan.payload, c)
+				}
+			}
+		case scanOSCEscape:
+			switch c {
+			case '\\':
+				notifications = scan.finish(notifications)
+			case ']':
+				// ESC ] inside an OSC aborts it and starts a new one.
+				scan.state = scanOSC
+				scan.payload = scan.payload[:0]
+			case 0x1b:
+				// Stay: the next byte decides.
+			default:
+				scan.state = scanGround
+			}
+		}
+	}
+
+	if scan.state == scanGround {
+		delete(s.panes, paneID)
+	} else {
+		if s.panes == nil {
+			s.panes = make(map[string]*paneScan)
+		}
+		s.panes[paneID] = scan
+	}
+	return notifications
+}
+
+// Forget drops scanner state for removed panes.
+func (s *Scanner) Forget(paneIDs []string) {
+	if len(paneIDs) == 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, paneID := range paneIDs {
+		delete(s.panes, paneID)
+	}
+}
+
+func (p *paneScan) finish(notifications []Notification) []Notification {
+	p.state = scanGround
+	if notification, ok := parseNotification(p.payload); ok {
+		notifications = append(notifications, notification)
+	}
+	return notifications
+}
+
+// parseNotification parses an OSC payload such as "9;done" or
+// "777;notify;Build;done".
+func parseNotification(payload []byte) (Notification, bool) {
+	code, rest, ok := strings.Cut(string(payload), ";")
+	if !ok {
+		return Notification{}, false
+	}
+
+	var notification Notification
+	switch code {
+	case "9":
+		if isConEmuCommand(rest) {
+			return Notification{}, false
+		}
+		notification.Body = rest
+	case "777":
+		command, args, _ := strings.Cut(rest, ";")
+		if command != "notify" {
+			return Notification{}, false
+		}
+		// The body may itself contain ';'.
+		notification.Title, notification.Body, _ = strings.Cut(args, ";")
+	default:
+		return Notification{}, false
+	}
+
+	notification.Title = sanitize(notification.Title)
+	notification.Body = sanitize(notification.Body)
+	if notification.Title == "" && notification.Body == "" {
+		return Notification{}, false
+	}
+	return notification, true
+}
+
+// isConEmuCommand reports whether an OSC 9 argument is a ConEmu command,
+// i.e. a number optionally followed by ';' and parameters.
+func isConEmuCommand(arg string) bool {
+	number, _, _ := strings.Cut(arg, ";")
+	if number == "" {
+		return false
+	}
+	for _, r := range number {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// sanitize drops invalid UTF-8 (a truncated payload may end mid-character)
+// and control characters, which would otherwise reach the notification UI.
+func sanitize(text string) string {
+	text = strings.ToValidUTF8(text, "")
+	text = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, text)
+	return strings.TrimSpace(text)
+}
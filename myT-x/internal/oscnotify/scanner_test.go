@@ -0,0 +1,91 @@
+package oscnotify
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestScannerDetectsNotifications(t *testing.T) {
+	tests := []struct {
+		name   string
+		chunks []string
+		want   []Notification
+	}{
+		{
+			name:   "plain output",
+			chunks: []string{"hello\r\n"},
+		},
+		{
+			name:   "OSC 9 with BEL terminator",
+			chunks: []string{"build\x1b]9;Build finished\x07\r\n"},
+			want:   []Notification{{Body: "Build finished"}},
+		},
+		{
+			name:   "OSC 777 with ST terminator and semicolons in the body",
+			chunks: []string{"\x1b]777;notify;Tests;3 passed; 1 failed\x1b\\"},
+			want:   []Notification{{Title: "Tests", Body: "3 passed; 1 failed"}},
+		},
+		{
+			name:   "sequence split across chunks",
+			chunks: []string{"\x1b]77", "7;notify;Dep", "loy;done\x1b", "\\"},
+			want:   []Notification{{Title: "Deploy", Body: "done"}},
+		},
+		{
+			name:   "ConEmu commands and other OSC sequences are ignored",
+			chunks: []string{"\x1b]9;4;1;50\x07\x1b]9;9;C:\\work\x07\x1b]0;title\x07\x1b]777;preexec\x07\x1b]9;\x07"},
+		},
+		{
+			name:   "OSC 9 whose text starts with a digit but is not a command",
+			chunks: []string{"\x1b]9;3 tests failed\x07"},
+			want:   []Notification{{Body: "3 tests failed"}},
+		},
+		{
+			name:   "control characters are stripped",
+			chunks: []string{"\x1b]9;line\ttab\x01\x07"},
+			want:   []Notification{{Body: "linetab"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var scanner Scanner
+			var got []Notification
+			for _, chunk := range tt.chunks {
+				got = append(got, scanner.Scan("%1", []byte(chunk))...)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("notifications = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScannerTruncatesLongPayloads(t *testing.T) {
+	var scanner Scanner
+	body := strings.Repeat("あ", maxPayloadLength)
+	got := scanner.Scan("%1", []byte("\x1b]9;"+body+"\x07"))
+	if len(got) != 1 {
+		t.Fatalf("notifications = %d, want 1", len(got))
+	}
+	if len(got[0].Body) > maxPayloadLength || !strings.HasPrefix(body, got[0].Body) {
+		t.Fatalf("body length = %d, want a valid prefix of at most %d bytes", len(got[0].Body), maxPayloadLength)
+	}
+}
+
+func TestScannerTracksPanesIndependently(t *testing.T) {
+	var scanner Scanner
+	scanner.Scan("%1", []byte("\x1b]9;first"))
+	if got := scanner.Scan("%2", []byte(" pane\x07")); len(got) != 0 {
+		t.Fatalf("pane %%2 notifications = %+v, want none", got)
+	}
+	if got := scanner.Scan("%1", []byte(" pane\x07")); len(got) != 1 || got[0].Body != "first pane" {
+		t.Fatalf("pane %%1 notifications = %+v, want \"first pane\"", got)
+	}
+
+	scanner.Scan("%1", []byte("\x1b]9;lost"))
+	scanner.Forget([]string{"%1"})
+	if got := scanner.Scan("%1", []byte("\x07")); len(got) != 0 {
+		t.Fatalf("notifications after Forget = %+v, want none", got)
+	}
+}
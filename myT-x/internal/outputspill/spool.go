@@ -0,0 +1,367 @@
+// Package outputspill keeps pane output on disk while no client is watching
+// it, e.g. while the window sits in the tray or the frontend stream is
+// disconnected. The in-memory replay buffer only holds the most recent
+// output; a spill file starts with a copy of that buffer and grows with
+// everything printed afterwards, so an agent's overnight output can be
+// replayed in full once a client is back.
+package outputspill
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// DefaultMaxBytesPerPane bounds the disk used per pane. When a pane spills
+// more, its oldest output is dropped.
+const DefaultMaxBytesPerPane = 64 * 1024 * 1024
+
+// Deps holds external dependencies injected at construction time.
+type Deps struct {
+	// Attended reports whether a client currently consumes pane output.
+	Attended func() bool
+
+	// Seed returns the in-memory output history of a pane. A spill file
+	// starts with it. Optional: nil starts spill files empty.
+	Seed func(paneID string) []byte
+
+	// OnResume is called with the panes that hold spilled output when a
+	// client is back, so it can replay them. Optional.
+	OnResume func(paneIDs []string)
+
+	// Dir holds the spill files. It is cleared when the first spill of the
+	// process starts. Optional: defaults to DefaultDir().
+	Dir string
+
+	// MaxBytesPerPane bounds the spill of one pane.
+	// Optional: defaults to DefaultMaxBytesPerPane.
+	MaxBytesPerPane int64
+}
+
+// DefaultDir returns the spill directory under the system temp directory.
+func DefaultDir() string {
+	return filepath.Join(os.TempDir(), "myT-x-spill")
+}
+
+// paneSpill is the spill of one pane, stored as two segments so the oldest
+// half can be dropped without rewriting the file: output is appended to
+// current, which replaces previous once it reaches half the limit.
+type paneSpill struct {
+	// base names the segment files of the pane within the spill directory.
+	base        string
+	segment     int
+	current     *os.File
+	currentPath string
+	currentSize int64
+	// previousPath holds the segment before current, or "" when there is none.
+	previousPath string
+}
+
+// Spool spills the output of each pane to disk while no client is attended.
+// A pane keeps spilling, even once attended again, until its spill is taken,
+// so the spill never misses output between the resume and the replay.
+//
+// Thread-safety is managed internally via mu. No external locking is required.
+type Spool struct {
+	deps Deps
+
+	mu       sync.Mutex
+	attended bool
+	dirReady bool
+	closed   bool
+	panes    map[string]*paneSpill
+}
+
+// NewSpool creates a spool. Panics if Attended is nil.
+func NewSpool(deps Deps) *Spool {
+	if deps.Attended == nil {
+		panic("outputspill.NewSpool: Attended must be non-nil")
+	}
+	if deps.Dir == "" {
+		deps.Dir = DefaultDir()
+	}
+	if deps.MaxBytesPerPane <= 0 {
+		deps.MaxBytesPerPane = DefaultMaxBytesPerPane
+	}
+	return &Spool{
+		deps:     deps,
+		attended: true,
+		panes:    map[string]*paneSpill{},
+	}
+}
+
+// Write records a flushed output chunk of a pane. It only touches the disk
+// while unattended or while the pane has a spill that was not taken yet.
+func (s *Spool) Write(paneID string, chunk []byte) {
+	if paneID == "" || len(chunk) == 0 {
+		return
+	}
+	attended := s.deps.Attended()
+	s.mu.Lock()
+	resumed := s.setAttendedLocked(attended)
+	if !s.closed {
+		pane := s.panes[paneID]
+		if pane == nil && !attended {
+			pane = s.startLocked(paneID, chunk)
+		}
+		if pane != nil {
+			if err := s.appendLocked(pane, chunk); err != nil {
+				slog.Warn("[WARN-SPILL] failed to spill pane output, dropping the spill", "paneID", paneID, "error", err)
+				s.dropLocked(paneID)
+			}
+		}
+	}
+	s.mu.Unlock()
+	s.notifyResumed(resumed)
+}
+
+// Refresh re-checks whether a client is attended and reports spilled panes
+// when one is back. Call it when the attended state may have changed
+// without pane output, e.g. when the window is shown.
+func (s *Spool) Refresh() {
+	attended := s.deps.Attended()
+	s.mu.Lock()
+	resumed := s.setAttendedLocked(attended)
+	s.mu.Unlock()
+	s.notifyResumed(resumed)
+}
+
+// Pending returns the panes that hold spilled output, sorted.
+func (s *Spool) Pending() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.pendingLocked()
+}
+
+// Take returns the spilled output of a pane and deletes it. It reports
+// false when the pane has no spill.
+func (s *Spool) Take(paneID string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pane := s.panes[paneID]
+	if pane == nil {
+		return nil, false
+	}
+	defer s.dropLocked(paneID)
+
+	var data []byte
+	for _, path := range []string{pane.previousPath, pane.currentPath} {
+		if path == "" {
+			continue
+		}
+		segment, err := os.ReadFile(path)
+		if err != nil {
+			slog.Warn("[WARN-SPILL] failed to read spilled pane output", "paneID", paneID, "path", path, "error", err)
+			continue
+		}
+		data = append(data, segment...)
+	}
+	return data, len(data) > 0
+}
+
+// Remove deletes the spill of a pane that went away.
+func (s *Spool) Remove(paneID string) {
+	s.mu.Lock()
+	s.dropLocked(paneID)
+	s.mu.Unlock()
+}
+
+// Retain deletes the spills of panes not in alive.
+func (s *Spool) Retain(alive map[string]struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for paneID := range s.panes {
+		if _, ok := alive[paneID]; !ok {
+			s.dropLocked(paneID)
+		}
+	}
+}
+
+// Close deletes every spill and stops spilling. Safe to call multiple times.
+func (s *Spool) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	s.closed = true
+	for paneID := range s.panes {
+		s.dropLocked(paneID)
+	}
+	if s.dirReady {
+		if err := os.RemoveAll(s.deps.Dir); err != nil {
+			slog.Debug("[DEBUG-SPILL] failed to remove spill directory", "dir", s.deps.Dir, "error", err)
+		}
+	}
+}
+
+// setAttendedLocked records the attended state and returns the panes to
+// report when a client just came back.
+// REQUIRES: s.mu must be held by the caller.
+func (s *Spool) setAttendedLocked(attended bool) []string {
+	wasAttended := s.attended
+	s.attended = attended
+	if wasAttended || !attended {
+		return nil
+	}
+	return s.pendingLocked()
+}
+
+func (s *Spool) pendingLocked() []string {
+	paneIDs := make([]string, 0, len(s.panes))
+	for paneID := range s.panes {
+		paneIDs = append(paneIDs, paneID)
+	}
+	slices.Sort(paneIDs)
+	return paneIDs
+}
+
+func (s *Spool) notifyResumed(paneIDs []string) {
+	if len(paneIDs) == 0 || s.deps.OnResume == nil {
+		return
+	}
+	slog.Debug("[DEBUG-SPILL] client attended again with spilled panes", "panes", paneIDs)
+	s.deps.OnResume(paneIDs)
+}
+
+// startLocked creates the spill of a pane, seeded with its in-memory
+// history. It returns nil when the spill file cannot be created.
+// REQUIRES: s.mu must be held by the caller.
+func (s *Spool) startLocked(paneID string, chunk []byte) *paneSpill {
+	if err := s.ensureDirLocked(); err != nil {
+		slog.Warn("[WARN-SPILL] failed to prepare spill directory", "dir", s.deps.Dir, "error", err)
+		return nil
+	}
+	pane := &paneSpill{base: fileBase(paneID)}
+	pane.currentPath = s.segmentPath(pane.base, pane.segment)
+	file, err := os.OpenFile(pane.currentPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		slog.Warn("[WARN-SPILL] failed to create spill file", "paneID", paneID, "error", err)
+		return nil
+	}
+	pane.current = file
+	s.panes[paneID] = pane
+
+	if s.deps.Seed != nil {
+		seed := s.deps.Seed(paneID)
+		// The pane state is fed ahead of the flush that reports chunk, so the
+		// history usually ends with it already.
+		seed = bytes.TrimSuffix(seed, chunk)
+		if err := s.appendLocked(pane, seed); err != nil {
+			slog.Warn("[WARN-SPILL] failed to seed spill file", "paneID", paneID, "error", err)
+		}
+	}
+	slog.Debug("[DEBUG-SPILL] spilling pane output", "paneID", paneID, "path", pane.currentPath)
+	return pane
+}
+
+// appendLocked appends data to the current segment, rotating it first when
+// it reached half the per-pane limit.
+// REQUIRES: s.mu must be held by the caller.
+func (s *Spool) appendLocked(pane *paneSpill, data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+	segmentLimit := s.deps.MaxBytesPerPane / 2
+	if int64(len(data)) > segmentLimit {
+		data = data[int64(len(data))-segmentLimit:]
+	}
+	if pane.currentSize+int64(len(data)) > segmentLimit {
+		if err := s.rotateLocked(pane); err != nil {
+			return err
+		}
+	}
+	n, err := pane.current.Write(data)
+	pane.currentSize += int64(n)
+	if err != nil {
+		return fmt.Errorf("write %s: %w", pane.currentPath, err)
+	}
+	return nil
+}
+
+// rotateLocked turns the current segment into the previous one, dropping
+// the old previous segment, and starts an empty current segment.
+// REQUIRES: s.mu must be held by the caller.
+func (s *Spool) rotateLocked(pane *paneSpill) error {
+	if err := pane.current.Close(); err != nil {
+		return fmt.Errorf("close %s: %w", pane.currentPath, err)
+	}
+	if pane.previousPath != "" {
+		if err := os.Remove(pane.previousPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("remove %s: %w", pane.previousPath, err)
+		}
+	}
+	pane.previousPath = pane.currentPath
+	nextSegment := 1 - pane.segment
+	nextPath := s.segmentPath(pane.base, nextSegment)
+	file, err := os.OpenFile(nextPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		pane.current = nil
+		return fmt.Errorf("create %s: %w", nextPath, err)
+	}
+	pane.current = file
+	pane.segment = nextSegment
+	pane.currentPath = nextPath
+	pane.currentSize = 0
+	return nil
+}
+
+// dropLocked closes and deletes the spill of a pane.
+// REQUIRES: s.mu must be held by the caller.
+func (s *Spool) dropLocked(paneID string) {
+	pane := s.panes[paneID]
+	if pane == nil {
+		return
+	}
+	delete(s.panes, paneID)
+	if pane.current != nil {
+		if err := pane.current.Close(); err != nil {
+			slog.Debug("[DEBUG-SPILL] failed to close spill file", "path", pane.currentPath, "error", err)
+		}
+	}
+	for _, path := range []string{pane.previousPath, pane.currentPath} {
+		if path == "" {
+			continue
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			slog.Debug("[DEBUG-SPILL] failed to remove spill file", "path", path, "error", err)
+		}
+	}
+}
+
+// ensureDirLocked clears spill files left by an earlier process and creates
+// the spill directory, once per spool.
+// REQUIRES: s.mu must be held by the caller.
+func (s *Spool) ensureDirLocked() error {
+	if s.dirReady {
+		return nil
+	}
+	if err := os.RemoveAll(s.deps.Dir); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.deps.Dir, 0o700); err != nil {
+		return err
+	}
+	s.dirReady = true
+	return nil
+}
+
+func (s *Spool) segmentPath(base string, segment int) string {
+	return filepath.Join(s.deps.Dir, fmt.Sprintf("%s.%d.out", base, segment))
+}
+
+// fileBase maps a pane ID such as "%3" to a file name.
+func fileBase(paneID string) string {
+	return "pane-" + strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' {
+			return r
+		}
+		return '_'
+	}, paneID)
+}
@@ -0,0 +1,111 @@
+package outputspill
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSpoolSpillsWhileUnattended(t *testing.T) {
+	dir := t.TempDir() + "/spill"
+	attended := true
+	var resumed [][]string
+	spool := NewSpool(Deps{
+		Attended: func() bool { return attended },
+		Seed:     func(paneID string) []byte { return []byte("history|chunk-1") },
+		OnResume: func(paneIDs []string) { resumed = append(resumed, paneIDs) },
+		Dir:      dir,
+	})
+	t.Cleanup(spool.Close)
+
+	spool.Write("%1", []byte("attended"))
+	if _, ok := spool.Take("%1"); ok {
+		t.Fatal("Take() should find nothing while attended")
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Fatalf("spill directory created while attended: %v", err)
+	}
+
+	attended = false
+	// The history already ends with the first spilled chunk.
+	spool.Write("%1", []byte("chunk-1"))
+	spool.Write("%1", []byte("|chunk-2"))
+	if got := spool.Pending(); !reflect.DeepEqual(got, []string{"%1"}) {
+		t.Fatalf("Pending() = %v", got)
+	}
+
+	attended = true
+	spool.Refresh()
+	if !reflect.DeepEqual(resumed, [][]string{{"%1"}}) {
+		t.Fatalf("OnResume calls = %v", resumed)
+	}
+	// Output after the resume still goes to the spill until it is taken.
+	spool.Write("%1", []byte("|chunk-3"))
+	spool.Refresh()
+	if len(resumed) != 1 {
+		t.Fatalf("OnResume called again without a new absence: %v", resumed)
+	}
+
+	data, ok := spool.Take("%1")
+	if !ok || string(data) != "history|chunk-1|chunk-2|chunk-3" {
+		t.Fatalf("Take() = %q, %v", data, ok)
+	}
+	if _, ok := spool.Take("%1"); ok {
+		t.Fatal("Take() should delete the spill")
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) != 0 {
+		t.Fatalf("spill files left after Take: %v, %v", entries, err)
+	}
+	spool.Write("%1", []byte("live"))
+	if got := spool.Pending(); len(got) != 0 {
+		t.Fatalf("Pending() after Take while attended = %v", got)
+	}
+}
+
+func TestSpoolDropsOldestOutputBeyondLimit(t *testing.T) {
+	dir := t.TempDir()
+	spool := NewSpool(Deps{
+		Attended:        func() bool { return false },
+		Dir:             dir,
+		MaxBytesPerPane: 20,
+	})
+	t.Cleanup(spool.Close)
+
+	for _, chunk := range []string{"aaaa", "bbbb", "cccc", "dddd", "eeee", "ffff"} {
+		spool.Write("%2", []byte(chunk))
+	}
+	data, ok := spool.Take("%2")
+	if !ok {
+		t.Fatal("Take() found no spill")
+	}
+	got := string(data)
+	if !strings.HasSuffix(got, "eeeeffff") || strings.Contains(got, "aaaa") || len(got) > 20 {
+		t.Fatalf("Take() = %q, want the newest output within 20 bytes", got)
+	}
+}
+
+func TestSpoolRetainAndClose(t *testing.T) {
+	dir := t.TempDir() + "/spill"
+	spool := NewSpool(Deps{Attended: func() bool { return false }, Dir: dir})
+
+	spool.Write("%1", []byte("one"))
+	spool.Write("%2", []byte("two"))
+	spool.Retain(map[string]struct{}{"%2": {}})
+	if got := spool.Pending(); !reflect.DeepEqual(got, []string{"%2"}) {
+		t.Fatalf("Pending() after Retain = %v", got)
+	}
+	spool.Remove("%2")
+	spool.Write("%3", []byte("three"))
+
+	spool.Close()
+	spool.Close()
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Fatalf("spill directory left after Close: %v", err)
+	}
+	spool.Write("%3", []byte("after close"))
+	if got := spool.Pending(); len(got) != 0 {
+		t.Fatalf("Pending() after Close = %v", got)
+	}
+}
@@ -0,0 +1,98 @@
+package outputwatch
+
+type lineScanState uint8
+
+const (
+	lineScanGround lineScanState = iota
+	// lineScanEscape follows an ESC.
+	lineScanEscape
+	// lineScanCSI is inside ESC [ ... (control sequence).
+	lineScanCSI
+	// lineScanString is inside an OSC, DCS, SOS, PM, or APC string.
+	lineScanString
+	// lineScanStringEscape follows an ESC inside a string (possible ST).
+	lineScanStringEscape
+)
+
+// lineBuffer assembles the printable text of pane output into lines.
+//
+// Escape sequences and control characters are dropped. A CR that is not part
+// of CRLF starts the line over, approximating a terminal overwriting the line
+// (progress bars, spinners). Text is matched only once its line ends, so a
+// prompt without a trailing newline is not matched.
+type lineBuffer struct {
+	state     lineScanState
+	pendingCR bool
+	line      []byte
+}
+
+// feed appends chunk and returns the lines it completed.
+func (b *lineBuffer) feed(chunk []byte) [][]byte {
+	var lines [][]byte
+	for _, c := range chunk {
+		switch b.state {
+		case lineScanGround:
+			if b.pendingCR && c != '\n' {
+				b.line = b.line[:0]
+			}
+			b.pendingCR = false
+			switch {
+			case c == 0x1b:
+				b.state = lineScanEscape
+			case c == '\n':
+				lines = append(lines, append([]byte(nil), b.line...))
+				b.line = b.line[:0]
+			case c == '\r':
+				b.pendingCR = true
+			case c == '\t':
+				b.appendByte(' ')
+			case c < 0x20 || c == 0x7f:
+				// Other control characters carry no text.
+			default:
+				b.appendByte(c)
+			}
+		case lineScanEscape:
+			switch c {
+			case '[':
+				b.state = lineScanCSI
+			case ']', 'P', 'X', '^', '_':
+				b.state = lineScanString
+			case 0x1b:
+				// Stay in escape state for a repeated ESC.
+			default:
+				b.state = lineScanGround
+			}
+		case lineScanCSI:
+			if c >= 0x40 && c <= 0x7e {
+				b.state = lineScanGround
+			}
+		case lineScanString:
+			if c == 0x07 {
+				b.state = lineScanGround
+			} else if c == 0x1b {
+				b.state = lineScanStringEscape
+			}
+		case lineScanStringEscape:
+			switch c {
+			case '\\', 0x07:
+				b.state = lineScanGround
+			case 0x1b:
+				// Stay: the next byte decides.
+			default:
+				b.state = lineScanString
+			}
+		}
+	}
+	return lines
+}
+
+func (b *lineBuffer) appendByte(c byte) {
+	if len(b.line) < maxLineBytes {
+		b.line = append(b.line, c)
+	}
+}
+
+// empty reports whether the buffer holds no state worth keeping.
+func (b *lineBuffer) empty() bool {
+	return b.state == lineScanGround && !b.pendingCR && len(b.line) == 0
+}
@@ -0,0 +1,53 @@
+package outputwatch
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func feedAll(b *lineBuffer, chunks ...string) []string {
+	var got []string
+	for _, chunk := range chunks {
+		for _, line := range b.feed([]byte(chunk)) {
+			got = append(got, string(line))
+		}
+	}
+	return got
+}
+
+func TestLineBufferFeed(t *testing.T) {
+	tests := []struct {
+		name   string
+		chunks []string
+		want   []string
+	}{
+		{name: "plain lines", chunks: []string{"one\ntwo\r\nthree"}, want: []string{"one", "two"}},
+		{name: "line split across chunks", chunks: []string{"FA", "TAL err", "or\r", "\n"}, want: []string{"FATAL error"}},
+		{name: "colors removed", chunks: []string{"\x1b[1;31mFATAL\x1b[0m: boom\n"}, want: []string{"FATAL: boom"}},
+		{name: "escape split across chunks", chunks: []string{"ok \x1b[3", "2mdone\n"}, want: []string{"ok done"}},
+		{name: "osc title removed", chunks: []string{"\x1b]0;title\x07ready\n", "\x1b]2;x\x1b\\go\n"}, want: []string{"ready", "go"}},
+		{name: "carriage return overwrites", chunks: []string{"10%\r50%\r100% done\n"}, want: []string{"100% done"}},
+		{name: "tabs and controls", chunks: []string{"a\tb\x08c\n"}, want: []string{"a bc"}},
+		{name: "blank line", chunks: []string{"\n"}, want: []string{""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b lineBuffer
+			if got := feedAll(&b, tt.chunks...); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("lines = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLineBufferCapsLongLines(t *testing.T) {
+	var b lineBuffer
+	got := feedAll(&b, strings.Repeat("x", maxLineBytes+100)+"\n")
+	if len(got) != 1 || len(got[0]) != maxLineBytes {
+		t.Fatalf("line length = %d, want %d", len(got[0]), maxLineBytes)
+	}
+	if !b.empty() {
+		t.Fatal("buffer should be empty after the line ended")
+	}
+}
@@ -0,0 +1,232 @@
+// Package outputwatch evaluates user-defined watch expressions against pane
+// output. A watch pairs a regular expression with an action (notify, mark the
+// pane's session, or run a hook) and applies to one pane, one session, or all
+// panes. Output is matched line by line after escape sequences are removed.
+//
+// Watches live for the lifetime of the process; they are not persisted.
+package outputwatch
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Deps holds external dependencies injected at construction time.
+type Deps struct {
+	// SessionName returns the session of a pane. It is called only after a
+	// line matched a pattern, never for every chunk of output.
+	SessionName func(paneID string) (string, error)
+
+	// OnMatch is called for each match, outside internal locks.
+	OnMatch func(Match)
+
+	// Now returns the current time. Optional: defaults to time.Now.
+	Now func() time.Time
+
+	// Cooldown suppresses repeated matches of one watch in one pane.
+	// Optional: 0 means DefaultCooldown, a negative value disables it.
+	Cooldown time.Duration
+}
+
+type compiledWatch struct {
+	watch Watch
+	re    *regexp.Regexp
+}
+
+// firedKey identifies the cooldown of one watch in one pane.
+type firedKey struct {
+	watchID string
+	paneID  string
+}
+
+// Service holds the watches and the per-pane line state.
+//
+// Thread-safety: watches is copy-on-write so Scan reads it without locking
+// while no watch is defined; mu protects everything else.
+type Service struct {
+	deps    Deps
+	watches atomic.Pointer[[]*compiledWatch]
+
+	mu        sync.Mutex
+	buffers   map[string]*lineBuffer
+	lastFired map[firedKey]time.Time
+	nextID    uint64
+}
+
+// NewService creates an output watch service.
+// Panics if SessionName or OnMatch is nil.
+func NewService(deps Deps) *Service {
+	if deps.SessionName == nil || deps.OnMatch == nil {
+		panic("outputwatch.NewService: required function fields in Deps must be non-nil (SessionName, OnMatch)")
+	}
+	if deps.Now == nil {
+		deps.Now = time.Now
+	}
+	if deps.Cooldown == 0 {
+		deps.Cooldown = DefaultCooldown
+	}
+	return &Service{
+		deps:      deps,
+		buffers:   map[string]*lineBuffer{},
+		lastFired: map[firedKey]time.Time{},
+	}
+}
+
+// Add registers a watch. action is "notify", "mark", or "hook:<command>".
+func (s *Service) Add(target, pattern, action string) (Watch, error) {
+	re, err := compilePattern(pattern)
+	if err != nil {
+		return Watch{}, err
+	}
+	actionName, hook, err := ParseAction(action)
+	if err != nil {
+		return Watch{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	current := s.loadWatches()
+	if len(current) >= MaxWatches {
+		return Watch{}, fmt.Errorf("too many output watches (max %d)", MaxWatches)
+	}
+	s.nextID++
+	watch := Watch{
+		ID:        "watch-" + strconv.FormatUint(s.nextID, 10),
+		Target:    normalizeTarget(target),
+		Pattern:   pattern,
+		Action:    actionName,
+		Hook:      hook,
+		CreatedAt: s.deps.Now(),
+	}
+	next := append(slices.Clone(current), &compiledWatch{watch: watch, re: re})
+	s.watches.Store(&next)
+	slog.Debug("[DEBUG-OUTPUT-WATCH] watch added", "id", watch.ID, "target", watch.Target, "action", watch.Action)
+	return watch, nil
+}
+
+// Remove deletes the watch with id.
+func (s *Service) Remove(id string) error {
+	id = strings.TrimSpace(id)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	current := s.loadWatches()
+	index := slices.IndexFunc(current, func(cw *compiledWatch) bool { return cw.watch.ID == id })
+	if index < 0 {
+		return fmt.Errorf("output watch %q not found", id)
+	}
+	next := slices.Delete(slices.Clone(current), index, index+1)
+	s.watches.Store(&next)
+	for key := range s.lastFired {
+		if key.watchID == id {
+			delete(s.lastFired, key)
+		}
+	}
+	if len(next) == 0 {
+		// Nothing scans output any more; partial lines would only go stale.
+		clear(s.buffers)
+	}
+	return nil
+}
+
+// List returns the watches in creation order.
+func (s *Service) List() []Watch {
+	current := s.loadWatches()
+	watches := make([]Watch, 0, len(current))
+	for _, cw := range current {
+		watches = append(watches, cw.watch)
+	}
+	return watches
+}
+
+// Scan feeds flushed output of a pane and reports the lines it completes
+// that match a watch. It returns at once while no watch is defined.
+func (s *Service) Scan(paneID string, chunk []byte) {
+	watches := s.loadWatches()
+	if len(watches) == 0 || len(chunk) == 0 {
+		return
+	}
+
+	s.mu.Lock()
+	buf := s.buffers[paneID]
+	if buf == nil {
+		buf = &lineBuffer{}
+		s.buffers[paneID] = buf
+	}
+	lines := buf.feed(chunk)
+	if buf.empty() {
+		delete(s.buffers, paneID)
+	}
+	s.mu.Unlock()
+
+	type candidate struct {
+		watch Watch
+		line  []byte
+	}
+	var candidates []candidate
+	for _, line := range lines {
+		for _, cw := range watches {
+			if cw.re.Match(line) {
+				candidates = append(candidates, candidate{watch: cw.watch, line: line})
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return
+	}
+
+	sessionName, err := s.deps.SessionName(paneID)
+	if err != nil {
+		slog.Debug("[DEBUG-OUTPUT-WATCH] session unavailable for matched pane", "paneID", paneID, "error", err)
+	}
+	now := s.deps.Now()
+	var matches []Match
+	s.mu.Lock()
+	for _, c := range candidates {
+		if !c.watch.MatchesTarget(paneID, sessionName) {
+			continue
+		}
+		key := firedKey{watchID: c.watch.ID, paneID: paneID}
+		if last, ok := s.lastFired[key]; ok && s.deps.Cooldown > 0 && now.Sub(last) < s.deps.Cooldown {
+			continue
+		}
+		s.lastFired[key] = now
+		matches = append(matches, Match{Watch: c.watch, PaneID: paneID, SessionName: sessionName, Line: string(c.line)})
+	}
+	s.mu.Unlock()
+
+	for _, m := range matches {
+		slog.Debug("[DEBUG-OUTPUT-WATCH] watch matched", "id", m.Watch.ID, "paneID", paneID, "action", m.Watch.Action)
+		s.deps.OnMatch(m)
+	}
+}
+
+// Forget drops line state and cooldowns of removed panes.
+func (s *Service) Forget(paneIDs []string) {
+	if len(paneIDs) == 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, paneID := range paneIDs {
+		delete(s.buffers, paneID)
+		for key := range s.lastFired {
+			if key.paneID == paneID {
+				delete(s.lastFired, key)
+			}
+		}
+	}
+}
+
+func (s *Service) loadWatches() []*compiledWatch {
+	if watches := s.watches.Load(); watches != nil {
+		return *watches
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package outputwatch
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+type watchHarness struct {
+	svc      *Service
+	matches  []Match
+	now      time.Time
+	sessions map[string]string
+	lookups  int
+}
+
+func newWatchHarness(t *testing.T) *watchHarness {
+	t.Helper()
+	h := &watchHarness{
+		now:      time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		sessions: map[string]string{"%1": "agents", "%2": "agents", "%3": "web"},
+	}
+	h.svc = NewService(Deps{
+		SessionName: func(paneID string) (string, error) {
+			h.lookups++
+			name, ok := h.sessions[paneID]
+			if !ok {
+				return "", errors.New("pane not found")
+			}
+			return name, nil
+		},
+		OnMatch: func(m Match) { h.matches = append(h.matches, m) },
+		Now:     func() time.Time { return h.now },
+	})
+	return h
+}
+
+func TestNewServicePanicsWithoutRequiredDeps(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("NewService() did not panic")
+		}
+	}()
+	NewService(Deps{SessionName: func(string) (string, error) { return "", nil }})
+}
+
+func TestServiceAddListRemove(t *testing.T) {
+	h := newWatchHarness(t)
+
+	first, err := h.svc.Add("", "FATAL", "notify")
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if first.Target != TargetAll || first.Action != ActionNotify || first.ID == "" {
+		t.Fatalf("Add() = %+v", first)
+	}
+	second, err := h.svc.Add("%1", "done", "hook:echo ok")
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if second.Action != ActionHook || second.Hook != "echo ok" || second.ID == first.ID {
+		t.Fatalf("Add() = %+v", second)
+	}
+	if _, err := h.svc.Add("%1", "(", "notify"); err == nil {
+		t.Fatal("Add() with invalid pattern error = nil")
+	}
+	if _, err := h.svc.Add("%1", "x", "shout"); err == nil {
+		t.Fatal("Add() with invalid action error = nil")
+	}
+
+	if got := h.svc.List(); len(got) != 2 || got[0].ID != first.ID || got[1].ID != second.ID {
+		t.Fatalf("List() = %+v", got)
+	}
+	if err := h.svc.Remove(first.ID); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if err := h.svc.Remove(first.ID); err == nil {
+		t.Fatal("Remove() of a removed watch error = nil")
+	}
+	if got := h.svc.List(); len(got) != 1 || got[0].ID != second.ID {
+		t.Fatalf("List() after Remove = %+v", got)
+	}
+}
+
+func TestServiceAddLimit(t *testing.T) {
+	h := newWatchHarness(t)
+	for range MaxWatches {
+		if _, err := h.svc.Add("", "x", "notify"); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+	if _, err := h.svc.Add("", "x", "notify"); err == nil {
+		t.Fatal("Add() beyond MaxWatches error = nil")
+	}
+}
+
+func TestServiceScanTargets(t *testing.T) {
+	h := newWatchHarness(t)
+	all, _ := h.svc.Add(TargetAll, "FATAL", "notify")
+	session, _ := h.svc.Add("agents", `All tests passed`, "mark")
+	pane, _ := h.svc.Add("%3", "listening on", "notify")
+
+	h.svc.Scan("%1", []byte("\x1b[31mFATAL\x1b[0m crash\nAll tests passed\n"))
+	h.svc.Scan("%3", []byte("All tests passed\nlistening on :8080\n"))
+	h.svc.Scan("%2", []byte("listening on :9090\n"))
+
+	want := []struct {
+		id, pane, line string
+	}{
+		{all.ID, "%1", "FATAL crash"},
+		{session.ID, "%1", "All tests passed"},
+		{pane.ID, "%3", "listening on :8080"},
+	}
+	if len(h.matches) != len(want) {
+		t.Fatalf("matches = %+v, want %d matches", h.matches, len(want))
+	}
+	for i, w := range want {
+		m := h.matches[i]
+		if m.Watch.ID != w.id || m.PaneID != w.pane || m.Line != w.line {
+			t.Fatalf("match[%d] = %+v, want %+v", i, m, w)
+		}
+	}
+	if h.matches[0].SessionName != "agents" {
+		t.Fatalf("SessionName = %q, want agents", h.matches[0].SessionName)
+	}
+}
+
+func TestServiceScanCooldown(t *testing.T) {
+	h := newWatchHarness(t)
+	if _, err := h.svc.Add("", "ERROR", "notify"); err != nil {
+		t.Fatal(err)
+	}
+
+	h.svc.Scan("%1", []byte("ERROR a\nERROR b\n"))
+	h.svc.Scan("%2", []byte("ERROR c\n"))
+	if len(h.matches) != 2 {
+		t.Fatalf("matches = %d, want one per pane", len(h.matches))
+	}
+
+	h.now = h.now.Add(DefaultCooldown)
+	h.svc.Scan("%1", []byte("ERROR d\n"))
+	if len(h.matches) != 3 || h.matches[2].Line != "ERROR d" {
+		t.Fatalf("matches after cooldown = %+v", h.matches)
+	}
+}
+
+func TestServiceScanWithoutWatchesKeepsNoState(t *testing.T) {
+	h := newWatchHarness(t)
+	h.svc.Scan("%1", []byte("partial"))
+	if len(h.svc.buffers) != 0 || h.lookups != 0 {
+		t.Fatalf("buffers = %d, lookups = %d, want none", len(h.svc.buffers), h.lookups)
+	}
+
+	watch, _ := h.svc.Add("", "zzz", "notify")
+	h.svc.Scan("%1", []byte("partial"))
+	h.svc.Scan("%2", []byte("no match\n"))
+	if len(h.svc.buffers) != 1 || h.lookups != 0 {
+		t.Fatalf("buffers = %d, lookups = %d, want 1 and 0", len(h.svc.buffers), h.lookups)
+	}
+
+	h.svc.Forget([]string{"%1"})
+	if len(h.svc.buffers) != 0 {
+		t.Fatalf("buffers after Forget = %d, want 0", len(h.svc.buffers))
+	}
+	h.svc.Scan("%1", []byte("partial"))
+	if err := h.svc.Remove(watch.ID); err != nil {
+		t.Fatal(err)
+	}
+	if len(h.svc.buffers) != 0 {
+		t.Fatalf("buffers after removing the last watch = %d, want 0", len(h.svc.buffers))
+	}
+}
+
+func TestServiceScanUnknownPaneMatchesOnlyAllTarget(t *testing.T) {
+	h := newWatchHarness(t)
+	h.svc.Add("agents", "boom", "notify")
+	all, _ := h.svc.Add("", "boom", "notify")
+
+	h.svc.Scan("%9", []byte("boom\n"))
+	if len(h.matches) != 1 || h.matches[0].Watch.ID != all.ID || h.matches[0].SessionName != "" {
+		t.Fatalf("matches = %+v", h.matches)
+	}
+}
@@ -0,0 +1,154 @@
+package outputwatch
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// ActionNotify shows a notification in the frontend.
+	ActionNotify = "notify"
+	// ActionMark records a bell on the pane's session so it needs attention.
+	ActionMark = "mark"
+	// ActionHook runs a command through cmd.exe. It is written as
+	// "hook:<command>" when adding a watch.
+	ActionHook = "hook"
+
+	// TargetAll matches every pane. An empty target means the same.
+	TargetAll = "*"
+
+	// MatchedEvent carries a MatchEvent each time a watch matches a line.
+	MatchedEvent = "output-watch:matched"
+
+	// MaxWatches bounds the number of watches evaluated on pane output.
+	MaxWatches = 64
+	// MaxPatternLength bounds a watch pattern in bytes.
+	MaxPatternLength = 512
+
+	// DefaultCooldown suppresses repeated matches of one watch in one pane,
+	// so a log storm yields one alert.
+	DefaultCooldown = 10 * time.Second
+
+	// maxLineBytes bounds the bytes kept for one output line; the rest of a
+	// longer line is not matched.
+	maxLineBytes = 4096
+)
+
+// Watch is a user-defined pattern evaluated against pane output lines.
+type Watch struct {
+	ID string `json:"id"`
+	// Target is a pane ID ("%3"), a session name, or TargetAll.
+	Target  string `json:"target"`
+	Pattern string `json:"pattern"`
+	// Action is ActionNotify, ActionMark, or ActionHook.
+	Action string `json:"action"`
+	// Hook is the command run by ActionHook.
+	Hook      string    `json:"hook,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// MatchesTarget reports whether the watch applies to a pane of a session.
+func (w Watch) MatchesTarget(paneID, sessionName string) bool {
+	switch {
+	case w.Target == TargetAll:
+		return true
+	case isPaneTarget(w.Target):
+		return w.Target == paneID
+	default:
+		return w.Target == sessionName
+	}
+}
+
+// Match is one output line that matched a watch.
+type Match struct {
+	Watch       Watch
+	PaneID      string
+	SessionName string
+	Line        string
+}
+
+// MatchEvent is the MatchedEvent payload.
+type MatchEvent struct {
+	WatchID     string `json:"watch_id"`
+	PaneID      string `json:"pane_id"`
+	SessionName string `json:"session_name"`
+	Pattern     string `json:"pattern"`
+	Action      string `json:"action"`
+	Line        string `json:"line"`
+}
+
+// NewMatchEvent returns the MatchedEvent payload for m.
+func NewMatchEvent(m Match) MatchEvent {
+	return MatchEvent{
+		WatchID:     m.Watch.ID,
+		PaneID:      m.PaneID,
+		SessionName: m.SessionName,
+		Pattern:     m.Watch.Pattern,
+		Action:      m.Watch.Action,
+		Line:        m.Line,
+	}
+}
+
+// ParseAction splits an action written as "notify", "mark", or
+// "hook:<command>" into the action and the hook command.
+func ParseAction(action string) (string, string, error) {
+	action = strings.TrimSpace(action)
+	if name, hook, ok := strings.Cut(action, ":"); ok && strings.EqualFold(strings.TrimSpace(name), ActionHook) {
+		hook = strings.TrimSpace(hook)
+		if hook == "" {
+			return "", "", errors.New("hook action requires a command (hook:<command>)")
+		}
+		return ActionHook, hook, nil
+	}
+	switch strings.ToLower(action) {
+	case ActionNotify:
+		return ActionNotify, "", nil
+	case ActionMark:
+		return ActionMark, "", nil
+	case ActionHook:
+		return "", "", errors.New("hook action requires a command (hook:<command>)")
+	default:
+		return "", "", fmt.Errorf("unknown watch action %q (want notify, mark, or hook:<command>)", action)
+	}
+}
+
+// normalizeTarget trims target and maps an empty target to TargetAll.
+func normalizeTarget(target string) string {
+	target = strings.TrimSpace(target)
+	if target == "" {
+		return TargetAll
+	}
+	return target
+}
+
+// compilePattern validates and compiles a watch pattern.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if strings.TrimSpace(pattern) == "" {
+		return nil, errors.New("watch pattern is required")
+	}
+	if len(pattern) > MaxPatternLength {
+		return nil, fmt.Errorf("watch pattern exceeds %d bytes", MaxPatternLength)
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid watch pattern: %w", err)
+	}
+	return re, nil
+}
+
+// isPaneTarget reports whether target names a pane ("%<n>").
+func isPaneTarget(target string) bool {
+	digits, ok := strings.CutPrefix(target, "%")
+	if !ok || digits == "" {
+		return false
+	}
+	for _, c := range digits {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,66 @@
+package outputwatch
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseAction(t *testing.T) {
+	tests := []struct {
+		in       string
+		wantName string
+		wantHook string
+		wantErr  bool
+	}{
+		{in: "notify", wantName: ActionNotify},
+		{in: " Mark ", wantName: ActionMark},
+		{in: "hook:echo done", wantName: ActionHook, wantHook: "echo done"},
+		{in: "HOOK: run.cmd --flag=a:b", wantName: ActionHook, wantHook: "run.cmd --flag=a:b"},
+		{in: "hook", wantErr: true},
+		{in: "hook:  ", wantErr: true},
+		{in: "beep", wantErr: true},
+		{in: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		name, hook, err := ParseAction(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("ParseAction(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+		}
+		if name != tt.wantName || hook != tt.wantHook {
+			t.Fatalf("ParseAction(%q) = (%q, %q), want (%q, %q)", tt.in, name, hook, tt.wantName, tt.wantHook)
+		}
+	}
+}
+
+func TestWatchMatchesTarget(t *testing.T) {
+	tests := []struct {
+		target  string
+		paneID  string
+		session string
+		want    bool
+	}{
+		{target: TargetAll, paneID: "%1", session: "a", want: true},
+		{target: "%1", paneID: "%1", session: "a", want: true},
+		{target: "%1", paneID: "%12", session: "a", want: false},
+		{target: "a", paneID: "%1", session: "a", want: true},
+		{target: "a", paneID: "%1", session: "b", want: false},
+		// "%" alone is not a pane ID, so it names a session.
+		{target: "%", paneID: "%1", session: "%", want: true},
+	}
+	for _, tt := range tests {
+		if got := (Watch{Target: tt.target}).MatchesTarget(tt.paneID, tt.session); got != tt.want {
+			t.Errorf("Watch{Target: %q}.MatchesTarget(%q, %q) = %v, want %v", tt.target, tt.paneID, tt.session, got, tt.want)
+		}
+	}
+}
+
+func TestCompilePattern(t *testing.T) {
+	if _, err := compilePattern("FATAL|All tests passed"); err != nil {
+		t.Fatalf("compilePattern() error = %v", err)
+	}
+	for _, pattern := range []string{"", "  ", "(", strings.Repeat("a", MaxPatternLength+1)} {
+		if _, err := compilePattern(pattern); err == nil {
+			t.Errorf("compilePattern(%q) error = nil, want error", pattern)
+		}
+	}
+}
@@ -0,0 +1,249 @@
+// Package paneactivity classifies panes as busy, idle, or waiting for input,
+// so the UI can show at a glance which agents need attention. A pane is busy
+// while it produces output and idle once it has been quiet for a while. It
+// is waiting when its recent output ends in a prompt that asks for input and
+// its shell still runs a child process (the program that asked).
+package paneactivity
+
+import (
+	"cmp"
+	"context"
+	"log/slog"
+	"regexp"
+	"slices"
+	"sync"
+	"time"
+
+	"myT-x/internal/apptypes"
+)
+
+// Deps holds external dependencies injected at construction time.
+type Deps struct {
+	// Emitter sends ChangedEvent to the frontend.
+	// Optional: defaults to a no-op emitter if nil.
+	Emitter apptypes.RuntimeEventEmitter
+
+	// Panes returns every live pane.
+	Panes func() []Pane
+
+	// ChildProcesses reports, keyed by pane ID, whether the shell of each
+	// pane runs a child process. It returns nil when process state is
+	// unavailable. Optional: nil means process state is unavailable.
+	ChildProcesses func(panes []Pane) map[string]bool
+
+	// WaitingPatterns match prompts that ask for input.
+	// Optional: defaults to DefaultWaitingPatterns.
+	WaitingPatterns []*regexp.Regexp
+
+	// Now returns the current time. Optional: defaults to time.Now.
+	Now func() time.Time
+
+	// IdleAfter is the quiet period after which a pane is idle.
+	// Optional: defaults to DefaultIdleAfter.
+	IdleAfter time.Duration
+
+	// PollInterval is the period of Run. Optional: defaults to DefaultPollInterval.
+	PollInterval time.Duration
+
+	// Paused reports whether Run should skip polls, e.g. while the system
+	// sleeps. Optional: defaults to never paused.
+	Paused func() bool
+}
+
+// paneRecord is the tracked state of one pane.
+type paneRecord struct {
+	sessionName string
+	state       State
+	since       time.Time
+	lastOutput  time.Time
+	// childKnown is false while process state is unavailable.
+	childKnown bool
+	hasChild   bool
+	tail       tailBuffer
+}
+
+func (r *paneRecord) activity(paneID string) Activity {
+	return Activity{
+		PaneID:          paneID,
+		SessionName:     r.sessionName,
+		State:           r.state,
+		Since:           r.since,
+		HasChildProcess: r.hasChild,
+	}
+}
+
+// Service tracks the activity state of every pane.
+//
+// Thread-safety is managed internally via mu. No external locking is required.
+type Service struct {
+	deps Deps
+
+	mu    sync.Mutex
+	panes map[string]*paneRecord
+}
+
+// NewService creates a pane activity service.
+// Panics if Panes is nil.
+func NewService(deps Deps) *Service {
+	if deps.Panes == nil {
+		panic("paneactivity.NewService: Panes must be non-nil")
+	}
+	if deps.Emitter == nil {
+		deps.Emitter = apptypes.NoopEmitter{}
+	}
+	if deps.WaitingPatterns == nil {
+		deps.WaitingPatterns = DefaultWaitingPatterns
+	}
+	if deps.Now == nil {
+		deps.Now = time.Now
+	}
+	if deps.IdleAfter <= 0 {
+		deps.IdleAfter = DefaultIdleAfter
+	}
+	if deps.PollInterval <= 0 {
+		deps.PollInterval = DefaultPollInterval
+	}
+	if deps.Paused == nil {
+		deps.Paused = func() bool { return false }
+	}
+	return &Service{
+		deps:  deps,
+		panes: map[string]*paneRecord{},
+	}
+}
+
+// Observe feeds flushed output of a pane. A pane that starts producing
+// output becomes busy at once, or waiting when the output ends in a prompt.
+func (s *Service) Observe(paneID string, chunk []byte) {
+	if len(chunk) == 0 {
+		return
+	}
+	now := s.deps.Now()
+	s.mu.Lock()
+	rec := s.panes[paneID]
+	if rec == nil {
+		rec = &paneRecord{state: StateIdle, since: now}
+		s.panes[paneID] = rec
+	}
+	rec.lastOutput = now
+	rec.tail.feed(chunk)
+	change, changed := s.reclassifyLocked(paneID, rec, now)
+	s.mu.Unlock()
+
+	if changed {
+		s.emit(change)
+	}
+}
+
+// Run polls until ctx is cancelled, skipping ticks while paused.
+func (s *Service) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.deps.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if s.deps.Paused() {
+				continue
+			}
+			s.Poll()
+		}
+	}
+}
+
+// Poll refreshes the pane list and process state, moves quiet panes to idle,
+// and reports panes that went away as StateClosed.
+func (s *Service) Poll() {
+	panes := s.deps.Panes()
+	var children map[string]bool
+	if s.deps.ChildProcesses != nil && len(panes) > 0 {
+		children = s.deps.ChildProcesses(panes)
+	}
+	now := s.deps.Now()
+
+	var changes []Change
+	s.mu.Lock()
+	alive := make(map[string]struct{}, len(panes))
+	for _, pane := range panes {
+		alive[pane.PaneID] = struct{}{}
+		rec := s.panes[pane.PaneID]
+		if rec == nil {
+			rec = &paneRecord{state: StateIdle, since: now}
+			s.panes[pane.PaneID] = rec
+		}
+		rec.sessionName = pane.SessionName
+		rec.childKnown = children != nil
+		rec.hasChild = children[pane.PaneID]
+		if change, changed := s.reclassifyLocked(pane.PaneID, rec, now); changed {
+			changes = append(changes, change)
+		}
+	}
+	for paneID, rec := range s.panes {
+		if _, ok := alive[paneID]; ok {
+			continue
+		}
+		delete(s.panes, paneID)
+		changes = append(changes, Change{
+			Activity:      Activity{PaneID: paneID, SessionName: rec.sessionName, State: StateClosed, Since: now},
+			PreviousState: rec.state,
+		})
+	}
+	s.mu.Unlock()
+
+	slices.SortFunc(changes, func(a, b Change) int { return compareActivities(a.Activity, b.Activity) })
+	for _, change := range changes {
+		s.emit(change)
+	}
+}
+
+// Snapshot returns the activity of every tracked pane, ordered by session
+// name, then pane ID.
+func (s *Service) Snapshot() []Activity {
+	s.mu.Lock()
+	activities := make([]Activity, 0, len(s.panes))
+	for paneID, rec := range s.panes {
+		activities = append(activities, rec.activity(paneID))
+	}
+	s.mu.Unlock()
+	slices.SortFunc(activities, compareActivities)
+	return activities
+}
+
+// reclassifyLocked updates the state of rec and reports the change, if any.
+// REQUIRES: s.mu must be held by the caller.
+func (s *Service) reclassifyLocked(paneID string, rec *paneRecord, now time.Time) (Change, bool) {
+	next := s.classifyLocked(rec, now)
+	if next == rec.state {
+		return Change{}, false
+	}
+	previous := rec.state
+	rec.state = next
+	rec.since = now
+	return Change{Activity: rec.activity(paneID), PreviousState: previous}, true
+}
+
+// classifyLocked returns the state rec is in at now. A prompt left on the
+// screen by a program that has exited no longer waits for anything, so a
+// pane only waits while its shell runs a child process or while process
+// state is unavailable.
+// REQUIRES: s.mu must be held by the caller.
+func (s *Service) classifyLocked(rec *paneRecord, now time.Time) State {
+	if (!rec.childKnown || rec.hasChild) && rec.tail.matches(s.deps.WaitingPatterns) {
+		return StateWaiting
+	}
+	if !rec.lastOutput.IsZero() && now.Sub(rec.lastOutput) < s.deps.IdleAfter {
+		return StateBusy
+	}
+	return StateIdle
+}
+
+func (s *Service) emit(change Change) {
+	slog.Debug("[DEBUG-PANE-ACTIVITY] state changed",
+		"paneID", change.PaneID, "from", change.PreviousState, "to", change.State)
+	s.deps.Emitter.Emit(ChangedEvent, change)
+}
+
+func compareActivities(a, b Activity) int {
+	return cmp.Or(cmp.Compare(a.SessionName, b.SessionName), cmp.Compare(a.PaneID, b.PaneID))
+}
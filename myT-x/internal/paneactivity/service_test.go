@@ -0,0 +1,157 @@
+package paneactivity
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"myT-x/internal/apptypes"
+)
+
+type recordingEmitter struct {
+	mu      sync.Mutex
+	changes []Change
+}
+
+func (e *recordingEmitter) emit(name string, payload any) {
+	if name != ChangedEvent {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.changes = append(e.changes, payload.(Change))
+}
+
+// states returns "pane:state" for each recorded change and clears them.
+func (e *recordingEmitter) states() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var states []string
+	for _, change := range e.changes {
+		states = append(states, change.PaneID+":"+string(change.State))
+	}
+	e.changes = nil
+	return states
+}
+
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) Now() time.Time          { return c.now }
+func (c *fakeClock) advance(d time.Duration) { c.now = c.now.Add(d) }
+func newFakeClock() *fakeClock               { return &fakeClock{now: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)} }
+func onePane(sessionName, paneID string) []Pane {
+	return []Pane{{SessionName: sessionName, PaneID: paneID, PID: 10}}
+}
+
+func TestNewServicePanicsWithoutPanes(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic for missing Panes")
+		}
+	}()
+	NewService(Deps{})
+}
+
+func TestObserveAndPollMoveBetweenBusyAndIdle(t *testing.T) {
+	emitter := &recordingEmitter{}
+	clock := newFakeClock()
+	service := NewService(Deps{
+		Emitter: apptypes.EventEmitterFunc(emitter.emit),
+		Panes:   func() []Pane { return onePane("agent", "%1") },
+		Now:     clock.Now,
+	})
+
+	service.Poll()
+	if got := emitter.states(); got != nil {
+		t.Fatalf("new idle pane emitted %v", got)
+	}
+
+	service.Observe("%1", []byte("compiling...\n"))
+	service.Observe("%1", []byte("still compiling...\n"))
+	if got := emitter.states(); !slices.Equal(got, []string{"%1:busy"}) {
+		t.Fatalf("changes after output = %v, want [%%1:busy]", got)
+	}
+
+	clock.advance(DefaultIdleAfter / 2)
+	service.Poll()
+	clock.advance(DefaultIdleAfter)
+	service.Poll()
+	if got := emitter.states(); !slices.Equal(got, []string{"%1:idle"}) {
+		t.Fatalf("changes after quiet period = %v, want [%%1:idle]", got)
+	}
+	activities := service.Snapshot()
+	if len(activities) != 1 || activities[0].SessionName != "agent" || activities[0].State != StateIdle || !activities[0].Since.Equal(clock.now) {
+		t.Fatalf("Snapshot() = %+v", activities)
+	}
+}
+
+func TestObserveDetectsPromptWaitingForInput(t *testing.T) {
+	emitter := &recordingEmitter{}
+	clock := newFakeClock()
+	hasChild := true
+	service := NewService(Deps{
+		Emitter:        apptypes.EventEmitterFunc(emitter.emit),
+		Panes:          func() []Pane { return onePane("agent", "%1") },
+		ChildProcesses: func([]Pane) map[string]bool { return map[string]bool{"%1": hasChild} },
+		Now:            clock.Now,
+	})
+	service.Poll()
+
+	// A full-screen dialog drawn with cursor movements instead of newlines.
+	service.Observe("%1", []byte("\x1b[5;1H\x1b[1mDo you want to proceed?\x1b[0m\x1b[6;1H\xe2\x9d\xaf 1. Yes\x1b[7;1H  2. No"))
+	if got := emitter.states(); !slices.Equal(got, []string{"%1:waiting"}) {
+		t.Fatalf("changes after prompt = %v, want [%%1:waiting]", got)
+	}
+	// Waiting outlasts the quiet period.
+	clock.advance(2 * DefaultIdleAfter)
+	service.Poll()
+	if got := emitter.states(); got != nil {
+		t.Fatalf("waiting pane changed to %v while quiet", got)
+	}
+
+	// The program that asked exited: the prompt left on screen waits for nothing.
+	hasChild = false
+	service.Poll()
+	if got := emitter.states(); !slices.Equal(got, []string{"%1:idle"}) {
+		t.Fatalf("changes after child exit = %v, want [%%1:idle]", got)
+	}
+}
+
+func TestObservePromptScrollsOutOfTail(t *testing.T) {
+	emitter := &recordingEmitter{}
+	service := NewService(Deps{
+		Emitter: apptypes.EventEmitterFunc(emitter.emit),
+		Panes:   func() []Pane { return onePane("agent", "%1") },
+	})
+
+	service.Observe("%1", []byte("Overwrite file? (y/n) "))
+	service.Observe("%1", []byte("y\n"))
+	for range tailLines {
+		service.Observe("%1", []byte("copying\n"))
+	}
+	if got := emitter.states(); !slices.Equal(got, []string{"%1:waiting", "%1:busy"}) {
+		t.Fatalf("changes = %v, want [%%1:waiting %%1:busy]", got)
+	}
+}
+
+func TestPollReportsClosedPanes(t *testing.T) {
+	emitter := &recordingEmitter{}
+	panes := onePane("agent", "%1")
+	service := NewService(Deps{
+		Emitter: apptypes.EventEmitterFunc(emitter.emit),
+		Panes:   func() []Pane { return panes },
+	})
+	service.Observe("%1", []byte("output\n"))
+	service.Poll()
+	emitter.states()
+
+	panes = nil
+	service.Poll()
+	if got := emitter.states(); !slices.Equal(got, []string{"%1:closed"}) {
+		t.Fatalf("changes after pane removal = %v, want [%%1:closed]", got)
+	}
+	if activities := service.Snapshot(); len(activities) != 0 {
+		t.Fatalf("Snapshot() after removal = %+v, want empty", activities)
+	}
+}
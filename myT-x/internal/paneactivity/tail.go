@@ -0,0 +1,139 @@
+package paneactivity
+
+import (
+	"bytes"
+	"regexp"
+)
+
+const (
+	// tailLines is the number of recent non-blank lines kept per pane.
+	tailLines = 8
+	// maxTailLineBytes bounds one kept line; the rest of a longer line is
+	// dropped.
+	maxTailLineBytes = 512
+)
+
+type tailScanState uint8
+
+const (
+	tailGround tailScanState = iota
+	// tailEscape follows an ESC.
+	tailEscape
+	// tailCSI is inside ESC [ ... (control sequence).
+	tailCSI
+	// tailString is inside an OSC, DCS, SOS, PM, or APC string.
+	tailString
+	// tailStringEscape follows an ESC inside a string (possible ST).
+	tailStringEscape
+)
+
+// tailBuffer keeps the printable text of the last lines of pane output.
+//
+// Escape sequences and control characters are dropped. Full-screen programs
+// such as agent CLIs position the cursor instead of writing newlines, so a
+// cursor movement also ends the line. A CR that is not part of CRLF starts
+// the line over (spinners, progress bars). Blank lines are not kept.
+type tailBuffer struct {
+	state     tailScanState
+	pendingCR bool
+	lines     [][]byte
+	line      []byte
+}
+
+func (b *tailBuffer) feed(chunk []byte) {
+	for _, c := range chunk {
+		switch b.state {
+		case tailGround:
+			if b.pendingCR && c != '\n' {
+				b.line = b.line[:0]
+			}
+			b.pendingCR = false
+			switch {
+			case c == 0x1b:
+				b.state = tailEscape
+			case c == '\n':
+				b.endLine()
+			case c == '\r':
+				b.pendingCR = true
+			case c == '\t':
+				b.appendByte(' ')
+			case c < 0x20 || c == 0x7f:
+				// Other control characters carry no text.
+			default:
+				b.appendByte(c)
+			}
+		case tailEscape:
+			switch c {
+			case '[':
+				b.state = tailCSI
+			case ']', 'P', 'X', '^', '_':
+				b.state = tailString
+			case 0x1b:
+				// Stay in escape state for a repeated ESC.
+			default:
+				b.state = tailGround
+			}
+		case tailCSI:
+			if c >= 0x40 && c <= 0x7e {
+				b.state = tailGround
+				switch c {
+				case 'A', 'B', 'E', 'F', 'H', 'd', 'f':
+					// Cursor movement between rows.
+					b.endLine()
+				}
+			}
+		case tailString:
+			if c == 0x07 {
+				b.state = tailGround
+			} else if c == 0x1b {
+				b.state = tailStringEscape
+			}
+		case tailStringEscape:
+			switch c {
+			case '\\', 0x07:
+				b.state = tailGround
+			case 0x1b:
+				// Stay: the next byte decides.
+			default:
+				b.state = tailString
+			}
+		}
+	}
+}
+
+func (b *tailBuffer) appendByte(c byte) {
+	if len(b.line) < maxTailLineBytes {
+		b.line = append(b.line, c)
+	}
+}
+
+// endLine keeps the current line unless it is blank.
+func (b *tailBuffer) endLine() {
+	line := bytes.TrimSpace(b.line)
+	b.line = b.line[:0]
+	if len(line) == 0 {
+		return
+	}
+	if len(b.lines) == tailLines {
+		copy(b.lines, b.lines[1:])
+		b.lines = b.lines[:tailLines-1]
+	}
+	b.lines = append(b.lines, bytes.Clone(line))
+}
+
+// matches reports whether a kept line or the unfinished line matches one of
+// patterns. The unfinished line matters because prompts rarely end in a
+// newline.
+func (b *tailBuffer) matches(patterns []*regexp.Regexp) bool {
+	for _, re := range patterns {
+		if len(b.line) > 0 && re.Match(b.line) {
+			return true
+		}
+		for _, line := range b.lines {
+			if re.Match(line) {
+				return true
+			}
+		}
+	}
+	return false
+}
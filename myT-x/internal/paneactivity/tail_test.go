@@ -0,0 +1,29 @@
+package paneactivity
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTailBufferKeepsRecentNonBlankLines(t *testing.T) {
+	var b tailBuffer
+	b.feed([]byte("first\n\n   \n\x1b[32msecond\x1b[0m\r\n"))
+	b.feed([]byte("\x1b]0;title\x07progress 10%\rprogress 90%"))
+	b.feed([]byte("\x1b[3;1Hthird"))
+
+	var got []string
+	for _, line := range b.lines {
+		got = append(got, string(line))
+	}
+	if strings.Join(got, "|") != "first|second|progress 90%" || string(b.line) != "third" {
+		t.Fatalf("lines = %q, current = %q", got, b.line)
+	}
+
+	b.feed([]byte("\n"))
+	for range tailLines - 1 {
+		b.feed([]byte("filler\n"))
+	}
+	if len(b.lines) != tailLines || string(b.lines[0]) != "third" {
+		t.Fatalf("lines after overflow = %q", b.lines)
+	}
+}
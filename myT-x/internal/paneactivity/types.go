@@ -0,0 +1,66 @@
+package paneactivity
+
+import (
+	"regexp"
+	"time"
+)
+
+// State classifies what a pane is doing.
+type State string
+
+const (
+	// StateBusy means the pane produced output within Deps.IdleAfter.
+	StateBusy State = "busy"
+	// StateIdle means the pane has been quiet for Deps.IdleAfter.
+	StateIdle State = "idle"
+	// StateWaiting means the recent output of the pane ends in a prompt
+	// asking for input, e.g. an agent permission dialog or "(y/n)".
+	StateWaiting State = "waiting"
+	// StateClosed is reported once when a pane goes away.
+	StateClosed State = "closed"
+)
+
+const (
+	// ChangedEvent carries a Change each time the state of a pane changes.
+	ChangedEvent = "pane:activity-changed"
+
+	// DefaultIdleAfter is the quiet period after which a pane is idle.
+	DefaultIdleAfter = 3 * time.Second
+	// DefaultPollInterval is the period of Run.
+	DefaultPollInterval = time.Second
+)
+
+// DefaultWaitingPatterns match prompts that ask for input. They are matched
+// against each of the last lines of pane output with escape sequences removed.
+var DefaultWaitingPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)[(\[](y/n|yes/no)[)\]]`),
+	regexp.MustCompile(`(?i)do you want to (proceed|continue|make this edit|create|run|allow)`),
+	regexp.MustCompile(`(?i)press enter to (continue|confirm)`),
+	regexp.MustCompile(`(?i)waiting for (your )?(input|approval|confirmation)`),
+	regexp.MustCompile(`❯\s*1\.\s*Yes`),
+}
+
+// Pane is one live pane and its shell process.
+type Pane struct {
+	SessionName string
+	PaneID      string
+	PID         int
+}
+
+// Activity is the current state of one pane.
+type Activity struct {
+	PaneID      string `json:"pane_id"`
+	SessionName string `json:"session_name"`
+	State       State  `json:"state"`
+	// Since is when the pane entered State.
+	Since time.Time `json:"since"`
+	// HasChildProcess reports whether the pane shell runs a child process,
+	// e.g. an agent CLI. It is false when process state is unavailable.
+	HasChildProcess bool `json:"has_child_process"`
+}
+
+// Change is the ChangedEvent payload.
+type Change struct {
+	Activity
+	PreviousState State `json:"previous_state"`
+}
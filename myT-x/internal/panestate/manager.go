@@ -284,6 +284,43 @@ func (m *Manager) Snapshot(paneID string) string {
 	return m.replayStringLocked(state)
 }
 
+// Screen is a row-oriented copy of a pane's emulated viewport.
+type Screen struct {
+	Cols  int
+	Rows  int
+	Lines []string
+}
+
+// Screen returns the emulated viewport of a pane, row by row.
+// Unlike Snapshot, inactive panes are emulated on demand from the replay ring
+// so that callers always get a screen model rather than raw bytes.
+func (m *Manager) Screen(paneID string) (Screen, bool) {
+	paneID = strings.TrimSpace(paneID)
+	if paneID == "" {
+		return Screen{}, false
+	}
+
+	m.mu.RLock()
+	state := m.states[paneID]
+	m.mu.RUnlock()
+
+	if state == nil {
+		return Screen{}, false
+	}
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	if state.dirty {
+		// Inactive panes are marked dirty again by their next Feed, so a
+		// polling caller rebuilds at most once per poll while output flows.
+		m.rebuildTerminal(state)
+		state.dirty = false
+	}
+	cols, rows := state.terminal.Size()
+	return Screen{Cols: cols, Rows: rows, Lines: state.terminal.Lines()}, true
+}
+
 // Replay returns bounded recent replay-ring bytes for a pane.
 // Unlike Snapshot, Replay never substitutes the live emulator viewport. The
 // returned data is best-effort history and may be truncated at arbitrary byte
@@ -394,3 +394,24 @@ func BenchmarkManagerConcurrentFeed(b *testing.B) {
 		wg.Wait()
 	}
 }
+
+func TestManagerScreenEmulatesInactivePanes(t *testing.T) {
+	manager := NewManager(1024)
+	manager.EnsurePane("%0", 20, 3)
+	manager.Feed("%0", []byte("first\r\nsecond"))
+
+	screen, ok := manager.Screen("%0")
+	if !ok {
+		t.Fatal("Screen() ok = false, want true")
+	}
+	if screen.Cols != 20 || screen.Rows != 3 || len(screen.Lines) != 3 {
+		t.Fatalf("Screen() = %+v, want 20x3 with 3 lines", screen)
+	}
+	if screen.Lines[0] != "first" || screen.Lines[1] != "second" || screen.Lines[2] != "" {
+		t.Fatalf("Lines = %q, want emulated rows", screen.Lines)
+	}
+
+	if _, ok := manager.Screen("%9"); ok {
+		t.Fatal("Screen() for unknown pane ok = true, want false")
+	}
+}
@@ -3,6 +3,7 @@ package panestate
 import (
 	"fmt"
 	"log/slog"
+	"strconv"
 	"strings"
 	"unicode/utf8"
 )
@@ -32,12 +33,27 @@ type terminalState struct {
 
 	escapeMode    escapeMode
 	oscEscPending bool
-	csiLen        int // number of runes consumed in current CSI sequence
+	csiLen        int    // number of runes consumed in current CSI sequence
+	csiParams     []rune // parameter and intermediate runes of the current CSI sequence
+
+	// mainScreen holds the main screen while the application uses the
+	// alternate screen, so that leaving it restores the previous viewport.
+	mainScreen *savedScreen
 
 	remainder [utf8.UTFMax]byte // buffer for incomplete multi-byte sequence at chunk boundary
 	remLen    int               // valid bytes in remainder
 }
 
+// savedScreen is a viewport set aside by the alternate screen.
+type savedScreen struct {
+	lines [][]rune
+	head  int
+	row   int
+	col   int
+	cols  int
+	rows  int
+}
+
 func newTerminalState(cols int, rows int) *terminalState {
 	cols, rows = sanitizeSize(cols, rows)
 	lines := make([][]rune, rows)
@@ -201,6 +217,15 @@ func (t *terminalState) String() string {
 	return b.String()
 }
 
+// Lines returns the viewport rows in display order, one string per row.
+func (t *terminalState) Lines() []string {
+	lines := make([]string, t.rows)
+	for i := range t.rows {
+		lines[i] = string(t.lines[t.physIdx(i)])
+	}
+	return lines
+}
+
 func (t *terminalState) consumeRune(r rune) {
 	if t.escapeMode != escapeNone {
 		t.consumeEscapeRune(r)
@@ -238,6 +263,7 @@ func (t *terminalState) consumeEscapeRune(r rune) {
 		case '[':
 			t.escapeMode = escapeCSI
 			t.csiLen = 0
+			t.csiParams = t.csiParams[:0]
 		case ']':
 			t.escapeMode = escapeOSC
 			t.oscEscPending = false
@@ -249,12 +275,15 @@ func (t *terminalState) consumeEscapeRune(r rune) {
 		// A CSI sequence ends when a final-byte rune [@-~] appears.
 		// CR/LF also forcibly terminates a malformed sequence.
 		if r >= 0x40 && r <= 0x7e {
+			t.applyCSI(r)
 			t.resetEscape()
 		} else if r == '\r' || r == '\n' {
 			t.resetEscape()
 		} else if t.csiLen >= maxCSILen {
 			slog.Warn("[panestate] DEBUG CSI sequence exceeded max length, resetting parser", "csiLen", t.csiLen)
 			t.resetEscape()
+		} else {
+			t.csiParams = append(t.csiParams, r)
 		}
 	case escapeOSC:
 		if r == 0x07 {
@@ -274,6 +303,60 @@ func (t *terminalState) consumeEscapeRune(r rune) {
 	}
 }
 
+// applyCSI handles the CSI sequences that affect the emulated viewport.
+// Only alternate screen switches (DECSET 47, 1047, 1049) are interpreted;
+// everything else is ignored.
+func (t *terminalState) applyCSI(final rune) {
+	if final != 'h' && final != 'l' {
+		return
+	}
+	private, ok := strings.CutPrefix(string(t.csiParams), "?")
+	if !ok {
+		return
+	}
+	for field := range strings.SplitSeq(private, ";") {
+		switch mode, _ := strconv.Atoi(field); mode {
+		case 47, 1047, 1049:
+			t.setAlternateScreen(final == 'h')
+		}
+	}
+}
+
+// setAlternateScreen switches between the main and a blank alternate screen.
+// Switching to the screen already in use is a no-op.
+func (t *terminalState) setAlternateScreen(on bool) {
+	if on {
+		if t.mainScreen != nil {
+			return
+		}
+		t.mainScreen = &savedScreen{
+			lines: t.lines, head: t.head, row: t.row, col: t.col,
+			cols: t.cols, rows: t.rows,
+		}
+		t.lines = make([][]rune, t.rows)
+		for i := range t.lines {
+			t.lines[i] = make([]rune, 0, t.cols)
+		}
+		// Cursor positioning is not emulated; programs home the cursor on
+		// the alternate screen, so start at the top.
+		t.head, t.row, t.col = 0, 0, 0
+		return
+	}
+
+	main := t.mainScreen
+	if main == nil {
+		return
+	}
+	t.mainScreen = nil
+	cols, rows := t.cols, t.rows
+	t.lines, t.head, t.row, t.col = main.lines, main.head, main.row, main.col
+	t.cols, t.rows = main.cols, main.rows
+	if cols != t.cols || rows != t.rows {
+		// The pane was resized while the alternate screen was in use.
+		t.Resize(cols, rows)
+	}
+}
+
 func (t *terminalState) resetEscape() {
 	t.escapeMode = escapeNone
 	t.oscEscPending = false
@@ -538,3 +538,29 @@ func TestTerminalStateSizeAccessor(t *testing.T) {
 		t.Errorf("Size() after resize = (%d, %d), want (40, 10)", cols, rows)
 	}
 }
+
+func TestTerminalStateAlternateScreen(t *testing.T) {
+	term := newTerminalState(20, 2)
+	_, _ = term.Write([]byte("$ vim\r\n"))
+	_, _ = term.Write([]byte("\x1b[?1049h~ file"))
+	if got := term.String(); got != "~ file\n" {
+		t.Fatalf("alternate screen = %q, want only the alternate contents", got)
+	}
+
+	// Entering again must not overwrite the saved main screen.
+	_, _ = term.Write([]byte("\x1b[?1049h"))
+	term.Resize(10, 3)
+	_, _ = term.Write([]byte("\x1b[?1049l"))
+	if got := term.String(); got != "$ vim\n\n" {
+		t.Fatalf("main screen after leaving = %q, want the restored shell at the new size", got)
+	}
+	if cols, rows := term.Size(); cols != 10 || rows != 3 {
+		t.Fatalf("Size() = (%d, %d), want (10, 3)", cols, rows)
+	}
+
+	// Leaving when not on the alternate screen is a no-op.
+	_, _ = term.Write([]byte("\x1b[?47l$ "))
+	if got := term.String(); got != "$ vim\n$ \n" {
+		t.Fatalf("main screen = %q, want output to continue", got)
+	}
+}
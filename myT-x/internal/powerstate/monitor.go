@@ -0,0 +1,111 @@
+// Package powerstate tracks system sleep/resume and session lock/unlock so
+// the app can pause background work before the machine sleeps and check its
+// sessions after it wakes.
+package powerstate
+
+import (
+	"sync"
+	"time"
+)
+
+// Resume reasons passed to Deps.Resume.
+const (
+	ReasonResume = "resume"
+	ReasonUnlock = "unlock"
+)
+
+// Deps holds external dependencies injected at construction time.
+// Callbacks run on the caller's goroutine (the OS event thread) and must not
+// block; long work belongs in a worker started by the callback.
+type Deps struct {
+	// Pause stops background work before the system sleeps.
+	Pause func()
+	// Resume restarts background work and checks session health. away is how
+	// long the system slept or stayed locked; zero when the start was not seen.
+	Resume func(reason string, away time.Duration)
+
+	// Now returns the current time. Optional: defaults to time.Now.
+	Now func() time.Time
+}
+
+// Monitor turns raw power and session events into Pause/Resume calls.
+//
+// Windows does not guarantee pairing: a resume may arrive without a suspend
+// (e.g. after a failed sleep), and suspend may be reported twice. Monitor
+// pauses once per sleep and always runs Resume on resume.
+type Monitor struct {
+	deps Deps
+
+	mu          sync.Mutex
+	suspendedAt time.Time // zero while awake
+	lockedAt    time.Time // zero while unlocked
+}
+
+// NewMonitor creates a power state monitor.
+// Panics if Pause or Resume is nil.
+func NewMonitor(deps Deps) *Monitor {
+	if deps.Pause == nil || deps.Resume == nil {
+		panic("powerstate.NewMonitor: required function fields in Deps must be non-nil (Pause, Resume)")
+	}
+	if deps.Now == nil {
+		deps.Now = time.Now
+	}
+	return &Monitor{deps: deps}
+}
+
+// Suspended reports whether the system is going to or is asleep.
+func (m *Monitor) Suspended() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return !m.suspendedAt.IsZero()
+}
+
+// Suspend handles the system entering sleep.
+func (m *Monitor) Suspend() {
+	m.mu.Lock()
+	if !m.suspendedAt.IsZero() {
+		m.mu.Unlock()
+		return
+	}
+	m.suspendedAt = m.deps.Now()
+	m.mu.Unlock()
+	m.deps.Pause()
+}
+
+// Resume handles the system waking from sleep.
+func (m *Monitor) Resume() {
+	m.mu.Lock()
+	var away time.Duration
+	if !m.suspendedAt.IsZero() {
+		away = m.deps.Now().Sub(m.suspendedAt)
+	}
+	m.suspendedAt = time.Time{}
+	m.mu.Unlock()
+	m.deps.Resume(ReasonResume, away)
+}
+
+// Lock handles the user session being locked.
+func (m *Monitor) Lock() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.lockedAt.IsZero() {
+		m.lockedAt = m.deps.Now()
+	}
+}
+
+// Unlock handles the user session being unlocked. Panes can die while the
+// session is locked (e.g. modern standby does not always report suspend), so
+// unlock runs the same health check as resume. Unlock while asleep is ignored;
+// the following resume covers it.
+func (m *Monitor) Unlock() {
+	m.mu.Lock()
+	lockedAt := m.lockedAt
+	m.lockedAt = time.Time{}
+	suspended := !m.suspendedAt.IsZero()
+	now := m.deps.Now()
+	m.mu.Unlock()
+	if lockedAt.IsZero() || suspended {
+		return
+	}
+	m.deps.Resume(ReasonUnlock, now.Sub(lockedAt))
+}
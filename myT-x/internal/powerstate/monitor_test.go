@@ -0,0 +1,97 @@
+package powerstate
+
+import (
+	"testing"
+	"time"
+)
+
+type resumeCall struct {
+	reason string
+	away   time.Duration
+}
+
+func newTestMonitor(now *time.Time) (*Monitor, *int, *[]resumeCall) {
+	pauses := 0
+	var resumes []resumeCall
+	monitor := NewMonitor(Deps{
+		Pause: func() { pauses++ },
+		Resume: func(reason string, away time.Duration) {
+			resumes = append(resumes, resumeCall{reason: reason, away: away})
+		},
+		Now: func() time.Time { return *now },
+	})
+	return monitor, &pauses, &resumes
+}
+
+func TestNewMonitorPanicsOnMissingDeps(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("NewMonitor() did not panic with nil Pause/Resume")
+		}
+	}()
+	NewMonitor(Deps{})
+}
+
+func TestMonitorSuspendResume(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 0, 0, 0, time.UTC)
+	monitor, pauses, resumes := newTestMonitor(&now)
+
+	monitor.Suspend()
+	monitor.Suspend()
+	if *pauses != 1 || !monitor.Suspended() {
+		t.Fatalf("after double suspend: pauses = %d, suspended = %v, want 1 and true", *pauses, monitor.Suspended())
+	}
+
+	now = now.Add(90 * time.Minute)
+	monitor.Resume()
+	if monitor.Suspended() {
+		t.Fatal("Suspended() = true after resume")
+	}
+	if len(*resumes) != 1 || (*resumes)[0] != (resumeCall{ReasonResume, 90 * time.Minute}) {
+		t.Fatalf("resumes = %+v, want one resume after 90m", *resumes)
+	}
+
+	// Resume without a preceding suspend still checks health.
+	monitor.Resume()
+	if len(*resumes) != 2 || (*resumes)[1] != (resumeCall{ReasonResume, 0}) {
+		t.Fatalf("resumes = %+v, want unpaired resume with zero duration", *resumes)
+	}
+}
+
+func TestMonitorLockUnlock(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 0, 0, 0, time.UTC)
+	monitor, pauses, resumes := newTestMonitor(&now)
+
+	monitor.Unlock()
+	if len(*resumes) != 0 {
+		t.Fatalf("unlock without lock resumed: %+v", *resumes)
+	}
+
+	monitor.Lock()
+	now = now.Add(10 * time.Minute)
+	monitor.Lock()
+	now = now.Add(5 * time.Minute)
+	monitor.Unlock()
+	if *pauses != 0 {
+		t.Fatalf("pauses = %d, lock must not pause background work", *pauses)
+	}
+	if len(*resumes) != 1 || (*resumes)[0] != (resumeCall{ReasonUnlock, 15 * time.Minute}) {
+		t.Fatalf("resumes = %+v, want one unlock after 15m", *resumes)
+	}
+}
+
+func TestMonitorUnlockWhileSuspendedDefersToResume(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 0, 0, 0, time.UTC)
+	monitor, _, resumes := newTestMonitor(&now)
+
+	monitor.Lock()
+	monitor.Suspend()
+	monitor.Unlock()
+	if len(*resumes) != 0 {
+		t.Fatalf("unlock while suspended resumed: %+v", *resumes)
+	}
+	monitor.Resume()
+	if len(*resumes) != 1 || (*resumes)[0].reason != ReasonResume {
+		t.Fatalf("resumes = %+v, want a single resume", *resumes)
+	}
+}
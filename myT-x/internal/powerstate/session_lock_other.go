@@ -0,0 +1,13 @@
+//go:build !windows
+
+package powerstate
+
+import (
+	"context"
+	"errors"
+)
+
+// ListenSessionLock is only supported on Windows.
+func ListenSessionLock(context.Context, func(), func()) error {
+	return errors.ErrUnsupported
+}
@@ -0,0 +1,193 @@
+//go:build windows
+
+package powerstate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	user32DLL   = syscall.NewLazyDLL("user32.dll")
+	kernel32DLL = syscall.NewLazyDLL("kernel32.dll")
+	wtsapi32DLL = syscall.NewLazyDLL("wtsapi32.dll")
+
+	procRegisterClassExW                 = user32DLL.NewProc("RegisterClassExW")
+	procCreateWindowExW                  = user32DLL.NewProc("CreateWindowExW")
+	procDestroyWindow                    = user32DLL.NewProc("DestroyWindow")
+	procDefWindowProcW                   = user32DLL.NewProc("DefWindowProcW")
+	procGetMessageW                      = user32DLL.NewProc("GetMessageW")
+	procDispatchMessageW                 = user32DLL.NewProc("DispatchMessageW")
+	procPostMessageW                     = user32DLL.NewProc("PostMessageW")
+	procGetModuleHandleW                 = kernel32DLL.NewProc("GetModuleHandleW")
+	procWTSRegisterSessionNotification   = wtsapi32DLL.NewProc("WTSRegisterSessionNotification")
+	procWTSUnRegisterSessionNotification = wtsapi32DLL.NewProc("WTSUnRegisterSessionNotification")
+)
+
+const (
+	wmWTSSessionChange   = 0x02B1
+	wmApp                = 0x8000
+	wmStopListener       = wmApp + 1
+	wtsSessionLock       = 0x7
+	wtsSessionUnlock     = 0x8
+	notifyForThisSession = 0
+
+	// hwndMessage is HWND_MESSAGE ((HWND)-3): the parent of message-only windows.
+	hwndMessage = ^uintptr(2)
+
+	sessionLockClassName = "myTxSessionLockListener"
+)
+
+// wndClassEx mirrors the Win32 WNDCLASSEXW struct.
+type wndClassEx struct {
+	size       uint32
+	style      uint32
+	wndProc    uintptr
+	clsExtra   int32
+	wndExtra   int32
+	instance   uintptr
+	icon       uintptr
+	cursor     uintptr
+	background uintptr
+	menuName   *uint16
+	className  *uint16
+	iconSm     uintptr
+}
+
+// point mirrors the Win32 POINT struct.
+type point struct {
+	x int32
+	y int32
+}
+
+// winMsg mirrors the Win32 MSG struct.
+type winMsg struct {
+	hWnd     uintptr
+	message  uint32
+	wParam   uintptr
+	lParam   uintptr
+	time     uint32
+	pt       point
+	lPrivate uint32
+}
+
+type sessionLockHandlers struct {
+	onLock   func()
+	onUnlock func()
+}
+
+var (
+	registerClassOnce sync.Once
+	registerClassErr  error
+	// listeners maps a listener window handle to its callbacks.
+	listeners sync.Map
+	// wndProcCallback is created once: syscall.NewCallback slots are never freed.
+	wndProcCallback = syscall.NewCallback(sessionLockWndProc)
+)
+
+func sessionLockWndProc(hwnd, msg, wParam, lParam uintptr) uintptr {
+	if msg == wmWTSSessionChange {
+		if value, ok := listeners.Load(hwnd); ok {
+			handlers := value.(sessionLockHandlers)
+			switch wParam {
+			case wtsSessionLock:
+				handlers.onLock()
+			case wtsSessionUnlock:
+				handlers.onUnlock()
+			}
+		}
+		return 0
+	}
+	ret, _, _ := procDefWindowProcW.Call(hwnd, msg, wParam, lParam)
+	return ret
+}
+
+func registerSessionLockClass(instance uintptr) error {
+	registerClassOnce.Do(func() {
+		className, err := syscall.UTF16PtrFromString(sessionLockClassName)
+		if err != nil {
+			registerClassErr = err
+			return
+		}
+		class := wndClassEx{
+			wndProc:   wndProcCallback,
+			instance:  instance,
+			className: className,
+		}
+		class.size = uint32(unsafe.Sizeof(class))
+		if atom, _, callErr := procRegisterClassExW.Call(uintptr(unsafe.Pointer(&class))); atom == 0 {
+			registerClassErr = fmt.Errorf("RegisterClassExW: %w", callErr)
+		}
+	})
+	return registerClassErr
+}
+
+// ListenSessionLock calls onLock and onUnlock when the user session is locked
+// or unlocked, until ctx is cancelled. The callbacks run on the listener's
+// message thread and must not block.
+func ListenSessionLock(ctx context.Context, onLock, onUnlock func()) error {
+	if onLock == nil || onUnlock == nil {
+		return errors.New("onLock and onUnlock callbacks are required")
+	}
+	for _, dll := range []*syscall.LazyDLL{user32DLL, kernel32DLL, wtsapi32DLL} {
+		if err := dll.Load(); err != nil {
+			return fmt.Errorf("%s is unavailable: %w", dll.Name, err)
+		}
+	}
+
+	// Window messages are delivered to the thread that created the window.
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	instance, _, _ := procGetModuleHandleW.Call(0)
+	if err := registerSessionLockClass(instance); err != nil {
+		return err
+	}
+	className, err := syscall.UTF16PtrFromString(sessionLockClassName)
+	if err != nil {
+		return err
+	}
+	hwnd, _, callErr := procCreateWindowExW.Call(
+		0, uintptr(unsafe.Pointer(className)), 0, 0,
+		0, 0, 0, 0,
+		hwndMessage, 0, instance, 0,
+	)
+	if hwnd == 0 {
+		return fmt.Errorf("CreateWindowExW: %w", callErr)
+	}
+	defer procDestroyWindow.Call(hwnd)
+
+	listeners.Store(hwnd, sessionLockHandlers{onLock: onLock, onUnlock: onUnlock})
+	defer listeners.Delete(hwnd)
+
+	if ok, _, callErr := procWTSRegisterSessionNotification.Call(hwnd, notifyForThisSession); ok == 0 {
+		return fmt.Errorf("WTSRegisterSessionNotification: %w", callErr)
+	}
+	defer procWTSUnRegisterSessionNotification.Call(hwnd)
+
+	stop := context.AfterFunc(ctx, func() {
+		procPostMessageW.Call(hwnd, wmStopListener, 0, 0)
+	})
+	defer stop()
+
+	var msg winMsg
+	for {
+		ret, _, callErr := procGetMessageW.Call(uintptr(unsafe.Pointer(&msg)), 0, 0, 0)
+		switch int32(ret) {
+		case -1:
+			return fmt.Errorf("GetMessageW: %w", callErr)
+		case 0:
+			// WM_QUIT
+			return nil
+		}
+		if msg.message == wmStopListener {
+			return nil
+		}
+		procDispatchMessageW.Call(uintptr(unsafe.Pointer(&msg)))
+	}
+}
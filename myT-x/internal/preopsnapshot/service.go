@@ -0,0 +1,328 @@
+// Package preopsnapshot captures a lightweight post-mortem snapshot of a
+// session (scrollback tail, git status, layout) right before a risky
+// operation runs, and appends it to a persisted audit log.
+package preopsnapshot
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// AuditLogFileName is the JSON-lines audit log snapshots are appended to.
+	AuditLogFileName = "pre-op-audit.jsonl"
+	// maxAuditLogBytes rotates the audit log to AuditLogFileName+".1" once exceeded.
+	maxAuditLogBytes = 4 << 20
+	// maxSnapshotsPerSession bounds the snapshots kept in memory per session.
+	maxSnapshotsPerSession = 20
+	// maxTailLines bounds the scrollback lines kept per pane.
+	maxTailLines = 200
+	// maxPanes bounds the panes captured per snapshot.
+	maxPanes = 16
+	// maxGitStatusBytes bounds the git status output kept per snapshot.
+	maxGitStatusBytes = 16 * 1024
+)
+
+// Deps holds external dependencies injected at construction time.
+type Deps struct {
+	// CaptureSession returns the layout and raw pane contents of a session.
+	CaptureSession func(sessionName string) (SessionState, error)
+
+	// GitStatus returns `git status` output for a directory.
+	// Optional: git status is skipped if nil.
+	GitStatus func(dir string) (string, error)
+
+	// AuditLogDir returns the directory the audit log is kept in.
+	// Optional: snapshots are kept in memory only if nil.
+	AuditLogDir func() (string, error)
+
+	// Now returns the current time. Optional: defaults to time.Now.
+	Now func() time.Time
+}
+
+// Service records pre-operation snapshots.
+//
+// Thread-safety is managed internally via mu. No external locking is required.
+type Service struct {
+	deps Deps
+
+	mu sync.Mutex
+	// loaded is set once the audit log has been read back into bySession.
+	loaded bool
+	// bySession holds the most recent snapshots per session, oldest first.
+	bySession map[string][]Snapshot
+	seq       uint64
+}
+
+// NewService creates a pre-operation snapshot service.
+// Panics if CaptureSession is nil.
+func NewService(deps Deps) *Service {
+	if deps.CaptureSession == nil {
+		panic("preopsnapshot.NewService: CaptureSession must be non-nil")
+	}
+	if deps.Now == nil {
+		deps.Now = time.Now
+	}
+	return &Service{deps: deps, bySession: map[string][]Snapshot{}}
+}
+
+// Capture snapshots sessionName before operation runs and appends it to the
+// audit log. It never fails: capture problems are recorded in
+// Snapshot.Errors so the operation itself is not blocked.
+func (s *Service) Capture(operation Operation, sessionName, detail string) Snapshot {
+	now := s.deps.Now()
+	snapshot := Snapshot{
+		Session:    sessionName,
+		Operation:  operation,
+		Detail:     detail,
+		CapturedAt: now.UTC().Format(time.RFC3339),
+	}
+
+	state, err := s.deps.CaptureSession(sessionName)
+	if err != nil {
+		snapshot.Errors = append(snapshot.Errors, fmt.Sprintf("capture session: %v", err))
+	}
+	snapshot.WorkDir = state.WorkDir
+	snapshot.Layout = state.Layout
+	for _, pane := range state.Panes {
+		if len(snapshot.Panes) == maxPanes {
+			snapshot.Errors = append(snapshot.Errors, fmt.Sprintf("only the first %d panes were captured", maxPanes))
+			break
+		}
+		snapshot.Panes = append(snapshot.Panes, PaneTail{
+			PaneID: pane.PaneID,
+			Window: pane.Window,
+			Title:  pane.Title,
+			Lines:  tailLines(pane.Content, maxTailLines),
+		})
+	}
+
+	if s.deps.GitStatus != nil && state.WorkDir != "" {
+		status, err := s.deps.GitStatus(state.WorkDir)
+		if err != nil {
+			snapshot.Errors = append(snapshot.Errors, fmt.Sprintf("git status: %v", err))
+		}
+		if len(status) > maxGitStatusBytes {
+			status = status[:maxGitStatusBytes] + "\n... (truncated)"
+		}
+		snapshot.GitStatus = status
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seq++
+	snapshot.ID = strconv.FormatInt(now.UnixNano(), 36) + "-" + strconv.FormatUint(s.seq, 10)
+	// Read back earlier snapshots first so this one is not duplicated by a later load.
+	s.loadLocked()
+	s.addLocked(snapshot)
+	if err := s.appendLocked(snapshot); err != nil {
+		slog.Warn("[WARN-PREOP] failed to append snapshot to audit log", "session", sessionName, "operation", operation, "error", err)
+	}
+	slog.Info("[PREOP] captured snapshot before risky operation",
+		"session", sessionName, "operation", operation, "id", snapshot.ID, "panes", len(snapshot.Panes))
+	return snapshot
+}
+
+// List returns the recorded snapshots of sessionName, newest first.
+func (s *Service) List(sessionName string) []Snapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.loadLocked()
+	snapshots := slices.Clone(s.bySession[sessionName])
+	slices.Reverse(snapshots)
+	return snapshots
+}
+
+// REQUIRES: s.mu must be held by the caller.
+func (s *Service) addLocked(snapshot Snapshot) {
+	snapshots := append(s.bySession[snapshot.Session], snapshot)
+	if len(snapshots) > maxSnapshotsPerSession {
+		snapshots = slices.Clone(snapshots[len(snapshots)-maxSnapshotsPerSession:])
+	}
+	s.bySession[snapshot.Session] = snapshots
+}
+
+func (s *Service) auditLogPath() (string, error) {
+	if s.deps.AuditLogDir == nil {
+		return "", nil
+	}
+	dir, err := s.deps.AuditLogDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve audit log directory: %w", err)
+	}
+	return filepath.Join(dir, AuditLogFileName), nil
+}
+
+// loadLocked reads the audit log once. Unreadable lines are skipped so a
+// torn write does not hide the rest of the log.
+//
+// REQUIRES: s.mu must be held by the caller.
+func (s *Service) loadLocked() {
+	if s.loaded {
+		return
+	}
+	s.loaded = true
+	path, err := s.auditLogPath()
+	if err != nil || path == "" {
+		if err != nil {
+			slog.Warn("[WARN-PREOP] audit log unavailable", "error", err)
+		}
+		return
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			slog.Warn("[WARN-PREOP] failed to open audit log", "path", path, "error", err)
+		}
+		return
+	}
+	defer f.Close()
+
+	current := s.bySession
+	s.bySession = map[string][]Snapshot{}
+	reader := bufio.NewReader(f)
+	for {
+		line, readErr := reader.ReadBytes('\n')
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			var snapshot Snapshot
+			if err := json.Unmarshal(line, &snapshot); err != nil || snapshot.Session == "" {
+				slog.Debug("[DEBUG-PREOP] skipping unreadable audit log line", "error", err)
+			} else {
+				s.addLocked(snapshot)
+			}
+		}
+		if readErr != nil {
+			if !errors.Is(readErr, io.EOF) {
+				slog.Warn("[WARN-PREOP] failed to read audit log", "path", path, "error", readErr)
+			}
+			break
+		}
+	}
+	for _, snapshots := range current {
+		for _, snapshot := range snapshots {
+			s.addLocked(snapshot)
+		}
+	}
+}
+
+// appendLocked appends snapshot as one JSON line, rotating the log first
+// when it has grown past maxAuditLogBytes.
+//
+// REQUIRES: s.mu must be held by the caller.
+func (s *Service) appendLocked(snapshot Snapshot) error {
+	path, err := s.auditLogPath()
+	if err != nil || path == "" {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create audit log directory: %w", err)
+	}
+	if info, err := os.Stat(path); err == nil && info.Size() > maxAuditLogBytes {
+		if err := os.Rename(path, path+".1"); err != nil {
+			return fmt.Errorf("rotate audit log: %w", err)
+		}
+	}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("marshal snapshot: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("open audit log: %w", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("write audit log: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("close audit log: %w", err)
+	}
+	return nil
+}
+
+// tailLines returns the last n non-trailing-blank lines of content with
+// terminal escape sequences removed.
+func tailLines(content string, n int) []string {
+	lines := strings.Split(stripEscapes(content), "\n")
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return lines
+}
+
+// stripEscapes removes CSI/OSC/two-byte escape sequences and control
+// characters other than tab and newline. A lone carriage return discards the
+// text before it on the same line, as a terminal would overwrite it.
+func stripEscapes(s string) string {
+	var out strings.Builder
+	out.Grow(len(s))
+	lineStart := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == 0x1b && i+1 < len(s):
+			i = skipEscape(s, i+1)
+		case c == '\r':
+			if i+1 < len(s) && s[i+1] == '\n' {
+				continue
+			}
+			// Carriage return: subsequent output overwrites the line.
+			truncated := out.String()[:lineStart]
+			out.Reset()
+			out.WriteString(truncated)
+		case c == '\n':
+			out.WriteByte(c)
+			lineStart = out.Len()
+		case c == '\t' || c >= 0x20 && c != 0x7f:
+			out.WriteByte(c)
+		}
+	}
+	return out.String()
+}
+
+// skipEscape returns the index of the last byte of the escape sequence whose
+// introducer follows ESC at s[i].
+func skipEscape(s string, i int) int {
+	switch s[i] {
+	case '[': // CSI: parameters then a final byte in 0x40-0x7e.
+		for j := i + 1; j < len(s); j++ {
+			if s[j] >= 0x40 && s[j] <= 0x7e {
+				return j
+			}
+		}
+		return len(s) - 1
+	case ']', 'P', '_', '^': // OSC/DCS/APC/PM: terminated by BEL or ST (ESC \).
+		for j := i + 1; j < len(s); j++ {
+			if s[j] == 0x07 {
+				return j
+			}
+			if s[j] == 0x1b && j+1 < len(s) && s[j+1] == '\\' {
+				return j + 1
+			}
+		}
+		return len(s) - 1
+	case '(', ')', '*', '+': // charset designation: one more byte.
+		return min(i+1, len(s)-1)
+	default:
+		return i
+	}
+}
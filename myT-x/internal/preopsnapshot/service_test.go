@@ -0,0 +1,137 @@
+package preopsnapshot
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestService(t *testing.T, dir string, state SessionState, captureErr error) *Service {
+	t.Helper()
+	return NewService(Deps{
+		CaptureSession: func(string) (SessionState, error) { return state, captureErr },
+		GitStatus: func(dir string) (string, error) {
+			return "## feature\n M main.go", nil
+		},
+		AuditLogDir: func() (string, error) { return dir, nil },
+		Now:         func() time.Time { return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC) },
+	})
+}
+
+func TestCaptureRecordsAndPersistsSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	state := SessionState{
+		WorkDir: "C:/repo/.wt/feature",
+		Layout:  "d463,159x48,0,0,1",
+		Panes:   []PaneContent{{PaneID: "%1", Window: "main", Content: "\x1b[32mok\x1b[0m\r\nbuild\rtest passed\n\n"}},
+	}
+	svc := newTestService(t, dir, state, nil)
+
+	got := svc.Capture(OperationForceCleanup, "feature", "C:/repo/.wt/feature")
+	if got.ID == "" || got.CapturedAt != "2026-01-02T03:04:05Z" || got.GitStatus != "## feature\n M main.go" || got.Layout != state.Layout {
+		t.Fatalf("Capture() = %+v", got)
+	}
+	if want := []string{"ok", "test passed"}; len(got.Panes) != 1 || !reflect.DeepEqual(got.Panes[0].Lines, want) {
+		t.Fatalf("Capture().Panes = %+v, want lines %q", got.Panes, want)
+	}
+
+	second := svc.Capture(OperationPromoteBranch, "feature", "feature/x")
+	if second.ID == got.ID {
+		t.Fatalf("snapshot IDs collide: %q", got.ID)
+	}
+	if list := svc.List("feature"); len(list) != 2 || list[0].Operation != OperationPromoteBranch {
+		t.Fatalf("List() = %+v, want newest first", list)
+	}
+	if list := svc.List("other"); len(list) != 0 {
+		t.Fatalf("List(other) = %+v, want empty", list)
+	}
+
+	// A fresh service reads the audit log back.
+	reloaded := newTestService(t, dir, state, nil)
+	list := reloaded.List("feature")
+	if len(list) != 2 || list[1].ID != got.ID || !reflect.DeepEqual(list[1].Panes, got.Panes) {
+		t.Fatalf("reloaded List() = %+v", list)
+	}
+	reloaded.Capture(OperationForceCleanup, "feature", "")
+	if list := reloaded.List("feature"); len(list) != 3 {
+		t.Fatalf("List() after reload and capture = %d snapshots, want 3", len(list))
+	}
+}
+
+func TestCaptureNeverFails(t *testing.T) {
+	svc := NewService(Deps{
+		CaptureSession: func(string) (SessionState, error) {
+			return SessionState{WorkDir: "C:/repo"}, errors.New("session not found")
+		},
+		GitStatus: func(string) (string, error) { return "", errors.New("not a git repository") },
+	})
+	got := svc.Capture(OperationForceCleanup, "gone", "")
+	if len(got.Errors) != 2 || !strings.Contains(got.Errors[0], "session not found") || !strings.Contains(got.Errors[1], "not a git repository") {
+		t.Fatalf("Capture().Errors = %q", got.Errors)
+	}
+	if list := svc.List("gone"); len(list) != 1 {
+		t.Fatalf("List() = %+v, want in-memory snapshot without audit log", list)
+	}
+}
+
+func TestCaptureBoundsSnapshot(t *testing.T) {
+	panes := make([]PaneContent, maxPanes+1)
+	for i := range panes {
+		panes[i] = PaneContent{PaneID: "%" + string(rune('a'+i)), Content: strings.Repeat("line\n", maxTailLines+10)}
+	}
+	svc := newTestService(t, t.TempDir(), SessionState{Panes: panes}, nil)
+	for range maxSnapshotsPerSession + 3 {
+		svc.Capture(OperationForceCleanup, "big", "")
+	}
+	list := svc.List("big")
+	if len(list) != maxSnapshotsPerSession {
+		t.Fatalf("List() = %d snapshots, want %d", len(list), maxSnapshotsPerSession)
+	}
+	if len(list[0].Panes) != maxPanes || len(list[0].Panes[0].Lines) != maxTailLines || len(list[0].Errors) != 1 {
+		t.Fatalf("snapshot panes = %d, lines = %d, errors = %q", len(list[0].Panes), len(list[0].Panes[0].Lines), list[0].Errors)
+	}
+}
+
+func TestLoadSkipsCorruptLines(t *testing.T) {
+	dir := t.TempDir()
+	content := "{\"id\":\"a\",\"session\":\"s\",\"operation\":\"force-cleanup\"}\n{torn\n\n{\"id\":\"b\",\"session\":\"s\",\"operation\":\"promote-branch\"}"
+	if err := os.WriteFile(filepath.Join(dir, AuditLogFileName), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	svc := newTestService(t, dir, SessionState{}, nil)
+	list := svc.List("s")
+	if len(list) != 2 || list[0].ID != "b" || list[1].ID != "a" {
+		t.Fatalf("List() = %+v", list)
+	}
+}
+
+func TestStripEscapes(t *testing.T) {
+	tests := map[string]string{
+		"csi color":       "\x1b[1;31mred\x1b[0m",
+		"osc title bel":   "\x1b]0;title\x07red",
+		"osc title st":    "\x1b]0;title\x1b\\red",
+		"charset":         "\x1b(Bred",
+		"carriage return": "progress 10%\rred",
+		"controls":        "r\x00e\x08d",
+	}
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := stripEscapes(input); got != "red" {
+				t.Fatalf("stripEscapes(%q) = %q, want %q", input, got, "red")
+			}
+		})
+	}
+}
+
+func TestNewServicePanicsWithoutCaptureSession(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("NewService() did not panic")
+		}
+	}()
+	NewService(Deps{})
+}
@@ -0,0 +1,53 @@
+package preopsnapshot
+
+// Operation names a risky operation that is snapshotted before it runs.
+type Operation string
+
+const (
+	// OperationForceCleanup removes a worktree with worktree.force_cleanup
+	// enabled, discarding uncommitted changes.
+	OperationForceCleanup Operation = "force-cleanup"
+	// OperationPromoteBranch moves a detached worktree onto a new branch.
+	OperationPromoteBranch Operation = "promote-branch"
+)
+
+// PaneContent is the raw content of one pane as reported by CaptureSession.
+type PaneContent struct {
+	PaneID  string
+	Window  string
+	Title   string
+	Content string
+}
+
+// SessionState is the session state reported by CaptureSession.
+type SessionState struct {
+	// WorkDir is where git status is taken: the worktree path, else the
+	// session root. Empty skips git status.
+	WorkDir string
+	// Layout is the tmux layout dump of the active window.
+	Layout string
+	Panes  []PaneContent
+}
+
+// PaneTail is the end of one pane's scrollback, escape sequences removed.
+type PaneTail struct {
+	PaneID string   `json:"pane_id"`
+	Window string   `json:"window,omitempty"`
+	Title  string   `json:"title,omitempty"`
+	Lines  []string `json:"lines"`
+}
+
+// Snapshot is the state of a session captured right before a risky operation.
+type Snapshot struct {
+	ID         string     `json:"id"`
+	Session    string     `json:"session"`
+	Operation  Operation  `json:"operation"`
+	Detail     string     `json:"detail,omitempty"`
+	CapturedAt string     `json:"captured_at"`
+	WorkDir    string     `json:"work_dir,omitempty"`
+	Layout     string     `json:"layout,omitempty"`
+	GitStatus  string     `json:"git_status,omitempty"`
+	Panes      []PaneTail `json:"panes,omitempty"`
+	// Errors lists capture steps that failed; the snapshot is still recorded.
+	Errors []string `json:"errors,omitempty"`
+}
@@ -0,0 +1,19 @@
+//go:build !windows
+
+package procutil
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// ProcessAlive reports whether the process pid is still running.
+func ProcessAlive(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	err = process.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
@@ -0,0 +1,16 @@
+package procutil
+
+import (
+	"os"
+	"testing"
+)
+
+func TestProcessAlive(t *testing.T) {
+	if !ProcessAlive(os.Getpid()) {
+		t.Fatal("ProcessAlive(own pid) = false, want true")
+	}
+	// PIDs are far below this on every supported platform.
+	if ProcessAlive(1 << 30) {
+		t.Fatal("ProcessAlive(unused pid) = true, want false")
+	}
+}
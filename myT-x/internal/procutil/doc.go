@@ -1,4 +1,4 @@
-// Package procutil provides cross-platform process utilities.
-// Currently exposes HideWindow, which prevents console window flash
-// on Windows when launching child processes via exec.Command.
+// Package procutil provides cross-platform process utilities:
+// HideWindow prevents console window flash on Windows when launching child
+// processes via exec.Command, and ProcessAlive checks whether a PID is running.
 package procutil
@@ -0,0 +1,292 @@
+// Package recentdirs keeps the history of directories picked for new
+// sessions so the new-session dialog can offer them without opening the
+// native folder picker. Entries can be pinned to keep them at the top.
+package recentdirs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"myT-x/internal/statestore"
+)
+
+const (
+	// listKey is the single record in statestore.BucketRecentDirectories
+	// holding the whole list.
+	listKey = "list"
+	// MaxRecent caps the unpinned entries; the least recently used is dropped.
+	MaxRecent = 20
+	// MaxPinned caps the pinned entries.
+	MaxPinned = 20
+)
+
+// ErrPinLimit is returned by SetPinned when MaxPinned entries are pinned.
+var ErrPinLimit = fmt.Errorf("at most %d directories can be pinned", MaxPinned)
+
+// entry is the persisted form of one directory.
+type entry struct {
+	Path     string    `json:"path"`
+	Pinned   bool      `json:"pinned,omitempty"`
+	LastUsed time.Time `json:"last_used"`
+}
+
+// Directory is one recent directory with its current validation state.
+type Directory struct {
+	Path     string `json:"path"`
+	Pinned   bool   `json:"pinned"`
+	LastUsed string `json:"last_used"`
+	// Exists reports whether the path is still a directory.
+	Exists bool `json:"exists"`
+	// IsGitRepo reports whether the directory is inside a git work tree.
+	IsGitRepo bool `json:"is_git_repo"`
+}
+
+// Deps holds external dependencies injected at construction time.
+type Deps struct {
+	// Store returns the state store the list is persisted in.
+	Store func() (statestore.Store, error)
+
+	// IsGitRepo reports whether dir is inside a git work tree.
+	// Optional: defaults to looking for a .git entry in dir or a parent,
+	// which avoids one git process per listed directory.
+	IsGitRepo func(dir string) bool
+
+	// Now returns the current time. Optional: defaults to time.Now.
+	Now func() time.Time
+}
+
+// Service reads and updates the recent-directory list.
+//
+// Thread-safety is managed internally via mu, which serializes
+// read-modify-write cycles on the stored list.
+type Service struct {
+	deps Deps
+	mu   sync.Mutex
+}
+
+// NewService creates a recent-directories service.
+// Panics if Store is nil.
+func NewService(deps Deps) *Service {
+	if deps.Store == nil {
+		panic("recentdirs.NewService: Store must be non-nil")
+	}
+	if deps.IsGitRepo == nil {
+		deps.IsGitRepo = hasGitDir
+	}
+	if deps.Now == nil {
+		deps.Now = time.Now
+	}
+	return &Service{deps: deps}
+}
+
+// List returns pinned directories first, then the rest, each group most
+// recently used first.
+func (s *Service) List() ([]Directory, error) {
+	s.mu.Lock()
+	entries, err := s.load()
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	dirs := make([]Directory, 0, len(entries))
+	for _, e := range entries {
+		info, statErr := os.Stat(e.Path)
+		exists := statErr == nil && info.IsDir()
+		dirs = append(dirs, Directory{
+			Path:      e.Path,
+			Pinned:    e.Pinned,
+			LastUsed:  e.LastUsed.Format(time.RFC3339),
+			Exists:    exists,
+			IsGitRepo: exists && s.deps.IsGitRepo(e.Path),
+		})
+	}
+	return dirs, nil
+}
+
+// Record marks dir as used now, adding it when missing.
+func (s *Service) Record(dir string) error {
+	dir, err := normalizePath(dir)
+	if err != nil {
+		return err
+	}
+	return s.update(func(entries []entry) ([]entry, error) {
+		now := s.deps.Now().UTC()
+		if i := indexOf(entries, dir); i >= 0 {
+			entries[i].Path = dir
+			entries[i].LastUsed = now
+			return entries, nil
+		}
+		return append(entries, entry{Path: dir, LastUsed: now}), nil
+	})
+}
+
+// SetPinned pins or unpins dir. Pinning a directory that is not in the list
+// adds it.
+func (s *Service) SetPinned(dir string, pinned bool) error {
+	dir, err := normalizePath(dir)
+	if err != nil {
+		return err
+	}
+	return s.update(func(entries []entry) ([]entry, error) {
+		i := indexOf(entries, dir)
+		if i >= 0 && entries[i].Pinned == pinned {
+			return entries, nil
+		}
+		if pinned && countPinned(entries) >= MaxPinned {
+			return nil, ErrPinLimit
+		}
+		if i < 0 {
+			if !pinned {
+				return entries, nil
+			}
+			return append(entries, entry{Path: dir, Pinned: true, LastUsed: s.deps.Now().UTC()}), nil
+		}
+		entries[i].Pinned = pinned
+		return entries, nil
+	})
+}
+
+// Remove deletes dir from the list. Removing a missing entry is not an error.
+func (s *Service) Remove(dir string) error {
+	dir, err := normalizePath(dir)
+	if err != nil {
+		return err
+	}
+	return s.update(func(entries []entry) ([]entry, error) {
+		return slices.DeleteFunc(entries, func(e entry) bool { return samePath(e.Path, dir) }), nil
+	})
+}
+
+// update applies fn to the stored list, then sorts, trims, and saves it.
+func (s *Service) update(fn func([]entry) ([]entry, error)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries, err := s.load()
+	if err != nil {
+		return err
+	}
+	entries, err = fn(entries)
+	if err != nil {
+		return err
+	}
+	return s.save(trim(sortEntries(entries)))
+}
+
+func (s *Service) load() ([]entry, error) {
+	store, err := s.deps.Store()
+	if err != nil {
+		return nil, err
+	}
+	data, err := store.Get(context.Background(), statestore.BucketRecentDirectories, listKey)
+	if errors.Is(err, statestore.ErrNotFound) {
+		return []entry{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read recent directories: %w", err)
+	}
+	var entries []entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		// The list is only a convenience; a corrupt record must not block
+		// the new-session dialog. The next update overwrites it.
+		slog.Warn("[WARN-RECENTDIRS] recent directories record is corrupt, starting empty", "error", err)
+		return []entry{}, nil
+	}
+	return sortEntries(entries), nil
+}
+
+func (s *Service) save(entries []entry) error {
+	store, err := s.deps.Store()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return fmt.Errorf("marshal recent directories: %w", err)
+	}
+	if err := store.Put(context.Background(), statestore.BucketRecentDirectories, listKey, data); err != nil {
+		return fmt.Errorf("write recent directories: %w", err)
+	}
+	return nil
+}
+
+// sortEntries orders pinned entries first, then by last use, newest first.
+func sortEntries(entries []entry) []entry {
+	slices.SortStableFunc(entries, func(a, b entry) int {
+		if a.Pinned != b.Pinned {
+			if a.Pinned {
+				return -1
+			}
+			return 1
+		}
+		return b.LastUsed.Compare(a.LastUsed)
+	})
+	return entries
+}
+
+// trim drops the least recently used unpinned entries beyond MaxRecent.
+// entries must be sorted.
+func trim(entries []entry) []entry {
+	pinned := countPinned(entries)
+	if len(entries)-pinned > MaxRecent {
+		entries = entries[:pinned+MaxRecent]
+	}
+	return entries
+}
+
+func countPinned(entries []entry) int {
+	count := 0
+	for _, e := range entries {
+		if e.Pinned {
+			count++
+		}
+	}
+	return count
+}
+
+func indexOf(entries []entry, dir string) int {
+	return slices.IndexFunc(entries, func(e entry) bool { return samePath(e.Path, dir) })
+}
+
+// samePath compares paths case-insensitively, as Windows does.
+func samePath(a, b string) bool {
+	return strings.EqualFold(a, b)
+}
+
+func normalizePath(dir string) (string, error) {
+	dir = strings.TrimSpace(dir)
+	if dir == "" {
+		return "", errors.New("directory is required")
+	}
+	if strings.ContainsRune(dir, '\x00') {
+		return "", errors.New("directory contains a null byte")
+	}
+	if !filepath.IsAbs(dir) {
+		return "", fmt.Errorf("directory must be an absolute path: %s", dir)
+	}
+	return filepath.Clean(dir), nil
+}
+
+// hasGitDir reports whether dir or one of its parents holds a .git entry
+// (a directory, or a file for worktrees and submodules).
+func hasGitDir(dir string) bool {
+	for {
+		if _, err := os.Lstat(filepath.Join(dir, ".git")); err == nil {
+			return true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return false
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,223 @@
+package recentdirs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"myT-x/internal/statestore"
+)
+
+func newTestService(t *testing.T) (*Service, *statestore.MemoryStore, *time.Time) {
+	t.Helper()
+	store := statestore.NewMemoryStore()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc := NewService(Deps{
+		Store: func() (statestore.Store, error) { return store, nil },
+		Now:   func() time.Time { return now },
+	})
+	return svc, store, &now
+}
+
+func paths(dirs []Directory) []string {
+	out := make([]string, 0, len(dirs))
+	for _, dir := range dirs {
+		out = append(out, dir.Path)
+	}
+	return out
+}
+
+func TestNewServicePanicsWithoutStore(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("NewService(Deps{}) did not panic")
+		}
+	}()
+	NewService(Deps{})
+}
+
+func TestRecordOrdersByRecency(t *testing.T) {
+	svc, _, now := newTestService(t)
+	a, b := t.TempDir(), t.TempDir()
+
+	if err := svc.Record(a); err != nil {
+		t.Fatalf("Record(a) error = %v", err)
+	}
+	*now = now.Add(time.Minute)
+	if err := svc.Record(b); err != nil {
+		t.Fatalf("Record(b) error = %v", err)
+	}
+	*now = now.Add(time.Minute)
+	// Re-recording moves the entry to the top without duplicating it.
+	if err := svc.Record(a + string(filepath.Separator)); err != nil {
+		t.Fatalf("Record(a/) error = %v", err)
+	}
+
+	dirs, err := svc.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if got := paths(dirs); len(got) != 2 || got[0] != a || got[1] != b {
+		t.Fatalf("List() paths = %v, want [%s %s]", got, a, b)
+	}
+	if dirs[0].LastUsed != "2026-03-01T12:02:00Z" {
+		t.Fatalf("LastUsed = %q, want 2026-03-01T12:02:00Z", dirs[0].LastUsed)
+	}
+}
+
+func TestRecordRejectsInvalidPaths(t *testing.T) {
+	svc, _, _ := newTestService(t)
+	for _, dir := range []string{"", "   ", "relative/dir", "/tmp/a\x00b"} {
+		if err := svc.Record(dir); err == nil {
+			t.Errorf("Record(%q) error = nil, want error", dir)
+		}
+	}
+}
+
+func TestPinnedEntriesStayOnTopAndSurviveTrim(t *testing.T) {
+	svc, _, now := newTestService(t)
+	root := t.TempDir()
+	pinnedDir := filepath.Join(root, "pinned")
+
+	if err := svc.SetPinned(pinnedDir, true); err != nil {
+		t.Fatalf("SetPinned() error = %v", err)
+	}
+	for i := range MaxRecent + 3 {
+		*now = now.Add(time.Minute)
+		if err := svc.Record(filepath.Join(root, fmt.Sprintf("dir-%02d", i))); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+	}
+
+	dirs, err := svc.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(dirs) != MaxRecent+1 {
+		t.Fatalf("len(List()) = %d, want %d", len(dirs), MaxRecent+1)
+	}
+	if dirs[0].Path != pinnedDir || !dirs[0].Pinned {
+		t.Fatalf("first entry = %+v, want pinned %s", dirs[0], pinnedDir)
+	}
+	if want := filepath.Join(root, fmt.Sprintf("dir-%02d", MaxRecent+2)); dirs[1].Path != want {
+		t.Fatalf("second entry = %s, want newest %s", dirs[1].Path, want)
+	}
+	if oldest := filepath.Join(root, "dir-00"); dirs[len(dirs)-1].Path == oldest {
+		t.Fatalf("oldest unpinned entry %s was not trimmed", oldest)
+	}
+
+	if err := svc.SetPinned(pinnedDir, false); err != nil {
+		t.Fatalf("SetPinned(false) error = %v", err)
+	}
+	dirs, _ = svc.List()
+	if dirs[0].Pinned {
+		t.Fatalf("first entry still pinned after unpin: %+v", dirs[0])
+	}
+}
+
+func TestSetPinnedLimit(t *testing.T) {
+	svc, _, _ := newTestService(t)
+	root := t.TempDir()
+	for i := range MaxPinned {
+		if err := svc.SetPinned(filepath.Join(root, fmt.Sprint(i)), true); err != nil {
+			t.Fatalf("SetPinned(%d) error = %v", i, err)
+		}
+	}
+	if err := svc.SetPinned(filepath.Join(root, "extra"), true); !errors.Is(err, ErrPinLimit) {
+		t.Fatalf("SetPinned(extra) error = %v, want ErrPinLimit", err)
+	}
+	// Unpinning an unknown directory is a no-op.
+	if err := svc.SetPinned(filepath.Join(root, "unknown"), false); err != nil {
+		t.Fatalf("SetPinned(unknown, false) error = %v", err)
+	}
+}
+
+func TestRemove(t *testing.T) {
+	svc, _, _ := newTestService(t)
+	dir := t.TempDir()
+	if err := svc.Record(dir); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if err := svc.Remove(dir); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if err := svc.Remove(dir); err != nil {
+		t.Fatalf("Remove(missing) error = %v", err)
+	}
+	if dirs, _ := svc.List(); len(dirs) != 0 {
+		t.Fatalf("List() after Remove = %v, want empty", paths(dirs))
+	}
+}
+
+func TestListValidatesEntries(t *testing.T) {
+	svc, _, _ := newTestService(t)
+	repo := t.TempDir()
+	if err := os.Mkdir(filepath.Join(repo, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	sub := filepath.Join(repo, "pkg")
+	if err := os.Mkdir(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	plain := t.TempDir()
+	missing := filepath.Join(plain, "gone")
+
+	for _, dir := range []string{repo, sub, plain, missing} {
+		if err := svc.Record(dir); err != nil {
+			t.Fatalf("Record(%s) error = %v", dir, err)
+		}
+	}
+	dirs, err := svc.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	got := map[string]Directory{}
+	for _, dir := range dirs {
+		got[dir.Path] = dir
+	}
+	checks := []struct {
+		path      string
+		exists    bool
+		isGitRepo bool
+	}{
+		{repo, true, true},
+		{sub, true, true},
+		{plain, true, false},
+		{missing, false, false},
+	}
+	for _, c := range checks {
+		dir := got[c.path]
+		if dir.Exists != c.exists || dir.IsGitRepo != c.isGitRepo {
+			t.Errorf("%s: exists=%v isGitRepo=%v, want %v %v", c.path, dir.Exists, dir.IsGitRepo, c.exists, c.isGitRepo)
+		}
+	}
+}
+
+func TestListToleratesCorruptRecord(t *testing.T) {
+	svc, store, _ := newTestService(t)
+	if err := store.Put(context.Background(), statestore.BucketRecentDirectories, listKey, []byte("{not json")); err != nil {
+		t.Fatal(err)
+	}
+	dirs, err := svc.List()
+	if err != nil || len(dirs) != 0 {
+		t.Fatalf("List() = %v, %v; want empty list", dirs, err)
+	}
+	if err := svc.Record(t.TempDir()); err != nil {
+		t.Fatalf("Record() after corrupt record error = %v", err)
+	}
+}
+
+func TestStoreErrorPropagates(t *testing.T) {
+	wantErr := errors.New("store unavailable")
+	svc := NewService(Deps{Store: func() (statestore.Store, error) { return nil, wantErr }})
+	if _, err := svc.List(); !errors.Is(err, wantErr) {
+		t.Fatalf("List() error = %v, want %v", err, wantErr)
+	}
+	if err := svc.Record(t.TempDir()); !errors.Is(err, wantErr) {
+		t.Fatalf("Record() error = %v, want %v", err, wantErr)
+	}
+}
@@ -0,0 +1,70 @@
+package rendering
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// driverResetEventID is the "Display driver stopped responding and has
+// successfully recovered" event (a TDR) of the Display provider.
+const driverResetEventID = 4101
+
+// driverResetQuery returns the wevtutil XPath query for the driver resets
+// recorded since since.
+func driverResetQuery(now, since time.Time) string {
+	ms := max(now.Sub(since).Milliseconds(), 1)
+	return "*[System[Provider[@Name='Display'] and (EventID=4101) and TimeCreated[timediff(@SystemTime) <= " +
+		strconv.FormatInt(ms, 10) + "]]]"
+}
+
+// eventXML is the subset of a Windows event rendered by `wevtutil qe /f:xml`.
+type eventXML struct {
+	System struct {
+		Provider struct {
+			Name string `xml:"Name,attr"`
+		} `xml:"Provider"`
+		EventID     int `xml:"EventID"`
+		TimeCreated struct {
+			SystemTime string `xml:"SystemTime,attr"`
+		} `xml:"TimeCreated"`
+	} `xml:"System"`
+}
+
+// parseDriverResets parses the concatenated <Event> elements wevtutil prints.
+func parseDriverResets(output string) ([]DriverReset, error) {
+	decoder := xml.NewDecoder(strings.NewReader(output))
+	resets := []DriverReset{}
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return resets, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "Event" {
+			continue
+		}
+		var event eventXML
+		if err := decoder.DecodeElement(&event, &start); err != nil {
+			return nil, err
+		}
+		if event.System.EventID != driverResetEventID {
+			continue
+		}
+		at, err := time.Parse(time.RFC3339Nano, event.System.TimeCreated.SystemTime)
+		if err != nil {
+			continue
+		}
+		resets = append(resets, DriverReset{
+			Time:     at.UTC().Format(time.RFC3339),
+			Provider: event.System.Provider.Name,
+			EventID:  event.System.EventID,
+		})
+	}
+}
@@ -0,0 +1,14 @@
+//go:build !windows
+
+package rendering
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// queryDriverResets is unsupported outside Windows.
+func queryDriverResets(context.Context, time.Time) ([]DriverReset, error) {
+	return nil, errors.New("driver reset detection requires the Windows event log")
+}
@@ -0,0 +1,33 @@
+package rendering
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseDriverResets(t *testing.T) {
+	output := `<Event xmlns='http://schemas.microsoft.com/win/2004/08/events/event'><System><Provider Name='Display'/><EventID Qualifiers='0'>4101</EventID><TimeCreated SystemTime='2026-05-16T09:30:00.1234567Z'/></System><EventData><Data>nvlddmkm</Data></EventData></Event>
+<Event xmlns='http://schemas.microsoft.com/win/2004/08/events/event'><System><Provider Name='Display'/><EventID>4100</EventID><TimeCreated SystemTime='2026-05-16T08:00:00Z'/></System></Event>`
+	got, err := parseDriverResets(output)
+	if err != nil {
+		t.Fatalf("parseDriverResets() error = %v", err)
+	}
+	want := []DriverReset{{Time: "2026-05-16T09:30:00Z", Provider: "Display", EventID: 4101}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("parseDriverResets() = %+v, want %+v", got, want)
+	}
+
+	if got, err := parseDriverResets(""); err != nil || len(got) != 0 {
+		t.Fatalf("parseDriverResets(empty) = %+v, %v", got, err)
+	}
+}
+
+func TestDriverResetQuery(t *testing.T) {
+	now := time.Date(2026, 5, 16, 10, 0, 0, 0, time.UTC)
+	got := driverResetQuery(now, now.Add(-time.Hour))
+	if !strings.Contains(got, "EventID=4101") || !strings.Contains(got, "timediff(@SystemTime) <= 3600000]") {
+		t.Fatalf("driverResetQuery() = %q", got)
+	}
+}
@@ -0,0 +1,28 @@
+//go:build windows
+
+package rendering
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"myT-x/internal/procutil"
+)
+
+// maxDriverResets bounds the events read from the log.
+const maxDriverResets = 20
+
+// queryDriverResets reads the driver resets since since from the System log.
+func queryDriverResets(ctx context.Context, since time.Time) ([]DriverReset, error) {
+	cmd := exec.CommandContext(ctx, "wevtutil", "qe", "System",
+		"/q:"+driverResetQuery(time.Now(), since), "/f:xml", "/rd:true", fmt.Sprintf("/c:%d", maxDriverResets))
+	procutil.HideWindow(cmd)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("query system event log: %w", err)
+	}
+	return parseDriverResets(strings.TrimSpace(string(output)))
+}
@@ -0,0 +1,332 @@
+// Package rendering detects WebView2 rendering failures and keeps the
+// persisted flag that launches the window with software rendering.
+//
+// Two signals arm the fallback: a crash loop, where consecutive launches
+// never reach DOM ready (the blank-window symptom), and GPU driver resets
+// recorded in the Windows event log while the app runs on the GPU.
+package rendering
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"myT-x/internal/apptypes"
+)
+
+const (
+	// StateFileName is the JSON file the fallback flag is persisted to.
+	StateFileName = "rendering-state.json"
+	// FallbackArmedEvent is emitted with a FallbackArmedPayload when a driver
+	// reset arms software rendering for the next launch.
+	FallbackArmedEvent = "rendering:fallback-armed"
+	// CrashLoopThreshold is the number of consecutive launches without DOM
+	// ready after which the next launch uses software rendering.
+	CrashLoopThreshold = 2
+	// driverResetLookback bounds the driver resets listed in Diagnostics.
+	driverResetLookback = 7 * 24 * time.Hour
+	// defaultCheckInterval is how often Run looks for new driver resets.
+	defaultCheckInterval = 5 * time.Minute
+)
+
+// Reasons software rendering was enabled.
+const (
+	ReasonCrashLoop   = "crash_loop"
+	ReasonDriverReset = "driver_reset"
+	ReasonUser        = "user"
+)
+
+// DriverReset is one GPU driver reset recorded in the system event log.
+type DriverReset struct {
+	Time     string `json:"time"`
+	Provider string `json:"provider"`
+	EventID  int    `json:"event_id"`
+}
+
+// Diagnostics describes the rendering mode and the failures behind it.
+type Diagnostics struct {
+	// SoftwareRendering is the mode of the current launch.
+	SoftwareRendering bool `json:"software_rendering"`
+	// SoftwareRenderingNextLaunch is the persisted fallback flag.
+	SoftwareRenderingNextLaunch bool `json:"software_rendering_next_launch"`
+	// Reason is why the flag was set (crash_loop, driver_reset, user).
+	Reason        string `json:"reason,omitempty"`
+	FallbackSince string `json:"fallback_since,omitempty"`
+	// UnreadyLaunches counts the consecutive launches before this one that
+	// never reached DOM ready.
+	UnreadyLaunches int  `json:"unready_launches"`
+	Ready           bool `json:"ready"`
+	// DriverResets lists the driver resets of the last seven days, newest first.
+	DriverResets      []DriverReset `json:"driver_resets"`
+	DriverResetsError string        `json:"driver_resets_error,omitempty"`
+}
+
+// FallbackArmedPayload is the payload of FallbackArmedEvent.
+type FallbackArmedPayload struct {
+	Reason       string        `json:"reason"`
+	DriverResets []DriverReset `json:"driver_resets"`
+}
+
+// persistedState is the content of StateFileName.
+type persistedState struct {
+	SoftwareRendering bool   `json:"software_rendering"`
+	Reason            string `json:"reason,omitempty"`
+	Since             string `json:"since,omitempty"`
+	UnreadyLaunches   int    `json:"unready_launches"`
+}
+
+// Deps holds external dependencies injected at construction time.
+type Deps struct {
+	// StatePath returns the path of the state file.
+	StatePath func() (string, error)
+
+	// DriverResets returns the GPU driver resets recorded since since,
+	// newest first. Optional: defaults to the Windows System event log.
+	DriverResets func(ctx context.Context, since time.Time) ([]DriverReset, error)
+
+	// Emitter emits FallbackArmedEvent. Optional: defaults to a no-op emitter.
+	Emitter apptypes.RuntimeEventEmitter
+
+	// CheckInterval is how often Run looks for driver resets.
+	// Optional: defaults to five minutes.
+	CheckInterval time.Duration
+
+	// Now returns the current time. Optional: defaults to time.Now.
+	Now func() time.Time
+}
+
+// Monitor tracks launches and driver resets and owns the fallback flag.
+//
+// Thread-safety is managed internally via mu. No external locking is required.
+type Monitor struct {
+	deps Deps
+
+	mu             sync.Mutex
+	state          persistedState
+	loaded         bool
+	launchedAt     time.Time
+	launchSoftware bool
+	unreadyBefore  int
+	ready          bool
+}
+
+// NewMonitor creates a rendering monitor.
+// Panics if StatePath is nil.
+func NewMonitor(deps Deps) *Monitor {
+	if deps.StatePath == nil {
+		panic("rendering.NewMonitor: StatePath must be non-nil")
+	}
+	if deps.DriverResets == nil {
+		deps.DriverResets = queryDriverResets
+	}
+	if deps.Emitter == nil {
+		deps.Emitter = apptypes.NoopEmitter{}
+	}
+	if deps.CheckInterval <= 0 {
+		deps.CheckInterval = defaultCheckInterval
+	}
+	if deps.Now == nil {
+		deps.Now = time.Now
+	}
+	return &Monitor{deps: deps}
+}
+
+// BeginLaunch records a launch before the window is created and reports
+// whether it must use software rendering. The launch counts as unready
+// until MarkReady; CrashLoopThreshold unready launches in a row arm the
+// fallback.
+func (m *Monitor) BeginLaunch() (softwareRendering bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.loadLocked()
+	now := m.deps.Now()
+	m.launchedAt = now
+	m.unreadyBefore = m.state.UnreadyLaunches
+	if !m.state.SoftwareRendering && m.state.UnreadyLaunches >= CrashLoopThreshold {
+		m.state.SoftwareRendering = true
+		m.state.Reason = ReasonCrashLoop
+		m.state.Since = now.UTC().Format(time.RFC3339)
+		slog.Warn("[WARN-RENDER] window never became ready in consecutive launches, using software rendering",
+			"unreadyLaunches", m.state.UnreadyLaunches)
+	}
+	m.launchSoftware = m.state.SoftwareRendering
+	m.state.UnreadyLaunches++
+	if err := m.saveLocked(); err != nil {
+		slog.Warn("[WARN-RENDER] failed to save rendering state", "error", err)
+	}
+	return m.launchSoftware
+}
+
+// MarkReady records that the window content loaded, which ends a crash loop.
+func (m *Monitor) MarkReady() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.ready {
+		return
+	}
+	m.ready = true
+	m.state.UnreadyLaunches = 0
+	if err := m.saveLocked(); err != nil {
+		slog.Warn("[WARN-RENDER] failed to save rendering state", "error", err)
+	}
+}
+
+// SetSoftwareRendering sets the fallback flag for the next launch.
+func (m *Monitor) SetSoftwareRendering(enabled bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.loadLocked()
+	if enabled == m.state.SoftwareRendering {
+		return nil
+	}
+	m.state.SoftwareRendering = enabled
+	m.state.Reason = ""
+	m.state.Since = ""
+	if enabled {
+		m.state.Reason = ReasonUser
+		m.state.Since = m.deps.Now().UTC().Format(time.RFC3339)
+	}
+	return m.saveLocked()
+}
+
+// Diagnostics reports the rendering mode, the fallback flag, and the recent
+// driver resets.
+func (m *Monitor) Diagnostics(ctx context.Context) Diagnostics {
+	m.mu.Lock()
+	m.loadLocked()
+	diag := Diagnostics{
+		SoftwareRendering:           m.launchSoftware,
+		SoftwareRenderingNextLaunch: m.state.SoftwareRendering,
+		Reason:                      m.state.Reason,
+		FallbackSince:               m.state.Since,
+		UnreadyLaunches:             m.unreadyBefore,
+		Ready:                       m.ready,
+		DriverResets:                []DriverReset{},
+	}
+	m.mu.Unlock()
+
+	resets, err := m.deps.DriverResets(ctx, m.deps.Now().Add(-driverResetLookback))
+	if err != nil {
+		diag.DriverResetsError = err.Error()
+	} else if resets != nil {
+		diag.DriverResets = resets
+	}
+	return diag
+}
+
+// Run looks for driver resets every CheckInterval until ctx is cancelled.
+func (m *Monitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.deps.CheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.CheckDriverResets(ctx)
+		}
+	}
+}
+
+// CheckDriverResets arms software rendering for the next launch when the
+// GPU driver was reset since this launch started on the GPU. A reset loses
+// the WebView2 GPU device, which often leaves the window blank. It reports
+// whether the fallback was armed.
+func (m *Monitor) CheckDriverResets(ctx context.Context) bool {
+	m.mu.Lock()
+	m.loadLocked()
+	skip := m.launchSoftware || m.state.SoftwareRendering || m.launchedAt.IsZero()
+	since := m.launchedAt
+	m.mu.Unlock()
+	if skip {
+		return false
+	}
+
+	resets, err := m.deps.DriverResets(ctx, since)
+	if err != nil {
+		slog.Debug("[DEBUG-RENDER] driver reset query failed", "error", err)
+		return false
+	}
+	if len(resets) == 0 {
+		return false
+	}
+
+	m.mu.Lock()
+	if m.state.SoftwareRendering {
+		m.mu.Unlock()
+		return false
+	}
+	m.state.SoftwareRendering = true
+	m.state.Reason = ReasonDriverReset
+	m.state.Since = m.deps.Now().UTC().Format(time.RFC3339)
+	if err := m.saveLocked(); err != nil {
+		slog.Warn("[WARN-RENDER] failed to save rendering state", "error", err)
+	}
+	m.mu.Unlock()
+
+	slog.Warn("[WARN-RENDER] GPU driver reset detected, software rendering armed for the next launch",
+		"resets", len(resets), "latest", resets[0].Time)
+	m.deps.Emitter.Emit(FallbackArmedEvent, FallbackArmedPayload{Reason: ReasonDriverReset, DriverResets: resets})
+	return true
+}
+
+// loadLocked reads the state file once.
+//
+// REQUIRES: m.mu must be held by the caller.
+func (m *Monitor) loadLocked() {
+	if m.loaded {
+		return
+	}
+	m.loaded = true
+	path, err := m.deps.StatePath()
+	if err != nil {
+		slog.Warn("[WARN-RENDER] rendering state unavailable", "error", err)
+		return
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			slog.Warn("[WARN-RENDER] failed to read rendering state", "path", path, "error", err)
+		}
+		return
+	}
+	var state persistedState
+	if err := json.Unmarshal(data, &state); err != nil {
+		slog.Warn("[WARN-RENDER] ignoring unreadable rendering state", "path", path, "error", err)
+		return
+	}
+	m.state = state
+}
+
+// saveLocked writes the state file atomically.
+//
+// REQUIRES: m.mu must be held by the caller.
+func (m *Monitor) saveLocked() error {
+	path, err := m.deps.StatePath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(m.state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal rendering state: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create rendering state directory: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("write rendering state: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("replace rendering state: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package rendering
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"myT-x/internal/apptypes"
+)
+
+type monitorHarness struct {
+	path   string
+	now    time.Time
+	resets []DriverReset
+	events []FallbackArmedPayload
+}
+
+func (h *monitorHarness) newMonitor() *Monitor {
+	return NewMonitor(Deps{
+		StatePath: func() (string, error) { return h.path, nil },
+		DriverResets: func(_ context.Context, since time.Time) ([]DriverReset, error) {
+			var out []DriverReset
+			for _, reset := range h.resets {
+				if at, _ := time.Parse(time.RFC3339, reset.Time); !at.Before(since) {
+					out = append(out, reset)
+				}
+			}
+			return out, nil
+		},
+		Emitter: apptypes.EventEmitterFunc(func(name string, payload any) {
+			if name == FallbackArmedEvent {
+				h.events = append(h.events, payload.(FallbackArmedPayload))
+			}
+		}),
+		Now: func() time.Time { return h.now },
+	})
+}
+
+func newMonitorHarness(t *testing.T) *monitorHarness {
+	t.Helper()
+	return &monitorHarness{
+		path: filepath.Join(t.TempDir(), StateFileName),
+		now:  time.Date(2026, 5, 16, 10, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestBeginLaunchFallsBackAfterCrashLoop(t *testing.T) {
+	h := newMonitorHarness(t)
+
+	// A ready launch resets the count.
+	m := h.newMonitor()
+	if m.BeginLaunch() {
+		t.Fatal("first launch uses software rendering")
+	}
+	m.MarkReady()
+
+	for i := range CrashLoopThreshold {
+		if h.newMonitor().BeginLaunch() {
+			t.Fatalf("unready launch %d uses software rendering", i+1)
+		}
+	}
+	m = h.newMonitor()
+	if !m.BeginLaunch() {
+		t.Fatal("launch after a crash loop uses the GPU, want software rendering")
+	}
+	diag := m.Diagnostics(context.Background())
+	if !diag.SoftwareRendering || !diag.SoftwareRenderingNextLaunch || diag.Reason != ReasonCrashLoop ||
+		diag.UnreadyLaunches != CrashLoopThreshold {
+		t.Fatalf("Diagnostics() = %+v", diag)
+	}
+
+	// The flag persists after recovery until the user clears it.
+	m.MarkReady()
+	if !h.newMonitor().BeginLaunch() {
+		t.Fatal("fallback flag was not persisted")
+	}
+	if err := h.newMonitor().SetSoftwareRendering(false); err != nil {
+		t.Fatal(err)
+	}
+	m = h.newMonitor()
+	if m.BeginLaunch() {
+		t.Fatal("launch after clearing the flag uses software rendering")
+	}
+}
+
+func TestCheckDriverResetsArmsFallback(t *testing.T) {
+	h := newMonitorHarness(t)
+	h.resets = []DriverReset{{Time: "2026-05-16T09:00:00Z", Provider: "Display", EventID: 4101}}
+	m := h.newMonitor()
+	m.BeginLaunch()
+	m.MarkReady()
+
+	if m.CheckDriverResets(context.Background()) {
+		t.Fatal("a reset before the launch armed the fallback")
+	}
+	h.resets = append([]DriverReset{{Time: "2026-05-16T10:05:00Z", Provider: "Display", EventID: 4101}}, h.resets...)
+	h.now = h.now.Add(10 * time.Minute)
+	if !m.CheckDriverResets(context.Background()) {
+		t.Fatal("a reset during the launch did not arm the fallback")
+	}
+	if len(h.events) != 1 || h.events[0].Reason != ReasonDriverReset || len(h.events[0].DriverResets) != 1 {
+		t.Fatalf("events = %+v", h.events)
+	}
+	if m.CheckDriverResets(context.Background()) {
+		t.Fatal("an armed fallback was armed again")
+	}
+	diag := m.Diagnostics(context.Background())
+	if diag.SoftwareRendering || !diag.SoftwareRenderingNextLaunch || len(diag.DriverResets) != 2 {
+		t.Fatalf("Diagnostics() = %+v", diag)
+	}
+}
@@ -0,0 +1,103 @@
+// Package repoconfig loads the per-repository .mytx.yaml and gates it behind
+// a persisted directory trust store, mirroring VS Code's workspace trust:
+// repo-provided setup scripts and startup commands are arbitrary code, so they
+// only run once the user has marked the directory (or a parent) as trusted.
+package repoconfig
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+
+	"myT-x/internal/config"
+)
+
+const (
+	// FileName is the per-repository config file looked up in the repository root.
+	FileName = ".mytx.yaml"
+	// maxConfigSize bounds the bytes read from FileName.
+	maxConfigSize = 64 * 1024
+	// maxSetupScripts bounds the setup_scripts entries honored from FileName.
+	maxSetupScripts = 32
+)
+
+// Config is the subset of settings a repository may provide.
+type Config struct {
+	// SetupScripts run after a worktree of the repository is created, after
+	// the global worktree.setup_scripts.
+	SetupScripts []string `yaml:"setup_scripts" json:"setup_scripts"`
+	// StartupCommand is typed into the initial pane of new sessions when the
+	// caller did not pass one. It takes precedence over startup_commands.session.
+	StartupCommand string `yaml:"startup_command" json:"startup_command"`
+}
+
+// IsEmpty reports whether the config carries nothing that would be executed.
+func (c *Config) IsEmpty() bool {
+	return c == nil || (len(c.SetupScripts) == 0 && c.StartupCommand == "")
+}
+
+// Parse decodes and normalizes the contents of a FileName.
+func Parse(data []byte) (*Config, error) {
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", FileName, err)
+	}
+	if err := cfg.normalize(); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", FileName, err)
+	}
+	return &cfg, nil
+}
+
+// readLimited reads at most limit bytes of path. Returns (nil, nil) when the
+// file does not exist.
+func readLimited(path string, limit int64) ([]byte, error) {
+	name := filepath.Base(path)
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("open %s: %w", name, err)
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(io.LimitReader(f, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", name, err)
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("%s exceeds %d bytes", name, limit)
+	}
+	return data, nil
+}
+
+// normalize trims entries and rejects values that cannot be run safely.
+func (c *Config) normalize() error {
+	scripts := make([]string, 0, len(c.SetupScripts))
+	for _, script := range c.SetupScripts {
+		script = strings.TrimSpace(script)
+		if script == "" {
+			continue
+		}
+		if strings.ContainsRune(script, '\x00') {
+			return errors.New("setup_scripts entries must not contain null bytes")
+		}
+		scripts = append(scripts, script)
+	}
+	if len(scripts) > maxSetupScripts {
+		return fmt.Errorf("setup_scripts has %d entries, at most %d are allowed", len(scripts), maxSetupScripts)
+	}
+	c.SetupScripts = scripts
+
+	c.StartupCommand = config.NormalizeStartupCommand(c.StartupCommand)
+	if !config.IsValidStartupCommand(c.StartupCommand) {
+		return fmt.Errorf("startup_command must be a single line of at most %d characters", config.MaxStartupCommandLen)
+	}
+	return nil
+}
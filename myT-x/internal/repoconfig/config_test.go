@@ -0,0 +1,61 @@
+package repoconfig
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeRepoConfig(t *testing.T, dir, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, FileName), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestResolveNormalizesTrustedConfig(t *testing.T) {
+	svc, _, _ := newTestService(t)
+	repo := t.TempDir()
+	writeRepoConfig(t, repo, "setup_scripts:\n  - ' npm ci '\n  - ''\n  - make gen\nstartup_command: '  npm run dev  '\n")
+	if err := svc.SetTrust(repo, LevelTrusted); err != nil {
+		t.Fatal(err)
+	}
+	cfg := svc.Resolve(repo)
+	if cfg == nil || strings.Join(cfg.SetupScripts, "|") != "npm ci|make gen" || cfg.StartupCommand != "npm run dev" {
+		t.Fatalf("Resolve() = %+v", cfg)
+	}
+	if cfg.IsEmpty() {
+		t.Fatal("IsEmpty() = true, want false")
+	}
+}
+
+func TestStatusRejectsInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "invalid yaml", content: "setup_scripts: [", wantErr: "parse"},
+		{name: "multi-line startup command", content: "startup_command: \"a\\nb\"\n", wantErr: "single line"},
+		{name: "too many scripts", content: "setup_scripts: [" + strings.Repeat("x,", maxSetupScripts) + "x]\n", wantErr: "at most"},
+		{name: "too large", content: "startup_command: " + strings.Repeat("x", maxConfigSize) + "\n", wantErr: "exceeds"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _, _ := newTestService(t)
+			repo := t.TempDir()
+			writeRepoConfig(t, repo, tt.content)
+			if err := svc.SetTrust(repo, LevelTrusted); err != nil {
+				t.Fatal(err)
+			}
+			status, err := svc.Status(repo)
+			if err != nil || status.Config != nil || !strings.Contains(status.ConfigError, tt.wantErr) {
+				t.Fatalf("Status() = %+v, %v; want config error containing %q", status, err, tt.wantErr)
+			}
+			if cfg := svc.Resolve(repo); cfg != nil {
+				t.Fatalf("Resolve() = %+v, want nil", cfg)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package repoconfig
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+
+	"myT-x/internal/config"
+	"myT-x/internal/sshsig"
+)
+
+const (
+	// SignatureFileName is the detached signature committed next to FileName,
+	// produced with `ssh-keygen -Y sign -n mytx-repo-config -f <key> .mytx.yaml`.
+	SignatureFileName = FileName + ".sig"
+	// SignatureNamespace is the ssh-keygen -n namespace repo configs are signed in.
+	SignatureNamespace = "mytx-repo-config"
+	// maxSignatureSize bounds the bytes read from SignatureFileName.
+	maxSignatureSize = 16 * 1024
+)
+
+// SignatureState is the outcome of verifying SignatureFileName.
+type SignatureState string
+
+const (
+	// SignatureNone means the repo config is unsigned and no signature is required.
+	SignatureNone SignatureState = ""
+	// SignatureValid means the signature verified against a configured signing key.
+	SignatureValid SignatureState = "valid"
+	// SignatureUnverified means a signature exists but no signing keys are
+	// configured, so it was not checked.
+	SignatureUnverified SignatureState = "unverified"
+	// SignatureInvalid means the signature failed verification; the repo config is rejected.
+	SignatureInvalid SignatureState = "invalid"
+	// SignatureMissing means a signature is required (require_signature or
+	// any signing_keys) but the repo config is unsigned; the repo config is
+	// rejected.
+	SignatureMissing SignatureState = "missing"
+)
+
+// verifySignature checks data against SignatureFileName in dir under the
+// configured signing policy. It returns the signer label on success and a
+// non-nil error when the repo config must not be honored.
+//
+// Configured signing keys imply a required signature: otherwise deleting the
+// .sig file would turn a rejected repo config into an accepted unsigned one.
+func verifySignature(dir string, data []byte, settings *config.RepoConfigSettings) (SignatureState, string, error) {
+	signature, err := readLimited(filepath.Join(dir, SignatureFileName), maxSignatureSize)
+	if err != nil {
+		return SignatureInvalid, "", err
+	}
+
+	var (
+		keyLines []string
+		required bool
+	)
+	if settings != nil {
+		keyLines, required = settings.SigningKeys, settings.RequireSignature
+	}
+	keys := make([]sshsig.PublicKey, 0, len(keyLines))
+	for _, line := range keyLines {
+		key, err := sshsig.ParseAuthorizedKey(line)
+		if err != nil {
+			// Config validation drops unparsable keys; this only guards callers
+			// that bypass it.
+			slog.Debug("[DEBUG-REPOCONFIG] skipping invalid signing key", "error", err)
+			continue
+		}
+		keys = append(keys, key)
+	}
+
+	if signature == nil {
+		switch {
+		case required:
+			return SignatureMissing, "", fmt.Errorf("%s is not signed; repo_config.require_signature is enabled", FileName)
+		case len(keys) > 0:
+			return SignatureMissing, "", fmt.Errorf("%s is not signed; repo_config.signing_keys requires a signature", FileName)
+		}
+		return SignatureNone, "", nil
+	}
+	if len(keys) == 0 {
+		if required {
+			return SignatureInvalid, "", fmt.Errorf("%s cannot be verified: repo_config.signing_keys is empty", SignatureFileName)
+		}
+		return SignatureUnverified, "", nil
+	}
+
+	signer, err := sshsig.Verify(data, signature, SignatureNamespace, keys)
+	if err != nil {
+		return SignatureInvalid, "", fmt.Errorf("%s rejected: %w", SignatureFileName, err)
+	}
+	if signer.Comment != "" {
+		return SignatureValid, signer.Comment, nil
+	}
+	return SignatureValid, signer.Fingerprint(), nil
+}
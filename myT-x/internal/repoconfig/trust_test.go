@@ -0,0 +1,165 @@
+package repoconfig
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type recordingEmitter struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (e *recordingEmitter) Emit(name string, _ any) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.events = append(e.events, name)
+}
+
+func (e *recordingEmitter) EmitWithContext(_ context.Context, name string, payload any) {
+	e.Emit(name, payload)
+}
+
+func (e *recordingEmitter) names() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]string(nil), e.events...)
+}
+
+func newTestService(t *testing.T) (*Service, *recordingEmitter, string) {
+	t.Helper()
+	configDir := t.TempDir()
+	emitter := &recordingEmitter{}
+	svc := NewService(Deps{
+		ConfigDir: func() (string, error) { return configDir, nil },
+		Emitter:   emitter,
+	})
+	return svc, emitter, configDir
+}
+
+func TestResolveRequiresTrust(t *testing.T) {
+	svc, emitter, _ := newTestService(t)
+	repo := t.TempDir()
+	writeRepoConfig(t, repo, "startup_command: npm run dev\n")
+
+	if cfg := svc.Resolve(repo); cfg != nil {
+		t.Fatalf("Resolve() before trust = %+v, want nil", cfg)
+	}
+	if got := emitter.names(); len(got) != 1 || got[0] != TrustRequiredEvent {
+		t.Fatalf("events = %v, want one %s", got, TrustRequiredEvent)
+	}
+
+	if err := svc.SetTrust(repo, LevelTrusted); err != nil {
+		t.Fatalf("SetTrust() error = %v", err)
+	}
+	cfg := svc.Resolve(repo)
+	if cfg == nil || cfg.StartupCommand != "npm run dev" {
+		t.Fatalf("Resolve() after trust = %+v", cfg)
+	}
+
+	if err := svc.SetTrust(repo, LevelUntrusted); err != nil {
+		t.Fatalf("SetTrust() error = %v", err)
+	}
+	if cfg := svc.Resolve(repo); cfg != nil {
+		t.Fatalf("Resolve() when untrusted = %+v, want nil", cfg)
+	}
+	// Untrusted directories are not prompted for again.
+	for _, name := range emitter.names()[1:] {
+		if name == TrustRequiredEvent {
+			t.Fatalf("events = %v, want no prompt for untrusted directory", emitter.names())
+		}
+	}
+}
+
+func TestResolveWithoutConfigDoesNotPrompt(t *testing.T) {
+	svc, emitter, _ := newTestService(t)
+	if cfg := svc.Resolve(t.TempDir()); cfg != nil {
+		t.Fatalf("Resolve() = %+v, want nil", cfg)
+	}
+	if got := emitter.names(); len(got) != 0 {
+		t.Fatalf("events = %v, want none", got)
+	}
+}
+
+func TestTrustIsInheritedAndPersisted(t *testing.T) {
+	svc, _, configDir := newTestService(t)
+	parent := t.TempDir()
+	child := filepath.Join(parent, "work", "repo")
+	if err := os.MkdirAll(child, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.SetTrust(parent, LevelTrusted); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.SetTrust(filepath.Join(parent, "work"), LevelUntrusted); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded := NewService(Deps{ConfigDir: func() (string, error) { return configDir, nil }})
+	status, err := reloaded.Status(child)
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	if status.Level != LevelUntrusted || status.TrustedBy != filepath.Join(parent, "work") {
+		t.Fatalf("Status() = %+v, want closest ancestor decision", status)
+	}
+	status, _ = reloaded.Status(parent)
+	if status.Level != LevelTrusted {
+		t.Fatalf("Status(parent) = %+v, want trusted", status)
+	}
+	// Sibling prefixes must not match.
+	status, _ = reloaded.Status(parent + "-other")
+	if status.Level != LevelUnknown {
+		t.Fatalf("Status(sibling) = %+v, want unknown", status)
+	}
+
+	if err := reloaded.SetTrust(filepath.Join(parent, "work"), LevelUnknown); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := reloaded.Entries()
+	if err != nil || len(entries) != 1 || entries[0].Path != parent {
+		t.Fatalf("Entries() = %+v, %v; want only parent", entries, err)
+	}
+}
+
+func TestStatusReportsConfigError(t *testing.T) {
+	svc, emitter, _ := newTestService(t)
+	repo := t.TempDir()
+	writeRepoConfig(t, repo, "setup_scripts: [")
+	if err := svc.SetTrust(repo, LevelTrusted); err != nil {
+		t.Fatal(err)
+	}
+
+	status, err := svc.Status(repo)
+	if err != nil || status.ConfigError == "" || status.Config != nil {
+		t.Fatalf("Status() = %+v, %v; want config error", status, err)
+	}
+	if cfg := svc.Resolve(repo); cfg != nil {
+		t.Fatalf("Resolve() = %+v, want nil for broken config", cfg)
+	}
+	if got := emitter.names(); len(got) != 1 || got[0] != TrustChangedEvent {
+		t.Fatalf("events = %v, want only %s", got, TrustChangedEvent)
+	}
+}
+
+func TestTrustStoreErrors(t *testing.T) {
+	svc, _, configDir := newTestService(t)
+	if err := svc.SetTrust(t.TempDir(), Level("always")); err == nil || !strings.Contains(err.Error(), "invalid trust level") {
+		t.Fatalf("SetTrust(invalid) error = %v", err)
+	}
+	if err := svc.SetTrust(" ", LevelTrusted); err == nil {
+		t.Fatal("SetTrust(empty) error = nil")
+	}
+
+	if err := os.WriteFile(filepath.Join(configDir, trustStoreFileName), []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	corrupt := NewService(Deps{ConfigDir: func() (string, error) { return configDir, nil }})
+	if _, err := corrupt.Status(t.TempDir()); err == nil || !strings.Contains(err.Error(), "parse trust store") {
+		t.Fatalf("Status() with corrupt store error = %v", err)
+	}
+}
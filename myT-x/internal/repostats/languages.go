@@ -0,0 +1,100 @@
+package repostats
+
+import (
+	"path"
+	"strings"
+)
+
+// languageOther groups text files whose language is not recognized.
+const languageOther = "Other"
+
+// languageByExtension maps lowercase file extensions (with dot) to languages.
+var languageByExtension = map[string]string{
+	".go":     "Go",
+	".ts":     "TypeScript",
+	".tsx":    "TypeScript",
+	".mts":    "TypeScript",
+	".cts":    "TypeScript",
+	".js":     "JavaScript",
+	".jsx":    "JavaScript",
+	".mjs":    "JavaScript",
+	".cjs":    "JavaScript",
+	".py":     "Python",
+	".rs":     "Rust",
+	".java":   "Java",
+	".kt":     "Kotlin",
+	".kts":    "Kotlin",
+	".scala":  "Scala",
+	".cs":     "C#",
+	".fs":     "F#",
+	".vb":     "Visual Basic",
+	".c":      "C",
+	".h":      "C",
+	".cc":     "C++",
+	".cpp":    "C++",
+	".cxx":    "C++",
+	".hpp":    "C++",
+	".hh":     "C++",
+	".m":      "Objective-C",
+	".mm":     "Objective-C",
+	".swift":  "Swift",
+	".rb":     "Ruby",
+	".php":    "PHP",
+	".lua":    "Lua",
+	".dart":   "Dart",
+	".ex":     "Elixir",
+	".exs":    "Elixir",
+	".erl":    "Erlang",
+	".hs":     "Haskell",
+	".clj":    "Clojure",
+	".zig":    "Zig",
+	".r":      "R",
+	".jl":     "Julia",
+	".pl":     "Perl",
+	".sh":     "Shell",
+	".bash":   "Shell",
+	".zsh":    "Shell",
+	".ps1":    "PowerShell",
+	".psm1":   "PowerShell",
+	".bat":    "Batch",
+	".cmd":    "Batch",
+	".sql":    "SQL",
+	".html":   "HTML",
+	".htm":    "HTML",
+	".css":    "CSS",
+	".scss":   "SCSS",
+	".sass":   "SCSS",
+	".less":   "Less",
+	".vue":    "Vue",
+	".svelte": "Svelte",
+	".md":     "Markdown",
+	".mdx":    "Markdown",
+	".json":   "JSON",
+	".yaml":   "YAML",
+	".yml":    "YAML",
+	".toml":   "TOML",
+	".xml":    "XML",
+	".proto":  "Protocol Buffers",
+	".tf":     "Terraform",
+}
+
+// languageByName maps lowercase file names without a telling extension.
+var languageByName = map[string]string{
+	"dockerfile":     "Dockerfile",
+	"makefile":       "Makefile",
+	"gnumakefile":    "Makefile",
+	"cmakelists.txt": "CMake",
+}
+
+// detectLanguage returns the language of a root-relative path, or
+// languageOther when it is not recognized.
+func detectLanguage(relPath string) string {
+	name := strings.ToLower(path.Base(relPath))
+	if language, ok := languageByName[name]; ok {
+		return language
+	}
+	if language, ok := languageByExtension[path.Ext(name)]; ok {
+		return language
+	}
+	return languageOther
+}
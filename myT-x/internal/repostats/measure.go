@@ -0,0 +1,77 @@
+package repostats
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// binaryProbeSize is how much of a file is checked for NUL bytes.
+	binaryProbeSize = 8 * 1024
+	// maxLineCountSize is the largest file whose lines are counted; larger
+	// files still contribute their size.
+	maxLineCountSize = 16 * 1024 * 1024
+)
+
+// measureFile counts the lines of one root-relative file. ok is false when the
+// file is missing, not regular, or a symlink.
+func measureFile(root, relPath string) (fileStats, bool) {
+	localPath := filepath.FromSlash(relPath)
+	if !filepath.IsLocal(localPath) {
+		return fileStats{}, false
+	}
+	absPath := filepath.Join(root, localPath)
+	info, err := os.Lstat(absPath)
+	if err != nil || !info.Mode().IsRegular() {
+		return fileStats{}, false
+	}
+	stats := fileStats{
+		language: detectLanguage(relPath),
+		bytes:    info.Size(),
+	}
+
+	f, err := os.Open(absPath)
+	if err != nil {
+		return fileStats{}, false
+	}
+	defer f.Close()
+
+	probe := make([]byte, binaryProbeSize)
+	n, err := io.ReadFull(f, probe)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return fileStats{}, false
+	}
+	probe = probe[:n]
+	if bytes.IndexByte(probe, 0) >= 0 {
+		stats.binary = true
+		return stats, true
+	}
+	if info.Size() > maxLineCountSize {
+		return stats, true
+	}
+
+	stats.lines = bytes.Count(probe, []byte{'\n'})
+	last := byte('\n')
+	if n > 0 {
+		last = probe[n-1]
+	}
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := f.Read(buf)
+		if n > 0 {
+			stats.lines += bytes.Count(buf[:n], []byte{'\n'})
+			last = buf[n-1]
+		}
+		if err != nil {
+			break
+		}
+	}
+	// A final line without a trailing newline still counts.
+	if last != '\n' {
+		stats.lines++
+	}
+	return stats, true
+}
@@ -0,0 +1,71 @@
+package repostats
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMeasureFile(t *testing.T) {
+	root := t.TempDir()
+	writeRepoFiles(t, root, map[string]string{
+		"empty.txt":     "",
+		"one.txt":       "one",
+		"two.txt":       "one\ntwo\n",
+		"crlf.txt":      "one\r\ntwo",
+		"large.txt":     strings.Repeat("line\n", binaryProbeSize),
+		"bin.dat":       "ab\x00cd",
+		"sub/script.sh": "echo\n",
+	})
+	tests := []struct {
+		path       string
+		wantLines  int
+		wantBinary bool
+		wantLang   string
+	}{
+		{path: "empty.txt", wantLines: 0, wantLang: languageOther},
+		{path: "one.txt", wantLines: 1, wantLang: languageOther},
+		{path: "two.txt", wantLines: 2, wantLang: languageOther},
+		{path: "crlf.txt", wantLines: 2, wantLang: languageOther},
+		{path: "large.txt", wantLines: binaryProbeSize, wantLang: languageOther},
+		{path: "bin.dat", wantBinary: true, wantLang: languageOther},
+		{path: "sub/script.sh", wantLines: 1, wantLang: "Shell"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, ok := measureFile(root, tt.path)
+			if !ok {
+				t.Fatal("measureFile() ok = false")
+			}
+			if got.lines != tt.wantLines || got.binary != tt.wantBinary || got.language != tt.wantLang {
+				t.Fatalf("measureFile() = %+v, want lines=%d binary=%v language=%s", got, tt.wantLines, tt.wantBinary, tt.wantLang)
+			}
+		})
+	}
+
+	if _, ok := measureFile(root, "missing.txt"); ok {
+		t.Fatal("measureFile(missing) ok = true")
+	}
+	if _, ok := measureFile(root, "../escape.txt"); ok {
+		t.Fatal("measureFile(../escape.txt) ok = true")
+	}
+	if _, ok := measureFile(root, filepath.ToSlash("sub")); ok {
+		t.Fatal("measureFile(directory) ok = true")
+	}
+}
+
+func TestDetectLanguage(t *testing.T) {
+	tests := map[string]string{
+		"a/b/main.go":        "Go",
+		"App.TSX":            "TypeScript",
+		"Dockerfile":         "Dockerfile",
+		"sub/CMakeLists.txt": "CMake",
+		"notes.txt":          languageOther,
+		"LICENSE":            languageOther,
+	}
+	for path, want := range tests {
+		if got := detectLanguage(path); got != want {
+			t.Errorf("detectLanguage(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,424 @@
+// Package repostats computes per-language file, line, and byte counts for a
+// repository so the new-session and worktree dialogs can show what a
+// directory contains. Results are cached per repository and refreshed
+// incrementally: a file watcher marks changed paths dirty and only those are
+// re-read on the next request.
+package repostats
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	gitpkg "myT-x/internal/git"
+)
+
+const (
+	// DefaultMaxRepos is the number of repositories kept in the cache when
+	// Deps.MaxRepos is zero. The least recently used entry is evicted first.
+	DefaultMaxRepos = 8
+	// DefaultUnwatchedTTL is how long stats of a repository without a working
+	// watcher are reused before a full rescan, used when Deps.UnwatchedTTL is zero.
+	DefaultUnwatchedTTL = 30 * time.Second
+	// maxScanFiles bounds the number of files counted per repository.
+	maxScanFiles = 50000
+)
+
+// skippedDirs are never walked or watched.
+var skippedDirs = []string{".git", "node_modules"}
+
+// Deps holds optional settings injected at construction time.
+type Deps struct {
+	// MaxRepos bounds the cache size. Optional: defaults to DefaultMaxRepos.
+	MaxRepos int
+
+	// UnwatchedTTL is the cache lifetime for repositories that could not be
+	// watched. Optional: defaults to DefaultUnwatchedTTL.
+	UnwatchedTTL time.Duration
+
+	// ListFiles returns root-relative forward-slash paths of the files to count
+	// and whether root is a git repository.
+	// Optional: defaults to git ls-files, or a directory walk outside git.
+	ListFiles func(root string) (paths []string, isGitRepo bool, err error)
+
+	// Now returns the current time. Optional: defaults to time.Now.
+	Now func() time.Time
+}
+
+// Service caches repository stats.
+//
+// Thread-safety is managed internally via mu and per-entry locks.
+// No external locking is required.
+type Service struct {
+	deps Deps
+
+	mu      sync.Mutex
+	entries map[string]*repoEntry
+	closed  bool
+}
+
+// repoEntry is the cached state of one repository.
+type repoEntry struct {
+	root string
+
+	// scanMu serializes scans of this repository.
+	scanMu sync.Mutex
+	// files is only accessed with scanMu held.
+	files map[string]fileStats
+
+	// mu guards the fields below, which the watcher goroutine also updates.
+	mu sync.Mutex
+	// dirty holds paths whose content changed since the last scan.
+	dirty map[string]struct{}
+	// relist is set when files may have been added or removed.
+	relist bool
+	// rescan discards all cached file stats on the next scan.
+	rescan    bool
+	scanned   bool
+	scannedAt time.Time
+	lastUsed  time.Time
+	stats     RepoStats
+	watcher   *repoWatcher
+}
+
+// NewService creates a repository stats service.
+func NewService(deps Deps) *Service {
+	if deps.MaxRepos <= 0 {
+		deps.MaxRepos = DefaultMaxRepos
+	}
+	if deps.UnwatchedTTL <= 0 {
+		deps.UnwatchedTTL = DefaultUnwatchedTTL
+	}
+	if deps.ListFiles == nil {
+		deps.ListFiles = listRepoFiles
+	}
+	if deps.Now == nil {
+		deps.Now = time.Now
+	}
+	return &Service{
+		deps:    deps,
+		entries: map[string]*repoEntry{},
+	}
+}
+
+// Stats returns the language breakdown of repoPath. The first call scans the
+// whole tree; later calls re-read only files the watcher reported as changed.
+func (s *Service) Stats(repoPath string) (RepoStats, error) {
+	root, err := normalizeRoot(repoPath)
+	if err != nil {
+		return RepoStats{}, err
+	}
+	entry, err := s.entry(root)
+	if err != nil {
+		return RepoStats{}, err
+	}
+
+	entry.scanMu.Lock()
+	defer entry.scanMu.Unlock()
+
+	entry.mu.Lock()
+	now := s.deps.Now()
+	entry.lastUsed = now
+	fresh := entry.scanned && !entry.relist && !entry.rescan && len(entry.dirty) == 0 &&
+		(entry.watcher != nil || now.Sub(entry.scannedAt) < s.deps.UnwatchedTTL)
+	if fresh {
+		stats := cloneStats(entry.stats)
+		entry.mu.Unlock()
+		return stats, nil
+	}
+	// Unwatched repositories cannot tell what changed, so expiry forces a full rescan.
+	rescan := entry.rescan || !entry.scanned || entry.watcher == nil
+	dirty := entry.dirty
+	entry.dirty = map[string]struct{}{}
+	entry.relist = false
+	entry.rescan = false
+	entry.mu.Unlock()
+
+	stats, err := s.scan(entry, rescan, dirty)
+	if err != nil {
+		// Keep the invalidation so the next request retries.
+		entry.mu.Lock()
+		entry.rescan = true
+		entry.mu.Unlock()
+		return RepoStats{}, err
+	}
+
+	entry.mu.Lock()
+	entry.stats = stats
+	entry.scanned = true
+	entry.scannedAt = now
+	entry.mu.Unlock()
+	return cloneStats(stats), nil
+}
+
+// Close stops all watchers and drops the cache. Stats fails after Close.
+func (s *Service) Close() {
+	s.mu.Lock()
+	entries := s.entries
+	s.entries = map[string]*repoEntry{}
+	s.closed = true
+	s.mu.Unlock()
+
+	for _, entry := range entries {
+		entry.closeWatcher()
+	}
+}
+
+// Compact drops cached repositories not requested within maxIdle and stops
+// their watchers. It returns the number of entries dropped.
+func (s *Service) Compact(maxIdle time.Duration) int {
+	s.mu.Lock()
+	now := s.deps.Now()
+	var dropped []*repoEntry
+	for key, entry := range s.entries {
+		entry.mu.Lock()
+		lastUsed := entry.lastUsed
+		entry.mu.Unlock()
+		if lastUsed.IsZero() || now.Sub(lastUsed) < maxIdle {
+			continue
+		}
+		dropped = append(dropped, entry)
+		delete(s.entries, key)
+	}
+	s.mu.Unlock()
+
+	for _, entry := range dropped {
+		entry.closeWatcher()
+	}
+	return len(dropped)
+}
+
+// entry returns the cache entry of root, creating it (and its watcher) and
+// evicting the least recently used entry when needed.
+func (s *Service) entry(root string) (*repoEntry, error) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil, errors.New("repository stats service is closed")
+	}
+	if entry, ok := s.entries[root]; ok {
+		s.mu.Unlock()
+		return entry, nil
+	}
+	entry := &repoEntry{
+		root:  root,
+		files: map[string]fileStats{},
+		dirty: map[string]struct{}{},
+	}
+	s.entries[root] = entry
+	evicted := s.evictLocked()
+	s.mu.Unlock()
+
+	for _, old := range evicted {
+		old.closeWatcher()
+	}
+
+	watcher, err := newRepoWatcher(root, entry)
+	if err != nil {
+		slog.Debug("[REPOSTATS] watcher unavailable, falling back to TTL cache",
+			"root", root, "error", err)
+		return entry, nil
+	}
+	entry.mu.Lock()
+	entry.watcher = watcher
+	entry.mu.Unlock()
+
+	// Close may have run while the watcher was starting.
+	s.mu.Lock()
+	stale := s.entries[root] != entry
+	s.mu.Unlock()
+	if stale {
+		entry.closeWatcher()
+	}
+	return entry, nil
+}
+
+// evictLocked removes least recently used entries beyond MaxRepos and returns
+// them so their watchers can be closed without holding s.mu.
+func (s *Service) evictLocked() []*repoEntry {
+	var evicted []*repoEntry
+	for len(s.entries) > s.deps.MaxRepos {
+		var (
+			oldestKey  string
+			oldestUsed time.Time
+			found      bool
+		)
+		for key, entry := range s.entries {
+			entry.mu.Lock()
+			lastUsed := entry.lastUsed
+			entry.mu.Unlock()
+			// Entries that were never used are the ones just created.
+			if lastUsed.IsZero() {
+				continue
+			}
+			if !found || lastUsed.Before(oldestUsed) {
+				oldestKey, oldestUsed, found = key, lastUsed, true
+			}
+		}
+		if !found {
+			break
+		}
+		evicted = append(evicted, s.entries[oldestKey])
+		delete(s.entries, oldestKey)
+	}
+	return evicted
+}
+
+// scan refreshes entry.files and aggregates them. Only new and dirty files are
+// re-read unless rescan is set. Called with entry.scanMu held.
+func (s *Service) scan(entry *repoEntry, rescan bool, dirty map[string]struct{}) (RepoStats, error) {
+	paths, isGitRepo, err := s.deps.ListFiles(entry.root)
+	if err != nil {
+		return RepoStats{}, err
+	}
+	slices.Sort(paths)
+	paths = slices.Compact(paths)
+	truncated := false
+	if len(paths) > maxScanFiles {
+		paths = paths[:maxScanFiles]
+		truncated = true
+	}
+
+	previous := entry.files
+	files := make(map[string]fileStats, len(paths))
+	for _, relPath := range paths {
+		if cached, ok := previous[relPath]; ok && !rescan {
+			if _, changed := dirty[relPath]; !changed {
+				files[relPath] = cached
+				continue
+			}
+		}
+		stats, ok := measureFile(entry.root, relPath)
+		if !ok {
+			continue
+		}
+		files[relPath] = stats
+	}
+	entry.files = files
+
+	result := aggregate(files)
+	result.RepoPath = entry.root
+	result.IsGitRepo = isGitRepo
+	result.Truncated = truncated
+	return result, nil
+}
+
+// aggregate groups file stats by language, largest line count first.
+func aggregate(files map[string]fileStats) RepoStats {
+	byLanguage := map[string]*LanguageStats{}
+	var result RepoStats
+	for _, file := range files {
+		if file.binary {
+			continue
+		}
+		language := byLanguage[file.language]
+		if language == nil {
+			language = &LanguageStats{Language: file.language}
+			byLanguage[file.language] = language
+		}
+		language.Files++
+		language.Lines += file.lines
+		language.Bytes += file.bytes
+		result.TotalFiles++
+		result.TotalLines += file.lines
+		result.TotalBytes += file.bytes
+	}
+	result.Languages = make([]LanguageStats, 0, len(byLanguage))
+	for _, language := range byLanguage {
+		result.Languages = append(result.Languages, *language)
+	}
+	slices.SortFunc(result.Languages, func(a, b LanguageStats) int {
+		if c := cmp.Compare(b.Lines, a.Lines); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(b.Bytes, a.Bytes); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Language, b.Language)
+	})
+	return result
+}
+
+func cloneStats(stats RepoStats) RepoStats {
+	stats.Languages = slices.Clone(stats.Languages)
+	return stats
+}
+
+// normalizeRoot returns the absolute, cleaned form of repoPath after checking
+// that it is an existing directory.
+func normalizeRoot(repoPath string) (string, error) {
+	repoPath = strings.TrimSpace(repoPath)
+	if repoPath == "" {
+		return "", errors.New("repository path is required")
+	}
+	root, err := filepath.Abs(repoPath)
+	if err != nil {
+		return "", fmt.Errorf("invalid repository path: %w", err)
+	}
+	info, err := os.Stat(root)
+	if err != nil {
+		return "", fmt.Errorf("repository path is not accessible: %w", err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("repository path is not a directory: %s", root)
+	}
+	return root, nil
+}
+
+// listRepoFiles lists tracked and untracked non-ignored files of a git
+// repository, or walks the directory when root is not inside one.
+func listRepoFiles(root string) ([]string, bool, error) {
+	if gitpkg.IsGitRepository(root) {
+		output, err := gitpkg.RunGitCLIPublic(root, []string{
+			"-c", "core.quotepath=false",
+			"ls-files", "--cached", "--others", "--exclude-standard", "-z",
+		})
+		if err != nil {
+			return nil, true, fmt.Errorf("failed to list repository files: %w", err)
+		}
+		var paths []string
+		for _, relPath := range strings.Split(string(output), "\x00") {
+			if relPath != "" {
+				paths = append(paths, relPath)
+			}
+		}
+		return paths, true, nil
+	}
+
+	var paths []string
+	walkErr := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // skip inaccessible entries
+		}
+		if d.IsDir() {
+			if p != root && slices.Contains(skippedDirs, d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		relPath, relErr := filepath.Rel(root, p)
+		if relErr != nil {
+			return nil
+		}
+		paths = append(paths, filepath.ToSlash(relPath))
+		if len(paths) > maxScanFiles {
+			return fs.SkipAll
+		}
+		return nil
+	})
+	if walkErr != nil {
+		return nil, false, fmt.Errorf("failed to walk directory: %w", walkErr)
+	}
+	return paths, false, nil
+}
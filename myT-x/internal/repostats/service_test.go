@@ -0,0 +1,216 @@
+package repostats
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeRepoFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for relPath, content := range files {
+		target := filepath.Join(root, filepath.FromSlash(relPath))
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(target, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// walkOnly lists files without consulting git so tests do not depend on the
+// temp directory's surroundings.
+func walkOnly(root string) ([]string, bool, error) {
+	paths, _, err := listRepoFiles(root)
+	return paths, false, err
+}
+
+func languageOf(stats RepoStats, name string) (LanguageStats, bool) {
+	for _, language := range stats.Languages {
+		if language.Language == name {
+			return language, true
+		}
+	}
+	return LanguageStats{}, false
+}
+
+func TestStatsAggregatesLanguages(t *testing.T) {
+	root := t.TempDir()
+	writeRepoFiles(t, root, map[string]string{
+		"main.go":              "package main\n\nfunc main() {}\n",
+		"util/util.go":         "package util",
+		"web/app.tsx":          "export {}\n",
+		"README.md":            "# x\n",
+		"Makefile":             "all:\n",
+		"data.unknown":         "a\nb\n",
+		"logo.png":             "\x89PNG\x00\x00",
+		"node_modules/x/x.js":  "skipped\n",
+		".git/objects/deadbee": "skipped\n",
+	})
+	svc := NewService(Deps{ListFiles: walkOnly})
+	t.Cleanup(svc.Close)
+
+	stats, err := svc.Stats(root)
+	if err != nil {
+		t.Fatalf("Stats() error = %v", err)
+	}
+	if stats.TotalFiles != 6 || stats.TotalLines != 9 {
+		t.Fatalf("totals = %d files %d lines, want 6 and 9: %+v", stats.TotalFiles, stats.TotalLines, stats)
+	}
+	goStats, ok := languageOf(stats, "Go")
+	if !ok || goStats.Files != 2 || goStats.Lines != 4 {
+		t.Fatalf("Go = %+v, want 2 files and 4 lines", goStats)
+	}
+	if stats.Languages[0].Language != "Go" {
+		t.Fatalf("first language = %q, want Go (most lines)", stats.Languages[0].Language)
+	}
+	if other, ok := languageOf(stats, languageOther); !ok || other.Lines != 2 {
+		t.Fatalf("Other = %+v, want unknown extension grouped", other)
+	}
+	if _, ok := languageOf(stats, "JavaScript"); ok {
+		t.Fatal("node_modules must be skipped")
+	}
+}
+
+func TestStatsPicksUpChangesFromWatcher(t *testing.T) {
+	root := t.TempDir()
+	writeRepoFiles(t, root, map[string]string{"a.go": "package a\n"})
+	svc := NewService(Deps{ListFiles: walkOnly})
+	t.Cleanup(svc.Close)
+
+	if _, err := svc.Stats(root); err != nil {
+		t.Fatalf("Stats() error = %v", err)
+	}
+	entry := svc.entries[root]
+	if entry == nil || entry.watcher == nil {
+		t.Skip("file watching is unavailable in this environment")
+	}
+
+	writeRepoFiles(t, root, map[string]string{
+		"a.go":     "package a\n\nvar x = 1\n",
+		"new/b.py": "print(1)\nprint(2)\n",
+	})
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		stats, err := svc.Stats(root)
+		if err != nil {
+			t.Fatalf("Stats() error = %v", err)
+		}
+		goStats, _ := languageOf(stats, "Go")
+		pyStats, _ := languageOf(stats, "Python")
+		if goStats.Lines == 3 && pyStats.Lines == 2 {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("stats did not pick up changes: %+v", stats)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestStatsUnwatchedUsesTTL(t *testing.T) {
+	root := t.TempDir()
+	writeRepoFiles(t, root, map[string]string{"a.go": "package a\n"})
+	now := time.Unix(1000, 0)
+	svc := NewService(Deps{
+		ListFiles:    walkOnly,
+		UnwatchedTTL: time.Minute,
+		Now:          func() time.Time { return now },
+	})
+	t.Cleanup(svc.Close)
+
+	if _, err := svc.Stats(root); err != nil {
+		t.Fatalf("Stats() error = %v", err)
+	}
+	svc.entries[root].closeWatcher()
+	writeRepoFiles(t, root, map[string]string{"b.go": "package a\n"})
+
+	stats, _ := svc.Stats(root)
+	if stats.TotalFiles != 1 {
+		t.Fatalf("files = %d, want cached result within TTL", stats.TotalFiles)
+	}
+	now = now.Add(2 * time.Minute)
+	stats, _ = svc.Stats(root)
+	if stats.TotalFiles != 2 {
+		t.Fatalf("files = %d, want rescan after TTL", stats.TotalFiles)
+	}
+}
+
+func TestStatsEvictsLeastRecentlyUsed(t *testing.T) {
+	roots := []string{t.TempDir(), t.TempDir(), t.TempDir()}
+	now := time.Unix(1000, 0)
+	svc := NewService(Deps{
+		ListFiles: walkOnly,
+		MaxRepos:  2,
+		Now: func() time.Time {
+			now = now.Add(time.Second)
+			return now
+		},
+	})
+	t.Cleanup(svc.Close)
+
+	for _, root := range []string{roots[0], roots[1], roots[0], roots[2]} {
+		if _, err := svc.Stats(root); err != nil {
+			t.Fatalf("Stats(%s) error = %v", root, err)
+		}
+	}
+	if _, ok := svc.entries[roots[1]]; ok || len(svc.entries) != 2 {
+		t.Fatalf("entries = %v, want %s evicted", svc.entries, roots[1])
+	}
+}
+
+func TestCompactDropsIdleEntries(t *testing.T) {
+	stale, recent := t.TempDir(), t.TempDir()
+	now := time.Unix(1000, 0)
+	svc := NewService(Deps{ListFiles: walkOnly, Now: func() time.Time { return now }})
+	t.Cleanup(svc.Close)
+
+	if _, err := svc.Stats(stale); err != nil {
+		t.Fatalf("Stats(stale) error = %v", err)
+	}
+	now = now.Add(time.Hour)
+	if _, err := svc.Stats(recent); err != nil {
+		t.Fatalf("Stats(recent) error = %v", err)
+	}
+	if got := svc.Compact(30 * time.Minute); got != 1 {
+		t.Fatalf("Compact() = %d, want 1", got)
+	}
+	if _, ok := svc.entries[stale]; ok {
+		t.Fatalf("entries = %v, want %s dropped", svc.entries, stale)
+	}
+	if _, ok := svc.entries[recent]; !ok {
+		t.Fatalf("entries = %v, want %s kept", svc.entries, recent)
+	}
+}
+
+func TestStatsValidation(t *testing.T) {
+	svc := NewService(Deps{ListFiles: walkOnly})
+	file := filepath.Join(t.TempDir(), "f.txt")
+	writeRepoFiles(t, filepath.Dir(file), map[string]string{"f.txt": "x"})
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr string
+	}{
+		{name: "empty", path: " ", wantErr: "repository path is required"},
+		{name: "missing", path: filepath.Join(t.TempDir(), "missing"), wantErr: "not accessible"},
+		{name: "file", path: file, wantErr: "not a directory"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.Stats(tt.path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Stats() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	svc.Close()
+	if _, err := svc.Stats(t.TempDir()); err == nil || !strings.Contains(err.Error(), "closed") {
+		t.Fatalf("Stats() after Close error = %v, want closed", err)
+	}
+}
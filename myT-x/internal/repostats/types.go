@@ -0,0 +1,32 @@
+package repostats
+
+// RepoStats is the language breakdown of one repository or directory.
+type RepoStats struct {
+	RepoPath  string          `json:"repo_path"`
+	IsGitRepo bool            `json:"is_git_repo"`
+	Languages []LanguageStats `json:"languages"`
+	// TotalFiles counts text files only; binaries are skipped.
+	TotalFiles int   `json:"total_files"`
+	TotalLines int   `json:"total_lines"`
+	TotalBytes int64 `json:"total_bytes"`
+	// Truncated is true when the file list exceeded the scan limit and only
+	// the first files (in path order) were counted.
+	Truncated bool `json:"truncated"`
+}
+
+// LanguageStats aggregates the files of one language.
+type LanguageStats struct {
+	Language string `json:"language"`
+	Files    int    `json:"files"`
+	Lines    int    `json:"lines"`
+	Bytes    int64  `json:"bytes"`
+}
+
+// fileStats is the cached measurement of one file.
+type fileStats struct {
+	language string
+	lines    int
+	bytes    int64
+	// binary files are remembered so they are not re-read, but not reported.
+	binary bool
+}
@@ -0,0 +1,163 @@
+package repostats
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path/filepath"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// maxWatchedDirs bounds the directories watched per repository. Larger trees
+// fall back to the TTL cache instead of exhausting OS watch handles.
+const maxWatchedDirs = 4096
+
+var errTooManyDirs = errors.New("too many directories to watch")
+
+// repoWatcher marks changed paths of one repository dirty.
+type repoWatcher struct {
+	root    string
+	entry   *repoEntry
+	watcher *fsnotify.Watcher
+	dirs    int
+
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+func newRepoWatcher(root string, entry *repoEntry) (*repoWatcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("create watcher: %w", err)
+	}
+	w := &repoWatcher{
+		root:    root,
+		entry:   entry,
+		watcher: watcher,
+		done:    make(chan struct{}),
+	}
+	if err := w.addTree(root); err != nil {
+		_ = watcher.Close()
+		return nil, err
+	}
+	go w.run()
+	return w, nil
+}
+
+// addTree watches dir and its subdirectories, skipping skippedDirs.
+func (w *repoWatcher) addTree(dir string) error {
+	return filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // skip inaccessible entries
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if p != w.root && slices.Contains(skippedDirs, d.Name()) {
+			return filepath.SkipDir
+		}
+		if w.dirs >= maxWatchedDirs {
+			return errTooManyDirs
+		}
+		if addErr := w.watcher.Add(p); addErr != nil {
+			return fmt.Errorf("watch %s: %w", p, addErr)
+		}
+		w.dirs++
+		return nil
+	})
+}
+
+func (w *repoWatcher) run() {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("[REPOSTATS] panic in watcher loop",
+				"root", w.root, "panic", r, "stack", string(debug.Stack()))
+			// Without a watcher the entry falls back to the TTL cache.
+			w.entry.closeWatcher()
+			w.entry.markRescan()
+		}
+	}()
+	for {
+		select {
+		case <-w.done:
+			return
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			w.handleEvent(event)
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			// Overflow and similar errors mean events were lost.
+			slog.Debug("[REPOSTATS] watcher error, scheduling full rescan", "root", w.root, "error", err)
+			w.entry.markRescan()
+		}
+	}
+}
+
+func (w *repoWatcher) handleEvent(event fsnotify.Event) {
+	relPath, err := filepath.Rel(w.root, event.Name)
+	if err != nil || !filepath.IsLocal(relPath) {
+		return
+	}
+	relPath = filepath.ToSlash(relPath)
+	for _, segment := range strings.Split(relPath, "/") {
+		if slices.Contains(skippedDirs, segment) {
+			return
+		}
+	}
+
+	if event.Has(fsnotify.Create) {
+		// New directories must be watched to see the files created inside them.
+		if err := w.addTree(event.Name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			slog.Debug("[REPOSTATS] failed to watch new directory", "path", event.Name, "error", err)
+			w.entry.markRescan()
+		}
+	}
+	listChanged := event.Has(fsnotify.Create) || event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename) ||
+		filepath.Base(event.Name) == ".gitignore"
+	w.entry.markDirty(relPath, listChanged)
+}
+
+// Stop closes the watcher. Safe to call more than once.
+func (w *repoWatcher) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.done)
+		if err := w.watcher.Close(); err != nil {
+			slog.Debug("[REPOSTATS] failed to close watcher", "root", w.root, "error", err)
+		}
+	})
+}
+
+func (e *repoEntry) markDirty(relPath string, listChanged bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.dirty[relPath] = struct{}{}
+	if listChanged {
+		e.relist = true
+	}
+}
+
+func (e *repoEntry) markRescan() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.rescan = true
+}
+
+func (e *repoEntry) closeWatcher() {
+	e.mu.Lock()
+	watcher := e.watcher
+	e.watcher = nil
+	e.mu.Unlock()
+	if watcher != nil {
+		watcher.Stop()
+	}
+}
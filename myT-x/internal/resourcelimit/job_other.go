@@ -0,0 +1,15 @@
+//go:build !windows
+
+package resourcelimit
+
+// noopJob accepts limits without enforcing them; job objects are
+// Windows-only.
+type noopJob struct{}
+
+func newJob() (job, error) {
+	return noopJob{}, nil
+}
+
+func (noopJob) setLimits(Limits) error { return nil }
+func (noopJob) assign(int) error       { return nil }
+func (noopJob) close() error           { return nil }
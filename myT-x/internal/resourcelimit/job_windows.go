@@ -0,0 +1,81 @@
+//go:build windows
+
+package resourcelimit
+
+import (
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+const (
+	_JOB_OBJECT_CPU_RATE_CONTROL_ENABLE   = 0x1
+	_JOB_OBJECT_CPU_RATE_CONTROL_HARD_CAP = 0x4
+)
+
+// _JOBOBJECT_CPU_RATE_CONTROL_INFORMATION mirrors the Windows structure with
+// the CpuRate member of its union.
+type _JOBOBJECT_CPU_RATE_CONTROL_INFORMATION struct {
+	ControlFlags uint32
+	// CpuRate is the share of the machine in 1/100 percent.
+	CpuRate uint32
+}
+
+// windowsJob is a job object without JOB_OBJECT_LIMIT_KILL_ON_JOB_CLOSE, so
+// releasing it never kills the user's processes.
+type windowsJob struct {
+	handle windows.Handle
+}
+
+func newJob() (job, error) {
+	handle, err := windows.CreateJobObject(nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create job object: %w", err)
+	}
+	return &windowsJob{handle: handle}, nil
+}
+
+func (j *windowsJob) setLimits(limits Limits) error {
+	memory := windows.JOBOBJECT_EXTENDED_LIMIT_INFORMATION{}
+	if limits.MemoryMB > 0 {
+		memory.BasicLimitInformation.LimitFlags = windows.JOB_OBJECT_LIMIT_JOB_MEMORY
+		memory.JobMemoryLimit = uintptr(limits.MemoryMB) << 20
+	}
+	if _, err := windows.SetInformationJobObject(j.handle, windows.JobObjectExtendedLimitInformation,
+		uintptr(unsafe.Pointer(&memory)), uint32(unsafe.Sizeof(memory))); err != nil {
+		return fmt.Errorf("set job memory limit: %w", err)
+	}
+
+	cpu := _JOBOBJECT_CPU_RATE_CONTROL_INFORMATION{}
+	if limits.CPUPercent > 0 {
+		cpu.ControlFlags = _JOB_OBJECT_CPU_RATE_CONTROL_ENABLE | _JOB_OBJECT_CPU_RATE_CONTROL_HARD_CAP
+		cpu.CpuRate = uint32(min(limits.CPUPercent, 100) * 100)
+	}
+	if _, err := windows.SetInformationJobObject(j.handle, windows.JobObjectCpuRateControlInformation,
+		uintptr(unsafe.Pointer(&cpu)), uint32(unsafe.Sizeof(cpu))); err != nil {
+		return fmt.Errorf("set job cpu rate: %w", err)
+	}
+	return nil
+}
+
+func (j *windowsJob) assign(pid int) error {
+	process, err := windows.OpenProcess(windows.PROCESS_SET_QUOTA|windows.PROCESS_TERMINATE, false, uint32(pid))
+	if err != nil {
+		return fmt.Errorf("open process: %w", err)
+	}
+	defer windows.CloseHandle(process)
+	if err := windows.AssignProcessToJobObject(j.handle, process); err != nil {
+		return fmt.Errorf("assign process to job: %w", err)
+	}
+	return nil
+}
+
+func (j *windowsJob) close() error {
+	if j.handle == 0 {
+		return nil
+	}
+	err := windows.CloseHandle(j.handle)
+	j.handle = 0
+	return err
+}
@@ -0,0 +1,157 @@
+// Package resourcelimit caps the CPU and memory of a session's pane process
+// trees and samples their live usage. On Windows each session gets a job
+// object that its pane shells are assigned to; processes the shells start
+// inherit the job, so a runaway agent is throttled or denied memory instead
+// of starving the machine. Outside Windows limits are accepted but not
+// enforced.
+package resourcelimit
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+)
+
+// Limits caps the pane processes of one session. Zero fields are unlimited.
+type Limits struct {
+	// MemoryMB is the committed memory limit of all processes together.
+	MemoryMB int `json:"memory_mb"`
+	// CPUPercent is the share of the whole machine (all cores) the processes
+	// may use, from 1 to 100.
+	CPUPercent int `json:"cpu_percent"`
+}
+
+// IsZero reports whether l sets no limit.
+func (l Limits) IsZero() bool {
+	return l.MemoryMB <= 0 && l.CPUPercent <= 0
+}
+
+// job is the OS object enforcing Limits on its processes.
+type job interface {
+	setLimits(Limits) error
+	assign(pid int) error
+	close() error
+}
+
+// sessionJob is the job of one session.
+type sessionJob struct {
+	job    job
+	limits Limits
+}
+
+// Manager owns one job per limited session.
+//
+// Thread-safety is managed internally via mu. No external locking is required.
+type Manager struct {
+	newJob func() (job, error)
+
+	mu       sync.Mutex
+	sessions map[string]*sessionJob
+}
+
+// NewManager creates a manager without jobs.
+func NewManager() *Manager {
+	return &Manager{newJob: newJob, sessions: map[string]*sessionJob{}}
+}
+
+// Assign places the pane shell pid in the job of sessionName and applies
+// limits to the job. A session without a job only gets one when limits are
+// non-zero. Processes cannot leave a job, so zero limits on an existing job
+// lift its limits instead. Every call assigns pid, even one seen before:
+// assigning a process to its own job again is a no-op for the OS, and a
+// reused PID may now belong to a new, unconfined pane shell.
+func (m *Manager) Assign(sessionName string, pid int, limits Limits) error {
+	if sessionName == "" || pid <= 0 {
+		return fmt.Errorf("resourcelimit: invalid pane process %q/%d", sessionName, pid)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sj := m.sessions[sessionName]
+	if sj == nil {
+		if limits.IsZero() {
+			return nil
+		}
+		j, err := m.newJob()
+		if err != nil {
+			return fmt.Errorf("resourcelimit: create job for %q: %w", sessionName, err)
+		}
+		if err := j.setLimits(limits); err != nil {
+			_ = j.close()
+			return fmt.Errorf("resourcelimit: set limits for %q: %w", sessionName, err)
+		}
+		sj = &sessionJob{job: j, limits: limits}
+		m.sessions[sessionName] = sj
+		slog.Info("[RESOURCE-LIMIT] session job created", "session", sessionName,
+			"memoryMB", limits.MemoryMB, "cpuPercent", limits.CPUPercent)
+	} else if sj.limits != limits {
+		if err := sj.job.setLimits(limits); err != nil {
+			return fmt.Errorf("resourcelimit: set limits for %q: %w", sessionName, err)
+		}
+		sj.limits = limits
+		slog.Info("[RESOURCE-LIMIT] session limits changed", "session", sessionName,
+			"memoryMB", limits.MemoryMB, "cpuPercent", limits.CPUPercent)
+	}
+
+	if err := sj.job.assign(pid); err != nil {
+		return fmt.Errorf("resourcelimit: assign pid %d to %q: %w", pid, sessionName, err)
+	}
+	return nil
+}
+
+// Limits returns the limits applied to sessionName, or zero Limits when the
+// session has no job.
+func (m *Manager) Limits(sessionName string) Limits {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if sj := m.sessions[sessionName]; sj != nil {
+		return sj.limits
+	}
+	return Limits{}
+}
+
+// Rename moves the job of oldName to newName. Renaming a session without a
+// job is a no-op.
+func (m *Manager) Rename(oldName, newName string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sj := m.sessions[oldName]
+	if sj == nil || oldName == newName {
+		return nil
+	}
+	if _, exists := m.sessions[newName]; exists {
+		return fmt.Errorf("resourcelimit: session %q already has a job", newName)
+	}
+	delete(m.sessions, oldName)
+	m.sessions[newName] = sj
+	return nil
+}
+
+// Release closes the job of sessionName. Processes still in the job keep
+// running; the job only goes away with its last process.
+func (m *Manager) Release(sessionName string) error {
+	m.mu.Lock()
+	sj := m.sessions[sessionName]
+	delete(m.sessions, sessionName)
+	m.mu.Unlock()
+	if sj == nil {
+		return nil
+	}
+	if err := sj.job.close(); err != nil {
+		return fmt.Errorf("resourcelimit: close job of %q: %w", sessionName, err)
+	}
+	return nil
+}
+
+// Close releases every job.
+func (m *Manager) Close() {
+	m.mu.Lock()
+	sessions := m.sessions
+	m.sessions = map[string]*sessionJob{}
+	m.mu.Unlock()
+	for name, sj := range sessions {
+		if err := sj.job.close(); err != nil {
+			slog.Warn("[WARN-RESOURCE-LIMIT] failed to close session job", "session", name, "error", err)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package resourcelimit
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+type fakeJob struct {
+	limits   []Limits
+	assigned []int
+	closed   bool
+	failPID  int
+}
+
+func (j *fakeJob) setLimits(limits Limits) error {
+	j.limits = append(j.limits, limits)
+	return nil
+}
+
+func (j *fakeJob) assign(pid int) error {
+	if pid == j.failPID {
+		return errors.New("access denied")
+	}
+	j.assigned = append(j.assigned, pid)
+	return nil
+}
+
+func (j *fakeJob) close() error {
+	j.closed = true
+	return nil
+}
+
+func newFakeManager() (*Manager, *[]*fakeJob) {
+	var jobs []*fakeJob
+	m := NewManager()
+	m.newJob = func() (job, error) {
+		j := &fakeJob{failPID: -1}
+		jobs = append(jobs, j)
+		return j, nil
+	}
+	return m, &jobs
+}
+
+func TestManagerAssignCreatesJobOnlyWithLimits(t *testing.T) {
+	m, jobs := newFakeManager()
+	if err := m.Assign("free", 10, Limits{}); err != nil {
+		t.Fatalf("Assign() error = %v", err)
+	}
+	if len(*jobs) != 0 {
+		t.Fatalf("jobs = %d, want none for zero limits", len(*jobs))
+	}
+
+	limits := Limits{MemoryMB: 512, CPUPercent: 25}
+	// 20 comes again, e.g. as a reused PID, and must be assigned again.
+	for _, pid := range []int{20, 21, 20} {
+		if err := m.Assign("agent", pid, limits); err != nil {
+			t.Fatalf("Assign(%d) error = %v", pid, err)
+		}
+	}
+	if len(*jobs) != 1 {
+		t.Fatalf("jobs = %d, want 1", len(*jobs))
+	}
+	j := (*jobs)[0]
+	if !slices.Equal(j.assigned, []int{20, 21, 20}) || !slices.Equal(j.limits, []Limits{limits}) {
+		t.Fatalf("job = %+v", j)
+	}
+	if got := m.Limits("agent"); got != limits {
+		t.Fatalf("Limits() = %+v, want %+v", got, limits)
+	}
+
+	// Lifting the limits keeps the job, since processes cannot leave it.
+	if err := m.Assign("agent", 21, Limits{}); err != nil {
+		t.Fatalf("Assign() error = %v", err)
+	}
+	if !slices.Equal(j.limits, []Limits{limits, {}}) || len(j.assigned) != 4 {
+		t.Fatalf("job after lifting = %+v", j)
+	}
+}
+
+func TestManagerAssignErrors(t *testing.T) {
+	m, jobs := newFakeManager()
+	if err := m.Assign("", 1, Limits{MemoryMB: 64}); err == nil {
+		t.Fatal("Assign() with empty session should fail")
+	}
+	if err := m.Assign("s", 1, Limits{MemoryMB: 64}); err != nil {
+		t.Fatalf("Assign() error = %v", err)
+	}
+	(*jobs)[0].failPID = 2
+	if err := m.Assign("s", 2, Limits{MemoryMB: 64}); err == nil {
+		t.Fatal("Assign() should report a failed assignment")
+	}
+	// A failed pid is retried on the next Assign.
+	(*jobs)[0].failPID = -1
+	if err := m.Assign("s", 2, Limits{MemoryMB: 64}); err != nil {
+		t.Fatalf("Assign() retry error = %v", err)
+	}
+
+	m.newJob = func() (job, error) { return nil, errors.New("quota") }
+	if err := m.Assign("t", 3, Limits{CPUPercent: 10}); err == nil {
+		t.Fatal("Assign() should report a job creation failure")
+	}
+}
+
+func TestManagerRenameAndRelease(t *testing.T) {
+	m, jobs := newFakeManager()
+	limits := Limits{CPUPercent: 50}
+	if err := m.Assign("a", 1, limits); err != nil {
+		t.Fatalf("Assign() error = %v", err)
+	}
+	if err := m.Assign("b", 2, limits); err != nil {
+		t.Fatalf("Assign() error = %v", err)
+	}
+	if err := m.Rename("a", "b"); err == nil {
+		t.Fatal("Rename() onto a session with a job should fail")
+	}
+	if err := m.Rename("a", "c"); err != nil {
+		t.Fatalf("Rename() error = %v", err)
+	}
+	if err := m.Rename("missing", "d"); err != nil {
+		t.Fatalf("Rename() of a session without a job error = %v", err)
+	}
+	if m.Limits("a") != (Limits{}) || m.Limits("c") != limits {
+		t.Fatalf("limits after rename: a=%+v c=%+v", m.Limits("a"), m.Limits("c"))
+	}
+
+	if err := m.Release("c"); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	if !(*jobs)[0].closed || (*jobs)[1].closed {
+		t.Fatalf("closed = %v, %v; want only the released job", (*jobs)[0].closed, (*jobs)[1].closed)
+	}
+	if err := m.Release("c"); err != nil {
+		t.Fatalf("second Release() error = %v", err)
+	}
+	m.Close()
+	if !(*jobs)[1].closed || m.Limits("b") != (Limits{}) {
+		t.Fatal("Close() should release every job")
+	}
+}
+
+func TestLimitsIsZero(t *testing.T) {
+	if !(Limits{}).IsZero() || (Limits{MemoryMB: 1}).IsZero() || (Limits{CPUPercent: 1}).IsZero() {
+		t.Fatal("IsZero() mismatch")
+	}
+}
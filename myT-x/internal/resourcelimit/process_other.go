@@ -0,0 +1,18 @@
+//go:build !windows
+
+package resourcelimit
+
+import "errors"
+
+// errUnsupported is returned by process queries outside Windows.
+var errUnsupported = errors.New("resourcelimit: process usage is Windows-only")
+
+// scanProcesses is a stub outside Windows; usage sampling is Windows-only.
+func scanProcesses() (map[uint32]Process, error) {
+	return nil, nil
+}
+
+// queryProcess is a stub outside Windows; usage sampling is Windows-only.
+func queryProcess(uint32) (processUsage, error) {
+	return processUsage{}, errUnsupported
+}
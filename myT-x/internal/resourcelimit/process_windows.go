@@ -0,0 +1,95 @@
+//go:build windows
+
+package resourcelimit
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	kernel32 = windows.NewLazySystemDLL("kernel32.dll")
+
+	procK32GetProcessMemoryInfo = kernel32.NewProc("K32GetProcessMemoryInfo")
+)
+
+// _PROCESS_MEMORY_COUNTERS mirrors the Windows PROCESS_MEMORY_COUNTERS
+// structure.
+type _PROCESS_MEMORY_COUNTERS struct {
+	CB                         uint32
+	PageFaultCount             uint32
+	PeakWorkingSetSize         uintptr
+	WorkingSetSize             uintptr
+	QuotaPeakPagedPoolUsage    uintptr
+	QuotaPagedPoolUsage        uintptr
+	QuotaPeakNonPagedPoolUsage uintptr
+	QuotaNonPagedPoolUsage     uintptr
+	PagefileUsage              uintptr
+	PeakPagefileUsage          uintptr
+}
+
+// scanProcesses returns a PID-keyed snapshot of every running process.
+func scanProcesses() (map[uint32]Process, error) {
+	snap, err := windows.CreateToolhelp32Snapshot(windows.TH32CS_SNAPPROCESS, 0)
+	if err != nil {
+		return nil, fmt.Errorf("CreateToolhelp32Snapshot: %w", err)
+	}
+	defer windows.CloseHandle(snap)
+
+	processes := make(map[uint32]Process, 256)
+	var entry windows.ProcessEntry32
+	entry.Size = uint32(unsafe.Sizeof(entry))
+	err = windows.Process32First(snap, &entry)
+	for err == nil {
+		processes[entry.ProcessID] = Process{
+			PID:       entry.ProcessID,
+			ParentPID: entry.ParentProcessID,
+		}
+		err = windows.Process32Next(snap, &entry)
+	}
+	if !errors.Is(err, windows.ERROR_NO_MORE_FILES) {
+		return nil, fmt.Errorf("Process32Next: %w", err)
+	}
+	return processes, nil
+}
+
+// queryProcess returns the kernel plus user CPU time and the working set of
+// pid.
+func queryProcess(pid uint32) (processUsage, error) {
+	process, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, pid)
+	if err != nil {
+		return processUsage{}, fmt.Errorf("open process: %w", err)
+	}
+	defer windows.CloseHandle(process)
+
+	var creation, exit, kernel, user windows.Filetime
+	if err := windows.GetProcessTimes(process, &creation, &exit, &kernel, &user); err != nil {
+		return processUsage{}, fmt.Errorf("GetProcessTimes: %w", err)
+	}
+	if err := procK32GetProcessMemoryInfo.Find(); err != nil {
+		return processUsage{}, err
+	}
+	counters := _PROCESS_MEMORY_COUNTERS{}
+	counters.CB = uint32(unsafe.Sizeof(counters))
+	r1, _, callErr := procK32GetProcessMemoryInfo.Call(
+		uintptr(process),
+		uintptr(unsafe.Pointer(&counters)),
+		uintptr(counters.CB),
+	)
+	if r1 == 0 {
+		return processUsage{}, fmt.Errorf("K32GetProcessMemoryInfo: %w", callErr)
+	}
+	return processUsage{
+		CPU: filetimeDuration(kernel) + filetimeDuration(user),
+		RSS: uint64(counters.WorkingSetSize),
+	}, nil
+}
+
+// filetimeDuration converts a FILETIME interval (100ns units) to a Duration.
+func filetimeDuration(ft windows.Filetime) time.Duration {
+	return time.Duration(uint64(ft.HighDateTime)<<32|uint64(ft.LowDateTime)) * 100
+}
@@ -0,0 +1,212 @@
+package resourcelimit
+
+import (
+	"runtime"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	// maxAncestorDepth bounds the parent walk so that a PID reuse loop in a
+	// stale snapshot cannot spin forever.
+	maxAncestorDepth = 64
+
+	// warmupInterval is the measuring window of a pane without an earlier
+	// sample; CPU usage needs two readings of the CPU time.
+	warmupInterval = 250 * time.Millisecond
+)
+
+// PaneRoot is the shell process of one pane.
+type PaneRoot struct {
+	SessionName string
+	PaneID      string
+	PID         int
+}
+
+// PaneUsage is the live usage of one pane's process tree.
+type PaneUsage struct {
+	PaneID string `json:"pane_id"`
+	PID    int    `json:"pid"`
+	// Processes counts the shell and its descendants.
+	Processes int `json:"processes"`
+	// CPUPercent is the share of the whole machine (all cores) used since
+	// the previous sample.
+	CPUPercent float64 `json:"cpu_percent"`
+	// RSSBytes is the summed working set of the processes.
+	RSSBytes uint64 `json:"rss_bytes"`
+}
+
+// SessionUsage is the usage of one session's panes and its limits.
+type SessionUsage struct {
+	SessionName string      `json:"session_name"`
+	Limits      Limits      `json:"limits"`
+	Panes       []PaneUsage `json:"panes"`
+}
+
+// Process is one entry of a process snapshot.
+type Process struct {
+	PID       uint32
+	ParentPID uint32
+}
+
+// processUsage is the accumulated CPU time and working set of one process.
+type processUsage struct {
+	CPU time.Duration
+	RSS uint64
+}
+
+// cpuReading is the CPU time of a pane tree at a point in time.
+type cpuReading struct {
+	pid int
+	cpu time.Duration
+	at  time.Time
+}
+
+// Sampler measures the CPU and memory of pane process trees. CPU usage is
+// the delta against the previous Sample, so the first sample of a pane
+// measures over a short warmup window.
+//
+// A process that exits between samples takes its CPU time with it; the
+// pane's delta is clamped to zero in that case.
+//
+// Thread-safety is managed internally via mu. No external locking is required.
+type Sampler struct {
+	scan   func() (map[uint32]Process, error)
+	query  func(pid uint32) (processUsage, error)
+	now    func() time.Time
+	sleep  func(time.Duration)
+	numCPU int
+
+	mu sync.Mutex
+	// previous holds the last CPU reading keyed by pane ID.
+	previous map[string]cpuReading
+}
+
+// NewSampler creates a sampler of the running processes. Outside Windows
+// the process snapshot is empty and every pane reports zero usage.
+func NewSampler() *Sampler {
+	return &Sampler{
+		scan:     scanProcesses,
+		query:    queryProcess,
+		now:      time.Now,
+		sleep:    time.Sleep,
+		numCPU:   runtime.NumCPU(),
+		previous: map[string]cpuReading{},
+	}
+}
+
+// Sample returns the usage of each root's process tree in the order of
+// roots. A process belongs to the nearest pane shell among its ancestors.
+func (s *Sampler) Sample(roots []PaneRoot) ([]PaneUsage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	processes, err := s.scan()
+	if err != nil {
+		return nil, err
+	}
+	trees := paneTrees(roots, processes)
+
+	usage := make([]PaneUsage, len(roots))
+	readings := make([]cpuReading, len(roots))
+	warmup := make([]bool, len(roots))
+	for i, root := range roots {
+		cpu, rss := s.measure(trees[i])
+		readings[i] = cpuReading{pid: root.PID, cpu: cpu, at: s.now()}
+		usage[i] = PaneUsage{PaneID: root.PaneID, PID: root.PID, Processes: len(trees[i]), RSSBytes: rss}
+		if prev, ok := s.previous[root.PaneID]; !ok || prev.pid != root.PID {
+			s.previous[root.PaneID] = readings[i]
+			warmup[i] = true
+		}
+	}
+	if slices.Contains(warmup, true) {
+		s.sleep(warmupInterval)
+		for i, root := range roots {
+			if warmup[i] {
+				cpu, _ := s.measure(trees[i])
+				readings[i] = cpuReading{pid: root.PID, cpu: cpu, at: s.now()}
+			}
+		}
+	}
+
+	live := make(map[string]struct{}, len(roots))
+	for i, root := range roots {
+		live[root.PaneID] = struct{}{}
+		usage[i].CPUPercent = s.cpuPercent(s.previous[root.PaneID], readings[i])
+		s.previous[root.PaneID] = readings[i]
+	}
+	for paneID := range s.previous {
+		if _, ok := live[paneID]; !ok {
+			delete(s.previous, paneID)
+		}
+	}
+	return usage, nil
+}
+
+// measure sums the CPU time and working set of pids. Processes that exited
+// or deny access are skipped.
+func (s *Sampler) measure(pids []uint32) (time.Duration, uint64) {
+	var cpu time.Duration
+	var rss uint64
+	for _, pid := range pids {
+		process, err := s.query(pid)
+		if err != nil {
+			continue
+		}
+		cpu += process.CPU
+		rss += process.RSS
+	}
+	return cpu, rss
+}
+
+func (s *Sampler) cpuPercent(prev, cur cpuReading) float64 {
+	elapsed := cur.at.Sub(prev.at)
+	if elapsed <= 0 || cur.cpu <= prev.cpu || s.numCPU <= 0 {
+		return 0
+	}
+	percent := float64(cur.cpu-prev.cpu) / float64(elapsed*time.Duration(s.numCPU)) * 100
+	return min(percent, 100)
+}
+
+// paneTrees returns, for each root, the PIDs of its shell and the processes
+// whose nearest pane shell ancestor it is. A root whose shell is missing from
+// the snapshot still owns its own PID.
+func paneTrees(roots []PaneRoot, processes map[uint32]Process) [][]uint32 {
+	trees := make([][]uint32, len(roots))
+	rootIndex := make(map[uint32]int, len(roots))
+	for i, root := range roots {
+		if root.PID > 0 {
+			rootIndex[uint32(root.PID)] = i
+			trees[i] = []uint32{uint32(root.PID)}
+		}
+	}
+	for pid := range processes {
+		if _, isRoot := rootIndex[pid]; isRoot {
+			continue
+		}
+		if i, ok := nearestRoot(pid, rootIndex, processes); ok {
+			trees[i] = append(trees[i], pid)
+		}
+	}
+	for _, tree := range trees {
+		slices.Sort(tree)
+	}
+	return trees
+}
+
+// nearestRoot returns the index of the pane shell that is pid's closest
+// ancestor.
+func nearestRoot(pid uint32, rootIndex map[uint32]int, processes map[uint32]Process) (int, bool) {
+	for range maxAncestorDepth {
+		process, ok := processes[pid]
+		if !ok || process.ParentPID == 0 || process.ParentPID == pid {
+			return 0, false
+		}
+		pid = process.ParentPID
+		if i, ok := rootIndex[pid]; ok {
+			return i, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,117 @@
+package resourcelimit
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) Now() time.Time        { return c.now }
+func (c *fakeClock) Sleep(d time.Duration) { c.now = c.now.Add(d) }
+
+func newFakeSampler(processes map[uint32]Process, cpu map[uint32]time.Duration) (*Sampler, *fakeClock) {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	s := NewSampler()
+	s.numCPU = 2
+	s.now = clock.Now
+	s.sleep = clock.Sleep
+	s.scan = func() (map[uint32]Process, error) { return processes, nil }
+	s.query = func(pid uint32) (processUsage, error) {
+		if pid == 99 {
+			return processUsage{}, errors.New("access denied")
+		}
+		return processUsage{CPU: cpu[pid], RSS: uint64(pid) << 20}, nil
+	}
+	return s, clock
+}
+
+func TestSamplerAttributesProcessTrees(t *testing.T) {
+	processes := map[uint32]Process{
+		10: {PID: 10, ParentPID: 1},
+		11: {PID: 11, ParentPID: 10},
+		12: {PID: 12, ParentPID: 11},
+		20: {PID: 20, ParentPID: 11}, // a pane shell started from pane 10's tree
+		21: {PID: 21, ParentPID: 20},
+		99: {PID: 99, ParentPID: 21},
+		30: {PID: 30, ParentPID: 1},
+	}
+	cpu := map[uint32]time.Duration{}
+	s, clock := newFakeSampler(processes, cpu)
+	roots := []PaneRoot{
+		{SessionName: "a", PaneID: "%0", PID: 10},
+		{SessionName: "b", PaneID: "%1", PID: 20},
+	}
+
+	// The first sample measures CPU over the warmup window.
+	s.query = func(pid uint32) (processUsage, error) {
+		if pid == 99 {
+			return processUsage{}, errors.New("access denied")
+		}
+		// 125ms of CPU for pid 12 during the 250ms warmup: 25% of 2 cores.
+		if pid == 12 && clock.now.After(time.Unix(1000, 0)) {
+			return processUsage{CPU: 125 * time.Millisecond, RSS: 12 << 20}, nil
+		}
+		return processUsage{CPU: cpu[pid], RSS: uint64(pid) << 20}, nil
+	}
+	usage, err := s.Sample(roots)
+	if err != nil {
+		t.Fatalf("Sample() error = %v", err)
+	}
+	want := []PaneUsage{
+		{PaneID: "%0", PID: 10, Processes: 3, CPUPercent: 25, RSSBytes: (10 + 11 + 12) << 20},
+		{PaneID: "%1", PID: 20, Processes: 3, CPUPercent: 0, RSSBytes: (20 + 21) << 20},
+	}
+	if !slices.Equal(usage, want) {
+		t.Fatalf("Sample() = %+v, want %+v", usage, want)
+	}
+}
+
+func TestSamplerUsesPreviousSample(t *testing.T) {
+	processes := map[uint32]Process{10: {PID: 10, ParentPID: 1}}
+	cpu := map[uint32]time.Duration{10: time.Second}
+	s, clock := newFakeSampler(processes, cpu)
+	slept := 0
+	s.sleep = func(d time.Duration) {
+		slept++
+		clock.Sleep(d)
+	}
+	roots := []PaneRoot{{SessionName: "a", PaneID: "%0", PID: 10}}
+	if _, err := s.Sample(roots); err != nil {
+		t.Fatalf("Sample() error = %v", err)
+	}
+
+	clock.now = clock.now.Add(10 * time.Second)
+	cpu[10] += 20 * time.Second // two cores fully busy
+	usage, err := s.Sample(roots)
+	if err != nil {
+		t.Fatalf("Sample() error = %v", err)
+	}
+	if slept != 1 || usage[0].CPUPercent != 100 {
+		t.Fatalf("slept = %d, usage = %+v; want one warmup and 100%%", slept, usage)
+	}
+
+	// A new shell in the same pane starts over; a vanished pane is pruned.
+	if _, err := s.Sample([]PaneRoot{{SessionName: "a", PaneID: "%0", PID: 11}}); err != nil {
+		t.Fatalf("Sample() error = %v", err)
+	}
+	if slept != 2 {
+		t.Fatalf("slept = %d, want a warmup for the restarted shell", slept)
+	}
+	if _, err := s.Sample(nil); err != nil {
+		t.Fatalf("Sample() error = %v", err)
+	}
+	if len(s.previous) != 0 {
+		t.Fatalf("previous = %v, want pruned", s.previous)
+	}
+}
+
+func TestSamplerScanError(t *testing.T) {
+	s := NewSampler()
+	s.scan = func() (map[uint32]Process, error) { return nil, errors.New("snapshot failed") }
+	if _, err := s.Sample([]PaneRoot{{PaneID: "%0", PID: 1}}); err == nil {
+		t.Fatal("Sample() should report a scan failure")
+	}
+}
@@ -2,7 +2,6 @@ package scheduler
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
@@ -543,10 +542,11 @@ func (s *Service) SaveTemplate(sessionName string, tmpl Template) error {
 		return fmt.Errorf("migrate legacy templates: %w", err)
 	}
 
-	templates, err := readTemplatesForWrite(path)
+	templates, recovery, err := readTemplates(path)
 	if err != nil {
 		return fmt.Errorf("read templates: %w", err)
 	}
+	s.reportTemplateRecovery(sessionName, recovery)
 
 	// Upsert: overwrite if Title matches, otherwise append.
 	found := false
@@ -584,10 +584,11 @@ func (s *Service) LoadTemplates(sessionName string) ([]Template, error) {
 		return []Template{}, fmt.Errorf("migrate legacy templates: %w", err)
 	}
 
-	templates, err := readTemplates(path)
+	templates, recovery, err := readTemplates(path)
 	if err != nil {
 		return []Template{}, fmt.Errorf("read templates: %w", err)
 	}
+	s.reportTemplateRecovery(sessionName, recovery)
 	return templates, nil
 }
 
@@ -612,10 +613,11 @@ func (s *Service) DeleteTemplate(sessionName, title string) error {
 		return fmt.Errorf("migrate legacy templates: %w", err)
 	}
 
-	templates, err := readTemplatesForWrite(path)
+	templates, recovery, err := readTemplates(path)
 	if err != nil {
 		return fmt.Errorf("read templates: %w", err)
 	}
+	s.reportTemplateRecovery(sessionName, recovery)
 
 	filtered := make([]Template, 0, len(templates))
 	found := false
@@ -654,37 +656,65 @@ func (s *Service) resolveTemplatePath(sessionName string) (string, error) {
 	return path, nil
 }
 
-// readTemplates reads templates from file.
-// Returns an empty slice if the file does not exist or is malformed.
-func readTemplates(path string) ([]Template, error) {
-	return readTemplatesWithMode(path, true)
-}
-
-func readTemplatesForWrite(path string) ([]Template, error) {
-	return readTemplatesWithMode(path, false)
-}
-
-func readTemplatesWithMode(path string, allowMalformed bool) ([]Template, error) {
+// readTemplates reads templates from file and verifies their checksums.
+// Returns an empty slice if the file does not exist.
+//
+// Corrupt records are dropped instead of failing the caller: the original
+// file is quarantined next to the store for diagnostics, the surviving
+// records are rewritten, and the returned Recovery lists what was dropped.
+// Recovery is nil when the file was intact. A file written by a newer
+// version is left untouched and reported as an error.
+func readTemplates(path string) ([]Template, *sessioninfo.Recovery, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return []Template{}, nil
+			return []Template{}, nil, nil
 		}
-		return nil, err
+		return nil, nil, err
 	}
 
-	var templates []Template
-	if err := json.Unmarshal(data, &templates); err != nil {
-		if allowMalformed {
-			slog.Error("[SCHEDULER] failed to parse templates, returning empty",
-				"path", path, "err", err)
-			return []Template{}, nil
-		}
-		slog.Warn("[SCHEDULER] failed to parse templates, refusing to overwrite",
-			"path", path, "err", err)
-		return nil, fmt.Errorf("parse templates: %w", err)
+	templates, dropped, err := sessioninfo.DecodeRecords[Template](data)
+	if errors.Is(err, sessioninfo.ErrNewerRecordVersion) {
+		return nil, nil, fmt.Errorf("read templates %s: %w", path, err)
 	}
-	return templates, nil
+	if err != nil {
+		templates = []Template{}
+		dropped = []sessioninfo.DroppedRecord{{Index: -1, Reason: err.Error()}}
+	}
+	if len(dropped) == 0 {
+		return templates, nil, nil
+	}
+
+	quarantined, err := sessioninfo.QuarantineFile(path, time.Now())
+	if err != nil {
+		// Without a diagnostic copy, overwriting would destroy the only
+		// evidence of the corruption. Keep refusing writes in that case.
+		return nil, nil, fmt.Errorf("quarantine corrupt templates: %w", err)
+	}
+	if err := writeTemplates(path, templates); err != nil {
+		return nil, nil, fmt.Errorf("rewrite recovered templates: %w", err)
+	}
+	return templates, &sessioninfo.Recovery{
+		File:            path,
+		QuarantinedPath: quarantined,
+		Dropped:         dropped,
+	}, nil
+}
+
+// reportTemplateRecovery logs and emits a recovery event after readTemplates
+// dropped corrupt records. No-op when recovery is nil.
+func (s *Service) reportTemplateRecovery(sessionName string, recovery *sessioninfo.Recovery) {
+	if recovery == nil {
+		return
+	}
+	recovery.SessionName = sessionName
+	slog.Warn("[SCHEDULER] dropped corrupt templates and quarantined the original file",
+		"session", sessionName,
+		"path", recovery.File,
+		"quarantined_path", recovery.QuarantinedPath,
+		"dropped", len(recovery.Dropped),
+	)
+	s.deps.Emitter.Emit(sessioninfo.RecoveredEvent, *recovery)
 }
 
 func migrateLegacyTemplatesIfNeeded(
@@ -743,7 +773,7 @@ func migrateLegacyTemplatesIfNeeded(
 	return nil
 }
 
-// writeTemplates writes templates to file with indented JSON.
+// writeTemplates writes templates to file as a checksummed record envelope.
 // Uses write-to-temp + rename for atomic write safety (defensive-coding-checklist #60).
 func writeTemplates(path string, templates []Template) error {
 	dir := filepath.Dir(path)
@@ -751,7 +781,7 @@ func writeTemplates(path string, templates []Template) error {
 		return fmt.Errorf("create directory %s: %w", dir, err)
 	}
 
-	data, err := json.MarshalIndent(templates, "", "  ")
+	data, err := sessioninfo.EncodeRecords(templates)
 	if err != nil {
 		return fmt.Errorf("marshal templates: %w", err)
 	}
@@ -1111,8 +1111,14 @@ func TestLoadTemplatesPrefersSessionInfoOverLegacyProjectTemplates(t *testing.T)
 	}
 }
 
-func TestLoadTemplatesMalformedJSON(t *testing.T) {
+func TestLoadTemplatesMalformedJSONQuarantinesFile(t *testing.T) {
 	svc, workDir, configDir := setupTemplateTestService(t)
+	var events []sessioninfo.Recovery
+	svc.deps.Emitter = &testEmitter{onEmit: func(name string, payload any) {
+		if name == sessioninfo.RecoveredEvent {
+			events = append(events, payload.(sessioninfo.Recovery))
+		}
+	}}
 
 	templatePath := templatePathForTest(t, configDir, workDir)
 	dir := filepath.Dir(templatePath)
@@ -1130,29 +1136,66 @@ func TestLoadTemplatesMalformedJSON(t *testing.T) {
 	if len(loaded) != 0 {
 		t.Fatalf("expected 0 templates for malformed JSON, got %d", len(loaded))
 	}
+	if len(events) != 1 {
+		t.Fatalf("recovery events = %d, want 1", len(events))
+	}
+	got := events[0]
+	if got.SessionName != "test-session" || got.File != templatePath {
+		t.Fatalf("recovery = %+v, want session/file populated", got)
+	}
+	if len(got.Dropped) != 1 || got.Dropped[0].Index != -1 {
+		t.Fatalf("Dropped = %+v, want whole-file entry", got.Dropped)
+	}
+	quarantined, err := os.ReadFile(got.QuarantinedPath)
+	if err != nil {
+		t.Fatalf("ReadFile(quarantined) error = %v", err)
+	}
+	if string(quarantined) != "not json" {
+		t.Fatalf("quarantined content = %q, want original bytes", quarantined)
+	}
+
+	// The rewritten store must load cleanly without another recovery.
+	if _, err := svc.LoadTemplates("test-session"); err != nil {
+		t.Fatalf("second LoadTemplates() error = %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("recovery events after reload = %d, want 1", len(events))
+	}
 }
 
-func TestSaveTemplateMalformedJSONReturnsError(t *testing.T) {
+func TestLoadTemplatesNewerVersionLeavesFileUntouched(t *testing.T) {
 	svc, workDir, configDir := setupTemplateTestService(t)
 
 	templatePath := templatePathForTest(t, configDir, workDir)
-	dir := filepath.Dir(templatePath)
-	if err := os.MkdirAll(dir, 0o755); err != nil {
+	if err := os.MkdirAll(filepath.Dir(templatePath), 0o755); err != nil {
 		t.Fatal(err)
 	}
-	if err := os.WriteFile(templatePath, []byte("not json"), 0o644); err != nil {
+	newer := []byte(`{"format":"mytx-records","version":99,"records":[]}`)
+	if err := os.WriteFile(templatePath, newer, 0o644); err != nil {
 		t.Fatal(err)
 	}
 
-	err := svc.SaveTemplate("test-session", Template{
+	if _, err := svc.LoadTemplates("test-session"); !errors.Is(err, sessioninfo.ErrNewerRecordVersion) {
+		t.Fatalf("LoadTemplates() error = %v, want ErrNewerRecordVersion", err)
+	}
+	if err := svc.SaveTemplate("test-session", Template{
 		Title: "Check", Message: "hello", IntervalSeconds: 10, MaxCount: 1,
-	})
-	if err == nil || !strings.Contains(err.Error(), "parse templates") {
-		t.Fatalf("SaveTemplate() error = %v, want parse templates error", err)
+	}); err == nil {
+		t.Fatal("SaveTemplate() error = nil, want refusal to overwrite a newer file")
+	}
+	data, err := os.ReadFile(templatePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != string(newer) {
+		t.Fatalf("template file = %q, want it unchanged", data)
+	}
+	if matches, _ := filepath.Glob(templatePath + ".corrupt-*"); len(matches) != 0 {
+		t.Fatalf("quarantined files = %v, want none", matches)
 	}
 }
 
-func TestDeleteTemplateMalformedJSONReturnsError(t *testing.T) {
+func TestSaveTemplateMalformedJSONRecovers(t *testing.T) {
 	svc, workDir, configDir := setupTemplateTestService(t)
 
 	templatePath := templatePathForTest(t, configDir, workDir)
@@ -1164,9 +1207,93 @@ func TestDeleteTemplateMalformedJSONReturnsError(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	err := svc.DeleteTemplate("test-session", "Check")
-	if err == nil || !strings.Contains(err.Error(), "parse templates") {
-		t.Fatalf("DeleteTemplate() error = %v, want parse templates error", err)
+	if err := svc.SaveTemplate("test-session", Template{
+		Title: "Check", Message: "hello", IntervalSeconds: 10, MaxCount: 1,
+	}); err != nil {
+		t.Fatalf("SaveTemplate() error = %v", err)
+	}
+	loaded, err := svc.LoadTemplates("test-session")
+	if err != nil {
+		t.Fatalf("LoadTemplates() error = %v", err)
+	}
+	if len(loaded) != 1 || loaded[0].Title != "Check" {
+		t.Fatalf("LoadTemplates() = %+v, want only the new template", loaded)
+	}
+	matches, err := filepath.Glob(templatePath + ".corrupt-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 1 {
+		t.Fatalf("quarantined files = %v, want exactly one", matches)
+	}
+}
+
+func TestLoadTemplatesDropsChecksumMismatch(t *testing.T) {
+	svc, workDir, configDir := setupTemplateTestService(t)
+	var events []sessioninfo.Recovery
+	svc.deps.Emitter = &testEmitter{onEmit: func(name string, payload any) {
+		if name == sessioninfo.RecoveredEvent {
+			events = append(events, payload.(sessioninfo.Recovery))
+		}
+	}}
+
+	for _, title := range []string{"Alpha", "Beta"} {
+		if err := svc.SaveTemplate("test-session", Template{
+			Title: title, Message: "msg", IntervalSeconds: 10, MaxCount: 1,
+		}); err != nil {
+			t.Fatalf("SaveTemplate(%s) error = %v", title, err)
+		}
+	}
+
+	templatePath := templatePathForTest(t, configDir, workDir)
+	data, err := os.ReadFile(templatePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Tamper with the second record without updating its checksum.
+	tampered := strings.Replace(string(data), `"title": "Beta"`, `"title": "Gamma"`, 1)
+	if tampered == string(data) {
+		t.Fatal("test setup: Beta record not found in template file")
+	}
+	if err := os.WriteFile(templatePath, []byte(tampered), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := svc.LoadTemplates("test-session")
+	if err != nil {
+		t.Fatalf("LoadTemplates() error = %v", err)
+	}
+	if len(loaded) != 1 || loaded[0].Title != "Alpha" {
+		t.Fatalf("LoadTemplates() = %+v, want only Alpha", loaded)
+	}
+	if len(events) != 1 || len(events[0].Dropped) != 1 || events[0].Dropped[0].Index != 1 {
+		t.Fatalf("recovery events = %+v, want one event dropping index 1", events)
+	}
+}
+
+func TestLoadTemplatesAcceptsLegacyArray(t *testing.T) {
+	svc, workDir, configDir := setupTemplateTestService(t)
+	svc.deps.Emitter = &testEmitter{onEmit: func(name string, payload any) {
+		if name == sessioninfo.RecoveredEvent {
+			t.Errorf("unexpected recovery event for legacy array: %+v", payload)
+		}
+	}}
+
+	templatePath := templatePathForTest(t, configDir, workDir)
+	if err := os.MkdirAll(filepath.Dir(templatePath), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	legacy := `[{"title":"Legacy","message":"msg","interval_seconds":10,"max_count":1}]`
+	if err := os.WriteFile(templatePath, []byte(legacy), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := svc.LoadTemplates("test-session")
+	if err != nil {
+		t.Fatalf("LoadTemplates() error = %v", err)
+	}
+	if len(loaded) != 1 || loaded[0].Title != "Legacy" {
+		t.Fatalf("LoadTemplates() = %+v, want legacy template", loaded)
 	}
 }
 
@@ -1200,15 +1327,27 @@ func TestTemplateFileFormat(t *testing.T) {
 		t.Fatalf("ReadFile() error = %v", err)
 	}
 
-	var raw []map[string]any
+	var raw struct {
+		Format  string `json:"format"`
+		Records []struct {
+			SHA256 string         `json:"sha256"`
+			Data   map[string]any `json:"data"`
+		} `json:"records"`
+	}
 	if err := json.Unmarshal(data, &raw); err != nil {
 		t.Fatalf("json.Unmarshal() error = %v", err)
 	}
-	if len(raw) != 1 {
-		t.Fatalf("expected 1 entry in JSON, got %d", len(raw))
+	if raw.Format != "mytx-records" {
+		t.Errorf("format = %q, want %q", raw.Format, "mytx-records")
+	}
+	if len(raw.Records) != 1 {
+		t.Fatalf("expected 1 record in JSON, got %d", len(raw.Records))
+	}
+	if raw.Records[0].SHA256 == "" {
+		t.Error("record checksum is empty")
 	}
-	if raw[0]["title"] != "Test" {
-		t.Errorf("title = %v, want %q", raw[0]["title"], "Test")
+	if raw.Records[0].Data["title"] != "Test" {
+		t.Errorf("title = %v, want %q", raw.Records[0].Data["title"], "Test")
 	}
 }
 
@@ -1263,17 +1402,14 @@ func TestReadTemplatesIOError(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	// readTemplatesForWrite (allowMalformed=false) should return the error.
-	_, err := readTemplatesForWrite(dirAsFile)
+	// I/O errors are not corruption: they must surface instead of
+	// triggering quarantine.
+	_, recovery, err := readTemplates(dirAsFile)
 	if err == nil {
 		t.Fatal("expected error when path is a directory")
 	}
-
-	// readTemplates (allowMalformed=true) should also return the error
-	// (allowMalformed only affects JSON parse errors, not I/O errors).
-	_, err = readTemplates(dirAsFile)
-	if err == nil {
-		t.Fatal("expected error when path is a directory (even with allowMalformed)")
+	if recovery != nil {
+		t.Fatalf("recovery = %+v, want nil for I/O error", recovery)
 	}
 }
 
@@ -0,0 +1,102 @@
+// Package screensync turns successive pane screen states into an initial
+// keyframe followed by cell-level diffs, so that remote viewers receive only
+// what changed on screen instead of the raw terminal byte stream.
+package screensync
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// Frame types.
+const (
+	FrameKeyframe = "keyframe"
+	FrameDiff     = "diff"
+)
+
+// Screen is a row-oriented pane viewport.
+type Screen struct {
+	Cols  int
+	Rows  int
+	Lines []string
+}
+
+// CellChange replaces Del runes starting at rune offset Col of row Row with
+// Text. Offsets count runes, not bytes.
+type CellChange struct {
+	Row  int    `json:"row"`
+	Col  int    `json:"col"`
+	Del  int    `json:"del"`
+	Text string `json:"text"`
+}
+
+// Frame is one screen update sent to a viewer.
+// Keyframes carry the full screen in Lines; diffs carry Changes that apply
+// on top of the screen at BaseSeq.
+type Frame struct {
+	Type    string       `json:"type"`
+	PaneID  string       `json:"paneId"`
+	Seq     uint64       `json:"seq"`
+	BaseSeq uint64       `json:"baseSeq,omitempty"`
+	Cols    int          `json:"cols"`
+	Rows    int          `json:"rows"`
+	Lines   []string     `json:"lines,omitempty"`
+	Changes []CellChange `json:"changes,omitempty"`
+}
+
+// Diff returns the cell changes that turn prev into next. Both must have the
+// same number of rows; each changed row yields one change covering the span
+// between its common prefix and common suffix.
+func Diff(prev []string, next []string) []CellChange {
+	var changes []CellChange
+	for row := range min(len(prev), len(next)) {
+		if prev[row] == next[row] {
+			continue
+		}
+		changes = append(changes, diffRow(row, []rune(prev[row]), []rune(next[row])))
+	}
+	return changes
+}
+
+func diffRow(row int, prev []rune, next []rune) CellChange {
+	prefix := 0
+	for prefix < len(prev) && prefix < len(next) && prev[prefix] == next[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(prev)-prefix && suffix < len(next)-prefix &&
+		prev[len(prev)-1-suffix] == next[len(next)-1-suffix] {
+		suffix++
+	}
+	return CellChange{
+		Row:  row,
+		Col:  prefix,
+		Del:  len(prev) - prefix - suffix,
+		Text: string(next[prefix : len(next)-suffix]),
+	}
+}
+
+// Apply returns a copy of lines with changes applied. It is the viewer-side
+// counterpart of Diff.
+func Apply(lines []string, changes []CellChange) ([]string, error) {
+	out := append([]string(nil), lines...)
+	for _, change := range changes {
+		if change.Row < 0 || change.Row >= len(out) {
+			return nil, fmt.Errorf("change row %d out of range (rows %d)", change.Row, len(out))
+		}
+		row := []rune(out[change.Row])
+		if change.Col < 0 || change.Del < 0 || change.Col+change.Del > len(row) {
+			return nil, fmt.Errorf("change span %d+%d out of range in row %d (length %d)",
+				change.Col, change.Del, change.Row, len(row))
+		}
+		if !utf8.ValidString(change.Text) {
+			return nil, fmt.Errorf("change text for row %d is not valid UTF-8", change.Row)
+		}
+		updated := make([]rune, 0, len(row)-change.Del+utf8.RuneCountInString(change.Text))
+		updated = append(updated, row[:change.Col]...)
+		updated = append(updated, []rune(change.Text)...)
+		updated = append(updated, row[change.Col+change.Del:]...)
+		out[change.Row] = string(updated)
+	}
+	return out, nil
+}
@@ -0,0 +1,62 @@
+package screensync
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDiffProducesMinimalSpans(t *testing.T) {
+	prev := []string{"hello world", "same", "abc", "日本語テキスト", ""}
+	next := []string{"hello there", "same", "ab", "日本語のテキスト", "new"}
+
+	got := Diff(prev, next)
+	want := []CellChange{
+		{Row: 0, Col: 6, Del: 5, Text: "there"},
+		{Row: 2, Col: 2, Del: 1, Text: ""},
+		{Row: 3, Col: 3, Del: 0, Text: "の"},
+		{Row: 4, Col: 0, Del: 0, Text: "new"},
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("Diff() = %+v, want %+v", got, want)
+	}
+
+	applied, err := Apply(prev, got)
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if !slices.Equal(applied, next) {
+		t.Fatalf("Apply() = %q, want %q", applied, next)
+	}
+	if prev[0] != "hello world" {
+		t.Fatal("Apply() modified its input")
+	}
+}
+
+func TestDiffRepeatedRunes(t *testing.T) {
+	prev := []string{"aaaa"}
+	next := []string{"aa"}
+	applied, err := Apply(prev, Diff(prev, next))
+	if err != nil || !slices.Equal(applied, next) {
+		t.Fatalf("Apply(Diff()) = %q, %v, want %q", applied, err, next)
+	}
+}
+
+func TestApplyRejectsOutOfRangeChanges(t *testing.T) {
+	lines := []string{"abc"}
+	tests := []struct {
+		name   string
+		change CellChange
+	}{
+		{name: "row", change: CellChange{Row: 1}},
+		{name: "negative col", change: CellChange{Col: -1}},
+		{name: "span past end", change: CellChange{Col: 2, Del: 2}},
+		{name: "invalid text", change: CellChange{Text: "\xff"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Apply(lines, []CellChange{tt.change}); err == nil {
+				t.Fatalf("Apply(%+v) error = nil", tt.change)
+			}
+		})
+	}
+}
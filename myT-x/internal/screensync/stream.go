@@ -0,0 +1,133 @@
+package screensync
+
+import (
+	"slices"
+	"time"
+)
+
+const (
+	// DefaultKeyframeInterval is the maximum time between two keyframes.
+	DefaultKeyframeInterval = 30 * time.Second
+	// DefaultKeyframeEvery is the maximum number of diffs between two keyframes.
+	DefaultKeyframeEvery = 300
+	// DefaultWindow is how many frames may be unacknowledged before a stream
+	// holds back further updates.
+	DefaultWindow = 8
+)
+
+// Options tunes a Stream. Zero values select the defaults.
+type Options struct {
+	KeyframeInterval time.Duration
+	KeyframeEvery    int
+	Window           int
+}
+
+// Stream produces the frame sequence of one pane for one viewer.
+//
+// Diffs are always computed against the last screen sent, so while the
+// acknowledgment window is full the changes coalesce and a slow viewer gets
+// one larger diff instead of every intermediate state.
+//
+// Stream is not safe for concurrent use; callers serialize access.
+type Stream struct {
+	paneID string
+	opts   Options
+
+	last  Screen
+	sent  bool
+	seq   uint64
+	acked uint64
+
+	diffsSinceKeyframe int
+	lastKeyframe       time.Time
+	forceKeyframe      bool
+}
+
+// NewStream creates a stream for paneID. The first frame is a keyframe.
+func NewStream(paneID string, opts Options) *Stream {
+	if opts.KeyframeInterval <= 0 {
+		opts.KeyframeInterval = DefaultKeyframeInterval
+	}
+	if opts.KeyframeEvery <= 0 {
+		opts.KeyframeEvery = DefaultKeyframeEvery
+	}
+	if opts.Window <= 0 {
+		opts.Window = DefaultWindow
+	}
+	return &Stream{paneID: paneID, opts: opts}
+}
+
+// Next returns the frame that brings the viewer from the last sent screen to
+// screen. It reports false when nothing needs to be sent: the screen is
+// unchanged and no keyframe is due, or the acknowledgment window is full.
+func (s *Stream) Next(screen Screen, now time.Time) (Frame, bool) {
+	if s.Unacked() >= s.opts.Window {
+		return Frame{}, false
+	}
+	if s.keyframeDue(screen, now) {
+		return s.keyframe(screen, now), true
+	}
+	changes := Diff(s.last.Lines, screen.Lines)
+	if len(changes) == 0 {
+		return Frame{}, false
+	}
+	s.seq++
+	s.diffsSinceKeyframe++
+	s.last.Lines = slices.Clone(screen.Lines)
+	return Frame{
+		Type:    FrameDiff,
+		PaneID:  s.paneID,
+		Seq:     s.seq,
+		BaseSeq: s.seq - 1,
+		Cols:    screen.Cols,
+		Rows:    screen.Rows,
+		Changes: changes,
+	}, true
+}
+
+func (s *Stream) keyframeDue(screen Screen, now time.Time) bool {
+	return !s.sent ||
+		s.forceKeyframe ||
+		screen.Cols != s.last.Cols ||
+		screen.Rows != s.last.Rows ||
+		len(screen.Lines) != len(s.last.Lines) ||
+		s.diffsSinceKeyframe >= s.opts.KeyframeEvery ||
+		now.Sub(s.lastKeyframe) >= s.opts.KeyframeInterval
+}
+
+func (s *Stream) keyframe(screen Screen, now time.Time) Frame {
+	s.seq++
+	s.sent = true
+	s.forceKeyframe = false
+	s.diffsSinceKeyframe = 0
+	s.lastKeyframe = now
+	s.last = Screen{Cols: screen.Cols, Rows: screen.Rows, Lines: slices.Clone(screen.Lines)}
+	return Frame{
+		Type:   FrameKeyframe,
+		PaneID: s.paneID,
+		Seq:    s.seq,
+		Cols:   screen.Cols,
+		Rows:   screen.Rows,
+		Lines:  slices.Clone(screen.Lines),
+	}
+}
+
+// Ack records that the viewer has applied every frame up to seq.
+// Stale or future sequence numbers are ignored.
+func (s *Stream) Ack(seq uint64) {
+	if seq > s.acked && seq <= s.seq {
+		s.acked = seq
+	}
+}
+
+// RequestKeyframe makes the next frame a keyframe. Frames in flight are
+// treated as lost, which also reopens the acknowledgment window.
+func (s *Stream) RequestKeyframe() {
+	s.forceKeyframe = true
+	s.acked = s.seq
+}
+
+// Unacked returns the number of frames sent but not yet acknowledged.
+func (s *Stream) Unacked() int {
+	return int(s.seq - s.acked)
+}
@@ -0,0 +1,136 @@
+package screensync
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func screenOf(lines ...string) Screen {
+	return Screen{Cols: 10, Rows: len(lines), Lines: lines}
+}
+
+// viewer replays frames the way a remote client would.
+type viewer struct {
+	seq   uint64
+	lines []string
+}
+
+func (v *viewer) apply(t *testing.T, frame Frame) {
+	t.Helper()
+	switch frame.Type {
+	case FrameKeyframe:
+		v.lines = slices.Clone(frame.Lines)
+	case FrameDiff:
+		if frame.BaseSeq != v.seq {
+			t.Fatalf("diff base %d does not match viewer seq %d", frame.BaseSeq, v.seq)
+		}
+		lines, err := Apply(v.lines, frame.Changes)
+		if err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+		v.lines = lines
+	default:
+		t.Fatalf("unknown frame type %q", frame.Type)
+	}
+	v.seq = frame.Seq
+}
+
+func TestStreamKeyframeThenDiffs(t *testing.T) {
+	now := time.Unix(1000, 0)
+	stream := NewStream("%1", Options{})
+	var client viewer
+
+	frame, ok := stream.Next(screenOf("a", "b"), now)
+	if !ok || frame.Type != FrameKeyframe || frame.PaneID != "%1" || frame.Seq != 1 {
+		t.Fatalf("first frame = %+v, %v, want keyframe seq 1", frame, ok)
+	}
+	client.apply(t, frame)
+
+	if _, ok := stream.Next(screenOf("a", "b"), now); ok {
+		t.Fatal("Next() on unchanged screen = true, want nothing to send")
+	}
+
+	frame, ok = stream.Next(screenOf("a", "bc"), now)
+	if !ok || frame.Type != FrameDiff || len(frame.Changes) != 1 || frame.Lines != nil {
+		t.Fatalf("second frame = %+v, %v, want single-change diff", frame, ok)
+	}
+	client.apply(t, frame)
+	if !slices.Equal(client.lines, []string{"a", "bc"}) {
+		t.Fatalf("viewer lines = %q", client.lines)
+	}
+
+	frame, _ = stream.Next(Screen{Cols: 20, Rows: 2, Lines: []string{"a", "bc"}}, now)
+	if frame.Type != FrameKeyframe {
+		t.Fatalf("frame after resize = %+v, want keyframe", frame)
+	}
+}
+
+func TestStreamPeriodicKeyframes(t *testing.T) {
+	now := time.Unix(1000, 0)
+	stream := NewStream("%1", Options{KeyframeInterval: time.Minute, KeyframeEvery: 2, Window: 100})
+	stream.Next(screenOf("0"), now)
+
+	var types []string
+	for _, line := range []string{"1", "2", "3"} {
+		frame, _ := stream.Next(screenOf(line), now)
+		types = append(types, frame.Type)
+	}
+	if want := []string{FrameDiff, FrameDiff, FrameKeyframe}; !slices.Equal(types, want) {
+		t.Fatalf("frame types = %v, want %v", types, want)
+	}
+
+	// An unchanged screen still gets a keyframe once the interval elapses.
+	frame, ok := stream.Next(screenOf("3"), now.Add(time.Minute))
+	if !ok || frame.Type != FrameKeyframe {
+		t.Fatalf("frame after interval = %+v, %v, want keyframe", frame, ok)
+	}
+}
+
+func TestStreamWindowCoalescesUntilAcked(t *testing.T) {
+	now := time.Unix(1000, 0)
+	stream := NewStream("%1", Options{Window: 2})
+	var client viewer
+
+	for _, line := range []string{"a", "ab"} {
+		frame, ok := stream.Next(screenOf(line), now)
+		if !ok {
+			t.Fatalf("Next(%q) = false within window", line)
+		}
+		client.apply(t, frame)
+	}
+	for _, line := range []string{"abc", "abcd"} {
+		if _, ok := stream.Next(screenOf(line), now); ok {
+			t.Fatalf("Next(%q) = true with full window", line)
+		}
+	}
+
+	stream.Ack(99) // future seq is ignored
+	if got := stream.Unacked(); got != 2 {
+		t.Fatalf("Unacked() = %d, want 2", got)
+	}
+	stream.Ack(client.seq)
+	frame, ok := stream.Next(screenOf("abcd"), now)
+	if !ok || frame.Type != FrameDiff {
+		t.Fatalf("frame after ack = %+v, %v, want coalesced diff", frame, ok)
+	}
+	client.apply(t, frame)
+	if !slices.Equal(client.lines, []string{"abcd"}) {
+		t.Fatalf("viewer lines = %q, want abcd", client.lines)
+	}
+}
+
+func TestStreamRequestKeyframeReopensWindow(t *testing.T) {
+	now := time.Unix(1000, 0)
+	stream := NewStream("%1", Options{Window: 1})
+	stream.Next(screenOf("a"), now)
+	if _, ok := stream.Next(screenOf("b"), now); ok {
+		t.Fatal("Next() = true with full window")
+	}
+
+	stream.RequestKeyframe()
+	frame, ok := stream.Next(screenOf("b"), now)
+	if !ok || frame.Type != FrameKeyframe || frame.Seq != 2 {
+		t.Fatalf("frame after resync = %+v, %v, want keyframe seq 2", frame, ok)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"myT-x/internal/config"
 	gitpkg "myT-x/internal/git"
 	"myT-x/internal/tmux"
 )
@@ -64,6 +65,20 @@ func ApplySessionEnvFlags(sm *tmux.SessionManager, sessionName string, useClaude
 	}
 }
 
+// ResolveSessionStartupCommand returns the startup command for a new session's
+// initial pane. An explicit command takes priority over config
+// startup_commands.session; remain-on-exit is on when either source enables it.
+func ResolveSessionStartupCommand(cfg config.Config, command string, remainOnExit bool) (string, bool) {
+	command = config.NormalizeStartupCommand(command)
+	if cfg.StartupCommands == nil {
+		return command, remainOnExit
+	}
+	if command == "" {
+		command = cfg.StartupCommands.Session
+	}
+	return command, remainOnExit || cfg.StartupCommands.RemainOnExit
+}
+
 // EnrichSessionGitMetadata probes the rootPath for git information and stores
 // branch metadata on the session for sidebar display. This is best-effort:
 // any failure is logged but does not abort session creation.
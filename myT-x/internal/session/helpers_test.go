@@ -3,6 +3,7 @@ package session
 import (
 	"testing"
 
+	"myT-x/internal/config"
 	"myT-x/internal/tmux"
 )
 
@@ -51,6 +52,35 @@ func TestAgentTeamEnvVars(t *testing.T) {
 	}
 }
 
+func TestResolveSessionStartupCommand(t *testing.T) {
+	withConfig := config.Config{StartupCommands: &config.StartupCommandsConfig{
+		Session:      "npm run dev",
+		RemainOnExit: true,
+	}}
+	tests := []struct {
+		name        string
+		cfg         config.Config
+		command     string
+		remain      bool
+		wantCommand string
+		wantRemain  bool
+	}{
+		{name: "no config no command", cfg: config.Config{}, wantCommand: "", wantRemain: false},
+		{name: "explicit command without config", cfg: config.Config{}, command: " claude ", remain: true, wantCommand: "claude", wantRemain: true},
+		{name: "config fallback", cfg: withConfig, wantCommand: "npm run dev", wantRemain: true},
+		{name: "explicit command wins over config", cfg: withConfig, command: "claude", wantCommand: "claude", wantRemain: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotCommand, gotRemain := ResolveSessionStartupCommand(tt.cfg, tt.command, tt.remain)
+			if gotCommand != tt.wantCommand || gotRemain != tt.wantRemain {
+				t.Fatalf("ResolveSessionStartupCommand() = (%q, %v), want (%q, %v)",
+					gotCommand, gotRemain, tt.wantCommand, tt.wantRemain)
+			}
+		})
+	}
+}
+
 func TestPathsEqualFold(t *testing.T) {
 	tests := []struct {
 		name string
@@ -0,0 +1,76 @@
+package session
+
+import (
+	"path/filepath"
+	"time"
+
+	"myT-x/internal/sessionquery"
+	"myT-x/internal/tmux"
+)
+
+// QueryResult is one page of QuerySessions matches.
+type QueryResult struct {
+	Sessions []tmux.SessionSnapshot `json:"sessions"`
+	// Total counts every match, not just this page.
+	Total int `json:"total"`
+}
+
+// QuerySessions returns the page of sessions matching filter in sort order.
+// Repository and branch come from worktree metadata; a plain session matches
+// Repo by its root path and never matches Branch.
+func (s *Service) QuerySessions(filter sessionquery.Filter, sort sessionquery.Sort, page sessionquery.Page) (QueryResult, error) {
+	sessions, err := s.deps.RequireSessions()
+	if err != nil {
+		return QueryResult{}, err
+	}
+	snapshots := sessions.Snapshot()
+	activities := sessions.SessionActivities()
+
+	byName := make(map[string]tmux.SessionSnapshot, len(snapshots))
+	items := make([]sessionquery.Item, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		byName[snapshot.Name] = snapshot
+		items = append(items, queryItem(snapshot, activities[snapshot.Name]))
+	}
+
+	matched, total, err := sessionquery.Run(items, filter, sort, page, time.Now())
+	if err != nil {
+		return QueryResult{}, err
+	}
+	result := QueryResult{Sessions: make([]tmux.SessionSnapshot, 0, len(matched)), Total: total}
+	for _, item := range matched {
+		result.Sessions = append(result.Sessions, byName[item.Name])
+	}
+	return result, nil
+}
+
+func queryItem(snapshot tmux.SessionSnapshot, activity tmux.SessionActivity) sessionquery.Item {
+	item := sessionquery.Item{
+		Name:         snapshot.Name,
+		RepoPath:     snapshot.RootPath,
+		Status:       sessionquery.StatusRunning,
+		Detached:     snapshot.Detached,
+		CreatedAt:    snapshot.CreatedAt,
+		LastActivity: activity.LastActivity,
+	}
+	if wt := snapshot.Worktree; wt != nil {
+		if wt.RepoPath != "" {
+			item.RepoPath = wt.RepoPath
+		}
+		item.Branch = wt.BranchName
+	}
+	if item.RepoPath != "" {
+		item.RepoPath = filepath.Clean(item.RepoPath)
+	}
+	switch {
+	case activity.NeedsInput:
+		item.Status = sessionquery.StatusNeedsInput
+	case snapshot.IsIdle:
+		item.Status = sessionquery.StatusIdle
+	}
+	if badge := snapshot.Badge; badge != nil {
+		item.BadgeColor = badge.Color
+		item.BadgeEmoji = badge.Emoji
+	}
+	return item
+}
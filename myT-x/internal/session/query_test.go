@@ -0,0 +1,86 @@
+package session
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"myT-x/internal/sessionquery"
+	"myT-x/internal/tmux"
+)
+
+func snapshotNames(snapshots []tmux.SessionSnapshot) []string {
+	out := make([]string, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		out = append(out, snapshot.Name)
+	}
+	return out
+}
+
+func TestQuerySessions(t *testing.T) {
+	deps := newTestDeps()
+	sm, _ := deps.RequireSessions()
+	repo := filepath.Join(t.TempDir(), "Backend")
+	for _, name := range []string{"docs", "feature", "plain"} {
+		if _, _, err := sm.CreateSession(name, "main", 120, 40); err != nil {
+			t.Fatalf("CreateSession(%s) error = %v", name, err)
+		}
+	}
+	if err := sm.SetWorktreeInfo("feature", &tmux.SessionWorktreeInfo{
+		Path: filepath.Join(repo, ".wt", "feature"), RepoPath: repo, BranchName: "feature/search",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := sm.SetRootPath("plain", repo); err != nil {
+		t.Fatal(err)
+	}
+	if err := sm.SetSessionBadge("docs", &tmux.SessionBadge{Emoji: "📝"}); err != nil {
+		t.Fatal(err)
+	}
+	svc := NewService(deps)
+
+	tests := []struct {
+		name   string
+		filter sessionquery.Filter
+		sort   sessionquery.Sort
+		page   sessionquery.Page
+		want   []string
+		total  int
+	}{
+		{"all", sessionquery.Filter{}, sessionquery.Sort{}, sessionquery.Page{}, []string{"docs", "feature", "plain"}, 3},
+		{"repo", sessionquery.Filter{Repo: "backend"}, sessionquery.Sort{}, sessionquery.Page{}, []string{"feature", "plain"}, 2},
+		{"branch", sessionquery.Filter{Branch: "feature/*"}, sessionquery.Sort{}, sessionquery.Page{}, []string{"feature"}, 1},
+		{"badge", sessionquery.Filter{Badge: "📝"}, sessionquery.Sort{}, sessionquery.Page{}, []string{"docs"}, 1},
+		{"sorted page", sessionquery.Filter{}, sessionquery.Sort{By: sessionquery.SortName, Descending: true},
+			sessionquery.Page{Limit: 2}, []string{"plain", "feature"}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.QuerySessions(tt.filter, tt.sort, tt.page)
+			if err != nil {
+				t.Fatalf("QuerySessions() error = %v", err)
+			}
+			if !slices.Equal(snapshotNames(got.Sessions), tt.want) || got.Total != tt.total {
+				t.Fatalf("QuerySessions() = %v (total %d), want %v (total %d)",
+					snapshotNames(got.Sessions), got.Total, tt.want, tt.total)
+			}
+		})
+	}
+}
+
+func TestQuerySessionsErrors(t *testing.T) {
+	svc := NewService(newTestDeps())
+	if _, err := svc.QuerySessions(sessionquery.Filter{Status: "busy"}, sessionquery.Sort{}, sessionquery.Page{}); err == nil {
+		t.Fatal("QuerySessions() error = nil for unknown status")
+	}
+
+	deps := newTestDeps()
+	deps.RequireSessions = func() (*tmux.SessionManager, error) {
+		return nil, errors.New("unavailable")
+	}
+	svc = NewService(deps)
+	if _, err := svc.QuerySessions(sessionquery.Filter{}, sessionquery.Sort{}, sessionquery.Page{}); err == nil {
+		t.Fatal("QuerySessions() error = nil when session manager is unavailable")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
 	"os"
 	"path/filepath"
 	"strings"
@@ -15,6 +16,7 @@ import (
 	"myT-x/internal/config"
 	gitpkg "myT-x/internal/git"
 	"myT-x/internal/ipc"
+	"myT-x/internal/preopsnapshot"
 	"myT-x/internal/tmux"
 )
 
@@ -76,6 +78,11 @@ type Deps struct {
 	// (for example router callbacks plus Wails APIs) may invoke it more than once.
 	OnSessionDestroyed func(sessionName string)
 
+	// OnSessionCreated runs after a newly created session has its root path and
+	// worktree metadata stored, right before it becomes the active session.
+	// Optional: nil means no extra follow-up work is required.
+	OnSessionCreated func(sessionName string)
+
 	// OnSessionRenamed runs rename follow-up work after the tmux session map and
 	// active session name are updated, but before the rename event is emitted.
 	// Returning an error aborts the rename and triggers a best-effort rollback to
@@ -88,6 +95,18 @@ type Deps struct {
 	// Optional: nil means no extra reconciliation is required.
 	OnSessionRenameRollbackFailed func(oldName, newName string) error
 
+	// ResolveRepoStartupCommand returns the startup_command of the trusted
+	// per-repository .mytx.yaml in rootPath, or "" when there is none or the
+	// directory is not trusted. Used by CreateSession when the caller passes
+	// no startup command.
+	// Optional: nil means only config startup_commands.session applies.
+	ResolveRepoStartupCommand func(rootPath string) string
+
+	// CaptureBeforeRiskyOperation snapshots a session (scrollback tail, git
+	// status, layout) before KillSession force-removes its worktree.
+	// Optional: nil skips the snapshot.
+	CaptureBeforeRiskyOperation func(operation preopsnapshot.Operation, sessionName, detail string)
+
 	// --- IO operations (optional, defaults to stdlib) ---
 
 	// ExecuteRouterRequest dispatches a request to the command router.
@@ -201,16 +220,54 @@ func (s *Service) GetActiveSessionName() string {
 // This is the high-level API used by the Wails-bound SetActiveSession wrapper.
 // During shutdown, the event emission is skipped to avoid reaching a partially
 // torn-down runtime; the name is still stored for internal consistency.
+// Activating a detached session (new-session -d) attaches it.
 func (s *Service) SetActive(sessionName string) {
+	previous := s.GetActiveSessionName()
 	name := s.SetActiveSessionName(sessionName)
+	s.markSessionsSeen(previous, name)
 	if s.deps.IsShuttingDown() {
 		slog.Debug("[DEBUG-SESSION] SetActive: event emission skipped during shutdown",
 			"session", name)
 		return
 	}
+	s.attachIfDetached(name)
 	s.deps.Emitter.Emit("tmux:active-session", map[string]string{"name": name})
 }
 
+// markSessionsSeen records that the user has seen the output of the session
+// being left and of the session being activated, so neither counts as
+// unseen activity for navigation.
+func (s *Service) markSessionsSeen(names ...string) {
+	sessions, err := s.deps.RequireSessions()
+	if err != nil {
+		return
+	}
+	for _, name := range names {
+		sessions.MarkSessionSeen(name)
+	}
+}
+
+// attachIfDetached clears the detached flag of sessionName and notifies the
+// snapshot pipeline. Failures are logged only; activation proceeds regardless.
+func (s *Service) attachIfDetached(sessionName string) {
+	if sessionName == "" {
+		return
+	}
+	sessions, err := s.deps.RequireSessions()
+	if err != nil {
+		return
+	}
+	wasDetached, err := sessions.AttachSession(sessionName)
+	if err != nil {
+		slog.Debug("[DEBUG-SESSION] SetActive: attach skipped",
+			"session", sessionName, "error", err)
+		return
+	}
+	if wasDetached {
+		s.deps.EmitBackendEvent("tmux:session-attached", map[string]any{"name": sessionName})
+	}
+}
+
 // ===========================================================================
 // Session lifecycle — create, rename, kill, quick-start
 // ===========================================================================
@@ -251,12 +308,34 @@ func (s *Service) QuickStartSession(launchDir string) (tmux.SessionSnapshot, err
 	if mkErr := os.MkdirAll(dir, 0o755); mkErr != nil {
 		return tmux.SessionSnapshot{}, fmt.Errorf("quick start directory create failed: %w", mkErr)
 	}
+	return s.openDirectorySession(dir)
+}
+
+// OpenDirectorySession activates the session rooted at dir, or creates one
+// named after the directory when none exists. Unlike QuickStartSession it
+// never creates dir, so it is safe for paths from outside the app (mytx://
+// deep links). dir must be an absolute local path.
+func (s *Service) OpenDirectorySession(dir string) (tmux.SessionSnapshot, error) {
+	dir = strings.TrimSpace(dir)
+	if !filepath.IsAbs(dir) {
+		return tmux.SessionSnapshot{}, fmt.Errorf("directory must be an absolute path: %q", dir)
+	}
+	dir = filepath.Clean(dir)
+	if resolvedDir, evalErr := filepath.EvalSymlinks(dir); evalErr == nil {
+		dir = resolvedDir
+	}
+	return s.openDirectorySession(dir)
+}
+
+// openDirectorySession is the shared tail of QuickStartSession and
+// OpenDirectorySession; dir is already absolute and canonicalized.
+func (s *Service) openDirectorySession(dir string) (tmux.SessionSnapshot, error) {
 	info, err := os.Stat(dir)
 	if err != nil {
-		return tmux.SessionSnapshot{}, fmt.Errorf("quick start directory not accessible: %w", err)
+		return tmux.SessionSnapshot{}, fmt.Errorf("directory not accessible: %w", err)
 	}
 	if !info.IsDir() {
-		return tmux.SessionSnapshot{}, fmt.Errorf("quick start path is not a directory: %s", dir)
+		return tmux.SessionSnapshot{}, fmt.Errorf("path is not a directory: %s", dir)
 	}
 
 	// If directory is already used by an existing session, activate it.
@@ -279,7 +358,7 @@ func (s *Service) QuickStartSession(launchDir string) (tmux.SessionSnapshot, err
 				}
 			}
 		} else {
-			slog.Debug("[DEBUG-SESSION] quick start conflict snapshot unavailable; creating new session",
+			slog.Debug("[DEBUG-SESSION] directory conflict snapshot unavailable; creating new session",
 				"session", conflict, "path", dir, "error", sErr)
 		}
 		// NOTE: If we reach here, the conflict session disappeared between
@@ -319,6 +398,10 @@ func (s *Service) CreateSession(rootPath, sessionName string, opts CreateSession
 	if sessionName == "" {
 		return tmux.SessionSnapshot{}, errors.New("session name is required")
 	}
+	opts.StartupCommand = config.NormalizeStartupCommand(opts.StartupCommand)
+	if !config.IsValidStartupCommand(opts.StartupCommand) {
+		return tmux.SessionSnapshot{}, fmt.Errorf("startup command must be a single line of at most %d characters", config.MaxStartupCommandLen)
+	}
 	sessionName, releaseSessionName := s.ReserveAvailableSessionName(sessionName)
 	defer releaseSessionName()
 	createdName := ""
@@ -362,7 +445,15 @@ func (s *Service) CreateSession(rootPath, sessionName string, opts CreateSession
 	if err := s.StoreRootPath(createdName, rootPath); err != nil {
 		return tmux.SessionSnapshot{}, err
 	}
-	snapshot, retErr = s.ActivateCreatedSession(createdName)
+	startupCommand := opts.StartupCommand
+	if startupCommand == "" && s.deps.ResolveRepoStartupCommand != nil {
+		startupCommand = s.deps.ResolveRepoStartupCommand(rootPath)
+	}
+	s.RunStartupCommand(createdName, rootPath, startupCommand, opts.RemainOnExit)
+	if err := s.createTemplatePanes(createdName, rootPath, opts); err != nil {
+		return tmux.SessionSnapshot{}, err
+	}
+	snapshot, retErr = s.finishCreatedSession(createdName, opts.Detached)
 	return snapshot, retErr
 }
 
@@ -483,6 +574,13 @@ func (s *Service) KillSession(sessionName string, deleteWorktree bool) error {
 			"session", sessionName, "error", wtErr)
 	}
 
+	// Force cleanup discards uncommitted changes; snapshot the session while
+	// its panes still exist.
+	if deleteWorktree && worktreeInfo != nil && s.deps.CaptureBeforeRiskyOperation != nil &&
+		s.deps.GetConfigSnapshot().Worktree.ForceCleanup {
+		s.deps.CaptureBeforeRiskyOperation(preopsnapshot.OperationForceCleanup, sessionName, worktreeInfo.Path)
+	}
+
 	resp := s.deps.ExecuteRouterRequest(router, ipc.TmuxRequest{
 		Command: "kill-session",
 		Flags: map[string]any{
@@ -585,6 +683,19 @@ func (s *Service) CreateSessionForDirectory(
 			}
 		}
 	}
+	if opts.IsolateShellHistory {
+		if req.Env == nil {
+			req.Env = make(map[string]string, 1)
+		}
+		req.Env[tmux.ShellHistoryEnvVar] = tmux.ShellHistoryIsolated
+	}
+	// Template env is explicit per-session configuration and wins over both.
+	if len(opts.Env) > 0 {
+		if req.Env == nil {
+			req.Env = make(map[string]string, len(opts.Env))
+		}
+		maps.Copy(req.Env, opts.Env)
+	}
 	resp := s.deps.ExecuteRouterRequest(router, req)
 	if resp.ExitCode != 0 {
 		return "", fmt.Errorf("failed to create session: %s", strings.TrimSpace(resp.Stderr))
@@ -601,6 +712,31 @@ func (s *Service) CreateSessionForDirectory(
 	return createdName, nil
 }
 
+// RunStartupCommand runs the startup command in the initial pane of a newly
+// created session. command overrides config startup_commands.session; see
+// ResolveSessionStartupCommand.
+//
+// NOTE: Failure is non-fatal (Warn only). The session is fully usable without
+// its startup command, so creation is not rolled back.
+//
+// Internal step method: exported for cross-package wiring (worktree.Deps).
+func (s *Service) RunStartupCommand(sessionName, workDir, command string, remainOnExit bool) {
+	command, remainOnExit = ResolveSessionStartupCommand(s.deps.GetConfigSnapshot(), command, remainOnExit)
+	if command == "" {
+		return
+	}
+	router, err := s.deps.RequireRouter()
+	if err != nil {
+		slog.Warn("[WARN-SESSION] startup command skipped: router unavailable",
+			"session", sessionName, "error", err)
+		return
+	}
+	if err := router.RunStartupCommand(sessionName, workDir, command, remainOnExit); err != nil {
+		slog.Warn("[WARN-SESSION] startup command failed; continuing",
+			"session", sessionName, "error", err)
+	}
+}
+
 // rollbackSessionByRouter destroys a session by name via the command router.
 func (s *Service) rollbackSessionByRouter(router *tmux.CommandRouter, sessionName string) error {
 	resp := s.deps.ExecuteRouterRequest(router, ipc.TmuxRequest{
@@ -636,14 +772,31 @@ func (s *Service) StoreRootPath(sessionName, rootPath string) error {
 // Internal step method: exported for cross-package wiring (worktree.Deps).
 // External callers should use CreateSession.
 func (s *Service) ActivateCreatedSession(createdName string) (tmux.SessionSnapshot, error) {
+	return s.finishCreatedSession(createdName, false)
+}
+
+// finishCreatedSession is ActivateCreatedSession for CreateSession: a
+// detached session (CreateSessionOptions.Detached) is marked detached and
+// left inactive so it runs headless until SetActive attaches it.
+func (s *Service) finishCreatedSession(createdName string, detached bool) (tmux.SessionSnapshot, error) {
 	sessions, err := s.deps.RequireSessions()
 	if err != nil {
 		return tmux.SessionSnapshot{}, err
 	}
+	if detached {
+		if err := sessions.SetDetached(createdName, true); err != nil {
+			return tmux.SessionSnapshot{}, fmt.Errorf("failed to detach created session: %w", err)
+		}
+	}
+	if s.deps.OnSessionCreated != nil {
+		s.deps.OnSessionCreated(createdName)
+	}
 	snapshots := sessions.Snapshot()
 	for _, snapshot := range snapshots {
 		if snapshot.Name == createdName {
-			s.SetActiveSessionName(snapshot.Name)
+			if !detached {
+				s.SetActiveSessionName(snapshot.Name)
+			}
 			return snapshot, nil
 		}
 	}
@@ -926,6 +1079,16 @@ func (s *Service) CleanupSessionWorktree(params WorktreeCleanupParams) {
 		return
 	}
 
+	// A lock set by the user blocks removal; locks placed by myT-x are released.
+	if err := repo.ReleaseWorktreeLockForRemoval(wtPath); err != nil {
+		if errors.Is(err, gitpkg.ErrWorktreeLocked) {
+			err = fmt.Errorf("worktree cleanup skipped: %w", err)
+		}
+		slog.Warn("[WARN-GIT] failed to clean up worktree", "session", params.SessionName, "path", wtPath, "error", err)
+		s.EmitWorktreeCleanupFailure(params.SessionName, wtPath, err)
+		return
+	}
+
 	// Check for uncommitted changes in the worktree.
 	// On any error, skip cleanup to avoid data loss (unless ForceCleanup is set).
 	if !cfg.Worktree.ForceCleanup && !s.IsWorktreeCleanForRemoval(wtPath) {
@@ -3,6 +3,8 @@ package session
 import (
 	"context"
 	"errors"
+	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
@@ -79,7 +81,7 @@ func (e testEmitter) EmitWithContext(_ context.Context, name string, payload any
 // ---------------------------------------------------------------------------
 
 func TestDeps_FieldCount(t *testing.T) {
-	const expectedFieldCount = 12
+	const expectedFieldCount = 15
 	if got := reflect.TypeFor[Deps]().NumField(); got != expectedFieldCount {
 		t.Fatalf("Deps has %d fields, expected %d; update newTestDeps, "+
 			"newTestDepsWithRouter, newSessionServiceForTest, and this assertion", got, expectedFieldCount)
@@ -87,10 +89,10 @@ func TestDeps_FieldCount(t *testing.T) {
 }
 
 func TestCreateSessionOptionsFieldCountGuard(t *testing.T) {
-	const expectedFieldCount = 4
+	const expectedFieldCount = 11
 	if got := reflect.TypeFor[CreateSessionOptions]().NumField(); got != expectedFieldCount {
 		t.Fatalf("session.CreateSessionOptions field count = %d, want %d; "+
-			"update toSessionOpts() in app_session_api.go and this assertion", got, expectedFieldCount)
+			"update toSessionOpts() in app_session_api.go, TemplateCreateOptions, and this assertion", got, expectedFieldCount)
 	}
 }
 
@@ -190,6 +192,35 @@ func TestSetActive_SkipsEmitDuringShutdown(t *testing.T) {
 	}
 }
 
+func TestSetActive_AttachesDetachedSession(t *testing.T) {
+	deps := newTestDeps()
+	sessions, _ := deps.RequireSessions()
+	if _, _, err := sessions.CreateSession("batch", "0", 120, 40); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	if err := sessions.SetDetached("batch", true); err != nil {
+		t.Fatalf("SetDetached() error = %v", err)
+	}
+	var backendEvents []string
+	deps.EmitBackendEvent = func(name string, _ any) {
+		backendEvents = append(backendEvents, name)
+	}
+	svc := NewService(deps)
+
+	svc.SetActive("batch")
+	if snapshots := sessions.Snapshot(); snapshots[0].Detached {
+		t.Fatal("SetActive must clear Detached")
+	}
+	if len(backendEvents) != 1 || backendEvents[0] != "tmux:session-attached" {
+		t.Fatalf("backend events = %v, want [tmux:session-attached]", backendEvents)
+	}
+
+	svc.SetActive("batch")
+	if len(backendEvents) != 1 {
+		t.Fatalf("backend events = %v, want no re-emit for attached session", backendEvents)
+	}
+}
+
 func TestSetActiveSessionName_Normalizes(t *testing.T) {
 	svc := NewService(newTestDeps())
 	got := svc.SetActiveSessionName("  trimmed  ")
@@ -415,6 +446,40 @@ func TestReserveAvailableSessionName_ReleaseRestoresCandidate(t *testing.T) {
 	}
 }
 
+func TestOpenDirectorySession_ActivatesExistingSession(t *testing.T) {
+	dir := t.TempDir()
+	deps := newTestDeps()
+	sm := tmux.NewSessionManager()
+	sm.CreateSession("sess1", "0", 120, 40)
+	sm.SetRootPath("sess1", dir)
+	deps.RequireSessions = func() (*tmux.SessionManager, error) {
+		return sm, nil
+	}
+	svc := NewService(deps)
+
+	snapshot, err := svc.OpenDirectorySession(dir)
+	if err != nil {
+		t.Fatalf("OpenDirectorySession() error = %v", err)
+	}
+	if snapshot.Name != "sess1" || svc.GetActiveSessionName() != "sess1" {
+		t.Fatalf("OpenDirectorySession() = %q (active %q), want sess1", snapshot.Name, svc.GetActiveSessionName())
+	}
+}
+
+func TestOpenDirectorySession_RejectsMissingOrRelativeDir(t *testing.T) {
+	svc := NewService(newTestDeps())
+	if _, err := svc.OpenDirectorySession("relative/dir"); err == nil {
+		t.Fatal("OpenDirectorySession(relative) error = nil")
+	}
+	missing := filepath.Join(t.TempDir(), "missing")
+	if _, err := svc.OpenDirectorySession(missing); err == nil {
+		t.Fatal("OpenDirectorySession(missing) error = nil")
+	}
+	if _, statErr := os.Stat(missing); !os.IsNotExist(statErr) {
+		t.Fatalf("OpenDirectorySession created %s", missing)
+	}
+}
+
 func TestFindSessionByRootPath_Match(t *testing.T) {
 	deps := newTestDeps()
 	sm := tmux.NewSessionManager()
@@ -641,6 +706,26 @@ func TestActivateCreatedSession_SetsActiveAndReturnsSnapshot(t *testing.T) {
 	}
 }
 
+func TestFinishCreatedSession_DetachedStaysInactive(t *testing.T) {
+	deps := newTestDeps()
+	sm := tmux.NewSessionManager()
+	sm.CreateSession("batch", "0", 120, 40)
+	deps.RequireSessions = func() (*tmux.SessionManager, error) {
+		return sm, nil
+	}
+	svc := NewService(deps)
+	snap, err := svc.finishCreatedSession("batch", true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !snap.Detached {
+		t.Error("snapshot.Detached = false, want true")
+	}
+	if got := svc.GetActiveSessionName(); got != "" {
+		t.Errorf("GetActiveSessionName() = %q, want no active session", got)
+	}
+}
+
 // ---------------------------------------------------------------------------
 // EmitWorktreeCleanupFailure tests (C-2)
 // ---------------------------------------------------------------------------
@@ -737,6 +822,29 @@ func TestOverrideExecuteRouterRequest(t *testing.T) {
 	}
 }
 
+func TestCreateSessionForDirectory_IsolateShellHistory(t *testing.T) {
+	var got []ipc.TmuxRequest
+	svc := NewService(newTestDepsWithRouter(func(_ *tmux.CommandRouter, req ipc.TmuxRequest) ipc.TmuxResponse {
+		got = append(got, req)
+		return ipc.TmuxResponse{ExitCode: 0, Stdout: "demo\n"}
+	}))
+
+	for _, isolate := range []bool{false, true} {
+		if _, err := svc.CreateSessionForDirectory(`C:\work`, "demo", CreateSessionOptions{IsolateShellHistory: isolate}); err != nil {
+			t.Fatalf("CreateSessionForDirectory(isolate=%v) error = %v", isolate, err)
+		}
+	}
+	if len(got) != 2 {
+		t.Fatalf("requests = %d, want 2", len(got))
+	}
+	if _, ok := got[0].Env[tmux.ShellHistoryEnvVar]; ok {
+		t.Fatalf("shared history request env = %v, want no %s", got[0].Env, tmux.ShellHistoryEnvVar)
+	}
+	if got[1].Env[tmux.ShellHistoryEnvVar] != tmux.ShellHistoryIsolated {
+		t.Fatalf("isolated history request env = %v", got[1].Env)
+	}
+}
+
 // ---------------------------------------------------------------------------
 // FindSessionSnapshotByName tests
 // ---------------------------------------------------------------------------
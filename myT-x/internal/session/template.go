@@ -0,0 +1,114 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"strings"
+
+	"myT-x/internal/config"
+	"myT-x/internal/ipc"
+	"myT-x/internal/tmux"
+)
+
+// TemplateCreateOptions maps a session template (config session_templates)
+// to CreateSessionOptions. The template Command runs in the initial pane.
+func TemplateCreateOptions(template config.SessionTemplate) CreateSessionOptions {
+	return CreateSessionOptions{
+		StartupCommand: template.Command,
+		Env:            maps.Clone(template.Env),
+		Panes:          template.Panes,
+		Layout:         template.Layout,
+	}
+}
+
+// createTemplatePanes splits off opts.Panes after the initial pane, runs
+// their commands, and applies opts.Layout. Each pane splits the one created
+// before it and, like any split, inherits that pane's environment.
+//
+// A failed split is returned so that CreateSession rolls the session back.
+// Pane commands and the layout are best-effort: the panes are usable without
+// them, matching RunStartupCommand.
+func (s *Service) createTemplatePanes(sessionName, workDir string, opts CreateSessionOptions) error {
+	if len(opts.Panes) == 0 && opts.Layout == "" {
+		return nil
+	}
+	sessions, router, err := s.requireSessionsAndRouter()
+	if err != nil {
+		return err
+	}
+	session, ok := sessions.GetSession(sessionName)
+	if !ok || len(session.Windows) == 0 || len(session.Windows[0].Panes) == 0 {
+		return fmt.Errorf("session has no initial pane: %s", sessionName)
+	}
+	firstPaneID := session.Windows[0].Panes[0].IDString()
+
+	remainOnExit := false
+	if cfg := s.deps.GetConfigSnapshot(); cfg.StartupCommands != nil {
+		remainOnExit = cfg.StartupCommands.RemainOnExit
+	}
+
+	targetPaneID := firstPaneID
+	for i, pane := range opts.Panes {
+		env := maps.Clone(opts.Env)
+		if len(pane.Env) > 0 {
+			if env == nil {
+				env = make(map[string]string, len(pane.Env))
+			}
+			maps.Copy(env, pane.Env)
+		}
+		resp := s.deps.ExecuteRouterRequest(router, ipc.TmuxRequest{
+			Command: "split-window",
+			Flags: map[string]any{
+				"-t": targetPaneID,
+				"-c": workDir,
+				"-h": pane.Split == config.SessionTemplateSplitHorizontal,
+				"-d": true,
+				"-P": true,
+				"-F": "#{pane_id}",
+			},
+			Env: env,
+		})
+		if resp.ExitCode != 0 {
+			return fmt.Errorf("failed to create template pane %d: %s", i+1, strings.TrimSpace(resp.Stderr))
+		}
+		newPaneID := strings.TrimSpace(resp.Stdout)
+		if newPaneID == "" {
+			return fmt.Errorf("failed to create template pane %d: empty pane id returned by tmux", i+1)
+		}
+		if pane.Command != "" {
+			if err := router.RunStartupCommand(newPaneID, workDir, pane.Command, remainOnExit); err != nil {
+				slog.Warn("[WARN-SESSION] template pane command failed; continuing",
+					"session", sessionName, "paneId", newPaneID, "error", err)
+			}
+		}
+		targetPaneID = newPaneID
+	}
+
+	if opts.Layout != "" {
+		if err := applyTemplateLayout(sessions, sessionName, opts.Layout); err != nil {
+			slog.Warn("[WARN-SESSION] template layout not applied; continuing",
+				"session", sessionName, "layout", opts.Layout, "error", err)
+		}
+	}
+	// Focus stays on the initial pane, which runs the template Command.
+	if err := sessions.SetActivePane(session.Windows[0].Panes[0].ID); err != nil {
+		slog.Debug("[DEBUG-SESSION] failed to focus initial template pane",
+			"session", sessionName, "paneId", firstPaneID, "error", err)
+	}
+	return nil
+}
+
+// applyTemplateLayout applies a built-in preset name or a tmux layout string
+// to the active window of the session.
+func applyTemplateLayout(sessions *tmux.SessionManager, sessionName, layout string) error {
+	switch {
+	case tmux.IsBuiltinLayoutPreset(layout):
+		return sessions.ApplyLayoutPresetToActiveWindow(sessionName, tmux.LayoutPreset(layout))
+	case tmux.IsLayoutString(layout):
+		return sessions.ApplyLayoutStringToActiveWindow(sessionName, layout)
+	default:
+		return errors.New("layout must be a built-in preset name or a tmux layout string")
+	}
+}
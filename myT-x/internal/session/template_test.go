@@ -0,0 +1,132 @@
+package session
+
+import (
+	"maps"
+	"strconv"
+	"strings"
+	"testing"
+
+	"myT-x/internal/config"
+	"myT-x/internal/ipc"
+	"myT-x/internal/tmux"
+)
+
+func TestTemplateCreateOptions(t *testing.T) {
+	template := config.SessionTemplate{
+		Name:    "dev",
+		Command: "nvim .",
+		Env:     map[string]string{"APP_ENV": "dev"},
+		Panes:   []config.SessionTemplatePane{{Command: "npm run dev"}},
+		Layout:  "tiled",
+	}
+	opts := TemplateCreateOptions(template)
+	if opts.StartupCommand != "nvim ." || opts.Layout != "tiled" || len(opts.Panes) != 1 {
+		t.Fatalf("TemplateCreateOptions() = %#v", opts)
+	}
+	opts.Env["APP_ENV"] = "changed"
+	if template.Env["APP_ENV"] != "dev" {
+		t.Fatal("TemplateCreateOptions shares the template Env map")
+	}
+}
+
+func TestCreateTemplatePanes(t *testing.T) {
+	newService := func(t *testing.T, fail bool) (*Service, *tmux.SessionManager, *[]ipc.TmuxRequest) {
+		t.Helper()
+		sm := tmux.NewSessionManager()
+		if _, _, err := sm.CreateSession("dev", "0", 120, 40); err != nil {
+			t.Fatalf("CreateSession() error = %v", err)
+		}
+		var requests []ipc.TmuxRequest
+		deps := newTestDepsWithRouter(func(_ *tmux.CommandRouter, req ipc.TmuxRequest) ipc.TmuxResponse {
+			requests = append(requests, req)
+			if req.Command != "split-window" || fail {
+				return ipc.TmuxResponse{ExitCode: 1, Stderr: "split failed"}
+			}
+			target, _ := req.Flags["-t"].(string)
+			id, err := strconv.Atoi(strings.TrimPrefix(target, "%"))
+			if err != nil {
+				return ipc.TmuxResponse{ExitCode: 1, Stderr: err.Error()}
+			}
+			direction := tmux.SplitVertical
+			if horizontal, _ := req.Flags["-h"].(bool); horizontal {
+				direction = tmux.SplitHorizontal
+			}
+			pane, err := sm.SplitPane(id, direction)
+			if err != nil {
+				return ipc.TmuxResponse{ExitCode: 1, Stderr: err.Error()}
+			}
+			return ipc.TmuxResponse{Stdout: pane.IDString() + "\n"}
+		})
+		deps.RequireSessions = func() (*tmux.SessionManager, error) { return sm, nil }
+		return NewService(deps), sm, &requests
+	}
+
+	t.Run("splits panes in order with merged env", func(t *testing.T) {
+		svc, sm, requests := newService(t, false)
+		opts := CreateSessionOptions{
+			Env: map[string]string{"APP_ENV": "dev", "PORT": "1"},
+			Panes: []config.SessionTemplatePane{
+				{Split: config.SessionTemplateSplitHorizontal, Env: map[string]string{"PORT": "3000"}},
+				{Split: config.SessionTemplateSplitVertical},
+			},
+			Layout: "tiled",
+		}
+		if err := svc.createTemplatePanes("dev", "C:\\work", opts); err != nil {
+			t.Fatalf("createTemplatePanes() error = %v", err)
+		}
+		if len(*requests) != 2 {
+			t.Fatalf("requests = %d, want 2", len(*requests))
+		}
+		first, second := (*requests)[0], (*requests)[1]
+		if first.Flags["-t"] != "%0" || first.Flags["-h"] != true || first.Flags["-c"] != "C:\\work" {
+			t.Fatalf("first split flags = %#v", first.Flags)
+		}
+		if want := map[string]string{"APP_ENV": "dev", "PORT": "3000"}; !maps.Equal(first.Env, want) {
+			t.Fatalf("first split env = %v, want %v", first.Env, want)
+		}
+		if second.Flags["-t"] != "%1" || second.Flags["-h"] != false {
+			t.Fatalf("second split flags = %#v", second.Flags)
+		}
+		if want := map[string]string{"APP_ENV": "dev", "PORT": "1"}; !maps.Equal(second.Env, want) {
+			t.Fatalf("second split env = %v, want %v", second.Env, want)
+		}
+		if opts.Env["PORT"] != "1" {
+			t.Fatal("createTemplatePanes mutated the template env")
+		}
+
+		session, ok := sm.GetSession("dev")
+		if !ok || len(session.Windows[0].Panes) != 3 {
+			t.Fatalf("session panes = %v, want 3", session)
+		}
+		if session.Windows[0].ActivePN != 0 {
+			t.Fatalf("active pane index = %d, want 0", session.Windows[0].ActivePN)
+		}
+	})
+
+	t.Run("no panes and no layout is a no-op", func(t *testing.T) {
+		svc, _, requests := newService(t, false)
+		if err := svc.createTemplatePanes("dev", "", CreateSessionOptions{}); err != nil {
+			t.Fatalf("createTemplatePanes() error = %v", err)
+		}
+		if len(*requests) != 0 {
+			t.Fatalf("requests = %d, want 0", len(*requests))
+		}
+	})
+
+	t.Run("split failure is returned", func(t *testing.T) {
+		svc, _, _ := newService(t, true)
+		err := svc.createTemplatePanes("dev", "", CreateSessionOptions{
+			Panes: []config.SessionTemplatePane{{}},
+		})
+		if err == nil || !strings.Contains(err.Error(), "split failed") {
+			t.Fatalf("createTemplatePanes() error = %v, want split failure", err)
+		}
+	})
+
+	t.Run("invalid layout is not fatal", func(t *testing.T) {
+		svc, _, _ := newService(t, false)
+		if err := svc.createTemplatePanes("dev", "", CreateSessionOptions{Layout: "no-such-layout"}); err != nil {
+			t.Fatalf("createTemplatePanes() error = %v", err)
+		}
+	})
+}
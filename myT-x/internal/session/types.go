@@ -1,13 +1,25 @@
 package session
 
-// CreateSessionOptions holds the boolean options for session creation.
+import "myT-x/internal/config"
+
+// CreateSessionOptions holds the options for session creation.
 // The main package defines its own CreateSessionOptions with JSON tags for
 // Wails binding; the App layer maps between the two types.
 type CreateSessionOptions struct {
-	EnableAgentTeam     bool // set Agent Teams env vars on initial pane
-	UseClaudeEnv        bool // apply claude_env config to panes
-	UsePaneEnv          bool // apply pane_env config to additional panes
-	UseSessionPaneScope bool // set MYTX_SESSION on panes + scope list-panes
+	EnableAgentTeam     bool   // set Agent Teams env vars on initial pane
+	UseClaudeEnv        bool   // apply claude_env config to panes
+	UsePaneEnv          bool   // apply pane_env config to additional panes
+	UseSessionPaneScope bool   // set MYTX_SESSION on panes + scope list-panes
+	StartupCommand      string // run in the initial pane; empty = config startup_commands.session
+	RemainOnExit        bool   // keep the initial pane open after the startup command exits
+	IsolateShellHistory bool   // keep shell history in the session's state directory
+	Detached            bool   // create without activating; shown as detached until attached (new-session -d)
+
+	// Env, Panes, and Layout come from a session template (config
+	// session_templates); see TemplateCreateOptions.
+	Env    map[string]string            // set on every pane; overrides other env sources
+	Panes  []config.SessionTemplatePane // split off after the initial pane, in order
+	Layout string                       // built-in preset or tmux layout string applied last
 }
 
 // WorktreeCleanupParams holds parameters for CleanupSessionWorktree.
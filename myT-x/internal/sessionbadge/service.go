@@ -0,0 +1,144 @@
+package sessionbadge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"myT-x/internal/sessioninfo"
+	"myT-x/internal/statestore"
+)
+
+// badgeFileName is the legacy per-workdir badge file. Badges found there are
+// imported into the state store on first load.
+const badgeFileName = "session-badge.json"
+
+// Badge is the user-defined badge persisted for a session working directory.
+type Badge struct {
+	Color string `json:"color,omitempty"`
+	Emoji string `json:"emoji,omitempty"`
+}
+
+// IsEmpty reports whether the badge has neither color nor emoji.
+func (b Badge) IsEmpty() bool {
+	return b.Color == "" && b.Emoji == ""
+}
+
+// Deps contains App-level functions required by the session badge service.
+type Deps struct {
+	ResolveSessionWorkDir func(sessionName string) (string, error)
+	ConfigDir             func() (string, error)
+	// Store returns the state store badges are persisted in.
+	Store func() (statestore.Store, error)
+}
+
+// Service loads and persists user-defined session badges in the state store,
+// keyed by working directory, so a badge survives the session and is restored
+// when a session is created in the same working directory again.
+type Service struct {
+	deps Deps
+	// migrateMu serializes legacy file imports so a badge is imported once.
+	migrateMu sync.Mutex
+}
+
+// NewService creates a session badge service.
+func NewService(deps Deps) *Service {
+	if deps.ResolveSessionWorkDir == nil || deps.ConfigDir == nil || deps.Store == nil {
+		panic("sessionbadge.NewService: required function fields in Deps must be non-nil (ResolveSessionWorkDir, ConfigDir, Store)")
+	}
+	return &Service{deps: deps}
+}
+
+// Load returns the persisted badge of a session. A missing badge yields a zero Badge.
+func (s *Service) Load(sessionName string) (Badge, error) {
+	store, workDir, key, err := s.resolve(sessionName)
+	if err != nil {
+		return Badge{}, err
+	}
+	ctx := context.Background()
+
+	data, err := store.Get(ctx, statestore.BucketSessionBadges, key)
+	if errors.Is(err, statestore.ErrNotFound) {
+		data, err = s.importLegacyBadge(ctx, store, workDir, key)
+	}
+	if err != nil {
+		if errors.Is(err, statestore.ErrNotFound) {
+			return Badge{}, nil
+		}
+		return Badge{}, fmt.Errorf("read session badge: %w", err)
+	}
+
+	var badge Badge
+	if err := json.Unmarshal(data, &badge); err != nil {
+		return Badge{}, fmt.Errorf("parse session badge: %w", err)
+	}
+	return badge, nil
+}
+
+// Save persists badge for a session. An empty badge removes it.
+// Callers are expected to validate the badge beforehand.
+func (s *Service) Save(sessionName string, badge Badge) error {
+	store, _, key, err := s.resolve(sessionName)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	if badge.IsEmpty() {
+		if err := store.Delete(ctx, statestore.BucketSessionBadges, key); err != nil {
+			return fmt.Errorf("remove session badge: %w", err)
+		}
+		return nil
+	}
+	data, err := json.Marshal(badge)
+	if err != nil {
+		return fmt.Errorf("marshal badge: %w", err)
+	}
+	if err := store.Put(ctx, statestore.BucketSessionBadges, key, data); err != nil {
+		return fmt.Errorf("write session badge: %w", err)
+	}
+	return nil
+}
+
+// importLegacyBadge moves a badge from the legacy session-info file into the
+// store. It returns statestore.ErrNotFound when there is nothing to import.
+func (s *Service) importLegacyBadge(ctx context.Context, store statestore.Store, workDir, key string) ([]byte, error) {
+	configDir, err := s.deps.ConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	legacyPath, err := sessioninfo.FilePath(configDir, workDir, badgeFileName)
+	if err != nil {
+		return nil, err
+	}
+
+	s.migrateMu.Lock()
+	defer s.migrateMu.Unlock()
+	if _, err := statestore.ImportLegacyFile(ctx, store, statestore.BucketSessionBadges, key, legacyPath); err != nil {
+		return nil, err
+	}
+	return store.Get(ctx, statestore.BucketSessionBadges, key)
+}
+
+func (s *Service) resolve(sessionName string) (statestore.Store, string, string, error) {
+	sessionName = strings.TrimSpace(sessionName)
+	if sessionName == "" {
+		return nil, "", "", errors.New("session name is required for session badge")
+	}
+	workDir, err := s.deps.ResolveSessionWorkDir(sessionName)
+	if err != nil {
+		return nil, "", "", err
+	}
+	key, err := sessioninfo.FolderKey(workDir)
+	if err != nil {
+		return nil, "", "", err
+	}
+	store, err := s.deps.Store()
+	if err != nil {
+		return nil, "", "", err
+	}
+	return store, workDir, key, nil
+}
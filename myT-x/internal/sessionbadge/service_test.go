@@ -0,0 +1,120 @@
+package sessionbadge
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"myT-x/internal/sessioninfo"
+	"myT-x/internal/statestore"
+)
+
+func newTestService(t *testing.T) (*Service, string, string, statestore.Store) {
+	t.Helper()
+	rootPath := filepath.Join(t.TempDir(), "workspace")
+	configDir := filepath.Join(t.TempDir(), "config")
+	store := statestore.NewMemoryStore()
+	service := NewService(Deps{
+		ResolveSessionWorkDir: func(string) (string, error) {
+			return rootPath, nil
+		},
+		ConfigDir: func() (string, error) {
+			return configDir, nil
+		},
+		Store: func() (statestore.Store, error) {
+			return store, nil
+		},
+	})
+	return service, rootPath, configDir, store
+}
+
+func TestNewServicePanicsOnMissingDeps(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic for missing deps")
+		}
+	}()
+	NewService(Deps{})
+}
+
+func TestLoadReturnsEmptyWhenBadgeFileMissing(t *testing.T) {
+	service, _, _, _ := newTestService(t)
+
+	badge, err := service.Load("alpha")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !badge.IsEmpty() {
+		t.Fatalf("Load() = %+v, want empty badge", badge)
+	}
+}
+
+func TestSaveLoadRoundTripAndClear(t *testing.T) {
+	service, rootPath, _, store := newTestService(t)
+	want := Badge{Color: "#ff8800", Emoji: "🔥"}
+
+	if err := service.Save("alpha", want); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	// A different session in the same working directory shares the badge.
+	got, err := service.Load("beta")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got != want {
+		t.Fatalf("Load() = %+v, want %+v", got, want)
+	}
+
+	if err := service.Save("alpha", Badge{}); err != nil {
+		t.Fatalf("Save(empty) error = %v", err)
+	}
+	key, err := sessioninfo.FolderKey(rootPath)
+	if err != nil {
+		t.Fatalf("FolderKey() error = %v", err)
+	}
+	if _, err := store.Get(context.Background(), statestore.BucketSessionBadges, key); !errors.Is(err, statestore.ErrNotFound) {
+		t.Fatalf("badge record still present after clear: %v", err)
+	}
+	// Clearing an already-cleared badge is a no-op.
+	if err := service.Save("alpha", Badge{}); err != nil {
+		t.Fatalf("second Save(empty) error = %v", err)
+	}
+}
+
+func TestLoadRejectsEmptySessionName(t *testing.T) {
+	service, _, _, _ := newTestService(t)
+	if _, err := service.Load("  "); err == nil {
+		t.Fatal("Load() error = nil, want error for empty session name")
+	}
+}
+
+func TestLoadImportsLegacyBadgeFile(t *testing.T) {
+	service, rootPath, configDir, _ := newTestService(t)
+	legacyPath, err := sessioninfo.FilePath(configDir, rootPath, badgeFileName)
+	if err != nil {
+		t.Fatalf("FilePath() error = %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(legacyPath), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(legacyPath, []byte(`{"color":"#00ff00"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	badge, err := service.Load("alpha")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if badge.Color != "#00ff00" {
+		t.Fatalf("Load() = %+v, want legacy badge", badge)
+	}
+	if _, err := os.Stat(legacyPath); !os.IsNotExist(err) {
+		t.Fatalf("legacy badge file still present after import: %v", err)
+	}
+	// Later loads are served from the store.
+	if badge, err := service.Load("alpha"); err != nil || badge.Color != "#00ff00" {
+		t.Fatalf("second Load() = %+v, %v", badge, err)
+	}
+}
@@ -0,0 +1,422 @@
+// Package sessioncopy copies files and directories from one session's
+// directory to another's, e.g. a build artifact or a patch from one agent's
+// worktree to another. Paths are resolved against the session roots and must
+// stay inside them, also through symbolic links.
+package sessioncopy
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"myT-x/internal/apptypes"
+)
+
+const (
+	// ProgressEvent carries a Progress while a copy runs and once it ends.
+	ProgressEvent = "session:copy-progress"
+
+	// progressInterval throttles ProgressEvent while a copy runs.
+	progressInterval = 200 * time.Millisecond
+)
+
+// Request names the source and destination of a copy. Relative paths are
+// resolved against the session root. An empty DstPath places the source at
+// its path relative to the source root; a DstPath that is an existing
+// directory or ends with a separator receives the source inside it.
+type Request struct {
+	SrcSession string
+	SrcPath    string
+	DstSession string
+	DstPath    string
+}
+
+// Result reports a finished copy.
+type Result struct {
+	ID          string `json:"id"`
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+	Files       int    `json:"files"`
+	Bytes       int64  `json:"bytes"`
+}
+
+// Progress is the payload of ProgressEvent.
+type Progress struct {
+	ID          string `json:"id"`
+	SrcSession  string `json:"src_session"`
+	DstSession  string `json:"dst_session"`
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+	CopiedFiles int    `json:"copied_files"`
+	TotalFiles  int    `json:"total_files"`
+	CopiedBytes int64  `json:"copied_bytes"`
+	TotalBytes  int64  `json:"total_bytes"`
+	Done        bool   `json:"done"`
+	Error       string `json:"error,omitempty"`
+}
+
+// Deps holds external dependencies injected at construction time.
+type Deps struct {
+	// SessionRoot returns the directory of a session (its worktree or root
+	// path). Paths of the session must be inside it.
+	SessionRoot func(sessionName string) (string, error)
+
+	// Emitter sends runtime events to the frontend.
+	// Optional: defaults to a no-op emitter if nil.
+	Emitter apptypes.RuntimeEventEmitter
+}
+
+// Service copies between sessions.
+//
+// Thread-safety: copies run independently; the only shared state is the
+// atomic ID counter.
+type Service struct {
+	deps   Deps
+	nextID atomic.Uint64
+}
+
+// NewService creates a session copy service.
+// Panics if SessionRoot is nil.
+func NewService(deps Deps) *Service {
+	if deps.SessionRoot == nil {
+		panic("sessioncopy.NewService: SessionRoot must be non-nil")
+	}
+	if deps.Emitter == nil {
+		deps.Emitter = apptypes.NoopEmitter{}
+	}
+	return &Service{deps: deps}
+}
+
+// entry is one file or directory to create.
+type entry struct {
+	src  string
+	dst  string
+	dir  bool
+	size int64
+	perm fs.FileMode
+}
+
+// Copy copies req.SrcPath of req.SrcSession to req.DstPath of req.DstSession.
+// Existing files are replaced and existing directories are merged, like cp.
+// Symbolic links and .git entries inside a copied directory are skipped.
+func (s *Service) Copy(req Request) (Result, error) {
+	srcRoot, err := s.sessionRoot(req.SrcSession)
+	if err != nil {
+		return Result{}, err
+	}
+	dstRoot, err := s.sessionRoot(req.DstSession)
+	if err != nil {
+		return Result{}, err
+	}
+	src, err := resolveSource(srcRoot, req.SrcPath)
+	if err != nil {
+		return Result{}, err
+	}
+	info, err := os.Stat(src)
+	if err != nil {
+		return Result{}, fmt.Errorf("source: %w", err)
+	}
+	dst, err := resolveDestination(srcRoot, src, dstRoot, req.DstPath)
+	if err != nil {
+		return Result{}, err
+	}
+	if dst == src {
+		return Result{}, errors.New("source and destination are the same")
+	}
+	if info.IsDir() && isWithin(dst, src) {
+		return Result{}, fmt.Errorf("cannot copy %s into itself", src)
+	}
+	if dstInfo, err := os.Stat(dst); err == nil && dstInfo.IsDir() != info.IsDir() {
+		if info.IsDir() {
+			return Result{}, fmt.Errorf("a file named %s already exists", dst)
+		}
+		return Result{}, fmt.Errorf("a directory named %s already exists", dst)
+	}
+
+	entries, err := plan(src, dst, info)
+	if err != nil {
+		return Result{}, err
+	}
+	if err := checkLandings(entries, dstRoot); err != nil {
+		return Result{}, err
+	}
+	progress := Progress{
+		ID:          fmt.Sprintf("copy-%d", s.nextID.Add(1)),
+		SrcSession:  req.SrcSession,
+		DstSession:  req.DstSession,
+		Source:      src,
+		Destination: dst,
+	}
+	for _, e := range entries {
+		if !e.dir {
+			progress.TotalFiles++
+			progress.TotalBytes += e.size
+		}
+	}
+	s.deps.Emitter.Emit(ProgressEvent, progress)
+
+	copyErr := s.copyEntries(entries, dstRoot, &progress)
+	progress.Done = true
+	if copyErr != nil {
+		progress.Error = copyErr.Error()
+	}
+	s.deps.Emitter.Emit(ProgressEvent, progress)
+	if copyErr != nil {
+		return Result{}, copyErr
+	}
+	slog.Info("[SESSION-COPY] copied between sessions",
+		"from", req.SrcSession, "to", req.DstSession, "source", src, "destination", dst,
+		"files", progress.CopiedFiles, "bytes", progress.CopiedBytes)
+	return Result{
+		ID:          progress.ID,
+		Source:      src,
+		Destination: dst,
+		Files:       progress.CopiedFiles,
+		Bytes:       progress.CopiedBytes,
+	}, nil
+}
+
+func (s *Service) sessionRoot(sessionName string) (string, error) {
+	if strings.TrimSpace(sessionName) == "" {
+		return "", errors.New("session name is required")
+	}
+	root, err := s.deps.SessionRoot(sessionName)
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(root) == "" {
+		return "", fmt.Errorf("session %q has no directory", sessionName)
+	}
+	resolved, err := filepath.EvalSymlinks(filepath.Clean(root))
+	if err != nil {
+		return "", fmt.Errorf("directory of session %q: %w", sessionName, err)
+	}
+	return resolved, nil
+}
+
+// resolveSource resolves path against root and its symbolic links, and
+// checks that the result stays inside root.
+func resolveSource(root, path string) (string, error) {
+	if strings.TrimSpace(path) == "" {
+		return "", errors.New("source path is required")
+	}
+	joined := joinRoot(root, path)
+	if !isWithin(joined, root) {
+		return "", fmt.Errorf("source %s is outside the source session directory %s", path, root)
+	}
+	resolved, err := filepath.EvalSymlinks(joined)
+	if err != nil {
+		return "", fmt.Errorf("source: %w", err)
+	}
+	if !isWithin(resolved, root) {
+		return "", fmt.Errorf("source %s leads outside the source session directory %s", path, root)
+	}
+	return resolved, nil
+}
+
+// resolveDestination resolves the destination of src and checks that it,
+// and the existing directory it would be created in, stay inside dstRoot.
+func resolveDestination(srcRoot, src, dstRoot, path string) (string, error) {
+	var dst string
+	switch {
+	case strings.TrimSpace(path) == "":
+		rel, err := filepath.Rel(srcRoot, src)
+		if err != nil {
+			return "", err
+		}
+		dst = filepath.Join(dstRoot, rel)
+	default:
+		dst = joinRoot(dstRoot, path)
+		if info, err := os.Stat(dst); (err == nil && info.IsDir()) || strings.HasSuffix(path, "/") || strings.HasSuffix(path, `\`) {
+			dst = filepath.Join(dst, filepath.Base(src))
+		}
+	}
+	if !isWithin(dst, dstRoot) {
+		return "", fmt.Errorf("destination %s is outside the destination session directory %s", path, dstRoot)
+	}
+	if dst == dstRoot {
+		return "", errors.New("destination must not be the session directory itself")
+	}
+
+	return resolveLanding(dst, dstRoot)
+}
+
+// resolveLanding resolves the symbolic links and junctions of path, or of its
+// nearest existing ancestor, and checks that the result stays inside root.
+// It returns path rebased onto the resolved ancestor.
+func resolveLanding(path, root string) (string, error) {
+	// The nearest existing ancestor decides where a write really lands.
+	existing := path
+	for {
+		if _, err := os.Lstat(existing); err == nil {
+			break
+		}
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			break
+		}
+		existing = parent
+	}
+	resolved, err := filepath.EvalSymlinks(existing)
+	if err != nil {
+		return "", fmt.Errorf("destination: %w", err)
+	}
+	if !isWithin(resolved, root) {
+		return "", fmt.Errorf("destination %s leads outside the destination session directory %s", path, root)
+	}
+	if rel, err := filepath.Rel(existing, path); err == nil {
+		path = filepath.Join(resolved, rel)
+	}
+	return path, nil
+}
+
+// checkLandings rejects entries that an existing symbolic link or junction
+// in the destination tree would redirect outside dstRoot.
+func checkLandings(entries []entry, dstRoot string) error {
+	for _, e := range entries {
+		if _, err := resolveLanding(e.dst, dstRoot); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// plan lists the entries to create, directories before their contents.
+func plan(src, dst string, info fs.FileInfo) ([]entry, error) {
+	if !info.IsDir() {
+		return []entry{{src: src, dst: dst, size: info.Size(), perm: info.Mode().Perm()}}, nil
+	}
+	var entries []entry
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Name() == ".git" && path != src {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		switch {
+		case d.IsDir():
+			entries = append(entries, entry{src: path, dst: target, dir: true})
+		case d.Type().IsRegular():
+			fileInfo, err := d.Info()
+			if err != nil {
+				return err
+			}
+			entries = append(entries, entry{src: path, dst: target, size: fileInfo.Size(), perm: fileInfo.Mode().Perm()})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", src, err)
+	}
+	return entries, nil
+}
+
+// copyEntries creates entries in order. Each landing is checked again right
+// before it is written, as the destination tree may change during the copy.
+func (s *Service) copyEntries(entries []entry, dstRoot string, progress *Progress) error {
+	if len(entries) > 0 {
+		if err := os.MkdirAll(filepath.Dir(entries[0].dst), 0o755); err != nil {
+			return err
+		}
+	}
+	lastEmit := time.Now()
+	report := func(n int64) {
+		progress.CopiedBytes += n
+		if time.Since(lastEmit) >= progressInterval {
+			lastEmit = time.Now()
+			s.deps.Emitter.Emit(ProgressEvent, *progress)
+		}
+	}
+	for _, e := range entries {
+		if _, err := resolveLanding(e.dst, dstRoot); err != nil {
+			return err
+		}
+		if e.dir {
+			if err := os.MkdirAll(e.dst, 0o755); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := copyFile(e, report); err != nil {
+			return fmt.Errorf("copy %s: %w", e.src, err)
+		}
+		progress.CopiedFiles++
+	}
+	return nil
+}
+
+// copyFile copies e through a temporary file in the destination directory
+// so an interrupted copy never leaves a truncated file.
+func copyFile(e entry, report func(int64)) error {
+	in, err := os.Open(e.src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	tmp, err := os.CreateTemp(filepath.Dir(e.dst), "."+filepath.Base(e.dst)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	_, copyErr := io.Copy(tmp, &progressReader{r: in, report: report})
+	closeErr := tmp.Close()
+	if err := errors.Join(copyErr, closeErr); err != nil {
+		_ = os.Remove(tmpName)
+		return err
+	}
+	if err := os.Chmod(tmpName, e.perm|0o200); err != nil {
+		_ = os.Remove(tmpName)
+		return err
+	}
+	if err := os.Rename(tmpName, e.dst); err != nil {
+		_ = os.Remove(tmpName)
+		return err
+	}
+	return nil
+}
+
+// progressReader reports the bytes read through it.
+type progressReader struct {
+	r      io.Reader
+	report func(int64)
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	if n > 0 {
+		p.report(int64(n))
+	}
+	return n, err
+}
+
+// joinRoot resolves path against root unless it is absolute.
+func joinRoot(root, path string) string {
+	path = strings.TrimSpace(path)
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+	return filepath.Join(root, path)
+}
+
+// isWithin reports whether path is dir or inside it.
+func isWithin(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && (rel == "." || filepath.IsLocal(rel))
+}
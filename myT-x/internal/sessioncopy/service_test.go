@@ -0,0 +1,231 @@
+package sessioncopy
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type recordingEmitter struct {
+	mu     sync.Mutex
+	events []Progress
+}
+
+func (e *recordingEmitter) Emit(name string, payload any) {
+	if name != ProgressEvent {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.events = append(e.events, payload.(Progress))
+}
+
+func (e *recordingEmitter) EmitWithContext(_ context.Context, name string, payload any) {
+	e.Emit(name, payload)
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+// newTestService returns a service over two session directories, "a" and "b".
+func newTestService(t *testing.T) (*Service, *recordingEmitter, string, string) {
+	t.Helper()
+	base := t.TempDir()
+	roots := map[string]string{
+		"a": filepath.Join(base, "a"),
+		"b": filepath.Join(base, "b"),
+	}
+	for _, root := range roots {
+		if err := os.MkdirAll(root, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	emitter := &recordingEmitter{}
+	svc := NewService(Deps{
+		SessionRoot: func(name string) (string, error) {
+			if root, ok := roots[name]; ok {
+				return root, nil
+			}
+			return "", fmt.Errorf("session %q not found", name)
+		},
+		Emitter: emitter,
+	})
+	return svc, emitter, roots["a"], roots["b"]
+}
+
+func TestCopyFileKeepsRelativePathByDefault(t *testing.T) {
+	svc, emitter, rootA, rootB := newTestService(t)
+	writeFile(t, filepath.Join(rootA, "dist", "app.zip"), "artifact")
+
+	result, err := svc.Copy(Request{SrcSession: "a", SrcPath: "dist/app.zip", DstSession: "b"})
+	if err != nil {
+		t.Fatalf("Copy() error = %v", err)
+	}
+	want := filepath.Join(rootB, "dist", "app.zip")
+	if !strings.EqualFold(result.Destination, want) {
+		t.Fatalf("Destination = %q, want %q", result.Destination, want)
+	}
+	if got := readFile(t, want); got != "artifact" {
+		t.Fatalf("copied content = %q", got)
+	}
+	if result.Files != 1 || result.Bytes != int64(len("artifact")) {
+		t.Fatalf("Result = %+v, want 1 file of 8 bytes", result)
+	}
+
+	emitter.mu.Lock()
+	defer emitter.mu.Unlock()
+	if len(emitter.events) < 2 {
+		t.Fatalf("progress events = %d, want a start and a done event", len(emitter.events))
+	}
+	last := emitter.events[len(emitter.events)-1]
+	if !last.Done || last.Error != "" || last.CopiedBytes != last.TotalBytes || last.ID != result.ID {
+		t.Fatalf("last progress = %+v, want a successful done event", last)
+	}
+}
+
+func TestCopyIntoExistingDirectoryAndReplaceFile(t *testing.T) {
+	svc, _, rootA, rootB := newTestService(t)
+	writeFile(t, filepath.Join(rootA, "fix.patch"), "new")
+	writeFile(t, filepath.Join(rootB, "patches", "fix.patch"), "old")
+
+	if _, err := svc.Copy(Request{SrcSession: "a", SrcPath: "fix.patch", DstSession: "b", DstPath: "patches"}); err != nil {
+		t.Fatalf("Copy() error = %v", err)
+	}
+	if got := readFile(t, filepath.Join(rootB, "patches", "fix.patch")); got != "new" {
+		t.Fatalf("copied content = %q, want the replaced file", got)
+	}
+
+	if _, err := svc.Copy(Request{SrcSession: "a", SrcPath: filepath.Join(rootA, "fix.patch"), DstSession: "b", DstPath: "incoming/"}); err != nil {
+		t.Fatalf("Copy() to a new directory error = %v", err)
+	}
+	if got := readFile(t, filepath.Join(rootB, "incoming", "fix.patch")); got != "new" {
+		t.Fatalf("copied content = %q", got)
+	}
+}
+
+func TestCopyDirectorySkipsGitEntries(t *testing.T) {
+	svc, _, rootA, rootB := newTestService(t)
+	writeFile(t, filepath.Join(rootA, "build", "out.txt"), "out")
+	writeFile(t, filepath.Join(rootA, "build", "sub", "more.txt"), "more")
+	writeFile(t, filepath.Join(rootA, "build", ".git"), "gitdir: elsewhere")
+
+	result, err := svc.Copy(Request{SrcSession: "a", SrcPath: "build", DstSession: "b", DstPath: "from-a"})
+	if err != nil {
+		t.Fatalf("Copy() error = %v", err)
+	}
+	if result.Files != 2 {
+		t.Fatalf("Files = %d, want 2", result.Files)
+	}
+	if got := readFile(t, filepath.Join(rootB, "from-a", "sub", "more.txt")); got != "more" {
+		t.Fatalf("copied content = %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(rootB, "from-a", ".git")); !os.IsNotExist(err) {
+		t.Fatalf(".git should not be copied, stat error = %v", err)
+	}
+}
+
+func TestCopyRejectsPathsOutsideSessionDirectories(t *testing.T) {
+	svc, emitter, rootA, rootB := newTestService(t)
+	writeFile(t, filepath.Join(rootA, "file.txt"), "x")
+	outside := filepath.Join(filepath.Dir(rootA), "outside.txt")
+	writeFile(t, outside, "secret")
+
+	cases := []Request{
+		{SrcSession: "a", SrcPath: "../outside.txt", DstSession: "b"},
+		{SrcSession: "a", SrcPath: outside, DstSession: "b"},
+		{SrcSession: "a", SrcPath: "file.txt", DstSession: "b", DstPath: "../escape.txt"},
+		{SrcSession: "a", SrcPath: "file.txt", DstSession: "b", DstPath: rootA},
+		{SrcSession: "a", SrcPath: "file.txt", DstSession: "missing"},
+		{SrcSession: "a", SrcPath: "", DstSession: "b"},
+		{SrcSession: "a", SrcPath: ".", DstSession: "a", DstPath: "copy"},
+		{SrcSession: "a", SrcPath: "file.txt", DstSession: "a"},
+	}
+	for _, req := range cases {
+		if _, err := svc.Copy(req); err == nil {
+			t.Errorf("Copy(%+v) should fail", req)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(rootB), "escape.txt")); !os.IsNotExist(err) {
+		t.Fatalf("nothing may be written outside the session directory, stat error = %v", err)
+	}
+	if len(emitter.events) != 0 {
+		t.Fatalf("rejected copies emitted %d progress events", len(emitter.events))
+	}
+}
+
+func TestCopyRejectsSymlinkLeavingSessionDirectory(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("creating symbolic links needs Developer Mode on Windows")
+	}
+	svc, _, rootA, rootB := newTestService(t)
+	outside := filepath.Join(filepath.Dir(rootA), "outside")
+	writeFile(t, filepath.Join(outside, "secret.txt"), "secret")
+	if err := os.Symlink(outside, filepath.Join(rootA, "link")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(rootB, "link")); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(rootA, "file.txt"), "x")
+
+	if _, err := svc.Copy(Request{SrcSession: "a", SrcPath: "link/secret.txt", DstSession: "b", DstPath: "secret.txt"}); err == nil {
+		t.Fatal("Copy() through a source link leaving the session should fail")
+	}
+	if _, err := svc.Copy(Request{SrcSession: "a", SrcPath: "file.txt", DstSession: "b", DstPath: "link/file.txt"}); err == nil {
+		t.Fatal("Copy() through a destination link leaving the session should fail")
+	}
+}
+
+func TestCopyRejectsSymlinkInsideExistingDestination(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("creating symbolic links needs Developer Mode on Windows")
+	}
+	svc, emitter, rootA, rootB := newTestService(t)
+	writeFile(t, filepath.Join(rootA, "build", "out.txt"), "out")
+	writeFile(t, filepath.Join(rootA, "build", "sub", "more.txt"), "more")
+	outside := filepath.Join(filepath.Dir(rootB), "outside")
+	if err := os.MkdirAll(outside, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	// The copy merges into the existing build directory of b.
+	if err := os.MkdirAll(filepath.Join(rootB, "build"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(rootB, "build", "sub")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := svc.Copy(Request{SrcSession: "a", SrcPath: "build", DstSession: "b"}); err == nil {
+		t.Fatal("Copy() through a link nested in the destination should fail")
+	}
+	if _, err := os.Stat(filepath.Join(outside, "more.txt")); !os.IsNotExist(err) {
+		t.Fatalf("nothing may be written through the nested link, stat error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(rootB, "build", "out.txt")); !os.IsNotExist(err) {
+		t.Fatalf("a rejected copy must not be partially applied, stat error = %v", err)
+	}
+	if len(emitter.events) != 0 {
+		t.Fatalf("rejected copy emitted %d progress events", len(emitter.events))
+	}
+}
@@ -0,0 +1,197 @@
+// Package sessiongroup manages session groups: sessions that belong to one
+// workspace, e.g. the frontend, backend and infra repositories of a
+// microservices project, and are created and killed as one unit.
+package sessiongroup
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"unicode"
+
+	"myT-x/internal/tmux"
+	"myT-x/internal/worktree"
+)
+
+// maxGroupNameLen bounds the length of a group name.
+const maxGroupNameLen = 64
+
+// Member describes one session of a group created by Create.
+type Member struct {
+	// RepoPath is the session root, or the repository of its worktree.
+	RepoPath string `json:"repo_path"`
+	// SessionName is deduplicated like CreateSession; empty derives it from RepoPath.
+	SessionName string `json:"session_name"`
+	// Worktree creates the session on a new worktree of RepoPath.
+	// Nil creates a plain session rooted at RepoPath.
+	Worktree *worktree.WorktreeSessionOptions `json:"worktree,omitempty"`
+	// Options configures a plain session. Ignored when Worktree is set.
+	Options worktree.SessionEnvOptions `json:"options"`
+}
+
+// Deps holds external dependencies injected at construction time.
+type Deps struct {
+	// RequireSessions returns the session manager or an error when it is not
+	// initialized.
+	RequireSessions func() (*tmux.SessionManager, error)
+
+	// CreateMember creates the session of one member.
+	CreateMember func(member Member) (tmux.SessionSnapshot, error)
+
+	// KillSession closes a session and, when deleteWorktree is true, removes
+	// its worktree.
+	KillSession func(name string, deleteWorktree bool) error
+
+	// RequestSnapshot schedules a snapshot emission after a group change.
+	// Optional: defaults to a no-op if nil.
+	RequestSnapshot func()
+}
+
+// Service creates, extends and kills session groups.
+//
+// Thread-safety: mu serializes Create and Kill so that two calls cannot
+// interleave members of the same group.
+type Service struct {
+	deps Deps
+	mu   sync.Mutex
+}
+
+// NewService creates a session group service.
+// Panics if any required function field in deps is nil.
+func NewService(deps Deps) *Service {
+	if deps.RequireSessions == nil || deps.CreateMember == nil || deps.KillSession == nil {
+		panic("sessiongroup.NewService: required function fields in Deps must be non-nil " +
+			"(RequireSessions, CreateMember, KillSession)")
+	}
+	if deps.RequestSnapshot == nil {
+		deps.RequestSnapshot = func() {}
+	}
+	return &Service{deps: deps}
+}
+
+// normalizeGroupName trims group and rejects empty, multi-line or overlong
+// names.
+func normalizeGroupName(group string) (string, error) {
+	group = strings.TrimSpace(group)
+	if group == "" {
+		return "", errors.New("group name is required")
+	}
+	if strings.ContainsFunc(group, unicode.IsControl) || len(group) > maxGroupNameLen {
+		return "", fmt.Errorf("group name must be a single line of at most %d characters", maxGroupNameLen)
+	}
+	return group, nil
+}
+
+// Create creates the sessions of members in order and puts them in a new
+// group. When a member fails, the sessions already created are killed,
+// together with the worktrees created for them, and the returned error joins
+// the failure with every rollback error.
+func (s *Service) Create(group string, members []Member) ([]tmux.SessionSnapshot, error) {
+	group, err := normalizeGroupName(group)
+	if err != nil {
+		return nil, err
+	}
+	if len(members) == 0 {
+		return nil, errors.New("a session group needs at least one member")
+	}
+	sessions, err := s.deps.RequireSessions()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(sessions.SessionsInGroup(group)) > 0 {
+		return nil, fmt.Errorf("session group %s already exists", group)
+	}
+
+	created := make([]tmux.SessionSnapshot, 0, len(members))
+	withWorktree := make([]bool, 0, len(members))
+	for i, member := range members {
+		snapshot, createErr := s.deps.CreateMember(member)
+		if createErr == nil {
+			created = append(created, snapshot)
+			withWorktree = append(withWorktree, member.Worktree != nil)
+			createErr = sessions.SetSessionGroup(snapshot.Name, group)
+		}
+		if createErr != nil {
+			createErr = fmt.Errorf("member %d (%s): %w", i+1, member.RepoPath, createErr)
+			return nil, s.rollback(group, created, withWorktree, createErr)
+		}
+		snapshot.Group = group
+		created[len(created)-1] = snapshot
+	}
+	slog.Info("[SESSION-GROUP] group created", "group", group, "members", len(created))
+	s.deps.RequestSnapshot()
+	return created, nil
+}
+
+// rollback kills the sessions created for a failed Create, newest first, and
+// returns cause joined with the rollback errors.
+func (s *Service) rollback(group string, created []tmux.SessionSnapshot, withWorktree []bool, cause error) error {
+	errs := []error{cause}
+	for i := len(created) - 1; i >= 0; i-- {
+		name := created[i].Name
+		if err := s.deps.KillSession(name, withWorktree[i]); err != nil {
+			errs = append(errs, fmt.Errorf("rollback %s: %w", name, err))
+		}
+	}
+	slog.Warn("[SESSION-GROUP] group creation failed, rolled back",
+		"group", group, "rolledBack", len(created), "error", cause, "rollbackErrors", len(errs)-1)
+	s.deps.RequestSnapshot()
+	return errors.Join(errs...)
+}
+
+// Add moves an existing session into group, creating the group when it has
+// no members yet.
+func (s *Service) Add(sessionName, group string) error {
+	sessionName = strings.TrimSpace(sessionName)
+	if sessionName == "" {
+		return errors.New("session name is required")
+	}
+	group, err := normalizeGroupName(group)
+	if err != nil {
+		return err
+	}
+	sessions, err := s.deps.RequireSessions()
+	if err != nil {
+		return err
+	}
+	if err := sessions.SetSessionGroup(sessionName, group); err != nil {
+		return err
+	}
+	s.deps.RequestSnapshot()
+	return nil
+}
+
+// Kill kills every session of group, removing their worktrees when
+// deleteWorktrees is true. It continues past failures and returns them
+// joined.
+func (s *Service) Kill(group string, deleteWorktrees bool) error {
+	group, err := normalizeGroupName(group)
+	if err != nil {
+		return err
+	}
+	sessions, err := s.deps.RequireSessions()
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := sessions.SessionsInGroup(group)
+	if len(names) == 0 {
+		return fmt.Errorf("session group not found: %s", group)
+	}
+	var errs []error
+	for _, name := range names {
+		if err := s.deps.KillSession(name, deleteWorktrees); err != nil {
+			errs = append(errs, fmt.Errorf("kill %s: %w", name, err))
+		}
+	}
+	slog.Info("[SESSION-GROUP] group killed",
+		"group", group, "members", len(names), "failed", len(errs))
+	return errors.Join(errs...)
+}
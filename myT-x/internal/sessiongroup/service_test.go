@@ -0,0 +1,168 @@
+package sessiongroup
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"myT-x/internal/tmux"
+	"myT-x/internal/worktree"
+)
+
+type killCall struct {
+	name           string
+	deleteWorktree bool
+}
+
+type fakeSessions struct {
+	manager  *tmux.SessionManager
+	failRepo string
+	killErr  map[string]error
+	kills    []killCall
+}
+
+func newFakeSessions(t *testing.T) *fakeSessions {
+	t.Helper()
+	manager := tmux.NewSessionManager()
+	t.Cleanup(manager.Close)
+	return &fakeSessions{manager: manager, killErr: map[string]error{}}
+}
+
+func (f *fakeSessions) service() *Service {
+	return NewService(Deps{
+		RequireSessions: func() (*tmux.SessionManager, error) { return f.manager, nil },
+		CreateMember: func(member Member) (tmux.SessionSnapshot, error) {
+			if member.RepoPath == f.failRepo {
+				return tmux.SessionSnapshot{}, errors.New("repository not found")
+			}
+			session, _, err := f.manager.CreateSession(member.SessionName, "0", 120, 40)
+			if err != nil {
+				return tmux.SessionSnapshot{}, err
+			}
+			return tmux.SessionSnapshot{ID: session.ID, Name: session.Name}, nil
+		},
+		KillSession: func(name string, deleteWorktree bool) error {
+			f.kills = append(f.kills, killCall{name: name, deleteWorktree: deleteWorktree})
+			if err := f.killErr[name]; err != nil {
+				return err
+			}
+			_, err := f.manager.RemoveSession(name)
+			return err
+		},
+	})
+}
+
+func TestNewServicePanicsOnMissingDeps(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("NewService() with empty Deps did not panic")
+		}
+	}()
+	NewService(Deps{})
+}
+
+func TestCreateGroupsMembers(t *testing.T) {
+	f := newFakeSessions(t)
+	svc := f.service()
+
+	snapshots, err := svc.Create(" shop ", []Member{
+		{RepoPath: "/src/web", SessionName: "web", Worktree: &worktree.WorktreeSessionOptions{BranchName: "feat"}},
+		{RepoPath: "/src/api", SessionName: "api"},
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if len(snapshots) != 2 || snapshots[0].Group != "shop" || snapshots[1].Name != "api" {
+		t.Fatalf("Create() = %+v", snapshots)
+	}
+	if got := f.manager.SessionsInGroup("shop"); !slices.Equal(got, []string{"web", "api"}) {
+		t.Fatalf("SessionsInGroup() = %v", got)
+	}
+	if _, err := svc.Create("shop", []Member{{RepoPath: "/src/infra", SessionName: "infra"}}); err == nil {
+		t.Fatal("Create() of an existing group error = nil")
+	}
+}
+
+func TestCreateRejectsInvalidInput(t *testing.T) {
+	svc := newFakeSessions(t).service()
+	tests := []struct {
+		name    string
+		group   string
+		members []Member
+	}{
+		{"empty group", " ", []Member{{RepoPath: "/src/web"}}},
+		{"multi-line group", "a\nb", []Member{{RepoPath: "/src/web"}}},
+		{"long group", strings.Repeat("g", maxGroupNameLen+1), []Member{{RepoPath: "/src/web"}}},
+		{"no members", "shop", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.Create(tt.group, tt.members); err == nil {
+				t.Fatal("Create() error = nil")
+			}
+		})
+	}
+}
+
+func TestCreateRollsBackCreatedMembers(t *testing.T) {
+	f := newFakeSessions(t)
+	f.failRepo = "/src/infra"
+	f.killErr["api"] = errors.New("pane busy")
+	svc := f.service()
+
+	_, err := svc.Create("shop", []Member{
+		{RepoPath: "/src/web", SessionName: "web", Worktree: &worktree.WorktreeSessionOptions{BranchName: "feat"}},
+		{RepoPath: "/src/api", SessionName: "api"},
+		{RepoPath: "/src/infra", SessionName: "infra"},
+	})
+	if err == nil {
+		t.Fatal("Create() error = nil, want the member failure")
+	}
+	for _, want := range []string{"member 3 (/src/infra): repository not found", "rollback api: pane busy"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("Create() error = %q, want it to contain %q", err, want)
+		}
+	}
+	wantKills := []killCall{{name: "api"}, {name: "web", deleteWorktree: true}}
+	if !slices.Equal(f.kills, wantKills) {
+		t.Fatalf("kills = %+v, want %+v (newest first, worktrees only for worktree members)", f.kills, wantKills)
+	}
+	if f.manager.HasSession("web") {
+		t.Fatal("web still exists after rollback")
+	}
+}
+
+func TestAddAndKillGroup(t *testing.T) {
+	f := newFakeSessions(t)
+	svc := f.service()
+	for _, name := range []string{"web", "api", "notes"} {
+		if _, _, err := f.manager.CreateSession(name, "0", 120, 40); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, name := range []string{"web", "api"} {
+		if err := svc.Add(name, "shop"); err != nil {
+			t.Fatalf("Add(%s) error = %v", name, err)
+		}
+	}
+	if err := svc.Add("missing", "shop"); err == nil {
+		t.Fatal("Add(missing) error = nil")
+	}
+
+	f.killErr["web"] = errors.New("pane busy")
+	err := svc.Kill("shop", true)
+	if err == nil || !strings.Contains(err.Error(), "kill web: pane busy") {
+		t.Fatalf("Kill() error = %v, want the web failure", err)
+	}
+	wantKills := []killCall{{name: "web", deleteWorktree: true}, {name: "api", deleteWorktree: true}}
+	if !slices.Equal(f.kills, wantKills) {
+		t.Fatalf("kills = %+v, want every member killed despite the failure", f.kills)
+	}
+	if !f.manager.HasSession("notes") {
+		t.Fatal("ungrouped session was killed")
+	}
+	if err := svc.Kill("empty", false); err == nil {
+		t.Fatal("Kill() of an unknown group error = nil")
+	}
+}
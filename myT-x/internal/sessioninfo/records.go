@@ -0,0 +1,187 @@
+package sessioninfo
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// recordFileFormat identifies the checksummed record envelope on disk.
+const recordFileFormat = "mytx-records"
+
+// recordFileVersion is the current envelope contract version.
+const recordFileVersion = 1
+
+// ErrNewerRecordVersion is returned when records were written by a newer
+// build. Callers must leave such files untouched instead of quarantining
+// them, so a downgrade does not destroy data.
+var ErrNewerRecordVersion = errors.New("records written by a newer version")
+
+// RecoveredEvent is emitted when a session-info file contained corrupt
+// records that were dropped on load.
+const RecoveredEvent = "session-info:recovered"
+
+// recordFile is the on-disk envelope for session-info record stores.
+// Each record carries its own checksum so a single damaged entry can be
+// dropped without losing the rest of the file.
+type recordFile struct {
+	Format  string           `json:"format"`
+	Version int              `json:"version"`
+	Records []recordEnvelope `json:"records"`
+}
+
+type recordEnvelope struct {
+	// Version is set on JSON-lines records only; whole-file envelopes carry
+	// it in recordFile.
+	Version int             `json:"v,omitempty"`
+	SHA256  string          `json:"sha256"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// DroppedRecord describes a record rejected during verification.
+// Index is -1 when the whole file could not be parsed.
+type DroppedRecord struct {
+	Index  int    `json:"index"`
+	Reason string `json:"reason"`
+}
+
+// Recovery reports what was dropped from a session-info file on load.
+type Recovery struct {
+	SessionName     string          `json:"session_name"`
+	File            string          `json:"file"`
+	QuarantinedPath string          `json:"quarantined_path"`
+	Dropped         []DroppedRecord `json:"dropped"`
+}
+
+// EncodeRecords marshals records into the checksummed envelope.
+func EncodeRecords[T any](records []T) ([]byte, error) {
+	file := recordFile{
+		Format:  recordFileFormat,
+		Version: recordFileVersion,
+		Records: make([]recordEnvelope, 0, len(records)),
+	}
+	for i, record := range records {
+		raw, err := json.Marshal(record)
+		if err != nil {
+			return nil, fmt.Errorf("marshal record %d: %w", i, err)
+		}
+		file.Records = append(file.Records, recordEnvelope{SHA256: recordChecksum(raw), Data: raw})
+	}
+	return json.MarshalIndent(file, "", "  ")
+}
+
+// DecodeRecords verifies and decodes data written by EncodeRecords.
+//
+// Records whose checksum does not match, or that fail to decode into T, are
+// returned in dropped instead of failing the whole load. Files written before
+// checksums were introduced (a bare JSON array of T) are accepted as-is.
+// err is non-nil only when data is neither an envelope nor a legacy array,
+// and wraps ErrNewerRecordVersion when a newer build wrote the file.
+func DecodeRecords[T any](data []byte) (records []T, dropped []DroppedRecord, err error) {
+	var file recordFile
+	if envErr := json.Unmarshal(data, &file); envErr == nil && file.Format == recordFileFormat {
+		if file.Version > recordFileVersion {
+			return nil, nil, fmt.Errorf("%w: file version %d", ErrNewerRecordVersion, file.Version)
+		}
+		if file.Version != recordFileVersion {
+			return nil, nil, fmt.Errorf("unsupported record file version %d", file.Version)
+		}
+		records = make([]T, 0, len(file.Records))
+		for i, envelope := range file.Records {
+			record, reason := decodeEnvelope[T](envelope)
+			if reason != "" {
+				dropped = append(dropped, DroppedRecord{Index: i, Reason: reason})
+				continue
+			}
+			records = append(records, record)
+		}
+		return records, dropped, nil
+	}
+
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, nil, fmt.Errorf("parse record file: %w", err)
+	}
+	if records == nil {
+		records = []T{}
+	}
+	return records, nil, nil
+}
+
+// EncodeRecordLine marshals record as one checksummed JSON line, without a
+// trailing newline, for append-only JSON-lines stores.
+func EncodeRecordLine[T any](record T) ([]byte, error) {
+	raw, err := json.Marshal(record)
+	if err != nil {
+		return nil, fmt.Errorf("marshal record: %w", err)
+	}
+	return json.Marshal(recordEnvelope{Version: recordFileVersion, SHA256: recordChecksum(raw), Data: raw})
+}
+
+// DecodeRecordLine verifies and decodes a line written by EncodeRecordLine.
+// Lines written before checksums were introduced (a bare JSON T) are
+// accepted as-is. The error wraps ErrNewerRecordVersion for lines from a
+// newer build; otherwise its text is the reason to report in DroppedRecord.
+func DecodeRecordLine[T any](line []byte) (T, error) {
+	var zero T
+	var envelope recordEnvelope
+	if err := json.Unmarshal(line, &envelope); err != nil {
+		return zero, errors.New("invalid record JSON")
+	}
+	if envelope.Version > recordFileVersion {
+		return zero, fmt.Errorf("%w: line version %d", ErrNewerRecordVersion, envelope.Version)
+	}
+	if envelope.SHA256 == "" && envelope.Data == nil {
+		var record T
+		if err := json.Unmarshal(line, &record); err != nil {
+			return zero, fmt.Errorf("decode record: %v", err)
+		}
+		return record, nil
+	}
+	record, reason := decodeEnvelope[T](envelope)
+	if reason != "" {
+		return zero, errors.New(reason)
+	}
+	return record, nil
+}
+
+// decodeEnvelope verifies envelope and decodes its data. reason is non-empty
+// when the record must be dropped.
+func decodeEnvelope[T any](envelope recordEnvelope) (record T, reason string) {
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, envelope.Data); err != nil {
+		return record, "invalid record JSON"
+	}
+	if recordChecksum(compact.Bytes()) != envelope.SHA256 {
+		return record, "checksum mismatch"
+	}
+	if err := json.Unmarshal(compact.Bytes(), &record); err != nil {
+		return record, fmt.Sprintf("decode record: %v", err)
+	}
+	return record, ""
+}
+
+// QuarantineFile renames path to <path>.corrupt-<timestamp> so the damaged
+// content is kept for diagnostics and the original name can be rewritten.
+func QuarantineFile(path string, now time.Time) (string, error) {
+	quarantined := fmt.Sprintf("%s.corrupt-%s", path, now.Format("20060102-150405"))
+	if _, err := os.Stat(quarantined); err == nil {
+		// Same-second collision: keep both copies.
+		quarantined = fmt.Sprintf("%s-%d", quarantined, now.UnixNano())
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+	if err := os.Rename(path, quarantined); err != nil {
+		return "", fmt.Errorf("quarantine %s: %w", path, err)
+	}
+	return quarantined, nil
+}
+
+func recordChecksum(compactJSON []byte) string {
+	sum := sha256.Sum256(compactJSON)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,161 @@
+package sessioninfo
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testRecord struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+func TestEncodeDecodeRecordsRoundTrip(t *testing.T) {
+	in := []testRecord{{Name: "a", Count: 1}, {Name: "b", Count: 2}}
+	data, err := EncodeRecords(in)
+	if err != nil {
+		t.Fatalf("EncodeRecords() error = %v", err)
+	}
+	out, dropped, err := DecodeRecords[testRecord](data)
+	if err != nil {
+		t.Fatalf("DecodeRecords() error = %v", err)
+	}
+	if len(dropped) != 0 {
+		t.Fatalf("dropped = %+v, want none", dropped)
+	}
+	if len(out) != 2 || out[0] != in[0] || out[1] != in[1] {
+		t.Fatalf("DecodeRecords() = %+v, want %+v", out, in)
+	}
+}
+
+func TestDecodeRecordsDropsCorruptEntries(t *testing.T) {
+	data, err := EncodeRecords([]testRecord{{Name: "keep", Count: 1}, {Name: "tamper", Count: 2}, {Name: "shape", Count: 3}})
+	if err != nil {
+		t.Fatalf("EncodeRecords() error = %v", err)
+	}
+	text := string(data)
+	text = strings.Replace(text, `"name": "tamper"`, `"name": "tampered"`, 1)
+	// Valid JSON with a recomputed checksum would still fail to decode into
+	// testRecord if the shape is wrong; simulate a mismatch on the third entry too.
+	text = strings.Replace(text, `"count": 3`, `"count": "three"`, 1)
+
+	out, dropped, err := DecodeRecords[testRecord]([]byte(text))
+	if err != nil {
+		t.Fatalf("DecodeRecords() error = %v", err)
+	}
+	if len(out) != 1 || out[0].Name != "keep" {
+		t.Fatalf("DecodeRecords() = %+v, want only keep", out)
+	}
+	if len(dropped) != 2 || dropped[0].Index != 1 || dropped[1].Index != 2 {
+		t.Fatalf("dropped = %+v, want indexes 1 and 2", dropped)
+	}
+	if dropped[0].Reason != "checksum mismatch" {
+		t.Fatalf("dropped[0].Reason = %q, want checksum mismatch", dropped[0].Reason)
+	}
+}
+
+func TestDecodeRecordsLegacyArray(t *testing.T) {
+	out, dropped, err := DecodeRecords[testRecord]([]byte(`[{"name":"old","count":7}]`))
+	if err != nil {
+		t.Fatalf("DecodeRecords() error = %v", err)
+	}
+	if len(dropped) != 0 || len(out) != 1 || out[0].Name != "old" {
+		t.Fatalf("DecodeRecords() = %+v dropped=%+v, want legacy record", out, dropped)
+	}
+}
+
+func TestDecodeRecordsRejectsUnparseable(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"garbage", "not json"},
+		{"unknown version", `{"format":"mytx-records","version":0,"records":[]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := DecodeRecords[testRecord]([]byte(tt.data))
+			if err == nil {
+				t.Fatal("DecodeRecords() error = nil, want error")
+			}
+			if errors.Is(err, ErrNewerRecordVersion) {
+				t.Fatalf("DecodeRecords() error = %v, want a corruption error", err)
+			}
+		})
+	}
+}
+
+func TestDecodeRecordsNewerVersion(t *testing.T) {
+	_, _, err := DecodeRecords[testRecord]([]byte(`{"format":"mytx-records","version":99,"records":[]}`))
+	if !errors.Is(err, ErrNewerRecordVersion) {
+		t.Fatalf("DecodeRecords() error = %v, want ErrNewerRecordVersion", err)
+	}
+}
+
+func TestEncodeDecodeRecordLine(t *testing.T) {
+	line, err := EncodeRecordLine(testRecord{Name: "a", Count: 1})
+	if err != nil {
+		t.Fatalf("EncodeRecordLine() error = %v", err)
+	}
+	if strings.Contains(string(line), "\n") {
+		t.Fatalf("EncodeRecordLine() = %q, want a single line", line)
+	}
+	got, err := DecodeRecordLine[testRecord](line)
+	if err != nil || got != (testRecord{Name: "a", Count: 1}) {
+		t.Fatalf("DecodeRecordLine() = %+v, %v", got, err)
+	}
+
+	tampered := strings.Replace(string(line), `"name":"a"`, `"name":"b"`, 1)
+	if _, err := DecodeRecordLine[testRecord]([]byte(tampered)); err == nil || err.Error() != "checksum mismatch" {
+		t.Fatalf("DecodeRecordLine(tampered) error = %v, want checksum mismatch", err)
+	}
+	if _, err := DecodeRecordLine[testRecord]([]byte(`{"sha256":"x","da`)); err == nil {
+		t.Fatal("DecodeRecordLine(torn) error = nil, want error")
+	}
+	legacy, err := DecodeRecordLine[testRecord]([]byte(`{"name":"old","count":7}`))
+	if err != nil || legacy.Name != "old" {
+		t.Fatalf("DecodeRecordLine(legacy) = %+v, %v", legacy, err)
+	}
+	if _, err := DecodeRecordLine[testRecord]([]byte(`{"v":2,"sha256":"x","data":{}}`)); !errors.Is(err, ErrNewerRecordVersion) {
+		t.Fatalf("DecodeRecordLine(newer) error = %v, want ErrNewerRecordVersion", err)
+	}
+}
+
+func TestQuarantineFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "records.json")
+	if err := os.WriteFile(path, []byte("broken"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	first, err := QuarantineFile(path, now)
+	if err != nil {
+		t.Fatalf("QuarantineFile() error = %v", err)
+	}
+	if want := path + ".corrupt-20260102-030405"; first != want {
+		t.Fatalf("QuarantineFile() = %q, want %q", first, want)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("original file still present: %v", err)
+	}
+
+	// A second quarantine in the same second must not overwrite the first.
+	if err := os.WriteFile(path, []byte("broken again"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	second, err := QuarantineFile(path, now)
+	if err != nil {
+		t.Fatalf("second QuarantineFile() error = %v", err)
+	}
+	if second == first {
+		t.Fatalf("second quarantine reused path %q", first)
+	}
+	data, err := os.ReadFile(first)
+	if err != nil || string(data) != "broken" {
+		t.Fatalf("first quarantined content = %q, %v; want original", data, err)
+	}
+}
@@ -1,19 +1,22 @@
 package sessionmemo
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"os"
-	"path/filepath"
 	"strings"
 	"sync"
 
 	"myT-x/internal/sessioninfo"
+	"myT-x/internal/statestore"
 )
 
 const (
+	// memoFileName is the legacy memo file. Memos found there are imported
+	// into the state store on first load.
 	memoFileName = "session-memo.md"
 	// 1 MiB keeps sidebar memo reads bounded while staying far above normal notes.
 	maxMemoBytes = 1 << 20
@@ -23,71 +26,79 @@ const (
 type Deps struct {
 	ResolveSessionWorkDir func(sessionName string) (string, error)
 	ConfigDir             func() (string, error)
+	// Store returns the state store memos are persisted in.
+	Store func() (statestore.Store, error)
 }
 
-// Service loads, caches, and persists memo text for each terminal session.
+// Service loads, caches, and persists memo text for each terminal session in
+// the state store, keyed by working directory.
 type Service struct {
 	deps Deps
-	// fileIOMu serializes Windows file replacement for the memo file. It is
-	// intentionally separate from mu so cache state is not locked during I/O.
-	fileIOMu              sync.Mutex
-	mu                    sync.Mutex
-	memoByPath            map[string]string
-	memoPathBySessionName map[string]string
-	memoVersionByPath     map[string]uint64
-	nextMemoVersion       uint64
+	// migrateMu serializes legacy file imports so a memo is imported once.
+	migrateMu        sync.Mutex
+	mu               sync.Mutex
+	memoByKey        map[string]string
+	memoKeyBySession map[string]string
+	memoVersionByKey map[string]uint64
+	nextMemoVersion  uint64
 }
 
 // NewService creates a session memo service.
 func NewService(deps Deps) *Service {
-	if deps.ResolveSessionWorkDir == nil || deps.ConfigDir == nil {
-		panic("sessionmemo.NewService: required function fields in Deps must be non-nil (ResolveSessionWorkDir, ConfigDir)")
+	if deps.ResolveSessionWorkDir == nil || deps.ConfigDir == nil || deps.Store == nil {
+		panic("sessionmemo.NewService: required function fields in Deps must be non-nil (ResolveSessionWorkDir, ConfigDir, Store)")
 	}
 	return &Service{
-		deps:                  deps,
-		memoByPath:            make(map[string]string),
-		memoPathBySessionName: make(map[string]string),
-		memoVersionByPath:     make(map[string]uint64),
+		deps:             deps,
+		memoByKey:        make(map[string]string),
+		memoKeyBySession: make(map[string]string),
+		memoVersionByKey: make(map[string]uint64),
 	}
 }
 
-// Load returns the current session memo from app-config session storage.
+// Load returns the current session memo from the state store.
 func (s *Service) Load(sessionName string) (string, error) {
-	normalizedSessionName, path, legacyPath, err := s.resolveMemoPaths(sessionName)
+	normalizedSessionName, store, workDir, key, err := s.resolve(sessionName)
 	if err != nil {
 		return "", err
 	}
+	ctx := context.Background()
 
 	s.mu.Lock()
-	startVersion := s.memoVersionByPath[path]
+	startVersion := s.memoVersionByKey[key]
 	s.mu.Unlock()
 
-	s.fileIOMu.Lock()
-	if err := migrateLegacyMemoIfNeeded(path, legacyPath); err != nil {
-		s.fileIOMu.Unlock()
-		return "", fmt.Errorf("migrate legacy session memo: %w", err)
-	}
-	memo, err := readMemo(path)
-	s.fileIOMu.Unlock()
-	if err != nil {
+	data, err := store.Get(ctx, statestore.BucketSessionMemos, key)
+	if errors.Is(err, statestore.ErrNotFound) {
+		data, err = s.importLegacyMemo(ctx, store, workDir, key)
+	}
+	memo := ""
+	switch {
+	case errors.Is(err, statestore.ErrNotFound):
+	case err != nil:
 		return "", fmt.Errorf("read session memo: %w", err)
+	case len(data) > maxMemoBytes:
+		return "", fmt.Errorf("session memo is too large: %d bytes", len(data))
+	default:
+		memo = string(data)
 	}
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	s.rememberSessionPathLocked(normalizedSessionName, path)
-	if s.memoVersionByPath[path] != startVersion {
-		if cachedMemo, ok := s.memoByPath[path]; ok {
+	s.rememberSessionKeyLocked(normalizedSessionName, key)
+	if s.memoVersionByKey[key] != startVersion {
+		if cachedMemo, ok := s.memoByKey[key]; ok {
 			return cachedMemo, nil
 		}
 	}
-	s.memoByPath[path] = memo
+	s.memoByKey[key] = memo
 	return memo, nil
 }
 
-// Save writes the session memo to app-config session storage and updates the in-memory cache.
+// Save writes the session memo to the state store and updates the in-memory
+// cache. An empty memo removes the record.
 func (s *Service) Save(sessionName string, text string) error {
-	normalizedSessionName, path, _, err := s.resolveMemoPaths(sessionName)
+	normalizedSessionName, store, _, key, err := s.resolve(sessionName)
 	if err != nil {
 		return err
 	}
@@ -95,19 +106,22 @@ func (s *Service) Save(sessionName string, text string) error {
 		return fmt.Errorf("session memo must be %d bytes or fewer", maxMemoBytes)
 	}
 
-	s.fileIOMu.Lock()
-	err = writeMemo(path, text)
-	s.fileIOMu.Unlock()
+	ctx := context.Background()
+	if text == "" {
+		err = store.Delete(ctx, statestore.BucketSessionMemos, key)
+	} else {
+		err = store.Put(ctx, statestore.BucketSessionMemos, key, []byte(text))
+	}
 	if err != nil {
 		return fmt.Errorf("write session memo: %w", err)
 	}
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	s.rememberSessionPathLocked(normalizedSessionName, path)
-	s.memoByPath[path] = text
+	s.rememberSessionKeyLocked(normalizedSessionName, key)
+	s.memoByKey[key] = text
 	s.nextMemoVersion++
-	s.memoVersionByPath[path] = s.nextMemoVersion
+	s.memoVersionByKey[key] = s.nextMemoVersion
 	return nil
 }
 
@@ -119,17 +133,20 @@ func (s *Service) CleanupSession(sessionName string) error {
 	}
 
 	s.mu.Lock()
-	path := s.memoPathBySessionName[normalizedSessionName]
-	if path != "" {
-		s.forgetSessionPathLocked(normalizedSessionName, path)
+	key := s.memoKeyBySession[normalizedSessionName]
+	if key != "" {
+		s.forgetSessionKeyLocked(normalizedSessionName, key)
 		s.mu.Unlock()
 		return nil
 	}
 	s.mu.Unlock()
 
-	_, resolvedPath, _, err := s.resolveMemoPaths(normalizedSessionName)
+	workDir, err := s.deps.ResolveSessionWorkDir(normalizedSessionName)
+	if err == nil {
+		key, err = sessioninfo.FolderKey(workDir)
+	}
 	if err != nil {
-		slog.Debug("[DEBUG-SESSION-MEMO] cleanup skipped because memo path could not be resolved",
+		slog.Debug("[DEBUG-SESSION-MEMO] cleanup skipped because memo key could not be resolved",
 			"session", normalizedSessionName,
 			"error", err,
 		)
@@ -137,7 +154,7 @@ func (s *Service) CleanupSession(sessionName string) error {
 	}
 
 	s.mu.Lock()
-	s.forgetSessionPathLocked(normalizedSessionName, resolvedPath)
+	s.forgetSessionKeyLocked(normalizedSessionName, key)
 	s.mu.Unlock()
 	return nil
 }
@@ -152,63 +169,106 @@ func (s *Service) RenameSession(oldName, newName string) error {
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	if path := s.memoPathBySessionName[oldName]; path != "" {
-		delete(s.memoPathBySessionName, oldName)
-		s.memoPathBySessionName[newName] = path
+	if key := s.memoKeyBySession[oldName]; key != "" {
+		delete(s.memoKeyBySession, oldName)
+		s.memoKeyBySession[newName] = key
 	}
 	return nil
 }
 
-func (s *Service) resolveMemoPaths(sessionName string) (string, string, string, error) {
-	normalizedSessionName := strings.TrimSpace(sessionName)
-	if normalizedSessionName == "" {
-		return "", "", "", errors.New("session name is required for session memo")
+// importLegacyMemo moves a memo from the legacy session-info file into the
+// store, falling back to the older project-local file, which is copied and
+// left in place because it lives in the user's repository. It returns
+// statestore.ErrNotFound when there is nothing to import.
+func (s *Service) importLegacyMemo(ctx context.Context, store statestore.Store, workDir, key string) ([]byte, error) {
+	configDir, err := s.deps.ConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	legacyPath, err := sessioninfo.FilePath(configDir, workDir, memoFileName)
+	if err != nil {
+		return nil, err
+	}
+	projectPath, err := sessioninfo.LegacyProjectFilePath(workDir, memoFileName)
+	if err != nil {
+		return nil, err
 	}
 
-	workDir, err := s.deps.ResolveSessionWorkDir(normalizedSessionName)
+	s.migrateMu.Lock()
+	defer s.migrateMu.Unlock()
+	// A concurrent Load may have imported the memo while this one waited.
+	if data, err := store.Get(ctx, statestore.BucketSessionMemos, key); !errors.Is(err, statestore.ErrNotFound) {
+		return data, err
+	}
+	imported, err := statestore.ImportLegacyFile(ctx, store, statestore.BucketSessionMemos, key, legacyPath)
 	if err != nil {
-		return "", "", "", err
+		return nil, err
 	}
-	configDir, err := s.deps.ConfigDir()
+	if imported {
+		return store.Get(ctx, statestore.BucketSessionMemos, key)
+	}
+	memo, found, err := readMemo(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, statestore.ErrNotFound
+	}
+	if err := store.Put(ctx, statestore.BucketSessionMemos, key, []byte(memo)); err != nil {
+		return nil, err
+	}
+	slog.Info("[DEBUG-SESSION-MEMO] imported legacy project memo into the state store",
+		"legacy_path", projectPath,
+	)
+	return []byte(memo), nil
+}
+
+func (s *Service) resolve(sessionName string) (string, statestore.Store, string, string, error) {
+	normalizedSessionName := strings.TrimSpace(sessionName)
+	if normalizedSessionName == "" {
+		return "", nil, "", "", errors.New("session name is required for session memo")
+	}
+	workDir, err := s.deps.ResolveSessionWorkDir(normalizedSessionName)
 	if err != nil {
-		return "", "", "", err
+		return "", nil, "", "", err
 	}
-	path, err := sessioninfo.FilePath(configDir, workDir, memoFileName)
+	key, err := sessioninfo.FolderKey(workDir)
 	if err != nil {
-		return "", "", "", err
+		return "", nil, "", "", err
 	}
-	legacyPath, err := sessioninfo.LegacyProjectFilePath(workDir, memoFileName)
+	store, err := s.deps.Store()
 	if err != nil {
-		return "", "", "", err
+		return "", nil, "", "", err
 	}
-	return normalizedSessionName, path, legacyPath, nil
+	return normalizedSessionName, store, workDir, key, nil
 }
 
-func (s *Service) rememberSessionPathLocked(sessionName, path string) {
-	s.memoPathBySessionName[sessionName] = path
+func (s *Service) rememberSessionKeyLocked(sessionName, key string) {
+	s.memoKeyBySession[sessionName] = key
 }
 
-func (s *Service) forgetSessionPathLocked(sessionName, path string) {
-	delete(s.memoPathBySessionName, sessionName)
-	delete(s.memoByPath, path)
-	delete(s.memoVersionByPath, path)
+func (s *Service) forgetSessionKeyLocked(sessionName, key string) {
+	delete(s.memoKeyBySession, sessionName)
+	delete(s.memoByKey, key)
+	delete(s.memoVersionByKey, key)
 }
 
-func readMemo(path string) (string, error) {
+// readMemo reads a legacy memo file. found is false when it does not exist.
+func readMemo(path string) (memo string, found bool, err error) {
 	info, err := os.Stat(path)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return "", nil
+			return "", false, nil
 		}
-		return "", err
+		return "", false, err
 	}
 	if info.Size() > maxMemoBytes {
-		return "", fmt.Errorf("session memo file is too large: %d bytes", info.Size())
+		return "", false, fmt.Errorf("session memo file is too large: %d bytes", info.Size())
 	}
 
 	file, err := os.Open(path)
 	if err != nil {
-		return "", err
+		return "", false, err
 	}
 	defer func() {
 		if closeErr := file.Close(); closeErr != nil {
@@ -218,83 +278,10 @@ func readMemo(path string) (string, error) {
 
 	data, err := io.ReadAll(io.LimitReader(file, maxMemoBytes+1))
 	if err != nil {
-		return "", err
+		return "", false, err
 	}
 	if len(data) > maxMemoBytes {
-		return "", fmt.Errorf("session memo file is too large: %d bytes", len(data))
-	}
-	return string(data), nil
-}
-
-func migrateLegacyMemoIfNeeded(currentPath, legacyPath string) error {
-	if _, err := os.Stat(currentPath); err == nil {
-		return nil
-	} else if !os.IsNotExist(err) {
-		return err
-	}
-	if _, err := os.Stat(legacyPath); err != nil {
-		if os.IsNotExist(err) {
-			return nil
-		}
-		return err
-	}
-	memo, err := readMemo(legacyPath)
-	if err != nil {
-		return err
-	}
-	if err := writeMemo(currentPath, memo); err != nil {
-		return err
-	}
-	slog.Info("[DEBUG-SESSION-MEMO] migrated legacy project memo to session-info",
-		"legacy_path", legacyPath,
-		"current_path", currentPath,
-	)
-	return nil
-}
-
-func writeMemo(path string, memo string) error {
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0o755); err != nil {
-		return fmt.Errorf("create session memo directory %s: %w", dir, err)
-	}
-
-	tmpFile, err := os.CreateTemp(dir, ".session-memo.*.tmp")
-	if err != nil {
-		return fmt.Errorf("create temp file for session memo: %w", err)
-	}
-	tmpPath := tmpFile.Name()
-	defer func() {
-		if tmpFile != nil {
-			// Successful close transfers ownership to the filesystem; avoid a second
-			// close while still closing failed-write handles.
-			if closeErr := tmpFile.Close(); closeErr != nil {
-				slog.Debug("[DEBUG-SESSION-MEMO] failed to close temp file", "path", tmpPath, "error", closeErr)
-			}
-		}
-		// Rename removes tmpPath on success. If it still exists, the write failed
-		// before replacement and the leftover temp file can be safely removed.
-		if _, statErr := os.Stat(tmpPath); statErr == nil {
-			if removeErr := os.Remove(tmpPath); removeErr != nil {
-				slog.Debug("[DEBUG-SESSION-MEMO] failed to remove temp file", "path", tmpPath, "error", removeErr)
-			}
-		} else if !os.IsNotExist(statErr) {
-			slog.Debug("[DEBUG-SESSION-MEMO] failed to stat temp file", "path", tmpPath, "error", statErr)
-		}
-	}()
-
-	if _, err := tmpFile.WriteString(memo); err != nil {
-		return fmt.Errorf("write session memo temp file: %w", err)
-	}
-	if err := tmpFile.Sync(); err != nil {
-		return fmt.Errorf("sync session memo temp file: %w", err)
+		return "", false, fmt.Errorf("session memo file is too large: %d bytes", len(data))
 	}
-	if err := tmpFile.Close(); err != nil {
-		return fmt.Errorf("close session memo temp file: %w", err)
-	}
-	tmpFile = nil
-
-	if err := replaceMemoFile(tmpPath, path); err != nil {
-		return fmt.Errorf("replace session memo file: %w", err)
-	}
-	return nil
+	return string(data), true, nil
 }
@@ -1,6 +1,8 @@
 package sessionmemo
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -9,20 +11,22 @@ import (
 	"testing"
 
 	"myT-x/internal/sessioninfo"
+	"myT-x/internal/statestore"
 )
 
-func newTestService(t *testing.T) (*Service, string, string) {
+func newTestService(t *testing.T) (*Service, string, string, statestore.Store) {
 	t.Helper()
 	rootPath := filepath.Join(t.TempDir(), "workspace")
-	service, configDir := newTestServiceWithRootFunc(t, func(string) string {
+	service, configDir, store := newTestServiceWithRootFunc(t, func(string) string {
 		return rootPath
 	})
-	return service, rootPath, configDir
+	return service, rootPath, configDir, store
 }
 
-func newTestServiceWithRootFunc(t *testing.T, rootForSession func(string) string) (*Service, string) {
+func newTestServiceWithRootFunc(t *testing.T, rootForSession func(string) string) (*Service, string, statestore.Store) {
 	t.Helper()
 	configDir := filepath.Join(t.TempDir(), "config")
+	store := statestore.NewMemoryStore()
 	return NewService(Deps{
 		ResolveSessionWorkDir: func(sessionName string) (string, error) {
 			return rootForSession(sessionName), nil
@@ -30,10 +34,51 @@ func newTestServiceWithRootFunc(t *testing.T, rootForSession func(string) string
 		ConfigDir: func() (string, error) {
 			return configDir, nil
 		},
-	}), configDir
+		Store: func() (statestore.Store, error) {
+			return store, nil
+		},
+	}), configDir, store
+}
+
+func memoKeyForTest(t *testing.T, workDir string) string {
+	t.Helper()
+	key, err := sessioninfo.FolderKey(workDir)
+	if err != nil {
+		t.Fatalf("session memo key: %v", err)
+	}
+	return key
+}
+
+func storedMemo(t *testing.T, store statestore.Store, workDir string) (string, bool) {
+	t.Helper()
+	data, err := store.Get(context.Background(), statestore.BucketSessionMemos, memoKeyForTest(t, workDir))
+	if errors.Is(err, statestore.ErrNotFound) {
+		return "", false
+	}
+	if err != nil {
+		t.Fatalf("store.Get() error = %v", err)
+	}
+	return string(data), true
+}
+
+func putMemo(t *testing.T, store statestore.Store, workDir, memo string) {
+	t.Helper()
+	if err := store.Put(context.Background(), statestore.BucketSessionMemos, memoKeyForTest(t, workDir), []byte(memo)); err != nil {
+		t.Fatalf("store.Put() error = %v", err)
+	}
+}
+
+func writeLegacyMemo(t *testing.T, path, memo string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	if err := os.WriteFile(path, []byte(memo), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
 }
 
-func memoPathForTest(t *testing.T, configDir, workDir string) string {
+func legacyMemoPathForTest(t *testing.T, configDir, workDir string) string {
 	t.Helper()
 	path, err := sessioninfo.FilePath(configDir, workDir, memoFileName)
 	if err != nil {
@@ -51,8 +96,8 @@ func TestNewServicePanicsOnMissingDeps(t *testing.T) {
 	NewService(Deps{})
 }
 
-func TestLoadReturnsEmptyWhenMemoFileMissing(t *testing.T) {
-	service, _, _ := newTestService(t)
+func TestLoadReturnsEmptyWhenMemoMissing(t *testing.T) {
+	service, _, _, _ := newTestService(t)
 
 	memo, err := service.Load("alpha")
 	if err != nil {
@@ -64,20 +109,14 @@ func TestLoadReturnsEmptyWhenMemoFileMissing(t *testing.T) {
 }
 
 func TestSaveAndLoadMemo(t *testing.T) {
-	service, rootPath, configDir := newTestService(t)
+	service, rootPath, _, store := newTestService(t)
 	want := "\nKeep leading whitespace.\nAnd trailing whitespace.\n"
 
 	if err := service.Save("alpha", want); err != nil {
 		t.Fatalf("Save() error = %v", err)
 	}
-
-	memoPath := memoPathForTest(t, configDir, rootPath)
-	data, err := os.ReadFile(memoPath)
-	if err != nil {
-		t.Fatalf("ReadFile(%s) error = %v", memoPath, err)
-	}
-	if string(data) != want {
-		t.Fatalf("saved memo = %q, want %q", string(data), want)
+	if got, _ := storedMemo(t, store, rootPath); got != want {
+		t.Fatalf("stored memo = %q, want %q", got, want)
 	}
 
 	got, err := service.Load("alpha")
@@ -89,8 +128,8 @@ func TestSaveAndLoadMemo(t *testing.T) {
 	}
 }
 
-func TestSaveReplacesExistingMemoFile(t *testing.T) {
-	service, rootPath, configDir := newTestService(t)
+func TestSaveReplacesExistingMemo(t *testing.T) {
+	service, rootPath, _, store := newTestService(t)
 	if err := service.Save("alpha", "first memo"); err != nil {
 		t.Fatalf("Save() first error = %v", err)
 	}
@@ -98,41 +137,47 @@ func TestSaveReplacesExistingMemoFile(t *testing.T) {
 		t.Fatalf("Save() second error = %v", err)
 	}
 
-	memoPath := memoPathForTest(t, configDir, rootPath)
-	data, err := os.ReadFile(memoPath)
-	if err != nil {
-		t.Fatalf("ReadFile(%s) error = %v", memoPath, err)
+	if got, _ := storedMemo(t, store, rootPath); got != "second memo" {
+		t.Fatalf("stored memo = %q, want replacement content", got)
+	}
+}
+
+func TestSaveEmptyMemoRemovesRecord(t *testing.T) {
+	service, rootPath, _, store := newTestService(t)
+	if err := service.Save("alpha", "memo"); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if err := service.Save("alpha", ""); err != nil {
+		t.Fatalf("Save(empty) error = %v", err)
 	}
-	if string(data) != "second memo" {
-		t.Fatalf("saved memo = %q, want replacement content", string(data))
+
+	if _, found := storedMemo(t, store, rootPath); found {
+		t.Fatal("empty memo should remove the stored record")
 	}
 }
 
-func TestLoadReadsMemoFileAfterExternalChange(t *testing.T) {
-	service, rootPath, configDir := newTestService(t)
+func TestLoadReadsStoreAfterExternalChange(t *testing.T) {
+	service, rootPath, _, store := newTestService(t)
 	if err := service.Save("alpha", "in memory"); err != nil {
 		t.Fatalf("Save() error = %v", err)
 	}
 
-	memoPath := memoPathForTest(t, configDir, rootPath)
-	if err := os.WriteFile(memoPath, []byte("changed on disk"), 0o644); err != nil {
-		t.Fatalf("WriteFile() error = %v", err)
-	}
+	putMemo(t, store, rootPath, "changed in store")
 
 	got, err := service.Load("alpha")
 	if err != nil {
 		t.Fatalf("Load() error = %v", err)
 	}
-	if got != "changed on disk" {
-		t.Fatalf("Load() = %q, want memo reloaded from disk", got)
+	if got != "changed in store" {
+		t.Fatalf("Load() = %q, want memo reloaded from the store", got)
 	}
 }
 
-func TestLoadCacheIsScopedByResolvedMemoPath(t *testing.T) {
+func TestLoadCacheIsScopedByResolvedWorkDir(t *testing.T) {
 	rootBySession := map[string]string{
 		"alpha": filepath.Join(t.TempDir(), "workspace-one"),
 	}
-	service, configDir := newTestServiceWithRootFunc(t, func(sessionName string) string {
+	service, _, store := newTestServiceWithRootFunc(t, func(sessionName string) string {
 		return rootBySession[sessionName]
 	})
 	if err := service.Save("alpha", "old workspace memo"); err != nil {
@@ -140,25 +185,19 @@ func TestLoadCacheIsScopedByResolvedMemoPath(t *testing.T) {
 	}
 
 	rootBySession["alpha"] = filepath.Join(t.TempDir(), "workspace-two")
-	memoPath := memoPathForTest(t, configDir, rootBySession["alpha"])
-	if err := os.MkdirAll(filepath.Dir(memoPath), 0o755); err != nil {
-		t.Fatalf("MkdirAll() error = %v", err)
-	}
-	if err := os.WriteFile(memoPath, []byte("new workspace memo"), 0o644); err != nil {
-		t.Fatalf("WriteFile() error = %v", err)
-	}
+	putMemo(t, store, rootBySession["alpha"], "new workspace memo")
 
 	got, err := service.Load("alpha")
 	if err != nil {
 		t.Fatalf("Load() error = %v", err)
 	}
 	if got != "new workspace memo" {
-		t.Fatalf("Load() = %q, want memo from the new resolved path", got)
+		t.Fatalf("Load() = %q, want memo of the new working directory", got)
 	}
 }
 
 func TestSaveRejectsEmptySessionName(t *testing.T) {
-	service, _, _ := newTestService(t)
+	service, _, _, _ := newTestService(t)
 
 	err := service.Save("  ", "memo")
 	if err == nil {
@@ -170,7 +209,7 @@ func TestSaveRejectsEmptySessionName(t *testing.T) {
 }
 
 func TestSaveRejectsOversizedMemo(t *testing.T) {
-	service, _, _ := newTestService(t)
+	service, _, _, _ := newTestService(t)
 
 	err := service.Save("alpha", strings.Repeat("x", maxMemoBytes+1))
 	if err == nil {
@@ -182,7 +221,7 @@ func TestSaveRejectsOversizedMemo(t *testing.T) {
 }
 
 func TestSaveAcceptsMultibyteMemoAtByteLimit(t *testing.T) {
-	service, _, _ := newTestService(t)
+	service, _, _, _ := newTestService(t)
 	memo := strings.Repeat("界", maxMemoBytes/3) + "x"
 	if len([]byte(memo)) != maxMemoBytes {
 		t.Fatalf("test memo byte length = %d, want %d", len([]byte(memo)), maxMemoBytes)
@@ -194,7 +233,7 @@ func TestSaveAcceptsMultibyteMemoAtByteLimit(t *testing.T) {
 }
 
 func TestSaveRejectsMultibyteMemoOverByteLimit(t *testing.T) {
-	service, _, _ := newTestService(t)
+	service, _, _, _ := newTestService(t)
 	memo := strings.Repeat("界", maxMemoBytes/3) + "xx"
 	if len([]byte(memo)) != maxMemoBytes+1 {
 		t.Fatalf("test memo byte length = %d, want %d", len([]byte(memo)), maxMemoBytes+1)
@@ -209,15 +248,22 @@ func TestSaveRejectsMultibyteMemoOverByteLimit(t *testing.T) {
 	}
 }
 
-func TestLoadRejectsOversizedMemoFile(t *testing.T) {
-	service, rootPath, configDir := newTestService(t)
-	memoPath := memoPathForTest(t, configDir, rootPath)
-	if err := os.MkdirAll(filepath.Dir(memoPath), 0o755); err != nil {
-		t.Fatalf("MkdirAll() error = %v", err)
+func TestLoadRejectsOversizedStoredMemo(t *testing.T) {
+	service, rootPath, _, store := newTestService(t)
+	putMemo(t, store, rootPath, strings.Repeat("x", maxMemoBytes+1))
+
+	_, err := service.Load("alpha")
+	if err == nil {
+		t.Fatal("Load() expected size error")
 	}
-	if err := os.WriteFile(memoPath, []byte(strings.Repeat("x", maxMemoBytes+1)), 0o644); err != nil {
-		t.Fatalf("WriteFile() error = %v", err)
+	if !strings.Contains(err.Error(), "too large") {
+		t.Fatalf("Load() error = %v, want size error", err)
 	}
+}
+
+func TestLoadRejectsOversizedLegacyMemoFile(t *testing.T) {
+	service, rootPath, _, _ := newTestService(t)
+	writeLegacyMemo(t, filepath.Join(rootPath, ".myT-x", memoFileName), strings.Repeat("x", maxMemoBytes+1))
 
 	_, err := service.Load("alpha")
 	if err == nil {
@@ -229,7 +275,7 @@ func TestLoadRejectsOversizedMemoFile(t *testing.T) {
 }
 
 func TestCleanupSessionRemovesCachedMemo(t *testing.T) {
-	service, rootPath, configDir := newTestService(t)
+	service, rootPath, _, store := newTestService(t)
 	if err := service.Save("alpha", "cached memo"); err != nil {
 		t.Fatalf("Save() error = %v", err)
 	}
@@ -237,17 +283,17 @@ func TestCleanupSessionRemovesCachedMemo(t *testing.T) {
 	if err := service.CleanupSession("alpha"); err != nil {
 		t.Fatalf("CleanupSession() error = %v", err)
 	}
-
-	memoPath := memoPathForTest(t, configDir, rootPath)
-	if err := os.WriteFile(memoPath, []byte("disk memo"), 0o644); err != nil {
-		t.Fatalf("WriteFile() error = %v", err)
+	if len(service.memoByKey) != 0 || len(service.memoKeyBySession) != 0 {
+		t.Fatalf("cache after cleanup = %v / %v, want empty", service.memoByKey, service.memoKeyBySession)
 	}
+
+	putMemo(t, store, rootPath, "stored memo")
 	got, err := service.Load("alpha")
 	if err != nil {
 		t.Fatalf("Load() error = %v", err)
 	}
-	if got != "disk memo" {
-		t.Fatalf("Load() = %q, want memo from disk after cleanup", got)
+	if got != "stored memo" {
+		t.Fatalf("Load() = %q, want memo from the store after cleanup", got)
 	}
 }
 
@@ -256,39 +302,26 @@ func TestRenameSessionMovesCachedMemoIndex(t *testing.T) {
 		"alpha":         filepath.Join(t.TempDir(), "workspace"),
 		"renamed-alpha": filepath.Join(t.TempDir(), "workspace-renamed"),
 	}
-	service, configDir := newTestServiceWithRootFunc(t, func(sessionName string) string {
+	service, _, _ := newTestServiceWithRootFunc(t, func(sessionName string) string {
 		return rootBySession[sessionName]
 	})
 	if err := service.Save("alpha", "cached memo"); err != nil {
 		t.Fatalf("Save() error = %v", err)
 	}
 
-	rootBySession["renamed-alpha"] = rootBySession["alpha"]
 	if err := service.RenameSession("alpha", "renamed-alpha"); err != nil {
 		t.Fatalf("RenameSession() error = %v", err)
 	}
 	if err := service.CleanupSession("renamed-alpha"); err != nil {
 		t.Fatalf("CleanupSession() error = %v", err)
 	}
-
-	memoPath := memoPathForTest(t, configDir, rootBySession["renamed-alpha"])
-	if err := os.WriteFile(memoPath, []byte("renamed disk memo"), 0o644); err != nil {
-		t.Fatalf("WriteFile() error = %v", err)
-	}
-	got, err := service.Load("renamed-alpha")
-	if err != nil {
-		t.Fatalf("Load() error = %v", err)
-	}
-	if got != "renamed disk memo" {
-		t.Fatalf("Load() = %q, want memo from disk after renamed cleanup", got)
+	if len(service.memoByKey) != 0 || len(service.memoKeyBySession) != 0 {
+		t.Fatalf("cache after renamed cleanup = %v / %v, want empty", service.memoByKey, service.memoKeyBySession)
 	}
 }
 
-func TestSaveCreatesSessionInfoDirectoryOnly(t *testing.T) {
-	service, rootPath, configDir := newTestService(t)
-	if _, err := os.Stat(filepath.Join(rootPath, ".myT-x")); !os.IsNotExist(err) {
-		t.Fatalf("precondition: .myT-x should not exist: err=%v", err)
-	}
+func TestSaveDoesNotCreateProjectDirectory(t *testing.T) {
+	service, rootPath, _, _ := newTestService(t)
 
 	if err := service.Save("alpha", "memo"); err != nil {
 		t.Fatalf("Save() error = %v", err)
@@ -297,20 +330,35 @@ func TestSaveCreatesSessionInfoDirectoryOnly(t *testing.T) {
 	if _, err := os.Stat(filepath.Join(rootPath, ".myT-x")); !os.IsNotExist(err) {
 		t.Errorf("Save() created workDir .myT-x, err=%v", err)
 	}
-	if _, err := os.Stat(filepath.Dir(memoPathForTest(t, configDir, rootPath))); err != nil {
-		t.Errorf("session-info memo directory not created: %v", err)
-	}
 }
 
-func TestLoadMigratesLegacyProjectMemo(t *testing.T) {
-	service, rootPath, configDir := newTestService(t)
-	legacyDir := filepath.Join(rootPath, ".myT-x")
-	if err := os.MkdirAll(legacyDir, 0o755); err != nil {
-		t.Fatalf("MkdirAll() error = %v", err)
+func TestLoadImportsLegacySessionInfoMemo(t *testing.T) {
+	service, rootPath, configDir, store := newTestService(t)
+	legacyPath := legacyMemoPathForTest(t, configDir, rootPath)
+	writeLegacyMemo(t, legacyPath, "session-info memo")
+
+	got, err := service.Load("alpha")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
 	}
-	if err := os.WriteFile(filepath.Join(legacyDir, memoFileName), []byte("legacy memo"), 0o644); err != nil {
-		t.Fatalf("WriteFile() error = %v", err)
+	if got != "session-info memo" {
+		t.Fatalf("Load() = %q, want imported memo", got)
+	}
+	if stored, _ := storedMemo(t, store, rootPath); stored != "session-info memo" {
+		t.Fatalf("stored memo = %q, want imported memo", stored)
+	}
+	if _, err := os.Stat(legacyPath); !os.IsNotExist(err) {
+		t.Fatalf("legacy memo file should be retired after import: %v", err)
+	}
+	if _, err := os.Stat(legacyPath + ".migrated"); err != nil {
+		t.Fatalf("legacy memo file should be kept with a .migrated suffix: %v", err)
 	}
+}
+
+func TestLoadMigratesLegacyProjectMemo(t *testing.T) {
+	service, rootPath, _, store := newTestService(t)
+	legacyPath := filepath.Join(rootPath, ".myT-x", memoFileName)
+	writeLegacyMemo(t, legacyPath, "legacy memo")
 
 	got, err := service.Load("alpha")
 	if err != nil {
@@ -319,35 +367,18 @@ func TestLoadMigratesLegacyProjectMemo(t *testing.T) {
 	if got != "legacy memo" {
 		t.Fatalf("Load() = %q, want migrated legacy memo", got)
 	}
-	memoPath := memoPathForTest(t, configDir, rootPath)
-	data, err := os.ReadFile(memoPath)
-	if err != nil {
-		t.Fatalf("ReadFile(%s) error = %v", memoPath, err)
-	}
-	if string(data) != "legacy memo" {
-		t.Fatalf("migrated memo = %q, want legacy memo", string(data))
+	if stored, _ := storedMemo(t, store, rootPath); stored != "legacy memo" {
+		t.Fatalf("stored memo = %q, want legacy memo", stored)
 	}
-	if data, err := os.ReadFile(filepath.Join(legacyDir, memoFileName)); err != nil || string(data) != "legacy memo" {
+	if data, err := os.ReadFile(legacyPath); err != nil || string(data) != "legacy memo" {
 		t.Fatalf("legacy memo should remain untouched, data=%q err=%v", string(data), err)
 	}
 }
 
 func TestLoadPrefersSessionInfoMemoOverLegacyProjectMemo(t *testing.T) {
-	service, rootPath, configDir := newTestService(t)
-	legacyDir := filepath.Join(rootPath, ".myT-x")
-	if err := os.MkdirAll(legacyDir, 0o755); err != nil {
-		t.Fatalf("MkdirAll() legacy error = %v", err)
-	}
-	if err := os.WriteFile(filepath.Join(legacyDir, memoFileName), []byte("legacy memo"), 0o644); err != nil {
-		t.Fatalf("WriteFile() legacy error = %v", err)
-	}
-	memoPath := memoPathForTest(t, configDir, rootPath)
-	if err := os.MkdirAll(filepath.Dir(memoPath), 0o755); err != nil {
-		t.Fatalf("MkdirAll() current error = %v", err)
-	}
-	if err := os.WriteFile(memoPath, []byte("current memo"), 0o644); err != nil {
-		t.Fatalf("WriteFile() current error = %v", err)
-	}
+	service, rootPath, configDir, _ := newTestService(t)
+	writeLegacyMemo(t, filepath.Join(rootPath, ".myT-x", memoFileName), "legacy memo")
+	writeLegacyMemo(t, legacyMemoPathForTest(t, configDir, rootPath), "current memo")
 
 	got, err := service.Load("alpha")
 	if err != nil {
@@ -358,31 +389,26 @@ func TestLoadPrefersSessionInfoMemoOverLegacyProjectMemo(t *testing.T) {
 	}
 }
 
-func TestSaveRemovesTempFileWhenReplaceFails(t *testing.T) {
-	service, rootPath, configDir := newTestService(t)
-	memoPath := memoPathForTest(t, configDir, rootPath)
-	if err := os.MkdirAll(memoPath, 0o755); err != nil {
-		t.Fatalf("MkdirAll(%s) error = %v", memoPath, err)
-	}
+func TestLoadPrefersStoredMemoOverLegacyFiles(t *testing.T) {
+	service, rootPath, configDir, store := newTestService(t)
+	putMemo(t, store, rootPath, "stored memo")
+	legacyPath := legacyMemoPathForTest(t, configDir, rootPath)
+	writeLegacyMemo(t, legacyPath, "stale file memo")
 
-	err := service.Save("alpha", "memo")
-	if err == nil {
-		t.Fatal("Save() expected replace error")
+	got, err := service.Load("alpha")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
 	}
-
-	entries, readErr := os.ReadDir(filepath.Dir(memoPath))
-	if readErr != nil {
-		t.Fatalf("ReadDir() error = %v", readErr)
+	if got != "stored memo" {
+		t.Fatalf("Load() = %q, want stored memo", got)
 	}
-	for _, entry := range entries {
-		if strings.HasPrefix(entry.Name(), ".session-memo.") {
-			t.Fatalf("temporary memo file was not removed: %s", entry.Name())
-		}
+	if _, err := os.Stat(legacyPath); err != nil {
+		t.Fatalf("legacy file should not be touched once the store has a memo: %v", err)
 	}
 }
 
 func TestConcurrentSaveAndLoad(t *testing.T) {
-	service, _, _ := newTestService(t)
+	service, _, _, _ := newTestService(t)
 	if err := service.Save("alpha", "seed"); err != nil {
 		t.Fatalf("Save() seed error = %v", err)
 	}
@@ -0,0 +1,81 @@
+package sessionports
+
+import (
+	"cmp"
+	"strconv"
+)
+
+// maxAncestorDepth bounds the parent walk so that a PID reuse loop in a
+// stale snapshot cannot spin forever.
+const maxAncestorDepth = 64
+
+// resolvePorts attributes listeners to panes by walking each listening
+// process up its parent chain until it reaches a pane shell PID.
+// A port bound on several addresses (e.g. 0.0.0.0 and ::) is reported once
+// per session. The result is sorted by session name, then port.
+//
+// Windows reuses PIDs, so a long-lived listener whose parent exited can in
+// rare cases be attributed to an unrelated pane that received the parent's
+// PID. Pane shells outlive their children in practice, so this is accepted.
+func resolvePorts(roots []PaneRoot, listeners []Listener, processes map[uint32]Process) []Port {
+	if len(roots) == 0 || len(listeners) == 0 {
+		return nil
+	}
+	rootByPID := make(map[uint32]PaneRoot, len(roots))
+	for _, root := range roots {
+		if root.PID > 0 {
+			rootByPID[uint32(root.PID)] = root
+		}
+	}
+	if len(rootByPID) == 0 {
+		return nil
+	}
+
+	type portKey struct {
+		session string
+		port    int
+	}
+	seen := make(map[portKey]struct{}, len(listeners))
+	var ports []Port
+	for _, listener := range listeners {
+		root, ok := findPaneRoot(listener.PID, rootByPID, processes)
+		if !ok {
+			continue
+		}
+		key := portKey{session: root.SessionName, port: listener.Port}
+		if _, dup := seen[key]; dup {
+			continue
+		}
+		seen[key] = struct{}{}
+		ports = append(ports, Port{
+			SessionName: root.SessionName,
+			PaneID:      root.PaneID,
+			Port:        listener.Port,
+			PID:         listener.PID,
+			Process:     processes[listener.PID].Name,
+			Address:     listener.Address,
+			URL:         "http://localhost:" + strconv.Itoa(listener.Port),
+		})
+	}
+	sortPorts(ports)
+	return ports
+}
+
+func comparePorts(a, b Port) int {
+	return cmp.Or(cmp.Compare(a.SessionName, b.SessionName), cmp.Compare(a.Port, b.Port))
+}
+
+// findPaneRoot returns the pane whose shell is pid or one of its ancestors.
+func findPaneRoot(pid uint32, rootByPID map[uint32]PaneRoot, processes map[uint32]Process) (PaneRoot, bool) {
+	for range maxAncestorDepth {
+		if root, ok := rootByPID[pid]; ok {
+			return root, true
+		}
+		process, ok := processes[pid]
+		if !ok || process.ParentPID == 0 || process.ParentPID == pid {
+			return PaneRoot{}, false
+		}
+		pid = process.ParentPID
+	}
+	return PaneRoot{}, false
+}
@@ -0,0 +1,49 @@
+package sessionports
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestResolvePorts(t *testing.T) {
+	roots := []PaneRoot{
+		{SessionName: "web", PaneID: "%1", PID: 100},
+		{SessionName: "api", PaneID: "%2", PID: 200},
+	}
+	processes := map[uint32]Process{
+		100: {PID: 100, ParentPID: 1, Name: "powershell.exe"},
+		110: {PID: 110, ParentPID: 100, Name: "cmd.exe"},
+		111: {PID: 111, ParentPID: 110, Name: "node.exe"},
+		200: {PID: 200, ParentPID: 1, Name: "powershell.exe"},
+		201: {PID: 201, ParentPID: 200, Name: "go.exe"},
+		300: {PID: 300, ParentPID: 1, Name: "postgres.exe"},
+	}
+	listeners := []Listener{
+		{Address: "::", Port: 5173, PID: 111},
+		{Address: "0.0.0.0", Port: 5173, PID: 111},
+		{Address: "127.0.0.1", Port: 8080, PID: 201},
+		{Address: "0.0.0.0", Port: 5432, PID: 300},
+		{Address: "0.0.0.0", Port: 9000, PID: 999},
+	}
+
+	got := resolvePorts(roots, listeners, processes)
+	want := []Port{
+		{SessionName: "api", PaneID: "%2", Port: 8080, PID: 201, Process: "go.exe", Address: "127.0.0.1", URL: "http://localhost:8080"},
+		{SessionName: "web", PaneID: "%1", Port: 5173, PID: 111, Process: "node.exe", Address: "::", URL: "http://localhost:5173"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("resolvePorts() = %#v, want %#v", got, want)
+	}
+}
+
+func TestResolvePortsStopsOnParentCycle(t *testing.T) {
+	roots := []PaneRoot{{SessionName: "web", PaneID: "%1", PID: 100}}
+	processes := map[uint32]Process{
+		10: {PID: 10, ParentPID: 11},
+		11: {PID: 11, ParentPID: 10},
+	}
+	listeners := []Listener{{Address: "0.0.0.0", Port: 3000, PID: 10}}
+	if got := resolvePorts(roots, listeners, processes); got != nil {
+		t.Fatalf("resolvePorts() = %#v, want nil", got)
+	}
+}
@@ -0,0 +1,16 @@
+//go:build !windows
+
+package sessionports
+
+// ProcessTreeSupported is false: scanProcesses is a stub outside Windows.
+const ProcessTreeSupported = false
+
+// scanListeners is a stub outside Windows; port tracking is Windows-only.
+func scanListeners() ([]Listener, error) {
+	return nil, nil
+}
+
+// scanProcesses is a stub outside Windows; port tracking is Windows-only.
+func scanProcesses() (map[uint32]Process, error) {
+	return nil, nil
+}
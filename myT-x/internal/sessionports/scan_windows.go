@@ -0,0 +1,163 @@
+//go:build windows
+
+package sessionports
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/netip"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// ProcessTreeSupported reports whether scanProcesses returns a real process
+// snapshot, so that Service.PaneForProcess can identify processes.
+const ProcessTreeSupported = true
+
+var (
+	iphlpapi = windows.NewLazySystemDLL("iphlpapi.dll")
+
+	procGetExtendedTcpTable = iphlpapi.NewProc("GetExtendedTcpTable")
+)
+
+const (
+	// _TCP_TABLE_OWNER_PID_LISTENER returns only LISTEN sockets with owner PIDs.
+	_TCP_TABLE_OWNER_PID_LISTENER = 3
+
+	// tcpTableMaxAttempts bounds retries when the table grows between the
+	// size query and the read.
+	tcpTableMaxAttempts = 4
+)
+
+// _MIB_TCPROW_OWNER_PID mirrors the Windows MIB_TCPROW_OWNER_PID structure.
+type _MIB_TCPROW_OWNER_PID struct {
+	State      uint32
+	LocalAddr  [4]byte
+	LocalPort  uint32
+	RemoteAddr [4]byte
+	RemotePort uint32
+	OwningPID  uint32
+}
+
+// _MIB_TCP6ROW_OWNER_PID mirrors the Windows MIB_TCP6ROW_OWNER_PID structure.
+type _MIB_TCP6ROW_OWNER_PID struct {
+	LocalAddr     [16]byte
+	LocalScopeID  uint32
+	LocalPort     uint32
+	RemoteAddr    [16]byte
+	RemoteScopeID uint32
+	RemotePort    uint32
+	State         uint32
+	OwningPID     uint32
+}
+
+// scanListeners returns every listening TCP socket (IPv4 and IPv6) with its
+// owning PID via GetExtendedTcpTable.
+func scanListeners() ([]Listener, error) {
+	v4, err := readTCPTable(windows.AF_INET)
+	if err != nil {
+		return nil, fmt.Errorf("read IPv4 listener table: %w", err)
+	}
+	v6, err := readTCPTable(windows.AF_INET6)
+	if err != nil {
+		return nil, fmt.Errorf("read IPv6 listener table: %w", err)
+	}
+
+	var listeners []Listener
+	for _, row := range tableRows[_MIB_TCPROW_OWNER_PID](v4) {
+		listeners = append(listeners, Listener{
+			Address: netip.AddrFrom4(row.LocalAddr).String(),
+			Port:    int(networkPort(row.LocalPort)),
+			PID:     row.OwningPID,
+		})
+	}
+	for _, row := range tableRows[_MIB_TCP6ROW_OWNER_PID](v6) {
+		listeners = append(listeners, Listener{
+			Address: netip.AddrFrom16(row.LocalAddr).String(),
+			Port:    int(networkPort(row.LocalPort)),
+			PID:     row.OwningPID,
+		})
+	}
+	return listeners, nil
+}
+
+// tableRows views the rows of a raw MIB table (a uint32 entry count followed
+// by rows) without copying. The count is clamped to what the buffer holds.
+func tableRows[Row any](table []byte) []Row {
+	if len(table) < 4 {
+		return nil
+	}
+	var zero Row
+	count := min(int(binary.LittleEndian.Uint32(table)), (len(table)-4)/int(unsafe.Sizeof(zero)))
+	if count <= 0 {
+		return nil
+	}
+	return unsafe.Slice((*Row)(unsafe.Pointer(&table[4])), count)
+}
+
+// readTCPTable returns the raw listener table for one address family.
+func readTCPTable(family uint32) ([]byte, error) {
+	if err := procGetExtendedTcpTable.Find(); err != nil {
+		return nil, err
+	}
+	var size uint32
+	for range tcpTableMaxAttempts {
+		var buf []byte
+		var ptr uintptr
+		if size > 0 {
+			buf = make([]byte, size)
+			ptr = uintptr(unsafe.Pointer(&buf[0]))
+		}
+		ret, _, _ := procGetExtendedTcpTable.Call(
+			ptr,
+			uintptr(unsafe.Pointer(&size)),
+			0,
+			uintptr(family),
+			_TCP_TABLE_OWNER_PID_LISTENER,
+			0,
+		)
+		switch windows.Errno(ret) {
+		case windows.ERROR_SUCCESS:
+			return buf, nil
+		case windows.ERROR_INSUFFICIENT_BUFFER:
+			continue
+		default:
+			return nil, fmt.Errorf("GetExtendedTcpTable: %w", windows.Errno(ret))
+		}
+	}
+	return nil, errors.New("GetExtendedTcpTable: table kept growing")
+}
+
+// networkPort converts the network-byte-order port in the low 16 bits of a
+// MIB row's port field to host order.
+func networkPort(raw uint32) uint16 {
+	return uint16(raw&0xff)<<8 | uint16(raw>>8&0xff)
+}
+
+// scanProcesses returns a PID-keyed snapshot of every running process.
+func scanProcesses() (map[uint32]Process, error) {
+	snap, err := windows.CreateToolhelp32Snapshot(windows.TH32CS_SNAPPROCESS, 0)
+	if err != nil {
+		return nil, fmt.Errorf("CreateToolhelp32Snapshot: %w", err)
+	}
+	defer windows.CloseHandle(snap)
+
+	processes := make(map[uint32]Process, 256)
+	var entry windows.ProcessEntry32
+	entry.Size = uint32(unsafe.Sizeof(entry))
+	err = windows.Process32First(snap, &entry)
+	for err == nil {
+		processes[entry.ProcessID] = Process{
+			PID:       entry.ProcessID,
+			ParentPID: entry.ParentProcessID,
+			Name:      windows.UTF16ToString(entry.ExeFile[:]),
+		}
+		err = windows.Process32Next(snap, &entry)
+	}
+	if !errors.Is(err, windows.ERROR_NO_MORE_FILES) {
+		return nil, fmt.Errorf("Process32Next: %w", err)
+	}
+	return processes, nil
+}
@@ -0,0 +1,218 @@
+// Package sessionports tracks TCP ports that processes started from panes
+// listen on, so the UI can offer "open http://localhost:5173" for the session
+// that owns a dev server. Listeners are polled from the OS TCP table and
+// attributed to panes through the process parent chain.
+package sessionports
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"myT-x/internal/apptypes"
+)
+
+// DefaultPollInterval is the listener polling period used when
+// Deps.PollInterval is zero.
+const DefaultPollInterval = 3 * time.Second
+
+// Deps holds external dependencies injected at construction time.
+type Deps struct {
+	// Emitter sends runtime events to the frontend.
+	// Optional: defaults to a no-op emitter if nil.
+	Emitter apptypes.RuntimeEventEmitter
+
+	// PaneRoots returns the shell process of every live pane.
+	PaneRoots func() []PaneRoot
+
+	// ScanListeners returns the listening TCP sockets of the machine.
+	// Optional: defaults to GetExtendedTcpTable on Windows.
+	ScanListeners func() ([]Listener, error)
+
+	// ScanProcesses returns a PID-keyed process snapshot.
+	// Optional: defaults to a Toolhelp32 snapshot on Windows.
+	ScanProcesses func() (map[uint32]Process, error)
+
+	// PollInterval is the period of Run. Optional: defaults to DefaultPollInterval.
+	PollInterval time.Duration
+
+	// Paused reports whether Run should skip polls, e.g. while the system
+	// sleeps. Optional: defaults to never paused.
+	Paused func() bool
+}
+
+// Service keeps the latest per-session port registry.
+//
+// Thread-safety is managed internally via mu. No external locking is required.
+type Service struct {
+	deps Deps
+
+	mu sync.Mutex
+	// ports holds the latest poll result keyed by "session\x00port".
+	ports map[string]Port
+	// lastScanErr suppresses repeated warnings for the same scan failure.
+	lastScanErr string
+}
+
+// NewService creates a session port service.
+// Panics if PaneRoots is nil.
+func NewService(deps Deps) *Service {
+	if deps.PaneRoots == nil {
+		panic("sessionports.NewService: PaneRoots must be non-nil")
+	}
+	if deps.Emitter == nil {
+		deps.Emitter = apptypes.NoopEmitter{}
+	}
+	if deps.ScanListeners == nil {
+		deps.ScanListeners = scanListeners
+	}
+	if deps.ScanProcesses == nil {
+		deps.ScanProcesses = scanProcesses
+	}
+	if deps.PollInterval <= 0 {
+		deps.PollInterval = DefaultPollInterval
+	}
+	if deps.Paused == nil {
+		deps.Paused = func() bool { return false }
+	}
+	return &Service{
+		deps:  deps,
+		ports: map[string]Port{},
+	}
+}
+
+// Run polls until ctx is cancelled, skipping ticks while paused. Scan failures
+// keep the previous registry and are logged once until the failure changes or
+// clears.
+func (s *Service) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.deps.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if s.deps.Paused() {
+				continue
+			}
+			s.logScanError(s.Poll())
+		}
+	}
+}
+
+// Poll scans listeners once, updates the registry, and emits OpenedEvent and
+// ClosedEvent for the difference. The OS is not queried while no pane exists.
+func (s *Service) Poll() error {
+	roots := s.deps.PaneRoots()
+	var current []Port
+	if len(roots) > 0 {
+		listeners, err := s.deps.ScanListeners()
+		if err != nil {
+			return err
+		}
+		if len(listeners) > 0 {
+			processes, err := s.deps.ScanProcesses()
+			if err != nil {
+				return err
+			}
+			current = resolvePorts(roots, listeners, processes)
+		}
+	}
+
+	next := make(map[string]Port, len(current))
+	for _, port := range current {
+		next[registryKey(port.SessionName, port.Port)] = port
+	}
+
+	s.mu.Lock()
+	var opened, closed []Port
+	for key, port := range next {
+		if _, ok := s.ports[key]; !ok {
+			opened = append(opened, port)
+		}
+	}
+	for key, port := range s.ports {
+		if _, ok := next[key]; !ok {
+			closed = append(closed, port)
+		}
+	}
+	s.ports = next
+	s.mu.Unlock()
+
+	sortPorts(closed)
+	sortPorts(opened)
+	for _, port := range closed {
+		slog.Debug("[DEBUG-PORTS] port closed", "session", port.SessionName, "port", port.Port)
+		s.deps.Emitter.Emit(ClosedEvent, port)
+	}
+	for _, port := range opened {
+		slog.Debug("[DEBUG-PORTS] port opened", "session", port.SessionName, "port", port.Port,
+			"pane", port.PaneID, "process", port.Process)
+		s.deps.Emitter.Emit(OpenedEvent, port)
+	}
+	return nil
+}
+
+// PaneForProcess returns the pane whose shell is pid or one of its
+// ancestors, e.g. to identify the pane a shim client runs in. ok is false
+// when pid is outside every pane's process tree or the process snapshot
+// fails.
+func (s *Service) PaneForProcess(pid uint32) (PaneRoot, bool) {
+	if pid == 0 {
+		return PaneRoot{}, false
+	}
+	rootByPID := map[uint32]PaneRoot{}
+	for _, root := range s.deps.PaneRoots() {
+		if root.PID > 0 {
+			rootByPID[uint32(root.PID)] = root
+		}
+	}
+	if len(rootByPID) == 0 {
+		return PaneRoot{}, false
+	}
+	processes, err := s.deps.ScanProcesses()
+	if err != nil {
+		slog.Debug("[DEBUG-PORTS] process snapshot failed", "error", err)
+		return PaneRoot{}, false
+	}
+	return findPaneRoot(pid, rootByPID, processes)
+}
+
+// SessionPorts returns the ports of a session sorted by port number.
+// The result is never nil.
+func (s *Service) SessionPorts(sessionName string) []Port {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ports := make([]Port, 0)
+	for _, port := range s.ports {
+		if port.SessionName == sessionName {
+			ports = append(ports, port)
+		}
+	}
+	sortPorts(ports)
+	return ports
+}
+
+func (s *Service) logScanError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err == nil {
+		s.lastScanErr = ""
+		return
+	}
+	if msg := err.Error(); msg != s.lastScanErr {
+		s.lastScanErr = msg
+		slog.Warn("[WARN-PORTS] listener scan failed", "error", err)
+	}
+}
+
+func registryKey(sessionName string, port int) string {
+	return sessionName + "\x00" + strconv.Itoa(port)
+}
+
+func sortPorts(ports []Port) {
+	slices.SortFunc(ports, comparePorts)
+}
@@ -0,0 +1,198 @@
+package sessionports
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"myT-x/internal/apptypes"
+)
+
+type recordedEvent struct {
+	name string
+	port Port
+}
+
+type recordingEmitter struct {
+	mu     sync.Mutex
+	events []recordedEvent
+}
+
+func (e *recordingEmitter) emit(name string, payload any) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.events = append(e.events, recordedEvent{name: name, port: payload.(Port)})
+}
+
+func (e *recordingEmitter) take() []recordedEvent {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	events := e.events
+	e.events = nil
+	return events
+}
+
+func TestNewServicePanicsWithoutPaneRoots(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic for missing PaneRoots")
+		}
+	}()
+	NewService(Deps{})
+}
+
+func TestPollEmitsOpenedAndClosed(t *testing.T) {
+	emitter := &recordingEmitter{}
+	listeners := []Listener{{Address: "0.0.0.0", Port: 5173, PID: 11}}
+	service := NewService(Deps{
+		Emitter:       apptypes.EventEmitterFunc(emitter.emit),
+		PaneRoots:     func() []PaneRoot { return []PaneRoot{{SessionName: "web", PaneID: "%1", PID: 10}} },
+		ScanListeners: func() ([]Listener, error) { return listeners, nil },
+		ScanProcesses: func() (map[uint32]Process, error) {
+			return map[uint32]Process{11: {PID: 11, ParentPID: 10, Name: "node.exe"}}, nil
+		},
+	})
+
+	if err := service.Poll(); err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+	events := emitter.take()
+	if len(events) != 1 || events[0].name != OpenedEvent || events[0].port.Port != 5173 {
+		t.Fatalf("events = %+v, want one opened 5173", events)
+	}
+	if ports := service.SessionPorts("web"); len(ports) != 1 || ports[0].URL != "http://localhost:5173" {
+		t.Fatalf("SessionPorts(web) = %+v", ports)
+	}
+
+	// An unchanged scan emits nothing.
+	if err := service.Poll(); err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+	if events := emitter.take(); len(events) != 0 {
+		t.Fatalf("events = %+v, want none", events)
+	}
+
+	listeners = nil
+	if err := service.Poll(); err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+	events = emitter.take()
+	if len(events) != 1 || events[0].name != ClosedEvent || events[0].port.Port != 5173 {
+		t.Fatalf("events = %+v, want one closed 5173", events)
+	}
+	if ports := service.SessionPorts("web"); ports == nil || len(ports) != 0 {
+		t.Fatalf("SessionPorts(web) = %#v, want empty non-nil", ports)
+	}
+}
+
+func TestPollKeepsRegistryOnScanError(t *testing.T) {
+	scanErr := error(nil)
+	service := NewService(Deps{
+		PaneRoots: func() []PaneRoot { return []PaneRoot{{SessionName: "web", PaneID: "%1", PID: 10}} },
+		ScanListeners: func() ([]Listener, error) {
+			if scanErr != nil {
+				return nil, scanErr
+			}
+			return []Listener{{Address: "::", Port: 3000, PID: 10}}, nil
+		},
+		ScanProcesses: func() (map[uint32]Process, error) { return nil, nil },
+	})
+	if err := service.Poll(); err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+	scanErr = errors.New("access denied")
+	if err := service.Poll(); !errors.Is(err, scanErr) {
+		t.Fatalf("Poll() error = %v, want %v", err, scanErr)
+	}
+	if ports := service.SessionPorts("web"); len(ports) != 1 {
+		t.Fatalf("SessionPorts(web) = %+v, want previous registry kept", ports)
+	}
+}
+
+func TestPollSkipsScanWithoutPanes(t *testing.T) {
+	service := NewService(Deps{
+		PaneRoots: func() []PaneRoot { return nil },
+		ScanListeners: func() ([]Listener, error) {
+			t.Fatal("ScanListeners called without panes")
+			return nil, nil
+		},
+	})
+	if err := service.Poll(); err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+}
+
+func TestRunSkipsPollsWhilePaused(t *testing.T) {
+	var paused atomic.Bool
+	paused.Store(true)
+	var polls atomic.Int32
+	service := NewService(Deps{
+		PaneRoots: func() []PaneRoot {
+			polls.Add(1)
+			return nil
+		},
+		PollInterval: time.Millisecond,
+		Paused:       paused.Load,
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		service.Run(ctx)
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	if got := polls.Load(); got != 0 {
+		t.Fatalf("polls while paused = %d, want 0", got)
+	}
+	paused.Store(false)
+	deadline := time.Now().Add(2 * time.Second)
+	for polls.Load() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("Run() did not poll after unpausing")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	<-done
+}
+
+func TestPaneForProcessWalksParentChain(t *testing.T) {
+	service := NewService(Deps{
+		PaneRoots: func() []PaneRoot {
+			return []PaneRoot{{SessionName: "web", PaneID: "%1", PID: 10}, {SessionName: "api", PaneID: "%2", PID: 20}}
+		},
+		ScanProcesses: func() (map[uint32]Process, error) {
+			return map[uint32]Process{
+				30: {PID: 30, ParentPID: 21, Name: "tmux.exe"},
+				21: {PID: 21, ParentPID: 20, Name: "node.exe"},
+				40: {PID: 40, ParentPID: 1, Name: "tmux.exe"},
+			}, nil
+		},
+	})
+
+	if root, ok := service.PaneForProcess(30); !ok || root.PaneID != "%2" || root.SessionName != "api" {
+		t.Fatalf("PaneForProcess(30) = %+v, %v, want pane %%2 of api", root, ok)
+	}
+	if root, ok := service.PaneForProcess(10); !ok || root.PaneID != "%1" {
+		t.Fatalf("PaneForProcess(10) = %+v, %v, want the pane shell itself", root, ok)
+	}
+	for _, pid := range []uint32{0, 40, 99} {
+		if root, ok := service.PaneForProcess(pid); ok {
+			t.Fatalf("PaneForProcess(%d) = %+v, want no pane", pid, root)
+		}
+	}
+}
+
+func TestPaneForProcessReportsScanFailure(t *testing.T) {
+	service := NewService(Deps{
+		PaneRoots:     func() []PaneRoot { return []PaneRoot{{SessionName: "web", PaneID: "%1", PID: 10}} },
+		ScanProcesses: func() (map[uint32]Process, error) { return nil, errors.New("snapshot failed") },
+	})
+	if root, ok := service.PaneForProcess(10); ok {
+		t.Fatalf("PaneForProcess() = %+v, want no pane when the snapshot fails", root)
+	}
+}
@@ -0,0 +1,44 @@
+package sessionports
+
+const (
+	// OpenedEvent carries a Port when a pane process starts listening.
+	OpenedEvent = "session-ports:opened"
+	// ClosedEvent carries a Port when its listener goes away or its session
+	// closes.
+	ClosedEvent = "session-ports:closed"
+)
+
+// Port is a TCP listener owned by a process in a pane's process tree.
+type Port struct {
+	SessionName string `json:"session_name"`
+	PaneID      string `json:"pane_id"`
+	Port        int    `json:"port"`
+	PID         uint32 `json:"pid"`
+	// Process is the executable name of the listening process, e.g. "node.exe".
+	Process string `json:"process,omitempty"`
+	// Address is the local bind address, e.g. "127.0.0.1", "0.0.0.0", or "::".
+	Address string `json:"address"`
+	// URL is the http://localhost URL the UI offers to open.
+	URL string `json:"url"`
+}
+
+// PaneRoot is the shell process of one pane.
+type PaneRoot struct {
+	SessionName string
+	PaneID      string
+	PID         int
+}
+
+// Listener is one listening TCP socket reported by the OS.
+type Listener struct {
+	Address string
+	Port    int
+	PID     uint32
+}
+
+// Process is one entry of a process snapshot.
+type Process struct {
+	PID       uint32
+	ParentPID uint32
+	Name      string
+}
@@ -0,0 +1,206 @@
+// Package sessionquery filters, sorts, and pages the session list on the
+// backend so API clients driving hundreds of sessions do not have to fetch
+// and scan full snapshots. The sidebar search uses the same engine.
+package sessionquery
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultLimit is the page size used when Page.Limit is zero.
+	DefaultLimit = 50
+	// MaxLimit caps Page.Limit.
+	MaxLimit = 500
+)
+
+// Session statuses. A session that went idle after producing output the user
+// has not seen yet (or rang the bell) is StatusNeedsInput rather than
+// StatusIdle.
+const (
+	StatusRunning    = "running"
+	StatusIdle       = "idle"
+	StatusNeedsInput = "needs_input"
+)
+
+// Sort keys. SortDefault keeps the sidebar order.
+const (
+	SortDefault  = ""
+	SortName     = "name"
+	SortCreated  = "created"
+	SortActivity = "activity"
+)
+
+// Filter selects sessions. Empty fields match every session; set fields must
+// all match.
+type Filter struct {
+	// Text matches a case-insensitive substring of the session name, branch,
+	// or repository path.
+	Text string `json:"text,omitempty"`
+	// Repo matches the repository path, or its base name, case-insensitively.
+	Repo string `json:"repo,omitempty"`
+	// Branch is a glob matched against the worktree branch.
+	Branch string `json:"branch,omitempty"`
+	// Status is one of StatusRunning, StatusIdle, or StatusNeedsInput.
+	Status string `json:"status,omitempty"`
+	// Badge matches the badge color or emoji exactly.
+	Badge string `json:"badge,omitempty"`
+	// ActiveWithinSeconds keeps sessions with output in the last N seconds.
+	ActiveWithinSeconds int `json:"active_within_seconds,omitempty"`
+	// IncludeDetached also returns sessions hidden from the UI.
+	IncludeDetached bool `json:"include_detached,omitempty"`
+}
+
+// Sort orders the matching sessions. Ties keep the sidebar order.
+type Sort struct {
+	By         string `json:"by,omitempty"`
+	Descending bool   `json:"descending,omitempty"`
+}
+
+// Page selects a window of the sorted matches.
+type Page struct {
+	Offset int `json:"offset,omitempty"`
+	// Limit defaults to DefaultLimit and is capped at MaxLimit.
+	Limit int `json:"limit,omitempty"`
+}
+
+// Item is the queryable view of one session.
+type Item struct {
+	Name         string
+	RepoPath     string
+	Branch       string
+	Status       string
+	BadgeColor   string
+	BadgeEmoji   string
+	Detached     bool
+	CreatedAt    time.Time
+	LastActivity time.Time
+}
+
+// Run returns the page of items matching filter in sort order, together with
+// the number of matches before paging. items are expected in sidebar order.
+func Run(items []Item, filter Filter, sort Sort, page Page, now time.Time) ([]Item, int, error) {
+	if err := validate(filter, sort, page); err != nil {
+		return nil, 0, err
+	}
+	filter.Text = strings.ToLower(strings.TrimSpace(filter.Text))
+	filter.Repo = strings.TrimSpace(filter.Repo)
+	filter.Branch = strings.TrimSpace(filter.Branch)
+	filter.Badge = strings.TrimSpace(filter.Badge)
+
+	matched := make([]Item, 0, len(items))
+	for _, item := range items {
+		if matches(item, filter, now) {
+			matched = append(matched, item)
+		}
+	}
+	if compare := comparator(sort.By); compare != nil {
+		slices.SortStableFunc(matched, func(a, b Item) int {
+			if sort.Descending {
+				return compare(b, a)
+			}
+			return compare(a, b)
+		})
+	}
+
+	total := len(matched)
+	limit := page.Limit
+	if limit == 0 {
+		limit = DefaultLimit
+	}
+	limit = min(limit, MaxLimit)
+	start := min(page.Offset, total)
+	end := min(start+limit, total)
+	return matched[start:end], total, nil
+}
+
+func validate(filter Filter, sort Sort, page Page) error {
+	switch filter.Status {
+	case "", StatusRunning, StatusIdle, StatusNeedsInput:
+	default:
+		return fmt.Errorf("unknown status %q", filter.Status)
+	}
+	switch sort.By {
+	case SortDefault, SortName, SortCreated, SortActivity:
+	default:
+		return fmt.Errorf("unknown sort key %q", sort.By)
+	}
+	if branch := strings.TrimSpace(filter.Branch); branch != "" {
+		if _, err := path.Match(branch, ""); err != nil {
+			return fmt.Errorf("invalid branch pattern %q: %w", branch, err)
+		}
+	}
+	if filter.ActiveWithinSeconds < 0 {
+		return errors.New("active_within_seconds must not be negative")
+	}
+	if page.Offset < 0 || page.Limit < 0 {
+		return errors.New("page offset and limit must not be negative")
+	}
+	return nil
+}
+
+// matches reports whether item satisfies filter. filter is trimmed and Text
+// is lower-cased.
+func matches(item Item, filter Filter, now time.Time) bool {
+	if item.Detached && !filter.IncludeDetached {
+		return false
+	}
+	if filter.Text != "" &&
+		!strings.Contains(strings.ToLower(item.Name), filter.Text) &&
+		!strings.Contains(strings.ToLower(item.Branch), filter.Text) &&
+		!strings.Contains(strings.ToLower(item.RepoPath), filter.Text) {
+		return false
+	}
+	if filter.Repo != "" && !repoMatches(item.RepoPath, filter.Repo) {
+		return false
+	}
+	if filter.Branch != "" {
+		if matched, _ := path.Match(filter.Branch, item.Branch); !matched {
+			return false
+		}
+	}
+	if filter.Status != "" && item.Status != filter.Status {
+		return false
+	}
+	if filter.Badge != "" && item.BadgeColor != filter.Badge && item.BadgeEmoji != filter.Badge {
+		return false
+	}
+	if filter.ActiveWithinSeconds > 0 {
+		cutoff := now.Add(-time.Duration(filter.ActiveWithinSeconds) * time.Second)
+		if item.LastActivity.Before(cutoff) {
+			return false
+		}
+	}
+	return true
+}
+
+// repoMatches compares case-insensitively because Windows paths are.
+func repoMatches(repoPath, want string) bool {
+	if repoPath == "" {
+		return false
+	}
+	repoPath = filepath.Clean(repoPath)
+	if strings.EqualFold(repoPath, filepath.Clean(want)) {
+		return true
+	}
+	return strings.EqualFold(filepath.Base(repoPath), want)
+}
+
+func comparator(by string) func(a, b Item) int {
+	switch by {
+	case SortName:
+		return func(a, b Item) int { return cmp.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name)) }
+	case SortCreated:
+		return func(a, b Item) int { return a.CreatedAt.Compare(b.CreatedAt) }
+	case SortActivity:
+		return func(a, b Item) int { return a.LastActivity.Compare(b.LastActivity) }
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package sessionquery
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+var (
+	testNow     = time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	backendRepo = filepath.FromSlash("/src/Backend")
+	docsRepo    = filepath.FromSlash("/src/Docs")
+)
+
+func testItems() []Item {
+	return []Item{
+		{Name: "api-fix", RepoPath: backendRepo, Branch: "fix/login", Status: StatusRunning,
+			BadgeColor: "#f00", CreatedAt: testNow.Add(-3 * time.Hour), LastActivity: testNow.Add(-10 * time.Second)},
+		{Name: "docs", RepoPath: docsRepo, Branch: "main", Status: StatusIdle,
+			BadgeEmoji: "📝", CreatedAt: testNow.Add(-1 * time.Hour), LastActivity: testNow.Add(-2 * time.Hour)},
+		{Name: "Backend-feature", RepoPath: backendRepo, Branch: "feature/search", Status: StatusNeedsInput,
+			CreatedAt: testNow.Add(-2 * time.Hour), LastActivity: testNow.Add(-5 * time.Minute)},
+		{Name: "bot", Detached: true, Status: StatusRunning, CreatedAt: testNow, LastActivity: testNow},
+	}
+}
+
+func names(items []Item) []string {
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		out = append(out, item.Name)
+	}
+	return out
+}
+
+func TestRunFilters(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []string
+	}{
+		{"empty filter hides detached", Filter{}, []string{"api-fix", "docs", "Backend-feature"}},
+		{"include detached", Filter{IncludeDetached: true}, []string{"api-fix", "docs", "Backend-feature", "bot"}},
+		{"text matches name case-insensitively", Filter{Text: " BACKEND "}, []string{"api-fix", "Backend-feature"}},
+		{"text matches branch", Filter{Text: "login"}, []string{"api-fix"}},
+		{"repo by base name", Filter{Repo: "backend"}, []string{"api-fix", "Backend-feature"}},
+		{"repo by path", Filter{Repo: docsRepo}, []string{"docs"}},
+		{"branch glob", Filter{Branch: "fe*/*"}, []string{"Backend-feature"}},
+		{"status", Filter{Status: StatusNeedsInput}, []string{"Backend-feature"}},
+		{"badge color", Filter{Badge: "#f00"}, []string{"api-fix"}},
+		{"badge emoji", Filter{Badge: "📝"}, []string{"docs"}},
+		{"activity recency", Filter{ActiveWithinSeconds: 600}, []string{"api-fix", "Backend-feature"}},
+		{"combined", Filter{Repo: "Backend", Status: StatusRunning}, []string{"api-fix"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, total, err := Run(testItems(), tt.filter, Sort{}, Page{}, testNow)
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if !slices.Equal(names(got), tt.want) || total != len(tt.want) {
+				t.Fatalf("Run() = %v (total %d), want %v", names(got), total, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunSorts(t *testing.T) {
+	tests := []struct {
+		sort Sort
+		want []string
+	}{
+		{Sort{By: SortName}, []string{"api-fix", "Backend-feature", "docs"}},
+		{Sort{By: SortCreated}, []string{"api-fix", "Backend-feature", "docs"}},
+		{Sort{By: SortActivity, Descending: true}, []string{"api-fix", "Backend-feature", "docs"}},
+		{Sort{By: SortCreated, Descending: true}, []string{"docs", "Backend-feature", "api-fix"}},
+	}
+	for _, tt := range tests {
+		got, _, err := Run(testItems(), Filter{}, tt.sort, Page{}, testNow)
+		if err != nil {
+			t.Fatalf("Run(%+v) error = %v", tt.sort, err)
+		}
+		if !slices.Equal(names(got), tt.want) {
+			t.Fatalf("Run(%+v) = %v, want %v", tt.sort, names(got), tt.want)
+		}
+	}
+}
+
+func TestRunPages(t *testing.T) {
+	filter := Filter{IncludeDetached: true}
+	got, total, err := Run(testItems(), filter, Sort{}, Page{Offset: 1, Limit: 2}, testNow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 4 || !slices.Equal(names(got), []string{"docs", "Backend-feature"}) {
+		t.Fatalf("page = %v (total %d)", names(got), total)
+	}
+
+	got, total, err = Run(testItems(), filter, Sort{}, Page{Offset: 10}, testNow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 4 || len(got) != 0 {
+		t.Fatalf("page past end = %v (total %d), want empty", names(got), total)
+	}
+
+	many := make([]Item, MaxLimit+10)
+	got, _, err = Run(many, Filter{}, Sort{}, Page{Limit: MaxLimit + 10}, testNow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != MaxLimit {
+		t.Fatalf("len(page) = %d, want MaxLimit", len(got))
+	}
+	got, _, _ = Run(many, Filter{}, Sort{}, Page{}, testNow)
+	if len(got) != DefaultLimit {
+		t.Fatalf("len(page) = %d, want DefaultLimit", len(got))
+	}
+}
+
+func TestRunRejectsInvalidQuery(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter Filter
+		sort   Sort
+		page   Page
+	}{
+		{"status", Filter{Status: "busy"}, Sort{}, Page{}},
+		{"sort", Filter{}, Sort{By: "size"}, Page{}},
+		{"branch glob", Filter{Branch: "["}, Sort{}, Page{}},
+		{"recency", Filter{ActiveWithinSeconds: -1}, Sort{}, Page{}},
+		{"offset", Filter{}, Sort{}, Page{Offset: -1}},
+		{"limit", Filter{}, Sort{}, Page{Limit: -1}},
+	}
+	for _, tt := range tests {
+		if _, _, err := Run(testItems(), tt.filter, tt.sort, tt.page, testNow); err == nil {
+			t.Errorf("%s: Run() error = nil, want error", tt.name)
+		}
+	}
+}
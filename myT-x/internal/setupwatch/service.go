@@ -0,0 +1,395 @@
+// Package setupwatch re-runs worktree setup scripts when the dependency files
+// they install from change, e.g. `npm install` after package-lock.json
+// changes in an agent's worktree. The worktrees to watch are reconciled from
+// the live sessions periodically; file changes arrive through fsnotify and
+// are debounced per script.
+package setupwatch
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"myT-x/internal/apptypes"
+)
+
+const (
+	// DefaultDebounce is the quiet period after the last change of a watched
+	// file before its script runs, used when Deps.Debounce is zero.
+	DefaultDebounce = 2 * time.Second
+
+	// DefaultReconcileInterval is the period of Run, used when
+	// Deps.ReconcileInterval is zero.
+	DefaultReconcileInterval = 5 * time.Second
+
+	// RanEvent carries a RunResult after a watcher re-ran a script.
+	RanEvent = "worktree:setup-watch-ran"
+)
+
+// Target is the worktree of one session.
+type Target struct {
+	SessionName string
+	Dir         string
+}
+
+// Rule re-runs Script when one of Files (relative to the worktree) changes.
+type Rule struct {
+	Script string
+	Files  []string
+}
+
+// RunResult is the payload of RanEvent.
+type RunResult struct {
+	SessionName string `json:"sessionName"`
+	Dir         string `json:"dir"`
+	Script      string `json:"script"`
+	Success     bool   `json:"success"`
+	Error       string `json:"error,omitempty"`
+}
+
+// Deps holds external dependencies injected at construction time.
+type Deps struct {
+	// Targets returns the worktrees of the live sessions.
+	Targets func() []Target
+
+	// Rules returns the configured watchers.
+	Rules func() []Rule
+
+	// RunScript runs script in dir and returns its combined output.
+	RunScript func(ctx context.Context, dir, script string) ([]byte, error)
+
+	// SetupRunning reports whether the worktree's setup scripts are still
+	// running, e.g. the first install after creation. Watchers wait for them
+	// and take the files they leave behind as the baseline.
+	// Optional: defaults to never running.
+	SetupRunning func(dir string) bool
+
+	// Emitter sends runtime events to the frontend.
+	// Optional: defaults to a no-op emitter if nil.
+	Emitter apptypes.RuntimeEventEmitter
+
+	// Debounce is the quiet period before a script runs.
+	// Optional: defaults to DefaultDebounce.
+	Debounce time.Duration
+
+	// ReconcileInterval is the period of Run.
+	// Optional: defaults to DefaultReconcileInterval.
+	ReconcileInterval time.Duration
+}
+
+// job is one rule in one worktree.
+type job struct {
+	target Target
+	rule   Rule
+	// files are the absolute paths of rule.Files in target.Dir.
+	files []string
+
+	timer   *time.Timer
+	running bool
+	// digest is the content hash of files after the last run. Changes made
+	// while the script runs are taken as its own output (e.g. npm rewriting
+	// package-lock.json), so they do not trigger it again. Empty until the
+	// baseline is taken.
+	digest string
+}
+
+// Service watches the dependency files of every worktree session.
+//
+// Thread-safety is managed internally via mu. No external locking is required.
+type Service struct {
+	deps Deps
+
+	mu      sync.Mutex
+	ctx     context.Context
+	watcher *fsnotify.Watcher
+	// jobs is keyed by jobKey(dir, script).
+	jobs map[string]*job
+	// byFile maps a watched absolute file path to its job keys.
+	byFile map[string][]string
+	// dirs is the set of watched parent directories.
+	dirs map[string]struct{}
+}
+
+// NewService creates a setup watch service.
+// Panics if Targets, Rules, or RunScript is nil.
+func NewService(deps Deps) *Service {
+	if deps.Targets == nil {
+		panic("setupwatch.NewService: Targets must be non-nil")
+	}
+	if deps.Rules == nil {
+		panic("setupwatch.NewService: Rules must be non-nil")
+	}
+	if deps.RunScript == nil {
+		panic("setupwatch.NewService: RunScript must be non-nil")
+	}
+	if deps.SetupRunning == nil {
+		deps.SetupRunning = func(string) bool { return false }
+	}
+	if deps.Emitter == nil {
+		deps.Emitter = apptypes.NoopEmitter{}
+	}
+	if deps.Debounce <= 0 {
+		deps.Debounce = DefaultDebounce
+	}
+	if deps.ReconcileInterval <= 0 {
+		deps.ReconcileInterval = DefaultReconcileInterval
+	}
+	return &Service{
+		deps:   deps,
+		jobs:   map[string]*job{},
+		byFile: map[string][]string{},
+		dirs:   map[string]struct{}{},
+	}
+}
+
+// Run reconciles the watched worktrees every ReconcileInterval and re-runs
+// scripts on file changes until ctx is canceled.
+func (s *Service) Run(ctx context.Context) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		slog.Warn("[WARN-SETUP-WATCH] failed to create file watcher", "error", err)
+		return
+	}
+	s.mu.Lock()
+	s.ctx = ctx
+	s.watcher = watcher
+	s.mu.Unlock()
+	defer s.stop()
+
+	s.Reconcile()
+	ticker := time.NewTicker(s.deps.ReconcileInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.Reconcile()
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Has(fsnotify.Write) || event.Has(fsnotify.Create) || event.Has(fsnotify.Rename) {
+				s.handleChange(event.Name)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			slog.Debug("[DEBUG-SETUP-WATCH] watcher error", "error", err)
+		}
+	}
+}
+
+func (s *Service) stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, j := range s.jobs {
+		if j.timer != nil {
+			j.timer.Stop()
+		}
+	}
+	if err := s.watcher.Close(); err != nil {
+		slog.Debug("[DEBUG-SETUP-WATCH] failed to close watcher", "error", err)
+	}
+	s.watcher = nil
+	s.jobs = map[string]*job{}
+	s.byFile = map[string][]string{}
+	s.dirs = map[string]struct{}{}
+}
+
+// Reconcile starts watching the files of new worktrees and rules and stops
+// watching the ones that went away. It is a no-op while Run is not active.
+func (s *Service) Reconcile() {
+	targets := s.deps.Targets()
+	rules := s.deps.Rules()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.watcher == nil {
+		return
+	}
+
+	desired := map[string]*job{}
+	seenDirs := map[string]struct{}{}
+	for _, target := range targets {
+		dir := filepath.Clean(target.Dir)
+		if strings.TrimSpace(target.Dir) == "" {
+			continue
+		}
+		// Sessions sharing a worktree run each script once.
+		if _, dup := seenDirs[dir]; dup {
+			continue
+		}
+		seenDirs[dir] = struct{}{}
+		for _, rule := range rules {
+			key := jobKey(dir, rule.Script)
+			if _, dup := desired[key]; dup {
+				continue
+			}
+			files := make([]string, 0, len(rule.Files))
+			for _, file := range rule.Files {
+				files = append(files, filepath.Join(dir, file))
+			}
+			desired[key] = &job{target: Target{SessionName: target.SessionName, Dir: dir}, rule: rule, files: files}
+		}
+	}
+
+	for key, j := range s.jobs {
+		next, keep := desired[key]
+		if keep && slices.Equal(next.files, j.files) {
+			j.target.SessionName = next.target.SessionName
+			desired[key] = j
+			continue
+		}
+		if j.timer != nil {
+			j.timer.Stop()
+		}
+	}
+	for _, j := range desired {
+		if j.digest == "" && !s.deps.SetupRunning(j.target.Dir) {
+			// The current content is the baseline; setup_scripts cover the
+			// first install.
+			j.digest = digestFiles(j.files)
+		}
+	}
+	s.jobs = desired
+
+	byFile := map[string][]string{}
+	dirs := map[string]struct{}{}
+	for key, j := range s.jobs {
+		for _, file := range j.files {
+			byFile[file] = append(byFile[file], key)
+			dirs[filepath.Dir(file)] = struct{}{}
+		}
+	}
+	s.byFile = byFile
+	for dir := range s.dirs {
+		if _, ok := dirs[dir]; !ok {
+			_ = s.watcher.Remove(dir)
+			delete(s.dirs, dir)
+		}
+	}
+	for dir := range dirs {
+		if _, ok := s.dirs[dir]; ok {
+			continue
+		}
+		// A directory that does not exist yet (e.g. a package added later)
+		// is retried on the next reconcile.
+		if err := s.watcher.Add(dir); err != nil {
+			slog.Debug("[DEBUG-SETUP-WATCH] cannot watch directory", "dir", dir, "error", err)
+			continue
+		}
+		s.dirs[dir] = struct{}{}
+	}
+}
+
+// handleChange debounces the jobs watching path.
+func (s *Service) handleChange(path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, key := range s.byFile[filepath.Clean(path)] {
+		j := s.jobs[key]
+		if j == nil {
+			continue
+		}
+		if j.timer != nil {
+			j.timer.Stop()
+		}
+		j.timer = time.AfterFunc(s.deps.Debounce, func() { s.fire(key, j) })
+	}
+}
+
+// fire runs the job unless it is already running.
+func (s *Service) fire(key string, j *job) {
+	s.mu.Lock()
+	if s.jobs[key] != j || s.ctx == nil {
+		s.mu.Unlock()
+		return
+	}
+	if s.deps.SetupRunning(j.target.Dir) {
+		// Check again once the setup scripts are done; what they install is
+		// the new baseline.
+		j.digest = ""
+		j.timer = time.AfterFunc(s.deps.Debounce, func() { s.fire(key, j) })
+		s.mu.Unlock()
+		return
+	}
+	if j.running {
+		s.mu.Unlock()
+		return
+	}
+	j.running = true
+	ctx := s.ctx
+	s.mu.Unlock()
+
+	go s.runJob(ctx, j)
+}
+
+func (s *Service) runJob(ctx context.Context, j *job) {
+	s.mu.Lock()
+	lastDigest := j.digest
+	target := j.target
+	s.mu.Unlock()
+
+	digest := digestFiles(j.files)
+	if lastDigest != "" && digest != lastDigest && ctx.Err() == nil {
+		s.runScript(ctx, target, j.rule)
+		digest = digestFiles(j.files)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j.digest = digest
+	j.running = false
+}
+
+func (s *Service) runScript(ctx context.Context, target Target, rule Rule) {
+	slog.Info("[SETUP-WATCH] dependency files changed, re-running setup script",
+		"session", target.SessionName, "dir", target.Dir, "script", rule.Script)
+	output, err := s.deps.RunScript(ctx, target.Dir, rule.Script)
+	result := RunResult{
+		SessionName: target.SessionName,
+		Dir:         target.Dir,
+		Script:      rule.Script,
+		Success:     err == nil,
+	}
+	if err != nil {
+		result.Error = fmt.Sprintf("script %q failed: %v", rule.Script, err)
+		slog.Warn("[WARN-SETUP-WATCH] setup script failed",
+			"session", target.SessionName, "script", rule.Script, "error", err, "output", string(output))
+	}
+	s.deps.Emitter.Emit(RanEvent, result)
+}
+
+func jobKey(dir, script string) string {
+	return dir + "\x00" + script
+}
+
+// digestFiles hashes the contents of files; missing files hash as empty.
+func digestFiles(files []string) string {
+	hash := sha256.New()
+	for _, path := range files {
+		hash.Write([]byte(path))
+		hash.Write([]byte{0})
+		f, err := os.Open(path)
+		if err != nil {
+			hash.Write([]byte{1})
+			continue
+		}
+		_, _ = io.Copy(hash, f)
+		_ = f.Close()
+		hash.Write([]byte{0})
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil))
+}
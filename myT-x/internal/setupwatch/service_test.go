@@ -0,0 +1,207 @@
+package setupwatch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"myT-x/internal/apptypes"
+)
+
+type recordingEmitter struct {
+	mu      sync.Mutex
+	results []RunResult
+}
+
+func (e *recordingEmitter) Emit(name string, payload any) {
+	if name != RanEvent {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.results = append(e.results, payload.(RunResult))
+}
+
+func (e *recordingEmitter) EmitWithContext(_ context.Context, name string, payload any) {
+	e.Emit(name, payload)
+}
+
+func (e *recordingEmitter) count() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return len(e.results)
+}
+
+var _ apptypes.RuntimeEventEmitter = (*recordingEmitter)(nil)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func startService(t *testing.T, deps Deps) *Service {
+	t.Helper()
+	if deps.Debounce == 0 {
+		deps.Debounce = 20 * time.Millisecond
+	}
+	if deps.ReconcileInterval == 0 {
+		deps.ReconcileInterval = time.Hour
+	}
+	s := NewService(deps)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	waitFor(t, "the watcher to start", func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return len(s.dirs) > 0
+	})
+	return s
+}
+
+func TestServiceRerunsScriptOnChange(t *testing.T) {
+	dir := t.TempDir()
+	lock := filepath.Join(dir, "package-lock.json")
+	writeFile(t, lock, "v1")
+
+	var runs atomic.Int32
+	emitter := &recordingEmitter{}
+	startService(t, Deps{
+		Targets: func() []Target { return []Target{{SessionName: "agent", Dir: dir}, {SessionName: "shared", Dir: dir}} },
+		Rules:   func() []Rule { return []Rule{{Script: "npm install", Files: []string{"package-lock.json"}}} },
+		RunScript: func(_ context.Context, gotDir, script string) ([]byte, error) {
+			runs.Add(1)
+			if gotDir != dir || script != "npm install" {
+				t.Errorf("RunScript(%q, %q)", gotDir, script)
+			}
+			// The script rewrites the lock file; that must not re-trigger it.
+			writeFile(t, lock, "v2-normalized")
+			return nil, nil
+		},
+		Emitter: emitter,
+	})
+
+	// Unrelated files and unchanged content do not run the script.
+	writeFile(t, filepath.Join(dir, "README.md"), "docs")
+	writeFile(t, lock, "v1")
+	time.Sleep(100 * time.Millisecond)
+	if runs.Load() != 0 {
+		t.Fatalf("runs = %d, want 0 before the lock file changes", runs.Load())
+	}
+
+	writeFile(t, lock, "v2")
+	waitFor(t, "the script to run", func() bool { return emitter.count() == 1 })
+	time.Sleep(100 * time.Millisecond)
+	if runs.Load() != 1 {
+		t.Fatalf("runs = %d, want 1 for a shared worktree and a self-written lock file", runs.Load())
+	}
+	if got := emitter.results[0]; got.SessionName != "agent" || !got.Success {
+		t.Fatalf("result = %+v", got)
+	}
+}
+
+func TestServiceWaitsForSetupScripts(t *testing.T) {
+	dir := t.TempDir()
+	lock := filepath.Join(dir, "go.sum")
+	writeFile(t, lock, "v1")
+
+	var setupRunning atomic.Bool
+	setupRunning.Store(true)
+	var runs atomic.Int32
+	s := startService(t, Deps{
+		Targets:      func() []Target { return []Target{{SessionName: "agent", Dir: dir}} },
+		Rules:        func() []Rule { return []Rule{{Script: "go mod download", Files: []string{"go.sum"}}} },
+		RunScript:    func(context.Context, string, string) ([]byte, error) { runs.Add(1); return nil, nil },
+		SetupRunning: func(string) bool { return setupRunning.Load() },
+	})
+
+	// Changes made while the setup scripts run become the baseline.
+	writeFile(t, lock, "installed")
+	time.Sleep(100 * time.Millisecond)
+	setupRunning.Store(false)
+	waitFor(t, "the baseline", func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for _, j := range s.jobs {
+			return j.digest != "" && !j.running
+		}
+		return false
+	})
+	if runs.Load() != 0 {
+		t.Fatalf("runs = %d, want 0 for changes made by the setup scripts", runs.Load())
+	}
+
+	writeFile(t, lock, "updated")
+	waitFor(t, "the script to run", func() bool { return runs.Load() == 1 })
+}
+
+func TestServiceSerializesRunsPerScript(t *testing.T) {
+	dir := t.TempDir()
+	lock := filepath.Join(dir, "package-lock.json")
+	writeFile(t, lock, "v1")
+
+	release := make(chan struct{})
+	var runs, concurrent, maxConcurrent atomic.Int32
+	startService(t, Deps{
+		Targets: func() []Target { return []Target{{SessionName: "agent", Dir: dir}} },
+		Rules:   func() []Rule { return []Rule{{Script: "npm install", Files: []string{"package-lock.json"}}} },
+		RunScript: func(context.Context, string, string) ([]byte, error) {
+			if n := concurrent.Add(1); n > maxConcurrent.Load() {
+				maxConcurrent.Store(n)
+			}
+			if runs.Add(1) == 1 {
+				<-release
+			}
+			concurrent.Add(-1)
+			return nil, nil
+		},
+	})
+
+	writeFile(t, lock, "v2")
+	waitFor(t, "the first run", func() bool { return runs.Load() == 1 })
+	// Changes during the run do not start a second, concurrent run.
+	writeFile(t, lock, "v3")
+	time.Sleep(60 * time.Millisecond)
+	close(release)
+	waitFor(t, "the first run to finish", func() bool { return concurrent.Load() == 0 })
+	time.Sleep(60 * time.Millisecond)
+
+	writeFile(t, lock, "v4")
+	waitFor(t, "the next run", func() bool { return runs.Load() == 2 })
+	if maxConcurrent.Load() != 1 {
+		t.Fatalf("max concurrent runs = %d, want 1", maxConcurrent.Load())
+	}
+}
+
+func TestNewServicePanicsWithoutRequiredDeps(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("NewService(Deps{}) should panic")
+		}
+	}()
+	NewService(Deps{})
+}
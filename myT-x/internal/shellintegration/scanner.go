@@ -0,0 +1,202 @@
+// Package shellintegration detects shell integration markers (OSC 133, and
+// the VS Code OSC 633 variant) in pane output.
+//
+// Shells configured for shell integration wrap each prompt/command cycle in
+// markers:
+//
+//	ESC ] 133 ; A ST        prompt start
+//	ESC ] 133 ; B ST        prompt end (command input starts)
+//	ESC ] 133 ; C ST        command executed
+//	ESC ] 133 ; D ; <n> ST  command finished with exit status n
+//
+// ST is either BEL or ESC \.
+package shellintegration
+
+import (
+	"bytes"
+	"strconv"
+	"sync"
+)
+
+// MarkerKind identifies a shell integration marker.
+type MarkerKind string
+
+const (
+	MarkerPromptStart     MarkerKind = "prompt-start"
+	MarkerCommandStart    MarkerKind = "command-start"
+	MarkerCommandExecuted MarkerKind = "command-executed"
+	MarkerCommandFinished MarkerKind = "command-finished"
+)
+
+// Marker is one shell integration marker found in pane output.
+type Marker struct {
+	Kind MarkerKind
+	// ExitCode is the reported exit status of a MarkerCommandFinished marker.
+	// Only meaningful when HasExitCode is true; shells omit it when no
+	// command ran (e.g. an empty prompt line).
+	ExitCode    int
+	HasExitCode bool
+}
+
+// maxPayloadLength bounds how much of an OSC payload is kept. Markers of
+// interest are short; longer payloads (titles, hyperlinks, 633;E command
+// lines) are only tracked for termination.
+const maxPayloadLength = 32
+
+type scanState uint8
+
+const (
+	scanGround scanState = iota
+	scanEscape
+	scanOSC
+	scanOSCEscape
+)
+
+type paneScan struct {
+	state   scanState
+	payload []byte
+	// overflow is set when the payload exceeded maxPayloadLength.
+	overflow bool
+}
+
+// Scanner tracks OSC parser state per pane across output chunks.
+// The zero value is ready to use.
+type Scanner struct {
+	mu    sync.Mutex
+	panes map[string]*paneScan
+}
+
+// Scan feeds chunk for paneID and returns the markers it completed, in order.
+func (s *Scanner) Scan(paneID string, chunk []byte) []Marker {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	scan := s.panes[paneID]
+	if scan == nil {
+		// Fast path: nothing to do for chunks without ESC outside an OSC.
+		if bytes.IndexByte(chunk, 0x1b) < 0 {
+			return nil
+		}
+		scan = &paneScan{}
+	}
+
+	var markers []Marker
+	for _, c := range chunk {
+		switch scan.state {
+		case scanGround:
+			if c == 0x1b {
+				scan.state = scanEscape
+			}
+		case scanEscape:
+			switch c {
+			case ']':
+				scan.state = scanOSC
+				scan.payload = scan.payload[:0]
+				scan.overflow = false
+			case 0x1b:
+				// Stay in escape state for a repeated ESC.
+			default:
+				scan.state = scanGround
+			}
+		case scanOSC:
+			switch c {
+			case 0x07:
+				markers = scan.finish(markers)
+			case 0x1b:
+				scan.state = scanOSCEscape
+			default:
+				scan.appendPayload(c)
+			}
+		case scanOSCEscape:
+			switch c {
+			case '\\':
+				markers = scan.finish(markers)
+			case ']':
+				// ESC ] inside an OSC aborts it and starts a new one.
+				scan.state = scanOSC
+				scan.payload = scan.payload[:0]
+				scan.overflow = false
+			case 0x1b:
+				// Stay: the next byte decides.
+			default:
+				scan.state = scanGround
+			}
+		}
+	}
+
+	if scan.state == scanGround {
+		delete(s.panes, paneID)
+	} else {
+		if s.panes == nil {
+			s.panes = make(map[string]*paneScan)
+		}
+		s.panes[paneID] = scan
+	}
+	return markers
+}
+
+// Forget drops scanner state for removed panes.
+func (s *Scanner) Forget(paneIDs []string) {
+	if len(paneIDs) == 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, paneID := range paneIDs {
+		delete(s.panes, paneID)
+	}
+}
+
+func (p *paneScan) appendPayload(c byte) {
+	if len(p.payload) >= maxPayloadLength {
+		p.overflow = true
+		return
+	}
+	p.payload = append(p.payload, c)
+}
+
+func (p *paneScan) finish(markers []Marker) []Marker {
+	p.state = scanGround
+	if p.overflow {
+		return markers
+	}
+	if marker, ok := parseMarker(p.payload); ok {
+		markers = append(markers, marker)
+	}
+	return markers
+}
+
+// parseMarker parses an OSC payload such as "133;D;1" or "633;A".
+func parseMarker(payload []byte) (Marker, bool) {
+	code, rest, ok := bytes.Cut(payload, []byte{';'})
+	if !ok || (string(code) != "133" && string(code) != "633") || len(rest) == 0 {
+		return Marker{}, false
+	}
+	kindByte := rest[0]
+	params := rest[1:]
+	if len(params) > 0 && params[0] != ';' {
+		return Marker{}, false
+	}
+
+	var marker Marker
+	switch kindByte {
+	case 'A':
+		marker.Kind = MarkerPromptStart
+	case 'B':
+		marker.Kind = MarkerCommandStart
+	case 'C':
+		marker.Kind = MarkerCommandExecuted
+	case 'D':
+		marker.Kind = MarkerCommandFinished
+		if len(params) > 1 {
+			exitText, _, _ := bytes.Cut(params[1:], []byte{';'})
+			if exitCode, err := strconv.Atoi(string(exitText)); err == nil {
+				marker.ExitCode = exitCode
+				marker.HasExitCode = true
+			}
+		}
+	default:
+		return Marker{}, false
+	}
+	return marker, true
+}
@@ -0,0 +1,81 @@
+package shellintegration
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestScannerDetectsMarkers(t *testing.T) {
+	tests := []struct {
+		name   string
+		chunks []string
+		want   []Marker
+	}{
+		{
+			name:   "plain output",
+			chunks: []string{"hello\r\n"},
+		},
+		{
+			name:   "full cycle with BEL terminators",
+			chunks: []string{"\x1b]133;A\x07PS> \x1b]133;B\x07", "\x1b]133;C\x07out\r\n\x1b]133;D;0\x07"},
+			want: []Marker{
+				{Kind: MarkerPromptStart},
+				{Kind: MarkerCommandStart},
+				{Kind: MarkerCommandExecuted},
+				{Kind: MarkerCommandFinished, ExitCode: 0, HasExitCode: true},
+			},
+		},
+		{
+			name:   "ST terminator and VS Code variant",
+			chunks: []string{"\x1b]633;D;2\x1b\\", "\x1b]633;A;cl=m\x1b\\"},
+			want: []Marker{
+				{Kind: MarkerCommandFinished, ExitCode: 2, HasExitCode: true},
+				{Kind: MarkerPromptStart},
+			},
+		},
+		{
+			name:   "finished without exit code",
+			chunks: []string{"\x1b]133;D\x07"},
+			want:   []Marker{{Kind: MarkerCommandFinished}},
+		},
+		{
+			name:   "marker split across chunks",
+			chunks: []string{"\x1b]13", "3;D;", "127\x07"},
+			want:   []Marker{{Kind: MarkerCommandFinished, ExitCode: 127, HasExitCode: true}},
+		},
+		{
+			name:   "unrelated OSC sequences are ignored",
+			chunks: []string{"\x1b]0;title 133;D;1\x07\x1b]1337;X\x07\x1b]133;Z\x07"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var scanner Scanner
+			var got []Marker
+			for _, chunk := range tt.chunks {
+				got = append(got, scanner.Scan("%1", []byte(chunk))...)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("markers = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScannerTracksPanesIndependently(t *testing.T) {
+	var scanner Scanner
+	scanner.Scan("%1", []byte("\x1b]133;D;"))
+	if got := scanner.Scan("%2", []byte("1\x07")); len(got) != 0 {
+		t.Fatalf("pane %%2 markers = %+v, want none", got)
+	}
+	if got := scanner.Scan("%1", []byte("1\x07")); len(got) != 1 || got[0].ExitCode != 1 {
+		t.Fatalf("pane %%1 markers = %+v, want exit code 1", got)
+	}
+
+	scanner.Scan("%1", []byte("\x1b]133;D;"))
+	scanner.Forget([]string{"%1"})
+	if got := scanner.Scan("%1", []byte("1\x07")); len(got) != 0 {
+		t.Fatalf("markers after Forget = %+v, want none", got)
+	}
+}
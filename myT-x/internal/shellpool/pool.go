@@ -0,0 +1,355 @@
+// Package shellpool keeps pre-started shells ready so a new session can attach
+// to a warm shell instead of waiting for shell startup.
+//
+// A warm shell is started in the pool directory with the app environment.
+// Acquire moves it to the pane directory and applies the pane environment by
+// typing a prelude line, so only shells whose syntax the pool knows
+// (PowerShell and cmd) are pooled. The prelude is kept out of the shell
+// history so pane environments are not written to disk.
+package shellpool
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"myT-x/internal/procutil"
+)
+
+// defaultHealthInterval is how often Run checks warm shells and refills.
+const defaultHealthInterval = 30 * time.Second
+
+// Terminal is the part of *terminal.Terminal the pool uses.
+type Terminal interface {
+	Write(data []byte) (int, error)
+	Resize(cols, rows int) error
+	Close() error
+	IsClosed() bool
+	PID() int
+}
+
+// Spec describes the shells the pool keeps warm.
+type Spec struct {
+	// Shell is the shell executable; panes resolving to another shell
+	// bypass the pool.
+	Shell string
+	// Dir is the directory warm shells start in.
+	Dir string
+	// Size is the number of warm shells kept. 0 disables the pool.
+	Size int
+	// MaxIdle recycles a warm shell that has waited longer than this.
+	// 0 keeps warm shells until they are used or drained.
+	MaxIdle time.Duration
+}
+
+// Request describes the pane a warm shell is acquired for.
+type Request struct {
+	Shell string
+	Dir   string
+	// Env holds the pane-specific variables applied on top of the app
+	// environment the warm shell inherited.
+	Env     map[string]string
+	Columns int
+	Rows    int
+}
+
+// Stats is a point-in-time view of the pool.
+type Stats struct {
+	Shell    string `json:"shell"`
+	Size     int    `json:"size"`
+	Idle     int    `json:"idle"`
+	Starting int    `json:"starting"`
+	Hits     uint64 `json:"hits"`
+	Misses   uint64 `json:"misses"`
+}
+
+// Deps holds external dependencies injected at construction time.
+type Deps struct {
+	// Start launches shell with args in dir with the app environment.
+	Start func(shell string, args []string, dir string) (Terminal, error)
+
+	// Alive reports whether the process pid is still running.
+	// Optional: defaults to an OS process lookup.
+	Alive func(pid int) bool
+
+	// HealthInterval is how often Run checks warm shells.
+	// Optional: defaults to 30 seconds.
+	HealthInterval time.Duration
+
+	// Now returns the current time. Optional: defaults to time.Now.
+	Now func() time.Time
+}
+
+type warmShell struct {
+	term      Terminal
+	startedAt time.Time
+	// path is PATH at start; a shell started before a PATH change (e.g. the
+	// tmux shim install) would not find newly added commands.
+	path string
+}
+
+// Pool keeps warm shells for one Spec.
+//
+// Thread-safety is managed internally via mu. No external locking is required.
+// Terminals are started and written outside mu.
+type Pool struct {
+	deps Deps
+
+	mu sync.Mutex
+	// generation increments whenever the spec changes so shells started
+	// for an older spec are discarded when they come up.
+	generation uint64
+	spec       Spec
+	idle       []warmShell
+	starting   int
+	closed     bool
+	hits       uint64
+	misses     uint64
+}
+
+// NewPool creates an empty, disabled pool. Configure enables it.
+// Panics if Start is nil.
+func NewPool(deps Deps) *Pool {
+	if deps.Start == nil {
+		panic("shellpool.NewPool: Start must be non-nil")
+	}
+	if deps.Alive == nil {
+		deps.Alive = procutil.ProcessAlive
+	}
+	if deps.HealthInterval <= 0 {
+		deps.HealthInterval = defaultHealthInterval
+	}
+	if deps.Now == nil {
+		deps.Now = time.Now
+	}
+	return &Pool{deps: deps}
+}
+
+// Configure applies spec. Warm shells for a different shell or directory are
+// drained; the pool is then refilled in the background. A spec with Size 0
+// or an unsupported shell disables the pool.
+func (p *Pool) Configure(spec Spec) {
+	spec.Shell = strings.TrimSpace(spec.Shell)
+	spec.Size = max(spec.Size, 0)
+	spec.MaxIdle = max(spec.MaxIdle, 0)
+	if shellFamilyOf(spec.Shell) == familyUnsupported {
+		spec.Size = 0
+	}
+
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return
+	}
+	var drained []warmShell
+	if !strings.EqualFold(spec.Shell, p.spec.Shell) || spec.Dir != p.spec.Dir {
+		p.generation++
+		drained = p.idle
+		p.idle = nil
+	}
+	p.spec = spec
+	if excess := len(p.idle) - spec.Size; excess > 0 {
+		drained = append(drained, p.idle[:excess]...)
+		p.idle = p.idle[excess:]
+	}
+	p.refillLocked()
+	p.mu.Unlock()
+
+	if len(drained) > 0 {
+		slog.Debug("[DEBUG-SHELLPOOL] drained warm shells", "count", len(drained))
+	}
+	closeWarmShells(drained)
+}
+
+// Acquire returns a warm shell prepared for req, or false when none is
+// available and the caller must start a shell itself. The shell has been
+// resized and has the prelude typed into it; its buffered startup output is
+// left for the caller's read loop.
+func (p *Pool) Acquire(req Request) (Terminal, bool) {
+	p.mu.Lock()
+	if p.closed || p.spec.Size == 0 || !strings.EqualFold(strings.TrimSpace(req.Shell), p.spec.Shell) {
+		p.mu.Unlock()
+		return nil, false
+	}
+	prelude, ok := preludeLine(p.spec.Shell, req.Dir, req.Env)
+	if !ok {
+		p.misses++
+		p.mu.Unlock()
+		slog.Debug("[DEBUG-SHELLPOOL] pane cannot use a warm shell; starting a new one", "dir", req.Dir)
+		return nil, false
+	}
+	var (
+		picked *warmShell
+		stale  []warmShell
+	)
+	now := p.deps.Now()
+	path := os.Getenv("PATH")
+	for len(p.idle) > 0 {
+		candidate := p.idle[0]
+		p.idle = p.idle[1:]
+		if p.healthyLocked(candidate, now) && candidate.path == path {
+			picked = &candidate
+			break
+		}
+		stale = append(stale, candidate)
+	}
+	if picked == nil {
+		p.misses++
+	}
+	p.refillLocked()
+	p.mu.Unlock()
+
+	closeWarmShells(stale)
+	if picked == nil {
+		return nil, false
+	}
+	term := picked.term
+	if req.Columns > 0 && req.Rows > 0 {
+		if err := term.Resize(req.Columns, req.Rows); err != nil {
+			slog.Debug("[DEBUG-SHELLPOOL] resize of warm shell failed", "error", err)
+		}
+	}
+	if _, err := term.Write([]byte(prelude + "\r")); err != nil {
+		slog.Warn("[WARN-SHELLPOOL] failed to prepare warm shell; starting a new one", "error", err)
+		closeWarmShells([]warmShell{*picked})
+		p.mu.Lock()
+		p.misses++
+		p.mu.Unlock()
+		return nil, false
+	}
+	p.mu.Lock()
+	p.hits++
+	p.mu.Unlock()
+	return term, true
+}
+
+// Run checks warm shells every HealthInterval until ctx is cancelled,
+// replacing shells that exited or waited longer than MaxIdle.
+func (p *Pool) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.deps.HealthInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.CheckHealth()
+		}
+	}
+}
+
+// CheckHealth closes warm shells that exited or expired and refills the pool.
+func (p *Pool) CheckHealth() {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return
+	}
+	now := p.deps.Now()
+	var removed []warmShell
+	kept := p.idle[:0]
+	for _, shell := range p.idle {
+		if p.healthyLocked(shell, now) {
+			kept = append(kept, shell)
+			continue
+		}
+		removed = append(removed, shell)
+	}
+	clear(p.idle[len(kept):])
+	p.idle = kept
+	p.refillLocked()
+	p.mu.Unlock()
+
+	if len(removed) > 0 {
+		slog.Debug("[DEBUG-SHELLPOOL] recycled warm shells", "count", len(removed))
+	}
+	closeWarmShells(removed)
+}
+
+// Stats returns the current pool state.
+func (p *Pool) Stats() Stats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return Stats{
+		Shell:    p.spec.Shell,
+		Size:     p.spec.Size,
+		Idle:     len(p.idle),
+		Starting: p.starting,
+		Hits:     p.hits,
+		Misses:   p.misses,
+	}
+}
+
+// Close disables the pool and closes every warm shell. Shells still starting
+// are closed when they come up.
+func (p *Pool) Close() {
+	p.mu.Lock()
+	p.closed = true
+	p.generation++
+	drained := p.idle
+	p.idle = nil
+	p.mu.Unlock()
+	closeWarmShells(drained)
+}
+
+// healthyLocked reports whether shell can still be handed out.
+// Caller must hold p.mu.
+func (p *Pool) healthyLocked(shell warmShell, now time.Time) bool {
+	if shell.term.IsClosed() {
+		return false
+	}
+	if p.spec.MaxIdle > 0 && now.Sub(shell.startedAt) > p.spec.MaxIdle {
+		return false
+	}
+	pid := shell.term.PID()
+	return pid <= 0 || p.deps.Alive(pid)
+}
+
+// refillLocked starts shells until idle plus starting reaches the pool size.
+// Caller must hold p.mu.
+func (p *Pool) refillLocked() {
+	if p.closed {
+		return
+	}
+	for range p.spec.Size - len(p.idle) - p.starting {
+		p.starting++
+		go p.startWarmShell(p.generation, p.spec.Shell, p.spec.Dir)
+	}
+}
+
+func (p *Pool) startWarmShell(generation uint64, shell, dir string) {
+	path := os.Getenv("PATH")
+	term, err := p.deps.Start(shell, warmShellArgs(shell), dir)
+	if err == nil && term == nil {
+		err = errors.New("start returned no terminal")
+	}
+
+	p.mu.Lock()
+	p.starting--
+	if err != nil {
+		p.mu.Unlock()
+		// No retry here: the next Acquire or health check refills, which
+		// keeps a broken shell from spinning.
+		slog.Warn("[WARN-SHELLPOOL] failed to start warm shell", "shell", shell, "error", err)
+		return
+	}
+	if p.closed || generation != p.generation || len(p.idle) >= p.spec.Size {
+		p.mu.Unlock()
+		closeWarmShells([]warmShell{{term: term}})
+		return
+	}
+	p.idle = append(p.idle, warmShell{term: term, startedAt: p.deps.Now(), path: path})
+	p.mu.Unlock()
+}
+
+func closeWarmShells(shells []warmShell) {
+	for _, shell := range shells {
+		if err := shell.term.Close(); err != nil {
+			slog.Debug("[DEBUG-SHELLPOOL] failed to close warm shell", "error", err)
+		}
+	}
+}
@@ -0,0 +1,300 @@
+package shellpool
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeTerminal struct {
+	mu       sync.Mutex
+	pid      int
+	closed   bool
+	writes   []string
+	cols     int
+	rows     int
+	writeErr error
+}
+
+func (f *fakeTerminal) Write(data []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.writeErr != nil {
+		return 0, f.writeErr
+	}
+	f.writes = append(f.writes, string(data))
+	return len(data), nil
+}
+
+func (f *fakeTerminal) Resize(cols, rows int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.cols, f.rows = cols, rows
+	return nil
+}
+
+func (f *fakeTerminal) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.closed = true
+	return nil
+}
+
+func (f *fakeTerminal) IsClosed() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.closed
+}
+
+func (f *fakeTerminal) PID() int { return f.pid }
+
+type poolHarness struct {
+	mu      sync.Mutex
+	started []*fakeTerminal
+	dirs    []string
+	args    [][]string
+	dead    map[int]bool
+	now     time.Time
+	failErr error
+}
+
+func newHarness(t *testing.T) (*Pool, *poolHarness) {
+	t.Helper()
+	h := &poolHarness{dead: map[int]bool{}, now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	pool := NewPool(Deps{
+		Start: func(shell string, args []string, dir string) (Terminal, error) {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			if h.failErr != nil {
+				return nil, h.failErr
+			}
+			term := &fakeTerminal{pid: len(h.started) + 100}
+			h.started = append(h.started, term)
+			h.dirs = append(h.dirs, dir)
+			h.args = append(h.args, args)
+			return term, nil
+		},
+		Alive: func(pid int) bool {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			return !h.dead[pid]
+		},
+		Now: func() time.Time {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			return h.now
+		},
+	})
+	t.Cleanup(pool.Close)
+	return pool, h
+}
+
+func (h *poolHarness) terminals() []*fakeTerminal {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]*fakeTerminal(nil), h.started...)
+}
+
+// waitIdle waits until the pool holds want idle shells and nothing is starting.
+func waitIdle(t *testing.T, pool *Pool, want int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		stats := pool.Stats()
+		if stats.Idle == want && stats.Starting == 0 {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("pool stats = %+v, want %d idle", pool.Stats(), want)
+}
+
+func TestNewPoolPanicsWithoutStart(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("NewPool(Deps{}) did not panic")
+		}
+	}()
+	NewPool(Deps{})
+}
+
+func TestPoolFillsAndAcquires(t *testing.T) {
+	pool, h := newHarness(t)
+	pool.Configure(Spec{Shell: "powershell.exe", Dir: `C:\launch`, Size: 2})
+	waitIdle(t, pool, 2)
+
+	term, ok := pool.Acquire(Request{
+		Shell:   "PowerShell.exe",
+		Dir:     `C:\work`,
+		Env:     map[string]string{"MYTX_SESSION": "demo"},
+		Columns: 100,
+		Rows:    30,
+	})
+	if !ok {
+		t.Fatal("Acquire() = false, want a warm shell")
+	}
+	h.mu.Lock()
+	args := h.args[0]
+	h.mu.Unlock()
+	if len(args) != 4 || args[3] != powerShellPreludeReader {
+		t.Fatalf("warm shell args = %q, want the prelude reader command", args)
+	}
+	fake := term.(*fakeTerminal)
+	if fake.cols != 100 || fake.rows != 30 {
+		t.Fatalf("warm shell size = %dx%d, want 100x30", fake.cols, fake.rows)
+	}
+	want := `Set-Location -LiteralPath 'C:\work'; Set-Item -LiteralPath 'Env:MYTX_SESSION' -Value 'demo'; Clear-Host` + "\r"
+	if len(fake.writes) != 1 || fake.writes[0] != want {
+		t.Fatalf("writes = %q, want %q", fake.writes, want)
+	}
+
+	// The pool refills after handing out a shell.
+	waitIdle(t, pool, 2)
+	if got := len(h.terminals()); got != 3 {
+		t.Fatalf("started = %d, want 3", got)
+	}
+	if stats := pool.Stats(); stats.Hits != 1 || stats.Misses != 0 {
+		t.Fatalf("stats = %+v, want 1 hit", stats)
+	}
+}
+
+func TestPoolBypassesOtherShells(t *testing.T) {
+	pool, _ := newHarness(t)
+	pool.Configure(Spec{Shell: "powershell.exe", Size: 1})
+	waitIdle(t, pool, 1)
+
+	if _, ok := pool.Acquire(Request{Shell: "cmd.exe", Dir: `C:\work`}); ok {
+		t.Fatal("Acquire(cmd.exe) = true, want pool bypass for a different shell")
+	}
+	if _, ok := pool.Acquire(Request{Shell: "powershell.exe", Env: map[string]string{"X": "a\nb"}}); ok {
+		t.Fatal("Acquire(multi-line env) = true, want fresh shell")
+	}
+	if stats := pool.Stats(); stats.Idle != 1 || stats.Misses != 1 {
+		t.Fatalf("stats = %+v, want shell kept and one miss", stats)
+	}
+}
+
+func TestPoolDisabledForUnsupportedShell(t *testing.T) {
+	pool, h := newHarness(t)
+	pool.Configure(Spec{Shell: "wsl.exe", Size: 2})
+	if stats := pool.Stats(); stats.Size != 0 || stats.Starting != 0 {
+		t.Fatalf("stats = %+v, want disabled pool", stats)
+	}
+	if len(h.terminals()) != 0 {
+		t.Fatal("unsupported shell was pre-started")
+	}
+}
+
+func TestPoolDrainsOnSpecChange(t *testing.T) {
+	pool, h := newHarness(t)
+	pool.Configure(Spec{Shell: "powershell.exe", Dir: `C:\a`, Size: 2})
+	waitIdle(t, pool, 2)
+	old := h.terminals()
+
+	pool.Configure(Spec{Shell: "cmd.exe", Dir: `C:\a`, Size: 1})
+	waitIdle(t, pool, 1)
+	for i, term := range old {
+		if !term.IsClosed() {
+			t.Fatalf("old warm shell %d not closed after shell change", i)
+		}
+	}
+
+	// Shrinking keeps the current shells up to the new size.
+	pool.Configure(Spec{Shell: "cmd.exe", Dir: `C:\a`, Size: 0})
+	if stats := pool.Stats(); stats.Idle != 0 {
+		t.Fatalf("idle = %d after disabling, want 0", stats.Idle)
+	}
+	terms := h.terminals()
+	if !terms[len(terms)-1].IsClosed() {
+		t.Fatal("warm shell not closed after disabling the pool")
+	}
+}
+
+func TestPoolCheckHealthReplacesDeadAndExpiredShells(t *testing.T) {
+	pool, h := newHarness(t)
+	pool.Configure(Spec{Shell: "pwsh", Size: 2, MaxIdle: time.Minute})
+	waitIdle(t, pool, 2)
+	terms := h.terminals()
+
+	h.mu.Lock()
+	h.dead[terms[0].pid] = true
+	h.mu.Unlock()
+	pool.CheckHealth()
+	waitIdle(t, pool, 2)
+	if !terms[0].IsClosed() || terms[1].IsClosed() {
+		t.Fatal("CheckHealth did not replace only the dead shell")
+	}
+
+	h.mu.Lock()
+	h.now = h.now.Add(2 * time.Minute)
+	h.mu.Unlock()
+	pool.CheckHealth()
+	waitIdle(t, pool, 2)
+	if !terms[1].IsClosed() {
+		t.Fatal("expired warm shell was not recycled")
+	}
+	if got := len(h.terminals()); got != 5 {
+		t.Fatalf("started = %d, want 5", got)
+	}
+}
+
+func TestPoolAcquireSkipsClosedShell(t *testing.T) {
+	pool, h := newHarness(t)
+	pool.Configure(Spec{Shell: "cmd.exe", Size: 2})
+	waitIdle(t, pool, 2)
+	terms := h.terminals()
+	_ = terms[0].Close()
+
+	term, ok := pool.Acquire(Request{Shell: "cmd.exe", Dir: `C:\work`})
+	if !ok || term != Terminal(terms[1]) {
+		t.Fatalf("Acquire() = %v, %v; want the live shell", term, ok)
+	}
+}
+
+func TestPoolAcquireWriteFailure(t *testing.T) {
+	pool, h := newHarness(t)
+	pool.Configure(Spec{Shell: "cmd.exe", Size: 1})
+	waitIdle(t, pool, 1)
+	terms := h.terminals()
+	terms[0].writeErr = errors.New("broken pipe")
+
+	if _, ok := pool.Acquire(Request{Shell: "cmd.exe"}); ok {
+		t.Fatal("Acquire() = true after write failure")
+	}
+	if !terms[0].IsClosed() {
+		t.Fatal("warm shell not closed after write failure")
+	}
+}
+
+func TestPoolStartFailureDoesNotSpin(t *testing.T) {
+	pool, h := newHarness(t)
+	h.failErr = errors.New("no shell")
+	pool.Configure(Spec{Shell: "cmd.exe", Size: 2})
+	waitIdle(t, pool, 0)
+
+	h.mu.Lock()
+	h.failErr = nil
+	h.mu.Unlock()
+	pool.CheckHealth()
+	waitIdle(t, pool, 2)
+}
+
+func TestPoolCloseClosesShells(t *testing.T) {
+	pool, h := newHarness(t)
+	pool.Configure(Spec{Shell: "cmd.exe", Size: 2})
+	waitIdle(t, pool, 2)
+	pool.Close()
+	for i, term := range h.terminals() {
+		if !term.IsClosed() {
+			t.Fatalf("warm shell %d not closed by Close", i)
+		}
+	}
+	if _, ok := pool.Acquire(Request{Shell: "cmd.exe"}); ok {
+		t.Fatal("Acquire() after Close = true")
+	}
+	pool.Configure(Spec{Shell: "cmd.exe", Size: 2})
+	if stats := pool.Stats(); stats.Starting != 0 || stats.Idle != 0 {
+		t.Fatalf("stats after Close = %+v, want no refill", stats)
+	}
+}
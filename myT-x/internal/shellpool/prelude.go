@@ -0,0 +1,136 @@
+package shellpool
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// maxPreludeLen caps the prelude line. cmd.exe rejects lines over 8191
+// characters; longer preludes start a fresh shell instead.
+const maxPreludeLen = 4096
+
+type shellFamily int
+
+const (
+	familyUnsupported shellFamily = iota
+	familyPowerShell
+	familyCmd
+)
+
+// shellFamilyOf classifies shell by executable name, matching the detection
+// used for startup commands.
+func shellFamilyOf(shell string) shellFamily {
+	switch strings.ToLower(filepath.Base(strings.ReplaceAll(shell, `\`, "/"))) {
+	case "powershell.exe", "powershell", "pwsh.exe", "pwsh":
+		return familyPowerShell
+	case "cmd.exe", "cmd":
+		return familyCmd
+	default:
+		return familyUnsupported
+	}
+}
+
+// powerShellPreludeReader is the -Command a warm PowerShell starts with. It
+// reads the prelude with [Console]::ReadLine instead of PSReadLine, so the
+// pane's directory and environment never reach the PSReadLine history file,
+// and then runs it in the global scope before the interactive prompt starts.
+const powerShellPreludeReader = ". ([scriptblock]::Create([Console]::ReadLine()))"
+
+// warmShellArgs returns the arguments a warm shell is started with.
+func warmShellArgs(shell string) []string {
+	if shellFamilyOf(shell) == familyPowerShell {
+		return []string{"-NoLogo", "-NoExit", "-Command", powerShellPreludeReader}
+	}
+	return nil
+}
+
+// preludeLine returns the line that moves a warm shell to dir, sets env, and
+// clears the screen. It returns false when a value cannot be typed safely,
+// in which case the pane starts a fresh shell.
+//
+// The line must stay out of the shell history: PowerShell reads it outside
+// the line editor (see powerShellPreludeReader), and cmd, whose doskey
+// history only lives in the console, drops its history after applying it.
+func preludeLine(shell, dir string, env map[string]string) (string, bool) {
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	var parts []string
+	switch shellFamilyOf(shell) {
+	case familyPowerShell:
+		if dir != "" {
+			if !typeable(dir) {
+				return "", false
+			}
+			parts = append(parts, "Set-Location -LiteralPath "+powerShellQuote(dir))
+		}
+		for _, key := range keys {
+			if !typeable(key) || !typeable(env[key]) {
+				return "", false
+			}
+			parts = append(parts, "Set-Item -LiteralPath "+powerShellQuote("Env:"+key)+" -Value "+powerShellQuote(env[key]))
+		}
+		parts = append(parts, "Clear-Host")
+		return joinPrelude(parts, "; ")
+	case familyCmd:
+		if dir != "" {
+			if !cmdSafe(dir) {
+				return "", false
+			}
+			parts = append(parts, `cd /d "`+dir+`"`)
+		}
+		for _, key := range keys {
+			if !cmdSafe(key) || !cmdSafe(env[key]) {
+				return "", false
+			}
+			// The quotes around KEY=VALUE keep & | < > literal.
+			parts = append(parts, `set "`+key+"="+env[key]+`"`)
+		}
+		// The console added this line to the doskey history when cmd read it.
+		parts = append(parts, "doskey /reinstall", "cls")
+		return joinPrelude(parts, " & ")
+	default:
+		return "", false
+	}
+}
+
+func joinPrelude(parts []string, sep string) (string, bool) {
+	line := strings.Join(parts, sep)
+	if len(line) > maxPreludeLen {
+		return "", false
+	}
+	return line, true
+}
+
+// typeable reports whether value contains no control characters, which the
+// shell line editor would interpret instead of inserting.
+func typeable(value string) bool {
+	return !strings.ContainsFunc(value, func(r rune) bool { return r < 0x20 || r == 0x7f })
+}
+
+// cmdSafe reports whether value can be typed inside cmd.exe double quotes:
+// quotes would end the quoting and % or ! would expand variables.
+func cmdSafe(value string) bool {
+	return typeable(value) && !strings.ContainsAny(value, `"%!`)
+}
+
+// powerShellQuote single-quotes value. PowerShell treats the typographic
+// single quotes as quote characters too, so every kind is doubled.
+func powerShellQuote(value string) string {
+	var b strings.Builder
+	b.Grow(len(value) + 2)
+	b.WriteByte('\'')
+	for _, r := range value {
+		switch r {
+		case '\'', '‘', '’', '‚', '‛':
+			b.WriteRune(r)
+		}
+		b.WriteRune(r)
+	}
+	b.WriteByte('\'')
+	return b.String()
+}
@@ -0,0 +1,80 @@
+package shellpool
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestPreludeLine(t *testing.T) {
+	tests := []struct {
+		name   string
+		shell  string
+		dir    string
+		env    map[string]string
+		want   string
+		wantOK bool
+	}{
+		{
+			name:   "powershell quotes values",
+			shell:  `C:\Program Files\PowerShell\7\pwsh.exe`,
+			dir:    `C:\it's here`,
+			env:    map[string]string{"B": "x’y", "A": "1"},
+			want:   `Set-Location -LiteralPath 'C:\it''s here'; Set-Item -LiteralPath 'Env:A' -Value '1'; Set-Item -LiteralPath 'Env:B' -Value 'x’’y'; Clear-Host`,
+			wantOK: true,
+		},
+		{
+			name:   "powershell without dir",
+			shell:  "powershell.exe",
+			want:   "Clear-Host",
+			wantOK: true,
+		},
+		{
+			name:   "cmd keeps metacharacters quoted",
+			shell:  "CMD.EXE",
+			dir:    `C:\work`,
+			env:    map[string]string{"K": "a&b|c"},
+			want:   `cd /d "C:\work" & set "K=a&b|c" & doskey /reinstall & cls`,
+			wantOK: true,
+		},
+		{name: "cmd rejects percent", shell: "cmd.exe", env: map[string]string{"K": "%PATH%"}},
+		{name: "cmd rejects quotes", shell: "cmd.exe", dir: `C:\"x`},
+		{name: "control characters rejected", shell: "pwsh", env: map[string]string{"K": "a\x1bb"}},
+		{name: "unsupported shell", shell: "bash"},
+		{name: "too long", shell: "pwsh", env: map[string]string{"K": strings.Repeat("x", maxPreludeLen)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := preludeLine(tt.shell, tt.dir, tt.env)
+			if ok != tt.wantOK || got != tt.want {
+				t.Fatalf("preludeLine() = %q, %v; want %q, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestShellFamilyOf(t *testing.T) {
+	tests := map[string]shellFamily{
+		"powershell.exe":              familyPowerShell,
+		`C:\Windows\System32\cmd.exe`: familyCmd,
+		"pwsh":                        familyPowerShell,
+		"wsl.exe":                     familyUnsupported,
+		"":                            familyUnsupported,
+	}
+	for shell, want := range tests {
+		if got := shellFamilyOf(shell); got != want {
+			t.Errorf("shellFamilyOf(%q) = %v, want %v", shell, got, want)
+		}
+	}
+}
+
+func TestWarmShellArgsReadPowerShellPreludeOutsideLineEditor(t *testing.T) {
+	got := warmShellArgs(`C:\Program Files\PowerShell\7\pwsh.exe`)
+	want := []string{"-NoLogo", "-NoExit", "-Command", powerShellPreludeReader}
+	if !slices.Equal(got, want) {
+		t.Fatalf("warmShellArgs(pwsh) = %q, want %q", got, want)
+	}
+	if got := warmShellArgs("cmd.exe"); got != nil {
+		t.Fatalf("warmShellArgs(cmd) = %q, want nil", got)
+	}
+}
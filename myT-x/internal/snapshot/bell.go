@@ -0,0 +1,101 @@
+package snapshot
+
+import "sync"
+
+type bellScanState uint8
+
+const (
+	bellScanGround bellScanState = iota
+	// bellScanEscape follows an ESC outside an OSC sequence.
+	bellScanEscape
+	// bellScanOSC is inside ESC ] ... (operating system command).
+	bellScanOSC
+	// bellScanOSCEscape follows an ESC inside an OSC sequence (possible ST).
+	bellScanOSCEscape
+)
+
+// bellScanner detects terminal bells (BEL, 0x07) in pane output.
+//
+// BEL also terminates OSC sequences such as window-title updates, which
+// shells and ConPTY emit on every prompt. The scanner therefore tracks OSC
+// state per pane across flushed chunks and reports only BELs outside an OSC
+// sequence. The zero value is ready to use.
+type bellScanner struct {
+	mu    sync.Mutex
+	state map[string]bellScanState
+}
+
+// Scan feeds chunk for paneID and reports whether it contained a bell.
+func (b *bellScanner) Scan(paneID string, chunk []byte) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	state := b.state[paneID]
+	bell := false
+	for _, c := range chunk {
+		switch state {
+		case bellScanGround:
+			if c == 0x1b {
+				state = bellScanEscape
+			} else if c == 0x07 {
+				bell = true
+			}
+		case bellScanEscape:
+			switch c {
+			case ']':
+				state = bellScanOSC
+			case 0x1b:
+				// Stay in escape state for a repeated ESC.
+			case 0x07:
+				state = bellScanGround
+				bell = true
+			default:
+				state = bellScanGround
+			}
+		case bellScanOSC:
+			if c == 0x07 {
+				state = bellScanGround
+			} else if c == 0x1b {
+				state = bellScanOSCEscape
+			}
+		case bellScanOSCEscape:
+			switch c {
+			case '\\':
+				state = bellScanGround
+			case 0x1b:
+				// Stay: the next byte decides.
+			case 0x07:
+				state = bellScanGround
+			default:
+				// ESC without backslash aborts the OSC and starts a new escape.
+				if c == ']' {
+					state = bellScanOSC
+				} else {
+					state = bellScanGround
+				}
+			}
+		}
+	}
+
+	if state == bellScanGround {
+		delete(b.state, paneID)
+	} else {
+		if b.state == nil {
+			b.state = make(map[string]bellScanState)
+		}
+		b.state[paneID] = state
+	}
+	return bell
+}
+
+// Forget drops scanner state for removed panes.
+func (b *bellScanner) Forget(paneIDs []string) {
+	if len(paneIDs) == 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, paneID := range paneIDs {
+		delete(b.state, paneID)
+	}
+}
@@ -0,0 +1,41 @@
+package snapshot
+
+import "testing"
+
+func TestBellScannerScan(t *testing.T) {
+	tests := []struct {
+		name   string
+		chunks []string
+		want   []bool
+	}{
+		{"plain bell", []string{"done\a"}, []bool{true}},
+		{"no bell", []string{"hello\r\n"}, []bool{false}},
+		{"osc title terminated by BEL", []string{"\x1b]0;title\a$ "}, []bool{false}},
+		{"osc title terminated by ST", []string{"\x1b]0;title\x1b\\\a"}, []bool{true}},
+		{"bell after osc", []string{"\x1b]2;t\a\a"}, []bool{true}},
+		{"osc split across chunks", []string{"\x1b]0;ti", "tle\a", "\a"}, []bool{false, false, true}},
+		{"csi is not osc", []string{"\x1b[31m\a"}, []bool{true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var scanner bellScanner
+			for i, chunk := range tt.chunks {
+				if got := scanner.Scan("%1", []byte(chunk)); got != tt.want[i] {
+					t.Fatalf("Scan(chunk %d %q) = %v, want %v", i, chunk, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestBellScannerStateIsPerPane(t *testing.T) {
+	var scanner bellScanner
+	scanner.Scan("%1", []byte("\x1b]0;open"))
+	if !scanner.Scan("%2", []byte("\a")) {
+		t.Fatal("bell in %2 suppressed by open OSC in %1")
+	}
+	scanner.Forget([]string{"%1"})
+	if !scanner.Scan("%1", []byte("\a")) {
+		t.Fatal("bell after Forget should be reported")
+	}
+}
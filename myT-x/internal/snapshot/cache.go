@@ -9,6 +9,7 @@ package snapshot
 //   - ClearSnapshotRequestTimer cleans up the debounce timer.
 
 import (
+	"context"
 	"log/slog"
 	"time"
 
@@ -43,17 +44,34 @@ func (s *Service) emitSnapshot() {
 	if s.shouldSyncPaneStates(s.deps.TopologyGeneration()) {
 		s.syncPaneStates(snapshots)
 	}
-	delta, changed, initial := s.snapshotDelta(snapshots)
+	delta, base, changed, initial := s.snapshotDeltaWithBase(snapshots)
 	if initial {
-		s.deps.Emitter.EmitWithContext(ctx, "tmux:snapshot", snapshots)
-		s.recordSnapshotEmission("full", snapshots)
+		s.emitEncoded(ctx, "tmux:snapshot", "full", snapshots)
 		return
 	}
 	if !changed {
 		return
 	}
-	s.deps.Emitter.EmitWithContext(ctx, "tmux:snapshot-delta", delta)
-	s.recordSnapshotEmission("delta", delta)
+	if s.compactDeltas.Load() {
+		s.emitEncoded(ctx, "tmux:snapshot-delta", "delta", compactDelta(delta, base))
+		return
+	}
+	s.emitEncoded(ctx, "tmux:snapshot-delta", "delta", delta)
+}
+
+// emitEncoded emits payload, wrapped in a gzip envelope when negotiated and
+// large enough, and records the emitted payload in the metrics.
+func (s *Service) emitEncoded(ctx context.Context, eventName, kind string, payload any) {
+	if s.gzipPayloads.Load() {
+		encoded, ok, err := encodeGzipPayload(payload)
+		if err != nil {
+			slog.Warn("[snapshot] failed to compress payload, sending uncompressed", "event", eventName, "error", err)
+		} else if ok {
+			payload = encoded
+		}
+	}
+	s.deps.Emitter.EmitWithContext(ctx, eventName, payload)
+	s.recordSnapshotEmission(kind, payload)
 }
 
 // shouldSyncPaneStates tracks the last synced topology generation and returns
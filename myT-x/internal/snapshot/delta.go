@@ -33,6 +33,12 @@ func sessionSnapshotEqual(left, right tmux.SessionSnapshot) bool {
 	if left.IsAgentTeam != right.IsAgentTeam {
 		return false
 	}
+	if left.Detached != right.Detached {
+		return false
+	}
+	if !sessionBadgeEqual(left.Badge, right.Badge) {
+		return false
+	}
 	if len(left.Windows) != len(right.Windows) {
 		return false
 	}
@@ -47,6 +53,12 @@ func sessionSnapshotEqual(left, right tmux.SessionSnapshot) bool {
 	if left.RootPath != right.RootPath {
 		return false
 	}
+	if left.TaskbarAlertsMuted != right.TaskbarAlertsMuted {
+		return false
+	}
+	if left.Group != right.Group {
+		return false
+	}
 	return true
 }
 
@@ -75,6 +87,18 @@ func sessionWorktreeInfoEqual(left, right *tmux.SessionWorktreeInfo) bool {
 	return true
 }
 
+// sessionBadgeEqual compares two SessionBadge pointers field-by-field.
+// IMPORTANT: update this function when fields are added/removed from SessionBadge.
+// TestSnapshotFieldCounts guards against forgetting this via reflection-based field count checks.
+func sessionBadgeEqual(left, right *tmux.SessionBadge) bool {
+	if left == nil || right == nil {
+		return left == right
+	}
+	return left.Color == right.Color &&
+		left.Emoji == right.Emoji &&
+		left.Auto == right.Auto
+}
+
 // windowSnapshotEqual compares two WindowSnapshot values field-by-field.
 // IMPORTANT: update this function when fields are added/removed from WindowSnapshot.
 // TestSnapshotFieldCounts guards against forgetting this via reflection-based field count checks.
@@ -124,6 +148,15 @@ func paneSnapshotEqual(left, right tmux.PaneSnapshot) bool {
 	if left.Height != right.Height {
 		return false
 	}
+	if left.Collapsed != right.Collapsed {
+		return false
+	}
+	if left.Mouse != right.Mouse {
+		return false
+	}
+	if left.TerminalModes != right.TerminalModes {
+		return false
+	}
 	return true
 }
 
@@ -158,6 +191,14 @@ func layoutSnapshotEqual(left, right *tmux.LayoutNode) bool {
 // Lock ordering: snapshotDeltaMu -> snapshotMu (outer -> inner).
 // snapshotDeltaMu serializes concurrent emit paths; snapshotMu guards the cache.
 func (s *Service) snapshotDelta(snapshots []tmux.SessionSnapshot) (tmux.SessionSnapshotDelta, bool, bool) {
+	delta, _, changed, initial := s.snapshotDeltaWithBase(snapshots)
+	return delta, changed, initial
+}
+
+// snapshotDeltaWithBase is snapshotDelta that also returns the previously
+// cached snapshot of every upserted session that existed before, keyed by
+// name, for compactDelta.
+func (s *Service) snapshotDeltaWithBase(snapshots []tmux.SessionSnapshot) (tmux.SessionSnapshotDelta, map[string]tmux.SessionSnapshot, bool, bool) {
 	s.snapshotDeltaMu.Lock()
 	defer s.snapshotDeltaMu.Unlock()
 
@@ -175,7 +216,7 @@ func (s *Service) snapshotDelta(snapshots []tmux.SessionSnapshot) (tmux.SessionS
 		}
 		s.snapshotPrimed = true
 		s.snapshotMu.Unlock()
-		return tmux.SessionSnapshotDelta{}, false, true
+		return tmux.SessionSnapshotDelta{}, nil, false, true
 	}
 	// NOTE: snapshotDelta intentionally computes outside snapshotMu to avoid
 	// holding the cache lock across full snapshot comparison on the hot path.
@@ -189,6 +230,8 @@ func (s *Service) snapshotDelta(snapshots []tmux.SessionSnapshot) (tmux.SessionS
 		Removed: make([]string, 0),
 	}
 
+	base := make(map[string]tmux.SessionSnapshot)
+
 	// Build a lightweight name set for removal detection.
 	currentNames := make(map[string]struct{}, len(snapshots))
 	for _, snapshot := range snapshots {
@@ -197,6 +240,9 @@ func (s *Service) snapshotDelta(snapshots []tmux.SessionSnapshot) (tmux.SessionS
 		if ok && sessionSnapshotEqual(prev, snapshot) {
 			continue
 		}
+		if ok {
+			base[snapshot.Name] = prev
+		}
 		delta.Upserts = append(delta.Upserts, snapshot)
 	}
 
@@ -235,12 +281,12 @@ func (s *Service) snapshotDelta(snapshots []tmux.SessionSnapshot) (tmux.SessionS
 		}
 		s.snapshotPrimed = true
 		s.snapshotMu.Unlock()
-		return tmux.SessionSnapshotDelta{}, false, true
+		return tmux.SessionSnapshotDelta{}, nil, false, true
 	}
 	s.snapshotCache = previous
 	s.snapshotMu.Unlock()
 
-	return delta, len(delta.Upserts) > 0 || len(delta.Removed) > 0, false
+	return delta, base, len(delta.Upserts) > 0 || len(delta.Removed) > 0, false
 }
 
 // copySnapshotCache creates a shallow copy of the snapshot cache map using
@@ -140,12 +140,13 @@ func TestSnapshotFieldCounts(t *testing.T) {
 		typ        reflect.Type
 		wantFields int
 	}{
-		{"TmuxSession", reflect.TypeFor[tmux.TmuxSession](), 14},
-		{"SessionSnapshot", reflect.TypeFor[tmux.SessionSnapshot](), 9},
+		{"TmuxSession", reflect.TypeFor[tmux.TmuxSession](), 22},
+		{"SessionSnapshot", reflect.TypeFor[tmux.SessionSnapshot](), 13},
+		{"SessionBadge", reflect.TypeFor[tmux.SessionBadge](), 3},
 		{"SessionWorktreeInfo", reflect.TypeFor[tmux.SessionWorktreeInfo](), 5},
-		{"PaneContextSnapshot", reflect.TypeFor[tmux.PaneContextSnapshot](), 9},
+		{"PaneContextSnapshot", reflect.TypeFor[tmux.PaneContextSnapshot](), 10},
 		{"WindowSnapshot", reflect.TypeFor[tmux.WindowSnapshot](), 5},
-		{"PaneSnapshot", reflect.TypeFor[tmux.PaneSnapshot](), 6},
+		{"PaneSnapshot", reflect.TypeFor[tmux.PaneSnapshot](), 9},
 		{"LayoutNode", reflect.TypeFor[tmux.LayoutNode](), 5},
 	}
 	for _, tt := range tests {
@@ -269,6 +270,19 @@ func TestSnapshotDeltaDetectsRootPathChange(t *testing.T) {
 	}
 }
 
+func TestSnapshotDeltaDetectsGroupChange(t *testing.T) {
+	svc := newTestService(t)
+
+	svc.snapshotDelta([]tmux.SessionSnapshot{testSnapshot("s1", 1, false)})
+
+	modified := testSnapshot("s1", 1, false)
+	modified.Group = "shop"
+	_, changed, _ := svc.snapshotDelta([]tmux.SessionSnapshot{modified})
+	if !changed {
+		t.Error("Group change should be detected")
+	}
+}
+
 func TestSnapshotDeltaDetectsWorktreeChange(t *testing.T) {
 	svc := newTestService(t)
 
@@ -0,0 +1,171 @@
+package snapshot
+
+// encoding.go — Negotiated wire encodings for snapshot events.
+//
+// Large workspaces (50+ panes) resend every pane and layout tree of a session
+// whenever any field of that session changes. Two opt-in encodings reduce the
+// bytes crossing the Wails bridge:
+//
+//   - compact-delta: delta upserts carry only the ID of panes and windows whose
+//     snapshot is unchanged since the previous emission; the frontend hydrates
+//     them from its store and falls back to ListSessions when it cannot.
+//   - gzip: payloads larger than gzipThresholdBytes are sent as a base64 gzip
+//     envelope, decoded with the browser's native DecompressionStream.
+//
+// Both stay disabled until the frontend negotiates them, so payloads remain
+// plain JSON for consumers that do not understand them.
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"myT-x/internal/tmux"
+)
+
+const (
+	// EncodingCompactDelta sends unchanged panes and layouts by reference in deltas.
+	EncodingCompactDelta = "compact-delta"
+	// EncodingGzip wraps large payloads in a gzip envelope.
+	EncodingGzip = "gzip"
+
+	// gzipEnvelopeEncoding identifies the envelope format on the wire.
+	gzipEnvelopeEncoding = "gzip-json"
+	// gzipThresholdBytes is the marshaled size below which compression is
+	// skipped: small deltas gain little and base64 adds a third back.
+	gzipThresholdBytes = 4 * 1024
+)
+
+// EncodedPayload is a snapshot event payload compressed for the bridge.
+// Data is base64(gzip(JSON of the original payload)).
+type EncodedPayload struct {
+	Encoding string `json:"encoding"`
+	Data     string `json:"data"`
+}
+
+// compactSessionDelta mirrors tmux.SessionSnapshotDelta with compacted upserts.
+type compactSessionDelta struct {
+	Upserts []compactSession `json:"upserts"`
+	Removed []string         `json:"removed"`
+}
+
+// compactSession shadows Windows of the embedded snapshot; encoding/json
+// prefers the shallower field.
+type compactSession struct {
+	tmux.SessionSnapshot
+	Windows []compactWindow `json:"windows"`
+}
+
+// compactWindow carries either the full layout or LayoutUnchanged, and panes
+// as tmux.PaneSnapshot or paneRef values.
+type compactWindow struct {
+	tmux.WindowSnapshot
+	Layout          *tmux.LayoutNode `json:"layout"`
+	LayoutUnchanged bool             `json:"layout_unchanged,omitempty"`
+	Panes           []any            `json:"panes"`
+}
+
+// paneRef stands in for a pane whose snapshot did not change.
+type paneRef struct {
+	ID        string `json:"id"`
+	Unchanged bool   `json:"unchanged"`
+}
+
+// NegotiateEncoding enables the encodings in accepted that the service
+// supports and returns them. Encodings not listed are disabled, so an empty
+// list restores plain JSON payloads.
+func (s *Service) NegotiateEncoding(accepted []string) []string {
+	compact := slices.Contains(accepted, EncodingCompactDelta)
+	gzipped := slices.Contains(accepted, EncodingGzip)
+	s.compactDeltas.Store(compact)
+	s.gzipPayloads.Store(gzipped)
+
+	enabled := make([]string, 0, 2)
+	if compact {
+		enabled = append(enabled, EncodingCompactDelta)
+	}
+	if gzipped {
+		enabled = append(enabled, EncodingGzip)
+	}
+	return enabled
+}
+
+// compactDelta replaces panes and layouts that equal their counterpart in base
+// (the previously emitted snapshot of the same session) with references.
+// Sessions absent from base are sent in full.
+func compactDelta(delta tmux.SessionSnapshotDelta, base map[string]tmux.SessionSnapshot) compactSessionDelta {
+	out := compactSessionDelta{
+		Upserts: make([]compactSession, 0, len(delta.Upserts)),
+		Removed: delta.Removed,
+	}
+	for _, session := range delta.Upserts {
+		prev, hasPrev := base[session.Name]
+		prevWindows := make(map[int]tmux.WindowSnapshot, len(prev.Windows))
+		prevPanes := make(map[string]tmux.PaneSnapshot)
+		if hasPrev {
+			for _, window := range prev.Windows {
+				prevWindows[window.ID] = window
+				for _, pane := range window.Panes {
+					prevPanes[pane.ID] = pane
+				}
+			}
+		}
+
+		compact := compactSession{
+			SessionSnapshot: session,
+			Windows:         make([]compactWindow, 0, len(session.Windows)),
+		}
+		for _, window := range session.Windows {
+			cw := compactWindow{
+				WindowSnapshot: window,
+				Layout:         window.Layout,
+				Panes:          make([]any, 0, len(window.Panes)),
+			}
+			if prevWindow, ok := prevWindows[window.ID]; ok && window.Layout != nil &&
+				layoutSnapshotEqual(prevWindow.Layout, window.Layout) {
+				cw.Layout = nil
+				cw.LayoutUnchanged = true
+			}
+			for _, pane := range window.Panes {
+				if prevPane, ok := prevPanes[pane.ID]; ok && paneSnapshotEqual(prevPane, pane) {
+					cw.Panes = append(cw.Panes, paneRef{ID: pane.ID, Unchanged: true})
+					continue
+				}
+				cw.Panes = append(cw.Panes, pane)
+			}
+			compact.Windows = append(compact.Windows, cw)
+		}
+		out.Upserts = append(out.Upserts, compact)
+	}
+	return out
+}
+
+// encodeGzipPayload compresses payload into an EncodedPayload. ok is false when
+// the marshaled payload is below gzipThresholdBytes and should be sent as is.
+func encodeGzipPayload(payload any) (EncodedPayload, bool, error) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return EncodedPayload{}, false, fmt.Errorf("marshal snapshot payload: %w", err)
+	}
+	if len(raw) < gzipThresholdBytes {
+		return EncodedPayload{}, false, nil
+	}
+	var buf bytes.Buffer
+	zw, err := gzip.NewWriterLevel(&buf, gzip.BestSpeed)
+	if err != nil {
+		return EncodedPayload{}, false, fmt.Errorf("create gzip writer: %w", err)
+	}
+	if _, err := zw.Write(raw); err != nil {
+		return EncodedPayload{}, false, fmt.Errorf("compress snapshot payload: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return EncodedPayload{}, false, fmt.Errorf("compress snapshot payload: %w", err)
+	}
+	return EncodedPayload{
+		Encoding: gzipEnvelopeEncoding,
+		Data:     base64.StdEncoding.EncodeToString(buf.Bytes()),
+	}, true, nil
+}
@@ -0,0 +1,221 @@
+package snapshot
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+
+	"myT-x/internal/tmux"
+)
+
+// largeWorkspace returns a session with panes panes split across windows of
+// four panes each, every window with a layout tree.
+func largeWorkspace(name string, panes int, idle bool) tmux.SessionSnapshot {
+	session := tmux.SessionSnapshot{Name: name, ID: 1, IsIdle: idle, RootPath: `C:\work\repo`}
+	for w := 0; w*4 < panes; w++ {
+		window := tmux.WindowSnapshot{ID: w, Name: fmt.Sprintf("win%d", w)}
+		var leaves []*tmux.LayoutNode
+		for p := w * 4; p < min(panes, w*4+4); p++ {
+			window.Panes = append(window.Panes, tmux.PaneSnapshot{
+				ID: fmt.Sprintf("%%%d", p), Index: p - w*4, Title: "agent " + fmt.Sprint(p), Width: 120, Height: 40,
+			})
+			leaves = append(leaves, &tmux.LayoutNode{Type: tmux.LayoutLeaf, PaneID: p})
+		}
+		root := leaves[0]
+		for _, leaf := range leaves[1:] {
+			root = &tmux.LayoutNode{Type: tmux.LayoutSplit, Direction: tmux.SplitHorizontal, Ratio: 0.5, Children: [2]*tmux.LayoutNode{root, leaf}}
+		}
+		window.Layout = root
+		session.Windows = append(session.Windows, window)
+	}
+	return session
+}
+
+func decodeEnvelope(t *testing.T, payload EncodedPayload) []byte {
+	t.Helper()
+	if payload.Encoding != gzipEnvelopeEncoding {
+		t.Fatalf("Encoding = %q, want %q", payload.Encoding, gzipEnvelopeEncoding)
+	}
+	compressed, err := base64.StdEncoding.DecodeString(payload.Data)
+	if err != nil {
+		t.Fatalf("base64 decode: %v", err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		t.Fatalf("gzip reader: %v", err)
+	}
+	raw, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("gzip read: %v", err)
+	}
+	return raw
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	svc := newTestService(t)
+	got := svc.NegotiateEncoding([]string{"msgpack", EncodingGzip, EncodingCompactDelta})
+	if want := []string{EncodingCompactDelta, EncodingGzip}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("NegotiateEncoding() = %v, want %v", got, want)
+	}
+	if !svc.compactDeltas.Load() || !svc.gzipPayloads.Load() {
+		t.Fatal("negotiated encodings were not enabled")
+	}
+	if got := svc.NegotiateEncoding(nil); len(got) != 0 || svc.compactDeltas.Load() || svc.gzipPayloads.Load() {
+		t.Fatalf("NegotiateEncoding(nil) = %v, want all encodings disabled", got)
+	}
+}
+
+func TestCompactDeltaReferencesUnchangedPanesAndLayouts(t *testing.T) {
+	prev := largeWorkspace("s1", 8, false)
+	next := largeWorkspace("s1", 8, true)
+	next.Windows[1].Panes[2].Title = "renamed"
+	next.Windows[1].Layout = &tmux.LayoutNode{Type: tmux.LayoutLeaf, PaneID: 4}
+	added := largeWorkspace("s2", 2, false)
+
+	got := compactDelta(
+		tmux.SessionSnapshotDelta{Upserts: []tmux.SessionSnapshot{next, added}, Removed: []string{"gone"}},
+		map[string]tmux.SessionSnapshot{"s1": prev},
+	)
+
+	if len(got.Upserts) != 2 || !reflect.DeepEqual(got.Removed, []string{"gone"}) {
+		t.Fatalf("compactDelta() = %+v", got)
+	}
+	s1 := got.Upserts[0]
+	if !s1.IsIdle || len(s1.Windows) != 2 {
+		t.Fatalf("compact session = %+v", s1)
+	}
+	if !s1.Windows[0].LayoutUnchanged || s1.Windows[0].Layout != nil {
+		t.Error("unchanged layout of window 0 should be sent by reference")
+	}
+	if s1.Windows[1].LayoutUnchanged || s1.Windows[1].Layout == nil {
+		t.Error("changed layout of window 1 should be sent in full")
+	}
+	for i, pane := range s1.Windows[0].Panes {
+		if ref, ok := pane.(paneRef); !ok || ref.ID != prev.Windows[0].Panes[i].ID {
+			t.Errorf("window 0 pane %d = %#v, want paneRef", i, pane)
+		}
+	}
+	if pane, ok := s1.Windows[1].Panes[2].(tmux.PaneSnapshot); !ok || pane.Title != "renamed" {
+		t.Errorf("changed pane = %#v, want full PaneSnapshot", s1.Windows[1].Panes[2])
+	}
+	for _, pane := range got.Upserts[1].Windows[0].Panes {
+		if _, ok := pane.(tmux.PaneSnapshot); !ok {
+			t.Errorf("new session pane = %#v, want full PaneSnapshot", pane)
+		}
+	}
+
+	// The wire format keeps the session fields and shadows windows/layout/panes.
+	raw, err := json.Marshal(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wire struct {
+		Upserts []struct {
+			Name    string `json:"name"`
+			Windows []struct {
+				Layout          *tmux.LayoutNode  `json:"layout"`
+				LayoutUnchanged bool              `json:"layout_unchanged"`
+				Panes           []json.RawMessage `json:"panes"`
+			} `json:"windows"`
+		} `json:"upserts"`
+	}
+	if err := json.Unmarshal(raw, &wire); err != nil {
+		t.Fatal(err)
+	}
+	if wire.Upserts[0].Name != "s1" || !wire.Upserts[0].Windows[0].LayoutUnchanged || wire.Upserts[0].Windows[0].Layout != nil {
+		t.Fatalf("wire session = %s", raw)
+	}
+	if string(wire.Upserts[0].Windows[0].Panes[0]) != `{"id":"%0","unchanged":true}` {
+		t.Fatalf("wire pane ref = %s", wire.Upserts[0].Windows[0].Panes[0])
+	}
+}
+
+func TestCompactDeltaShrinksLargeWorkspaceDelta(t *testing.T) {
+	prev := largeWorkspace("s1", 60, false)
+	next := largeWorkspace("s1", 60, true)
+	delta := tmux.SessionSnapshotDelta{Upserts: []tmux.SessionSnapshot{next}, Removed: []string{}}
+
+	full, err := json.Marshal(delta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	compact, err := json.Marshal(compactDelta(delta, map[string]tmux.SessionSnapshot{"s1": prev}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(compact)*3 > len(full) {
+		t.Fatalf("compact delta = %d bytes, full delta = %d bytes; want at least 3x smaller", len(compact), len(full))
+	}
+
+	estimate := PayloadSizeBytes(compactDelta(delta, map[string]tmux.SessionSnapshot{"s1": prev}))
+	if estimate < len(compact)/2 || estimate > len(compact)*2 {
+		t.Fatalf("PayloadSizeBytes(compact) = %d, marshaled = %d", estimate, len(compact))
+	}
+}
+
+func TestEncodeGzipPayload(t *testing.T) {
+	if _, ok, err := encodeGzipPayload(tmux.SessionSnapshotDelta{Removed: []string{"s1"}}); ok || err != nil {
+		t.Fatalf("small payload: ok = %v, err = %v; want sent uncompressed", ok, err)
+	}
+
+	snapshots := []tmux.SessionSnapshot{largeWorkspace("s1", 60, false)}
+	encoded, ok, err := encodeGzipPayload(snapshots)
+	if !ok || err != nil {
+		t.Fatalf("large payload: ok = %v, err = %v", ok, err)
+	}
+	raw := decodeEnvelope(t, encoded)
+	want, _ := json.Marshal(snapshots)
+	if !bytes.Equal(raw, want) {
+		t.Fatal("decoded envelope does not match the original payload")
+	}
+	if PayloadSizeBytes(encoded) >= len(want) {
+		t.Fatalf("envelope = %d bytes, raw = %d bytes; want smaller", PayloadSizeBytes(encoded), len(want))
+	}
+}
+
+func TestEmitSnapshotUsesNegotiatedEncodings(t *testing.T) {
+	rec := &recordingEmitter{}
+	current := []tmux.SessionSnapshot{largeWorkspace("s1", 60, false)}
+
+	d := validDeps()
+	d.Emitter = rec
+	d.SessionSnapshot = func() []tmux.SessionSnapshot { return current }
+	svc := NewService(d)
+	t.Cleanup(func() { svc.Shutdown() })
+
+	svc.emitSnapshot() // priming (plain full snapshot)
+	svc.NegotiateEncoding([]string{EncodingCompactDelta})
+	current = []tmux.SessionSnapshot{largeWorkspace("s1", 60, true)}
+	svc.emitSnapshot()
+	svc.NegotiateEncoding([]string{EncodingCompactDelta, EncodingGzip})
+	current = []tmux.SessionSnapshot{largeWorkspace("s1", 60, false), largeWorkspace("s2", 60, false)}
+	svc.emitSnapshot()
+
+	evts := rec.events()
+	if len(evts) != 3 {
+		t.Fatalf("expected 3 emissions, got %d", len(evts))
+	}
+	if _, ok := evts[0].payload.([]tmux.SessionSnapshot); !ok {
+		t.Errorf("priming payload = %T, want []tmux.SessionSnapshot", evts[0].payload)
+	}
+	if _, ok := evts[1].payload.(compactSessionDelta); !ok {
+		t.Errorf("compact delta payload = %T, want compactSessionDelta", evts[1].payload)
+	}
+	encoded, ok := evts[2].payload.(EncodedPayload)
+	if !ok {
+		t.Fatalf("gzip delta payload = %T, want EncodedPayload", evts[2].payload)
+	}
+	var decoded struct {
+		Upserts []struct {
+			Name string `json:"name"`
+		} `json:"upserts"`
+	}
+	if err := json.Unmarshal(decodeEnvelope(t, encoded), &decoded); err != nil || len(decoded.Upserts) != 2 {
+		t.Fatalf("decoded delta = %+v, err = %v", decoded, err)
+	}
+}
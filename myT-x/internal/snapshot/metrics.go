@@ -51,6 +51,11 @@ func PayloadSizeBytes(payload any) int {
 			return 0
 		}
 		return estimateSessionSnapshotDeltaSize(*data)
+	case compactSessionDelta:
+		return estimateCompactSessionDeltaSize(data)
+	case EncodedPayload:
+		// {"encoding":"...","data":"..."}
+		return 22 + estimateStringSize(data.Encoding) + estimateStringSize(data.Data)
 	default:
 		slog.Warn("[snapshot-metrics] PayloadSizeBytes: unsupported payload type, returning 0",
 			"type", fmt.Sprintf("%T", payload))
@@ -67,6 +72,48 @@ func estimateSessionSnapshotDeltaSize(delta tmux.SessionSnapshotDelta) int {
 	return size
 }
 
+func estimateCompactSessionDeltaSize(delta compactSessionDelta) int {
+	// {"upserts":[...],"removed":[...]}
+	size := 22
+	size += 2 + max(len(delta.Upserts)-1, 0)
+	for _, session := range delta.Upserts {
+		header := session.SessionSnapshot
+		header.Windows = nil
+		// Replace the empty windows list of the header with the compact windows.
+		size += estimateSessionSnapshotSize(header) - 2
+		size += 2 + max(len(session.Windows)-1, 0)
+		for _, window := range session.Windows {
+			size += estimateCompactWindowSize(window)
+		}
+	}
+	size += 2 // comma separating upserts and removed arrays
+	size += estimateStringListSize(delta.Removed)
+	return size
+}
+
+func estimateCompactWindowSize(window compactWindow) int {
+	header := window.WindowSnapshot
+	header.Layout = window.Layout
+	header.Panes = nil
+	// Replace the empty panes list of the header with the compact panes.
+	size := estimateWindowSnapshotSize(header) - 2
+	if window.LayoutUnchanged {
+		// ,"layout_unchanged":true
+		size += 24
+	}
+	size += 2 + max(len(window.Panes)-1, 0)
+	for _, pane := range window.Panes {
+		switch p := pane.(type) {
+		case tmux.PaneSnapshot:
+			size += estimatePaneSnapshotSize(p)
+		case paneRef:
+			// {"id":"...","unchanged":true}
+			size += 24 + estimateStringSize(p.ID)
+		}
+	}
+	return size
+}
+
 func estimateSessionSnapshotListSize(snapshots []tmux.SessionSnapshot) int {
 	if len(snapshots) == 0 {
 		return 2
@@ -80,15 +127,17 @@ func estimateSessionSnapshotListSize(snapshots []tmux.SessionSnapshot) int {
 
 func estimateSessionSnapshotSize(snapshot tmux.SessionSnapshot) int {
 	// Fixed overhead = sum of JSON key/punctuation bytes for all SessionSnapshot fields:
-	//   {"id":,"name":"","created_at":"","is_idle":,"active_window_id":,"is_agent_team":,"windows":,"worktree":,"root_path":""}
-	// Counted as: 2 (braces) + 9 field keys with quotes/colons/commas + string-value quotes = 103 bytes.
-	size := 103
+	//   {"id":,"name":"","created_at":"","is_idle":,"active_window_id":,"is_agent_team":,"detached":,"badge":,"windows":,"worktree":,"root_path":""}
+	// Counted as: 2 (braces) + 11 field keys with quotes/colons/commas + string-value quotes = 124 bytes.
+	size := 124
 	size += estimateIntSize(snapshot.ID)
 	size += estimateStringSize(snapshot.Name)
 	size += estimateStringSize(snapshot.CreatedAt.Format(time.RFC3339Nano))
 	size += estimateBoolSize(snapshot.IsIdle)
 	size += estimateIntSize(snapshot.ActiveWindowID)
 	size += estimateBoolSize(snapshot.IsAgentTeam)
+	size += estimateBoolSize(snapshot.Detached)
+	size += estimateSessionBadgeSize(snapshot.Badge)
 	size += estimateWindowSnapshotListSize(snapshot.Windows)
 	size += estimateSessionWorktreeInfoSize(snapshot.Worktree)
 	size += estimateStringSize(snapshot.RootPath)
@@ -109,6 +158,18 @@ func estimateSessionWorktreeInfoSize(worktree *tmux.SessionWorktreeInfo) int {
 	return size
 }
 
+func estimateSessionBadgeSize(badge *tmux.SessionBadge) int {
+	if badge == nil {
+		return 0
+	}
+	// {"color":"...","emoji":"...","auto":...}
+	size := 31
+	size += estimateStringSize(badge.Color)
+	size += estimateStringSize(badge.Emoji)
+	size += estimateBoolSize(badge.Auto)
+	return size
+}
+
 func estimateWindowSnapshotListSize(windows []tmux.WindowSnapshot) int {
 	if len(windows) == 0 {
 		return 2
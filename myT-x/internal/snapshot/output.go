@@ -131,6 +131,16 @@ func (s *Service) ensureOutputFlusher() *terminal.OutputFlushManager {
 		if s.deps.UpdateActivityByPaneID(paneID) {
 			s.RequestSnapshot(false)
 		}
+		if s.bells.Scan(paneID, flushed) {
+			s.deps.OnPaneBell(paneID)
+		}
+		if markers := s.shellMarkers.Scan(paneID, flushed); len(markers) > 0 {
+			s.deps.OnShellMarkers(paneID, markers)
+		}
+		if notifications := s.paneNotifications.Scan(paneID, flushed); len(notifications) > 0 {
+			s.deps.OnPaneNotifications(paneID, notifications)
+		}
+		s.deps.OnPaneOutput(paneID, flushed)
 		// Delivery strategy (WebSocket vs IPC) is encapsulated in the dep closure.
 		s.deps.DeliverPaneOutput(ctx, paneID, flushed)
 	})
@@ -151,6 +161,9 @@ func (s *Service) DetachAllOutputBuffers() []string {
 	}
 	removed := flusher.RetainPanes(nil)
 	flusher.Stop()
+	s.bells.Forget(removed)
+	s.shellMarkers.Forget(removed)
+	s.paneNotifications.Forget(removed)
 	return removed
 }
 
@@ -165,7 +178,11 @@ func (s *Service) DetachStaleOutputBuffers(existingPanes map[string]struct{}) []
 	if s.outputFlusher == nil {
 		return nil
 	}
-	return s.outputFlusher.RetainPanes(existingPanes)
+	removed := s.outputFlusher.RetainPanes(existingPanes)
+	s.bells.Forget(removed)
+	s.shellMarkers.Forget(removed)
+	s.paneNotifications.Forget(removed)
+	return removed
 }
 
 // CleanupDetachedPaneStates removes corresponding pane state entries.
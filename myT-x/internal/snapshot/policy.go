@@ -31,6 +31,7 @@ var snapshotEventPolicies = map[string]snapshotEventPolicy{
 	"tmux:session-destroyed": {trigger: true, bypassDebounce: true},
 	"tmux:session-emptied":   {trigger: true, bypassDebounce: true},
 	"tmux:session-renamed":   {trigger: true, bypassDebounce: true},
+	"tmux:session-attached":  {trigger: true, bypassDebounce: true},
 	"tmux:pane-created":      {trigger: true, bypassDebounce: false},
 	"tmux:layout-changed":    {trigger: true, bypassDebounce: false},
 	"tmux:pane-focused":      {trigger: true, bypassDebounce: true},
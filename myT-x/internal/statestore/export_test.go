@@ -0,0 +1,72 @@
+package statestore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExportJSONEmbedsJSONValues(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	if err := store.Put(ctx, BucketSessionBadges, "repo", []byte(`{"color":"#ff0000"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Append(ctx, "history", []byte("git status")); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := ExportJSON(ctx, store, &buf); err != nil {
+		t.Fatalf("ExportJSON() error = %v", err)
+	}
+	var doc struct {
+		SchemaVersion int `json:"schema_version"`
+		Records       []struct {
+			Value map[string]string `json:"value"`
+		} `json:"records"`
+		Logs []struct {
+			Value string `json:"value"`
+		} `json:"logs"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("export is not valid JSON: %v\n%s", err, buf.String())
+	}
+	if doc.SchemaVersion != SchemaVersion || len(doc.Records) != 1 || doc.Records[0].Value["color"] != "#ff0000" {
+		t.Fatalf("records = %+v", doc)
+	}
+	if len(doc.Logs) != 1 || doc.Logs[0].Value != "git status" {
+		t.Fatalf("logs = %+v, want plain text value", doc.Logs)
+	}
+}
+
+func TestImportLegacyFile(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	path := filepath.Join(t.TempDir(), "legacy.json")
+
+	imported, err := ImportLegacyFile(ctx, store, "b", "k", path)
+	if err != nil || imported {
+		t.Fatalf("ImportLegacyFile(missing) = %v, %v, want false, nil", imported, err)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"a":1}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	imported, err = ImportLegacyFile(ctx, store, "b", "k", path)
+	if err != nil || !imported {
+		t.Fatalf("ImportLegacyFile() = %v, %v, want true, nil", imported, err)
+	}
+	if got, _ := store.Get(ctx, "b", "k"); string(got) != `{"a":1}` {
+		t.Fatalf("Get() = %q, want imported contents", got)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("legacy file still present: %v", err)
+	}
+	if _, err := os.Stat(path + legacySuffix); err != nil {
+		t.Fatalf("retired legacy file missing: %v", err)
+	}
+}
@@ -0,0 +1,32 @@
+package statestore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// legacySuffix is appended to a legacy file once its contents are imported.
+// The file is kept rather than deleted so a downgrade can still find it.
+const legacySuffix = ".migrated"
+
+// ImportLegacyFile moves the contents of a legacy state file at path into
+// bucket/key and renames the file with a ".migrated" suffix. It reports
+// whether a file was imported; a missing file is not an error.
+func ImportLegacyFile(ctx context.Context, store Store, bucket, key, path string) (bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, fmt.Errorf("read legacy state file: %w", err)
+	}
+	if err := store.Put(ctx, bucket, key, data); err != nil {
+		return false, err
+	}
+	if err := os.Rename(path, path+legacySuffix); err != nil {
+		return true, fmt.Errorf("retire legacy state file: %w", err)
+	}
+	return true, nil
+}
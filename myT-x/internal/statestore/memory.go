@@ -0,0 +1,211 @@
+package statestore
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryStore is a Store that keeps all state in memory.
+type MemoryStore struct {
+	now func() time.Time
+
+	mu      sync.Mutex
+	closed  bool
+	records map[string]map[string]Record // bucket -> key -> record
+	logs    []LogEntry
+	nextID  int64
+}
+
+// NewMemoryStore creates an empty in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		now:     time.Now,
+		records: make(map[string]map[string]Record),
+	}
+}
+
+// Get implements Store.
+func (m *MemoryStore) Get(_ context.Context, bucket, key string) ([]byte, error) {
+	if err := validateRecordKey(bucket, key); err != nil {
+		return nil, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return nil, ErrClosed
+	}
+	record, ok := m.records[bucket][key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return slices.Clone(record.Value), nil
+}
+
+// Put implements Store.
+func (m *MemoryStore) Put(_ context.Context, bucket, key string, value []byte) error {
+	if err := validateRecordKey(bucket, key); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return ErrClosed
+	}
+	if m.records[bucket] == nil {
+		m.records[bucket] = make(map[string]Record)
+	}
+	m.records[bucket][key] = Record{Bucket: bucket, Key: key, Value: slices.Clone(value), UpdatedAt: m.now().UTC()}
+	return nil
+}
+
+// Delete implements Store.
+func (m *MemoryStore) Delete(_ context.Context, bucket, key string) error {
+	if err := validateRecordKey(bucket, key); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return ErrClosed
+	}
+	delete(m.records[bucket], key)
+	return nil
+}
+
+// List implements Store.
+func (m *MemoryStore) List(_ context.Context, bucket string) ([]Record, error) {
+	if err := validateName("bucket", bucket); err != nil {
+		return nil, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return nil, ErrClosed
+	}
+	return m.sortedRecordsLocked(bucket), nil
+}
+
+func (m *MemoryStore) sortedRecordsLocked(bucket string) []Record {
+	records := make([]Record, 0, len(m.records[bucket]))
+	for _, record := range m.records[bucket] {
+		record.Value = slices.Clone(record.Value)
+		records = append(records, record)
+	}
+	slices.SortFunc(records, func(a, b Record) int { return strings.Compare(a.Key, b.Key) })
+	return records
+}
+
+// Append implements Store.
+func (m *MemoryStore) Append(_ context.Context, stream string, value []byte) (int64, error) {
+	if err := validateName("stream", stream); err != nil {
+		return 0, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return 0, ErrClosed
+	}
+	m.nextID++
+	m.logs = append(m.logs, LogEntry{ID: m.nextID, Stream: stream, Value: slices.Clone(value), CreatedAt: m.now().UTC()})
+	return m.nextID, nil
+}
+
+// ReadLog implements Store.
+func (m *MemoryStore) ReadLog(_ context.Context, stream string, afterID int64, limit int) ([]LogEntry, error) {
+	if err := validateName("stream", stream); err != nil {
+		return nil, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return nil, ErrClosed
+	}
+	entries := []LogEntry{}
+	for _, entry := range m.logs {
+		if entry.Stream != stream || entry.ID <= afterID {
+			continue
+		}
+		entry.Value = slices.Clone(entry.Value)
+		entries = append(entries, entry)
+		if limit > 0 && len(entries) == limit {
+			break
+		}
+	}
+	return entries, nil
+}
+
+// TrimLog implements Store.
+func (m *MemoryStore) TrimLog(_ context.Context, stream string, keep int) (int, error) {
+	if err := validateName("stream", stream); err != nil {
+		return 0, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return 0, ErrClosed
+	}
+	total := 0
+	for _, entry := range m.logs {
+		if entry.Stream == stream {
+			total++
+		}
+	}
+	excess := total - max(keep, 0)
+	if excess <= 0 {
+		return 0, nil
+	}
+	deleted := 0
+	m.logs = slices.DeleteFunc(m.logs, func(entry LogEntry) bool {
+		if entry.Stream != stream || deleted == excess {
+			return false
+		}
+		deleted++
+		return true
+	})
+	return deleted, nil
+}
+
+// Check implements Store. Memory state cannot be corrupted on disk.
+func (m *MemoryStore) Check(context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return ErrClosed
+	}
+	return nil
+}
+
+// Dump implements Store.
+func (m *MemoryStore) Dump(context.Context) (Dump, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return Dump{}, ErrClosed
+	}
+	buckets := make([]string, 0, len(m.records))
+	for bucket := range m.records {
+		buckets = append(buckets, bucket)
+	}
+	slices.Sort(buckets)
+
+	dump := Dump{SchemaVersion: SchemaVersion, Records: []Record{}, Logs: []LogEntry{}}
+	for _, bucket := range buckets {
+		dump.Records = append(dump.Records, m.sortedRecordsLocked(bucket)...)
+	}
+	for _, entry := range m.logs {
+		entry.Value = slices.Clone(entry.Value)
+		dump.Logs = append(dump.Logs, entry)
+	}
+	return dump, nil
+}
+
+// Close implements Store.
+func (m *MemoryStore) Close() error {
+	m.mu.Lock()
+	m.closed = true
+	m.mu.Unlock()
+	return nil
+}
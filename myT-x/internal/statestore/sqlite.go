@@ -0,0 +1,376 @@
+package statestore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// migrations are applied in order; migrations[i] upgrades the schema from
+// version i to i+1 and runs in one transaction. Never edit a released
+// migration, append a new one.
+var migrations = [][]string{
+	{
+		`CREATE TABLE records (
+			bucket     TEXT NOT NULL,
+			key        TEXT NOT NULL,
+			value      BLOB NOT NULL,
+			updated_at TEXT NOT NULL,
+			PRIMARY KEY (bucket, key)
+		)`,
+		`CREATE TABLE logs (
+			id         INTEGER PRIMARY KEY AUTOINCREMENT,
+			stream     TEXT NOT NULL,
+			value      BLOB NOT NULL,
+			created_at TEXT NOT NULL
+		)`,
+		`CREATE INDEX logs_stream_id ON logs (stream, id)`,
+	},
+}
+
+// SchemaVersion is the schema version written by this build.
+var SchemaVersion = len(migrations)
+
+// SQLiteStore is the default Store backed by a SQLite database file.
+type SQLiteStore struct {
+	db     *sql.DB
+	path   string
+	now    func() time.Time
+	closed atomic.Bool
+}
+
+// OpenSQLite opens (creating if needed) the database at path and applies
+// pending migrations. A database written by a newer build is rejected rather
+// than modified.
+func OpenSQLite(path string) (*SQLiteStore, error) {
+	if strings.TrimSpace(path) == "" {
+		return nil, errors.New("state store path is required")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("create state store directory: %w", err)
+	}
+	db, err := sql.Open("sqlite", path+"?_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, fmt.Errorf("open state store: %w", err)
+	}
+	// A single connection serializes writers in-process; busy_timeout covers
+	// other processes such as the export tool.
+	db.SetMaxOpenConns(1)
+
+	store := &SQLiteStore{db: db, path: path, now: time.Now}
+	if err := store.migrate(context.Background()); err != nil {
+		if closeErr := db.Close(); closeErr != nil {
+			slog.Warn("[WARN-STATESTORE] close after failed migration", "error", closeErr)
+		}
+		return nil, err
+	}
+	return store, nil
+}
+
+// Path returns the database file path.
+func (s *SQLiteStore) Path() string {
+	return s.path
+}
+
+func (s *SQLiteStore) migrate(ctx context.Context) error {
+	version, err := s.schemaVersion(ctx)
+	if err != nil {
+		return err
+	}
+	if version > SchemaVersion {
+		return fmt.Errorf("state store schema version %d is newer than supported version %d", version, SchemaVersion)
+	}
+	for next := version; next < SchemaVersion; next++ {
+		tx, err := s.db.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("begin migration %d: %w", next+1, err)
+		}
+		for _, statement := range migrations[next] {
+			if _, err := tx.ExecContext(ctx, statement); err != nil {
+				_ = tx.Rollback()
+				return fmt.Errorf("apply migration %d: %w", next+1, err)
+			}
+		}
+		// PRAGMA does not accept bound parameters; next+1 is a trusted int.
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("PRAGMA user_version = %d", next+1)); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("record migration %d: %w", next+1, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("commit migration %d: %w", next+1, err)
+		}
+		slog.Info("[STATESTORE] applied migration", "version", next+1, "path", s.path)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) schemaVersion(ctx context.Context) (int, error) {
+	var version int
+	if err := s.db.QueryRowContext(ctx, "PRAGMA user_version").Scan(&version); err != nil {
+		return 0, fmt.Errorf("read state store schema version: %w", err)
+	}
+	return version, nil
+}
+
+func (s *SQLiteStore) timestamp() string {
+	return s.now().UTC().Format(time.RFC3339Nano)
+}
+
+func parseTimestamp(value string) time.Time {
+	parsed, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}
+	}
+	return parsed
+}
+
+// Get implements Store.
+func (s *SQLiteStore) Get(ctx context.Context, bucket, key string) ([]byte, error) {
+	if err := validateRecordKey(bucket, key); err != nil {
+		return nil, err
+	}
+	if s.closed.Load() {
+		return nil, ErrClosed
+	}
+	var value []byte
+	err := s.db.QueryRowContext(ctx, `SELECT value FROM records WHERE bucket = ? AND key = ?`, bucket, key).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get %s/%s: %w", bucket, key, err)
+	}
+	return value, nil
+}
+
+// Put implements Store.
+func (s *SQLiteStore) Put(ctx context.Context, bucket, key string, value []byte) error {
+	if err := validateRecordKey(bucket, key); err != nil {
+		return err
+	}
+	if s.closed.Load() {
+		return ErrClosed
+	}
+	if value == nil {
+		value = []byte{}
+	}
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO records (bucket, key, value, updated_at) VALUES (?, ?, ?, ?)
+		 ON CONFLICT(bucket, key) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at`,
+		bucket, key, value, s.timestamp(),
+	)
+	if err != nil {
+		return fmt.Errorf("put %s/%s: %w", bucket, key, err)
+	}
+	return nil
+}
+
+// Delete implements Store.
+func (s *SQLiteStore) Delete(ctx context.Context, bucket, key string) error {
+	if err := validateRecordKey(bucket, key); err != nil {
+		return err
+	}
+	if s.closed.Load() {
+		return ErrClosed
+	}
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM records WHERE bucket = ? AND key = ?`, bucket, key); err != nil {
+		return fmt.Errorf("delete %s/%s: %w", bucket, key, err)
+	}
+	return nil
+}
+
+// List implements Store.
+func (s *SQLiteStore) List(ctx context.Context, bucket string) ([]Record, error) {
+	if err := validateName("bucket", bucket); err != nil {
+		return nil, err
+	}
+	if s.closed.Load() {
+		return nil, ErrClosed
+	}
+	return s.queryRecords(ctx,
+		`SELECT bucket, key, value, updated_at FROM records WHERE bucket = ? ORDER BY key`, bucket)
+}
+
+func (s *SQLiteStore) queryRecords(ctx context.Context, query string, args ...any) ([]Record, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query records: %w", err)
+	}
+	defer rows.Close()
+
+	records := []Record{}
+	for rows.Next() {
+		var record Record
+		var updatedAt string
+		if err := rows.Scan(&record.Bucket, &record.Key, &record.Value, &updatedAt); err != nil {
+			return nil, fmt.Errorf("scan record: %w", err)
+		}
+		record.UpdatedAt = parseTimestamp(updatedAt)
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate records: %w", err)
+	}
+	return records, nil
+}
+
+// Append implements Store.
+func (s *SQLiteStore) Append(ctx context.Context, stream string, value []byte) (int64, error) {
+	if err := validateName("stream", stream); err != nil {
+		return 0, err
+	}
+	if s.closed.Load() {
+		return 0, ErrClosed
+	}
+	if value == nil {
+		value = []byte{}
+	}
+	result, err := s.db.ExecContext(ctx,
+		`INSERT INTO logs (stream, value, created_at) VALUES (?, ?, ?)`, stream, value, s.timestamp())
+	if err != nil {
+		return 0, fmt.Errorf("append to %s: %w", stream, err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("append to %s: %w", stream, err)
+	}
+	return id, nil
+}
+
+// ReadLog implements Store.
+func (s *SQLiteStore) ReadLog(ctx context.Context, stream string, afterID int64, limit int) ([]LogEntry, error) {
+	if err := validateName("stream", stream); err != nil {
+		return nil, err
+	}
+	if s.closed.Load() {
+		return nil, ErrClosed
+	}
+	if limit <= 0 {
+		// SQLite treats a negative LIMIT as no limit.
+		limit = -1
+	}
+	return s.queryLogs(ctx,
+		`SELECT id, stream, value, created_at FROM logs WHERE stream = ? AND id > ? ORDER BY id LIMIT ?`,
+		stream, afterID, limit)
+}
+
+func (s *SQLiteStore) queryLogs(ctx context.Context, query string, args ...any) ([]LogEntry, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query logs: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []LogEntry{}
+	for rows.Next() {
+		var entry LogEntry
+		var createdAt string
+		if err := rows.Scan(&entry.ID, &entry.Stream, &entry.Value, &createdAt); err != nil {
+			return nil, fmt.Errorf("scan log entry: %w", err)
+		}
+		entry.CreatedAt = parseTimestamp(createdAt)
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate logs: %w", err)
+	}
+	return entries, nil
+}
+
+// TrimLog implements Store.
+func (s *SQLiteStore) TrimLog(ctx context.Context, stream string, keep int) (int, error) {
+	if err := validateName("stream", stream); err != nil {
+		return 0, err
+	}
+	if s.closed.Load() {
+		return 0, ErrClosed
+	}
+	result, err := s.db.ExecContext(ctx,
+		`DELETE FROM logs WHERE stream = ? AND id NOT IN (
+			SELECT id FROM logs WHERE stream = ? ORDER BY id DESC LIMIT ?
+		)`, stream, stream, max(keep, 0))
+	if err != nil {
+		return 0, fmt.Errorf("trim %s: %w", stream, err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("trim %s: %w", stream, err)
+	}
+	return int(deleted), nil
+}
+
+// Check implements Store. It runs SQLite's integrity check and verifies the
+// schema version.
+func (s *SQLiteStore) Check(ctx context.Context) error {
+	if s.closed.Load() {
+		return ErrClosed
+	}
+	rows, err := s.db.QueryContext(ctx, "PRAGMA integrity_check")
+	if err != nil {
+		return fmt.Errorf("integrity check: %w", err)
+	}
+	defer rows.Close()
+
+	var problems []string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return fmt.Errorf("integrity check: %w", err)
+		}
+		if line != "ok" {
+			problems = append(problems, line)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("integrity check: %w", err)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("state store is corrupt: %s", strings.Join(problems, "; "))
+	}
+
+	version, err := s.schemaVersion(ctx)
+	if err != nil {
+		return err
+	}
+	if version != SchemaVersion {
+		return fmt.Errorf("state store schema version %d, want %d", version, SchemaVersion)
+	}
+	return nil
+}
+
+// Dump implements Store.
+func (s *SQLiteStore) Dump(ctx context.Context) (Dump, error) {
+	if s.closed.Load() {
+		return Dump{}, ErrClosed
+	}
+	version, err := s.schemaVersion(ctx)
+	if err != nil {
+		return Dump{}, err
+	}
+	records, err := s.queryRecords(ctx, `SELECT bucket, key, value, updated_at FROM records ORDER BY bucket, key`)
+	if err != nil {
+		return Dump{}, err
+	}
+	logs, err := s.queryLogs(ctx, `SELECT id, stream, value, created_at FROM logs ORDER BY id`)
+	if err != nil {
+		return Dump{}, err
+	}
+	return Dump{SchemaVersion: version, Records: records, Logs: logs}, nil
+}
+
+// Close implements Store.
+func (s *SQLiteStore) Close() error {
+	if s.closed.Swap(true) {
+		return nil
+	}
+	return s.db.Close()
+}
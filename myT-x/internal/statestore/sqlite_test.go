@@ -0,0 +1,55 @@
+package statestore
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOpenSQLiteMigratesAndReopens(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "nested", FileName)
+
+	store, err := OpenSQLite(path)
+	if err != nil {
+		t.Fatalf("OpenSQLite() error = %v", err)
+	}
+	if err := store.Put(ctx, "notes", "a", []byte("kept")); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err = OpenSQLite(path)
+	if err != nil {
+		t.Fatalf("reopen error = %v", err)
+	}
+	defer store.Close()
+	if got, err := store.Get(ctx, "notes", "a"); err != nil || string(got) != "kept" {
+		t.Fatalf("Get() after reopen = %q, %v", got, err)
+	}
+	if version, err := store.schemaVersion(ctx); err != nil || version != SchemaVersion {
+		t.Fatalf("schema version = %d, %v, want %d", version, err, SchemaVersion)
+	}
+}
+
+func TestOpenSQLiteRejectsNewerSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("PRAGMA user_version = 999"); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := OpenSQLite(path); err == nil || !strings.Contains(err.Error(), "newer") {
+		t.Fatalf("OpenSQLite() error = %v, want newer schema error", err)
+	}
+}
@@ -0,0 +1,111 @@
+// Package statestore abstracts persisted app state behind one Store
+// interface so that features stop adding bespoke JSON file formats.
+//
+// A Store holds two kinds of data:
+//   - records: small values addressed by bucket and key (session state, notes)
+//   - logs: append-only streams of entries (audit logs, command history)
+//
+// SQLiteStore is the default backend. MemoryStore keeps everything in memory
+// and backs tests and the fallback when the database cannot be opened.
+//
+// Session badges, session memos, recent directories, ephemeral sessions and
+// the command approval audit log live here; badges and memos import their
+// legacy files on first load. Input history deliberately keeps its daily
+// JSONL files: they are rotated and pruned per day, read back one day at a
+// time, and recovered line by line after a crash, none of which a log stream
+// provides.
+package statestore
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+// FileName is the SQLite database file name under the config directory.
+const FileName = "state.db"
+
+// Well-known buckets and log streams.
+const (
+	BucketSessionBadges     = "session-badges"
+	BucketSessionMemos      = "session-memos"
+	BucketRecentDirectories = "recent-directories"
+	BucketChangelog         = "changelog"
+	BucketEphemeralSessions = "ephemeral-sessions"
+
+	StreamCommandApprovals = "audit.command-approvals"
+)
+
+// ErrNotFound is returned by Get when the record does not exist.
+var ErrNotFound = errors.New("state record not found")
+
+// ErrClosed is returned by every operation after Close.
+var ErrClosed = errors.New("state store is closed")
+
+// Record is one bucket/key value.
+type Record struct {
+	Bucket    string    `json:"bucket"`
+	Key       string    `json:"key"`
+	Value     []byte    `json:"value"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// LogEntry is one entry of an append-only log stream.
+// IDs increase monotonically within a store.
+type LogEntry struct {
+	ID        int64     `json:"id"`
+	Stream    string    `json:"stream"`
+	Value     []byte    `json:"value"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Store is the persistence interface shared by all backends.
+// Implementations are safe for concurrent use.
+type Store interface {
+	// Get returns the value of bucket/key, or ErrNotFound.
+	Get(ctx context.Context, bucket, key string) ([]byte, error)
+	// Put creates or replaces bucket/key.
+	Put(ctx context.Context, bucket, key string, value []byte) error
+	// Delete removes bucket/key. Deleting a missing record is not an error.
+	Delete(ctx context.Context, bucket, key string) error
+	// List returns every record of bucket ordered by key.
+	List(ctx context.Context, bucket string) ([]Record, error)
+
+	// Append adds value to stream and returns the new entry ID.
+	Append(ctx context.Context, stream string, value []byte) (int64, error)
+	// ReadLog returns up to limit entries of stream with an ID greater than
+	// afterID, oldest first. limit <= 0 means no limit.
+	ReadLog(ctx context.Context, stream string, afterID int64, limit int) ([]LogEntry, error)
+	// TrimLog deletes the oldest entries of stream so that at most keep
+	// remain, and returns the number deleted.
+	TrimLog(ctx context.Context, stream string, keep int) (int, error)
+
+	// Check verifies the integrity of the underlying storage.
+	Check(ctx context.Context) error
+	// Dump returns every record and log entry, for export.
+	Dump(ctx context.Context) (Dump, error)
+	// Close releases the backend. Further calls return ErrClosed.
+	Close() error
+}
+
+// Dump is the complete contents of a store.
+type Dump struct {
+	SchemaVersion int        `json:"schema_version"`
+	Records       []Record   `json:"records"`
+	Logs          []LogEntry `json:"logs"`
+}
+
+func validateName(kind, name string) error {
+	if strings.TrimSpace(name) == "" {
+		return errors.New(kind + " is required")
+	}
+	return nil
+}
+
+func validateRecordKey(bucket, key string) error {
+	if err := validateName("bucket", bucket); err != nil {
+		return err
+	}
+	return validateName("key", key)
+}
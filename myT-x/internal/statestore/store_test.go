@@ -0,0 +1,145 @@
+package statestore
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+// backends returns one fresh store per implementation so every test below
+// runs against each backend.
+func backends(t *testing.T) map[string]Store {
+	t.Helper()
+	sqliteStore, err := OpenSQLite(filepath.Join(t.TempDir(), FileName))
+	if err != nil {
+		t.Fatalf("OpenSQLite() error = %v", err)
+	}
+	stores := map[string]Store{
+		"memory": NewMemoryStore(),
+		"sqlite": sqliteStore,
+	}
+	t.Cleanup(func() {
+		for _, store := range stores {
+			_ = store.Close()
+		}
+	})
+	return stores
+}
+
+func TestStoreRecords(t *testing.T) {
+	ctx := context.Background()
+	for name, store := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			if _, err := store.Get(ctx, "notes", "a"); !errors.Is(err, ErrNotFound) {
+				t.Fatalf("Get(missing) error = %v, want ErrNotFound", err)
+			}
+			for key, value := range map[string]string{"b": "2", "a": "1"} {
+				if err := store.Put(ctx, "notes", key, []byte(value)); err != nil {
+					t.Fatalf("Put(%s) error = %v", key, err)
+				}
+			}
+			if err := store.Put(ctx, "notes", "a", []byte("updated")); err != nil {
+				t.Fatalf("Put(a) overwrite error = %v", err)
+			}
+			if err := store.Put(ctx, "other", "a", []byte("x")); err != nil {
+				t.Fatalf("Put(other) error = %v", err)
+			}
+
+			got, err := store.Get(ctx, "notes", "a")
+			if err != nil || string(got) != "updated" {
+				t.Fatalf("Get(a) = %q, %v, want updated", got, err)
+			}
+			records, err := store.List(ctx, "notes")
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+			if len(records) != 2 || records[0].Key != "a" || records[1].Key != "b" || records[0].UpdatedAt.IsZero() {
+				t.Fatalf("List() = %+v, want a and b in key order", records)
+			}
+
+			if err := store.Delete(ctx, "notes", "a"); err != nil {
+				t.Fatalf("Delete() error = %v", err)
+			}
+			if err := store.Delete(ctx, "notes", "a"); err != nil {
+				t.Fatalf("Delete(missing) error = %v", err)
+			}
+			if _, err := store.Get(ctx, "notes", "a"); !errors.Is(err, ErrNotFound) {
+				t.Fatalf("Get(deleted) error = %v, want ErrNotFound", err)
+			}
+			if err := store.Put(ctx, " ", "a", nil); err == nil {
+				t.Fatal("Put(empty bucket) error = nil")
+			}
+		})
+	}
+}
+
+func TestStoreLogs(t *testing.T) {
+	ctx := context.Background()
+	for name, store := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			var ids []int64
+			for _, value := range []string{"one", "two", "three"} {
+				id, err := store.Append(ctx, "audit", []byte(value))
+				if err != nil {
+					t.Fatalf("Append() error = %v", err)
+				}
+				ids = append(ids, id)
+			}
+			if _, err := store.Append(ctx, "history", []byte("other")); err != nil {
+				t.Fatalf("Append(history) error = %v", err)
+			}
+			if ids[0] >= ids[1] || ids[1] >= ids[2] {
+				t.Fatalf("Append() ids = %v, want increasing", ids)
+			}
+
+			entries, err := store.ReadLog(ctx, "audit", ids[0], 1)
+			if err != nil || len(entries) != 1 || string(entries[0].Value) != "two" {
+				t.Fatalf("ReadLog(after first, 1) = %+v, %v, want [two]", entries, err)
+			}
+
+			deleted, err := store.TrimLog(ctx, "audit", 1)
+			if err != nil || deleted != 2 {
+				t.Fatalf("TrimLog() = %d, %v, want 2", deleted, err)
+			}
+			entries, _ = store.ReadLog(ctx, "audit", 0, 0)
+			if len(entries) != 1 || string(entries[0].Value) != "three" {
+				t.Fatalf("ReadLog() after trim = %+v, want [three]", entries)
+			}
+			if entries, _ := store.ReadLog(ctx, "history", 0, 0); len(entries) != 1 {
+				t.Fatalf("TrimLog() touched another stream: %+v", entries)
+			}
+		})
+	}
+}
+
+func TestStoreCheckDumpAndClose(t *testing.T) {
+	ctx := context.Background()
+	for name, store := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			if err := store.Put(ctx, "notes", "a", []byte(`{"x":1}`)); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := store.Append(ctx, "audit", []byte("entry")); err != nil {
+				t.Fatal(err)
+			}
+			if err := store.Check(ctx); err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			dump, err := store.Dump(ctx)
+			if err != nil {
+				t.Fatalf("Dump() error = %v", err)
+			}
+			if dump.SchemaVersion != SchemaVersion || len(dump.Records) != 1 || len(dump.Logs) != 1 {
+				t.Fatalf("Dump() = %+v", dump)
+			}
+
+			if err := store.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+			if err := store.Put(ctx, "notes", "a", nil); !errors.Is(err, ErrClosed) {
+				t.Fatalf("Put() after Close error = %v, want ErrClosed", err)
+			}
+		})
+	}
+}
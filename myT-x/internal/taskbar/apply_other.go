@@ -0,0 +1,13 @@
+//go:build !windows
+
+package taskbar
+
+// apply is a no-op: taskbar flashing and overlays are Windows features.
+func apply(State) error {
+	return nil
+}
+
+// windowFocused always reports true so that no alerts are computed.
+func windowFocused() bool {
+	return true
+}
@@ -0,0 +1,255 @@
+//go:build windows
+
+package taskbar
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"sync"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	ole32  = windows.NewLazySystemDLL("ole32.dll")
+	user32 = windows.NewLazySystemDLL("user32.dll")
+	gdi32  = windows.NewLazySystemDLL("gdi32.dll")
+
+	procCoCreateInstance    = ole32.NewProc("CoCreateInstance")
+	procFlashWindowEx       = user32.NewProc("FlashWindowEx")
+	procGetForegroundWindow = user32.NewProc("GetForegroundWindow")
+	procGetWindow           = user32.NewProc("GetWindow")
+	procIsWindow            = user32.NewProc("IsWindow")
+	procIsWindowVisible     = user32.NewProc("IsWindowVisible")
+	procCreateIconIndirect  = user32.NewProc("CreateIconIndirect")
+	procDestroyIcon         = user32.NewProc("DestroyIcon")
+	procCreateDIBSection    = gdi32.NewProc("CreateDIBSection")
+	procCreateBitmap        = gdi32.NewProc("CreateBitmap")
+	procDeleteObject        = gdi32.NewProc("DeleteObject")
+)
+
+var (
+	clsidTaskbarList = windows.GUID{Data1: 0x56fdf344, Data2: 0xfd6d, Data3: 0x11d0, Data4: [8]byte{0x95, 0x8a, 0x00, 0x60, 0x97, 0xc9, 0xa0, 0x90}}
+	iidITaskbarList3 = windows.GUID{Data1: 0xea1afb91, Data2: 0x9e28, Data3: 0x4b86, Data4: [8]byte{0x90, 0xe9, 0x9e, 0x9f, 0x8a, 0x5e, 0xef, 0xaf}}
+)
+
+// ITaskbarList3 vtable slots. Every interface starts with the three IUnknown
+// methods.
+const (
+	slotRelease        = 2
+	slotHrInit         = 3
+	slotSetOverlayIcon = 18
+)
+
+const (
+	_FLASHW_STOP      = 0
+	_FLASHW_TRAY      = 0x2
+	_FLASHW_TIMERNOFG = 0xc
+
+	_GW_OWNER       = 4
+	_BI_RGB         = 0
+	_DIB_RGB_COLORS = 0
+)
+
+type _FLASHWINFO struct {
+	cbSize    uint32
+	hwnd      uintptr
+	dwFlags   uint32
+	uCount    uint32
+	dwTimeout uint32
+}
+
+type _ICONINFO struct {
+	fIcon    int32
+	xHotspot uint32
+	yHotspot uint32
+	hbmMask  uintptr
+	hbmColor uintptr
+}
+
+type _BITMAPINFOHEADER struct {
+	biSize          uint32
+	biWidth         int32
+	biHeight        int32
+	biPlanes        uint16
+	biBitCount      uint16
+	biCompression   uint32
+	biSizeImage     uint32
+	biXPelsPerMeter int32
+	biYPelsPerMeter int32
+	biClrUsed       uint32
+	biClrImportant  uint32
+}
+
+// apply flashes the main window's taskbar button and sets its overlay icon.
+// COM requires the calls to stay on one thread.
+func apply(state State) error {
+	hwnd := mainWindow()
+	if hwnd == 0 {
+		// The window is not created yet; the next poll retries.
+		return nil
+	}
+
+	flags := uint32(_FLASHW_STOP)
+	if state.Flash {
+		flags = _FLASHW_TRAY | _FLASHW_TIMERNOFG
+	}
+	if state.Flash || state.Count == 0 {
+		info := _FLASHWINFO{hwnd: hwnd, dwFlags: flags}
+		info.cbSize = uint32(unsafe.Sizeof(info))
+		procFlashWindowEx.Call(uintptr(unsafe.Pointer(&info)))
+	}
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	switch err := windows.CoInitializeEx(0, windows.COINIT_APARTMENTTHREADED); {
+	case err == nil, errors.Is(err, syscall.Errno(windows.S_FALSE)):
+		defer windows.CoUninitialize()
+	case errors.Is(err, syscall.Errno(windows.RPC_E_CHANGED_MODE)):
+		// Already initialized as MTA on this thread; the taskbar API works there too.
+	default:
+		return fmt.Errorf("CoInitializeEx: %w", err)
+	}
+
+	var list unsafe.Pointer
+	hr, _, _ := procCoCreateInstance.Call(
+		uintptr(unsafe.Pointer(&clsidTaskbarList)),
+		0,
+		windows.CLSCTX_INPROC_SERVER,
+		uintptr(unsafe.Pointer(&iidITaskbarList3)),
+		uintptr(unsafe.Pointer(&list)),
+	)
+	if int32(hr) < 0 {
+		return fmt.Errorf("CoCreateInstance(TaskbarList): %w", syscall.Errno(hr))
+	}
+	defer comCall(list, slotRelease)
+	if hr := comCall(list, slotHrInit); int32(hr) < 0 {
+		return fmt.Errorf("HrInit: %w", syscall.Errno(hr))
+	}
+
+	var icon uintptr
+	var description *uint16
+	if state.Count > 0 {
+		var err error
+		if icon, err = createOverlayIcon(renderOverlay(state.Count, state.Color)); err != nil {
+			return err
+		}
+		// The taskbar keeps its own copy of the icon.
+		defer procDestroyIcon.Call(icon)
+		description, err = windows.UTF16PtrFromString(fmt.Sprintf("%d sessions need attention", state.Count))
+		if err != nil {
+			return err
+		}
+	}
+	if hr := comCall(list, slotSetOverlayIcon, hwnd, icon, uintptr(unsafe.Pointer(description))); int32(hr) < 0 {
+		return fmt.Errorf("SetOverlayIcon: %w", syscall.Errno(hr))
+	}
+	runtime.KeepAlive(description)
+	return nil
+}
+
+// comCall invokes vtable slot method of obj and returns the HRESULT.
+//
+//go:uintptrescapes
+func comCall(obj unsafe.Pointer, method int, args ...uintptr) uintptr {
+	vtbl := *(*unsafe.Pointer)(obj)
+	fn := *(*uintptr)(unsafe.Add(vtbl, uintptr(method)*unsafe.Sizeof(uintptr(0))))
+	hr, _, _ := syscall.SyscallN(fn, append([]uintptr{uintptr(obj)}, args...)...)
+	return hr
+}
+
+// createOverlayIcon creates an icon from renderOverlay pixels. The caller
+// destroys it.
+func createOverlayIcon(pixels []uint32) (uintptr, error) {
+	header := _BITMAPINFOHEADER{
+		biWidth:       overlaySize,
+		biHeight:      -overlaySize, // top-down rows
+		biPlanes:      1,
+		biBitCount:    32,
+		biCompression: _BI_RGB,
+	}
+	header.biSize = uint32(unsafe.Sizeof(header))
+
+	var bits unsafe.Pointer
+	color, _, callErr := procCreateDIBSection.Call(0, uintptr(unsafe.Pointer(&header)), _DIB_RGB_COLORS,
+		uintptr(unsafe.Pointer(&bits)), 0, 0)
+	if color == 0 {
+		return 0, fmt.Errorf("CreateDIBSection: %w", callErr)
+	}
+	defer procDeleteObject.Call(color)
+	copy(unsafe.Slice((*uint32)(bits), len(pixels)), pixels)
+
+	// The alpha channel of the color bitmap defines transparency; the mask
+	// only has to exist.
+	mask, _, callErr := procCreateBitmap.Call(overlaySize, overlaySize, 1, 1, 0)
+	if mask == 0 {
+		return 0, fmt.Errorf("CreateBitmap: %w", callErr)
+	}
+	defer procDeleteObject.Call(mask)
+
+	info := _ICONINFO{fIcon: 1, hbmMask: mask, hbmColor: color}
+	icon, _, callErr := procCreateIconIndirect.Call(uintptr(unsafe.Pointer(&info)))
+	if icon == 0 {
+		return 0, fmt.Errorf("CreateIconIndirect: %w", callErr)
+	}
+	return icon, nil
+}
+
+var (
+	mainWindowMu sync.Mutex
+	// mainWindowHandle caches the window found by mainWindow.
+	mainWindowHandle uintptr
+	// enumWindowsCallback is created once: callbacks are never freed.
+	enumWindowsCallback = syscall.NewCallback(func(hwnd, _ uintptr) uintptr {
+		if isMainWindow(hwnd) {
+			mainWindowHandle = hwnd
+			return 0 // stop enumerating
+		}
+		return 1
+	})
+)
+
+// mainWindow returns the visible top-level window of this process, or 0.
+func mainWindow() uintptr {
+	mainWindowMu.Lock()
+	defer mainWindowMu.Unlock()
+	if mainWindowHandle != 0 {
+		if alive, _, _ := procIsWindow.Call(mainWindowHandle); alive != 0 {
+			return mainWindowHandle
+		}
+		mainWindowHandle = 0
+	}
+	// EnumWindows reports an error when the callback stops it early.
+	_ = windows.EnumWindows(enumWindowsCallback, nil)
+	return mainWindowHandle
+}
+
+func isMainWindow(hwnd uintptr) bool {
+	var pid uint32
+	if _, err := windows.GetWindowThreadProcessId(windows.HWND(hwnd), &pid); err != nil || pid != uint32(os.Getpid()) {
+		return false
+	}
+	if visible, _, _ := procIsWindowVisible.Call(hwnd); visible == 0 {
+		return false
+	}
+	owner, _, _ := procGetWindow.Call(hwnd, _GW_OWNER)
+	return owner == 0
+}
+
+// windowFocused reports whether the foreground window belongs to this
+// process.
+func windowFocused() bool {
+	hwnd, _, _ := procGetForegroundWindow.Call()
+	if hwnd == 0 {
+		return false
+	}
+	var pid uint32
+	if _, err := windows.GetWindowThreadProcessId(windows.HWND(hwnd), &pid); err != nil {
+		return false
+	}
+	return pid == uint32(os.Getpid())
+}
@@ -0,0 +1,119 @@
+package taskbar
+
+import (
+	"strconv"
+	"strings"
+)
+
+const (
+	// overlaySize is the edge length of the overlay icon in pixels, the
+	// small icon size the taskbar draws overlays at.
+	overlaySize = 16
+
+	// defaultAlertColor is used when the alerting session has no badge color.
+	defaultAlertColor = "#e5484d"
+
+	glyphWidth  = 3
+	glyphHeight = 5
+	glyphScale  = 2
+	// supersample is the per-axis sample count used to anti-alias the disc.
+	supersample = 4
+)
+
+// glyphs are 3x5 bitmaps, one row per string, for the overlay count.
+// '+' stands for counts above nine, which do not fit the icon.
+var glyphs = map[byte][glyphHeight]string{
+	'0': {"###", "#.#", "#.#", "#.#", "###"},
+	'1': {".#.", "##.", ".#.", ".#.", "###"},
+	'2': {"###", "..#", "###", "#..", "###"},
+	'3': {"###", "..#", "###", "..#", "###"},
+	'4': {"#.#", "#.#", "###", "..#", "..#"},
+	'5': {"###", "#..", "###", "..#", "###"},
+	'6': {"###", "#..", "###", "#.#", "###"},
+	'7': {"###", "..#", "..#", "..#", "..#"},
+	'8': {"###", "#.#", "###", "#.#", "###"},
+	'9': {"###", "#.#", "###", "..#", "###"},
+	'+': {"...", ".#.", "###", ".#.", "..."},
+}
+
+// renderOverlay draws the overlay icon for count alerts as a colored disc
+// with the count in the middle. Pixels are premultiplied 0xAARRGGBB values
+// in top-down row order, the layout of a 32-bit DIB section.
+func renderOverlay(count int, color string) []uint32 {
+	r, g, b, ok := parseHexColor(color)
+	if !ok {
+		r, g, b, _ = parseHexColor(defaultAlertColor)
+	}
+	// Dark text on light badge colors keeps the count readable.
+	var text uint32 = 0xffffffff
+	if 299*int(r)+587*int(g)+114*int(b) > 160_000 {
+		text = 0xff000000
+	}
+
+	pixels := make([]uint32, overlaySize*overlaySize)
+	const center = overlaySize / 2.0
+	const radius = overlaySize / 2.0
+	for y := range overlaySize {
+		for x := range overlaySize {
+			covered := 0
+			for sy := range supersample {
+				for sx := range supersample {
+					dx := float64(x) + (float64(sx)+0.5)/supersample - center
+					dy := float64(y) + (float64(sy)+0.5)/supersample - center
+					if dx*dx+dy*dy <= radius*radius {
+						covered++
+					}
+				}
+			}
+			alpha := uint32(covered * 255 / (supersample * supersample))
+			pixels[y*overlaySize+x] = alpha<<24 |
+				(uint32(r)*alpha/255)<<16 |
+				(uint32(g)*alpha/255)<<8 |
+				uint32(b)*alpha/255
+		}
+	}
+
+	glyph := glyphs[countGlyph(count)]
+	left := (overlaySize - glyphWidth*glyphScale) / 2
+	top := (overlaySize - glyphHeight*glyphScale) / 2
+	for row, bits := range glyph {
+		for col := range glyphWidth {
+			if bits[col] != '#' {
+				continue
+			}
+			for dy := range glyphScale {
+				for dx := range glyphScale {
+					pixels[(top+row*glyphScale+dy)*overlaySize+left+col*glyphScale+dx] = text
+				}
+			}
+		}
+	}
+	return pixels
+}
+
+// countGlyph returns the glyph shown for count.
+func countGlyph(count int) byte {
+	if count > 9 {
+		return '+'
+	}
+	return strconv.Itoa(max(count, 0))[0]
+}
+
+// parseHexColor parses a #rgb or #rrggbb color.
+func parseHexColor(color string) (r, g, b uint8, ok bool) {
+	hex, found := strings.CutPrefix(strings.TrimSpace(color), "#")
+	if !found {
+		return 0, 0, 0, false
+	}
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) != 6 {
+		return 0, 0, 0, false
+	}
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return 0, 0, 0, false
+	}
+	return uint8(value >> 16), uint8(value >> 8), uint8(value), true
+}
@@ -0,0 +1,58 @@
+package taskbar
+
+import "testing"
+
+func TestRenderOverlay(t *testing.T) {
+	pixels := renderOverlay(3, "#00f")
+	if len(pixels) != overlaySize*overlaySize {
+		t.Fatalf("len(pixels) = %d, want %d", len(pixels), overlaySize*overlaySize)
+	}
+	if corner := pixels[0]; corner != 0 {
+		t.Fatalf("corner pixel = %#08x, want transparent", corner)
+	}
+	// Just inside the disc edge, left of the glyph.
+	if edge := pixels[8*overlaySize+1]; edge != 0xff0000ff {
+		t.Fatalf("disc pixel = %#08x, want opaque blue", edge)
+	}
+	// Top-left cell of the '3' glyph.
+	left := (overlaySize - glyphWidth*glyphScale) / 2
+	top := (overlaySize - glyphHeight*glyphScale) / 2
+	if text := pixels[top*overlaySize+left]; text != 0xffffffff {
+		t.Fatalf("glyph pixel = %#08x, want white text on a dark color", text)
+	}
+
+	if text := renderOverlay(1, "#ffff00")[top*overlaySize+left+glyphScale]; text != 0xff000000 {
+		t.Fatalf("glyph pixel = %#08x, want black text on a light color", text)
+	}
+	if got, want := renderOverlay(1, "bogus")[8*overlaySize+1], renderOverlay(1, defaultAlertColor)[8*overlaySize+1]; got != want {
+		t.Fatalf("invalid color pixel = %#08x, want default color %#08x", got, want)
+	}
+}
+
+func TestCountGlyph(t *testing.T) {
+	for count, want := range map[int]byte{0: '0', 1: '1', 9: '9', 10: '+', 250: '+'} {
+		if got := countGlyph(count); got != want {
+			t.Errorf("countGlyph(%d) = %q, want %q", count, got, want)
+		}
+	}
+}
+
+func TestParseHexColor(t *testing.T) {
+	tests := []struct {
+		in      string
+		r, g, b uint8
+		ok      bool
+	}{
+		{"#e5484d", 0xe5, 0x48, 0x4d, true},
+		{"#0F0", 0, 0xff, 0, true},
+		{"e5484d", 0, 0, 0, false},
+		{"#12345", 0, 0, 0, false},
+		{"#zzzzzz", 0, 0, 0, false},
+	}
+	for _, tt := range tests {
+		r, g, b, ok := parseHexColor(tt.in)
+		if r != tt.r || g != tt.g || b != tt.b || ok != tt.ok {
+			t.Errorf("parseHexColor(%q) = %d,%d,%d,%v; want %d,%d,%d,%v", tt.in, r, g, b, ok, tt.r, tt.g, tt.b, tt.ok)
+		}
+	}
+}
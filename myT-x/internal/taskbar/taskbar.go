@@ -0,0 +1,164 @@
+// Package taskbar routes attention from background sessions to the Windows
+// taskbar button. While the window is unfocused, sessions that rang the
+// terminal bell or went idle waiting for input flash the button and show an
+// overlay badge with their count, colored like the session badge, so users
+// who alt-tabbed away notice that an agent is blocked on a question.
+package taskbar
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+)
+
+// DefaultPollInterval is the attention polling period used when
+// Deps.PollInterval is zero.
+const DefaultPollInterval = time.Second
+
+// Alert is a background session that needs the user's attention.
+type Alert struct {
+	Session string
+	// Color is the session badge color (#rgb or #rrggbb), or "".
+	Color string
+}
+
+// State is what the taskbar button shows.
+type State struct {
+	// Count is the number of alerting sessions. Zero clears the overlay.
+	Count int
+	// Color is the overlay color, taken from the first alert. Empty means
+	// the default alert color.
+	Color string
+	// Flash flashes the button until the window comes to the foreground.
+	Flash bool
+}
+
+// Deps holds external dependencies injected at construction time.
+type Deps struct {
+	// Alerts returns the background sessions needing attention, most
+	// important first. Muted and detached sessions must already be excluded.
+	Alerts func() []Alert
+
+	// WindowFocused reports whether the application window is in the
+	// foreground. Optional: defaults to a foreground window check on Windows
+	// and always true elsewhere.
+	WindowFocused func() bool
+
+	// Apply updates the taskbar button.
+	// Optional: defaults to FlashWindowEx and ITaskbarList3 on Windows and a
+	// no-op elsewhere.
+	Apply func(State) error
+
+	// PollInterval is the period of Run. Optional: defaults to DefaultPollInterval.
+	PollInterval time.Duration
+}
+
+// Service polls session attention and updates the taskbar button when it
+// changes.
+//
+// Thread-safety is managed internally via mu. No external locking is required.
+type Service struct {
+	deps Deps
+
+	mu sync.Mutex
+	// alerted holds the sessions alerting at the last successful poll. Only
+	// sessions not in it flash the button again.
+	alerted map[string]bool
+	// applied is the last state written successfully; nil forces a write.
+	applied *State
+	// lastApplyErr suppresses repeated warnings for the same failure.
+	lastApplyErr string
+}
+
+// NewService creates a taskbar attention service.
+// Panics if Alerts is nil.
+func NewService(deps Deps) *Service {
+	if deps.Alerts == nil {
+		panic("taskbar.NewService: Alerts must be non-nil")
+	}
+	if deps.WindowFocused == nil {
+		deps.WindowFocused = windowFocused
+	}
+	if deps.Apply == nil {
+		deps.Apply = apply
+	}
+	if deps.PollInterval <= 0 {
+		deps.PollInterval = DefaultPollInterval
+	}
+	return &Service{deps: deps, alerted: map[string]bool{}}
+}
+
+// Run polls until ctx is cancelled and then clears the taskbar button.
+// Apply failures are logged once until the failure changes or clears, and
+// the write is retried on the next poll.
+func (s *Service) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.deps.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			if err := s.deps.Apply(State{}); err != nil {
+				slog.Debug("[DEBUG-TASKBAR] failed to clear taskbar on shutdown", "error", err)
+			}
+			return
+		case <-ticker.C:
+			s.logApplyError(s.Poll())
+		}
+	}
+}
+
+// Poll updates the taskbar button from the current alerts. Alerts are shown
+// only while the window is unfocused; a session that starts alerting while
+// unfocused flashes the button. Sessions that were already alerting while
+// the user was looking do not flash it when the window loses focus.
+func (s *Service) Poll() error {
+	alerts := s.deps.Alerts()
+	focused := s.deps.WindowFocused()
+
+	current := make(map[string]bool, len(alerts))
+	for _, alert := range alerts {
+		current[alert.Session] = true
+	}
+
+	s.mu.Lock()
+	var state State
+	if !focused && len(alerts) > 0 {
+		state.Count = len(alerts)
+		state.Color = alerts[0].Color
+		state.Flash = slices.ContainsFunc(alerts, func(alert Alert) bool { return !s.alerted[alert.Session] })
+	}
+	unchanged := s.applied != nil && s.applied.Count == state.Count && s.applied.Color == state.Color
+	if unchanged && !state.Flash {
+		s.alerted = current
+		s.mu.Unlock()
+		return nil
+	}
+	s.mu.Unlock()
+
+	if err := s.deps.Apply(state); err != nil {
+		return err
+	}
+	slog.Debug("[DEBUG-TASKBAR] taskbar updated", "count", state.Count, "flash", state.Flash)
+
+	s.mu.Lock()
+	state.Flash = false
+	s.applied = &state
+	s.alerted = current
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *Service) logApplyError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err == nil {
+		s.lastApplyErr = ""
+		return
+	}
+	if msg := err.Error(); msg != s.lastApplyErr {
+		s.lastApplyErr = msg
+		slog.Warn("[WARN-TASKBAR] taskbar update failed", "error", err)
+	}
+}
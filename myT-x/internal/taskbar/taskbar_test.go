@@ -0,0 +1,93 @@
+package taskbar
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type fakeTaskbar struct {
+	alerts  []Alert
+	focused bool
+	applied []State
+	err     error
+}
+
+func newFakeService(f *fakeTaskbar) *Service {
+	return NewService(Deps{
+		Alerts:        func() []Alert { return f.alerts },
+		WindowFocused: func() bool { return f.focused },
+		Apply: func(state State) error {
+			if f.err != nil {
+				return f.err
+			}
+			f.applied = append(f.applied, state)
+			return nil
+		},
+	})
+}
+
+func TestNewServicePanicsWithoutAlerts(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("NewService() did not panic")
+		}
+	}()
+	NewService(Deps{})
+}
+
+func TestPoll(t *testing.T) {
+	f := &fakeTaskbar{}
+	s := newFakeService(f)
+	poll := func() {
+		t.Helper()
+		if err := s.Poll(); err != nil {
+			t.Fatalf("Poll() error = %v", err)
+		}
+	}
+
+	// The first poll clears whatever a previous run left behind.
+	poll()
+	// A session alerting while the window is focused is not shown.
+	f.focused = true
+	f.alerts = []Alert{{Session: "a", Color: "#f00"}}
+	poll()
+	// Losing focus shows the badge without flashing for the known alert.
+	f.focused = false
+	poll()
+	// Nothing changed: no write.
+	poll()
+	// A new alert flashes; the color follows the first alert.
+	f.alerts = []Alert{{Session: "b", Color: "#00f"}, {Session: "a", Color: "#f00"}}
+	poll()
+	// Focus clears the taskbar.
+	f.focused = true
+	poll()
+
+	want := []State{
+		{},
+		{Count: 1, Color: "#f00"},
+		{Count: 2, Color: "#00f", Flash: true},
+		{},
+	}
+	if !reflect.DeepEqual(f.applied, want) {
+		t.Fatalf("applied = %+v, want %+v", f.applied, want)
+	}
+}
+
+func TestPollRetriesFailedApply(t *testing.T) {
+	f := &fakeTaskbar{alerts: []Alert{{Session: "a"}}, err: errors.New("no window")}
+	s := newFakeService(f)
+	if err := s.Poll(); err == nil {
+		t.Fatal("Poll() error = nil, want apply error")
+	}
+
+	f.err = nil
+	if err := s.Poll(); err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+	// The alert still counts as new, so the retry flashes.
+	if want := []State{{Count: 1, Flash: true}}; !reflect.DeepEqual(f.applied, want) {
+		t.Fatalf("applied = %+v, want %+v", f.applied, want)
+	}
+}
@@ -7,8 +7,22 @@ import (
 	"os"
 	"slices"
 	"sync"
+	"sync/atomic"
 )
 
+// bytesRead and bytesWritten count the terminal I/O of every Terminal in
+// the process, for metrics.
+var (
+	bytesRead    atomic.Uint64
+	bytesWritten atomic.Uint64
+)
+
+// IOTotals returns the bytes read from and written to all terminals since
+// the process started.
+func IOTotals() (read, written uint64) {
+	return bytesRead.Load(), bytesWritten.Load()
+}
+
 // PID returns the process id.
 func (t *Terminal) PID() int {
 	t.mu.RLock()
@@ -39,6 +53,7 @@ func (t *Terminal) Write(data []byte) (int, error) {
 	}
 	if t.pty != nil {
 		n, err := t.pty.Write(data)
+		bytesWritten.Add(uint64(max(n, 0)))
 		if err != nil {
 			slog.Warn("[terminal] Write failed", "error", err, "dataLen", len(data))
 		}
@@ -46,6 +61,7 @@ func (t *Terminal) Write(data []byte) (int, error) {
 	}
 	if t.ptmx != nil {
 		n, err := t.ptmx.Write(data)
+		bytesWritten.Add(uint64(max(n, 0)))
 		if err != nil {
 			slog.Warn("[terminal] Write (ptmx) failed", "error", err, "dataLen", len(data))
 		}
@@ -56,6 +72,7 @@ func (t *Terminal) Write(data []byte) (int, error) {
 	}
 	payload := normalizePipeInput(data)
 	n, err := t.stdin.Write(payload)
+	bytesWritten.Add(uint64(max(n, 0)))
 	if err != nil {
 		slog.Warn("[terminal] Write (stdin) failed", "error", err, "dataLen", len(data))
 	}
@@ -134,6 +151,7 @@ func readSource(reader io.Reader, onData func([]byte)) {
 	for {
 		n, err := reader.Read(buf)
 		if n > 0 {
+			bytesRead.Add(uint64(n))
 			// onData must consume the bytes during this call because the backing
 			// buffer is reused on the next read.
 			onData(buf[:n])
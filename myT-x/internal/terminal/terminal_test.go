@@ -1,6 +1,7 @@
 package terminal
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -76,3 +77,18 @@ func TestWritePipeModeConvertsCRToCRLF(t *testing.T) {
 		t.Fatalf("pipe input = %q, want %q", got, "cmd\\r\\n")
 	}
 }
+
+func TestReadSourceCountsBytes(t *testing.T) {
+	readBefore, _ := IOTotals()
+	var got []byte
+	readSource(strings.NewReader("hello"), func(chunk []byte) {
+		got = append(got, chunk...)
+	})
+	readAfter, _ := IOTotals()
+	if string(got) != "hello" {
+		t.Fatalf("readSource() delivered %q", got)
+	}
+	if readAfter-readBefore != 5 {
+		t.Fatalf("IOTotals() read delta = %d, want 5", readAfter-readBefore)
+	}
+}
@@ -3,6 +3,7 @@ package tmux
 import (
 	"context"
 	"fmt"
+	"io"
 	"log/slog"
 	"maps"
 	"os"
@@ -11,6 +12,7 @@ import (
 
 	"myT-x/internal/apptypes"
 	"myT-x/internal/ipc"
+	"myT-x/internal/terminal"
 )
 
 // DefaultTerminalCols is the default terminal width when no explicit size is provided.
@@ -53,6 +55,55 @@ type RouterOptions struct {
 	// Used by the MCP bridge CLI to auto-detect the session when --session and
 	// $MYTX_SESSION are unavailable.
 	ResolveSessionByCwd func(cwd string) (string, error)
+	// CopyBetweenSessions copies srcPath of srcSession to dstPath of
+	// dstSession (copy-between-sessions) and returns the destination path.
+	// Optional: nil makes copy-between-sessions fail.
+	CopyBetweenSessions func(srcSession, srcPath, dstSession, dstPath string) (string, error)
+	// ResolveShell picks the shell for a pane from its working directory
+	// (config shell_rules). An empty result falls back to DefaultShell.
+	// Optional: nil means every pane uses DefaultShell.
+	ResolveShell func(workDir string) string
+	// ResolveWindowStartupCommand returns the configured startup command for
+	// the first pane of a new window (config startup_commands.window). It is
+	// used only when new-window carries no positional shell command.
+	// Optional: nil means new windows start without a startup command.
+	ResolveWindowStartupCommand func() (command string, remainOnExit bool)
+	// AcquireWarmTerminal returns a pre-started shell already moved to
+	// workDir with env applied, or nil when none is available for shell.
+	// env holds only the pane-specific variables, sanitized.
+	// Optional: nil means every pane starts a new shell.
+	AcquireWarmTerminal func(shell, workDir string, env map[string]string, cols, rows int) *terminal.Terminal
+	// FoldOutput reports whether new panes fold repeated lines in their
+	// capture-pane history (config output_folding).
+	// Optional: nil means history is never folded.
+	FoldOutput func() bool
+	// ResolveGitIdentityEnv returns the GIT_AUTHOR_*/GIT_COMMITTER_* variables
+	// of the git identity configured for a pane (config git_identities), or
+	// nil. They fill env without replacing variables already set.
+	// Optional: nil means panes inherit the global git identity.
+	ResolveGitIdentityEnv func(sessionName, workDir string) map[string]string
+	// ResolveToolPaths returns the repo-local tool directories to prepend to
+	// a pane's PATH (config tool_paths), or nil.
+	// Optional: nil means panes inherit the app's PATH unchanged.
+	ResolveToolPaths func(sessionName, workDir string) []string
+	// ResolveShellHistoryDir returns the directory holding the shell history
+	// of a session whose history is isolated, or "" to keep the user's
+	// global history.
+	// Optional: nil means shell history is never isolated.
+	ResolveShellHistoryDir func(sessionName, workDir string) string
+	// ResolveNetworkCaptureEnv returns the proxy variables of a session whose
+	// network capture is on, or nil. They replace inherited values.
+	// Optional: nil means panes never use a capture proxy.
+	ResolveNetworkCaptureEnv func(sessionName string) map[string]string
+	// ControlModeEnabled reports whether control mode (ipc.ControlModeFlag)
+	// may be used (feature flag control_mode).
+	// Optional: nil means control mode is always available.
+	ControlModeEnabled func() bool
+	// OnPaneStarted is called with the shell PID after a pane's terminal
+	// starts (new-session, new-window, split-window, respawn-pane), e.g. to
+	// place the shell under the session's resource limits.
+	// Optional: nil means no callback.
+	OnPaneStarted func(sessionName string, paneID string, pid int)
 }
 
 // CommandRouter dispatches tmux-compatible commands.
@@ -70,6 +121,11 @@ type CommandRouter struct {
 	buffers     *BufferStore
 	options     *compatOptionStore
 	handlers    map[string]func(ipc.TmuxRequest) ipc.TmuxResponse
+	// startupPanes tracks pane IDs (int) that ran a startup command so their
+	// process exit closes the pane unless remain-on-exit is on.
+	startupPanes sync.Map
+	// control fans pane output out to control mode (tmux -C) clients.
+	control controlModeHub
 	// renamePane is a narrow test seam used to force non-fatal rename errors.
 	renamePane func(paneID string, title string) (string, error)
 	// attachTerminalFn is a test seam for attach/rollback paths.
@@ -179,6 +235,9 @@ func NewCommandRouter(sessions *SessionManager, emitter EventEmitter, opts Route
 		"if-shell":               router.handleIfShell,
 		"mcp-resolve-stdio":      router.handleMCPResolveStdio,
 		"resolve-session-by-cwd": router.handleResolveSessionByCwd,
+		"copy-between-sessions":  router.handleCopyBetweenSessions,
+		ipc.ServerInfoCommand:    router.handleServerInfo,
+		ListCommandsCommand:      router.handleListCommands,
 	}
 	return router
 }
@@ -312,6 +371,25 @@ func (r *CommandRouter) Execute(req ipc.TmuxRequest) ipc.TmuxResponse {
 	}
 }
 
+// ExecuteStream runs req like Execute. The output of a foreground run-shell
+// command is written to stdout as it is produced, and the command is killed
+// when ctx is canceled. An attach-session or new-session request with
+// ipc.ControlModeFlag streams control mode notifications to stdout until ctx is
+// canceled. Other commands return their whole output in the response and
+// ignore ctx. run-shell merges the command's stderr into stdout, so stderr
+// is unused.
+func (r *CommandRouter) ExecuteStream(ctx context.Context, req ipc.TmuxRequest, stdout, _ io.Writer) ipc.TmuxResponse {
+	switch canonicalTmuxCommandName(strings.TrimSpace(req.Command)) {
+	case "run-shell":
+		return r.runShell(ctx, req, stdout)
+	case "attach-session", "new-session":
+		if mustBool(req.Flags[ipc.ControlModeFlag]) {
+			return r.runControlMode(ctx, req, stdout)
+		}
+	}
+	return r.Execute(req)
+}
+
 // ---------------------------------------------------------------------------
 // Shared target resolution helpers (used by multiple handler files)
 // ---------------------------------------------------------------------------
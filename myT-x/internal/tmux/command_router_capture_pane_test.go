@@ -1,6 +1,9 @@
 package tmux
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 // TestSelectCapturePaneLines_ReturnsErrorForInvalidFlag_ByDesign locks in the
 // contract that selectCapturePaneLines returns a non-nil error when -S/-E
@@ -51,3 +54,29 @@ func TestSelectCapturePaneLines_EmptyDataReturnsNoError(t *testing.T) {
 		t.Fatalf("empty data must return nil slice; got %q", string(out))
 	}
 }
+
+func TestJoinWrappedCaptureLines(t *testing.T) {
+	full := strings.Repeat("a", 10)
+	cases := []struct {
+		name  string
+		data  string
+		width int
+		want  string
+	}{
+		{"joins full-width lines", full + "\n" + "bcd\n" + "e\n", 10, full + "bcd\n" + "e\n"},
+		{"joins a chain of wraps", full + "\r\n" + full + "\r\n" + "x\r\n", 10, full + full + "x\r\n"},
+		{"keeps short lines", "short\nline\n", 10, "short\nline\n"},
+		{"ignores escape sequences", "\x1b[31m" + full[:9] + "\x1b[0m\n" + "next\n", 10, "\x1b[31m" + full[:9] + "\x1b[0m\n" + "next\n"},
+		{"ignores OSC titles", "\x1b]0;title\x07" + full + "\n" + "next\n", 10, "\x1b]0;title\x07" + full + "next\n"},
+		{"counts wide runes as two cells", "あいうえお\n" + "next\n", 10, "あいうえおnext\n"},
+		{"keeps a final unterminated line", "tail", 4, "tail"},
+		{"unknown width leaves data unchanged", full + "\nx\n", 0, full + "\nx\n"},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(joinWrappedCaptureLines([]byte(tt.data), tt.width)); got != tt.want {
+				t.Fatalf("joinWrappedCaptureLines() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/rivo/uniseg"
+
 	"myT-x/internal/ipc"
 )
 
@@ -136,12 +138,10 @@ func (r *CommandRouter) handlePasteBuffer(req ipc.TmuxRequest) ipc.TmuxResponse
 
 	// Bracket paste mode: wrap data with escape sequences.
 	if bracketPaste {
-		bracketStart := []byte("\033[200~")
-		bracketEnd := []byte("\033[201~")
-		wrapped := make([]byte, 0, len(bracketStart)+len(data)+len(bracketEnd))
-		wrapped = append(wrapped, bracketStart...)
+		wrapped := make([]byte, 0, len(bracketedPasteStart)+len(data)+len(bracketedPasteEnd))
+		wrapped = append(wrapped, bracketedPasteStart...)
 		wrapped = append(wrapped, data...)
-		wrapped = append(wrapped, bracketEnd...)
+		wrapped = append(wrapped, bracketedPasteEnd...)
 		data = wrapped
 	}
 
@@ -321,6 +321,7 @@ func (r *CommandRouter) handleCapturePane(req ipc.TmuxRequest) ipc.TmuxResponse
 	printToStdout := mustBool(req.Flags["-p"])
 	bufferName := mustString(req.Flags["-b"])
 	quiet := mustBool(req.Flags["-q"])
+	joinWrapped := mustBool(req.Flags["-J"])
 
 	// Resolve target pane.
 	target, err := r.resolveTargetFromRequest(req)
@@ -353,6 +354,9 @@ func (r *CommandRouter) handleCapturePane(req ipc.TmuxRequest) ipc.TmuxResponse
 		}
 		return errResp(err)
 	}
+	if joinWrapped {
+		data = joinWrappedCaptureLines(data, target.Width)
+	}
 
 	if printToStdout {
 		slog.Debug("[DEBUG-BUFFER] capture-pane: print to stdout", "pane", targetPaneID, "size", len(data))
@@ -416,6 +420,60 @@ func capturePaneLineSpans(data []byte) []capturePaneLineSpan {
 	return spans
 }
 
+// joinWrappedCaptureLines implements capture-pane -J. The history holds raw
+// output, where the terminal's line wraps appear as line breaks after a
+// full-width line; a line whose visible width reaches paneWidth is therefore
+// joined with the line that follows it. Escape sequences do not count
+// toward the width.
+func joinWrappedCaptureLines(data []byte, paneWidth int) []byte {
+	if len(data) == 0 || paneWidth <= 0 {
+		return data
+	}
+	out := make([]byte, 0, len(data))
+	for _, span := range capturePaneLineSpans(data) {
+		line := data[span.start:span.end]
+		content := bytes.TrimRight(line, "\r\n")
+		if len(content) < len(line) && capturePaneVisibleWidth(content) >= paneWidth {
+			out = append(out, content...)
+			continue
+		}
+		out = append(out, line...)
+	}
+	return out
+}
+
+// capturePaneVisibleWidth returns the display width of line in cells,
+// skipping CSI, OSC, and other escape sequences and control characters.
+func capturePaneVisibleWidth(line []byte) int {
+	var visible strings.Builder
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case c == 0x1b && i+1 < len(line) && line[i+1] == '[':
+			// CSI: parameters and intermediates up to a final byte 0x40-0x7e.
+			i += 2
+			for i < len(line) && (line[i] < 0x40 || line[i] > 0x7e) {
+				i++
+			}
+		case c == 0x1b && i+1 < len(line) && line[i+1] == ']':
+			// OSC: terminated by BEL or ST (ESC \\).
+			i += 2
+			for i < len(line) && line[i] != 0x07 && (line[i] != 0x1b || i+1 >= len(line) || line[i+1] != '\\') {
+				i++
+			}
+			if i < len(line) && line[i] == 0x1b {
+				i++
+			}
+		case c == 0x1b:
+			i++
+		case c < 0x20 || c == 0x7f:
+		default:
+			visible.WriteByte(c)
+		}
+	}
+	return uniseg.StringWidth(visible.String())
+}
+
 func resolveCapturePaneLineIndex(flag any, lineCount int, isStart bool) (int, error) {
 	if lineCount <= 0 {
 		return 0, nil
@@ -1042,6 +1042,20 @@ func TestHandleCapturePane(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:           "-J joins lines wrapped at the pane width",
+			paneHasHistory: true,
+			historyContent: strings.Repeat("w", 120) + "\r\nrest\r\nline-2\r\n",
+			flags:          map[string]any{"-p": true, "-t": "%0", "-J": true, "-S": "-3"},
+			createPane:     true,
+			wantExitCode:   0,
+			verifyStdout: func(t *testing.T, stdout string) {
+				want := strings.Repeat("w", 120) + "rest\r\nline-2\r\n"
+				if stdout != want {
+					t.Fatalf("stdout = %q, want %q", stdout, want)
+				}
+			},
+		},
 	}
 
 	for _, tt := range tests {
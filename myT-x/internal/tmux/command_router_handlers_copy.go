@@ -0,0 +1,51 @@
+package tmux
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"myT-x/internal/ipc"
+)
+
+// handleCopyBetweenSessions copies a file or directory from one session's
+// directory to another's. -s defaults to the session of the calling pane.
+func (r *CommandRouter) handleCopyBetweenSessions(req ipc.TmuxRequest) ipc.TmuxResponse {
+	if r.opts.CopyBetweenSessions == nil {
+		return errResp(errors.New("copy-between-sessions is unavailable"))
+	}
+	dstSession := parseSessionName(mustString(req.Flags["-t"]))
+	if dstSession == "" {
+		return errResp(fmt.Errorf("missing required flag: -t"))
+	}
+	if len(req.Args) == 0 || strings.TrimSpace(req.Args[0]) == "" {
+		return errResp(errors.New("copy-between-sessions requires a source path"))
+	}
+	if len(req.Args) > 2 {
+		return errResp(fmt.Errorf("expected at most 2 positional arguments, got %d", len(req.Args)))
+	}
+	srcPath := req.Args[0]
+	dstPath := ""
+	if len(req.Args) == 2 {
+		dstPath = req.Args[1]
+	}
+
+	srcSession := parseSessionName(mustString(req.Flags["-s"]))
+	if srcSession == "" {
+		callerPane, err := r.sessions.ResolveTarget("", ParseCallerPane(req.CallerPane))
+		if err != nil {
+			return errResp(fmt.Errorf("no source session: pass -s or run from a pane: %w", err))
+		}
+		paneCtx, err := r.sessions.GetPaneContextSnapshot(callerPane.ID)
+		if err != nil {
+			return errResp(err)
+		}
+		srcSession = paneCtx.SessionName
+	}
+
+	destination, err := r.opts.CopyBetweenSessions(srcSession, srcPath, dstSession, dstPath)
+	if err != nil {
+		return errResp(err)
+	}
+	return okResp(destination + "\n")
+}
@@ -0,0 +1,89 @@
+package tmux
+
+import (
+	"strings"
+	"testing"
+
+	"myT-x/internal/ipc"
+)
+
+func TestHandleCopyBetweenSessions(t *testing.T) {
+	sessions := NewSessionManager()
+	_, pane, err := sessions.CreateSession("agent-a", "0", 120, 40)
+	if err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+
+	type call struct{ srcSession, srcPath, dstSession, dstPath string }
+	var got []call
+	router := NewCommandRouter(sessions, nil, RouterOptions{
+		CopyBetweenSessions: func(srcSession, srcPath, dstSession, dstPath string) (string, error) {
+			got = append(got, call{srcSession, srcPath, dstSession, dstPath})
+			return `C:\wt\agent-b\` + srcPath, nil
+		},
+	})
+
+	resp := router.Execute(ipc.TmuxRequest{
+		Command:    "copy-between-sessions",
+		Flags:      map[string]any{"-t": "agent-b"},
+		Args:       []string{"dist/app.zip"},
+		CallerPane: pane.IDString(),
+	})
+	if resp.ExitCode != 0 {
+		t.Fatalf("ExitCode = %d, stderr = %q", resp.ExitCode, resp.Stderr)
+	}
+	if resp.Stdout != `C:\wt\agent-b\dist/app.zip`+"\n" {
+		t.Fatalf("Stdout = %q, want the destination path", resp.Stdout)
+	}
+
+	resp = router.Execute(ipc.TmuxRequest{
+		Command: "copy-between-sessions",
+		Flags:   map[string]any{"-s": "agent-c", "-t": "agent-b:0"},
+		Args:    []string{"fix.patch", "patches/"},
+	})
+	if resp.ExitCode != 0 {
+		t.Fatalf("ExitCode = %d, stderr = %q", resp.ExitCode, resp.Stderr)
+	}
+
+	want := []call{
+		{"agent-a", "dist/app.zip", "agent-b", ""},
+		{"agent-c", "fix.patch", "agent-b", "patches/"},
+	}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("calls = %+v, want %+v", got, want)
+	}
+}
+
+func TestHandleCopyBetweenSessionsRejectsBadRequests(t *testing.T) {
+	router := NewCommandRouter(NewSessionManager(), nil, RouterOptions{
+		CopyBetweenSessions: func(string, string, string, string) (string, error) {
+			t.Fatal("CopyBetweenSessions must not be called")
+			return "", nil
+		},
+	})
+	cases := []struct {
+		name string
+		req  ipc.TmuxRequest
+		want string
+	}{
+		{"missing target", ipc.TmuxRequest{Args: []string{"a.txt"}}, "-t"},
+		{"missing source path", ipc.TmuxRequest{Flags: map[string]any{"-t": "b"}}, "source path"},
+		{"too many args", ipc.TmuxRequest{Flags: map[string]any{"-t": "b"}, Args: []string{"a", "b", "c"}}, "at most 2"},
+		{"no source session", ipc.TmuxRequest{Flags: map[string]any{"-t": "b"}, Args: []string{"a.txt"}}, "no source session"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.req.Command = "copy-between-sessions"
+			resp := router.Execute(tc.req)
+			if resp.ExitCode == 0 || !strings.Contains(resp.Stderr, tc.want) {
+				t.Fatalf("resp = %+v, want an error containing %q", resp, tc.want)
+			}
+		})
+	}
+
+	unavailable := NewCommandRouter(NewSessionManager(), nil, RouterOptions{})
+	resp := unavailable.Execute(ipc.TmuxRequest{Command: "copy-between-sessions", Flags: map[string]any{"-t": "b"}, Args: []string{"a"}})
+	if resp.ExitCode == 0 || !strings.Contains(resp.Stderr, "unavailable") {
+		t.Fatalf("resp = %+v, want unavailable error", resp)
+	}
+}
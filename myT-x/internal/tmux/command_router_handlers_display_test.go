@@ -0,0 +1,52 @@
+package tmux
+
+import (
+	"testing"
+
+	"myT-x/internal/ipc"
+)
+
+func TestHandleDisplayMessageExpandsSelfIdentification(t *testing.T) {
+	sessions := NewSessionManager()
+	t.Cleanup(sessions.Close)
+	router := NewCommandRouter(sessions, &captureEmitter{}, RouterOptions{ShimAvailable: true})
+
+	if _, _, err := sessions.CreateSession("agents", "main", 120, 40); err != nil {
+		t.Fatalf("CreateSession error: %v", err)
+	}
+	if err := sessions.SetRootPath("agents", `C:\repo`); err != nil {
+		t.Fatalf("SetRootPath error: %v", err)
+	}
+
+	display := func(format string) string {
+		t.Helper()
+		resp := router.Execute(ipc.TmuxRequest{
+			Command:    "display-message",
+			Flags:      map[string]any{"-p": true},
+			Args:       []string{format},
+			CallerPane: "%0",
+		})
+		if resp.ExitCode != 0 {
+			t.Fatalf("display-message %q exit code = %d, stderr = %q", format, resp.ExitCode, resp.Stderr)
+		}
+		return resp.Stdout
+	}
+
+	if got := display("#{session_name}:#{window_index}.#{pane_index}"); got != "agents:0.0\n" {
+		t.Fatalf("display-message self target = %q", got)
+	}
+	// Without a terminal the pane has no process; the path falls back to the session root.
+	if got := display("#{window_name} #{pane_pid} #{pane_current_path}"); got != `main 0 C:\repo`+"\n" {
+		t.Fatalf("display-message pane details = %q", got)
+	}
+
+	sessions.setPaneStartDir(0, `C:\repo\sub`)
+	if got := display("#{pane_current_path}"); got != `C:\repo\sub`+"\n" {
+		t.Fatalf("display-message pane_current_path after start dir = %q", got)
+	}
+
+	resp := router.Execute(ipc.TmuxRequest{Command: "display-message", Args: []string{"hello"}, CallerPane: "%0"})
+	if resp.ExitCode != 0 || resp.Stdout != "" {
+		t.Fatalf("display-message without -p = %+v, want empty output", resp)
+	}
+}
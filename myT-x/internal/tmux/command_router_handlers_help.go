@@ -0,0 +1,302 @@
+package tmux
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"myT-x/internal/ipc"
+)
+
+// ListCommandsCommand lists the supported commands, or describes one.
+const ListCommandsCommand = "list-commands"
+
+// CommandHelp documents one command served by the router.
+type CommandHelp struct {
+	// Usage is the synopsis in tmux notation, starting with the command name.
+	Usage string
+	// Description is a one-line summary.
+	Description string
+	// Notes list myT-x differences from real tmux: unsupported or ignored
+	// flags and changed behavior.
+	Notes []string
+	// Internal marks commands used between myT-x processes that
+	// list-commands does not show.
+	Internal bool
+}
+
+// commandHelp is the registry behind list-commands and the shim's --help.
+// Every handler registered in NewCommandRouter must have an entry.
+var commandHelp = map[string]CommandHelp{
+	"new-session": {
+		Usage:       "new-session [-dP] [-c start-directory] [-e VARIABLE=value] [-F format] [-n window-name] [-s session-name] [-x width] [-y height] [command]",
+		Description: "Create a new session.",
+		Notes: []string{
+			"Without -d the app window is brought to the foreground; there is no client to attach.",
+			"Run from inside a pane ($TMUX set), the shim refuses it without -d like tmux; unset TMUX to force.",
+		},
+	},
+	"has-session": {
+		Usage:       "has-session -t target-session",
+		Description: "Exit 0 when the target session exists, 1 otherwise.",
+	},
+	"split-window": {
+		Usage:       "split-window [-dhPv] [-c start-directory] [-e VARIABLE=value] [-F format] [-l size] [-p percentage] [-t target-pane] [command]",
+		Description: "Split the target pane.",
+		Notes: []string{
+			"-l and -p are accepted but the new pane always takes half of the target pane.",
+			"The new pane ID is printed even without -P.",
+		},
+	},
+	"send-keys": {
+		Usage:       "send-keys [-lMNWX] [-t target-pane] key ...",
+		Description: "Send keys or literal text to a pane.",
+		Notes: []string{
+			"-M (mouse passthrough) is accepted as a no-op.",
+			"-W (myT-x only) types the input key by key for interactive TUIs.",
+			"-N (myT-x only) sends CR as CRLF for ConPTY Enter compatibility.",
+			"-X sends copy-mode commands as key sequences; unknown commands succeed silently.",
+		},
+	},
+	"select-pane": {
+		Usage:       "select-pane [-DLRU] [-P style] [-T title] [-t target-pane]",
+		Description: "Focus a pane, or move focus in a direction.",
+		Notes: []string{
+			"-P (pane style) is accepted and ignored.",
+		},
+	},
+	"list-sessions": {
+		Usage:       "list-sessions [-F format] [-f filter]",
+		Description: "List sessions.",
+	},
+	"kill-session": {
+		Usage:       "kill-session -t target-session",
+		Description: "Close the target session and its panes.",
+	},
+	"list-panes": {
+		Usage:       "list-panes [-as] [-F format] [-f filter] [-t target]",
+		Description: "List the panes of a window, a session (-s), or all sessions (-a).",
+	},
+	"display-message": {
+		Usage:       "display-message -p [-t target-pane] [message]",
+		Description: "Print a message expanded as a format.",
+		Notes: []string{
+			"Only -p is supported; without it nothing is displayed and the command succeeds.",
+		},
+	},
+	"attach-session": {
+		Usage:       "attach-session [-t target-session]",
+		Description: "Bring the app window to the foreground on the target session.",
+		Notes: []string{
+			"There are no tmux clients; the command clears the detached state of a session created with -d and returns immediately.",
+			"Run from inside a pane ($TMUX set), the shim refuses it like tmux; unset TMUX to force.",
+		},
+	},
+	"kill-pane": {
+		Usage:       "kill-pane [-t target-pane]",
+		Description: "Close the target pane.",
+	},
+	"rename-session": {
+		Usage:       "rename-session [-t target-session] new-name",
+		Description: "Rename a session.",
+	},
+	"resize-pane": {
+		Usage:       "resize-pane [-DLRUZ] [-t target-pane] [-x width] [-y height] [adjustment]",
+		Description: "Resize or zoom a pane.",
+	},
+	"select-layout": {
+		Usage:       "select-layout [-Eno] [-p] [-t target-window] [layout-name]",
+		Description: "Apply a layout preset or a layout string to a window.",
+		Notes: []string{
+			"Without a layout the command is a no-op; layout cycling (-n, -p, -o, -E) belongs to the app UI.",
+		},
+	},
+	"show-environment": {
+		Usage:       "show-environment [-g] [-t target-session] [variable]",
+		Description: "Show session or global environment variables.",
+	},
+	"set-environment": {
+		Usage:       "set-environment [-gu] [-t target-session] variable [value]",
+		Description: "Set or unset a session environment variable.",
+		Notes: []string{
+			"-g is accepted as a no-op; the global environment comes from the app config.",
+		},
+	},
+	"set-option": {
+		Usage:       "set-option [-aFgopqsuw] [-t target] option [value]",
+		Description: "Set an option.",
+		Notes: []string{
+			"Only the focus-events compatibility option is stored; other options are rejected unless -q is given.",
+		},
+	},
+	"show-options": {
+		Usage:       "show-options [-AgHpqsvw] [-t target] [option]",
+		Description: "Show options. Alias: show.",
+		Notes: []string{
+			"Only the focus-events compatibility option is reported.",
+		},
+	},
+	"list-windows": {
+		Usage:       "list-windows [-a] [-F format] [-f filter] [-t target-session]",
+		Description: "List windows.",
+		Notes: []string{
+			"Sessions have one window; new-window creates a child session instead of a window.",
+		},
+	},
+	"rename-window": {
+		Usage:       "rename-window [-t target-window] new-name",
+		Description: "Rename a window.",
+	},
+	"new-window": {
+		Usage:       "new-window -t parent-session -n child-name [-dP] [-c start-directory] [-e VARIABLE=value] [-F format] [command]",
+		Description: "Create a child session of the parent session.",
+		Notes: []string{
+			"Unlike tmux, no window is added: a child session named by -n is created, so -n is required.",
+			"The child session inherits the session-level settings of its parent.",
+		},
+	},
+	"kill-window": {
+		Usage:       "kill-window [-t target-window]",
+		Description: "Close a window and its panes.",
+		Notes: []string{
+			"Closing the only window of a session empties the session.",
+		},
+	},
+	"select-window": {
+		Usage:       "select-window -t target-window",
+		Description: "Focus a window.",
+	},
+	"copy-mode": {
+		Usage:       "copy-mode [-equ] [-t target-pane]",
+		Description: "Enter or leave copy mode in the app's scrollback view.",
+		Notes: []string{
+			"-e and -u are accepted and ignored.",
+		},
+	},
+	"list-buffers": {
+		Usage:       "list-buffers [-F format]",
+		Description: "List paste buffers.",
+	},
+	"set-buffer": {
+		Usage:       "set-buffer [-a] [-b buffer-name] [-n new-buffer-name] data",
+		Description: "Create, append to, or rename a paste buffer.",
+	},
+	"paste-buffer": {
+		Usage:       "paste-buffer [-dpr] [-b buffer-name] [-s separator] [-t target-pane]",
+		Description: "Paste a buffer into a pane.",
+		Notes: []string{
+			"-r replaces LF with CR instead of leaving line endings unchanged.",
+		},
+	},
+	"delete-buffer": {
+		Usage:       "delete-buffer [-b buffer-name]",
+		Description: "Delete a paste buffer, or the latest one without -b.",
+	},
+	"load-buffer": {
+		Usage:       "load-buffer [-w] [-b buffer-name] [-t target-client] path",
+		Description: "Load a file into a paste buffer.",
+		Notes: []string{
+			"Reading from stdin (-) is not supported.",
+			"-w and -t are accepted as no-ops.",
+		},
+	},
+	"save-buffer": {
+		Usage:       "save-buffer [-a] [-b buffer-name] path",
+		Description: "Save a paste buffer to a file.",
+	},
+	"capture-pane": {
+		Usage:       "capture-pane [-aCeJMNpPqT] [-b buffer-name] [-E end-line] [-S start-line] [-t target-pane]",
+		Description: "Capture the contents of a pane.",
+		Notes: []string{
+			"Captures from the pane's retained output history, not a rendered screen.",
+			"-J joins lines that filled the pane width with the line that follows them.",
+			"-a, -C, -e, -M, -N, -P, and -T are accepted and ignored.",
+		},
+	},
+	"run-shell": {
+		Usage:       "run-shell [-bC] [-c start-directory] [-t target-pane] shell-command",
+		Description: "Run a shell command, or tmux commands with -C.",
+		Notes: []string{
+			"Output of long-running commands is streamed to the shim as it is produced.",
+		},
+	},
+	"if-shell": {
+		Usage:       "if-shell [-bF] [-t target-pane] shell-command command [command]",
+		Description: "Run the first command if the condition succeeds, otherwise the second.",
+	},
+	"copy-between-sessions": {
+		Usage:       "copy-between-sessions [-s source-session] -t target-session source-path [target-path]",
+		Description: "Copy a file or directory from one session's directory to another's.",
+		Notes: []string{
+			"myT-x only. -s defaults to the session of the calling pane.",
+			"Relative paths are resolved against each session's directory (worktree or root) and must stay inside it.",
+			"Without target-path the source keeps its relative path; an existing directory or a trailing separator receives the source inside it.",
+			"Existing files are replaced and directories merged; symbolic links and .git entries inside a directory are skipped.",
+		},
+	},
+	ListCommandsCommand: {
+		Usage:       "list-commands [command]",
+		Description: "List the supported commands, or show the help of one command.",
+		Notes: []string{
+			"-F is not supported.",
+		},
+	},
+	"activate-window": {
+		Usage:       "activate-window [link]",
+		Description: "Bring the app window to the foreground.",
+		Internal:    true,
+	},
+	"mcp-resolve-stdio": {
+		Usage:       "mcp-resolve-stdio",
+		Description: "Resolve the MCP stdio endpoint of a session.",
+		Internal:    true,
+	},
+	"resolve-session-by-cwd": {
+		Usage:       "resolve-session-by-cwd directory",
+		Description: "Resolve the session whose root contains a directory.",
+		Internal:    true,
+	},
+	ipc.ServerInfoCommand: {
+		Usage:       "server-info",
+		Description: "Describe the server process and its sessions.",
+		Internal:    true,
+	},
+}
+
+// handleListCommands prints the usage of every public command, or the full
+// help of the command named by the first argument.
+func (r *CommandRouter) handleListCommands(req ipc.TmuxRequest) ipc.TmuxResponse {
+	if len(req.Args) > 0 {
+		name := strings.TrimSpace(req.Args[0])
+		help, ok := commandHelp[name]
+		if !ok {
+			return errResp(fmt.Errorf("unknown command: %s", name))
+		}
+		return okResp(formatCommandHelp(help))
+	}
+
+	names := make([]string, 0, len(commandHelp))
+	for name, help := range commandHelp {
+		if !help.Internal {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	lines := make([]string, 0, len(names))
+	for _, name := range names {
+		lines = append(lines, commandHelp[name].Usage)
+	}
+	return okResp(joinLines(lines))
+}
+
+func formatCommandHelp(help CommandHelp) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "usage: %s\n%s\n", help.Usage, help.Description)
+	if len(help.Notes) > 0 {
+		b.WriteString("\nmyT-x notes:\n")
+		for _, note := range help.Notes {
+			fmt.Fprintf(&b, "  - %s\n", note)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,63 @@
+package tmux
+
+import (
+	"strings"
+	"testing"
+
+	"myT-x/internal/ipc"
+)
+
+func TestCommandHelpCoversEveryHandler(t *testing.T) {
+	router := NewCommandRouter(NewSessionManager(), nil, RouterOptions{ShimAvailable: true})
+	for name := range router.handlers {
+		help, ok := commandHelp[name]
+		if !ok {
+			t.Errorf("handler %q has no commandHelp entry", name)
+			continue
+		}
+		if !strings.HasPrefix(help.Usage, name) || help.Description == "" {
+			t.Errorf("commandHelp[%q] = %+v, want a usage starting with the name and a description", name, help)
+		}
+	}
+	for name := range commandHelp {
+		if _, ok := router.handlers[name]; !ok {
+			t.Errorf("commandHelp entry %q has no handler", name)
+		}
+	}
+}
+
+func TestHandleListCommands(t *testing.T) {
+	router := NewCommandRouter(NewSessionManager(), nil, RouterOptions{})
+
+	resp := router.Execute(ipc.TmuxRequest{Command: ListCommandsCommand})
+	if resp.ExitCode != 0 {
+		t.Fatalf("list-commands ExitCode = %d, stderr = %q", resp.ExitCode, resp.Stderr)
+	}
+	lines := strings.Split(strings.TrimSuffix(resp.Stdout, "\n"), "\n")
+	if len(lines) == 0 || !strings.HasPrefix(lines[0], "attach-session ") {
+		t.Fatalf("first line = %q, want the sorted usage list", lines[0])
+	}
+	if strings.Contains(resp.Stdout, "server-info") || strings.Contains(resp.Stdout, "mcp-resolve-stdio") {
+		t.Fatalf("list-commands shows internal commands:\n%s", resp.Stdout)
+	}
+
+	resp = router.Execute(ipc.TmuxRequest{Command: ListCommandsCommand, Args: []string{"new-window"}})
+	if resp.ExitCode != 0 {
+		t.Fatalf("list-commands new-window ExitCode = %d, stderr = %q", resp.ExitCode, resp.Stderr)
+	}
+	for _, want := range []string{"usage: new-window -t parent-session -n child-name", "myT-x notes:", "  - Unlike tmux"} {
+		if !strings.Contains(resp.Stdout, want) {
+			t.Errorf("help output missing %q:\n%s", want, resp.Stdout)
+		}
+	}
+
+	resp = router.Execute(ipc.TmuxRequest{Command: ListCommandsCommand, Args: []string{"has-session"}})
+	if strings.Contains(resp.Stdout, "myT-x notes") {
+		t.Errorf("help without notes should not print a notes section:\n%s", resp.Stdout)
+	}
+
+	resp = router.Execute(ipc.TmuxRequest{Command: ListCommandsCommand, Args: []string{"choose-tree"}})
+	if resp.ExitCode != 1 || !strings.Contains(resp.Stderr, "unknown command: choose-tree") {
+		t.Fatalf("unknown command resp = %+v", resp)
+	}
+}
@@ -92,16 +92,59 @@ func formatShowOptionLine(optionName string, value string, valueOnly bool) strin
 	return fmt.Sprintf("%s %s", optionName, value)
 }
 
-// handleSelectLayout accepts tmux layout commands but treats them as a no-op
-// because pane layout selection is managed by the application UI.
+// handleSelectLayout applies a built-in preset name or a tmux layout dump
+// (as printed by #{window_layout}) to the target window. Without a layout
+// argument the command is accepted as a no-op because layout cycling
+// (-n/-p/-o/-E) is managed by the application UI.
 func (r *CommandRouter) handleSelectLayout(req ipc.TmuxRequest) ipc.TmuxResponse {
-	slog.Debug("[DEBUG-OPTION] select-layout ignored (tmux compatibility no-op)",
-		"flags", req.Flags,
-		"args", req.Args,
-	)
+	layout := ""
+	if len(req.Args) > 0 {
+		layout = strings.TrimSpace(req.Args[0])
+	}
+	if layout == "" {
+		slog.Debug("[DEBUG-OPTION] select-layout without layout ignored (tmux compatibility no-op)",
+			"flags", req.Flags,
+		)
+		return okResp("")
+	}
+
+	sessionName, windowID, err := r.resolveLayoutWindowFromRequest(req)
+	if err != nil {
+		return errResp(err)
+	}
+	switch {
+	case IsBuiltinLayoutPreset(layout):
+		err = r.sessions.ApplyLayoutPresetByWindowID(sessionName, windowID, LayoutPreset(layout))
+	case IsLayoutString(layout):
+		err = r.sessions.ApplyLayoutStringByWindowID(sessionName, windowID, layout)
+	default:
+		err = fmt.Errorf("invalid layout: %s", layout)
+	}
+	if err != nil {
+		return errResp(err)
+	}
+	r.emitLayoutChangedForSession(sessionName, windowID, "DEBUG-SELECTLAYOUT")
 	return okResp("")
 }
 
+// resolveLayoutWindowFromRequest resolves the select-layout target window.
+// Unlike resolveWindowIDFromRequest, -t is optional: the caller pane's window
+// is used when it is omitted, matching tmux.
+func (r *CommandRouter) resolveLayoutWindowFromRequest(req ipc.TmuxRequest) (string, int, error) {
+	if strings.TrimSpace(mustString(req.Flags["-t"])) != "" {
+		return r.resolveWindowIDFromRequest(req)
+	}
+	pane, err := r.resolveTargetFromRequest(req)
+	if err != nil {
+		return "", 0, err
+	}
+	paneCtx, err := r.sessions.GetPaneContextSnapshot(pane.ID)
+	if err != nil {
+		return "", 0, fmt.Errorf("cannot resolve window for select-layout: %w", err)
+	}
+	return paneCtx.SessionName, paneCtx.WindowID, nil
+}
+
 func compatOptionErrorResp(commandName string, quiet bool, err error) ipc.TmuxResponse {
 	if quiet {
 		slog.Debug("[DEBUG-OPTION] quiet compatibility option error swallowed",
@@ -251,25 +251,58 @@ func TestHandleSetOptionRejectsUnsupportedScopedOption(t *testing.T) {
 
 func TestHandleSelectLayout(t *testing.T) {
 	tests := []struct {
-		name  string
-		flags map[string]any
-		args  []string
+		name       string
+		flags      map[string]any
+		args       []string
+		wantExit   int
+		wantLayout bool
 	}{
 		{
-			name: "target and preset are ignored",
-			flags: map[string]any{
-				"-t": "demo:0",
-			},
-			args: []string{"main-vertical"},
+			name:     "empty request is a no-op",
+			flags:    map[string]any{"-n": true},
+			wantExit: 0,
+		},
+		{
+			name:       "built-in preset applies to target window",
+			flags:      map[string]any{"-t": "demo:0"},
+			args:       []string{"even-vertical"},
+			wantExit:   0,
+			wantLayout: true,
+		},
+		{
+			name:       "tmux layout string applies to target window",
+			flags:      map[string]any{"-t": "demo:0"},
+			args:       []string{"d463,159x48,0,0{79x48,0,0,0,79x48,80,0,1}"},
+			wantExit:   0,
+			wantLayout: true,
+		},
+		{
+			name:     "unknown layout name fails",
+			flags:    map[string]any{"-t": "demo:0"},
+			args:     []string{"spiral"},
+			wantExit: 1,
 		},
 		{
-			name: "empty request still succeeds",
+			name:     "layout with wrong pane count fails",
+			flags:    map[string]any{"-t": "demo:0"},
+			args:     []string{"b25d,80x24,0,0,0"},
+			wantExit: 1,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			router := NewCommandRouter(NewSessionManager(), &captureEmitter{}, RouterOptions{ShimAvailable: true})
+			sessions := NewSessionManager()
+			defer sessions.Close()
+			_, first, err := sessions.CreateSession("demo", "main", 159, 48)
+			if err != nil {
+				t.Fatalf("CreateSession() error = %v", err)
+			}
+			if _, err := sessions.SplitPane(first.ID, SplitHorizontal); err != nil {
+				t.Fatalf("SplitPane() error = %v", err)
+			}
+			emitter := &captureEmitter{}
+			router := NewCommandRouter(sessions, emitter, RouterOptions{ShimAvailable: true})
 
 			resp := router.Execute(ipc.TmuxRequest{
 				Command: "select-layout",
@@ -277,12 +310,15 @@ func TestHandleSelectLayout(t *testing.T) {
 				Args:    tt.args,
 			})
 
-			if resp.ExitCode != 0 {
-				t.Fatalf("ExitCode = %d, want 0, stderr=%q", resp.ExitCode, resp.Stderr)
+			if resp.ExitCode != tt.wantExit {
+				t.Fatalf("ExitCode = %d, want %d, stderr=%q", resp.ExitCode, tt.wantExit, resp.Stderr)
 			}
 			if resp.Stdout != "" {
 				t.Fatalf("Stdout = %q, want empty", resp.Stdout)
 			}
+			if got := firstEventIndex(emitter.EventNames(), "tmux:layout-changed") >= 0; got != tt.wantLayout {
+				t.Fatalf("layout-changed emitted = %v, want %v", got, tt.wantLayout)
+			}
 		})
 	}
 }
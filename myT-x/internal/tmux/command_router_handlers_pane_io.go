@@ -45,7 +45,12 @@ func (r *CommandRouter) handleSendKeys(req ipc.TmuxRequest) ipc.TmuxResponse {
 		return r.handleSendKeysCopyMode(target, req.Args)
 	}
 
-	payload := TranslateSendKeys(req.Args)
+	modes, err := r.sessions.PaneTerminalModes(target.IDString())
+	if err != nil {
+		slog.Debug("[DEBUG-SENDKEYS] terminal modes unavailable, using defaults",
+			"targetPane", target.IDString(), "error", err)
+	}
+	payload := TranslateSendKeysForModes(req.Args, modes)
 
 	slog.Debug("[DEBUG-SENDKEYS] writing to pane",
 		"targetPane", target.IDString(),
@@ -88,9 +88,19 @@ func (r *CommandRouter) handleKillPane(req ipc.TmuxRequest) ipc.TmuxResponse {
 		preferredWindowID = -1
 	}
 
+	if killErr := r.killPaneWithEvents(paneID, sessionName, preferredWindowID); killErr != nil {
+		return errResp(killErr)
+	}
+	return okResp("")
+}
+
+// killPaneWithEvents kills paneID and emits session-emptied or layout-changed.
+// sessionName/preferredWindowID come from a pre-kill snapshot; an empty
+// sessionName falls back to the name reported by KillPane.
+func (r *CommandRouter) killPaneWithEvents(paneID, sessionName string, preferredWindowID int) error {
 	sName, sessionEmptied, killErr := r.sessions.KillPane(paneID)
 	if killErr != nil {
-		return errResp(killErr)
+		return killErr
 	}
 	if sessionName == "" {
 		sessionName = sName
@@ -103,8 +113,7 @@ func (r *CommandRouter) handleKillPane(req ipc.TmuxRequest) ipc.TmuxResponse {
 	} else {
 		r.emitLayoutChangedForSession(sessionName, preferredWindowID, "DEBUG-KILLPANE")
 	}
-
-	return okResp("")
+	return nil
 }
 
 func (r *CommandRouter) handleResizePane(req ipc.TmuxRequest) ipc.TmuxResponse {
@@ -1,6 +1,7 @@
 package tmux
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
@@ -49,6 +50,22 @@ func (r *CommandRouter) handleNewSession(req ipc.TmuxRequest) ipc.TmuxResponse {
 		}
 	}
 
+	if req.Env[ShellHistoryEnvVar] == ShellHistoryIsolated {
+		if setErr := r.sessions.SetShellHistoryIsolated(session.Name, true); setErr != nil {
+			return rollbackSession("set-shell-history", setErr)
+		}
+	}
+
+	// -d creates a background session: the pane process runs, but the UI does
+	// not surface or focus the session until attach-session (or the user
+	// selecting it) clears the flag.
+	detached := mustBool(req.Flags["-d"])
+	if detached {
+		if setErr := r.sessions.SetDetached(session.Name, true); setErr != nil {
+			return rollbackSession("set-detached", setErr)
+		}
+	}
+
 	// The initial pane of a new session always skips pane_env defaults.
 	// pane_env settings (effort level, custom env vars) are intended for
 	// additional panes only (split-window, new-window).
@@ -65,7 +82,8 @@ func (r *CommandRouter) handleNewSession(req ipc.TmuxRequest) ipc.TmuxResponse {
 	r.bestEffortSendKeys(pane, req.Args, true, "DEBUG-SESSION", paneCtx.SessionName)
 
 	// I-16: Emit session-created regardless of -d flag.
-	// The -d flag controls focus (detach), not whether the session was created.
+	// The -d flag controls visibility and focus, not whether the session was
+	// created; the payload carries it so the frontend does not switch to it.
 	emitCtx, emitCtxErr := r.sessions.GetPaneContextSnapshot(pane.ID)
 	if emitCtxErr != nil {
 		slog.Debug("[DEBUG-SESSION] failed to refresh pane context for session-created event",
@@ -77,6 +95,7 @@ func (r *CommandRouter) handleNewSession(req ipc.TmuxRequest) ipc.TmuxResponse {
 		"id":            emitCtx.SessionID,
 		"initialPane":   pane.IDString(),
 		"initialLayout": emitCtx.Layout,
+		"detached":      detached,
 	})
 
 	// -P with -F: format output using tmux format variables.
@@ -278,20 +297,53 @@ func (r *CommandRouter) handleSetEnvironment(req ipc.TmuxRequest) ipc.TmuxRespon
 
 // handleAttachSession activates the app window for the target session.
 // Unlike real tmux, myT-x has no client connection concept. This handler
-// simply emits an "app:activate-window" event to bring the host window
-// to the foreground and returns success without producing stdout output.
+// clears the detached flag of a session created with new-session -d, emits an
+// "app:activate-window" event to bring the host window to the foreground, and
+// returns success without producing stdout output.
 func (r *CommandRouter) handleAttachSession(req ipc.TmuxRequest) ipc.TmuxResponse {
 	target := strings.TrimSpace(mustString(req.Flags["-t"]))
 	if target == "" {
 		return errResp(fmt.Errorf("missing required flag: -t"))
 	}
 	resolvedSession := parseSessionName(target)
-	if _, ok := r.sessions.GetSession(resolvedSession); !ok {
+	wasDetached, err := r.sessions.AttachSession(resolvedSession)
+	if err != nil {
 		return errResp(fmt.Errorf("session not found: %s", resolvedSession))
 	}
 	slog.Debug("[DEBUG-SESSION] attach-session command received", "target", target, "resolvedSession", resolvedSession)
+	if wasDetached {
+		r.emitter.Emit("tmux:session-attached", map[string]any{
+			"name": resolvedSession,
+		})
+	}
 	r.emitter.Emit("app:activate-window", nil)
 	// tmux attach-session does not produce stdout on success.
 	// NOTE: activate-window is an internal IPC command and intentionally returns "ok\n".
 	return okResp("")
 }
+
+// handleServerInfo describes this server to a restarted host that probes the
+// pipe before starting its own server. It reports the host PID and the pane
+// IDs of every session, so the caller can tell which live server holds the
+// pipe and what it would have to adopt.
+func (r *CommandRouter) handleServerInfo(ipc.TmuxRequest) ipc.TmuxResponse {
+	snapshots := r.sessions.Snapshot()
+	info := ipc.ServerInfo{
+		PID:      r.opts.HostPID,
+		Sessions: make([]ipc.ServerSession, 0, len(snapshots)),
+	}
+	for _, snapshot := range snapshots {
+		session := ipc.ServerSession{Name: snapshot.Name, Panes: []string{}}
+		for _, window := range snapshot.Windows {
+			for _, pane := range window.Panes {
+				session.Panes = append(session.Panes, pane.ID)
+			}
+		}
+		info.Sessions = append(info.Sessions, session)
+	}
+	raw, err := json.Marshal(info)
+	if err != nil {
+		return errResp(fmt.Errorf("encode server info: %w", err))
+	}
+	return okResp(string(raw))
+}
@@ -1,6 +1,7 @@
 package tmux
 
 import (
+	"encoding/json"
 	"errors"
 	"sort"
 	"strings"
@@ -187,6 +188,63 @@ func TestHandleAttachSession(t *testing.T) {
 	}
 }
 
+func TestHandleNewSessionDetachedUntilAttached(t *testing.T) {
+	emitter := &captureEmitter{}
+	sessions := NewSessionManager()
+	t.Cleanup(sessions.Close)
+
+	router := NewCommandRouter(sessions, emitter, RouterOptions{ShimAvailable: true})
+	router.attachTerminalFn = func(*TmuxPane, string, map[string]string, *TmuxPane) error {
+		return nil
+	}
+
+	resp := router.Execute(ipc.TmuxRequest{
+		Command: "new-session",
+		Flags:   map[string]any{"-s": "batch", "-d": true},
+	})
+	if resp.ExitCode != 0 {
+		t.Fatalf("new-session -d ExitCode = %d, stderr=%q", resp.ExitCode, resp.Stderr)
+	}
+	session, ok := sessions.GetSession("batch")
+	if !ok || !session.Detached {
+		t.Fatalf("session Detached = %v (found=%v), want true", ok && session.Detached, ok)
+	}
+	var created map[string]any
+	for _, ev := range emitter.Events() {
+		if ev.name == "tmux:session-created" {
+			created, _ = ev.payload.(map[string]any)
+		}
+	}
+	if created == nil || created["detached"] != true {
+		t.Fatalf("session-created payload = %#v, want detached=true", created)
+	}
+
+	resp = router.Execute(ipc.TmuxRequest{
+		Command: "attach-session",
+		Flags:   map[string]any{"-t": "batch"},
+	})
+	if resp.ExitCode != 0 {
+		t.Fatalf("attach-session ExitCode = %d, stderr=%q", resp.ExitCode, resp.Stderr)
+	}
+	session, _ = sessions.GetSession("batch")
+	if session.Detached {
+		t.Fatal("attach-session must clear Detached")
+	}
+	if firstEventIndex(emitter.EventNames(), "tmux:session-attached") < 0 {
+		t.Fatalf("events = %v, want tmux:session-attached", emitter.EventNames())
+	}
+
+	// Attaching an already-attached session does not re-emit session-attached.
+	before := len(emitter.Events())
+	router.Execute(ipc.TmuxRequest{
+		Command: "attach-session",
+		Flags:   map[string]any{"-t": "batch"},
+	})
+	if firstEventIndex(emitter.EventNames()[before:], "tmux:session-attached") >= 0 {
+		t.Fatalf("events = %v, want no tmux:session-attached for attached session", emitter.EventNames())
+	}
+}
+
 func TestHandleKillSession(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -1355,3 +1413,32 @@ func TestBuildPaneEnvForSessionBlockedKeyFiltering(t *testing.T) {
 		})
 	}
 }
+
+func TestHandleServerInfo(t *testing.T) {
+	sessions := NewSessionManager()
+	t.Cleanup(sessions.Close)
+	if _, _, err := sessions.CreateSession("api", "0", 120, 40); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := sessions.CreateSession("web", "0", 120, 40); err != nil {
+		t.Fatal(err)
+	}
+	router := NewCommandRouter(sessions, nil, RouterOptions{HostPID: 4242})
+
+	resp := router.Execute(ipc.TmuxRequest{Command: ipc.ServerInfoCommand})
+	if resp.ExitCode != 0 {
+		t.Fatalf("ExitCode = %d, stderr = %q", resp.ExitCode, resp.Stderr)
+	}
+	var info ipc.ServerInfo
+	if err := json.Unmarshal([]byte(resp.Stdout), &info); err != nil {
+		t.Fatalf("decode server info: %v", err)
+	}
+	if info.PID != 4242 || len(info.Sessions) != 2 {
+		t.Fatalf("server info = %+v, want pid 4242 and 2 sessions", info)
+	}
+	for i, name := range []string{"api", "web"} {
+		if info.Sessions[i].Name != name || len(info.Sessions[i].Panes) != 1 {
+			t.Fatalf("session %d = %+v, want %s with one pane", i, info.Sessions[i], name)
+		}
+	}
+}
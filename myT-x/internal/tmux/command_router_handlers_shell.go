@@ -1,10 +1,16 @@
 package tmux
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"os/exec"
+	"strconv"
 	"strings"
+	"time"
 
 	"myT-x/internal/ipc"
 )
@@ -13,6 +19,14 @@ import (
 // Flags: -b (background), -t (target for format context), -C (tmux commands), -c (work dir).
 // The command string is taken from req.Args.
 func (r *CommandRouter) handleRunShell(req ipc.TmuxRequest) ipc.TmuxResponse {
+	return r.runShell(context.Background(), req, nil)
+}
+
+// runShell implements run-shell. When out is non-nil, the output of a
+// foreground shell command is written to out as it is produced instead of
+// being returned in the response, and the command is killed when ctx is
+// canceled.
+func (r *CommandRouter) runShell(ctx context.Context, req ipc.TmuxRequest, out io.Writer) ipc.TmuxResponse {
 	if len(req.Args) == 0 {
 		return errResp(fmt.Errorf("run-shell requires a command argument"))
 	}
@@ -63,7 +77,21 @@ func (r *CommandRouter) handleRunShell(req ipc.TmuxRequest) ipc.TmuxResponse {
 		return okResp("")
 	}
 
-	stdout, exitCode, err := executeShellCommand(command, workDir)
+	var stdout string
+	var exitCode int
+	var err error
+	if out != nil {
+		exitCode, err = streamShellCommand(ctx, command, workDir, out)
+	} else {
+		stdout, exitCode, err = executeShellCommand(command, workDir)
+	}
+	if errors.Is(err, context.Canceled) {
+		slog.Debug("[DEBUG-RUNSHELL] command canceled by client", "command", command)
+		return ipc.TmuxResponse{
+			ExitCode: exitCode,
+			Stderr:   "run-shell: canceled\n",
+		}
+	}
 	if err != nil {
 		slog.Debug("[DEBUG-RUNSHELL] command failed",
 			"command", command,
@@ -212,19 +240,53 @@ func (r *CommandRouter) handleIfShell(req ipc.TmuxRequest) ipc.TmuxResponse {
 // executeShellCommand runs a command via the system shell and returns its output.
 // On Windows, uses cmd.exe /C. Returns stdout, exit code, and error.
 func executeShellCommand(command string, workDir string) (string, int, error) {
-	cmd := exec.Command("cmd.exe", "/C", command)
+	var output bytes.Buffer
+	exitCode, err := streamShellCommand(context.Background(), command, workDir, &output)
+	if err != nil {
+		return "", exitCode, err
+	}
+	return output.String(), exitCode, nil
+}
+
+// canceledShellExitCode is reported for a shell command killed because its
+// request was canceled, matching a shell interrupted by Ctrl+C.
+const canceledShellExitCode = 130
+
+// shellCancelWaitDelay bounds how long a canceled command may keep its output
+// pipes open (e.g. through a detached grandchild) before Run returns.
+const shellCancelWaitDelay = 2 * time.Second
+
+// streamShellCommand runs a command via the system shell like
+// executeShellCommand, writing its combined stdout and stderr to out as they
+// are produced. Returns the exit code, and an error when the command could
+// not be run. When ctx is canceled the whole process tree is killed and the
+// error is ctx.Err().
+func streamShellCommand(ctx context.Context, command string, workDir string, out io.Writer) (int, error) {
+	cmd := exec.CommandContext(ctx, "cmd.exe", "/C", command)
 	if workDir != "" {
 		cmd.Dir = workDir
 	}
+	cmd.Stdout = out
+	cmd.Stderr = out
+	// Killing only cmd.exe would leave the actual command running.
+	cmd.Cancel = func() error {
+		if err := exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid)).Run(); err != nil {
+			slog.Debug("[DEBUG-RUNSHELL] taskkill failed, killing shell only", "error", err)
+			return cmd.Process.Kill()
+		}
+		return nil
+	}
+	cmd.WaitDelay = shellCancelWaitDelay
 
-	output, err := cmd.CombinedOutput()
-	exitCode := 0
+	err := cmd.Run()
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return canceledShellExitCode, ctxErr
+	}
 	if err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			exitCode = exitErr.ExitCode()
-		} else {
-			return "", 1, err
+		if exitErr, ok := errors.AsType[*exec.ExitError](err); ok {
+			return exitErr.ExitCode(), nil
 		}
+		return 1, err
 	}
-	return string(output), exitCode, nil
+	return 0, nil
 }
@@ -1,8 +1,10 @@
 package tmux
 
 import (
+	"context"
 	"strings"
 	"testing"
+	"time"
 
 	"myT-x/internal/ipc"
 )
@@ -64,6 +66,57 @@ func TestHandleRunShell(t *testing.T) {
 	}
 }
 
+func TestExecuteStreamRunShell(t *testing.T) {
+	sessions := NewSessionManager()
+	t.Cleanup(sessions.Close)
+	router := NewCommandRouter(sessions, nil, RouterOptions{})
+
+	var stdout, stderr strings.Builder
+	resp := router.ExecuteStream(context.Background(), ipc.TmuxRequest{
+		Command: "run-shell",
+		Args:    []string{"echo streamed & exit /b 7"},
+	}, &stdout, &stderr)
+	if resp.ExitCode != 7 {
+		t.Fatalf("exit code = %d, want 7, stderr = %q", resp.ExitCode, resp.Stderr)
+	}
+	if resp.Stdout != "" {
+		t.Fatalf("response stdout = %q, want output streamed only", resp.Stdout)
+	}
+	if !strings.Contains(stdout.String(), "streamed") {
+		t.Fatalf("streamed stdout = %q, want it to contain %q", stdout.String(), "streamed")
+	}
+
+	// Commands other than run-shell are not streamed.
+	stdout.Reset()
+	req := ipc.TmuxRequest{Command: "has-session", Flags: map[string]any{"-t": "missing"}}
+	if got, want := router.ExecuteStream(context.Background(), req, &stdout, &stderr), router.Execute(req); got != want {
+		t.Fatalf("has-session via ExecuteStream = %+v, want %+v", got, want)
+	}
+	if stdout.Len() != 0 || stderr.Len() != 0 {
+		t.Fatalf("has-session streamed stdout %q, stderr %q; want nothing", stdout.String(), stderr.String())
+	}
+}
+
+func TestExecuteStreamRunShellCanceled(t *testing.T) {
+	sessions := NewSessionManager()
+	t.Cleanup(sessions.Close)
+	router := NewCommandRouter(sessions, nil, RouterOptions{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	resp := router.ExecuteStream(ctx, ipc.TmuxRequest{
+		Command: "run-shell",
+		Args:    []string{"ping -n 30 127.0.0.1 >nul"},
+	}, &strings.Builder{}, &strings.Builder{})
+	if resp.ExitCode != canceledShellExitCode || !strings.Contains(resp.Stderr, "canceled") {
+		t.Fatalf("response = %+v, want canceled run-shell", resp)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Fatalf("canceled run-shell returned after %v, want the command killed", elapsed)
+	}
+}
+
 func TestHandleRunShellTmuxCommands(t *testing.T) {
 	sessions := NewSessionManager()
 	t.Cleanup(sessions.Close)
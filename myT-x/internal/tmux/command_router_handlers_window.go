@@ -11,10 +11,15 @@ import (
 
 // handleActivateWindow signals the host application to bring its window
 // to the foreground. Used by the second instance to activate the first
-// instance's window before exiting.
-func (r *CommandRouter) handleActivateWindow(_ ipc.TmuxRequest) ipc.TmuxResponse {
-	slog.Debug("[DEBUG-IPC] activate-window command received")
-	r.emitter.Emit("app:activate-window", nil)
+// instance's window before exiting. An optional argument forwards the
+// mytx:// deep link the second instance was launched with.
+func (r *CommandRouter) handleActivateWindow(req ipc.TmuxRequest) ipc.TmuxResponse {
+	slog.Debug("[DEBUG-IPC] activate-window command received", "hasLink", len(req.Args) > 0)
+	var payload any
+	if len(req.Args) > 0 {
+		payload = map[string]string{"link": req.Args[0]}
+	}
+	r.emitter.Emit("app:activate-window", payload)
 	// Internal IPC callers expect "ok\n". attach-session intentionally keeps stdout empty.
 	return ipc.TmuxResponse{ExitCode: 0, Stdout: "ok\n"}
 }
@@ -45,7 +45,7 @@ func copySessionFlags(sessions *SessionManager, parent *TmuxSession, newSessionN
 //  7. Copy session flags from parent (IsAgentTeam, UseClaudeEnv, UsePaneEnv)
 //  8. Resolve environment variables for the new pane
 //  9. Attach pane terminal
-//  10. Send bootstrap keys (best-effort)
+//  10. Send bootstrap keys or the configured window startup command (best-effort)
 //  11. Set active pane unless -d is specified
 //  12. Emit tmux:session-created event
 //  13. Return formatted output if -P is specified
@@ -151,7 +151,17 @@ func (r *CommandRouter) handleNewWindow(req ipc.TmuxRequest) ipc.TmuxResponse {
 	}
 
 	// 10. send-keys bootstrap（best-effort）
-	r.bestEffortSendKeys(pane, req.Args, true, "DEBUG-WINDOW", newSessionName)
+	//     位置引数が無い場合は config の startup_commands.window を実行する。
+	if len(req.Args) == 0 && r.opts.ResolveWindowStartupCommand != nil {
+		if command, remainOnExit := r.opts.ResolveWindowStartupCommand(); strings.TrimSpace(command) != "" {
+			if startErr := r.runStartupCommand(pane, workDir, strings.TrimSpace(command), remainOnExit); startErr != nil {
+				slog.Warn("[WINDOW] startup command failed; continuing",
+					"session", newSessionName, "error", startErr)
+			}
+		}
+	} else {
+		r.bestEffortSendKeys(pane, req.Args, true, "DEBUG-WINDOW", newSessionName)
+	}
 
 	// 11. -d でなければアクティブペイン設定 + フォーカスイベント発行
 	//     handleSelectWindow / handleSelectPane と同一パターン:
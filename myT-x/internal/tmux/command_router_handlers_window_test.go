@@ -2,6 +2,7 @@ package tmux
 
 import (
 	"errors"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -11,9 +12,11 @@ import (
 func TestHandleActivateWindow(t *testing.T) {
 	tests := []struct {
 		name          string
+		args          []string
 		wantExitCode  int
 		wantStdout    string
 		wantEventName string
+		wantPayload   any
 	}{
 		{
 			name:          "returns exit code 0 and emits event",
@@ -21,6 +24,14 @@ func TestHandleActivateWindow(t *testing.T) {
 			wantStdout:    "ok\n",
 			wantEventName: "app:activate-window",
 		},
+		{
+			name:          "forwards deep link argument",
+			args:          []string{"mytx://session/api"},
+			wantExitCode:  0,
+			wantStdout:    "ok\n",
+			wantEventName: "app:activate-window",
+			wantPayload:   map[string]string{"link": "mytx://session/api"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -30,7 +41,7 @@ func TestHandleActivateWindow(t *testing.T) {
 				DefaultShell: "cmd.exe",
 			})
 
-			resp := router.Execute(ipc.TmuxRequest{Command: "activate-window"})
+			resp := router.Execute(ipc.TmuxRequest{Command: "activate-window", Args: tt.args})
 
 			if resp.ExitCode != tt.wantExitCode {
 				t.Errorf("ExitCode = %d, want %d", resp.ExitCode, tt.wantExitCode)
@@ -46,8 +57,8 @@ func TestHandleActivateWindow(t *testing.T) {
 			if events[0].name != tt.wantEventName {
 				t.Errorf("event name = %q, want %q", events[0].name, tt.wantEventName)
 			}
-			if events[0].payload != nil {
-				t.Errorf("event payload = %#v, want nil", events[0].payload)
+			if !reflect.DeepEqual(events[0].payload, tt.wantPayload) {
+				t.Errorf("event payload = %#v, want %#v", events[0].payload, tt.wantPayload)
 			}
 		})
 	}
@@ -6,7 +6,7 @@ import (
 )
 
 func TestRouterOptionsStructFieldCounts(t *testing.T) {
-	if got := reflect.TypeFor[RouterOptions]().NumField(); got != 11 {
-		t.Fatalf("RouterOptions field count = %d, want 11 (DefaultShell, PipeName, HostPID, ShimAvailable, PaneEnv, ClaudeEnv, OnSessionDestroyed, OnSessionRenamed, OnSessionRenameRollbackFailed, ResolveMCPStdio, ResolveSessionByCwd)", got)
+	if got := reflect.TypeFor[RouterOptions]().NumField(); got != 22 {
+		t.Fatalf("RouterOptions field count = %d, want 22 (DefaultShell, PipeName, HostPID, ShimAvailable, PaneEnv, ClaudeEnv, OnSessionDestroyed, OnSessionRenamed, OnSessionRenameRollbackFailed, ResolveMCPStdio, ResolveSessionByCwd, CopyBetweenSessions, ResolveShell, ResolveWindowStartupCommand, AcquireWarmTerminal, FoldOutput, ResolveGitIdentityEnv, ResolveToolPaths, ResolveShellHistoryDir, ResolveNetworkCaptureEnv, ControlModeEnabled, OnPaneStarted)", got)
 	}
 }
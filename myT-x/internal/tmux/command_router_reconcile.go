@@ -0,0 +1,75 @@
+// command_router_reconcile.go — Closing panes whose shell process died unnoticed (e.g. during system sleep).
+package tmux
+
+import (
+	"log/slog"
+)
+
+// DeadPane describes a pane whose shell process was found dead.
+type DeadPane struct {
+	SessionName string `json:"session_name"`
+	PaneID      string `json:"pane_id"`
+	PID         int    `json:"pid"`
+	// Closed is false when the pane was kept (remain-on-exit) or closing failed.
+	Closed bool `json:"closed"`
+}
+
+// ReconcileDeadPanes checks the shell process of every pane and closes panes
+// whose process is gone, as a normal process exit would: panes with
+// remain-on-exit on are kept and reported via tmux:pane-exited.
+// alive reports whether a process is running. It returns the number of
+// panes checked and the dead panes found.
+func (r *CommandRouter) ReconcileDeadPanes(alive func(pid int) bool) (int, []DeadPane) {
+	checked := 0
+	var dead []DeadPane
+	for _, session := range r.sessions.Snapshot() {
+		panes, err := r.sessions.GetSessionPanePIDs(session.Name)
+		if err != nil {
+			// Session closed between Snapshot and the PID lookup.
+			continue
+		}
+		for _, pane := range panes {
+			if pane.PID <= 0 {
+				// No attached process (terminal not started or already closed).
+				continue
+			}
+			checked++
+			if alive(pane.PID) {
+				continue
+			}
+			dead = append(dead, r.closeDeadPane(session.Name, pane))
+		}
+	}
+	return checked, dead
+}
+
+func (r *CommandRouter) closeDeadPane(sessionName string, pane PanePIDInfo) DeadPane {
+	result := DeadPane{SessionName: sessionName, PaneID: pane.PaneID, PID: pane.PID}
+	paneID, err := parsePaneID(pane.PaneID)
+	if err != nil {
+		return result
+	}
+	r.startupPanes.Delete(paneID)
+	paneCtx, err := r.sessions.GetPaneContextSnapshot(paneID)
+	if err != nil {
+		// Removed concurrently, e.g. by its own read loop ending.
+		result.Closed = true
+		return result
+	}
+	if r.paneRemainsOnExit(paneCtx, paneID) {
+		r.emitter.Emit("tmux:pane-exited", map[string]any{
+			"sessionName": paneCtx.SessionName,
+			"paneId":      pane.PaneID,
+		})
+		return result
+	}
+	if err := r.killPaneWithEvents(pane.PaneID, paneCtx.SessionName, paneCtx.WindowID); err != nil {
+		slog.Warn("[WARN-RECONCILE] failed to close pane with dead process",
+			"session", paneCtx.SessionName, "paneId", pane.PaneID, "pid", pane.PID, "error", err)
+		return result
+	}
+	slog.Info("[RECONCILE] closed pane with dead process",
+		"session", paneCtx.SessionName, "paneId", pane.PaneID, "pid", pane.PID)
+	result.Closed = true
+	return result
+}
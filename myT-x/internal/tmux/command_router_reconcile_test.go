@@ -0,0 +1,83 @@
+package tmux
+
+import (
+	"testing"
+
+	"myT-x/internal/ipc"
+)
+
+func TestReconcileDeadPanesSkipsPanesWithoutProcess(t *testing.T) {
+	sessions := NewSessionManager()
+	t.Cleanup(sessions.Close)
+	router := NewCommandRouter(sessions, &captureEmitter{}, RouterOptions{ShimAvailable: true})
+	if _, _, err := sessions.CreateSession("demo", "0", 120, 40); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+
+	checked, dead := router.ReconcileDeadPanes(func(int) bool {
+		t.Fatal("alive must not be called for panes without a process")
+		return false
+	})
+	if checked != 0 || len(dead) != 0 {
+		t.Fatalf("ReconcileDeadPanes() = %d, %v, want 0 checked and no dead panes", checked, dead)
+	}
+}
+
+func TestCloseDeadPaneClosesPane(t *testing.T) {
+	emitter := &captureEmitter{}
+	sessions := NewSessionManager()
+	t.Cleanup(sessions.Close)
+	router := NewCommandRouter(sessions, emitter, RouterOptions{ShimAvailable: true})
+	_, pane, err := sessions.CreateSession("demo", "0", 120, 40)
+	if err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	router.startupPanes.Store(pane.ID, struct{}{})
+
+	got := router.closeDeadPane("demo", PanePIDInfo{PaneID: pane.IDString(), PID: 42})
+
+	want := DeadPane{SessionName: "demo", PaneID: pane.IDString(), PID: 42, Closed: true}
+	if got != want {
+		t.Fatalf("closeDeadPane() = %+v, want %+v", got, want)
+	}
+	if _, err := sessions.GetPaneContextSnapshot(pane.ID); err == nil {
+		t.Fatal("dead pane should be removed")
+	}
+	if _, tracked := router.startupPanes.Load(pane.ID); tracked {
+		t.Fatal("dead pane should no longer be tracked as a startup pane")
+	}
+	if firstEventIndex(emitter.EventNames(), "tmux:session-emptied") < 0 {
+		t.Fatalf("events = %v, want tmux:session-emptied", emitter.EventNames())
+	}
+}
+
+func TestCloseDeadPaneRemainOnExitKeepsPane(t *testing.T) {
+	emitter := &captureEmitter{}
+	sessions := NewSessionManager()
+	t.Cleanup(sessions.Close)
+	router := NewCommandRouter(sessions, emitter, RouterOptions{ShimAvailable: true})
+	_, pane, err := sessions.CreateSession("demo", "0", 120, 40)
+	if err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	resp := router.Execute(ipc.TmuxRequest{
+		Command: "set-option",
+		Flags:   map[string]any{"-t": "demo"},
+		Args:    []string{"remain-on-exit", "on"},
+	})
+	if resp.ExitCode != 0 {
+		t.Fatalf("set-option remain-on-exit exit = %d, stderr = %q", resp.ExitCode, resp.Stderr)
+	}
+
+	got := router.closeDeadPane("demo", PanePIDInfo{PaneID: pane.IDString(), PID: 42})
+
+	if got.Closed {
+		t.Fatalf("closeDeadPane() = %+v, want pane kept", got)
+	}
+	if _, err := sessions.GetPaneContextSnapshot(pane.ID); err != nil {
+		t.Fatalf("pane should remain with remain-on-exit on: %v", err)
+	}
+	if firstEventIndex(emitter.EventNames(), "tmux:pane-exited") < 0 {
+		t.Fatalf("events = %v, want tmux:pane-exited", emitter.EventNames())
+	}
+}
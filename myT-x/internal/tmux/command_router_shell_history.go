@@ -0,0 +1,70 @@
+package tmux
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// ShellHistoryEnvVar is set to ShellHistoryIsolated in the new-session
+	// request env to isolate the session's shell history, and in the env of
+	// every pane whose history is isolated.
+	ShellHistoryEnvVar = "MYTX_SHELL_HISTORY"
+	// ShellHistoryIsolated is the ShellHistoryEnvVar value of isolated panes.
+	ShellHistoryIsolated = "isolated"
+	// PSReadLineHistoryEnvVar is the history file PowerShell panes pass to
+	// Set-PSReadLineOption -HistorySavePath at startup.
+	PSReadLineHistoryEnvVar = "MYTX_PSREADLINE_HISTORY"
+
+	// bashHistoryFileName and psReadLineHistoryFileName are created by the
+	// shells inside the directory from ResolveShellHistoryDir.
+	bashHistoryFileName       = "bash_history"
+	psReadLineHistoryFileName = "psreadline_history.txt"
+)
+
+// psReadLineHistoryCommand points PSReadLine at PSReadLineHistoryEnvVar. It
+// runs after the user's profile, so a profile HistorySavePath is overridden,
+// and is skipped when PSReadLine is not available.
+const psReadLineHistoryCommand = "if (Get-Command Set-PSReadLineOption -ErrorAction SilentlyContinue) " +
+	"{ Set-PSReadLineOption -HistorySavePath $env:" + PSReadLineHistoryEnvVar + " }"
+
+// applyShellHistoryEnv points HISTFILE and PSReadLineHistoryEnvVar into the
+// session's history directory when the session's history is isolated.
+// Values inherited from a source pane are replaced so that every pane of the
+// session shares the same files.
+func (r *CommandRouter) applyShellHistoryEnv(pane *TmuxPane, env map[string]string, workDir string) {
+	if env == nil || pane == nil || r.opts.ResolveShellHistoryDir == nil {
+		return
+	}
+	paneCtx, err := r.sessions.GetPaneContextSnapshot(pane.ID)
+	if err != nil || !paneCtx.ShellHistoryIsolated {
+		return
+	}
+	// The initial pane starts before the session root is stored.
+	sessionDir := paneCtx.SessionWorkDir
+	if sessionDir == "" {
+		sessionDir = workDir
+	}
+	dir := r.opts.ResolveShellHistoryDir(paneCtx.SessionName, sessionDir)
+	if dir == "" {
+		return
+	}
+	env[ShellHistoryEnvVar] = ShellHistoryIsolated
+	env["HISTFILE"] = filepath.Join(dir, bashHistoryFileName)
+	env[PSReadLineHistoryEnvVar] = filepath.Join(dir, psReadLineHistoryFileName)
+}
+
+// shellHistoryArgs returns the shell arguments that apply
+// PSReadLineHistoryEnvVar, or nil. Only PowerShell needs them; bash reads
+// HISTFILE itself.
+func shellHistoryArgs(shell string, env map[string]string) []string {
+	if env[PSReadLineHistoryEnvVar] == "" {
+		return nil
+	}
+	switch strings.ToLower(filepath.Base(strings.ReplaceAll(shell, `\`, "/"))) {
+	case "powershell.exe", "powershell", "pwsh.exe", "pwsh":
+		return []string{"-NoExit", "-Command", psReadLineHistoryCommand}
+	default:
+		return nil
+	}
+}
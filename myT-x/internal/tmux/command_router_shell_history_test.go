@@ -0,0 +1,79 @@
+package tmux
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestApplyShellHistoryEnv(t *testing.T) {
+	sessions := NewSessionManager()
+	t.Cleanup(sessions.Close)
+	_, pane, err := sessions.CreateSession("agent", "0", 120, 40)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var gotSession, gotDir string
+	router := NewCommandRouter(sessions, nil, RouterOptions{
+		ResolveShellHistoryDir: func(sessionName, workDir string) string {
+			gotSession, gotDir = sessionName, workDir
+			return filepath.Join("state", "agent")
+		},
+	})
+
+	env := map[string]string{}
+	router.applyShellHistoryEnv(pane, env, `C:\work`)
+	if len(env) != 0 || gotSession != "" {
+		t.Fatalf("env = %v, want unchanged while history is shared", env)
+	}
+
+	if err := sessions.SetShellHistoryIsolated("agent", true); err != nil {
+		t.Fatal(err)
+	}
+	env = map[string]string{"HISTFILE": "inherited"}
+	router.applyShellHistoryEnv(pane, env, `C:\work`)
+	if gotSession != "agent" || gotDir != `C:\work` {
+		t.Fatalf("resolver called with (%q, %q), want the session and its -c dir", gotSession, gotDir)
+	}
+	want := map[string]string{
+		ShellHistoryEnvVar:      ShellHistoryIsolated,
+		"HISTFILE":              filepath.Join("state", "agent", bashHistoryFileName),
+		PSReadLineHistoryEnvVar: filepath.Join("state", "agent", psReadLineHistoryFileName),
+	}
+	for key, value := range want {
+		if env[key] != value {
+			t.Fatalf("env[%s] = %q, want %q", key, env[key], value)
+		}
+	}
+
+	if err := sessions.SetRootPath("agent", `C:\repo`); err != nil {
+		t.Fatal(err)
+	}
+	router.applyShellHistoryEnv(pane, map[string]string{}, `C:\other`)
+	if gotDir != `C:\repo` {
+		t.Fatalf("resolver dir = %q, want the session root", gotDir)
+	}
+}
+
+func TestShellHistoryArgs(t *testing.T) {
+	isolated := map[string]string{PSReadLineHistoryEnvVar: `C:\state\psreadline_history.txt`}
+	want := []string{"-NoExit", "-Command", psReadLineHistoryCommand}
+	tests := []struct {
+		name  string
+		shell string
+		env   map[string]string
+		want  []string
+	}{
+		{"powershell", `C:\Windows\System32\WindowsPowerShell\v1.0\powershell.exe`, isolated, want},
+		{"pwsh", "pwsh", isolated, want},
+		{"bash reads HISTFILE", "bash.exe", isolated, nil},
+		{"shared history", "powershell.exe", map[string]string{}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := shellHistoryArgs(tt.shell, tt.env); !slices.Equal(got, tt.want) {
+				t.Fatalf("shellHistoryArgs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+// command_router_startup.go — Startup commands for the first pane of a session/window and remain-on-exit handling.
+package tmux
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+
+	"myT-x/internal/terminal"
+)
+
+// RunStartupCommand types command into the active pane of sessionName and ties
+// the pane lifetime to it: when the command finishes the shell exits, and the
+// pane is closed unless remain-on-exit is on for that pane.
+//
+// workDir must be the directory the pane was spawned in so that the same
+// shell_rules resolution picks the quoting rules for the running shell.
+// An empty command is a no-op.
+func (r *CommandRouter) RunStartupCommand(sessionName, workDir, command string, remainOnExit bool) error {
+	command = strings.TrimSpace(command)
+	if command == "" {
+		return nil
+	}
+	pane, err := r.sessions.ResolveTarget(sessionName, -1)
+	if err != nil {
+		return err
+	}
+	return r.runStartupCommand(pane, workDir, command, remainOnExit)
+}
+
+// runStartupCommand sets the pane-scope remain-on-exit option, registers the
+// pane for exit handling, and writes the shell-wrapped command line.
+func (r *CommandRouter) runStartupCommand(pane *TmuxPane, workDir, command string, remainOnExit bool) error {
+	if pane == nil {
+		return errors.New("pane is required")
+	}
+	paneCtx, err := r.sessions.GetPaneContextSnapshot(pane.ID)
+	if err != nil {
+		return err
+	}
+	term := pane.Terminal
+	if term == nil {
+		return fmt.Errorf("pane terminal is not attached: %s", pane.IDString())
+	}
+
+	remainValue := "off"
+	if remainOnExit {
+		remainValue = "on"
+	}
+	line, err := startupCommandLine(r.resolvePaneShell(workDir), command, remainOnExit)
+	if err != nil {
+		return err
+	}
+	r.options.setOption(compatOptionScope{
+		kind:      compatOptionScopePane,
+		sessionID: paneCtx.SessionID,
+		windowID:  paneCtx.WindowID,
+		paneID:    pane.ID,
+	}, compatOptionRemainOnExit, remainValue, false)
+	r.startupPanes.Store(pane.ID, struct{}{})
+
+	slog.Debug("[DEBUG-STARTUP] running startup command",
+		"session", paneCtx.SessionName,
+		"paneId", pane.IDString(),
+		"remainOnExit", remainOnExit,
+	)
+	if err := writeSendKeysPayload(term, []byte(line+"\r")); err != nil {
+		r.startupPanes.Delete(pane.ID)
+		return fmt.Errorf("write startup command: %w", err)
+	}
+	return nil
+}
+
+// startupCommandLine builds the line typed into the pane shell. Unless
+// remainOnExit is set, the line also makes the shell exit when the command
+// finishes so the pane lifetime follows the command.
+//
+// The command is user-authored for the target shell. It is handed to a child
+// shell so that nothing in it can break the wrapper around it:
+//   - PowerShell: -EncodedCommand, so quotes, semicolons and newlines need no
+//     escaping. The child maps a failed cmdlet to exit 1 because
+//     $LASTEXITCODE only reflects native commands and may be stale.
+//   - cmd: /S /C "..." with every metacharacter caret-escaped for the
+//     interactive parser, which passes the command through verbatim.
+//   - Other (POSIX-like) shells get the command typed as-is.
+//
+// Line breaks would submit the line early in cmd and POSIX shells, so they
+// are rejected there.
+func startupCommandLine(shell, command string, remainOnExit bool) (string, error) {
+	switch strings.ToLower(filepath.Base(strings.ReplaceAll(shell, `\`, "/"))) {
+	case "powershell.exe", "powershell", "pwsh.exe", "pwsh":
+		line := "& " + powerShellQuote(shell) + " -NoLogo -EncodedCommand " + powerShellEncodedCommand(command)
+		if remainOnExit {
+			return line, nil
+		}
+		// The child is a native process, so $LASTEXITCODE is fresh here.
+		return line + "; exit $LASTEXITCODE", nil
+	case "cmd.exe", "cmd":
+		if strings.ContainsAny(command, "\r\n") {
+			return "", errors.New("startup command for cmd must be a single line")
+		}
+		line := `"` + shell + `" /D /S /C ^"` + cmdCaretEscape(command) + `^"`
+		if remainOnExit {
+			return line, nil
+		}
+		// & (not &&) runs exit even when the command fails.
+		return line + " & exit", nil
+	default:
+		// bash, wsl, nu and other POSIX-like shells.
+		if strings.ContainsAny(command, "\r\n") {
+			return "", errors.New("startup command must be a single line")
+		}
+		if remainOnExit {
+			return command, nil
+		}
+		return command + "; exit", nil
+	}
+}
+
+// powerShellEncodedCommand returns the -EncodedCommand payload (base64 of
+// UTF-16LE) for command. The trailing check turns a failed cmdlet into a
+// non-zero exit; a failed native command keeps its own exit code.
+func powerShellEncodedCommand(command string) string {
+	script := command + "\nif (-not $?) { if ($LASTEXITCODE) { exit $LASTEXITCODE }; exit 1 }"
+	units := utf16.Encode([]rune(script))
+	buf := make([]byte, 0, len(units)*2)
+	for _, u := range units {
+		buf = binary.LittleEndian.AppendUint16(buf, u)
+	}
+	return base64.StdEncoding.EncodeToString(buf)
+}
+
+// powerShellQuote single-quotes s for PowerShell. PowerShell treats the
+// typographic single quotes as quote characters too, so every kind is doubled.
+func powerShellQuote(s string) string {
+	var b strings.Builder
+	b.Grow(len(s) + 2)
+	b.WriteByte('\'')
+	for _, r := range s {
+		switch r {
+		case '\'', '‘', '’', '‚', '‛':
+			b.WriteRune(r)
+		}
+		b.WriteRune(r)
+	}
+	b.WriteByte('\'')
+	return b.String()
+}
+
+// cmdCaretEscape prefixes cmd metacharacters with ^ so the interactive parser
+// never enters quote mode and passes s to the child cmd unchanged. % is left
+// alone: %VAR% expansion is what the command author expects.
+func cmdCaretEscape(s string) string {
+	var b strings.Builder
+	b.Grow(len(s) + len(s)/4)
+	for _, r := range s {
+		switch r {
+		case '^', '&', '|', '<', '>', '(', ')', '"':
+			b.WriteByte('^')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// handlePaneProcessExit runs after a pane's read loop ends on its own (the
+// shell process exited). Only panes that ran a startup command are handled;
+// interactive panes keep their existing behavior.
+func (r *CommandRouter) handlePaneProcessExit(paneID int, term *terminal.Terminal) {
+	if _, tracked := r.startupPanes.LoadAndDelete(paneID); !tracked {
+		return
+	}
+	if term != nil && term.IsClosed() {
+		// The pane was killed explicitly; nothing left to do.
+		return
+	}
+	paneCtx, err := r.sessions.GetPaneContextSnapshot(paneID)
+	if err != nil {
+		slog.Debug("[DEBUG-STARTUP] pane already removed after process exit",
+			"paneId", formatPaneID(paneID), "error", err)
+		return
+	}
+	if r.paneRemainsOnExit(paneCtx, paneID) {
+		slog.Info("[STARTUP] startup command exited; keeping pane (remain-on-exit)",
+			"session", paneCtx.SessionName, "paneId", formatPaneID(paneID))
+		r.emitter.Emit("tmux:pane-exited", map[string]any{
+			"sessionName": paneCtx.SessionName,
+			"paneId":      formatPaneID(paneID),
+		})
+		return
+	}
+	if err := r.killPaneWithEvents(formatPaneID(paneID), paneCtx.SessionName, paneCtx.WindowID); err != nil {
+		slog.Warn("[WARN-STARTUP] failed to close pane after startup command exited",
+			"session", paneCtx.SessionName, "paneId", formatPaneID(paneID), "error", err)
+	}
+}
+
+// paneRemainsOnExit reports whether remain-on-exit is on for paneID.
+func (r *CommandRouter) paneRemainsOnExit(paneCtx PaneContextSnapshot, paneID int) bool {
+	remain, _ := r.options.getOption(compatOptionScope{
+		kind:      compatOptionScopePane,
+		sessionID: paneCtx.SessionID,
+		windowID:  paneCtx.WindowID,
+		paneID:    paneID,
+	}, compatOptionRemainOnExit)
+	return remain == "on"
+}
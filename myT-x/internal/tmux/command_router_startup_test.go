@@ -0,0 +1,175 @@
+package tmux
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"testing"
+	"unicode/utf16"
+
+	"myT-x/internal/ipc"
+)
+
+func TestStartupCommandLine(t *testing.T) {
+	encoded := powerShellEncodedCommand("npm run dev")
+	tests := []struct {
+		name         string
+		shell        string
+		remainOnExit bool
+		want         string
+	}{
+		{name: "powershell exits with child status", shell: "powershell.exe", want: "& 'powershell.exe' -NoLogo -EncodedCommand " + encoded + "; exit $LASTEXITCODE"},
+		{name: "pwsh full path", shell: `C:\Program Files\PowerShell\7\pwsh.exe`, want: `& 'C:\Program Files\PowerShell\7\pwsh.exe' -NoLogo -EncodedCommand ` + encoded + "; exit $LASTEXITCODE"},
+		{name: "powershell remain-on-exit", shell: "pwsh.exe", remainOnExit: true, want: "& 'pwsh.exe' -NoLogo -EncodedCommand " + encoded},
+		{name: "cmd exits even on failure", shell: "CMD.EXE", want: `"CMD.EXE" /D /S /C ^"npm run dev^" & exit`},
+		{name: "cmd remain-on-exit", shell: "cmd.exe", remainOnExit: true, want: `"cmd.exe" /D /S /C ^"npm run dev^"`},
+		{name: "bash", shell: "bash.exe", want: "npm run dev; exit"},
+		{name: "wsl", shell: "wsl.exe", want: "npm run dev; exit"},
+		{name: "posix remain-on-exit", shell: "bash.exe", remainOnExit: true, want: "npm run dev"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := startupCommandLine(tt.shell, "npm run dev", tt.remainOnExit)
+			if err != nil {
+				t.Fatalf("startupCommandLine(%q) error = %v", tt.shell, err)
+			}
+			if got != tt.want {
+				t.Fatalf("startupCommandLine(%q) = %q, want %q", tt.shell, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStartupCommandLineEscapesCmdMetacharacters(t *testing.T) {
+	got, err := startupCommandLine("cmd.exe", `echo "a & b" > out.txt || (exit 1)`, false)
+	if err != nil {
+		t.Fatalf("startupCommandLine() error = %v", err)
+	}
+	want := `"cmd.exe" /D /S /C ^"echo ^"a ^& b^" ^> out.txt ^|^| ^(exit 1^)^" & exit`
+	if got != want {
+		t.Fatalf("startupCommandLine() = %q, want %q", got, want)
+	}
+}
+
+func TestStartupCommandLineRejectsNewlinesOutsidePowerShell(t *testing.T) {
+	for _, shell := range []string{"cmd.exe", "bash.exe"} {
+		if _, err := startupCommandLine(shell, "npm install\nnpm run dev", false); err == nil {
+			t.Fatalf("startupCommandLine(%q) error = nil, want multi-line rejection", shell)
+		}
+	}
+	if _, err := startupCommandLine("pwsh.exe", "npm install\nnpm run dev", false); err != nil {
+		t.Fatalf("startupCommandLine(pwsh) error = %v, want encoded multi-line command", err)
+	}
+}
+
+func TestPowerShellEncodedCommandDecodesToScript(t *testing.T) {
+	raw, err := base64.StdEncoding.DecodeString(powerShellEncodedCommand("Write-Host 'hi'; npm test"))
+	if err != nil {
+		t.Fatalf("DecodeString() error = %v", err)
+	}
+	if len(raw)%2 != 0 {
+		t.Fatalf("payload length %d is not UTF-16", len(raw))
+	}
+	units := make([]uint16, len(raw)/2)
+	for i := range units {
+		units[i] = binary.LittleEndian.Uint16(raw[i*2:])
+	}
+	want := "Write-Host 'hi'; npm test\nif (-not $?) { if ($LASTEXITCODE) { exit $LASTEXITCODE }; exit 1 }"
+	if got := string(utf16.Decode(units)); got != want {
+		t.Fatalf("decoded script = %q, want %q", got, want)
+	}
+}
+
+func TestRunStartupCommandEmptyIsNoop(t *testing.T) {
+	sessions := NewSessionManager()
+	t.Cleanup(sessions.Close)
+	router := NewCommandRouter(sessions, &captureEmitter{}, RouterOptions{ShimAvailable: true})
+
+	if err := router.RunStartupCommand("missing", "", "   ", false); err != nil {
+		t.Fatalf("RunStartupCommand(empty) error = %v, want nil", err)
+	}
+}
+
+func TestRunStartupCommandRequiresAttachedTerminal(t *testing.T) {
+	sessions := NewSessionManager()
+	t.Cleanup(sessions.Close)
+	router := NewCommandRouter(sessions, &captureEmitter{}, RouterOptions{ShimAvailable: true})
+	_, pane, err := sessions.CreateSession("demo", "0", 120, 40)
+	if err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+
+	if err := router.RunStartupCommand("demo", "", "npm run dev", false); err == nil {
+		t.Fatal("RunStartupCommand() error = nil, want terminal-not-attached error")
+	}
+	if _, tracked := router.startupPanes.Load(pane.ID); tracked {
+		t.Fatal("pane must not be tracked when the startup command was not written")
+	}
+}
+
+func TestHandlePaneProcessExitClosesStartupPane(t *testing.T) {
+	emitter := &captureEmitter{}
+	sessions := NewSessionManager()
+	t.Cleanup(sessions.Close)
+	router := NewCommandRouter(sessions, emitter, RouterOptions{ShimAvailable: true})
+	_, pane, err := sessions.CreateSession("demo", "0", 120, 40)
+	if err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	router.startupPanes.Store(pane.ID, struct{}{})
+
+	router.handlePaneProcessExit(pane.ID, nil)
+
+	if _, err := sessions.GetPaneContextSnapshot(pane.ID); err == nil {
+		t.Fatal("startup pane should be removed after its process exits")
+	}
+	if firstEventIndex(emitter.EventNames(), "tmux:session-emptied") < 0 {
+		t.Fatalf("events = %v, want tmux:session-emptied", emitter.EventNames())
+	}
+}
+
+func TestHandlePaneProcessExitRemainOnExitKeepsPane(t *testing.T) {
+	emitter := &captureEmitter{}
+	sessions := NewSessionManager()
+	t.Cleanup(sessions.Close)
+	router := NewCommandRouter(sessions, emitter, RouterOptions{ShimAvailable: true})
+	_, pane, err := sessions.CreateSession("demo", "0", 120, 40)
+	if err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	router.startupPanes.Store(pane.ID, struct{}{})
+
+	// remain-on-exit set through the tmux-compatible option path.
+	resp := router.Execute(ipc.TmuxRequest{
+		Command: "set-option",
+		Flags:   map[string]any{"-t": "demo"},
+		Args:    []string{"remain-on-exit", "on"},
+	})
+	if resp.ExitCode != 0 {
+		t.Fatalf("set-option remain-on-exit exit = %d, stderr = %q", resp.ExitCode, resp.Stderr)
+	}
+
+	router.handlePaneProcessExit(pane.ID, nil)
+
+	if _, err := sessions.GetPaneContextSnapshot(pane.ID); err != nil {
+		t.Fatalf("pane should remain with remain-on-exit on: %v", err)
+	}
+	if firstEventIndex(emitter.EventNames(), "tmux:pane-exited") < 0 {
+		t.Fatalf("events = %v, want tmux:pane-exited", emitter.EventNames())
+	}
+}
+
+func TestHandlePaneProcessExitIgnoresInteractivePane(t *testing.T) {
+	sessions := NewSessionManager()
+	t.Cleanup(sessions.Close)
+	router := NewCommandRouter(sessions, &captureEmitter{}, RouterOptions{ShimAvailable: true})
+	_, pane, err := sessions.CreateSession("demo", "0", 120, 40)
+	if err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+
+	router.handlePaneProcessExit(pane.ID, nil)
+
+	if _, err := sessions.GetPaneContextSnapshot(pane.ID); err != nil {
+		t.Fatalf("interactive pane must be kept: %v", err)
+	}
+}
@@ -3,8 +3,10 @@ package tmux
 import (
 	"fmt"
 	"log/slog"
+	"maps"
 	"os"
 	"runtime/debug"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -53,7 +55,27 @@ func (r *CommandRouter) attachPaneTerminal(pane *TmuxPane, workDir string, env m
 	if r.attachTerminalFn == nil {
 		return fmt.Errorf("attach terminal function is not configured")
 	}
-	return r.attachTerminalFn(pane, workDir, env, source)
+	if err := r.attachTerminalFn(pane, workDir, env, source); err != nil {
+		return err
+	}
+	r.sessions.setPaneStartDir(pane.ID, workDir)
+	return nil
+}
+
+// resolvePaneShell returns the shell for a pane spawned in workDir.
+// ResolveShell (shell_rules) takes priority over DefaultShell.
+func (r *CommandRouter) resolvePaneShell(workDir string) string {
+	shell := ""
+	if r.opts.ResolveShell != nil {
+		shell = strings.TrimSpace(r.opts.ResolveShell(workDir))
+	}
+	if shell == "" {
+		shell = r.opts.DefaultShell
+	}
+	if shell == "" {
+		shell = "powershell.exe"
+	}
+	return shell
 }
 
 func replacePaneOutputHistory(pane *TmuxPane, capacity int) *PaneOutputHistory {
@@ -70,10 +92,7 @@ func (r *CommandRouter) attachTerminal(pane *TmuxPane, workDir string, env map[s
 	if pane == nil {
 		return fmt.Errorf("pane is required")
 	}
-	shell := r.opts.DefaultShell
-	if shell == "" {
-		shell = "powershell.exe"
-	}
+	shell := r.resolvePaneShell(workDir)
 	cols := pane.Width
 	rows := pane.Height
 	if cols <= 0 {
@@ -83,15 +102,11 @@ func (r *CommandRouter) attachTerminal(pane *TmuxPane, workDir string, env map[s
 		rows = DefaultTerminalRows
 	}
 
-	merged := mergeEnvironment(env)
-	cfg := terminal.Config{
-		Shell:   shell,
-		Dir:     workDir,
-		Env:     merged,
-		Columns: cols,
-		Rows:    rows,
-	}
-	t, err := terminal.Start(cfg)
+	r.applyGitIdentityEnv(env, workDir)
+	r.applyToolPathsEnv(env, workDir)
+	r.applyShellHistoryEnv(pane, env, workDir)
+	r.applyNetworkCaptureEnv(pane, env)
+	t, err := r.startPaneTerminal(shell, workDir, env, cols, rows)
 	if err != nil {
 		return err
 	}
@@ -110,9 +125,18 @@ func (r *CommandRouter) attachTerminal(pane *TmuxPane, workDir string, env map[s
 		return bindErr
 	}
 
+	r.notifyPaneStarted(pane, t.PID())
+
 	history := replacePaneOutputHistory(pane, defaultPaneOutputHistoryCapacity)
+	if r.opts.FoldOutput != nil && r.opts.FoldOutput() {
+		history.SetFolding(true)
+	}
 
 	paneID := pane.IDString()
+	paneNumID := pane.ID
+	// A new process starts with default modes.
+	r.sessions.setPaneTerminalModes(paneNumID, PaneTerminalModes{})
+	modes := &terminalModeScanner{}
 	slog.Info("[terminal] attachTerminal: starting ReadLoop", "paneId", paneID, "shell", shell)
 	go func() {
 		restartDelay := initialRouterPanicRestartBackoff
@@ -133,14 +157,19 @@ func (r *CommandRouter) attachTerminal(pane *TmuxPane, workDir string, env map[s
 						}
 					}()
 					history.Write(chunk)
+					if modes.Scan(chunk) {
+						r.sessions.setPaneTerminalModes(paneNumID, modes.Modes())
+					}
 					slog.Debug("[terminal] ReadLoop output", "paneId", paneID, "chunkLen", len(chunk))
 					r.emitter.Emit("tmux:pane-output", PaneOutputEvent{
 						PaneID: paneID,
 						Data:   chunk,
 					})
+					r.control.publish(paneID, chunk)
 				})
 			}()
 			if !panicked {
+				r.handlePaneProcessExit(paneNumID, t)
 				return
 			}
 			if t.IsClosed() {
@@ -160,6 +189,19 @@ func (r *CommandRouter) attachTerminal(pane *TmuxPane, workDir string, env map[s
 	return nil
 }
 
+// notifyPaneStarted reports a started pane shell to opts.OnPaneStarted.
+func (r *CommandRouter) notifyPaneStarted(pane *TmuxPane, pid int) {
+	if r.opts.OnPaneStarted == nil || pid <= 0 {
+		return
+	}
+	paneCtx, err := r.sessions.GetPaneContextSnapshot(pane.ID)
+	if err != nil {
+		slog.Debug("[terminal] notifyPaneStarted: pane closed before notification", "paneId", pane.IDString(), "error", err)
+		return
+	}
+	r.opts.OnPaneStarted(paneCtx.SessionName, pane.IDString(), pid)
+}
+
 func addTmuxEnvironment(env map[string]string, pipeName string, hostPID int, sessionIndex int, paneID int, shimAvailable bool, sessionName string) {
 	tmuxValue := fmt.Sprintf(`%s,%d,%d`, pipeName, hostPID, sessionIndex)
 	paneValue := formatPaneID(paneID)
@@ -188,6 +230,96 @@ func addTmuxEnvironment(env map[string]string, pipeName string, hostPID int, ses
 	env["MYTX_SESSION"] = sessionName
 }
 
+// applyGitIdentityEnv fills the git identity of the pane's session into env.
+// The session is read from MYTX_SESSION, which addTmuxEnvironment always sets.
+// Identity variables already in env (new-window -e, a split source pane) win.
+func (r *CommandRouter) applyGitIdentityEnv(env map[string]string, workDir string) {
+	if env == nil || r.opts.ResolveGitIdentityEnv == nil {
+		return
+	}
+	identityEnv := r.opts.ResolveGitIdentityEnv(env["MYTX_SESSION"], workDir)
+	if len(identityEnv) == 0 {
+		return
+	}
+	mergePaneEnvDefaults(env, identityEnv)
+}
+
+// ToolPathsEnvVar lists the directories prepended to a pane's PATH,
+// separated by os.PathListSeparator. It is recomputed for every pane, so a
+// value inherited from a source pane or passed with new-window -e is
+// replaced.
+const ToolPathsEnvVar = "MYTX_TOOL_PATHS"
+
+// applyToolPathsEnv records the tool directories of the pane's session in
+// env[ToolPathsEnvVar]. startPaneTerminal prepends them to PATH.
+func (r *CommandRouter) applyToolPathsEnv(env map[string]string, workDir string) {
+	if env == nil {
+		return
+	}
+	delete(env, ToolPathsEnvVar)
+	if r.opts.ResolveToolPaths == nil {
+		return
+	}
+	dirs := r.opts.ResolveToolPaths(env["MYTX_SESSION"], workDir)
+	if len(dirs) == 0 {
+		return
+	}
+	env[ToolPathsEnvVar] = strings.Join(dirs, string(os.PathListSeparator))
+}
+
+// NetworkCaptureEnvVar lists the variables a pane got from
+// ResolveNetworkCaptureEnv, separated by commas, so that a pane split from it
+// after capture was turned off does not inherit a closed proxy.
+const NetworkCaptureEnvVar = "MYTX_NETWORK_CAPTURE"
+
+// applyNetworkCaptureEnv points the pane at its session's network capture
+// proxy, and drops capture variables inherited from a source pane.
+func (r *CommandRouter) applyNetworkCaptureEnv(pane *TmuxPane, env map[string]string) {
+	if env == nil {
+		return
+	}
+	if inherited := env[NetworkCaptureEnvVar]; inherited != "" {
+		for key := range strings.SplitSeq(inherited, ",") {
+			delete(env, key)
+		}
+		delete(env, NetworkCaptureEnvVar)
+	}
+	if pane == nil || r.opts.ResolveNetworkCaptureEnv == nil {
+		return
+	}
+	paneCtx, err := r.sessions.GetPaneContextSnapshot(pane.ID)
+	if err != nil {
+		return
+	}
+	captureEnv := r.opts.ResolveNetworkCaptureEnv(paneCtx.SessionName)
+	if len(captureEnv) == 0 {
+		return
+	}
+	maps.Copy(env, captureEnv)
+	env[NetworkCaptureEnvVar] = strings.Join(slices.Sorted(maps.Keys(captureEnv)), ",")
+}
+
+// prependPath returns environ with the ToolPathsEnvVar directories of custom
+// in front of PATH. The PATH key is matched case-insensitively because
+// Windows spells it "Path".
+func prependPath(environ []string, custom map[string]string) []string {
+	prefix := custom[ToolPathsEnvVar]
+	if prefix == "" {
+		return environ
+	}
+	for i, item := range environ {
+		key, value, ok := strings.Cut(item, "=")
+		if ok && strings.EqualFold(key, "PATH") {
+			if value != "" {
+				prefix += string(os.PathListSeparator) + value
+			}
+			environ[i] = key + "=" + prefix
+			return environ
+		}
+	}
+	return append(environ, "PATH="+prefix)
+}
+
 // mergePaneEnvDefaults merges paneEnv entries into env as lowest-priority
 // defaults. Existing keys in env are never overwritten.
 //
@@ -208,6 +340,41 @@ func mergePaneEnvDefaults(env map[string]string, paneEnv map[string]string) {
 	}
 }
 
+// startPaneTerminal takes a warm shell from the session pool when one is
+// available for shell and starts a new one otherwise.
+func (r *CommandRouter) startPaneTerminal(shell, workDir string, env map[string]string, cols, rows int) (*terminal.Terminal, error) {
+	// A warm shell was started with the app's PATH and history, so tool paths
+	// and an isolated history need a new one.
+	if r.opts.AcquireWarmTerminal != nil && env[ToolPathsEnvVar] == "" && env[ShellHistoryEnvVar] != ShellHistoryIsolated {
+		if t := r.opts.AcquireWarmTerminal(shell, workDir, sanitizeCustomEnvironment(env), cols, rows); t != nil {
+			slog.Debug("[terminal] attachTerminal: using warm shell", "shell", shell, "dir", workDir)
+			return t, nil
+		}
+	}
+	return terminal.Start(terminal.Config{
+		Shell:   shell,
+		Args:    shellHistoryArgs(shell, env),
+		Dir:     workDir,
+		Env:     prependPath(mergeEnvironment(env), env),
+		Columns: cols,
+		Rows:    rows,
+	})
+}
+
+// sanitizeCustomEnvironment returns the entries of custom that
+// mergeEnvironment would apply, keyed by their sanitized names.
+func sanitizeCustomEnvironment(custom map[string]string) map[string]string {
+	out := make(map[string]string, len(custom))
+	for key, value := range custom {
+		safeKey, safeValue, ok := sanitizeCustomEnvironmentEntry(key, value)
+		if !ok {
+			continue
+		}
+		out[safeKey] = safeValue
+	}
+	return out
+}
+
 func mergeEnvironment(custom map[string]string) []string {
 	base := os.Environ()
 	if len(custom) == 0 {
@@ -223,13 +390,7 @@ func mergeEnvironment(custom map[string]string) []string {
 		}
 		out[key] = value
 	}
-	for key, value := range custom {
-		safeKey, safeValue, ok := sanitizeCustomEnvironmentEntry(key, value)
-		if !ok {
-			continue
-		}
-		out[safeKey] = safeValue
-	}
+	maps.Copy(out, sanitizeCustomEnvironment(custom))
 	merged := make([]string, 0, len(out))
 	for key, value := range out {
 		merged = append(merged, fmt.Sprintf("%s=%s", key, value))
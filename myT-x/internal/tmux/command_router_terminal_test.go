@@ -3,7 +3,9 @@ package tmux
 import (
 	"bytes"
 	"log/slog"
+	"maps"
 	"os"
+	"slices"
 	"sort"
 	"strings"
 	"testing"
@@ -44,6 +46,18 @@ func TestMergeEnvironmentSanitizesNullAndLength(t *testing.T) {
 	}
 }
 
+func TestSanitizeCustomEnvironmentDropsBlockedKeys(t *testing.T) {
+	got := sanitizeCustomEnvironment(map[string]string{
+		"Path":       `C:\attacker`,
+		" MYTX_KEY ": "a\x00b",
+		"BAD=KEY":    "x",
+	})
+	want := map[string]string{"MYTX_KEY": "ab"}
+	if !maps.Equal(got, want) {
+		t.Fatalf("sanitizeCustomEnvironment() = %#v, want %#v", got, want)
+	}
+}
+
 func TestMergeEnvironmentBlocksCaseInsensitiveKeys(t *testing.T) {
 	basePath := os.Getenv("PATH")
 	merged := mergeEnvironment(map[string]string{
@@ -149,6 +163,46 @@ func TestShimAvailableGetterSetter(t *testing.T) {
 	}
 }
 
+func TestResolvePaneShell(t *testing.T) {
+	tests := []struct {
+		name         string
+		defaultShell string
+		resolve      func(string) string
+		want         string
+	}{
+		{name: "no resolver uses default", defaultShell: "cmd.exe", want: "cmd.exe"},
+		{name: "no default uses powershell", want: "powershell.exe"},
+		{
+			name:         "resolver wins",
+			defaultShell: "cmd.exe",
+			resolve: func(workDir string) string {
+				if workDir == `C:\linux` {
+					return "wsl.exe"
+				}
+				return ""
+			},
+			want: "wsl.exe",
+		},
+		{
+			name:         "empty resolver result falls back",
+			defaultShell: "cmd.exe",
+			resolve:      func(string) string { return "  " },
+			want:         "cmd.exe",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := NewCommandRouter(nil, nil, RouterOptions{
+				DefaultShell: tt.defaultShell,
+				ResolveShell: tt.resolve,
+			})
+			if got := router.resolvePaneShell(`C:\linux`); got != tt.want {
+				t.Fatalf("resolvePaneShell() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestAddTmuxEnvironmentAlwaysSetsTMUX(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -416,6 +470,115 @@ func TestMergePaneEnvDefaults(t *testing.T) {
 	}
 }
 
+func TestApplyGitIdentityEnv(t *testing.T) {
+	var gotSession, gotWorkDir string
+	router := NewCommandRouter(nil, nil, RouterOptions{
+		ResolveGitIdentityEnv: func(sessionName, workDir string) map[string]string {
+			gotSession, gotWorkDir = sessionName, workDir
+			return map[string]string{"GIT_AUTHOR_NAME": "OSS", "GIT_AUTHOR_EMAIL": "oss@example.com"}
+		},
+	})
+	env := map[string]string{"MYTX_SESSION": "oss-api", "GIT_AUTHOR_NAME": "Explicit"}
+	router.applyGitIdentityEnv(env, `C:\work\api`)
+
+	if gotSession != "oss-api" || gotWorkDir != `C:\work\api` {
+		t.Fatalf("resolver called with (%q, %q), want (%q, %q)", gotSession, gotWorkDir, "oss-api", `C:\work\api`)
+	}
+	want := map[string]string{
+		"MYTX_SESSION":     "oss-api",
+		"GIT_AUTHOR_NAME":  "Explicit",
+		"GIT_AUTHOR_EMAIL": "oss@example.com",
+	}
+	if !maps.Equal(env, want) {
+		t.Fatalf("env = %v, want %v", env, want)
+	}
+
+	// Without a resolver the env is left untouched.
+	plain := map[string]string{"MYTX_SESSION": "oss-api"}
+	NewCommandRouter(nil, nil, RouterOptions{}).applyGitIdentityEnv(plain, "")
+	if len(plain) != 1 {
+		t.Fatalf("env without resolver = %v, want unchanged", plain)
+	}
+}
+
+func TestApplyToolPathsEnv(t *testing.T) {
+	sep := string(os.PathListSeparator)
+	var gotSession string
+	router := NewCommandRouter(nil, nil, RouterOptions{
+		ResolveToolPaths: func(sessionName, _ string) []string {
+			gotSession = sessionName
+			if sessionName == "web" {
+				return []string{`C:\web\node_modules\.bin`, `C:\Users\me\go\bin`}
+			}
+			return nil
+		},
+	})
+
+	env := map[string]string{"MYTX_SESSION": "web"}
+	router.applyToolPathsEnv(env, `C:\web`)
+	if want := `C:\web\node_modules\.bin` + sep + `C:\Users\me\go\bin`; gotSession != "web" || env[ToolPathsEnvVar] != want {
+		t.Fatalf("env[%s] = %q (session %q), want %q", ToolPathsEnvVar, env[ToolPathsEnvVar], gotSession, want)
+	}
+
+	// A value inherited from another session's pane is replaced.
+	inherited := map[string]string{"MYTX_SESSION": "docs", ToolPathsEnvVar: `C:\web\node_modules\.bin`}
+	router.applyToolPathsEnv(inherited, `C:\docs`)
+	if _, ok := inherited[ToolPathsEnvVar]; ok {
+		t.Fatalf("inherited %s kept: %v", ToolPathsEnvVar, inherited)
+	}
+}
+
+func TestApplyNetworkCaptureEnv(t *testing.T) {
+	sessions := NewSessionManager()
+	_, pane, err := sessions.CreateSession("agent", "0", 120, 40)
+	if err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	capturing := true
+	router := NewCommandRouter(sessions, nil, RouterOptions{
+		ResolveNetworkCaptureEnv: func(sessionName string) map[string]string {
+			if sessionName != "agent" || !capturing {
+				return nil
+			}
+			return map[string]string{"HTTP_PROXY": "http://127.0.0.1:4100", "HTTPS_PROXY": "http://127.0.0.1:4100"}
+		},
+	})
+
+	env := map[string]string{"HTTP_PROXY": "http://corp:8080"}
+	router.applyNetworkCaptureEnv(pane, env)
+	want := map[string]string{
+		"HTTP_PROXY":         "http://127.0.0.1:4100",
+		"HTTPS_PROXY":        "http://127.0.0.1:4100",
+		NetworkCaptureEnvVar: "HTTPS_PROXY,HTTP_PROXY",
+	}
+	if !maps.Equal(env, want) {
+		t.Fatalf("env = %v, want %v", env, want)
+	}
+
+	// A pane split after capture was turned off drops the inherited proxy.
+	capturing = false
+	router.applyNetworkCaptureEnv(pane, env)
+	if len(env) != 0 {
+		t.Fatalf("env after capture off = %v, want the capture variables removed", env)
+	}
+}
+
+func TestPrependPath(t *testing.T) {
+	sep := string(os.PathListSeparator)
+	custom := map[string]string{ToolPathsEnvVar: "tools" + sep + "bin"}
+
+	got := prependPath([]string{"A=1", "Path=orig"}, custom)
+	if want := []string{"A=1", "Path=tools" + sep + "bin" + sep + "orig"}; !slices.Equal(got, want) {
+		t.Fatalf("prependPath() = %v, want %v", got, want)
+	}
+	if got := prependPath([]string{"A=1"}, custom); !slices.Equal(got, []string{"A=1", "PATH=tools" + sep + "bin"}) {
+		t.Fatalf("prependPath() without PATH = %v", got)
+	}
+	if got := prependPath([]string{"PATH=orig"}, nil); !slices.Equal(got, []string{"PATH=orig"}) {
+		t.Fatalf("prependPath() without tool paths = %v, want unchanged", got)
+	}
+}
+
 func TestMergePaneEnvDefaultsNilEnv(t *testing.T) {
 	// nil env must not panic — early return guards against nil map write.
 	mergePaneEnvDefaults(nil, map[string]string{"KEY": "val"})
@@ -672,3 +835,32 @@ func TestNextRouterPanicRestartBackoff(t *testing.T) {
 		})
 	}
 }
+
+func TestNotifyPaneStarted(t *testing.T) {
+	sessions := NewSessionManager()
+	t.Cleanup(sessions.Close)
+	_, pane, err := sessions.CreateSession("agent", "0", 120, 40)
+	if err != nil {
+		t.Fatal(err)
+	}
+	type call struct {
+		session, paneID string
+		pid             int
+	}
+	var calls []call
+	router := NewCommandRouter(sessions, nil, RouterOptions{
+		OnPaneStarted: func(sessionName string, paneID string, pid int) {
+			calls = append(calls, call{sessionName, paneID, pid})
+		},
+	})
+
+	router.notifyPaneStarted(pane, 4242)
+	router.notifyPaneStarted(pane, 0)
+	router.notifyPaneStarted(&TmuxPane{ID: pane.ID + 100}, 4243)
+	want := []call{{"agent", pane.IDString(), 4242}}
+	if !slices.Equal(calls, want) {
+		t.Fatalf("OnPaneStarted calls = %+v, want %+v", calls, want)
+	}
+
+	NewCommandRouter(sessions, nil, RouterOptions{}).notifyPaneStarted(pane, 4242)
+}
@@ -55,6 +55,9 @@ func TestCommandRouterHandlerMapHasNoDuplicateKeys(t *testing.T) {
 		"if-shell",
 		"mcp-resolve-stdio",
 		"resolve-session-by-cwd",
+		"copy-between-sessions",
+		"server-info",
+		"list-commands",
 	}
 
 	if len(router.handlers) != len(expectedCommands) {
@@ -0,0 +1,55 @@
+package tmux
+
+import (
+	"maps"
+	"slices"
+)
+
+// Flag value types of CommandSchema.Flags.
+const (
+	FlagTypeBoolean = "boolean"
+	FlagTypeString  = "string"
+)
+
+// CommandSchema describes one command the router serves, for clients that
+// generate or validate requests against the server.
+type CommandSchema struct {
+	Name        string
+	Usage       string
+	Description string
+	Notes       []string
+	// Internal commands are used between myT-x processes.
+	Internal bool
+	// Flags maps each known flag to FlagTypeBoolean or FlagTypeString. It is
+	// nil when the command reads flags the parser does not know in advance.
+	Flags map[string]string
+}
+
+// CommandSchemas returns every command the router serves, sorted by name,
+// from the registries behind list-commands and the command-line parser.
+func CommandSchemas() []CommandSchema {
+	names := slices.Sorted(maps.Keys(commandHelp))
+	schemas := make([]CommandSchema, 0, len(names))
+	for _, name := range names {
+		help := commandHelp[name]
+		schema := CommandSchema{
+			Name:        name,
+			Usage:       help.Usage,
+			Description: help.Description,
+			Notes:       slices.Clone(help.Notes),
+			Internal:    help.Internal,
+		}
+		if specs, ok := internalCommandFlagSpecs[name]; ok {
+			schema.Flags = make(map[string]string, len(specs))
+			for flag, kind := range specs {
+				if kind == tmuxFlagBool {
+					schema.Flags[flag] = FlagTypeBoolean
+				} else {
+					schema.Flags[flag] = FlagTypeString
+				}
+			}
+		}
+		schemas = append(schemas, schema)
+	}
+	return schemas
+}
@@ -0,0 +1,35 @@
+package tmux
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCommandSchemasCoverCommandHelp(t *testing.T) {
+	schemas := CommandSchemas()
+	if len(schemas) != len(commandHelp) {
+		t.Fatalf("len(CommandSchemas()) = %d, want %d", len(schemas), len(commandHelp))
+	}
+	if !slices.IsSortedFunc(schemas, func(a, b CommandSchema) int {
+		if a.Name < b.Name {
+			return -1
+		}
+		if a.Name > b.Name {
+			return 1
+		}
+		return 0
+	}) {
+		t.Fatal("CommandSchemas() should be sorted by name")
+	}
+
+	for _, schema := range schemas {
+		if schema.Usage == "" {
+			t.Fatalf("%s has no usage", schema.Name)
+		}
+		if schema.Name == "split-window" {
+			if schema.Flags["-h"] != FlagTypeBoolean || schema.Flags["-t"] != FlagTypeString {
+				t.Fatalf("split-window flags = %v", schema.Flags)
+			}
+		}
+	}
+}
@@ -5,7 +5,10 @@ import (
 	"sync"
 )
 
-const compatOptionFocusEvents = "focus-events"
+const (
+	compatOptionFocusEvents  = "focus-events"
+	compatOptionRemainOnExit = "remain-on-exit"
+)
 
 type compatOptionScopeKind string
 
@@ -41,12 +44,12 @@ func newCompatOptionStore() *compatOptionStore {
 }
 
 func supportedCompatOptionNames() []string {
-	return []string{compatOptionFocusEvents}
+	return []string{compatOptionFocusEvents, compatOptionRemainOnExit}
 }
 
 func compatOptionDefaultValue(name string) (string, bool) {
 	switch strings.TrimSpace(name) {
-	case compatOptionFocusEvents:
+	case compatOptionFocusEvents, compatOptionRemainOnExit:
 		return "off", true
 	default:
 		return "", false
@@ -55,7 +58,7 @@ func compatOptionDefaultValue(name string) (string, bool) {
 
 func normalizeCompatOptionValue(name string, value string) (string, bool) {
 	switch strings.TrimSpace(name) {
-	case compatOptionFocusEvents:
+	case compatOptionFocusEvents, compatOptionRemainOnExit:
 		switch strings.ToLower(strings.TrimSpace(value)) {
 		case "1", "on", "true":
 			return "on", true
@@ -0,0 +1,199 @@
+package tmux
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"myT-x/internal/ipc"
+)
+
+const (
+	// controlModeClientBuffer is the number of output chunks queued per
+	// control client. Chunks beyond it are dropped rather than blocking the
+	// pane read loop.
+	controlModeClientBuffer = 256
+	// controlModeSessionCheckInterval is how often a control client checks
+	// that its session still exists.
+	controlModeSessionCheckInterval = time.Second
+)
+
+// controlModeOutput is one pane output chunk queued for a control client.
+type controlModeOutput struct {
+	paneID string
+	data   []byte
+}
+
+// controlModeClient receives the output of every pane.
+type controlModeClient struct {
+	output  chan controlModeOutput
+	dropped atomic.Bool
+}
+
+// controlModeHub fans pane output out to the connected control clients.
+type controlModeHub struct {
+	mu      sync.RWMutex
+	clients map[*controlModeClient]struct{}
+	// count mirrors len(clients) so that pane read loops skip the lock when
+	// no control client is connected, which is the common case.
+	count atomic.Int32
+}
+
+func (h *controlModeHub) subscribe() *controlModeClient {
+	client := &controlModeClient{output: make(chan controlModeOutput, controlModeClientBuffer)}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.clients == nil {
+		h.clients = map[*controlModeClient]struct{}{}
+	}
+	h.clients[client] = struct{}{}
+	h.count.Store(int32(len(h.clients)))
+	return client
+}
+
+func (h *controlModeHub) unsubscribe(client *controlModeClient) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.clients, client)
+	h.count.Store(int32(len(h.clients)))
+}
+
+// publish queues a copy of data for every client. A client whose queue is
+// full loses the chunk.
+func (h *controlModeHub) publish(paneID string, data []byte) {
+	if h.count.Load() == 0 || len(data) == 0 {
+		return
+	}
+	chunk := controlModeOutput{paneID: paneID, data: append([]byte(nil), data...)}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for client := range h.clients {
+		select {
+		case client.output <- chunk:
+		default:
+			if !client.dropped.Swap(true) {
+				slog.Warn("[WARN-CONTROL] control client is too slow; dropping pane output", "paneId", paneID)
+			}
+		}
+	}
+}
+
+// controlModeEscape escapes pane output for a %output notification as tmux
+// does: bytes below space and backslash become a backslash and three octal
+// digits.
+func controlModeEscape(data []byte) string {
+	var b strings.Builder
+	b.Grow(len(data))
+	for _, c := range data {
+		if c < ' ' || c == '\\' {
+			fmt.Fprintf(&b, "\\%03o", c)
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// runControlMode runs the attach-session or new-session request of a control
+// mode client, then streams notifications to out until ctx is canceled (the
+// client detached) or the session goes away. The client sends its commands
+// as separate requests and frames their output itself.
+//
+// Notifications are the subset that tmux-aware tools need to follow a
+// session: %session-changed once attached, %output for every pane of the
+// session, and %sessions-changed when the session is gone.
+func (r *CommandRouter) runControlMode(ctx context.Context, req ipc.TmuxRequest, out io.Writer) ipc.TmuxResponse {
+	if r.opts.ControlModeEnabled != nil && !r.opts.ControlModeEnabled() {
+		return ipc.TmuxResponse{
+			ExitCode: 1,
+			Stderr:   "control mode is disabled; enable the control_mode feature flag\n",
+		}
+	}
+	initial := req
+	initial.Flags = maps.Clone(req.Flags)
+	delete(initial.Flags, ipc.ControlModeFlag)
+	command := canonicalTmuxCommandName(strings.TrimSpace(req.Command))
+	printName := command == "new-session" && !mustBool(initial.Flags["-P"])
+	if printName {
+		// Learn the name of the new session without showing it to the client.
+		initial.Flags["-P"] = true
+		initial.Flags["-F"] = "#{session_name}"
+	}
+
+	resp := r.Execute(initial)
+	shown := resp
+	if printName {
+		shown.Stdout = ""
+	}
+	if err := ipc.WriteControlModeBlock(out, 0, shown); err != nil || resp.ExitCode != 0 {
+		return ipc.TmuxResponse{ExitCode: 1}
+	}
+
+	sessionName := parseSessionName(strings.TrimSpace(mustString(initial.Flags["-t"])))
+	if command == "new-session" {
+		sessionName = strings.TrimSpace(resp.Stdout)
+		if !printName {
+			sessionName = strings.TrimSpace(mustString(initial.Flags["-s"]))
+		}
+	}
+	session, ok := r.sessions.GetSession(sessionName)
+	if !ok {
+		return errResp(fmt.Errorf("session not found: %s", sessionName))
+	}
+	sessionID := session.ID
+	if _, err := fmt.Fprintf(out, "%%session-changed $%d %s\n", sessionID, session.Name); err != nil {
+		return ipc.TmuxResponse{ExitCode: 1}
+	}
+
+	client := r.control.subscribe()
+	defer r.control.unsubscribe(client)
+	slog.Debug("[DEBUG-CONTROL] control client attached", "session", session.Name)
+
+	ticker := time.NewTicker(controlModeSessionCheckInterval)
+	defer ticker.Stop()
+	currentName := session.Name
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Debug("[DEBUG-CONTROL] control client detached", "session", currentName)
+			return okResp("")
+		case <-ticker.C:
+			// Follow renames by ID; the client stays attached to the session.
+			name, ok := r.sessions.sessionNameByID(sessionID)
+			if !ok {
+				if _, err := io.WriteString(out, "%sessions-changed\n"); err != nil {
+					return ipc.TmuxResponse{ExitCode: 1}
+				}
+				return okResp("")
+			}
+			currentName = name
+		case chunk := <-client.output:
+			name, _, ok := r.sessions.PaneLocation(chunk.paneID)
+			if !ok || name != currentName {
+				continue
+			}
+			line := "%output " + chunk.paneID + " " + controlModeEscape(chunk.data) + "\n"
+			if _, err := io.WriteString(out, line); err != nil {
+				return ipc.TmuxResponse{ExitCode: 1}
+			}
+		}
+	}
+}
+
+// sessionNameByID returns the name of the session with id.
+func (m *SessionManager) sessionNameByID(id int) (string, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for name, session := range m.sessions {
+		if session.ID == id {
+			return name, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,122 @@
+package tmux
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"myT-x/internal/ipc"
+)
+
+func TestControlModeEscape(t *testing.T) {
+	got := controlModeEscape([]byte("ok\r\n\x1b[1m\\ é"))
+	want := `ok\015\012\033[1m\134 é`
+	if got != want {
+		t.Fatalf("controlModeEscape() = %q, want %q", got, want)
+	}
+}
+
+func TestRunControlMode(t *testing.T) {
+	sm := NewSessionManager()
+	if _, _, err := sm.CreateSession("dev", "0", 120, 40); err != nil {
+		t.Fatalf("CreateSession(dev) error = %v", err)
+	}
+	if _, _, err := sm.CreateSession("other", "0", 120, 40); err != nil {
+		t.Fatalf("CreateSession(other) error = %v", err)
+	}
+	router := NewCommandRouter(sm, nil, RouterOptions{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reader, writer := io.Pipe()
+	done := make(chan ipc.TmuxResponse, 1)
+	go func() {
+		done <- router.ExecuteStream(ctx, ipc.TmuxRequest{
+			Command: "attach-session",
+			Flags:   map[string]any{"-t": "dev", ipc.ControlModeFlag: true},
+		}, writer, io.Discard)
+		writer.Close()
+	}()
+
+	lines := bufio.NewScanner(reader)
+	readLine := func() string {
+		t.Helper()
+		if !lines.Scan() {
+			t.Fatalf("control mode output ended: %v", lines.Err())
+		}
+		return lines.Text()
+	}
+	if line := readLine(); !strings.HasPrefix(line, "%begin ") {
+		t.Fatalf("first line = %q, want %%begin", line)
+	}
+	if line := readLine(); !strings.HasPrefix(line, "%end ") {
+		t.Fatalf("second line = %q, want %%end", line)
+	}
+	if line := readLine(); line != "%session-changed $0 dev" {
+		t.Fatalf("third line = %q, want session-changed", line)
+	}
+
+	// The client subscribes after announcing the session; wait for it.
+	deadline := time.Now().Add(5 * time.Second)
+	for router.control.count.Load() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("control client did not subscribe")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	router.control.publish("%1", []byte("other session\n"))
+	router.control.publish("%0", []byte("hi\r\n"))
+	if line := readLine(); line != `%output %0 hi\015\012` {
+		t.Fatalf("output line = %q", line)
+	}
+
+	cancel()
+	select {
+	case resp := <-done:
+		if resp.ExitCode != 0 {
+			t.Fatalf("ExecuteStream() = %#v, want success", resp)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("control mode did not stop after cancel")
+	}
+	if router.control.count.Load() != 0 {
+		t.Fatal("control client still subscribed after detach")
+	}
+}
+
+func TestRunControlModeUnknownSession(t *testing.T) {
+	router := NewCommandRouter(NewSessionManager(), nil, RouterOptions{})
+	var b strings.Builder
+	resp := router.ExecuteStream(context.Background(), ipc.TmuxRequest{
+		Command: "attach-session",
+		Flags:   map[string]any{"-t": "missing", ipc.ControlModeFlag: true},
+	}, &b, io.Discard)
+	if resp.ExitCode == 0 {
+		t.Fatal("ExecuteStream() succeeded for a missing session")
+	}
+	if !strings.Contains(b.String(), "%error ") || !strings.Contains(b.String(), "session not found: missing") {
+		t.Fatalf("output = %q, want an %%error block", b.String())
+	}
+}
+
+func TestRunControlModeDisabledByFeatureFlag(t *testing.T) {
+	sm := NewSessionManager()
+	if _, _, err := sm.CreateSession("dev", "0", 120, 40); err != nil {
+		t.Fatalf("CreateSession(dev) error = %v", err)
+	}
+	router := NewCommandRouter(sm, nil, RouterOptions{ControlModeEnabled: func() bool { return false }})
+	var b strings.Builder
+	resp := router.ExecuteStream(context.Background(), ipc.TmuxRequest{
+		Command: "attach-session",
+		Flags:   map[string]any{"-t": "dev", ipc.ControlModeFlag: true},
+	}, &b, io.Discard)
+	if resp.ExitCode == 0 || !strings.Contains(resp.Stderr, "control_mode") {
+		t.Fatalf("response = %+v, want disabled error", resp)
+	}
+	if b.Len() != 0 {
+		t.Fatalf("output = %q, want none", b.String())
+	}
+}
@@ -19,6 +19,7 @@
 //
 //	types.go                             — Model types (TmuxSession, TmuxWindow, TmuxPane, snapshots, events)
 //	layout.go                            — Pane layout tree (LayoutNode, split/swap/clone)
+//	layout_string.go                     — tmux layout dump format (window_layout, select-layout)
 //	buffer_store.go                      — Paste buffer storage (BufferStore, PasteBuffer)
 //	pane_output_history.go               — Ring buffer for terminal output capture
 //
@@ -26,12 +27,12 @@
 //
 //	session_manager.go                   — Struct, constructor, mutation markers
 //	session_manager_sessions.go          — Session CRUD
-//	session_manager_windows.go           — Window CRUD
+//	session_manager_window.go            — Window CRUD
 //	session_manager_panes.go             — Pane split, activation, layout presets
 //	session_manager_pane_lifecycle.go    — Pane lifecycle (creation, destruction, swap)
 //	session_manager_pane_io.go           — Pane I/O (list, write, resize, rename)
 //	session_manager_snapshot.go          — Snapshot generation and caching
-//	session_manager_targets.go           — Target resolution, directional navigation
+//	session_manager_targets.go           — Target resolution (incl. ".1", "+", "-" relative to the caller pane), directional navigation
 //	session_manager_env.go               — Environment variable management
 //	session_manager_idle.go              — Idle state detection
 //	session_manager_helpers.go           — Shared utilities (parsePaneID, SanitizeSessionName, etc.)
@@ -45,15 +46,17 @@
 //
 // Command handlers (one file per command family):
 //
-//	command_router_handlers_session.go   — new/kill/rename/list/has/attach-session
+//	command_router_handlers_session.go   — new/kill/rename/list/has/attach-session, server-info
+//	command_router_handlers_copy.go      — copy-between-sessions
 //	command_router_handlers_window.go    — new/kill/rename/list/select/activate-window
 //	command_router_handlers_pane.go      — split-window, select-pane, capture-pane, copy-mode
 //	command_router_handlers_pane_lifecycle.go — kill-pane, resize-pane, layout event helpers
-//	command_router_handlers_options.go   — set-option, show-options, select-layout compatibility handlers
+//	command_router_handlers_options.go   — set-option, show-options, select-layout handlers
 //	command_router_handlers_display.go   — display-message
 //	command_router_handlers_buffer.go    — list/set/paste/load/save-buffer
 //	command_router_handlers_shell.go     — run-shell, if-shell
 //	command_router_handlers_mcp.go       — mcp-resolve-stdio, resolve-session-by-cwd
+//	command_router_handlers_help.go      — list-commands and the command help registry
 //
 // Parsing & formatting:
 //
@@ -85,7 +85,7 @@ func firstPaneInSession(session *TmuxSession) *TmuxPane {
 // formatSessionLine, etc.) do NOT carry a "Locked" suffix because they are
 // pure functions operating on already-cloned snapshots or value parameters.
 // They do not access SessionManager fields and therefore have no lock
-// requirement. See session_manager_windows.go for the "Locked"/"RLocked"
+// requirement. See session_manager_window.go for the "Locked"/"RLocked"
 // suffix convention used by SessionManager methods that operate under lock.
 //
 // NOTE (S-46): Nested #{...} placeholders are supported via manual brace-matching
@@ -161,9 +161,10 @@ func resolveFormatExpr(expr string, pane *TmuxPane) string {
 func lookupFormatVariable(name string, pane *TmuxPane) string {
 	if pane == nil {
 		switch name {
-		case "session_name", "session_id", "window_name", "window_id", "pane_id", "pane_tty":
+		case "session_name", "session_id", "window_name", "window_id", "window_layout", "pane_id", "pane_tty", "pane_current_path":
 			return ""
-		case "session_windows", "window_index", "window_panes", "window_active", "pane_index", "pane_width", "pane_height", "pane_active", "session_created":
+		case "session_windows", "window_index", "window_panes", "window_active", "pane_index", "pane_width", "pane_height", "pane_active", "pane_pid", "session_created",
+			"alternate_on", "keypad_cursor_flag", "keypad_flag":
 			return "0"
 		case "pane_active_suffix":
 			return ""
@@ -194,6 +195,15 @@ func lookupFormatVariable(name string, pane *TmuxPane) string {
 		return "0"
 	case "pane_tty":
 		return pane.ttyPath()
+	case "pane_pid":
+		return strconv.Itoa(pane.processID())
+	case "pane_current_path":
+		// ConPTY does not report directory changes of the shell, so this is
+		// the directory the pane started in, falling back to the session's.
+		if pane.startDir != "" {
+			return pane.startDir
+		}
+		return sessionWorkDir(session)
 	case "pane_active_suffix":
 		if pane.Active {
 			return " (active)"
@@ -201,6 +211,12 @@ func lookupFormatVariable(name string, pane *TmuxPane) string {
 		return ""
 	case "pane_title":
 		return pane.Title
+	case "alternate_on":
+		return formatFlag(pane.TerminalModes.AlternateScreen)
+	case "keypad_cursor_flag":
+		return formatFlag(pane.TerminalModes.AppCursorKeys)
+	case "keypad_flag":
+		return formatFlag(pane.TerminalModes.AppKeypad)
 	case "window_index":
 		if window == nil || session == nil {
 			return "0"
@@ -225,6 +241,11 @@ func lookupFormatVariable(name string, pane *TmuxPane) string {
 			return "0"
 		}
 		return strconv.Itoa(len(window.Panes))
+	case "window_layout":
+		if window == nil {
+			return ""
+		}
+		return windowLayoutString(window)
 	case "window_active":
 		if window == nil || session == nil {
 			return "0"
@@ -266,6 +287,13 @@ func lookupFormatVariable(name string, pane *TmuxPane) string {
 	}
 }
 
+func formatFlag(on bool) string {
+	if on {
+		return "1"
+	}
+	return "0"
+}
+
 func joinLines(lines []string) string {
 	if len(lines) == 0 {
 		return ""
@@ -75,6 +75,11 @@ func TestLookupFormatVariableAllVariables(t *testing.T) {
 		Title:  "my-pane",
 		Window: window,
 		Env:    map[string]string{},
+
+		startDir: `C:\work\sub`,
+		pid:      4242,
+
+		TerminalModes: PaneTerminalModes{AlternateScreen: true, AppCursorKeys: true},
 	}
 	window.Panes = []*TmuxPane{pane}
 	session.Windows = []*TmuxWindow{window}
@@ -91,8 +96,13 @@ func TestLookupFormatVariableAllVariables(t *testing.T) {
 		{name: "pane_height", variable: "pane_height", want: "50"},
 		{name: "pane_active when active", variable: "pane_active", want: "1"},
 		{name: "pane_tty", variable: "pane_tty", want: `\\.\conpty\%7`},
+		{name: "pane_pid from read clone", variable: "pane_pid", want: "4242"},
+		{name: "pane_current_path", variable: "pane_current_path", want: `C:\work\sub`},
 		{name: "pane_active_suffix when active", variable: "pane_active_suffix", want: " (active)"},
 		{name: "pane_title", variable: "pane_title", want: "my-pane"},
+		{name: "alternate_on", variable: "alternate_on", want: "1"},
+		{name: "keypad_cursor_flag", variable: "keypad_cursor_flag", want: "1"},
+		{name: "keypad_flag", variable: "keypad_flag", want: "0"},
 		// --- window variables ---
 		{name: "window_id", variable: "window_id", want: "@5"},
 		{name: "window_index returns session-local window index", variable: "window_index", want: "0"},
@@ -126,6 +136,7 @@ func TestLookupFormatVariableInactivePane(t *testing.T) {
 		Name:           "demo",
 		ActiveWindowID: 5,
 		Env:            map[string]string{},
+		RootPath:       `C:\demo`,
 	}
 	window := &TmuxWindow{
 		ID:       5,
@@ -153,6 +164,7 @@ func TestLookupFormatVariableInactivePane(t *testing.T) {
 		{name: "pane_active when inactive", variable: "pane_active", want: "0"},
 		{name: "pane_active_suffix when inactive", variable: "pane_active_suffix", want: ""},
 		{name: "pane_title when empty", variable: "pane_title", want: ""},
+		{name: "pane_current_path falls back to session root", variable: "pane_current_path", want: `C:\demo`},
 	}
 
 	for _, tt := range tests {
@@ -196,6 +208,8 @@ func TestLookupFormatVariableNilPane(t *testing.T) {
 		{name: "window_id", variable: "window_id", want: ""},
 		{name: "pane_id", variable: "pane_id", want: ""},
 		{name: "pane_tty", variable: "pane_tty", want: ""},
+		{name: "pane_current_path", variable: "pane_current_path", want: ""},
+		{name: "pane_pid", variable: "pane_pid", want: "0"},
 		{name: "session_windows", variable: "session_windows", want: "0"},
 		{name: "window_index", variable: "window_index", want: "0"},
 		{name: "window_panes", variable: "window_panes", want: "0"},
@@ -205,6 +219,7 @@ func TestLookupFormatVariableNilPane(t *testing.T) {
 		{name: "pane_height", variable: "pane_height", want: "0"},
 		{name: "pane_active", variable: "pane_active", want: "0"},
 		{name: "session_created", variable: "session_created", want: "0"},
+		{name: "alternate_on", variable: "alternate_on", want: "0"},
 		{name: "pane_active_suffix", variable: "pane_active_suffix", want: ""},
 		{name: "unknown_var", variable: "unknown_var", want: ""},
 	}
@@ -31,6 +31,18 @@ var sendKeysTable = map[string][]byte{
 	"bspace":  {0x7f},
 }
 
+// cursorKeyFinals maps named cursor keys (all lowercase) to the final byte of
+// their sequence: ESC [ <final> normally, ESC O <final> while the pane
+// application has enabled application cursor keys (DECCKM).
+var cursorKeyFinals = map[string]byte{
+	"up":    'A',
+	"down":  'B',
+	"right": 'C',
+	"left":  'D',
+	"home":  'H',
+	"end":   'F',
+}
+
 // copyModeCommandTable maps copy-mode command names (all lowercase) to byte sequences.
 // Used by send-keys -X to translate copy-mode commands to terminal input.
 // Unknown commands are silently ignored (shim spec: never block on transform failure).
@@ -58,10 +70,17 @@ func TranslateCopyModeCommand(command string) ([]byte, bool) {
 	return value, ok
 }
 
-// TranslateSendKeys translates tmux send-keys arguments to bytes.
-// Each argument is resolved in order: sendKeysTable lookup, then
-// parseControlKey fallback, then raw byte passthrough.
+// TranslateSendKeys translates tmux send-keys arguments to bytes for a pane
+// in default terminal modes. See TranslateSendKeysForModes.
 func TranslateSendKeys(args []string) []byte {
+	return TranslateSendKeysForModes(args, PaneTerminalModes{})
+}
+
+// TranslateSendKeysForModes translates tmux send-keys arguments to bytes.
+// Each argument is resolved in order: cursor keys encoded for modes,
+// sendKeysTable lookup, then parseControlKey fallback, then raw byte
+// passthrough.
+func TranslateSendKeysForModes(args []string, modes PaneTerminalModes) []byte {
 	if len(args) == 0 {
 		return nil
 	}
@@ -69,6 +88,18 @@ func TranslateSendKeys(args []string) []byte {
 	out := make([]byte, 0, 64)
 	for _, arg := range args {
 		normalized := normalizeSendKeyToken(arg)
+		if final, ok := cursorKeyFinals[normalized]; ok {
+			introducer := byte('[')
+			if modes.AppCursorKeys {
+				introducer = 'O'
+			}
+			out = append(out, 0x1b, introducer, final)
+			continue
+		}
+		if normalized == "kpenter" && modes.AppKeypad {
+			out = append(out, 0x1b, 'O', 'M')
+			continue
+		}
 		if value, ok := sendKeysTable[normalized]; ok {
 			out = append(out, value...)
 			continue
@@ -550,3 +550,44 @@ func TestTranslateCopyModeCommand(t *testing.T) {
 		})
 	}
 }
+
+func TestTranslateSendKeysForModes(t *testing.T) {
+	tests := []struct {
+		name  string
+		args  []string
+		modes PaneTerminalModes
+		want  string
+	}{
+		{
+			name: "cursor keys in normal mode",
+			args: []string{"Up", "down", "RIGHT", "Left", "Home", "End"},
+			want: "\x1b[A\x1b[B\x1b[C\x1b[D\x1b[H\x1b[F",
+		},
+		{
+			name:  "cursor keys in application mode",
+			args:  []string{"Up", "Down", "Home"},
+			modes: PaneTerminalModes{AppCursorKeys: true},
+			want:  "\x1bOA\x1bOB\x1bOH",
+		},
+		{
+			name:  "KPEnter in application keypad mode",
+			args:  []string{"KPEnter", "Enter"},
+			modes: PaneTerminalModes{AppKeypad: true},
+			want:  "\x1bOM\r",
+		},
+		{
+			name:  "other keys are unaffected by modes",
+			args:  []string{"ls", "Space", "C-c"},
+			modes: PaneTerminalModes{AppCursorKeys: true, AppKeypad: true},
+			want:  "ls \x03",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TranslateSendKeysForModes(tt.args, tt.modes); string(got) != tt.want {
+				t.Fatalf("TranslateSendKeysForModes(%q) = %q, want %q", tt.args, got, tt.want)
+			}
+		})
+	}
+}
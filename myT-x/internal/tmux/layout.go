@@ -108,6 +108,16 @@ const (
 	PresetTiled          LayoutPreset = "tiled"
 )
 
+// IsBuiltinLayoutPreset reports whether name is one of the built-in presets.
+func IsBuiltinLayoutPreset(name string) bool {
+	switch LayoutPreset(name) {
+	case PresetEvenHorizontal, PresetEvenVertical, PresetMainVertical, PresetMainHorizontal, PresetTiled:
+		return true
+	default:
+		return false
+	}
+}
+
 // BuildPresetLayout creates a layout tree from a preset for the given pane IDs.
 func BuildPresetLayout(preset LayoutPreset, paneIDs []int) *LayoutNode {
 	if len(paneIDs) == 0 {
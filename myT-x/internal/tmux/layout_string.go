@@ -0,0 +1,362 @@
+package tmux
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// tmux layout strings ("window_layout") describe a window as a cell tree:
+//
+//	d463,159x48,0,0{79x48,0,0,0,79x48,80,0,1}
+//
+// The leading 4-digit hex value is a checksum of the rest. Each cell is
+// WxH,X,Y followed by ",<pane id>" for a pane, "{...}" for cells laid out
+// left to right, or "[...]" for cells laid out top to bottom. Siblings are
+// separated by one-cell borders. Older tmux versions omit pane ids; those
+// layouts are accepted too.
+
+// minLayoutCellSize is the smallest cell dimension used when pane sizes are unknown.
+const minLayoutCellSize = 1
+
+// layoutCell is the geometry form of a LayoutNode used for tmux interchange.
+type layoutCell struct {
+	width, height int
+	x, y          int
+	paneID        int
+	direction     SplitDirection // empty for panes
+	children      []*layoutCell
+}
+
+// FormatLayoutString renders root in tmux's layout dump format.
+// paneSizes maps pane IDs to their current [width, height]; missing or zero
+// sizes fall back to one cell so the result is always well-formed.
+func FormatLayoutString(root *LayoutNode, paneSizes map[int][2]int) string {
+	if root == nil {
+		return ""
+	}
+	cell := layoutCellFromNode(root, paneSizes)
+	cell.normalize(0, 0, cell.width, cell.height)
+
+	var body strings.Builder
+	cell.write(&body)
+	return fmt.Sprintf("%04x,%s", layoutChecksum(body.String()), body.String())
+}
+
+// ParseLayoutString parses a tmux layout dump and assigns paneIDs to its
+// panes in order, as tmux does for select-layout. The pane count must match.
+func ParseLayoutString(layout string, paneIDs []int) (*LayoutNode, error) {
+	layout = strings.TrimSpace(layout)
+	checksumText, body, ok := strings.Cut(layout, ",")
+	if !ok || len(checksumText) != 4 {
+		return nil, fmt.Errorf("invalid layout: %s", layout)
+	}
+	checksum, err := strconv.ParseUint(checksumText, 16, 16)
+	if err != nil || uint16(checksum) != layoutChecksum(body) {
+		return nil, fmt.Errorf("invalid layout: checksum mismatch: %s", layout)
+	}
+
+	parser := layoutParser{input: body}
+	cell, err := parser.parseCell()
+	if err != nil {
+		return nil, fmt.Errorf("invalid layout: %w", err)
+	}
+	if parser.pos != len(parser.input) {
+		return nil, fmt.Errorf("invalid layout: trailing data at offset %d", parser.pos)
+	}
+	if err := cell.check(); err != nil {
+		return nil, fmt.Errorf("invalid layout: %w", err)
+	}
+
+	paneCount := cell.paneCount()
+	if paneCount != len(paneIDs) {
+		return nil, fmt.Errorf("layout has %d panes but window has %d", paneCount, len(paneIDs))
+	}
+	next := 0
+	return cell.toNode(paneIDs, &next), nil
+}
+
+// IsLayoutString reports whether s looks like a tmux layout dump rather than
+// a preset name. It does not validate the layout.
+func IsLayoutString(s string) bool {
+	checksumText, rest, ok := strings.Cut(strings.TrimSpace(s), ",")
+	if !ok || len(checksumText) != 4 || rest == "" {
+		return false
+	}
+	_, err := strconv.ParseUint(checksumText, 16, 16)
+	return err == nil
+}
+
+// layoutChecksum is tmux's layout_checksum.
+func layoutChecksum(layout string) uint16 {
+	var csum uint16
+	for i := 0; i < len(layout); i++ {
+		csum = (csum >> 1) + ((csum & 1) << 15)
+		csum += uint16(layout[i])
+	}
+	return csum
+}
+
+func layoutCellFromNode(node *LayoutNode, paneSizes map[int][2]int) *layoutCell {
+	if node.Type != LayoutSplit || node.Children[0] == nil || node.Children[1] == nil {
+		size := paneSizes[node.PaneID]
+		return &layoutCell{
+			width:  max(size[0], minLayoutCellSize),
+			height: max(size[1], minLayoutCellSize),
+			paneID: node.PaneID,
+		}
+	}
+	first := layoutCellFromNode(node.Children[0], paneSizes)
+	second := layoutCellFromNode(node.Children[1], paneSizes)
+	cell := &layoutCell{direction: node.Direction, children: []*layoutCell{first, second}}
+	if node.Direction == SplitVertical {
+		cell.width = max(first.width, second.width)
+		cell.height = first.height + 1 + second.height
+	} else {
+		cell.direction = SplitHorizontal
+		cell.width = first.width + 1 + second.width
+		cell.height = max(first.height, second.height)
+	}
+	return cell
+}
+
+// normalize positions the cell and stretches children across the cross axis
+// so that the tree satisfies tmux's layout_check.
+func (c *layoutCell) normalize(x, y, width, height int) {
+	c.x, c.y = x, y
+	if len(c.children) == 0 {
+		c.width, c.height = width, height
+		return
+	}
+
+	// Along the main axis children keep their sizes; the last child absorbs
+	// any difference so sizes plus borders add up exactly.
+	mainTotal := width
+	if c.direction == SplitVertical {
+		mainTotal = height
+	}
+	remaining := mainTotal - (len(c.children) - 1)
+	offset := 0
+	for i, child := range c.children {
+		size := child.width
+		if c.direction == SplitVertical {
+			size = child.height
+		}
+		if i == len(c.children)-1 {
+			size = remaining
+		}
+		size = max(size, minLayoutCellSize)
+		remaining -= size
+		if c.direction == SplitVertical {
+			child.normalize(x, y+offset, width, size)
+		} else {
+			child.normalize(x+offset, y, size, height)
+		}
+		offset += size + 1
+	}
+	c.width, c.height = width, height
+}
+
+func (c *layoutCell) write(out *strings.Builder) {
+	fmt.Fprintf(out, "%dx%d,%d,%d", c.width, c.height, c.x, c.y)
+	if len(c.children) == 0 {
+		fmt.Fprintf(out, ",%d", c.paneID)
+		return
+	}
+	open, closeBracket := byte('{'), byte('}')
+	if c.direction == SplitVertical {
+		open, closeBracket = '[', ']'
+	}
+	out.WriteByte(open)
+	for i, child := range c.children {
+		if i > 0 {
+			out.WriteByte(',')
+		}
+		child.write(out)
+	}
+	out.WriteByte(closeBracket)
+}
+
+// check mirrors tmux's layout_check: children must fill their parent exactly.
+func (c *layoutCell) check() error {
+	if len(c.children) == 0 {
+		return nil
+	}
+	if len(c.children) == 1 {
+		return c.children[0].check()
+	}
+	total := -1
+	for _, child := range c.children {
+		if c.direction == SplitVertical {
+			if child.width != c.width {
+				return errors.New("child width does not match parent")
+			}
+			total += child.height + 1
+		} else {
+			if child.height != c.height {
+				return errors.New("child height does not match parent")
+			}
+			total += child.width + 1
+		}
+		if err := child.check(); err != nil {
+			return err
+		}
+	}
+	want := c.width
+	if c.direction == SplitVertical {
+		want = c.height
+	}
+	if total != want {
+		return errors.New("child sizes do not add up to parent")
+	}
+	return nil
+}
+
+func (c *layoutCell) paneCount() int {
+	if len(c.children) == 0 {
+		return 1
+	}
+	count := 0
+	for _, child := range c.children {
+		count += child.paneCount()
+	}
+	return count
+}
+
+// toNode converts the n-ary cell tree into the binary LayoutNode tree,
+// deriving each split ratio from cell sizes along the split axis.
+func (c *layoutCell) toNode(paneIDs []int, next *int) *LayoutNode {
+	if len(c.children) == 0 {
+		node := newLeafLayout(paneIDs[*next])
+		*next++
+		return node
+	}
+	return c.childrenToNode(c.children, paneIDs, next)
+}
+
+func (c *layoutCell) childrenToNode(children []*layoutCell, paneIDs []int, next *int) *LayoutNode {
+	if len(children) == 1 {
+		return children[0].toNode(paneIDs, next)
+	}
+	size := func(cell *layoutCell) int {
+		if c.direction == SplitVertical {
+			return cell.height
+		}
+		return cell.width
+	}
+	total := 0
+	for _, child := range children {
+		total += size(child)
+	}
+	first := children[0].toNode(paneIDs, next)
+	rest := c.childrenToNode(children[1:], paneIDs, next)
+	return &LayoutNode{
+		Type:      LayoutSplit,
+		Direction: c.direction,
+		Ratio:     float64(size(children[0])) / float64(total),
+		Children:  [2]*LayoutNode{first, rest},
+	}
+}
+
+type layoutParser struct {
+	input string
+	pos   int
+}
+
+func (p *layoutParser) parseCell() (*layoutCell, error) {
+	width, err := p.parseInt('x')
+	if err != nil {
+		return nil, err
+	}
+	height, err := p.parseInt(',')
+	if err != nil {
+		return nil, err
+	}
+	x, err := p.parseInt(',')
+	if err != nil {
+		return nil, err
+	}
+	y, err := p.parseNumber()
+	if err != nil {
+		return nil, err
+	}
+	if width < minLayoutCellSize || height < minLayoutCellSize {
+		return nil, fmt.Errorf("cell size %dx%d at offset %d is too small", width, height, p.pos)
+	}
+	cell := &layoutCell{width: width, height: height, x: x, y: y}
+
+	if p.pos >= len(p.input) {
+		return cell, nil
+	}
+	switch p.input[p.pos] {
+	case ',':
+		// Either ",<pane id>" or the separator before the next sibling cell:
+		// a pane id is never followed by 'x'.
+		save := p.pos
+		p.pos++
+		if _, err := p.parseNumber(); err == nil && (p.pos >= len(p.input) || p.input[p.pos] != 'x') {
+			return cell, nil
+		}
+		p.pos = save
+		return cell, nil
+	case '}', ']':
+		return cell, nil
+	case '{', '[':
+		cell.direction = SplitHorizontal
+		closeBracket := byte('}')
+		if p.input[p.pos] == '[' {
+			cell.direction = SplitVertical
+			closeBracket = ']'
+		}
+		p.pos++
+		for {
+			child, err := p.parseCell()
+			if err != nil {
+				return nil, err
+			}
+			cell.children = append(cell.children, child)
+			if p.pos >= len(p.input) {
+				return nil, errors.New("unterminated cell list")
+			}
+			if p.input[p.pos] == ',' {
+				p.pos++
+				continue
+			}
+			if p.input[p.pos] != closeBracket {
+				return nil, fmt.Errorf("unexpected %q at offset %d", p.input[p.pos], p.pos)
+			}
+			p.pos++
+			return cell, nil
+		}
+	default:
+		return nil, fmt.Errorf("unexpected %q at offset %d", p.input[p.pos], p.pos)
+	}
+}
+
+func (p *layoutParser) parseInt(sep byte) (int, error) {
+	value, err := p.parseNumber()
+	if err != nil {
+		return 0, err
+	}
+	if p.pos >= len(p.input) || p.input[p.pos] != sep {
+		return 0, fmt.Errorf("expected %q at offset %d", sep, p.pos)
+	}
+	p.pos++
+	return value, nil
+}
+
+func (p *layoutParser) parseNumber() (int, error) {
+	start := p.pos
+	for p.pos < len(p.input) && p.input[p.pos] >= '0' && p.input[p.pos] <= '9' {
+		p.pos++
+	}
+	if start == p.pos {
+		return 0, fmt.Errorf("expected number at offset %d", start)
+	}
+	value, err := strconv.Atoi(p.input[start:p.pos])
+	if err != nil {
+		return 0, fmt.Errorf("invalid number at offset %d: %w", start, err)
+	}
+	return value, nil
+}
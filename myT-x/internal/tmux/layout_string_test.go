@@ -0,0 +1,124 @@
+package tmux
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestParseLayoutStringTmuxSamples(t *testing.T) {
+	tests := []struct {
+		name      string
+		layout    string
+		paneIDs   []int
+		direction SplitDirection
+		ratio     float64
+	}{
+		{
+			name:      "side by side with pane ids",
+			layout:    "d463,159x48,0,0{79x48,0,0,0,79x48,80,0,1}",
+			paneIDs:   []int{7, 9},
+			direction: SplitHorizontal,
+			ratio:     79.0 / 158.0,
+		},
+		{
+			// Example from the tmux manual (pre-1.9 format without pane ids).
+			name:      "legacy format without pane ids",
+			layout:    "bb62,159x48,0,0{79x48,0,0,79x48,80,0}",
+			paneIDs:   []int{1, 2},
+			direction: SplitHorizontal,
+			ratio:     79.0 / 158.0,
+		},
+		{
+			name:      "stacked",
+			layout:    "b104,159x48,0,0[159x24,0,0,0,159x23,0,25,1]",
+			paneIDs:   []int{3, 4},
+			direction: SplitVertical,
+			ratio:     24.0 / 47.0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, err := ParseLayoutString(tt.layout, tt.paneIDs)
+			if err != nil {
+				t.Fatalf("ParseLayoutString() error = %v", err)
+			}
+			if root.Type != LayoutSplit || root.Direction != tt.direction {
+				t.Fatalf("root = %+v, want %s split", root, tt.direction)
+			}
+			if math.Abs(root.Ratio-tt.ratio) > 1e-9 {
+				t.Fatalf("Ratio = %v, want %v", root.Ratio, tt.ratio)
+			}
+			if root.Children[0].PaneID != tt.paneIDs[0] || root.Children[1].PaneID != tt.paneIDs[1] {
+				t.Fatalf("pane order = %d,%d, want %v", root.Children[0].PaneID, root.Children[1].PaneID, tt.paneIDs)
+			}
+		})
+	}
+}
+
+func TestParseLayoutStringRejectsInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		layout  string
+		paneIDs []int
+		wantErr string
+	}{
+		{name: "not a layout", layout: "tiled", paneIDs: []int{1}, wantErr: "invalid layout"},
+		{name: "bad checksum", layout: "0000,80x24,0,0,0", paneIDs: []int{1}, wantErr: "checksum"},
+		{name: "pane count mismatch", layout: "b25d,80x24,0,0,0", paneIDs: []int{1, 2}, wantErr: "layout has 1 panes"},
+		{
+			name:    "children do not fill parent",
+			layout:  withLayoutChecksum("159x48,0,0{70x48,0,0,0,79x48,80,0,1}"),
+			paneIDs: []int{1, 2},
+			wantErr: "do not add up",
+		},
+		{name: "truncated", layout: withLayoutChecksum("159x48,0,0{79x48,0,0,0"), paneIDs: []int{1}, wantErr: "invalid layout"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseLayoutString(tt.layout, tt.paneIDs)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("ParseLayoutString() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFormatLayoutStringRoundTrip(t *testing.T) {
+	paneIDs := []int{0, 1, 2}
+	root := BuildPresetLayout(PresetMainVertical, paneIDs)
+	sizes := map[int][2]int{
+		0: {100, 40},
+		1: {59, 20},
+		2: {59, 19},
+	}
+
+	layout := FormatLayoutString(root, sizes)
+	want := withLayoutChecksum("160x40,0,0{100x40,0,0,0,59x40,101,0[59x20,101,0,1,59x19,101,21,2]}")
+	if layout != want {
+		t.Fatalf("FormatLayoutString() = %q, want %q", layout, want)
+	}
+
+	parsed, err := ParseLayoutString(layout, paneIDs)
+	if err != nil {
+		t.Fatalf("ParseLayoutString(%q) error = %v", layout, err)
+	}
+	if again := FormatLayoutString(parsed, sizes); again != layout {
+		t.Fatalf("round trip = %q, want %q", again, layout)
+	}
+}
+
+func TestFormatLayoutStringUnknownSizes(t *testing.T) {
+	root := BuildPresetLayout(PresetTiled, []int{1, 2, 3})
+	layout := FormatLayoutString(root, nil)
+	if _, err := ParseLayoutString(layout, []int{1, 2, 3}); err != nil {
+		t.Fatalf("FormatLayoutString(nil sizes) = %q does not parse: %v", layout, err)
+	}
+}
+
+func withLayoutChecksum(body string) string {
+	return fmt.Sprintf("%04x,%s", layoutChecksum(body), body)
+}
@@ -0,0 +1,202 @@
+package tmux
+
+import (
+	"bytes"
+	"strconv"
+	"unicode/utf8"
+)
+
+const (
+	// foldMinRun is the shortest run of matching lines replaced by a
+	// "[line xN]" marker. Shorter runs are kept verbatim because the marker
+	// would save nothing.
+	foldMinRun = 4
+	// maxFoldLineBytes bounds a line held back for comparison. Longer lines
+	// are written through and never folded.
+	maxFoldLineBytes = 4096
+)
+
+// outputFolder collapses runs of identical or near-identical lines (progress
+// spinners, polling loops) into the first line followed by a "[line xN]"
+// marker. Lines compare by foldKey, which ignores escape sequences, digits,
+// and spinner glyphs. A CR not followed by LF also ends a line, so spinners
+// that redraw in place fold as well.
+//
+// The folder is not safe for concurrent use; PaneOutputHistory serializes it.
+type outputFolder struct {
+	// line is the current incomplete line.
+	line []byte
+	// pendingCR is set when line ends with a CR that may start a CRLF.
+	pendingCR bool
+	// lastKey is the foldKey of the last written line, "" when it cannot fold.
+	lastKey string
+	// repeats counts lines matching lastKey since it was written.
+	repeats int
+	// held keeps the repeated lines while the run is too short to fold.
+	held [][]byte
+}
+
+// feed passes data through, calling write with the bytes to store.
+func (f *outputFolder) feed(data []byte, write func([]byte)) {
+	for _, c := range data {
+		if f.pendingCR {
+			f.pendingCR = false
+			if c == '\n' {
+				f.line = append(f.line, c)
+				f.completeLine(write)
+				continue
+			}
+			f.completeLine(write)
+		}
+		f.line = append(f.line, c)
+		switch {
+		case c == '\n':
+			f.completeLine(write)
+		case c == '\r':
+			f.pendingCR = true
+		case len(f.line) >= maxFoldLineBytes:
+			f.flushRun(write)
+			write(f.line)
+			f.line = f.line[:0]
+			f.lastKey = ""
+		}
+	}
+}
+
+func (f *outputFolder) completeLine(write func([]byte)) {
+	key := foldKey(f.line)
+	if key != "" && key == f.lastKey {
+		f.repeats++
+		if f.repeats <= foldMinRun-2 {
+			f.held = append(f.held, append([]byte(nil), f.line...))
+		} else {
+			f.held = nil
+		}
+	} else {
+		f.flushRun(write)
+		write(f.line)
+		f.lastKey = key
+	}
+	f.line = f.line[:0]
+}
+
+// flushRun writes the held lines or the marker of the run that just ended.
+func (f *outputFolder) flushRun(write func([]byte)) {
+	if f.repeats == 0 {
+		return
+	}
+	if run := f.appendRun(nil); len(run) > 0 {
+		write(run)
+	}
+	f.repeats = 0
+	f.held = nil
+}
+
+// appendRun appends the held lines, or the marker once the run is long
+// enough to fold, to dst.
+func (f *outputFolder) appendRun(dst []byte) []byte {
+	if f.repeats == 0 {
+		return dst
+	}
+	if f.repeats <= foldMinRun-2 {
+		for _, line := range f.held {
+			dst = append(dst, line...)
+		}
+		return dst
+	}
+	dst = append(dst, "[line x"...)
+	dst = strconv.AppendInt(dst, int64(f.repeats+1), 10)
+	return append(dst, "]\r\n"...)
+}
+
+// pending returns the output held back by the folder: the current run and
+// the incomplete line.
+func (f *outputFolder) pending() []byte {
+	return append(f.appendRun(nil), f.line...)
+}
+
+// foldKey normalizes a line for comparison: escape sequences, digits, spinner
+// glyphs, and surrounding whitespace are ignored. Lines with an empty key
+// (blank lines) never fold.
+func foldKey(line []byte) string {
+	key := make([]byte, 0, len(line))
+	lastDigit := false
+	for i := 0; i < len(line); {
+		c := line[i]
+		switch {
+		case c == 0x1b:
+			i = skipEscapeSequence(line, i)
+			continue
+		case c >= '0' && c <= '9':
+			if !lastDigit {
+				key = append(key, '#')
+			}
+			lastDigit = true
+			i++
+			continue
+		case c < 0x20 || c == 0x7f:
+			i++
+			continue
+		}
+		lastDigit = false
+		r, size := utf8.DecodeRune(line[i:])
+		if isSpinnerRune(r, len(bytes.TrimLeft(key, " ")) == 0) {
+			i += size
+			continue
+		}
+		key = append(key, line[i:i+size]...)
+		i += size
+	}
+	start, end := 0, len(key)
+	for start < end && key[start] == ' ' {
+		start++
+	}
+	for end > start && key[end-1] == ' ' {
+		end--
+	}
+	return string(key[start:end])
+}
+
+// skipEscapeSequence returns the index just past the escape sequence that
+// starts at line[i] (an ESC). Unterminated sequences run to the end of line.
+func skipEscapeSequence(line []byte, i int) int {
+	i++
+	if i >= len(line) {
+		return i
+	}
+	switch line[i] {
+	case '[':
+		for i++; i < len(line); i++ {
+			if line[i] >= 0x40 && line[i] <= 0x7e {
+				return i + 1
+			}
+		}
+	case ']':
+		for i++; i < len(line); i++ {
+			if line[i] == 0x07 {
+				return i + 1
+			}
+			if line[i] == 0x1b && i+1 < len(line) && line[i+1] == '\\' {
+				return i + 2
+			}
+		}
+	default:
+		return i + 1
+	}
+	return i
+}
+
+// isSpinnerRune reports whether r is a glyph commonly animated by spinners.
+// ASCII spinner frames count only at the start of a line, where they cannot
+// be confused with paths or separators.
+func isSpinnerRune(r rune, atLineStart bool) bool {
+	switch {
+	case r >= 0x2800 && r <= 0x28ff: // Braille patterns
+		return true
+	case r == '|' || r == '/' || r == '-' || r == '\\':
+		return atLineStart
+	case r == '◐' || r == '◓' || r == '◑' || r == '◒':
+		return true
+	}
+	return false
+}
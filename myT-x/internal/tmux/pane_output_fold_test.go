@@ -0,0 +1,102 @@
+package tmux
+
+import (
+	"strings"
+	"testing"
+)
+
+func foldAll(chunks ...string) (stored, pending string) {
+	var f outputFolder
+	var out strings.Builder
+	for _, chunk := range chunks {
+		f.feed([]byte(chunk), func(data []byte) { out.Write(data) })
+	}
+	return out.String(), string(f.pending())
+}
+
+func TestOutputFolderFeed(t *testing.T) {
+	tests := []struct {
+		name        string
+		chunks      []string
+		wantStored  string
+		wantPending string
+	}{
+		{
+			name:       "distinct lines pass through",
+			chunks:     []string{"a\r\nb\r\nc\r\n"},
+			wantStored: "a\r\nb\r\nc\r\n",
+		},
+		{
+			name:       "short run kept verbatim",
+			chunks:     []string{"poll 1\r\npoll 2\r\npoll 3\r\ndone\r\n"},
+			wantStored: "poll 1\r\npoll 2\r\npoll 3\r\ndone\r\n",
+		},
+		{
+			name:       "long run folded into marker",
+			chunks:     []string{strings.Repeat("waiting...\r\n", 243) + "ready\r\n"},
+			wantStored: "waiting...\r\n[line x243]\r\nready\r\n",
+		},
+		{
+			name:        "near-identical lines across chunks",
+			chunks:      []string{"attempt 1 failed\r\n", "attempt 2 fai", "led\r\nattempt 3 failed\r\n", "attempt 10 failed\r\n"},
+			wantStored:  "attempt 1 failed\r\n",
+			wantPending: "[line x4]\r\n",
+		},
+		{
+			name:        "carriage return spinner folds",
+			chunks:      []string{"⠋ Building 10%\r⠙ Building 20%\r⠹ Building 30%\r⠸ Building 40%\r⠼ Building 50%\r"},
+			wantStored:  "⠋ Building 10%\r",
+			wantPending: "[line x4]\r\n⠼ Building 50%\r",
+		},
+		{
+			name:       "escape sequences ignored",
+			chunks:     []string{"\x1b[32mok\x1b[0m\r\nok\r\n\x1b]0;title\x07ok\r\nok\r\nnext\r\n"},
+			wantStored: "\x1b[32mok\x1b[0m\r\n[line x4]\r\nnext\r\n",
+		},
+		{
+			name:       "blank lines never fold",
+			chunks:     []string{"\r\n\r\n\r\n\r\n\r\n"},
+			wantStored: "\r\n\r\n\r\n\r\n\r\n",
+		},
+		{
+			name:        "incomplete line held",
+			chunks:      []string{"prompt> "},
+			wantPending: "prompt> ",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stored, pending := foldAll(tt.chunks...)
+			if stored != tt.wantStored || pending != tt.wantPending {
+				t.Fatalf("stored = %q, pending = %q; want %q, %q", stored, pending, tt.wantStored, tt.wantPending)
+			}
+		})
+	}
+}
+
+func TestOutputFolderLongLinePassesThrough(t *testing.T) {
+	long := strings.Repeat("x", maxFoldLineBytes)
+	stored, pending := foldAll(long + long + "\n")
+	if stored != long+long+"\n" || pending != "" {
+		t.Fatalf("stored %d bytes, pending %q", len(stored), pending)
+	}
+}
+
+func TestFoldKey(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		{"  Downloading 12.5 MB / 100 MB  \r\n", "Downloading #.# MB / # MB"},
+		{"| Resolving", "Resolving"},
+		{"- item", "item"},
+		{"src/main.go", "src/main.go"},
+		{"\x1b[1;33m⠧\x1b[0m Thinking", "Thinking"},
+		{"\x1b]0;title\x1b\\\r\n", ""},
+	}
+	for _, tt := range tests {
+		if got := foldKey([]byte(tt.line)); got != tt.want {
+			t.Errorf("foldKey(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
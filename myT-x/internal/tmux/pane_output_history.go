@@ -10,12 +10,19 @@ const defaultPaneOutputHistoryCapacity = 256 * 1024 // 256 KiB
 // PaneOutputHistory is a thread-safe ring buffer that accumulates
 // terminal output from a pane's ReadLoop. Used by capture-pane to
 // retrieve recent output.
+//
+// With folding enabled (SetFolding), runs of near-identical lines are stored
+// as one line and a "[line xN]" marker so pathological loops do not push
+// useful output out of the ring. Only the stored history is folded; the live
+// output stream and pane logs still receive every byte.
 type PaneOutputHistory struct {
 	mu       sync.Mutex
 	buf      []byte
 	writePos int
 	size     int
 	capacity int
+	// folder is nil while folding is disabled.
+	folder *outputFolder
 }
 
 // NewPaneOutputHistory creates a ring buffer with the given byte capacity.
@@ -38,7 +45,15 @@ func (h *PaneOutputHistory) Write(data []byte) {
 		slog.Debug("[DEBUG-BUFFER] pane output history write ignored after release", "dataLen", len(data))
 		return
 	}
+	if h.folder != nil {
+		h.folder.feed(data, h.writeLocked)
+		return
+	}
+	h.writeLocked(data)
+}
 
+// writeLocked appends data to the ring. Caller must hold h.mu.
+func (h *PaneOutputHistory) writeLocked(data []byte) {
 	// If data is larger than capacity, only keep the tail
 	if len(data) >= h.capacity {
 		copy(h.buf, data[len(data)-h.capacity:])
@@ -59,19 +74,39 @@ func (h *PaneOutputHistory) Write(data []byte) {
 	}
 }
 
-// Capture returns all buffered output in chronological order.
-// Returns a new slice (safe to retain).
+// SetFolding enables or disables folding of repeated lines. Output held back
+// for comparison is written to the ring when folding is disabled.
+func (h *PaneOutputHistory) SetFolding(enabled bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	switch {
+	case enabled && h.folder == nil:
+		h.folder = &outputFolder{}
+	case !enabled && h.folder != nil:
+		if pending := h.folder.pending(); len(pending) > 0 && len(h.buf) > 0 {
+			h.writeLocked(pending)
+		}
+		h.folder = nil
+	}
+}
+
+// Capture returns all buffered output in chronological order, followed by
+// output the folder still holds back. Returns a new slice (safe to retain).
 func (h *PaneOutputHistory) Capture() []byte {
 	h.mu.Lock()
 	defer h.mu.Unlock()
-	if h.size == 0 || h.capacity <= 0 || len(h.buf) == 0 {
+	var pending []byte
+	if h.folder != nil {
+		pending = h.folder.pending()
+	}
+	if (h.size == 0 && len(pending) == 0) || h.capacity <= 0 || len(h.buf) == 0 {
 		if h.capacity <= 0 || len(h.buf) == 0 {
 			slog.Debug("[DEBUG-BUFFER] pane output history capture ignored after release")
 		}
 		return nil
 	}
 
-	result := make([]byte, h.size)
+	result := make([]byte, h.size, h.size+len(pending))
 	if h.size < h.capacity {
 		// Buffer hasn't wrapped yet: data is [0..writePos)
 		copy(result, h.buf[:h.size])
@@ -81,7 +116,7 @@ func (h *PaneOutputHistory) Capture() []byte {
 		copy(result, h.buf[h.writePos:])
 		copy(result[firstLen:], h.buf[:h.writePos])
 	}
-	return result
+	return append(result, pending...)
 }
 
 // Reset clears all buffered output.
@@ -90,6 +125,9 @@ func (h *PaneOutputHistory) Reset() {
 	defer h.mu.Unlock()
 	h.writePos = 0
 	h.size = 0
+	if h.folder != nil {
+		h.folder = &outputFolder{}
+	}
 }
 
 // Release drops the backing buffer so detached read-loop closures cannot retain
@@ -102,4 +140,5 @@ func (h *PaneOutputHistory) Release() {
 	h.writePos = 0
 	h.size = 0
 	h.capacity = 0
+	h.folder = nil
 }
@@ -385,3 +385,46 @@ func TestPaneOutputHistory_SequentialWrites(t *testing.T) {
 		t.Error("step 5 (wrap) failed")
 	}
 }
+
+func TestPaneOutputHistory_Folding(t *testing.T) {
+	h := NewPaneOutputHistory(1024)
+	h.SetFolding(true)
+
+	h.Write([]byte("start\r\n"))
+	for range 100 {
+		h.Write([]byte("polling status...\r\n"))
+	}
+	if got, want := string(h.Capture()), "start\r\npolling status...\r\n[line x100]\r\n"; got != want {
+		t.Fatalf("Capture() during run = %q, want %q", got, want)
+	}
+
+	h.Write([]byte("done\r\n$ "))
+	if got, want := string(h.Capture()), "start\r\npolling status...\r\n[line x100]\r\ndone\r\n$ "; got != want {
+		t.Fatalf("Capture() after run = %q, want %q", got, want)
+	}
+
+	// Disabling folding writes held output into the ring.
+	h.SetFolding(false)
+	h.Write([]byte("ls\r\n"))
+	if got, want := string(h.Capture()), "start\r\npolling status...\r\n[line x100]\r\ndone\r\n$ ls\r\n"; got != want {
+		t.Fatalf("Capture() after disabling = %q, want %q", got, want)
+	}
+}
+
+func TestPaneOutputHistory_FoldingResetAndRelease(t *testing.T) {
+	h := NewPaneOutputHistory(64)
+	h.SetFolding(true)
+	h.Write([]byte("partial"))
+	h.Reset()
+	if got := h.Capture(); got != nil {
+		t.Fatalf("Capture() after Reset = %q, want nil", got)
+	}
+
+	h.Write([]byte("again"))
+	h.Release()
+	if got := h.Capture(); got != nil {
+		t.Fatalf("Capture() after Release = %q, want nil", got)
+	}
+	h.Write([]byte("ignored\n"))
+	h.SetFolding(false)
+}
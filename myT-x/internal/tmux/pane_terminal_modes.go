@@ -0,0 +1,221 @@
+package tmux
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PaneTerminalModes are the terminal modes a pane application has switched
+// on, tracked from its output. Full-screen programs (vim, fzf, less) rely on
+// them for key encoding and screen handling.
+type PaneTerminalModes struct {
+	// AlternateScreen is set while the application uses the alternate screen
+	// (DECSET 47, 1047, or 1049).
+	AlternateScreen bool `json:"alternateScreen,omitempty"`
+	// AppCursorKeys is DECCKM (DECSET 1): cursor keys send ESC O instead of
+	// ESC [.
+	AppCursorKeys bool `json:"appCursorKeys,omitempty"`
+	// AppKeypad is DECKPAM (ESC =), cleared by DECKPNM (ESC >).
+	AppKeypad bool `json:"appKeypad,omitempty"`
+	// BracketedPaste is DECSET 2004.
+	BracketedPaste bool `json:"bracketedPaste,omitempty"`
+}
+
+// RestoreSequence returns the escape sequences that put a fresh terminal into
+// modes. It is empty for the zero value.
+func (modes PaneTerminalModes) RestoreSequence() string {
+	var b strings.Builder
+	if modes.AlternateScreen {
+		b.WriteString("\x1b[?1049h")
+	}
+	if modes.AppCursorKeys {
+		b.WriteString("\x1b[?1h")
+	}
+	if modes.AppKeypad {
+		b.WriteString("\x1b=")
+	}
+	if modes.BracketedPaste {
+		b.WriteString("\x1b[?2004h")
+	}
+	return b.String()
+}
+
+// maxModeParamsLen bounds the CSI parameter bytes kept by
+// terminalModeScanner. Longer sequences are consumed but not applied.
+const maxModeParamsLen = 64
+
+type modeScanState uint8
+
+const (
+	modeScanGround modeScanState = iota
+	modeScanEscape
+	modeScanCSI
+	// modeScanString skips OSC, DCS, APC, PM, and SOS payloads up to ST.
+	modeScanString
+	modeScanStringEscape
+)
+
+// terminalModeScanner tracks PaneTerminalModes across output chunks of one
+// pane. It is not safe for concurrent use; each pane read loop owns one.
+type terminalModeScanner struct {
+	modes    PaneTerminalModes
+	state    modeScanState
+	params   []byte
+	overflow bool
+}
+
+// Modes returns the current modes.
+func (s *terminalModeScanner) Modes() PaneTerminalModes {
+	return s.modes
+}
+
+// Scan feeds chunk and reports whether the modes changed.
+func (s *terminalModeScanner) Scan(chunk []byte) bool {
+	// Fast path: plain output cannot change modes.
+	if s.state == modeScanGround && bytes.IndexByte(chunk, 0x1b) < 0 {
+		return false
+	}
+	before := s.modes
+	for _, c := range chunk {
+		s.consume(c)
+	}
+	return s.modes != before
+}
+
+func (s *terminalModeScanner) consume(c byte) {
+	switch s.state {
+	case modeScanGround:
+		if c == 0x1b {
+			s.state = modeScanEscape
+		}
+	case modeScanEscape:
+		s.consumeEscape(c)
+	case modeScanCSI:
+		switch {
+		case c >= 0x40 && c <= 0x7e:
+			s.applyCSI(c)
+			s.state = modeScanGround
+		case c >= 0x20 && c <= 0x3f:
+			if len(s.params) < maxModeParamsLen {
+				s.params = append(s.params, c)
+			} else {
+				s.overflow = true
+			}
+		case c == 0x1b:
+			s.state = modeScanEscape
+		case c == 0x18 || c == 0x1a:
+			// CAN and SUB abort the sequence.
+			s.state = modeScanGround
+		}
+		// Other C0 controls execute inside a CSI without ending it.
+	case modeScanString:
+		switch c {
+		case 0x07:
+			s.state = modeScanGround
+		case 0x1b:
+			s.state = modeScanStringEscape
+		}
+	case modeScanStringEscape:
+		if c == '\\' {
+			s.state = modeScanGround
+			return
+		}
+		// ESC followed by anything else ends the string and starts a new
+		// escape sequence.
+		s.consumeEscape(c)
+	}
+}
+
+func (s *terminalModeScanner) consumeEscape(c byte) {
+	switch c {
+	case '[':
+		s.state = modeScanCSI
+		s.params = s.params[:0]
+		s.overflow = false
+	case ']', 'P', '_', '^', 'X':
+		s.state = modeScanString
+	case '=':
+		s.modes.AppKeypad = true
+		s.state = modeScanGround
+	case '>':
+		s.modes.AppKeypad = false
+		s.state = modeScanGround
+	case 'c':
+		// RIS (full reset).
+		s.modes = PaneTerminalModes{}
+		s.state = modeScanGround
+	case 0x1b:
+		s.state = modeScanEscape
+	default:
+		s.state = modeScanGround
+	}
+}
+
+// applyCSI applies a complete CSI sequence with final byte final.
+func (s *terminalModeScanner) applyCSI(final byte) {
+	if s.overflow {
+		return
+	}
+	params := string(s.params)
+	switch final {
+	case 'p':
+		if params == "!" {
+			// DECSTR (soft reset) returns cursor keys and keypad to normal.
+			s.modes.AppCursorKeys = false
+			s.modes.AppKeypad = false
+		}
+	case 'h', 'l':
+		private, ok := strings.CutPrefix(params, "?")
+		if !ok {
+			return
+		}
+		set := final == 'h'
+		for field := range strings.SplitSeq(private, ";") {
+			mode, err := strconv.Atoi(field)
+			if err != nil {
+				continue
+			}
+			switch mode {
+			case 1:
+				s.modes.AppCursorKeys = set
+			case 47, 1047, 1049:
+				s.modes.AlternateScreen = set
+			case 2004:
+				s.modes.BracketedPaste = set
+			}
+		}
+	}
+}
+
+// setPaneTerminalModes records the modes tracked from the output of a pane.
+// Unknown panes are ignored.
+func (m *SessionManager) setPaneTerminalModes(paneID int, modes PaneTerminalModes) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pane := m.panes[paneID]
+	if pane == nil || pane.TerminalModes == modes {
+		return
+	}
+	pane.TerminalModes = modes
+	m.markStateMutationLocked()
+}
+
+// PaneTerminalModes returns the terminal modes of a pane (%N).
+func (m *SessionManager) PaneTerminalModes(paneID string) (PaneTerminalModes, error) {
+	id, err := parsePaneID(strings.TrimSpace(paneID))
+	if err != nil {
+		return PaneTerminalModes{}, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	pane := m.panes[id]
+	if pane == nil {
+		return PaneTerminalModes{}, fmt.Errorf("pane not found: %s", paneID)
+	}
+	return pane.TerminalModes, nil
+}
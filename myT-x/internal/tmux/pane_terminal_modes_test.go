@@ -0,0 +1,117 @@
+package tmux
+
+import "testing"
+
+func TestTerminalModeScanner(t *testing.T) {
+	tests := []struct {
+		name   string
+		chunks []string
+		want   PaneTerminalModes
+	}{
+		{
+			name:   "plain output",
+			chunks: []string{"hello\r\n"},
+		},
+		{
+			name:   "vim startup",
+			chunks: []string{"\x1b[?1049h\x1b[?1h\x1b=\x1b[?2004h\x1b[H\x1b[2J"},
+			want:   PaneTerminalModes{AlternateScreen: true, AppCursorKeys: true, AppKeypad: true, BracketedPaste: true},
+		},
+		{
+			name:   "vim exit",
+			chunks: []string{"\x1b[?1049h\x1b[?1h\x1b=", "\x1b[?1l\x1b>\x1b[?1049l"},
+		},
+		{
+			name:   "several modes in one sequence",
+			chunks: []string{"\x1b[?1;1047;2004h"},
+			want:   PaneTerminalModes{AlternateScreen: true, AppCursorKeys: true, BracketedPaste: true},
+		},
+		{
+			name:   "sequence split across chunks",
+			chunks: []string{"\x1b", "[?20", "04h"},
+			want:   PaneTerminalModes{BracketedPaste: true},
+		},
+		{
+			name:   "non-private and other modes are ignored",
+			chunks: []string{"\x1b[1h\x1b[4h\x1b[?25l\x1b[?1000h"},
+		},
+		{
+			name:   "OSC payloads are skipped",
+			chunks: []string{"\x1b]0;[?1049h\x07\x1b]2;=\x1b\\"},
+		},
+		{
+			name:   "ESC inside a CSI starts a new sequence",
+			chunks: []string{"\x1b[?10\x1b[?2004h"},
+			want:   PaneTerminalModes{BracketedPaste: true},
+		},
+		{
+			name:   "soft reset clears key modes",
+			chunks: []string{"\x1b[?1h\x1b=\x1b[?2004h\x1b[!p"},
+			want:   PaneTerminalModes{BracketedPaste: true},
+		},
+		{
+			name:   "full reset clears all modes",
+			chunks: []string{"\x1b[?1049h\x1b[?2004h\x1bc"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var scanner terminalModeScanner
+			for _, chunk := range tt.chunks {
+				scanner.Scan([]byte(chunk))
+			}
+			if got := scanner.Modes(); got != tt.want {
+				t.Fatalf("modes = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTerminalModeScannerReportsChanges(t *testing.T) {
+	var scanner terminalModeScanner
+	if !scanner.Scan([]byte("\x1b[?2004h")) {
+		t.Fatal("Scan(enable bracketed paste) = false, want true")
+	}
+	if scanner.Scan([]byte("\x1b[?2004h$ ")) {
+		t.Fatal("Scan(repeated enable) = true, want false")
+	}
+	if scanner.Scan([]byte("output without escapes")) {
+		t.Fatal("Scan(plain output) = true, want false")
+	}
+}
+
+func TestPaneTerminalModesRestoreSequence(t *testing.T) {
+	if got := (PaneTerminalModes{}).RestoreSequence(); got != "" {
+		t.Fatalf("zero RestoreSequence() = %q, want empty", got)
+	}
+	modes := PaneTerminalModes{AlternateScreen: true, AppCursorKeys: true, AppKeypad: true, BracketedPaste: true}
+	var scanner terminalModeScanner
+	scanner.Scan([]byte(modes.RestoreSequence()))
+	if got := scanner.Modes(); got != modes {
+		t.Fatalf("modes after RestoreSequence() = %+v, want %+v", got, modes)
+	}
+}
+
+func TestSetPaneTerminalModes(t *testing.T) {
+	manager := NewSessionManager()
+	t.Cleanup(manager.Close)
+	_, pane, err := manager.CreateSession("demo", "main", 120, 40)
+	if err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+
+	modes := PaneTerminalModes{AlternateScreen: true, BracketedPaste: true}
+	manager.setPaneTerminalModes(pane.ID, modes)
+	if got, err := manager.PaneTerminalModes(pane.IDString()); err != nil || got != modes {
+		t.Fatalf("PaneTerminalModes() = %+v, %v, want %+v", got, err, modes)
+	}
+	if got := manager.Snapshot()[0].Windows[0].Panes[0].TerminalModes; got != modes {
+		t.Fatalf("snapshot terminal modes = %+v, want %+v", got, modes)
+	}
+
+	manager.setPaneTerminalModes(99, modes)
+	if _, err := manager.PaneTerminalModes("%99"); err == nil {
+		t.Fatal("PaneTerminalModes(%99) succeeded, want pane not found")
+	}
+}
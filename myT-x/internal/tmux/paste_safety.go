@@ -0,0 +1,81 @@
+package tmux
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	bracketedPasteStart = "\x1b[200~"
+	bracketedPasteEnd   = "\x1b[201~"
+)
+
+// PreparedPaste is clipboard text made safe to write to a pane.
+type PreparedPaste struct {
+	// Data is the payload to write to the pane.
+	Data string
+	// Lines is the number of lines in the paste. A trailing line break does
+	// not start a new line, but it does submit the last one.
+	Lines int
+	// LineBreaks counts the line breaks; each one submits a line to a shell.
+	LineBreaks int
+	// Bracketed is set when Data is wrapped in bracketed paste markers.
+	Bracketed bool
+	// StrippedControls is set when control characters were removed.
+	StrippedControls bool
+}
+
+// PreparePaste normalizes text for pasting into a pane with modes.
+//
+// Line breaks become CR, as a terminal sends them. Other C0 and C1 control
+// characters, DEL, and invalid UTF-8 are removed: ESC in particular would let
+// pasted text inject key sequences or end a bracketed paste early. When the
+// pane application enabled bracketed paste, the result is wrapped in the
+// paste markers so that the application can tell it from typed input.
+func PreparePaste(text string, modes PaneTerminalModes) PreparedPaste {
+	text = strings.ReplaceAll(text, "\r\n", "\r")
+
+	var b strings.Builder
+	b.Grow(len(text) + len(bracketedPasteStart) + len(bracketedPasteEnd))
+	paste := PreparedPaste{Bracketed: modes.BracketedPaste}
+	if paste.Bracketed {
+		b.WriteString(bracketedPasteStart)
+	}
+	// openLine is set while the last line has content but no line break yet.
+	openLine := false
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		i += size
+		switch {
+		case r == '\r' || r == '\n':
+			b.WriteByte('\r')
+			paste.LineBreaks++
+			paste.Lines++
+			openLine = false
+		case r == '\t':
+			b.WriteByte('\t')
+			openLine = true
+		case r == utf8.RuneError && size == 1, r < 0x20, r >= 0x7f && r <= 0x9f:
+			paste.StrippedControls = true
+		default:
+			b.WriteRune(r)
+			openLine = true
+		}
+	}
+	if openLine {
+		paste.Lines++
+	}
+	if paste.Bracketed {
+		b.WriteString(bracketedPasteEnd)
+	}
+	paste.Data = b.String()
+	return paste
+}
+
+// SubmitsLines reports whether writing the paste to a pane with modes would
+// submit lines as commands: it contains a line break, is not bracketed, and
+// the pane is not running a full-screen application (which uses the
+// alternate screen and takes line breaks as editing input).
+func (p PreparedPaste) SubmitsLines(modes PaneTerminalModes) bool {
+	return p.LineBreaks > 0 && !p.Bracketed && !modes.AlternateScreen
+}
@@ -0,0 +1,61 @@
+package tmux
+
+import "testing"
+
+func TestPreparePaste(t *testing.T) {
+	tests := []struct {
+		name         string
+		text         string
+		modes        PaneTerminalModes
+		wantData     string
+		wantLines    int
+		wantBreaks   int
+		wantStripped bool
+		wantSubmits  bool
+	}{
+		{name: "single line", text: "echo hi", wantData: "echo hi", wantLines: 1},
+		{
+			name: "line breaks become CR", text: "a\r\nb\nc\r",
+			wantData: "a\rb\rc\r", wantLines: 3, wantBreaks: 3, wantSubmits: true,
+		},
+		{
+			name: "newline-terminated command submits", text: "rm -rf build\n",
+			wantData: "rm -rf build\r", wantLines: 1, wantBreaks: 1, wantSubmits: true,
+		},
+		{
+			name: "control sequences are stripped", text: "ls\x1b[201~\x07\x7f\u009b2J\tx",
+			wantData: "ls[201~2J\tx", wantLines: 1, wantStripped: true,
+		},
+		{name: "invalid UTF-8 is stripped", text: "a\xffb", wantData: "ab", wantLines: 1, wantStripped: true},
+		{name: "only controls", text: "\x1b\x00", wantData: "", wantStripped: true},
+		{
+			name: "bracketed paste is wrapped", text: "a\nb", modes: PaneTerminalModes{BracketedPaste: true},
+			wantData: "\x1b[200~a\rb\x1b[201~", wantLines: 2, wantBreaks: 1,
+		},
+		{
+			name: "alternate screen does not submit", text: "a\nb", modes: PaneTerminalModes{AlternateScreen: true},
+			wantData: "a\rb", wantLines: 2, wantBreaks: 1,
+		},
+		{name: "non-ASCII text is kept", text: "こんにちは", wantData: "こんにちは", wantLines: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := PreparePaste(tt.text, tt.modes)
+			if got.Data != tt.wantData {
+				t.Errorf("Data = %q, want %q", got.Data, tt.wantData)
+			}
+			if got.Lines != tt.wantLines || got.LineBreaks != tt.wantBreaks {
+				t.Errorf("Lines, LineBreaks = %d, %d, want %d, %d", got.Lines, got.LineBreaks, tt.wantLines, tt.wantBreaks)
+			}
+			if got.StrippedControls != tt.wantStripped {
+				t.Errorf("StrippedControls = %v, want %v", got.StrippedControls, tt.wantStripped)
+			}
+			if got.Bracketed != tt.modes.BracketedPaste {
+				t.Errorf("Bracketed = %v, want %v", got.Bracketed, tt.modes.BracketedPaste)
+			}
+			if submits := got.SubmitsLines(tt.modes); submits != tt.wantSubmits {
+				t.Errorf("SubmitsLines() = %v, want %v", submits, tt.wantSubmits)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package tmux
+
+import (
+	"strings"
+	"time"
+)
+
+// MarkSessionSeen records that the user has seen a session's output up to now.
+// Unknown or empty names are ignored.
+func (m *SessionManager) MarkSessionSeen(name string) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if session := m.sessions[name]; session != nil {
+		session.LastSeen = m.now()
+	}
+}
+
+// RecordBellByPaneID records a terminal bell for the session owning paneID.
+// It returns true when the session had no unseen bell before.
+func (m *SessionManager) RecordBellByPaneID(paneID string) bool {
+	id, err := parsePaneID(strings.TrimSpace(paneID))
+	if err != nil {
+		return false
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pane := m.panes[id]
+	if pane == nil || pane.Window == nil || pane.Window.Session == nil {
+		return false
+	}
+
+	session := pane.Window.Session
+	hadBell := session.LastBell.After(session.LastSeen)
+	session.LastBell = m.now()
+	return !hadBell
+}
+
+// NextAttentionSession returns the session other than current that most needs
+// the user's attention.
+//
+// Sessions with an unseen bell, or that went idle after producing unseen
+// output (typically an agent waiting for input), rank first. Sessions still
+// producing unseen output rank next and are skipped when needsInputOnly is
+// true. Ties are broken by the most recent event. Detached sessions are never
+// returned because they are not shown in the UI.
+func (m *SessionManager) NextAttentionSession(current string, needsInputOnly bool) (string, bool) {
+	current = strings.TrimSpace(current)
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	bestName := ""
+	bestRank := 0
+	var bestAt time.Time
+	for name, session := range m.sessions {
+		if session == nil || session.Detached || name == current {
+			continue
+		}
+		rank, at := sessionAttention(session)
+		if rank == 0 || (needsInputOnly && rank < attentionNeedsInput) {
+			continue
+		}
+		if rank < bestRank {
+			continue
+		}
+		// Name order keeps the choice deterministic for identical timestamps.
+		if rank == bestRank && (at.Before(bestAt) || (at.Equal(bestAt) && name > bestName)) {
+			continue
+		}
+		bestName, bestRank, bestAt = name, rank, at
+	}
+	return bestName, bestName != ""
+}
+
+// SessionNeedsInput reports whether a session has an unseen bell or went idle
+// after producing unseen output.
+func (m *SessionManager) SessionNeedsInput(name string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	session := m.sessions[strings.TrimSpace(name)]
+	if session == nil {
+		return false
+	}
+	rank, _ := sessionAttention(session)
+	return rank >= attentionNeedsInput
+}
+
+// SessionActivity is the activity state of one session.
+type SessionActivity struct {
+	// LastActivity is when a pane of the session last produced output.
+	LastActivity time.Time
+	// NeedsInput mirrors SessionNeedsInput.
+	NeedsInput bool
+}
+
+// SessionActivities returns the activity state of every session by name.
+func (m *SessionManager) SessionActivities() map[string]SessionActivity {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	out := make(map[string]SessionActivity, len(m.sessions))
+	for name, session := range m.sessions {
+		if session == nil {
+			continue
+		}
+		rank, _ := sessionAttention(session)
+		out[name] = SessionActivity{LastActivity: session.LastActivity, NeedsInput: rank >= attentionNeedsInput}
+	}
+	return out
+}
+
+const (
+	attentionActivity   = 1
+	attentionNeedsInput = 2
+)
+
+// sessionAttention ranks a session's unseen state and returns the time of the
+// event that produced the rank. Caller must hold m.mu.
+func sessionAttention(session *TmuxSession) (int, time.Time) {
+	unseenActivity := session.LastActivity.After(session.LastSeen)
+	if session.LastBell.After(session.LastSeen) {
+		at := session.LastBell
+		if unseenActivity && session.LastActivity.After(at) {
+			at = session.LastActivity
+		}
+		return attentionNeedsInput, at
+	}
+	if !unseenActivity {
+		return 0, time.Time{}
+	}
+	if session.IsIdle {
+		return attentionNeedsInput, session.LastActivity
+	}
+	return attentionActivity, session.LastActivity
+}
@@ -0,0 +1,121 @@
+package tmux
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextAttentionSessionRanking(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	manager := NewSessionManager()
+	manager.now = func() time.Time { return now }
+	manager.idleThreshold = 5 * time.Second
+
+	panes := map[string]string{}
+	for _, name := range []string{"current", "busy", "waiting", "belled"} {
+		_, pane, err := manager.CreateSession(name, "main", 120, 40)
+		if err != nil {
+			t.Fatalf("CreateSession(%s) error = %v", name, err)
+		}
+		panes[name] = pane.IDString()
+	}
+
+	if got, ok := manager.NextAttentionSession("current", false); ok {
+		t.Fatalf("NextAttentionSession() = %q, want none before any output", got)
+	}
+
+	// "waiting" produces output, then goes idle.
+	now = now.Add(time.Second)
+	manager.UpdateActivityByPaneID(panes["waiting"])
+	now = now.Add(6 * time.Second)
+	manager.CheckIdleState()
+	// "busy" keeps producing output after that.
+	manager.UpdateActivityByPaneID(panes["busy"])
+
+	if got, _ := manager.NextAttentionSession("current", false); got != "waiting" {
+		t.Fatalf("NextAttentionSession() = %q, want waiting (idle after output outranks busy)", got)
+	}
+	if !manager.SessionNeedsInput("waiting") || manager.SessionNeedsInput("busy") {
+		t.Fatal("SessionNeedsInput() mismatch for waiting/busy")
+	}
+
+	now = now.Add(time.Second)
+	if !manager.RecordBellByPaneID(panes["belled"]) {
+		t.Fatal("RecordBellByPaneID() = false, want true for first bell")
+	}
+	if manager.RecordBellByPaneID(panes["belled"]) {
+		t.Fatal("RecordBellByPaneID() = true, want false while bell is still unseen")
+	}
+	if got, _ := manager.NextAttentionSession("current", false); got != "belled" {
+		t.Fatalf("NextAttentionSession() = %q, want belled (most recent)", got)
+	}
+
+	manager.MarkSessionSeen("belled")
+	manager.MarkSessionSeen("waiting")
+	if got, _ := manager.NextAttentionSession("current", false); got != "busy" {
+		t.Fatalf("NextAttentionSession() = %q, want busy after others were seen", got)
+	}
+	if got, ok := manager.NextAttentionSession("current", true); ok {
+		t.Fatalf("NextAttentionSession(needsInputOnly) = %q, want none", got)
+	}
+	if got, ok := manager.NextAttentionSession("busy", false); ok {
+		t.Fatalf("NextAttentionSession(busy) = %q, want current session excluded", got)
+	}
+}
+
+func TestNextAttentionSessionSkipsDetached(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	manager := NewSessionManager()
+	manager.now = func() time.Time { return now }
+
+	_, pane, err := manager.CreateSession("hidden", "main", 120, 40)
+	if err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	if err := manager.SetDetached("hidden", true); err != nil {
+		t.Fatalf("SetDetached() error = %v", err)
+	}
+	now = now.Add(time.Second)
+	manager.RecordBellByPaneID(pane.IDString())
+
+	if got, ok := manager.NextAttentionSession("", false); ok {
+		t.Fatalf("NextAttentionSession() = %q, want detached session skipped", got)
+	}
+}
+
+func TestSessionActivities(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	manager := NewSessionManager()
+	manager.now = func() time.Time { return now }
+	manager.idleThreshold = 5 * time.Second
+
+	panes := map[string]string{}
+	for _, name := range []string{"quiet", "waiting"} {
+		_, pane, err := manager.CreateSession(name, "main", 120, 40)
+		if err != nil {
+			t.Fatalf("CreateSession(%s) error = %v", name, err)
+		}
+		panes[name] = pane.IDString()
+	}
+	created := now
+
+	now = now.Add(time.Second)
+	manager.UpdateActivityByPaneID(panes["waiting"])
+	outputAt := now
+	now = now.Add(6 * time.Second)
+	manager.CheckIdleState()
+
+	got := manager.SessionActivities()
+	if len(got) != 2 {
+		t.Fatalf("SessionActivities() = %+v, want 2 sessions", got)
+	}
+	if got["waiting"] != (SessionActivity{LastActivity: outputAt, NeedsInput: true}) {
+		t.Fatalf("waiting = %+v", got["waiting"])
+	}
+	if got["quiet"] != (SessionActivity{LastActivity: created}) {
+		t.Fatalf("quiet = %+v", got["quiet"])
+	}
+}
@@ -0,0 +1,151 @@
+package tmux
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// collapsedActivityGrace ignores pane output right after a collapse, so the
+// trailing redraw of a command that just went quiet does not expand it again.
+const collapsedActivityGrace = 2 * time.Second
+
+// SetPaneCollapsed collapses or expands one pane (%N) and returns its session
+// name. Collapsing the active pane activates the next expanded pane; the last
+// expanded pane of a window cannot be collapsed.
+func (m *SessionManager) SetPaneCollapsed(paneID string, collapsed bool) (string, error) {
+	id, err := parsePaneID(strings.TrimSpace(paneID))
+	if err != nil {
+		return "", err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pane := m.panes[id]
+	if pane == nil || pane.Window == nil || pane.Window.Session == nil {
+		return "", fmt.Errorf("pane not found: %s", paneID)
+	}
+	sessionName := pane.Window.Session.Name
+	if pane.Collapsed == collapsed {
+		return sessionName, nil
+	}
+	if !collapsed {
+		pane.Collapsed = false
+		m.markStateMutationLocked()
+		return sessionName, nil
+	}
+
+	if err := m.collapsePaneLocked(pane); err != nil {
+		return "", err
+	}
+	return sessionName, nil
+}
+
+// CollapseIdlePanes collapses the panes of the session's active window that
+// produced no output for the idle threshold that also marks sessions idle.
+// The active pane stays expanded. Returns the collapsed pane IDs in window order.
+func (m *SessionManager) CollapseIdlePanes(sessionName string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	session, err := m.resolveSessionTargetLocked(sessionName)
+	if err != nil {
+		return nil, err
+	}
+	window := m.activeWindowInSessionLocked(session)
+	if window == nil {
+		return nil, errors.New("session has no active window")
+	}
+
+	now := m.now()
+	collapsedIDs := make([]string, 0)
+	for _, pane := range window.Panes {
+		if pane == nil || pane.Active || pane.Collapsed || now.Sub(pane.lastOutputAt) < m.idleThreshold {
+			continue
+		}
+		if err := m.collapsePaneLocked(pane); err != nil {
+			// Only the last expanded pane is refused, so nothing else to do.
+			break
+		}
+		collapsedIDs = append(collapsedIDs, pane.IDString())
+	}
+	return collapsedIDs, nil
+}
+
+// collapsePaneLocked collapses pane, moving activation to another expanded
+// pane of the window when pane is active. Caller must hold m.mu.
+func (m *SessionManager) collapsePaneLocked(pane *TmuxPane) error {
+	window := pane.Window
+	var next *TmuxPane
+	for _, candidate := range window.Panes {
+		if candidate != nil && candidate != pane && !candidate.Collapsed {
+			next = candidate
+			break
+		}
+	}
+	if next == nil {
+		return errors.New("cannot collapse the last expanded pane of a window")
+	}
+
+	pane.Collapsed = true
+	pane.collapsedAt = m.now()
+	if pane.Active {
+		pane.Active = false
+		next.Active = true
+		window.ActivePN = next.Index
+		m.markTopologyMutationLocked()
+		return nil
+	}
+	m.markStateMutationLocked()
+	return nil
+}
+
+// visibleLayout returns a copy of the window layout without collapsed panes.
+// A split that loses one side is replaced by the other side, so the remaining
+// panes reflow into the freed space.
+func visibleLayout(window *TmuxWindow) *LayoutNode {
+	collapsed := make(map[int]bool)
+	for _, pane := range window.Panes {
+		if pane != nil && pane.Collapsed {
+			collapsed[pane.ID] = true
+		}
+	}
+	if len(collapsed) == 0 {
+		return cloneLayout(window.Layout)
+	}
+	if pruned := pruneLayout(window.Layout, collapsed); pruned != nil {
+		return pruned
+	}
+	// Every pane is collapsed, which the collapse operations prevent.
+	// Show the full layout rather than an empty window.
+	return cloneLayout(window.Layout)
+}
+
+func pruneLayout(node *LayoutNode, collapsed map[int]bool) *LayoutNode {
+	if node == nil {
+		return nil
+	}
+	if node.Type == LayoutLeaf {
+		if collapsed[node.PaneID] {
+			return nil
+		}
+		return newLeafLayout(node.PaneID)
+	}
+	first := pruneLayout(node.Children[0], collapsed)
+	second := pruneLayout(node.Children[1], collapsed)
+	switch {
+	case first == nil:
+		return second
+	case second == nil:
+		return first
+	}
+	return &LayoutNode{
+		Type:      node.Type,
+		Direction: node.Direction,
+		Ratio:     node.Ratio,
+		PaneID:    node.PaneID,
+		Children:  [2]*LayoutNode{first, second},
+	}
+}
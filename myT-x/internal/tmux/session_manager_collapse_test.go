@@ -0,0 +1,147 @@
+package tmux
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+// newCollapseTestManager returns a manager with one window of three panes
+// split left to right; the last pane is active.
+func newCollapseTestManager(t *testing.T) (*SessionManager, []*TmuxPane, *time.Time) {
+	t.Helper()
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	manager := NewSessionManager()
+	manager.now = func() time.Time { return now }
+	_, pane0, err := manager.CreateSession("demo", "main", 120, 40)
+	if err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	pane1, err := manager.SplitPane(pane0.ID, SplitHorizontal)
+	if err != nil {
+		t.Fatalf("SplitPane() error = %v", err)
+	}
+	pane2, err := manager.SplitPane(pane1.ID, SplitHorizontal)
+	if err != nil {
+		t.Fatalf("SplitPane() error = %v", err)
+	}
+	return manager, []*TmuxPane{pane0, pane1, pane2}, &now
+}
+
+func layoutPaneIDs(node *LayoutNode) []int {
+	if node == nil {
+		return nil
+	}
+	if node.Type == LayoutLeaf {
+		return []int{node.PaneID}
+	}
+	return append(layoutPaneIDs(node.Children[0]), layoutPaneIDs(node.Children[1])...)
+}
+
+func TestSetPaneCollapsedReflowsSnapshotLayout(t *testing.T) {
+	manager, panes, _ := newCollapseTestManager(t)
+
+	if _, err := manager.SetPaneCollapsed(panes[1].IDString(), true); err != nil {
+		t.Fatalf("SetPaneCollapsed() error = %v", err)
+	}
+	window := manager.Snapshot()[0].Windows[0]
+	if got, want := layoutPaneIDs(window.Layout), []int{panes[0].ID, panes[2].ID}; !slices.Equal(got, want) {
+		t.Fatalf("snapshot layout panes = %v, want %v", got, want)
+	}
+	if !window.Panes[1].Collapsed || window.Panes[0].Collapsed {
+		t.Fatalf("snapshot collapsed flags = %+v", window.Panes)
+	}
+	if len(window.Panes) != 3 {
+		t.Fatalf("collapsed pane must stay in the pane list, got %d panes", len(window.Panes))
+	}
+
+	if _, err := manager.SetPaneCollapsed(panes[1].IDString(), false); err != nil {
+		t.Fatalf("SetPaneCollapsed(expand) error = %v", err)
+	}
+	window = manager.Snapshot()[0].Windows[0]
+	if got, want := layoutPaneIDs(window.Layout), []int{panes[0].ID, panes[1].ID, panes[2].ID}; !slices.Equal(got, want) {
+		t.Fatalf("expanded layout panes = %v, want original position %v", got, want)
+	}
+}
+
+func TestSetPaneCollapsedMovesActivationAndKeepsOnePaneVisible(t *testing.T) {
+	manager, panes, _ := newCollapseTestManager(t)
+
+	if _, err := manager.SetPaneCollapsed(panes[2].IDString(), true); err != nil {
+		t.Fatalf("SetPaneCollapsed(active) error = %v", err)
+	}
+	if panes[2].Active || !panes[0].Active || panes[0].Window.ActivePN != panes[0].Index {
+		t.Fatalf("activation did not move to the first expanded pane")
+	}
+	if _, err := manager.SetPaneCollapsed(panes[1].IDString(), true); err != nil {
+		t.Fatalf("SetPaneCollapsed() error = %v", err)
+	}
+	if _, err := manager.SetPaneCollapsed(panes[0].IDString(), true); err == nil {
+		t.Fatal("collapsing the last expanded pane succeeded")
+	}
+
+	if err := manager.SetActivePane(panes[1].ID); err != nil {
+		t.Fatalf("SetActivePane() error = %v", err)
+	}
+	if panes[1].Collapsed {
+		t.Fatal("focusing a collapsed pane did not expand it")
+	}
+}
+
+func TestUpdateActivityExpandsCollapsedPaneAfterGrace(t *testing.T) {
+	manager, panes, now := newCollapseTestManager(t)
+	if _, err := manager.SetPaneCollapsed(panes[0].IDString(), true); err != nil {
+		t.Fatalf("SetPaneCollapsed() error = %v", err)
+	}
+
+	*now = now.Add(collapsedActivityGrace / 2)
+	if manager.UpdateActivityByPaneID(panes[0].IDString()) || !panes[0].Collapsed {
+		t.Fatal("output within the grace period expanded the pane")
+	}
+
+	*now = now.Add(collapsedActivityGrace)
+	if !manager.UpdateActivityByPaneID(panes[0].IDString()) {
+		t.Fatal("UpdateActivityByPaneID() = false, want snapshot change")
+	}
+	if panes[0].Collapsed {
+		t.Fatal("output after the grace period did not expand the pane")
+	}
+}
+
+func TestCollapseIdlePanesSkipsActiveAndRecentPanes(t *testing.T) {
+	manager, panes, now := newCollapseTestManager(t)
+	*now = now.Add(time.Hour)
+	manager.UpdateActivityByPaneID(panes[1].IDString())
+
+	collapsed, err := manager.CollapseIdlePanes("demo")
+	if err != nil {
+		t.Fatalf("CollapseIdlePanes() error = %v", err)
+	}
+	if want := []string{panes[0].IDString()}; !slices.Equal(collapsed, want) {
+		t.Fatalf("CollapseIdlePanes() = %v, want %v", collapsed, want)
+	}
+	if panes[2].Collapsed {
+		t.Fatal("active pane was collapsed")
+	}
+}
+
+func TestKillPaneExpandsCollapsedFallbackPane(t *testing.T) {
+	manager, panes, _ := newCollapseTestManager(t)
+	if _, err := manager.SetPaneCollapsed(panes[0].IDString(), true); err != nil {
+		t.Fatalf("SetPaneCollapsed() error = %v", err)
+	}
+	if _, err := manager.SetPaneCollapsed(panes[1].IDString(), true); err != nil {
+		t.Fatalf("SetPaneCollapsed() error = %v", err)
+	}
+	if _, _, err := manager.KillPane(panes[2].IDString()); err != nil {
+		t.Fatalf("KillPane() error = %v", err)
+	}
+	window := manager.Snapshot()[0].Windows[0]
+	if len(layoutPaneIDs(window.Layout)) == 0 {
+		t.Fatal("snapshot layout is empty after killing the only expanded pane")
+	}
+	active := window.Panes[window.ActivePN]
+	if !active.Active || active.Collapsed {
+		t.Fatalf("fallback active pane = %+v, want expanded", active)
+	}
+}
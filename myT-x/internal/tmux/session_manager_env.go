@@ -1,7 +1,9 @@
 package tmux
 
 import (
+	"cmp"
 	"fmt"
+	"slices"
 	"strings"
 
 	"myT-x/internal/terminal"
@@ -204,6 +206,133 @@ func (m *SessionManager) SetAgentTeam(name string, isAgent bool) error {
 	return nil
 }
 
+// SetDetached sets whether the named session is detached (hidden from the UI
+// until attached).
+func (m *SessionManager) SetDetached(name string, detached bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	session, err := m.getSessionByNameLocked(name)
+	if err != nil {
+		return err
+	}
+	if session.Detached != detached {
+		m.markStateMutationLocked()
+	}
+	session.Detached = detached
+	return nil
+}
+
+// SetSessionBadge sets the user-defined badge of the named session.
+// A nil or empty badge clears it.
+func (m *SessionManager) SetSessionBadge(name string, badge *SessionBadge) error {
+	return m.setBadge(name, badge, func(session *TmuxSession) **SessionBadge { return &session.Badge })
+}
+
+// SetAutoBadge sets the rule-based badge of the named session.
+// A nil or empty badge clears it.
+func (m *SessionManager) SetAutoBadge(name string, badge *SessionBadge) error {
+	return m.setBadge(name, badge, func(session *TmuxSession) **SessionBadge { return &session.AutoBadge })
+}
+
+func (m *SessionManager) setBadge(name string, badge *SessionBadge, field func(*TmuxSession) **SessionBadge) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	session, err := m.getSessionByNameLocked(name)
+	if err != nil {
+		return err
+	}
+	var next *SessionBadge
+	if !badge.IsEmpty() {
+		next = &SessionBadge{Color: badge.Color, Emoji: badge.Emoji}
+	}
+	target := field(session)
+	if sessionBadgeEqual(*target, next) {
+		return nil
+	}
+	*target = next
+	m.markStateMutationLocked()
+	return nil
+}
+
+// SetSessionTaskbarAlertsMuted sets whether the named session is excluded
+// from taskbar alerts.
+func (m *SessionManager) SetSessionTaskbarAlertsMuted(name string, muted bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	session, err := m.getSessionByNameLocked(name)
+	if err != nil {
+		return err
+	}
+	if session.TaskbarAlertsMuted == muted {
+		return nil
+	}
+	session.TaskbarAlertsMuted = muted
+	m.markStateMutationLocked()
+	return nil
+}
+
+// SetSessionGroup moves the named session into group. An empty group
+// removes the session from its group.
+func (m *SessionManager) SetSessionGroup(name, group string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	session, err := m.getSessionByNameLocked(name)
+	if err != nil {
+		return err
+	}
+	if session.Group == group {
+		return nil
+	}
+	session.Group = group
+	m.markStateMutationLocked()
+	return nil
+}
+
+// SessionsInGroup returns the names of the sessions in group, sorted by
+// session ID so that members are listed in creation order.
+func (m *SessionManager) SessionsInGroup(group string) []string {
+	if group == "" {
+		return nil
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	members := make([]*TmuxSession, 0)
+	for _, session := range m.sessions {
+		if session != nil && session.Group == group {
+			members = append(members, session)
+		}
+	}
+	slices.SortFunc(members, func(a, b *TmuxSession) int { return cmp.Compare(a.ID, b.ID) })
+	names := make([]string, len(members))
+	for i, session := range members {
+		names[i] = session.Name
+	}
+	return names
+}
+
+// AttachSession clears the detached flag of the named session and reports
+// whether the session was detached before the call.
+func (m *SessionManager) AttachSession(name string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	session, err := m.getSessionByNameLocked(name)
+	if err != nil {
+		return false, err
+	}
+	if !session.Detached {
+		return false, nil
+	}
+	session.Detached = false
+	m.markStateMutationLocked()
+	return true, nil
+}
+
 // SetUseClaudeEnv sets whether claude_env is applied to panes in the named session.
 func (m *SessionManager) SetUseClaudeEnv(name string, enabled bool) error {
 	m.mu.Lock()
@@ -250,6 +379,23 @@ func (m *SessionManager) SetUseSessionPaneScope(name string, enabled bool) error
 	return nil
 }
 
+// SetShellHistoryIsolated sets whether the panes of the named session use a
+// per-session shell history file.
+func (m *SessionManager) SetShellHistoryIsolated(name string, isolated bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	session, err := m.getSessionByNameLocked(name)
+	if err != nil {
+		return err
+	}
+	if session.ShellHistoryIsolated == isolated {
+		return nil
+	}
+	session.ShellHistoryIsolated = isolated
+	m.markStateMutationLocked()
+	return nil
+}
+
 // GetPaneEnv returns a copy of environment variables for the pane identified
 // by paneID (format "%N"). The caller may safely mutate the returned map
 // without affecting internal state.
@@ -290,6 +436,17 @@ func (m *SessionManager) SetPaneRuntime(paneID int, term *terminal.Terminal, env
 	return nil
 }
 
+// setPaneStartDir records the directory a pane process was started in.
+// Unknown panes are ignored.
+func (m *SessionManager) setPaneStartDir(paneID int, dir string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if pane := m.panes[paneID]; pane != nil {
+		pane.startDir = strings.TrimSpace(dir)
+	}
+}
+
 // GetPaneContextSnapshot returns lock-safe pane/session/window context for paneID.
 func (m *SessionManager) GetPaneContextSnapshot(paneID int) (PaneContextSnapshot, error) {
 	m.mu.RLock()
@@ -314,6 +471,8 @@ func (m *SessionManager) GetPaneContextSnapshot(paneID int) (PaneContextSnapshot
 		SessionWorkDir: workDir,
 		PaneWidth:      pane.Width,
 		PaneHeight:     pane.Height,
+
+		ShellHistoryIsolated: sess.ShellHistoryIsolated,
 	}, nil
 }
 
@@ -341,6 +500,42 @@ func (m *SessionManager) HasPane(paneID string) bool {
 	return ok
 }
 
+// PaneLocation returns the session name and window ID ("@N") that own
+// paneID (format "%N").
+func (m *SessionManager) PaneLocation(paneID string) (sessionName string, windowID string, ok bool) {
+	id, err := parsePaneID(strings.TrimSpace(paneID))
+	if err != nil {
+		return "", "", false
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	pane := m.panes[id]
+	if pane == nil || pane.Window == nil || pane.Window.Session == nil {
+		return "", "", false
+	}
+	return pane.Window.Session.Name, formatWindowID(pane.Window.ID), true
+}
+
+// PaneCurrentPath returns the working directory of paneID (format "%N") as
+// #{pane_current_path} reports it: the directory the pane started in,
+// falling back to the session's.
+func (m *SessionManager) PaneCurrentPath(paneID string) (string, error) {
+	id, err := parsePaneID(strings.TrimSpace(paneID))
+	if err != nil {
+		return "", err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	pane := m.panes[id]
+	if pane == nil || pane.Window == nil || pane.Window.Session == nil {
+		return "", fmt.Errorf("pane not found: %s", paneID)
+	}
+	if pane.startDir != "" {
+		return pane.startDir, nil
+	}
+	return sessionWorkDir(pane.Window.Session), nil
+}
+
 // GetSessionPanePIDs はセッション内の全ペインのPID情報を返す。
 // ロック順序: SessionManager.mu (RLock) → Terminal.mu (RLock via PID())
 func (m *SessionManager) GetSessionPanePIDs(sessionName string) ([]PanePIDInfo, error) {
@@ -2,6 +2,7 @@ package tmux
 
 import (
 	"encoding/json"
+	"slices"
 	"strings"
 	"testing"
 )
@@ -512,6 +513,85 @@ func TestSetAgentTeam(t *testing.T) {
 	}
 }
 
+func TestSetDetachedAndAttachSession(t *testing.T) {
+	manager := NewSessionManager()
+	t.Cleanup(manager.Close)
+	if _, _, err := manager.CreateSession("demo", "0", 120, 40); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+
+	if err := manager.SetDetached("demo", true); err != nil {
+		t.Fatalf("SetDetached() error = %v", err)
+	}
+	if snapshots := manager.Snapshot(); !snapshots[0].Detached {
+		t.Fatal("Snapshot()[0].Detached = false, want true")
+	}
+
+	wasDetached, err := manager.AttachSession("demo")
+	if err != nil {
+		t.Fatalf("AttachSession() error = %v", err)
+	}
+	if !wasDetached {
+		t.Fatal("AttachSession() wasDetached = false, want true")
+	}
+	if snapshots := manager.Snapshot(); snapshots[0].Detached {
+		t.Fatal("Snapshot()[0].Detached = true after attach, want false")
+	}
+
+	if wasDetached, err = manager.AttachSession("demo"); err != nil || wasDetached {
+		t.Fatalf("AttachSession() second call = (%v, %v), want (false, nil)", wasDetached, err)
+	}
+	if _, err := manager.AttachSession("missing"); err == nil {
+		t.Fatal("AttachSession(missing) error = nil, want session not found")
+	}
+	if err := manager.SetDetached("missing", true); err == nil {
+		t.Fatal("SetDetached(missing) error = nil, want session not found")
+	}
+}
+
+func TestSessionBadgeUserOverridesAuto(t *testing.T) {
+	manager := NewSessionManager()
+	t.Cleanup(manager.Close)
+	if _, _, err := manager.CreateSession("demo", "0", 120, 40); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	if got := manager.Snapshot()[0].Badge; got != nil {
+		t.Fatalf("initial Badge = %+v, want nil", got)
+	}
+
+	if err := manager.SetAutoBadge("demo", &SessionBadge{Color: "#f00"}); err != nil {
+		t.Fatalf("SetAutoBadge() error = %v", err)
+	}
+	if got := manager.Snapshot()[0].Badge; got == nil || *got != (SessionBadge{Color: "#f00", Auto: true}) {
+		t.Fatalf("Badge after auto = %+v, want auto #f00", got)
+	}
+
+	if err := manager.SetSessionBadge("demo", &SessionBadge{Emoji: "🚀", Auto: true}); err != nil {
+		t.Fatalf("SetSessionBadge() error = %v", err)
+	}
+	if got := manager.Snapshot()[0].Badge; got == nil || *got != (SessionBadge{Emoji: "🚀"}) {
+		t.Fatalf("Badge after user set = %+v, want user 🚀", got)
+	}
+
+	// Clearing the user badge falls back to the rule-based one.
+	if err := manager.SetSessionBadge("demo", &SessionBadge{}); err != nil {
+		t.Fatalf("SetSessionBadge(empty) error = %v", err)
+	}
+	if got := manager.Snapshot()[0].Badge; got == nil || !got.Auto {
+		t.Fatalf("Badge after clear = %+v, want auto fallback", got)
+	}
+	if err := manager.SetAutoBadge("demo", nil); err != nil {
+		t.Fatalf("SetAutoBadge(nil) error = %v", err)
+	}
+	if got := manager.Snapshot()[0].Badge; got != nil {
+		t.Fatalf("Badge after clearing both = %+v, want nil", got)
+	}
+
+	if err := manager.SetSessionBadge("missing", &SessionBadge{Color: "#fff"}); err == nil {
+		t.Fatal("SetSessionBadge(missing) error = nil, want session not found")
+	}
+}
+
 func TestGetPaneContextSnapshot(t *testing.T) {
 	manager := NewSessionManager()
 	session, pane, err := manager.CreateSession("demo", "0", 120, 40)
@@ -784,6 +864,46 @@ func TestHasPane(t *testing.T) {
 	}
 }
 
+func TestPaneLocation(t *testing.T) {
+	manager := NewSessionManager()
+	session, pane, err := manager.CreateSession("demo", "0", 120, 40)
+	if err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+
+	sessionName, windowID, ok := manager.PaneLocation(pane.IDString())
+	if !ok || sessionName != "demo" || windowID != formatWindowID(session.Windows[0].ID) {
+		t.Fatalf("PaneLocation() = %q, %q, %v", sessionName, windowID, ok)
+	}
+	for _, input := range []string{"%999", "999", ""} {
+		if _, _, ok := manager.PaneLocation(input); ok {
+			t.Fatalf("PaneLocation(%q) ok = true, want false", input)
+		}
+	}
+}
+
+func TestPaneCurrentPath(t *testing.T) {
+	manager := NewSessionManager()
+	_, pane, err := manager.CreateSession("demo", "0", 120, 40)
+	if err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	if err := manager.SetRootPath("demo", `C:\repo`); err != nil {
+		t.Fatalf("SetRootPath() error = %v", err)
+	}
+
+	if got, err := manager.PaneCurrentPath(pane.IDString()); err != nil || got != `C:\repo` {
+		t.Fatalf("PaneCurrentPath() = %q, %v, want the session root", got, err)
+	}
+	manager.setPaneStartDir(pane.ID, `C:\repo\web`)
+	if got, err := manager.PaneCurrentPath(pane.IDString()); err != nil || got != `C:\repo\web` {
+		t.Fatalf("PaneCurrentPath() = %q, %v, want the pane start dir", got, err)
+	}
+	if _, err := manager.PaneCurrentPath("%999"); err == nil {
+		t.Fatal("PaneCurrentPath(unknown) error = nil, want error")
+	}
+}
+
 func TestSessionWorktreeInfoIsEmptyBoundaries(t *testing.T) {
 	var nilInfo *SessionWorktreeInfo
 	if !nilInfo.IsEmpty() {
@@ -985,3 +1105,63 @@ func TestWorktreeInfoEqual(t *testing.T) {
 		})
 	}
 }
+
+func TestSetSessionTaskbarAlertsMuted(t *testing.T) {
+	manager := NewSessionManager()
+	t.Cleanup(manager.Close)
+	if _, _, err := manager.CreateSession("demo", "0", 120, 40); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+
+	if err := manager.SetSessionTaskbarAlertsMuted("demo", true); err != nil {
+		t.Fatalf("SetSessionTaskbarAlertsMuted() error = %v", err)
+	}
+	if !manager.Snapshot()[0].TaskbarAlertsMuted {
+		t.Fatal("snapshot TaskbarAlertsMuted = false, want true")
+	}
+	if err := manager.SetSessionTaskbarAlertsMuted("demo", false); err != nil {
+		t.Fatalf("SetSessionTaskbarAlertsMuted(false) error = %v", err)
+	}
+	if manager.Snapshot()[0].TaskbarAlertsMuted {
+		t.Fatal("snapshot TaskbarAlertsMuted = true after unmute")
+	}
+	if err := manager.SetSessionTaskbarAlertsMuted("missing", true); err == nil {
+		t.Fatal("SetSessionTaskbarAlertsMuted(missing) error = nil, want session not found")
+	}
+}
+
+func TestSetSessionGroupAndSessionsInGroup(t *testing.T) {
+	manager := NewSessionManager()
+	t.Cleanup(manager.Close)
+	for _, name := range []string{"web", "api", "infra"} {
+		if _, _, err := manager.CreateSession(name, "0", 120, 40); err != nil {
+			t.Fatalf("CreateSession(%s) error = %v", name, err)
+		}
+	}
+
+	for _, name := range []string{"infra", "web"} {
+		if err := manager.SetSessionGroup(name, "shop"); err != nil {
+			t.Fatalf("SetSessionGroup(%s) error = %v", name, err)
+		}
+	}
+	if got := manager.SessionsInGroup("shop"); !slices.Equal(got, []string{"web", "infra"}) {
+		t.Fatalf("SessionsInGroup() = %v, want [web infra] in creation order", got)
+	}
+	if got := manager.SessionsInGroup(""); got != nil {
+		t.Fatalf("SessionsInGroup(\"\") = %v, want nil", got)
+	}
+	session, ok := manager.GetSession("web")
+	if !ok || session.Group != "shop" {
+		t.Fatalf("GetSession(web).Group = %+v, want shop", session)
+	}
+
+	if err := manager.SetSessionGroup("web", ""); err != nil {
+		t.Fatalf("SetSessionGroup(web, \"\") error = %v", err)
+	}
+	if got := manager.SessionsInGroup("shop"); !slices.Equal(got, []string{"infra"}) {
+		t.Fatalf("SessionsInGroup() after ungroup = %v, want [infra]", got)
+	}
+	if err := manager.SetSessionGroup("missing", "shop"); err == nil {
+		t.Fatal("SetSessionGroup(missing) error = nil, want session not found")
+	}
+}
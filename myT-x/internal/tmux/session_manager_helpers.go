@@ -19,6 +19,21 @@ func copyBoolPtr(src *bool) *bool {
 	return &v
 }
 
+func copySessionBadge(src *SessionBadge) *SessionBadge {
+	if src == nil {
+		return nil
+	}
+	v := *src
+	return &v
+}
+
+func sessionBadgeEqual(left, right *SessionBadge) bool {
+	if left == nil || right == nil {
+		return left == right
+	}
+	return *left == *right
+}
+
 func copyEnvMap(input map[string]string) map[string]string {
 	// Preserve caller safety by always returning a mutable map:
 	// nil/empty input -> empty non-nil map.
@@ -5,8 +5,9 @@ import (
 	"time"
 )
 
-// UpdateActivityByPaneID updates a session's activity timestamp by pane id (%N).
-// It returns true when an idle session moved back to active.
+// UpdateActivityByPaneID updates the activity timestamps of a pane (%N) and
+// its session. It returns true when an idle session moved back to active or
+// a collapsed pane was expanded by the output, i.e. when the snapshot changed.
 func (m *SessionManager) UpdateActivityByPaneID(paneID string) bool {
 	id, err := parsePaneID(strings.TrimSpace(paneID))
 	if err != nil {
@@ -21,14 +22,24 @@ func (m *SessionManager) UpdateActivityByPaneID(paneID string) bool {
 		return false
 	}
 
+	now := m.now()
+	pane.lastOutputAt = now
+	changed := false
+	if pane.Collapsed && now.Sub(pane.collapsedAt) >= collapsedActivityGrace {
+		pane.Collapsed = false
+		changed = true
+	}
+
 	session := pane.Window.Session
-	session.LastActivity = m.now()
-	if !session.IsIdle {
-		return false
+	session.LastActivity = now
+	if session.IsIdle {
+		session.IsIdle = false
+		changed = true
 	}
-	session.IsIdle = false
-	m.markStateMutationLocked()
-	return true
+	if changed {
+		m.markStateMutationLocked()
+	}
+	return changed
 }
 
 // CheckIdleState evaluates all sessions and returns true when any idle state changed.
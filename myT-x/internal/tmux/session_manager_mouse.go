@@ -0,0 +1,40 @@
+package tmux
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Pane mouse modes. An empty mode follows the configured default.
+const (
+	PaneMouseOn  = "on"
+	PaneMouseOff = "off"
+)
+
+// SetPaneMouse sets the mouse mode of one pane (%N) and returns its session
+// name. mode is PaneMouseOn, PaneMouseOff, or "" to follow the config default.
+func (m *SessionManager) SetPaneMouse(paneID string, mode string) (string, error) {
+	id, err := parsePaneID(strings.TrimSpace(paneID))
+	if err != nil {
+		return "", err
+	}
+	mode = strings.ToLower(strings.TrimSpace(mode))
+	switch mode {
+	case "", PaneMouseOn, PaneMouseOff:
+	default:
+		return "", fmt.Errorf("invalid mouse mode: %s", mode)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pane := m.panes[id]
+	if pane == nil || pane.Window == nil || pane.Window.Session == nil {
+		return "", fmt.Errorf("pane not found: %s", paneID)
+	}
+	if pane.Mouse != mode {
+		pane.Mouse = mode
+		m.markStateMutationLocked()
+	}
+	return pane.Window.Session.Name, nil
+}
@@ -0,0 +1,34 @@
+package tmux
+
+import "testing"
+
+func TestSetPaneMouse(t *testing.T) {
+	manager := NewSessionManager()
+	t.Cleanup(manager.Close)
+	_, pane, err := manager.CreateSession("demo", "main", 120, 40)
+	if err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+
+	sessionName, err := manager.SetPaneMouse(pane.IDString(), " OFF ")
+	if err != nil || sessionName != "demo" {
+		t.Fatalf("SetPaneMouse(off) = %q, %v, want demo", sessionName, err)
+	}
+	if got := manager.Snapshot()[0].Windows[0].Panes[0].Mouse; got != PaneMouseOff {
+		t.Fatalf("snapshot mouse = %q, want off", got)
+	}
+
+	if _, err := manager.SetPaneMouse(pane.IDString(), ""); err != nil {
+		t.Fatalf("SetPaneMouse(default) error = %v", err)
+	}
+	if got := manager.Snapshot()[0].Windows[0].Panes[0].Mouse; got != "" {
+		t.Fatalf("snapshot mouse after reset = %q, want default", got)
+	}
+
+	if _, err := manager.SetPaneMouse(pane.IDString(), "copy"); err == nil {
+		t.Fatal("SetPaneMouse(copy) succeeded, want invalid mode error")
+	}
+	if _, err := manager.SetPaneMouse("%99", PaneMouseOn); err == nil {
+		t.Fatal("SetPaneMouse(%99) succeeded, want pane not found")
+	}
+}
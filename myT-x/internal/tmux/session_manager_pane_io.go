@@ -137,6 +137,7 @@ func copyPaneSlice(panes []*TmuxPane) []TmuxPane {
 		}
 		copied := *pane
 		copied.Env = copyEnvMap(pane.Env)
+		copied.pid = pane.processID()
 		copied.Terminal = nil
 		copied.OutputHistory = nil
 		copied.Window = nil
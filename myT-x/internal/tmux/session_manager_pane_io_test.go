@@ -11,14 +11,14 @@ import (
 )
 
 func TestTmuxCopyFieldCountGuards(t *testing.T) {
-	if got := reflect.TypeFor[TmuxPane]().NumField(); got != 11 {
-		t.Fatalf("TmuxPane field count = %d, want 11. If a field was added, review copyPaneSlice and cloneSessionForRead.", got)
+	if got := reflect.TypeFor[TmuxPane]().NumField(); got != 18 {
+		t.Fatalf("TmuxPane field count = %d, want 18. If a field was added, review copyPaneSlice and cloneSessionForRead.", got)
 	}
 	if got := reflect.TypeFor[TmuxWindow]().NumField(); got != 6 {
 		t.Fatalf("TmuxWindow field count = %d, want 6. If a field was added, review cloneSessionForRead.", got)
 	}
-	if got := reflect.TypeFor[TmuxSession]().NumField(); got != 14 {
-		t.Fatalf("TmuxSession field count = %d, want 14. If a field was added, review cloneSessionForRead.", got)
+	if got := reflect.TypeFor[TmuxSession]().NumField(); got != 22 {
+		t.Fatalf("TmuxSession field count = %d, want 22. If a field was added, review cloneSessionForRead.", got)
 	}
 }
 
@@ -164,6 +164,8 @@ func (m *SessionManager) killPaneLocked(id int, paneIDStr string) (killPaneResul
 			}
 			candidate.Active = i == sessionWindow.ActivePN
 		}
+		// The fallback active pane may be collapsed; it must stay visible.
+		sessionWindow.Panes[sessionWindow.ActivePN].Collapsed = false
 		if nextLayout, removed := removePaneFromLayout(sessionWindow.Layout, pane.ID); removed && nextLayout != nil {
 			sessionWindow.Layout = nextLayout
 		} else {
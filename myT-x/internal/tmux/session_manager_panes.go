@@ -72,6 +72,8 @@ func (m *SessionManager) SetActivePane(paneID int) error {
 		p.Active = false
 	}
 	pane.Active = true
+	// Focusing a collapsed pane (e.g. clicking its header) expands it.
+	pane.Collapsed = false
 	window.ActivePN = pane.Index
 	session.ActiveWindowID = window.ID
 	// Active pane changes alter frontend-visible pane ordering/selection semantics.
@@ -147,17 +149,116 @@ func (m *SessionManager) applyLayoutPresetToWindowLocked(window *TmuxWindow, pre
 	if len(window.Panes) == 0 {
 		return errors.New("window has no panes")
 	}
-	paneIDs := make([]int, 0, len(window.Panes))
-	for _, pane := range window.Panes {
-		if pane == nil {
+	paneIDs := windowPaneIDs(window)
+	if len(paneIDs) == 0 {
+		return errors.New("window has no valid panes")
+	}
+	window.Layout = BuildPresetLayout(preset, paneIDs)
+	m.markTopologyMutationLocked()
+	return nil
+}
+
+// ApplyLayoutStringByWindowID applies a tmux layout dump (select-layout
+// <layout>) to a window identified by stable window ID.
+func (m *SessionManager) ApplyLayoutStringByWindowID(sessionName string, windowID int, layout string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	session, ok := m.sessions[sessionName]
+	if !ok {
+		return fmt.Errorf("session not found: %s", sessionName)
+	}
+	for _, window := range session.Windows {
+		if window == nil || window.ID != windowID {
 			continue
 		}
-		paneIDs = append(paneIDs, pane.ID)
+		return m.applyLayoutStringToWindowLocked(window, layout)
+	}
+	return fmt.Errorf("window not found in session: %s", sessionName)
+}
+
+// ApplyLayoutStringToActiveWindow applies a tmux layout dump to the active
+// window of a session. Panes are assigned to the layout in window order.
+func (m *SessionManager) ApplyLayoutStringToActiveWindow(sessionName string, layout string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	session, ok := m.sessions[sessionName]
+	if !ok {
+		return fmt.Errorf("session not found: %s", sessionName)
+	}
+	window := m.activeWindowInSessionLocked(session)
+	if window == nil {
+		return errors.New("session has no windows")
+	}
+	return m.applyLayoutStringToWindowLocked(window, layout)
+}
+
+// ActiveWindowLayoutString returns the tmux layout dump of the active window
+// of a session together with its pane count.
+func (m *SessionManager) ActiveWindowLayoutString(sessionName string) (string, int, error) {
+	// Lock (not RLock): activeWindowInSessionLocked may repair ActiveWindowID.
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	session, ok := m.sessions[sessionName]
+	if !ok {
+		return "", 0, fmt.Errorf("session not found: %s", sessionName)
+	}
+	window := m.activeWindowInSessionLocked(session)
+	if window == nil {
+		return "", 0, errors.New("session has no windows")
+	}
+	paneCount := len(windowPaneIDs(window))
+	if paneCount == 0 {
+		return "", 0, errors.New("window has no valid panes")
 	}
+	return windowLayoutString(window), paneCount, nil
+}
+
+// REQUIRES: m.mu must be held by the caller.
+func (m *SessionManager) applyLayoutStringToWindowLocked(window *TmuxWindow, layout string) error {
+	if window == nil {
+		return errors.New("window is nil")
+	}
+	paneIDs := windowPaneIDs(window)
 	if len(paneIDs) == 0 {
 		return errors.New("window has no valid panes")
 	}
-	window.Layout = BuildPresetLayout(preset, paneIDs)
+	root, err := ParseLayoutString(layout, paneIDs)
+	if err != nil {
+		return err
+	}
+	window.Layout = root
 	m.markTopologyMutationLocked()
 	return nil
 }
+
+// windowPaneIDs returns the IDs of the non-nil panes of window in order.
+func windowPaneIDs(window *TmuxWindow) []int {
+	paneIDs := make([]int, 0, len(window.Panes))
+	for _, pane := range window.Panes {
+		if pane == nil {
+			continue
+		}
+		paneIDs = append(paneIDs, pane.ID)
+	}
+	return paneIDs
+}
+
+// windowLayoutString renders the window layout in tmux's layout dump format.
+// A window without a stored layout is reported as its default arrangement.
+func windowLayoutString(window *TmuxWindow) string {
+	paneSizes := make(map[int][2]int, len(window.Panes))
+	for _, pane := range window.Panes {
+		if pane == nil {
+			continue
+		}
+		paneSizes[pane.ID] = [2]int{pane.Width, pane.Height}
+	}
+	root := window.Layout
+	if root == nil {
+		root = BuildPresetLayout(PresetEvenHorizontal, windowPaneIDs(window))
+	}
+	return FormatLayoutString(root, paneSizes)
+}
@@ -110,6 +110,7 @@ func (m *SessionManager) CreateSession(name string, windowName string, width, he
 		Name:         name,
 		CreatedAt:    now,
 		LastActivity: now,
+		LastSeen:     now,
 		Env:          map[string]string{},
 	}
 	m.nextSessionID++
@@ -344,13 +345,22 @@ func cloneSessionForRead(session *TmuxSession) *TmuxSession {
 		CreatedAt:           session.CreatedAt,
 		LastActivity:        session.LastActivity,
 		IsIdle:              session.IsIdle,
+		LastSeen:            session.LastSeen,
+		LastBell:            session.LastBell,
 		Env:                 copyEnvMap(session.Env),
 		IsAgentTeam:         session.IsAgentTeam,
+		Detached:            session.Detached,
+		Badge:               copySessionBadge(session.Badge),
+		AutoBadge:           copySessionBadge(session.AutoBadge),
+		TaskbarAlertsMuted:  session.TaskbarAlertsMuted,
+		Group:               session.Group,
 		RootPath:            session.RootPath,
 		ActiveWindowID:      session.ActiveWindowID,
 		UseClaudeEnv:        copyBoolPtr(session.UseClaudeEnv),
 		UsePaneEnv:          copyBoolPtr(session.UsePaneEnv),
 		UseSessionPaneScope: copyBoolPtr(session.UseSessionPaneScope),
+
+		ShellHistoryIsolated: session.ShellHistoryIsolated,
 	}
 	if session.Worktree != nil {
 		worktreeCopy := *session.Worktree
@@ -390,6 +400,14 @@ func cloneSessionForRead(session *TmuxSession) *TmuxSession {
 				Height:   pane.Height,
 				Env:      copyEnvMap(pane.Env),
 				Window:   windowCopy,
+
+				Collapsed:     pane.Collapsed,
+				Mouse:         pane.Mouse,
+				TerminalModes: pane.TerminalModes,
+				collapsedAt:   pane.collapsedAt,
+				lastOutputAt:  pane.lastOutputAt,
+				startDir:      pane.startDir,
+				pid:           pane.processID(),
 				// S-45: Terminal intentionally nil — see function doc.
 			}
 			windowCopy.Panes = append(windowCopy.Panes, paneCopy)
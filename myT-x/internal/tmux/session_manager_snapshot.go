@@ -39,9 +39,14 @@ func (m *SessionManager) Snapshot() []SessionSnapshot {
 			IsIdle:         session.IsIdle,
 			ActiveWindowID: session.ActiveWindowID,
 			IsAgentTeam:    session.IsAgentTeam,
+			Detached:       session.Detached,
+			Badge:          effectiveSessionBadge(session),
 			Windows:        make([]WindowSnapshot, 0, len(session.Windows)),
 			Worktree:       worktree,
 			RootPath:       session.RootPath,
+
+			TaskbarAlertsMuted: session.TaskbarAlertsMuted,
+			Group:              session.Group,
 		}
 		for _, window := range session.Windows {
 			if window == nil {
@@ -50,7 +55,7 @@ func (m *SessionManager) Snapshot() []SessionSnapshot {
 			ws := WindowSnapshot{
 				ID:       window.ID,
 				Name:     window.Name,
-				Layout:   cloneLayout(window.Layout),
+				Layout:   visibleLayout(window),
 				ActivePN: window.ActivePN,
 				Panes:    make([]PaneSnapshot, 0, len(window.Panes)),
 			}
@@ -65,6 +70,10 @@ func (m *SessionManager) Snapshot() []SessionSnapshot {
 					Active: pane.Active,
 					Width:  pane.Width,
 					Height: pane.Height,
+
+					Collapsed:     pane.Collapsed,
+					Mouse:         pane.Mouse,
+					TerminalModes: pane.TerminalModes,
 				}
 				ws.Panes = append(ws.Panes, ps)
 			}
@@ -80,6 +89,22 @@ func (m *SessionManager) Snapshot() []SessionSnapshot {
 
 // cloneSessionSnapshots creates independent deep copies of a snapshot slice.
 // Delegates to SessionSnapshot.Clone() for each element.
+// effectiveSessionBadge returns the user-set badge, falling back to the
+// rule-based badge marked Auto. Returns nil when neither is set.
+func effectiveSessionBadge(session *TmuxSession) *SessionBadge {
+	if !session.Badge.IsEmpty() {
+		badge := copySessionBadge(session.Badge)
+		badge.Auto = false
+		return badge
+	}
+	if !session.AutoBadge.IsEmpty() {
+		badge := copySessionBadge(session.AutoBadge)
+		badge.Auto = true
+		return badge
+	}
+	return nil
+}
+
 func cloneSessionSnapshots(src []SessionSnapshot) []SessionSnapshot {
 	if len(src) == 0 {
 		return []SessionSnapshot{}
@@ -107,6 +132,19 @@ func (m *SessionManager) sortedSessionNamesLocked() []string {
 	return m.sortedSessionNames
 }
 
+// Counts returns the number of sessions and panes without building a
+// snapshot.
+func (m *SessionManager) Counts() (sessions, panes int) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, pane := range m.panes {
+		if pane != nil {
+			panes++
+		}
+	}
+	return len(m.sessions), panes
+}
+
 // ActivePaneIDs returns the set of all pane ID strings currently managed.
 // This is a lightweight alternative to Snapshot() when only pane IDs are needed.
 func (m *SessionManager) ActivePaneIDs() map[string]struct{} {
@@ -77,3 +77,23 @@ func TestSortedSessionNamesLockedRebuildsAfterSessionMapMutation(t *testing.T) {
 		t.Fatalf("last session = %q, want %q", after[len(after)-1], "gamma")
 	}
 }
+
+func TestSessionManagerCounts(t *testing.T) {
+	manager := NewSessionManager()
+	if sessions, panes := manager.Counts(); sessions != 0 || panes != 0 {
+		t.Fatalf("Counts() = %d, %d, want 0, 0", sessions, panes)
+	}
+	_, pane, err := manager.CreateSession("alpha", "0", 120, 40)
+	if err != nil {
+		t.Fatalf("CreateSession(alpha) error = %v", err)
+	}
+	if _, err := manager.SplitPane(pane.ID, SplitHorizontal); err != nil {
+		t.Fatalf("SplitPane() error = %v", err)
+	}
+	if _, _, err := manager.CreateSession("beta", "0", 120, 40); err != nil {
+		t.Fatalf("CreateSession(beta) error = %v", err)
+	}
+	if sessions, panes := manager.Counts(); sessions != 2 || panes != 3 {
+		t.Fatalf("Counts() = %d, %d, want 2, 3", sessions, panes)
+	}
+}
@@ -3,8 +3,11 @@ package tmux
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
+
+	"myT-x/internal/tmuxtarget"
 )
 
 // ResolveTarget parses a tmux target and returns a pane.
@@ -71,6 +74,13 @@ func (m *SessionManager) resolveTargetCore(target string, callerPaneID int) (pan
 		return nil, true, nil, nil
 	}
 
+	if rel, isRelative, parseErr := tmuxtarget.ParseRelativePane(target); isRelative {
+		if parseErr != nil {
+			return nil, false, nil, parseErr
+		}
+		p, relErr := m.resolveRelativePaneLocked(rel, target, callerPaneID)
+		return p, false, nil, relErr
+	}
 	if strings.HasPrefix(target, "%") {
 		id, parseErr := parsePaneID(target)
 		if parseErr != nil {
@@ -178,6 +188,34 @@ func (m *SessionManager) resolveWindowPaneTarget(session *TmuxSession, remainder
 	return pane, false, nil
 }
 
+// resolveRelativePaneLocked resolves a target relative to the caller pane
+// ($TMUX_PANE), e.g. ".1", "+" or "-", within the caller's window. Without a
+// caller pane there is no current pane to count from.
+//
+// REQUIRES: m.mu must be held by the caller in some mode (RLock or Lock).
+func (m *SessionManager) resolveRelativePaneLocked(rel tmuxtarget.RelativePane, target string, callerPaneID int) (*TmuxPane, error) {
+	current, ok := m.panes[callerPaneID]
+	if callerPaneID < 0 || !ok || current == nil {
+		return nil, fmt.Errorf("no current pane for relative target %q", target)
+	}
+	if current.Window == nil {
+		return nil, errors.New("current pane has no window")
+	}
+	panes := current.Window.Panes
+	idx := slices.IndexFunc(panes, func(p *TmuxPane) bool { return p != nil && p.ID == current.ID })
+	if idx < 0 {
+		return nil, fmt.Errorf("current pane not found in its window: %%%d", current.ID)
+	}
+	idx, err := rel.Resolve(idx, len(panes))
+	if err != nil {
+		return nil, fmt.Errorf("target %q: %w", target, err)
+	}
+	if panes[idx] == nil {
+		return nil, fmt.Errorf("pane at index %d is nil", idx)
+	}
+	return panes[idx], nil
+}
+
 // resolveTargetWriteLocked re-resolves a target under exclusive Lock.
 // Called only when the RLock fast path detected that auto-repair is needed.
 //
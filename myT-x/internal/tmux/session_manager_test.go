@@ -23,6 +23,57 @@ func TestResolveTargetByPaneID(t *testing.T) {
 	}
 }
 
+func TestResolveTargetRelativeToCallerPane(t *testing.T) {
+	manager := NewSessionManager()
+	_, pane0, err := manager.CreateSession("demo", "main", 120, 40)
+	if err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	pane1, err := manager.SplitPane(pane0.ID, SplitHorizontal)
+	if err != nil {
+		t.Fatalf("SplitPane() error = %v", err)
+	}
+	pane2, err := manager.SplitPane(pane1.ID, SplitHorizontal)
+	if err != nil {
+		t.Fatalf("SplitPane() error = %v", err)
+	}
+
+	cases := []struct {
+		target string
+		caller int
+		want   int
+	}{
+		{".", pane1.ID, pane1.ID},
+		{".0", pane2.ID, pane0.ID},
+		{"+", pane0.ID, pane1.ID},
+		{"+", pane2.ID, pane0.ID},
+		{"-", pane0.ID, pane2.ID},
+		{".-2", pane2.ID, pane0.ID},
+	}
+	for _, tc := range cases {
+		resolved, err := manager.ResolveTarget(tc.target, tc.caller)
+		if err != nil {
+			t.Fatalf("ResolveTarget(%q, %%%d) error = %v", tc.target, tc.caller, err)
+		}
+		if resolved.ID != tc.want {
+			t.Fatalf("ResolveTarget(%q, %%%d) = %%%d, want %%%d", tc.target, tc.caller, resolved.ID, tc.want)
+		}
+	}
+
+	for _, tc := range []struct {
+		target string
+		caller int
+	}{
+		{".3", pane0.ID},
+		{".x", pane0.ID},
+		{"+", -1},
+	} {
+		if _, err := manager.ResolveTarget(tc.target, tc.caller); err == nil {
+			t.Fatalf("ResolveTarget(%q, %d) should fail", tc.target, tc.caller)
+		}
+	}
+}
+
 // C-5: TestResolveTargetRepairsStaleActiveWindowID verifies that ResolveTarget
 // handles a stale/invalid ActiveWindowID gracefully by auto-repairing to the
 // first surviving window. The auto-repair path is triggered when the RLock fast
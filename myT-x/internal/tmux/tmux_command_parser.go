@@ -63,6 +63,9 @@ var internalCommandFlagSpecs = map[string]map[string]tmuxFlagKind{
 	"capture-pane":     {"-a": tmuxFlagBool, "-b": tmuxFlagString, "-C": tmuxFlagBool, "-e": tmuxFlagBool, "-E": tmuxFlagString, "-J": tmuxFlagBool, "-M": tmuxFlagBool, "-N": tmuxFlagBool, "-p": tmuxFlagBool, "-P": tmuxFlagBool, "-q": tmuxFlagBool, "-S": tmuxFlagString, "-T": tmuxFlagBool, "-t": tmuxFlagString},
 	"run-shell":        {"-b": tmuxFlagBool, "-t": tmuxFlagString, "-C": tmuxFlagBool, "-c": tmuxFlagString},
 	"if-shell":         {"-b": tmuxFlagBool, "-F": tmuxFlagBool, "-t": tmuxFlagString},
+
+	// myT-x extensions.
+	"copy-between-sessions": {"-s": tmuxFlagString, "-t": tmuxFlagString},
 }
 
 func canonicalTmuxCommandName(name string) string {
@@ -23,9 +23,31 @@ type TmuxSession struct {
 	IsIdle         bool              `json:"-"`
 	Env            map[string]string `json:"env,omitempty"`
 
+	// LastSeen is when the user last had this session active. Output after
+	// LastSeen counts as unseen activity.
+	LastSeen time.Time `json:"-"`
+	// LastBell is when a pane of this session last rang the terminal bell.
+	LastBell time.Time `json:"-"`
+
 	// IsAgentTeam is omitted when false. Frontend treats missing as false.
 	IsAgentTeam bool `json:"is_agent_team,omitempty"`
 
+	// Detached marks a session created with new-session -d. Its panes run
+	// normally but the UI does not surface it until it is attached.
+	Detached bool `json:"detached,omitempty"`
+
+	// Badge is the user-set color/emoji label. Nil means none was set.
+	Badge *SessionBadge `json:"badge,omitempty"`
+	// AutoBadge is resolved from session_badge_rules and shown only when
+	// Badge is nil. Backend-only; snapshots expose the effective badge.
+	AutoBadge *SessionBadge `json:"-"`
+	// TaskbarAlertsMuted opts the session out of taskbar flashing and the
+	// taskbar badge count.
+	TaskbarAlertsMuted bool `json:"taskbar_alerts_muted,omitempty"`
+	// Group is the session group the session belongs to, e.g. one repo of a
+	// multi-repo workspace. Empty means ungrouped.
+	Group string `json:"group,omitempty"`
+
 	// Worktree metadata grouped as one logical unit.
 	// Nil means no worktree-related metadata is attached to the session.
 	Worktree *SessionWorktreeInfo `json:"worktree,omitempty"`
@@ -45,6 +67,24 @@ type TmuxSession struct {
 	// and list-panes -a is scoped to the caller's session.
 	// nil = legacy session (no session scoping, backward compatible).
 	UseSessionPaneScope *bool `json:"use_session_pane_scope,omitempty"`
+	// ShellHistoryIsolated points the shell history of the session's panes
+	// into the session's state directory instead of the user's global
+	// history file.
+	ShellHistoryIsolated bool `json:"shell_history_isolated,omitempty"`
+}
+
+// SessionBadge is a color/emoji label shown next to a session in the UI.
+type SessionBadge struct {
+	Color string `json:"color,omitempty"`
+	Emoji string `json:"emoji,omitempty"`
+	// Auto is true when the badge was resolved from session_badge_rules
+	// rather than set by the user. Only meaningful in snapshots.
+	Auto bool `json:"auto,omitempty"`
+}
+
+// IsEmpty reports whether the badge carries neither color nor emoji.
+func (b *SessionBadge) IsEmpty() bool {
+	return b == nil || (b.Color == "" && b.Emoji == "")
 }
 
 // SessionWorktreeInfo is frontend-safe git/worktree metadata for a session.
@@ -105,6 +145,22 @@ type TmuxPane struct {
 	Env           map[string]string  `json:"env,omitempty"`
 	OutputHistory *PaneOutputHistory `json:"-"`
 	Window        *TmuxWindow        `json:"-"`
+	// Collapsed panes keep their process and scrollback but are left out of
+	// the snapshot layout; the frontend shows them as headers only.
+	Collapsed bool `json:"collapsed,omitempty"`
+	// Mouse is PaneMouseOn or PaneMouseOff; empty follows the config default.
+	Mouse string `json:"mouse,omitempty"`
+	// TerminalModes are tracked from the pane output by its read loop.
+	TerminalModes PaneTerminalModes `json:"terminalModes"`
+	// collapsedAt starts the grace period of collapsedActivityGrace.
+	collapsedAt time.Time
+	// lastOutputAt is the time the pane last produced output.
+	lastOutputAt time.Time
+	// startDir is the directory the pane process was started in.
+	startDir string
+	// pid is the pane process id copied into read clones, which carry no
+	// Terminal. See processID.
+	pid int
 }
 
 // IDString returns the pane identifier in tmux "%N" format.
@@ -118,6 +174,14 @@ func (p *TmuxPane) IDString() string {
 	return formatPaneID(p.ID)
 }
 
+// processID returns the pane process id, or 0 when no process is attached.
+func (p *TmuxPane) processID() int {
+	if p.Terminal != nil {
+		return p.Terminal.PID()
+	}
+	return p.pid
+}
+
 func (p *TmuxPane) ttyPath() string {
 	return fmt.Sprintf(`\\.\conpty\%%%d`, p.ID)
 }
@@ -134,6 +198,12 @@ type PaneSnapshot struct {
 	Active bool   `json:"active"`
 	Width  int    `json:"width"`
 	Height int    `json:"height"`
+	// Collapsed is omitted when false. Collapsed panes are absent from
+	// WindowSnapshot.Layout.
+	Collapsed bool `json:"collapsed,omitempty"`
+	// Mouse is omitted when the pane follows the config default.
+	Mouse         string            `json:"mouse,omitempty"`
+	TerminalModes PaneTerminalModes `json:"terminalModes"`
 }
 
 // WindowSnapshot is a frontend-safe window representation.
@@ -156,11 +226,19 @@ type SessionSnapshot struct {
 	// ActiveWindowID identifies the active window in this session snapshot.
 	ActiveWindowID int `json:"active_window_id"`
 	// IsAgentTeam is omitted when false. Frontend treats missing as false.
-	IsAgentTeam bool             `json:"is_agent_team,omitempty"`
-	Windows     []WindowSnapshot `json:"windows"`
+	IsAgentTeam bool `json:"is_agent_team,omitempty"`
+	// Detached is omitted when false. Frontend treats missing as attached.
+	Detached bool `json:"detached,omitempty"`
+	// Badge is the effective badge: the user-set one, else the rule-based one.
+	Badge   *SessionBadge    `json:"badge,omitempty"`
+	Windows []WindowSnapshot `json:"windows"`
 
 	Worktree *SessionWorktreeInfo `json:"worktree,omitempty"`
 	RootPath string               `json:"root_path,omitempty"`
+	// TaskbarAlertsMuted is omitted when false.
+	TaskbarAlertsMuted bool `json:"taskbar_alerts_muted,omitempty"`
+	// Group is omitted when the session is ungrouped.
+	Group string `json:"group,omitempty"`
 }
 
 // Clone returns a deep copy of the SessionSnapshot.
@@ -171,6 +249,7 @@ func (ss SessionSnapshot) Clone() SessionSnapshot {
 		worktreeCopy := *ss.Worktree
 		out.Worktree = &worktreeCopy
 	}
+	out.Badge = copySessionBadge(ss.Badge)
 
 	if len(ss.Windows) == 0 {
 		out.Windows = []WindowSnapshot{}
@@ -223,6 +302,8 @@ type PaneContextSnapshot struct {
 	// SessionWorkDir is the effective working directory for the session.
 	// Worktree sessions use Worktree.Path; regular sessions use RootPath.
 	SessionWorkDir string
+	// ShellHistoryIsolated mirrors TmuxSession.ShellHistoryIsolated.
+	ShellHistoryIsolated bool
 	// PaneWidth and PaneHeight are the pane's column/row dimensions at snapshot
 	// time. Added for I-02 so that handleResizePane can read fallback dimensions
 	// under RLock instead of dereferencing the live pointer after lock release.
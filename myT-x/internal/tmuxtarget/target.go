@@ -0,0 +1,106 @@
+// Package tmuxtarget holds the tmux client semantics that the tmux-shim and
+// the command router must agree on: which commands refuse to run nested
+// inside an existing session, and how pane targets relative to the current
+// pane ($TMUX_PANE) are parsed and resolved. It has no dependencies so the
+// shim can use it without pulling in the session manager.
+package tmuxtarget
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrNested is returned for a command that would attach a client inside an
+// existing session. The message is the one real tmux prints.
+var ErrNested = errors.New("sessions should be nested with care, unset $TMUX to force")
+
+// CheckNesting reports ErrNested when command would attach the calling
+// client while it already runs inside a session (insideSession, i.e. $TMUX is
+// set): attach-session, and new-session without -d (detached). Control mode
+// clients are exempt, as in tmux. Command aliases must already be expanded.
+func CheckNesting(command string, detached, controlMode, insideSession bool) error {
+	if !insideSession || controlMode {
+		return nil
+	}
+	switch command {
+	case "attach-session":
+		return ErrNested
+	case "new-session":
+		if !detached {
+			return ErrNested
+		}
+	}
+	return nil
+}
+
+// RelativePane is a pane target relative to the current pane:
+//
+//	.      the current pane
+//	.N     pane index N of the current window
+//	+ -    the next or previous pane of the current window
+//	+N -N  N panes after or before the current pane
+//	.+ .-  same as + and -, also with a count
+//
+// Offsets wrap around the window like in tmux.
+type RelativePane struct {
+	// Index is the absolute pane index when Offset is false.
+	Index int
+	// Offset selects the pane Delta positions from the current pane.
+	Offset bool
+	Delta  int
+}
+
+// ParseRelativePane parses target as a RelativePane. ok is false when target
+// is not a relative pane target; err is set when it looks like one but is
+// malformed.
+func ParseRelativePane(target string) (rel RelativePane, ok bool, err error) {
+	target = strings.TrimSpace(target)
+	if target == "" {
+		return RelativePane{}, false, nil
+	}
+	rest := target
+	dotted := false
+	if after, found := strings.CutPrefix(rest, "."); found {
+		rest, dotted = after, true
+	}
+	switch {
+	case dotted && rest == "":
+		return RelativePane{Offset: true}, true, nil
+	case strings.HasPrefix(rest, "+"), strings.HasPrefix(rest, "-"):
+		delta := 1
+		if count := rest[1:]; count != "" {
+			delta, err = strconv.Atoi(count)
+			if err != nil || delta < 0 || strings.HasPrefix(count, "+") || strings.HasPrefix(count, "-") {
+				return RelativePane{}, true, fmt.Errorf("invalid relative pane target: %s", target)
+			}
+		}
+		if rest[0] == '-' {
+			delta = -delta
+		}
+		return RelativePane{Offset: true, Delta: delta}, true, nil
+	case dotted:
+		index, convErr := strconv.Atoi(rest)
+		if convErr != nil || index < 0 || strings.HasPrefix(rest, "+") {
+			return RelativePane{}, true, fmt.Errorf("invalid relative pane target: %s", target)
+		}
+		return RelativePane{Index: index}, true, nil
+	}
+	return RelativePane{}, false, nil
+}
+
+// Resolve returns the pane index rel selects in a window of count panes whose
+// current pane has index current.
+func (rel RelativePane) Resolve(current, count int) (int, error) {
+	if count <= 0 {
+		return 0, errors.New("window has no panes")
+	}
+	if !rel.Offset {
+		if rel.Index >= count {
+			return 0, fmt.Errorf("pane index out of range: %d", rel.Index)
+		}
+		return rel.Index, nil
+	}
+	return ((current+rel.Delta)%count + count) % count, nil
+}
@@ -0,0 +1,81 @@
+package tmuxtarget
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckNesting(t *testing.T) {
+	cases := []struct {
+		command                       string
+		detached, controlMode, nested bool
+		wantErr                       bool
+	}{
+		{command: "new-session", nested: true, wantErr: true},
+		{command: "new-session", detached: true, nested: true},
+		{command: "new-session", controlMode: true, nested: true},
+		{command: "new-session"},
+		{command: "attach-session", nested: true, wantErr: true},
+		{command: "attach-session", detached: true, nested: true, wantErr: true},
+		{command: "attach-session"},
+		{command: "split-window", nested: true},
+	}
+	for _, tc := range cases {
+		err := CheckNesting(tc.command, tc.detached, tc.controlMode, tc.nested)
+		if got := errors.Is(err, ErrNested); got != tc.wantErr {
+			t.Errorf("CheckNesting(%+v) = %v, want nested error %v", tc, err, tc.wantErr)
+		}
+	}
+}
+
+func TestParseRelativePane(t *testing.T) {
+	cases := []struct {
+		target string
+		want   RelativePane
+		ok     bool
+	}{
+		{target: ".", want: RelativePane{Offset: true}, ok: true},
+		{target: ".1", want: RelativePane{Index: 1}, ok: true},
+		{target: "+", want: RelativePane{Offset: true, Delta: 1}, ok: true},
+		{target: "-", want: RelativePane{Offset: true, Delta: -1}, ok: true},
+		{target: "+2", want: RelativePane{Offset: true, Delta: 2}, ok: true},
+		{target: ".-3", want: RelativePane{Offset: true, Delta: -3}, ok: true},
+		{target: "%3"},
+		{target: "work:0.1"},
+		{target: ""},
+	}
+	for _, tc := range cases {
+		got, ok, err := ParseRelativePane(tc.target)
+		if err != nil || ok != tc.ok || got != tc.want {
+			t.Errorf("ParseRelativePane(%q) = %+v, %v, %v; want %+v, %v", tc.target, got, ok, err, tc.want, tc.ok)
+		}
+	}
+	for _, target := range []string{".x", "+x", "--1", ".+-1", ".-1.5"} {
+		if _, ok, err := ParseRelativePane(target); !ok || err == nil {
+			t.Errorf("ParseRelativePane(%q) = ok %v, err %v; want a malformed relative target", target, ok, err)
+		}
+	}
+}
+
+func TestRelativePaneResolveWraps(t *testing.T) {
+	cases := []struct {
+		rel     RelativePane
+		current int
+		want    int
+	}{
+		{RelativePane{Offset: true}, 1, 1},
+		{RelativePane{Offset: true, Delta: 1}, 2, 0},
+		{RelativePane{Offset: true, Delta: -1}, 0, 2},
+		{RelativePane{Offset: true, Delta: -4}, 1, 0},
+		{RelativePane{Index: 2}, 0, 2},
+	}
+	for _, tc := range cases {
+		got, err := tc.rel.Resolve(tc.current, 3)
+		if err != nil || got != tc.want {
+			t.Errorf("%+v.Resolve(%d, 3) = %d, %v; want %d", tc.rel, tc.current, got, err, tc.want)
+		}
+	}
+	if _, err := (RelativePane{Index: 3}).Resolve(0, 3); err == nil {
+		t.Error("Resolve() of an index past the last pane should fail")
+	}
+}
@@ -0,0 +1,135 @@
+// Package toolpaths detects the tool manifests of a repository and the tool
+// directories they imply, so new panes can find repo-local tools on PATH.
+package toolpaths
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"myT-x/internal/config"
+)
+
+// Entry is one detected tool directory.
+type Entry struct {
+	// Kind is the manifest kind, e.g. config.ToolPathKindNode.
+	Kind string `json:"kind"`
+	// Manifest is the manifest file that triggered the entry.
+	Manifest string `json:"manifest"`
+	// Dir is the directory prepended to PATH.
+	Dir string `json:"dir"`
+}
+
+// environment is the process environment Detect reads; tests replace it.
+type environment struct {
+	getenv  func(string) string
+	homeDir func() (string, error)
+}
+
+var processEnvironment = environment{getenv: os.Getenv, homeDir: os.UserHomeDir}
+
+// Detect returns the tool directories for the manifests of kinds found in
+// root, in the order of kinds. A manifest whose tool directory does not
+// exist (e.g. package.json before npm install) yields no entry.
+func Detect(root string, kinds []string) []Entry {
+	return detect(root, kinds, processEnvironment)
+}
+
+func detect(root string, kinds []string, env environment) []Entry {
+	root = strings.TrimSpace(root)
+	if root == "" {
+		return nil
+	}
+	var entries []Entry
+	for _, kind := range kinds {
+		manifestName, dir := kindManifest(kind, root, env)
+		if manifestName == "" || dir == "" {
+			continue
+		}
+		manifest := filepath.Join(root, manifestName)
+		if !isFile(manifest) || !isDir(dir) {
+			continue
+		}
+		entries = append(entries, Entry{Kind: kind, Manifest: manifest, Dir: filepath.Clean(dir)})
+	}
+	return entries
+}
+
+// kindManifest returns the manifest file name of kind and the tool
+// directory it implies.
+func kindManifest(kind, root string, env environment) (manifest, dir string) {
+	switch kind {
+	case config.ToolPathKindNode:
+		return "package.json", filepath.Join(root, "node_modules", ".bin")
+	case config.ToolPathKindAsdf:
+		return ".tool-versions", asdfShimsDir(env)
+	case config.ToolPathKindGo:
+		return "go.mod", goBinDir(env)
+	}
+	return "", ""
+}
+
+func asdfShimsDir(env environment) string {
+	if dataDir := strings.TrimSpace(env.getenv("ASDF_DATA_DIR")); dataDir != "" {
+		return filepath.Join(dataDir, "shims")
+	}
+	if home, err := env.homeDir(); err == nil && home != "" {
+		return filepath.Join(home, ".asdf", "shims")
+	}
+	return ""
+}
+
+func goBinDir(env environment) string {
+	if gobin := strings.TrimSpace(env.getenv("GOBIN")); gobin != "" {
+		return gobin
+	}
+	for _, gopath := range filepath.SplitList(env.getenv("GOPATH")) {
+		if gopath = strings.TrimSpace(gopath); gopath != "" {
+			return filepath.Join(gopath, "bin")
+		}
+	}
+	if home, err := env.homeDir(); err == nil && home != "" {
+		return filepath.Join(home, "go", "bin")
+	}
+	return ""
+}
+
+// RepoLocal reports whether the tool directory of kind lies inside the
+// repository, so that whoever controls the repository controls its tools.
+func RepoLocal(kind string) bool {
+	return kind == config.ToolPathKindNode
+}
+
+// WithoutRepoLocal returns entries without repo-local tool directories, for
+// repositories the user has not trusted. The result is never nil.
+func WithoutRepoLocal(entries []Entry) []Entry {
+	kept := make([]Entry, 0, len(entries))
+	for _, entry := range entries {
+		if !RepoLocal(entry.Kind) {
+			kept = append(kept, entry)
+		}
+	}
+	return kept
+}
+
+// Dirs returns the distinct directories of entries in order.
+func Dirs(entries []Entry) []string {
+	var dirs []string
+	for _, entry := range entries {
+		if !slices.Contains(dirs, entry.Dir) {
+			dirs = append(dirs, entry.Dir)
+		}
+	}
+	return dirs
+}
+
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,109 @@
+package toolpaths
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"myT-x/internal/config"
+)
+
+func mkdirAll(t *testing.T, path string) string {
+	t.Helper()
+	if err := os.MkdirAll(path, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func writeFile(t *testing.T, path string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte("{}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func fakeEnvironment(vars map[string]string, home string) environment {
+	return environment{
+		getenv: func(key string) string { return vars[key] },
+		homeDir: func() (string, error) {
+			if home == "" {
+				return "", errors.New("no home")
+			}
+			return home, nil
+		},
+	}
+}
+
+func TestDetect(t *testing.T) {
+	root := t.TempDir()
+	home := t.TempDir()
+	writeFile(t, filepath.Join(root, "package.json"))
+	writeFile(t, filepath.Join(root, ".tool-versions"))
+	writeFile(t, filepath.Join(root, "go.mod"))
+	nodeBin := mkdirAll(t, filepath.Join(root, "node_modules", ".bin"))
+	shims := mkdirAll(t, filepath.Join(home, ".asdf", "shims"))
+	gobin := mkdirAll(t, filepath.Join(t.TempDir(), "gobin"))
+
+	env := fakeEnvironment(map[string]string{"GOBIN": gobin}, home)
+	got := detect(root, config.ToolPathKinds, env)
+	want := []Entry{
+		{Kind: config.ToolPathKindNode, Manifest: filepath.Join(root, "package.json"), Dir: nodeBin},
+		{Kind: config.ToolPathKindAsdf, Manifest: filepath.Join(root, ".tool-versions"), Dir: shims},
+		{Kind: config.ToolPathKindGo, Manifest: filepath.Join(root, "go.mod"), Dir: gobin},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("detect() = %+v, want %+v", got, want)
+	}
+	if dirs := Dirs(got); !reflect.DeepEqual(dirs, []string{nodeBin, shims, gobin}) {
+		t.Fatalf("Dirs() = %v", dirs)
+	}
+
+	if got := detect(root, []string{config.ToolPathKindGo}, env); len(got) != 1 || got[0].Kind != config.ToolPathKindGo {
+		t.Fatalf("detect(go only) = %+v, want the go entry", got)
+	}
+}
+
+func TestDetectSkipsMissingDirectories(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "package.json"))
+	writeFile(t, filepath.Join(root, "go.mod"))
+
+	// No node_modules/.bin yet and no GOPATH/bin on disk.
+	env := fakeEnvironment(map[string]string{"GOPATH": t.TempDir()}, "")
+	if got := detect(root, config.ToolPathKinds, env); len(got) != 0 {
+		t.Fatalf("detect() = %+v, want no entries", got)
+	}
+	if got := detect("", config.ToolPathKinds, env); got != nil {
+		t.Fatalf("detect(\"\") = %+v, want nil", got)
+	}
+}
+
+func TestGoBinDirFallbacks(t *testing.T) {
+	gopath := filepath.Join("a", "gopath")
+	if got, want := goBinDir(fakeEnvironment(map[string]string{"GOPATH": gopath + string(filepath.ListSeparator) + "other"}, "")), filepath.Join(gopath, "bin"); got != want {
+		t.Fatalf("goBinDir(GOPATH) = %q, want %q", got, want)
+	}
+	if got, want := goBinDir(fakeEnvironment(nil, "home")), filepath.Join("home", "go", "bin"); got != want {
+		t.Fatalf("goBinDir(home) = %q, want %q", got, want)
+	}
+	if got := goBinDir(fakeEnvironment(nil, "")); got != "" {
+		t.Fatalf("goBinDir() without any source = %q, want empty", got)
+	}
+}
+
+func TestWithoutRepoLocal(t *testing.T) {
+	entries := []Entry{
+		{Kind: config.ToolPathKindNode, Dir: filepath.Join("repo", "node_modules", ".bin")},
+		{Kind: config.ToolPathKindGo, Dir: filepath.Join("home", "go", "bin")},
+	}
+	got := WithoutRepoLocal(entries)
+	if !reflect.DeepEqual(got, entries[1:]) {
+		t.Fatalf("WithoutRepoLocal() = %+v, want only the go entry", got)
+	}
+	if got := WithoutRepoLocal(nil); got == nil || len(got) != 0 {
+		t.Fatalf("WithoutRepoLocal(nil) = %#v, want an empty slice", got)
+	}
+}
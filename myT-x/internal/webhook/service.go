@@ -0,0 +1,278 @@
+// Package webhook POSTs selected runtime events as JSON to user-configured
+// endpoints. Deliveries are queued and sent in the background with retries,
+// so emitting an event never waits for the network. A body is signed with
+// HMAC-SHA256 when the endpoint has a secret.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// MaxAttempts bounds the requests made for one delivery.
+	MaxAttempts = 4
+	// RequestTimeout bounds one request.
+	RequestTimeout = 10 * time.Second
+	// queueSize bounds deliveries waiting to be sent. Further events are
+	// dropped until the queue drains.
+	queueSize = 256
+	// maxConcurrentDeliveries bounds deliveries in flight, so one slow
+	// endpoint cannot hold up the others.
+	maxConcurrentDeliveries = 4
+	// maxErrorBodyBytes bounds the response body kept for a failed request.
+	maxErrorBodyBytes = 256
+)
+
+// Deps holds external dependencies injected at construction time.
+type Deps struct {
+	// Do sends one request. Optional: defaults to an http.Client with
+	// RequestTimeout.
+	Do func(*http.Request) (*http.Response, error)
+
+	// Backoff returns the wait before retry attempt (1 for the first retry).
+	// Optional: defaults to 2s, 4s, 8s.
+	Backoff func(attempt int) time.Duration
+
+	// Now returns the current time. Optional: defaults to time.Now.
+	Now func() time.Time
+}
+
+type delivery struct {
+	endpoint Endpoint
+	event    string
+	id       string
+	body     []byte
+}
+
+// Service matches runtime events against endpoints and delivers them.
+//
+// Thread-safety: endpoints is copy-on-write so Notify reads it without
+// locking; queue is a channel. No external locking is required.
+type Service struct {
+	deps      Deps
+	endpoints atomic.Pointer[[]Endpoint]
+	queue     chan delivery
+}
+
+// NewService creates a webhook service without endpoints.
+func NewService(deps Deps) *Service {
+	if deps.Do == nil {
+		client := &http.Client{Timeout: RequestTimeout}
+		deps.Do = client.Do
+	}
+	if deps.Backoff == nil {
+		deps.Backoff = defaultBackoff
+	}
+	if deps.Now == nil {
+		deps.Now = time.Now
+	}
+	return &Service{
+		deps:  deps,
+		queue: make(chan delivery, queueSize),
+	}
+}
+
+func defaultBackoff(attempt int) time.Duration {
+	return time.Duration(1<<attempt) * time.Second
+}
+
+// SetEndpoints replaces the endpoints. Deliveries already queued are still
+// sent to the endpoint they were queued for.
+func (s *Service) SetEndpoints(endpoints []Endpoint) {
+	next := slices.Clone(endpoints)
+	s.endpoints.Store(&next)
+}
+
+// Notify queues event for every endpoint subscribed to it. It never blocks:
+// when the queue is full the delivery is dropped with a warning.
+func (s *Service) Notify(event string, payload any) {
+	current := s.endpoints.Load()
+	if current == nil || len(*current) == 0 {
+		return
+	}
+	var data []byte
+	for _, endpoint := range *current {
+		if !slices.Contains(endpoint.Events, event) {
+			continue
+		}
+		if data == nil {
+			encoded, err := json.Marshal(payload)
+			if err != nil {
+				slog.Warn("[WARN-WEBHOOK] failed to encode event payload", "event", event, "error", err)
+				return
+			}
+			data = encoded
+		}
+		d, err := s.newDelivery(endpoint, event, data)
+		if err != nil {
+			slog.Warn("[WARN-WEBHOOK] failed to build delivery", "event", event, "error", err)
+			return
+		}
+		select {
+		case s.queue <- d:
+		default:
+			slog.Warn("[WARN-WEBHOOK] delivery queue full, dropping event", "event", event, "url", redactURL(endpoint.URL))
+		}
+	}
+}
+
+func (s *Service) newDelivery(endpoint Endpoint, event string, data []byte) (delivery, error) {
+	id, err := newDeliveryID()
+	if err != nil {
+		return delivery{}, err
+	}
+	body, err := json.Marshal(Body{
+		Event:      event,
+		DeliveryID: id,
+		Timestamp:  s.deps.Now().UTC(),
+		Text:       summaryText(event, data),
+		Data:       data,
+	})
+	if err != nil {
+		return delivery{}, err
+	}
+	return delivery{endpoint: endpoint, event: event, id: id, body: body}, nil
+}
+
+// Run sends queued deliveries until ctx is cancelled, then waits for the
+// deliveries in flight. Deliveries still queued at that point are dropped.
+func (s *Service) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	slots := make(chan struct{}, maxConcurrentDeliveries)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case d := <-s.queue:
+			select {
+			case <-ctx.Done():
+				return
+			case slots <- struct{}{}:
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() { <-slots }()
+				s.deliver(ctx, d)
+			}()
+		}
+	}
+}
+
+// deliver POSTs d, retrying network errors, 429, and 5xx responses.
+func (s *Service) deliver(ctx context.Context, d delivery) {
+	var lastErr error
+	for attempt := range MaxAttempts {
+		if attempt > 0 {
+			timer := time.NewTimer(s.deps.Backoff(attempt))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+		}
+		retry, err := s.post(ctx, d)
+		if err == nil {
+			slog.Debug("[DEBUG-WEBHOOK] delivered", "event", d.event, "delivery", d.id, "attempts", attempt+1)
+			return
+		}
+		lastErr = err
+		if !retry || ctx.Err() != nil {
+			break
+		}
+	}
+	slog.Warn("[WARN-WEBHOOK] delivery failed",
+		"event", d.event, "delivery", d.id, "url", redactURL(d.endpoint.URL), "error", lastErr)
+}
+
+// post sends one request. retry reports whether a failure is transient.
+func (s *Service) post(ctx context.Context, d delivery) (retry bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.endpoint.URL, bytes.NewReader(d.body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "myT-x-webhook")
+	req.Header.Set(EventHeader, d.event)
+	req.Header.Set(DeliveryHeader, d.id)
+	if d.endpoint.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(d.endpoint.Secret, d.body))
+	}
+	resp, err := s.deps.Do(req)
+	if err != nil {
+		// url.Error repeats the full URL, which may hold a credential.
+		if urlErr, ok := errors.AsType[*url.Error](err); ok {
+			err = urlErr.Err
+		}
+		return true, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return false, nil
+	}
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+	retry = resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, bytes.TrimSpace(detail))
+}
+
+// Sign returns the SignatureHeader value of body for secret.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// redactURL keeps the scheme and host of rawURL for logs. Webhook URLs often
+// carry their credential in the path or query.
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "(invalid URL)"
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+func newDeliveryID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b[:]), nil
+}
+
+// summaryText builds Body.Text from the payload's own summary field when it
+// has one, and otherwise from the event name and the session it names.
+func summaryText(event string, data []byte) string {
+	var fields map[string]any
+	if json.Unmarshal(data, &fields) == nil {
+		if v, ok := fields["summary"].(string); ok && v != "" {
+			return "[myT-x] " + v
+		}
+		for _, key := range []string{"sessionName", "session_name", "session"} {
+			if v, ok := fields[key].(string); ok && v != "" {
+				return "[myT-x] " + event + " (session " + strconv.Quote(v) + ")"
+			}
+		}
+	}
+	return "[myT-x] " + event
+}
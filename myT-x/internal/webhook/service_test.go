@@ -0,0 +1,213 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+// recordingServer answers with statuses in order (200 once exhausted) and
+// records every request.
+type recordingServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	statuses []int
+	requests []recordedRequest
+	received chan struct{}
+}
+
+func newRecordingServer(t *testing.T, statuses ...int) *recordingServer {
+	t.Helper()
+	rs := &recordingServer{statuses: statuses, received: make(chan struct{}, 16)}
+	rs.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		rs.mu.Lock()
+		rs.requests = append(rs.requests, recordedRequest{header: r.Header.Clone(), body: body})
+		status := http.StatusOK
+		if len(rs.statuses) > 0 {
+			status, rs.statuses = rs.statuses[0], rs.statuses[1:]
+		}
+		rs.mu.Unlock()
+		w.WriteHeader(status)
+		rs.received <- struct{}{}
+	}))
+	t.Cleanup(rs.Close)
+	return rs
+}
+
+func (rs *recordingServer) waitRequests(t *testing.T, n int) []recordedRequest {
+	t.Helper()
+	for range n {
+		select {
+		case <-rs.received:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for %d requests", n)
+		}
+	}
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	return append([]recordedRequest(nil), rs.requests...)
+}
+
+func startService(t *testing.T, deps Deps) *Service {
+	t.Helper()
+	if deps.Backoff == nil {
+		deps.Backoff = func(int) time.Duration { return time.Millisecond }
+	}
+	svc := NewService(deps)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		svc.Run(ctx)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	return svc
+}
+
+func TestNotifyDeliversSignedBody(t *testing.T) {
+	server := newRecordingServer(t)
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	svc := startService(t, Deps{Now: func() time.Time { return now }})
+	svc.SetEndpoints([]Endpoint{
+		{URL: server.URL, Events: []string{"worktree:setup-complete"}, Secret: "s3cret"},
+	})
+
+	svc.Notify("tmux:pane-focused", map[string]any{"paneId": "%1"})
+	svc.Notify("worktree:setup-complete", map[string]any{"sessionName": "feature", "success": true})
+
+	requests := server.waitRequests(t, 1)
+	if len(requests) != 1 {
+		t.Fatalf("requests = %d, want 1 (unsubscribed event must not be sent)", len(requests))
+	}
+	req := requests[0]
+	if got := req.header.Get(EventHeader); got != "worktree:setup-complete" {
+		t.Fatalf("%s = %q", EventHeader, got)
+	}
+	if got, want := req.header.Get(SignatureHeader), Sign("s3cret", req.body); got != want {
+		t.Fatalf("%s = %q, want %q", SignatureHeader, got, want)
+	}
+	var body Body
+	if err := json.Unmarshal(req.body, &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Event != "worktree:setup-complete" || body.DeliveryID != req.header.Get(DeliveryHeader) ||
+		!body.Timestamp.Equal(now) || body.Text != `[myT-x] worktree:setup-complete (session "feature")` {
+		t.Fatalf("body = %+v", body)
+	}
+	if string(body.Data) != `{"sessionName":"feature","success":true}` {
+		t.Fatalf("body.Data = %s", body.Data)
+	}
+}
+
+func TestNotifyWithoutSecretIsUnsigned(t *testing.T) {
+	server := newRecordingServer(t)
+	svc := startService(t, Deps{})
+	svc.SetEndpoints([]Endpoint{{URL: server.URL, Events: []string{"pane:command-finished"}}})
+
+	svc.Notify("pane:command-finished", map[string]any{"exitCode": 1})
+
+	requests := server.waitRequests(t, 1)
+	if got := requests[0].header.Get(SignatureHeader); got != "" {
+		t.Fatalf("%s = %q, want none", SignatureHeader, got)
+	}
+}
+
+func TestDeliverRetriesTransientFailures(t *testing.T) {
+	server := newRecordingServer(t, http.StatusBadGateway, http.StatusTooManyRequests)
+	svc := startService(t, Deps{})
+	svc.SetEndpoints([]Endpoint{{URL: server.URL, Events: []string{"worktree:cleanup-failed"}}})
+
+	svc.Notify("worktree:cleanup-failed", map[string]any{"sessionName": "s"})
+
+	requests := server.waitRequests(t, 3)
+	id := requests[0].header.Get(DeliveryHeader)
+	for i, req := range requests {
+		if got := req.header.Get(DeliveryHeader); got != id {
+			t.Fatalf("request %d delivery = %q, want %q for every retry", i, got, id)
+		}
+	}
+}
+
+func TestDeliverDoesNotRetryClientErrors(t *testing.T) {
+	var mu sync.Mutex
+	calls := 0
+	svc := NewService(Deps{
+		Do: func(*http.Request) (*http.Response, error) {
+			mu.Lock()
+			calls++
+			mu.Unlock()
+			return &http.Response{StatusCode: http.StatusBadRequest, Body: io.NopCloser(strings.NewReader("no_text"))}, nil
+		},
+		Backoff: func(int) time.Duration { return 0 },
+	})
+	svc.deliver(context.Background(), delivery{endpoint: Endpoint{URL: "https://example.invalid/hook"}, event: "e"})
+	if calls != 1 {
+		t.Fatalf("calls = %d, want 1", calls)
+	}
+}
+
+func TestDeliverGivesUpAfterMaxAttempts(t *testing.T) {
+	calls := 0
+	svc := NewService(Deps{
+		Do: func(*http.Request) (*http.Response, error) {
+			calls++
+			return nil, errors.New("connection refused")
+		},
+		Backoff: func(int) time.Duration { return 0 },
+	})
+	svc.deliver(context.Background(), delivery{endpoint: Endpoint{URL: "https://example.invalid/hook"}, event: "e"})
+	if calls != MaxAttempts {
+		t.Fatalf("calls = %d, want %d", calls, MaxAttempts)
+	}
+}
+
+func TestNotifyDropsWhenQueueFull(t *testing.T) {
+	svc := NewService(Deps{}) // Run is not started, so nothing drains the queue.
+	svc.SetEndpoints([]Endpoint{{URL: "https://example.invalid/hook", Events: []string{"e"}}})
+	for range queueSize + 10 {
+		svc.Notify("e", nil)
+	}
+	if got := len(svc.queue); got != queueSize {
+		t.Fatalf("queued = %d, want %d", got, queueSize)
+	}
+}
+
+func TestSummaryText(t *testing.T) {
+	tests := []struct {
+		data string
+		want string
+	}{
+		{`{"session_name":"api"}`, `[myT-x] e (session "api")`},
+		{`{"paneId":"%1"}`, `[myT-x] e`},
+		{`{"summary":"3 sessions active","session_name":"api"}`, `[myT-x] 3 sessions active`},
+		{`null`, `[myT-x] e`},
+	}
+	for _, tt := range tests {
+		if got := summaryText("e", []byte(tt.data)); got != tt.want {
+			t.Errorf("summaryText(%s) = %q, want %q", tt.data, got, tt.want)
+		}
+	}
+}
+
+func TestRedactURL(t *testing.T) {
+	if got := redactURL("https://hooks.slack.com/services/T000/B000/XXXX?token=1"); got != "https://hooks.slack.com" {
+		t.Fatalf("redactURL() = %q", got)
+	}
+}
@@ -0,0 +1,36 @@
+package webhook
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	// EventHeader names the event of a delivery.
+	EventHeader = "X-MyTX-Event"
+	// DeliveryHeader carries the delivery ID, identical across retries.
+	DeliveryHeader = "X-MyTX-Delivery"
+	// SignatureHeader carries "sha256=<hex>", the HMAC-SHA256 of the body
+	// keyed with the endpoint secret. It is omitted when there is no secret.
+	SignatureHeader = "X-MyTX-Signature-256"
+)
+
+// Endpoint is a URL that receives the listed events.
+type Endpoint struct {
+	URL string
+	// Events lists the runtime event names delivered to URL.
+	Events []string
+	// Secret signs each body when non-empty.
+	Secret string
+}
+
+// Body is the JSON document POSTed for one event.
+type Body struct {
+	Event      string    `json:"event"`
+	DeliveryID string    `json:"delivery_id"`
+	Timestamp  time.Time `json:"timestamp"`
+	// Text is a one-line summary. Chat services such as Slack and Teams
+	// display it as the message.
+	Text string          `json:"text"`
+	Data json.RawMessage `json:"data"`
+}
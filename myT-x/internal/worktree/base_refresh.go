@@ -0,0 +1,302 @@
+package worktree
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"myT-x/internal/config"
+	gitpkg "myT-x/internal/git"
+)
+
+// BaseAdvancedEvent carries a BaseBranchStatus when the base branch of a
+// worktree session gained commits the session's branch does not contain.
+const BaseAdvancedEvent = "worktree:base-advanced"
+
+// BaseRefreshedEvent carries a BaseRefreshResult after a rebase or merge onto
+// the base branch succeeded or was aborted on conflicts.
+const BaseRefreshedEvent = "worktree:base-refreshed"
+
+// Triggers of a base branch refresh, reported in BaseRefreshResult.
+const (
+	BaseRefreshTriggerManual   = "manual"
+	BaseRefreshTriggerFocus    = "focus"
+	BaseRefreshTriggerSchedule = "schedule"
+)
+
+// baseFocusCheckCooldown bounds how often switching to a session fetches its
+// base branch.
+const baseFocusCheckCooldown = time.Minute
+
+// BaseBranchStatus compares a worktree branch with its base branch.
+type BaseBranchStatus struct {
+	SessionName string `json:"session_name"`
+	BaseBranch  string `json:"base_branch"`
+	// BaseRef is the ref compared against: the upstream of BaseBranch
+	// ("origin/main") when it has one, BaseBranch itself otherwise.
+	BaseRef    string `json:"base_ref"`
+	BaseCommit string `json:"base_commit"`
+	// Behind is the number of commits of BaseRef the branch does not contain.
+	Behind int `json:"behind"`
+}
+
+// BaseRefreshResult is the outcome of a rebase or merge onto the base branch.
+type BaseRefreshResult struct {
+	SessionName string `json:"session_name"`
+	BaseRef     string `json:"base_ref"`
+	Strategy    string `json:"strategy"`
+	Trigger     string `json:"trigger"`
+	// Behind is the number of base commits the branch lacked before.
+	Behind int `json:"behind"`
+	// Refreshed is false when the branch was already up to date or when the
+	// rebase or merge conflicted.
+	Refreshed bool `json:"refreshed"`
+	// Conflicts lists the files that conflicted. The rebase or merge is then
+	// aborted, leaving the branch as it was.
+	Conflicts []string `json:"conflicts"`
+}
+
+// baseBranchCheck is the last base branch check of one session.
+type baseBranchCheck struct {
+	at time.Time
+	// notifiedCommit is the base commit last reported by BaseAdvancedEvent.
+	notifiedCommit string
+}
+
+// CheckBaseBranch fetches the base branch of the session's worktree and
+// reports how far the worktree branch is behind it. A failed fetch is logged
+// and the last fetched state is compared instead, so checks work offline.
+func (s *Service) CheckBaseBranch(sessionName string) (BaseBranchStatus, error) {
+	status, _, err := s.checkBaseBranch(sessionName)
+	return status, err
+}
+
+func (s *Service) checkBaseBranch(sessionName string) (BaseBranchStatus, *gitpkg.Repository, error) {
+	sessionName = strings.TrimSpace(sessionName)
+	if sessionName == "" {
+		return BaseBranchStatus{}, nil, errors.New("session name is required")
+	}
+	worktreeInfo, err := s.requireWorktreeInfo(sessionName)
+	if err != nil {
+		return BaseBranchStatus{}, nil, err
+	}
+	if worktreeInfo.IsDetached {
+		return BaseBranchStatus{}, nil, fmt.Errorf("session %s is on a detached HEAD", sessionName)
+	}
+	baseBranch := strings.TrimSpace(worktreeInfo.BaseBranch)
+	if baseBranch == "" {
+		return BaseBranchStatus{}, nil, fmt.Errorf("session %s has no base branch", sessionName)
+	}
+	wtRepo, err := gitpkg.Open(worktreeInfo.Path)
+	if err != nil {
+		return BaseBranchStatus{}, nil, fmt.Errorf("failed to open worktree: %w", err)
+	}
+	ref, remote, err := wtRepo.ResolveBaseRef(baseBranch)
+	if err != nil {
+		return BaseBranchStatus{}, nil, err
+	}
+	if remote != "" {
+		if err := wtRepo.Fetch(remote); err != nil {
+			slog.Warn("[WARN-GIT] failed to fetch base branch, comparing with the last fetched state",
+				"session", sessionName, "remote", remote, "error", err)
+		}
+	}
+	commit, err := wtRepo.ResolveCommit(ref)
+	if err != nil {
+		return BaseBranchStatus{}, nil, err
+	}
+	behind, err := wtRepo.CountCommitsBehind(ref)
+	if err != nil {
+		return BaseBranchStatus{}, nil, err
+	}
+	return BaseBranchStatus{
+		SessionName: sessionName,
+		BaseBranch:  baseBranch,
+		BaseRef:     ref,
+		BaseCommit:  commit,
+		Behind:      behind,
+	}, wtRepo, nil
+}
+
+// RefreshBaseBranch rebases the session's branch onto its base branch, or
+// merges the base branch into it, with strategy gitpkg.IntegrateRebase or
+// gitpkg.IntegrateMerge. The worktree must be clean and not shared with other
+// sessions. Conflicts abort the operation and are reported in the result and
+// by BaseRefreshedEvent.
+func (s *Service) RefreshBaseBranch(sessionName, strategy string) (BaseRefreshResult, error) {
+	return s.refreshBaseBranch(sessionName, strategy, BaseRefreshTriggerManual)
+}
+
+func (s *Service) refreshBaseBranch(sessionName, strategy, trigger string) (BaseRefreshResult, error) {
+	strategy = strings.ToLower(strings.TrimSpace(strategy))
+	if strategy != gitpkg.IntegrateRebase && strategy != gitpkg.IntegrateMerge {
+		return BaseRefreshResult{}, fmt.Errorf("refresh strategy must be %s or %s", gitpkg.IntegrateRebase, gitpkg.IntegrateMerge)
+	}
+	sessions, err := s.deps.RequireSessions()
+	if err != nil {
+		return BaseRefreshResult{}, err
+	}
+	status, wtRepo, err := s.checkBaseBranch(sessionName)
+	if err != nil {
+		return BaseRefreshResult{}, err
+	}
+	sessionName = status.SessionName
+	// Rewriting the branch would move it under every session in the worktree.
+	if err := s.requireUnsharedWorktree(sessions, sessionName, wtRepo.GetPath(), strategy+" onto the base branch"); err != nil {
+		return BaseRefreshResult{}, err
+	}
+	result := BaseRefreshResult{
+		SessionName: sessionName,
+		BaseRef:     status.BaseRef,
+		Strategy:    strategy,
+		Trigger:     trigger,
+		Behind:      status.Behind,
+	}
+	if status.Behind == 0 {
+		return result, nil
+	}
+
+	conflicts, err := wtRepo.IntegrateBase(status.BaseRef, strategy)
+	if errors.Is(err, gitpkg.ErrWorktreeHasUncommittedChanges) {
+		return BaseRefreshResult{}, fmt.Errorf("commit or stash the current changes before the %s", strategy)
+	}
+	if err != nil {
+		return BaseRefreshResult{}, err
+	}
+	if len(conflicts) > 0 {
+		slog.Warn("[WARN-GIT] base branch refresh conflicted, aborted",
+			"session", sessionName, "base", status.BaseRef, "strategy", strategy, "conflicts", conflicts)
+		result.Conflicts = conflicts
+	} else {
+		result.Refreshed = true
+		slog.Debug("[DEBUG-GIT] worktree branch refreshed from base",
+			"session", sessionName, "base", status.BaseRef, "strategy", strategy, "behind", status.Behind)
+	}
+	s.deps.Emitter.Emit(BaseRefreshedEvent, result)
+	return result, nil
+}
+
+// RefreshBaseOnFocus applies the worktree.base_refresh policy to a session
+// that was just switched to, when the policy checks on focus. Repeated
+// switches within a minute check only once.
+func (s *Service) RefreshBaseOnFocus(sessionName string) {
+	cfg := s.deps.GetConfigSnapshot()
+	policy := cfg.Worktree.BaseRefresh
+	if !cfg.Worktree.Enabled || policy == nil || !policy.OnFocus {
+		return
+	}
+	if !s.claimBaseCheck(sessionName, time.Now(), baseFocusCheckCooldown) {
+		return
+	}
+	s.applyBaseRefreshPolicy(*policy, sessionName, BaseRefreshTriggerFocus)
+}
+
+// RefreshDueBaseBranches applies the worktree.base_refresh policy to every
+// worktree session not checked within its interval_minutes. Call it
+// periodically; it does nothing while the policy has no schedule.
+func (s *Service) RefreshDueBaseBranches(now time.Time) {
+	sessions, err := s.deps.RequireSessions()
+	if err != nil {
+		return
+	}
+	snapshots := sessions.Snapshot()
+	live := make(map[string]struct{}, len(snapshots))
+	var candidates []string
+	for _, snap := range snapshots {
+		live[snap.Name] = struct{}{}
+		if snap.Worktree != nil && snap.Worktree.Path != "" && snap.Worktree.BaseBranch != "" && !snap.Worktree.IsDetached {
+			candidates = append(candidates, snap.Name)
+		}
+	}
+	s.baseRefreshMu.Lock()
+	for name := range s.baseChecks {
+		if _, ok := live[name]; !ok {
+			delete(s.baseChecks, name)
+		}
+	}
+	s.baseRefreshMu.Unlock()
+
+	cfg := s.deps.GetConfigSnapshot()
+	policy := cfg.Worktree.BaseRefresh
+	if !cfg.Worktree.Enabled || policy == nil || policy.Interval() <= 0 {
+		return
+	}
+	for _, name := range candidates {
+		if s.deps.IsShuttingDown() {
+			return
+		}
+		if s.claimBaseCheck(name, now, policy.Interval()) {
+			s.applyBaseRefreshPolicy(*policy, name, BaseRefreshTriggerSchedule)
+		}
+	}
+}
+
+// claimBaseCheck records a check of sessionName at now unless the last one
+// ran less than minGap before.
+func (s *Service) claimBaseCheck(sessionName string, now time.Time, minGap time.Duration) bool {
+	s.baseRefreshMu.Lock()
+	defer s.baseRefreshMu.Unlock()
+	if s.baseChecks == nil {
+		s.baseChecks = map[string]baseBranchCheck{}
+	}
+	check := s.baseChecks[sessionName]
+	if !check.at.IsZero() && now.Sub(check.at) < minGap {
+		return false
+	}
+	check.at = now
+	s.baseChecks[sessionName] = check
+	return true
+}
+
+// applyBaseRefreshPolicy checks the base branch of sessionName and, when it
+// advanced, reports it or integrates it as policy.Mode says. An automatic
+// rebase or merge that cannot run, e.g. because the worktree has
+// uncommitted changes, falls back to reporting.
+func (s *Service) applyBaseRefreshPolicy(policy config.WorktreeBaseRefreshConfig, sessionName, trigger string) {
+	if policy.Mode == config.BaseRefreshNotify {
+		s.notifyBaseAdvanced(sessionName)
+		return
+	}
+	result, err := s.refreshBaseBranch(sessionName, policy.Mode, trigger)
+	if err != nil {
+		slog.Warn("[WARN-GIT] automatic base branch refresh skipped",
+			"session", sessionName, "strategy", policy.Mode, "trigger", trigger, "error", err)
+		s.notifyBaseAdvanced(sessionName)
+		return
+	}
+	if len(result.Conflicts) > 0 {
+		// The conflicts were reported; report the base too so that the
+		// offer to refresh by hand stays visible.
+		s.notifyBaseAdvanced(sessionName)
+	}
+}
+
+// notifyBaseAdvanced emits BaseAdvancedEvent when the base branch of
+// sessionName is ahead, once per base commit.
+func (s *Service) notifyBaseAdvanced(sessionName string) {
+	status, _, err := s.checkBaseBranch(sessionName)
+	if err != nil {
+		slog.Debug("[DEBUG-GIT] base branch check failed", "session", sessionName, "error", err)
+		return
+	}
+	if status.Behind == 0 {
+		return
+	}
+	s.baseRefreshMu.Lock()
+	if s.baseChecks == nil {
+		s.baseChecks = map[string]baseBranchCheck{}
+	}
+	check := s.baseChecks[status.SessionName]
+	fresh := check.notifiedCommit != status.BaseCommit
+	check.notifiedCommit = status.BaseCommit
+	s.baseChecks[status.SessionName] = check
+	s.baseRefreshMu.Unlock()
+	if !fresh {
+		return
+	}
+	slog.Debug("[DEBUG-GIT] worktree base branch advanced",
+		"session", status.SessionName, "base", status.BaseRef, "behind", status.Behind)
+	s.deps.Emitter.Emit(BaseAdvancedEvent, status)
+}
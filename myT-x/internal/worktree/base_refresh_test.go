@@ -0,0 +1,145 @@
+package worktree
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"myT-x/internal/config"
+	gitpkg "myT-x/internal/git"
+	"myT-x/internal/testutil"
+	"myT-x/internal/tmux"
+)
+
+func TestBaseRefreshPolicyNotifiesThenRebases(t *testing.T) {
+	repoPath := testutil.CreateTempGitRepo(t)
+	repo, err := gitpkg.Open(repoPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	base, err := repo.CurrentBranch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wtPath := filepath.Join(t.TempDir(), "feature")
+	if err := repo.CreateWorktree(wtPath, "feature", base); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = repo.RemoveWorktreeForced(wtPath) })
+
+	sm := tmux.NewSessionManager()
+	if _, _, err := sm.CreateSession("feature", "0", 120, 40); err != nil {
+		t.Fatal(err)
+	}
+	if err := sm.SetWorktreeInfo("feature", &tmux.SessionWorktreeInfo{
+		Path: wtPath, RepoPath: repoPath, BranchName: "feature", BaseBranch: base,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	svc, emitter := newTestServiceForSetup(t)
+	svc.deps.RequireSessions = func() (*tmux.SessionManager, error) { return sm, nil }
+	policy := &config.WorktreeBaseRefreshConfig{Mode: config.BaseRefreshNotify, IntervalMinutes: 10}
+	svc.deps.GetConfigSnapshot = func() config.Config {
+		cfg := config.DefaultConfig()
+		cfg.Worktree.Enabled = true
+		cfg.Worktree.BaseRefresh = policy
+		return cfg
+	}
+	count := func(event string) int {
+		n := 0
+		for _, e := range emitter.emittedEvents {
+			if e.Name == event {
+				n++
+			}
+		}
+		return n
+	}
+
+	now := time.Now()
+	svc.RefreshDueBaseBranches(now)
+	if got := count(BaseAdvancedEvent); got != 0 {
+		t.Fatalf("base-advanced events with an up-to-date branch = %d, want 0", got)
+	}
+
+	if err := os.WriteFile(filepath.Join(repoPath, "base.txt"), []byte("base"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.CommitAll("advance base"); err != nil {
+		t.Fatal(err)
+	}
+	svc.RefreshDueBaseBranches(now.Add(time.Minute))
+	if got := count(BaseAdvancedEvent); got != 0 {
+		t.Fatalf("base-advanced events before the interval elapsed = %d, want 0", got)
+	}
+	svc.RefreshDueBaseBranches(now.Add(10 * time.Minute))
+	svc.RefreshDueBaseBranches(now.Add(20 * time.Minute))
+	if got := count(BaseAdvancedEvent); got != 1 {
+		t.Fatalf("base-advanced events = %d, want 1 per base commit", got)
+	}
+	status := emitter.findEvent(BaseAdvancedEvent).Payload.(BaseBranchStatus)
+	if status.SessionName != "feature" || status.BaseRef != base || status.Behind != 1 {
+		t.Fatalf("base-advanced payload = %+v", status)
+	}
+
+	// An automatic rebase of a dirty worktree falls back to reporting, which
+	// stays quiet for the already reported base commit.
+	policy.Mode = config.BaseRefreshRebase
+	wip := filepath.Join(wtPath, "wip.txt")
+	if err := os.WriteFile(wip, []byte("wip"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	svc.RefreshDueBaseBranches(now.Add(30 * time.Minute))
+	if got := count(BaseRefreshedEvent); got != 0 {
+		t.Fatalf("base-refreshed events with a dirty worktree = %d, want 0", got)
+	}
+	if _, err := svc.RefreshBaseBranch("feature", "squash"); err == nil {
+		t.Fatal("RefreshBaseBranch() should reject an unknown strategy")
+	}
+	if err := os.Remove(wip); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := svc.RefreshBaseBranch("feature", gitpkg.IntegrateRebase)
+	if err != nil {
+		t.Fatalf("RefreshBaseBranch() error = %v", err)
+	}
+	if !result.Refreshed || result.Behind != 1 || len(result.Conflicts) != 0 || result.Trigger != BaseRefreshTriggerManual {
+		t.Fatalf("RefreshBaseBranch() = %+v", result)
+	}
+	if got := count(BaseRefreshedEvent); got != 1 {
+		t.Fatalf("base-refreshed events = %d, want 1", got)
+	}
+	if _, err := os.Stat(filepath.Join(wtPath, "base.txt")); err != nil {
+		t.Fatalf("base.txt missing after rebase: %v", err)
+	}
+	status, err = svc.CheckBaseBranch("feature")
+	if err != nil || status.Behind != 0 {
+		t.Fatalf("CheckBaseBranch() after rebase = %+v, %v", status, err)
+	}
+}
+
+func TestRefreshBaseBranchRejectsSharedWorktree(t *testing.T) {
+	svc, _, sm := newSharedWorktreeTestService(t)
+	if err := sm.SetWorktreeInfo("api", &tmux.SessionWorktreeInfo{
+		Path: testutil.CreateTempGitRepo(t), RepoPath: `C:\repo`, BranchName: "main", BaseBranch: "HEAD",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.RefreshBaseBranch("solo", gitpkg.IntegrateMerge); err == nil {
+		t.Fatal("RefreshBaseBranch() of a session without a base branch should fail")
+	}
+	if _, err := svc.RefreshBaseBranch("api", gitpkg.IntegrateMerge); err != nil {
+		t.Fatalf("RefreshBaseBranch() of an unshared worktree error = %v", err)
+	}
+	info, _ := sm.GetWorktreeInfo("api")
+	if err := sm.SetWorktreeInfo("web", &tmux.SessionWorktreeInfo{
+		Path: info.Path, RepoPath: `C:\repo`, BranchName: "feature",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.RefreshBaseBranch("api", gitpkg.IntegrateMerge); err == nil || !strings.Contains(err.Error(), "shared") {
+		t.Fatalf("RefreshBaseBranch() of a shared worktree error = %v", err)
+	}
+}
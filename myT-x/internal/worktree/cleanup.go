@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	gitpkg "myT-x/internal/git"
+	"myT-x/internal/preopsnapshot"
 )
 
 // CleanupWorktree manually removes the worktree associated with a session.
@@ -26,16 +27,26 @@ func (s *Service) CleanupWorktree(sessionName string) error {
 	wtPath := worktreeInfo.Path
 	repoPath := worktreeInfo.RepoPath
 	cfg := s.deps.GetConfigSnapshot()
+	if err := s.requireUnsharedWorktree(sessions, sessionName, wtPath, "remove worktree"); err != nil {
+		return err
+	}
 
 	repo, err := gitpkg.Open(repoPath)
 	if err != nil {
 		return fmt.Errorf("failed to open repository: %w", err)
 	}
 
+	// A lock set by the user blocks removal even with ForceCleanup.
+	if err := releaseLockForRemoval(repo, wtPath); err != nil {
+		return err
+	}
+
 	if !cfg.Worktree.ForceCleanup {
 		if err := gitpkg.CheckWorktreeCleanForRemoval(wtPath); err != nil {
 			return fmt.Errorf("failed to remove worktree safely: %w", err)
 		}
+	} else {
+		s.captureBeforeRiskyOperation(preopsnapshot.OperationForceCleanup, sessionName, wtPath)
 	}
 
 	if err := repo.RemoveWorktree(wtPath); err != nil {
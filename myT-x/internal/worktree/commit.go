@@ -6,15 +6,17 @@ import (
 	"log/slog"
 	"strings"
 
+	"myT-x/internal/config"
 	gitpkg "myT-x/internal/git"
+	"myT-x/internal/preopsnapshot"
 	"myT-x/internal/tmux"
 )
 
 // CommitAndPushWorktree commits and/or pushes changes in the session's worktree.
-func (s *Service) CommitAndPushWorktree(sessionName, commitMessage string, push bool) error {
+func (s *Service) CommitAndPushWorktree(sessionName, commitMessage string, push bool) (CommitAndPushResult, error) {
 	sessionName = strings.TrimSpace(sessionName)
 	if sessionName == "" {
-		return errors.New("session name is required")
+		return CommitAndPushResult{}, errors.New("session name is required")
 	}
 	commitMessage = strings.TrimSpace(commitMessage)
 	if commitMessage == "" && push {
@@ -25,40 +27,63 @@ func (s *Service) CommitAndPushWorktree(sessionName, commitMessage string, push
 			"session", sessionName)
 	}
 	if _, err := s.deps.RequireSessions(); err != nil {
-		return err
+		return CommitAndPushResult{}, err
 	}
 
 	worktreeInfo, err := s.requireWorktreeInfo(sessionName)
 	if err != nil {
-		return err
+		return CommitAndPushResult{}, err
 	}
 	wtPath := worktreeInfo.Path
 
 	wtRepo, err := gitpkg.Open(wtPath)
 	if err != nil {
-		return fmt.Errorf("failed to open worktree: %w", err)
+		return CommitAndPushResult{}, fmt.Errorf("failed to open worktree: %w", err)
 	}
 
 	if commitMessage != "" {
-		if err := wtRepo.CommitAll(commitMessage); err != nil {
-			return fmt.Errorf("commit failed: %w", err)
+		var identityEnv map[string]string
+		identity, ok := config.ResolveGitIdentity(s.deps.GetConfigSnapshot(), config.GitIdentityFacts{
+			SessionName: sessionName,
+			RepoPath:    worktreeInfo.RepoPath,
+			WorkDir:     wtPath,
+		})
+		if ok {
+			identityEnv = identity.Env()
+		}
+		if err := wtRepo.CommitAllWithEnv(commitMessage, identityEnv); err != nil {
+			return CommitAndPushResult{}, fmt.Errorf("commit failed: %w", err)
 		}
 		slog.Debug("[DEBUG-GIT] worktree committed",
-			"session", sessionName, "message", commitMessage)
+			"session", sessionName, "message", commitMessage, "identity", identity.Email)
 	}
 
 	if push {
 		if err := wtRepo.Push(); err != nil {
-			return fmt.Errorf("push failed: %w", err)
+			return CommitAndPushResult{}, fmt.Errorf("push failed: %w", err)
 		}
 		slog.Debug("[DEBUG-GIT] worktree pushed", "session", sessionName)
 	}
 
-	return nil
+	var result CommitAndPushResult
+	if forgeCfg := s.deps.GetConfigSnapshot().Forge; push && forgeCfg != nil && forgeCfg.CreateOnPush {
+		pr, err := s.CreatePullRequestForSession(sessionName, CreatePullRequestOptions{})
+		if err != nil {
+			slog.Warn("[WARN-GIT] pushed, but opening the pull request failed", "session", sessionName, "error", err)
+			result.PullRequestError = err.Error()
+		} else {
+			result.PullRequest = &pr
+		}
+	}
+	return result, nil
 }
 
 // PromoteWorktreeToBranch promotes a detached HEAD worktree to a named branch.
-func (s *Service) PromoteWorktreeToBranch(sessionName string, branchName string) error {
+// With opts.RebaseOntoBase the detached commits are first rebased onto the
+// fetched base branch; a conflicting rebase is aborted and reported as a
+// *PromoteConflictError. Any later failure moves HEAD back to its prior
+// commit.
+func (s *Service) PromoteWorktreeToBranch(sessionName string, branchName string, opts PromoteWorktreeOptions) error {
 	sessionName = strings.TrimSpace(sessionName)
 	if sessionName == "" {
 		return errors.New("session name is required")
@@ -81,18 +106,40 @@ func (s *Service) PromoteWorktreeToBranch(sessionName string, branchName string)
 	wtPath := worktreeInfo.Path
 	repoPath := worktreeInfo.RepoPath
 	baseBranch := worktreeInfo.BaseBranch
+	if override := strings.TrimSpace(opts.BaseBranch); override != "" {
+		baseBranch = override
+	}
 	isDetached := worktreeInfo.IsDetached
 	if !isDetached {
 		return fmt.Errorf("session %s is not a detached worktree", sessionName)
 	}
+	if opts.RebaseOntoBase && baseBranch == "" {
+		return errors.New("base branch is required to rebase before promotion")
+	}
+	// Checking out a branch would switch it for every session in the worktree.
+	if err := s.requireUnsharedWorktree(sessions, sessionName, wtPath, "promote to branch"); err != nil {
+		return err
+	}
 
 	wtRepo, err := gitpkg.Open(wtPath)
 	if err != nil {
 		return fmt.Errorf("failed to open worktree: %w", err)
 	}
 
+	s.captureBeforeRiskyOperation(preopsnapshot.OperationPromoteBranch, sessionName, branchName)
+	priorHead := ""
+	if opts.RebaseOntoBase {
+		priorHead, err = rebaseDetachedOntoBase(wtRepo, baseBranch)
+		if err != nil {
+			return err
+		}
+	}
 	if err := wtRepo.CheckoutNewBranch(branchName); err != nil {
-		return fmt.Errorf("failed to create branch: %w", err)
+		err = fmt.Errorf("failed to create branch: %w", err)
+		if rollbackErr := restorePromotionHead(wtRepo, priorHead); rollbackErr != nil {
+			return fmt.Errorf("%w (git rollback also failed: %v)", err, rollbackErr)
+		}
+		return err
 	}
 
 	if err := sessions.SetWorktreeInfo(sessionName, &tmux.SessionWorktreeInfo{
@@ -102,15 +149,59 @@ func (s *Service) PromoteWorktreeToBranch(sessionName string, branchName string)
 		BaseBranch: baseBranch,
 		IsDetached: false,
 	}); err != nil {
-		if rollbackErr := rollbackPromotedWorktreeBranch(wtRepo, branchName); rollbackErr != nil {
+		rollbackErr := rollbackPromotedWorktreeBranch(wtRepo, branchName)
+		if rollbackErr == nil {
+			rollbackErr = restorePromotionHead(wtRepo, priorHead)
+		}
+		if rollbackErr != nil {
 			return fmt.Errorf("failed to update worktree info: %w (git rollback also failed: %v)", err, rollbackErr)
 		}
 		return fmt.Errorf("failed to update worktree info: %w", err)
 	}
 
 	slog.Debug("[DEBUG-GIT] worktree promoted to branch",
-		"session", sessionName, "branch", branchName, "path", wtPath)
+		"session", sessionName, "branch", branchName, "path", wtPath, "rebased", opts.RebaseOntoBase)
 
 	s.deps.RequestSnapshot(true)
 	return nil
 }
+
+// rebaseDetachedOntoBase fetches baseBranch and rebases the detached HEAD of
+// wtRepo onto it. It returns the commit HEAD pointed to before the rebase.
+func rebaseDetachedOntoBase(wtRepo *gitpkg.Repository, baseBranch string) (string, error) {
+	priorHead, err := wtRepo.ResolveCommit("HEAD")
+	if err != nil {
+		return "", err
+	}
+	ref, remote, err := wtRepo.ResolveBaseRef(baseBranch)
+	if err != nil {
+		return "", fmt.Errorf("invalid base branch: %w", err)
+	}
+	if remote != "" {
+		if err := wtRepo.Fetch(remote); err != nil {
+			return "", fmt.Errorf("failed to fetch base branch: %w", err)
+		}
+	}
+	conflicts, err := wtRepo.IntegrateBase(ref, gitpkg.IntegrateRebase)
+	if errors.Is(err, gitpkg.ErrWorktreeHasUncommittedChanges) {
+		return "", errors.New("commit or stash the current changes before rebasing onto the base branch")
+	}
+	if err != nil {
+		return "", err
+	}
+	if len(conflicts) > 0 {
+		slog.Warn("[WARN-GIT] rebase before promotion conflicted, aborted",
+			"path", wtRepo.GetPath(), "base", ref, "conflicts", conflicts)
+		return "", &PromoteConflictError{BaseRef: ref, Conflicts: conflicts}
+	}
+	return priorHead, nil
+}
+
+// restorePromotionHead moves HEAD back to priorHead after a rebase that
+// preceded a failed promotion. An empty priorHead means no rebase ran.
+func restorePromotionHead(wtRepo *gitpkg.Repository, priorHead string) error {
+	if priorHead == "" {
+		return nil
+	}
+	return wtRepo.CheckoutDetachedAt(priorHead)
+}
@@ -0,0 +1,125 @@
+package worktree
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	gitpkg "myT-x/internal/git"
+	"myT-x/internal/testutil"
+	"myT-x/internal/tmux"
+)
+
+// newDetachedPromotionFixture returns a service whose session "detached"
+// works in a detached worktree with one commit that writes file, while the
+// base branch gained a commit that writes baseFile.
+func newDetachedPromotionFixture(t *testing.T, file, baseFile string) (*Service, *tmux.SessionManager, *gitpkg.Repository, string) {
+	t.Helper()
+	repoPath := testutil.CreateTempGitRepo(t)
+	repo, err := gitpkg.Open(repoPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	base, err := repo.CurrentBranch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wtPath := filepath.Join(t.TempDir(), "detached")
+	if err := repo.CreateWorktreeDetached(wtPath, base); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = repo.RemoveWorktreeForced(wtPath) })
+	wtRepo, err := gitpkg.Open(wtPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct {
+		repo *gitpkg.Repository
+		dir  string
+		file string
+	}{{wtRepo, wtPath, file}, {repo, repoPath, baseFile}} {
+		if err := os.WriteFile(filepath.Join(c.dir, c.file), []byte(c.dir), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := c.repo.CommitAll("write " + c.file); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sm := tmux.NewSessionManager()
+	if _, _, err := sm.CreateSession("detached", "0", 120, 40); err != nil {
+		t.Fatal(err)
+	}
+	if err := sm.SetWorktreeInfo("detached", &tmux.SessionWorktreeInfo{
+		Path: wtPath, RepoPath: repoPath, IsDetached: true,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	svc, _ := newTestServiceForSetup(t)
+	svc.deps.RequireSessions = func() (*tmux.SessionManager, error) { return sm, nil }
+	return svc, sm, wtRepo, base
+}
+
+func TestPromoteWorktreeToBranchRebasesOntoBase(t *testing.T) {
+	svc, sm, wtRepo, base := newDetachedPromotionFixture(t, "feature.txt", "base.txt")
+
+	if err := svc.PromoteWorktreeToBranch("detached", "feature", PromoteWorktreeOptions{RebaseOntoBase: true}); err == nil {
+		t.Fatal("PromoteWorktreeToBranch() without a base branch should fail")
+	}
+	if err := svc.PromoteWorktreeToBranch("detached", "feature", PromoteWorktreeOptions{
+		RebaseOntoBase: true, BaseBranch: base,
+	}); err != nil {
+		t.Fatalf("PromoteWorktreeToBranch() error = %v", err)
+	}
+
+	if branch, err := wtRepo.CurrentBranch(); err != nil || branch != "feature" {
+		t.Fatalf("CurrentBranch() = %q, %v; want feature", branch, err)
+	}
+	if behind, err := wtRepo.CountCommitsBehind(base); err != nil || behind != 0 {
+		t.Fatalf("CountCommitsBehind(%s) = %d, %v; want 0", base, behind, err)
+	}
+	for _, file := range []string{"feature.txt", "base.txt"} {
+		if _, err := os.Stat(filepath.Join(wtRepo.GetPath(), file)); err != nil {
+			t.Fatalf("%s missing after promotion: %v", file, err)
+		}
+	}
+	info, err := sm.GetWorktreeInfo("detached")
+	if err != nil || info.IsDetached || info.BranchName != "feature" || info.BaseBranch != base {
+		t.Fatalf("worktree info = %+v, %v", info, err)
+	}
+}
+
+func TestPromoteWorktreeToBranchConflictRestoresHead(t *testing.T) {
+	svc, sm, wtRepo, base := newDetachedPromotionFixture(t, "shared.txt", "shared.txt")
+	priorHead, err := wtRepo.ResolveCommit("HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = svc.PromoteWorktreeToBranch("detached", "feature", PromoteWorktreeOptions{
+		RebaseOntoBase: true, BaseBranch: base,
+	})
+	var conflictErr *PromoteConflictError
+	if !errors.As(err, &conflictErr) {
+		t.Fatalf("PromoteWorktreeToBranch() error = %v, want *PromoteConflictError", err)
+	}
+	if conflictErr.BaseRef != base || !slices.Equal(conflictErr.Conflicts, []string{"shared.txt"}) {
+		t.Fatalf("conflict error = %+v", conflictErr)
+	}
+
+	if head, _ := wtRepo.ResolveCommit("HEAD"); head != priorHead {
+		t.Fatalf("HEAD = %s, want prior %s", head, priorHead)
+	}
+	if detached, err := wtRepo.IsDetachedHead(); err != nil || !detached {
+		t.Fatalf("IsDetachedHead() = %v, %v; want true", detached, err)
+	}
+	branches, err := wtRepo.ListBranches()
+	if err != nil || slices.Contains(branches, "feature") {
+		t.Fatalf("branches = %v, %v; want no feature branch", branches, err)
+	}
+	if info, _ := sm.GetWorktreeInfo("detached"); info == nil || !info.IsDetached || info.BaseBranch != "" {
+		t.Fatalf("worktree info = %+v, want it unchanged", info)
+	}
+}
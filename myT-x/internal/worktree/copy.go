@@ -3,6 +3,7 @@ package worktree
 import (
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
@@ -46,8 +47,11 @@ func (s *Service) copyConfigEntriesToWorktree(
 // CopyConfigFilesToWorktree copies configured files (e.g. .env) from the
 // repository root to the worktree. Returns a list of files that failed to copy.
 // Missing source files are silently skipped (common for optional files like .env).
-func (s *Service) CopyConfigFilesToWorktree(repoPath, wtPath string, files []string) []string {
-	return s.copyConfigEntriesToWorktree(repoPath, wtPath, files, "file", s.copyConfigFileToWorktree)
+// eolPolicy is a config.CopyFilesEOL* value; empty copies bytes verbatim.
+func (s *Service) CopyConfigFilesToWorktree(repoPath, wtPath string, files []string, eolPolicy string) []string {
+	return s.copyConfigEntriesToWorktree(repoPath, wtPath, files, "file", func(repoBase, wtBase, file string) bool {
+		return s.copyConfigFileToWorktree(repoBase, wtBase, file, eolPolicy)
+	})
 }
 
 func validateAndResolveSourceEntry(
@@ -85,7 +89,7 @@ func validateAndResolveSourceEntry(
 	return resolvedSrc, dstPath, true, false
 }
 
-func (s *Service) copyConfigFileToWorktree(repoBase, wtBase, file string) bool {
+func (s *Service) copyConfigFileToWorktree(repoBase, wtBase, file, eolPolicy string) bool {
 	resolvedSrc, dst, canProcess, failed := validateAndResolveSourceEntry(
 		repoBase, wtBase, file, "copy_files", "file",
 	)
@@ -106,7 +110,13 @@ func (s *Service) copyConfigFileToWorktree(repoBase, wtBase, file string) bool {
 
 	// Note: a TOCTOU window exists between destination validation and file open.
 	// This is acceptable because copy paths come from trusted local configuration.
-	if copyErr := s.copyFileByStreaming(resolvedSrc, dst); copyErr != nil {
+	var copyErr error
+	if eol := s.resolveCopyEOL(eolPolicy, repoBase, file); eol != "" {
+		copyErr = s.copyFileWithEOL(resolvedSrc, dst, eol)
+	} else {
+		copyErr = s.copyFileByStreaming(resolvedSrc, dst)
+	}
+	if copyErr != nil {
 		if errors.Is(copyErr, os.ErrNotExist) {
 			slog.Debug("[DEBUG-GIT] source file disappeared before copy_files stream copy, skipping",
 				"src", resolvedSrc, "dst", dst)
@@ -474,7 +484,12 @@ func (s *Service) copyFileByStreaming(srcPath, dstPath string) (retErr error) {
 		return fmt.Errorf("open source file: %w", openSrcErr)
 	}
 	defer closeFileAndJoinError(srcFile, "source file", &retErr)
+	return s.writeDestinationFile(dstPath, srcFile)
+}
 
+// writeDestinationFile writes src to dstPath and syncs it. A partially
+// written destination is removed on failure.
+func (s *Service) writeDestinationFile(dstPath string, src io.Reader) (retErr error) {
 	// Create destination files with owner-only permissions.
 	// We intentionally do not preserve source mode bits for copied config data.
 	dstFile, openDstErr := os.OpenFile(dstPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
@@ -492,7 +507,7 @@ func (s *Service) copyFileByStreaming(srcPath, dstPath string) (retErr error) {
 	}()
 	defer closeFileAndJoinError(dstFile, "destination file", &retErr)
 
-	if _, copyErr := s.deps.Copy.StreamCopy(dstFile, srcFile); copyErr != nil {
+	if _, copyErr := s.deps.Copy.StreamCopy(dstFile, src); copyErr != nil {
 		return fmt.Errorf("stream copy file: %w", copyErr)
 	}
 	if syncErr := s.deps.Copy.SyncFile(dstFile); syncErr != nil {
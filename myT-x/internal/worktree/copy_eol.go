@@ -0,0 +1,72 @@
+package worktree
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"myT-x/internal/config"
+	gitpkg "myT-x/internal/git"
+)
+
+// maxEOLConvertBytes caps the size of a copy_files entry that is converted
+// in memory. Larger files are copied verbatim; config files are far smaller.
+const maxEOLConvertBytes = 4 * 1024 * 1024
+
+// resolveCopyEOL returns the line ending (gitpkg.EOLLF or gitpkg.EOLCRLF) that
+// file should be converted to under policy, or "" to copy it verbatim.
+func (s *Service) resolveCopyEOL(policy, repoBase, file string) string {
+	switch policy {
+	case config.CopyFilesEOLLF:
+		return gitpkg.EOLLF
+	case config.CopyFilesEOLCRLF:
+		return gitpkg.EOLCRLF
+	case config.CopyFilesEOLGitAttributes:
+		eol, err := s.deps.Copy.EOLAttribute(repoBase, file)
+		if err != nil {
+			slog.Warn("[WARN-GIT] failed to read eol attribute for copy_files entry, copying verbatim",
+				"file", file, "error", err)
+			return ""
+		}
+		return eol
+	default:
+		return ""
+	}
+}
+
+// copyFileWithEOL copies srcPath to dstPath with its line endings converted to
+// eol. Binary-looking and oversized files are copied verbatim.
+func (s *Service) copyFileWithEOL(srcPath, dstPath, eol string) error {
+	srcFile, err := os.Open(srcPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return fmt.Errorf("open source file: %w", err)
+	}
+	data, readErr := io.ReadAll(io.LimitReader(srcFile, maxEOLConvertBytes+1))
+	if closeErr := srcFile.Close(); readErr == nil && closeErr != nil {
+		readErr = closeErr
+	}
+	if readErr != nil {
+		return fmt.Errorf("read source file: %w", readErr)
+	}
+	if len(data) > maxEOLConvertBytes || bytes.IndexByte(data, 0) >= 0 {
+		slog.Debug("[DEBUG-GIT] copy_files entry is large or binary, copying verbatim",
+			"src", srcPath, "eol", eol)
+		return s.copyFileByStreaming(srcPath, dstPath)
+	}
+	return s.writeDestinationFile(dstPath, bytes.NewReader(convertEOL(data, eol)))
+}
+
+// convertEOL rewrites every line ending in data to eol. Lone CRs are kept.
+func convertEOL(data []byte, eol string) []byte {
+	normalized := bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+	if eol != gitpkg.EOLCRLF {
+		return normalized
+	}
+	return bytes.ReplaceAll(normalized, []byte("\n"), []byte("\r\n"))
+}
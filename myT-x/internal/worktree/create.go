@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
@@ -44,6 +46,10 @@ func (s *Service) CreateSessionWithWorktree(
 		return tmux.SessionSnapshot{}, err
 	}
 	opts.BranchName = validatedBranchName
+	opts.StartupCommand = config.NormalizeStartupCommand(opts.StartupCommand)
+	if !config.IsValidStartupCommand(opts.StartupCommand) {
+		return tmux.SessionSnapshot{}, fmt.Errorf("startup command must be a single line of at most %d characters", config.MaxStartupCommandLen)
+	}
 	cfg := s.deps.GetConfigSnapshot()
 	createdName := ""
 	wtPath := ""
@@ -113,6 +119,7 @@ func (s *Service) CreateSessionWithWorktree(
 	}
 	wtPath = wtResult.WtPath
 	worktreeCreated = true
+	s.autoLockDetachableWorktree(repo, wtPath)
 
 	if wtResult.PullFailed {
 		s.deps.Emitter.Emit("worktree:pull-failed", map[string]any{
@@ -122,7 +129,7 @@ func (s *Service) CreateSessionWithWorktree(
 		})
 	}
 
-	createdName, err = s.deps.CreateSession(wtPath, sessionName, opts.EnableAgentTeam, opts.UseClaudeEnv, opts.UsePaneEnv)
+	createdName, err = s.deps.CreateSession(wtPath, sessionName, opts.EnableAgentTeam, opts.UseClaudeEnv, opts.UsePaneEnv, opts.IsolateShellHistory)
 	if err != nil {
 		return tmux.SessionSnapshot{}, err
 	}
@@ -146,7 +153,7 @@ func (s *Service) CreateSessionWithWorktree(
 	}
 
 	// Copy configured files (e.g. .env) from repo to worktree.
-	if copyFailures := s.CopyConfigFilesToWorktree(repoPath, wtPath, cfg.Worktree.CopyFiles); len(copyFailures) > 0 {
+	if copyFailures := s.CopyConfigFilesToWorktree(repoPath, wtPath, cfg.Worktree.CopyFiles, cfg.Worktree.CopyFilesEOL); len(copyFailures) > 0 {
 		slog.Warn("[WARN-GIT] failed to copy one or more configured files to worktree",
 			"session", createdName, "path", wtPath, "files", copyFailures)
 		s.deps.Emitter.Emit("worktree:copy-files-failed", map[string]any{
@@ -170,8 +177,22 @@ func (s *Service) CreateSessionWithWorktree(
 	// blocking setup scripts on copy failure would degrade the user experience
 	// for unrelated issues.
 
+	// Repo-provided scripts and commands run only for trusted directories.
+	setupScripts := cfg.Worktree.SetupScripts
+	startupCommand := opts.StartupCommand
+	if repoCfg := s.resolveRepoConfig(repoPath); repoCfg != nil {
+		setupScripts = append(slices.Clone(setupScripts), repoCfg.SetupScripts...)
+		if startupCommand == "" {
+			startupCommand = repoCfg.StartupCommand
+		}
+	}
+
+	// NOTE: The startup command may run while setup scripts are still in
+	// progress; scripts run in a separate process, not in the session pane.
+	s.runStartupCommand(createdName, wtPath, startupCommand, opts.RemainOnExit)
+
 	// Run setup scripts asynchronously if configured.
-	if len(cfg.Worktree.SetupScripts) > 0 {
+	if len(setupScripts) > 0 {
 		parentCtx := context.Background()
 		if appCtx := s.deps.RuntimeContext(); appCtx != nil {
 			parentCtx = appCtx
@@ -204,7 +225,7 @@ func (s *Service) CreateSessionWithWorktree(
 				defer func() {
 					s.deps.RecoverBackgroundPanic("worktree-setup-scripts", recover())
 				}()
-				s.runSetupScriptsWithTimeout(ctx, wtPath, createdName, cfg.Shell, cfg.Worktree.SetupScripts, setupTimeout)
+				s.runSetupScriptsWithTimeout(ctx, wtPath, createdName, config.ResolveShell(cfg, wtPath), setupScripts, setupTimeout)
 			}(setupScriptsCtx, cancel, setupScriptsDone, releaseTrackedCancel, skipSetupWorkerDone)
 		}
 	}
@@ -218,7 +239,9 @@ func (s *Service) CreateSessionWithWorktree(
 
 // CreateSessionWithExistingWorktree creates a session using an existing worktree.
 // No new worktree is created; the session opens in the given worktree path.
-// Returns an error if the worktree path is already in use by another session.
+// Returns an error if the worktree path is already in use by another session,
+// unless worktree.allow_shared is enabled. A session sharing the worktree
+// records the branch checked out now as its branch context.
 func (s *Service) CreateSessionWithExistingWorktree(
 	repoPath string,
 	sessionName string,
@@ -246,6 +269,10 @@ func (s *Service) CreateSessionWithExistingWorktree(
 	if worktreePath == "" {
 		return tmux.SessionSnapshot{}, errors.New("worktree path is required")
 	}
+	opts.StartupCommand = config.NormalizeStartupCommand(opts.StartupCommand)
+	if !config.IsValidStartupCommand(opts.StartupCommand) {
+		return tmux.SessionSnapshot{}, fmt.Errorf("startup command must be a single line of at most %d characters", config.MaxStartupCommandLen)
+	}
 	sessionName, releaseSessionName := s.reserveAvailableSessionName(sessionName)
 	defer releaseSessionName()
 	cfg := s.deps.GetConfigSnapshot()
@@ -261,8 +288,9 @@ func (s *Service) CreateSessionWithExistingWorktree(
 		return tmux.SessionSnapshot{}, fmt.Errorf("failed to stat worktree path %s: %w", worktreePath, err)
 	}
 
-	// Prevent branch mixing: reject if another session already uses this worktree.
-	if conflict := s.deps.FindSessionByWorktreePath(worktreePath); conflict != "" {
+	// Prevent branch mixing: reject if another session already uses this
+	// worktree. Shared worktrees are watched for conflicting checkouts instead.
+	if conflict := s.deps.FindSessionByWorktreePath(worktreePath); conflict != "" && !cfg.Worktree.AllowShared {
 		return tmux.SessionSnapshot{}, fmt.Errorf(
 			"worktree path is already in use by session %q: %s", conflict, worktreePath)
 	}
@@ -278,6 +306,7 @@ func (s *Service) CreateSessionWithExistingWorktree(
 	if err != nil {
 		return tmux.SessionSnapshot{}, fmt.Errorf("failed to check HEAD state: %w", err)
 	}
+	s.autoLockDetachableWorktree(wtRepo, worktreePath)
 	if isDetached {
 		branchName = ""
 	} else {
@@ -300,7 +329,7 @@ func (s *Service) CreateSessionWithExistingWorktree(
 		s.deps.RequestSnapshot(true)
 	}()
 
-	createdName, err = s.deps.CreateSession(worktreePath, sessionName, opts.EnableAgentTeam, opts.UseClaudeEnv, opts.UsePaneEnv)
+	createdName, err = s.deps.CreateSession(worktreePath, sessionName, opts.EnableAgentTeam, opts.UseClaudeEnv, opts.UsePaneEnv, opts.IsolateShellHistory)
 	if err != nil {
 		return tmux.SessionSnapshot{}, err
 	}
@@ -321,6 +350,11 @@ func (s *Service) CreateSessionWithExistingWorktree(
 	if err := s.deps.StoreRootPath(createdName, repoPath); err != nil {
 		return tmux.SessionSnapshot{}, err
 	}
+	startupCommand := opts.StartupCommand
+	if repoCfg := s.resolveRepoConfig(repoPath); repoCfg != nil && startupCommand == "" {
+		startupCommand = repoCfg.StartupCommand
+	}
+	s.runStartupCommand(createdName, worktreePath, startupCommand, opts.RemainOnExit)
 	snapshot, retErr = s.deps.ActivateCreatedSession(createdName)
 	if retErr == nil {
 		s.deps.RequestSnapshot(true)
@@ -328,6 +362,51 @@ func (s *Service) CreateSessionWithExistingWorktree(
 	return snapshot, retErr
 }
 
+// trackSetupRun marks setup scripts as running in wtPath until the returned
+// function is called.
+func (s *Service) trackSetupRun(wtPath string) func() {
+	dir := filepath.Clean(wtPath)
+	s.setupMu.Lock()
+	if s.setupDirs == nil {
+		s.setupDirs = map[string]int{}
+	}
+	s.setupDirs[dir]++
+	s.setupMu.Unlock()
+	return func() {
+		s.setupMu.Lock()
+		defer s.setupMu.Unlock()
+		if s.setupDirs[dir]--; s.setupDirs[dir] <= 0 {
+			delete(s.setupDirs, dir)
+		}
+	}
+}
+
+// SetupRunning reports whether setup scripts are running in the worktree at
+// dir, e.g. the initial install after the worktree was created.
+func (s *Service) SetupRunning(dir string) bool {
+	s.setupMu.Lock()
+	defer s.setupMu.Unlock()
+	return s.setupDirs[filepath.Clean(dir)] > 0
+}
+
+// RunSetupScript runs one setup script in a worktree directory with the
+// shell and per-script timeout that worktree creation uses, e.g. when a
+// setup watcher re-runs it. The combined output is returned with a failure.
+func (s *Service) RunSetupScript(ctx context.Context, dir, script string) ([]byte, error) {
+	script = strings.TrimSpace(script)
+	if script == "" {
+		return nil, errors.New("setup script is empty")
+	}
+	cfg := s.deps.GetConfigSnapshot()
+	shell := config.ResolveShell(cfg, dir)
+	if strings.TrimSpace(shell) == "" {
+		shell = "powershell.exe"
+	}
+	ctx, cancel := context.WithTimeout(ctx, cfg.Worktree.SetupScriptTimeout())
+	defer cancel()
+	return s.deps.ExecuteSetupCommand(ctx, shell, shellExecFlag(shell), script, dir)
+}
+
 // runSetupScriptsWithParentContext runs setup scripts sequentially with the
 // default per-script timeout. Tests call this helper directly.
 func (s *Service) runSetupScriptsWithParentContext(parentCtx context.Context, wtPath, sessionName, shell string, scripts []string) {
@@ -349,6 +428,7 @@ func (s *Service) runSetupScriptsWithTimeout(
 	if strings.TrimSpace(shell) == "" {
 		shell = "powershell.exe"
 	}
+	defer s.trackSetupRun(wtPath)()
 
 	// If parent context is not provided, use app context so scripts are cancelled
 	// on app shutdown. When app context is nil (startup race), fall back to
@@ -0,0 +1,230 @@
+package worktree
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	gitpkg "myT-x/internal/git"
+	"myT-x/internal/tmux"
+)
+
+// ExternalWorktreeDetectedEvent carries an ExternalWorktree when a worktree
+// created outside myT-x, e.g. by `git worktree add` in a terminal, appears in
+// a repository that has sessions.
+const ExternalWorktreeDetectedEvent = "worktree:external-detected"
+
+// ExternalWorktree is a linked worktree that myT-x did not create and that no
+// session uses. AdoptExternalWorktree opens it as a worktree session.
+type ExternalWorktree struct {
+	RepoPath   string `json:"repo_path"`
+	Path       string `json:"path"`
+	BranchName string `json:"branch_name"`
+	IsDetached bool   `json:"is_detached"`
+}
+
+// externalWorktreeScan is the last scan of one repository by
+// DetectExternalWorktrees.
+type externalWorktreeScan struct {
+	// stamp is the modification time of the worktree admin directory.
+	stamp time.Time
+	// paths are the normalized paths of the external worktrees found.
+	paths map[string]struct{}
+}
+
+// ListExternalWorktrees returns the worktrees of repoPath that were created
+// outside myT-x (not under its .wt/ directory) and are not used by a session.
+func (s *Service) ListExternalWorktrees(repoPath string) ([]ExternalWorktree, error) {
+	repoPath = strings.TrimSpace(repoPath)
+	if repoPath == "" {
+		return nil, errors.New("repository path is required")
+	}
+	if !s.deps.GetConfigSnapshot().Worktree.Enabled {
+		return nil, nil
+	}
+	sessions, err := s.deps.RequireSessions()
+	if err != nil {
+		return nil, err
+	}
+	return collectExternalWorktrees(repoPath, sessions.Snapshot())
+}
+
+func collectExternalWorktrees(repoPath string, snapshots []tmux.SessionSnapshot) ([]ExternalWorktree, error) {
+	repo, err := gitpkg.Open(repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+	worktrees, err := repo.ListWorktreesWithInfo()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	used := make(map[string]struct{}, len(snapshots))
+	for _, snap := range snapshots {
+		if snap.Worktree != nil && snap.Worktree.Path != "" {
+			used[normalizeWorktreePath(snap.Worktree.Path)] = struct{}{}
+		}
+	}
+	managedDir := normalizeWorktreePath(gitpkg.GenerateWorktreeDirPath(repo.GetPath())) + string(filepath.Separator)
+
+	var external []ExternalWorktree
+	for _, wt := range worktrees {
+		key := normalizeWorktreePath(wt.Path)
+		if wt.IsMain || strings.HasPrefix(key, managedDir) {
+			continue
+		}
+		if _, inUse := used[key]; inUse {
+			continue
+		}
+		// git keeps listing a worktree whose directory was deleted until it
+		// is pruned; there is nothing to adopt.
+		if _, err := os.Stat(wt.Path); err != nil {
+			continue
+		}
+		external = append(external, ExternalWorktree{
+			RepoPath:   repo.GetPath(),
+			Path:       wt.Path,
+			BranchName: wt.Branch,
+			IsDetached: wt.IsDetached,
+		})
+	}
+	return external, nil
+}
+
+// worktreeAdminStamp returns the modification time of the worktree admin
+// directory (.git/worktrees) of repoPath, which changes when a worktree is
+// added or removed. The stamp is zero while the directory does not exist.
+// ok is false when it cannot be checked, e.g. when .git is a file because
+// repoPath is itself a linked worktree.
+func worktreeAdminStamp(repoPath string) (stamp time.Time, ok bool) {
+	gitDir, err := os.Stat(filepath.Join(repoPath, ".git"))
+	if errors.Is(err, os.ErrNotExist) {
+		return time.Time{}, true
+	}
+	if err != nil || !gitDir.IsDir() {
+		return time.Time{}, false
+	}
+	info, err := os.Stat(filepath.Join(repoPath, ".git", "worktrees"))
+	if errors.Is(err, os.ErrNotExist) {
+		return time.Time{}, true
+	}
+	if err != nil {
+		return time.Time{}, false
+	}
+	return info.ModTime(), true
+}
+
+// sessionRepoPaths returns the repositories of the sessions: the repository
+// of worktree sessions and the root directory of the others.
+func sessionRepoPaths(snapshots []tmux.SessionSnapshot) []string {
+	seen := map[string]struct{}{}
+	var paths []string
+	for _, snap := range snapshots {
+		path := snap.RootPath
+		if snap.Worktree != nil && snap.Worktree.RepoPath != "" {
+			path = snap.Worktree.RepoPath
+		}
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		key := normalizeWorktreePath(path)
+		if _, dup := seen[key]; dup {
+			continue
+		}
+		seen[key] = struct{}{}
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+	return paths
+}
+
+// DetectExternalWorktrees scans the repositories of the sessions and emits
+// ExternalWorktreeDetectedEvent for each external worktree that appeared
+// since the previous scan. The first scan of a repository only records its
+// worktrees; ListExternalWorktrees reports them. A repository is listed
+// again only when its worktree admin directory changed.
+func (s *Service) DetectExternalWorktrees() {
+	if !s.deps.GetConfigSnapshot().Worktree.Enabled {
+		return
+	}
+	sessions, err := s.deps.RequireSessions()
+	if err != nil {
+		return
+	}
+	snapshots := sessions.Snapshot()
+	repoPaths := sessionRepoPaths(snapshots)
+
+	s.externalMu.Lock()
+	defer s.externalMu.Unlock()
+	next := make(map[string]externalWorktreeScan, len(repoPaths))
+	var fresh []ExternalWorktree
+	for _, repoPath := range repoPaths {
+		key := normalizeWorktreePath(repoPath)
+		prev, scanned := s.externalScans[key]
+		stamp, stampOK := worktreeAdminStamp(repoPath)
+		if scanned && stampOK && stamp.Equal(prev.stamp) {
+			next[key] = prev
+			continue
+		}
+
+		scan := externalWorktreeScan{stamp: stamp, paths: map[string]struct{}{}}
+		external, err := collectExternalWorktrees(repoPath, snapshots)
+		if err != nil {
+			// Session directories that are not repositories end up here.
+			slog.Debug("[DEBUG-GIT] external worktree scan skipped", "repo", repoPath, "error", err)
+			next[key] = scan
+			continue
+		}
+		for _, wt := range external {
+			wtKey := normalizeWorktreePath(wt.Path)
+			scan.paths[wtKey] = struct{}{}
+			if _, known := prev.paths[wtKey]; scanned && !known {
+				fresh = append(fresh, wt)
+			}
+		}
+		next[key] = scan
+	}
+	s.externalScans = next
+
+	for _, wt := range fresh {
+		slog.Info("[INFO-GIT] external worktree detected",
+			"repo", wt.RepoPath, "path", wt.Path, "branch", wt.BranchName)
+		s.deps.Emitter.Emit(ExternalWorktreeDetectedEvent, wt)
+	}
+}
+
+// AdoptExternalWorktree opens an external worktree of repoPath as a worktree
+// session. An empty sessionName defaults to the worktree's branch, or its
+// directory name on a detached HEAD.
+func (s *Service) AdoptExternalWorktree(repoPath, worktreePath, sessionName string, opts SessionEnvOptions) (tmux.SessionSnapshot, error) {
+	worktreePath = strings.TrimSpace(worktreePath)
+	if worktreePath == "" {
+		return tmux.SessionSnapshot{}, errors.New("worktree path is required")
+	}
+	external, err := s.ListExternalWorktrees(repoPath)
+	if err != nil {
+		return tmux.SessionSnapshot{}, err
+	}
+	key := normalizeWorktreePath(worktreePath)
+	idx := slices.IndexFunc(external, func(wt ExternalWorktree) bool {
+		return normalizeWorktreePath(wt.Path) == key
+	})
+	if idx < 0 {
+		return tmux.SessionSnapshot{}, fmt.Errorf("not an external worktree of %s: %s", repoPath, worktreePath)
+	}
+	wt := external[idx]
+
+	if strings.TrimSpace(sessionName) == "" {
+		sessionName = wt.BranchName
+		if sessionName == "" {
+			sessionName = filepath.Base(wt.Path)
+		}
+	}
+	return s.CreateSessionWithExistingWorktree(wt.RepoPath, sessionName, wt.Path, opts)
+}
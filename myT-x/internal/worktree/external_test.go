@@ -0,0 +1,91 @@
+package worktree
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"myT-x/internal/config"
+	gitpkg "myT-x/internal/git"
+	"myT-x/internal/testutil"
+	"myT-x/internal/tmux"
+)
+
+func TestDetectAndAdoptExternalWorktrees(t *testing.T) {
+	repoPath := testutil.CreateTempGitRepo(t)
+	repo, err := gitpkg.Open(repoPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addWorktree := func(path, branch string) {
+		t.Helper()
+		if err := repo.CreateWorktree(path, branch, "HEAD"); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { _ = repo.RemoveWorktreeForced(path) })
+	}
+	// Worktrees under .wt/ are created by myT-x and never external.
+	addWorktree(gitpkg.GenerateWorktreePath(repoPath, "managed"), "managed")
+	existing := filepath.Join(t.TempDir(), "existing")
+	addWorktree(existing, "existing")
+
+	sm := tmux.NewSessionManager()
+	if _, _, err := sm.CreateSession("main", "0", 120, 40); err != nil {
+		t.Fatal(err)
+	}
+	if err := sm.SetRootPath("main", repoPath); err != nil {
+		t.Fatal(err)
+	}
+	svc, emitter := newTestServiceForSetup(t)
+	svc.deps.RequireSessions = func() (*tmux.SessionManager, error) { return sm, nil }
+	svc.deps.RequireSessionsAndRouter = svc.deps.RequireSessions
+	svc.deps.GetConfigSnapshot = func() config.Config {
+		cfg := config.DefaultConfig()
+		cfg.Worktree.Enabled = true
+		return cfg
+	}
+	svc.deps.CreateSession = func(_, name string, _, _, _, _ bool) (string, error) {
+		_, _, err := sm.CreateSession(name, "0", 120, 40)
+		return name, err
+	}
+
+	// The first scan only records the worktrees that already exist.
+	svc.DetectExternalWorktrees()
+	if event := emitter.findEvent(ExternalWorktreeDetectedEvent); event != nil {
+		t.Fatalf("first scan emitted %+v", event.Payload)
+	}
+
+	manual := filepath.Join(t.TempDir(), "manual")
+	addWorktree(manual, "manual")
+	svc.DetectExternalWorktrees()
+	svc.DetectExternalWorktrees()
+	var detected []ExternalWorktree
+	for _, event := range emitter.emittedEvents {
+		if event.Name == ExternalWorktreeDetectedEvent {
+			detected = append(detected, event.Payload.(ExternalWorktree))
+		}
+	}
+	if len(detected) != 1 || normalizeWorktreePath(detected[0].Path) != normalizeWorktreePath(manual) || detected[0].BranchName != "manual" {
+		t.Fatalf("detected = %+v, want only the manual worktree once", detected)
+	}
+
+	external, err := svc.ListExternalWorktrees(repoPath)
+	if err != nil || len(external) != 2 {
+		t.Fatalf("ListExternalWorktrees() = %+v, %v; want existing and manual", external, err)
+	}
+
+	if _, err := svc.AdoptExternalWorktree(repoPath, gitpkg.GenerateWorktreePath(repoPath, "managed"), "", SessionEnvOptions{}); err == nil || !strings.Contains(err.Error(), "not an external worktree") {
+		t.Fatalf("AdoptExternalWorktree(managed) error = %v", err)
+	}
+	if _, err := svc.AdoptExternalWorktree(repoPath, manual, "", SessionEnvOptions{}); err != nil {
+		t.Fatalf("AdoptExternalWorktree() error = %v", err)
+	}
+	session, ok := sm.GetSession("manual")
+	if !ok || session.Worktree == nil || session.Worktree.BranchName != "manual" ||
+		normalizeWorktreePath(session.Worktree.Path) != normalizeWorktreePath(manual) {
+		t.Fatalf("adopted session = %+v, want worktree metadata for %s", session, manual)
+	}
+	if external, _ := svc.ListExternalWorktrees(repoPath); len(external) != 1 {
+		t.Fatalf("ListExternalWorktrees() after adoption = %+v, want only existing", external)
+	}
+}
@@ -149,6 +149,10 @@ func createWorktreeForSession(
 // Returns the removal error (if any) for inclusion in the caller's error message.
 func rollbackWorktree(repo *gitpkg.Repository, wtPath, branchName string) error {
 	var rollbackErr error
+	// A worktree created moments ago can only carry our own auto-lock.
+	if unlockErr := repo.ReleaseWorktreeLockForRemoval(wtPath); unlockErr != nil {
+		slog.Warn("[WARN-GIT] failed to release worktree lock during rollback", "error", unlockErr)
+	}
 	if rmErr := repo.RemoveWorktreeForced(wtPath); rmErr != nil {
 		slog.Warn("[WARN-GIT] failed to rollback worktree", "error", rmErr)
 		rollbackErr = fmt.Errorf("failed to remove worktree during rollback: %w", rmErr)
@@ -189,7 +193,7 @@ func shellExecFlag(shell string) string {
 	switch base {
 	case "cmd.exe":
 		return "/c"
-	case "bash.exe", "wsl.exe":
+	case "bash.exe", "wsl.exe", "nu.exe":
 		return "-c"
 	case "powershell.exe", "pwsh.exe":
 		return "-Command"
@@ -0,0 +1,236 @@
+package worktree
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	gitpkg "myT-x/internal/git"
+)
+
+const (
+	// hygieneLargeUntrackedMinBytes is the size from which an untracked file
+	// is reported as a large artifact.
+	hygieneLargeUntrackedMinBytes = 10 << 20
+	// hygieneMaxLargeUntracked caps the large artifacts reported, largest first.
+	hygieneMaxLargeUntracked = 100
+	// hygieneOldStashAge is the age from which a stash is reported as old.
+	hygieneOldStashAge = 30 * 24 * time.Hour
+)
+
+// RepoHygiene summarizes clutter that accumulates in a repository and its
+// worktrees. Every item can be passed back to CleanupRepoHygiene.
+type RepoHygiene struct {
+	RepoPath          string                    `json:"repoPath"`
+	StaleBranches     []gitpkg.StaleBranch      `json:"staleBranches"`
+	PrunableWorktrees []gitpkg.PrunableWorktree `json:"prunableWorktrees"`
+	LargeUntracked    []UntrackedArtifact       `json:"largeUntracked"`
+	OldStashes        []gitpkg.StashEntry       `json:"oldStashes"`
+	// Warnings lists checks that failed; the other sections are still valid.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// UntrackedArtifact is a large untracked file in one of the repository's
+// worktrees.
+type UntrackedArtifact struct {
+	// Path is the absolute path of the file.
+	Path         string `json:"path"`
+	WorktreePath string `json:"worktreePath"`
+	Size         int64  `json:"size"`
+}
+
+// RepoHygieneCleanup selects the RepoHygiene items to clean up.
+type RepoHygieneCleanup struct {
+	// DeleteBranches lists StaleBranch names.
+	DeleteBranches []string `json:"deleteBranches"`
+	// PruneWorktrees removes all prunable worktree entries.
+	PruneWorktrees bool `json:"pruneWorktrees"`
+	// DropStashes lists StashEntry commits.
+	DropStashes []string `json:"dropStashes"`
+	// DeleteUntracked lists UntrackedArtifact paths.
+	DeleteUntracked []string `json:"deleteUntracked"`
+}
+
+// RepoHygieneCleanupResult reports what CleanupRepoHygiene removed. Items
+// that could not be removed are listed in Failures; the rest of the batch
+// still runs.
+type RepoHygieneCleanupResult struct {
+	DeletedBranches  []string `json:"deletedBranches"`
+	PrunedWorktrees  bool     `json:"prunedWorktrees"`
+	DroppedStashes   []string `json:"droppedStashes"`
+	DeletedUntracked []string `json:"deletedUntracked"`
+	Failures         []string `json:"failures,omitempty"`
+}
+
+// GetRepoHygiene reports stale branches, prunable worktrees, large untracked
+// files, and old stashes of the repository at repoPath.
+func (s *Service) GetRepoHygiene(repoPath string) (RepoHygiene, error) {
+	repo, err := gitpkg.Open(strings.TrimSpace(repoPath))
+	if err != nil {
+		return RepoHygiene{}, err
+	}
+	return collectRepoHygiene(repo, time.Now()), nil
+}
+
+func collectRepoHygiene(repo *gitpkg.Repository, now time.Time) RepoHygiene {
+	report := RepoHygiene{RepoPath: repo.GetPath()}
+	warn := func(check string, err error) {
+		slog.Warn("[WARN-GIT] repository hygiene check failed",
+			"repo", report.RepoPath, "check", check, "error", err)
+		report.Warnings = append(report.Warnings, fmt.Sprintf("%s: %v", check, err))
+	}
+
+	if stale, err := repo.ListStaleBranches(); err != nil {
+		warn("stale branches", err)
+	} else {
+		report.StaleBranches = stale
+	}
+
+	if prunable, err := repo.ListPrunableWorktrees(); err != nil {
+		warn("prunable worktrees", err)
+	} else {
+		report.PrunableWorktrees = prunable
+	}
+
+	if stashes, err := repo.ListStashes(); err != nil {
+		warn("stashes", err)
+	} else {
+		cutoff := now.Add(-hygieneOldStashAge).Unix()
+		for _, stash := range stashes {
+			if stash.CreatedUnix > 0 && stash.CreatedUnix <= cutoff {
+				report.OldStashes = append(report.OldStashes, stash)
+			}
+		}
+	}
+
+	worktrees, err := repo.ListWorktreesWithInfo()
+	if err != nil {
+		warn("untracked files", err)
+		return report
+	}
+	prunable := make(map[string]struct{}, len(report.PrunableWorktrees))
+	for _, wt := range report.PrunableWorktrees {
+		prunable[normalizeWorktreePath(wt.Path)] = struct{}{}
+	}
+	for _, wt := range worktrees {
+		if _, skip := prunable[normalizeWorktreePath(wt.Path)]; skip {
+			continue
+		}
+		wtRepo, err := gitpkg.Open(wt.Path)
+		if err != nil {
+			warn("untracked files in "+wt.Path, err)
+			continue
+		}
+		files, err := wtRepo.ListUntrackedFiles()
+		if err != nil {
+			warn("untracked files in "+wt.Path, err)
+			continue
+		}
+		for _, file := range files {
+			if file.Size < hygieneLargeUntrackedMinBytes {
+				continue
+			}
+			report.LargeUntracked = append(report.LargeUntracked, UntrackedArtifact{
+				Path:         filepath.Join(wt.Path, filepath.FromSlash(file.Path)),
+				WorktreePath: wt.Path,
+				Size:         file.Size,
+			})
+		}
+	}
+	slices.SortStableFunc(report.LargeUntracked, func(a, b UntrackedArtifact) int {
+		return cmp.Compare(b.Size, a.Size)
+	})
+	if len(report.LargeUntracked) > hygieneMaxLargeUntracked {
+		report.LargeUntracked = report.LargeUntracked[:hygieneMaxLargeUntracked]
+	}
+	return report
+}
+
+// CleanupRepoHygiene removes the selected items of the repository at
+// repoPath. Only items present in a fresh GetRepoHygiene report are removed,
+// so a stale selection can never delete unmerged branches or tracked files.
+func (s *Service) CleanupRepoHygiene(repoPath string, req RepoHygieneCleanup) (RepoHygieneCleanupResult, error) {
+	repo, err := gitpkg.Open(strings.TrimSpace(repoPath))
+	if err != nil {
+		return RepoHygieneCleanupResult{}, err
+	}
+	report := collectRepoHygiene(repo, time.Now())
+	result := RepoHygieneCleanupResult{}
+	fail := func(item string, err error) {
+		slog.Warn("[WARN-GIT] repository hygiene cleanup failed",
+			"repo", report.RepoPath, "item", item, "error", err)
+		result.Failures = append(result.Failures, fmt.Sprintf("%s: %v", item, err))
+	}
+	errNotReported := errors.New("no longer reported; refresh and retry")
+
+	staleBranches := make(map[string]struct{}, len(report.StaleBranches))
+	for _, branch := range report.StaleBranches {
+		staleBranches[branch.Name] = struct{}{}
+	}
+	for _, name := range req.DeleteBranches {
+		if _, ok := staleBranches[name]; !ok {
+			fail("branch "+name, errNotReported)
+			continue
+		}
+		if err := repo.DeleteLocalBranch(name, false); err != nil {
+			fail("branch "+name, err)
+			continue
+		}
+		result.DeletedBranches = append(result.DeletedBranches, name)
+	}
+
+	if req.PruneWorktrees && len(report.PrunableWorktrees) > 0 {
+		if err := repo.PruneWorktrees(); err != nil {
+			fail("worktree prune", err)
+		} else {
+			result.PrunedWorktrees = true
+		}
+	}
+
+	oldStashes := make(map[string]struct{}, len(report.OldStashes))
+	for _, stash := range report.OldStashes {
+		oldStashes[stash.Commit] = struct{}{}
+	}
+	for _, commit := range req.DropStashes {
+		if _, ok := oldStashes[commit]; !ok {
+			fail("stash "+commit, errNotReported)
+			continue
+		}
+		if err := repo.DropStash(commit); err != nil {
+			fail("stash "+commit, err)
+			continue
+		}
+		result.DroppedStashes = append(result.DroppedStashes, commit)
+	}
+
+	artifacts := make(map[string]struct{}, len(report.LargeUntracked))
+	for _, artifact := range report.LargeUntracked {
+		artifacts[normalizeWorktreePath(artifact.Path)] = struct{}{}
+	}
+	for _, path := range req.DeleteUntracked {
+		if _, ok := artifacts[normalizeWorktreePath(path)]; !ok {
+			fail(path, errNotReported)
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			fail(path, err)
+			continue
+		}
+		result.DeletedUntracked = append(result.DeletedUntracked, path)
+	}
+
+	slog.Info("[INFO-GIT] repository hygiene cleanup finished",
+		"repo", report.RepoPath,
+		"branches", len(result.DeletedBranches),
+		"prunedWorktrees", result.PrunedWorktrees,
+		"stashes", len(result.DroppedStashes),
+		"untracked", len(result.DeletedUntracked),
+		"failures", len(result.Failures))
+	return result, nil
+}
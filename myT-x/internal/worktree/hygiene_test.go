@@ -0,0 +1,129 @@
+package worktree
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	gitpkg "myT-x/internal/git"
+	"myT-x/internal/testutil"
+)
+
+func runHygieneGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// setupHygieneRepo creates a repository with one item of every hygiene
+// category: a merged branch, a deleted worktree, a large untracked file,
+// and a stash.
+func setupHygieneRepo(t *testing.T) (repoDir, largeFile, stashCommit string) {
+	t.Helper()
+	repoDir = testutil.CreateTempGitRepo(t)
+	runHygieneGit(t, repoDir, "branch", "merged")
+	wtPath := filepath.Join(t.TempDir(), "gone")
+	runHygieneGit(t, repoDir, "worktree", "add", "-q", "--detach", wtPath)
+	if err := os.RemoveAll(wtPath); err != nil {
+		t.Fatal(err)
+	}
+	largeFile = filepath.Join(repoDir, "dump.bin")
+	if err := os.WriteFile(largeFile, make([]byte, hygieneLargeUntrackedMinBytes), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repoDir, "small.txt"), []byte("small"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repoDir, "develop-README.md"), []byte("changed"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runHygieneGit(t, repoDir, "stash", "push", "-q", "-m", "wip", "--", "develop-README.md")
+	stashCommit = runHygieneGit(t, repoDir, "rev-parse", "stash@{0}")
+	return repoDir, largeFile, stashCommit
+}
+
+func TestCollectRepoHygiene(t *testing.T) {
+	testutil.SkipIfNoGit(t)
+
+	repoDir, largeFile, stashCommit := setupHygieneRepo(t)
+	repo, err := gitpkg.Open(repoDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report := collectRepoHygiene(repo, time.Now())
+	if len(report.Warnings) != 0 {
+		t.Fatalf("Warnings = %v", report.Warnings)
+	}
+	if len(report.StaleBranches) != 1 || report.StaleBranches[0].Name != "merged" {
+		t.Fatalf("StaleBranches = %+v, want merged", report.StaleBranches)
+	}
+	if len(report.PrunableWorktrees) != 1 {
+		t.Fatalf("PrunableWorktrees = %+v, want 1 entry", report.PrunableWorktrees)
+	}
+	if len(report.LargeUntracked) != 1 || report.LargeUntracked[0].Path != largeFile {
+		t.Fatalf("LargeUntracked = %+v, want %s only", report.LargeUntracked, largeFile)
+	}
+	if len(report.OldStashes) != 0 {
+		t.Fatalf("OldStashes = %+v, want none for a fresh stash", report.OldStashes)
+	}
+
+	report = collectRepoHygiene(repo, time.Now().Add(hygieneOldStashAge+time.Hour))
+	if len(report.OldStashes) != 1 || report.OldStashes[0].Commit != stashCommit {
+		t.Fatalf("OldStashes = %+v, want %s", report.OldStashes, stashCommit)
+	}
+}
+
+func TestCleanupRepoHygiene(t *testing.T) {
+	testutil.SkipIfNoGit(t)
+
+	repoDir, largeFile, stashCommit := setupHygieneRepo(t)
+	trackedFile := filepath.Join(repoDir, "develop-README.md")
+	svc := &Service{}
+
+	result, err := svc.CleanupRepoHygiene(repoDir, RepoHygieneCleanup{
+		DeleteBranches:  []string{"merged", "master"},
+		PruneWorktrees:  true,
+		DropStashes:     []string{stashCommit}, // not old yet
+		DeleteUntracked: []string{largeFile, trackedFile},
+	})
+	if err != nil {
+		t.Fatalf("CleanupRepoHygiene() error = %v", err)
+	}
+	if !slices.Equal(result.DeletedBranches, []string{"merged"}) {
+		t.Fatalf("DeletedBranches = %v, want [merged]", result.DeletedBranches)
+	}
+	if !result.PrunedWorktrees {
+		t.Fatal("PrunedWorktrees = false, want true")
+	}
+	if len(result.DroppedStashes) != 0 {
+		t.Fatalf("DroppedStashes = %v, want none", result.DroppedStashes)
+	}
+	if !slices.Equal(result.DeletedUntracked, []string{largeFile}) {
+		t.Fatalf("DeletedUntracked = %v, want [%s]", result.DeletedUntracked, largeFile)
+	}
+	if len(result.Failures) != 3 {
+		t.Fatalf("Failures = %v, want branch, stash, and tracked file rejected", result.Failures)
+	}
+	if _, err := os.Stat(trackedFile); err != nil {
+		t.Fatalf("tracked file was touched: %v", err)
+	}
+
+	repo, err := gitpkg.Open(repoDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := collectRepoHygiene(repo, time.Now())
+	if len(report.StaleBranches)+len(report.PrunableWorktrees)+len(report.LargeUntracked) != 0 {
+		t.Fatalf("report after cleanup = %+v, want clean", report)
+	}
+}
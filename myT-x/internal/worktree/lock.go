@@ -0,0 +1,91 @@
+package worktree
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	gitpkg "myT-x/internal/git"
+)
+
+// autoLockDetachableWorktree locks a worktree that lives on a removable or
+// network drive so that `git worktree prune` in the main repository does not
+// drop it while the drive is unplugged. Failures are logged and never abort
+// session creation; the main working tree and existing locks are left
+// untouched.
+func (s *Service) autoLockDetachableWorktree(repo *gitpkg.Repository, wtPath string) {
+	if s.deps.DetachableVolumeKind == nil {
+		return
+	}
+	kind := s.deps.DetachableVolumeKind(wtPath)
+	if kind == "" {
+		return
+	}
+	info, found, err := repo.FindWorktreeInfo(wtPath)
+	if err != nil {
+		slog.Warn("[WARN-GIT] failed to read worktree lock state", "path", wtPath, "error", err)
+		return
+	}
+	// git refuses to lock the main working tree.
+	if !found || info.IsMain || info.Locked {
+		return
+	}
+	if err := repo.LockWorktree(wtPath, gitpkg.AutoLockReasonPrefix+kind); err != nil {
+		slog.Warn("[WARN-GIT] failed to auto-lock worktree", "path", wtPath, "volume", kind, "error", err)
+		return
+	}
+	slog.Debug("[DEBUG-GIT] auto-locked worktree on detachable volume", "path", wtPath, "volume", kind)
+}
+
+// SetWorktreeLock locks or unlocks the worktree of a session.
+// Locking an already locked worktree replaces its reason; unlocking an
+// unlocked worktree is a no-op.
+func (s *Service) SetWorktreeLock(sessionName string, locked bool, reason string) error {
+	sessionName = strings.TrimSpace(sessionName)
+	if sessionName == "" {
+		return errors.New("session name is required")
+	}
+	reason = strings.TrimSpace(reason)
+	if !locked && reason != "" {
+		return errors.New("lock reason is only valid when locking")
+	}
+	worktreeInfo, err := s.requireWorktreeInfo(sessionName)
+	if err != nil {
+		return err
+	}
+	repo, err := gitpkg.Open(worktreeInfo.RepoPath)
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+	wtPath := worktreeInfo.Path
+
+	current, currentReason, err := repo.WorktreeLockState(wtPath)
+	if err != nil {
+		return err
+	}
+	switch {
+	case !locked && !current:
+		return nil
+	case !locked:
+		return repo.UnlockWorktree(wtPath)
+	case current && currentReason == reason:
+		return nil
+	case current:
+		// git has no command to change the reason of a lock.
+		if err := repo.UnlockWorktree(wtPath); err != nil {
+			return err
+		}
+	}
+	return repo.LockWorktree(wtPath, reason)
+}
+
+// releaseLockForRemoval wraps ReleaseWorktreeLockForRemoval with a message
+// that tells the user how to proceed when their own lock blocks removal.
+func releaseLockForRemoval(repo *gitpkg.Repository, wtPath string) error {
+	err := repo.ReleaseWorktreeLockForRemoval(wtPath)
+	if errors.Is(err, gitpkg.ErrWorktreeLocked) {
+		return fmt.Errorf("cannot remove worktree %s: %w", wtPath, err)
+	}
+	return err
+}
@@ -1,20 +1,50 @@
 package worktree
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
+	"os"
 	"path/filepath"
 	"strings"
 
 	gitpkg "myT-x/internal/git"
+	"myT-x/internal/preopsnapshot"
 )
 
+// orphansPrunedEvent is emitted after PruneOrphanedWorktrees removed
+// orphans (never in a dry run).
+const orphansPrunedEvent = "worktree:orphans-pruned"
+
 // OrphanedWorktree describes a worktree that is not associated with any active session.
 type OrphanedWorktree struct {
-	Path       string                 `json:"path"`
-	BranchName string                 `json:"branchName"`
-	HasChanges bool                   `json:"hasChanges"`
-	Health     *gitpkg.WorktreeHealth `json:"health,omitempty"`
+	Path       string `json:"path"`
+	BranchName string `json:"branchName"`
+	HasChanges bool   `json:"hasChanges"`
+	// Locked is true for a lock set by the user. Pruning skips such worktrees;
+	// locks placed by myT-x itself are released before removal.
+	Locked bool `json:"locked"`
+	// Untracked is true for a directory under .wt/ that git no longer lists
+	// as a worktree (e.g. after its admin files were deleted).
+	Untracked bool                   `json:"untracked"`
+	Health    *gitpkg.WorktreeHealth `json:"health,omitempty"`
+}
+
+// OrphanPruneResult reports what PruneOrphanedWorktrees removed. Orphans that
+// could not be removed are listed in Failures; the rest of the batch still runs.
+type OrphanPruneResult struct {
+	DryRun bool `json:"dryRun"`
+	// Removed lists the removed orphan paths, or in a dry run the paths that
+	// would be removed.
+	Removed  []string        `json:"removed"`
+	Skipped  []SkippedOrphan `json:"skipped,omitempty"`
+	Failures []string        `json:"failures,omitempty"`
+}
+
+// SkippedOrphan is an orphan that PruneOrphanedWorktrees left in place.
+type SkippedOrphan struct {
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
 }
 
 // normalizeWorktreePath returns a canonical form of a worktree path for
@@ -36,19 +66,24 @@ func (s *Service) ListOrphanedWorktrees(repoPath string) ([]OrphanedWorktree, er
 		return nil, nil
 	}
 
+	_, orphans, err := s.collectOrphanedWorktrees(repoPath)
+	return orphans, err
+}
+
+func (s *Service) collectOrphanedWorktrees(repoPath string) (*gitpkg.Repository, []OrphanedWorktree, error) {
 	sessions, err := s.deps.RequireSessions()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	repo, err := gitpkg.Open(repoPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open repository: %w", err)
+		return nil, nil, fmt.Errorf("failed to open repository: %w", err)
 	}
 
 	worktrees, err := repo.ListWorktreesWithInfo()
 	if err != nil {
-		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+		return nil, nil, fmt.Errorf("failed to list worktrees: %w", err)
 	}
 
 	// Collect all worktree paths that are tied to active sessions.
@@ -61,8 +96,10 @@ func (s *Service) ListOrphanedWorktrees(repoPath string) ([]OrphanedWorktree, er
 		}
 	}
 
+	knownWtPaths := make(map[string]struct{}, len(worktrees))
 	var orphans []OrphanedWorktree
 	for _, wt := range worktrees {
+		knownWtPaths[normalizeWorktreePath(wt.Path)] = struct{}{}
 		if wt.IsMain {
 			continue
 		}
@@ -73,6 +110,7 @@ func (s *Service) ListOrphanedWorktrees(repoPath string) ([]OrphanedWorktree, er
 		orphan := OrphanedWorktree{
 			Path:       wt.Path,
 			BranchName: wt.Branch,
+			Locked:     wt.Locked && !strings.HasPrefix(wt.LockReason, gitpkg.AutoLockReasonPrefix),
 		}
 
 		// Attach health status.
@@ -99,5 +137,158 @@ func (s *Service) ListOrphanedWorktrees(repoPath string) ([]OrphanedWorktree, er
 		orphans = append(orphans, orphan)
 	}
 
-	return orphans, nil
+	// Directories under .wt/ that git lost track of.
+	wtDir := gitpkg.GenerateWorktreeDirPath(repo.GetPath())
+	entries, err := os.ReadDir(wtDir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		slog.Warn("[WARN-GIT] failed to read .wt directory for orphan detection",
+			"path", wtDir, "error", err)
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		path := filepath.Join(wtDir, entry.Name())
+		key := normalizeWorktreePath(path)
+		if _, known := knownWtPaths[key]; known {
+			continue
+		}
+		if _, active := activeWtPaths[key]; active {
+			continue
+		}
+		health := repo.CheckWorktreeHealth(path)
+		orphans = append(orphans, OrphanedWorktree{
+			Path:      path,
+			Untracked: true,
+			Health:    &health,
+		})
+	}
+
+	return repo, orphans, nil
+}
+
+// PruneOrphanedWorktrees removes the orphans reported by ListOrphanedWorktrees.
+// Worktrees locked by the user are always skipped. Unless
+// worktree.force_cleanup is enabled, worktrees with uncommitted changes and
+// non-empty untracked directories are skipped as well. With dryRun the
+// result lists what would be removed and nothing is touched.
+func (s *Service) PruneOrphanedWorktrees(repoPath string, dryRun bool) (OrphanPruneResult, error) {
+	repoPath = strings.TrimSpace(repoPath)
+	if repoPath == "" {
+		return OrphanPruneResult{}, fmt.Errorf("repository path is required")
+	}
+
+	result := OrphanPruneResult{DryRun: dryRun}
+	cfg := s.deps.GetConfigSnapshot()
+	if !cfg.Worktree.Enabled {
+		return result, nil
+	}
+
+	repo, orphans, err := s.collectOrphanedWorktrees(repoPath)
+	if err != nil {
+		return OrphanPruneResult{}, err
+	}
+
+	staleEntries := false
+	for _, orphan := range orphans {
+		if reason := orphanSkipReason(orphan, cfg.Worktree.ForceCleanup); reason != "" {
+			result.Skipped = append(result.Skipped, SkippedOrphan{Path: orphan.Path, Reason: reason})
+			continue
+		}
+		if dryRun {
+			result.Removed = append(result.Removed, orphan.Path)
+			continue
+		}
+		if !orphan.Untracked && !dirExists(orphan.Path) {
+			// Only git's admin entry is left; one prune below removes them all.
+			staleEntries = true
+			result.Removed = append(result.Removed, orphan.Path)
+			continue
+		}
+		if err := s.removeOrphanedWorktree(repo, orphan, cfg.Worktree.ForceCleanup); err != nil {
+			slog.Warn("[WARN-GIT] failed to prune orphaned worktree",
+				"path", orphan.Path, "error", err)
+			result.Failures = append(result.Failures, fmt.Sprintf("%s: %v", orphan.Path, err))
+			continue
+		}
+		result.Removed = append(result.Removed, orphan.Path)
+	}
+
+	if dryRun {
+		return result, nil
+	}
+	if staleEntries {
+		if err := repo.PruneWorktrees(); err != nil {
+			result.Failures = append(result.Failures, fmt.Sprintf("worktree prune: %v", err))
+		}
+	}
+	if len(result.Removed) > 0 {
+		gitpkg.RemoveEmptyWtDir(result.Removed[0])
+	}
+
+	slog.Info("[INFO-GIT] orphaned worktree prune finished",
+		"repo", repo.GetPath(),
+		"removed", len(result.Removed),
+		"skipped", len(result.Skipped),
+		"failures", len(result.Failures))
+	if len(result.Removed) > 0 || len(result.Failures) > 0 {
+		s.deps.Emitter.Emit(orphansPrunedEvent, map[string]any{
+			"repoPath": repo.GetPath(),
+			"removed":  result.Removed,
+			"failures": result.Failures,
+		})
+	}
+	return result, nil
+}
+
+// orphanSkipReason returns why orphan must not be removed, or "".
+func orphanSkipReason(orphan OrphanedWorktree, forceCleanup bool) string {
+	switch {
+	case orphan.Locked:
+		return "worktree is locked; unlock it before removing"
+	case forceCleanup:
+		return ""
+	case orphan.Untracked && !isEmptyDir(orphan.Path):
+		return "directory is not tracked by git and is not empty"
+	case orphan.HasChanges && dirExists(orphan.Path):
+		return "worktree has uncommitted changes"
+	}
+	return ""
+}
+
+func (s *Service) removeOrphanedWorktree(repo *gitpkg.Repository, orphan OrphanedWorktree, forceCleanup bool) error {
+	if orphan.Untracked {
+		if forceCleanup {
+			s.captureBeforeRiskyOperation(preopsnapshot.OperationForceCleanup, "", orphan.Path)
+		}
+		return os.RemoveAll(orphan.Path)
+	}
+
+	if err := releaseLockForRemoval(repo, orphan.Path); err != nil {
+		return err
+	}
+	if forceCleanup && orphan.HasChanges {
+		s.captureBeforeRiskyOperation(preopsnapshot.OperationForceCleanup, "", orphan.Path)
+	}
+	if err := repo.RemoveWorktree(orphan.Path); err != nil {
+		if !forceCleanup {
+			return fmt.Errorf("failed to remove worktree: %w", err)
+		}
+		if fErr := repo.RemoveWorktreeForced(orphan.Path); fErr != nil {
+			return fmt.Errorf("failed to remove worktree (forced): %w", fErr)
+		}
+	}
+	gitpkg.PostRemovalCleanup(repo, orphan.Path)
+	s.deps.CleanupOrphanedLocalBranch("", repo, orphan.BranchName)
+	return nil
+}
+
+func dirExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+func isEmptyDir(path string) bool {
+	entries, err := os.ReadDir(path)
+	return err == nil && len(entries) == 0
 }
@@ -0,0 +1,175 @@
+package worktree
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"myT-x/internal/config"
+	"myT-x/internal/forge"
+	gitpkg "myT-x/internal/git"
+)
+
+// CreatePullRequestOptions customizes CreatePullRequestForSession. Zero
+// values fall back to defaults.
+type CreatePullRequestOptions struct {
+	// Title defaults to the subject of the branch's last commit.
+	Title string `json:"title,omitempty"`
+	// Body defaults to the body of the branch's last commit.
+	Body string `json:"body,omitempty"`
+	// Base defaults to the session's base branch, then to the default branch
+	// of the remote.
+	Base string `json:"base,omitempty"`
+	// Draft overrides forge.draft when set.
+	Draft *bool `json:"draft,omitempty"`
+}
+
+// CommitAndPushResult is the outcome of CommitAndPushWorktree.
+type CommitAndPushResult struct {
+	// PullRequest is the request opened (or found open) after the push when
+	// forge.create_on_push is set.
+	PullRequest *forge.PullRequest `json:"pull_request,omitempty"`
+	// PullRequestError says why opening the request failed. The commit and
+	// push themselves succeeded.
+	PullRequestError string `json:"pull_request_error,omitempty"`
+}
+
+// CreatePullRequestForSession opens a pull request (GitHub) or merge request
+// (GitLab) for the pushed branch of the session's worktree, or returns the
+// one already open for it. The forge and API root are detected from the
+// remote URL unless the forge config names them.
+func (s *Service) CreatePullRequestForSession(sessionName string, opts CreatePullRequestOptions) (forge.PullRequest, error) {
+	sessionName = strings.TrimSpace(sessionName)
+	if sessionName == "" {
+		return forge.PullRequest{}, errors.New("session name is required")
+	}
+	if _, err := s.deps.RequireSessions(); err != nil {
+		return forge.PullRequest{}, err
+	}
+	cfg := s.deps.GetConfigSnapshot()
+	if cfg.Forge == nil {
+		return forge.PullRequest{}, errors.New("forge is not configured: add a forge section to config.yaml")
+	}
+	worktreeInfo, err := s.requireWorktreeInfo(sessionName)
+	if err != nil {
+		return forge.PullRequest{}, err
+	}
+	if worktreeInfo.IsDetached {
+		return forge.PullRequest{}, fmt.Errorf("session %s is on a detached HEAD; promote it to a branch first", sessionName)
+	}
+	wtRepo, err := gitpkg.Open(worktreeInfo.Path)
+	if err != nil {
+		return forge.PullRequest{}, fmt.Errorf("failed to open worktree: %w", err)
+	}
+	branch, err := s.deps.CurrentBranch(wtRepo)
+	if err != nil {
+		return forge.PullRequest{}, fmt.Errorf("failed to determine current branch: %w", err)
+	}
+	remoteName, err := gitpkg.ResolveRemoteName(worktreeInfo.Path, branch)
+	if err != nil {
+		return forge.PullRequest{}, err
+	}
+	target, err := forgeTarget(*cfg.Forge, wtRepo, remoteName)
+	if err != nil {
+		return forge.PullRequest{}, err
+	}
+
+	base := strings.TrimSpace(opts.Base)
+	if base == "" {
+		base = pullRequestBase(wtRepo, remoteName, worktreeInfo.BaseBranch)
+	}
+	if base == "" {
+		return forge.PullRequest{}, fmt.Errorf("cannot tell which branch session %s merges into: pass a base branch", sessionName)
+	}
+	if base == branch {
+		return forge.PullRequest{}, fmt.Errorf("branch %s cannot be merged into itself", branch)
+	}
+
+	title, body := strings.TrimSpace(opts.Title), strings.TrimSpace(opts.Body)
+	if title == "" {
+		subject, commitBody, err := wtRepo.CommitMessage("HEAD")
+		if err != nil {
+			return forge.PullRequest{}, err
+		}
+		title = subject
+		if body == "" {
+			body = commitBody
+		}
+	}
+	draft := cfg.Forge.Draft
+	if opts.Draft != nil {
+		draft = *opts.Draft
+	}
+
+	ctx := s.deps.RuntimeContext()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	pr, err := s.deps.CreatePullRequest(ctx, target, forge.PullRequestOptions{
+		Head:  branch,
+		Base:  base,
+		Title: title,
+		Body:  body,
+		Draft: draft,
+	})
+	if err != nil {
+		return forge.PullRequest{}, fmt.Errorf("failed to open pull request: %w", err)
+	}
+	slog.Debug("[DEBUG-GIT] pull request opened",
+		"session", sessionName, "head", branch, "base", base, "url", pr.URL, "existing", pr.Existing)
+	return pr, nil
+}
+
+// forgeTarget resolves where to open pull requests for remoteName.
+func forgeTarget(forgeCfg config.ForgeConfig, repo *gitpkg.Repository, remoteName string) (forge.Target, error) {
+	rawURL, err := repo.RemoteURL(remoteName)
+	if err != nil {
+		return forge.Target{}, err
+	}
+	remote, err := forge.ParseRemoteURL(rawURL)
+	if err != nil {
+		return forge.Target{}, err
+	}
+	kind := forgeCfg.Kind
+	if kind == "" {
+		kind = forge.DetectKind(remote.Host)
+	}
+	if kind == "" {
+		return forge.Target{}, fmt.Errorf("cannot tell whether %s is GitHub or GitLab: set forge.kind", remote.Host)
+	}
+	apiURL := forgeCfg.APIURL
+	if apiURL == "" {
+		if apiURL, err = forge.APIBaseURL(kind, remote.Host); err != nil {
+			return forge.Target{}, err
+		}
+	}
+	token := forgeCfg.ResolveToken()
+	if token == "" {
+		return forge.Target{}, errors.New("forge token is not configured: set forge.token or forge.token_env")
+	}
+	return forge.Target{Kind: kind, APIURL: apiURL, Token: token, Remote: remote}, nil
+}
+
+// pullRequestBase returns the branch on remoteName that baseBranch stands
+// for, falling back to the remote's default branch. It returns "" when
+// neither is known.
+func pullRequestBase(repo *gitpkg.Repository, remoteName, baseBranch string) string {
+	if baseBranch = strings.TrimSpace(baseBranch); baseBranch != "" {
+		if ref, remote, err := repo.ResolveBaseRef(baseBranch); err == nil {
+			if branch, ok := strings.CutPrefix(ref, remote+"/"); ok && remote != "" {
+				return branch
+			}
+			if remote == "" && gitpkg.ValidateBranchName(ref) == nil {
+				return ref
+			}
+		}
+	}
+	branch, err := repo.RemoteDefaultBranch(remoteName)
+	if err != nil {
+		slog.Debug("[DEBUG-GIT] no default branch for pull request base", "remote", remoteName, "error", err)
+		return ""
+	}
+	return branch
+}
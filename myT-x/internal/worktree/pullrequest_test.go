@@ -0,0 +1,146 @@
+package worktree
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"myT-x/internal/config"
+	"myT-x/internal/forge"
+	gitpkg "myT-x/internal/git"
+	"myT-x/internal/testutil"
+	"myT-x/internal/tmux"
+)
+
+func TestCreatePullRequestForSession(t *testing.T) {
+	testutil.SkipIfNoLocalGitTransport(t)
+	repoPath := testutil.CreateTempGitRepo(t)
+	repo, err := gitpkg.Open(repoPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	base, err := repo.CurrentBranch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Fetch from the forge URL, push to a local bare repository.
+	bare := filepath.Join(t.TempDir(), "origin.git")
+	runGitInDir(t, repoPath, "init", "--bare", bare)
+	runGitInDir(t, repoPath, "remote", "add", "origin", "git@github.com:acme/widgets.git")
+	runGitInDir(t, repoPath, "config", "remote.origin.pushurl", bare)
+
+	wtPath := filepath.Join(t.TempDir(), "feature")
+	if err := repo.CreateWorktree(wtPath, "feature", base); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = repo.RemoveWorktreeForced(wtPath) })
+	if err := os.WriteFile(filepath.Join(wtPath, "feature.txt"), []byte("feature"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	sm := tmux.NewSessionManager()
+	if _, _, err := sm.CreateSession("feature", "0", 120, 40); err != nil {
+		t.Fatal(err)
+	}
+	if err := sm.SetWorktreeInfo("feature", &tmux.SessionWorktreeInfo{
+		Path: wtPath, RepoPath: repoPath, BranchName: "feature", BaseBranch: base,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	svc, _ := newTestServiceForSetup(t)
+	svc.deps.RequireSessions = func() (*tmux.SessionManager, error) { return sm, nil }
+	var forgeCfg *config.ForgeConfig
+	svc.deps.GetConfigSnapshot = func() config.Config {
+		cfg := config.DefaultConfig()
+		cfg.Worktree.Enabled = true
+		cfg.Forge = forgeCfg
+		return cfg
+	}
+	var calls []forge.PullRequestOptions
+	var lastTarget forge.Target
+	var createErr error
+	svc.deps.CreatePullRequest = func(_ context.Context, target forge.Target, opts forge.PullRequestOptions) (forge.PullRequest, error) {
+		lastTarget = target
+		calls = append(calls, opts)
+		if createErr != nil {
+			return forge.PullRequest{}, createErr
+		}
+		return forge.PullRequest{URL: "https://github.com/acme/widgets/pull/7", Number: 7}, nil
+	}
+
+	// Without a forge section, a push does not open anything.
+	result, err := svc.CommitAndPushWorktree("feature", "Add feature\n\nLonger explanation.", true)
+	if err != nil {
+		t.Fatalf("CommitAndPushWorktree() error = %v", err)
+	}
+	if result.PullRequest != nil || result.PullRequestError != "" || len(calls) != 0 {
+		t.Fatalf("CommitAndPushWorktree() without forge = %+v, calls %d", result, len(calls))
+	}
+	if _, err := svc.CreatePullRequestForSession("feature", CreatePullRequestOptions{}); err == nil {
+		t.Fatal("CreatePullRequestForSession() should fail without a forge section")
+	}
+
+	forgeCfg = &config.ForgeConfig{Draft: true}
+	if _, err := svc.CreatePullRequestForSession("feature", CreatePullRequestOptions{}); err == nil ||
+		!strings.Contains(err.Error(), "token") {
+		t.Fatalf("CreatePullRequestForSession() without a token error = %v", err)
+	}
+
+	forgeCfg.Token = "secret"
+	pr, err := svc.CreatePullRequestForSession("feature", CreatePullRequestOptions{})
+	if err != nil {
+		t.Fatalf("CreatePullRequestForSession() error = %v", err)
+	}
+	if pr.Number != 7 {
+		t.Fatalf("CreatePullRequestForSession() = %+v", pr)
+	}
+	wantTarget := forge.Target{
+		Kind:   forge.KindGitHub,
+		APIURL: "https://api.github.com",
+		Token:  "secret",
+		Remote: forge.Remote{Host: "github.com", Path: "acme/widgets"},
+	}
+	if lastTarget != wantTarget {
+		t.Fatalf("target = %+v, want %+v", lastTarget, wantTarget)
+	}
+	wantOpts := forge.PullRequestOptions{
+		Head: "feature", Base: base, Title: "Add feature", Body: "Longer explanation.", Draft: true,
+	}
+	if calls[0] != wantOpts {
+		t.Fatalf("options = %+v, want %+v", calls[0], wantOpts)
+	}
+
+	notDraft := false
+	if _, err := svc.CreatePullRequestForSession("feature", CreatePullRequestOptions{
+		Title: "Custom", Base: "release", Draft: &notDraft,
+	}); err != nil {
+		t.Fatalf("CreatePullRequestForSession() with options error = %v", err)
+	}
+	if got := calls[1]; got.Title != "Custom" || got.Body != "" || got.Base != "release" || got.Draft {
+		t.Fatalf("options with overrides = %+v", got)
+	}
+
+	// With create_on_push, a push reports the request or why it failed.
+	forgeCfg.CreateOnPush = true
+	result, err = svc.CommitAndPushWorktree("feature", "", true)
+	if err != nil {
+		t.Fatalf("CommitAndPushWorktree() error = %v", err)
+	}
+	if result.PullRequest == nil || result.PullRequest.Number != 7 {
+		t.Fatalf("CommitAndPushWorktree() with create_on_push = %+v", result)
+	}
+	createErr = errors.New("boom")
+	result, err = svc.CommitAndPushWorktree("feature", "", true)
+	if err != nil {
+		t.Fatalf("CommitAndPushWorktree() should succeed when only the pull request fails: %v", err)
+	}
+	if result.PullRequest != nil || !strings.Contains(result.PullRequestError, "boom") {
+		t.Fatalf("CommitAndPushWorktree() with a failing forge = %+v", result)
+	}
+	if result, _ := svc.CommitAndPushWorktree("feature", "", false); len(calls) != 4 || result.PullRequest != nil {
+		t.Fatalf("commit without push opened a pull request: %+v, calls %d", result, len(calls))
+	}
+}
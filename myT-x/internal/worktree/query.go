@@ -60,12 +60,22 @@ func (s *Service) CheckWorktreeStatus(sessionName string) (WorktreeStatus, error
 		}
 	}
 
+	locked, lockReason, err := wtRepo.WorktreeLockState(wtPath)
+	if err != nil {
+		// Non-fatal: the close dialog still works without lock state.
+		slog.Debug("[DEBUG-GIT] WorktreeLockState failed, treating as unlocked",
+			"session", sessionName, "error", err)
+		locked, lockReason = false, ""
+	}
+
 	return WorktreeStatus{
 		HasWorktree:    true,
 		HasUncommitted: hasUncommitted,
 		HasUnpushed:    hasUnpushed,
 		BranchName:     branchName,
 		IsDetached:     isDetached,
+		Locked:         locked,
+		LockReason:     lockReason,
 	}, nil
 }
 
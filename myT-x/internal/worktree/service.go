@@ -9,11 +9,15 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"myT-x/internal/apptypes"
 	"myT-x/internal/config"
+	"myT-x/internal/forge"
 	gitpkg "myT-x/internal/git"
+	"myT-x/internal/preopsnapshot"
 	"myT-x/internal/procutil"
+	"myT-x/internal/repoconfig"
 	"myT-x/internal/tmux"
 )
 
@@ -60,11 +64,23 @@ type Deps struct {
 	// list. createSessionForDirectory (the underlying implementation) only needs
 	// the three flags that affect initial pane env setup. UseSessionPaneScope is
 	// applied separately via ApplySessionEnvFlags after session creation.
-	CreateSession func(sessionDir, sessionName string, enableAgentTeam, useClaudeEnv, usePaneEnv bool) (createdName string, err error)
+	// isolateShellHistory must reach the initial pane, so it is passed here.
+	CreateSession func(sessionDir, sessionName string, enableAgentTeam, useClaudeEnv, usePaneEnv, isolateShellHistory bool) (createdName string, err error)
 
 	// ApplySessionEnvFlags sets session-level env flags after creation.
 	ApplySessionEnvFlags func(sm *tmux.SessionManager, sessionName string, useClaudeEnv, usePaneEnv, useSessionPaneScope bool)
 
+	// RunStartupCommand runs the session startup command in the initial pane.
+	// Failures are logged by the implementation and never abort creation.
+	// Optional: nil means worktree sessions start without a startup command.
+	RunStartupCommand func(sessionName, workDir, command string, remainOnExit bool)
+
+	// ResolveRepoConfig returns the per-repository .mytx.yaml of repoPath when
+	// the directory is trusted, or nil. Its startup_command applies when the
+	// caller passes none, and its setup_scripts run after the global ones.
+	// Optional: nil means only the global config applies.
+	ResolveRepoConfig func(repoPath string) *repoconfig.Config
+
 	// ActivateCreatedSession sets the session as active and returns its snapshot.
 	ActivateCreatedSession func(createdName string) (tmux.SessionSnapshot, error)
 
@@ -86,6 +102,11 @@ type Deps struct {
 	// CleanupOrphanedLocalBranch removes orphaned branches after worktree cleanup.
 	CleanupOrphanedLocalBranch func(sessionName string, repo *gitpkg.Repository, branchName string)
 
+	// CaptureBeforeRiskyOperation snapshots a session (scrollback tail, git
+	// status, layout) before force cleanup or branch promotion runs.
+	// Optional: nil skips the snapshot.
+	CaptureBeforeRiskyOperation func(operation preopsnapshot.Operation, sessionName, detail string)
+
 	// RegisterSetupWorker atomically marks a setup worker as active for shutdown.
 	// The returned release callback must be called exactly once when the worker
 	// exits. When shouldStart is false, the caller must skip launching the worker
@@ -113,6 +134,15 @@ type Deps struct {
 	// Defaults to repo.CurrentBranch().
 	CurrentBranch func(repo *gitpkg.Repository) (string, error)
 
+	// DetachableVolumeKind describes the volume of a path when it is a
+	// removable or network drive ("" otherwise). Worktrees on such volumes are
+	// auto-locked. Defaults to GetDriveType on Windows and "" elsewhere.
+	DetachableVolumeKind func(path string) string
+
+	// CreatePullRequest opens a pull or merge request on a forge.
+	// Defaults to forge.NewClient(forge.Deps{}).CreatePullRequest.
+	CreatePullRequest func(ctx context.Context, target forge.Target, opts forge.PullRequestOptions) (forge.PullRequest, error)
+
 	// ExecuteSetupCommand runs a setup script in a directory.
 	// Defaults to exec.CommandContext with HideWindow.
 	ExecuteSetupCommand func(ctx context.Context, shell, shellFlag, script, dir string) ([]byte, error)
@@ -146,6 +176,11 @@ type CopyDeps struct {
 	// Defaults to os.Remove.
 	RemoveFile func(name string) error
 
+	// EOLAttribute returns the .gitattributes line ending of a repo-relative
+	// path for the copy_files_eol "gitattributes" policy.
+	// Defaults to gitpkg.EOLAttribute.
+	EOLAttribute func(repoPath, path string) (string, error)
+
 	// MaxCopyDirsFileCount is the maximum file count for copy_dirs operations.
 	// Defaults to 10,000.
 	MaxCopyDirsFileCount int
@@ -160,10 +195,29 @@ type CopyDeps struct {
 // ---------------------------------------------------------------------------
 
 // Service encapsulates worktree lifecycle management.
-// Stateless: all session state lives in SessionManager (internal lock).
-// No App-level or Service-level mutex is needed.
+// Session state lives in SessionManager (internal lock). The only
+// Service-level state is the set of reported shared-branch conflicts,
+// guarded by branchConflictMu.
 type Service struct {
 	deps Deps
+
+	branchConflictMu      sync.Mutex
+	warnedBranchConflicts map[string]string
+
+	// externalMu guards externalScans, the last DetectExternalWorktrees scan
+	// per normalized repository path.
+	externalMu    sync.Mutex
+	externalScans map[string]externalWorktreeScan
+
+	// baseRefreshMu guards baseChecks, the last base branch check per
+	// session name.
+	baseRefreshMu sync.Mutex
+	baseChecks    map[string]baseBranchCheck
+
+	// setupMu guards setupDirs, the number of setup script runs in progress
+	// per cleaned worktree path.
+	setupMu   sync.Mutex
+	setupDirs map[string]int
 }
 
 func (s *Service) reserveAvailableSessionName(name string) (string, func()) {
@@ -176,6 +230,29 @@ func (s *Service) reserveAvailableSessionName(name string) (string, func()) {
 	return s.deps.FindAvailableSessionName(name), func() {}
 }
 
+func (s *Service) runStartupCommand(sessionName, workDir, command string, remainOnExit bool) {
+	if s.deps.RunStartupCommand == nil {
+		return
+	}
+	s.deps.RunStartupCommand(sessionName, workDir, command, remainOnExit)
+}
+
+// resolveRepoConfig returns the trusted repo config of repoPath, or nil.
+func (s *Service) resolveRepoConfig(repoPath string) *repoconfig.Config {
+	if s.deps.ResolveRepoConfig == nil {
+		return nil
+	}
+	return s.deps.ResolveRepoConfig(repoPath)
+}
+
+// captureBeforeRiskyOperation records a pre-operation snapshot when configured.
+func (s *Service) captureBeforeRiskyOperation(operation preopsnapshot.Operation, sessionName, detail string) {
+	if s.deps.CaptureBeforeRiskyOperation == nil {
+		return
+	}
+	s.deps.CaptureBeforeRiskyOperation(operation, sessionName, detail)
+}
+
 // NewService creates a worktree service with the given dependencies.
 // Panics if any required function field in deps is nil, reporting which fields are missing.
 func NewService(deps Deps) *Service {
@@ -246,6 +323,12 @@ func NewService(deps Deps) *Service {
 			return repo.CurrentBranch()
 		}
 	}
+	if deps.DetachableVolumeKind == nil {
+		deps.DetachableVolumeKind = detachableVolumeKind
+	}
+	if deps.CreatePullRequest == nil {
+		deps.CreatePullRequest = forge.NewClient(forge.Deps{}).CreatePullRequest
+	}
 	if deps.ExecuteSetupCommand == nil {
 		deps.ExecuteSetupCommand = func(ctx context.Context, shell, shellFlag, script, dir string) ([]byte, error) {
 			cmd := exec.CommandContext(ctx, shell, shellFlag, script)
@@ -269,6 +352,9 @@ func NewService(deps Deps) *Service {
 	if deps.Copy.RemoveFile == nil {
 		deps.Copy.RemoveFile = os.Remove
 	}
+	if deps.Copy.EOLAttribute == nil {
+		deps.Copy.EOLAttribute = gitpkg.EOLAttribute
+	}
 	if deps.Copy.MaxCopyDirsFileCount == 0 {
 		deps.Copy.MaxCopyDirsFileCount = 10_000
 	}
@@ -11,12 +11,14 @@ import (
 	"os/exec"
 	"path/filepath"
 	"reflect"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
 	"myT-x/internal/config"
 	gitpkg "myT-x/internal/git"
+	"myT-x/internal/repoconfig"
 	"myT-x/internal/testutil"
 	"myT-x/internal/tmux"
 )
@@ -98,7 +100,7 @@ func newTestServiceForSetup(t *testing.T) (*Service, *mockEmitter) {
 			GetConfigSnapshot:          func() config.Config { return config.DefaultConfig() },
 			RuntimeContext:             func() context.Context { return context.Background() },
 			FindAvailableSessionName:   func(name string) string { return name },
-			CreateSession:              func(_, _ string, _, _, _ bool) (string, error) { return "", nil },
+			CreateSession:              func(_, _ string, _, _, _, _ bool) (string, error) { return "", nil },
 			ApplySessionEnvFlags:       func(_ *tmux.SessionManager, _ string, _, _, _ bool) {},
 			ActivateCreatedSession:     func(_ string) (tmux.SessionSnapshot, error) { return tmux.SessionSnapshot{}, nil },
 			RollbackCreatedSession:     func(_ string) error { return nil },
@@ -125,6 +127,7 @@ func newTestServiceForSetup(t *testing.T) (*Service, *mockEmitter) {
 				SyncFile:              func(file *os.File) error { return file.Sync() },
 				StatFileInfo:          os.Stat,
 				RemoveFile:            os.Remove,
+				EOLAttribute:          gitpkg.EOLAttribute,
 				MaxCopyDirsFileCount:  10_000,
 				MaxCopyDirsTotalBytes: 500 * 1024 * 1024,
 			},
@@ -176,6 +179,7 @@ func TestShellExecFlag(t *testing.T) {
 		{"bash.exe", "bash.exe", "-c"},
 		{"bash alias", "bash", "-c"},
 		{"wsl.exe", "wsl.exe", "-c"},
+		{"trusted nushell path", `C:\Users\me\.cargo\bin\nu.exe`, "-c"},
 		{"powershell.exe", "powershell.exe", "-Command"},
 		{"pwsh.exe", "pwsh.exe", "-Command"},
 		{"pwsh alias", "pwsh", "-Command"},
@@ -340,6 +344,52 @@ func TestRunSetupScriptsWithParentContextFallback(t *testing.T) {
 	}
 }
 
+func TestSetupRunningTracksSetupScripts(t *testing.T) {
+	t.Parallel()
+
+	svc, _ := newTestServiceForSetup(t)
+	dir := t.TempDir()
+
+	running := false
+	svc.deps.ExecuteSetupCommand = func(_ context.Context, _ string, _ string, _ string, _ string) ([]byte, error) {
+		running = svc.SetupRunning(dir + string(filepath.Separator))
+		return []byte("ok"), nil
+	}
+
+	svc.runSetupScriptsWithParentContext(nil, dir, "session-running", "powershell.exe", []string{"echo one"})
+	if !running {
+		t.Fatal("SetupRunning() during setup = false, want true")
+	}
+	if svc.SetupRunning(dir) {
+		t.Fatal("SetupRunning() after setup = true, want false")
+	}
+}
+
+func TestRunSetupScript(t *testing.T) {
+	t.Parallel()
+
+	svc, _ := newTestServiceForSetup(t)
+	var gotScript, gotDir string
+	svc.deps.ExecuteSetupCommand = func(ctx context.Context, _ string, _ string, script string, dir string) ([]byte, error) {
+		if _, ok := ctx.Deadline(); !ok {
+			t.Error("ExecuteSetupCommand context has no deadline, want the setup script timeout")
+		}
+		gotScript, gotDir = script, dir
+		return []byte("ok"), nil
+	}
+
+	dir := t.TempDir()
+	if _, err := svc.RunSetupScript(context.Background(), dir, "  npm install  "); err != nil {
+		t.Fatalf("RunSetupScript() error = %v", err)
+	}
+	if gotScript != "npm install" || gotDir != dir {
+		t.Fatalf("ExecuteSetupCommand(script=%q, dir=%q), want (%q, %q)", gotScript, gotDir, "npm install", dir)
+	}
+	if _, err := svc.RunSetupScript(context.Background(), dir, " "); err == nil {
+		t.Fatal("RunSetupScript(blank) error = nil, want error")
+	}
+}
+
 func TestWaitForSetupScriptsCancellation(t *testing.T) {
 	if !waitForSetupScriptsCancellation(nil, 10*time.Millisecond) {
 		t.Fatal("waitForSetupScriptsCancellation(nil) = false, want true")
@@ -413,7 +463,7 @@ func TestCopyConfigFilesToWorktree(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		failures := svc.CopyConfigFilesToWorktree(repoDir, wtDir, []string{".env"})
+		failures := svc.CopyConfigFilesToWorktree(repoDir, wtDir, []string{".env"}, "")
 		if len(failures) != 0 {
 			t.Fatalf("unexpected failures: %v", failures)
 		}
@@ -441,7 +491,7 @@ func TestCopyConfigFilesToWorktree(t *testing.T) {
 
 		logBuf := testutil.CaptureLogBuffer(t, slog.LevelDebug)
 
-		failures := svc.CopyConfigFilesToWorktree(repoDir, wtDir, []string{".env"})
+		failures := svc.CopyConfigFilesToWorktree(repoDir, wtDir, []string{".env"}, "")
 		if len(failures) != 0 {
 			t.Fatalf("unexpected failures: %v", failures)
 		}
@@ -474,7 +524,7 @@ func TestCopyConfigFilesToWorktree(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		failures := svc.CopyConfigFilesToWorktree(repoDir, wtDir, []string{filepath.Join("config", "app.yaml")})
+		failures := svc.CopyConfigFilesToWorktree(repoDir, wtDir, []string{filepath.Join("config", "app.yaml")}, "")
 		if !reflect.DeepEqual(failures, []string{filepath.Join("config", "app.yaml")}) {
 			t.Fatalf("failures = %#v, want %#v", failures, []string{filepath.Join("config", "app.yaml")})
 		}
@@ -491,7 +541,7 @@ func TestCopyConfigFilesToWorktree(t *testing.T) {
 		repoDir := t.TempDir()
 		wtDir := t.TempDir()
 
-		failures := svc.CopyConfigFilesToWorktree(repoDir, wtDir, []string{"nonexistent.env"})
+		failures := svc.CopyConfigFilesToWorktree(repoDir, wtDir, []string{"nonexistent.env"}, "")
 		if len(failures) != 0 {
 			t.Fatalf("missing files should be silently skipped, got failures: %v", failures)
 		}
@@ -501,7 +551,7 @@ func TestCopyConfigFilesToWorktree(t *testing.T) {
 		repoDir := t.TempDir()
 		wtDir := t.TempDir()
 
-		failures := svc.CopyConfigFilesToWorktree(repoDir, wtDir, []string{`C:\Windows\System32\config.sys`})
+		failures := svc.CopyConfigFilesToWorktree(repoDir, wtDir, []string{`C:\Windows\System32\config.sys`}, "")
 		if !reflect.DeepEqual(failures, []string{`C:\Windows\System32\config.sys`}) {
 			t.Fatalf("absolute paths should be reported as failures: %v", failures)
 		}
@@ -523,7 +573,7 @@ func TestCopyConfigFilesToWorktree(t *testing.T) {
 		}
 		defer os.Remove(outsideFile)
 
-		failures := svc.CopyConfigFilesToWorktree(repoDir, wtDir, []string{"../sensitive.txt"})
+		failures := svc.CopyConfigFilesToWorktree(repoDir, wtDir, []string{"../sensitive.txt"}, "")
 		if !reflect.DeepEqual(failures, []string{"../sensitive.txt"}) {
 			t.Fatalf("traversal paths should be reported as failures: %v", failures)
 		}
@@ -541,7 +591,7 @@ func TestCopyConfigFilesToWorktree(t *testing.T) {
 			t.Skipf("symlink not supported in this environment: %v", err)
 		}
 
-		failures := svc.CopyConfigFilesToWorktree(repoDir, wtDir, []string{".env"})
+		failures := svc.CopyConfigFilesToWorktree(repoDir, wtDir, []string{".env"}, "")
 		if !reflect.DeepEqual(failures, []string{".env"}) {
 			t.Fatalf("symlink escape should be reported as failure: %v", failures)
 		}
@@ -568,7 +618,7 @@ func TestCopyConfigFilesToWorktree(t *testing.T) {
 			t.Skipf("symlink not supported in this environment: %v", err)
 		}
 
-		failures := svc.CopyConfigFilesToWorktree(repoDir, wtDir, []string{filepath.Join("config", "app.yaml")})
+		failures := svc.CopyConfigFilesToWorktree(repoDir, wtDir, []string{filepath.Join("config", "app.yaml")}, "")
 		if !reflect.DeepEqual(failures, []string{filepath.Join("config", "app.yaml")}) {
 			t.Fatalf("destination symlink escape should be reported as failure: %v", failures)
 		}
@@ -590,7 +640,7 @@ func TestCopyConfigFilesToWorktree(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		failures := svc.CopyConfigFilesToWorktree(repoDir, wtDir, []string{filepath.Join("config", "app.yaml")})
+		failures := svc.CopyConfigFilesToWorktree(repoDir, wtDir, []string{filepath.Join("config", "app.yaml")}, "")
 		if len(failures) != 0 {
 			t.Fatalf("unexpected failures: %v", failures)
 		}
@@ -608,7 +658,7 @@ func TestCopyConfigFilesToWorktree(t *testing.T) {
 		repoDir := t.TempDir()
 		wtDir := t.TempDir()
 
-		failures := svc.CopyConfigFilesToWorktree(repoDir, wtDir, []string{})
+		failures := svc.CopyConfigFilesToWorktree(repoDir, wtDir, []string{}, "")
 		if len(failures) != 0 {
 			t.Fatalf("empty file list should produce no failures: %v", failures)
 		}
@@ -618,7 +668,7 @@ func TestCopyConfigFilesToWorktree(t *testing.T) {
 		repoDir := t.TempDir()
 		wtDir := t.TempDir()
 
-		failures := svc.CopyConfigFilesToWorktree(repoDir, wtDir, nil)
+		failures := svc.CopyConfigFilesToWorktree(repoDir, wtDir, nil, "")
 		if len(failures) != 0 {
 			t.Fatalf("nil file list should produce no failures: %v", failures)
 		}
@@ -628,7 +678,7 @@ func TestCopyConfigFilesToWorktree(t *testing.T) {
 		wtDir := t.TempDir()
 		want := []string{".env", "config/app.yaml"}
 
-		failures := svc.CopyConfigFilesToWorktree("\x00", wtDir, want)
+		failures := svc.CopyConfigFilesToWorktree("\x00", wtDir, want, "")
 		if !reflect.DeepEqual(failures, want) {
 			t.Fatalf("copy failures = %v, want %v", failures, want)
 		}
@@ -638,13 +688,80 @@ func TestCopyConfigFilesToWorktree(t *testing.T) {
 		repoDir := t.TempDir()
 		want := []string{".env", "config/app.yaml"}
 
-		failures := svc.CopyConfigFilesToWorktree(repoDir, "\x00", want)
+		failures := svc.CopyConfigFilesToWorktree(repoDir, "\x00", want, "")
 		if !reflect.DeepEqual(failures, want) {
 			t.Fatalf("copy failures = %v, want %v", failures, want)
 		}
 	})
 }
 
+func TestCopyConfigFilesToWorktreeEOL(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    string
+		attribute string
+		attrErr   error
+		content   string
+		want      string
+	}{
+		{name: "empty policy copies verbatim", policy: "", content: "a\r\nb\n", want: "a\r\nb\n"},
+		{name: "lf policy", policy: config.CopyFilesEOLLF, content: "a\r\nb\r\n", want: "a\nb\n"},
+		{name: "crlf policy", policy: config.CopyFilesEOLCRLF, content: "a\nb\r\n", want: "a\r\nb\r\n"},
+		{name: "gitattributes crlf", policy: config.CopyFilesEOLGitAttributes, attribute: gitpkg.EOLCRLF, content: "a\nb\n", want: "a\r\nb\r\n"},
+		{name: "gitattributes unset copies verbatim", policy: config.CopyFilesEOLGitAttributes, content: "a\r\nb\n", want: "a\r\nb\n"},
+		{name: "gitattributes error copies verbatim", policy: config.CopyFilesEOLGitAttributes, attrErr: errors.New("git failed"), content: "a\nb\n", want: "a\nb\n"},
+		{name: "binary file copied verbatim", policy: config.CopyFilesEOLCRLF, content: "a\n\x00b\n", want: "a\n\x00b\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _ := newTestServiceForSetup(t)
+			var gotPath string
+			svc.deps.Copy.EOLAttribute = func(_ string, path string) (string, error) {
+				gotPath = path
+				return tt.attribute, tt.attrErr
+			}
+			repoDir := t.TempDir()
+			wtDir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(repoDir, ".env"), []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			failures := svc.CopyConfigFilesToWorktree(repoDir, wtDir, []string{".env"}, tt.policy)
+			if len(failures) != 0 {
+				t.Fatalf("unexpected failures: %v", failures)
+			}
+			data, err := os.ReadFile(filepath.Join(wtDir, ".env"))
+			if err != nil {
+				t.Fatalf("failed to read destination file: %v", err)
+			}
+			if string(data) != tt.want {
+				t.Fatalf("destination file content = %q, want %q", string(data), tt.want)
+			}
+			if tt.policy == config.CopyFilesEOLGitAttributes && gotPath != ".env" {
+				t.Fatalf("EOLAttribute path = %q, want %q", gotPath, ".env")
+			}
+		})
+	}
+}
+
+func TestConvertEOL(t *testing.T) {
+	tests := []struct {
+		in   string
+		eol  string
+		want string
+	}{
+		{"a\r\nb\nc", gitpkg.EOLLF, "a\nb\nc"},
+		{"a\r\nb\nc", gitpkg.EOLCRLF, "a\r\nb\r\nc"},
+		{"a\rb\n", gitpkg.EOLCRLF, "a\rb\r\n"},
+		{"", gitpkg.EOLCRLF, ""},
+	}
+	for _, tt := range tests {
+		if got := string(convertEOL([]byte(tt.in), tt.eol)); got != tt.want {
+			t.Errorf("convertEOL(%q, %q) = %q, want %q", tt.in, tt.eol, got, tt.want)
+		}
+	}
+}
+
 func TestCopyConfigDirsToWorktree(t *testing.T) {
 	t.Parallel()
 	// Shared service with default IO deps for subtests that do not override deps.
@@ -2154,7 +2271,7 @@ func TestCreateSessionWithExistingWorktreeReturnsErrorWhenCurrentBranchFails(t *
 			},
 			RuntimeContext:           func() context.Context { return context.Background() },
 			FindAvailableSessionName: func(name string) string { return name },
-			CreateSession: func(sessionDir, sessionName string, _, _, _ bool) (string, error) {
+			CreateSession: func(sessionDir, sessionName string, _, _, _, _ bool) (string, error) {
 				if _, _, err := sm.CreateSession(sessionName, "0", 120, 40); err != nil {
 					return "", err
 				}
@@ -2227,7 +2344,7 @@ func TestCreateSessionWithExistingWorktreeDetectsDetachedHead(t *testing.T) {
 			},
 			RuntimeContext:           func() context.Context { return context.Background() },
 			FindAvailableSessionName: func(name string) string { return name },
-			CreateSession: func(sessionDir, sessionName string, _, _, _ bool) (string, error) {
+			CreateSession: func(sessionDir, sessionName string, _, _, _, _ bool) (string, error) {
 				if _, _, err := sm.CreateSession(sessionName, "0", 120, 40); err != nil {
 					return "", err
 				}
@@ -2284,6 +2401,50 @@ func TestCreateSessionWithExistingWorktreeDetectsDetachedHead(t *testing.T) {
 	}
 }
 
+func TestCreateSessionWithExistingWorktreeUsesRepoStartupCommand(t *testing.T) {
+	t.Parallel()
+	repoPath := testutil.CreateTempGitRepo(t)
+
+	tests := []struct {
+		name        string
+		explicit    string
+		repoCfg     *repoconfig.Config
+		wantCommand string
+	}{
+		{name: "untrusted or missing repo config", wantCommand: ""},
+		{name: "trusted repo config", repoCfg: &repoconfig.Config{StartupCommand: "npm run dev"}, wantCommand: "npm run dev"},
+		{name: "explicit command wins", explicit: "make", repoCfg: &repoconfig.Config{StartupCommand: "npm run dev"}, wantCommand: "make"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sm := tmux.NewSessionManager()
+			svc, _ := newTestServiceForSetup(t)
+			svc.deps.RequireSessionsAndRouter = func() (*tmux.SessionManager, error) { return sm, nil }
+			svc.deps.CreateSession = func(_, sessionName string, _, _, _, _ bool) (string, error) {
+				if _, _, err := sm.CreateSession(sessionName, "0", 120, 40); err != nil {
+					return "", err
+				}
+				return sessionName, nil
+			}
+			svc.deps.ResolveRepoConfig = func(path string) *repoconfig.Config {
+				if path != repoPath {
+					t.Errorf("ResolveRepoConfig(%q), want repo path %q", path, repoPath)
+				}
+				return tt.repoCfg
+			}
+			gotCommand := "<not called>"
+			svc.deps.RunStartupCommand = func(_, _, command string, _ bool) { gotCommand = command }
+
+			if _, err := svc.CreateSessionWithExistingWorktree(repoPath, "repo-cfg", repoPath, SessionEnvOptions{StartupCommand: tt.explicit}); err != nil {
+				t.Fatalf("CreateSessionWithExistingWorktree() error = %v", err)
+			}
+			if gotCommand != tt.wantCommand {
+				t.Fatalf("startup command = %q, want %q", gotCommand, tt.wantCommand)
+			}
+		})
+	}
+}
+
 func TestCreateSessionWithExistingWorktreeReturnsErrorWhenBranchDetectionFailsWithNonEmptyBranch(t *testing.T) {
 	t.Parallel()
 	repoPath := testutil.CreateTempGitRepo(t)
@@ -2306,7 +2467,7 @@ func TestCreateSessionWithExistingWorktreeReturnsErrorWhenBranchDetectionFailsWi
 			},
 			RuntimeContext:           func() context.Context { return context.Background() },
 			FindAvailableSessionName: func(name string) string { return name },
-			CreateSession: func(sessionDir, sessionName string, _, _, _ bool) (string, error) {
+			CreateSession: func(sessionDir, sessionName string, _, _, _, _ bool) (string, error) {
 				if _, _, err := sm.CreateSession(sessionName, "0", 120, 40); err != nil {
 					return "", err
 				}
@@ -2375,7 +2536,7 @@ func TestCreateSessionWithWorktreeReturnsErrorWhenCurrentBranchFails(t *testing.
 			},
 			RuntimeContext:           func() context.Context { return context.Background() },
 			FindAvailableSessionName: func(name string) string { return name },
-			CreateSession: func(sessionDir, sessionName string, _, _, _ bool) (string, error) {
+			CreateSession: func(sessionDir, sessionName string, _, _, _, _ bool) (string, error) {
 				if _, _, err := sm.CreateSession(sessionName, "0", 120, 40); err != nil {
 					return "", err
 				}
@@ -2447,7 +2608,7 @@ func TestCreateSessionWithWorktreeUsesHeadBaseForDetachedRepo(t *testing.T) {
 			},
 			RuntimeContext:           func() context.Context { return context.Background() },
 			FindAvailableSessionName: func(name string) string { return name },
-			CreateSession: func(sessionDir, sessionName string, _, _, _ bool) (string, error) {
+			CreateSession: func(sessionDir, sessionName string, _, _, _, _ bool) (string, error) {
 				if _, _, err := sm.CreateSession(sessionName, "0", 120, 40); err != nil {
 					return "", err
 				}
@@ -2516,30 +2677,102 @@ func TestCreateSessionWithWorktreeUsesHeadBaseForDetachedRepo(t *testing.T) {
 // Field count guard tests
 // ===========================================================================
 
+func TestWorktreeLockLifecycle(t *testing.T) {
+	t.Parallel()
+	testutil.SkipIfNoGit(t)
+	repoPath := testutil.CreateTempGitRepo(t)
+	repo, err := gitpkg.Open(repoPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wtDir := gitpkg.GenerateWorktreeDirPath(repoPath)
+	if err := os.MkdirAll(wtDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	wtPath := filepath.Join(wtDir, "usb-wt")
+	if err := repo.CreateWorktreeDetached(wtPath, "HEAD"); err != nil {
+		t.Fatal(err)
+	}
+
+	sm := tmux.NewSessionManager()
+	svc, _ := newTestServiceForSetup(t)
+	svc.deps.RequireSessions = func() (*tmux.SessionManager, error) { return sm, nil }
+	svc.deps.RequireSessionsAndRouter = func() (*tmux.SessionManager, error) { return sm, nil }
+	svc.deps.GetConfigSnapshot = func() config.Config {
+		cfg := config.DefaultConfig()
+		cfg.Worktree.Enabled = true
+		return cfg
+	}
+	svc.deps.CreateSession = func(_, sessionName string, _, _, _, _ bool) (string, error) {
+		if _, _, err := sm.CreateSession(sessionName, "0", 120, 40); err != nil {
+			return "", err
+		}
+		return sessionName, nil
+	}
+	svc.deps.DetachableVolumeKind = func(string) string { return "removable drive" }
+
+	if _, err := svc.CreateSessionWithExistingWorktree(repoPath, "usb", wtPath, SessionEnvOptions{}); err != nil {
+		t.Fatalf("CreateSessionWithExistingWorktree() error = %v", err)
+	}
+	status, err := svc.CheckWorktreeStatus("usb")
+	if err != nil {
+		t.Fatalf("CheckWorktreeStatus() error = %v", err)
+	}
+	if want := gitpkg.AutoLockReasonPrefix + "removable drive"; !status.Locked || status.LockReason != want {
+		t.Fatalf("status lock = %v, %q; want auto-lock %q", status.Locked, status.LockReason, want)
+	}
+
+	if err := svc.SetWorktreeLock("usb", true, "keep for review"); err != nil {
+		t.Fatalf("SetWorktreeLock(true) error = %v", err)
+	}
+	if locked, reason, _ := repo.WorktreeLockState(wtPath); !locked || reason != "keep for review" {
+		t.Fatalf("lock state = %v, %q; want user lock", locked, reason)
+	}
+	if err := svc.SetWorktreeLock("usb", false, "reason"); err == nil {
+		t.Fatal("SetWorktreeLock(false) with reason error = nil")
+	}
+	if err := svc.CleanupWorktree("usb"); !errors.Is(err, gitpkg.ErrWorktreeLocked) {
+		t.Fatalf("CleanupWorktree() error = %v, want ErrWorktreeLocked", err)
+	}
+	if _, err := os.Stat(wtPath); err != nil {
+		t.Fatalf("locked worktree was removed: %v", err)
+	}
+
+	if err := svc.SetWorktreeLock("usb", false, ""); err != nil {
+		t.Fatalf("SetWorktreeLock(false) error = %v", err)
+	}
+	if err := svc.SetWorktreeLock("usb", false, ""); err != nil {
+		t.Fatalf("SetWorktreeLock(false) on unlocked worktree error = %v", err)
+	}
+	if err := svc.CleanupWorktree("usb"); err != nil {
+		t.Fatalf("CleanupWorktree() error = %v", err)
+	}
+}
+
 func TestWorktreeStructFieldCounts(t *testing.T) {
-	if got := reflect.TypeFor[WorktreeSessionOptions]().NumField(); got != 8 {
-		t.Fatalf("WorktreeSessionOptions field count = %d, want 8; update tests for new fields", got)
+	if got := reflect.TypeFor[WorktreeSessionOptions]().NumField(); got != 12 {
+		t.Fatalf("WorktreeSessionOptions field count = %d, want 12; update tests for new fields", got)
 	}
-	if got := reflect.TypeFor[WorktreeStatus]().NumField(); got != 5 {
-		t.Fatalf("WorktreeStatus field count = %d, want 5; update tests for new fields", got)
+	if got := reflect.TypeFor[WorktreeStatus]().NumField(); got != 7 {
+		t.Fatalf("WorktreeStatus field count = %d, want 7; update tests for new fields", got)
 	}
-	if got := reflect.TypeFor[SessionEnvOptions]().NumField(); got != 4 {
-		t.Fatalf("SessionEnvOptions field count = %d, want 4; update tests for new fields", got)
+	if got := reflect.TypeFor[SessionEnvOptions]().NumField(); got != 7 {
+		t.Fatalf("SessionEnvOptions field count = %d, want 7; update tests for new fields", got)
 	}
 	if got := reflect.TypeFor[copyWalkBudget]().NumField(); got != 2 {
 		t.Fatalf("copyWalkBudget field count = %d, want 2; update tests for new fields", got)
 	}
-	if got := reflect.TypeFor[Deps]().NumField(); got != 25 {
-		t.Fatalf("Deps field count = %d, want 25; update tests for new fields", got)
+	if got := reflect.TypeFor[Deps]().NumField(); got != 30 {
+		t.Fatalf("Deps field count = %d, want 30; update tests for new fields", got)
 	}
-	if got := reflect.TypeFor[CopyDeps]().NumField(); got != 7 {
-		t.Fatalf("CopyDeps field count = %d, want 7; update tests for new fields", got)
+	if got := reflect.TypeFor[CopyDeps]().NumField(); got != 8 {
+		t.Fatalf("CopyDeps field count = %d, want 8; update tests for new fields", got)
 	}
 }
 
 func TestOrphanedWorktreeFieldCount(t *testing.T) {
-	if got := reflect.TypeFor[OrphanedWorktree]().NumField(); got != 4 {
-		t.Fatalf("OrphanedWorktree field count = %d, want 4; update tests for new fields", got)
+	if got := reflect.TypeFor[OrphanedWorktree]().NumField(); got != 6 {
+		t.Fatalf("OrphanedWorktree field count = %d, want 6; update tests for new fields", got)
 	}
 	if got := reflect.TypeFor[createWorktreeResult]().NumField(); got != 4 {
 		t.Fatalf("createWorktreeResult field count = %d, want 4; update tests for new fields", got)
@@ -2690,7 +2923,7 @@ func TestListOrphanedWorktrees(t *testing.T) {
 				GetConfigSnapshot:          func() config.Config { return config.DefaultConfig() },
 				RuntimeContext:             func() context.Context { return context.Background() },
 				FindAvailableSessionName:   func(name string) string { return name },
-				CreateSession:              func(_, _ string, _, _, _ bool) (string, error) { return "", nil },
+				CreateSession:              func(_, _ string, _, _, _, _ bool) (string, error) { return "", nil },
 				ApplySessionEnvFlags:       func(_ *tmux.SessionManager, _ string, _, _, _ bool) {},
 				ActivateCreatedSession:     func(_ string) (tmux.SessionSnapshot, error) { return tmux.SessionSnapshot{}, nil },
 				RollbackCreatedSession:     func(_ string) error { return nil },
@@ -2745,7 +2978,7 @@ func TestListOrphanedWorktrees(t *testing.T) {
 				GetConfigSnapshot:          func() config.Config { return config.DefaultConfig() },
 				RuntimeContext:             func() context.Context { return context.Background() },
 				FindAvailableSessionName:   func(name string) string { return name },
-				CreateSession:              func(_, _ string, _, _, _ bool) (string, error) { return "", nil },
+				CreateSession:              func(_, _ string, _, _, _, _ bool) (string, error) { return "", nil },
 				ApplySessionEnvFlags:       func(_ *tmux.SessionManager, _ string, _, _, _ bool) {},
 				ActivateCreatedSession:     func(_ string) (tmux.SessionSnapshot, error) { return tmux.SessionSnapshot{}, nil },
 				RollbackCreatedSession:     func(_ string) error { return nil },
@@ -2824,7 +3057,7 @@ func TestListOrphanedWorktrees(t *testing.T) {
 				GetConfigSnapshot:          func() config.Config { return config.DefaultConfig() },
 				RuntimeContext:             func() context.Context { return context.Background() },
 				FindAvailableSessionName:   func(name string) string { return name },
-				CreateSession:              func(_, _ string, _, _, _ bool) (string, error) { return "", nil },
+				CreateSession:              func(_, _ string, _, _, _, _ bool) (string, error) { return "", nil },
 				ApplySessionEnvFlags:       func(_ *tmux.SessionManager, _ string, _, _, _ bool) {},
 				ActivateCreatedSession:     func(_ string) (tmux.SessionSnapshot, error) { return tmux.SessionSnapshot{}, nil },
 				RollbackCreatedSession:     func(_ string) error { return nil },
@@ -2866,5 +3099,174 @@ func TestListOrphanedWorktrees(t *testing.T) {
 	})
 }
 
+func TestListOrphanedWorktreesReportsUntrackedDirectories(t *testing.T) {
+	t.Parallel()
+	testutil.SkipIfNoGit(t)
+
+	repoPath := testutil.CreateTempGitRepo(t)
+	strayPath := filepath.Join(gitpkg.GenerateWorktreeDirPath(repoPath), "stray")
+	if err := os.MkdirAll(strayPath, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(gitpkg.GenerateWorktreeDirPath(repoPath)) })
+
+	svc, _ := newTestServiceForSetup(t)
+	orphans, err := svc.ListOrphanedWorktrees(repoPath)
+	if err != nil {
+		t.Fatalf("ListOrphanedWorktrees() error = %v", err)
+	}
+	if len(orphans) != 1 || !orphans[0].Untracked || orphans[0].Path != strayPath {
+		t.Fatalf("orphans = %+v, want the untracked directory %s", orphans, strayPath)
+	}
+	if orphans[0].Health == nil || orphans[0].Health.IsHealthy {
+		t.Fatalf("untracked directory health = %+v, want unhealthy", orphans[0].Health)
+	}
+}
+
+func TestPruneOrphanedWorktrees(t *testing.T) {
+	t.Parallel()
+	testutil.SkipIfNoGit(t)
+
+	// setup creates a clean, a dirty, a user-locked, and a deleted worktree,
+	// plus an empty and a non-empty directory that git does not track.
+	setup := func(t *testing.T) (repoPath string, paths map[string]string) {
+		t.Helper()
+		repoPath = testutil.CreateTempGitRepo(t)
+		repo, err := gitpkg.Open(repoPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		wtDir := gitpkg.GenerateWorktreeDirPath(repoPath)
+		t.Cleanup(func() { _ = os.RemoveAll(wtDir) })
+		paths = map[string]string{}
+		for _, name := range []string{"clean", "dirty", "locked", "deleted"} {
+			paths[name] = filepath.Join(wtDir, name)
+			if err := repo.CreateWorktree(paths[name], "orphan-"+name, "HEAD"); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := os.WriteFile(filepath.Join(paths["dirty"], "wip.txt"), []byte("wip"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := repo.LockWorktree(paths["locked"], "keep"); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.RemoveAll(paths["deleted"]); err != nil {
+			t.Fatal(err)
+		}
+		for _, name := range []string{"empty", "stray"} {
+			paths[name] = filepath.Join(wtDir, name)
+			if err := os.MkdirAll(paths[name], 0o755); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := os.WriteFile(filepath.Join(paths["stray"], "notes.txt"), []byte("notes"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return repoPath, paths
+	}
+	skippedPaths := func(result OrphanPruneResult) []string {
+		var got []string
+		for _, skipped := range result.Skipped {
+			got = append(got, skipped.Path)
+		}
+		slices.Sort(got)
+		return got
+	}
+
+	t.Run("dry run reports without removing", func(t *testing.T) {
+		t.Parallel()
+		repoPath, paths := setup(t)
+		svc, emitter := newTestServiceForSetup(t)
+
+		result, err := svc.PruneOrphanedWorktrees(repoPath, true)
+		if err != nil {
+			t.Fatalf("PruneOrphanedWorktrees() error = %v", err)
+		}
+		slices.Sort(result.Removed)
+		wantRemoved := []string{paths["clean"], paths["deleted"], paths["empty"]}
+		slices.Sort(wantRemoved)
+		if !result.DryRun || !slices.Equal(result.Removed, wantRemoved) {
+			t.Fatalf("result = %+v, want dry run removing %v", result, wantRemoved)
+		}
+		wantSkipped := []string{paths["dirty"], paths["locked"], paths["stray"]}
+		slices.Sort(wantSkipped)
+		if got := skippedPaths(result); !slices.Equal(got, wantSkipped) {
+			t.Fatalf("skipped = %v, want %v", got, wantSkipped)
+		}
+		if _, err := os.Stat(paths["clean"]); err != nil {
+			t.Fatalf("dry run removed %s: %v", paths["clean"], err)
+		}
+		if event := emitter.findEvent(orphansPrunedEvent); event != nil {
+			t.Fatalf("dry run emitted %s", orphansPrunedEvent)
+		}
+	})
+
+	t.Run("removes safe orphans and emits an event", func(t *testing.T) {
+		t.Parallel()
+		repoPath, paths := setup(t)
+		svc, emitter := newTestServiceForSetup(t)
+
+		result, err := svc.PruneOrphanedWorktrees(repoPath, false)
+		if err != nil {
+			t.Fatalf("PruneOrphanedWorktrees() error = %v", err)
+		}
+		if len(result.Removed) != 3 || len(result.Failures) != 0 {
+			t.Fatalf("result = %+v, want 3 removals and no failures", result)
+		}
+		for _, name := range []string{"clean", "empty"} {
+			if _, err := os.Stat(paths[name]); !os.IsNotExist(err) {
+				t.Fatalf("%s still exists: %v", paths[name], err)
+			}
+		}
+		for _, name := range []string{"dirty", "locked", "stray"} {
+			if _, err := os.Stat(paths[name]); err != nil {
+				t.Fatalf("%s was removed: %v", paths[name], err)
+			}
+		}
+		if payload := emitter.findPayload(orphansPrunedEvent); payload == nil || payload["repoPath"] == "" {
+			t.Fatalf("%s payload = %v", orphansPrunedEvent, payload)
+		}
+
+		remaining, err := svc.ListOrphanedWorktrees(repoPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(remaining) != 3 {
+			t.Fatalf("remaining orphans = %+v, want dirty, locked, and stray", remaining)
+		}
+	})
+
+	t.Run("force cleanup removes dirty and untracked directories but not locked", func(t *testing.T) {
+		t.Parallel()
+		repoPath, paths := setup(t)
+		svc, _ := newTestServiceForSetup(t)
+		svc.deps.GetConfigSnapshot = func() config.Config {
+			cfg := config.DefaultConfig()
+			cfg.Worktree.ForceCleanup = true
+			return cfg
+		}
+
+		result, err := svc.PruneOrphanedWorktrees(repoPath, false)
+		if err != nil {
+			t.Fatalf("PruneOrphanedWorktrees() error = %v", err)
+		}
+		if got := skippedPaths(result); !slices.Equal(got, []string{paths["locked"]}) {
+			t.Fatalf("skipped = %v, want only the locked worktree", got)
+		}
+		if len(result.Removed) != 5 || len(result.Failures) != 0 {
+			t.Fatalf("result = %+v, want 5 removals and no failures", result)
+		}
+	})
+
+	t.Run("empty repoPath returns error", func(t *testing.T) {
+		t.Parallel()
+		svc, _ := newTestServiceForSetup(t)
+		if _, err := svc.PruneOrphanedWorktrees(" ", true); err == nil {
+			t.Fatal("expected error for empty repoPath")
+		}
+	})
+}
+
 // Verify unused imports are not present.
 var _ = fmt.Sprintf
@@ -0,0 +1,125 @@
+package worktree
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	gitpkg "myT-x/internal/git"
+	"myT-x/internal/tmux"
+)
+
+// BranchConflictEvent carries a SharedBranchConflict when a worktree shared by
+// several sessions is checked out to a branch other than the one a session
+// works on.
+const BranchConflictEvent = "worktree:branch-conflict"
+
+// SharedBranchConflict reports a session whose branch context no longer
+// matches the branch checked out in its shared worktree.
+type SharedBranchConflict struct {
+	WorktreePath   string `json:"worktree_path"`
+	SessionName    string `json:"session_name"`
+	ExpectedBranch string `json:"expected_branch"`
+	ActualBranch   string `json:"actual_branch"`
+	// SharedWith lists the other sessions using the worktree.
+	SharedWith []string `json:"shared_with"`
+}
+
+// sharedWorktreeKey normalizes a worktree path for grouping. Windows paths
+// are case-insensitive.
+func sharedWorktreeKey(path string) string {
+	return strings.ToLower(filepath.Clean(strings.TrimSpace(path)))
+}
+
+// sessionsSharingWorktree returns the sessions other than sessionName whose
+// worktree is wtPath, sorted by name.
+func sessionsSharingWorktree(snapshots []tmux.SessionSnapshot, wtPath, sessionName string) []string {
+	key := sharedWorktreeKey(wtPath)
+	var names []string
+	for _, snap := range snapshots {
+		if snap.Name == sessionName || snap.Worktree == nil || snap.Worktree.Path == "" {
+			continue
+		}
+		if sharedWorktreeKey(snap.Worktree.Path) == key {
+			names = append(names, snap.Name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// requireUnsharedWorktree rejects operations that would change the worktree
+// under other sessions, such as switching its branch or removing it.
+func (s *Service) requireUnsharedWorktree(sessions *tmux.SessionManager, sessionName, wtPath, operation string) error {
+	shared := sessionsSharingWorktree(sessions.Snapshot(), wtPath, sessionName)
+	if len(shared) == 0 {
+		return nil
+	}
+	return fmt.Errorf("cannot %s: worktree is shared with session(s) %s: %s",
+		operation, strings.Join(shared, ", "), wtPath)
+}
+
+// DetectSharedBranchConflicts compares the branch context of every session in
+// a shared worktree with the branch statuses reports for it. Sessions without
+// a status, on a detached HEAD, or in an unshared worktree are skipped.
+func (s *Service) DetectSharedBranchConflicts(statuses map[string]gitpkg.WorkingTreeStatus) []SharedBranchConflict {
+	sessions, err := s.deps.RequireSessions()
+	if err != nil {
+		return nil
+	}
+	snapshots := sessions.Snapshot()
+	var conflicts []SharedBranchConflict
+	for _, snap := range snapshots {
+		if snap.Worktree == nil || snap.Worktree.Path == "" || snap.Worktree.BranchName == "" {
+			continue
+		}
+		status, ok := statuses[snap.Name]
+		if !ok || status.Detached || status.Branch == "" || status.Branch == snap.Worktree.BranchName {
+			continue
+		}
+		shared := sessionsSharingWorktree(snapshots, snap.Worktree.Path, snap.Name)
+		if len(shared) == 0 {
+			continue
+		}
+		conflicts = append(conflicts, SharedBranchConflict{
+			WorktreePath:   snap.Worktree.Path,
+			SessionName:    snap.Name,
+			ExpectedBranch: snap.Worktree.BranchName,
+			ActualBranch:   status.Branch,
+			SharedWith:     shared,
+		})
+	}
+	slices.SortFunc(conflicts, func(a, b SharedBranchConflict) int {
+		return strings.Compare(a.SessionName, b.SessionName)
+	})
+	return conflicts
+}
+
+// WarnSharedBranchConflicts emits BranchConflictEvent for every conflict
+// DetectSharedBranchConflicts finds. A conflict is reported once until the
+// session's branch context or the checked-out branch changes.
+func (s *Service) WarnSharedBranchConflicts(statuses map[string]gitpkg.WorkingTreeStatus) {
+	conflicts := s.DetectSharedBranchConflicts(statuses)
+
+	next := make(map[string]string, len(conflicts))
+	var fresh []SharedBranchConflict
+	s.branchConflictMu.Lock()
+	for _, conflict := range conflicts {
+		key := conflict.ExpectedBranch + "\x00" + conflict.ActualBranch
+		next[conflict.SessionName] = key
+		if s.warnedBranchConflicts[conflict.SessionName] != key {
+			fresh = append(fresh, conflict)
+		}
+	}
+	s.warnedBranchConflicts = next
+	s.branchConflictMu.Unlock()
+
+	for _, conflict := range fresh {
+		slog.Warn("[WARN-GIT] shared worktree switched away from session branch",
+			"session", conflict.SessionName, "expected", conflict.ExpectedBranch,
+			"actual", conflict.ActualBranch, "path", conflict.WorktreePath)
+		s.deps.Emitter.Emit(BranchConflictEvent, conflict)
+	}
+}
@@ -0,0 +1,114 @@
+package worktree
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	gitpkg "myT-x/internal/git"
+	"myT-x/internal/tmux"
+)
+
+// newSharedWorktreeTestService returns a service whose sessions api and web
+// share C:\wt (branch contexts main and feature) and whose session solo has
+// its own worktree.
+func newSharedWorktreeTestService(t *testing.T) (*Service, *mockEmitter, *tmux.SessionManager) {
+	t.Helper()
+	sm := tmux.NewSessionManager()
+	for _, s := range []struct{ name, path, branch string }{
+		{"api", `C:\wt`, "main"},
+		{"web", `c:\WT`, "feature"},
+		{"solo", `C:\solo`, "main"},
+	} {
+		if _, _, err := sm.CreateSession(s.name, "0", 120, 40); err != nil {
+			t.Fatalf("CreateSession(%s) error = %v", s.name, err)
+		}
+		if err := sm.SetWorktreeInfo(s.name, &tmux.SessionWorktreeInfo{
+			Path: s.path, RepoPath: `C:\repo`, BranchName: s.branch,
+		}); err != nil {
+			t.Fatalf("SetWorktreeInfo(%s) error = %v", s.name, err)
+		}
+	}
+	svc, emitter := newTestServiceForSetup(t)
+	svc.deps.RequireSessions = func() (*tmux.SessionManager, error) { return sm, nil }
+	return svc, emitter, sm
+}
+
+func TestDetectSharedBranchConflicts(t *testing.T) {
+	svc, _, _ := newSharedWorktreeTestService(t)
+
+	conflicts := svc.DetectSharedBranchConflicts(map[string]gitpkg.WorkingTreeStatus{
+		"api":  {Branch: "feature"},
+		"web":  {Branch: "feature"},
+		"solo": {Branch: "other"},
+	})
+	if len(conflicts) != 1 {
+		t.Fatalf("conflicts = %+v, want only api", conflicts)
+	}
+	got := conflicts[0]
+	if got.SessionName != "api" || got.ExpectedBranch != "main" || got.ActualBranch != "feature" ||
+		!slices.Equal(got.SharedWith, []string{"web"}) {
+		t.Fatalf("conflict = %+v", got)
+	}
+
+	detached := svc.DetectSharedBranchConflicts(map[string]gitpkg.WorkingTreeStatus{
+		"api": {Detached: true},
+		"web": {Detached: true},
+	})
+	if len(detached) != 0 {
+		t.Fatalf("detached conflicts = %+v, want none", detached)
+	}
+}
+
+func TestWarnSharedBranchConflictsReportsOnce(t *testing.T) {
+	svc, emitter, _ := newSharedWorktreeTestService(t)
+	count := func() int {
+		n := 0
+		for _, e := range emitter.emittedEvents {
+			if e.Name == BranchConflictEvent {
+				n++
+			}
+		}
+		return n
+	}
+	onFeature := map[string]gitpkg.WorkingTreeStatus{"api": {Branch: "feature"}, "web": {Branch: "feature"}}
+
+	svc.WarnSharedBranchConflicts(onFeature)
+	svc.WarnSharedBranchConflicts(onFeature)
+	if got := count(); got != 1 {
+		t.Fatalf("events after repeated polls = %d, want 1", got)
+	}
+
+	svc.WarnSharedBranchConflicts(map[string]gitpkg.WorkingTreeStatus{"api": {Branch: "main"}, "web": {Branch: "main"}})
+	if got := count(); got != 2 {
+		t.Fatalf("events after switching to main = %d, want 2 (web)", got)
+	}
+	last := emitter.emittedEvents[len(emitter.emittedEvents)-1].Payload.(SharedBranchConflict)
+	if last.SessionName != "web" || last.ActualBranch != "main" {
+		t.Fatalf("last conflict = %+v, want web on main", last)
+	}
+
+	svc.WarnSharedBranchConflicts(map[string]gitpkg.WorkingTreeStatus{"api": {Branch: "main"}, "web": {Branch: "feature"}})
+	svc.WarnSharedBranchConflicts(onFeature)
+	if got := count(); got != 3 {
+		t.Fatalf("events after the conflict cleared and returned = %d, want 3", got)
+	}
+}
+
+func TestSharedWorktreeBlocksPromoteAndCleanup(t *testing.T) {
+	svc, _, sm := newSharedWorktreeTestService(t)
+	if err := sm.SetWorktreeInfo("api", &tmux.SessionWorktreeInfo{
+		Path: `C:\wt`, RepoPath: `C:\repo`, IsDetached: true,
+	}); err != nil {
+		t.Fatalf("SetWorktreeInfo(api) error = %v", err)
+	}
+
+	err := svc.PromoteWorktreeToBranch("api", "feature-2", PromoteWorktreeOptions{})
+	if err == nil || !strings.Contains(err.Error(), "shared with session(s) web") {
+		t.Fatalf("PromoteWorktreeToBranch() error = %v, want shared worktree error", err)
+	}
+	err = svc.CleanupWorktree("web")
+	if err == nil || !strings.Contains(err.Error(), "shared with session(s) api") {
+		t.Fatalf("CleanupWorktree() error = %v, want shared worktree error", err)
+	}
+}
@@ -0,0 +1,146 @@
+package worktree
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"unicode"
+
+	gitpkg "myT-x/internal/git"
+)
+
+// maxStashMessageLen bounds the user part of a stash message.
+const maxStashMessageLen = 200
+
+// WorktreeStashPopResult is the outcome of PopWorktreeStash.
+type WorktreeStashPopResult struct {
+	// Stash is the entry that was applied, or that conflicted.
+	Stash gitpkg.StashEntry `json:"stash"`
+	// Conflicts lists the files that conflicted with HEAD. The worktree is
+	// then rolled back and the stash is kept.
+	Conflicts []string `json:"conflicts"`
+	// Dropped is false when the stash was kept: on conflicts, or when it was
+	// applied but could not be dropped.
+	Dropped bool `json:"dropped"`
+}
+
+// sessionStashMarker tags the stashes of a session. The stash list is shared
+// by every worktree of a repository, so PopWorktreeStash finds the session's
+// own entries by this marker.
+func sessionStashMarker(sessionName string) string {
+	return "myT-x[" + sessionName + "]"
+}
+
+// StashWorktree stashes the tracked and untracked changes of the session's
+// worktree, e.g. before CleanupWorktree or a branch switch.
+func (s *Service) StashWorktree(sessionName, message string) (gitpkg.StashEntry, error) {
+	sessionName = strings.TrimSpace(sessionName)
+	if sessionName == "" {
+		return gitpkg.StashEntry{}, errors.New("session name is required")
+	}
+	message = strings.TrimSpace(message)
+	if strings.ContainsFunc(message, unicode.IsControl) || len(message) > maxStashMessageLen {
+		return gitpkg.StashEntry{}, fmt.Errorf("stash message must be a single line of at most %d characters", maxStashMessageLen)
+	}
+	sessions, err := s.deps.RequireSessions()
+	if err != nil {
+		return gitpkg.StashEntry{}, err
+	}
+	worktreeInfo, err := s.requireWorktreeInfo(sessionName)
+	if err != nil {
+		return gitpkg.StashEntry{}, err
+	}
+	wtPath := worktreeInfo.Path
+	// Stashing would take the changes of every session in the worktree.
+	if err := s.requireUnsharedWorktree(sessions, sessionName, wtPath, "stash worktree"); err != nil {
+		return gitpkg.StashEntry{}, err
+	}
+	wtRepo, err := gitpkg.Open(wtPath)
+	if err != nil {
+		return gitpkg.StashEntry{}, fmt.Errorf("failed to open worktree: %w", err)
+	}
+
+	fullMessage := sessionStashMarker(sessionName)
+	if message != "" {
+		fullMessage += " " + message
+	}
+	entry, err := wtRepo.StashPush(fullMessage)
+	if err != nil {
+		return gitpkg.StashEntry{}, err
+	}
+	slog.Debug("[DEBUG-GIT] worktree stashed",
+		"session", sessionName, "stash", entry.Commit, "path", wtPath)
+	return entry, nil
+}
+
+// PopWorktreeStash applies the newest stash StashWorktree created for the
+// session and drops it. The worktree must be clean. When the stash conflicts
+// with HEAD, the worktree is rolled back, the stash is kept, and the
+// conflicted files are reported in the result.
+func (s *Service) PopWorktreeStash(sessionName string) (WorktreeStashPopResult, error) {
+	sessionName = strings.TrimSpace(sessionName)
+	if sessionName == "" {
+		return WorktreeStashPopResult{}, errors.New("session name is required")
+	}
+	sessions, err := s.deps.RequireSessions()
+	if err != nil {
+		return WorktreeStashPopResult{}, err
+	}
+	worktreeInfo, err := s.requireWorktreeInfo(sessionName)
+	if err != nil {
+		return WorktreeStashPopResult{}, err
+	}
+	wtPath := worktreeInfo.Path
+	if err := s.requireUnsharedWorktree(sessions, sessionName, wtPath, "pop stash"); err != nil {
+		return WorktreeStashPopResult{}, err
+	}
+	wtRepo, err := gitpkg.Open(wtPath)
+	if err != nil {
+		return WorktreeStashPopResult{}, fmt.Errorf("failed to open worktree: %w", err)
+	}
+
+	stashes, err := wtRepo.ListStashes()
+	if err != nil {
+		return WorktreeStashPopResult{}, err
+	}
+	marker := sessionStashMarker(sessionName)
+	idx := -1
+	for i, stash := range stashes {
+		// Message is "On <branch>: <message>" or "WIP on <branch>: ...".
+		if _, msg, ok := strings.Cut(stash.Message, ": "); ok && strings.HasPrefix(msg, marker) {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return WorktreeStashPopResult{}, fmt.Errorf("session %s has no stash", sessionName)
+	}
+	result := WorktreeStashPopResult{Stash: stashes[idx]}
+
+	conflicts, err := wtRepo.ApplyStash(result.Stash.Commit)
+	if errors.Is(err, gitpkg.ErrWorktreeHasUncommittedChanges) {
+		return WorktreeStashPopResult{}, errors.New("commit or stash the current changes before popping a stash")
+	}
+	if err != nil {
+		return WorktreeStashPopResult{}, err
+	}
+	if len(conflicts) > 0 {
+		slog.Warn("[WARN-GIT] worktree stash conflicts with HEAD, rolled back",
+			"session", sessionName, "stash", result.Stash.Commit, "conflicts", conflicts)
+		result.Conflicts = conflicts
+		return result, nil
+	}
+
+	// The changes are back in the worktree; a failed drop only leaves a
+	// redundant stash behind.
+	if err := wtRepo.DropStash(result.Stash.Commit); err != nil {
+		slog.Warn("[WARN-GIT] failed to drop applied worktree stash",
+			"session", sessionName, "stash", result.Stash.Commit, "error", err)
+		return result, nil
+	}
+	result.Dropped = true
+	slog.Debug("[DEBUG-GIT] worktree stash popped",
+		"session", sessionName, "stash", result.Stash.Commit, "path", wtPath)
+	return result, nil
+}
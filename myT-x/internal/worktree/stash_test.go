@@ -0,0 +1,89 @@
+package worktree
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	gitpkg "myT-x/internal/git"
+	"myT-x/internal/testutil"
+	"myT-x/internal/tmux"
+)
+
+func TestStashAndPopWorktreeStash(t *testing.T) {
+	repoPath := testutil.CreateTempGitRepo(t)
+	repo, err := gitpkg.Open(repoPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wtPath := filepath.Join(t.TempDir(), "feature")
+	if err := repo.CreateWorktree(wtPath, "feature", "HEAD"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = repo.RemoveWorktreeForced(wtPath) })
+
+	sm := tmux.NewSessionManager()
+	for _, name := range []string{"feature", "other"} {
+		if _, _, err := sm.CreateSession(name, "0", 120, 40); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := sm.SetWorktreeInfo("feature", &tmux.SessionWorktreeInfo{
+		Path: wtPath, RepoPath: repoPath, BranchName: "feature",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	svc, _ := newTestServiceForSetup(t)
+	svc.deps.RequireSessions = func() (*tmux.SessionManager, error) { return sm, nil }
+
+	// The stash list is shared by the repository's worktrees; a stash of the
+	// main worktree must not be popped for the session.
+	readme := filepath.Join(repoPath, "develop-README.md")
+	if err := os.WriteFile(readme, []byte("main change"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.StashPush("main worktree"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := svc.PopWorktreeStash("feature"); err == nil || !strings.Contains(err.Error(), "has no stash") {
+		t.Fatalf("PopWorktreeStash() without a stash error = %v", err)
+	}
+	if _, err := svc.StashWorktree("feature", "bad\nmessage"); err == nil {
+		t.Fatal("StashWorktree() should reject a multi-line message")
+	}
+
+	wtFile := filepath.Join(wtPath, "work.txt")
+	if err := os.WriteFile(wtFile, []byte("wip"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	entry, err := svc.StashWorktree("feature", "before cleanup")
+	if err != nil {
+		t.Fatalf("StashWorktree() error = %v", err)
+	}
+	if !strings.Contains(entry.Message, "myT-x[feature] before cleanup") {
+		t.Fatalf("stash message = %q", entry.Message)
+	}
+	if _, err := os.Stat(wtFile); !os.IsNotExist(err) {
+		t.Fatalf("work.txt still present after stash: %v", err)
+	}
+
+	result, err := svc.PopWorktreeStash("feature")
+	if err != nil {
+		t.Fatalf("PopWorktreeStash() error = %v", err)
+	}
+	if result.Stash.Commit != entry.Commit || !result.Dropped || len(result.Conflicts) != 0 {
+		t.Fatalf("PopWorktreeStash() = %+v", result)
+	}
+	if data, _ := os.ReadFile(wtFile); string(data) != "wip" {
+		t.Fatalf("work.txt = %q, want it restored", data)
+	}
+	stashes, err := repo.ListStashes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stashes) != 1 || !strings.HasSuffix(stashes[0].Message, "main worktree") {
+		t.Fatalf("stashes = %+v, want only the main worktree stash", stashes)
+	}
+}
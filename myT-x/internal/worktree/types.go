@@ -1,5 +1,10 @@
 package worktree
 
+import (
+	"fmt"
+	"strings"
+)
+
 // WorktreeSessionOptions holds options for creating a session with a worktree.
 //
 // Mode semantics (invariant):
@@ -10,14 +15,39 @@ package worktree
 // (via CreateSessionWithExistingWorktree) are still supported and can be
 // promoted via PromoteWorktreeToBranch.
 type WorktreeSessionOptions struct {
-	BranchName            string `json:"branch_name"`              // required: branch name for the new worktree
-	BaseBranch            string `json:"base_branch"`              // empty = current HEAD
-	PullBeforeCreate      bool   `json:"pull_before_create"`       // pull latest before creating worktree
-	ContinueOnPullFailure bool   `json:"continue_on_pull_failure"` // best-effort pull: continue with local state when pull fails
-	EnableAgentTeam       bool   `json:"enable_agent_team"`        // set Agent Teams env vars on initial pane
-	UseClaudeEnv          bool   `json:"use_claude_env"`           // apply claude_env config to panes
-	UsePaneEnv            bool   `json:"use_pane_env"`             // apply pane_env config to additional panes
-	UseSessionPaneScope   bool   `json:"use_session_pane_scope"`   // set MYTX_SESSION on panes + scope list-panes
+	BranchName            string `json:"branch_name"`                     // required: branch name for the new worktree
+	BaseBranch            string `json:"base_branch"`                     // empty = current HEAD
+	PullBeforeCreate      bool   `json:"pull_before_create"`              // pull latest before creating worktree
+	ContinueOnPullFailure bool   `json:"continue_on_pull_failure"`        // best-effort pull: continue with local state when pull fails
+	EnableAgentTeam       bool   `json:"enable_agent_team"`               // set Agent Teams env vars on initial pane
+	UseClaudeEnv          bool   `json:"use_claude_env"`                  // apply claude_env config to panes
+	UsePaneEnv            bool   `json:"use_pane_env"`                    // apply pane_env config to additional panes
+	UseSessionPaneScope   bool   `json:"use_session_pane_scope"`          // set MYTX_SESSION on panes + scope list-panes
+	StartupCommand        string `json:"startup_command,omitempty"`       // run in the initial pane; empty = trusted .mytx.yaml, then config startup_commands.session
+	RemainOnExit          bool   `json:"remain_on_exit,omitempty"`        // keep the initial pane open after the startup command exits
+	IsolateShellHistory   bool   `json:"isolate_shell_history,omitempty"` // keep shell history in the session's state directory
+	Ephemeral             bool   `json:"ephemeral,omitempty"`             // remove the session and its worktree after the configured TTL or on app exit (handled by the app)
+}
+
+// PromoteWorktreeOptions holds options for PromoteWorktreeToBranch.
+type PromoteWorktreeOptions struct {
+	// RebaseOntoBase fetches the base branch and rebases the detached commits
+	// onto it before the branch is created.
+	RebaseOntoBase bool `json:"rebase_onto_base"`
+	// BaseBranch overrides the session's base branch. It becomes the base
+	// branch of the promoted session.
+	BaseBranch string `json:"base_branch,omitempty"`
+}
+
+// PromoteConflictError reports that the rebase before a promotion conflicted.
+// The rebase was aborted and HEAD is back at its prior commit.
+type PromoteConflictError struct {
+	BaseRef   string
+	Conflicts []string
+}
+
+func (e *PromoteConflictError) Error() string {
+	return fmt.Sprintf("rebase onto %s conflicted, HEAD was restored: %s", e.BaseRef, strings.Join(e.Conflicts, ", "))
 }
 
 // WorktreeStatus holds the pre-close status of a worktree session.
@@ -27,16 +57,21 @@ type WorktreeStatus struct {
 	HasUnpushed    bool   `json:"has_unpushed"`
 	BranchName     string `json:"branch_name"`
 	IsDetached     bool   `json:"is_detached"`
+	Locked         bool   `json:"locked"`
+	LockReason     string `json:"lock_reason,omitempty"`
 }
 
 // SessionEnvOptions holds environment configuration options for session creation.
 // This mirrors the relevant fields from main.CreateSessionOptions to avoid
 // circular package imports between main and internal/worktree.
 type SessionEnvOptions struct {
-	EnableAgentTeam     bool `json:"enable_agent_team"`      // set Agent Teams env vars on initial pane
-	UseClaudeEnv        bool `json:"use_claude_env"`         // apply claude_env config to panes
-	UsePaneEnv          bool `json:"use_pane_env"`           // apply pane_env config to additional panes
-	UseSessionPaneScope bool `json:"use_session_pane_scope"` // set MYTX_SESSION on panes + scope list-panes
+	EnableAgentTeam     bool   `json:"enable_agent_team"`               // set Agent Teams env vars on initial pane
+	UseClaudeEnv        bool   `json:"use_claude_env"`                  // apply claude_env config to panes
+	UsePaneEnv          bool   `json:"use_pane_env"`                    // apply pane_env config to additional panes
+	UseSessionPaneScope bool   `json:"use_session_pane_scope"`          // set MYTX_SESSION on panes + scope list-panes
+	StartupCommand      string `json:"startup_command,omitempty"`       // run in the initial pane; empty = trusted .mytx.yaml, then config startup_commands.session
+	RemainOnExit        bool   `json:"remain_on_exit,omitempty"`        // keep the initial pane open after the startup command exits
+	IsolateShellHistory bool   `json:"isolate_shell_history,omitempty"` // keep shell history in the session's state directory
 }
 
 // copyWalkBudget tracks resource consumption during directory copy operations.
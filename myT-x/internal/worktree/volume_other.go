@@ -0,0 +1,9 @@
+//go:build !windows
+
+package worktree
+
+// detachableVolumeKind always reports "": drive types are only detected on
+// Windows.
+func detachableVolumeKind(string) string {
+	return ""
+}
@@ -0,0 +1,31 @@
+//go:build windows
+
+package worktree
+
+import (
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/sys/windows"
+)
+
+// detachableVolumeKind reports "removable drive" or "network drive" when path
+// lives on a volume that can disappear while the app runs, and "" otherwise.
+func detachableVolumeKind(path string) string {
+	volume := filepath.VolumeName(path)
+	if volume == "" {
+		return ""
+	}
+	root, err := windows.UTF16PtrFromString(strings.TrimRight(volume, `\/`) + `\`)
+	if err != nil {
+		return ""
+	}
+	switch windows.GetDriveType(root) {
+	case windows.DRIVE_REMOVABLE:
+		return "removable drive"
+	case windows.DRIVE_REMOTE:
+		return "network drive"
+	default:
+		return ""
+	}
+}
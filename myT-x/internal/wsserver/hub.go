@@ -2,6 +2,7 @@ package wsserver
 
 import (
 	"context"
+	"crypto/rand"
 	"encoding/json"
 	"fmt"
 	"log/slog"
@@ -12,6 +13,8 @@ import (
 	"time"
 
 	"github.com/gorilla/websocket"
+
+	"myT-x/internal/screensync"
 )
 
 // writeDeadline is the maximum time allowed for a single WebSocket write to
@@ -49,13 +52,25 @@ var wsUpgrader = websocket.Upgrader{
 }
 
 // HubOptions configures the WebSocket server.
-// Struct with a single field is intentional for future extensibility
-// (e.g., TLS config, buffer sizes, timeouts).
 type HubOptions struct {
 	// Addr is the listen address. Use "127.0.0.1:0" for OS-assigned port.
 	// 127.0.0.1 binding restricts access to localhost only, which is safe for
 	// a desktop application where frontend and backend run on the same machine.
 	Addr string
+
+	// ScreenSource returns the emulated screen of a pane for the view-only
+	// screen sync endpoint. Optional: the endpoint is not served when nil.
+	ScreenSource func(paneID string) (screensync.Screen, bool)
+
+	// LocatePane returns the session and window of a pane so stream clients
+	// can subscribe by session or window. Optional: without it only pane
+	// and "*" topics match.
+	LocatePane func(paneID string) (PaneLocation, bool)
+
+	// StreamEnabled reports whether the /stream endpoint accepts clients
+	// (feature flag external_stream). It is checked on every connection.
+	// Optional: nil means the endpoint is always served.
+	StreamEnabled func() bool
 }
 
 // Hub manages a single WebSocket connection for streaming pane terminal output
@@ -90,6 +105,18 @@ type Hub struct {
 	server   *http.Server
 	url      string // "ws://127.0.0.1:<port>/ws", set after Start
 
+	// screenMu protects screenViewers. Independent of mu and writeMu.
+	screenMu      sync.Mutex
+	screenViewers map[*screenViewer]struct{}
+	screenURL     string // "ws://127.0.0.1:<port>/screen", set after Start
+
+	// streamMu protects streamClients. Independent of mu, writeMu and screenMu.
+	streamMu      sync.Mutex
+	streamClients map[*streamClient]struct{}
+	streamURL     string // "ws://127.0.0.1:<port>/stream", set after Start
+	// streamToken authenticates stream clients for this run. Immutable.
+	streamToken string
+
 	// closeOnce ensures Stop is idempotent. Once Stop has been called,
 	// the Hub cannot be reused; create a new Hub instance instead.
 	closeOnce sync.Once
@@ -121,8 +148,11 @@ func NewHub(opts HubOptions) *Hub {
 		opts.Addr = "127.0.0.1:0"
 	}
 	return &Hub{
-		opts:       opts,
-		subscribed: make(map[string]bool),
+		opts:          opts,
+		subscribed:    make(map[string]bool),
+		screenViewers: make(map[*screenViewer]struct{}),
+		streamClients: make(map[*streamClient]struct{}),
+		streamToken:   rand.Text(),
 	}
 }
 
@@ -151,6 +181,12 @@ func (h *Hub) Start(ctx context.Context) error {
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("/ws", h.handleWS)
+	if h.opts.ScreenSource != nil {
+		h.screenURL = fmt.Sprintf("ws://127.0.0.1:%d%s", port, screenPath)
+		mux.HandleFunc(screenPath, h.handleScreenWS)
+	}
+	h.streamURL = fmt.Sprintf("ws://127.0.0.1:%d%s", port, streamPath)
+	mux.HandleFunc(streamPath, h.handleStreamWS)
 
 	h.server = &http.Server{
 		Handler: mux,
@@ -186,6 +222,8 @@ func (h *Hub) Stop() error {
 				slog.Debug("[DEBUG-WS] connection close during stop", "error", err)
 			}
 		}
+		h.closeScreenViewers()
+		h.closeStreamClients()
 
 		// Shutdown HTTP server with timeout.
 		if h.server != nil {
@@ -10,6 +10,42 @@
 //   - Remaining bytes: raw terminal data (may be empty).
 //
 // EncodePaneData produces frames in this format; DecodePaneData parses them.
+//
+// # Screen sync protocol
+//
+// The optional /screen endpoint serves view-only viewers with JSON text
+// messages instead of raw bytes. A viewer sends {"action":"watch","paneIds":[...]}
+// and receives a screensync.Frame keyframe with the full screen, followed by
+// cell-level diffs and periodic keyframes. The viewer acknowledges applied
+// frames with {"action":"ack","paneId":...,"seq":...}; at most
+// screensync.DefaultWindow frames stay unacknowledged, and changes made while
+// the window is full are coalesced into the next diff. A viewer that loses
+// track of a pane sends {"action":"resync","paneIds":[...]} to get a keyframe.
+//
+// # Pane stream protocol
+//
+// The /stream endpoint lets any number of clients (up to maxStreamClients)
+// tail raw pane output, e.g. external tools and scripts. The frame format is
+// chosen when connecting: /stream?format=binary (default) sends output in the
+// binary frame format above; /stream?format=json sends text messages
+// {"type":"output","paneId":"%3","data":"<base64>"}.
+//
+// Every connection must carry the per-run access token, as the token query
+// parameter of the URL returned by Hub.StreamURL or as an
+// "Authorization: Bearer <token>" header; others get HTTP 401. Requests
+// with a cross-origin Origin header are rejected, so web pages cannot
+// connect.
+//
+// Clients choose panes by topic with
+// {"action":"subscribe"|"unsubscribe","topics":[...]}, where a topic is "*",
+// "session/<name>", "window/<@id>" or "pane/<%id>". The server answers with
+// {"type":"subscribed","topics":[...]} listing all current topics, or an
+// error message.
+//
+// Each client has a bounded queue. Output that arrives while it is full is
+// dropped rather than slowing down other clients or the terminal, and the
+// gap is reported in both formats as a text message
+// {"type":"dropped","paneId":"%3","bytes":N} at the point where it occurred.
 package wsserver
 
 import (
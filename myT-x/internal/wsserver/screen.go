@@ -0,0 +1,298 @@
+package wsserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"myT-x/internal/screensync"
+)
+
+// screenPath is the HTTP path of the view-only screen sync endpoint.
+const screenPath = "/screen"
+
+// screenPollInterval is how often watched panes are checked for changes.
+// 200ms keeps typing responsive for viewers while coalescing bursts of output
+// into one diff, which is what makes the stream usable over slow links.
+const screenPollInterval = 200 * time.Millisecond
+
+// maxScreenViewers limits concurrent screen sync connections. Each viewer
+// costs one poll loop and one stream per watched pane.
+const maxScreenViewers = 8
+
+// Screen sync client actions.
+const (
+	screenWatchAction   = "watch"
+	screenUnwatchAction = "unwatch"
+	screenAckAction     = "ack"
+	screenResyncAction  = "resync"
+)
+
+// screenClientMsg is the JSON payload sent by screen sync viewers.
+// watch, unwatch and resync use PaneIDs; ack uses PaneID and Seq.
+type screenClientMsg struct {
+	Action  string   `json:"action"`
+	PaneIDs []string `json:"paneIds,omitempty"`
+	PaneID  string   `json:"paneId,omitempty"`
+	Seq     uint64   `json:"seq,omitempty"`
+}
+
+// screenViewer is one view-only connection on the screen sync endpoint.
+// Unlike the pane data client, any number of viewers (up to maxScreenViewers)
+// may be connected at once, each with its own streams and write lock.
+//
+// Lock ordering: writeMu and mu are never held together.
+type screenViewer struct {
+	conn *websocket.Conn
+
+	// writeMu serializes WriteMessage calls on conn.
+	writeMu sync.Mutex
+
+	// mu protects streams.
+	mu      sync.Mutex
+	streams map[string]*screensync.Stream // paneID -> stream
+}
+
+// ScreenURL returns the view-only screen sync WebSocket URL
+// (e.g. "ws://127.0.0.1:54321/screen"). Returns empty string if the server
+// has not started or no ScreenSource is configured.
+func (h *Hub) ScreenURL() string {
+	return h.screenURL
+}
+
+// ScreenViewerCount reports the number of connected screen sync viewers.
+func (h *Hub) ScreenViewerCount() int {
+	h.screenMu.Lock()
+	defer h.screenMu.Unlock()
+	return len(h.screenViewers)
+}
+
+// addScreenViewer registers v unless the viewer limit is reached.
+func (h *Hub) addScreenViewer(v *screenViewer) bool {
+	h.screenMu.Lock()
+	defer h.screenMu.Unlock()
+	if len(h.screenViewers) >= maxScreenViewers {
+		return false
+	}
+	h.screenViewers[v] = struct{}{}
+	return true
+}
+
+func (h *Hub) removeScreenViewer(v *screenViewer) {
+	h.screenMu.Lock()
+	delete(h.screenViewers, v)
+	h.screenMu.Unlock()
+}
+
+// closeScreenViewers closes every screen sync connection. Used by Stop.
+func (h *Hub) closeScreenViewers() {
+	h.screenMu.Lock()
+	viewers := make([]*screenViewer, 0, len(h.screenViewers))
+	for v := range h.screenViewers {
+		viewers = append(viewers, v)
+	}
+	h.screenViewers = make(map[*screenViewer]struct{})
+	h.screenMu.Unlock()
+
+	for _, v := range viewers {
+		h.closeConn(v.conn, "hub stopped")
+	}
+}
+
+// handleScreenWS serves the view-only screen sync protocol: after a viewer
+// watches a pane it receives a keyframe with the full screen, then cell-level
+// diffs and periodic keyframes. Viewers acknowledge frames they applied; a
+// viewer that falls behind by a full window gets coalesced diffs instead of
+// every intermediate state.
+func (h *Hub) handleScreenWS(w http.ResponseWriter, r *http.Request) {
+	conn, err := wsUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		slog.Warn("[WARN-WS] screen upgrade failed", "error", err)
+		return
+	}
+
+	viewer := &screenViewer{conn: conn, streams: make(map[string]*screensync.Stream)}
+	if !h.addScreenViewer(viewer) {
+		slog.Warn("[WARN-WS] screen viewer rejected: limit reached", "limit", maxScreenViewers)
+		closeMsg := websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "too many screen viewers")
+		if writeErr := conn.WriteControl(websocket.CloseMessage, closeMsg, time.Now().Add(writeDeadline)); writeErr != nil {
+			slog.Debug("[DEBUG-WS] failed to send screen viewer rejection", "error", writeErr)
+		}
+		h.closeConn(conn, "screen viewer limit")
+		return
+	}
+
+	conn.SetReadLimit(maxReadMessageSize)
+	if err := conn.SetReadDeadline(time.Now().Add(readDeadline)); err != nil {
+		slog.Warn("[WARN-WS] SetReadDeadline failed on screen viewer", "error", err)
+		h.removeScreenViewer(viewer)
+		h.closeConn(conn, "initial SetReadDeadline failure")
+		return
+	}
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(readDeadline))
+	})
+
+	slog.Debug("[DEBUG-WS] screen viewer connected", "remoteAddr", conn.RemoteAddr())
+
+	pushDone := make(chan struct{})
+	go h.screenPushLoop(viewer, pushDone)
+
+	defer func() {
+		if rec := recover(); rec != nil {
+			slog.Error("[ERROR-PANIC] wsserver handleScreenWS recovered",
+				"panic", rec,
+				"stack", string(debug.Stack()),
+			)
+		}
+		close(pushDone)
+		h.removeScreenViewer(viewer)
+		h.closeConn(conn, "screen read pump exit")
+		slog.Debug("[DEBUG-WS] screen viewer disconnected")
+	}()
+
+	for {
+		msgType, msg, readErr := conn.ReadMessage()
+		if readErr != nil {
+			if websocket.IsUnexpectedCloseError(readErr, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
+				slog.Warn("[WARN-WS] screen read error", "error", readErr)
+			}
+			return
+		}
+		if msgType != websocket.TextMessage {
+			continue
+		}
+
+		var clientMsg screenClientMsg
+		if jsonErr := json.Unmarshal(msg, &clientMsg); jsonErr != nil {
+			slog.Debug("[DEBUG-WS] invalid JSON from screen viewer", "error", jsonErr)
+			viewer.writeJSON(h, errorMsg{Type: "error", Message: fmt.Sprintf("invalid JSON: %s", jsonErr)})
+			continue
+		}
+		viewer.handleMessage(clientMsg)
+	}
+}
+
+// handleMessage applies a viewer's watch, unwatch, ack or resync request.
+func (v *screenViewer) handleMessage(msg screenClientMsg) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	switch msg.Action {
+	case screenWatchAction:
+		for _, id := range msg.PaneIDs {
+			if id == "" {
+				continue
+			}
+			if _, ok := v.streams[id]; !ok {
+				v.streams[id] = screensync.NewStream(id, screensync.Options{})
+			}
+		}
+	case screenUnwatchAction:
+		for _, id := range msg.PaneIDs {
+			delete(v.streams, id)
+		}
+	case screenAckAction:
+		if stream := v.streams[msg.PaneID]; stream != nil {
+			stream.Ack(msg.Seq)
+		}
+	case screenResyncAction:
+		for _, id := range msg.PaneIDs {
+			if stream := v.streams[id]; stream != nil {
+				stream.RequestKeyframe()
+			}
+		}
+	default:
+		slog.Debug("[DEBUG-WS] unknown screen action", "action", msg.Action)
+	}
+}
+
+// screenPushLoop polls the watched panes and sends frames until done is
+// closed or a write fails. It also sends the keepalive pings for the viewer.
+func (h *Hub) screenPushLoop(v *screenViewer, done <-chan struct{}) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			slog.Error("[ERROR-PANIC] wsserver screenPushLoop recovered",
+				"panic", rec,
+				"stack", string(debug.Stack()),
+			)
+			h.closeConn(v.conn, "screenPushLoop panic recovery")
+		}
+	}()
+
+	pollTicker := time.NewTicker(screenPollInterval)
+	defer pollTicker.Stop()
+	pingTicker := time.NewTicker(pingInterval)
+	defer pingTicker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case now := <-pollTicker.C:
+			for _, frame := range v.collectFrames(h.opts.ScreenSource, now) {
+				if !v.writeJSON(h, frame) {
+					return
+				}
+			}
+		case <-pingTicker.C:
+			if !v.write(h, websocket.PingMessage, nil) {
+				return
+			}
+		}
+	}
+}
+
+// collectFrames returns the pending frame of every watched pane.
+func (v *screenViewer) collectFrames(source func(paneID string) (screensync.Screen, bool), now time.Time) []screensync.Frame {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	var frames []screensync.Frame
+	for paneID, stream := range v.streams {
+		screen, ok := source(paneID)
+		if !ok {
+			continue
+		}
+		if frame, ok := stream.Next(screen, now); ok {
+			frames = append(frames, frame)
+		}
+	}
+	return frames
+}
+
+// writeJSON marshals payload and sends it as a text message.
+func (v *screenViewer) writeJSON(h *Hub, payload any) bool {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		slog.Debug("[DEBUG-WS] failed to marshal screen message", "error", err)
+		return true
+	}
+	return v.write(h, websocket.TextMessage, data)
+}
+
+// write sends one message. On failure the connection is closed, which ends
+// the read pump and unregisters the viewer.
+func (v *screenViewer) write(h *Hub, messageType int, data []byte) bool {
+	v.writeMu.Lock()
+	defer v.writeMu.Unlock()
+
+	if err := v.conn.SetWriteDeadline(time.Now().Add(writeDeadline)); err != nil {
+		slog.Warn("[WARN-WS] screen SetWriteDeadline failed, closing viewer", "error", err)
+		h.closeConn(v.conn, "screen SetWriteDeadline failure")
+		return false
+	}
+	if err := v.conn.WriteMessage(messageType, data); err != nil {
+		slog.Debug("[DEBUG-WS] screen write failed, closing viewer", "error", err)
+		h.closeConn(v.conn, "screen write error")
+		return false
+	}
+	h.clearWriteDeadline(v.conn)
+	return true
+}
@@ -0,0 +1,142 @@
+package wsserver
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"myT-x/internal/screensync"
+)
+
+// fakeScreens is a mutable ScreenSource for tests.
+type fakeScreens struct {
+	mu      sync.Mutex
+	screens map[string]screensync.Screen
+}
+
+func (f *fakeScreens) set(paneID string, lines ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.screens[paneID] = screensync.Screen{Cols: 20, Rows: len(lines), Lines: lines}
+}
+
+func (f *fakeScreens) source(paneID string) (screensync.Screen, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	screen, ok := f.screens[paneID]
+	return screen, ok
+}
+
+func startScreenHub(t *testing.T) (*Hub, *fakeScreens) {
+	t.Helper()
+	screens := &fakeScreens{screens: map[string]screensync.Screen{}}
+	hub := NewHub(HubOptions{Addr: testListenAddr, ScreenSource: screens.source})
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		if err := hub.Stop(); err != nil {
+			t.Errorf("hub.Stop() returned error: %v", err)
+		}
+		cancel()
+	})
+	if err := hub.Start(ctx); err != nil {
+		t.Fatalf("hub.Start() returned error: %v", err)
+	}
+	return hub, screens
+}
+
+func dialScreen(t *testing.T, hub *Hub) *websocket.Conn {
+	t.Helper()
+	conn, _, err := websocket.DefaultDialer.Dial(hub.ScreenURL(), nil)
+	if err != nil {
+		t.Fatalf("failed to dial screen endpoint: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func sendScreenMsg(t *testing.T, conn *websocket.Conn, msg screenClientMsg) {
+	t.Helper()
+	if err := conn.WriteJSON(msg); err != nil {
+		t.Fatalf("failed to write screen message: %v", err)
+	}
+}
+
+func readFrame(t *testing.T, conn *websocket.Conn) screensync.Frame {
+	t.Helper()
+	if err := conn.SetReadDeadline(time.Now().Add(2 * time.Second)); err != nil {
+		t.Fatalf("SetReadDeadline failed: %v", err)
+	}
+	_, data, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatalf("ReadMessage returned error: %v", err)
+	}
+	var frame screensync.Frame
+	if err := json.Unmarshal(data, &frame); err != nil {
+		t.Fatalf("failed to unmarshal frame %q: %v", data, err)
+	}
+	return frame
+}
+
+func TestScreenURLRequiresSource(t *testing.T) {
+	hub := startHub(t)
+	if got := hub.ScreenURL(); got != "" {
+		t.Fatalf("ScreenURL() = %q, want empty without ScreenSource", got)
+	}
+}
+
+func TestScreenSyncKeyframeDiffAndAck(t *testing.T) {
+	hub, screens := startScreenHub(t)
+	screens.set("%1", "$ ls", "")
+
+	conn := dialScreen(t, hub)
+	sendScreenMsg(t, conn, screenClientMsg{Action: screenWatchAction, PaneIDs: []string{"%1"}})
+
+	keyframe := readFrame(t, conn)
+	if keyframe.Type != screensync.FrameKeyframe || keyframe.PaneID != "%1" ||
+		!slices.Equal(keyframe.Lines, []string{"$ ls", ""}) {
+		t.Fatalf("first frame = %+v, want keyframe with full screen", keyframe)
+	}
+	sendScreenMsg(t, conn, screenClientMsg{Action: screenAckAction, PaneID: "%1", Seq: keyframe.Seq})
+
+	screens.set("%1", "$ ls", "a.txt")
+	diff := readFrame(t, conn)
+	if diff.Type != screensync.FrameDiff || diff.BaseSeq != keyframe.Seq {
+		t.Fatalf("second frame = %+v, want diff on keyframe", diff)
+	}
+	lines, err := screensync.Apply(keyframe.Lines, diff.Changes)
+	if err != nil || !slices.Equal(lines, []string{"$ ls", "a.txt"}) {
+		t.Fatalf("applied diff = %q, %v", lines, err)
+	}
+
+	sendScreenMsg(t, conn, screenClientMsg{Action: screenResyncAction, PaneIDs: []string{"%1"}})
+	if frame := readFrame(t, conn); frame.Type != screensync.FrameKeyframe {
+		t.Fatalf("frame after resync = %+v, want keyframe", frame)
+	}
+}
+
+func TestScreenSyncSupportsMultipleViewers(t *testing.T) {
+	hub, screens := startScreenHub(t)
+	screens.set("%1", "shared")
+
+	first := dialScreen(t, hub)
+	second := dialScreen(t, hub)
+	for _, conn := range []*websocket.Conn{first, second} {
+		sendScreenMsg(t, conn, screenClientMsg{Action: screenWatchAction, PaneIDs: []string{"%1"}})
+		if frame := readFrame(t, conn); frame.Type != screensync.FrameKeyframe {
+			t.Fatalf("frame = %+v, want keyframe", frame)
+		}
+	}
+	if got := hub.ScreenViewerCount(); got != 2 {
+		t.Fatalf("ScreenViewerCount() = %d, want 2", got)
+	}
+
+	_ = first.Close()
+	if !waitForCondition(t, 2*time.Second, func() bool { return hub.ScreenViewerCount() == 1 }) {
+		t.Fatal("viewer was not unregistered after disconnect")
+	}
+}
@@ -0,0 +1,497 @@
+package wsserver
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// streamPath is the HTTP path of the pane output stream endpoint.
+const streamPath = "/stream"
+
+// streamTokenParam is the query parameter carrying the stream access token.
+// Clients may send it as an "Authorization: Bearer" header instead.
+const streamTokenParam = "token"
+
+// streamUpgrader upgrades pane stream connections. Unlike wsUpgrader it keeps
+// gorilla's same-origin check: tools send no Origin header, while a browser
+// page on a foreign origin is rejected even if it learned the token.
+var streamUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 32 * 1024,
+}
+
+// maxStreamClients limits concurrent pane stream connections.
+const maxStreamClients = 16
+
+// Backpressure limits per stream client. Output that arrives while the queue
+// is full is dropped and reported with a dropped notice instead of blocking
+// the flush goroutine that feeds every pane.
+const (
+	streamQueueMaxFrames = 256
+	streamQueueMaxBytes  = 4 * 1024 * 1024
+)
+
+// Stream frame formats, chosen with the "format" query parameter.
+const (
+	streamFormatBinary = "binary"
+	streamFormatJSON   = "json"
+)
+
+// Stream topic prefixes. A topic selects the panes whose output a client
+// receives; streamTopicAll selects every pane.
+const (
+	streamTopicAll     = "*"
+	streamTopicSession = "session/"
+	streamTopicWindow  = "window/"
+	streamTopicPane    = "pane/"
+)
+
+// PaneLocation is the session and window that own a pane.
+type PaneLocation struct {
+	Session string
+	// WindowID is the window identifier in tmux "@N" format.
+	WindowID string
+}
+
+// streamClientMsg is the JSON payload sent by stream clients.
+type streamClientMsg struct {
+	Action string   `json:"action"`
+	Topics []string `json:"topics"`
+}
+
+// streamSubscribedMsg acknowledges a subscription change with the client's
+// full topic list.
+type streamSubscribedMsg struct {
+	Type   string   `json:"type"`
+	Topics []string `json:"topics"`
+}
+
+// streamOutputMsg is one output chunk in the JSON format. Data is base64
+// because a chunk may end inside a multi-byte character.
+type streamOutputMsg struct {
+	Type   string `json:"type"`
+	PaneID string `json:"paneId"`
+	Data   []byte `json:"data"`
+}
+
+// streamDroppedMsg reports output a slow client missed. It is sent in both
+// formats, as a text message, where the gap occurred.
+type streamDroppedMsg struct {
+	Type   string `json:"type"`
+	PaneID string `json:"paneId"`
+	Bytes  int    `json:"bytes"`
+}
+
+type streamMessage struct {
+	messageType int
+	data        []byte
+}
+
+// streamClient is one connection on the pane stream endpoint.
+//
+// Lock ordering: writeMu and mu are never held together.
+type streamClient struct {
+	conn   *websocket.Conn
+	binary bool
+	notify chan struct{}
+
+	// writeMu serializes WriteMessage calls on conn.
+	writeMu sync.Mutex
+
+	// mu protects topics, queue, queuedBytes and dropped.
+	mu          sync.Mutex
+	topics      map[string]struct{}
+	queue       []streamMessage
+	queuedBytes int
+	// dropped counts bytes dropped per pane since the last drain. A pane with
+	// pending drops takes no new output until the notice is queued, so each
+	// notice marks one contiguous gap.
+	dropped map[string]int
+}
+
+// StreamURL returns the pane output stream WebSocket URL with the access
+// token of this run (e.g. "ws://127.0.0.1:54321/stream?token=..."). Returns
+// empty string if the server has not started or the endpoint is disabled.
+func (h *Hub) StreamURL() string {
+	if !h.streamEnabled() || h.streamURL == "" {
+		return ""
+	}
+	return h.streamURL + "?" + streamTokenParam + "=" + url.QueryEscape(h.streamToken)
+}
+
+// streamAuthorized reports whether r carries the stream access token.
+func (h *Hub) streamAuthorized(r *http.Request) bool {
+	token := r.URL.Query().Get(streamTokenParam)
+	if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		token = strings.TrimSpace(bearer)
+	}
+	return token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(h.streamToken)) == 1
+}
+
+// streamEnabled reports whether HubOptions.StreamEnabled allows clients.
+func (h *Hub) streamEnabled() bool {
+	return h.opts.StreamEnabled == nil || h.opts.StreamEnabled()
+}
+
+// StreamClientCount reports the number of connected stream clients.
+func (h *Hub) StreamClientCount() int {
+	h.streamMu.Lock()
+	defer h.streamMu.Unlock()
+	return len(h.streamClients)
+}
+
+func (h *Hub) addStreamClient(c *streamClient) bool {
+	h.streamMu.Lock()
+	defer h.streamMu.Unlock()
+	if len(h.streamClients) >= maxStreamClients {
+		return false
+	}
+	h.streamClients[c] = struct{}{}
+	return true
+}
+
+func (h *Hub) removeStreamClient(c *streamClient) {
+	h.streamMu.Lock()
+	delete(h.streamClients, c)
+	h.streamMu.Unlock()
+}
+
+// closeStreamClients closes every stream connection. Used by Stop.
+func (h *Hub) closeStreamClients() {
+	h.streamMu.Lock()
+	clients := make([]*streamClient, 0, len(h.streamClients))
+	for c := range h.streamClients {
+		clients = append(clients, c)
+	}
+	h.streamClients = make(map[*streamClient]struct{})
+	h.streamMu.Unlock()
+
+	for _, c := range clients {
+		h.closeConn(c.conn, "hub stopped")
+	}
+}
+
+// PublishPaneOutput queues a pane output chunk for every stream client
+// subscribed to one of the pane's topics. It never blocks: a client whose
+// queue is full misses the chunk and later receives a dropped notice.
+//
+// Called from OutputFlushManager's flush goroutine alongside BroadcastPaneData.
+func (h *Hub) PublishPaneOutput(paneID string, data []byte) {
+	if len(data) == 0 || paneID == "" {
+		return
+	}
+	h.streamMu.Lock()
+	if len(h.streamClients) == 0 {
+		h.streamMu.Unlock()
+		return
+	}
+	clients := make([]*streamClient, 0, len(h.streamClients))
+	for c := range h.streamClients {
+		clients = append(clients, c)
+	}
+	h.streamMu.Unlock()
+
+	topics := h.paneTopics(paneID)
+	for _, c := range clients {
+		c.offer(paneID, data, topics)
+	}
+}
+
+// paneTopics returns the topics that select paneID.
+func (h *Hub) paneTopics(paneID string) []string {
+	topics := []string{streamTopicAll, streamTopicPane + paneID}
+	if h.opts.LocatePane == nil {
+		return topics
+	}
+	if location, ok := h.opts.LocatePane(paneID); ok {
+		topics = append(topics, streamTopicWindow+location.WindowID, streamTopicSession+location.Session)
+	}
+	return topics
+}
+
+// validStreamTopic reports whether topic is "*" or a prefix with a non-empty
+// identifier.
+func validStreamTopic(topic string) bool {
+	if topic == streamTopicAll {
+		return true
+	}
+	for _, prefix := range []string{streamTopicSession, streamTopicWindow, streamTopicPane} {
+		if id, ok := strings.CutPrefix(topic, prefix); ok {
+			return strings.TrimSpace(id) != ""
+		}
+	}
+	return false
+}
+
+// handleStreamWS serves the pane output stream: clients subscribe to topics
+// and receive the raw output of the matching panes as it is flushed.
+func (h *Hub) handleStreamWS(w http.ResponseWriter, r *http.Request) {
+	if !h.streamEnabled() {
+		http.Error(w, "pane output stream is disabled; enable the external_stream feature flag", http.StatusNotFound)
+		return
+	}
+	if !h.streamAuthorized(r) {
+		slog.Warn("[WARN-WS] stream client rejected: missing or invalid token", "remoteAddr", r.RemoteAddr)
+		http.Error(w, "missing or invalid stream token", http.StatusUnauthorized)
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = streamFormatBinary
+	}
+	if format != streamFormatBinary && format != streamFormatJSON {
+		http.Error(w, fmt.Sprintf("unsupported format %q: want binary or json", format), http.StatusBadRequest)
+		return
+	}
+
+	conn, err := streamUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		slog.Warn("[WARN-WS] stream upgrade failed", "error", err)
+		return
+	}
+
+	client := &streamClient{
+		conn:    conn,
+		binary:  format == streamFormatBinary,
+		notify:  make(chan struct{}, 1),
+		topics:  make(map[string]struct{}),
+		dropped: make(map[string]int),
+	}
+	if !h.addStreamClient(client) {
+		slog.Warn("[WARN-WS] stream client rejected: limit reached", "limit", maxStreamClients)
+		closeMsg := websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "too many stream clients")
+		if writeErr := conn.WriteControl(websocket.CloseMessage, closeMsg, time.Now().Add(writeDeadline)); writeErr != nil {
+			slog.Debug("[DEBUG-WS] failed to send stream client rejection", "error", writeErr)
+		}
+		h.closeConn(conn, "stream client limit")
+		return
+	}
+
+	conn.SetReadLimit(maxReadMessageSize)
+	if err := conn.SetReadDeadline(time.Now().Add(readDeadline)); err != nil {
+		slog.Warn("[WARN-WS] SetReadDeadline failed on stream client", "error", err)
+		h.removeStreamClient(client)
+		h.closeConn(conn, "initial SetReadDeadline failure")
+		return
+	}
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(readDeadline))
+	})
+
+	slog.Debug("[DEBUG-WS] stream client connected", "remoteAddr", conn.RemoteAddr(), "format", format)
+
+	writeDone := make(chan struct{})
+	go h.streamWriteLoop(client, writeDone)
+
+	defer func() {
+		if rec := recover(); rec != nil {
+			slog.Error("[ERROR-PANIC] wsserver handleStreamWS recovered",
+				"panic", rec,
+				"stack", string(debug.Stack()),
+			)
+		}
+		close(writeDone)
+		h.removeStreamClient(client)
+		h.closeConn(conn, "stream read pump exit")
+		slog.Debug("[DEBUG-WS] stream client disconnected")
+	}()
+
+	for {
+		msgType, msg, readErr := conn.ReadMessage()
+		if readErr != nil {
+			if websocket.IsUnexpectedCloseError(readErr, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
+				slog.Warn("[WARN-WS] stream read error", "error", readErr)
+			}
+			return
+		}
+		if msgType != websocket.TextMessage {
+			continue
+		}
+
+		var clientMsg streamClientMsg
+		if jsonErr := json.Unmarshal(msg, &clientMsg); jsonErr != nil {
+			slog.Debug("[DEBUG-WS] invalid JSON from stream client", "error", jsonErr)
+			client.writeJSON(h, errorMsg{Type: "error", Message: fmt.Sprintf("invalid JSON: %s", jsonErr)})
+			continue
+		}
+		reply, handleErr := client.handleMessage(clientMsg)
+		if handleErr != nil {
+			client.writeJSON(h, errorMsg{Type: "error", Message: handleErr.Error()})
+			continue
+		}
+		client.writeJSON(h, reply)
+	}
+}
+
+// handleMessage applies a subscribe or unsubscribe request. Invalid topics
+// reject the whole request.
+func (c *streamClient) handleMessage(msg streamClientMsg) (streamSubscribedMsg, error) {
+	for _, topic := range msg.Topics {
+		if !validStreamTopic(topic) {
+			return streamSubscribedMsg{}, fmt.Errorf("invalid topic %q: want *, session/<name>, window/<@id> or pane/<%%id>", topic)
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch msg.Action {
+	case subscribeAction:
+		for _, topic := range msg.Topics {
+			c.topics[topic] = struct{}{}
+		}
+	case unsubscribeAction:
+		for _, topic := range msg.Topics {
+			delete(c.topics, topic)
+		}
+	default:
+		return streamSubscribedMsg{}, fmt.Errorf("unknown action %q: want subscribe or unsubscribe", msg.Action)
+	}
+	topics := make([]string, 0, len(c.topics))
+	for topic := range c.topics {
+		topics = append(topics, topic)
+	}
+	slices.Sort(topics)
+	return streamSubscribedMsg{Type: "subscribed", Topics: topics}, nil
+}
+
+// offer queues data if the client subscribes to one of paneTopics and has
+// room for it; otherwise the bytes are counted as dropped.
+func (c *streamClient) offer(paneID string, data []byte, paneTopics []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !slices.ContainsFunc(paneTopics, func(topic string) bool {
+		_, ok := c.topics[topic]
+		return ok
+	}) {
+		return
+	}
+	if c.dropped[paneID] > 0 ||
+		len(c.queue) >= streamQueueMaxFrames ||
+		c.queuedBytes+len(data) > streamQueueMaxBytes {
+		c.dropped[paneID] += len(data)
+	} else {
+		message, err := c.encode(paneID, data)
+		if err != nil {
+			slog.Warn("[WARN-WS] failed to encode stream output", "paneId", paneID, "error", err)
+			return
+		}
+		c.queue = append(c.queue, message)
+		c.queuedBytes += len(message.data)
+	}
+
+	select {
+	case c.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (c *streamClient) encode(paneID string, data []byte) (streamMessage, error) {
+	if c.binary {
+		frame, err := EncodePaneData(paneID, data)
+		return streamMessage{messageType: websocket.BinaryMessage, data: frame}, err
+	}
+	payload, err := json.Marshal(streamOutputMsg{Type: "output", PaneID: paneID, Data: data})
+	return streamMessage{messageType: websocket.TextMessage, data: payload}, err
+}
+
+// drain takes the queued messages followed by the dropped notices. Drops
+// happen only while the queue is full, so each notice belongs after the
+// queued output of its pane.
+func (c *streamClient) drain() []streamMessage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	messages := c.queue
+	c.queue = nil
+	c.queuedBytes = 0
+	paneIDs := make([]string, 0, len(c.dropped))
+	for paneID := range c.dropped {
+		paneIDs = append(paneIDs, paneID)
+	}
+	slices.Sort(paneIDs)
+	for _, paneID := range paneIDs {
+		payload, err := json.Marshal(streamDroppedMsg{Type: "dropped", PaneID: paneID, Bytes: c.dropped[paneID]})
+		if err != nil {
+			continue
+		}
+		messages = append(messages, streamMessage{messageType: websocket.TextMessage, data: payload})
+	}
+	clear(c.dropped)
+	return messages
+}
+
+// streamWriteLoop sends queued output until done is closed or a write fails.
+// It also sends the keepalive pings for the client.
+func (h *Hub) streamWriteLoop(c *streamClient, done <-chan struct{}) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			slog.Error("[ERROR-PANIC] wsserver streamWriteLoop recovered",
+				"panic", rec,
+				"stack", string(debug.Stack()),
+			)
+			h.closeConn(c.conn, "streamWriteLoop panic recovery")
+		}
+	}()
+
+	pingTicker := time.NewTicker(pingInterval)
+	defer pingTicker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-c.notify:
+			for _, message := range c.drain() {
+				if !c.write(h, message.messageType, message.data) {
+					return
+				}
+			}
+		case <-pingTicker.C:
+			if !c.write(h, websocket.PingMessage, nil) {
+				return
+			}
+		}
+	}
+}
+
+// writeJSON marshals payload and sends it as a text message.
+func (c *streamClient) writeJSON(h *Hub, payload any) bool {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		slog.Debug("[DEBUG-WS] failed to marshal stream message", "error", err)
+		return true
+	}
+	return c.write(h, websocket.TextMessage, data)
+}
+
+// write sends one message. On failure the connection is closed, which ends
+// the read pump and unregisters the client.
+func (c *streamClient) write(h *Hub, messageType int, data []byte) bool {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	if err := c.conn.SetWriteDeadline(time.Now().Add(writeDeadline)); err != nil {
+		slog.Warn("[WARN-WS] stream SetWriteDeadline failed, closing client", "error", err)
+		h.closeConn(c.conn, "stream SetWriteDeadline failure")
+		return false
+	}
+	if err := c.conn.WriteMessage(messageType, data); err != nil {
+		slog.Debug("[DEBUG-WS] stream write failed, closing client", "error", err)
+		h.closeConn(c.conn, "stream write error")
+		return false
+	}
+	h.clearWriteDeadline(c.conn)
+	return true
+}
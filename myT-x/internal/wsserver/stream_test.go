@@ -0,0 +1,277 @@
+package wsserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func startStreamHub(t *testing.T, locate func(paneID string) (PaneLocation, bool)) *Hub {
+	t.Helper()
+	hub := NewHub(HubOptions{Addr: testListenAddr, LocatePane: locate})
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		if err := hub.Stop(); err != nil {
+			t.Errorf("hub.Stop() returned error: %v", err)
+		}
+		cancel()
+	})
+	if err := hub.Start(ctx); err != nil {
+		t.Fatalf("hub.Start() returned error: %v", err)
+	}
+	return hub
+}
+
+func dialStream(t *testing.T, hub *Hub, format string) *websocket.Conn {
+	t.Helper()
+	url := hub.StreamURL()
+	if format != "" {
+		url += "&format=" + format
+	}
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("failed to dial stream endpoint: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	waitForCondition(t, 2*time.Second, func() bool { return hub.StreamClientCount() > 0 })
+	return conn
+}
+
+func readStreamMessage(t *testing.T, conn *websocket.Conn) (int, []byte) {
+	t.Helper()
+	if err := conn.SetReadDeadline(time.Now().Add(2 * time.Second)); err != nil {
+		t.Fatalf("SetReadDeadline failed: %v", err)
+	}
+	msgType, data, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatalf("ReadMessage returned error: %v", err)
+	}
+	return msgType, data
+}
+
+func subscribeStream(t *testing.T, conn *websocket.Conn, topics ...string) []string {
+	t.Helper()
+	if err := conn.WriteJSON(streamClientMsg{Action: subscribeAction, Topics: topics}); err != nil {
+		t.Fatalf("failed to write subscribe: %v", err)
+	}
+	_, data := readStreamMessage(t, conn)
+	var reply streamSubscribedMsg
+	if err := json.Unmarshal(data, &reply); err != nil || reply.Type != "subscribed" {
+		t.Fatalf("subscribe reply = %q, %v", data, err)
+	}
+	return reply.Topics
+}
+
+func TestStreamBinaryPaneTopic(t *testing.T) {
+	hub := startStreamHub(t, nil)
+	conn := dialStream(t, hub, "")
+	if got := subscribeStream(t, conn, "pane/%2"); !reflect.DeepEqual(got, []string{"pane/%2"}) {
+		t.Fatalf("subscribed topics = %v", got)
+	}
+
+	hub.PublishPaneOutput("%1", []byte("other"))
+	hub.PublishPaneOutput("%2", []byte("hello"))
+
+	msgType, frame := readStreamMessage(t, conn)
+	if msgType != websocket.BinaryMessage {
+		t.Fatalf("message type = %d, want binary", msgType)
+	}
+	paneID, data, err := DecodePaneData(frame)
+	if err != nil || paneID != "%2" || string(data) != "hello" {
+		t.Fatalf("frame = %q %q %v, want %%2 hello", paneID, data, err)
+	}
+}
+
+func TestStreamJSONSessionAndWindowTopics(t *testing.T) {
+	locations := map[string]PaneLocation{
+		"%1": {Session: "api", WindowID: "@1"},
+		"%2": {Session: "api", WindowID: "@2"},
+		"%3": {Session: "web", WindowID: "@3"},
+	}
+	hub := startStreamHub(t, func(paneID string) (PaneLocation, bool) {
+		location, ok := locations[paneID]
+		return location, ok
+	})
+	conn := dialStream(t, hub, streamFormatJSON)
+	subscribeStream(t, conn, "window/@2")
+	if got := subscribeStream(t, conn, "session/web"); !reflect.DeepEqual(got, []string{"session/web", "window/@2"}) {
+		t.Fatalf("subscribed topics = %v", got)
+	}
+
+	for _, paneID := range []string{"%1", "%2", "%3"} {
+		hub.PublishPaneOutput(paneID, []byte("out-"+paneID))
+	}
+	for _, want := range []string{"%2", "%3"} {
+		msgType, data := readStreamMessage(t, conn)
+		var msg streamOutputMsg
+		if err := json.Unmarshal(data, &msg); err != nil || msgType != websocket.TextMessage {
+			t.Fatalf("message = %d %q, %v", msgType, data, err)
+		}
+		if msg.Type != "output" || msg.PaneID != want || string(msg.Data) != "out-"+want {
+			t.Fatalf("message = %+v, want output of %s", msg, want)
+		}
+	}
+}
+
+func TestStreamRejectsInvalidRequests(t *testing.T) {
+	hub := startStreamHub(t, nil)
+	if _, resp, err := websocket.DefaultDialer.Dial(hub.StreamURL()+"&format=xml", nil); err == nil || resp == nil || resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("dial with format=xml: err = %v, want HTTP 400", err)
+	}
+
+	conn := dialStream(t, hub, streamFormatJSON)
+	for _, msg := range []streamClientMsg{
+		{Action: subscribeAction, Topics: []string{"pane/"}},
+		{Action: subscribeAction, Topics: []string{"%1"}},
+		{Action: "watch", Topics: []string{"*"}},
+	} {
+		if err := conn.WriteJSON(msg); err != nil {
+			t.Fatal(err)
+		}
+		_, data := readStreamMessage(t, conn)
+		var reply errorMsg
+		if err := json.Unmarshal(data, &reply); err != nil || reply.Type != "error" {
+			t.Fatalf("reply to %+v = %q, want error", msg, data)
+		}
+	}
+}
+
+func TestStreamRequiresToken(t *testing.T) {
+	hub := startStreamHub(t, nil)
+	for _, url := range []string{hub.streamURL, hub.streamURL + "?token=wrong"} {
+		if _, resp, err := websocket.DefaultDialer.Dial(url, nil); err == nil || resp == nil || resp.StatusCode != http.StatusUnauthorized {
+			t.Fatalf("dial %s: err = %v, want HTTP 401", url, err)
+		}
+	}
+	if hub.StreamClientCount() != 0 {
+		t.Fatalf("StreamClientCount() = %d, want 0", hub.StreamClientCount())
+	}
+
+	header := http.Header{"Authorization": {"Bearer " + hub.streamToken}}
+	conn, _, err := websocket.DefaultDialer.Dial(hub.streamURL, header)
+	if err != nil {
+		t.Fatalf("dial with bearer token: %v", err)
+	}
+	_ = conn.Close()
+}
+
+func TestStreamRejectsForeignOrigin(t *testing.T) {
+	hub := startStreamHub(t, nil)
+	header := http.Header{"Origin": {"https://attacker.example"}}
+	if _, resp, err := websocket.DefaultDialer.Dial(hub.StreamURL(), header); err == nil || resp == nil || resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("dial from a foreign origin: err = %v, want HTTP 403", err)
+	}
+	if hub.StreamClientCount() != 0 {
+		t.Fatalf("StreamClientCount() = %d, want 0", hub.StreamClientCount())
+	}
+}
+
+func TestStreamClientBackpressure(t *testing.T) {
+	client := &streamClient{
+		binary:  true,
+		notify:  make(chan struct{}, 1),
+		topics:  map[string]struct{}{streamTopicAll: {}},
+		dropped: map[string]int{},
+	}
+	topics := []string{streamTopicAll, "pane/%1"}
+	for range streamQueueMaxFrames {
+		client.offer("%1", []byte("x"), topics)
+	}
+	client.offer("%1", []byte("dropped"), topics)
+	client.offer("%2", []byte("also dropped"), []string{streamTopicAll, "pane/%2"})
+
+	messages := client.drain()
+	if len(messages) != streamQueueMaxFrames+2 {
+		t.Fatalf("drained %d messages, want %d frames and 2 notices", len(messages), streamQueueMaxFrames)
+	}
+	var notices []streamDroppedMsg
+	for _, message := range messages[streamQueueMaxFrames:] {
+		var notice streamDroppedMsg
+		if err := json.Unmarshal(message.data, &notice); err != nil || message.messageType != websocket.TextMessage {
+			t.Fatalf("notice = %q, %v", message.data, err)
+		}
+		notices = append(notices, notice)
+	}
+	want := []streamDroppedMsg{
+		{Type: "dropped", PaneID: "%1", Bytes: len("dropped")},
+		{Type: "dropped", PaneID: "%2", Bytes: len("also dropped")},
+	}
+	if !reflect.DeepEqual(notices, want) {
+		t.Fatalf("notices = %+v, want %+v", notices, want)
+	}
+
+	client.offer("%1", []byte("after"), topics)
+	if messages := client.drain(); len(messages) != 1 || !strings.HasSuffix(string(messages[0].data), "after") {
+		t.Fatalf("drain after recovery = %d messages, want the new chunk", len(messages))
+	}
+}
+
+func TestStreamClientDropsOversizedBacklog(t *testing.T) {
+	client := &streamClient{
+		binary:  true,
+		notify:  make(chan struct{}, 1),
+		topics:  map[string]struct{}{"pane/%1": {}},
+		dropped: map[string]int{},
+	}
+	topics := []string{streamTopicAll, "pane/%1"}
+	big := make([]byte, streamQueueMaxBytes/2)
+	client.offer("%1", big, topics)
+	client.offer("%1", big, topics)
+	client.offer("%1", []byte("small"), topics)
+
+	messages := client.drain()
+	if len(messages) != 2 {
+		t.Fatalf("drained %d messages, want one chunk and one notice", len(messages))
+	}
+	var notice streamDroppedMsg
+	if err := json.Unmarshal(messages[1].data, &notice); err != nil || notice.Bytes != len(big)+len("small") {
+		t.Fatalf("notice = %+v, %v; a pane with a gap must take no output until the notice", notice, err)
+	}
+}
+
+func TestStopClosesStreamClients(t *testing.T) {
+	hub := startStreamHub(t, nil)
+	conn := dialStream(t, hub, "")
+	if err := hub.Stop(); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	if err := conn.SetReadDeadline(time.Now().Add(2 * time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := conn.ReadMessage(); err == nil {
+		t.Fatal("ReadMessage() succeeded after Stop")
+	}
+	if got := hub.StreamClientCount(); got != 0 {
+		t.Fatalf("StreamClientCount() = %d after Stop, want 0", got)
+	}
+}
+
+func TestStreamDisabledByFeatureFlag(t *testing.T) {
+	var enabled atomic.Bool
+	hub := NewHub(HubOptions{Addr: testListenAddr, StreamEnabled: enabled.Load})
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		_ = hub.Stop()
+		cancel()
+	})
+	if err := hub.Start(ctx); err != nil {
+		t.Fatalf("hub.Start() returned error: %v", err)
+	}
+
+	if url := hub.StreamURL(); url != "" {
+		t.Fatalf("StreamURL() while disabled = %q, want empty", url)
+	}
+	if _, resp, err := websocket.DefaultDialer.Dial(hub.streamURL, nil); err == nil || resp == nil || resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("dial while disabled: err = %v, want HTTP 404", err)
+	}
+
+	enabled.Store(true)
+	dialStream(t, hub, "")
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"myT-x/internal/deeplink"
 	"myT-x/internal/ipc"
 	"myT-x/internal/singleinstance"
 
@@ -19,7 +20,10 @@ import (
 //go:embed all:frontend/dist
 var assets embed.FS
 
-const appTitle = "myT-x v1.1.3"
+// appVersion must match wails.json productVersion.
+const appVersion = "1.1.3"
+
+const appTitle = "myT-x v" + appVersion
 
 func main() {
 	os.Exit(run())
@@ -32,10 +36,17 @@ func run() int {
 
 	// Single-instance check BEFORE any Wails/WebView2 initialization.
 	// Two simultaneous instances corrupt WebView2 browser process IME state.
+	// A mytx:// link click launches a new process with the URL as argument;
+	// it is forwarded to the running instance or opened after startup.
+	deepLink, hasDeepLink := deeplink.FromArgs(os.Args[1:])
 	mutexLock, err := singleinstance.TryLock(singleinstance.DefaultMutexName())
 	if errors.Is(err, singleinstance.ErrAlreadyRunning) {
 		slog.Debug("[DEBUG-SINGLE] another instance is already running, signaling activation")
-		if _, sendErr := ipc.Send("", ipc.TmuxRequest{Command: "activate-window"}); sendErr != nil {
+		activate := ipc.TmuxRequest{Command: "activate-window"}
+		if hasDeepLink {
+			activate.Args = []string{deepLink}
+		}
+		if _, sendErr := ipc.Send("", activate); sendErr != nil {
 			slog.Warn("[WARN-SINGLE] failed to signal existing instance", "error", sendErr)
 		}
 		return 0
@@ -44,28 +55,38 @@ func run() int {
 		// Mutex creation failed for unexpected reason. Continue startup defensively.
 		slog.Warn("[WARN-SINGLE] mutex creation failed, proceeding without single-instance guard", "error", err)
 	}
-	if mutexLock != nil {
-		defer func() {
-			if releaseErr := mutexLock.Release(); releaseErr != nil {
-				slog.Warn("[WARN-SINGLE] mutex release failed", "error", releaseErr)
-			}
-		}()
+	releaseLock := func() {
+		if mutexLock == nil {
+			return
+		}
+		if releaseErr := mutexLock.Release(); releaseErr != nil {
+			slog.Warn("[WARN-SINGLE] mutex release failed", "error", releaseErr)
+		}
+		mutexLock = nil
 	}
+	defer releaseLock()
 
 	app := NewApp()
+	if hasDeepLink {
+		app.setPendingDeepLink(deepLink)
+	}
 
+	// Sleep/resume pauses background work and checks sessions after wake.
+	windowsOpts := &windows.Options{
+		OnSuspend: app.onSystemSuspend,
+		OnResume:  app.onSystemResume,
+	}
 	// Isolate the WebView2 browser process from Edge and other WebView2 apps.
 	// Each unique WebviewUserDataPath creates a separate process group with its
 	// own TSF (Text Services Framework) context, preventing process-level IME
 	// state corruption that causes Japanese IME conversion failure.
-	var windowsOpts *windows.Options
 	if appData := os.Getenv("APPDATA"); appData != "" {
-		windowsOpts = &windows.Options{
-			WebviewUserDataPath: filepath.Join(appData, "myT-x", "WebView2"),
-		}
+		windowsOpts.WebviewUserDataPath = filepath.Join(appData, "myT-x", "WebView2")
 	} else {
 		slog.Error("[ERROR-IME] APPDATA not set, WebView2 process isolation disabled")
 	}
+	// Crash loops and GPU driver resets switch WebView2 to software rendering.
+	app.applyRenderingFallback(windowsOpts)
 
 	err = wails.Run(&options.App{
 		Title:     appTitle,
@@ -82,6 +103,7 @@ func run() int {
 		},
 		Windows:    windowsOpts,
 		OnStartup:  app.startup,
+		OnDomReady: app.domReady,
 		OnShutdown: app.shutdown,
 		Bind: []any{
 			app,
@@ -92,5 +114,13 @@ func run() int {
 		slog.Error("[ERROR-SINGLE] wails run failed", "error", err)
 		return 1
 	}
+	if app.relaunchRequested.Load() {
+		// The new instance must not find this one's single-instance lock.
+		releaseLock()
+		if err := relaunchProcess(); err != nil {
+			slog.Error("[ERROR-RENDER] relaunch failed", "error", err)
+			return 1
+		}
+	}
 	return 0
 }
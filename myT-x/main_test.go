@@ -24,6 +24,9 @@ func TestAppTitleVersionMatchesWailsProductVersion(t *testing.T) {
 	if config.Info.ProductVersion == "" {
 		t.Fatal("wails.json productVersion must not be empty")
 	}
+	if appVersion != config.Info.ProductVersion {
+		t.Fatalf("appVersion %q must match wails.json productVersion %q", appVersion, config.Info.ProductVersion)
+	}
 	if !strings.Contains(appTitle, config.Info.ProductVersion) {
 		t.Fatalf("appTitle %q must include wails.json productVersion %q", appTitle, config.Info.ProductVersion)
 	}